@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/runtime/ctl"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the account server.
@@ -71,6 +72,11 @@ func (ds *accountServer) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.AccountServerName), cc.AccountServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.AccountServer().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -113,6 +119,8 @@ func (ds *accountServer) register() error {
 
 // finalizer ...
 func (ds *accountServer) finalizer() {
+	defer tracing.Shutdown()
+
 	if err := ds.sd.Deregister(); err != nil {
 		logs.Errorf("process service shutdown, but deregister failed, err: %v", err)
 		return
@@ -0,0 +1,457 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// BudgetAlert 预算本次评估新达到的告警阈值
+type BudgetAlert struct {
+	PeriodKey    string
+	Currency     enumor.CurrencyCode
+	Amount       decimal.Decimal
+	ActualCost   decimal.Decimal
+	ForecastCost decimal.Decimal
+	// Actual 新达到的实际费用阈值
+	Actual []int64
+	// Forecast 新达到的预测费用阈值
+	Forecast []int64
+}
+
+// BudgetNotifier 预算告警通知
+type BudgetNotifier interface {
+	Notify(kt *kit.Kit, budget *billcore.Budget, alert *BudgetAlert) error
+}
+
+// NewBudgetController create budget controller, notifier is optional, alerts are only logged without notifier.
+func NewBudgetController(cli *client.ClientSet, notifier BudgetNotifier) *BudgetController {
+	if notifier == nil {
+		notifier = logBudgetNotifier{}
+	}
+
+	return &BudgetController{Client: cli, notifier: notifier}
+}
+
+// BudgetController 预算评估控制器，每日汇总账单有更新后评估所有预算，记录预算执行情况，并在费用达到阈值时发送通知
+type BudgetController struct {
+	Client   *client.ClientSet
+	notifier BudgetNotifier
+
+	// lastSummaryAt 上次评估时每日汇总账单的最近更新时间
+	lastSummaryAt string
+
+	kt         *kit.Kit
+	cancelFunc context.CancelFunc
+}
+
+// Start run controller
+func (bc *BudgetController) Start() error {
+	if bc.kt != nil {
+		return fmt.Errorf("controller already start")
+	}
+	kt := getInternalKit()
+	bc.cancelFunc = kt.CtxBackgroundWithCancel()
+	bc.kt = kt
+	go bc.runBudgetLoop(kt)
+	return nil
+}
+
+// Stop stop controller
+func (bc *BudgetController) Stop() {
+	if bc.cancelFunc != nil {
+		bc.cancelFunc()
+	}
+}
+
+func (bc *BudgetController) runBudgetLoop(kt *kit.Kit) {
+	ticker := time.NewTicker(*cc.AccountServer().Controller.BudgetSyncDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			bc.syncOnce(kt.NewSubKit())
+		case <-kt.Ctx.Done():
+			logs.Infof("budget controller context done, rid: %s", kt.Rid)
+			return
+		}
+	}
+}
+
+// syncOnce 每日汇总账单有更新时评估所有预算
+func (bc *BudgetController) syncOnce(kt *kit.Kit) {
+	latest, err := latestDailySummaryAt(kt, bc.Client)
+	if err != nil {
+		logs.Errorf("get latest daily summary failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+	if len(latest) == 0 || latest == bc.lastSummaryAt {
+		return
+	}
+
+	if err = bc.EvaluateAll(kt, time.Now().UTC()); err != nil {
+		logs.Errorf("evaluate budgets failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+	bc.lastSummaryAt = latest
+}
+
+// latestDailySummaryAt 返回每日汇总账单的最近更新时间，用于判断每日汇总账单是否有更新
+func latestDailySummaryAt(kt *kit.Kit, cli *client.ClientSet) (string, error) {
+	result, err := cli.DataService().Global.Bill.ListBillSummaryDaily(kt, &dsbillapi.BillSummaryDailyListReq{
+		Filter: tools.AllExpression(),
+		Page:   &core.BasePage{Limit: 1, Sort: "updated_at", Order: core.Descending},
+		Fields: []string{"id", "updated_at"},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Details) == 0 {
+		return "", nil
+	}
+
+	return result.Details[0].UpdatedAt, nil
+}
+
+// EvaluateAll 评估所有预算在 now 所在周期的执行情况
+func (bc *BudgetController) EvaluateAll(kt *kit.Kit, now time.Time) error {
+	ex := newExchanger(bc.Client)
+	req := &core.ListReq{Filter: tools.AllExpression(), Page: core.NewDefaultBasePage()}
+	for {
+		result, err := bc.Client.DataService().Global.Bill.ListBudget(kt, req)
+		if err != nil {
+			return err
+		}
+
+		for i := range result.Details {
+			budget := &result.Details[i]
+			if err = bc.evaluate(kt, budget, ex, now); err != nil {
+				logs.Errorf("evaluate budget %s(%s) failed, err: %v, rid: %s", budget.Name, budget.ID, err, kt.Rid)
+			}
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+func (bc *BudgetController) evaluate(kt *kit.Kit, budget *billcore.Budget, ex *exchanger, now time.Time) error {
+	period := newBudgetPeriod(budget.Period, now)
+	actual, err := bc.actualCost(kt, budget, period, ex)
+	if err != nil {
+		return err
+	}
+	forecast := period.forecast(actual)
+	amount := decimal.Zero
+	if budget.Amount != nil {
+		amount = *budget.Amount
+	}
+
+	latest, err := bc.latestRecord(kt, budget.ID, period.Key)
+	if err != nil {
+		return err
+	}
+	var actualAlerted, forecastAlerted []int64
+	if latest != nil {
+		actualAlerted, forecastAlerted = latest.ActualAlerted, latest.ForecastAlerted
+	}
+
+	alert := &BudgetAlert{
+		PeriodKey:    period.Key,
+		Currency:     budget.Currency,
+		Amount:       amount,
+		ActualCost:   actual,
+		ForecastCost: forecast,
+		Actual:       reachedThresholds(budget.ActualThresholds, actualAlerted, actual, amount),
+		Forecast:     reachedThresholds(budget.ForecastThresholds, forecastAlerted, forecast, amount),
+	}
+	if len(alert.Actual) != 0 || len(alert.Forecast) != 0 {
+		// 通知失败时不记录已通知的阈值，下次评估时重试
+		if err = bc.notifier.Notify(kt, budget, alert); err != nil {
+			logs.Errorf("notify budget %s alert failed, err: %v, rid: %s", budget.ID, err, kt.Rid)
+		} else {
+			actualAlerted = append(actualAlerted, alert.Actual...)
+			forecastAlerted = append(forecastAlerted, alert.Forecast...)
+		}
+	}
+
+	year, month, day := now.Date()
+	if latest != nil && latest.BillYear == year && latest.BillMonth == int(month) && latest.BillDay == day {
+		return bc.Client.DataService().Global.Bill.UpdateBudgetRecord(kt, &dsbillapi.BudgetRecordUpdateReq{
+			ID:              latest.ID,
+			Amount:          &amount,
+			ActualCost:      &actual,
+			ForecastCost:    &forecast,
+			ActualAlerted:   actualAlerted,
+			ForecastAlerted: forecastAlerted,
+		})
+	}
+
+	_, err = bc.Client.DataService().Global.Bill.CreateBudgetRecord(kt, &dsbillapi.BudgetRecordCreateReq{
+		BudgetID:        budget.ID,
+		PeriodKey:       period.Key,
+		BillYear:        year,
+		BillMonth:       int(month),
+		BillDay:         day,
+		Currency:        budget.Currency,
+		Amount:          &amount,
+		ActualCost:      &actual,
+		ForecastCost:    &forecast,
+		ActualAlerted:   actualAlerted,
+		ForecastAlerted: forecastAlerted,
+	})
+	return err
+}
+
+// actualCost 汇总预算范围内周期各月的二级账号账单（含调账），按账单月份的汇率换算为预算币种
+func (bc *BudgetController) actualCost(kt *kit.Kit, budget *billcore.Budget, period *budgetPeriod,
+	ex *exchanger) (decimal.Decimal, error) {
+
+	scopeRule, err := budgetScopeRule(budget)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	total := decimal.Zero
+	for _, ym := range period.Months {
+		req := &dsbillapi.BillSummaryMainListReq{
+			Filter: tools.ExpressionAnd(scopeRule, tools.RuleEqual("bill_year", ym.Year),
+				tools.RuleEqual("bill_month", ym.Month)),
+			Page: core.NewDefaultBasePage(),
+		}
+		for {
+			result, err := bc.Client.DataService().Global.Bill.ListBillSummaryMain(kt, req)
+			if err != nil {
+				return decimal.Zero, err
+			}
+
+			for _, summary := range result.Details {
+				cost := summary.CurrentMonthCost.Add(summary.AdjustmentCost)
+				converted, err := ex.convert(kt, cost, summary.Currency, budget.Currency, ym.Year, ym.Month)
+				if err != nil {
+					return decimal.Zero, err
+				}
+				total = total.Add(converted)
+			}
+
+			if uint(len(result.Details)) < req.Page.Limit {
+				break
+			}
+			req.Page.Start += uint32(req.Page.Limit)
+		}
+	}
+
+	return total, nil
+}
+
+func (bc *BudgetController) latestRecord(kt *kit.Kit, budgetID, periodKey string) (*billcore.BudgetRecord, error) {
+	result, err := bc.Client.DataService().Global.Bill.ListBudgetRecord(kt, &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("budget_id", budgetID), tools.RuleEqual("period_key", periodKey)),
+		Page:   &core.BasePage{Limit: 1, Sort: "created_at", Order: core.Descending},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	return &result.Details[0], nil
+}
+
+func budgetScopeRule(budget *billcore.Budget) (*filter.AtomRule, error) {
+	field := budget.ScopeType.SummaryField()
+	if len(field) == 0 {
+		return nil, fmt.Errorf("unsupported budget scope type: %s", budget.ScopeType)
+	}
+
+	switch budget.ScopeType {
+	case enumor.BudgetScopeBiz, enumor.BudgetScopeProduct:
+		id, err := strconv.ParseInt(budget.ScopeValue, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s scope value %s, err: %v", budget.ScopeType, budget.ScopeValue, err)
+		}
+		return tools.RuleEqual(field, id), nil
+	default:
+		return tools.RuleEqual(field, budget.ScopeValue), nil
+	}
+}
+
+// reachedThresholds 返回费用达到但尚未通知的阈值，阈值为预算金额的百分比
+func reachedThresholds(thresholds, alerted []int64, cost, amount decimal.Decimal) []int64 {
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	percent := cost.Mul(decimal.NewFromInt(100)).Div(amount)
+	reached := make([]int64, 0)
+	for _, threshold := range thresholds {
+		if slice.IsItemInSlice(alerted, threshold) || slice.IsItemInSlice(reached, threshold) {
+			continue
+		}
+		if percent.GreaterThanOrEqual(decimal.NewFromInt(threshold)) {
+			reached = append(reached, threshold)
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i] < reached[j] })
+
+	return reached
+}
+
+type yearMonth struct {
+	Year  int
+	Month int
+}
+
+// budgetPeriod 预算周期，按 UTC 时间划分，与账单月份一致
+type budgetPeriod struct {
+	Key         string
+	Months      []yearMonth
+	TotalDays   int
+	ElapsedDays int
+}
+
+func newBudgetPeriod(period enumor.BudgetPeriod, now time.Time) *budgetPeriod {
+	now = now.UTC()
+	year, month := now.Year(), int(now.Month())
+	startMonth, count := month, 1
+	key := fmt.Sprintf("%d-%02d", year, month)
+	if period == enumor.BudgetPeriodQuarter {
+		quarter := (month - 1) / 3
+		startMonth, count = quarter*3+1, 3
+		key = fmt.Sprintf("%d-Q%d", year, quarter+1)
+	}
+
+	start := time.Date(year, time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, count, 0)
+	p := &budgetPeriod{
+		Key:         key,
+		TotalDays:   int(end.Sub(start).Hours() / 24),
+		ElapsedDays: int(now.Sub(start).Hours()/24) + 1,
+	}
+	for i := 0; i < count; i++ {
+		p.Months = append(p.Months, yearMonth{Year: year, Month: startMonth + i})
+	}
+
+	return p
+}
+
+// forecast 按周期内已过天数的日均费用线性预测周期总费用
+func (p *budgetPeriod) forecast(actual decimal.Decimal) decimal.Decimal {
+	if p.ElapsedDays <= 0 || p.ElapsedDays >= p.TotalDays {
+		return actual
+	}
+
+	return actual.Mul(decimal.NewFromInt(int64(p.TotalDays))).Div(decimal.NewFromInt(int64(p.ElapsedDays))).Round(10)
+}
+
+// exchanger 按账单月份的汇率换算币种，汇率按月缓存
+type exchanger struct {
+	client *client.ClientSet
+	rates  map[yearMonth][]billcore.ExchangeRate
+}
+
+func newExchanger(cli *client.ClientSet) *exchanger {
+	return &exchanger{client: cli, rates: make(map[yearMonth][]billcore.ExchangeRate)}
+}
+
+// convert 优先使用直接汇率，其次使用反向汇率，最后经人民币换算
+func (e *exchanger) convert(kt *kit.Kit, amount decimal.Decimal, from, to enumor.CurrencyCode, year, month int) (
+	decimal.Decimal, error) {
+
+	if from == to || amount.IsZero() {
+		return amount, nil
+	}
+
+	rates, err := e.monthRates(kt, year, month)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if converted, ok := convertByRates(rates, amount, from, to); ok {
+		return converted, nil
+	}
+
+	if from != enumor.CurrencyCNY && to != enumor.CurrencyCNY {
+		if cny, ok := convertByRates(rates, amount, from, enumor.CurrencyCNY); ok {
+			if converted, ok := convertByRates(rates, cny, enumor.CurrencyCNY, to); ok {
+				return converted, nil
+			}
+		}
+	}
+
+	return decimal.Zero, fmt.Errorf("exchange rate from %s to %s of %d-%02d not found", from, to, year, month)
+}
+
+func (e *exchanger) monthRates(kt *kit.Kit, year, month int) ([]billcore.ExchangeRate, error) {
+	key := yearMonth{Year: year, Month: month}
+	if rates, ok := e.rates[key]; ok {
+		return rates, nil
+	}
+
+	result, err := e.client.DataService().Global.Bill.ListExchangeRate(kt, &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("year", year), tools.RuleEqual("month", month)),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.rates[key] = result.Details
+
+	return result.Details, nil
+}
+
+func convertByRates(rates []billcore.ExchangeRate, amount decimal.Decimal, from, to enumor.CurrencyCode) (
+	decimal.Decimal, bool) {
+
+	for _, rate := range rates {
+		if rate.ExchangeRate == nil || rate.ExchangeRate.IsZero() {
+			continue
+		}
+		if rate.FromCurrency == from && rate.ToCurrency == to {
+			return amount.Mul(*rate.ExchangeRate), true
+		}
+		if rate.FromCurrency == to && rate.ToCurrency == from {
+			return amount.Div(*rate.ExchangeRate), true
+		}
+	}
+
+	return decimal.Zero, false
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBudgetPeriod(t *testing.T) {
+	now := time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC)
+
+	month := newBudgetPeriod(enumor.BudgetPeriodMonth, now)
+	if month.Key != "2024-05" || month.TotalDays != 31 || month.ElapsedDays != 10 || len(month.Months) != 1 {
+		t.Errorf("unexpected month period: %+v", month)
+	}
+	if got := month.forecast(decimal.NewFromInt(100)); !got.Equal(decimal.NewFromInt(310)) {
+		t.Errorf("month forecast should be 310, got: %s", got)
+	}
+
+	quarter := newBudgetPeriod(enumor.BudgetPeriodQuarter, now)
+	if quarter.Key != "2024-Q2" || quarter.TotalDays != 91 || quarter.ElapsedDays != 40 {
+		t.Errorf("unexpected quarter period: %+v", quarter)
+	}
+	if !reflect.DeepEqual(quarter.Months, []yearMonth{{2024, 4}, {2024, 5}, {2024, 6}}) {
+		t.Errorf("unexpected quarter months: %+v", quarter.Months)
+	}
+}
+
+func TestReachedThresholds(t *testing.T) {
+	amount := decimal.NewFromInt(1000)
+	thresholds := []int64{100, 50, 80}
+
+	got := reachedThresholds(thresholds, nil, decimal.NewFromInt(850), amount)
+	if !reflect.DeepEqual(got, []int64{50, 80}) {
+		t.Errorf("reached thresholds should be [50 80], got: %v", got)
+	}
+
+	got = reachedThresholds(thresholds, []int64{50, 80}, decimal.NewFromInt(1000), amount)
+	if !reflect.DeepEqual(got, []int64{100}) {
+		t.Errorf("reached thresholds should be [100], got: %v", got)
+	}
+
+	if got = reachedThresholds(thresholds, nil, decimal.NewFromInt(100), decimal.Zero); len(got) != 0 {
+		t.Errorf("zero amount should not reach any threshold, got: %v", got)
+	}
+}
+
+func TestConvertByRates(t *testing.T) {
+	rate := decimal.NewFromInt(7)
+	rates := []billcore.ExchangeRate{
+		{FromCurrency: enumor.CurrencyUSD, ToCurrency: enumor.CurrencyCNY, ExchangeRate: &rate},
+	}
+
+	got, ok := convertByRates(rates, decimal.NewFromInt(10), enumor.CurrencyUSD, enumor.CurrencyCNY)
+	if !ok || !got.Equal(decimal.NewFromInt(70)) {
+		t.Errorf("usd to cny should be 70, got: %s, %v", got, ok)
+	}
+
+	got, ok = convertByRates(rates, decimal.NewFromInt(70), enumor.CurrencyCNY, enumor.CurrencyUSD)
+	if !ok || !got.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("cny to usd should be 10, got: %s, %v", got, ok)
+	}
+
+	if _, ok = convertByRates(rates, decimal.NewFromInt(1), "EUR", enumor.CurrencyCNY); ok {
+		t.Errorf("eur to cny should not be converted")
+	}
+}
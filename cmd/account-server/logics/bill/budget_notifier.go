@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+	"strings"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+)
+
+// NewMailBudgetNotifier 通过 cmsi 邮件通知预算的接收人
+func NewMailBudgetNotifier(cli cmsi.Client) BudgetNotifier {
+	return &mailBudgetNotifier{cli: cli}
+}
+
+type mailBudgetNotifier struct {
+	cli cmsi.Client
+}
+
+// Notify ...
+func (n *mailBudgetNotifier) Notify(kt *kit.Kit, budget *billcore.Budget, alert *BudgetAlert) error {
+	if len(budget.Receivers) == 0 {
+		logs.Warnf("budget %s(%s) has no receivers, skip notify, rid: %s", budget.Name, budget.ID, kt.Rid)
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("预算名称：%s", budget.Name),
+		fmt.Sprintf("预算范围：%s %s", budget.ScopeType, budget.ScopeValue),
+		fmt.Sprintf("预算周期：%s", alert.PeriodKey),
+		fmt.Sprintf("预算金额：%s %s", alert.Amount.StringFixed(2), alert.Currency),
+		fmt.Sprintf("实际费用：%s %s", alert.ActualCost.StringFixed(2), alert.Currency),
+		fmt.Sprintf("预测费用：%s %s", alert.ForecastCost.StringFixed(2), alert.Currency),
+	}
+	if len(alert.Actual) != 0 {
+		lines = append(lines, fmt.Sprintf("实际费用已达到预算的 %s", joinPercents(alert.Actual)))
+	}
+	if len(alert.Forecast) != 0 {
+		lines = append(lines, fmt.Sprintf("预测费用将达到预算的 %s", joinPercents(alert.Forecast)))
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(budget.Receivers, ","),
+		Title:            fmt.Sprintf("【海垒】云账单预算告警：%s", budget.Name),
+		Content:          strings.Join(lines, "<br/>"),
+	}
+	return n.cli.SendMail(kt, mail)
+}
+
+func joinPercents(thresholds []int64) string {
+	percents := make([]string, 0, len(thresholds))
+	for _, one := range thresholds {
+		percents = append(percents, fmt.Sprintf("%d%%", one))
+	}
+
+	return strings.Join(percents, "、")
+}
+
+// logBudgetNotifier 未配置通知渠道时只记录告警日志
+type logBudgetNotifier struct{}
+
+// Notify ...
+func (logBudgetNotifier) Notify(kt *kit.Kit, budget *billcore.Budget, alert *BudgetAlert) error {
+	logs.Warnf("budget %s(%s) of period %s reached thresholds, actual: %v%%(%s), forecast: %v%%(%s), "+
+		"amount: %s %s, rid: %s", budget.Name, budget.ID, alert.PeriodKey, alert.Actual, alert.ActualCost,
+		alert.Forecast, alert.ForecastCost, alert.Amount, alert.Currency, kt.Rid)
+	return nil
+}
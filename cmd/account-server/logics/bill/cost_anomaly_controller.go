@@ -0,0 +1,576 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/account-set"
+	billcore "hcm/pkg/api/core/bill"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// anomalyCheckDays 每次检测最近几天的费用，账单拉取有延迟，前几天的每日汇总账单仍可能更新
+	anomalyCheckDays = 3
+	// anomalyWindowDays 基线滚动窗口的天数
+	anomalyWindowDays = 28
+	// anomalyMinHistoryDays 窗口内至少要有多少天的费用才进行检测
+	anomalyMinHistoryDays = 14
+	// anomalyMinWeekdaySamples 窗口内同星期几的费用至少要有多少天才参与基线计算
+	anomalyMinWeekdaySamples = 3
+	// anomalyMaxContributors 最多记录几个费用增长的云产品
+	anomalyMaxContributors = 5
+)
+
+var (
+	// madScale 中位数绝对偏差换算为正态分布标准差的系数
+	madScale = decimal.NewFromFloat(1.4826)
+	// anomalyMinIncreaseRatio 费用相对基线的最小增长比例
+	anomalyMinIncreaseRatio = decimal.NewFromFloat(0.2)
+	// anomalyMinScaleRatio、anomalyMinScale 偏差尺度的下限，避免历史费用几乎不变时微小波动被判定为异常
+	anomalyMinScaleRatio = decimal.NewFromFloat(0.05)
+	anomalyMinScale      = decimal.NewFromInt(1)
+
+	anomalyLowScore    = decimal.NewFromFloat(3.5)
+	anomalyMediumScore = decimal.NewFromInt(6)
+	anomalyHighScore   = decimal.NewFromInt(10)
+)
+
+// CostAnomalyNotifier 费用异常通知
+type CostAnomalyNotifier interface {
+	Notify(kt *kit.Kit, account *protocore.BaseMainAccount, anomaly *billcore.CostAnomaly) error
+}
+
+// NewCostAnomalyController create cost anomaly controller, notifier is optional,
+// anomalies are only logged without notifier.
+func NewCostAnomalyController(cli *client.ClientSet, notifier CostAnomalyNotifier) *CostAnomalyController {
+	if notifier == nil {
+		notifier = logCostAnomalyNotifier{}
+	}
+
+	return &CostAnomalyController{Client: cli, notifier: notifier}
+}
+
+// CostAnomalyController 费用异常检测控制器，每日汇总账单有更新后按二级账号和运营产品检测最近几天的费用异常
+type CostAnomalyController struct {
+	Client   *client.ClientSet
+	notifier CostAnomalyNotifier
+
+	// lastSummaryAt 上次检测时每日汇总账单的最近更新时间
+	lastSummaryAt string
+
+	kt         *kit.Kit
+	cancelFunc context.CancelFunc
+}
+
+// Start run controller
+func (c *CostAnomalyController) Start() error {
+	if c.kt != nil {
+		return fmt.Errorf("controller already start")
+	}
+	kt := getInternalKit()
+	c.cancelFunc = kt.CtxBackgroundWithCancel()
+	c.kt = kt
+	go c.runCostAnomalyLoop(kt)
+	return nil
+}
+
+// Stop stop controller
+func (c *CostAnomalyController) Stop() {
+	if c.cancelFunc != nil {
+		c.cancelFunc()
+	}
+}
+
+func (c *CostAnomalyController) runCostAnomalyLoop(kt *kit.Kit) {
+	ticker := time.NewTicker(*cc.AccountServer().Controller.CostAnomalySyncDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.syncOnce(kt.NewSubKit())
+		case <-kt.Ctx.Done():
+			logs.Infof("cost anomaly controller context done, rid: %s", kt.Rid)
+			return
+		}
+	}
+}
+
+// syncOnce 每日汇总账单有更新时检测费用异常
+func (c *CostAnomalyController) syncOnce(kt *kit.Kit) {
+	latest, err := latestDailySummaryAt(kt, c.Client)
+	if err != nil {
+		logs.Errorf("get latest daily summary failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+	if len(latest) == 0 || latest == c.lastSummaryAt {
+		return
+	}
+
+	if err = c.DetectAll(kt, time.Now().UTC()); err != nil {
+		logs.Errorf("detect cost anomalies failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+	c.lastSummaryAt = latest
+}
+
+// DetectAll 检测 now 之前 anomalyCheckDays 天内所有二级账号的每日费用异常
+func (c *CostAnomalyController) DetectAll(kt *kit.Kit, now time.Time) error {
+	end := utcDate(now).AddDate(0, 0, -1)
+	firstCheckDay := end.AddDate(0, 0, 1-anomalyCheckDays)
+	start := firstCheckDay.AddDate(0, 0, -anomalyWindowDays)
+
+	seriesMap, err := c.loadSeries(kt, start, end)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range seriesMap {
+		for day := firstCheckDay; !day.After(end); day = day.AddDate(0, 0, 1) {
+			cost, ok := series.Costs[day]
+			if !ok {
+				continue
+			}
+			history, sameWeekday := series.history(day)
+			result, ok := detectCostAnomaly(cost, history, sameWeekday)
+			if !ok {
+				continue
+			}
+			if err = c.handleAnomaly(kt, series, day, cost, result); err != nil {
+				logs.Errorf("handle cost anomaly of %s/%d on %s failed, err: %v, rid: %s", series.MainAccountID,
+					series.ProductID, day.Format(time.DateOnly), err, kt.Rid)
+			}
+		}
+	}
+
+	return nil
+}
+
+// costSeries 二级账号及运营产品的每日费用
+type costSeries struct {
+	RootAccountID string
+	MainAccountID string
+	Vendor        enumor.Vendor
+	ProductID     int64
+	BkBizID       int64
+	Currency      enumor.CurrencyCode
+	// Costs 每日费用，key 为 UTC 零点
+	Costs map[time.Time]decimal.Decimal
+	// Versions 各账单月份的当前账单版本
+	Versions map[yearMonth]int
+}
+
+// history 返回 day 之前窗口内的每日费用，以及其中与 day 同星期几的费用
+func (s *costSeries) history(day time.Time) (history, sameWeekday []decimal.Decimal) {
+	for i := 1; i <= anomalyWindowDays; i++ {
+		one := day.AddDate(0, 0, -i)
+		cost, ok := s.Costs[one]
+		if !ok {
+			continue
+		}
+		history = append(history, cost)
+		if one.Weekday() == day.Weekday() {
+			sameWeekday = append(sameWeekday, cost)
+		}
+	}
+
+	return history, sameWeekday
+}
+
+// loadSeries 加载 [start, end] 内各二级账号当前账单版本的每日汇总费用
+func (c *CostAnomalyController) loadSeries(kt *kit.Kit, start, end time.Time) (map[string]*costSeries, error) {
+	seriesMap := make(map[string]*costSeries)
+	firstMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := firstMonth; !month.After(end); month = month.AddDate(0, 1, 0) {
+		ym := yearMonth{Year: month.Year(), Month: int(month.Month())}
+		versions, err := c.currentVersions(kt, ym)
+		if err != nil {
+			return nil, err
+		}
+
+		req := &dsbillapi.BillSummaryDailyListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("bill_year", ym.Year), tools.RuleEqual("bill_month", ym.Month)),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			result, err := c.Client.DataService().Global.Bill.ListBillSummaryDaily(kt, req)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, daily := range result.Details {
+				version, ok := versions[daily.MainAccountID]
+				if !ok || version != daily.VersionID {
+					continue
+				}
+				day := time.Date(daily.BillYear, time.Month(daily.BillMonth), daily.BillDay, 0, 0, 0, 0, time.UTC)
+				if day.Before(start) || day.After(end) {
+					continue
+				}
+
+				key := fmt.Sprintf("%s/%d", daily.MainAccountID, daily.ProductID)
+				series, ok := seriesMap[key]
+				if !ok {
+					series = &costSeries{
+						RootAccountID: daily.RootAccountID,
+						MainAccountID: daily.MainAccountID,
+						Vendor:        daily.Vendor,
+						ProductID:     daily.ProductID,
+						BkBizID:       daily.BkBizID,
+						Currency:      daily.Currency,
+						Costs:         make(map[time.Time]decimal.Decimal),
+						Versions:      make(map[yearMonth]int),
+					}
+					seriesMap[key] = series
+				}
+				if series.Currency != daily.Currency {
+					logs.Warnf("daily summary %s currency %s mismatch with %s, skip, rid: %s", daily.ID,
+						daily.Currency, series.Currency, kt.Rid)
+					continue
+				}
+				series.Costs[day] = series.Costs[day].Add(daily.Cost)
+				series.Versions[ym] = version
+			}
+
+			if uint(len(result.Details)) < req.Page.Limit {
+				break
+			}
+			req.Page.Start += uint32(req.Page.Limit)
+		}
+	}
+
+	return seriesMap, nil
+}
+
+// currentVersions 返回账单月份内各二级账号的当前账单版本
+func (c *CostAnomalyController) currentVersions(kt *kit.Kit, ym yearMonth) (map[string]int, error) {
+	versions := make(map[string]int)
+	req := &dsbillapi.BillSummaryMainListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("bill_year", ym.Year), tools.RuleEqual("bill_month", ym.Month)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"main_account_id", "current_version"},
+	}
+	for {
+		result, err := c.Client.DataService().Global.Bill.ListBillSummaryMain(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range result.Details {
+			versions[summary.MainAccountID] = summary.CurrentVersion
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return versions, nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+// handleAnomaly 记录费用异常，新发现的异常或严重程度升高时发送通知
+func (c *CostAnomalyController) handleAnomaly(kt *kit.Kit, series *costSeries, day time.Time, cost decimal.Decimal,
+	result *costAnomalyResult) error {
+
+	contributors, err := c.contributors(kt, series, day)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.getAnomaly(kt, series.MainAccountID, day)
+	if err != nil {
+		return err
+	}
+
+	anomaly := &billcore.CostAnomaly{
+		RootAccountID: series.RootAccountID,
+		MainAccountID: series.MainAccountID,
+		Vendor:        series.Vendor,
+		ProductID:     series.ProductID,
+		BkBizID:       series.BkBizID,
+		BillYear:      day.Year(),
+		BillMonth:     int(day.Month()),
+		BillDay:       day.Day(),
+		Currency:      series.Currency,
+		Cost:          &cost,
+		Baseline:      &result.Baseline,
+		Deviation:     &result.Deviation,
+		Score:         &result.Score,
+		Severity:      result.Severity,
+		Contributors:  contributors,
+	}
+	if existing == nil {
+		created, err := c.Client.DataService().Global.Bill.CreateCostAnomaly(kt, &dsbillapi.CostAnomalyCreateReq{
+			RootAccountID: anomaly.RootAccountID,
+			MainAccountID: anomaly.MainAccountID,
+			Vendor:        anomaly.Vendor,
+			ProductID:     anomaly.ProductID,
+			BkBizID:       anomaly.BkBizID,
+			BillYear:      anomaly.BillYear,
+			BillMonth:     anomaly.BillMonth,
+			BillDay:       anomaly.BillDay,
+			Currency:      anomaly.Currency,
+			Cost:          anomaly.Cost,
+			Baseline:      anomaly.Baseline,
+			Deviation:     anomaly.Deviation,
+			Score:         anomaly.Score,
+			Severity:      anomaly.Severity,
+			Contributors:  anomaly.Contributors,
+		})
+		if err != nil {
+			return err
+		}
+		anomaly.ID = created.ID
+	} else {
+		anomaly.ID = existing.ID
+		// 已通知过且严重程度没有升高时不再重复通知
+		anomaly.Notified = existing.Notified && result.Severity.Level() <= existing.Severity.Level()
+		err = c.Client.DataService().Global.Bill.UpdateCostAnomaly(kt, &dsbillapi.CostAnomalyUpdateReq{
+			ID:           anomaly.ID,
+			Currency:     anomaly.Currency,
+			Cost:         anomaly.Cost,
+			Baseline:     anomaly.Baseline,
+			Deviation:    anomaly.Deviation,
+			Score:        anomaly.Score,
+			Severity:     anomaly.Severity,
+			Contributors: anomaly.Contributors,
+			Notified:     cvt.ValToPtr(anomaly.Notified),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if anomaly.Notified {
+		return nil
+	}
+	return c.notify(kt, anomaly)
+}
+
+// notify 通知二级账号负责人，通知成功后标记为已通知，失败时下次检测重试
+func (c *CostAnomalyController) notify(kt *kit.Kit, anomaly *billcore.CostAnomaly) error {
+	account, err := c.Client.DataService().Global.MainAccount.GetBasicInfo(kt, anomaly.MainAccountID)
+	if err != nil {
+		return fmt.Errorf("get main account %s failed, err: %v", anomaly.MainAccountID, err)
+	}
+	if err = c.notifier.Notify(kt, &account.BaseMainAccount, anomaly); err != nil {
+		return fmt.Errorf("notify cost anomaly %s failed, err: %v", anomaly.ID, err)
+	}
+
+	return c.Client.DataService().Global.Bill.UpdateCostAnomaly(kt, &dsbillapi.CostAnomalyUpdateReq{
+		ID:       anomaly.ID,
+		Notified: cvt.ValToPtr(true),
+	})
+}
+
+func (c *CostAnomalyController) getAnomaly(kt *kit.Kit, mainAccountID string, day time.Time) (
+	*billcore.CostAnomaly, error) {
+
+	result, err := c.Client.DataService().Global.Bill.ListCostAnomaly(kt, &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("main_account_id", mainAccountID),
+			tools.RuleEqual("bill_year", day.Year()),
+			tools.RuleEqual("bill_month", int(day.Month())),
+			tools.RuleEqual("bill_day", day.Day()),
+		),
+		Page: &core.BasePage{Limit: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	return &result.Details[0], nil
+}
+
+// contributors 对比异常当天与前一天各云产品的费用，返回费用增长最多的云产品
+func (c *CostAnomalyController) contributors(kt *kit.Kit, series *costSeries, day time.Time) (
+	[]billcore.CostAnomalyContributor, error) {
+
+	current, err := c.productCosts(kt, series, day)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := c.productCosts(kt, series, day.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	return rankContributors(current, previous), nil
+}
+
+// productCosts 汇总二级账号当天当前账单版本明细中各云产品的费用
+func (c *CostAnomalyController) productCosts(kt *kit.Kit, series *costSeries, day time.Time) (
+	map[string]*billcore.CostAnomalyContributor, error) {
+
+	costs := make(map[string]*billcore.CostAnomalyContributor)
+	ym := yearMonth{Year: day.Year(), Month: int(day.Month())}
+	version, ok := series.Versions[ym]
+	if !ok {
+		return costs, nil
+	}
+
+	req := &dsbillapi.BillItemListReq{
+		ItemCommonOpt: &dsbillapi.ItemCommonOpt{Vendor: series.Vendor, Year: ym.Year, Month: ym.Month},
+		ListReq: &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("main_account_id", series.MainAccountID),
+				tools.RuleEqual("product_id", series.ProductID),
+				tools.RuleEqual("bill_day", day.Day()),
+				tools.RuleEqual("version_id", version),
+			),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"hc_product_code", "hc_product_name", "cost"},
+		},
+	}
+	for {
+		result, err := c.Client.DataService().Global.Bill.ListBillItem(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Details {
+			one, ok := costs[item.HcProductCode]
+			if !ok {
+				one = &billcore.CostAnomalyContributor{
+					HcProductCode: item.HcProductCode,
+					HcProductName: item.HcProductName,
+				}
+				costs[item.HcProductCode] = one
+			}
+			one.Cost = one.Cost.Add(item.Cost)
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return costs, nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+// rankContributors 返回费用比前一天增长的云产品，按增长额从大到小排列
+func rankContributors(current, previous map[string]*billcore.CostAnomalyContributor) []billcore.CostAnomalyContributor {
+	contributors := make([]billcore.CostAnomalyContributor, 0)
+	for code, one := range current {
+		contributor := *one
+		if prev, ok := previous[code]; ok {
+			contributor.PrevCost = prev.Cost
+		}
+		if contributor.Cost.GreaterThan(contributor.PrevCost) {
+			contributors = append(contributors, contributor)
+		}
+	}
+
+	sort.Slice(contributors, func(i, j int) bool {
+		iInc := contributors[i].Cost.Sub(contributors[i].PrevCost)
+		jInc := contributors[j].Cost.Sub(contributors[j].PrevCost)
+		if !iInc.Equal(jInc) {
+			return iInc.GreaterThan(jInc)
+		}
+		return contributors[i].HcProductCode < contributors[j].HcProductCode
+	})
+	if len(contributors) > anomalyMaxContributors {
+		contributors = contributors[:anomalyMaxContributors]
+	}
+
+	return contributors
+}
+
+// costAnomalyResult 每日费用异常检测结果
+type costAnomalyResult struct {
+	Baseline  decimal.Decimal
+	Deviation decimal.Decimal
+	Score     decimal.Decimal
+	Severity  enumor.CostAnomalySeverity
+}
+
+// detectCostAnomaly 以窗口内每日费用中位数与同星期几费用中位数的较大者作为基线，避免每周固定的费用高峰被误判，
+// 以中位数绝对偏差(MAD)衡量费用的波动，计算当天费用的稳健 z 分数，只检测费用上涨
+func detectCostAnomaly(cost decimal.Decimal, history, sameWeekday []decimal.Decimal) (*costAnomalyResult, bool) {
+	if len(history) < anomalyMinHistoryDays {
+		return nil, false
+	}
+
+	center := medianOf(history)
+	baseline := center
+	if len(sameWeekday) >= anomalyMinWeekdaySamples {
+		baseline = decimal.Max(baseline, medianOf(sameWeekday))
+	}
+
+	deviation := cost.Sub(baseline)
+	if !deviation.IsPositive() || deviation.LessThan(baseline.Mul(anomalyMinIncreaseRatio)) {
+		return nil, false
+	}
+
+	absDeviations := make([]decimal.Decimal, 0, len(history))
+	for _, one := range history {
+		absDeviations = append(absDeviations, one.Sub(center).Abs())
+	}
+	scale := decimal.Max(medianOf(absDeviations).Mul(madScale), baseline.Mul(anomalyMinScaleRatio), anomalyMinScale)
+	score := deviation.Div(scale).Round(4)
+
+	var severity enumor.CostAnomalySeverity
+	switch {
+	case score.GreaterThanOrEqual(anomalyHighScore):
+		severity = enumor.CostAnomalySeverityHigh
+	case score.GreaterThanOrEqual(anomalyMediumScore):
+		severity = enumor.CostAnomalySeverityMedium
+	case score.GreaterThanOrEqual(anomalyLowScore):
+		severity = enumor.CostAnomalySeverityLow
+	default:
+		return nil, false
+	}
+
+	return &costAnomalyResult{Baseline: baseline, Deviation: deviation, Score: score, Severity: severity}, true
+}
+
+func medianOf(values []decimal.Decimal) decimal.Decimal {
+	if len(values) == 0 {
+		return decimal.Zero
+	}
+
+	sorted := make([]decimal.Decimal, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
+
+func utcDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"reflect"
+	"testing"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDetectCostAnomaly(t *testing.T) {
+	history := make([]decimal.Decimal, 0)
+	for i := 0; i < 20; i++ {
+		history = append(history, decimal.NewFromInt(int64(95+i%11)))
+	}
+
+	cases := []struct {
+		name        string
+		cost        int64
+		history     []decimal.Decimal
+		sameWeekday []decimal.Decimal
+		want        enumor.CostAnomalySeverity
+	}{
+		{name: "spike", cost: 200, history: history, want: enumor.CostAnomalySeverityHigh},
+		{name: "moderate", cost: 130, history: history, want: enumor.CostAnomalySeverityMedium},
+		{name: "small increase", cost: 112, history: history},
+		{name: "decrease", cost: 10, history: history},
+		{name: "short history", cost: 200, history: history[:10]},
+		{
+			name: "weekly peak", cost: 210, history: history,
+			sameWeekday: []decimal.Decimal{decimal.NewFromInt(200), decimal.NewFromInt(205), decimal.NewFromInt(195)},
+		},
+	}
+	for _, c := range cases {
+		result, ok := detectCostAnomaly(decimal.NewFromInt(c.cost), c.history, c.sameWeekday)
+		if len(c.want) == 0 {
+			if ok {
+				t.Errorf("%s: should not be anomaly, got: %+v", c.name, result)
+			}
+			continue
+		}
+		if !ok || result.Severity != c.want {
+			t.Errorf("%s: severity should be %s, got: %+v, %v", c.name, c.want, result, ok)
+		}
+	}
+}
+
+func TestRankContributors(t *testing.T) {
+	current := map[string]*billcore.CostAnomalyContributor{
+		"cvm": {HcProductCode: "cvm", Cost: decimal.NewFromInt(300)},
+		"cbs": {HcProductCode: "cbs", Cost: decimal.NewFromInt(50)},
+		"cdn": {HcProductCode: "cdn", Cost: decimal.NewFromInt(120)},
+	}
+	previous := map[string]*billcore.CostAnomalyContributor{
+		"cvm": {HcProductCode: "cvm", Cost: decimal.NewFromInt(100)},
+		"cbs": {HcProductCode: "cbs", Cost: decimal.NewFromInt(60)},
+	}
+
+	got := rankContributors(current, previous)
+	codes := make([]string, 0, len(got))
+	for _, one := range got {
+		codes = append(codes, one.HcProductCode)
+	}
+	if !reflect.DeepEqual(codes, []string{"cvm", "cdn"}) {
+		t.Errorf("contributors should be [cvm cdn], got: %v", codes)
+	}
+	if !got[0].PrevCost.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("cvm prev cost should be 100, got: %s", got[0].PrevCost)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+	"strings"
+
+	protocore "hcm/pkg/api/core/account-set"
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/slice"
+)
+
+// NewMailCostAnomalyNotifier 通过 cmsi 邮件通知二级账号的负责人和备份负责人
+func NewMailCostAnomalyNotifier(cli cmsi.Client) CostAnomalyNotifier {
+	return &mailCostAnomalyNotifier{cli: cli}
+}
+
+type mailCostAnomalyNotifier struct {
+	cli cmsi.Client
+}
+
+// Notify ...
+func (n *mailCostAnomalyNotifier) Notify(kt *kit.Kit, account *protocore.BaseMainAccount,
+	anomaly *billcore.CostAnomaly) error {
+
+	receivers := slice.Unique(append(append([]string{}, account.Managers...), account.BakManagers...))
+	if len(receivers) == 0 {
+		logs.Warnf("main account %s(%s) has no managers, skip notify cost anomaly, rid: %s", account.Name,
+			account.ID, kt.Rid)
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("二级账号：%s(%s)", account.Name, account.CloudID),
+		fmt.Sprintf("云厂商：%s", anomaly.Vendor),
+		fmt.Sprintf("运营产品ID：%d", anomaly.ProductID),
+		fmt.Sprintf("账单日期：%d-%02d-%02d", anomaly.BillYear, anomaly.BillMonth, anomaly.BillDay),
+		fmt.Sprintf("严重程度：%s", anomaly.Severity),
+		fmt.Sprintf("当天费用：%s %s", anomaly.Cost.StringFixed(2), anomaly.Currency),
+		fmt.Sprintf("基线费用：%s %s", anomaly.Baseline.StringFixed(2), anomaly.Currency),
+	}
+	for _, one := range anomaly.Contributors {
+		lines = append(lines, fmt.Sprintf("云产品 %s(%s)：%s %s，前一天 %s %s", one.HcProductName, one.HcProductCode,
+			one.Cost.StringFixed(2), anomaly.Currency, one.PrevCost.StringFixed(2), anomaly.Currency))
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(receivers, ","),
+		Title:            fmt.Sprintf("【海垒】云账单费用异常：%s", account.Name),
+		Content:          strings.Join(lines, "<br/>"),
+	}
+	return n.cli.SendMail(kt, mail)
+}
+
+// logCostAnomalyNotifier 未配置通知渠道时只记录异常日志
+type logCostAnomalyNotifier struct{}
+
+// Notify ...
+func (logCostAnomalyNotifier) Notify(kt *kit.Kit, account *protocore.BaseMainAccount,
+	anomaly *billcore.CostAnomaly) error {
+
+	logs.Warnf("main account %s(%s) cost anomaly on %d-%02d-%02d, severity: %s, cost: %s, baseline: %s %s, "+
+		"score: %s, rid: %s", account.Name, account.ID, anomaly.BillYear, anomaly.BillMonth, anomaly.BillDay,
+		anomaly.Severity, anomaly.Cost, anomaly.Baseline, anomaly.Currency, anomaly.Score, kt.Rid)
+	return nil
+}
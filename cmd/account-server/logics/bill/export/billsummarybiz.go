@@ -42,6 +42,7 @@ type BillSummaryBizTable struct {
 	CurrentMonthCostSynced    string `header:"已确认账单美金（美元）"`
 	CurrentMonthRMBCost       string `header:"当前账单人民币（元）"`
 	CurrentMonthCost          string `header:"当前账单美金（美元）"`
+	MonthEndRMBForecast       string `header:"月末预测人民币（元）"`
 }
 
 // GetHeaderValues ...
@@ -0,0 +1,431 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	asbillapi "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// forecastSampleDays 按最近多少天的每日费用估计日均费用及其波动
+	forecastSampleDays = 14
+)
+
+// forecastZ 95% 置信区间对应的正态分布分位数
+var forecastZ = decimal.NewFromFloat(1.96)
+
+// NewCostForecaster create cost forecaster
+func NewCostForecaster(cli *client.ClientSet) *CostForecaster {
+	return &CostForecaster{Client: cli}
+}
+
+// CostForecaster 费用预测，以二级账号最近的每日费用估计日均费用及标准差，线性外推账单月份的月末费用和下月费用，
+// 并计入已确认的调账，按账单月份的汇率换算为人民币
+type CostForecaster struct {
+	Client *client.ClientSet
+}
+
+// MainAccountForecast 二级账号的费用预测，金额均为人民币
+type MainAccountForecast struct {
+	MainAccountID string
+	BkBizID       int64
+	Vendor        enumor.Vendor
+	// CurrentRMBCost 账单月份的当前费用，含已确认的调账
+	CurrentRMBCost decimal.Decimal
+	// MonthEndRMBCost、MonthEndRMBStd 月末费用预测及其标准差
+	MonthEndRMBCost decimal.Decimal
+	MonthEndRMBStd  decimal.Decimal
+	// NextMonthRMBCost、NextMonthRMBStd 下月费用预测及其标准差
+	NextMonthRMBCost decimal.Decimal
+	NextMonthRMBStd  decimal.Decimal
+}
+
+// ForecastMainAccounts 预测二级账号账单汇总所在月份的月末费用和下月费用，summaries 需为同一账单月份
+func (f *CostForecaster) ForecastMainAccounts(kt *kit.Kit, summaries []*dsbillapi.BillSummaryMain) (
+	map[string]*MainAccountForecast, error) {
+
+	forecasts := make(map[string]*MainAccountForecast, len(summaries))
+	if len(summaries) == 0 {
+		return forecasts, nil
+	}
+
+	cur := yearMonth{Year: summaries[0].BillYear, Month: summaries[0].BillMonth}
+	prev, next := addMonths(cur, -1), addMonths(cur, 1)
+	ex := newExchanger(f.Client)
+	for _, batch := range slice.Split(summaries, int(filter.DefaultMaxInLimit)) {
+		ids := make([]string, 0, len(batch))
+		curVersions := make(map[string]int, len(batch))
+		for _, summary := range batch {
+			if summary.BillYear != cur.Year || summary.BillMonth != cur.Month {
+				return nil, fmt.Errorf("main account summary %s is not in %d-%02d", summary.ID, cur.Year, cur.Month)
+			}
+			ids = append(ids, summary.MainAccountID)
+			curVersions[summary.MainAccountID] = summary.CurrentVersion
+		}
+
+		prevVersions, err := f.monthVersions(kt, prev, ids)
+		if err != nil {
+			return nil, err
+		}
+		curDaily, err := f.dailyCosts(kt, cur, ids, curVersions)
+		if err != nil {
+			return nil, err
+		}
+		prevDaily, err := f.dailyCosts(kt, prev, ids, prevVersions)
+		if err != nil {
+			return nil, err
+		}
+		nextAdjustments, err := f.confirmedAdjustments(kt, next, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range batch {
+			daily := newDailySeries(cur, curDaily[summary.MainAccountID], prevDaily[summary.MainAccountID])
+			forecast, err := f.forecastMainAccount(kt, ex, summary, daily, nextAdjustments[summary.MainAccountID])
+			if err != nil {
+				return nil, err
+			}
+			forecasts[summary.MainAccountID] = forecast
+		}
+	}
+
+	return forecasts, nil
+}
+
+func (f *CostForecaster) forecastMainAccount(kt *kit.Kit, ex *exchanger, summary *dsbillapi.BillSummaryMain,
+	daily *dailySeries, nextAdjustment decimal.Decimal) (*MainAccountForecast, error) {
+
+	cur := yearMonth{Year: summary.BillYear, Month: summary.BillMonth}
+	next := addMonths(cur, 1)
+	mean, std := daily.stats(forecastSampleDays)
+	current := summary.CurrentMonthCost.Add(summary.AdjustmentCost)
+	remaining, remainingStd := projectCost(mean, std, daily.TotalDays-daily.LastDay)
+	nextCost, nextStd := projectCost(mean, std, daysIn(next))
+
+	forecast := &MainAccountForecast{
+		MainAccountID: summary.MainAccountID,
+		BkBizID:       summary.BkBizID,
+		Vendor:        summary.Vendor,
+	}
+	converts := []struct {
+		target *decimal.Decimal
+		amount decimal.Decimal
+		months []yearMonth
+	}{
+		{target: &forecast.CurrentRMBCost, amount: current, months: []yearMonth{cur}},
+		{target: &forecast.MonthEndRMBCost, amount: current.Add(remaining), months: []yearMonth{cur}},
+		{target: &forecast.MonthEndRMBStd, amount: remainingStd, months: []yearMonth{cur}},
+		// 下月汇率尚未录入时使用本月汇率
+		{target: &forecast.NextMonthRMBCost, amount: nextCost.Add(nextAdjustment), months: []yearMonth{next, cur}},
+		{target: &forecast.NextMonthRMBStd, amount: nextStd, months: []yearMonth{next, cur}},
+	}
+	for _, one := range converts {
+		rmb, err := convertToRMB(kt, ex, one.amount, summary.Currency, one.months)
+		if err != nil {
+			return nil, fmt.Errorf("convert main account %s forecast to rmb failed, err: %v",
+				summary.MainAccountID, err)
+		}
+		*one.target = rmb
+	}
+
+	return forecast, nil
+}
+
+func convertToRMB(kt *kit.Kit, ex *exchanger, amount decimal.Decimal, currency enumor.CurrencyCode,
+	months []yearMonth) (decimal.Decimal, error) {
+
+	if len(currency) == 0 {
+		return amount, nil
+	}
+
+	var lastErr error
+	for _, ym := range months {
+		converted, err := ex.convert(kt, amount, currency, enumor.CurrencyRMB, ym.Year, ym.Month)
+		if err == nil {
+			return converted, nil
+		}
+		lastErr = err
+	}
+
+	return decimal.Zero, lastErr
+}
+
+// monthVersions 返回账单月份内二级账号的当前账单版本
+func (f *CostForecaster) monthVersions(kt *kit.Kit, ym yearMonth, mainAccountIDs []string) (map[string]int,
+	error) {
+
+	versions := make(map[string]int, len(mainAccountIDs))
+	req := &dsbillapi.BillSummaryMainListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("bill_year", ym.Year),
+			tools.RuleEqual("bill_month", ym.Month),
+			tools.RuleIn("main_account_id", mainAccountIDs),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"main_account_id", "current_version"},
+	}
+	for {
+		result, err := f.Client.DataService().Global.Bill.ListBillSummaryMain(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range result.Details {
+			versions[summary.MainAccountID] = summary.CurrentVersion
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return versions, nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+// dailyCosts 返回账单月份内二级账号当前账单版本的每日费用，key 为二级账号ID和账单日期
+func (f *CostForecaster) dailyCosts(kt *kit.Kit, ym yearMonth, mainAccountIDs []string,
+	versions map[string]int) (map[string]map[int]decimal.Decimal, error) {
+
+	costs := make(map[string]map[int]decimal.Decimal, len(mainAccountIDs))
+	req := &dsbillapi.BillSummaryDailyListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("bill_year", ym.Year),
+			tools.RuleEqual("bill_month", ym.Month),
+			tools.RuleIn("main_account_id", mainAccountIDs),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"main_account_id", "bill_day", "version_id", "cost"},
+	}
+	for {
+		result, err := f.Client.DataService().Global.Bill.ListBillSummaryDaily(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, daily := range result.Details {
+			if version, ok := versions[daily.MainAccountID]; !ok || version != daily.VersionID {
+				continue
+			}
+			if _, ok := costs[daily.MainAccountID]; !ok {
+				costs[daily.MainAccountID] = make(map[int]decimal.Decimal)
+			}
+			costs[daily.MainAccountID][daily.BillDay] = costs[daily.MainAccountID][daily.BillDay].Add(daily.Cost)
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return costs, nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+// confirmedAdjustments 返回账单月份内二级账号已确认的调账费用
+func (f *CostForecaster) confirmedAdjustments(kt *kit.Kit, ym yearMonth, mainAccountIDs []string) (
+	map[string]decimal.Decimal, error) {
+
+	adjustments := make(map[string]decimal.Decimal)
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("bill_year", ym.Year),
+			tools.RuleEqual("bill_month", ym.Month),
+			tools.RuleIn("main_account_id", mainAccountIDs),
+			tools.RuleEqual("state", enumor.BillAdjustmentStateConfirmed),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"main_account_id", "cost"},
+	}
+	for {
+		result, err := f.Client.DataService().Global.Bill.ListBillAdjustmentItem(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Details {
+			adjustments[item.MainAccountID] = adjustments[item.MainAccountID].Add(item.Cost)
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			return adjustments, nil
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+}
+
+// dailySeries 账单月份及上个月的每日费用
+type dailySeries struct {
+	// LastDay 账单月份内已有费用数据的最后一天，为 0 时表示账单月份还没有费用数据
+	LastDay   int
+	TotalDays int
+	cur       map[int]decimal.Decimal
+	prev      map[int]decimal.Decimal
+	prevDays  int
+}
+
+func newDailySeries(ym yearMonth, cur, prev map[int]decimal.Decimal) *dailySeries {
+	s := &dailySeries{TotalDays: daysIn(ym), cur: cur, prev: prev, prevDays: daysIn(addMonths(ym, -1))}
+	for day := range cur {
+		if day > s.LastDay && day <= s.TotalDays {
+			s.LastDay = day
+		}
+	}
+
+	return s
+}
+
+// stats 返回截至 LastDay 最近 n 天每日费用的均值和样本标准差，账单月份内不足 n 天时使用上个月的费用补足，没有数据的日期费用为 0
+func (s *dailySeries) stats(n int) (mean, std decimal.Decimal) {
+	samples := make([]decimal.Decimal, 0, n)
+	for i := 0; i < n; i++ {
+		day := s.LastDay - i
+		if day >= 1 {
+			samples = append(samples, s.cur[day])
+			continue
+		}
+		if day+s.prevDays < 1 {
+			break
+		}
+		samples = append(samples, s.prev[day+s.prevDays])
+	}
+	if len(samples) == 0 {
+		return decimal.Zero, decimal.Zero
+	}
+
+	sum := decimal.Zero
+	for _, one := range samples {
+		sum = sum.Add(one)
+	}
+	mean = sum.Div(decimal.NewFromInt(int64(len(samples))))
+	if len(samples) == 1 {
+		return mean, decimal.Zero
+	}
+
+	variance := decimal.Zero
+	for _, one := range samples {
+		diff := one.Sub(mean)
+		variance = variance.Add(diff.Mul(diff))
+	}
+	variance = variance.Div(decimal.NewFromInt(int64(len(samples) - 1)))
+
+	return mean, sqrtDecimal(variance)
+}
+
+// projectCost 按日均费用及标准差预测之后 days 天的费用及其标准差，假设每日费用相互独立
+func projectCost(mean, std decimal.Decimal, days int) (cost, costStd decimal.Decimal) {
+	if days <= 0 {
+		return decimal.Zero, decimal.Zero
+	}
+
+	return mean.Mul(decimal.NewFromInt(int64(days))), std.Mul(sqrtDecimal(decimal.NewFromInt(int64(days))))
+}
+
+// AggregateForecasts 按维度汇总二级账号的费用预测，各二级账号的预测误差视为相互独立
+func AggregateForecasts(forecasts map[string]*MainAccountForecast, dimension enumor.CostForecastDimension) (
+	[]asbillapi.CostForecast, error) {
+
+	type group struct {
+		result      asbillapi.CostForecast
+		monthEndVar decimal.Decimal
+		nextVar     decimal.Decimal
+	}
+	groups := make(map[string]*group)
+	keys := make([]string, 0)
+	for _, one := range forecasts {
+		var key string
+		var result asbillapi.CostForecast
+		switch dimension {
+		case enumor.CostForecastDimensionBiz:
+			key, result = strconv.FormatInt(one.BkBizID, 10), asbillapi.CostForecast{BkBizID: one.BkBizID}
+		case enumor.CostForecastDimensionMainAccount:
+			key = one.MainAccountID
+			result = asbillapi.CostForecast{MainAccountID: one.MainAccountID, BkBizID: one.BkBizID, Vendor: one.Vendor}
+		case enumor.CostForecastDimensionVendor:
+			key, result = string(one.Vendor), asbillapi.CostForecast{Vendor: one.Vendor}
+		default:
+			return nil, fmt.Errorf("unsupported cost forecast dimension: %s", dimension)
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &group{result: result}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.result.CurrentRMBCost = g.result.CurrentRMBCost.Add(one.CurrentRMBCost)
+		g.result.MonthEndRMBCost = g.result.MonthEndRMBCost.Add(one.MonthEndRMBCost)
+		g.result.NextMonthRMBCost = g.result.NextMonthRMBCost.Add(one.NextMonthRMBCost)
+		g.monthEndVar = g.monthEndVar.Add(one.MonthEndRMBStd.Mul(one.MonthEndRMBStd))
+		g.nextVar = g.nextVar.Add(one.NextMonthRMBStd.Mul(one.NextMonthRMBStd))
+	}
+
+	sort.Strings(keys)
+	results := make([]asbillapi.CostForecast, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		monthEndMargin := forecastZ.Mul(sqrtDecimal(g.monthEndVar))
+		nextMargin := forecastZ.Mul(sqrtDecimal(g.nextVar))
+		// 月末费用不会低于当前费用，下月费用不会低于 0
+		g.result.MonthEndRMBLower = decimal.Max(g.result.MonthEndRMBCost.Sub(monthEndMargin), g.result.CurrentRMBCost)
+		g.result.MonthEndRMBUpper = g.result.MonthEndRMBCost.Add(monthEndMargin)
+		g.result.NextMonthRMBLower = decimal.Max(g.result.NextMonthRMBCost.Sub(nextMargin), decimal.Zero)
+		g.result.NextMonthRMBUpper = g.result.NextMonthRMBCost.Add(nextMargin)
+		results = append(results, roundForecast(g.result))
+	}
+
+	return results, nil
+}
+
+func roundForecast(f asbillapi.CostForecast) asbillapi.CostForecast {
+	for _, one := range []*decimal.Decimal{&f.CurrentRMBCost, &f.MonthEndRMBCost, &f.MonthEndRMBLower,
+		&f.MonthEndRMBUpper, &f.NextMonthRMBCost, &f.NextMonthRMBLower, &f.NextMonthRMBUpper} {
+		*one = one.Round(2)
+	}
+
+	return f
+}
+
+func sqrtDecimal(d decimal.Decimal) decimal.Decimal {
+	if !d.IsPositive() {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromFloat(math.Sqrt(d.InexactFloat64()))
+}
+
+func addMonths(ym yearMonth, months int) yearMonth {
+	t := time.Date(ym.Year, time.Month(ym.Month), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	return yearMonth{Year: t.Year(), Month: int(t.Month())}
+}
+
+func daysIn(ym yearMonth) int {
+	return time.Date(ym.Year, time.Month(ym.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
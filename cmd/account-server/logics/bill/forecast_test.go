@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDailySeriesStats(t *testing.T) {
+	cur := map[int]decimal.Decimal{1: decimal.NewFromInt(10), 2: decimal.NewFromInt(20)}
+	prev := map[int]decimal.Decimal{29: decimal.NewFromInt(30), 30: decimal.NewFromInt(20)}
+	series := newDailySeries(yearMonth{Year: 2024, Month: 5}, cur, prev)
+	if series.LastDay != 2 || series.TotalDays != 31 {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	// 本月 2 天不足 4 天，使用上月 29、30 日补足
+	mean, std := series.stats(4)
+	if !mean.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("mean should be 20, got: %s", mean)
+	}
+	if got := std.Round(4); !got.Equal(decimal.NewFromFloat(8.165)) {
+		t.Errorf("std should be 8.165, got: %s", got)
+	}
+
+	cost, costStd := projectCost(mean, std, 29)
+	if !cost.Equal(decimal.NewFromInt(580)) || !costStd.IsPositive() {
+		t.Errorf("unexpected projection: %s, %s", cost, costStd)
+	}
+}
+
+func TestAggregateForecasts(t *testing.T) {
+	forecasts := map[string]*MainAccountForecast{
+		"a": {MainAccountID: "a", BkBizID: 1, Vendor: enumor.TCloud, CurrentRMBCost: decimal.NewFromInt(100),
+			MonthEndRMBCost: decimal.NewFromInt(300), MonthEndRMBStd: decimal.NewFromInt(30),
+			NextMonthRMBCost: decimal.NewFromInt(310), NextMonthRMBStd: decimal.NewFromInt(40)},
+		"b": {MainAccountID: "b", BkBizID: 1, Vendor: enumor.Aws, CurrentRMBCost: decimal.NewFromInt(50),
+			MonthEndRMBCost: decimal.NewFromInt(100), MonthEndRMBStd: decimal.NewFromInt(40),
+			NextMonthRMBCost: decimal.NewFromInt(20), NextMonthRMBStd: decimal.NewFromInt(30)},
+	}
+
+	got, err := AggregateForecasts(forecasts, enumor.CostForecastDimensionBiz)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("should aggregate into one biz, got: %+v, err: %v", got, err)
+	}
+	// 标准差合并为 sqrt(30^2+40^2)=50，区间半径为 1.96*50=98
+	biz := got[0]
+	if !biz.MonthEndRMBCost.Equal(decimal.NewFromInt(400)) || !biz.MonthEndRMBUpper.Equal(decimal.NewFromInt(498)) ||
+		!biz.MonthEndRMBLower.Equal(decimal.NewFromInt(302)) {
+		t.Errorf("unexpected month end forecast: %+v", biz)
+	}
+	if !biz.NextMonthRMBLower.Equal(decimal.NewFromInt(232)) {
+		t.Errorf("next month lower should be 232, got: %s", biz.NextMonthRMBLower)
+	}
+
+	got, err = AggregateForecasts(forecasts, enumor.CostForecastDimensionVendor)
+	if err != nil || len(got) != 2 {
+		t.Errorf("should aggregate into two vendors, got: %+v, err: %v", got, err)
+	}
+}
@@ -38,6 +38,13 @@ type BillManager struct {
 	CurrentMainControllers map[string]*MainAccountController
 	CurrentRootControllers map[string]*RootAccountController
 	AccountList            AccountLister
+	// BudgetNotifier 预算告警通知，为空时只记录日志
+	BudgetNotifier BudgetNotifier
+	// CostAnomalyNotifier 费用异常通知，为空时只记录日志
+	CostAnomalyNotifier CostAnomalyNotifier
+
+	budgetController      *BudgetController
+	costAnomalyController *CostAnomalyController
 }
 
 // Run bill manager
@@ -74,6 +81,42 @@ func (bm *BillManager) loopOnce() {
 	if err := bm.syncRootControllers(); err != nil {
 		logs.Errorf("sync root controllers failed, err: %s", err.Error())
 	}
+	if err := bm.syncBudgetController(); err != nil {
+		logs.Errorf("sync budget controller failed, err: %s", err.Error())
+	}
+	if err := bm.syncCostAnomalyController(); err != nil {
+		logs.Errorf("sync cost anomaly controller failed, err: %s", err.Error())
+	}
+}
+
+func (bm *BillManager) syncBudgetController() error {
+	if bm.budgetController != nil {
+		return nil
+	}
+
+	ctrl := NewBudgetController(bm.Client, bm.BudgetNotifier)
+	if err := ctrl.Start(); err != nil {
+		ctrl.Stop()
+		return fmt.Errorf("start budget controller failed, err: %v", err)
+	}
+	bm.budgetController = ctrl
+	logs.Infof("start budget controller")
+	return nil
+}
+
+func (bm *BillManager) syncCostAnomalyController() error {
+	if bm.costAnomalyController != nil {
+		return nil
+	}
+
+	ctrl := NewCostAnomalyController(bm.Client, bm.CostAnomalyNotifier)
+	if err := ctrl.Start(); err != nil {
+		ctrl.Stop()
+		return fmt.Errorf("start cost anomaly controller failed, err: %v", err)
+	}
+	bm.costAnomalyController = ctrl
+	logs.Infof("start cost anomaly controller")
+	return nil
 }
 
 func (bm *BillManager) syncRootControllers() error {
@@ -200,4 +243,16 @@ func (bm *BillManager) stopControllers() {
 		ctrl.Stop()
 		delete(bm.CurrentMainControllers, key)
 	}
+
+	if bm.budgetController != nil {
+		logs.Warnf("stop budget controller")
+		bm.budgetController.Stop()
+		bm.budgetController = nil
+	}
+
+	if bm.costAnomalyController != nil {
+		logs.Warnf("stop cost anomaly controller")
+		bm.costAnomalyController.Stop()
+		bm.costAnomalyController = nil
+	}
 }
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tcloud ...
+package tcloud
+
+import (
+	"hcm/cmd/account-server/logics/bill/puller"
+	"hcm/cmd/account-server/logics/bill/puller/daily"
+	"hcm/pkg/api/data-service/bill"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+const (
+	defaultTCloudDelay = 1
+)
+
+func init() {
+	puller.DailyPullerRegistry[enumor.TCloud] = &TCloudPuller{
+		BillDelay: defaultTCloudDelay,
+	}
+}
+
+// TCloudPuller tcloud puller
+type TCloudPuller struct {
+	BillDelay int
+}
+
+// EnsurePullTask 检查拉取任务，如果失败、不存在，则新建
+func (tp *TCloudPuller) EnsurePullTask(kt *kit.Kit, client *client.ClientSet,
+	billSummaryMain *dsbillapi.BillSummaryMain) error {
+
+	return tp.newDailyPuller(client, billSummaryMain).EnsurePullTask(kt)
+}
+
+// GetPullTaskList ...
+func (tp *TCloudPuller) GetPullTaskList(kt *kit.Kit, client *client.ClientSet,
+	billSummaryMain *dsbillapi.BillSummaryMain) ([]*bill.BillDailyPullTaskResult, error) {
+
+	return tp.newDailyPuller(client, billSummaryMain).GetPullTaskList(kt)
+}
+
+// newDailyPuller 创建与拉取任务的查询条件一致的每日拉取器，腾讯云按一级账号拉取后过滤二级账号，需要两者的云ID
+func (tp *TCloudPuller) newDailyPuller(client *client.ClientSet,
+	billSummaryMain *dsbillapi.BillSummaryMain) *daily.DailyPuller {
+
+	return &daily.DailyPuller{
+		RootAccountID:      billSummaryMain.RootAccountID,
+		RootAccountCloudID: billSummaryMain.RootAccountCloudID,
+		MainAccountID:      billSummaryMain.MainAccountID,
+		MainAccountCloudID: billSummaryMain.MainAccountCloudID,
+		ProductID:          billSummaryMain.ProductID,
+		BkBizID:            billSummaryMain.BkBizID,
+		Vendor:             billSummaryMain.Vendor,
+		BillYear:           billSummaryMain.BillYear,
+		BillMonth:          billSummaryMain.BillMonth,
+		Version:            billSummaryMain.CurrentVersion,
+		BillDelay:          tp.BillDelay,
+		Client:             client,
+	}
+}
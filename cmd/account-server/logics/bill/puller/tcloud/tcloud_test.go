@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"testing"
+
+	"hcm/cmd/account-server/logics/bill/puller"
+	"hcm/cmd/account-server/logics/bill/puller/daily"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	got, ok := puller.DailyPullerRegistry[enumor.TCloud]
+	assert.True(t, ok, "tcloud puller should be registered")
+	assert.Equal(t, &TCloudPuller{BillDelay: defaultTCloudDelay}, got)
+}
+
+func TestNewDailyPuller(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   int
+		summary *dsbillapi.BillSummaryMain
+		want    *daily.DailyPuller
+	}{
+		{
+			name:  "fill root and main account cloud id",
+			delay: defaultTCloudDelay,
+			summary: &dsbillapi.BillSummaryMain{
+				RootAccountID:      "root-1",
+				RootAccountCloudID: "100001",
+				MainAccountID:      "main-1",
+				MainAccountCloudID: "100002",
+				Vendor:             enumor.TCloud,
+				ProductID:          1,
+				BkBizID:            2,
+				BillYear:           2024,
+				BillMonth:          5,
+				CurrentVersion:     3,
+			},
+			want: &daily.DailyPuller{
+				RootAccountID:      "root-1",
+				RootAccountCloudID: "100001",
+				MainAccountID:      "main-1",
+				MainAccountCloudID: "100002",
+				Vendor:             enumor.TCloud,
+				ProductID:          1,
+				BkBizID:            2,
+				BillYear:           2024,
+				BillMonth:          5,
+				Version:            3,
+				BillDelay:          defaultTCloudDelay,
+			},
+		},
+		{
+			name:  "use puller bill delay",
+			delay: 3,
+			summary: &dsbillapi.BillSummaryMain{
+				RootAccountID:      "root-2",
+				RootAccountCloudID: "200001",
+				MainAccountID:      "main-2",
+				MainAccountCloudID: "200002",
+				Vendor:             enumor.TCloud,
+				BillYear:           2023,
+				BillMonth:          12,
+				CurrentVersion:     1,
+			},
+			want: &daily.DailyPuller{
+				RootAccountID:      "root-2",
+				RootAccountCloudID: "200001",
+				MainAccountID:      "main-2",
+				MainAccountCloudID: "200002",
+				Vendor:             enumor.TCloud,
+				BillYear:           2023,
+				BillMonth:          12,
+				Version:            1,
+				BillDelay:          3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := &TCloudPuller{BillDelay: tt.delay}
+			assert.Equal(t, tt.want, tp.newDailyPuller(nil, tt.summary))
+		})
+	}
+}
@@ -28,6 +28,8 @@ import (
 	_ "hcm/cmd/account-server/logics/bill/puller/gcp"
 	// register huawei puller
 	_ "hcm/cmd/account-server/logics/bill/puller/huawei"
+	// register tcloud puller
+	_ "hcm/cmd/account-server/logics/bill/puller/tcloud"
 	// register zenlayer puller
 	_ "hcm/cmd/account-server/logics/bill/puller/zenlayer"
 )
@@ -50,6 +50,12 @@ func (s *service) Get(cts *rest.Contexts) (interface{}, error) {
 			account.Extension.CloudInitPassword = ""
 		}
 		return account, err
+	case enumor.TCloud:
+		account, err := s.client.DataService().TCloud.MainAccount.Get(cts.Kit, accountID)
+		if account != nil {
+			account.Extension.CloudInitPassword = ""
+		}
+		return account, err
 	case enumor.Gcp:
 		account, err := s.client.DataService().Gcp.MainAccount.Get(cts.Kit, accountID)
 		// 	 nothing to set null
@@ -65,6 +65,8 @@ func (s *service) Add(cts *rest.Contexts) (interface{}, error) {
 	switch req.Vendor {
 	case enumor.Aws:
 		accountID, err = s.addForAws(cts, req)
+	case enumor.TCloud:
+		accountID, err = s.addForTCloud(cts, req)
 	case enumor.Gcp:
 		accountID, err = s.addForGcp(cts, req)
 	case enumor.Azure:
@@ -129,6 +131,32 @@ func (s *service) addForAws(cts *rest.Contexts, req *proto.RootAccountAddReq) (s
 	return result.ID, err
 }
 
+func (s *service) addForTCloud(cts *rest.Contexts, req *proto.RootAccountAddReq) (string, error) {
+	result, err := s.client.DataService().TCloud.RootAccount.Create(
+		cts.Kit,
+		&dataproto.RootAccountCreateReq[dataproto.TCloudRootAccountExtensionCreateReq]{
+			Name:        req.Name,
+			CloudID:     req.Extension["cloud_main_account_id"],
+			Email:       req.Email,
+			Managers:    req.Managers,
+			BakManagers: req.BakManagers,
+			Site:        req.Site,
+			DeptID:      req.DeptID,
+			Memo:        req.Memo,
+			Extension: &dataproto.TCloudRootAccountExtensionCreateReq{
+				CloudMainAccountID: req.Extension["cloud_main_account_id"],
+				CloudSubAccountID:  req.Extension["cloud_sub_account_id"],
+				CloudSecretID:      req.Extension["cloud_secret_id"],
+				CloudSecretKey:     req.Extension["cloud_secret_key"],
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return result.ID, err
+}
+
 func (s *service) addForGcp(cts *rest.Contexts, req *proto.RootAccountAddReq) (string, error) {
 	// extension 的email如果没有填写则使用req的email，如果extension的email填写了则要求req.email和extension的email一致
 	email, ok := req.Extension["email"]
@@ -50,6 +50,12 @@ func (s *service) Get(cts *rest.Contexts) (interface{}, error) {
 			account.Extension.CloudSecretKey = ""
 		}
 		return account, err
+	case enumor.TCloud:
+		account, err := s.client.DataService().TCloud.RootAccount.Get(cts.Kit, accountID)
+		if account != nil {
+			account.Extension.CloudSecretKey = ""
+		}
+		return account, err
 	case enumor.Gcp:
 		account, err := s.client.DataService().Gcp.RootAccount.Get(cts.Kit, accountID)
 		if account != nil {
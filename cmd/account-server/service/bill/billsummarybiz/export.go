@@ -23,10 +23,12 @@ import (
 	"fmt"
 	"time"
 
+	logicsbill "hcm/cmd/account-server/logics/bill"
 	"hcm/cmd/account-server/logics/bill/export"
 	"hcm/pkg/api/account-server/bill"
 	"hcm/pkg/api/core"
 	billproto "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/meta"
@@ -38,6 +40,7 @@ import (
 	"hcm/pkg/tools/slice"
 
 	"github.com/TencentBlueKing/gopkg/conv"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -91,7 +94,9 @@ func (s *service) ExportBizSummary(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	table, err := toRawData(cts.Kit, result, bizMap)
+	forecasts := s.forecastBiz(cts.Kit, req, bkBizIDs)
+
+	table, err := toRawData(cts.Kit, result, bizMap, forecasts)
 	if err != nil {
 		logs.Errorf("convert to raw data failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
@@ -112,7 +117,9 @@ func generateFilename() string {
 	return fmt.Sprintf(defaultExportFilename, time.Now().Format("2006-01-02-15_04_05"))
 }
 
-func toRawData(kt *kit.Kit, details []*billproto.BillSummaryBizResult, bizMap map[int64]string) ([][]string, error) {
+func toRawData(kt *kit.Kit, details []*billproto.BillSummaryBizResult, bizMap map[int64]string,
+	forecasts map[int64]decimal.Decimal) ([][]string, error) {
+
 	result := make([][]string, 0, len(details))
 	for _, detail := range details {
 		table := export.BillSummaryBizTable{
@@ -123,6 +130,9 @@ func toRawData(kt *kit.Kit, details []*billproto.BillSummaryBizResult, bizMap ma
 			CurrentMonthRMBCost:       detail.CurrentMonthRMBCost.String(),
 			CurrentMonthCost:          detail.CurrentMonthCost.String(),
 		}
+		if forecast, ok := forecasts[detail.BkBizID]; ok {
+			table.MonthEndRMBForecast = forecast.StringFixed(2)
+		}
 		fields, err := table.GetHeaderValues()
 		if err != nil {
 			logs.Errorf("get header fields failed, err: %v, rid: %s", err, kt.Rid)
@@ -133,6 +143,51 @@ func toRawData(kt *kit.Kit, details []*billproto.BillSummaryBizResult, bizMap ma
 	return result, nil
 }
 
+// forecastBiz 预测业务的月末费用，预测失败时不影响导出，月末预测列留空
+func (s *service) forecastBiz(kt *kit.Kit, req *bill.BizSummaryExportReq, bkBizIDs []int64) map[int64]decimal.Decimal {
+	summaries := make([]*billproto.BillSummaryMain, 0)
+	for _, ids := range slice.Split(slice.Unique(bkBizIDs), int(filter.DefaultMaxInLimit)) {
+		listReq := &billproto.BillSummaryMainListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("bill_year", req.BillYear),
+				tools.RuleEqual("bill_month", req.BillMonth),
+				tools.RuleIn("bk_biz_id", ids),
+			),
+			Page: core.NewDefaultBasePage(),
+		}
+		for {
+			result, err := s.client.DataService().Global.Bill.ListBillSummaryMain(kt, listReq)
+			if err != nil {
+				logs.Errorf("list main account summary for forecast failed, err: %v, rid: %s", err, kt.Rid)
+				return nil
+			}
+			summaries = append(summaries, result.Details...)
+
+			if uint(len(result.Details)) < listReq.Page.Limit {
+				break
+			}
+			listReq.Page.Start += uint32(listReq.Page.Limit)
+		}
+	}
+
+	forecasts, err := logicsbill.NewCostForecaster(s.client).ForecastMainAccounts(kt, summaries)
+	if err != nil {
+		logs.Errorf("forecast main account cost failed, err: %v, rid: %s", err, kt.Rid)
+		return nil
+	}
+	bizForecasts, err := logicsbill.AggregateForecasts(forecasts, enumor.CostForecastDimensionBiz)
+	if err != nil {
+		logs.Errorf("aggregate biz cost forecast failed, err: %v, rid: %s", err, kt.Rid)
+		return nil
+	}
+
+	result := make(map[int64]decimal.Decimal, len(bizForecasts))
+	for _, one := range bizForecasts {
+		result[one.BkBizID] = one.MonthEndRMBCost
+	}
+	return result
+}
+
 func (s *service) fetchBizSummary(cts *rest.Contexts, req *bill.BizSummaryExportReq) (
 	[]*billproto.BillSummaryBizResult, error) {
 
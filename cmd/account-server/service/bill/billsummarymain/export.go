@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"time"
 
+	logicsbill "hcm/cmd/account-server/logics/bill"
 	"hcm/cmd/account-server/logics/bill/export"
 	asbillapi "hcm/pkg/api/account-server/bill"
 	"hcm/pkg/api/core"
@@ -109,7 +110,13 @@ func (s *service) ExportMainAccountSummary(cts *rest.Contexts) (interface{}, err
 		return nil, err
 	}
 
-	table, err := toRawData(cts.Kit, result, mainAccountMap, rootAccountMap, bizMap)
+	// 预测失败时不影响导出，月末预测列留空
+	forecasts, err := logicsbill.NewCostForecaster(s.client).ForecastMainAccounts(cts.Kit, result)
+	if err != nil {
+		logs.Errorf("forecast main account cost failed, err: %v, rid: %s", err, cts.Kit.Rid)
+	}
+
+	table, err := toRawData(cts.Kit, result, mainAccountMap, rootAccountMap, bizMap, forecasts)
 	if err != nil {
 		logs.Errorf("convert to raw data error: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
@@ -176,7 +183,8 @@ func (s *service) fetchMainAccountSummary(cts *rest.Contexts, req *asbillapi.Mai
 }
 
 func toRawData(kt *kit.Kit, details []*dsbillapi.BillSummaryMain, mainAccountMap map[string]*accountset.BaseMainAccount,
-	rootAccountMap map[string]*accountset.BaseRootAccount, bizMap map[int64]string) ([][]string, error) {
+	rootAccountMap map[string]*accountset.BaseRootAccount, bizMap map[int64]string,
+	forecasts map[string]*logicsbill.MainAccountForecast) ([][]string, error) {
 
 	data := make([][]string, 0, len(details))
 	for _, detail := range details {
@@ -205,6 +213,9 @@ func toRawData(kt *kit.Kit, details []*dsbillapi.BillSummaryMain, mainAccountMap
 			CurrentMonthRMBCost:       detail.CurrentMonthRMBCost.String(),
 			CurrentMonthCost:          detail.CurrentMonthCost.String(),
 		}
+		if forecast, ok := forecasts[detail.MainAccountID]; ok {
+			table.MonthEndRMBForecast = forecast.MonthEndRMBCost.StringFixed(2)
+		}
 		fields, err := table.GetHeaderValues()
 		if err != nil {
 			logs.Errorf("get header fields failed: %v, rid: %s", err, kt.Rid)
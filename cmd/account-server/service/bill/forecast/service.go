@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package forecast ...
+package forecast
+
+import (
+	"net/http"
+
+	logicsbill "hcm/cmd/account-server/logics/bill"
+	"hcm/cmd/account-server/service/capability"
+	asbillapi "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	dsbillapi "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the bill cost forecast service
+func InitService(c *capability.Capability) {
+	svc := &service{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		forecaster: logicsbill.NewCostForecaster(c.ApiClient),
+	}
+
+	h := rest.NewHandler()
+
+	// register handler
+	h.Add("ListCostForecast", http.MethodPost, "/bills/cost_forecasts/list", svc.ListCostForecast)
+
+	h.Load(c.WebService)
+}
+
+type service struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	forecaster *logicsbill.CostForecaster
+}
+
+// ListCostForecast 预测账单月份的月末费用和下月费用，按业务、二级账号或云厂商汇总
+func (s *service) ListCostForecast(cts *rest.Contexts) (any, error) {
+	req := new(asbillapi.CostForecastReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	expression := tools.ExpressionAnd(
+		tools.RuleEqual("bill_year", req.BillYear),
+		tools.RuleEqual("bill_month", req.BillMonth),
+	)
+	if req.Filter != nil {
+		if expression, err = tools.And(req.Filter, expression); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	summaries := make([]*dsbillapi.BillSummaryMain, 0)
+	listReq := &dsbillapi.BillSummaryMainListReq{Filter: expression, Page: core.NewDefaultBasePage()}
+	for {
+		result, err := s.client.DataService().Global.Bill.ListBillSummaryMain(cts.Kit, listReq)
+		if err != nil {
+			logs.Errorf("list main account summary failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		summaries = append(summaries, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	forecasts, err := s.forecaster.ForecastMainAccounts(cts.Kit, summaries)
+	if err != nil {
+		logs.Errorf("forecast main account cost failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	details, err := logicsbill.AggregateForecasts(forecasts, req.Dimension)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return &asbillapi.CostForecastResult{Details: details}, nil
+}
@@ -38,7 +38,10 @@ import (
 	"hcm/cmd/account-server/service/bill/billsummarymain"
 	"hcm/cmd/account-server/service/bill/billsummaryroot"
 	"hcm/cmd/account-server/service/bill/billsyncrecord"
+	"hcm/cmd/account-server/service/bill/budget"
+	"hcm/cmd/account-server/service/bill/costanomaly"
 	exchangerate "hcm/cmd/account-server/service/bill/exchange-rate"
+	"hcm/cmd/account-server/service/bill/forecast"
 	"hcm/cmd/account-server/service/capability"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
@@ -52,6 +55,7 @@ import (
 	restcli "hcm/pkg/rest/client"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/thirdparty/esb"
 	"hcm/pkg/tools/ssl"
 
@@ -103,6 +107,18 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		return nil, err
 	}
 
+	// 配置了 cmsi 时通过邮件发送预算告警和费用异常通知
+	var budgetNotifier bill.BudgetNotifier
+	var costAnomalyNotifier bill.CostAnomalyNotifier
+	if cmsiCfg := cc.AccountServer().Cmsi; len(cmsiCfg.Endpoints) != 0 {
+		cmsiCli, err := cmsi.NewClient(&cmsiCfg, metrics.Register())
+		if err != nil {
+			return nil, err
+		}
+		budgetNotifier = bill.NewMailBudgetNotifier(cmsiCli)
+		costAnomalyNotifier = bill.NewMailCostAnomalyNotifier(cmsiCli)
+	}
+
 	// start bill manager
 	newBillManager := &bill.BillManager{
 		Sd:     sd,
@@ -112,6 +128,8 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		},
 		CurrentMainControllers: make(map[string]*bill.MainAccountController),
 		CurrentRootControllers: make(map[string]*bill.RootAccountController),
+		BudgetNotifier:         budgetNotifier,
+		CostAnomalyNotifier:    costAnomalyNotifier,
 	}
 
 	svr := &Service{
@@ -127,9 +145,7 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.New(cryptoConfig.Option())
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -213,6 +229,9 @@ func (s *Service) apiSet() *restful.Container {
 	billadjustment.InitBillAdjustmentService(c)
 	billsyncrecord.InitService(c)
 	exchangerate.InitService(c)
+	budget.InitService(c)
+	costanomaly.InitService(c)
+	forecast.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
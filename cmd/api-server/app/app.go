@@ -35,6 +35,7 @@ import (
 	"hcm/pkg/runtime/gwparser"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the api server
@@ -74,6 +75,11 @@ func (as *apiService) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.APIServerName), cc.ApiServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	if err := gwparser.Init(opt.DisableJWT, opt.PublicKey); err != nil {
 		return err
 	}
@@ -102,5 +108,5 @@ func (as *apiService) prepare(opt *options.Option) error {
 }
 
 func (as *apiService) finalizer() {
-	return
+	tracing.Shutdown()
 }
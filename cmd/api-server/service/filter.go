@@ -26,11 +26,16 @@ import (
 	"net/http"
 	"regexp"
 
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/gwparser"
+	"hcm/pkg/tracing"
 
 	"github.com/emicklei/go-restful/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // restFilter returns api server's restful request filter, we filter all requests base on URL.
@@ -45,13 +50,23 @@ func (p *proxy) restFilter() restful.FilterFunction {
 			fmt.Fprintf(w, errf.Error(err).Error())
 			return
 		}
+
+		// 创建入口span，链路上下文通过请求头传递给下游服务，并在响应头中返回 trace id
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), "api-server "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(tracing.RidKey.String(kt.Rid),
+				attribute.String("http.method", r.Method), attribute.String("http.target", r.URL.Path)))
+		kt.Ctx = ctx
 		req.Request.Header = kt.Header()
+		if traceID := tracing.TraceID(ctx); len(traceID) != 0 {
+			resp.Header().Set(constant.TraceIDKey, traceID)
+		}
 
 		body, err := peekRequest(r)
 		if err != nil {
 			w.WriteHeader(http.StatusForbidden)
 			fmt.Fprintf(w, errf.NewFromErr(errf.Unknown, err).Error())
 			logs.Errorf("peek request failed, err: %v, rid: %s", err, kt.Rid)
+			tracing.End(span, err)
 			return
 		}
 		// request and response details landing log for monitoring and troubleshooting problem.
@@ -59,6 +74,12 @@ func (p *proxy) restFilter() restful.FilterFunction {
 			"rid: %s", r.RequestURI, r.Method, body, kt.AppCode, kt.User, r.RemoteAddr, kt.Rid)
 
 		chain.ProcessFilter(req, resp)
+
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode()))
+		if resp.StatusCode() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode()))
+		}
+		span.End()
 	}
 }
 
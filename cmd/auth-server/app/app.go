@@ -35,6 +35,7 @@ import (
 	"hcm/pkg/runtime/ctl/cmd"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the auth server
@@ -83,6 +84,11 @@ func (as *authService) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.AuthServerName), cc.AuthServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.AuthServer().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -128,6 +134,8 @@ func (as *authService) register() error {
 }
 
 func (as *authService) finalizer() {
+	defer tracing.Shutdown()
+
 	if err := as.sd.Deregister(); err != nil {
 		logs.Errorf("process service shutdown, but deregister failed, err: %v", err)
 		return
@@ -34,6 +34,7 @@ import (
 	"hcm/pkg/runtime/ctl"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the cloud server.
@@ -72,6 +73,11 @@ func (ds *cloudServer) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.CloudServerName), cc.CloudServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.CloudServer().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -114,6 +120,8 @@ func (ds *cloudServer) register() error {
 }
 
 func (ds *cloudServer) finalizer() {
+	defer tracing.Shutdown()
+
 	lock.Manager.Close()
 
 	if err := ds.sd.Deregister(); err != nil {
@@ -0,0 +1,232 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package approval 内置审批引擎，按申请单类型和业务配置的审批链生成审批单，支持或签、会签、转交和超时升级
+package approval
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// maxUpdateRetry 并发审批导致版本冲突时的最大重试次数
+const maxUpdateRetry = 3
+
+// Interface define native approval engine interface.
+type Interface interface {
+	// GetChain 获取申请单类型在业务下的审批链，业务未单独配置时使用默认审批链
+	GetChain(kt *kit.Kit, appType enumor.ApplicationType, bizID int64) (*approval.Chain, error)
+	// CreateTicket 为申请单创建审批单，并进入第一个审批节点
+	CreateTicket(kt *kit.Kit, opt *CreateTicketOption) (*approval.Ticket, error)
+	// GetTicket 获取申请单的审批单
+	GetTicket(kt *kit.Kit, applicationID string) (*approval.Ticket, error)
+	// Operate 对申请单的审批单执行审批操作，如 Approve、Reject 等，版本冲突时会重新查询审批单后重试
+	Operate(kt *kit.Kit, applicationID string, op func(ticket *approval.Ticket) error) (*approval.Ticket, error)
+}
+
+// CreateTicketOption create approval ticket option.
+type CreateTicketOption struct {
+	SN              string
+	ApplicationID   string
+	ApplicationType enumor.ApplicationType
+	BkBizID         int64
+	Applicant       string
+	Title           string
+	// Stages 已替换审批人变量的审批节点，见 ResolveStages
+	Stages []approval.TicketStage
+}
+
+type engine struct {
+	client *client.ClientSet
+}
+
+// NewApproval new native approval engine.
+func NewApproval(client *client.ClientSet) Interface {
+	return &engine{
+		client: client,
+	}
+}
+
+// GetChain ...
+func (e *engine) GetChain(kt *kit.Kit, appType enumor.ApplicationType, bizID int64) (*approval.Chain, error) {
+	bizIDs := []int64{approval.DefaultChainBizID}
+	if bizID > 0 {
+		bizIDs = append(bizIDs, bizID)
+	}
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("application_type", appType), tools.RuleIn("bk_biz_id", bizIDs)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := e.client.DataService().Global.Approval.ListChain(kt, req)
+	if err != nil {
+		logs.Errorf("list approval chain failed, err: %v, type: %s, biz: %d, rid: %s", err, appType, bizID, kt.Rid)
+		return nil, err
+	}
+
+	var chain *approval.Chain
+	for idx := range resp.Details {
+		chain = &resp.Details[idx]
+		if chain.BkBizID == bizID {
+			break
+		}
+	}
+
+	if chain == nil {
+		return nil, errf.Newf(errf.RecordNotFound, "approval chain of %s not configured", appType)
+	}
+
+	return chain, nil
+}
+
+// CreateTicket ...
+func (e *engine) CreateTicket(kt *kit.Kit, opt *CreateTicketOption) (*approval.Ticket, error) {
+	bizID := opt.BkBizID
+	if bizID <= 0 {
+		bizID = approval.DefaultChainBizID
+	}
+
+	ticket := &approval.Ticket{
+		SN:              opt.SN,
+		ApplicationID:   opt.ApplicationID,
+		ApplicationType: opt.ApplicationType,
+		BkBizID:         bizID,
+		Applicant:       opt.Applicant,
+		Title:           opt.Title,
+		Stages:          opt.Stages,
+	}
+	if err := Start(ticket, time.Now()); err != nil {
+		return nil, err
+	}
+
+	req := &dataservice.ApprovalTicketCreateReq{
+		SN:               ticket.SN,
+		ApplicationID:    ticket.ApplicationID,
+		ApplicationType:  ticket.ApplicationType,
+		BkBizID:          ticket.BkBizID,
+		Applicant:        ticket.Applicant,
+		Title:            ticket.Title,
+		Status:           ticket.Status,
+		CurrentStage:     ticket.CurrentStage,
+		Stages:           ticket.Stages,
+		CurrentApprovers: ticket.CurrentApprovers,
+		Deadline:         ticket.Deadline,
+		Records:          ticket.Records,
+	}
+	result, err := e.client.DataService().Global.Approval.CreateTicket(kt, req)
+	if err != nil {
+		logs.Errorf("create approval ticket failed, err: %v, application: %s, rid: %s", err, opt.ApplicationID,
+			kt.Rid)
+		return nil, err
+	}
+	ticket.ID = result.ID
+
+	return ticket, nil
+}
+
+// GetTicket ...
+func (e *engine) GetTicket(kt *kit.Kit, applicationID string) (*approval.Ticket, error) {
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("application_id", applicationID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := e.client.DataService().Global.Approval.ListTicket(kt, req)
+	if err != nil {
+		logs.Errorf("list approval ticket failed, err: %v, application: %s, rid: %s", err, applicationID, kt.Rid)
+		return nil, err
+	}
+
+	if len(resp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "approval ticket of application %s not found", applicationID)
+	}
+
+	return &resp.Details[0], nil
+}
+
+// Operate ...
+func (e *engine) Operate(kt *kit.Kit, applicationID string, op func(ticket *approval.Ticket) error) (
+	*approval.Ticket, error) {
+
+	for retry := 0; retry < maxUpdateRetry; retry++ {
+		ticket, err := e.GetTicket(kt, applicationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = op(ticket); err != nil {
+			return nil, err
+		}
+
+		err = e.updateTicket(kt, ticket)
+		if err == nil {
+			ticket.Version++
+			return ticket, nil
+		}
+
+		// 审批单已被其他人更新，重新查询后再次执行
+		if errf.IsRecordNotFound(err) {
+			logs.Warnf("approval ticket %s version %d conflict, retry: %d, rid: %s", ticket.ID, ticket.Version,
+				retry, kt.Rid)
+			continue
+		}
+
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("approval ticket of application %s is busy, please try again later", applicationID)
+}
+
+func (e *engine) updateTicket(kt *kit.Kit, ticket *approval.Ticket) error {
+	req := &dataservice.ApprovalTicketUpdateReq{
+		ID:               ticket.ID,
+		Version:          ticket.Version,
+		Status:           ticket.Status,
+		CurrentStage:     ticket.CurrentStage,
+		Stages:           ticket.Stages,
+		CurrentApprovers: ticket.CurrentApprovers,
+		Deadline:         ticket.Deadline,
+		Records:          ticket.Records,
+	}
+
+	// 审批单结束时申请单状态与审批单在同一事务中更新，避免审批单已结束而申请单仍停留在审批中
+	if ticket.Status != enumor.Pending {
+		req.ApplicationID = ticket.ApplicationID
+		req.ApplicationStatus = ApplicationStatusOf(ticket.Status)
+	}
+
+	return e.client.DataService().Global.Approval.UpdateTicket(kt, req)
+}
+
+// ApplicationStatusOf 审批结果对应的申请单状态，审批通过后申请单进入交付中，其他结果保持原样
+func ApplicationStatusOf(status enumor.ApplicationStatus) enumor.ApplicationStatus {
+	if status == enumor.Pass {
+		return enumor.Delivering
+	}
+	return status
+}
@@ -0,0 +1,262 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// systemOperator 超时升级等由系统触发的审批记录的操作人
+const systemOperator = "system"
+
+// ResolveStages 将审批链的审批人变量替换为实际审批人，生成审批单的审批节点
+func ResolveStages(stages approval.Stages, vars map[string][]string) ([]approval.TicketStage, error) {
+	if err := stages.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := make([]approval.TicketStage, 0, len(stages))
+	for _, stage := range stages {
+		stage.Approvers = resolveUsers(stage.Approvers, vars)
+		if len(stage.Approvers) == 0 {
+			return nil, fmt.Errorf("stage %s has no approver after resolving variables", stage.Name)
+		}
+		stage.EscalateTo = resolveUsers(stage.EscalateTo, vars)
+
+		result = append(result, approval.TicketStage{Stage: stage, ApprovedBy: make([]string, 0)})
+	}
+
+	return result, nil
+}
+
+func resolveUsers(users []string, vars map[string][]string) []string {
+	result := make([]string, 0, len(users))
+	for _, user := range users {
+		if values, ok := vars[user]; ok {
+			result = append(result, values...)
+			continue
+		}
+		result = append(result, user)
+	}
+
+	return slice.Unique(slice.Filter(result, func(user string) bool { return len(user) != 0 }))
+}
+
+// Start 审批单进入第一个审批节点
+func Start(ticket *approval.Ticket, now time.Time) error {
+	if len(ticket.Stages) == 0 {
+		return errors.New("approval ticket has no stage")
+	}
+
+	ticket.Status = enumor.Pending
+	if ticket.Records == nil {
+		ticket.Records = make([]approval.Record, 0)
+	}
+	enterStage(ticket, 0, now)
+	return nil
+}
+
+func enterStage(ticket *approval.Ticket, idx int64, now time.Time) {
+	ticket.CurrentStage = idx
+	stage := &ticket.Stages[idx]
+	stage.StartedAt = times.ConvStdTimeFormat(now)
+	ticket.CurrentApprovers = currentApprovers(stage)
+
+	ticket.Deadline = ""
+	if stage.TimeoutMin > 0 && !stage.Escalated {
+		ticket.Deadline = times.ConvStdTimeFormat(now.Add(time.Duration(stage.TimeoutMin) * time.Minute))
+	}
+}
+
+// pendingApprovers 当前节点中既未审批、也未被升级审批人代为审批的原审批人
+func pendingApprovers(stage *approval.TicketStage) []string {
+	return slice.Filter(stage.Approvers, func(user string) bool {
+		return !slice.IsItemInSlice(stage.ApprovedBy, user) && !slice.IsItemInSlice(stage.Substituted, user)
+	})
+}
+
+// currentApprovers 节点的当前审批人，节点升级后升级审批人与尚未审批的原审批人同为当前审批人
+func currentApprovers(stage *approval.TicketStage) []string {
+	result := pendingApprovers(stage)
+	if stage.Escalated && len(result) != 0 {
+		result = append(result, stage.EscalateTo...)
+	}
+
+	return slice.Unique(result)
+}
+
+func finish(ticket *approval.Ticket, status enumor.ApplicationStatus, now time.Time) {
+	ticket.Status = status
+	ticket.Stages[ticket.CurrentStage].FinishedAt = times.ConvStdTimeFormat(now)
+	ticket.CurrentApprovers = make([]string, 0)
+	ticket.Deadline = ""
+}
+
+func addRecord(ticket *approval.Ticket, operator string, action enumor.ApprovalAction, memo string,
+	delegate []string, now time.Time) {
+
+	ticket.Records = append(ticket.Records, approval.Record{
+		Stage:    ticket.CurrentStage,
+		Operator: operator,
+		Action:   action,
+		Memo:     memo,
+		Delegate: delegate,
+		Time:     times.ConvStdTimeFormat(now),
+	})
+}
+
+func checkApprover(ticket *approval.Ticket, operator string) error {
+	if ticket.Status != enumor.Pending {
+		return errf.Newf(errf.InvalidParameter, "approval ticket is %s, can not be operated", ticket.Status)
+	}
+
+	if !slice.IsItemInSlice(ticket.CurrentApprovers, operator) {
+		return errf.Newf(errf.PermissionDenied, "%s is not the approver of current stage", operator)
+	}
+
+	return nil
+}
+
+// Approve 审批通过当前节点，或签节点任一审批人通过即进入下一节点，会签节点需全部审批人通过，
+// 最后一个节点通过后审批单状态变为通过
+func Approve(ticket *approval.Ticket, operator, memo string, now time.Time) error {
+	if err := checkApprover(ticket, operator); err != nil {
+		return err
+	}
+
+	stage := &ticket.Stages[ticket.CurrentStage]
+	if stage.Escalated && slice.IsItemInSlice(stage.EscalateTo, operator) {
+		// 升级审批人只代替尚未审批的原审批人，已审批的原审批人不受影响
+		stage.Substituted = append(stage.Substituted, pendingApprovers(stage)...)
+	}
+	stage.ApprovedBy = append(stage.ApprovedBy, operator)
+	ticket.CurrentApprovers = currentApprovers(stage)
+	addRecord(ticket, operator, enumor.ApproveApprovalAction, memo, nil, now)
+
+	if stage.Mode == enumor.AllApprovalMode && len(pendingApprovers(stage)) != 0 {
+		return nil
+	}
+
+	if int(ticket.CurrentStage) == len(ticket.Stages)-1 {
+		finish(ticket, enumor.Pass, now)
+		return nil
+	}
+
+	stage.FinishedAt = times.ConvStdTimeFormat(now)
+	enterStage(ticket, ticket.CurrentStage+1, now)
+	return nil
+}
+
+// Reject 任一审批人驳回，审批单即为驳回状态
+func Reject(ticket *approval.Ticket, operator, memo string, now time.Time) error {
+	if err := checkApprover(ticket, operator); err != nil {
+		return err
+	}
+
+	addRecord(ticket, operator, enumor.RejectApprovalAction, memo, nil, now)
+	finish(ticket, enumor.Rejected, now)
+	return nil
+}
+
+// Withdraw 申请人撤回审批中的审批单
+func Withdraw(ticket *approval.Ticket, operator, memo string, now time.Time) error {
+	if ticket.Status != enumor.Pending {
+		return errf.Newf(errf.InvalidParameter, "approval ticket is %s, can not be withdrawn", ticket.Status)
+	}
+
+	if ticket.Applicant != operator {
+		return errf.New(errf.PermissionDenied, "only applicant can withdraw the approval ticket")
+	}
+
+	addRecord(ticket, operator, enumor.WithdrawApprovalAction, memo, nil, now)
+	finish(ticket, enumor.Cancelled, now)
+	return nil
+}
+
+// Delegate 审批人将自己的审批转交给他人，被转交人替代其成为当前节点的审批人
+func Delegate(ticket *approval.Ticket, operator, delegateTo, memo string, now time.Time) error {
+	if err := checkApprover(ticket, operator); err != nil {
+		return err
+	}
+
+	if len(delegateTo) == 0 || delegateTo == operator {
+		return errf.New(errf.InvalidParameter, "delegate user is invalid")
+	}
+
+	if slice.IsItemInSlice(ticket.CurrentApprovers, delegateTo) {
+		return errf.Newf(errf.InvalidParameter, "%s is already the approver of current stage", delegateTo)
+	}
+
+	replace := func(users []string) []string {
+		result := make([]string, 0, len(users))
+		for _, user := range users {
+			if user == operator {
+				user = delegateTo
+			}
+			result = append(result, user)
+		}
+		return slice.Unique(result)
+	}
+
+	stage := &ticket.Stages[ticket.CurrentStage]
+	stage.Approvers = replace(stage.Approvers)
+	stage.EscalateTo = replace(stage.EscalateTo)
+	ticket.CurrentApprovers = currentApprovers(stage)
+	addRecord(ticket, operator, enumor.DelegateApprovalAction, memo, []string{delegateTo}, now)
+	return nil
+}
+
+// IsTimeout 审批单当前节点是否已超时
+func IsTimeout(ticket *approval.Ticket, now time.Time) bool {
+	if ticket.Status != enumor.Pending || len(ticket.Deadline) == 0 {
+		return false
+	}
+
+	deadline, err := time.Parse(constant.TimeStdFormat, ticket.Deadline)
+	if err != nil {
+		return false
+	}
+
+	return !now.Before(deadline)
+}
+
+// Escalate 当前节点超时后升级，升级审批人加入当前审批人，节点的或签/会签方式不变。原审批人仍只代表自己审批，
+// 升级审批人通过时代替所有尚未审批的原审批人。每个节点只升级一次，升级后不再超时
+func Escalate(ticket *approval.Ticket, now time.Time) error {
+	if !IsTimeout(ticket, now) {
+		return errf.New(errf.InvalidParameter, "approval ticket is not timeout")
+	}
+
+	stage := &ticket.Stages[ticket.CurrentStage]
+	stage.Escalated = true
+	ticket.CurrentApprovers = currentApprovers(stage)
+	ticket.Deadline = ""
+	addRecord(ticket, systemOperator, enumor.EscalateApprovalAction, "approval timeout", stage.EscalateTo, now)
+	return nil
+}
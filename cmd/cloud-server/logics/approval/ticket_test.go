@@ -0,0 +1,164 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+)
+
+func newTestTicket(t *testing.T, now time.Time) *approval.Ticket {
+	chain := approval.Stages{
+		{Name: "leader", Approvers: []string{"${platform_manager}"}, Mode: enumor.AnyApprovalMode,
+			TimeoutMin: 30, EscalateTo: []string{"boss"}},
+		{Name: "ops", Approvers: []string{"ops1", "ops2"}, Mode: enumor.AllApprovalMode},
+	}
+	stages, err := ResolveStages(chain, map[string][]string{"${platform_manager}": {"pm1", "pm2"}})
+	if err != nil {
+		t.Fatalf("resolve stages failed, err: %v", err)
+	}
+
+	ticket := &approval.Ticket{Applicant: "alice", Stages: stages}
+	if err = Start(ticket, now); err != nil {
+		t.Fatalf("start ticket failed, err: %v", err)
+	}
+	return ticket
+}
+
+func TestApproveAnyThenAll(t *testing.T) {
+	now := time.Now()
+	ticket := newTestTicket(t, now)
+	if len(ticket.CurrentApprovers) != 2 || len(ticket.Deadline) == 0 {
+		t.Fatalf("unexpected first stage, approvers: %v, deadline: %s", ticket.CurrentApprovers, ticket.Deadline)
+	}
+
+	if err := Approve(ticket, "ops1", "", now); err == nil {
+		t.Errorf("ops1 is not the approver of first stage, approve should fail")
+	}
+
+	// 或签节点任一审批人通过即进入下一节点
+	if err := Approve(ticket, "pm1", "ok", now); err != nil {
+		t.Fatalf("pm1 approve failed, err: %v", err)
+	}
+	if ticket.CurrentStage != 1 || len(ticket.Deadline) != 0 {
+		t.Fatalf("expect enter stage 1 without deadline, got stage: %d, deadline: %s", ticket.CurrentStage,
+			ticket.Deadline)
+	}
+
+	// 会签节点需全部审批人通过
+	if err := Delegate(ticket, "ops1", "ops3", "", now); err != nil {
+		t.Fatalf("ops1 delegate failed, err: %v", err)
+	}
+	if err := Approve(ticket, "ops2", "", now); err != nil {
+		t.Fatalf("ops2 approve failed, err: %v", err)
+	}
+	if ticket.Status != enumor.Pending {
+		t.Fatalf("all-of stage should wait for ops3, got status: %s", ticket.Status)
+	}
+	if err := Approve(ticket, "ops3", "", now); err != nil {
+		t.Fatalf("ops3 approve failed, err: %v", err)
+	}
+	if ticket.Status != enumor.Pass || len(ticket.CurrentApprovers) != 0 {
+		t.Errorf("expect ticket pass, got status: %s, approvers: %v", ticket.Status, ticket.CurrentApprovers)
+	}
+	if len(ticket.Records) != 4 {
+		t.Errorf("expect 4 records, got: %d", len(ticket.Records))
+	}
+}
+
+func TestEscalateAndReject(t *testing.T) {
+	now := time.Now()
+	ticket := newTestTicket(t, now)
+
+	if err := Escalate(ticket, now.Add(29*time.Minute)); err == nil {
+		t.Errorf("escalate before deadline should fail")
+	}
+
+	if err := Escalate(ticket, now.Add(30*time.Minute)); err != nil {
+		t.Fatalf("escalate failed, err: %v", err)
+	}
+	if IsTimeout(ticket, now.Add(time.Hour)) {
+		t.Errorf("escalated stage should not timeout again")
+	}
+
+	if err := Reject(ticket, "boss", "no", now); err != nil {
+		t.Fatalf("boss reject failed, err: %v", err)
+	}
+	if ticket.Status != enumor.Rejected {
+		t.Errorf("expect ticket rejected, got: %s", ticket.Status)
+	}
+
+	if err := Withdraw(ticket, "alice", "", now); err == nil {
+		t.Errorf("finished ticket should not be withdrawn")
+	}
+}
+
+func TestEscalateKeepAllMode(t *testing.T) {
+	now := time.Now()
+	chain := approval.Stages{
+		{Name: "ops", Approvers: []string{"ops1", "ops2", "ops3"}, Mode: enumor.AllApprovalMode, TimeoutMin: 30,
+			EscalateTo: []string{"boss"}},
+		{Name: "final", Approvers: []string{"admin"}, Mode: enumor.AnyApprovalMode},
+	}
+	stages, err := ResolveStages(chain, nil)
+	if err != nil {
+		t.Fatalf("resolve stages failed, err: %v", err)
+	}
+	ticket := &approval.Ticket{Applicant: "alice", Stages: stages}
+	if err = Start(ticket, now); err != nil {
+		t.Fatalf("start ticket failed, err: %v", err)
+	}
+
+	if err = Approve(ticket, "ops1", "", now); err != nil {
+		t.Fatalf("ops1 approve failed, err: %v", err)
+	}
+	if err = Escalate(ticket, now.Add(30*time.Minute)); err != nil {
+		t.Fatalf("escalate failed, err: %v", err)
+	}
+	if ticket.Stages[0].Mode != enumor.AllApprovalMode {
+		t.Fatalf("escalate should keep stage mode, got: %s", ticket.Stages[0].Mode)
+	}
+	if err = Approve(ticket, "ops1", "", now); err == nil {
+		t.Errorf("ops1 has approved, approve again should fail")
+	}
+
+	// 原审批人仍只代表自己，会签节点不能由单个原审批人通过
+	if err = Approve(ticket, "ops2", "", now); err != nil {
+		t.Fatalf("ops2 approve failed, err: %v", err)
+	}
+	if ticket.CurrentStage != 0 {
+		t.Fatalf("all-of stage should wait for ops3 or boss, got stage: %d", ticket.CurrentStage)
+	}
+
+	// 升级审批人代替尚未审批的原审批人
+	if err = Approve(ticket, "boss", "", now); err != nil {
+		t.Fatalf("boss approve failed, err: %v", err)
+	}
+	if ticket.CurrentStage != 1 {
+		t.Fatalf("expect enter stage 1, got: %d", ticket.CurrentStage)
+	}
+	substituted := ticket.Stages[0].Substituted
+	if len(substituted) != 1 || substituted[0] != "ops3" {
+		t.Errorf("expect boss substitutes ops3 only, got: %v", substituted)
+	}
+}
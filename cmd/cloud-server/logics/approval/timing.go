@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approval
+
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// EscalateTimeoutTickets 升级所有已超时的审批单，单个审批单升级失败不影响其他审批单，由周期性任务按定时任务流触发
+func EscalateTimeoutTickets(kt *kit.Kit, cli *client.ClientSet, lgc Interface, now time.Time) error {
+	// 升级会清空或改变审批单的超时时间，使其移出查询结果，因此按id游标分页，避免使用偏移量分页时跳过部分审批单
+	lastID := ""
+	for {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("status", enumor.Pending),
+				&filter.AtomRule{Field: "deadline", Op: filter.NotEqual.Factory(), Value: ""},
+				tools.RuleGreaterThan("id", lastID),
+			),
+			Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Ascending},
+		}
+		resp, err := cli.DataService().Global.Approval.ListTicket(kt, listReq)
+		if err != nil {
+			logs.Errorf("list pending approval ticket failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		for idx := range resp.Details {
+			ticket := &resp.Details[idx]
+			if !IsTimeout(ticket, now) {
+				continue
+			}
+
+			_, err = lgc.Operate(kt.NewSubKit(), ticket.ApplicationID, func(ticket *approval.Ticket) error {
+				return Escalate(ticket, now)
+			})
+			if err != nil {
+				logs.Errorf("escalate approval ticket %s failed, err: %v, rid: %s", ticket.ID, err, kt.Rid)
+				continue
+			}
+			logs.Infof("approval ticket %s stage %d timeout, escalated to %v, rid: %s", ticket.ID,
+				ticket.CurrentStage, ticket.Stages[ticket.CurrentStage].EscalateTo, kt.Rid)
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			return nil
+		}
+		lastID = resp.Details[len(resp.Details)-1].ID
+	}
+}
@@ -0,0 +1,656 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package assignrule 资源自动分配规则，按规则将同步到的未分配资源自动分配到业务
+package assignrule
+
+import (
+	"fmt"
+	"time"
+
+	logicaudit "hcm/cmd/cloud-server/logics/audit"
+	logicscvm "hcm/cmd/cloud-server/logics/cvm"
+	logicsdisk "hcm/cmd/cloud-server/logics/disk"
+	logicseip "hcm/cmd/cloud-server/logics/eip"
+	proto "hcm/pkg/api/cloud-server/assign"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// assignResTypes 按顺序执行分配的资源类型，主机先分配，其关联的硬盘和EIP会随主机一起分配
+var assignResTypes = []enumor.CloudResourceType{enumor.CvmCloudResType, enumor.DiskCloudResType,
+	enumor.EipCloudResType}
+
+// assignCursorOverlap 增量匹配时向前多匹配的时间，避免服务间时钟偏差导致漏掉新创建的资源，
+// 重复匹配的资源如果已分配会因不再是未分配资源而被跳过
+const assignCursorOverlap = 5 * time.Minute
+
+// RunOption defines options to run res assign rules.
+type RunOption struct {
+	// RuleIDs 指定执行的规则，为空时执行所有已启用的规则
+	RuleIDs []string
+	// DryRun 为 true 时只返回匹配结果，不执行分配
+	DryRun bool
+	// Incremental 为 true 时只匹配上次增量执行后创建的资源，用于资源同步后自动分配新同步的资源
+	Incremental bool
+}
+
+// Run 按规则匹配未分配的主机、硬盘和EIP，非预览模式下将匹配的资源分配到规则指定的业务，
+// 每次分配都会通过对应资源的分配逻辑生成分配审计。
+func Run(kt *kit.Kit, cli *dataservice.Client, opt *RunOption) (*proto.ResAssignRuleResult, error) {
+	matchers, err := listMatchers(kt, cli, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &runner{
+		kt:       kt,
+		cli:      cli,
+		matchers: matchers,
+		since:    make(map[enumor.CloudResourceType]time.Time),
+		result: &proto.ResAssignRuleResult{
+			Matched:   make([]proto.ResAssignMatch, 0),
+			Conflicts: make([]proto.ResAssignConflict, 0),
+			Failed:    make([]proto.ResAssignFailure, 0),
+		},
+	}
+
+	incremental := opt.Incremental && !opt.DryRun
+	if incremental {
+		if r.since, err = listCursors(kt, cli); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, resType := range assignResTypes {
+		accountIDs := r.accountIDs(resType)
+		if len(accountIDs) == 0 {
+			continue
+		}
+
+		// 先记录游标再匹配，匹配过程中新创建的资源留到下次执行
+		cursor := time.Now().Add(-assignCursorOverlap)
+
+		// 先匹配所有资源再分配，避免分配过程中未分配资源的分页发生变化
+		assignments, err := r.match(resType, accountIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		if !opt.DryRun && len(assignments) != 0 {
+			r.assign(resType, assignments)
+		}
+
+		if incremental {
+			setCursor(kt, cli, resType, cursor)
+		}
+	}
+
+	return r.result, nil
+}
+
+// listCursors 查询各资源类型上次增量匹配到的资源创建时间点
+func listCursors(kt *kit.Kit, cli *dataservice.Client) (map[enumor.CloudResourceType]time.Time, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("type", enumor.ResAssignRuleCursor),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"scope", "cursor_value"},
+	}
+	result, err := cli.Global.SyncCursor.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list res assign rule cursor failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	cursors := make(map[enumor.CloudResourceType]time.Time, len(result.Details))
+	for _, one := range result.Details {
+		cursor, err := time.Parse(constant.TimeStdFormat, one.CursorValue)
+		if err != nil {
+			// 游标无效时按全量匹配处理，匹配成功后会重新设置游标
+			logs.Errorf("parse res assign rule cursor failed, err: %v, scope: %s, value: %s, rid: %s", err,
+				one.Scope, one.CursorValue, kt.Rid)
+			continue
+		}
+		cursors[enumor.CloudResourceType(one.Scope)] = cursor
+	}
+
+	return cursors, nil
+}
+
+// setCursor 设置资源类型增量匹配到的时间点，设置失败时下次执行会重复匹配，只记录日志
+func setCursor(kt *kit.Kit, cli *dataservice.Client, resType enumor.CloudResourceType, cursor time.Time) {
+	setReq := &dssync.SyncCursorBatchSetReq{Items: []dssync.SyncCursorSetField{{
+		Type:        enumor.ResAssignRuleCursor,
+		Scope:       string(resType),
+		CursorValue: cursor.Format(constant.TimeStdFormat),
+	}}}
+	if err := cli.Global.SyncCursor.BatchSet(kt, setReq); err != nil {
+		logs.Errorf("set res assign rule cursor failed, err: %v, res type: %s, rid: %s", err, resType, kt.Rid)
+	}
+}
+
+// AutoAssign 执行所有已启用的规则，用于资源同步后自动分配上次执行后新同步的资源
+func AutoAssign(kt *kit.Kit, cli *dataservice.Client) {
+	result, err := Run(kt, cli, &RunOption{Incremental: true})
+	if err != nil {
+		logs.Errorf("auto assign resource by rules failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+
+	for _, one := range result.Conflicts {
+		logs.Warnf("%s %s matched conflict assign rules %v, skip assign, rid: %s", one.ResType, one.ResID,
+			one.RuleIDs, kt.Rid)
+	}
+
+	for _, one := range result.Failed {
+		logs.Errorf("auto assign %s %s by rule %s failed, reason: %s, rid: %s", one.ResType, one.ResID, one.RuleID,
+			one.Reason, kt.Rid)
+	}
+
+	if len(result.Matched) != 0 {
+		logs.Infof("auto assign resource by rules done, matched: %d, assigned: %d, conflict: %d, failed: %d, rid: %s",
+			len(result.Matched), result.AssignedCount, len(result.Conflicts), len(result.Failed), kt.Rid)
+	}
+}
+
+func listMatchers(kt *kit.Kit, cli *dataservice.Client, opt *RunOption) ([]*matcher, error) {
+	rules := []*filter.AtomRule{tools.RuleEqual("enabled", true)}
+	if len(opt.RuleIDs) != 0 {
+		rules = []*filter.AtomRule{tools.RuleIn("id", opt.RuleIDs)}
+		// 预览时允许指定未启用的规则，方便启用前确认规则效果
+		if !opt.DryRun {
+			rules = append(rules, tools.RuleEqual("enabled", true))
+		}
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   core.NewDefaultBasePage(),
+	}
+	matchers := make([]*matcher, 0)
+	for {
+		resp, err := cli.Global.ResAssignRule.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list res assign rule failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, rule := range resp.Details {
+			m, err := newMatcher(rule)
+			if err != nil {
+				logs.Errorf("res assign rule %s is invalid, skip it, err: %v, rid: %s", rule.ID, err, kt.Rid)
+				continue
+			}
+			matchers = append(matchers, m)
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	sortMatchers(matchers)
+	return matchers, nil
+}
+
+type runner struct {
+	kt       *kit.Kit
+	cli      *dataservice.Client
+	matchers []*matcher
+	// since 增量匹配时各资源类型只匹配该时间点后创建的资源
+	since map[enumor.CloudResourceType]time.Time
+	// cvmCloudArea 本次需要绑定管控区域的主机及规则指定的管控区域
+	cvmCloudArea map[string]int64
+	result       *proto.ResAssignRuleResult
+}
+
+// assignment 待分配的资源及其生效的规则
+type assignment struct {
+	res  *candidate
+	rule corecloud.ResAssignRule
+}
+
+func (r *runner) accountIDs(resType enumor.CloudResourceType) []string {
+	accountIDs := make([]string, 0)
+	for _, m := range r.matchers {
+		if _, ok := m.resTypes[resType]; ok {
+			accountIDs = append(accountIDs, m.rule.Conditions.AccountIDs...)
+		}
+	}
+	return slice.Unique(accountIDs)
+}
+
+func (r *runner) needTags(resType enumor.CloudResourceType) bool {
+	for _, m := range r.matchers {
+		if _, ok := m.resTypes[resType]; ok && m.needTags() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *runner) match(resType enumor.CloudResourceType, accountIDs []string) ([]assignment, error) {
+	assignments := make([]assignment, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		candidates, listed, err := r.listCandidates(resType, accountIDs, page)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.needTags(resType) {
+			if err = r.fillTags(resType, candidates); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, c := range candidates {
+			d := decide(r.matchers, c)
+			if len(d.conflicts) != 0 {
+				r.result.Conflicts = append(r.result.Conflicts, proto.ResAssignConflict{
+					ResType:   c.ResType,
+					ResID:     c.ID,
+					Name:      c.Name,
+					AccountID: c.AccountID,
+					RuleIDs:   matcherIDs(d.conflicts),
+				})
+				continue
+			}
+
+			if d.winner == nil {
+				continue
+			}
+
+			r.result.Matched = append(r.result.Matched, proto.ResAssignMatch{
+				ResType:        c.ResType,
+				ResID:          c.ID,
+				Name:           c.Name,
+				AccountID:      c.AccountID,
+				Region:         c.Region,
+				RuleID:         d.winner.rule.ID,
+				BkBizID:        d.winner.rule.BkBizID,
+				BkCloudID:      d.winner.rule.BkCloudID,
+				MatchedRuleIDs: matcherIDs(d.matched),
+			})
+			assignments = append(assignments, assignment{res: c, rule: d.winner.rule})
+		}
+
+		if uint(listed) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return assignments, nil
+}
+
+func matcherIDs(matchers []*matcher) []string {
+	ids := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		ids = append(ids, m.rule.ID)
+	}
+	return ids
+}
+
+// listCandidates 查询未分配业务的资源，返回待匹配的资源和本页查询到的资源数量，
+// 硬盘和EIP只处理未绑定主机的，已绑定的随主机一起分配。
+func (r *runner) listCandidates(resType enumor.CloudResourceType, accountIDs []string, page *core.BasePage) (
+	[]*candidate, int, error) {
+
+	rules := []*filter.AtomRule{
+		tools.RuleIn("account_id", accountIDs),
+		tools.RuleEqual("bk_biz_id", constant.UnassignedBiz),
+	}
+	if since, exists := r.since[resType]; exists {
+		rules = append(rules, tools.RuleGreaterThanEqual("created_at", since.Format(constant.TimeStdFormat)))
+	}
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   page,
+	}
+
+	switch resType {
+	case enumor.CvmCloudResType:
+		listReq.Fields = []string{"id", "name", "account_id", "region", "bk_cloud_id", "cloud_vpc_ids", "vpc_ids",
+			"private_ipv4_addresses", "public_ipv4_addresses"}
+		resp, err := r.cli.Global.Cvm.ListCvm(r.kt, listReq)
+		if err != nil {
+			logs.Errorf("list unassigned cvm failed, err: %v, rid: %s", err, r.kt.Rid)
+			return nil, 0, err
+		}
+
+		candidates := make([]*candidate, 0, len(resp.Details))
+		for _, one := range resp.Details {
+			candidates = append(candidates, &candidate{
+				ResType:     resType,
+				ID:          one.ID,
+				Name:        one.Name,
+				AccountID:   one.AccountID,
+				Region:      one.Region,
+				CloudVpcIDs: one.CloudVpcIDs,
+				VpcIDs:      one.VpcIDs,
+				IPs:         append(one.PrivateIPv4Addresses, one.PublicIPv4Addresses...),
+				BkCloudID:   one.BkCloudID,
+			})
+		}
+		return candidates, len(resp.Details), nil
+
+	case enumor.DiskCloudResType:
+		listReq.Fields = []string{"id", "name", "account_id", "region"}
+		resp, err := r.cli.Global.ListDisk(r.kt, listReq)
+		if err != nil {
+			logs.Errorf("list unassigned disk failed, err: %v, rid: %s", err, r.kt.Rid)
+			return nil, 0, err
+		}
+
+		candidates := make([]*candidate, 0, len(resp.Details))
+		for _, one := range resp.Details {
+			candidates = append(candidates, &candidate{ResType: resType, ID: one.ID, Name: one.Name,
+				AccountID: one.AccountID, Region: one.Region})
+		}
+		candidates, err = r.excludeBound(resType, candidates)
+		return candidates, len(resp.Details), err
+
+	case enumor.EipCloudResType:
+		listReq.Fields = []string{"id", "name", "account_id", "region", "public_ip"}
+		resp, err := r.cli.Global.ListEip(r.kt, listReq)
+		if err != nil {
+			logs.Errorf("list unassigned eip failed, err: %v, rid: %s", err, r.kt.Rid)
+			return nil, 0, err
+		}
+
+		candidates := make([]*candidate, 0, len(resp.Details))
+		for _, one := range resp.Details {
+			candidates = append(candidates, &candidate{ResType: resType, ID: one.ID,
+				Name: converter.PtrToVal(one.Name), AccountID: one.AccountID, Region: one.Region,
+				IPs: []string{one.PublicIp}})
+		}
+		candidates, err = r.excludeBound(resType, candidates)
+		return candidates, len(resp.Details), err
+
+	default:
+		return nil, 0, fmt.Errorf("res type %s does not support auto assign", resType)
+	}
+}
+
+// excludeBound 排除已绑定主机的硬盘和EIP
+func (r *runner) excludeBound(resType enumor.CloudResourceType, candidates []*candidate) ([]*candidate, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, one := range candidates {
+		ids = append(ids, one.ID)
+	}
+
+	bound := make(map[string]struct{})
+	switch resType {
+	case enumor.DiskCloudResType:
+		resp, err := r.cli.Global.ListDiskCvmRel(r.kt, &core.ListReq{
+			Filter: tools.ContainersExpression("disk_id", ids),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list disk cvm rel failed, err: %v, rid: %s", err, r.kt.Rid)
+			return nil, err
+		}
+		for _, rel := range resp.Details {
+			bound[rel.DiskID] = struct{}{}
+		}
+
+	case enumor.EipCloudResType:
+		resp, err := r.cli.Global.ListEipCvmRel(r.kt, &core.ListReq{
+			Filter: tools.ContainersExpression("eip_id", ids),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list eip cvm rel failed, err: %v, rid: %s", err, r.kt.Rid)
+			return nil, err
+		}
+		for _, rel := range resp.Details {
+			bound[rel.EipID] = struct{}{}
+		}
+	}
+
+	unbound := make([]*candidate, 0, len(candidates))
+	for _, one := range candidates {
+		if _, ok := bound[one.ID]; !ok {
+			unbound = append(unbound, one)
+		}
+	}
+
+	return unbound, nil
+}
+
+// fillTags 从统一资源标签表中查询资源的标签
+func (r *runner) fillTags(resType enumor.CloudResourceType, candidates []*candidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	resMap := make(map[string]*candidate, len(candidates))
+	for _, one := range candidates {
+		one.Tags = make(map[string]string)
+		resMap[one.ID] = one
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", resType),
+			tools.RuleIn("res_id", converter.MapKeyToSlice(resMap)),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"res_id", "key", "value"},
+	}
+	for {
+		resp, err := r.cli.Global.ResourceTag.List(r.kt, listReq)
+		if err != nil {
+			logs.Errorf("list %s resource tag failed, err: %v, rid: %s", resType, err, r.kt.Rid)
+			return err
+		}
+
+		for _, tag := range resp.Details {
+			if res, ok := resMap[tag.ResID]; ok {
+				res.Tags[tag.Key] = tag.Value
+			}
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return nil
+}
+
+func (r *runner) assign(resType enumor.CloudResourceType, assignments []assignment) {
+	switch resType {
+	case enumor.CvmCloudResType:
+		r.cvmCloudArea = make(map[string]int64)
+		for _, one := range assignments {
+			if one.res.BkCloudID == constant.UnbindBkCloudID && one.rule.BkCloudID >= 0 {
+				r.cvmCloudArea[one.res.ID] = one.rule.BkCloudID
+			}
+		}
+
+		// 主机分配时会校验并分配其关联的资源，逐个分配避免单个主机校验失败影响其他主机
+		for _, one := range assignments {
+			if err := r.assignCvm(one); err != nil {
+				r.fail(one, err)
+				continue
+			}
+			r.result.AssignedCount++
+		}
+
+	case enumor.DiskCloudResType, enumor.EipCloudResType:
+		groups := make(map[int64][]assignment)
+		for _, one := range assignments {
+			groups[one.rule.BkBizID] = append(groups[one.rule.BkBizID], one)
+		}
+
+		for bizID, group := range groups {
+			for _, batch := range slice.Split(group, constant.BatchOperationMaxLimit) {
+				ids := make([]string, 0, len(batch))
+				for _, one := range batch {
+					ids = append(ids, one.res.ID)
+				}
+
+				var err error
+				if resType == enumor.DiskCloudResType {
+					err = logicsdisk.Assign(r.kt, r.cli, ids, uint64(bizID), false)
+				} else {
+					err = logicseip.Assign(r.kt, r.cli, ids, uint64(bizID), false)
+				}
+				if err != nil {
+					for _, one := range batch {
+						r.fail(one, err)
+					}
+					continue
+				}
+				r.result.AssignedCount += len(batch)
+			}
+		}
+	}
+}
+
+// assignCvm 分配主机，主机未绑定管控区域时，先将其所在VPC绑定到规则指定的管控区域
+func (r *runner) assignCvm(one assignment) error {
+	if one.res.BkCloudID == constant.UnbindBkCloudID {
+		if one.rule.BkCloudID < 0 {
+			return fmt.Errorf("cvm is not bound to cloud area and rule does not specify bk_cloud_id")
+		}
+
+		if err := r.bindVpcCloudArea(one.res.VpcIDs, one.rule.BkCloudID); err != nil {
+			return err
+		}
+	}
+
+	return logicscvm.Assign(r.kt, r.cli, []string{one.res.ID}, one.rule.BkBizID)
+}
+
+// bindVpcCloudArea 将未分配且未绑定管控区域的VPC绑定到管控区域，VPC绑定后其下主机的管控区域会同步更新，
+// 因此只有VPC下的主机都匹配到了同一管控区域时才绑定，避免修改未被规则匹配的主机的管控区域
+func (r *runner) bindVpcCloudArea(vpcIDs []string, bkCloudID int64) error {
+	if len(vpcIDs) == 0 {
+		return fmt.Errorf("cvm has no vpc to bind cloud area")
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("id", vpcIDs),
+			tools.RuleEqual("bk_biz_id", constant.UnassignedBiz),
+			tools.RuleEqual("bk_cloud_id", constant.UnbindBkCloudID),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	resp, err := r.cli.Global.Vpc.List(r.kt.Ctx, r.kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list unbound vpc failed, err: %v, ids: %v, rid: %s", err, vpcIDs, r.kt.Rid)
+		return err
+	}
+
+	// 同一VPC下的主机已经触发过绑定
+	if len(resp.Details) == 0 {
+		return nil
+	}
+
+	unboundIDs := make([]string, 0, len(resp.Details))
+	for _, vpc := range resp.Details {
+		unboundIDs = append(unboundIDs, vpc.ID)
+	}
+	if err = r.checkVpcCvmMatched(unboundIDs, bkCloudID); err != nil {
+		return err
+	}
+
+	auditOpt := make([]logicaudit.ResCloudAreaBindOption, 0, len(resp.Details))
+	updates := make([]dataproto.VpcBaseInfoUpdateReq, 0, len(resp.Details))
+	for _, vpc := range resp.Details {
+		auditOpt = append(auditOpt, logicaudit.ResCloudAreaBindOption{ResID: vpc.ID, CloudID: bkCloudID})
+		updates = append(updates, dataproto.VpcBaseInfoUpdateReq{
+			IDs:  []string{vpc.ID},
+			Data: &dataproto.VpcUpdateBaseInfo{BkCloudID: bkCloudID},
+		})
+	}
+
+	if err = logicaudit.NewAudit(r.cli).ResCloudAreaBindAudit(r.kt, enumor.VpcCloudAuditResType,
+		auditOpt); err != nil {
+		logs.Errorf("create vpc bind cloud area audit failed, err: %v, rid: %s", err, r.kt.Rid)
+		return err
+	}
+
+	updateReq := &dataproto.VpcBaseInfoBatchUpdateReq{Vpcs: updates}
+	if err = r.cli.Global.Vpc.BatchUpdateBaseInfo(r.kt.Ctx, r.kt.Header(), updateReq); err != nil {
+		logs.Errorf("bind vpc with cloud area failed, err: %v, ids: %v, rid: %s", err, vpcIDs, r.kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// checkVpcCvmMatched 校验VPC下的主机都匹配到了指定的管控区域
+func (r *runner) checkVpcCvmMatched(vpcIDs []string, bkCloudID int64) error {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleJsonOverlaps("vpc_ids", vpcIDs)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	for {
+		resp, err := r.cli.Global.Cvm.ListCvm(r.kt, listReq)
+		if err != nil {
+			logs.Errorf("list vpc cvm failed, err: %v, vpc ids: %v, rid: %s", err, vpcIDs, r.kt.Rid)
+			return err
+		}
+
+		for _, one := range resp.Details {
+			if cloudID, exists := r.cvmCloudArea[one.ID]; !exists || cloudID != bkCloudID {
+				return fmt.Errorf("vpc %v has cvm %s not matched to cloud area %d, skip binding vpc cloud area",
+					vpcIDs, one.ID, bkCloudID)
+			}
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			return nil
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+}
+
+func (r *runner) fail(one assignment, err error) {
+	r.result.Failed = append(r.result.Failed, proto.ResAssignFailure{
+		ResType: one.res.ResType,
+		ResID:   one.res.ID,
+		RuleID:  one.rule.ID,
+		Reason:  err.Error(),
+	})
+}
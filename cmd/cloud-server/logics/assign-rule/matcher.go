@@ -0,0 +1,226 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assignrule
+
+import (
+	"net"
+	"regexp"
+	"sort"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+)
+
+// candidate 待分配的资源
+type candidate struct {
+	ResType   enumor.CloudResourceType
+	ID        string
+	Name      string
+	AccountID string
+	Region    string
+	// CloudVpcIDs 资源所在的VPC，仅主机有
+	CloudVpcIDs []string
+	// VpcIDs 资源所在VPC在hcm中的ID，仅主机有
+	VpcIDs []string
+	// IPs 主机内网IP或弹性IP地址
+	IPs       []string
+	Tags      map[string]string
+	BkCloudID int64
+}
+
+// matcher 编译后的分配规则
+type matcher struct {
+	rule      corecloud.ResAssignRule
+	resTypes  map[enumor.CloudResourceType]struct{}
+	accounts  map[string]struct{}
+	regions   map[string]struct{}
+	vpcs      map[string]struct{}
+	nameRegex *regexp.Regexp
+	cidrs     []*net.IPNet
+}
+
+func newMatcher(rule corecloud.ResAssignRule) (*matcher, error) {
+	m := &matcher{
+		rule:     rule,
+		resTypes: make(map[enumor.CloudResourceType]struct{}, len(rule.ResTypes)),
+		accounts: toSet(rule.Conditions.AccountIDs),
+		regions:  toSet(rule.Conditions.Regions),
+		vpcs:     toSet(rule.Conditions.CloudVpcIDs),
+		cidrs:    make([]*net.IPNet, 0, len(rule.Conditions.Cidrs)),
+	}
+
+	for _, resType := range rule.ResTypes {
+		m.resTypes[resType] = struct{}{}
+	}
+
+	if len(rule.Conditions.NameRegex) != 0 {
+		reg, err := regexp.Compile(rule.Conditions.NameRegex)
+		if err != nil {
+			return nil, err
+		}
+		m.nameRegex = reg
+	}
+
+	for _, cidr := range rule.Conditions.Cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		m.cidrs = append(m.cidrs, ipNet)
+	}
+
+	return m, nil
+}
+
+// needTags 规则是否包含标签条件
+func (m *matcher) needTags() bool {
+	return len(m.rule.Conditions.Tags) != 0
+}
+
+// match 资源是否满足规则的全部条件
+func (m *matcher) match(c *candidate) bool {
+	if _, ok := m.resTypes[c.ResType]; !ok {
+		return false
+	}
+
+	if _, ok := m.accounts[c.AccountID]; !ok {
+		return false
+	}
+
+	if len(m.regions) != 0 {
+		if _, ok := m.regions[c.Region]; !ok {
+			return false
+		}
+	}
+
+	if len(m.vpcs) != 0 && !containsAny(m.vpcs, c.CloudVpcIDs) {
+		return false
+	}
+
+	if m.nameRegex != nil && !m.nameRegex.MatchString(c.Name) {
+		return false
+	}
+
+	for _, tag := range m.rule.Conditions.Tags {
+		value, ok := c.Tags[tag.Key]
+		if !ok || value != tag.Value {
+			return false
+		}
+	}
+
+	if len(m.cidrs) != 0 && !m.containsIP(c.IPs) {
+		return false
+	}
+
+	return true
+}
+
+func (m *matcher) containsIP(ips []string) bool {
+	for _, one := range ips {
+		ip := net.ParseIP(one)
+		if ip == nil {
+			continue
+		}
+
+		for _, ipNet := range m.cidrs {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sameTarget 两条规则的分配目标是否相同
+func (m *matcher) sameTarget(other *matcher) bool {
+	return m.rule.BkBizID == other.rule.BkBizID && m.rule.BkCloudID == other.rule.BkCloudID
+}
+
+// sortMatchers 按优先级排序，数值越小优先级越高，优先级相同时按规则ID排序
+func sortMatchers(matchers []*matcher) {
+	sort.SliceStable(matchers, func(i, j int) bool {
+		if matchers[i].rule.Priority != matchers[j].rule.Priority {
+			return matchers[i].rule.Priority < matchers[j].rule.Priority
+		}
+		return matchers[i].rule.ID < matchers[j].rule.ID
+	})
+}
+
+// decision 资源的规则匹配结果
+type decision struct {
+	// winner 生效的规则，存在冲突或未命中任何规则时为空
+	winner *matcher
+	// matched 命中的全部规则，按优先级排序
+	matched []*matcher
+	// conflicts 与生效规则优先级相同但分配目标不同的规则，不为空时资源不会被分配
+	conflicts []*matcher
+}
+
+// decide 按优先级匹配资源，命中的最高优先级规则生效，最高优先级有多条规则命中且分配目标不同时视为冲突。
+// matchers 需要已经按优先级排序。
+func decide(matchers []*matcher, c *candidate) *decision {
+	d := new(decision)
+	for _, m := range matchers {
+		if m.match(c) {
+			d.matched = append(d.matched, m)
+		}
+	}
+
+	if len(d.matched) == 0 {
+		return d
+	}
+
+	top := d.matched[0]
+	for _, m := range d.matched[1:] {
+		if m.rule.Priority != top.rule.Priority {
+			break
+		}
+
+		if !m.sameTarget(top) {
+			d.conflicts = append(d.conflicts, m)
+		}
+	}
+
+	if len(d.conflicts) != 0 {
+		d.conflicts = append([]*matcher{top}, d.conflicts...)
+		return d
+	}
+
+	d.winner = top
+	return d
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, one := range values {
+		set[one] = struct{}{}
+	}
+	return set
+}
+
+func containsAny(set map[string]struct{}, values []string) bool {
+	for _, one := range values {
+		if _, ok := set[one]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assignrule
+
+import (
+	"testing"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+)
+
+func newTestMatcher(t *testing.T, id string, priority, bizID int64, cond corecloud.ResAssignRuleCondition) *matcher {
+	m, err := newMatcher(corecloud.ResAssignRule{
+		ID:         id,
+		ResTypes:   []enumor.CloudResourceType{enumor.CvmCloudResType, enumor.EipCloudResType},
+		Priority:   priority,
+		Enabled:    true,
+		Conditions: cond,
+		BkBizID:    bizID,
+		BkCloudID:  -1,
+	})
+	if err != nil {
+		t.Fatalf("new matcher failed, err: %v", err)
+	}
+	return m
+}
+
+func TestMatch(t *testing.T) {
+	m := newTestMatcher(t, "r1", 0, 1, corecloud.ResAssignRuleCondition{
+		AccountIDs:  []string{"a1"},
+		Regions:     []string{"ap-guangzhou"},
+		CloudVpcIDs: []string{"vpc-1"},
+		NameRegex:   "^web-",
+		Tags:        []core.TagPair{{Key: "env", Value: "prod"}},
+		Cidrs:       []string{"10.0.0.0/16"},
+	})
+
+	res := &candidate{
+		ResType:     enumor.CvmCloudResType,
+		Name:        "web-01",
+		AccountID:   "a1",
+		Region:      "ap-guangzhou",
+		CloudVpcIDs: []string{"vpc-1"},
+		IPs:         []string{"10.0.1.2"},
+		Tags:        map[string]string{"env": "prod", "app": "web"},
+	}
+	if !m.match(res) {
+		t.Errorf("candidate should match rule")
+	}
+
+	cases := map[string]func(c candidate) candidate{
+		"res_type": func(c candidate) candidate { c.ResType = enumor.DiskCloudResType; return c },
+		"account":  func(c candidate) candidate { c.AccountID = "a2"; return c },
+		"region":   func(c candidate) candidate { c.Region = "ap-shanghai"; return c },
+		"vpc":      func(c candidate) candidate { c.CloudVpcIDs = []string{"vpc-2"}; return c },
+		"name":     func(c candidate) candidate { c.Name = "db-01"; return c },
+		"tag":      func(c candidate) candidate { c.Tags = map[string]string{"env": "test"}; return c },
+		"cidr":     func(c candidate) candidate { c.IPs = []string{"192.168.0.1"}; return c },
+	}
+	for name, modify := range cases {
+		c := modify(*res)
+		if m.match(&c) {
+			t.Errorf("candidate with mismatched %s should not match rule", name)
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cond := corecloud.ResAssignRuleCondition{AccountIDs: []string{"a1"}}
+	res := &candidate{ResType: enumor.CvmCloudResType, AccountID: "a1"}
+
+	// 优先级高的规则生效
+	matchers := []*matcher{newTestMatcher(t, "r2", 10, 2, cond), newTestMatcher(t, "r1", 1, 1, cond)}
+	sortMatchers(matchers)
+	d := decide(matchers, res)
+	if d.winner == nil || d.winner.rule.ID != "r1" || len(d.matched) != 2 || len(d.conflicts) != 0 {
+		t.Errorf("rule r1 should win, got: %+v", d)
+	}
+
+	// 同优先级且分配目标相同时不冲突
+	matchers = []*matcher{newTestMatcher(t, "r1", 1, 1, cond), newTestMatcher(t, "r3", 1, 1, cond)}
+	d = decide(matchers, res)
+	if d.winner == nil || d.winner.rule.ID != "r1" {
+		t.Errorf("rule r1 should win, got: %+v", d)
+	}
+
+	// 同优先级且分配目标不同时冲突
+	matchers = []*matcher{newTestMatcher(t, "r1", 1, 1, cond), newTestMatcher(t, "r4", 1, 2, cond)}
+	d = decide(matchers, res)
+	if d.winner != nil || len(d.conflicts) != 2 {
+		t.Errorf("rule r1 and r4 should conflict, got: %+v", d)
+	}
+}
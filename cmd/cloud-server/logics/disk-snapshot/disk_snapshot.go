@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot 云硬盘快照相关逻辑，包括快照的创建、删除、回滚以及快照策略的定时执行
+package disksnapshot
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/pkg/api/core"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// Interface define disk snapshot interface.
+type Interface interface {
+	Create(kt *kit.Kit, vendor enumor.Vendor, req *hcproto.CreateReq) (*core.CreateResult, error)
+	BatchDelete(kt *kit.Kit, vendor enumor.Vendor, accountID string, ids []string) error
+	Rollback(kt *kit.Kit, vendor enumor.Vendor, req *hcproto.RollbackReq, diskID string) error
+}
+
+type diskSnapshot struct {
+	client *client.ClientSet
+	audit  audit.Interface
+}
+
+// NewDiskSnapshot new disk snapshot.
+func NewDiskSnapshot(client *client.ClientSet, audit audit.Interface) Interface {
+	return &diskSnapshot{
+		client: client,
+		audit:  audit,
+	}
+}
+
+// Create disk snapshot, the create audit is generated by data-service when the snapshot is saved.
+func (d *diskSnapshot) Create(kt *kit.Kit, vendor enumor.Vendor, req *hcproto.CreateReq) (*core.CreateResult,
+	error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return d.client.HCService().TCloud.DiskSnapshot.Create(kt, req)
+	case enumor.Aws:
+		return d.client.HCService().Aws.DiskSnapshot.Create(kt, req)
+	case enumor.HuaWei:
+		return d.client.HCService().HuaWei.DiskSnapshot.Create(kt, req)
+	case enumor.Gcp:
+		return d.client.HCService().Gcp.DiskSnapshot.Create(kt, req)
+	case enumor.Azure:
+		return d.client.HCService().Azure.DiskSnapshot.Create(kt, req)
+	default:
+		return nil, errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("no support vendor: %s", vendor))
+	}
+}
+
+// BatchDelete delete disk snapshots of the same account.
+func (d *diskSnapshot) BatchDelete(kt *kit.Kit, vendor enumor.Vendor, accountID string, ids []string) error {
+	// create delete audit.
+	if err := d.audit.ResDeleteAudit(kt, enumor.DiskSnapshotAuditResType, ids); err != nil {
+		logs.Errorf("create delete audit failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	req := &hcproto.BatchDeleteReq{AccountID: accountID, IDs: ids}
+
+	switch vendor {
+	case enumor.TCloud:
+		return d.client.HCService().TCloud.DiskSnapshot.BatchDelete(kt, req)
+	case enumor.Aws:
+		return d.client.HCService().Aws.DiskSnapshot.BatchDelete(kt, req)
+	case enumor.HuaWei:
+		return d.client.HCService().HuaWei.DiskSnapshot.BatchDelete(kt, req)
+	case enumor.Gcp:
+		return d.client.HCService().Gcp.DiskSnapshot.BatchDelete(kt, req)
+	case enumor.Azure:
+		return d.client.HCService().Azure.DiskSnapshot.BatchDelete(kt, req)
+	default:
+		return errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("no support vendor: %s", vendor))
+	}
+}
+
+// Rollback source disk with disk snapshot.
+func (d *diskSnapshot) Rollback(kt *kit.Kit, vendor enumor.Vendor, req *hcproto.RollbackReq, diskID string) error {
+	// create audit
+	operationInfo := protoaudit.CloudResourceOperationInfo{
+		ResType:           enumor.DiskSnapshotAuditResType,
+		ResID:             req.ID,
+		Action:            protoaudit.Rollback,
+		AssociatedResType: enumor.DiskAuditResType,
+		AssociatedResID:   diskID,
+	}
+	if err := d.audit.ResOperationAudit(kt, operationInfo); err != nil {
+		logs.Errorf("create rollback disk snapshot audit failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	switch vendor {
+	case enumor.TCloud:
+		return d.client.HCService().TCloud.DiskSnapshot.Rollback(kt, req)
+	case enumor.Aws:
+		return d.client.HCService().Aws.DiskSnapshot.Rollback(kt, req)
+	case enumor.HuaWei:
+		return d.client.HCService().HuaWei.DiskSnapshot.Rollback(kt, req)
+	case enumor.Gcp:
+		return d.client.HCService().Gcp.DiskSnapshot.Rollback(kt, req)
+	case enumor.Azure:
+		return d.client.HCService().Azure.DiskSnapshot.Rollback(kt, req)
+	default:
+		return errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("no support vendor: %s", vendor))
+	}
+}
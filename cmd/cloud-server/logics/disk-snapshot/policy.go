@@ -0,0 +1,252 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// RunDuePolicies 执行所有已启用且到期的快照策略，单个策略执行失败不影响其他策略，由周期性任务按定时任务流触发
+func RunDuePolicies(kt *kit.Kit, cli *client.ClientSet, lgc Interface, now time.Time) error {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("enabled", true),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.DataService().Global.DiskSnapshot.ListPolicy(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot policy failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		for _, policy := range resp.Details {
+			if !isPolicyDue(policy, now) {
+				continue
+			}
+
+			if err = runPolicy(kt.NewSubKit(), cli, lgc, policy, now); err != nil {
+				logs.Errorf("run disk snapshot policy %s failed, err: %v, rid: %s", policy.ID, err, kt.Rid)
+			}
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			return nil
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+}
+
+// isPolicyDue 策略从未执行或距离上次执行已超过间隔时间时到期
+func isPolicyDue(policy corecloud.DiskSnapshotPolicy, now time.Time) bool {
+	if !policy.Enabled {
+		return false
+	}
+
+	if len(policy.LastExecutedAt) == 0 {
+		return true
+	}
+
+	last, err := time.Parse(constant.TimeStdFormat, policy.LastExecutedAt)
+	if err != nil {
+		logs.Warnf("parse disk snapshot policy %s last executed time %s failed, err: %v", policy.ID,
+			policy.LastExecutedAt, err)
+		return true
+	}
+
+	return !now.Before(last.Add(time.Duration(policy.IntervalHours) * time.Hour))
+}
+
+func runPolicy(kt *kit.Kit, cli *client.ClientSet, lgc Interface, policy corecloud.DiskSnapshotPolicy,
+	now time.Time) error {
+
+	disks, err := listPolicyDisks(kt, cli, policy)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, disk := range disks {
+		req := &hcproto.CreateReq{
+			AccountID: disk.AccountID,
+			DiskID:    disk.ID,
+			Name:      genPolicySnapshotName(disk.ID, now),
+			PolicyID:  policy.ID,
+		}
+		if _, err = lgc.Create(kt, enumor.Vendor(disk.Vendor), req); err != nil {
+			logs.Errorf("create disk %s snapshot by policy %s failed, err: %v, rid: %s", disk.ID, policy.ID, err,
+				kt.Rid)
+			failed++
+		}
+	}
+
+	// 快照创建失败时也需要清理超出保留数量的快照
+	if err = cleanExpiredSnapshots(kt, cli, lgc, policy); err != nil {
+		return err
+	}
+
+	updateReq := &dataproto.DiskSnapshotPolicyBatchUpdateReq{
+		Policies: []dataproto.DiskSnapshotPolicyUpdate{{
+			ID:             policy.ID,
+			LastExecutedAt: times.ConvStdTimeFormat(now),
+		}},
+	}
+	if err = cli.DataService().Global.DiskSnapshot.BatchUpdatePolicy(kt, updateReq); err != nil {
+		logs.Errorf("update disk snapshot policy last executed time failed, err: %v, id: %s, rid: %s", err,
+			policy.ID, kt.Rid)
+		return err
+	}
+
+	logs.Infof("run disk snapshot policy %s done, disk count: %d, failed: %d, rid: %s", policy.ID, len(disks),
+		failed, kt.Rid)
+	return nil
+}
+
+// genPolicySnapshotName 生成策略快照名称，名称仅包含小写字母、数字和中划线以满足各云厂商的命名规则
+func genPolicySnapshotName(diskID string, now time.Time) string {
+	return fmt.Sprintf("hcm-%s-%s", diskID, now.Format("20060102150405"))
+}
+
+// listPolicyDisks 查询策略作用的云盘，仅包含仍属于策略所在业务且未回收的云盘
+func listPolicyDisks(kt *kit.Kit, cli *client.ClientSet, policy corecloud.DiskSnapshotPolicy) ([]diskRef, error) {
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("bk_biz_id", policy.BkBizID),
+		tools.RuleNotEqual("recycle_status", enumor.RecycleStatus),
+	}
+	if policy.ScopeType == corecloud.DiskScopeSnapshotPolicy {
+		rules = append(rules, tools.RuleIn("id", policy.DiskIDs))
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "vendor", "account_id"},
+	}
+	result := make([]diskRef, 0)
+	for {
+		resp, err := cli.DataService().Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Details {
+			result = append(result, diskRef{ID: one.ID, Vendor: one.Vendor, AccountID: one.AccountID})
+		}
+
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			return result, nil
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+}
+
+type diskRef struct {
+	ID        string
+	Vendor    string
+	AccountID string
+}
+
+// cleanExpiredSnapshots 删除策略创建的超出保留数量的快照
+func cleanExpiredSnapshots(kt *kit.Kit, cli *client.ClientSet, lgc Interface,
+	policy corecloud.DiskSnapshotPolicy) error {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("policy_id", policy.ID),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "vendor", "account_id", "cloud_disk_id", "created_at"},
+	}
+	snapshots := make([]corecloud.DiskSnapshot, 0)
+	for {
+		resp, err := cli.DataService().Global.DiskSnapshot.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot failed, err: %v, policy: %s, rid: %s", err, policy.ID, kt.Rid)
+			return err
+		}
+
+		snapshots = append(snapshots, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	// 删除快照需要按账号调用
+	accountMap := make(map[string][]corecloud.DiskSnapshot)
+	for _, one := range selectExpiredSnapshots(snapshots, policy.RetentionCount) {
+		accountMap[one.AccountID] = append(accountMap[one.AccountID], one)
+	}
+
+	for accountID, expired := range accountMap {
+		ids := make([]string, 0, len(expired))
+		for _, one := range expired {
+			ids = append(ids, one.ID)
+		}
+
+		for _, batch := range slice.Split(ids, constant.BatchOperationMaxLimit) {
+			if err := lgc.BatchDelete(kt, expired[0].Vendor, accountID, batch); err != nil {
+				logs.Errorf("delete expired disk snapshot failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectExpiredSnapshots 按源云盘分组，每块云盘保留最新的 retention 个快照，返回其余需要删除的快照
+func selectExpiredSnapshots(snapshots []corecloud.DiskSnapshot, retention uint64) []corecloud.DiskSnapshot {
+	diskMap := make(map[string][]corecloud.DiskSnapshot)
+	for _, one := range snapshots {
+		diskMap[one.CloudDiskID] = append(diskMap[one.CloudDiskID], one)
+	}
+
+	expired := make([]corecloud.DiskSnapshot, 0)
+	for _, list := range diskMap {
+		if uint64(len(list)) <= retention {
+			continue
+		}
+
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].CreatedAt != list[j].CreatedAt {
+				return list[i].CreatedAt > list[j].CreatedAt
+			}
+			return list[i].ID > list[j].ID
+		})
+		expired = append(expired, list[retention:]...)
+	}
+
+	return expired
+}
@@ -0,0 +1,75 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+)
+
+func TestSelectExpiredSnapshots(t *testing.T) {
+	snapshot := func(id, cloudDiskID, createdAt string) corecloud.DiskSnapshot {
+		return corecloud.DiskSnapshot{ID: id, CloudDiskID: cloudDiskID,
+			Revision: core.Revision{CreatedAt: createdAt}}
+	}
+	snapshots := []corecloud.DiskSnapshot{
+		snapshot("1", "disk-a", "2025-01-01T00:00:00+08:00"),
+		snapshot("2", "disk-a", "2025-01-03T00:00:00+08:00"),
+		snapshot("3", "disk-b", "2025-01-01T00:00:00+08:00"),
+		snapshot("4", "disk-a", "2025-01-02T00:00:00+08:00"),
+	}
+
+	expired := selectExpiredSnapshots(snapshots, 2)
+	if len(expired) != 1 || expired[0].ID != "1" {
+		t.Errorf("expect only oldest snapshot 1 of disk-a expired, got: %+v", expired)
+	}
+
+	if expired = selectExpiredSnapshots(snapshots, 3); len(expired) != 0 {
+		t.Errorf("expect no snapshot expired, got: %+v", expired)
+	}
+}
+
+func TestIsPolicyDue(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	policy := corecloud.DiskSnapshotPolicy{ID: "p1", Enabled: true, IntervalHours: 24}
+
+	if !isPolicyDue(policy, now) {
+		t.Errorf("policy never executed should be due")
+	}
+
+	policy.LastExecutedAt = now.Add(-23 * time.Hour).Format(constant.TimeStdFormat)
+	if isPolicyDue(policy, now) {
+		t.Errorf("policy executed 23 hours ago should not be due")
+	}
+
+	policy.LastExecutedAt = now.Add(-24 * time.Hour).Format(constant.TimeStdFormat)
+	if !isPolicyDue(policy, now) {
+		t.Errorf("policy executed 24 hours ago should be due")
+	}
+
+	policy.Enabled = false
+	if isPolicyDue(policy, now) {
+		t.Errorf("disabled policy should not be due")
+	}
+}
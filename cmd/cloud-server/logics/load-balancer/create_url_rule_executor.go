@@ -250,8 +250,12 @@ func (c *CreateUrlRuleExecutor) createFlowTask(kt *kit.Kit, lbID string,
 		ShareData: tableasync.NewShareData(map[string]string{
 			"lb_id": lbID,
 		}),
-		Tasks:       flowTasks,
-		IsInitState: true,
+		Tasks:          flowTasks,
+		IsInitState:    true,
+		Priority:       batchFlowPriority,
+		ConcurrencyKey: c.flowConcurrencyKey(),
+		// 任务流失败后删除已创建的资源，避免残留部分创建成功的资源
+		RollbackMode: enumor.FlowRollbackCompensate,
 	}
 	result, err := c.taskCli.CreateCustomFlow(kt, addReq)
 	if err != nil {
@@ -91,6 +91,14 @@ type basePreviewExecutor struct {
 	dataServiceCli *dataservice.Client
 }
 
+// batchFlowPriority 批量导入的任务流优先级低于交互式操作，避免大批量导入阻塞其他任务流的派发
+const batchFlowPriority = enumor.FlowPriorityLow
+
+// flowConcurrencyKey 批量导入的任务流按云账号控制并发，避免单个账号的大批量导入占满所有节点
+func (c *basePreviewExecutor) flowConcurrencyKey() string {
+	return enumor.AccountConcurrencyKey.Key(c.accountID)
+}
+
 func newBasePreviewExecutor(cli *dataservice.Client, vendor enumor.Vendor, bkBizID int64,
 	accountID string, regionIDs []string) *basePreviewExecutor {
 
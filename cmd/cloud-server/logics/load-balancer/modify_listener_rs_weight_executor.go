@@ -436,8 +436,10 @@ func (c *BatchListenerModifyRsWeightExecutor) createFlowTask(kt *kit.Kit, lbID s
 		ShareData: tableasync.NewShareData(map[string]string{
 			"lb_id": lbID,
 		}),
-		Tasks:       flowTasks,
-		IsInitState: true,
+		Tasks:          flowTasks,
+		IsInitState:    true,
+		Priority:       batchFlowPriority,
+		ConcurrencyKey: c.flowConcurrencyKey(),
 	}
 	result, err := c.taskCli.CreateCustomFlow(kt, addReq)
 	if err != nil {
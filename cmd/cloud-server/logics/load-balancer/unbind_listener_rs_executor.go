@@ -396,8 +396,10 @@ func (c *BatchListenerUnbindRsExecutor) createFlowTask(kt *kit.Kit, lbID string,
 		ShareData: tableasync.NewShareData(map[string]string{
 			"lb_id": lbID,
 		}),
-		Tasks:       flowTasks,
-		IsInitState: true,
+		Tasks:          flowTasks,
+		IsInitState:    true,
+		Priority:       batchFlowPriority,
+		ConcurrencyKey: c.flowConcurrencyKey(),
 	}
 	result, err := c.taskCli.CreateCustomFlow(kt, addReq)
 	if err != nil {
@@ -21,9 +21,11 @@
 package logics
 
 import (
+	"hcm/cmd/cloud-server/logics/approval"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/cvm"
 	"hcm/cmd/cloud-server/logics/disk"
+	disksnapshot "hcm/cmd/cloud-server/logics/disk-snapshot"
 	"hcm/cmd/cloud-server/logics/eip"
 	"hcm/pkg/client"
 	"hcm/pkg/thirdparty/esb"
@@ -31,10 +33,12 @@ import (
 
 // Logics defines cloud-server common logics.
 type Logics struct {
-	Audit audit.Interface
-	Disk  disk.Interface
-	Cvm   cvm.Interface
-	Eip   eip.Interface
+	Audit        audit.Interface
+	Disk         disk.Interface
+	Cvm          cvm.Interface
+	Eip          eip.Interface
+	DiskSnapshot disksnapshot.Interface
+	Approval     approval.Interface
 }
 
 // NewLogics create a new cloud server logics.
@@ -43,9 +47,11 @@ func NewLogics(c *client.ClientSet, esbClient esb.Client) *Logics {
 	eipLogics := eip.NewEip(c, auditLogics)
 	diskLogics := disk.NewDisk(c, auditLogics)
 	return &Logics{
-		Audit: auditLogics,
-		Disk:  disk.NewDisk(c, auditLogics),
-		Cvm:   cvm.NewCvm(c, auditLogics, eipLogics, diskLogics, esbClient),
-		Eip:   eip.NewEip(c, auditLogics),
+		Audit:        auditLogics,
+		Disk:         disk.NewDisk(c, auditLogics),
+		Cvm:          cvm.NewCvm(c, auditLogics, eipLogics, diskLogics, esbClient),
+		Eip:          eip.NewEip(c, auditLogics),
+		DiskSnapshot: disksnapshot.NewDiskSnapshot(c, auditLogics),
+		Approval:     approval.NewApproval(c),
 	}
 }
@@ -0,0 +1,568 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recommend
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"hcm/pkg/cc"
+)
+
+const (
+	// unreachablePing 存在无法连通的地区时的延迟，与插件保持一致
+	unreachablePing = 100000
+	// unreachableCoverRate 存在无法连通的地区时的覆盖率，与插件保持一致
+	unreachableCoverRate = 0.001
+
+	// maxExactVars 候选IDC数量不超过该值时穷举求解精确帕累托前沿，否则使用NSGA-II近似求解
+	maxExactVars = 16
+
+	nsgaPopSize     = 100
+	nsgaGenerations = 200
+	nsgaSeed        = 1
+)
+
+// Validate 校验算法输入，与插件的入参校验保持一致
+func (in *AlgorithmInput) Validate() error {
+	if in == nil {
+		return errors.New("algorithm input is required")
+	}
+
+	if len(in.CountryRate) == 0 {
+		return errors.New("COUNTRY_RATE_ORIGIN is required")
+	}
+
+	var total float64
+	for country, rate := range in.CountryRate {
+		if len(country) == 0 {
+			return errors.New("COUNTRY_RATE_ORIGIN key can not be null")
+		}
+		if _, exists := in.PingInfo[country]; !exists {
+			return fmt.Errorf("COUNTRY_RATE_ORIGIN and PING_INFO has different keys: %s", country)
+		}
+		total += rate
+	}
+	if total <= 0 {
+		return errors.New("sum of COUNTRY_RATE_ORIGIN must be greater than 0")
+	}
+
+	for _, idc := range in.IdcList {
+		if _, exists := in.IdcPrice[idc]; !exists {
+			return fmt.Errorf("IDC_LIST and IDC_PRICE has different keys: %s", idc)
+		}
+	}
+
+	for _, ranges := range [][]cc.ThreshHoldRanges{in.CoverPingRanges, in.IDCPriceRanges} {
+		for _, one := range ranges {
+			if len(one.Range) != 2 {
+				return fmt.Errorf("threshold range must contain 2 values, got: %v", one.Range)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Solve 求解IDC选型的帕累托前沿，目标为最小化加权ping延迟(F1)与IDC单位成本(F2)，约束为覆盖率不低于COVER_RATE。
+// 目标、约束及输出格式与算法插件相同：PICK_IDC_LIST 中的IDC必选，结果按F1升序排列。
+// 与插件不同的是 BAN_IDC_LIST 中的IDC不参与选择，插件目前未处理该字段。
+// 候选IDC不超过 maxExactVars 时穷举得到精确前沿，插件固定使用NSGA-II近似求解，候选较多时两者的前沿可能不同。
+func Solve(in *AlgorithmInput) (*AlgorithmOutput, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := newProblem(in)
+
+	var solutions []*solution
+	if len(p.candidates) <= maxExactVars {
+		solutions = p.enumerate()
+	} else {
+		solutions = p.nsga2()
+	}
+
+	return &AlgorithmOutput{ParetoList: p.resolve(solutions)}, nil
+}
+
+// problem IDC选型问题，决策变量为除必选、禁选外的每个候选IDC是否选中
+type problem struct {
+	in         *AlgorithmInput
+	candidates []string
+	picks      []string
+	countries  []string
+	// rates 归一化后的玩家分布，与 countries 一一对应
+	rates []float64
+	// pings[i][j] 为地区i到候选IDC j的延迟，无数据时为+Inf
+	pings [][]float64
+	// pickPings[i] 为地区i到必选IDC的最小延迟，无数据时为+Inf
+	pickPings []float64
+	prices    []float64
+	pickPrice float64
+}
+
+func newProblem(in *AlgorithmInput) *problem {
+	banned := make(map[string]struct{}, len(in.BanIdcList))
+	for _, idc := range in.BanIdcList {
+		banned[idc] = struct{}{}
+	}
+
+	picks := make([]string, 0, len(in.PickIdcList))
+	picked := make(map[string]struct{}, len(in.PickIdcList))
+	for _, idc := range in.PickIdcList {
+		if _, exists := picked[idc]; exists {
+			continue
+		}
+		picked[idc] = struct{}{}
+		picks = append(picks, idc)
+	}
+
+	candidates := make([]string, 0, len(in.IdcList))
+	seen := make(map[string]struct{}, len(in.IdcList))
+	for _, idc := range in.IdcList {
+		_, isBanned := banned[idc]
+		_, isPicked := picked[idc]
+		_, isSeen := seen[idc]
+		if isBanned || isPicked || isSeen {
+			continue
+		}
+		seen[idc] = struct{}{}
+		candidates = append(candidates, idc)
+	}
+
+	// 按地区名排序，保证浮点累加顺序稳定
+	countries := make([]string, 0, len(in.CountryRate))
+	var total float64
+	for country, rate := range in.CountryRate {
+		countries = append(countries, country)
+		total += rate
+	}
+	sort.Strings(countries)
+
+	p := &problem{
+		in:         in,
+		candidates: candidates,
+		picks:      picks,
+		countries:  countries,
+		rates:      make([]float64, len(countries)),
+		pings:      make([][]float64, len(countries)),
+		pickPings:  make([]float64, len(countries)),
+		prices:     make([]float64, len(candidates)),
+	}
+
+	for i, country := range countries {
+		p.rates[i] = in.CountryRate[country] / total
+
+		p.pings[i] = make([]float64, len(candidates))
+		for j, idc := range candidates {
+			p.pings[i][j] = pingOf(in.PingInfo[country], idc)
+		}
+
+		p.pickPings[i] = math.Inf(1)
+		for _, idc := range picks {
+			p.pickPings[i] = math.Min(p.pickPings[i], pingOf(in.PingInfo[country], idc))
+		}
+	}
+
+	for j, idc := range candidates {
+		p.prices[j] = in.IdcPrice[idc]
+	}
+	for _, idc := range picks {
+		p.pickPrice += in.IdcPrice[idc]
+	}
+
+	return p
+}
+
+func pingOf(pings map[string]float64, idc string) float64 {
+	ping, exists := pings[idc]
+	if !exists {
+		return math.Inf(1)
+	}
+	return ping
+}
+
+// solution 一个解，genes[j] 表示是否选中候选IDC j
+type solution struct {
+	genes     []bool
+	f1        float64
+	f2        float64
+	coverRate float64
+	// cv 约束违反程度，为0表示可行解
+	cv float64
+}
+
+func (p *problem) evaluate(genes []bool) *solution {
+	s := &solution{genes: genes, f2: p.pickPrice}
+	for j, chosen := range genes {
+		if chosen {
+			s.f2 += p.prices[j]
+		}
+	}
+
+	reachable := true
+	for i := range p.countries {
+		ping := p.pickPings[i]
+		for j, chosen := range genes {
+			if chosen && p.pings[i][j] < ping {
+				ping = p.pings[i][j]
+			}
+		}
+		if math.IsInf(ping, 1) {
+			reachable = false
+			break
+		}
+
+		s.f1 += ping * p.rates[i]
+		if ping <= float64(p.in.CoverPing) {
+			s.coverRate += p.rates[i]
+		}
+	}
+
+	if !reachable {
+		s.f1 = unreachablePing
+		s.coverRate = unreachableCoverRate
+	}
+	s.cv = math.Max(0, p.in.CoverRate-s.coverRate)
+
+	return s
+}
+
+// enumerate 穷举所有组合，返回精确的帕累托前沿
+func (p *problem) enumerate() []*solution {
+	n := len(p.candidates)
+	all := make([]*solution, 0, 1<<n)
+	for mask := 0; mask < 1<<n; mask++ {
+		genes := make([]bool, n)
+		for j := 0; j < n; j++ {
+			genes[j] = mask&(1<<j) != 0
+		}
+		all = append(all, p.evaluate(genes))
+	}
+	return optimum(all)
+}
+
+// nsga2 使用二进制编码的NSGA-II求解，使用固定随机种子保证结果可复现
+func (p *problem) nsga2() []*solution {
+	n := len(p.candidates)
+	rnd := rand.New(rand.NewSource(nsgaSeed))
+	archive := make(map[string]*solution)
+
+	add := func(genes []bool) *solution {
+		key := genesKey(genes)
+		if s, exists := archive[key]; exists {
+			return s
+		}
+		s := p.evaluate(genes)
+		archive[key] = s
+		return s
+	}
+
+	pop := make([]*solution, 0, nsgaPopSize)
+	for len(pop) < nsgaPopSize {
+		genes := make([]bool, n)
+		for j := range genes {
+			genes[j] = rnd.Intn(2) == 1
+		}
+		pop = append(pop, add(genes))
+	}
+	pop = uniqueSolutions(pop)
+	ranks, crowding := rankAndCrowding(pop)
+
+	tournament := func() *solution {
+		a, b := rnd.Intn(len(pop)), rnd.Intn(len(pop))
+		if ranks[a] < ranks[b] || (ranks[a] == ranks[b] && crowding[a] > crowding[b]) {
+			return pop[a]
+		}
+		return pop[b]
+	}
+
+	for gen := 0; gen < nsgaGenerations; gen++ {
+		offspring := make([]*solution, 0, nsgaPopSize)
+		for len(offspring) < nsgaPopSize {
+			mother, father := tournament(), tournament()
+			genes := make([]bool, n)
+			for j := range genes {
+				// 均匀交叉 + 位翻转变异
+				if rnd.Intn(2) == 0 {
+					genes[j] = mother.genes[j]
+				} else {
+					genes[j] = father.genes[j]
+				}
+				if rnd.Float64() < 1/float64(n) {
+					genes[j] = !genes[j]
+				}
+			}
+			offspring = append(offspring, add(genes))
+		}
+
+		merged := uniqueSolutions(append(pop, offspring...))
+		fronts := nonDominatedSort(merged)
+		next := make([]*solution, 0, nsgaPopSize)
+		for _, front := range fronts {
+			if len(next)+len(front) <= nsgaPopSize {
+				next = append(next, front...)
+				continue
+			}
+			dist := crowdingDistance(front)
+			idx := make([]int, len(front))
+			for i := range idx {
+				idx[i] = i
+			}
+			sort.SliceStable(idx, func(a, b int) bool { return dist[idx[a]] > dist[idx[b]] })
+			for _, i := range idx[:nsgaPopSize-len(next)] {
+				next = append(next, front[i])
+			}
+			break
+		}
+		pop = next
+		ranks, crowding = rankAndCrowding(pop)
+	}
+
+	all := make([]*solution, 0, len(archive))
+	for _, s := range archive {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool { return genesKey(all[i].genes) < genesKey(all[j].genes) })
+	return optimum(all)
+}
+
+// resolve 将解映射为IDC列表，过滤空解与重复解，按F1升序排列
+func (p *problem) resolve(solutions []*solution) []Solution {
+	result := make([]Solution, 0, len(solutions))
+	seen := make(map[string]struct{}, len(solutions))
+	for _, s := range solutions {
+		idcs := make([]string, 0, len(p.picks)+len(s.genes))
+		for j, chosen := range s.genes {
+			if chosen {
+				idcs = append(idcs, p.candidates[j])
+			}
+		}
+		idcs = append(idcs, p.picks...)
+		if len(idcs) == 0 {
+			continue
+		}
+
+		key := genesKey(s.genes)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		result = append(result, Solution{
+			Idc:       idcs,
+			F1:        s.f1,
+			F2:        s.f2,
+			F1Score:   scoreOf(p.in.CoverPingRanges, s.f1),
+			F2Score:   scoreOf(p.in.IDCPriceRanges, s.f2),
+			CoverRate: s.coverRate,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].F1 != result[j].F1 {
+			return result[i].F1 < result[j].F1
+		}
+		return result[i].F2 < result[j].F2
+	})
+	return result
+}
+
+// scoreOf 将目标函数值按照分数区间转换为评分，不在任何区间内时为-1
+func scoreOf(ranges []cc.ThreshHoldRanges, value float64) float64 {
+	for _, one := range ranges {
+		if float64(one.Range[0]) <= value && value < float64(one.Range[1]) {
+			return float64(one.Score)
+		}
+	}
+	return -1
+}
+
+// optimum 返回可行解中的非支配解，没有可行解时返回约束违反程度最小的解
+func optimum(all []*solution) []*solution {
+	feasible := make([]*solution, 0, len(all))
+	for _, s := range all {
+		if s.cv == 0 {
+			feasible = append(feasible, s)
+		}
+	}
+
+	if len(feasible) == 0 {
+		var least *solution
+		for _, s := range all {
+			if least == nil || s.cv < least.cv {
+				least = s
+			}
+		}
+		if least == nil {
+			return nil
+		}
+		return []*solution{least}
+	}
+
+	// 按F1升序、F2升序排序后扫描，F2严格小于之前所有解的F2时为非支配解，目标值完全相同的解互不支配
+	sort.SliceStable(feasible, func(i, j int) bool {
+		if feasible[i].f1 != feasible[j].f1 {
+			return feasible[i].f1 < feasible[j].f1
+		}
+		return feasible[i].f2 < feasible[j].f2
+	})
+
+	front := make([]*solution, 0)
+	bestF2 := math.Inf(1)
+	for i, s := range feasible {
+		if s.f2 < bestF2 {
+			front = append(front, s)
+			bestF2 = s.f2
+			continue
+		}
+		last := feasible[i-1]
+		if len(front) != 0 && front[len(front)-1] == last && s.f1 == last.f1 && s.f2 == last.f2 {
+			front = append(front, s)
+		}
+	}
+	return front
+}
+
+// dominates 约束支配关系：可行解优于不可行解，不可行解之间比较约束违反程度，可行解之间比较目标值
+func dominates(a, b *solution) bool {
+	if a.cv != b.cv {
+		return a.cv < b.cv
+	}
+	if a.cv > 0 {
+		return false
+	}
+	return a.f1 <= b.f1 && a.f2 <= b.f2 && (a.f1 < b.f1 || a.f2 < b.f2)
+}
+
+func nonDominatedSort(pop []*solution) [][]*solution {
+	dominatedBy := make([][]int, len(pop))
+	count := make([]int, len(pop))
+	current := make([]int, 0)
+	for i := range pop {
+		for j := range pop {
+			if i == j {
+				continue
+			}
+			if dominates(pop[i], pop[j]) {
+				dominatedBy[i] = append(dominatedBy[i], j)
+			} else if dominates(pop[j], pop[i]) {
+				count[i]++
+			}
+		}
+		if count[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	fronts := make([][]*solution, 0)
+	for len(current) != 0 {
+		front := make([]*solution, 0, len(current))
+		next := make([]int, 0)
+		for _, i := range current {
+			front = append(front, pop[i])
+			for _, j := range dominatedBy[i] {
+				count[j]--
+				if count[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+func crowdingDistance(front []*solution) []float64 {
+	dist := make([]float64, len(front))
+	if len(front) <= 2 {
+		for i := range dist {
+			dist[i] = math.Inf(1)
+		}
+		return dist
+	}
+
+	for _, objective := range []func(s *solution) float64{
+		func(s *solution) float64 { return s.f1 },
+		func(s *solution) float64 { return s.f2 },
+	} {
+		idx := make([]int, len(front))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return objective(front[idx[a]]) < objective(front[idx[b]]) })
+
+		minVal, maxVal := objective(front[idx[0]]), objective(front[idx[len(idx)-1]])
+		dist[idx[0]], dist[idx[len(idx)-1]] = math.Inf(1), math.Inf(1)
+		if maxVal == minVal {
+			continue
+		}
+		for k := 1; k < len(idx)-1; k++ {
+			dist[idx[k]] += (objective(front[idx[k+1]]) - objective(front[idx[k-1]])) / (maxVal - minVal)
+		}
+	}
+	return dist
+}
+
+func rankAndCrowding(pop []*solution) (map[int]int, map[int]float64) {
+	pos := make(map[*solution]int, len(pop))
+	for i, s := range pop {
+		pos[s] = i
+	}
+
+	ranks := make(map[int]int, len(pop))
+	crowding := make(map[int]float64, len(pop))
+	for rank, front := range nonDominatedSort(pop) {
+		dist := crowdingDistance(front)
+		for i, s := range front {
+			ranks[pos[s]] = rank
+			crowding[pos[s]] = dist[i]
+		}
+	}
+	return ranks, crowding
+}
+
+func uniqueSolutions(pop []*solution) []*solution {
+	seen := make(map[*solution]struct{}, len(pop))
+	result := make([]*solution, 0, len(pop))
+	for _, s := range pop {
+		if _, exists := seen[s]; exists {
+			continue
+		}
+		seen[s] = struct{}{}
+		result = append(result, s)
+	}
+	return result
+}
+
+func genesKey(genes []bool) string {
+	key := make([]byte, len(genes))
+	for i, chosen := range genes {
+		key[i] = '0'
+		if chosen {
+			key[i] = '1'
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recommend
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSolveGolden 对比算法插件(main.py)对相同输入的输出，期望结果 testdata/*.golden.json 需安装插件依赖后
+// 执行 make golden 生成，未生成时跳过。插件中IDC为集合，顺序不固定，因此只比较元素。
+// 插件未处理 BAN_IDC_LIST，含禁选IDC的输入不放入 *.input.json，见 TestSolveBanPick。
+func TestSolveGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input.json"))
+	require.NoError(t, err)
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".input.json")
+		t.Run(name, func(t *testing.T) {
+			golden := filepath.Join("testdata", name+".golden.json")
+			if _, err := os.Stat(golden); os.IsNotExist(err) {
+				t.Skipf("%s not found, run make golden to generate it", golden)
+			}
+
+			in := new(AlgorithmInput)
+			readJSON(t, input, in)
+			expect := new(AlgorithmOutput)
+			readJSON(t, golden, expect)
+
+			out, err := Solve(in)
+			require.NoError(t, err)
+			require.Len(t, out.ParetoList, len(expect.ParetoList))
+			for i, one := range expect.ParetoList {
+				got := out.ParetoList[i]
+				assert.ElementsMatch(t, one.Idc, got.Idc)
+				assert.InDelta(t, one.F1, got.F1, 1e-6)
+				assert.InDelta(t, one.F2, got.F2, 1e-6)
+				assert.InDelta(t, one.CoverRate, got.CoverRate, 1e-6)
+				assert.Equal(t, one.F1Score, got.F1Score)
+				assert.Equal(t, one.F2Score, got.F2Score)
+			}
+		})
+	}
+}
+
+// TestSolveBanPick 必选IDC出现在每个结果中，禁选IDC不出现在任何结果中
+func TestSolveBanPick(t *testing.T) {
+	in := new(AlgorithmInput)
+	readJSON(t, filepath.Join("testdata", "ban_pick.json"), in)
+
+	out, err := Solve(in)
+	require.NoError(t, err)
+	require.NotEmpty(t, out.ParetoList)
+	for i, one := range out.ParetoList {
+		for _, pick := range in.PickIdcList {
+			assert.Contains(t, one.Idc, pick)
+		}
+		for _, ban := range in.BanIdcList {
+			assert.NotContains(t, one.Idc, ban)
+		}
+		assert.GreaterOrEqual(t, one.CoverRate, in.CoverRate)
+		if i > 0 {
+			assert.LessOrEqual(t, out.ParetoList[i-1].F1, one.F1)
+		}
+	}
+}
+
+// TestSolveNSGA2 NSGA-II 近似求解的结果应与穷举得到的精确前沿一致
+func TestSolveNSGA2(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	in := &AlgorithmInput{
+		CountryRate: map[string]float64{},
+		CoverRate:   0.7,
+		CoverPing:   80,
+		PingInfo:    map[string]map[string]float64{},
+		IdcPrice:    map[string]float64{},
+	}
+	for i := 0; i < 18; i++ {
+		idc := fmt.Sprintf("idc-%02d", i)
+		in.IdcList = append(in.IdcList, idc)
+		in.IdcPrice[idc] = 0.1 + float64(rnd.Intn(30))/100
+	}
+	for i := 0; i < 30; i++ {
+		country := fmt.Sprintf("country-%02d", i)
+		in.CountryRate[country] = float64(1 + rnd.Intn(100))
+		in.PingInfo[country] = map[string]float64{}
+		for _, idc := range in.IdcList {
+			in.PingInfo[country][idc] = float64(10 + rnd.Intn(300))
+		}
+	}
+	require.NoError(t, in.Validate())
+
+	p := newProblem(in)
+	exact := p.resolve(p.enumerate())
+	approx := p.resolve(p.nsga2())
+	require.NotEmpty(t, exact)
+	assert.Equal(t, exact, approx)
+
+	// 固定随机种子，结果可复现
+	assert.Equal(t, approx, p.resolve(p.nsga2()))
+}
+
+func TestAlgorithmInputValidate(t *testing.T) {
+	in := new(AlgorithmInput)
+	readJSON(t, filepath.Join("testdata", "basic.input.json"), in)
+	require.NoError(t, in.Validate())
+
+	in.CountryRate["jp-tk"] = 10
+	assert.Error(t, in.Validate())
+	delete(in.CountryRate, "jp-tk")
+
+	in.IdcList = append(in.IdcList, "E")
+	assert.Error(t, in.Validate())
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
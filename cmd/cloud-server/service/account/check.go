@@ -95,6 +95,8 @@ func ParseAndCheckTCloudExtension(
 				CloudSubAccountID:  extension.CloudSubAccountID,
 				CloudSecretID:      extension.CloudSecretID,
 				CloudSecretKey:     extension.CloudSecretKey,
+				CloudRoleArn:       extension.CloudRoleArn,
+				CloudExternalID:    extension.CloudExternalID,
 			},
 		)
 		if err != nil {
@@ -129,6 +131,8 @@ func ParseAndCheckAwsExtension(
 				CloudIamUsername: extension.CloudIamUsername,
 				CloudSecretID:    extension.CloudSecretID,
 				CloudSecretKey:   extension.CloudSecretKey,
+				CloudRoleArn:     extension.CloudRoleArn,
+				CloudExternalID:  extension.CloudExternalID,
 			},
 		)
 		if err != nil {
@@ -165,6 +169,7 @@ func ParseAndCheckHuaWeiExtension(
 				CloudSecretKey:      extension.CloudSecretKey,
 				CloudIamUserID:      extension.CloudIamUserID,
 				CloudIamUsername:    extension.CloudIamUsername,
+				CloudAgencyName:     extension.CloudAgencyName,
 			},
 		)
 		if err != nil {
@@ -320,6 +325,8 @@ func (a *accountSvc) parseAndCheckTCloudExtensionByID(
 				CloudSubAccountID:  extension.CloudSubAccountID,
 				CloudSecretID:      extension.CloudSecretID,
 				CloudSecretKey:     extension.CloudSecretKey,
+				CloudRoleArn:       extension.CloudRoleArn,
+				CloudExternalID:    extension.CloudExternalID,
 			},
 		)
 		if err != nil {
@@ -364,6 +371,8 @@ func (a *accountSvc) parseAndCheckAwsExtensionByID(
 				CloudIamUsername: extension.CloudIamUsername,
 				CloudSecretID:    extension.CloudSecretID,
 				CloudSecretKey:   extension.CloudSecretKey,
+				CloudRoleArn:     extension.CloudRoleArn,
+				CloudExternalID:  extension.CloudExternalID,
 			},
 		)
 		if err != nil {
@@ -409,6 +418,7 @@ func (a *accountSvc) parseAndCheckHuaWeiExtensionByID(
 				CloudIamUsername:    extension.CloudIamUsername,
 				CloudSecretID:       extension.CloudSecretID,
 				CloudSecretKey:      extension.CloudSecretKey,
+				CloudAgencyName:     extension.CloudAgencyName,
 			},
 		)
 		if err != nil {
@@ -124,6 +124,8 @@ func (a *accountSvc) updateForTCloud(
 			CloudSubAccountID: extension.CloudSubAccountID,
 			CloudSecretID:     &extension.CloudSecretID,
 			CloudSecretKey:    &extension.CloudSecretKey,
+			CloudRoleArn:      &extension.CloudRoleArn,
+			CloudExternalID:   &extension.CloudExternalID,
 		}
 	}
 
@@ -171,6 +173,8 @@ func (a *accountSvc) updateForAws(
 			CloudIamUsername: extension.CloudIamUsername,
 			CloudSecretID:    &extension.CloudSecretID,
 			CloudSecretKey:   &extension.CloudSecretKey,
+			CloudRoleArn:     &extension.CloudRoleArn,
+			CloudExternalID:  &extension.CloudExternalID,
 		}
 	}
 
@@ -220,6 +224,7 @@ func (a *accountSvc) updateForHuaWei(
 			CloudIamUsername:    extension.CloudIamUsername,
 			CloudSecretID:       &extension.CloudSecretID,
 			CloudSecretKey:      &extension.CloudSecretKey,
+			CloudAgencyName:     &extension.CloudAgencyName,
 		}
 	}
 
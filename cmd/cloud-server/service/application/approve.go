@@ -112,6 +112,17 @@ func (a *applicationSvc) approve(cts *rest.Contexts) (interface{}, error) {
 	// 将ITSM单据状态转为hcm定义的单据状态
 	status := a.convertToStatus(req.CurrentStatus, *req.ApproveResult)
 
+	if err = a.onApprovalResult(cts, application, status); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// onApprovalResult 根据审批结果更新申请单状态，ITSM回调和内置审批引擎共用
+func (a *applicationSvc) onApprovalResult(cts *rest.Contexts, application *dataproto.ApplicationResp,
+	status enumor.ApplicationStatus) error {
+
 	// 计算下个状态，实际上除了通过外，其他状态都是不需要变化了，要么是终结态，要么是持续中
 	nextStatus := status
 	// 对于审批通过，则下个状态为交付中，其他状态则保持原样
@@ -120,9 +131,9 @@ func (a *applicationSvc) approve(cts *rest.Contexts) (interface{}, error) {
 	}
 
 	// 更新状态
-	err = a.updateStatusWithDetail(cts, application.ID, nextStatus, "")
+	err := a.updateStatusWithDetail(cts, application.ID, nextStatus, "")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// 通过后需要进行资源交付
@@ -131,7 +142,7 @@ func (a *applicationSvc) approve(cts *rest.Contexts) (interface{}, error) {
 		go a.deliver(cts, application)
 	}
 
-	return nil, nil
+	return nil
 }
 
 func parseReqFromApplicationContent[T any](content string) (*T, error) {
@@ -21,7 +21,10 @@ package application
 
 import (
 	"fmt"
+	"time"
 
+	lgcapproval "hcm/cmd/cloud-server/logics/approval"
+	"hcm/pkg/api/core/approval"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/rest"
@@ -43,10 +46,22 @@ func (a *applicationSvc) CancelApplication(cts *rest.Contexts) (interface{}, err
 		)
 	}
 
-	// 根据SN调用ITSM接口撤销单据
-	err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+	switch application.Source {
+	case enumor.ApplicationSourceNative:
+		// 撤回内置审批引擎的审批单，申请单状态已与审批单在同一事务中更新为已撤销
+		_, err = a.approval.Operate(cts.Kit, applicationID, func(ticket *approval.Ticket) error {
+			return lgcapproval.Withdraw(ticket, cts.Kit.User, "", time.Now())
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	default:
+		// 根据SN调用ITSM接口撤销单据
+		err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
+		if err != nil {
+			return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+		}
 	}
 
 	// 更新状态
@@ -46,6 +46,7 @@ import (
 	cscvm "hcm/pkg/api/cloud-server/cvm"
 	csdisk "hcm/pkg/api/cloud-server/disk"
 	csvpc "hcm/pkg/api/cloud-server/vpc"
+	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service"
 	hclb "hcm/pkg/api/hc-service/load-balancer"
 	"hcm/pkg/criteria/enumor"
@@ -92,6 +93,11 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 		return nil, err
 	}
 
+	// 使用内置审批引擎时不依赖ITSM
+	if a.approvalEngine == enumor.NativeApprovalEngine {
+		return a.createWithNativeApproval(cts, req, handler)
+	}
+
 	// 查询审批流程服务ID
 	applicationType := handler.GetType()
 	serviceID, managers, err := a.getApprovalProcessInfo(cts, applicationType)
@@ -134,7 +140,13 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 		return nil, fmt.Errorf("call itsm create ticket api failed, err: %w", err)
 	}
 
-	// 调用DB创建单据
+	return a.createApplication(cts, req, handler, sn, enumor.ApplicationSourceITSM)
+}
+
+// createApplication 调用DB创建单据
+func (a *applicationSvc) createApplication(cts *rest.Contexts, req *proto.CreateCommonReq,
+	handler handlers.ApplicationHandler, sn string, source enumor.ApplicationSource) (*core.CreateResult, error) {
+
 	content, err := json.MarshalToString(handler.GenerateApplicationContent())
 	if err != nil {
 		return nil, errf.NewFromErr(
@@ -143,22 +155,15 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 		)
 	}
 
-	// 主机、硬盘、VPC、负载均衡需要记录业务ID
-	var bkBizIDs = make([]int64, 0)
-	if applicationType == enumor.CreateCvm || applicationType == enumor.CreateDisk ||
-		applicationType == enumor.CreateVpc || applicationType == enumor.CreateLoadBalancer {
-		bkBizIDs = handler.GetBkBizIDs()
-	}
-
 	result, err := a.client.DataService().Global.Application.CreateApplication(
 		cts.Kit.Ctx,
 		cts.Kit.Header(),
 		&dataproto.ApplicationCreateReq{
 			SN:             sn,
-			Source:         enumor.ApplicationSourceITSM,
-			Type:           applicationType,
+			Source:         source,
+			Type:           handler.GetType(),
 			Status:         enumor.Pending,
-			BkBizIDs:       bkBizIDs,
+			BkBizIDs:       getApplicationBkBizIDs(handler),
 			Applicant:      cts.Kit.User,
 			Content:        content,
 			DeliveryDetail: "{}",
@@ -172,6 +177,16 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 	return result, nil
 }
 
+// getApplicationBkBizIDs 主机、硬盘、VPC、负载均衡需要记录业务ID
+func getApplicationBkBizIDs(handler handlers.ApplicationHandler) []int64 {
+	switch handler.GetType() {
+	case enumor.CreateCvm, enumor.CreateDisk, enumor.CreateVpc, enumor.CreateLoadBalancer:
+		return handler.GetBkBizIDs()
+	default:
+		return make([]int64, 0)
+	}
+}
+
 func parseReqFromRequestBody[T any](cts *rest.Contexts) (*T, error) {
 	req := new(T)
 	if err := cts.DecodeInto(req); err != nil {
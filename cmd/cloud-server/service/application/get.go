@@ -23,9 +23,12 @@ import (
 	"fmt"
 
 	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
 )
 
 // GetApplication ...
@@ -38,7 +41,16 @@ func (a *applicationSvc) GetApplication(cts *rest.Contexts) (interface{}, error)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	if application.Applicant != cts.Kit.User {
+	// 内置审批引擎的审批单包含审批进度和审批记录，审批人也可以查看申请单
+	var approvalTicket *approval.Ticket
+	if application.Source == enumor.ApplicationSourceNative {
+		approvalTicket, err = a.approval.GetTicket(cts.Kit, application.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if application.Applicant != cts.Kit.User && !isTicketApprover(approvalTicket, cts.Kit.User) {
 		_, authorized, err := a.authorizer.Authorize(cts.Kit, meta.ResourceAttribute{Basic: &meta.Basic{
 			Type:   meta.Application,
 			Action: meta.Find,
@@ -53,14 +65,9 @@ func (a *applicationSvc) GetApplication(cts *rest.Contexts) (interface{}, error)
 		}
 	}
 
-	// 查询审批链接
-	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
-	}
-
-	return &proto.ApplicationGetResp{
+	resp := &proto.ApplicationGetResp{
 		ID:             application.ID,
+		Source:         application.Source,
 		SN:             application.SN,
 		Type:           application.Type,
 		Status:         application.Status,
@@ -69,6 +76,34 @@ func (a *applicationSvc) GetApplication(cts *rest.Contexts) (interface{}, error)
 		DeliveryDetail: application.DeliveryDetail,
 		Memo:           application.Memo,
 		Revision:       application.Revision,
-		TicketUrl:      ticket.TicketURL,
-	}, nil
+	}
+
+	if approvalTicket != nil {
+		resp.ApprovalTicket = approvalTicket
+		return resp, nil
+	}
+
+	// 查询审批链接
+	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
+	if err != nil {
+		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
+	}
+	resp.TicketUrl = ticket.TicketURL
+
+	return resp, nil
+}
+
+// isTicketApprover 用户是否为审批单任一节点的审批人
+func isTicketApprover(ticket *approval.Ticket, user string) bool {
+	if ticket == nil {
+		return false
+	}
+
+	for _, stage := range ticket.Stages {
+		if slice.IsItemInSlice(stage.Approvers, user) {
+			return true
+		}
+	}
+
+	return false
 }
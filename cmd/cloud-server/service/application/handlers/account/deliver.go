@@ -97,6 +97,8 @@ func (a *ApplicationOfAddAccount) createForTCloud() (string, error) {
 				CloudSubAccountID:  a.req.Extension["cloud_sub_account_id"],
 				CloudSecretID:      a.req.Extension["cloud_secret_id"],
 				CloudSecretKey:     a.req.Extension["cloud_secret_key"],
+				CloudRoleArn:       a.req.Extension["cloud_role_arn"],
+				CloudExternalID:    a.req.Extension["cloud_external_id"],
 			},
 		},
 	)
@@ -122,6 +124,8 @@ func (a *ApplicationOfAddAccount) createForAws() (string, error) {
 				CloudIamUsername: a.req.Extension["cloud_iam_username"],
 				CloudSecretID:    a.req.Extension["cloud_secret_id"],
 				CloudSecretKey:   a.req.Extension["cloud_secret_key"],
+				CloudRoleArn:     a.req.Extension["cloud_role_arn"],
+				CloudExternalID:  a.req.Extension["cloud_external_id"],
 			},
 		},
 	)
@@ -149,6 +153,7 @@ func (a *ApplicationOfAddAccount) createForHuaWei() (string, error) {
 				CloudSecretKey:      a.req.Extension["cloud_secret_key"],
 				CloudIamUserID:      a.req.Extension["cloud_iam_user_id"],
 				CloudIamUsername:    a.req.Extension["cloud_iam_username"],
+				CloudAgencyName:     a.req.Extension["cloud_agency_name"],
 			},
 		},
 	)
@@ -31,7 +31,11 @@ import (
 func (a *ApplicationOfAddAccount) PrepareReq() error {
 	// 密钥加密
 	secretKeyField := a.req.Vendor.GetSecretField()
-	a.req.Extension[secretKeyField] = a.Cipher.EncryptToBase64(conv.ToString(a.req.Extension[secretKeyField]))
+	encryptedSecretKey, err := a.Cipher.EncryptToBase64(conv.ToString(a.req.Extension[secretKeyField]))
+	if err != nil {
+		return fmt.Errorf("encrypt secret key failed, err: %v", err)
+	}
+	a.req.Extension[secretKeyField] = encryptedSecretKey
 
 	return nil
 }
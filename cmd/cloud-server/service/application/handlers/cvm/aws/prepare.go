@@ -30,7 +30,10 @@ import (
 // PrepareReq 预处理请求参数，比如敏感数据加密
 func (a *ApplicationOfCreateAwsCvm) PrepareReq() error {
 	// 密码加密
-	encryptedPassword := a.Cipher.EncryptToBase64(a.req.Password)
+	encryptedPassword, err := a.Cipher.EncryptToBase64(a.req.Password)
+	if err != nil {
+		return fmt.Errorf("encrypt password failed, err: %v", err)
+	}
 	a.req.Password = encryptedPassword
 	a.req.ConfirmedPassword = encryptedPassword
 
@@ -44,6 +44,7 @@ func (a *ApplicationOfCreateMainAccount) CheckReq() error {
 	// 检查vendor
 	switch a.req.Vendor {
 	case enumor.Aws:
+	case enumor.TCloud:
 	case enumor.Gcp:
 	case enumor.HuaWei:
 	case enumor.Azure:
@@ -69,6 +69,8 @@ func (a *ApplicationOfCreateMainAccount) Complete() (enumor.ApplicationStatus, m
 	switch a.req.Vendor {
 	case enumor.Aws:
 		accountID, err = a.createForAws(&rootAccount.BaseRootAccount)
+	case enumor.TCloud:
+		accountID, err = a.createForTCloud(&rootAccount.BaseRootAccount)
 	case enumor.Gcp:
 		accountID, err = a.createForGcp(&rootAccount.BaseRootAccount)
 	case enumor.Azure:
@@ -129,7 +131,9 @@ func (a *ApplicationOfCreateMainAccount) createForAws(rootAccount *protocore.Bas
 		CloudMainAccountName: accountResp.AccountName,
 		CloudMainAccountID:   accountResp.AccountID,
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().Aws.MainAccount.Create(
 		a.Cts.Kit,
@@ -189,7 +193,9 @@ func (a *ApplicationOfCreateMainAccount) createForGcp(rootAccount *protocore.Bas
 		CloudProjectID:   accountResp.ProjectID,
 		CloudProjectName: accountResp.ProjectName,
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().Gcp.MainAccount.Create(
 		a.Cts.Kit,
@@ -227,7 +233,9 @@ func (a *ApplicationOfCreateMainAccount) createForAzure(rootAccount *protocore.B
 		CloudSubscriptionName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
 		CloudInitPassword:     comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().Azure.MainAccount.Create(
 		a.Cts.Kit,
@@ -265,7 +273,9 @@ func (a *ApplicationOfCreateMainAccount) createForHuaWei(rootAccount *protocore.
 		CloudMainAccountName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
 		CloudInitPassword:    comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().HuaWei.MainAccount.Create(
 		a.Cts.Kit,
@@ -294,6 +304,46 @@ func (a *ApplicationOfCreateMainAccount) createForHuaWei(rootAccount *protocore.
 	return result.ID, nil
 }
 
+func (a *ApplicationOfCreateMainAccount) createForTCloud(rootAccount *protocore.BaseRootAccount) (string, error) {
+	req := a.req
+	comReq := a.completeReq
+
+	extension := &dataproto.TCloudMainAccountExtensionCreateReq{
+		CloudMainAccountID:   comReq.Extension[a.Vendor().GetMainAccountIDFieldName()],
+		CloudMainAccountName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
+		CloudInitPassword:    comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
+	}
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
+
+	result, err := a.Client.DataService().TCloud.MainAccount.Create(
+		a.Cts.Kit,
+		&dataproto.MainAccountCreateReq[dataproto.TCloudMainAccountExtensionCreateReq]{
+			Name:              a.completeReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
+			CloudID:           a.completeReq.Extension[a.Vendor().GetMainAccountIDFieldName()],
+			Email:             req.Email,
+			Managers:          req.Managers,
+			BakManagers:       req.BakManagers,
+			Site:              req.Site,
+			BusinessType:      req.BusinessType,
+			Status:            enumor.MainAccountStatusRUNNING,
+			ParentAccountName: rootAccount.Name,
+			ParentAccountID:   rootAccount.ID,
+			DeptID:            req.DeptID,
+			BkBizID:           req.BkBizID,
+			OpProductID:       req.OpProductID,
+			Memo:              req.Memo,
+			Extension:         extension,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return result.ID, nil
+}
+
 func (a *ApplicationOfCreateMainAccount) createForZenlayer(rootAccount *protocore.BaseRootAccount) (string, error) {
 	req := a.req
 	comReq := a.completeReq
@@ -303,7 +353,9 @@ func (a *ApplicationOfCreateMainAccount) createForZenlayer(rootAccount *protocor
 		CloudMainAccountName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
 		CloudInitPassword:    comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().Zenlayer.MainAccount.Create(
 		a.Cts.Kit,
@@ -341,7 +393,9 @@ func (a *ApplicationOfCreateMainAccount) createForKaopu(rootAccount *protocore.B
 		CloudMainAccountName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
 		CloudInitPassword:    comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
 	}
-	extension.EncryptSecretKey(a.Cipher)
+	if err := extension.EncryptSecretKey(a.Cipher); err != nil {
+		return "", fmt.Errorf("encrypt main account secret key failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+	}
 
 	result, err := a.Client.DataService().Kaopu.MainAccount.Create(
 		a.Cts.Kit,
@@ -383,6 +437,8 @@ func (a *ApplicationOfCreateMainAccount) sendMail(account *dataproto.MainAccount
 	switch account.Vendor {
 	case enumor.Aws:
 		loginUrl = AwsLoginAddress
+	case enumor.TCloud:
+		loginUrl = TCloudLoginAddress
 	case enumor.Gcp:
 		loginUrl = fmt.Sprintf(GcpLoginAddress, account.CloudID)
 	case enumor.HuaWei:
@@ -26,11 +26,13 @@ import (
 
 	"github.com/tidwall/gjson"
 
+	lgcapproval "hcm/cmd/cloud-server/logics/approval"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/service/application/handlers"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -56,6 +58,9 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 		esbCli:     c.EsbClient,
 		bkHcmUrl:   bkHcmUrl,
 		cmsiCli:    c.CmsiCli,
+
+		approvalEngine: cc.CloudServer().Approval.Engine,
+		approval:       c.Logics.Approval,
 	}
 	h := rest.NewHandler()
 	h.Add("ListApplications", "POST", "/applications/list", svc.ListApplications)
@@ -63,6 +68,15 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 	h.Add("CancelApplication", "PATCH", "/applications/{application_id}/cancel", svc.CancelApplication)
 	h.Add("ApproveApplication", "POST", "/applications/approve", svc.ApproveApplication)
 
+	// 内置审批引擎的审批接口，撤回使用 CancelApplication
+	h.Add("ApproveNativeApplication", "POST", "/applications/{application_id}/approval/approve",
+		svc.ApproveNativeApplication)
+	h.Add("RejectNativeApplication", "POST", "/applications/{application_id}/approval/reject",
+		svc.RejectNativeApplication)
+	h.Add("DelegateNativeApplication", "POST", "/applications/{application_id}/approval/delegate",
+		svc.DelegateNativeApplication)
+	h.Add("ListTodoApprovalTickets", "POST", "/approval_tickets/todo/list", svc.ListTodoApprovalTickets)
+
 	h.Add("CreateForAddAccount", "POST", "/applications/types/add_account", svc.CreateForAddAccount)
 	h.Add("CreateForCreateCvm", "POST", "/vendors/{vendor}/applications/types/create_cvm", svc.CreateForCreateCvm)
 	h.Add("CreateForCreateVpc", "POST", "/vendors/{vendor}/applications/types/create_vpc", svc.CreateForCreateVpc)
@@ -99,6 +113,9 @@ type applicationSvc struct {
 	esbCli     esb.Client
 	bkHcmUrl   string
 	cmsiCli    cmsi.Client
+
+	approvalEngine enumor.ApprovalEngine
+	approval       lgcapproval.Interface
 }
 
 func (a *applicationSvc) getCallbackUrl() string {
@@ -0,0 +1,242 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	lgcapproval "hcm/cmd/cloud-server/logics/approval"
+	"hcm/cmd/cloud-server/service/application/handlers"
+	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/rand"
+)
+
+// createWithNativeApproval 使用内置审批引擎创建申请单，按申请单类型和业务匹配审批链生成审批单
+func (a *applicationSvc) createWithNativeApproval(cts *rest.Contexts, req *proto.CreateCommonReq,
+	handler handlers.ApplicationHandler) (*core.CreateResult, error) {
+
+	applicationType := handler.GetType()
+	bizID := approval.DefaultChainBizID
+	if bizIDs := handler.GetBkBizIDs(); len(bizIDs) == 1 && bizIDs[0] > 0 {
+		bizID = bizIDs[0]
+	}
+
+	chain, err := a.approval.GetChain(cts.Kit, applicationType, bizID)
+	if err != nil {
+		return nil, err
+	}
+
+	managers, err := a.getPlatformManagers(cts, applicationType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 审批人变量和ITSM流程中的变量保持一致，由各申请单Handler提供实际审批人
+	vars := make(map[string][]string)
+	for _, one := range handler.GetItsmApprover(managers) {
+		vars[fmt.Sprintf("${%s}", one.Variable)] = one.Approvers
+	}
+	stages, err := lgcapproval.ResolveStages(chain.Stages, vars)
+	if err != nil {
+		logs.Errorf("resolve approval chain %s stages failed, err: %v, rid: %s", chain.ID, err, cts.Kit.Rid)
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	title, err := handler.RenderItsmTitle()
+	if err != nil {
+		return nil, fmt.Errorf("render approval ticket title error: %w", err)
+	}
+
+	sn := newNativeSN()
+	result, err := a.createApplication(cts, req, handler, sn, enumor.ApplicationSourceNative)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &lgcapproval.CreateTicketOption{
+		SN:              sn,
+		ApplicationID:   result.ID,
+		ApplicationType: applicationType,
+		BkBizID:         bizID,
+		Applicant:       cts.Kit.User,
+		Title:           title,
+		Stages:          stages,
+	}
+	if _, err = a.approval.CreateTicket(cts.Kit, opt); err != nil {
+		// 审批单创建失败时取消申请单，避免申请单一直处于审批中
+		if updateErr := a.updateStatusWithDetail(cts, result.ID, enumor.Cancelled, ""); updateErr != nil {
+			logs.Errorf("cancel application %s failed, err: %v, rid: %s", result.ID, updateErr, cts.Kit.Rid)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newNativeSN 生成内置审批引擎的申请单号
+func newNativeSN() string {
+	return fmt.Sprintf("HCM%s%s", time.Now().Format("20060102150405"), strings.ToUpper(rand.String(6)))
+}
+
+// getPlatformManagers 获取审批流程中配置的平台管理员，内置审批引擎下审批流程为可选配置
+func (a *applicationSvc) getPlatformManagers(cts *rest.Contexts, applicationType enumor.ApplicationType) (
+	[]string, error) {
+
+	result, err := a.client.DataService().Global.ApprovalProcess.ListApprovalProcesses(cts.Kit.Ctx,
+		cts.Kit.Header(), &dataproto.ApprovalProcessListReq{
+			Filter: tools.EqualExpression("application_type", applicationType),
+			Page:   &core.BasePage{Count: false, Start: 0, Limit: 1},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Details) == 0 || len(result.Details[0].Managers) == 0 {
+		return make([]string, 0), nil
+	}
+
+	return strings.Split(result.Details[0].Managers, ","), nil
+}
+
+// getNativeApplication 获取内置审批引擎的审批中的申请单
+func (a *applicationSvc) getNativeApplication(cts *rest.Contexts) (*dataproto.ApplicationResp, error) {
+	applicationID := cts.PathParameter("application_id").String()
+	application, err := a.client.DataService().Global.Application.GetApplication(cts.Kit.Ctx, cts.Kit.Header(),
+		applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.Source != enumor.ApplicationSourceNative {
+		return nil, errf.Newf(errf.InvalidParameter, "application %s is not approved by native approval engine",
+			applicationID)
+	}
+
+	if application.Status != enumor.Pending {
+		return nil, errf.Newf(errf.InvalidParameter, "application %s is %s, can not be approved", applicationID,
+			application.Status)
+	}
+
+	return application, nil
+}
+
+// operateNativeApproval 执行审批操作，审批单结束时同步更新申请单状态
+func (a *applicationSvc) operateNativeApproval(cts *rest.Contexts,
+	op func(ticket *approval.Ticket) error) (interface{}, error) {
+
+	application, err := a.getNativeApplication(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := a.approval.Operate(cts.Kit, application.ID, op)
+	if err != nil {
+		logs.Errorf("operate approval ticket of application %s failed, err: %v, rid: %s", application.ID, err,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 申请单状态已由data-service与审批单在同一事务中更新，这里只需在审批通过后进行资源交付
+	if ticket.Status == enumor.Pass {
+		// TODO: 需要引入异步任务框架，这里先暂时用goroutine异步执行，无法记录状态等的，包括可能被kill等异常情况都无法处理和记录
+		go a.deliver(cts, application)
+	}
+
+	return nil, nil
+}
+
+// ApproveNativeApplication 内置审批引擎审批通过
+func (a *applicationSvc) ApproveNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return a.operateNativeApproval(cts, func(ticket *approval.Ticket) error {
+		return lgcapproval.Approve(ticket, cts.Kit.User, req.Memo, time.Now())
+	})
+}
+
+// RejectNativeApplication 内置审批引擎审批驳回
+func (a *applicationSvc) RejectNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return a.operateNativeApproval(cts, func(ticket *approval.Ticket) error {
+		return lgcapproval.Reject(ticket, cts.Kit.User, req.Memo, time.Now())
+	})
+}
+
+// DelegateNativeApplication 内置审批引擎转交审批
+func (a *applicationSvc) DelegateNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalDelegateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return a.operateNativeApproval(cts, func(ticket *approval.Ticket) error {
+		return lgcapproval.Delegate(ticket, cts.Kit.User, req.DelegateTo, req.Memo, time.Now())
+	})
+}
+
+// ListTodoApprovalTickets 查询当前用户待审批的审批单
+func (a *applicationSvc) ListTodoApprovalTickets(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTodoListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("status", enumor.Pending),
+			tools.RuleJSONContains("current_approvers", cts.Kit.User),
+		),
+		Page: req.Page,
+	}
+	return a.client.DataService().Global.Approval.ListTicket(cts.Kit, listReq)
+}
@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approvalprocess
+
+import (
+	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateApprovalChain 创建内置审批引擎的审批链
+func (svc *service) CreateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeApprovalChain(cts, meta.Update); err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.ApprovalChainBatchCreateReq{
+		Chains: []dataproto.ApprovalChainCreate{{
+			ApplicationType: req.ApplicationType,
+			BkBizID:         req.BkBizID,
+			Stages:          req.Stages,
+			Memo:            req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.Approval.BatchCreateChain(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateApprovalChain 更新审批链，只影响之后创建的审批单，已创建的审批单使用创建时的审批链快照
+func (svc *service) UpdateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.ApprovalChainUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeApprovalChain(cts, meta.Update); err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.ApprovalChainBatchUpdateReq{
+		Chains: []dataproto.ApprovalChainUpdate{{ID: id, Stages: req.Stages, Memo: req.Memo}},
+	}
+	if err := svc.client.DataService().Global.Approval.BatchUpdateChain(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update approval chain %s failed, err: %v, rid: %s", id, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListApprovalChain 查询审批链
+func (svc *service) ListApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeApprovalChain(cts, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.Approval.ListChain(cts.Kit, req)
+}
+
+// DeleteApprovalChain 删除审批链
+func (svc *service) DeleteApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.authorizeApprovalChain(cts, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := svc.client.DataService().Global.Approval.BatchDeleteChain(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete approval chain %s failed, err: %v, rid: %s", id, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// authorizeApprovalChain 审批链属于单据管理的配置
+func (svc *service) authorizeApprovalChain(cts *rest.Contexts, action meta.Action) error {
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Application, Action: action}}
+	return svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+}
@@ -44,6 +44,11 @@ func InitService(c *capability.Capability) {
 	h.Add("GetApprovalProcessServiceID", http.MethodGet, "/approval_processes/service_id",
 		svc.GetApprovalProcessServiceID)
 
+	h.Add("CreateApprovalChain", http.MethodPost, "/approval_chains/create", svc.CreateApprovalChain)
+	h.Add("UpdateApprovalChain", http.MethodPatch, "/approval_chains/{id}", svc.UpdateApprovalChain)
+	h.Add("ListApprovalChain", http.MethodPost, "/approval_chains/list", svc.ListApprovalChain)
+	h.Add("DeleteApprovalChain", http.MethodDelete, "/approval_chains/{id}", svc.DeleteApprovalChain)
+
 	h.Load(c.WebService)
 }
 
@@ -52,6 +52,14 @@ func InitService(c *capability.Capability) {
 
 	h.Add("AssignResourceToBiz", http.MethodPost, "/resources/assign/bizs", s.AssignResourceToBiz)
 
+	// 资源自动分配规则
+	h.Add("CreateResAssignRule", http.MethodPost, "/res_assign_rules/create", s.CreateResAssignRule)
+	h.Add("UpdateResAssignRule", http.MethodPatch, "/res_assign_rules/{id}", s.UpdateResAssignRule)
+	h.Add("ListResAssignRule", http.MethodPost, "/res_assign_rules/list", s.ListResAssignRule)
+	h.Add("DeleteResAssignRule", http.MethodDelete, "/res_assign_rules/batch", s.DeleteResAssignRule)
+	h.Add("PreviewResAssignRule", http.MethodPost, "/res_assign_rules/preview", s.PreviewResAssignRule)
+	h.Add("ExecuteResAssignRule", http.MethodPost, "/res_assign_rules/execute", s.ExecuteResAssignRule)
+
 	h.Load(c.WebService)
 }
 
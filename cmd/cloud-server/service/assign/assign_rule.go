@@ -0,0 +1,325 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assign
+
+import (
+	"fmt"
+
+	assignrule "hcm/cmd/cloud-server/logics/assign-rule"
+	proto "hcm/pkg/api/cloud-server/assign"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateResAssignRule create res assign rule.
+func (svc *svc) CreateResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.CreateResAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeRuleAssign(cts.Kit, req.Conditions.AccountIDs, req.BkBizID); err != nil {
+		return nil, err
+	}
+
+	createReq := &cloud.ResAssignRuleBatchCreateReq{
+		Rules: []cloud.ResAssignRuleCreate{{
+			Name:       req.Name,
+			ResTypes:   req.ResTypes,
+			Priority:   req.Priority,
+			Enabled:    req.Enabled,
+			Conditions: req.Conditions,
+			BkBizID:    req.BkBizID,
+			BkCloudID:  converter.PtrToVal(req.BkCloudID),
+			Memo:       req.Memo,
+		}},
+	}
+	if req.BkCloudID == nil {
+		createReq.Rules[0].BkCloudID = constant.UnbindBkCloudID
+	}
+
+	result, err := svc.client.DataService().Global.ResAssignRule.BatchCreate(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.IDs) == 0 {
+		return nil, fmt.Errorf("create res assign rule succeed but no id returned")
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateResAssignRule update res assign rule.
+func (svc *svc) UpdateResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.UpdateResAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listResAssignRule(cts.Kit, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "res assign rule %s not found", id)
+	}
+
+	// 需要同时具有原规则和更新后规则的分配权限
+	if err = svc.authorizeRules(cts.Kit, meta.Assign, rules); err != nil {
+		return nil, err
+	}
+
+	accountIDs := rules[0].Conditions.AccountIDs
+	if req.Conditions != nil {
+		accountIDs = req.Conditions.AccountIDs
+	}
+	bizID := rules[0].BkBizID
+	if req.BkBizID != 0 {
+		bizID = req.BkBizID
+	}
+	if err = svc.authorizeRuleAssign(cts.Kit, accountIDs, bizID); err != nil {
+		return nil, err
+	}
+
+	updateReq := &cloud.ResAssignRuleBatchUpdateReq{
+		Rules: []cloud.ResAssignRuleUpdate{{
+			ID:         id,
+			Name:       req.Name,
+			ResTypes:   req.ResTypes,
+			Priority:   req.Priority,
+			Enabled:    req.Enabled,
+			Conditions: req.Conditions,
+			BkBizID:    req.BkBizID,
+			BkCloudID:  req.BkCloudID,
+			Memo:       req.Memo,
+		}},
+	}
+	if err = svc.client.DataService().Global.ResAssignRule.BatchUpdate(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update res assign rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListResAssignRule list res assign rule.
+func (svc *svc) ListResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := svc.client.DataService().Global.ResAssignRule.List(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("list res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.authorizeRules(cts.Kit, meta.Find, result.Details); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteResAssignRule delete res assign rule.
+func (svc *svc) DeleteResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.DeleteResAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listResAssignRule(cts.Kit, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	if err = svc.authorizeRules(cts.Kit, meta.Assign, rules); err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err = svc.client.DataService().Global.ResAssignRule.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete res assign rule failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// PreviewResAssignRule preview resources that would be assigned by res assign rules.
+func (svc *svc) PreviewResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	return svc.runResAssignRule(cts, true)
+}
+
+// ExecuteResAssignRule assign matched unassigned resources by res assign rules immediately.
+func (svc *svc) ExecuteResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	return svc.runResAssignRule(cts, false)
+}
+
+func (svc *svc) runResAssignRule(cts *rest.Contexts, dryRun bool) (interface{}, error) {
+	req := new(proto.RunResAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listResAssignRule(cts.Kit, req.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	action := meta.Assign
+	if dryRun {
+		action = meta.Find
+	}
+	if err = svc.authorizeRules(cts.Kit, action, rules); err != nil {
+		return nil, err
+	}
+
+	opt := &assignrule.RunOption{RuleIDs: req.RuleIDs, DryRun: dryRun}
+	return assignrule.Run(cts.Kit, svc.client.DataService(), opt)
+}
+
+// listResAssignRule list res assign rules by ids, list all rules when ids is empty.
+func (svc *svc) listResAssignRule(kt *kit.Kit, ids []string) ([]corecloud.ResAssignRule, error) {
+	listReq := &core.ListReq{
+		Filter: tools.AllExpression(),
+		Page:   core.NewDefaultBasePage(),
+	}
+	if len(ids) != 0 {
+		listReq.Filter = tools.ContainersExpression("id", ids)
+	}
+
+	rules := make([]corecloud.ResAssignRule, 0)
+	for {
+		result, err := svc.client.DataService().Global.ResAssignRule.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list res assign rule failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		rules = append(rules, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return rules, nil
+}
+
+// authorizeRules 校验规则涉及的账号和业务的资源查看或分配权限
+func (svc *svc) authorizeRules(kt *kit.Kit, action meta.Action, rules []corecloud.ResAssignRule) error {
+	authRes := make([]meta.ResourceAttribute, 0)
+	for _, rule := range rules {
+		for _, accountID := range slice.Unique(rule.Conditions.AccountIDs) {
+			authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.CloudResource,
+				Action: action, ResourceID: accountID}, BizID: rule.BkBizID})
+		}
+	}
+
+	if len(authRes) == 0 {
+		return nil
+	}
+
+	return svc.authorizer.AuthorizeWithPerm(kt, authRes...)
+}
+
+// authorizeRuleAssign 校验账号的资源分配权限，并且账号需要关联了分配的目标业务
+func (svc *svc) authorizeRuleAssign(kt *kit.Kit, accountIDs []string, bizID int64) error {
+	accountIDs = slice.Unique(accountIDs)
+	authRes := make([]meta.ResourceAttribute, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.CloudResource,
+			Action: meta.Assign, ResourceID: accountID}, BizID: bizID})
+	}
+	if err := svc.authorizer.AuthorizeWithPerm(kt, authRes...); err != nil {
+		return err
+	}
+
+	relReq := &core.ListReq{
+		Filter: tools.ContainersExpression("account_id", accountIDs),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"account_id", "bk_biz_id"},
+	}
+	relRes, err := svc.client.DataService().Global.Account.ListAccountBizRel(kt.Ctx, kt.Header(), relReq)
+	if err != nil {
+		logs.Errorf("list account biz relation failed, err: %v, accounts: %v, rid: %s", err, accountIDs, kt.Rid)
+		return err
+	}
+
+	matched := make(map[string]struct{}, len(accountIDs))
+	for _, rel := range relRes.Details {
+		if rel.BkBizID == bizID || rel.BkBizID == constant.AttachedAllBiz {
+			matched[rel.AccountID] = struct{}{}
+		}
+	}
+
+	for _, accountID := range accountIDs {
+		if _, ok := matched[accountID]; !ok {
+			return errf.Newf(errf.InvalidParameter, "account(%s) and biz(%d) not matches", accountID, bizID)
+		}
+	}
+
+	return nil
+}
@@ -33,41 +33,29 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
-	"hcm/pkg/serviced"
 	"hcm/pkg/tools/retry"
 )
 
-// CloudBillConfigCreate 定时生成云账单配置
-func CloudBillConfigCreate(intervalMin time.Duration, sd serviced.ServiceDiscover, cliSet *client.ClientSet) {
-	logs.Infof("account cloud bill config pipeline enable && start, syncIntervalMin: %v", intervalMin)
+// CloudBillConfigCreate 生成云账单配置，由周期性任务按定时任务流触发
+func CloudBillConfigCreate(kt *kit.Kit, cliSet *client.ClientSet) error {
+	start := time.Now()
+	logs.Infof("account cloud bill config pipeline start, time: %v, rid: %s", start, kt.Rid)
 
-	for {
-		time.Sleep(intervalMin)
-
-		if !sd.IsMaster() {
-			continue
-		}
-
-		kt := core.NewBackendKit()
+	waitGroup := new(sync.WaitGroup)
 
-		start := time.Now()
-		logs.Infof("account cloud bill config pipeline start, time: %v, rid: %s", start, kt.Rid)
-
-		waitGroup := new(sync.WaitGroup)
-
-		vendors := []enumor.Vendor{enumor.Aws}
-		waitGroup.Add(len(vendors))
-		for _, vendor := range vendors {
-			go func(vendor enumor.Vendor) {
-				allAccountBillConfig(kt, cliSet, vendor)
-				waitGroup.Done()
-			}(vendor)
-		}
+	vendors := []enumor.Vendor{enumor.Aws}
+	waitGroup.Add(len(vendors))
+	for _, vendor := range vendors {
+		go func(vendor enumor.Vendor) {
+			allAccountBillConfig(kt, cliSet, vendor)
+			waitGroup.Done()
+		}(vendor)
+	}
 
-		waitGroup.Wait()
+	waitGroup.Wait()
 
-		logs.Infof("cloud resource all sync end, time: %v, rid: %s", start, kt.Rid)
-	}
+	logs.Infof("account cloud bill config pipeline end, cost: %v, rid: %s", time.Since(start), kt.Rid)
+	return nil
 }
 
 // allAccountBillConfig all account bill config.
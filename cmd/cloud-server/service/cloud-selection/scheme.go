@@ -28,6 +28,7 @@ import (
 	"hcm/pkg/api/core"
 	coresel "hcm/pkg/api/core/cloud-selection"
 	dsselection "hcm/pkg/api/data-service/cloud-selection"
+	"hcm/pkg/cc"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
@@ -281,16 +282,8 @@ func (svc *service) GenerateRecommendScheme(cts *rest.Contexts) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	algPlugin, err := plugin.NewPlugin[recommend.AlgorithmInput, recommend.AlgorithmOutput](
-		svc.cfg.AlgorithmPlugin.BinaryPath, svc.cfg.AlgorithmPlugin.Args...)
-	if err != nil {
-		logs.Errorf("init algorithm plugin fail, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, fmt.Errorf("init algorithm plugin fail")
-	}
-
-	algOut, err := algPlugin.Execute(cts.Kit, algIn)
+	algOut, err := svc.runAlgorithm(cts, algIn)
 	if err != nil {
-		logs.Errorf("fail to execute algorithm plugin, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
 	}
 	// converter result
@@ -310,6 +303,34 @@ func (svc *service) GenerateRecommendScheme(cts *rest.Contexts) (any, error) {
 
 }
 
+// runAlgorithm 默认使用内置算法求解，配置为 plugin 时调用外部算法插件
+func (svc *service) runAlgorithm(cts *rest.Contexts, algIn *recommend.AlgorithmInput) (
+	*recommend.AlgorithmOutput, error) {
+
+	if svc.cfg.AlgorithmType != cc.PluginAlgorithm {
+		algOut, err := recommend.Solve(algIn)
+		if err != nil {
+			logs.Errorf("fail to solve recommend algorithm, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		return algOut, nil
+	}
+
+	algPlugin, err := plugin.NewPlugin[recommend.AlgorithmInput, recommend.AlgorithmOutput](
+		svc.cfg.AlgorithmPlugin.BinaryPath, svc.cfg.AlgorithmPlugin.Args...)
+	if err != nil {
+		logs.Errorf("init algorithm plugin fail, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("init algorithm plugin fail")
+	}
+
+	algOut, err := algPlugin.Execute(cts.Kit, algIn)
+	if err != nil {
+		logs.Errorf("fail to execute algorithm plugin, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	return algOut, nil
+}
+
 func (svc *service) buildALgIn(cts *rest.Contexts, req *csselection.GenSchemeReq,
 	idcByID map[string]coresel.Idc) (*recommend.AlgorithmInput, error) {
 
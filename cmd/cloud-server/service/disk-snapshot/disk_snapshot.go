@@ -0,0 +1,272 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	proto "hcm/pkg/api/cloud-server/disk-snapshot"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListDiskSnapshot list disk snapshot.
+func (svc *diskSnapshotSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizDiskSnapshot list biz disk snapshot.
+func (svc *diskSnapshotSvc) ListBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListBizAuthRes)
+}
+
+func (svc *diskSnapshotSvc) listDiskSnapshot(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 快照复用云盘的查看权限
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer, ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &dataproto.DiskSnapshotListResult{Details: make([]corecloud.DiskSnapshot, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.DiskSnapshot.List(cts.Kit, req)
+}
+
+// CreateDiskSnapshot create disk snapshot.
+func (svc *diskSnapshotSvc) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// CreateBizDiskSnapshot create biz disk snapshot.
+func (svc *diskSnapshotSvc) CreateBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSnapshotSvc) createDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.CloudResourceType(table.DiskTable), req.DiskID, types.ResWithRecycleBasicFields...)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 创建快照视为对源云盘的更新操作
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &hcproto.CreateReq{
+		AccountID: basicInfo.AccountID,
+		DiskID:    req.DiskID,
+		Name:      req.Name,
+		Memo:      req.Memo,
+	}
+	result, err := svc.snapshotLgc.Create(cts.Kit, basicInfo.Vendor, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot failed, err: %v, disk: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshot.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// BatchDeleteBizDiskSnapshot batch delete biz disk snapshot.
+func (svc *diskSnapshotSvc) BatchDeleteBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSnapshotSvc) batchDeleteDiskSnapshot(cts *rest.Contexts,
+	validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listSnapshot(cts.Kit, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) != len(req.IDs) {
+		return nil, errf.New(errf.RecordNotFound, "some disk snapshots not found")
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Delete, BasicInfos: snapshotBasicInfos(snapshots)})
+	if err != nil {
+		return nil, err
+	}
+
+	// 云上删除快照需要按账号调用
+	accountMap := make(map[string][]string)
+	vendorMap := make(map[string]enumor.Vendor)
+	for _, one := range snapshots {
+		accountMap[one.AccountID] = append(accountMap[one.AccountID], one.ID)
+		vendorMap[one.AccountID] = one.Vendor
+	}
+
+	for accountID, ids := range accountMap {
+		if err = svc.snapshotLgc.BatchDelete(cts.Kit, vendorMap[accountID], accountID, ids); err != nil {
+			logs.Errorf("delete disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// RollbackDiskSnapshot rollback source disk with disk snapshot.
+func (svc *diskSnapshotSvc) RollbackDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// RollbackBizDiskSnapshot rollback biz source disk with disk snapshot.
+func (svc *diskSnapshotSvc) RollbackBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSnapshotSvc) rollbackDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.RollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listSnapshot(cts.Kit, []string{req.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot %s not found", req.ID)
+	}
+	snapshot := snapshots[0]
+	if !proto.IsRollbackSupported(snapshot.Vendor) {
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support rolling back disk with snapshot",
+			snapshot.Vendor)
+	}
+	if len(snapshot.DiskID) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "source disk of snapshot %s not found", req.ID)
+	}
+
+	diskInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.CloudResourceType(table.DiskTable), snapshot.DiskID, types.ResWithRecycleBasicFields...)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 回滚会覆盖源云盘的数据，需要同时校验快照和源云盘
+	basicInfos := snapshotBasicInfos(snapshots)
+	basicInfos[diskInfo.ID] = *diskInfo
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	rollbackReq := &hcproto.RollbackReq{
+		AccountID:        snapshot.AccountID,
+		ID:               snapshot.ID,
+		AutoStopInstance: req.AutoStopInstance,
+	}
+	if err = svc.snapshotLgc.Rollback(cts.Kit, snapshot.Vendor, rollbackReq, snapshot.DiskID); err != nil {
+		logs.Errorf("rollback disk snapshot failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *diskSnapshotSvc) listSnapshot(kt *kit.Kit, ids []string) ([]corecloud.DiskSnapshot, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := svc.client.DataService().Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	return resp.Details, nil
+}
+
+func snapshotBasicInfos(snapshots []corecloud.DiskSnapshot) map[string]types.CloudResourceBasicInfo {
+	infos := make(map[string]types.CloudResourceBasicInfo, len(snapshots))
+	for _, one := range snapshots {
+		infos[one.ID] = types.CloudResourceBasicInfo{
+			ResType:   enumor.DiskSnapshotCloudResType,
+			ID:        one.ID,
+			Vendor:    one.Vendor,
+			AccountID: one.AccountID,
+			BkBizID:   one.BkBizID,
+			Region:    one.Region,
+			CloudID:   one.CloudID,
+		}
+	}
+
+	return infos
+}
@@ -0,0 +1,256 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"fmt"
+
+	proto "hcm/pkg/api/cloud-server/disk-snapshot"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateDiskSnapshotPolicy create biz disk snapshot policy.
+func (svc *diskSnapshotSvc) CreateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := svc.authorizeBizPolicy(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.CreatePolicyReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.validatePolicyDisks(cts.Kit, bizID, req.DiskIDs); err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.DiskSnapshotPolicyBatchCreateReq{
+		Policies: []dataproto.DiskSnapshotPolicyCreate{{
+			Name:           req.Name,
+			BkBizID:        bizID,
+			ScopeType:      req.ScopeType,
+			DiskIDs:        slice.Unique(req.DiskIDs),
+			IntervalHours:  req.IntervalHours,
+			RetentionCount: req.RetentionCount,
+			Enabled:        req.Enabled,
+			Memo:           req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.DiskSnapshot.BatchCreatePolicy(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.IDs) == 0 {
+		return nil, fmt.Errorf("create disk snapshot policy succeed but no id returned")
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateDiskSnapshotPolicy update biz disk snapshot policy.
+func (svc *diskSnapshotSvc) UpdateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := svc.authorizeBizPolicy(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.UpdatePolicyReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policies, err := svc.listBizPolicy(cts.Kit, bizID, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot policy %s not found", id)
+	}
+
+	if len(req.DiskIDs) != 0 {
+		if policies[0].ScopeType != corecloud.DiskScopeSnapshotPolicy {
+			return nil, errf.New(errf.InvalidParameter, "disk_ids can only be updated when scope_type is disk")
+		}
+		if err = svc.validatePolicyDisks(cts.Kit, bizID, req.DiskIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	updateReq := &dataproto.DiskSnapshotPolicyBatchUpdateReq{
+		Policies: []dataproto.DiskSnapshotPolicyUpdate{{
+			ID:             id,
+			Name:           req.Name,
+			DiskIDs:        slice.Unique(req.DiskIDs),
+			IntervalHours:  req.IntervalHours,
+			RetentionCount: req.RetentionCount,
+			Enabled:        req.Enabled,
+			Memo:           req.Memo,
+		}},
+	}
+	if err = svc.client.DataService().Global.DiskSnapshot.BatchUpdatePolicy(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshotPolicy list biz disk snapshot policy.
+func (svc *diskSnapshotSvc) ListDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer, ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &dataproto.DiskSnapshotPolicyListResult{Details: make([]corecloud.DiskSnapshotPolicy, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.DiskSnapshot.ListPolicy(cts.Kit, req)
+}
+
+// BatchDeleteDiskSnapshotPolicy batch delete biz disk snapshot policy, snapshots created by the policies are kept.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := svc.authorizeBizPolicy(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.BatchDeletePolicyReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policies, err := svc.listBizPolicy(cts.Kit, bizID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) != len(req.IDs) {
+		return nil, errf.New(errf.InvalidParameter, "some disk snapshot policies not found in biz")
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err = svc.client.DataService().Global.DiskSnapshot.BatchDeletePolicy(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete disk snapshot policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// authorizeBizPolicy 快照策略会定时创建和删除业务下云盘的快照，操作策略需要业务下云盘的编辑权限
+func (svc *diskSnapshotSvc) authorizeBizPolicy(cts *rest.Contexts) (int64, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return 0, err
+	}
+	if bizID <= 0 {
+		return 0, errf.New(errf.InvalidParameter, "biz id is invalid")
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Disk, Action: meta.Update}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return 0, err
+	}
+
+	return bizID, nil
+}
+
+// validatePolicyDisks 校验策略指定的云盘都属于策略所在业务
+func (svc *diskSnapshotSvc) validatePolicyDisks(kt *kit.Kit, bizID int64, diskIDs []string) error {
+	diskIDs = slice.Unique(diskIDs)
+	if len(diskIDs) == 0 {
+		return nil
+	}
+
+	count := uint64(0)
+	for _, batch := range slice.Split(diskIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleIn("id", batch), tools.RuleEqual("bk_biz_id", bizID)),
+			Page:   core.NewCountPage(),
+		}
+		resp, err := svc.client.DataService().Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("count policy disk failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+		count += resp.Count
+	}
+
+	if count != uint64(len(diskIDs)) {
+		return errf.Newf(errf.InvalidParameter, "some disks not found in biz %d", bizID)
+	}
+
+	return nil
+}
+
+func (svc *diskSnapshotSvc) listBizPolicy(kt *kit.Kit, bizID int64, ids []string) ([]corecloud.DiskSnapshotPolicy,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", ids), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := svc.client.DataService().Global.DiskSnapshot.ListPolicy(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	return resp.Details, nil
+}
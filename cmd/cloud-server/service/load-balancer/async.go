@@ -164,9 +164,7 @@ func (svc *lbSvc) cloneFlow(cts *rest.Contexts, operateAuth handler.ValidWithAut
 		return nil, err
 	}
 	// 只能是终态 才能重新发起
-	if rel.Status != enumor.CancelResFlowStatus &&
-		rel.Status != enumor.TimeoutResFlowStatus &&
-		rel.Status != enumor.SuccessResFlowStatus {
+	if !rel.Status.IsEnd() {
 		return nil, errf.Newf(errf.InvalidParameter, "given flow status incorrect: %s", rel.Status)
 	}
 
@@ -0,0 +1,168 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"encoding/json"
+
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+func (svc *lbSvc) createAwsLB(kt *kit.Kit, rawReq json.RawMessage) (any, error) {
+	req := new(hcproto.AwsLoadBalancerCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.BkBizID = constant.UnassignedBiz
+	return svc.client.HCService().Aws.LoadBalancer.Create(kt, req)
+}
+
+func (svc *lbSvc) createAwsTargetGroup(kt *kit.Kit, rawReq json.RawMessage, bkBizID int64) (any, error) {
+	req := new(hcproto.AwsTargetGroupCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.BkBizID = bkBizID
+	return svc.client.HCService().Aws.LoadBalancer.CreateTargetGroup(kt, req)
+}
+
+func (svc *lbSvc) createAwsListener(kt *kit.Kit, rawReq json.RawMessage, bkBizID int64, lbID string) (any, error) {
+	req := new(hcproto.AwsListenerCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	req.LbID = lbID
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 预检测-负载均衡属于当前业务且没有执行中的任务
+	if err := svc.checkLBBeforeOperate(kt, bkBizID, lbID); err != nil {
+		return nil, err
+	}
+
+	result, err := svc.client.HCService().Aws.LoadBalancer.CreateListener(kt, req)
+	if err != nil {
+		logs.Errorf("fail to create aws listener, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *lbSvc) updateAwsListener(kt *kit.Kit, body json.RawMessage, id string) (any, error) {
+	req := new(hcproto.AwsListenerUpdateReq)
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.client.HCService().Aws.LoadBalancer.UpdateListener(kt, id, req); err != nil {
+		logs.Errorf("update aws listener failed, req: %+v, err: %v, rid: %s", req, err, kt.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (svc *lbSvc) updateAwsTargetGroupHealthCheck(cts *rest.Contexts, tgID string) (any, error) {
+	req := new(hcproto.AwsTargetGroupUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 云上更新成功后由 hc-service 同步回db
+	if err := svc.client.HCService().Aws.LoadBalancer.UpdateTargetGroup(cts.Kit, tgID, req); err != nil {
+		logs.Errorf("update aws target group health check failed, id: %s, err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// UpdateBizAwsLoadBalancer 业务下更新aws负载均衡
+func (svc *lbSvc) UpdateBizAwsLoadBalancer(cts *rest.Contexts) (any, error) {
+	lbID := cts.PathParameter("id").String()
+	if len(lbID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(hcproto.AwsLBUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authAndAuditLBUpdate(cts, lbID, enumor.Aws, req); err != nil {
+		return nil, err
+	}
+
+	return nil, svc.client.HCService().Aws.LoadBalancer.Update(cts.Kit, lbID, req)
+}
+
+// UpdateBizAwsUrlRule 业务下更新aws监听器规则
+func (svc *lbSvc) UpdateBizAwsUrlRule(cts *rest.Contexts) (any, error) {
+	lblID := cts.PathParameter("lbl_id").String()
+	if len(lblID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "listener is required")
+	}
+
+	ruleID := cts.PathParameter("rule_id").String()
+	if len(ruleID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "rule id is required")
+	}
+
+	req := new(hcproto.AwsRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authAndAuditRuleUpdate(cts, lblID, ruleID, enumor.Aws, req); err != nil {
+		return nil, err
+	}
+
+	return nil, svc.client.HCService().Aws.LoadBalancer.UpdateUrlRule(cts.Kit, ruleID, req)
+}
@@ -71,6 +71,10 @@ func (svc *lbSvc) BatchCreateLB(cts *rest.Contexts) (any, error) {
 	switch accountInfo.Vendor {
 	case enumor.TCloud:
 		return svc.batchCreateTCloudLB(cts.Kit, req.Data)
+	case enumor.Aws:
+		return svc.createAwsLB(cts.Kit, req.Data)
+	case enumor.HuaWei:
+		return svc.createHuaWeiLB(cts.Kit, req.Data)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", accountInfo.Vendor)
 	}
@@ -132,6 +136,10 @@ func (svc *lbSvc) createBizTargetGroup(cts *rest.Contexts, authHandler handler.V
 	switch accountInfo.Vendor {
 	case enumor.TCloud:
 		return svc.batchCreateTCloudTargetGroup(cts.Kit, req.Data, bkBizID)
+	case enumor.Aws:
+		return svc.createAwsTargetGroup(cts.Kit, req.Data, bkBizID)
+	case enumor.HuaWei:
+		return svc.createHuaWeiTargetGroup(cts.Kit, req.Data, bkBizID)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", accountInfo.Vendor)
 	}
@@ -227,6 +235,10 @@ func (svc *lbSvc) createListener(cts *rest.Contexts, authHandler handler.ValidWi
 	switch accountInfo.Vendor {
 	case enumor.TCloud:
 		return svc.batchCreateTCloudListener(cts.Kit, req.Data, bkBizID, lbID)
+	case enumor.Aws:
+		return svc.createAwsListener(cts.Kit, req.Data, bkBizID, lbID)
+	case enumor.HuaWei:
+		return svc.createHuaWeiListener(cts.Kit, req.Data, bkBizID, lbID)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", accountInfo.Vendor)
 	}
@@ -282,6 +294,24 @@ func (svc *lbSvc) batchCreateTCloudListener(kt *kit.Kit, rawReq json.RawMessage,
 	return &core.BatchCreateResult{IDs: []string{createResp.CloudLblID}}, nil
 }
 
+// checkLBBeforeOperate 检查负载均衡属于当前业务，且没有执行中的异步任务
+func (svc *lbSvc) checkLBBeforeOperate(kt *kit.Kit, bkBizID int64, lbID string) error {
+	lbInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(kt, enumor.LoadBalancerCloudResType, lbID)
+	if err != nil {
+		logs.Errorf("get load balancer basic info failed, err: %v, lbID: %s, rid: %s", err, lbID, kt.Rid)
+		return err
+	}
+	if lbInfo.BkBizID != bkBizID {
+		return errf.Newf(errf.InvalidParameter, "load balancer: %s not belongs to biz: %d", lbID, bkBizID)
+	}
+
+	// 预检测-是否有执行中的负载均衡
+	if _, err = svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType); err != nil {
+		return err
+	}
+	return nil
+}
+
 // checkLayerFourGlobalUniqueTarget 检查四层监听器，绑定的目标组里面的RS，是否已绑定其他监听器
 func (svc *lbSvc) checkLayerFourGlobalUniqueTarget(kt *kit.Kit, req *hcproto.ListenerWithRuleCreateReq) error {
 	if req.Protocol.IsLayer7Protocol() {
@@ -21,14 +21,13 @@
 package loadbalancer
 
 import (
-	"errors"
 	"fmt"
 
 	"hcm/cmd/cloud-server/logics/async"
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
 	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
 	dataproto "hcm/pkg/api/data-service/cloud"
-	hcproto "hcm/pkg/api/hc-service/load-balancer"
 	ts "hcm/pkg/api/task-server"
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
@@ -141,12 +140,28 @@ func (svc *lbSvc) deleteListener(cts *rest.Contexts, validHandler handler.ValidW
 		return nil, err
 	}
 
-	// delete tcloud cloud listener
-	err = svc.client.HCService().TCloud.Clb.DeleteListener(cts.Kit, req)
-	if err != nil {
-		logs.Errorf("[%s] request hcservice to delete listener failed, ids: %s, err: %v, rid: %s",
-			enumor.TCloud, req.IDs, err, cts.Kit.Rid)
-		return nil, err
+	vendorIDsMap := make(map[enumor.Vendor][]string)
+	for id, info := range basicInfoMap {
+		vendorIDsMap[info.Vendor] = append(vendorIDsMap[info.Vendor], id)
+	}
+
+	for vendor, ids := range vendorIDsMap {
+		delReq := &core.BatchDeleteReq{IDs: ids}
+		switch vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.Clb.DeleteListener(cts.Kit, delReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.LoadBalancer.BatchDeleteListener(cts.Kit, delReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.LoadBalancer.BatchDeleteListener(cts.Kit, delReq)
+		default:
+			return nil, fmt.Errorf("vendor: %s not support", vendor)
+		}
+		if err != nil {
+			logs.Errorf("[%s] request hcservice to delete listener failed, ids: %s, err: %v, rid: %s",
+				vendor, ids, err, cts.Kit.Rid)
+			return nil, err
+		}
 	}
 
 	return nil, nil
@@ -187,10 +202,11 @@ func (svc *lbSvc) batchDeleteLoadBalancer(cts *rest.Contexts, validHandler handl
 		if !exist {
 			return nil, fmt.Errorf("load balancer(%s) not found", lbID)
 		}
-		if info.Vendor != enumor.TCloud {
-			return nil, errors.New("only supports tcloud")
+		switch info.Vendor {
+		case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+		default:
+			return nil, fmt.Errorf("vendor: %s not support", info.Vendor)
 		}
-
 	}
 
 	// 业务校验、鉴权
@@ -204,7 +220,7 @@ func (svc *lbSvc) batchDeleteLoadBalancer(cts *rest.Contexts, validHandler handl
 		return nil, err
 	}
 
-	if err = svc.loadBalancerDeleteCheck(cts.Kit, req.IDs); err != nil {
+	if err = svc.loadBalancerDeleteCheck(cts.Kit, lbInfoMap); err != nil {
 		return nil, err
 	}
 	// 按规则删除审计
@@ -215,7 +231,7 @@ func (svc *lbSvc) batchDeleteLoadBalancer(cts *rest.Contexts, validHandler handl
 	}
 
 	// 按账号+地域分列表
-	tasks := buildTCloudLBDeletionTasks(lbInfoMap)
+	tasks := buildLBDeletionTasks(lbInfoMap)
 	flowReq := &ts.AddCustomFlowReq{
 		Name:        enumor.FlowDeleteLoadBalancer,
 		ShareData:   nil,
@@ -230,21 +246,18 @@ func (svc *lbSvc) batchDeleteLoadBalancer(cts *rest.Contexts, validHandler handl
 }
 
 // 负载均衡删除检查
-func (svc *lbSvc) loadBalancerDeleteCheck(kt *kit.Kit, lbIDs []string) error {
-	// 检查是否启用删除检查
-	lbReq := &core.ListReq{
-		Filter: tools.ContainersExpression("id", lbIDs),
-		Page:   core.NewDefaultBasePage(),
-	}
-	lbResp, err := svc.client.DataService().TCloud.LoadBalancer.ListLoadBalancer(kt, lbReq)
-	if err != nil {
-		logs.Errorf("fail to query load balancer for delete load balancers, err: %v, lb ids: %v, rid: %s",
-			err, lbIDs, kt.Rid)
-		return nil
+func (svc *lbSvc) loadBalancerDeleteCheck(kt *kit.Kit, infoMap map[string]types.CloudResourceBasicInfo) error {
+	lbIDs := make([]string, 0, len(infoMap))
+	vendorIDsMap := make(map[enumor.Vendor][]string)
+	for id, info := range infoMap {
+		lbIDs = append(lbIDs, id)
+		vendorIDsMap[info.Vendor] = append(vendorIDsMap[info.Vendor], id)
 	}
-	for _, lb := range lbResp.Details {
-		if cvt.PtrToVal(lb.Extension.DeleteProtect) {
-			return fmt.Errorf("%s(%s) is protected for delection", lb.Name, lb.CloudID)
+
+	// 检查是否启用删除检查
+	for vendor, ids := range vendorIDsMap {
+		if err := svc.deleteProtectCheck(kt, vendor, ids); err != nil {
+			return err
 		}
 	}
 
@@ -267,33 +280,85 @@ func (svc *lbSvc) loadBalancerDeleteCheck(kt *kit.Kit, lbIDs []string) error {
 	return nil
 }
 
-func buildTCloudLBDeletionTasks(infoMap map[string]types.CloudResourceBasicInfo) (tasks []ts.CustomFlowTask) {
+// deleteProtectCheck 检查负载均衡是否开启了删除保护
+func (svc *lbSvc) deleteProtectCheck(kt *kit.Kit, vendor enumor.Vendor, lbIDs []string) error {
+	lbReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", lbIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	protectedLBs := make([]corelb.BaseLoadBalancer, 0)
+	switch vendor {
+	case enumor.TCloud:
+		lbResp, err := svc.client.DataService().TCloud.LoadBalancer.ListLoadBalancer(kt, lbReq)
+		if err != nil {
+			logs.Errorf("fail to query load balancer for delete load balancers, err: %v, lb ids: %v, rid: %s",
+				err, lbIDs, kt.Rid)
+			return err
+		}
+		for _, lb := range lbResp.Details {
+			if lb.Extension != nil && cvt.PtrToVal(lb.Extension.DeleteProtect) {
+				protectedLBs = append(protectedLBs, lb.BaseLoadBalancer)
+			}
+		}
+	case enumor.Aws:
+		lbResp, err := svc.client.DataService().Aws.LoadBalancer.ListLoadBalancer(kt, lbReq)
+		if err != nil {
+			logs.Errorf("fail to query aws load balancer for delete, err: %v, lb ids: %v, rid: %s", err, lbIDs,
+				kt.Rid)
+			return err
+		}
+		for _, lb := range lbResp.Details {
+			if lb.Extension != nil && cvt.PtrToVal(lb.Extension.DeletionProtection) {
+				protectedLBs = append(protectedLBs, lb.BaseLoadBalancer)
+			}
+		}
+	case enumor.HuaWei:
+		lbResp, err := svc.client.DataService().HuaWei.LoadBalancer.ListLoadBalancer(kt, lbReq)
+		if err != nil {
+			logs.Errorf("fail to query huawei load balancer for delete, err: %v, lb ids: %v, rid: %s", err, lbIDs,
+				kt.Rid)
+			return err
+		}
+		for _, lb := range lbResp.Details {
+			if lb.Extension != nil && cvt.PtrToVal(lb.Extension.DeletionProtectionEnable) {
+				protectedLBs = append(protectedLBs, lb.BaseLoadBalancer)
+			}
+		}
+	default:
+		return fmt.Errorf("vendor: %s not support", vendor)
+	}
+
+	if len(protectedLBs) != 0 {
+		return fmt.Errorf("%s(%s) is protected for delection", protectedLBs[0].Name, protectedLBs[0].CloudID)
+	}
+	return nil
+}
+
+// buildLBDeletionTasks 按云厂商+账号+地域拆分删除任务
+func buildLBDeletionTasks(infoMap map[string]types.CloudResourceBasicInfo) (tasks []ts.CustomFlowTask) {
 
-	tcloudReqMap := make(map[string]*hcproto.TCloudBatchDeleteLoadbalancerReq, len(infoMap))
-	// TODO: 后期支持多vendor
+	reqMap := make(map[string]*actionlb.DeleteLoadBalancerOption, len(infoMap))
 	for id, info := range infoMap {
-		key := genAccountRegionKey(info)
-		if tcloudReqMap[key] == nil {
-			tcloudReqMap[key] = &hcproto.TCloudBatchDeleteLoadbalancerReq{
+		key := string(info.Vendor) + "_" + genAccountRegionKey(info)
+		if reqMap[key] == nil {
+			reqMap[key] = &actionlb.DeleteLoadBalancerOption{
+				Vendor:    info.Vendor,
 				AccountID: info.AccountID,
 				Region:    info.Region,
 				IDs:       []string{},
 			}
-
 		}
-		req := tcloudReqMap[key]
-		req.IDs = append(req.IDs, id)
+		opt := reqMap[key]
+		opt.IDs = append(opt.IDs, id)
 	}
 	getNextID := counter.NewNumStringCounter(1, 10)
-	for _, req := range tcloudReqMap {
+	for _, opt := range reqMap {
 		tasks = append(tasks, ts.CustomFlowTask{
 			ActionID:   action.ActIDType(getNextID()),
 			ActionName: enumor.ActionDeleteLoadBalancer,
-			Params: actionlb.DeleteLoadBalancerOption{
-				Vendor:                           enumor.TCloud,
-				TCloudBatchDeleteLoadbalancerReq: cvt.PtrToVal(req),
-			},
-			Retry: tableasync.NewRetryWithPolicy(3, 1000, 5000),
+			Params:     cvt.PtrToVal(opt),
+			Retry:      tableasync.NewRetryWithPolicy(3, 1000, 5000),
 		})
 
 	}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"sort"
+	"testing"
+
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_buildLBDeletionTasks(t *testing.T) {
+	infoMap := map[string]types.CloudResourceBasicInfo{
+		"lb-1": {ID: "lb-1", Vendor: enumor.TCloud, AccountID: "acc-1", Region: "ap-guangzhou"},
+		"lb-2": {ID: "lb-2", Vendor: enumor.TCloud, AccountID: "acc-1", Region: "ap-guangzhou"},
+		"lb-3": {ID: "lb-3", Vendor: enumor.Aws, AccountID: "acc-1", Region: "ap-guangzhou"},
+		"lb-4": {ID: "lb-4", Vendor: enumor.HuaWei, AccountID: "acc-2", Region: "cn-south-1"},
+		"lb-5": {ID: "lb-5", Vendor: enumor.HuaWei, AccountID: "acc-2", Region: "cn-north-4"},
+	}
+
+	tasks := buildLBDeletionTasks(infoMap)
+	assert.Len(t, tasks, 4)
+
+	got := make(map[string][]string)
+	for _, task := range tasks {
+		assert.EqualValues(t, enumor.ActionDeleteLoadBalancer, task.ActionName)
+		opt, ok := task.Params.(actionlb.DeleteLoadBalancerOption)
+		if !assert.True(t, ok) {
+			continue
+		}
+		ids := append([]string(nil), opt.IDs...)
+		sort.Strings(ids)
+		got[string(opt.Vendor)+"/"+opt.AccountID+"/"+opt.Region] = ids
+	}
+
+	assert.Equal(t, map[string][]string{
+		"tcloud/acc-1/ap-guangzhou": {"lb-1", "lb-2"},
+		"aws/acc-1/ap-guangzhou":    {"lb-3"},
+		"huawei/acc-2/cn-south-1":   {"lb-4"},
+		"huawei/acc-2/cn-north-4":   {"lb-5"},
+	}, got)
+}
@@ -0,0 +1,200 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"encoding/json"
+
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+func (svc *lbSvc) createHuaWeiLB(kt *kit.Kit, rawReq json.RawMessage) (any, error) {
+	req := new(hcproto.HuaWeiLoadBalancerCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.BkBizID = constant.UnassignedBiz
+	return svc.client.HCService().HuaWei.LoadBalancer.Create(kt, req)
+}
+
+// createHuaWeiTargetGroup 华为云后端服务器组必须归属于负载均衡，业务继承自负载均衡
+func (svc *lbSvc) createHuaWeiTargetGroup(kt *kit.Kit, rawReq json.RawMessage, bkBizID int64) (any, error) {
+	req := new(hcproto.HuaWeiTargetGroupCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.checkLBBeforeOperate(kt, bkBizID, req.LbID); err != nil {
+		return nil, err
+	}
+	return svc.client.HCService().HuaWei.LoadBalancer.CreateTargetGroup(kt, req)
+}
+
+func (svc *lbSvc) createHuaWeiListener(kt *kit.Kit, rawReq json.RawMessage, bkBizID int64, lbID string) (any,
+	error) {
+
+	req := new(hcproto.HuaWeiListenerCreateReq)
+	if err := json.Unmarshal(rawReq, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	req.LbID = lbID
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 预检测-负载均衡属于当前业务且没有执行中的任务
+	if err := svc.checkLBBeforeOperate(kt, bkBizID, lbID); err != nil {
+		return nil, err
+	}
+
+	result, err := svc.client.HCService().HuaWei.LoadBalancer.CreateListener(kt, req)
+	if err != nil {
+		logs.Errorf("fail to create huawei listener, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *lbSvc) updateHuaWeiListener(kt *kit.Kit, body json.RawMessage, id string) (any, error) {
+	req := new(hcproto.HuaWeiListenerUpdateReq)
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.client.HCService().HuaWei.LoadBalancer.UpdateListener(kt, id, req); err != nil {
+		logs.Errorf("update huawei listener failed, req: %+v, err: %v, rid: %s", req, err, kt.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// updateHuaWeiTargetGroup 更新后端服务器组名称、负载均衡算法
+func (svc *lbSvc) updateHuaWeiTargetGroup(cts *rest.Contexts, tgID string) (any, error) {
+	req := new(hcproto.HuaWeiTargetGroupUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	// 健康检查走单独的接口
+	req.HealthCheck = nil
+
+	if err := svc.client.HCService().HuaWei.LoadBalancer.UpdateTargetGroup(cts.Kit, tgID, req); err != nil {
+		logs.Errorf("update huawei target group failed, id: %s, err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (svc *lbSvc) updateHuaWeiTargetGroupHealthCheck(cts *rest.Contexts, tgID string) (any, error) {
+	req := new(hcproto.HuaWeiTargetGroupUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if req.HealthCheck == nil {
+		return nil, errf.New(errf.InvalidParameter, "health_check is required")
+	}
+	updateReq := &hcproto.HuaWeiTargetGroupUpdateReq{HealthCheck: req.HealthCheck}
+	if err := updateReq.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 云上更新成功后由 hc-service 同步回db
+	err := svc.client.HCService().HuaWei.LoadBalancer.UpdateTargetGroup(cts.Kit, tgID, updateReq)
+	if err != nil {
+		logs.Errorf("update huawei target group health check failed, id: %s, err: %v, rid: %s", tgID, err,
+			cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// UpdateBizHuaWeiLoadBalancer 业务下更新华为云负载均衡
+func (svc *lbSvc) UpdateBizHuaWeiLoadBalancer(cts *rest.Contexts) (any, error) {
+	lbID := cts.PathParameter("id").String()
+	if len(lbID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(hcproto.HuaWeiLBUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authAndAuditLBUpdate(cts, lbID, enumor.HuaWei, req); err != nil {
+		return nil, err
+	}
+
+	return nil, svc.client.HCService().HuaWei.LoadBalancer.Update(cts.Kit, lbID, req)
+}
+
+// UpdateBizHuaWeiUrlRule 业务下更新华为云转发策略
+func (svc *lbSvc) UpdateBizHuaWeiUrlRule(cts *rest.Contexts) (any, error) {
+	lblID := cts.PathParameter("lbl_id").String()
+	if len(lblID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "listener is required")
+	}
+
+	ruleID := cts.PathParameter("rule_id").String()
+	if len(ruleID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "rule id is required")
+	}
+
+	req := new(hcproto.HuaWeiRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	// 参数校验
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authAndAuditRuleUpdate(cts, lblID, ruleID, enumor.HuaWei, req); err != nil {
+		return nil, err
+	}
+
+	return nil, svc.client.HCService().HuaWei.LoadBalancer.UpdateUrlRule(cts.Kit, ruleID, req)
+}
@@ -73,6 +73,10 @@ func bizService(h *rest.Handler, svc *lbSvc) {
 	// h.Add("BizBatchCreateLB", http.MethodPost, "/load_balancers/create", svc.BizBatchCreateLB)
 	h.Add("UpdateBizTCloudLoadBalancer", http.MethodPatch,
 		"/vendors/tcloud/load_balancers/{id}", svc.UpdateBizTCloudLoadBalancer)
+	h.Add("UpdateBizAwsLoadBalancer", http.MethodPatch,
+		"/vendors/aws/load_balancers/{id}", svc.UpdateBizAwsLoadBalancer)
+	h.Add("UpdateBizHuaWeiLoadBalancer", http.MethodPatch,
+		"/vendors/huawei/load_balancers/{id}", svc.UpdateBizHuaWeiLoadBalancer)
 	h.Add("ListBizLoadBalancer", http.MethodPost, "/load_balancers/list", svc.ListBizLoadBalancer)
 	h.Add("ListLoadBalancerWithDeleteProtection", http.MethodPost,
 		"/load_balancers/with/delete_protection/list", svc.ListBizLoadBalancerWithDeleteProtect)
@@ -160,6 +164,10 @@ func bizURLRuleService(h *rest.Handler, svc *lbSvc) {
 		"/vendors/tcloud/listeners/{lbl_id}/rules/batch", svc.BatchDeleteBizTCloudUrlRule)
 	h.Add("BatchDeleteBizTCloudUrlRuleByDomain", http.MethodDelete,
 		"/vendors/tcloud/listeners/{lbl_id}/rules/by/domains/batch", svc.BatchDeleteBizTCloudUrlRuleByDomain)
+	h.Add("UpdateBizAwsUrlRule", http.MethodPatch,
+		"/vendors/aws/listeners/{lbl_id}/rules/{rule_id}", svc.UpdateBizAwsUrlRule)
+	h.Add("UpdateBizHuaWeiUrlRule", http.MethodPatch,
+		"/vendors/huawei/listeners/{lbl_id}/rules/{rule_id}", svc.UpdateBizHuaWeiUrlRule)
 }
 
 func bizSopService(h *rest.Handler, svc *lbSvc) {
@@ -653,33 +653,7 @@ func (svc *lbSvc) UpdateBizTCloudUrlRule(cts *rest.Contexts) (any, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	lblInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.ListenerCloudResType, lblID)
-	if err != nil {
-		return nil, err
-	}
-
-	// 业务校验、鉴权
-	err = handler.BizOperateAuth(cts,
-		&handler.ValidWithAuthOption{
-			Authorizer: svc.authorizer,
-			ResType:    meta.UrlRuleAuditResType,
-			Action:     meta.Update,
-			BasicInfo:  lblInfo,
-		})
-	if err != nil {
-		return nil, err
-	}
-
-	// 更新审计
-	updateFields, err := converter.StructToMap(req)
-	if err != nil {
-		logs.Errorf("convert rule update request to map failed, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, err
-	}
-	err = svc.audit.ChildResUpdateAudit(cts.Kit, enumor.UrlRuleAuditResType, lblInfo.ID, ruleID, updateFields)
-	if err != nil {
-		logs.Errorf("create update rule audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+	if err := svc.authAndAuditRuleUpdate(cts, lblID, ruleID, enumor.TCloud, req); err != nil {
 		return nil, err
 	}
 
@@ -58,11 +58,25 @@ func (svc *lbSvc) UpdateBizTCloudLoadBalancer(cts *rest.Contexts) (any, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.authAndAuditLBUpdate(cts, lbID, enumor.TCloud, req); err != nil {
+		return nil, err
+	}
+
+	return nil, svc.client.HCService().TCloud.Clb.Update(cts.Kit, lbID, req)
+}
+
+// authAndAuditLBUpdate 校验负载均衡云厂商、业务鉴权并记录更新审计
+func (svc *lbSvc) authAndAuditLBUpdate(cts *rest.Contexts, lbID string, vendor enumor.Vendor, req any) error {
 	baseInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.LoadBalancerCloudResType,
 		lbID)
 	if err != nil {
 		logs.Errorf("get load balancer vendor failed, id: %s, err: %s, rid: %s", lbID, err, cts.Kit.Rid)
-		return nil, err
+		return err
+	}
+
+	if baseInfo.Vendor != vendor {
+		return errf.Newf(errf.InvalidParameter, "load balancer: %s vendor is %s, not %s", lbID, baseInfo.Vendor,
+			vendor)
 	}
 
 	// validate biz and authorize
@@ -72,26 +86,59 @@ func (svc *lbSvc) UpdateBizTCloudLoadBalancer(cts *rest.Contexts) (any, error) {
 		Action:     meta.Update,
 		BasicInfo:  baseInfo})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
 		logs.Errorf("convert request to map failed, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, err
+		return err
 	}
 	if err = svc.audit.ResUpdateAudit(cts.Kit, enumor.LoadBalancerAuditResType, lbID, updateFields); err != nil {
 		logs.Errorf("create update audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, err
+		return err
 	}
-	switch baseInfo.Vendor {
-	case enumor.TCloud:
-		return nil, svc.client.HCService().TCloud.Clb.Update(cts.Kit, lbID, req)
-	default:
-		return nil, fmt.Errorf("vendor: %s not support", baseInfo.Vendor)
+	return nil
+}
+
+// authAndAuditRuleUpdate 校验监听器云厂商、业务鉴权并记录规则更新审计
+func (svc *lbSvc) authAndAuditRuleUpdate(cts *rest.Contexts, lblID, ruleID string, vendor enumor.Vendor,
+	req any) error {
+
+	lblInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.ListenerCloudResType, lblID)
+	if err != nil {
+		return err
 	}
 
+	if lblInfo.Vendor != vendor {
+		return errf.Newf(errf.InvalidParameter, "listener: %s vendor is %s, not %s", lblID, lblInfo.Vendor, vendor)
+	}
+
+	// 业务校验、鉴权
+	err = handler.BizOperateAuth(cts,
+		&handler.ValidWithAuthOption{
+			Authorizer: svc.authorizer,
+			ResType:    meta.UrlRuleAuditResType,
+			Action:     meta.Update,
+			BasicInfo:  lblInfo,
+		})
+	if err != nil {
+		return err
+	}
+
+	// 更新审计
+	updateFields, err := converter.StructToMap(req)
+	if err != nil {
+		logs.Errorf("convert rule update request to map failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return err
+	}
+	err = svc.audit.ChildResUpdateAudit(cts.Kit, enumor.UrlRuleAuditResType, lblInfo.ID, ruleID, updateFields)
+	if err != nil {
+		logs.Errorf("create update rule audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return err
+	}
+	return nil
 }
 
 // UpdateBizTargetGroup update biz target group.
@@ -122,6 +169,11 @@ func (svc *lbSvc) updateTargetGroup(cts *rest.Contexts, authHandler handler.Vali
 	switch baseInfo.Vendor {
 	case enumor.TCloud:
 		return svc.batchUpdateTCloudTargetGroup(cts, id)
+	case enumor.Aws:
+		// aws 目标组创建后名称、协议、端口、VPC均不可修改
+		return nil, errf.New(errf.InvalidParameter, "aws target group only supports health check update")
+	case enumor.HuaWei:
+		return svc.updateHuaWeiTargetGroup(cts, id)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", baseInfo.Vendor)
 	}
@@ -199,6 +251,10 @@ func (svc *lbSvc) updateTargetGroupHealth(cts *rest.Contexts, authHandler handle
 	switch baseInfo.Vendor {
 	case enumor.TCloud:
 		return svc.updateTCloudTargetGroupHealthCheck(cts, tgID)
+	case enumor.Aws:
+		return svc.updateAwsTargetGroupHealthCheck(cts, tgID)
+	case enumor.HuaWei:
+		return svc.updateHuaWeiTargetGroupHealthCheck(cts, tgID)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", baseInfo.Vendor)
 	}
@@ -319,6 +375,10 @@ func (svc *lbSvc) updateListener(cts *rest.Contexts, authHandler handler.ValidWi
 	switch info.Vendor {
 	case enumor.TCloud:
 		return svc.batchUpdateTCloudListener(cts.Kit, req.Data, id)
+	case enumor.Aws:
+		return svc.updateAwsListener(cts.Kit, req.Data, id)
+	case enumor.HuaWei:
+		return svc.updateHuaWeiListener(cts.Kit, req.Data, id)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", info.Vendor)
 	}
@@ -27,7 +27,6 @@ import (
 	"hcm/pkg/api/core"
 	recyclerecord "hcm/pkg/api/core/recycle-record"
 	dataproto "hcm/pkg/api/data-service/cloud"
-	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -36,116 +35,102 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
-	"hcm/pkg/serviced"
 	"hcm/pkg/thirdparty/esb"
 	"hcm/pkg/tools/retry"
 	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/times"
 )
 
-type recycle struct {
+// Recycler 回收到期资源，由周期性任务按定时任务流触发，每次处理一批到期的回收记录
+type Recycler struct {
 	client *client.ClientSet
 	logics *logics.Logics
-	state  serviced.State
 }
 
-// RecycleTiming timing recycle all resource.
-func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, esbClient esb.Client) {
-	r := &recycle{
+// NewRecycler new recycler.
+func NewRecycler(c *client.ClientSet, esbClient esb.Client) *Recycler {
+	return &Recycler{
 		client: c,
-		state:  state,
 		logics: logics.NewLogics(c, esbClient),
 	}
-
-	go r.recycleTiming(enumor.DiskCloudResType, r.recycleDiskWorker, conf)
-	go r.recycleTiming(enumor.CvmCloudResType, r.recycleCvmWorker, conf)
 }
 
-type recycleWorker func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error
-
-func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycleWorker, conf cc.Recycle) {
-	for {
-		kt := core.NewBackendKit()
+// RecycleDisk 回收一批到期的硬盘
+func (r *Recycler) RecycleDisk(kt *kit.Kit) error {
+	return r.recycle(kt, enumor.DiskCloudResType, r.recycleDiskWorker)
+}
 
-		if !r.state.IsMaster() {
-			logs.Infof("recycle %s, but is not master, skip", resType)
-			time.Sleep(time.Minute)
-			continue
-		}
+// RecycleCvm 回收一批到期的主机
+func (r *Recycler) RecycleCvm(kt *kit.Kit) error {
+	return r.recycle(kt, enumor.CvmCloudResType, r.recycleCvmWorker)
+}
 
-		logs.Infof("start recycle %s, rid: %s", resType, kt.Rid)
-		// get need recycled resource records
-		expr, err := tools.And(tools.EqualWithOpExpression(filter.And,
-			map[string]interface{}{"res_type": resType, "status": enumor.WaitingRecycleRecordStatus}),
-			&filter.AtomRule{Field: "recycled_at", Op: filter.LessThanEqual.Factory(),
-				Value: times.ConvStdTimeFormat(time.Now())},
-			// 不处理关联资源回收任务
-			&filter.AtomRule{Field: "recycle_type", Op: filter.NotEqual.Factory(), Value: enumor.RecycleTypeRelated},
-		)
-		if err != nil {
-			time.Sleep(time.Minute)
-			continue
-		}
-		listReq := &core.ListReq{
-			Filter: expr,
-			Page:   core.NewDefaultBasePage(),
-			Fields: []string{"id", "res_id", "bk_biz_id"},
-		}
-		recordRes, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt, listReq)
-		if err != nil {
-			logs.Errorf("list %s resource recycle record failed, err: %v, rid: %s", resType, err, kt.Rid)
-			time.Sleep(time.Minute)
-			continue
-		}
+type recycleWorker func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error
 
-		// sleep for a while if no resource needs recycling
-		if len(recordRes.Details) == 0 {
-			time.Sleep(time.Minute * 10)
-			continue
-		}
+func (r *Recycler) recycle(kt *kit.Kit, resType enumor.CloudResourceType, worker recycleWorker) error {
+	logs.Infof("start recycle %s, rid: %s", resType, kt.Rid)
+	// get need recycled resource records
+	expr, err := tools.And(tools.EqualWithOpExpression(filter.And,
+		map[string]interface{}{"res_type": resType, "status": enumor.WaitingRecycleRecordStatus}),
+		&filter.AtomRule{Field: "recycled_at", Op: filter.LessThanEqual.Factory(),
+			Value: times.ConvStdTimeFormat(time.Now())},
+		// 不处理关联资源回收任务
+		&filter.AtomRule{Field: "recycle_type", Op: filter.NotEqual.Factory(), Value: enumor.RecycleTypeRelated},
+	)
+	if err != nil {
+		return err
+	}
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "res_id", "bk_biz_id"},
+	}
+	recordRes, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt, listReq)
+	if err != nil {
+		logs.Errorf("list %s resource recycle record failed, err: %v, rid: %s", resType, err, kt.Rid)
+		return err
+	}
 
-		// get need recycled resource basic info
-		ids := make([]string, 0, len(recordRes.Details))
-		for _, record := range recordRes.Details {
-			ids = append(ids, record.ResID)
-		}
+	if len(recordRes.Details) == 0 {
+		return nil
+	}
 
-		infoReq := dataproto.ListResourceBasicInfoReq{
-			ResourceType: resType,
-			IDs:          ids,
-			Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
-		}
-		basicInfoMap, err := r.client.DataService().Global.Cloud.ListResBasicInfo(kt, infoReq)
-		if err != nil {
-			if ef := errf.Error(err); ef.Code == errf.RecordNotFound {
-				recordIDs := slice.Map(recordRes.Details, func(r recyclerecord.RecycleRecord) string { return r.ID })
-				logs.Errorf("recycle %s res(ids: %+v) all don't exist, mark all as fail, reason: %v, rid: %s",
-					resType, ids, err, kt.Rid)
-				logicsrecycle.MarkRecordFailed(kt, r.client.DataService(), err, recordIDs)
-				continue
-			}
-			logs.Errorf("get recycle %s resource detail failed, err: %v, ids: %+v, rid: %s", resType, err, ids, kt.Rid)
-			time.Sleep(time.Minute)
-			continue
-		}
+	// get need recycled resource basic info
+	ids := make([]string, 0, len(recordRes.Details))
+	for _, record := range recordRes.Details {
+		ids = append(ids, record.ResID)
+	}
 
-		// recycle resources one by one
-		for _, record := range recordRes.Details {
-			if !r.state.IsMaster() {
-				logs.Infof("recycle %s res(id: %s), but is not master, skip, rid: %s", resType, record.ResID, kt.Rid)
-				time.Sleep(time.Minute)
-				break
-			}
-			r.execWorker(kt, worker, record, basicInfoMap)
+	infoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
+	}
+	basicInfoMap, err := r.client.DataService().Global.Cloud.ListResBasicInfo(kt, infoReq)
+	if err != nil {
+		if ef := errf.Error(err); ef.Code == errf.RecordNotFound {
+			recordIDs := slice.Map(recordRes.Details, func(r recyclerecord.RecycleRecord) string { return r.ID })
+			logs.Errorf("recycle %s res(ids: %+v) all don't exist, mark all as fail, reason: %v, rid: %s",
+				resType, ids, err, kt.Rid)
+			logicsrecycle.MarkRecordFailed(kt, r.client.DataService(), err, recordIDs)
+			return nil
 		}
+		logs.Errorf("get recycle %s resource detail failed, err: %v, ids: %+v, rid: %s", resType, err, ids, kt.Rid)
+		return err
+	}
 
-		logs.Infof("finished recycle %s, count: %d, rid: %s", resType, len(recordRes.Details), kt.Rid)
+	// recycle resources one by one
+	for _, record := range recordRes.Details {
+		r.execWorker(kt, worker, record, basicInfoMap)
 	}
+
+	logs.Infof("finished recycle %s, count: %d, rid: %s", resType, len(recordRes.Details), kt.Rid)
+	return nil
 }
 
 const maxRetryCount = 3
 
-func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclerecord.RecycleRecord,
+func (r *Recycler) execWorker(kt *kit.Kit, worker recycleWorker, record recyclerecord.RecycleRecord,
 	basicInfoMap map[string]types.CloudResourceBasicInfo) {
 
 	basicInfo, exists := basicInfoMap[record.ResID]
@@ -177,7 +162,7 @@ func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclere
 	logicsrecycle.MarkRecordSuccess(kt, r.client.DataService(), []string{record.ID})
 }
 
-func (r *recycle) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+func (r *Recycler) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
 	res, err := r.logics.Disk.DeleteRecycledDisk(kt, map[string]types.CloudResourceBasicInfo{info.ID: *info})
 	if err != nil {
 		logs.Errorf("delete disk failed, err: %v, res: %+v, disk: %s, rid: %s", err, res, info.ID, kt.Rid)
@@ -186,7 +171,7 @@ func (r *recycle) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicI
 	return nil
 }
 
-func (r *recycle) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+func (r *Recycler) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
 	// 实际销毁CVM
 	res, err := r.logics.Cvm.DestroyRecycledCvm(kt, map[string]types.CloudResourceBasicInfo{info.ID: *info}, nil)
 	if err != nil {
@@ -0,0 +1,351 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag 统一资源标签
+package restag
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	proto "hcm/pkg/api/cloud-server/resource-tag"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/resource-tag"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitService initialize the resource tag service.
+func InitService(c *capability.Capability) {
+	svc := &resTagSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("ListResourceTag", http.MethodPost, "/resource_tags/list", svc.ListResourceTag)
+	h.Add("BatchCreateResourceTag", http.MethodPost, "/resource_tags/batch/create", svc.BatchCreateResourceTag)
+	h.Add("BatchUpdateResourceTag", http.MethodPost, "/resource_tags/batch/update", svc.BatchUpdateResourceTag)
+	h.Add("BatchDeleteResourceTag", http.MethodDelete, "/resource_tags/batch", svc.BatchDeleteResourceTag)
+
+	// 业务下的接口
+	h.Add("ListBizResourceTag", http.MethodPost, "/bizs/{bk_biz_id}/resource_tags/list", svc.ListBizResourceTag)
+	h.Add("BatchCreateBizResourceTag", http.MethodPost, "/bizs/{bk_biz_id}/resource_tags/batch/create",
+		svc.BatchCreateBizResourceTag)
+	h.Add("BatchUpdateBizResourceTag", http.MethodPost, "/bizs/{bk_biz_id}/resource_tags/batch/update",
+		svc.BatchUpdateBizResourceTag)
+	h.Add("BatchDeleteBizResourceTag", http.MethodDelete, "/bizs/{bk_biz_id}/resource_tags/batch",
+		svc.BatchDeleteBizResourceTag)
+
+	h.Load(c.WebService)
+}
+
+type resTagSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
+
+// resTagAuthTypes 资源类型对应的鉴权资源类型
+var resTagAuthTypes = map[enumor.CloudResourceType]meta.ResourceType{
+	enumor.CvmCloudResType:           meta.Cvm,
+	enumor.DiskCloudResType:          meta.Disk,
+	enumor.EipCloudResType:           meta.Eip,
+	enumor.VpcCloudResType:           meta.Vpc,
+	enumor.SubnetCloudResType:        meta.Subnet,
+	enumor.SecurityGroupCloudResType: meta.SecurityGroup,
+	enumor.LoadBalancerCloudResType:  meta.LoadBalancer,
+}
+
+// resTagBasicInfoFields 标签写回云上需要的资源基础信息
+var resTagBasicInfoFields = append([]string{"region", "cloud_id"}, types.CommonBasicInfoFields...)
+
+// ListResourceTag list tags of resources.
+func (svc *resTagSvc) ListResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResourceTag(cts, handler.ResOperateAuth)
+}
+
+// ListBizResourceTag list tags of biz resources.
+func (svc *resTagSvc) ListBizResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResourceTag(cts, handler.BizOperateAuth)
+}
+
+func (svc *resTagSvc) listResourceTag(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, err := svc.validateResources(cts, validHandler, req.ResType, req.ResIDs, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return svc.listTags(cts.Kit, req.ResType, req.ResIDs, nil)
+}
+
+// BatchCreateResourceTag batch create resource tags, value of existing tag key is overwritten.
+func (svc *resTagSvc) BatchCreateResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchUpsertResourceTag(cts, handler.ResOperateAuth, false)
+}
+
+// BatchCreateBizResourceTag batch create biz resource tags.
+func (svc *resTagSvc) BatchCreateBizResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchUpsertResourceTag(cts, handler.BizOperateAuth, false)
+}
+
+// BatchUpdateResourceTag batch update value of resource tags, tag keys must already exist on all resources.
+func (svc *resTagSvc) BatchUpdateResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchUpsertResourceTag(cts, handler.ResOperateAuth, true)
+}
+
+// BatchUpdateBizResourceTag batch update value of biz resource tags.
+func (svc *resTagSvc) BatchUpdateBizResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchUpsertResourceTag(cts, handler.BizOperateAuth, true)
+}
+
+func (svc *resTagSvc) batchUpsertResourceTag(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	mustExist bool) (interface{}, error) {
+
+	req := new(proto.BatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.validateResources(cts, validHandler, req.ResType, req.ResIDs, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if mustExist {
+		keys := make([]string, 0, len(req.Tags))
+		for _, tag := range req.Tags {
+			keys = append(keys, tag.Key)
+		}
+		if err = svc.checkTagsExist(cts.Kit, req.ResType, req.ResIDs, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	for group, refs := range groupResources(infos) {
+		hcReq := &hcproto.BatchUpsertReq{
+			AccountID: group.accountID,
+			Region:    group.region,
+			ResType:   req.ResType,
+			Resources: refs,
+			Tags:      req.Tags,
+		}
+
+		switch group.vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.ResourceTag.BatchUpsert(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.ResourceTag.BatchUpsert(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.ResourceTag.BatchUpsert(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.ResourceTag.BatchUpsert(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.ResourceTag.BatchUpsert(cts.Kit, hcReq)
+		default:
+			err = errf.Newf(errf.InvalidParameter, "%s does not support write back of resource tags", group.vendor)
+		}
+		if err != nil {
+			logs.Errorf("batch upsert resource tags failed, err: %v, group: %+v, rid: %s", err, group, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteResourceTag batch delete resource tags.
+func (svc *resTagSvc) BatchDeleteResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteResourceTag(cts, handler.ResOperateAuth)
+}
+
+// BatchDeleteBizResourceTag batch delete biz resource tags.
+func (svc *resTagSvc) BatchDeleteBizResourceTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteResourceTag(cts, handler.BizOperateAuth)
+}
+
+func (svc *resTagSvc) batchDeleteResourceTag(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.validateResources(cts, validHandler, req.ResType, req.ResIDs, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	for group, refs := range groupResources(infos) {
+		hcReq := &hcproto.BatchDeleteReq{
+			AccountID: group.accountID,
+			Region:    group.region,
+			ResType:   req.ResType,
+			Resources: refs,
+			TagKeys:   req.TagKeys,
+		}
+
+		switch group.vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.ResourceTag.BatchDelete(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.ResourceTag.BatchDelete(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.ResourceTag.BatchDelete(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.ResourceTag.BatchDelete(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.ResourceTag.BatchDelete(cts.Kit, hcReq)
+		default:
+			err = errf.Newf(errf.InvalidParameter, "%s does not support write back of resource tags", group.vendor)
+		}
+		if err != nil {
+			logs.Errorf("batch delete resource tags failed, err: %v, group: %+v, rid: %s", err, group, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// validateResources 查询资源基础信息并鉴权
+func (svc *resTagSvc) validateResources(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	resType enumor.CloudResourceType, ids []string, action meta.Action) (map[string]types.CloudResourceBasicInfo,
+	error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       resTagBasicInfoFields,
+	}
+	infos, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, res_type: %s, rid: %s", err, resType, cts.Kit.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, exist := infos[id]; !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: resTagAuthTypes[resType], Action: action, BasicInfos: infos})
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// checkTagsExist 校验所有资源上都已存在指定的标签键
+func (svc *resTagSvc) checkTagsExist(kt *kit.Kit, resType enumor.CloudResourceType, ids, keys []string) error {
+	tags, err := svc.listTags(kt, resType, ids, keys)
+	if err != nil {
+		return err
+	}
+
+	existMap := make(map[string]struct{}, len(tags.Details))
+	for _, tag := range tags.Details {
+		existMap[tag.ResID+"/"+tag.Key] = struct{}{}
+	}
+
+	for _, id := range ids {
+		for _, key := range keys {
+			if _, exist := existMap[id+"/"+key]; !exist {
+				return errf.Newf(errf.InvalidParameter, "tag %s does not exist on %s %s", key, resType, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// listTags 分页查询资源的标签，keys 为空时查询全部标签
+func (svc *resTagSvc) listTags(kt *kit.Kit, resType enumor.CloudResourceType, ids, keys []string) (
+	*dataproto.ResourceTagListResult, error) {
+
+	expr := tools.ExpressionAnd(tools.RuleEqual("res_type", resType), tools.RuleIn("res_id", ids))
+	if len(keys) > 0 {
+		expr.Rules = append(expr.Rules, tools.RuleIn("key", keys))
+	}
+
+	result := &dataproto.ResourceTagListResult{Details: make([]corecloud.ResourceTag, 0)}
+	listReq := &core.ListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	for {
+		resp, err := svc.client.DataService().Global.ResourceTag.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list resource tags failed, err: %v, res_type: %s, rid: %s", err, resType, kt.Rid)
+			return nil, err
+		}
+
+		result.Details = append(result.Details, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	result.Count = uint64(len(result.Details))
+
+	return result, nil
+}
+
+// resGroup 写回云上时按云厂商、账号和地域分组
+type resGroup struct {
+	vendor    enumor.Vendor
+	accountID string
+	region    string
+}
+
+func groupResources(infos map[string]types.CloudResourceBasicInfo) map[resGroup][]hcproto.ResourceRef {
+	groups := make(map[resGroup][]hcproto.ResourceRef)
+	for _, info := range infos {
+		group := resGroup{vendor: info.Vendor, accountID: info.AccountID, region: info.Region}
+		groups[group] = append(groups[group], hcproto.ResourceRef{ID: info.ID, CloudID: info.CloudID})
+	}
+	return groups
+}
@@ -29,7 +29,9 @@ import (
 	"time"
 
 	"hcm/cmd/cloud-server/logics"
+	"hcm/cmd/cloud-server/logics/approval"
 	logicaudit "hcm/cmd/cloud-server/logics/audit"
+	lgcsnapshot "hcm/cmd/cloud-server/logics/disk-snapshot"
 	"hcm/cmd/cloud-server/service/account"
 	"hcm/cmd/cloud-server/service/application"
 	appcvm "hcm/cmd/cloud-server/service/application/handlers/cvm"
@@ -45,6 +47,7 @@ import (
 	cloudselection "hcm/cmd/cloud-server/service/cloud-selection"
 	"hcm/cmd/cloud-server/service/cvm"
 	"hcm/cmd/cloud-server/service/disk"
+	disksnapshot "hcm/cmd/cloud-server/service/disk-snapshot"
 	"hcm/cmd/cloud-server/service/eip"
 	"hcm/cmd/cloud-server/service/firewall"
 	"hcm/cmd/cloud-server/service/image"
@@ -54,6 +57,7 @@ import (
 	"hcm/cmd/cloud-server/service/recycle"
 	"hcm/cmd/cloud-server/service/region"
 	resourcegroup "hcm/cmd/cloud-server/service/resource-group"
+	restag "hcm/cmd/cloud-server/service/resource-tag"
 	routetable "hcm/cmd/cloud-server/service/route-table"
 	securitygroup "hcm/cmd/cloud-server/service/security-group"
 	subaccount "hcm/cmd/cloud-server/service/sub-account"
@@ -61,15 +65,18 @@ import (
 	"hcm/cmd/cloud-server/service/sync"
 	"hcm/cmd/cloud-server/service/sync/lock"
 	"hcm/cmd/cloud-server/service/task"
+	"hcm/cmd/cloud-server/service/timing"
 	"hcm/cmd/cloud-server/service/user"
 	"hcm/cmd/cloud-server/service/vpc"
 	"hcm/cmd/cloud-server/service/zone"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/cryptography"
 	"hcm/pkg/handler"
 	"hcm/pkg/iam/auth"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
 	"hcm/pkg/rest"
@@ -116,17 +123,8 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		return nil, err
 	}
 
-	if cc.CloudServer().CloudResource.Sync.Enable {
-		interval := time.Duration(cc.CloudServer().CloudResource.Sync.SyncIntervalMin) * time.Minute
-		go sync.CloudResourceSync(interval, sd, apiClientSet)
-	}
-
-	if cc.CloudServer().BillConfig.Enable {
-		interval := time.Duration(cc.CloudServer().BillConfig.SyncIntervalMin) * time.Minute
-		go bill.CloudBillConfigCreate(interval, sd, apiClientSet)
-	}
-
-	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, esbClient)
+	registerTimingJobs(apiClientSet, esbClient)
+	go timing.EnsureScheduledFlows(apiClientSet)
 
 	go appcvm.TimingHandleDeliverApplication(svr.client, 2*time.Second)
 
@@ -135,6 +133,57 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 	return svr, nil
 }
 
+// registerTimingJobs 注册由定时任务流触发的周期性任务
+func registerTimingJobs(cli *client.ClientSet, esbClient esb.Client) {
+	recycler := recycle.NewRecycler(cli, esbClient)
+	timing.Register(enumor.RecycleDiskTimingJob, &timing.Job{Cron: "* * * * *", Enable: true,
+		Run: recycler.RecycleDisk})
+	timing.Register(enumor.RecycleCvmTimingJob, &timing.Job{Cron: "* * * * *", Enable: true,
+		Run: recycler.RecycleCvm})
+
+	syncCfg := cc.CloudServer().CloudResource.Sync
+	timing.Register(enumor.CloudResourceSyncTimingJob, &timing.Job{
+		Cron:   syncCfg.SyncCron(),
+		Enable: syncCfg.Enable,
+		Run:    func(kt *kit.Kit) error { return sync.CloudResourceSync(kt, cli) },
+	})
+
+	eventSyncCfg := syncCfg.EventSync
+	eventSyncer := sync.NewEventSyncer(cli, eventSyncCfg.SyncInterval(),
+		time.Duration(eventSyncCfg.DelayMin)*time.Minute)
+	timing.Register(enumor.CloudEventSyncTimingJob, &timing.Job{
+		Cron:   eventSyncCfg.SyncCron(),
+		Enable: eventSyncCfg.Enable,
+		Run:    eventSyncer.Sync,
+	})
+
+	approvalCfg := cc.CloudServer().Approval
+	approvalLgc := approval.NewApproval(cli)
+	timing.Register(enumor.ApprovalEscalateTimingJob, &timing.Job{
+		Cron:   approvalCfg.CheckCron(),
+		Enable: approvalCfg.Engine == enumor.NativeApprovalEngine,
+		Run: func(kt *kit.Kit) error {
+			return approval.EscalateTimeoutTickets(kt, cli, approvalLgc, time.Now())
+		},
+	})
+
+	snapshotLgc := logics.NewLogics(cli, esbClient).DiskSnapshot
+	timing.Register(enumor.DiskSnapshotPolicyTimingJob, &timing.Job{
+		Cron:   "*/5 * * * *",
+		Enable: true,
+		Run: func(kt *kit.Kit) error {
+			return lgcsnapshot.RunDuePolicies(kt, cli, snapshotLgc, time.Now())
+		},
+	})
+
+	billCfg := cc.CloudServer().BillConfig
+	timing.Register(enumor.BillConfigTimingJob, &timing.Job{
+		Cron:   billCfg.SyncCron(),
+		Enable: billCfg.Enable,
+		Run:    func(kt *kit.Kit) error { return bill.CloudBillConfigCreate(kt, cli) },
+	})
+}
+
 func getCloudClientSvr(sd serviced.ServiceDiscover) (*client.ClientSet, esb.Client, *Service, error) {
 	tls := cc.CloudServer().Network.TLS
 	var tlsConfig *ssl.TLSConfig
@@ -209,9 +258,7 @@ func getCloudClientSvr(sd serviced.ServiceDiscover) (*client.ClientSet, esb.Clie
 
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.New(cryptoConfig.Option())
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -294,6 +341,7 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	firewall.InitFirewallService(c)
 	vpc.InitVpcService(c)
 	disk.InitDiskService(c)
+	disksnapshot.InitDiskSnapshotService(c)
 	subnet.InitSubnetService(c)
 	image.InitImageService(c)
 	routetable.InitRouteTableService(c)
@@ -309,6 +357,7 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	application.InitApplicationService(c, bkHcmUrl)
 	audit.InitService(c)
 	assign.InitService(c)
+	restag.InitService(c)
 	recycle.InitService(c)
 	bill.InitBillService(c)
 
@@ -324,6 +373,7 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	bandwidthpackage.InitService(c)
 
 	task.InitService(c)
+	timing.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
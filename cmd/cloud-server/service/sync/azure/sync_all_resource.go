@@ -107,6 +107,10 @@ func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 		return enumor.DiskCloudResType, hitErr
 	}
 
+	if hitErr = SyncDiskSnapshot(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+		return enumor.DiskSnapshotCloudResType, hitErr
+	}
+
 	if hitErr = SyncSG(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
 		return enumor.SecurityGroupCloudResType, hitErr
 	}
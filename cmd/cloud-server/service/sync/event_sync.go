@@ -0,0 +1,265 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"time"
+
+	assignrule "hcm/cmd/cloud-server/logics/assign-rule"
+	"hcm/cmd/cloud-server/service/sync/aws"
+	"hcm/cmd/cloud-server/service/sync/huawei"
+	adhuawei "hcm/pkg/adaptor/huawei"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// eventSyncVendor 支持云审计事件增量同步的云厂商
+type eventSyncVendor struct {
+	vendor      enumor.Vendor
+	listRegions func(kt *kit.Kit, cliSet *client.ClientSet, accountID string) ([]string, error)
+	sync        func(kt *kit.Kit, cliSet *client.ClientSet, req *hcsync.CloudEventSyncReq) (
+		*hcsync.CloudEventSyncResult, error)
+}
+
+var eventSyncVendors = []eventSyncVendor{
+	{
+		vendor: enumor.TCloud,
+		// 腾讯云云审计为全地域查询
+		listRegions: func(kt *kit.Kit, cliSet *client.ClientSet, accountID string) ([]string, error) {
+			return []string{""}, nil
+		},
+		sync: func(kt *kit.Kit, cliSet *client.ClientSet, req *hcsync.CloudEventSyncReq) (
+			*hcsync.CloudEventSyncResult, error) {
+			return cliSet.HCService().TCloud.CloudEvent.Sync(kt, req)
+		},
+	},
+	{
+		vendor: enumor.Aws,
+		listRegions: func(kt *kit.Kit, cliSet *client.ClientSet, accountID string) ([]string, error) {
+			return aws.ListRegion(kt, cliSet.DataService(), accountID)
+		},
+		sync: func(kt *kit.Kit, cliSet *client.ClientSet, req *hcsync.CloudEventSyncReq) (
+			*hcsync.CloudEventSyncResult, error) {
+			return cliSet.HCService().Aws.CloudEvent.Sync(kt, req)
+		},
+	},
+	{
+		vendor: enumor.HuaWei,
+		listRegions: func(kt *kit.Kit, cliSet *client.ClientSet, accountID string) ([]string, error) {
+			return huawei.ListRegionByService(kt, cliSet.DataService(), adhuawei.Ecs)
+		},
+		sync: func(kt *kit.Kit, cliSet *client.ClientSet, req *hcsync.CloudEventSyncReq) (
+			*hcsync.CloudEventSyncResult, error) {
+			return cliSet.HCService().HuaWei.CloudEvent.Sync(kt, req)
+		},
+	},
+}
+
+const (
+	// eventSyncMaxWindow 单次拉取事件的最大时间窗口，落后较多时按该窗口分段拉取
+	eventSyncMaxWindow = time.Hour
+	// eventSyncMinWindow 拉取失败时时间窗口减半重试，直到不小于该窗口
+	eventSyncMinWindow = time.Minute
+	// eventSyncMaxLookback 最多回溯拉取的时长，超过后云上的审计事件已不可查询，跳过的时间段由全量同步兜底
+	eventSyncMaxLookback = 7 * 24 * time.Hour
+	// eventSyncMaxRequestPerRound 每个账号+地域每轮最多拉取的次数，未追上的部分在下一轮继续
+	eventSyncMaxRequestPerRound = 24
+)
+
+// NewEventSyncer 创建云审计事件增量同步器，interval 为两次同步的间隔，账号+地域首次同步时拉取最近一个间隔的事件。
+func NewEventSyncer(cliSet *client.ClientSet, interval, delay time.Duration) *EventSyncer {
+	return &EventSyncer{
+		cliSet:   cliSet,
+		interval: interval,
+		delay:    delay,
+	}
+}
+
+// EventSyncer 拉取云上审计事件，仅对发生变更的资源做增量同步。
+// 每个账号+地域维护一个已同步的时间点，保存在同步游标表中，由定时任务流按cron触发时任一实例都能接着上一轮继续。
+// 时间窗口同步成功后才会推进游标，失败时缩小窗口重试，仍失败的时间窗口在下一轮重新拉取。
+type EventSyncer struct {
+	cliSet   *client.ClientSet
+	interval time.Duration
+	delay    time.Duration
+}
+
+// Sync 执行一轮云审计事件增量同步，并按资源自动分配规则分配新同步的资源
+func (es *EventSyncer) Sync(kt *kit.Kit) error {
+	kt.RequestSource = enumor.AsynchronousTasks
+	if err := es.syncAll(kt); err != nil {
+		return err
+	}
+	assignrule.AutoAssign(kt, es.cliSet.DataService())
+	return nil
+}
+
+func (es *EventSyncer) syncAll(kt *kit.Kit) error {
+	end := time.Now().Add(-es.delay).Truncate(time.Second)
+
+	cursors, err := es.listCursors(kt)
+	if err != nil {
+		return err
+	}
+
+	for _, vendor := range eventSyncVendors {
+		listReq := &protocloud.AccountListReq{
+			Filter: &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor.vendor},
+				&filter.AtomRule{Field: "type", Op: filter.Equal.Factory(), Value: enumor.ResourceAccount}}},
+			Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+		}
+		for start := uint32(0); ; start += uint32(core.DefaultMaxPageLimit) {
+			listReq.Page.Start = start
+			accounts, err := listAccountWithRetry(kt, es.cliSet.DataService(), listReq)
+			if err != nil {
+				logs.Errorf("list %s account failed, err: %v, rid: %s", vendor.vendor, err, kt.Rid)
+				break
+			}
+
+			for _, acc := range accounts {
+				es.syncAccount(kt, vendor, acc.ID, cursors, end)
+			}
+
+			if len(accounts) < int(core.DefaultMaxPageLimit) {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// listCursors 查询全部账号+地域已同步到的时间点
+func (es *EventSyncer) listCursors(kt *kit.Kit) (map[string]time.Time, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("type", enumor.CloudEventSyncCursor),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Ascending},
+	}
+
+	cursors := make(map[string]time.Time)
+	for {
+		result, err := es.cliSet.DataService().Global.SyncCursor.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list cloud event sync cursor failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			cursor, err := time.Parse(constant.TimeStdFormat, one.CursorValue)
+			if err != nil {
+				logs.Errorf("parse cloud event sync cursor failed, err: %v, scope: %s, value: %s, rid: %s", err,
+					one.Scope, one.CursorValue, kt.Rid)
+				continue
+			}
+			cursors[one.Scope] = cursor
+		}
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			return cursors, nil
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+}
+
+func (es *EventSyncer) syncAccount(kt *kit.Kit, vendor eventSyncVendor, accountID string,
+	cursors map[string]time.Time, end time.Time) {
+
+	regions, err := vendor.listRegions(kt, es.cliSet, accountID)
+	if err != nil {
+		logs.Errorf("list %s region failed, err: %v, account: %s, rid: %s", vendor.vendor, err, accountID, kt.Rid)
+		return
+	}
+
+	for _, region := range regions {
+		scope := accountID + "/" + region
+		start, exist := cursors[scope]
+		if !exist {
+			start = end.Add(-es.interval)
+		}
+		if lookback := end.Add(-eventSyncMaxLookback); start.Before(lookback) {
+			logs.Warnf("%s cloud event sync cursor %v is too old, skip to %v, account: %s, region: %s, rid: %s",
+				vendor.vendor, start, lookback, accountID, region, kt.Rid)
+			start = lookback
+		}
+
+		es.syncRegion(kt, vendor, &hcsync.CloudEventSyncReq{AccountID: accountID, Region: region, StartTime: start,
+			EndTime: end})
+	}
+}
+
+// syncRegion 按不超过 eventSyncMaxWindow 的窗口分段拉取 [start, end) 的事件，每段成功后推进游标，
+// 失败时窗口减半重试，窗口已不大于 eventSyncMinWindow 时结束本轮。
+func (es *EventSyncer) syncRegion(kt *kit.Kit, vendor eventSyncVendor, window *hcsync.CloudEventSyncReq) {
+	scope := window.AccountID + "/" + window.Region
+	size := eventSyncMaxWindow
+	start, end := window.StartTime, window.EndTime
+	for i := 0; i < eventSyncMaxRequestPerRound && end.After(start); i++ {
+		req := &hcsync.CloudEventSyncReq{
+			AccountID: window.AccountID,
+			Region:    window.Region,
+			StartTime: start,
+			EndTime:   minTime(start.Add(size), end),
+		}
+		result, err := vendor.sync(kt.NewSubKit(), es.cliSet, req)
+		if err != nil {
+			logs.Errorf("%s sync by cloud event failed, err: %v, scope: %s, window: [%v, %v), rid: %s",
+				vendor.vendor, err, scope, req.StartTime, req.EndTime, kt.Rid)
+			if size <= eventSyncMinWindow {
+				return
+			}
+			size /= 2
+			continue
+		}
+
+		setReq := &dssync.SyncCursorBatchSetReq{Items: []dssync.SyncCursorSetField{{
+			Type:        enumor.CloudEventSyncCursor,
+			Scope:       scope,
+			CursorValue: req.EndTime.Format(constant.TimeStdFormat),
+		}}}
+		if err = es.cliSet.DataService().Global.SyncCursor.BatchSet(kt, setReq); err != nil {
+			logs.Errorf("set cloud event sync cursor failed, err: %v, scope: %s, rid: %s", err, scope, kt.Rid)
+			return
+		}
+		start = req.EndTime
+
+		if result != nil && result.EventCount > 0 {
+			logs.Infof("%s sync by cloud event success, scope: %s, window: [%v, %v), events: %d, synced: %v, "+
+				"rid: %s", vendor.vendor, scope, req.StartTime, req.EndTime, result.EventCount, result.SyncedCount,
+				kt.Rid)
+		}
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
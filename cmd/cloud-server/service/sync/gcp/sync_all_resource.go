@@ -100,6 +100,10 @@ func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 		return enumor.DiskCloudResType, hitErr
 	}
 
+	if hitErr = SyncDiskSnapshot(kt, cliSet, opt.AccountID, regions, sd); hitErr != nil {
+		return enumor.DiskSnapshotCloudResType, hitErr
+	}
+
 	if hitErr = SyncVpc(kt, cliSet, opt.AccountID, sd); hitErr != nil {
 		return enumor.VpcCloudResType, hitErr
 	}
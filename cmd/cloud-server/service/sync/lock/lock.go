@@ -74,7 +74,12 @@ func (mux *EtcdMutex) Close() {
 
 // TryLock try lock key, failed return error.
 func (mux *EtcdMutex) TryLock(key string) (etcd3.LeaseID, error) {
-	grant, err := mux.lease.Grant(mux.ctx, mux.ttl)
+	return mux.TryLockWithTTL(key, mux.ttl)
+}
+
+// TryLockWithTTL try lock key with given ttl seconds, failed return error.
+func (mux *EtcdMutex) TryLockWithTTL(key string, ttl int64) (etcd3.LeaseID, error) {
+	grant, err := mux.lease.Grant(mux.ctx, ttl)
 	if err != nil {
 		return 0, err
 	}
@@ -106,3 +111,8 @@ func (mux *EtcdMutex) UnLock(leaseID etcd3.LeaseID) error {
 func Key(accountID string) string {
 	return fmt.Sprintf("/hcm/lock/%s/sync/%s", cc.CloudServerName, accountID)
 }
+
+// TimingJobKey return timing job lock key.
+func TimingJobKey(job string) string {
+	return fmt.Sprintf("/hcm/lock/%s/timing/%s", cc.CloudServerName, job)
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"hcm/cmd/cloud-server/logics/account"
+	assignrule "hcm/cmd/cloud-server/logics/assign-rule"
 	"hcm/cmd/cloud-server/service/sync/detail"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
@@ -35,42 +36,35 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
-	"hcm/pkg/serviced"
 	"hcm/pkg/tools/retry"
 )
 
-// CloudResourceSync 定时同步云资源
-func CloudResourceSync(intervalMin time.Duration, sd serviced.ServiceDiscover, cliSet *client.ClientSet) {
-	logs.Infof("cloud resource sync enable, syncIntervalMin: %v", intervalMin)
-
-	for {
-		time.Sleep(intervalMin)
-
-		if !sd.IsMaster() {
-			continue
-		}
+// CloudResourceSync 同步所有账号的云资源，由周期性任务按定时任务流触发
+func CloudResourceSync(kt *kit.Kit, cliSet *client.ClientSet) error {
+	start := time.Now()
+	logs.Infof("cloud resource all sync start, time: %v, rid: %s", start, kt.Rid)
+
+	waitGroup := new(sync.WaitGroup)
+	syncers := account.GetAvailableVendorSyncers()
+
+	waitGroup.Add(len(syncers))
+	for _, vendorSyncer := range syncers {
+		go func(vendor account.VendorSyncer) {
+			subKt := kt.NewSubKit()
+			// for retry
+			subKt.RequestSource = enumor.AsynchronousTasks
+			allAccountSync(subKt, cliSet, vendor)
+			waitGroup.Done()
+		}(vendorSyncer)
+	}
 
-		start := time.Now()
-		logs.Infof("cloud resource all sync start, time: %v", start)
-
-		waitGroup := new(sync.WaitGroup)
-		syncers := account.GetAvailableVendorSyncers()
-
-		waitGroup.Add(len(syncers))
-		for _, vendorSyncer := range syncers {
-			go func(vendor account.VendorSyncer) {
-				kt := core.NewBackendKit()
-				// for retry
-				kt.RequestSource = enumor.AsynchronousTasks
-				allAccountSync(kt, cliSet, vendor)
-				waitGroup.Done()
-			}(vendorSyncer)
-		}
+	waitGroup.Wait()
 
-		waitGroup.Wait()
+	// 按资源自动分配规则分配新同步的资源
+	assignrule.AutoAssign(kt.NewSubKit(), cliSet.DataService())
 
-		logs.Infof("cloud resource all sync end, time: %v", start)
-	}
+	logs.Infof("cloud resource all sync end, cost: %v, rid: %s", time.Since(start), kt.Rid)
+	return nil
 }
 
 // allAccountSync all account sync.
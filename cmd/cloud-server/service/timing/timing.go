@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package timing 周期性任务。周期性任务注册为 task-server 的定时任务流，由定时任务流按cron触发本服务执行单次任务，
+// 从而复用异步任务框架的触发、重叠执行保护、审计和查看能力。
+package timing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/cmd/cloud-server/service/sync/lock"
+	"hcm/pkg/api/cloud-server/timing"
+	"hcm/pkg/api/core"
+	apits "hcm/pkg/api/task-server"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// Job 周期性任务
+type Job struct {
+	// Cron 标准5段式cron表达式
+	Cron string
+	// Enable 未启用时对应的定时任务流会被禁用
+	Enable bool
+	// Run 单次任务逻辑
+	Run func(kt *kit.Kit) error
+	// Timeout 单次执行超时时间，不设置或超过 MaxJobTimeout 时使用 MaxJobTimeout。超时后取消本次执行，由下一次触发继续处理
+	Timeout time.Duration
+}
+
+// MaxJobTimeout 单次任务最长执行时间，需小于调用方等待响应的超时时间(30分钟)，保证执行结果能返回给触发的异步任务
+const MaxJobTimeout = 25 * time.Minute
+
+// timeout 返回单次执行超时时间
+func (job *Job) timeout() time.Duration {
+	if job.Timeout <= 0 || job.Timeout > MaxJobTimeout {
+		return MaxJobTimeout
+	}
+	return job.Timeout
+}
+
+var jobs = make(map[enumor.TimingJob]*Job)
+
+// Register 注册周期性任务，需要在 EnsureScheduledFlows 之前调用
+func Register(name enumor.TimingJob, job *Job) {
+	jobs[name] = job
+}
+
+// InitService initialize the timing job service.
+func InitService(c *capability.Capability) {
+	svc := &service{client: c.ApiClient}
+
+	h := rest.NewHandler()
+	h.Add("RunTimingJob", http.MethodPost, "/timing_jobs/run", svc.RunJob)
+
+	h.Load(c.WebService)
+}
+
+type service struct {
+	client *client.ClientSet
+}
+
+// RunJob 执行一次周期性任务，仅允许后台调用
+func (svc *service) RunJob(cts *rest.Contexts) (interface{}, error) {
+	req := new(timing.RunJobReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if cts.Kit.User != constant.BackendOperationUserKey {
+		return nil, errf.New(errf.PermissionDenied, "timing job can only be run by backend")
+	}
+
+	job, exist := jobs[req.Job]
+	if !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "timing job %s is not registered", req.Job)
+	}
+
+	if !job.Enable {
+		logs.Infof("timing job %s is disabled, skip, rid: %s", req.Job, cts.Kit.Rid)
+		return nil, nil
+	}
+
+	// 定时任务流在上一次执行结束前不会再次触发，分布式锁用于保护调用方异常断开后重新触发时，多个实例重叠执行同一任务，
+	// 锁的租约略长于执行超时时间，实例异常退出时锁会自动释放
+	timeout := job.timeout()
+	leaseTTL := int64((timeout + time.Minute).Seconds())
+	leaseID, err := lock.Manager.TryLockWithTTL(lock.TimingJobKey(string(req.Job)), leaseTTL)
+	if err != nil {
+		if err == lock.ErrLockFailed {
+			return nil, errf.Newf(errf.Aborted, "timing job %s is still running", req.Job)
+		}
+		logs.Errorf("lock timing job %s failed, err: %v, rid: %s", req.Job, err, cts.Kit.Rid)
+		return nil, err
+	}
+	defer func() {
+		// 锁已经超时释放时忽略错误
+		if err := lock.Manager.UnLock(leaseID); err != nil && !strings.Contains(err.Error(), "lease not found") {
+			logs.Errorf("unlock timing job %s failed, err: %v, rid: %s", req.Job, err, cts.Kit.Rid)
+		}
+	}()
+
+	// 使用独立的kit按任务超时时间执行，调用方断开时不中断本次执行，避免资源处理到一半被取消
+	kt := core.NewBackendKit()
+	kt.Rid = cts.Kit.Rid
+	ctx, cancel := context.WithTimeout(kt.Ctx, timeout)
+	defer cancel()
+	kt.Ctx = ctx
+
+	return nil, runJob(kt, req.Job, job)
+}
+
+func runJob(kt *kit.Kit, name enumor.TimingJob, job *Job) error {
+	start := time.Now()
+	if err := job.Run(kt); err != nil {
+		logs.Errorf("run timing job %s failed, err: %v, rid: %s", name, err, kt.Rid)
+		return err
+	}
+	logs.Infof("run timing job %s success, cost: %v, rid: %s", name, time.Since(start), kt.Rid)
+
+	return nil
+}
+
+// ensureRetryInterval 同步定时任务流失败时的重试间隔
+const ensureRetryInterval = 30 * time.Second
+
+// EnsureScheduledFlows 将注册的周期性任务同步为 task-server 中的定时任务流，失败时重试直到成功。
+// 定时任务流按名称全局唯一，多个实例同时同步时以先创建的为准。
+func EnsureScheduledFlows(cli *client.ClientSet) {
+	for {
+		kt := core.NewBackendKit()
+		err := ensureScheduledFlows(kt, cli)
+		if err == nil {
+			logs.Infof("ensure timing job scheduled flows success, rid: %s", kt.Rid)
+			return
+		}
+
+		logs.Errorf("ensure timing job scheduled flows failed, err: %v, rid: %s", err, kt.Rid)
+		time.Sleep(ensureRetryInterval)
+	}
+}
+
+func ensureScheduledFlows(kt *kit.Kit, cli *client.ClientSet) error {
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, scheduledFlowName(name))
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("name", names),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := cli.TaskServer().ListScheduledFlow(kt, listReq)
+	if err != nil {
+		return err
+	}
+	existMap := make(map[string]int, len(result.Details))
+	for idx, one := range result.Details {
+		existMap[one.Name] = idx
+	}
+
+	for name, job := range jobs {
+		idx, exist := existMap[scheduledFlowName(name)]
+		if !exist {
+			if !job.Enable {
+				continue
+			}
+
+			if err = createScheduledFlow(kt, cli, name, job); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sf := result.Details[idx]
+		if sf.Cron == job.Cron && sf.Enable != nil && *sf.Enable == job.Enable {
+			continue
+		}
+
+		enable := job.Enable
+		req := &apits.UpdateScheduledFlowReq{Cron: job.Cron, Enable: &enable}
+		if err = cli.TaskServer().UpdateScheduledFlow(kt, sf.ID, req); err != nil {
+			logs.Errorf("update timing job %s scheduled flow failed, err: %v, rid: %s", name, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createScheduledFlow(kt *kit.Kit, cli *client.ClientSet, name enumor.TimingJob, job *Job) error {
+	req := &apits.AddScheduledFlowReq{
+		Name:     scheduledFlowName(name),
+		Cron:     job.Cron,
+		FlowName: enumor.FlowRunTimingJob,
+		Memo:     string(name),
+		Tasks: []apits.TemplateFlowTask{
+			{ActionID: "1", Params: &timing.RunJobReq{Job: name}},
+		},
+		MisfirePolicy: enumor.MisfireSkip,
+	}
+	_, err := cli.TaskServer().CreateScheduledFlow(kt, req)
+	if err != nil && !errf.IsDuplicated(err) {
+		logs.Errorf("create timing job %s scheduled flow failed, err: %v, rid: %s", name, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func scheduledFlowName(name enumor.TimingJob) string {
+	return fmt.Sprintf("%s:%s", cc.CloudServerName, name)
+}
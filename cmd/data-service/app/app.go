@@ -31,8 +31,10 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
 	"hcm/pkg/runtime/ctl"
+	"hcm/pkg/runtime/ctl/cmd"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the data service.
@@ -71,6 +73,11 @@ func (ds *dataService) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.DataServiceName), cc.DataService().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.DataService().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -90,8 +97,22 @@ func (ds *dataService) prepare(opt *options.Option) error {
 
 	ds.sd = sd
 
+	if err := svc.StartAuditSink(sd); err != nil {
+		return err
+	}
+
+	if err := svc.StartAuditArchive(sd); err != nil {
+		return err
+	}
+
+	if err := svc.StartSecretRewrap(sd); err != nil {
+		return err
+	}
+
 	// init hcm control tool
-	if err := ctl.LoadCtl(ctl.WithBasics(sd)...); err != nil {
+	cmds := append(ctl.WithBasics(sd), cmd.WithVerifyAuditChain(svc.VerifyAuditChain),
+		cmd.WithRewrapSecret(svc.RewrapSecret))
+	if err := ctl.LoadCtl(cmds...); err != nil {
 		return fmt.Errorf("load control tool failed, err: %v", err)
 	}
 
@@ -109,6 +130,8 @@ func (ds *dataService) register() error {
 }
 
 func (ds *dataService) finalizer() {
+	defer tracing.Shutdown()
+
 	if err := ds.sd.Deregister(); err != nil {
 		logs.Errorf("process service shutdown, but deregister failed, err: %v", err)
 		return
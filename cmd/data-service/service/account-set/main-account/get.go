@@ -121,6 +121,8 @@ func (svc *service) GetMainAccount(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.Aws:
 		account, err = convertToMainAccountResult[protocore.AwsMainAccountExtension](baseAccount, dbAccount.Extension, svc)
+	case enumor.TCloud:
+		account, err = convertToMainAccountResult[protocore.TCloudMainAccountExtension](baseAccount, dbAccount.Extension, svc)
 	case enumor.Gcp:
 		account, err = convertToMainAccountResult[protocore.GcpMainAccountExtension](baseAccount, dbAccount.Extension, svc)
 	case enumor.HuaWei:
@@ -51,6 +51,8 @@ func (svc *service) CreateRootAccount(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.Aws:
 		result, err = createAccount[dataproto.AwsRootAccountExtensionCreateReq](vendor, svc, cts)
+	case enumor.TCloud:
+		result, err = createAccount[dataproto.TCloudRootAccountExtensionCreateReq](vendor, svc, cts)
 	case enumor.Gcp:
 		result, err = createAccount[dataproto.GcpRootAccountExtensionCreateReq](vendor, svc, cts)
 	case enumor.HuaWei:
@@ -86,7 +88,10 @@ func createAccount[T dataproto.RootAccountExtensionCreateReq, PT dataproto.RootS
 	if req.Extension != nil {
 		p := PT(req.Extension)
 		// 加密密钥
-		p.EncryptSecretKey(svc.cipher)
+		if err := p.EncryptSecretKey(svc.cipher); err != nil {
+			logs.Errorf("encrypt secret key failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
 	}
 
 	accountID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
@@ -109,6 +109,8 @@ func (svc *service) GetRootAccount(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.Aws:
 		account, err = convertToRootAccountResult[protocore.AwsRootAccountExtension](baseAccount, dbAccount.Extension, svc)
+	case enumor.TCloud:
+		account, err = convertToRootAccountResult[protocore.TCloudRootAccountExtension](baseAccount, dbAccount.Extension, svc)
 	case enumor.Gcp:
 		account, err = convertToRootAccountResult[protocore.GcpRootAccountExtension](baseAccount, dbAccount.Extension, svc)
 	case enumor.HuaWei:
@@ -46,6 +46,8 @@ func (svc *service) UpdateRootAccount(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.Aws:
 		return updateRootAccount[dataproto.AwsRootAccountExtensionUpdateReq](accountID, svc, cts)
+	case enumor.TCloud:
+		return updateRootAccount[dataproto.TCloudRootAccountExtensionUpdateReq](accountID, svc, cts)
 	case enumor.Gcp:
 		return updateRootAccount[dataproto.GcpRootAccountExtensionUpdateReq](accountID, svc, cts)
 	case enumor.HuaWei:
@@ -85,7 +87,10 @@ func updateRootAccount[T dataproto.RootAccountExtensionUpdateReq, PT dataproto.R
 	if req.Extension != nil {
 		// 将参数里的SecretKey加密
 		p := PT(req.Extension)
-		p.EncryptSecretKey(svc.cipher)
+		if err := p.EncryptSecretKey(svc.cipher); err != nil {
+			logs.Errorf("encrypt secret key failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
 
 		// 查询账号
 		dbAccount, err := getRootAccountFromTable(accountID, svc, cts)
@@ -0,0 +1,420 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
+	proto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableapplication "hcm/pkg/dal/table/application"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitApprovalService initialize the native approval engine's chain and ticket service.
+func InitApprovalService(cap *capability.Capability) {
+	svc := &approvalSvc{
+		dao: cap.Dao,
+	}
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateApprovalChain", "POST", "/approval_chains/batch/create", svc.BatchCreateApprovalChain)
+	h.Add("BatchUpdateApprovalChain", "PATCH", "/approval_chains/batch/update", svc.BatchUpdateApprovalChain)
+	h.Add("ListApprovalChain", "POST", "/approval_chains/list", svc.ListApprovalChain)
+	h.Add("BatchDeleteApprovalChain", "DELETE", "/approval_chains/batch", svc.BatchDeleteApprovalChain)
+
+	h.Add("CreateApprovalTicket", "POST", "/approval_tickets/create", svc.CreateApprovalTicket)
+	h.Add("UpdateApprovalTicket", "PATCH", "/approval_tickets/update", svc.UpdateApprovalTicket)
+	h.Add("ListApprovalTicket", "POST", "/approval_tickets/list", svc.ListApprovalTicket)
+
+	h.Load(cap.WebService)
+}
+
+type approvalSvc struct {
+	dao dao.Set
+}
+
+// BatchCreateApprovalChain batch create approval chains.
+func (svc *approvalSvc) BatchCreateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tableapplication.ApprovalChainTable, 0, len(req.Chains))
+	for _, one := range req.Chains {
+		stages, err := tabletypes.NewJsonField(one.Stages)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, tableapplication.ApprovalChainTable{
+			ApplicationType: string(one.ApplicationType),
+			BkBizID:         one.BkBizID,
+			Stages:          stages,
+			Memo:            one.Memo,
+			Creator:         cts.Kit.User,
+			Reviser:         cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ApprovalChain().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("create approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// BatchUpdateApprovalChain batch update approval chains.
+func (svc *approvalSvc) BatchUpdateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make(map[string]*tableapplication.ApprovalChainTable, len(req.Chains))
+	for _, one := range req.Chains {
+		model := &tableapplication.ApprovalChainTable{
+			Memo:    one.Memo,
+			Reviser: cts.Kit.User,
+		}
+
+		if len(one.Stages) != 0 {
+			stages, err := tabletypes.NewJsonField(one.Stages)
+			if err != nil {
+				return nil, errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			model.Stages = stages
+		}
+
+		models[one.ID] = model
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for id, model := range models {
+			if err := svc.dao.ApprovalChain().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("update approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListApprovalChain list approval chains.
+func (svc *approvalSvc) ListApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ApprovalChain().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list approval chain failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &proto.ApprovalChainListResult{Count: result.Count}, nil
+	}
+
+	details := make([]approval.Chain, 0, len(result.Details))
+	for _, one := range result.Details {
+		chain := approval.Chain{
+			ID:              one.ID,
+			ApplicationType: enumor.ApplicationType(one.ApplicationType),
+			BkBizID:         one.BkBizID,
+			Memo:            one.Memo,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+
+		if len(one.Stages) != 0 {
+			if err = json.UnmarshalFromString(string(one.Stages), &chain.Stages); err != nil {
+				logs.Errorf("unmarshal approval chain %s stages failed, err: %v, rid: %s", one.ID, err,
+					cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		details = append(details, chain)
+	}
+
+	return &proto.ApprovalChainListResult{Details: details}, nil
+}
+
+// BatchDeleteApprovalChain batch delete approval chains.
+func (svc *approvalSvc) BatchDeleteApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ApprovalChain().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreateApprovalTicket create approval ticket.
+func (svc *approvalSvc) CreateApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTicketCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	stages, err := tabletypes.NewJsonField(req.Stages)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	records, err := newRecordsJsonField(req.Records)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalTicketTable{
+		SN:               req.SN,
+		ApplicationID:    req.ApplicationID,
+		ApplicationType:  string(req.ApplicationType),
+		BkBizID:          req.BkBizID,
+		Applicant:        req.Applicant,
+		Title:            req.Title,
+		Status:           string(req.Status),
+		CurrentStage:     converter.ValToPtr(req.CurrentStage),
+		Stages:           stages,
+		CurrentApprovers: req.CurrentApprovers,
+		Deadline:         converter.ValToPtr(req.Deadline),
+		Records:          records,
+		Creator:          cts.Kit.User,
+		Reviser:          cts.Kit.User,
+	}
+
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ApprovalTicket().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create approval ticket failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id.(string)}, nil
+}
+
+// UpdateApprovalTicket update approval ticket, return RecordNotFound if the ticket has been updated by others.
+func (svc *approvalSvc) UpdateApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTicketUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	stages, err := tabletypes.NewJsonField(req.Stages)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	records, err := newRecordsJsonField(req.Records)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalTicketTable{
+		Status:           string(req.Status),
+		CurrentStage:     converter.ValToPtr(req.CurrentStage),
+		Stages:           stages,
+		CurrentApprovers: req.CurrentApprovers,
+		Deadline:         converter.ValToPtr(req.Deadline),
+		Records:          records,
+		Version:          req.Version + 1,
+		Reviser:          cts.Kit.User,
+	}
+
+	expr := tools.ExpressionAnd(tools.RuleEqual("id", req.ID), tools.RuleEqual("version", req.Version))
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.ApprovalTicket().UpdateWithTx(cts.Kit, txn, expr, model); err != nil {
+			return nil, err
+		}
+
+		if len(req.ApplicationStatus) == 0 {
+			return nil, nil
+		}
+
+		// 审批单结束时在同一事务中更新申请单状态，避免审批单已结束而申请单仍停留在审批中
+		appExpr := tools.ExpressionAnd(tools.RuleEqual("id", req.ApplicationID),
+			tools.RuleEqual("status", enumor.Pending))
+		appModel := &tableapplication.ApplicationTable{Status: string(req.ApplicationStatus), Reviser: cts.Kit.User}
+		err := svc.dao.Application().UpdateWithTx(cts.Kit, txn, appExpr, appModel)
+		if err != nil && errf.Error(err).Code == errf.RecordNotFound {
+			return nil, errf.Newf(errf.InvalidParameter, "application %s is not pending", req.ApplicationID)
+		}
+		return nil, err
+	})
+	if err != nil {
+		logs.Errorf("update approval ticket %s failed, err: %v, rid: %s", req.ID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListApprovalTicket list approval tickets.
+func (svc *approvalSvc) ListApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ApprovalTicket().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list approval ticket failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list approval ticket failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &proto.ApprovalTicketListResult{Count: result.Count}, nil
+	}
+
+	details := make([]approval.Ticket, 0, len(result.Details))
+	for _, one := range result.Details {
+		ticket, err := convApprovalTicket(&one)
+		if err != nil {
+			logs.Errorf("convert approval ticket %s failed, err: %v, rid: %s", one.ID, err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *ticket)
+	}
+
+	return &proto.ApprovalTicketListResult{Details: details}, nil
+}
+
+func convApprovalTicket(one *tableapplication.ApprovalTicketTable) (*approval.Ticket, error) {
+	ticket := &approval.Ticket{
+		ID:               one.ID,
+		SN:               one.SN,
+		ApplicationID:    one.ApplicationID,
+		ApplicationType:  enumor.ApplicationType(one.ApplicationType),
+		BkBizID:          one.BkBizID,
+		Applicant:        one.Applicant,
+		Title:            one.Title,
+		Status:           enumor.ApplicationStatus(one.Status),
+		CurrentStage:     converter.PtrToVal(one.CurrentStage),
+		CurrentApprovers: one.CurrentApprovers,
+		Deadline:         converter.PtrToVal(one.Deadline),
+		Version:          one.Version,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+
+	if len(one.Stages) != 0 {
+		if err := json.UnmarshalFromString(string(one.Stages), &ticket.Stages); err != nil {
+			return nil, fmt.Errorf("unmarshal stages failed, err: %v", err)
+		}
+	}
+
+	if len(one.Records) != 0 {
+		if err := json.UnmarshalFromString(string(one.Records), &ticket.Records); err != nil {
+			return nil, fmt.Errorf("unmarshal records failed, err: %v", err)
+		}
+	}
+
+	return ticket, nil
+}
+
+func newRecordsJsonField(records []approval.Record) (tabletypes.JsonField, error) {
+	if records == nil {
+		records = make([]approval.Record, 0)
+	}
+	return tabletypes.NewJsonField(records)
+}
@@ -0,0 +1,299 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package archive 审计记录归档，将超过保留天数的审计记录归档到对象存储，并支持从归档中查询审计记录
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+	daoaudit "hcm/pkg/dal/dao/audit"
+	"hcm/pkg/dal/objectstore"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+)
+
+// maxLineSize 归档文件中单条审计记录的最大长度
+const maxLineSize = 16 * 1024 * 1024
+
+// Archiver 在主节点上定期将超过保留天数的审计记录归档到对象存储，归档文件按审计记录所属的天和资源类型分区，
+// 文件上传成功后才在同一事务中记录归档清单并删除审计记录，上传失败时记录保留在数据库中等待下次归档
+type Archiver struct {
+	opt   cc.AuditArchive
+	dao   dao.Set
+	store objectstore.Storage
+	state serviced.State
+}
+
+// NewArchiver new audit archiver.
+func NewArchiver(opt cc.AuditArchive, daoSet dao.Set, store objectstore.Storage, state serviced.State) *Archiver {
+	return &Archiver{opt: opt, dao: daoSet, store: store, state: state}
+}
+
+// Run 定期执行归档，ctx 取消后退出
+func (a *Archiver) Run(ctx context.Context) {
+	interval := time.Duration(a.opt.IntervalMin) * time.Minute
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if !a.state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := a.Archive(kt); err != nil {
+			logs.Errorf("archive audit failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+// Archive 按资源类型的保留天数归档所有过期的审计记录
+func (a *Archiver) Archive(kt *kit.Kit) error {
+	for _, rule := range a.rules() {
+		for {
+			list, err := a.dao.AuditArchive().ListExpired(kt, rule)
+			if err != nil {
+				return err
+			}
+
+			if len(list) == 0 {
+				break
+			}
+
+			if err = a.archiveBatch(kt, list); err != nil {
+				return err
+			}
+
+			if uint(len(list)) < rule.Limit {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// rules 每个单独配置了保留天数的资源类型一条规则，其余资源类型使用默认保留天数
+func (a *Archiver) rules() []*daoaudit.ListExpiredOption {
+	rules := make([]*daoaudit.ListExpiredOption, 0)
+	configured := make([]enumor.AuditResourceType, 0, len(a.opt.RetentionDays))
+	for resType, days := range a.opt.RetentionDays {
+		configured = append(configured, resType)
+		if days == 0 {
+			continue
+		}
+
+		rules = append(rules, &daoaudit.ListExpiredOption{
+			ResTypes:      []enumor.AuditResourceType{resType},
+			RetentionDays: days,
+			Limit:         a.opt.BatchSize,
+		})
+	}
+
+	if a.opt.DefaultRetentionDays > 0 {
+		rules = append(rules, &daoaudit.ListExpiredOption{
+			ExcludeResTypes: configured,
+			RetentionDays:   a.opt.DefaultRetentionDays,
+			Limit:           a.opt.BatchSize,
+		})
+	}
+
+	return rules
+}
+
+// partition 一个归档文件中的审计记录
+type partition struct {
+	key     string
+	resType enumor.AuditResourceType
+	records []audit.AuditTable
+}
+
+// archiveBatch 将一批审计记录按分区和资源类型写入归档文件
+func (a *Archiver) archiveBatch(kt *kit.Kit, list []audit.AuditTable) error {
+	partitions := make([]*partition, 0)
+	index := make(map[string]*partition)
+	for _, one := range list {
+		key := PartitionKey(&one)
+		id := key + "/" + string(one.ResType)
+		if _, exist := index[id]; !exist {
+			index[id] = &partition{key: key, resType: one.ResType}
+			partitions = append(partitions, index[id])
+		}
+		index[id].records = append(index[id].records, one)
+	}
+
+	for _, one := range partitions {
+		if err := a.archivePartition(kt, one); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Archiver) archivePartition(kt *kit.Kit, p *partition) error {
+	buf := new(bytes.Buffer)
+	writer := gzip.NewWriter(buf)
+	encoder := json.NewEncoder(writer)
+
+	archive := &audit.AuditArchiveTable{
+		ResType:      p.resType,
+		PartitionKey: p.key,
+		StartID:      p.records[0].ID,
+		EndID:        p.records[len(p.records)-1].ID,
+		RecordCount:  uint64(len(p.records)),
+	}
+	ids := make([]uint64, 0, len(p.records))
+	for i, one := range p.records {
+		if err := encoder.Encode(one); err != nil {
+			return fmt.Errorf("encode audit %d failed, err: %v", one.ID, err)
+		}
+		ids = append(ids, one.ID)
+
+		createdAt, err := time.Parse(constant.TimeStdFormat, string(one.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("parse audit %d created_at failed, err: %v", one.ID, err)
+		}
+		if i == 0 || createdAt.Before(archive.StartAt) {
+			archive.StartAt = createdAt
+		}
+		if i == 0 || createdAt.After(archive.EndAt) {
+			archive.EndAt = createdAt
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("compress audit archive failed, err: %v", err)
+	}
+
+	// 文件名由记录id范围确定，归档清单写入失败后重试时覆盖同一个文件
+	archive.Path = fmt.Sprintf("%s/%s/%s/%d-%d.jsonl.gz", a.opt.Prefix, p.key, p.resType, archive.StartID,
+		archive.EndID)
+	if err := a.store.Upload(kt, archive.Path, buf); err != nil {
+		logs.Errorf("upload audit archive %s failed, err: %v, rid: %s", archive.Path, err, kt.Rid)
+		return err
+	}
+
+	if err := a.dao.AuditArchive().CreateArchive(kt, archive, ids); err != nil {
+		return err
+	}
+
+	logs.Infof("archive %d %s audits of %s to %s success, rid: %s", len(ids), p.resType, p.key, archive.Path,
+		kt.Rid)
+	return nil
+}
+
+// PartitionKey 审计记录的归档分区，哈希链上的记录使用所属的链，存量记录使用创建日期
+func PartitionKey(one *audit.AuditTable) string {
+	if len(one.ChainKey) != 0 {
+		return one.ChainKey
+	}
+
+	if len(one.CreatedAt) >= len(constant.DateLayout) {
+		return string(one.CreatedAt)[:len(constant.DateLayout)]
+	}
+
+	return string(one.CreatedAt)
+}
+
+// Load 下载并解析归档文件中的审计记录
+func Load(kt *kit.Kit, store objectstore.Storage, archive *audit.AuditArchiveTable) ([]audit.AuditTable, error) {
+	buf := new(bytes.Buffer)
+	if err := store.Download(kt, archive.Path, buf); err != nil {
+		logs.Errorf("download audit archive %s failed, err: %v, rid: %s", archive.Path, err, kt.Rid)
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(buf)
+	if err != nil {
+		return nil, fmt.Errorf("decompress audit archive %s failed, err: %v", archive.Path, err)
+	}
+	defer reader.Close()
+
+	list := make([]audit.AuditTable, 0, archive.RecordCount)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		one := audit.AuditTable{}
+		if err = json.Unmarshal(scanner.Bytes(), &one); err != nil {
+			return nil, fmt.Errorf("decode audit archive %s failed, err: %v", archive.Path, err)
+		}
+		list = append(list, one)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit archive %s failed, err: %v", archive.Path, err)
+	}
+
+	return list, nil
+}
+
+// ListChainRecords 查询哈希链上已归档的记录，按序号排序
+func ListChainRecords(kt *kit.Kit, daoSet dao.Set, store objectstore.Storage, chainKey string) (
+	[]audit.ChainRecord, error) {
+
+	archives, err := daoSet.AuditArchive().ListArchive(kt, &daoaudit.ListArchiveOption{PartitionKey: chainKey})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]audit.ChainRecord, 0)
+	for i := range archives {
+		list, err := Load(kt, store, &archives[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range list {
+			if list[j].ChainKey != chainKey {
+				continue
+			}
+
+			record, err := audit.NewChainRecord(&list[j])
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *record)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ChainSeq < records[j].ChainSeq
+	})
+
+	return records, nil
+}
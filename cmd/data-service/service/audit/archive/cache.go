@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package archive
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/objectstore"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+// Reader 查询归档中的审计记录，归档文件写入后不再修改，解析后的记录按LRU缓存，
+// 分页查询时不需要每页都重新下载和解析归档文件
+type Reader struct {
+	dao   dao.Set
+	store objectstore.Storage
+	cache *recordCache
+}
+
+// NewReader new archived audit reader, cacheRecords 为最多缓存的审计记录数，为0时不缓存
+func NewReader(daoSet dao.Set, store objectstore.Storage, cacheRecords uint) *Reader {
+	return &Reader{dao: daoSet, store: store, cache: newRecordCache(cacheRecords)}
+}
+
+// load 读取归档文件中的审计记录，优先从缓存中读取，返回的记录为缓存共享的数据，调用方不能修改
+func (r *Reader) load(kt *kit.Kit, archive *audit.AuditArchiveTable) ([]audit.AuditTable, error) {
+	key := fmt.Sprintf("%d/%s", archive.ID, archive.Path)
+	if list, ok := r.cache.get(key); ok {
+		return list, nil
+	}
+
+	list, err := Load(kt, r.store, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.add(key, list)
+	return list, nil
+}
+
+// recordCache 按归档文件缓存解析后的审计记录，总记录数超过容量时淘汰最久未使用的归档文件
+type recordCache struct {
+	lock     sync.Mutex
+	capacity uint
+	size     uint
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key  string
+	list []audit.AuditTable
+}
+
+func newRecordCache(capacity uint) *recordCache {
+	return &recordCache{capacity: capacity, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *recordCache) get(key string) ([]audit.AuditTable, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).list, true
+}
+
+func (c *recordCache) add(key string, records []audit.AuditTable) {
+	count := uint(len(records))
+	if count > c.capacity {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.items[key]; ok {
+		return
+	}
+
+	for c.size+count > c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, list: records})
+	c.size += count
+}
+
+func (c *recordCache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cacheEntry)
+	delete(c.items, entry.key)
+	c.size -= uint(len(entry.list))
+}
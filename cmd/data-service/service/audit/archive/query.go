@@ -0,0 +1,472 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	daoaudit "hcm/pkg/dal/dao/audit"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+// Query 查询归档中满足过滤条件的审计记录。只有过滤条件限定了创建时间范围时才查询归档，
+// 避免每次查询都扫描全部归档文件，扫描的记录数超过 maxScan 时返回错误，需要缩小时间范围
+func (r *Reader) Query(kt *kit.Kit, expr *filter.Expression, maxScan uint) ([]audit.AuditTable, error) {
+	opt, ok, err := archiveOption(expr)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	archives, err := r.dao.AuditArchive().ListArchive(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var scanned uint64
+	for _, one := range archives {
+		scanned += one.RecordCount
+	}
+	if scanned > uint64(maxScan) {
+		return nil, errf.Newf(errf.InvalidParameter, "too many archived audits(%d) in the time range, max: %d, "+
+			"please narrow the created_at range", scanned, maxScan)
+	}
+
+	matched := make([]audit.AuditTable, 0)
+	for i := range archives {
+		list, err := r.load(kt, &archives[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range list {
+			hit, err := Match(expr, &list[j])
+			if err != nil {
+				return nil, errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			if hit {
+				matched = append(matched, list[j])
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// Get 从归档中查询指定id的审计记录，不存在时返回 nil
+func (r *Reader) Get(kt *kit.Kit, id uint64) (*audit.AuditTable, error) {
+	archives, err := r.dao.AuditArchive().ListArchive(kt, &daoaudit.ListArchiveOption{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range archives {
+		list, err := r.load(kt, &archives[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range list {
+			if list[j].ID == id {
+				one := list[j]
+				return &one, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// archiveOption 从过滤条件中解析创建时间范围，从顶层的 and 规则中解析资源类型，没有创建时间范围时返回 false
+func archiveOption(expr *filter.Expression) (*daoaudit.ListArchiveOption, bool, error) {
+	if expr == nil {
+		return nil, false, nil
+	}
+
+	start, end, err := timeRange(expr)
+	if err != nil {
+		return nil, false, err
+	}
+	if start == nil && end == nil {
+		return nil, false, nil
+	}
+
+	opt := &daoaudit.ListArchiveOption{Start: start, End: end}
+	if expr.Op != filter.And {
+		return opt, true, nil
+	}
+
+	for _, rule := range expr.Rules {
+		atom, ok := asAtomRule(rule)
+		if !ok || atom.Field != "res_type" {
+			continue
+		}
+
+		switch filter.OpType(atom.Op) {
+		case filter.Equal:
+			if value, ok := atom.Value.(string); ok {
+				opt.ResTypes = []enumor.AuditResourceType{enumor.AuditResourceType(value)}
+			}
+		case filter.In:
+			if values, ok := atom.Value.([]interface{}); ok {
+				for _, value := range values {
+					opt.ResTypes = append(opt.ResTypes, enumor.AuditResourceType(fmt.Sprint(value)))
+				}
+			}
+		}
+	}
+
+	return opt, true, nil
+}
+
+// timeRange 递归解析过滤条件限定的创建时间范围，and 取各规则范围的交集，or 取各分支范围的并集，
+// 任一 or 分支不限定某一端时该端不限定，返回 nil 表示不限定
+func timeRange(expr *filter.Expression) (start, end *time.Time, err error) {
+	for i, rule := range expr.Rules {
+		var ruleStart, ruleEnd *time.Time
+		switch r := rule.(type) {
+		case *filter.Expression:
+			if ruleStart, ruleEnd, err = timeRange(r); err != nil {
+				return nil, nil, err
+			}
+		default:
+			atom, ok := asAtomRule(rule)
+			if !ok {
+				continue
+			}
+			if ruleStart, ruleEnd, err = atomTimeRange(atom); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if expr.Op == filter.Or {
+			if i == 0 {
+				start, end = ruleStart, ruleEnd
+				continue
+			}
+			start = unionBound(start, ruleStart, time.Time.Before)
+			end = unionBound(end, ruleEnd, time.Time.After)
+			continue
+		}
+
+		start = intersectBound(start, ruleStart, time.Time.After)
+		end = intersectBound(end, ruleEnd, time.Time.Before)
+	}
+
+	return start, end, nil
+}
+
+// atomTimeRange 解析单个创建时间规则限定的范围
+func atomTimeRange(atom *filter.AtomRule) (start, end *time.Time, err error) {
+	if atom.Field != "created_at" {
+		return nil, nil, nil
+	}
+
+	value, ok := atom.Value.(string)
+	if !ok {
+		return nil, nil, nil
+	}
+	t, err := time.Parse(constant.TimeStdFormat, value)
+	if err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch filter.OpType(atom.Op) {
+	case filter.GreaterThan, filter.GreaterThanEqual:
+		return &t, nil, nil
+	case filter.LessThan, filter.LessThanEqual:
+		return nil, &t, nil
+	case filter.Equal:
+		return &t, &t, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// intersectBound 取两个边界中更严格的一个，tighter(a, b) 为 true 表示 a 比 b 更严格
+func intersectBound(a, b *time.Time, tighter func(time.Time, time.Time) bool) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b == nil || tighter(*a, *b) {
+		return a
+	}
+	return b
+}
+
+// unionBound 取两个边界中更宽松的一个，任一不限定时不限定，looser(a, b) 为 true 表示 a 比 b 更宽松
+func unionBound(a, b *time.Time, looser func(time.Time, time.Time) bool) *time.Time {
+	if a == nil || b == nil {
+		return nil
+	}
+	if looser(*a, *b) {
+		return a
+	}
+	return b
+}
+
+func asAtomRule(rule filter.RuleFactory) (*filter.AtomRule, bool) {
+	switch r := rule.(type) {
+	case *filter.AtomRule:
+		return r, true
+	case filter.AtomRule:
+		return &r, true
+	default:
+		return nil, false
+	}
+}
+
+// Match 在内存中判断审计记录是否满足过滤条件，不支持的操作符返回错误
+func Match(expr *filter.Expression, one *audit.AuditTable) (bool, error) {
+	record, err := toMap(one)
+	if err != nil {
+		return false, err
+	}
+
+	return matchExpr(expr, record)
+}
+
+func toMap(one *audit.AuditTable) (map[string]interface{}, error) {
+	raw, err := json.Marshal(one)
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]interface{})
+	if err = json.Unmarshal(raw, &record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func matchExpr(expr *filter.Expression, record map[string]interface{}) (bool, error) {
+	if expr == nil || len(expr.Rules) == 0 {
+		return true, nil
+	}
+
+	for _, rule := range expr.Rules {
+		var hit bool
+		var err error
+		switch r := rule.(type) {
+		case *filter.Expression:
+			hit, err = matchExpr(r, record)
+		default:
+			atom, ok := asAtomRule(rule)
+			if !ok {
+				return false, fmt.Errorf("unsupported rule type: %T", rule)
+			}
+			hit, err = matchAtom(atom, record)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if expr.Op == filter.Or && hit {
+			return true, nil
+		}
+		if expr.Op == filter.And && !hit {
+			return false, nil
+		}
+	}
+
+	return expr.Op == filter.And, nil
+}
+
+func matchAtom(atom *filter.AtomRule, record map[string]interface{}) (bool, error) {
+	value := fieldValue(record, atom.Field)
+
+	switch filter.OpType(atom.Op) {
+	case filter.Equal, filter.JSONEqual:
+		return compare(atom.Field, value, atom.Value) == 0, nil
+	case filter.NotEqual, filter.JSONNotEqual:
+		return compare(atom.Field, value, atom.Value) != 0, nil
+	case filter.GreaterThan:
+		return compare(atom.Field, value, atom.Value) > 0, nil
+	case filter.GreaterThanEqual:
+		return compare(atom.Field, value, atom.Value) >= 0, nil
+	case filter.LessThan:
+		return compare(atom.Field, value, atom.Value) < 0, nil
+	case filter.LessThanEqual:
+		return compare(atom.Field, value, atom.Value) <= 0, nil
+	case filter.In, filter.JSONIn:
+		return in(atom.Field, value, atom.Value), nil
+	case filter.NotIn:
+		return !in(atom.Field, value, atom.Value), nil
+	case filter.ContainsSensitive:
+		return strings.Contains(fmt.Sprint(value), fmt.Sprint(atom.Value)), nil
+	case filter.ContainsInsensitive:
+		return strings.Contains(strings.ToLower(fmt.Sprint(value)), strings.ToLower(fmt.Sprint(atom.Value))), nil
+	default:
+		return false, fmt.Errorf("operator %s is not supported when query archived audits", atom.Op)
+	}
+}
+
+// fieldValue 获取记录的字段值，json字段使用 . 分隔的路径，例如 detail.data.res_flow.flow_id
+func fieldValue(record map[string]interface{}, field string) interface{} {
+	var value interface{} = record
+	for _, key := range strings.Split(field, filter.JSONFieldSeparator) {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	return value
+}
+
+func in(field string, value interface{}, target interface{}) bool {
+	values, ok := target.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, one := range values {
+		if compare(field, value, one) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compare 比较字段值和过滤条件的值，数字按数值比较，创建时间按时间比较，其余按字符串比较
+func compare(field string, value interface{}, target interface{}) int {
+	if v, ok := toFloat(value); ok {
+		if t, ok := toFloat(target); ok {
+			switch {
+			case v < t:
+				return -1
+			case v > t:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	v, t := fmt.Sprint(value), fmt.Sprint(target)
+	if field == "created_at" {
+		vt, vErr := time.Parse(constant.TimeStdFormat, v)
+		tt, tErr := time.Parse(constant.TimeStdFormat, t)
+		if vErr == nil && tErr == nil {
+			return vt.Compare(tt)
+		}
+	}
+
+	return strings.Compare(v, t)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Merge 合并数据库和归档中的审计记录并排序，返回分页后的结果
+func Merge(records []audit.AuditTable, page *core.BasePage) ([]audit.AuditTable, error) {
+	field := page.Sort
+	if len(field) == 0 {
+		field = "id"
+	}
+
+	values := make([]interface{}, len(records))
+	for i := range records {
+		record, err := toMap(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = fieldValue(record, field)
+	}
+
+	index := make([]int, len(records))
+	for i := range index {
+		index[i] = i
+	}
+	desc := page.Order.Order() == core.Descending
+	sort.SliceStable(index, func(i, j int) bool {
+		result := compare(field, values[index[i]], values[index[j]])
+		if desc {
+			return result > 0
+		}
+		return result < 0
+	})
+
+	result := make([]audit.AuditTable, 0, page.Limit)
+	for i := int(page.Start); i < len(index) && uint(len(result)) < page.Limit; i++ {
+		result = append(result, records[index[i]])
+	}
+
+	return result, nil
+}
+
+// Project 只保留审计记录中指定的字段，fields 为空时返回全部字段
+func Project(records []audit.AuditTable, fields []string) ([]audit.AuditTable, error) {
+	if len(fields) == 0 {
+		return records, nil
+	}
+
+	result := make([]audit.AuditTable, len(records))
+	for i := range records {
+		record, err := toMap(&records[i])
+		if err != nil {
+			return nil, err
+		}
+
+		projected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := record[field]; ok {
+				projected[field] = value
+			}
+		}
+
+		raw, err := json.Marshal(projected)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(raw, &result[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
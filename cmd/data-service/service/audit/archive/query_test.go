@@ -0,0 +1,170 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/runtime/filter"
+)
+
+func TestMatch(t *testing.T) {
+	one := &audit.AuditTable{
+		ID:        10,
+		ResType:   enumor.CvmAuditResType,
+		Action:    enumor.Delete,
+		BkBizID:   100,
+		Operator:  "admin",
+		CreatedAt: "2024-01-02T10:00:00Z",
+	}
+
+	expr := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "created_at", Op: filter.GreaterThanEqual.Factory(), Value: "2024-01-01T00:00:00Z"},
+			&filter.AtomRule{Field: "created_at", Op: filter.LessThan.Factory(), Value: "2024-01-03T00:00:00+08:00"},
+			&filter.AtomRule{Field: "res_type", Op: filter.In.Factory(), Value: []interface{}{"cvm", "disk"}},
+			&filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: 100},
+		},
+	}
+
+	matched, err := Match(expr, one)
+	if err != nil {
+		t.Fatalf("match failed, err: %v", err)
+	}
+	if !matched {
+		t.Errorf("audit should match the filter")
+	}
+
+	opt, ok, err := archiveOption(expr)
+	if err != nil || !ok {
+		t.Fatalf("parse archive option failed, ok: %v, err: %v", ok, err)
+	}
+	if opt.Start == nil || opt.End == nil || len(opt.ResTypes) != 2 {
+		t.Errorf("unexpected archive option: %+v", opt)
+	}
+
+	expr.Rules = append(expr.Rules, &filter.AtomRule{Field: "operator", Op: filter.NotEqual.Factory(), Value: "admin"})
+	if matched, _ = Match(expr, one); matched {
+		t.Errorf("audit should not match the filter")
+	}
+
+	if _, ok, _ = archiveOption(&filter.Expression{Op: filter.And, Rules: expr.Rules[2:]}); ok {
+		t.Errorf("filter without created_at range should not query archive")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	records := []audit.AuditTable{{ID: 3}, {ID: 1}, {ID: 5}, {ID: 2}, {ID: 4}}
+
+	result, err := Merge(records, &core.BasePage{Start: 1, Limit: 2, Order: core.Descending})
+	if err != nil {
+		t.Fatalf("merge failed, err: %v", err)
+	}
+	if len(result) != 2 || result[0].ID != 4 || result[1].ID != 3 {
+		t.Errorf("unexpected merge result: %+v", result)
+	}
+}
+
+func TestArchiveOptionNested(t *testing.T) {
+	expr := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "res_type", Op: filter.Equal.Factory(), Value: "cvm"},
+			&filter.Expression{
+				Op: filter.Or,
+				Rules: []filter.RuleFactory{
+					&filter.Expression{
+						Op: filter.And,
+						Rules: []filter.RuleFactory{
+							&filter.AtomRule{Field: "created_at", Op: filter.GreaterThanEqual.Factory(),
+								Value: "2024-01-02T00:00:00Z"},
+							&filter.AtomRule{Field: "created_at", Op: filter.LessThan.Factory(),
+								Value: "2024-01-03T00:00:00Z"},
+						},
+					},
+					&filter.AtomRule{Field: "created_at", Op: filter.Equal.Factory(), Value: "2024-01-05T00:00:00Z"},
+				},
+			},
+		},
+	}
+
+	opt, ok, err := archiveOption(expr)
+	if err != nil || !ok {
+		t.Fatalf("parse nested archive option failed, ok: %v, err: %v", ok, err)
+	}
+	if opt.Start.Format(time.RFC3339) != "2024-01-02T00:00:00Z" ||
+		opt.End.Format(time.RFC3339) != "2024-01-05T00:00:00Z" {
+		t.Errorf("unexpected time range: %v - %v", opt.Start, opt.End)
+	}
+	if len(opt.ResTypes) != 1 {
+		t.Errorf("unexpected res types: %v", opt.ResTypes)
+	}
+
+	// or 分支中有不限定创建时间的分支时，不能限定时间范围
+	expr.Rules[1].(*filter.Expression).Rules = append(expr.Rules[1].(*filter.Expression).Rules,
+		&filter.AtomRule{Field: "operator", Op: filter.Equal.Factory(), Value: "admin"})
+	if _, ok, _ = archiveOption(expr); ok {
+		t.Errorf("filter with unbounded or branch should not query archive")
+	}
+}
+
+func TestProject(t *testing.T) {
+	records := []audit.AuditTable{{ID: 1, ResID: "res-1", Operator: "admin", CreatedAt: "2024-01-02T10:00:00Z"}}
+
+	result, err := Project(records, []string{"id", "res_id"})
+	if err != nil {
+		t.Fatalf("project failed, err: %v", err)
+	}
+	if result[0].ID != 1 || result[0].ResID != "res-1" || len(result[0].Operator) != 0 ||
+		len(result[0].CreatedAt) != 0 {
+		t.Errorf("unexpected project result: %+v", result[0])
+	}
+	if records[0].Operator != "admin" {
+		t.Errorf("project should not modify the origin records")
+	}
+}
+
+func TestRecordCache(t *testing.T) {
+	cache := newRecordCache(3)
+	cache.add("a", make([]audit.AuditTable, 2))
+	cache.add("b", make([]audit.AuditTable, 1))
+	if _, ok := cache.get("a"); !ok {
+		t.Fatalf("a should be cached")
+	}
+
+	// b 最久未使用，被淘汰
+	cache.add("c", make([]audit.AuditTable, 1))
+	if _, ok := cache.get("b"); ok {
+		t.Errorf("b should be evicted")
+	}
+	if _, ok := cache.get("c"); !ok {
+		t.Errorf("c should be cached")
+	}
+
+	cache.add("d", make([]audit.AuditTable, 4))
+	if _, ok := cache.get("d"); ok {
+		t.Errorf("records more than capacity should not be cached")
+	}
+}
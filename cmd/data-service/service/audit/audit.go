@@ -24,24 +24,32 @@ import (
 	"fmt"
 	"net/http"
 
+	"hcm/cmd/data-service/service/audit/archive"
 	"hcm/cmd/data-service/service/audit/cloud"
 	"hcm/cmd/data-service/service/capability"
 	"hcm/pkg/api/core"
 	coreaudit "hcm/pkg/api/core/audit"
 	proto "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/cc"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/objectstore"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
 )
 
 // InitAuditService initial the Audit service
 func InitAuditService(cap *capability.Capability) {
 	svc := &svc{
-		cloudAudit: cloud.NewCloudAudit(cap.Dao),
-		dao:        cap.Dao,
+		cloudAudit:  cloud.NewCloudAudit(cap.Dao),
+		dao:         cap.Dao,
+		objectStore: cap.ObjectStore,
+		archive:     archive.NewReader(cap.Dao, cap.ObjectStore, cc.DataService().AuditArchive.CacheRecords),
 	}
 
 	h := rest.NewHandler()
@@ -58,14 +66,17 @@ func InitAuditService(cap *capability.Capability) {
 		svc.cloudAudit.CloudResourceRecycleAudit)
 	h.Add("ListAudit", http.MethodPost, "/audits/list", svc.ListAudit)
 	h.Add("GetAudit", http.MethodGet, "/audits/{id}", svc.GetAudit)
+	h.Add("VerifyAuditChain", http.MethodPost, "/audits/chains/verify", svc.VerifyChain)
 
 	h.Load(cap.WebService)
 }
 
 // Audit define audit service.
 type svc struct {
-	cloudAudit *cloud.Audit
-	dao        dao.Set
+	cloudAudit  *cloud.Audit
+	dao         dao.Set
+	objectStore objectstore.Storage
+	archive     *archive.Reader
 }
 
 // ListAudit list audits.
@@ -84,6 +95,16 @@ func (svc *svc) ListAudit(cts *rest.Contexts) (interface{}, error) {
 		Page:   req.Page,
 		Fields: req.Fields,
 	}
+	// 查询的时间范围内有已归档的审计记录时，合并归档中的记录
+	archived, err := svc.archive.Query(cts.Kit, req.Filter, cc.DataService().AuditArchive.MaxScanRecords)
+	if err != nil {
+		logs.Errorf("query archived audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(archived) != 0 {
+		return svc.listWithArchived(cts.Kit, req, archived)
+	}
+
 	result, err := svc.dao.Audit().List(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("list audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
@@ -93,28 +114,94 @@ func (svc *svc) ListAudit(cts *rest.Contexts) (interface{}, error) {
 		return &proto.ListResult{Count: result.Count}, nil
 	}
 
-	details := make([]coreaudit.Audit, 0, len(result.Details))
-	for _, one := range result.Details {
-		details = append(details, coreaudit.Audit{
-			ID:         one.ID,
-			ResID:      one.ResID,
-			CloudResID: one.CloudResID,
-			ResName:    one.ResName,
-			ResType:    one.ResType,
-			Action:     one.Action,
-			BkBizID:    one.BkBizID,
-			Vendor:     one.Vendor,
-			AccountID:  one.AccountID,
-			Operator:   one.Operator,
-			Detail:     one.Detail,
-			Source:     one.Source,
-			Rid:        one.Rid,
-			AppCode:    one.AppCode,
-			CreatedAt:  one.CreatedAt.String(),
-		})
-	}
-
-	return &proto.ListResult{Details: details}, nil
+	return &proto.ListResult{Details: convAudits(result.Details)}, nil
+}
+
+// listWithArchived 合并数据库和归档中的审计记录后分页，数据库中的记录需要查询到分页的结束位置，
+// 归档中的记录由 archive.Reader 缓存，翻页时不需要重新下载和解析归档文件
+func (svc *svc) listWithArchived(kt *kit.Kit, req *core.ListReq, archived []tableaudit.AuditTable) (
+	*proto.ListResult, error) {
+
+	if req.Page.Count {
+		result, err := svc.dao.Audit().List(kt, &types.ListOption{Filter: req.Filter, Page: req.Page})
+		if err != nil {
+			logs.Errorf("count audit failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		return &proto.ListResult{Count: result.Count + uint64(len(archived))}, nil
+	}
+
+	// 合并排序需要排序字段
+	fields := req.Fields
+	if len(fields) != 0 {
+		sortField := req.Page.Sort
+		if len(sortField) == 0 {
+			sortField = "id"
+		}
+		fields = slice.Unique(append([]string{"id", sortField}, req.Fields...))
+	}
+
+	records := archived
+	end := uint(req.Page.Start) + req.Page.Limit
+	for start := uint32(0); uint(start) < end; start += uint32(core.DefaultMaxPageLimit) {
+		opt := &types.ListOption{
+			Filter: req.Filter,
+			Page: &core.BasePage{Start: start, Limit: core.DefaultMaxPageLimit, Sort: req.Page.Sort,
+				Order: req.Page.Order},
+			Fields: fields,
+		}
+		result, err := svc.dao.Audit().List(kt, opt)
+		if err != nil {
+			logs.Errorf("list audit failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		records = append(records, result.Details...)
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			break
+		}
+	}
+
+	merged, err := archive.Merge(records, req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	if merged, err = archive.Project(merged, req.Fields); err != nil {
+		return nil, err
+	}
+
+	return &proto.ListResult{Details: convAudits(merged)}, nil
+}
+
+func convAudits(list []tableaudit.AuditTable) []coreaudit.Audit {
+	details := make([]coreaudit.Audit, 0, len(list))
+	for _, one := range list {
+		details = append(details, convAudit(&one))
+	}
+
+	return details
+}
+
+func convAudit(one *tableaudit.AuditTable) coreaudit.Audit {
+	return coreaudit.Audit{
+		ID:         one.ID,
+		ResID:      one.ResID,
+		CloudResID: one.CloudResID,
+		ResName:    one.ResName,
+		ResType:    one.ResType,
+		Action:     one.Action,
+		BkBizID:    one.BkBizID,
+		Vendor:     one.Vendor,
+		AccountID:  one.AccountID,
+		Operator:   one.Operator,
+		Detail:     one.Detail,
+		Source:     one.Source,
+		Rid:        one.Rid,
+		AppCode:    one.AppCode,
+		CreatedAt:  one.CreatedAt.String(),
+	}
 }
 
 // GetAudit get audits.
@@ -134,27 +221,22 @@ func (svc *svc) GetAudit(cts *rest.Contexts) (interface{}, error) {
 		return nil, fmt.Errorf("list audit failed, err: %v", err)
 	}
 
-	if len(result.Details) == 0 {
+	if len(result.Details) != 0 {
+		audit := convAudit(&result.Details[0])
+		return &audit, nil
+	}
+
+	// 审计记录可能已归档
+	archived, err := svc.archive.Get(cts.Kit, id)
+	if err != nil {
+		logs.Errorf("get archived audit failed, err: %v, id: %d, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if archived == nil {
 		return nil, errf.Newf(errf.RecordNotFound, "audit: %d not found", id)
 	}
 
-	audit := &coreaudit.Audit{
-		ID:         result.Details[0].ID,
-		ResID:      result.Details[0].ResID,
-		CloudResID: result.Details[0].CloudResID,
-		ResName:    result.Details[0].ResName,
-		ResType:    result.Details[0].ResType,
-		Action:     result.Details[0].Action,
-		BkBizID:    result.Details[0].BkBizID,
-		Vendor:     result.Details[0].Vendor,
-		AccountID:  result.Details[0].AccountID,
-		Operator:   result.Details[0].Operator,
-		Source:     result.Details[0].Source,
-		Rid:        result.Details[0].Rid,
-		AppCode:    result.Details[0].AppCode,
-		Detail:     result.Details[0].Detail,
-		CreatedAt:  result.Details[0].CreatedAt.String(),
-	}
-
-	return audit, nil
+	audit := convAudit(archived)
+	return &audit, nil
 }
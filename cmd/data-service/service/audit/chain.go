@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"hcm/cmd/data-service/service/audit/archive"
+	proto "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/objectstore"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// VerifyChain verify audit hash chains.
+func (svc *svc) VerifyChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.VerifyChainReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return VerifyChains(cts.Kit, svc.dao, svc.objectStore, req)
+}
+
+// VerifyChains 校验日期范围内每天各分片的审计哈希链，报告被篡改、删除的审计记录，已归档的记录从对象存储中读取后一起校验
+func VerifyChains(kt *kit.Kit, daoSet dao.Set, store objectstore.Storage, req *proto.VerifyChainReq) (
+	*proto.VerifyChainResult, error) {
+
+	days, err := req.Days()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	loader := func(kt *kit.Kit, chainKey string) ([]tableaudit.ChainRecord, error) {
+		return archive.ListChainRecords(kt, daoSet, store, chainKey)
+	}
+
+	result := &proto.VerifyChainResult{Intact: true}
+	for _, day := range days {
+		chainKeys, err := daoSet.Audit().ListChainKeys(kt, day)
+		if err != nil {
+			logs.Errorf("list audit chains of %s failed, err: %v, rid: %s", day, err, kt.Rid)
+			return nil, err
+		}
+
+		for _, chainKey := range chainKeys {
+			one, err := daoSet.Audit().VerifyChain(kt, chainKey, loader)
+			if err != nil {
+				logs.Errorf("verify audit chain %s failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+				return nil, err
+			}
+
+			if !one.Intact {
+				result.Intact = false
+				logs.Errorf("audit chain %s is not intact, altered: %d, removed: %d, forged checkpoints: %d, "+
+					"checkpoint removed: %v, forged head: %v, broken link: %v, rid: %s", chainKey, len(one.Altered),
+					len(one.Removed), len(one.ForgedCheckpoints), one.CheckpointRemoved, one.ForgedHead,
+					one.BrokenLink, kt.Rid)
+			}
+			result.Chains = append(result.Chains, *one)
+		}
+	}
+
+	return result, nil
+}
@@ -89,6 +89,8 @@ func (ad Audit) buildOperationAuditInfo(kt *kit.Kit, resType enumor.AuditResourc
 		audits, err = ad.eipOperationAuditBuild(kt, operations)
 	case enumor.DiskAuditResType:
 		audits, err = ad.diskOperationAuditBuild(kt, operations)
+	case enumor.DiskSnapshotAuditResType:
+		audits, err = ad.diskSnapshotOperationAuditBuild(kt, operations)
 	case enumor.TargetGroupAuditResType:
 		audits, err = ad.loadBalancer.TargetGroupOperationAuditBuild(kt, operations)
 	default:
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+func (ad Audit) diskSnapshotDeleteAuditBuild(kt *kit.Kit, deletes []protoaudit.CloudResourceDeleteInfo) (
+	[]*tableaudit.AuditTable, error) {
+
+	ids := make([]string, 0, len(deletes))
+	for _, one := range deletes {
+		ids = append(ids, one.ResID)
+	}
+	snapshotMap, err := ad.listDiskSnapshot(kt, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(deletes))
+	for _, one := range deletes {
+		snapshot, exist := snapshotMap[one.ResID]
+		if !exist {
+			continue
+		}
+
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      snapshot.ID,
+			CloudResID: snapshot.CloudID,
+			ResName:    snapshot.Name,
+			ResType:    enumor.DiskSnapshotAuditResType,
+			Action:     enumor.Delete,
+			BkBizID:    snapshot.BkBizID,
+			Vendor:     snapshot.Vendor,
+			AccountID:  snapshot.AccountID,
+			Operator:   kt.User,
+			Source:     kt.GetRequestSource(),
+			Rid:        kt.Rid,
+			AppCode:    kt.AppCode,
+			Detail: &tableaudit.BasicDetail{
+				Data: snapshot,
+			},
+		})
+	}
+
+	return audits, nil
+}
+
+// diskSnapshotOperationAuditBuild 快照仅支持回滚操作，审计中记录被回滚的云盘
+func (ad Audit) diskSnapshotOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResourceOperationInfo) (
+	[]*tableaudit.AuditTable, error) {
+
+	snapshotIDs := make([]string, 0, len(ops))
+	diskIDs := make([]string, 0, len(ops))
+	for _, op := range ops {
+		if op.Action != protoaudit.Rollback {
+			return nil, fmt.Errorf("audit action: %s not support", op.Action)
+		}
+
+		if op.AssociatedResType != enumor.DiskAuditResType {
+			return nil, fmt.Errorf("audit associated resource type: %s not support", op.AssociatedResType)
+		}
+
+		snapshotIDs = append(snapshotIDs, op.ResID)
+		diskIDs = append(diskIDs, op.AssociatedResID)
+	}
+
+	snapshotMap, err := ad.listDiskSnapshot(kt, snapshotIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	diskMap, err := ad.listDisk(kt, diskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(ops))
+	for _, op := range ops {
+		snapshot, exist := snapshotMap[op.ResID]
+		if !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "disk snapshot: %s not found", op.ResID)
+		}
+
+		diskData, exist := diskMap[op.AssociatedResID]
+		if !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "disk: %s not found", op.AssociatedResID)
+		}
+
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      snapshot.ID,
+			CloudResID: snapshot.CloudID,
+			ResName:    snapshot.Name,
+			ResType:    enumor.DiskSnapshotAuditResType,
+			Action:     enumor.Rollback,
+			BkBizID:    snapshot.BkBizID,
+			Vendor:     snapshot.Vendor,
+			AccountID:  snapshot.AccountID,
+			Operator:   kt.User,
+			Source:     kt.GetRequestSource(),
+			Rid:        kt.Rid,
+			AppCode:    kt.AppCode,
+			Detail: &tableaudit.BasicDetail{
+				Data: &tableaudit.AssociatedOperationAudit{
+					AssResType:    enumor.DiskAuditResType,
+					AssResID:      diskData.ID,
+					AssResCloudID: diskData.CloudID,
+					AssResName:    diskData.Name,
+				},
+			},
+		})
+	}
+
+	return audits, nil
+}
+
+func (ad Audit) listDiskSnapshot(kt *kit.Kit, ids []string) (map[string]tablecloud.DiskSnapshotTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	list, err := ad.dao.DiskSnapshot().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	result := make(map[string]tablecloud.DiskSnapshotTable, len(list.Details))
+	for _, one := range list.Details {
+		result[one.ID] = one
+	}
+
+	return result, nil
+}
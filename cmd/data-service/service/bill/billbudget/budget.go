@@ -0,0 +1,202 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billbudget
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateBillBudget create bill budget
+func (svc *service) CreateBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.BudgetCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	budget := tablebill.AccountBillBudget{
+		Name:               req.Name,
+		ScopeType:          req.ScopeType,
+		ScopeValue:         req.ScopeValue,
+		Period:             req.Period,
+		Amount:             &types.Decimal{Decimal: *req.Amount},
+		Currency:           req.Currency,
+		ActualThresholds:   req.ActualThresholds,
+		ForecastThresholds: req.ForecastThresholds,
+		Receivers:          req.Receivers,
+		Memo:               req.Memo,
+		Creator:            cts.Kit.User,
+		Reviser:            cts.Kit.User,
+	}
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AccountBillBudget().CreateWithTx(cts.Kit, txn, []tablebill.AccountBillBudget{budget})
+	})
+	if err != nil {
+		logs.Errorf("create bill budget failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	idList, ok := ids.([]string)
+	if !ok || len(idList) != 1 {
+		return nil, fmt.Errorf("create bill budget but return ids is invalid: %v", ids)
+	}
+
+	return &core.CreateResult{ID: idList[0]}, nil
+}
+
+// UpdateBillBudget update bill budget
+func (svc *service) UpdateBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.BudgetUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	budget := &tablebill.AccountBillBudget{
+		ID:       req.ID,
+		Name:     req.Name,
+		Currency: req.Currency,
+		Memo:     req.Memo,
+		Reviser:  cts.Kit.User,
+	}
+	if req.Amount != nil {
+		budget.Amount = &types.Decimal{Decimal: *req.Amount}
+	}
+	if req.ActualThresholds != nil {
+		budget.ActualThresholds = req.ActualThresholds
+	}
+	if req.ForecastThresholds != nil {
+		budget.ForecastThresholds = req.ForecastThresholds
+	}
+	if req.Receivers != nil {
+		budget.Receivers = req.Receivers
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AccountBillBudget().UpdateByIDWithTx(cts.Kit, txn, req.ID, budget)
+	})
+	if err != nil {
+		logs.Errorf("update bill budget failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillBudget list bill budget
+func (svc *service) ListBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{Filter: req.Filter, Page: req.Page, Fields: req.Fields}
+	data, err := svc.dao.AccountBillBudget().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dsbill.BudgetListResult{Details: slice.Map(data.Details, convBudget), Count: data.Count}, nil
+}
+
+// BatchDeleteBillBudget delete bill budget and its records
+func (svc *service) BatchDeleteBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   &core.BasePage{Limit: core.DefaultMaxPageLimit},
+		Fields: []string{"id"},
+	}
+	listResp, err := svc.dao.AccountBillBudget().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list bill budget to delete failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(listResp.Details, func(one tablebill.AccountBillBudget) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		recordFilter := tools.ContainersExpression("budget_id", ids)
+		if err := svc.dao.AccountBillBudgetRecord().DeleteWithTx(cts.Kit, txn, recordFilter); err != nil {
+			return nil, err
+		}
+		return nil, svc.dao.AccountBillBudget().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete bill budget failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func convBudget(b tablebill.AccountBillBudget) bill.Budget {
+	return bill.Budget{
+		ID:                 b.ID,
+		Name:               b.Name,
+		ScopeType:          b.ScopeType,
+		ScopeValue:         b.ScopeValue,
+		Period:             b.Period,
+		Amount:             decimalPtr(b.Amount),
+		Currency:           b.Currency,
+		ActualThresholds:   b.ActualThresholds,
+		ForecastThresholds: b.ForecastThresholds,
+		Receivers:          b.Receivers,
+		Memo:               b.Memo,
+		Revision: &core.Revision{
+			Creator:   b.Creator,
+			Reviser:   b.Reviser,
+			CreatedAt: b.CreatedAt.String(),
+			UpdatedAt: b.UpdatedAt.String(),
+		},
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billbudget
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// CreateBillBudgetRecord create bill budget record
+func (svc *service) CreateBillBudgetRecord(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.BudgetRecordCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	record := tablebill.AccountBillBudgetRecord{
+		BudgetID:        req.BudgetID,
+		PeriodKey:       req.PeriodKey,
+		BillYear:        req.BillYear,
+		BillMonth:       req.BillMonth,
+		BillDay:         req.BillDay,
+		Currency:        req.Currency,
+		Amount:          &types.Decimal{Decimal: *req.Amount},
+		ActualCost:      &types.Decimal{Decimal: *req.ActualCost},
+		ForecastCost:    &types.Decimal{Decimal: *req.ForecastCost},
+		ActualAlerted:   req.ActualAlerted,
+		ForecastAlerted: req.ForecastAlerted,
+		Creator:         cts.Kit.User,
+		Reviser:         cts.Kit.User,
+	}
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AccountBillBudgetRecord().CreateWithTx(cts.Kit, txn,
+			[]tablebill.AccountBillBudgetRecord{record})
+	})
+	if err != nil {
+		logs.Errorf("create bill budget record failed, err: %v, budget: %s, rid: %s", err, req.BudgetID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	idList, ok := ids.([]string)
+	if !ok || len(idList) != 1 {
+		return nil, fmt.Errorf("create bill budget record but return ids is invalid: %v", ids)
+	}
+
+	return &core.CreateResult{ID: idList[0]}, nil
+}
+
+// UpdateBillBudgetRecord update bill budget record
+func (svc *service) UpdateBillBudgetRecord(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.BudgetRecordUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	record := &tablebill.AccountBillBudgetRecord{
+		ID:              req.ID,
+		ActualAlerted:   req.ActualAlerted,
+		ForecastAlerted: req.ForecastAlerted,
+		Reviser:         cts.Kit.User,
+	}
+	if req.Amount != nil {
+		record.Amount = &types.Decimal{Decimal: *req.Amount}
+	}
+	if req.ActualCost != nil {
+		record.ActualCost = &types.Decimal{Decimal: *req.ActualCost}
+	}
+	if req.ForecastCost != nil {
+		record.ForecastCost = &types.Decimal{Decimal: *req.ForecastCost}
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AccountBillBudgetRecord().UpdateByIDWithTx(cts.Kit, txn, req.ID, record)
+	})
+	if err != nil {
+		logs.Errorf("update bill budget record failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillBudgetRecord list bill budget record
+func (svc *service) ListBillBudgetRecord(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{Filter: req.Filter, Page: req.Page, Fields: req.Fields}
+	data, err := svc.dao.AccountBillBudgetRecord().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dsbill.BudgetRecordListResult{Details: slice.Map(data.Details, convBudgetRecord), Count: data.Count}, nil
+}
+
+func convBudgetRecord(r tablebill.AccountBillBudgetRecord) bill.BudgetRecord {
+	return bill.BudgetRecord{
+		ID:              r.ID,
+		BudgetID:        r.BudgetID,
+		PeriodKey:       r.PeriodKey,
+		BillYear:        r.BillYear,
+		BillMonth:       r.BillMonth,
+		BillDay:         r.BillDay,
+		Currency:        r.Currency,
+		Amount:          decimalPtr(r.Amount),
+		ActualCost:      decimalPtr(r.ActualCost),
+		ForecastCost:    decimalPtr(r.ForecastCost),
+		ActualAlerted:   r.ActualAlerted,
+		ForecastAlerted: r.ForecastAlerted,
+		Revision: &core.Revision{
+			Creator:   r.Creator,
+			Reviser:   r.Reviser,
+			CreatedAt: r.CreatedAt.String(),
+			UpdatedAt: r.UpdatedAt.String(),
+		},
+	}
+}
+
+func decimalPtr(d *types.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+
+	return &d.Decimal
+}
@@ -0,0 +1,185 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billcostanomaly
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// CreateBillCostAnomaly create bill cost anomaly
+func (svc *service) CreateBillCostAnomaly(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.CostAnomalyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	anomaly := tablebill.AccountBillCostAnomaly{
+		RootAccountID: req.RootAccountID,
+		MainAccountID: req.MainAccountID,
+		Vendor:        req.Vendor,
+		ProductID:     req.ProductID,
+		BkBizID:       req.BkBizID,
+		BillYear:      req.BillYear,
+		BillMonth:     req.BillMonth,
+		BillDay:       req.BillDay,
+		Currency:      req.Currency,
+		Cost:          &types.Decimal{Decimal: *req.Cost},
+		Baseline:      &types.Decimal{Decimal: *req.Baseline},
+		Deviation:     &types.Decimal{Decimal: *req.Deviation},
+		Score:         &types.Decimal{Decimal: *req.Score},
+		Severity:      req.Severity,
+		Contributors:  req.Contributors,
+		Notified:      cvt.ValToPtr(req.Notified),
+		Creator:       cts.Kit.User,
+		Reviser:       cts.Kit.User,
+	}
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AccountBillCostAnomaly().CreateWithTx(cts.Kit, txn,
+			[]tablebill.AccountBillCostAnomaly{anomaly})
+	})
+	if err != nil {
+		logs.Errorf("create bill cost anomaly failed, err: %v, main account: %s, rid: %s", err,
+			req.MainAccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	idList, ok := ids.([]string)
+	if !ok || len(idList) != 1 {
+		return nil, fmt.Errorf("create bill cost anomaly but return ids is invalid: %v", ids)
+	}
+
+	return &core.CreateResult{ID: idList[0]}, nil
+}
+
+// UpdateBillCostAnomaly update bill cost anomaly
+func (svc *service) UpdateBillCostAnomaly(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.CostAnomalyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	anomaly := &tablebill.AccountBillCostAnomaly{
+		ID:           req.ID,
+		Currency:     req.Currency,
+		Severity:     req.Severity,
+		Contributors: req.Contributors,
+		Notified:     req.Notified,
+		Reviser:      cts.Kit.User,
+	}
+	if req.Cost != nil {
+		anomaly.Cost = &types.Decimal{Decimal: *req.Cost}
+	}
+	if req.Baseline != nil {
+		anomaly.Baseline = &types.Decimal{Decimal: *req.Baseline}
+	}
+	if req.Deviation != nil {
+		anomaly.Deviation = &types.Decimal{Decimal: *req.Deviation}
+	}
+	if req.Score != nil {
+		anomaly.Score = &types.Decimal{Decimal: *req.Score}
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AccountBillCostAnomaly().UpdateByIDWithTx(cts.Kit, txn, req.ID, anomaly)
+	})
+	if err != nil {
+		logs.Errorf("update bill cost anomaly failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillCostAnomaly list bill cost anomaly
+func (svc *service) ListBillCostAnomaly(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{Filter: req.Filter, Page: req.Page, Fields: req.Fields}
+	data, err := svc.dao.AccountBillCostAnomaly().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dsbill.CostAnomalyListResult{Details: slice.Map(data.Details, convCostAnomaly), Count: data.Count}, nil
+}
+
+func convCostAnomaly(a tablebill.AccountBillCostAnomaly) bill.CostAnomaly {
+	return bill.CostAnomaly{
+		ID:            a.ID,
+		RootAccountID: a.RootAccountID,
+		MainAccountID: a.MainAccountID,
+		Vendor:        a.Vendor,
+		ProductID:     a.ProductID,
+		BkBizID:       a.BkBizID,
+		BillYear:      a.BillYear,
+		BillMonth:     a.BillMonth,
+		BillDay:       a.BillDay,
+		Currency:      a.Currency,
+		Cost:          decimalPtr(a.Cost),
+		Baseline:      decimalPtr(a.Baseline),
+		Deviation:     decimalPtr(a.Deviation),
+		Score:         decimalPtr(a.Score),
+		Severity:      a.Severity,
+		Contributors:  a.Contributors,
+		Notified:      cvt.PtrToVal(a.Notified),
+		Revision: &core.Revision{
+			Creator:   a.Creator,
+			Reviser:   a.Reviser,
+			CreatedAt: a.CreatedAt.String(),
+			UpdatedAt: a.UpdatedAt.String(),
+		},
+	}
+}
+
+func decimalPtr(d *types.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+
+	return &d.Decimal
+}
@@ -58,6 +58,8 @@ func (svc *service) CreateBillItem(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.Aws:
 		return createBillItem[bill.AwsBillItemExtension](cts, svc, vendor)
+	case enumor.TCloud:
+		return createBillItem[bill.TCloudBillItemExtension](cts, svc, vendor)
 	case enumor.HuaWei:
 		return createBillItem[bill.HuaweiBillItemExtension](cts, svc, vendor)
 	case enumor.Azure:
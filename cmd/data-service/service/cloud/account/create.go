@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/dal/dao/orm"
 	tablecloud "hcm/pkg/dal/table/cloud"
 	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/tools/json"
 )
@@ -75,7 +76,10 @@ func createAccount[T protocloud.AccountExtensionCreateReq, PT protocloud.SecretE
 	if req.Extension != nil {
 		p := PT(req.Extension)
 		// 加密密钥
-		p.EncryptSecretKey(svc.cipher)
+		if err := p.EncryptSecretKey(svc.cipher); err != nil {
+			logs.Errorf("encrypt secret key failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
 	}
 
 	accountID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
@@ -106,7 +106,10 @@ func updateAccount[T protocloud.AccountExtensionUpdateReq, PT protocloud.SecretE
 	if req.Extension != nil {
 		// 将参数里的SecretKey加密
 		p := PT(req.Extension)
-		p.EncryptSecretKey(svc.cipher)
+		if err := p.EncryptSecretKey(svc.cipher); err != nil {
+			logs.Errorf("encrypt secret key failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
 
 		// 查询账号
 		dbAccount, err := getAccountFromTable(accountID, svc, cts)
@@ -24,6 +24,7 @@ import (
 
 	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service/cloud/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
@@ -71,6 +72,11 @@ func (dSvc *diskSvc) BatchDeleteDisk(cts *rest.Contexts) (interface{}, error) {
 			return nil, err
 		}
 
+		if err := dSvc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.DiskCloudResType,
+			delIDs); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -0,0 +1,416 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitDiskSnapshotService initialize the disk snapshot and disk snapshot policy service.
+func InitDiskSnapshotService(cap *capability.Capability) {
+	svc := &diskSnapshotSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateDiskSnapshot", "POST", "/disk_snapshots/batch/create", svc.BatchCreateDiskSnapshot)
+	h.Add("BatchUpdateDiskSnapshot", "PATCH", "/disk_snapshots/batch/update", svc.BatchUpdateDiskSnapshot)
+	h.Add("ListDiskSnapshot", "POST", "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", "DELETE", "/disk_snapshots/batch", svc.BatchDeleteDiskSnapshot)
+
+	h.Add("BatchCreateDiskSnapshotPolicy", "POST", "/disk_snapshot_policies/batch/create",
+		svc.BatchCreateDiskSnapshotPolicy)
+	h.Add("BatchUpdateDiskSnapshotPolicy", "PATCH", "/disk_snapshot_policies/batch/update",
+		svc.BatchUpdateDiskSnapshotPolicy)
+	h.Add("ListDiskSnapshotPolicy", "POST", "/disk_snapshot_policies/list", svc.ListDiskSnapshotPolicy)
+	h.Add("BatchDeleteDiskSnapshotPolicy", "DELETE", "/disk_snapshot_policies/batch",
+		svc.BatchDeleteDiskSnapshotPolicy)
+
+	h.Load(cap.WebService)
+}
+
+type diskSnapshotSvc struct {
+	dao dao.Set
+}
+
+// BatchCreateDiskSnapshot batch create disk snapshots.
+func (svc *diskSnapshotSvc) BatchCreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tablecloud.DiskSnapshotTable, 0, len(req.Snapshots))
+	for _, one := range req.Snapshots {
+		extension, err := tabletypes.NewJsonField(converter.PtrToVal(one.Extension))
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, tablecloud.DiskSnapshotTable{
+			Vendor:         one.Vendor,
+			AccountID:      one.AccountID,
+			CloudID:        one.CloudID,
+			Name:           one.Name,
+			BkBizID:        one.BkBizID,
+			Region:         one.Region,
+			Zone:           one.Zone,
+			DiskID:         one.DiskID,
+			CloudDiskID:    one.CloudDiskID,
+			DiskSize:       one.DiskSize,
+			Status:         one.Status,
+			Encrypted:      converter.ValToPtr(one.Encrypted),
+			PolicyID:       one.PolicyID,
+			CloudCreatedAt: one.CloudCreatedAt,
+			Memo:           one.Memo,
+			Extension:      extension,
+			Creator:        cts.Kit.User,
+			Reviser:        cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskSnapshot().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("create disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// BatchUpdateDiskSnapshot batch update disk snapshots.
+func (svc *diskSnapshotSvc) BatchUpdateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make(map[string]*tablecloud.DiskSnapshotTable, len(req.Snapshots))
+	for _, one := range req.Snapshots {
+		model := &tablecloud.DiskSnapshotTable{
+			Name:     one.Name,
+			BkBizID:  one.BkBizID,
+			DiskID:   one.DiskID,
+			DiskSize: one.DiskSize,
+			Status:   one.Status,
+			Memo:     one.Memo,
+			Reviser:  cts.Kit.User,
+		}
+
+		if one.Extension != nil {
+			extension, err := tabletypes.NewJsonField(one.Extension)
+			if err != nil {
+				return nil, errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			model.Extension = extension
+		}
+
+		models[one.ID] = model
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for id, model := range models {
+			if err := svc.dao.DiskSnapshot().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("update disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshot list disk snapshots.
+func (svc *diskSnapshotSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list disk snapshot failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.DiskSnapshotListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.DiskSnapshot, 0, len(result.Details))
+	for _, one := range result.Details {
+		snapshot := corecloud.DiskSnapshot{
+			ID:             one.ID,
+			Vendor:         one.Vendor,
+			AccountID:      one.AccountID,
+			CloudID:        one.CloudID,
+			Name:           one.Name,
+			BkBizID:        one.BkBizID,
+			Region:         one.Region,
+			Zone:           one.Zone,
+			DiskID:         one.DiskID,
+			CloudDiskID:    one.CloudDiskID,
+			DiskSize:       one.DiskSize,
+			Status:         one.Status,
+			Encrypted:      converter.PtrToVal(one.Encrypted),
+			PolicyID:       one.PolicyID,
+			CloudCreatedAt: one.CloudCreatedAt,
+			Memo:           one.Memo,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+
+		if len(one.Extension) != 0 {
+			snapshot.Extension = new(corecloud.DiskSnapshotExtension)
+			if err = json.UnmarshalFromString(string(one.Extension), snapshot.Extension); err != nil {
+				logs.Errorf("unmarshal disk snapshot %s extension failed, err: %v, rid: %s", one.ID, err,
+					cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		details = append(details, snapshot)
+	}
+
+	return &protocloud.DiskSnapshotListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshots.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.DiskSnapshot().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchCreateDiskSnapshotPolicy batch create disk snapshot policies.
+func (svc *diskSnapshotSvc) BatchCreateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotPolicyBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tablecloud.DiskSnapshotPolicyTable, 0, len(req.Policies))
+	for _, one := range req.Policies {
+		models = append(models, tablecloud.DiskSnapshotPolicyTable{
+			Name:           one.Name,
+			BkBizID:        one.BkBizID,
+			ScopeType:      string(one.ScopeType),
+			DiskIDs:        one.DiskIDs,
+			IntervalHours:  one.IntervalHours,
+			RetentionCount: one.RetentionCount,
+			Enabled:        converter.ValToPtr(one.Enabled),
+			Memo:           one.Memo,
+			Creator:        cts.Kit.User,
+			Reviser:        cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskSnapshotPolicy().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("create disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// BatchUpdateDiskSnapshotPolicy batch update disk snapshot policies.
+func (svc *diskSnapshotSvc) BatchUpdateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotPolicyBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make(map[string]*tablecloud.DiskSnapshotPolicyTable, len(req.Policies))
+	for _, one := range req.Policies {
+		models[one.ID] = &tablecloud.DiskSnapshotPolicyTable{
+			Name:           one.Name,
+			DiskIDs:        one.DiskIDs,
+			IntervalHours:  one.IntervalHours,
+			RetentionCount: one.RetentionCount,
+			Enabled:        one.Enabled,
+			LastExecutedAt: one.LastExecutedAt,
+			Memo:           one.Memo,
+			Reviser:        cts.Kit.User,
+		}
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for id, model := range models {
+			if err := svc.dao.DiskSnapshotPolicy().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshotPolicy list disk snapshot policies.
+func (svc *diskSnapshotSvc) ListDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.DiskSnapshotPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list disk snapshot policy failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.DiskSnapshotPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.DiskSnapshotPolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.DiskSnapshotPolicy{
+			ID:             one.ID,
+			Name:           one.Name,
+			BkBizID:        one.BkBizID,
+			ScopeType:      corecloud.DiskSnapshotPolicyScope(one.ScopeType),
+			DiskIDs:        one.DiskIDs,
+			IntervalHours:  one.IntervalHours,
+			RetentionCount: one.RetentionCount,
+			Enabled:        converter.PtrToVal(one.Enabled),
+			LastExecutedAt: one.LastExecutedAt,
+			Memo:           one.Memo,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.DiskSnapshotPolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshotPolicy batch delete disk snapshot policies.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.DiskSnapshotPolicy().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
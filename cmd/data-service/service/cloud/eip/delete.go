@@ -20,9 +20,14 @@
 package eip
 
 import (
+	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 
 	"github.com/jmoiron/sqlx"
@@ -39,11 +44,46 @@ func (svc *eipSvc) BatchDeleteEip(cts *rest.Contexts) (interface{}, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		return nil, svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter)
+	delIDs, err := svc.listEipIDs(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.EipCloudResType, delIDs)
 	})
 	if err != nil {
 		return nil, err
 	}
 	return nil, nil
 }
+
+// listEipIDs list ids of eips to delete, used to clean up their tags.
+func (svc *eipSvc) listEipIDs(kt *kit.Kit, req *dataproto.EipDeleteReq) ([]string, error) {
+	ids := make([]string, 0)
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := svc.dao.Eip().List(kt, opt)
+		if err != nil {
+			logs.Errorf("list eip failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Details {
+			ids = append(ids, one.ID)
+		}
+
+		if uint(len(resp.Details)) < opt.Page.Limit {
+			return ids, nil
+		}
+		opt.Page.Start += uint32(opt.Page.Limit)
+	}
+}
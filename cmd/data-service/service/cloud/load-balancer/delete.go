@@ -88,6 +88,11 @@ func (svc *lbSvc) BatchDeleteLoadBalancer(cts *rest.Contexts) (any, error) {
 			logs.Errorf("delete lb sg rel failed , err: %v, lb_ids: %v, rid: %s", err, lbIds, cts.Kit.Rid)
 			return nil, err
 		}
+		// 删除标签
+		err = svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.LoadBalancerCloudResType, lbIds)
+		if err != nil {
+			return nil, err
+		}
 
 		// 删除负载均衡
 		delFilter := tools.ContainersExpression("id", lbIds)
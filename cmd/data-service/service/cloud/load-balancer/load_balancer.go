@@ -53,7 +53,7 @@ func InitService(cap *capability.Capability) {
 	// 监听器
 	h.Add("GetListener", http.MethodGet, "/vendors/{vendor}/listeners/{id}", svc.GetListener)
 	h.Add("ListListener", http.MethodPost, "/load_balancers/listeners/list", svc.ListListener)
-	h.Add("ListListenerExt", http.MethodPost, "/vendors/tcloud/load_balancers/listeners/list", svc.ListListenerExt)
+	h.Add("ListListenerExt", http.MethodPost, "/vendors/{vendor}/load_balancers/listeners/list", svc.ListListenerExt)
 	h.Add("BatchCreateListener", http.MethodPost, "/vendors/{vendor}/listeners/batch/create", svc.BatchCreateListener)
 	h.Add("BatchCreateListenerWithRule", http.MethodPost, "/vendors/{vendor}/listeners/rules/batch/create",
 		svc.BatchCreateListenerWithRule)
@@ -66,14 +66,14 @@ func InitService(cap *capability.Capability) {
 		svc.ListListenerWithTargets)
 	h.Add("ListBatchListeners", http.MethodPost, "/load_balancers/listeners/batch/list", svc.ListBatchListeners)
 
-	// url规则
+	// url规则，aws、huawei的转发规则同样落在该表中
 	h.Add("BatchCreateTCloudUrlRule",
-		http.MethodPost, "/vendors/tcloud/url_rules/batch/create", svc.BatchCreateTCloudUrlRule)
+		http.MethodPost, "/vendors/{vendor}/url_rules/batch/create", svc.BatchCreateTCloudUrlRule)
 	h.Add("BatchUpdateTCloudUrlRule",
-		http.MethodPatch, "/vendors/tcloud/url_rules/batch/update", svc.BatchUpdateTCloudUrlRule)
+		http.MethodPatch, "/vendors/{vendor}/url_rules/batch/update", svc.BatchUpdateTCloudUrlRule)
 	h.Add("BatchDeleteTCloudUrlRule",
-		http.MethodDelete, "/vendors/tcloud/url_rules/batch", svc.BatchDeleteTCloudUrlRule)
-	h.Add("ListTCloudUrlRule", http.MethodPost, "/vendors/tcloud/load_balancers/url_rules/list", svc.ListTCloudUrlRule)
+		http.MethodDelete, "/vendors/{vendor}/url_rules/batch", svc.BatchDeleteTCloudUrlRule)
+	h.Add("ListTCloudUrlRule", http.MethodPost, "/vendors/{vendor}/load_balancers/url_rules/list", svc.ListTCloudUrlRule)
 
 	// 目标组
 	h.Add("BatchCreateTargetGroup", http.MethodPost,
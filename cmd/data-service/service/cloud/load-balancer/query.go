@@ -185,6 +185,10 @@ func (svc *lbSvc) ListLoadBalancerExt(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return convLbListResult[corelb.TCloudClbExtension](data.Details)
+	case enumor.Aws:
+		return convLbListResult[corelb.AwsLoadBalancerExtension](data.Details)
+	case enumor.HuaWei:
+		return convLbListResult[corelb.HuaWeiLoadBalancerExtension](data.Details)
 	default:
 		return nil, errf.Newf(errf.InvalidParameter, "unsupported vendor: %s", vendor)
 	}
@@ -219,7 +223,11 @@ func (svc *lbSvc) GetLoadBalancer(cts *rest.Contexts) (any, error) {
 	lbTable := result.Details[0]
 	switch lbTable.Vendor {
 	case enumor.TCloud:
-		return convLoadBalancerWithExt(&lbTable)
+		return convLoadBalancerWithExt[corelb.TCloudClbExtension](&lbTable)
+	case enumor.Aws:
+		return convLoadBalancerWithExt[corelb.AwsLoadBalancerExtension](&lbTable)
+	case enumor.HuaWei:
+		return convLoadBalancerWithExt[corelb.HuaWeiLoadBalancerExtension](&lbTable)
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
 	}
@@ -298,6 +306,24 @@ func (svc *lbSvc) ListListener(cts *rest.Contexts) (interface{}, error) {
 
 // ListListenerExt list listener with extension.
 func (svc *lbSvc) ListListenerExt(cts *rest.Contexts) (any, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch vendor {
+	case enumor.TCloud:
+		return listListenerExt[corelb.TCloudListenerExtension](cts, svc)
+	case enumor.Aws:
+		return listListenerExt[corelb.AwsListenerExtension](cts, svc)
+	case enumor.HuaWei:
+		return listListenerExt[corelb.HuaWeiListenerExtension](cts, svc)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "unsupported vendor: %s", vendor)
+	}
+}
+
+func listListenerExt[T corelb.ListenerExtension](cts *rest.Contexts, svc *lbSvc) (any, error) {
 	req := new(core.ListReq)
 	if err := cts.DecodeInto(req); err != nil {
 		return nil, err
@@ -319,19 +345,20 @@ func (svc *lbSvc) ListListenerExt(cts *rest.Contexts) (any, error) {
 	}
 
 	if req.Page.Count {
-		return &protocloud.ListenerListResult{Count: result.Count}, nil
+		return &core.ListResultT[corelb.Listener[T]]{Count: result.Count}, nil
 	}
 
-	details := make([]corelb.Listener[corelb.TCloudListenerExtension], 0, len(result.Details))
+	details := make([]corelb.Listener[T], 0, len(result.Details))
 	for _, one := range result.Details {
-		tmpOne, err := convTableToListener[corelb.TCloudListenerExtension](&one)
+		tmpOne, err := convTableToListener[T](&one)
 		if err != nil {
 			logs.Errorf("fail to conv listener with extension, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
 		}
 		details = append(details, *tmpOne)
 	}
 
-	return &protocloud.TCloudListenerListResult{Details: details}, nil
+	return &core.ListResultT[corelb.Listener[T]]{Details: details}, nil
 }
 
 func convTableToBaseListener(one *tablelb.LoadBalancerListenerTable) *corelb.BaseListener {
@@ -588,7 +615,7 @@ func (svc *lbSvc) GetTargetGroup(cts *rest.Contexts) (any, error) {
 
 	tgInfo := result.Details[0]
 	switch tgInfo.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		return convTableToBaseTargetGroup(cts.Kit, &tgInfo)
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
@@ -668,6 +695,21 @@ func (svc *lbSvc) GetListener(cts *rest.Contexts) (any, error) {
 			return nil, err
 		}
 		return newLblInfo, nil
+	case enumor.Aws:
+		newLblInfo, err := convTableToListener[corelb.AwsListenerExtension](&lblInfo)
+		if err != nil {
+			logs.Errorf("fail to conv aws listener with extension, lblID: %s, err: %v, rid: %s", id, err, cts.Kit.Rid)
+			return nil, err
+		}
+		return newLblInfo, nil
+	case enumor.HuaWei:
+		newLblInfo, err := convTableToListener[corelb.HuaWeiListenerExtension](&lblInfo)
+		if err != nil {
+			logs.Errorf("fail to conv huawei listener with extension, lblID: %s, err: %v, rid: %s", id, err,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		return newLblInfo, nil
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
 	}
@@ -899,7 +941,7 @@ func (svc *lbSvc) queryListenerWithTargets(kt *kit.Kit, req *protocloud.ListList
 	// 根据负载均衡ID、监听器ID、目标组ID，获取监听器与目标组的绑定关系列表
 	lblUrlRuleList := make([]protocloud.LoadBalancerUrlRuleResult, 0)
 	switch req.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		lblUrlRuleList, err = svc.listTCloudLoadBalancerUrlRuleByTgIDs(kt, lblReq, cloudClbIDs,
 			cloudLblIDs, targetGroupIDs)
 		if err != nil {
@@ -50,6 +50,10 @@ func (svc *lbSvc) BatchCreateTargetGroupWithRel(cts *rest.Contexts) (any, error)
 	switch vendor {
 	case enumor.TCloud:
 		return batchCreateTargetGroupWithRel[corelb.TCloudTargetGroupExtension](cts, svc, vendor)
+	case enumor.Aws:
+		return batchCreateTargetGroupWithRel[corelb.AwsTargetGroupExtension](cts, svc, vendor)
+	case enumor.HuaWei:
+		return batchCreateTargetGroupWithRel[corelb.HuaWeiTargetGroupExtension](cts, svc, vendor)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
@@ -138,8 +142,8 @@ func createRel[T corelb.TargetGroupExtension](kt *kit.Kit, svc *lbSvc, txn *sqlx
 		Reviser:             kt.User,
 	}}
 	switch vendor {
-	case enumor.TCloud:
-		// 更新规则表
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+		// 更新规则表，aws、huawei的规则同样落在url规则表中
 		rule := &tablelb.TCloudLbUrlRuleTable{
 			TargetGroupID:      tgID,
 			CloudTargetGroupID: tgID,
@@ -54,6 +54,10 @@ func (svc *lbSvc) BatchUpdateLoadBalancer(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchUpdateLoadBalancer[corelb.TCloudClbExtension](cts, svc)
+	case enumor.Aws:
+		return batchUpdateLoadBalancer[corelb.AwsLoadBalancerExtension](cts, svc)
+	case enumor.HuaWei:
+		return batchUpdateLoadBalancer[corelb.HuaWeiLoadBalancerExtension](cts, svc)
 
 	default:
 		return nil, fmt.Errorf("unsupport  vendor %s", vendor)
@@ -267,6 +271,13 @@ func (svc *lbSvc) UpdateTargetGroup(cts *rest.Contexts) (interface{}, error) {
 			}
 			updateData.HealthCheck = tabletype.JsonField(mergedHealth)
 		}
+		if len(req.Extension) != 0 {
+			mergedExt, err := json.UpdateMerge(req.Extension, string(oldTg.Extension))
+			if err != nil {
+				return nil, fmt.Errorf("json UpdateMerge target group extension failed, err: %v", err)
+			}
+			updateData.Extension = tabletype.JsonField(mergedExt)
+		}
 		updateDataList = append(updateDataList, updateData)
 	}
 	if err := svc.dao.LoadBalancerTargetGroup().UpdateBatch(cts.Kit, updateDataList); err != nil {
@@ -384,6 +395,10 @@ func (svc *lbSvc) BatchUpdateListener(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchUpdateListener[corelb.TCloudListenerExtension](cts)
+	case enumor.Aws:
+		return batchUpdateListener[corelb.AwsListenerExtension](cts)
+	case enumor.HuaWei:
+		return batchUpdateListener[corelb.HuaWeiListenerExtension](cts)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
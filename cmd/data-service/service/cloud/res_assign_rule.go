@@ -0,0 +1,255 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitResAssignRuleService initialize the res assign rule service.
+func InitResAssignRuleService(cap *capability.Capability) {
+	svc := &resAssignRuleSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateResAssignRule", "POST", "/res_assign_rules/batch/create", svc.BatchCreateResAssignRule)
+	h.Add("BatchUpdateResAssignRule", "PATCH", "/res_assign_rules/batch/update", svc.BatchUpdateResAssignRule)
+	h.Add("ListResAssignRule", "POST", "/res_assign_rules/list", svc.ListResAssignRule)
+	h.Add("BatchDeleteResAssignRule", "DELETE", "/res_assign_rules/batch", svc.BatchDeleteResAssignRule)
+
+	h.Load(cap.WebService)
+}
+
+type resAssignRuleSvc struct {
+	dao dao.Set
+}
+
+// BatchCreateResAssignRule batch create res assign rules.
+func (svc *resAssignRuleSvc) BatchCreateResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResAssignRuleBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tablecloud.ResAssignRuleTable, 0, len(req.Rules))
+	for _, one := range req.Rules {
+		conditions, err := tabletypes.NewJsonField(one.Conditions)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, tablecloud.ResAssignRuleTable{
+			Name:       one.Name,
+			ResTypes:   resTypesToStringArray(one.ResTypes),
+			Priority:   converter.ValToPtr(one.Priority),
+			Enabled:    converter.ValToPtr(one.Enabled),
+			Conditions: conditions,
+			BkBizID:    one.BkBizID,
+			BkCloudID:  converter.ValToPtr(one.BkCloudID),
+			Memo:       one.Memo,
+			Creator:    cts.Kit.User,
+			Reviser:    cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ResAssignRule().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("create res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// BatchUpdateResAssignRule batch update res assign rules.
+func (svc *resAssignRuleSvc) BatchUpdateResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResAssignRuleBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make(map[string]*tablecloud.ResAssignRuleTable, len(req.Rules))
+	for _, one := range req.Rules {
+		model := &tablecloud.ResAssignRuleTable{
+			Name:      one.Name,
+			Priority:  one.Priority,
+			Enabled:   one.Enabled,
+			BkBizID:   one.BkBizID,
+			BkCloudID: one.BkCloudID,
+			Memo:      one.Memo,
+			Reviser:   cts.Kit.User,
+		}
+
+		if len(one.ResTypes) != 0 {
+			model.ResTypes = resTypesToStringArray(one.ResTypes)
+		}
+
+		if one.Conditions != nil {
+			conditions, err := tabletypes.NewJsonField(one.Conditions)
+			if err != nil {
+				return nil, errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			model.Conditions = conditions
+		}
+
+		models[one.ID] = model
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for id, model := range models {
+			if err := svc.dao.ResAssignRule().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("update res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListResAssignRule list res assign rules.
+func (svc *resAssignRuleSvc) ListResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ResAssignRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list res assign rule failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.ResAssignRuleListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.ResAssignRule, 0, len(result.Details))
+	for _, one := range result.Details {
+		rule := corecloud.ResAssignRule{
+			ID:        one.ID,
+			Name:      one.Name,
+			ResTypes:  make([]enumor.CloudResourceType, 0, len(one.ResTypes)),
+			Priority:  converter.PtrToVal(one.Priority),
+			Enabled:   converter.PtrToVal(one.Enabled),
+			BkBizID:   one.BkBizID,
+			BkCloudID: converter.PtrToVal(one.BkCloudID),
+			Memo:      one.Memo,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+
+		for _, resType := range one.ResTypes {
+			rule.ResTypes = append(rule.ResTypes, enumor.CloudResourceType(resType))
+		}
+
+		if len(one.Conditions) != 0 {
+			if err = json.UnmarshalFromString(string(one.Conditions), &rule.Conditions); err != nil {
+				logs.Errorf("unmarshal res assign rule %s conditions failed, err: %v, rid: %s", one.ID, err,
+					cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		details = append(details, rule)
+	}
+
+	return &protocloud.ResAssignRuleListResult{Details: details}, nil
+}
+
+// BatchDeleteResAssignRule batch delete res assign rules.
+func (svc *resAssignRuleSvc) BatchDeleteResAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ResAssignRule().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete res assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func resTypesToStringArray(resTypes []enumor.CloudResourceType) tabletypes.StringArray {
+	result := make(tabletypes.StringArray, 0, len(resTypes))
+	for _, one := range resTypes {
+		result = append(result, string(one))
+	}
+	return result
+}
@@ -0,0 +1,319 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitResourceTagService initialize the resource tag service.
+func InitResourceTagService(cap *capability.Capability) {
+	svc := &resourceTagSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchUpsertResourceTag", "POST", "/resource_tags/batch/upsert", svc.BatchUpsertResourceTag)
+	h.Add("ListResourceTag", "POST", "/resource_tags/list", svc.ListResourceTag)
+	h.Add("BatchDeleteResourceTag", "DELETE", "/resource_tags/batch", svc.BatchDeleteResourceTag)
+
+	h.Load(cap.WebService)
+}
+
+type resourceTagSvc struct {
+	dao dao.Set
+}
+
+// BatchUpsertResourceTag batch create or update resource tags, delete tags not in request when replace is set.
+func (svc *resourceTagSvc) BatchUpsertResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagBatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := svc.resolveResID(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	resIDs := make([]string, 0, len(resources))
+	for _, one := range resources {
+		resIDs = append(resIDs, one.ResID)
+	}
+	existTags, err := svc.listResourceTags(cts.Kit, req, resIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	creates, updates, deleteIDs := diffResourceTags(cts.Kit, req, resources, existTags)
+	if len(creates) == 0 && len(updates) == 0 && len(deleteIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if len(deleteIDs) > 0 {
+			if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn,
+				tools.ContainersExpression("id", deleteIDs)); err != nil {
+				return nil, err
+			}
+		}
+
+		for id, value := range updates {
+			model := &tablecloud.ResourceTagTable{Value: value, Reviser: cts.Kit.User}
+			if err := svc.dao.ResourceTag().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(creates) > 0 {
+			if _, err := svc.dao.ResourceTag().BatchCreateWithTx(cts.Kit, txn, creates); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("upsert resource tag failed, err: %v, res_type: %s, rid: %s", err, req.ResType, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// resolveResID 补全未指定资源ID的资源，云上ID在 db 中不存在的资源会被忽略
+func (svc *resourceTagSvc) resolveResID(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq) (
+	[]protocloud.ResourceTagUpsertItem, error) {
+
+	cloudIDs := make([]string, 0)
+	for _, one := range req.Resources {
+		if len(one.ResID) == 0 {
+			cloudIDs = append(cloudIDs, one.CloudResID)
+		}
+	}
+
+	if len(cloudIDs) == 0 {
+		return req.Resources, nil
+	}
+
+	cloudIDMap, err := svc.dao.ResourceTag().ListResIDByCloudID(kt, req.ResType, req.AccountID, cloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]protocloud.ResourceTagUpsertItem, 0, len(req.Resources))
+	for _, one := range req.Resources {
+		if len(one.ResID) == 0 {
+			resID, exist := cloudIDMap[one.CloudResID]
+			if !exist {
+				logs.V(3).Infof("%s %s not found, skip upsert its tags, rid: %s", req.ResType, one.CloudResID,
+					kt.Rid)
+				continue
+			}
+			one.ResID = resID
+		}
+		resources = append(resources, one)
+	}
+
+	return resources, nil
+}
+
+func (svc *resourceTagSvc) listResourceTags(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq,
+	resIDs []string) ([]tablecloud.ResourceTagTable, error) {
+
+	result := make([]tablecloud.ResourceTagTable, 0)
+	for _, ids := range slice.Split(resIDs, int(core.DefaultMaxPageLimit)) {
+		opt := &types.ListOption{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("res_type", req.ResType), tools.RuleIn("res_id", ids)),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			resp, err := svc.dao.ResourceTag().List(kt, opt)
+			if err != nil {
+				logs.Errorf("list resource tag failed, err: %v, res_type: %s, rid: %s", err, req.ResType, kt.Rid)
+				return nil, err
+			}
+
+			result = append(result, resp.Details...)
+			if uint(len(resp.Details)) < opt.Page.Limit {
+				break
+			}
+			opt.Page.Start += uint32(opt.Page.Limit)
+		}
+	}
+
+	return result, nil
+}
+
+// diffResourceTags 对比请求中的标签与 db 中的标签，返回需要新增的标签、需要更新值的标签（id -> value）和需要删除的标签ID
+func diffResourceTags(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq,
+	resources []protocloud.ResourceTagUpsertItem, existTags []tablecloud.ResourceTagTable) (
+	[]tablecloud.ResourceTagTable, map[string]string, []string) {
+
+	// res_id -> key -> tag
+	existMap := make(map[string]map[string]tablecloud.ResourceTagTable)
+	for _, one := range existTags {
+		if _, ok := existMap[one.ResID]; !ok {
+			existMap[one.ResID] = make(map[string]tablecloud.ResourceTagTable)
+		}
+		existMap[one.ResID][one.Key] = one
+	}
+
+	creates := make([]tablecloud.ResourceTagTable, 0)
+	updates := make(map[string]string)
+	deleteIDs := make([]string, 0)
+	for _, res := range resources {
+		exists := existMap[res.ResID]
+		keys := make(map[string]struct{}, len(res.Tags))
+		for _, tag := range res.Tags {
+			keys[tag.Key] = struct{}{}
+
+			if exist, ok := exists[tag.Key]; ok {
+				if exist.Value != tag.Value {
+					updates[exist.ID] = tag.Value
+				}
+				continue
+			}
+
+			creates = append(creates, tablecloud.ResourceTagTable{
+				Vendor:     req.Vendor,
+				AccountID:  req.AccountID,
+				ResType:    req.ResType,
+				ResID:      res.ResID,
+				CloudResID: res.CloudResID,
+				Key:        tag.Key,
+				Value:      tag.Value,
+				Creator:    kt.User,
+				Reviser:    kt.User,
+			})
+		}
+
+		if !req.Replace {
+			continue
+		}
+
+		for key, exist := range exists {
+			if _, ok := keys[key]; !ok {
+				deleteIDs = append(deleteIDs, exist.ID)
+			}
+		}
+	}
+
+	return creates, updates, deleteIDs
+}
+
+// ListResourceTag list resource tags.
+func (svc *resourceTagSvc) ListResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ResourceTag().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list resource tag failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list resource tag failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.ResourceTagListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.ResourceTag, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.ResourceTag{
+			ID:         one.ID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			Key:        one.Key,
+			Value:      one.Value,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.ResourceTagListResult{Details: details}, nil
+}
+
+// BatchDeleteResourceTag batch delete resource tags.
+func (svc *resourceTagSvc) BatchDeleteResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete resource tag failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -485,6 +485,11 @@ func (svc *subnetSvc) BatchDeleteSubnet(cts *rest.Contexts) (interface{}, error)
 			return nil, err
 		}
 
+		if err := svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.SubnetCloudResType,
+			delSubnetIDs); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -40,6 +40,9 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchCreateAccountSD", http.MethodPost, "/account_sync_details/batch/create", svc.BatchCreateAccountSD)
 	h.Add("BatchUpdateAccountSD", http.MethodPatch, "/account_sync_details/batch/update", svc.BatchUpdateAccountSD)
 
+	h.Add("ListSyncCursor", http.MethodPost, "/sync_cursors/list", svc.ListSyncCursor)
+	h.Add("BatchSetSyncCursor", http.MethodPut, "/sync_cursors/batch/set", svc.BatchSetSyncCursor)
+
 	h.Load(cap.WebService)
 }
 
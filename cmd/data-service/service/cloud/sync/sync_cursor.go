@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablesync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ListSyncCursor list sync cursor.
+func (svc *service) ListSyncCursor(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.SyncCursor().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list sync cursor failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list sync cursor failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &dssync.SyncCursorListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coresync.SyncCursor, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, coresync.SyncCursor(one))
+	}
+
+	return &dssync.SyncCursorListResult{Details: details}, nil
+}
+
+// BatchSetSyncCursor set sync cursor, create it if the type and scope not exists.
+func (svc *service) BatchSetSyncCursor(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.SyncCursorBatchSetReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	existIDs, err := svc.listSyncCursorIDs(cts, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		creates := make([]tablesync.SyncCursorTable, 0)
+		for _, item := range req.Items {
+			id, exist := existIDs[syncCursorKey(item.Type, item.Scope)]
+			if !exist {
+				creates = append(creates, tablesync.SyncCursorTable{
+					Type:        item.Type,
+					Scope:       item.Scope,
+					CursorValue: item.CursorValue,
+					Creator:     cts.Kit.User,
+					Reviser:     cts.Kit.User,
+				})
+				continue
+			}
+
+			model := &tablesync.SyncCursorTable{CursorValue: item.CursorValue, Reviser: cts.Kit.User}
+			if _, err := svc.dao.SyncCursor().UpdateWithTx(cts.Kit, txn, tools.EqualExpression("id", id),
+				model); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(creates) == 0 {
+			return nil, nil
+		}
+		return svc.dao.SyncCursor().BatchCreateWithTx(cts.Kit, txn, creates)
+	})
+	if err != nil {
+		logs.Errorf("batch set sync cursor failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listSyncCursorIDs 查询已存在的游标，返回 类型/作用范围 到游标id的映射
+func (svc *service) listSyncCursorIDs(cts *rest.Contexts, items []dssync.SyncCursorSetField) (map[string]string,
+	error) {
+
+	cursorTypes := make([]enumor.SyncCursorType, 0, len(items))
+	scopes := make([]string, 0, len(items))
+	for _, item := range items {
+		cursorTypes = append(cursorTypes, item.Type)
+		scopes = append(scopes, item.Scope)
+	}
+
+	opt := &types.ListOption{
+		Filter: tools.ExpressionAnd(tools.RuleIn("type", slice.Unique(cursorTypes)),
+			tools.RuleIn("scope", slice.Unique(scopes))),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "type", "scope"},
+	}
+	daoResp, err := svc.dao.SyncCursor().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list sync cursor failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	result := make(map[string]string, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		result[syncCursorKey(one.Type, one.Scope)] = one.ID
+	}
+
+	return result, nil
+}
+
+func syncCursorKey(cursorType enumor.SyncCursorType, scope string) string {
+	return string(cursorType) + "/" + scope
+}
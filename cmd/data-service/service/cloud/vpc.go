@@ -486,6 +486,11 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 			return nil, err
 		}
 
+		if err := svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.VpcCloudResType,
+			delVpcIDs); err != nil {
+			return nil, err
+		}
+
 		delSubnetFilter := tools.ContainersExpression("vpc_id", delVpcIDs)
 		if err := svc.dao.Subnet().BatchDeleteWithTx(cts.Kit, txn, delSubnetFilter); err != nil {
 			return nil, err
@@ -65,11 +65,13 @@ func (s service) GenerateTemporalUrl(cts *rest.Contexts) (any, error) {
 		return nil, err
 	}
 
-	return &cos.GenerateTemporalUrlResult{
-		AK:    cred.TmpSecretID,
-		Token: cred.SessionToken,
-		URL:   url,
-	}, nil
+	result := &cos.GenerateTemporalUrlResult{URL: url}
+	// s3、本地存储的签名信息包含在url中，没有临时密钥
+	if cred != nil {
+		result.AK = cred.TmpSecretID
+		result.Token = cred.SessionToken
+	}
+	return result, nil
 }
 
 // UploadFile uploads a file to COS.
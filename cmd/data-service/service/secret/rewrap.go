@@ -0,0 +1,399 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package secret 云账号密钥的维护，包括密钥加密密钥(KEK)轮换后重新包装存量的加密字段
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/cryptography"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/serviced"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// mainAccountInitPasswordField 二级账号扩展字段中加密存储的初始密码
+	mainAccountInitPasswordField = "cloud_init_password"
+	// cvmPasswordField、cvmConfirmedPasswordField 创建主机申请单内容中加密存储的密码
+	cvmPasswordField          = "password"
+	cvmConfirmedPasswordField = "confirmed_password"
+	// accountExtensionField 新增账号申请单内容中的账号扩展字段，其中的密钥字段加密存储
+	accountExtensionField = "extension"
+)
+
+// Rewrapper 将账号、一级账号的密钥，二级账号的初始密码，以及申请单内容中的密钥、密码，
+// 由旧版本KEK包装或未使用信封加密的密文使用当前版本的KEK重新包装。
+// 更新时以原密文作为条件，且只更新JSON列中的加密字段，重新包装期间字段被修改时放弃本次更新，
+// JSON列中其他字段的修改不受影响，不影响账号的在线使用
+type Rewrapper struct {
+	dao       dao.Set
+	cipher    cryptography.Rewrapper
+	batchSize uint
+}
+
+// NewRewrapper new secret rewrapper.
+func NewRewrapper(daoSet dao.Set, cipher cryptography.Rewrapper) *Rewrapper {
+	return &Rewrapper{dao: daoSet, cipher: cipher, batchSize: core.DefaultMaxPageLimit}
+}
+
+// Run 在主节点上定期执行重新包装，ctx 取消后退出
+func (r *Rewrapper) Run(ctx context.Context, interval time.Duration, state serviced.State) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		result, err := r.Rewrap(kt)
+		if err != nil {
+			logs.Errorf("rewrap secret failed, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		logs.Infof("rewrap secret success, account: %+v, root account: %+v, main account: %+v, application: %+v, "+
+			"rid: %s", *result.Account, *result.RootAccount, *result.MainAccount, *result.Application, kt.Rid)
+	}
+}
+
+// Rewrap 重新包装全部表中的加密字段，存在重新包装失败的字段时返回错误，此时旧版本的KEK不能下线
+func (r *Rewrapper) Rewrap(kt *kit.Kit) (*protocloud.RewrapSecretResult, error) {
+	result := new(protocloud.RewrapSecretResult)
+	for _, table := range r.tables() {
+		stat, err := r.rewrapTable(kt, table)
+		if err != nil {
+			return nil, err
+		}
+		*table.stat(result) = stat
+	}
+
+	if failed := result.Failed(); failed > 0 {
+		return nil, errf.Newf(errf.Aborted, "%d encrypted fields failed to rewrap and still depend on old kek, "+
+			"account: %+v, root account: %+v, main account: %+v, application: %+v", failed, *result.Account,
+			*result.RootAccount, *result.MainAccount, *result.Application)
+	}
+
+	return result, nil
+}
+
+// secretTable 存储了加密字段的表
+type secretTable struct {
+	name string
+	// list 按ID升序分页列出记录
+	list func(kt *kit.Kit, opt *types.ListOption) ([]secretRecord, error)
+	// fields 列出记录时查询的字段
+	fields []string
+	// update 仅当记录中path字段等于原密文时更新为新密文
+	update func(kt *kit.Kit, expr *filter.Expression, path []string, oldValue string, value string) error
+	// stat 返回结果中该表的统计
+	stat func(result *protocloud.RewrapSecretResult) **protocloud.RewrapSecretStat
+}
+
+// secretRecord 一条记录中存储加密字段的JSON列
+type secretRecord struct {
+	id   string
+	data tabletype.JsonField
+	// paths 已知的加密字段在JSON列中的路径
+	paths [][]string
+}
+
+// tables 全部存储了加密字段的表，新增加密字段时需要在这里登记
+func (r *Rewrapper) tables() []secretTable {
+	return []secretTable{
+		{
+			name:   "account",
+			fields: []string{"id", "vendor", "extension"},
+			list: func(kt *kit.Kit, opt *types.ListOption) ([]secretRecord, error) {
+				list, err := r.dao.Account().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]secretRecord, 0, len(list.Details))
+				for _, one := range list.Details {
+					records = append(records, secretRecord{id: one.ID, data: one.Extension,
+						paths: vendorSecretPaths(enumor.Vendor(one.Vendor))})
+				}
+				return records, nil
+			},
+			update: r.dao.Account().UpdateExtensionField,
+			stat: func(result *protocloud.RewrapSecretResult) **protocloud.RewrapSecretStat {
+				return &result.Account
+			},
+		},
+		{
+			name:   "root_account",
+			fields: []string{"id", "vendor", "extension"},
+			list: func(kt *kit.Kit, opt *types.ListOption) ([]secretRecord, error) {
+				list, err := r.dao.RootAccount().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]secretRecord, 0, len(list.Details))
+				for _, one := range list.Details {
+					records = append(records, secretRecord{id: one.ID, data: one.Extension,
+						paths: vendorSecretPaths(enumor.Vendor(one.Vendor))})
+				}
+				return records, nil
+			},
+			update: r.dao.RootAccount().UpdateExtensionField,
+			stat: func(result *protocloud.RewrapSecretResult) **protocloud.RewrapSecretStat {
+				return &result.RootAccount
+			},
+		},
+		{
+			name:   "main_account",
+			fields: []string{"id", "extension"},
+			list: func(kt *kit.Kit, opt *types.ListOption) ([]secretRecord, error) {
+				list, err := r.dao.MainAccount().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]secretRecord, 0, len(list.Details))
+				for _, one := range list.Details {
+					records = append(records, secretRecord{id: one.ID, data: one.Extension,
+						paths: [][]string{{mainAccountInitPasswordField}}})
+				}
+				return records, nil
+			},
+			update: r.dao.MainAccount().UpdateExtensionField,
+			stat: func(result *protocloud.RewrapSecretResult) **protocloud.RewrapSecretStat {
+				return &result.MainAccount
+			},
+		},
+		{
+			name:   "application",
+			fields: []string{"id", "type", "content"},
+			list: func(kt *kit.Kit, opt *types.ListOption) ([]secretRecord, error) {
+				list, err := r.dao.Application().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]secretRecord, 0, len(list.Details))
+				for _, one := range list.Details {
+					records = append(records, secretRecord{id: one.ID, data: one.Content,
+						paths: applicationSecretPaths(enumor.ApplicationType(one.Type), one.Content)})
+				}
+				return records, nil
+			},
+			update: r.dao.Application().UpdateContentField,
+			stat: func(result *protocloud.RewrapSecretResult) **protocloud.RewrapSecretStat {
+				return &result.Application
+			},
+		},
+	}
+}
+
+// vendorSecretPaths 账号、一级账号扩展字段中加密存储的密钥
+func vendorSecretPaths(vendor enumor.Vendor) [][]string {
+	field := vendor.GetSecretField()
+	if len(field) == 0 {
+		return nil
+	}
+	return [][]string{{field}}
+}
+
+// applicationSecretPaths 申请单内容中加密存储的字段，新增账号申请单加密了账号密钥，创建主机申请单加密了登录密码
+func applicationSecretPaths(appType enumor.ApplicationType, content tabletype.JsonField) [][]string {
+	vendor := enumor.Vendor(gjson.Get(string(content), "vendor").String())
+	switch appType {
+	case enumor.AddAccount:
+		field := vendor.GetSecretField()
+		if len(field) == 0 {
+			return nil
+		}
+		return [][]string{{accountExtensionField, field}}
+	case enumor.CreateCvm:
+		// GCP 使用公钥登录，未加密
+		if vendor == enumor.Gcp {
+			return nil
+		}
+		return [][]string{{cvmPasswordField}, {cvmConfirmedPasswordField}}
+	default:
+		return nil
+	}
+}
+
+func (r *Rewrapper) rewrapTable(kt *kit.Kit, table secretTable) (*protocloud.RewrapSecretStat, error) {
+	stat := new(protocloud.RewrapSecretStat)
+	lastID := ""
+	for {
+		records, err := table.list(kt, r.listOption(lastID, table.fields))
+		if err != nil {
+			logs.Errorf("list %s failed, err: %v, rid: %s", table.name, err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range records {
+			stat.Total++
+			r.rewrapRecord(kt, stat, table, one)
+		}
+
+		if uint(len(records)) < r.batchSize {
+			return stat, nil
+		}
+		lastID = records[len(records)-1].id
+	}
+}
+
+func (r *Rewrapper) listOption(lastID string, fields []string) *types.ListOption {
+	expr := tools.AllExpression()
+	if len(lastID) != 0 {
+		expr = tools.ExpressionAnd(tools.RuleIDGreaterThan(lastID))
+	}
+
+	return &types.ListOption{
+		Filter: expr,
+		Page:   &core.BasePage{Limit: r.batchSize, Sort: "id", Order: core.Ascending},
+		Fields: fields,
+	}
+}
+
+// rewrapRecord 重新包装一条记录中的全部加密字段，除已知的加密字段外，JSON列中其他信封加密的密文也会被重新包装，
+// 单个字段失败时只记录，不影响其他字段
+func (r *Rewrapper) rewrapRecord(kt *kit.Kit, stat *protocloud.RewrapSecretStat, table secretTable,
+	record secretRecord) {
+
+	if len(record.data) == 0 {
+		return
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(record.data), &data); err != nil {
+		stat.Failed++
+		logs.Errorf("unmarshal %s %s json field failed, err: %v, rid: %s", table.name, record.id, err, kt.Rid)
+		return
+	}
+
+	known := make(map[string]struct{}, len(record.paths))
+	for _, path := range record.paths {
+		known[strings.Join(path, ".")] = struct{}{}
+	}
+
+	paths := record.paths
+	for _, path := range findEnvelopePaths(data, nil) {
+		if _, exists := known[strings.Join(path, ".")]; exists {
+			continue
+		}
+		stat.Discovered++
+		logs.Warnf("found envelope encrypted field %v in %s %s which is not registered, rewrap it, rid: %s", path,
+			table.name, record.id, kt.Rid)
+		paths = append(paths, path)
+	}
+
+	for _, path := range paths {
+		encrypted, ok := lookupString(data, path)
+		if !ok || len(encrypted) == 0 {
+			continue
+		}
+
+		r.rewrapField(kt, stat, table, record.id, path, encrypted)
+	}
+}
+
+func (r *Rewrapper) rewrapField(kt *kit.Kit, stat *protocloud.RewrapSecretStat, table secretTable, id string,
+	path []string, encrypted string) {
+
+	rewrapped, changed, err := r.cipher.Rewrap(encrypted)
+	if err != nil {
+		stat.Failed++
+		logs.Errorf("rewrap %s %s field %v failed, err: %v, rid: %s", table.name, id, path, err, kt.Rid)
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	// 以原密文作为更新条件，且只更新加密字段，避免覆盖重新包装期间对记录的修改
+	err = table.update(kt, tools.EqualExpression("id", id), path, encrypted, rewrapped)
+	if err != nil {
+		if errf.IsRecordNotFound(err) {
+			stat.Conflicted++
+			return
+		}
+
+		stat.Failed++
+		logs.Errorf("update %s %s field %v failed, err: %v, rid: %s", table.name, id, path, err, kt.Rid)
+		return
+	}
+
+	stat.Rewrapped++
+}
+
+// findEnvelopePaths 返回JSON对象中全部值为信封加密密文的字段路径，按路径排序，不查找数组中的元素
+func findEnvelopePaths(data map[string]interface{}, prefix []string) [][]string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	paths := make([][]string, 0)
+	for _, key := range keys {
+		path := append(append([]string{}, prefix...), key)
+		switch value := data[key].(type) {
+		case string:
+			if cryptography.IsEnvelope(value) {
+				paths = append(paths, path)
+			}
+		case map[string]interface{}:
+			paths = append(paths, findEnvelopePaths(value, path)...)
+		}
+	}
+	return paths
+}
+
+// lookupString 获取JSON对象中path对应的字符串
+func lookupString(data map[string]interface{}, path []string) (string, bool) {
+	var cur interface{} = data
+	for _, field := range path {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if cur, ok = obj[field]; !ok {
+			return "", false
+		}
+	}
+
+	value, ok := cur.(string)
+	return value, ok
+}
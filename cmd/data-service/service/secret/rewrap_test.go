@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package secret
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationSecretPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		appType enumor.ApplicationType
+		content string
+		want    [][]string
+	}{
+		{
+			name:    "add tcloud account",
+			appType: enumor.AddAccount,
+			content: `{"vendor":"tcloud","extension":{"cloud_secret_id":"id","cloud_secret_key":"enc"}}`,
+			want:    [][]string{{"extension", "cloud_secret_key"}},
+		},
+		{
+			name:    "add azure account",
+			appType: enumor.AddAccount,
+			content: `{"vendor":"azure","extension":{"cloud_client_secret_key":"enc"}}`,
+			want:    [][]string{{"extension", "cloud_client_secret_key"}},
+		},
+		{
+			name:    "create tcloud cvm",
+			appType: enumor.CreateCvm,
+			content: `{"vendor":"tcloud","password":"enc","confirmed_password":"enc"}`,
+			want:    [][]string{{"password"}, {"confirmed_password"}},
+		},
+		{
+			name:    "create gcp cvm",
+			appType: enumor.CreateCvm,
+			content: `{"vendor":"gcp","password":"public key"}`,
+			want:    nil,
+		},
+		{
+			name:    "create vpc",
+			appType: enumor.CreateVpc,
+			content: `{"vendor":"tcloud"}`,
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, applicationSecretPaths(tt.appType, tabletype.JsonField(tt.content)))
+		})
+	}
+}
+
+func TestFindEnvelopePaths(t *testing.T) {
+	data := make(map[string]interface{})
+	require.NoError(t, json.UnmarshalFromString(`{
+		"cloud_secret_key": "hcmenc:1:d3JhcHBlZA==:ZGF0YQ==",
+		"cloud_secret_id": "AKID",
+		"legacy": "bGVnYWN5",
+		"nested": {"token": "hcmenc:1:d3JhcHBlZA==:ZGF0YQ==", "count": 1},
+		"list": ["hcmenc:1:d3JhcHBlZA==:ZGF0YQ=="]
+	}`, &data))
+
+	assert.Equal(t, [][]string{{"cloud_secret_key"}, {"nested", "token"}}, findEnvelopePaths(data, nil))
+
+	value, ok := lookupString(data, []string{"nested", "token"})
+	assert.True(t, ok)
+	assert.Equal(t, "hcmenc:1:d3JhcHBlZA==:ZGF0YQ==", value)
+
+	_, ok = lookupString(data, []string{"nested", "count"})
+	assert.False(t, ok)
+	_, ok = lookupString(data, []string{"cloud_secret_id", "x"})
+	assert.False(t, ok)
+	_, ok = lookupString(data, []string{"not_exist"})
+	assert.False(t, ok)
+}
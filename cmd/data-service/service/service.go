@@ -32,8 +32,11 @@ import (
 	rootaccount "hcm/cmd/data-service/service/account-set/root-account"
 	"hcm/cmd/data-service/service/application"
 	"hcm/cmd/data-service/service/audit"
+	"hcm/cmd/data-service/service/audit/archive"
 	"hcm/cmd/data-service/service/auth"
 	"hcm/cmd/data-service/service/bill/billadjustmentitem"
+	"hcm/cmd/data-service/service/bill/billbudget"
+	"hcm/cmd/data-service/service/bill/billcostanomaly"
 	"hcm/cmd/data-service/service/bill/billdailytask"
 	"hcm/cmd/data-service/service/bill/billexchangerate"
 	"hcm/cmd/data-service/service/bill/billitem"
@@ -73,14 +76,19 @@ import (
 	"hcm/cmd/data-service/service/cloud/zone"
 	"hcm/cmd/data-service/service/cos"
 	recyclerecord "hcm/cmd/data-service/service/recycle-record"
+	"hcm/cmd/data-service/service/secret"
 	"hcm/cmd/data-service/service/task"
 	"hcm/cmd/data-service/service/user"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/auditsink"
 	"hcm/pkg/cc"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/cryptography"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/objectstore"
 	"hcm/pkg/handler"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
 	"hcm/pkg/rest"
@@ -137,11 +145,106 @@ func NewService() (*Service, error) {
 	return svr, nil
 }
 
+// VerifyAuditChain 校验审计哈希链，供控制工具命令使用
+func (s *Service) VerifyAuditChain(kt *kit.Kit, req *protoaudit.VerifyChainReq) (*protoaudit.VerifyChainResult,
+	error) {
+
+	return audit.VerifyChains(kt, s.dao, s.objectStore, req)
+}
+
+// StartAuditSink 配置了审计事件投递目标时，启动审计事件投递，仅在主节点上投递
+func (s *Service) StartAuditSink(state serviced.State) error {
+	opt := cc.DataService().AuditSink
+	if !opt.Enable() {
+		return nil
+	}
+
+	dispatcher, err := auditsink.NewDispatcher(opt, s.dao.AuditOutbox(), state)
+	if err != nil {
+		return fmt.Errorf("init audit sink dispatcher failed, err: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher.Run(ctx)
+
+	go func() {
+		notifier := shutdown.AddNotifier()
+		select {
+		case <-notifier.Signal:
+			defer notifier.Done()
+			logs.Infof("start shutdown audit sink dispatcher...")
+			cancel()
+		}
+	}()
+
+	logs.Infof("start audit sink dispatcher with %d sinks success.", len(opt.Sinks))
+	return nil
+}
+
+// StartAuditArchive 配置了审计保留期时，启动审计记录归档，仅在主节点上归档
+func (s *Service) StartAuditArchive(state serviced.State) error {
+	opt := cc.DataService().AuditArchive
+	if !opt.Enable() {
+		return nil
+	}
+
+	archiver := archive.NewArchiver(opt, s.dao, s.objectStore, state)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go archiver.Run(ctx)
+
+	go func() {
+		notifier := shutdown.AddNotifier()
+		select {
+		case <-notifier.Signal:
+			defer notifier.Done()
+			logs.Infof("start shutdown audit archiver...")
+			cancel()
+		}
+	}()
+
+	logs.Infof("start audit archiver success, default retention days: %d.", opt.DefaultRetentionDays)
+	return nil
+}
+
+// RewrapSecret 使用当前版本的KEK重新包装存量账号密钥，供控制工具命令使用
+func (s *Service) RewrapSecret(kt *kit.Kit) (*protocloud.RewrapSecretResult, error) {
+	rewrapper, ok := s.cipher.(cryptography.Rewrapper)
+	if !ok {
+		return nil, errf.New(errf.Aborted, "envelope encryption is not enabled")
+	}
+
+	return secret.NewRewrapper(s.dao, rewrapper).Rewrap(kt)
+}
+
+// StartSecretRewrap 开启信封加密并配置了执行间隔时，定期重新包装存量账号密钥，仅在主节点上执行
+func (s *Service) StartSecretRewrap(state serviced.State) error {
+	intervalMin := cc.DataService().Crypto.Envelope.RewrapIntervalMin
+	rewrapper, ok := s.cipher.(cryptography.Rewrapper)
+	if !ok || intervalMin == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go secret.NewRewrapper(s.dao, rewrapper).Run(ctx, time.Duration(intervalMin)*time.Minute, state)
+
+	go func() {
+		notifier := shutdown.AddNotifier()
+		select {
+		case <-notifier.Signal:
+			defer notifier.Done()
+			logs.Infof("start shutdown account secret rewrapper...")
+			cancel()
+		}
+	}()
+
+	logs.Infof("start account secret rewrapper success, interval: %d minutes.", intervalMin)
+	return nil
+}
+
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.New(cryptoConfig.Option())
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -149,6 +252,10 @@ func (s *Service) ListenAndServeRest() error {
 	root := http.NewServeMux()
 	root.HandleFunc("/", s.apiSet().ServeHTTP)
 	root.HandleFunc("/healthz", s.Healthz)
+	// 本地对象存储的临时链接不经过鉴权，由签名校验
+	if local, ok := s.objectStore.(*objectstore.LocalStorage); ok {
+		root.Handle(objectstore.LocalURLPath, local)
+	}
 	handler.SetCommonHandler(root)
 
 	network := cc.DataService().Network
@@ -220,6 +327,9 @@ func (s *Service) apiSet() *restful.Container {
 	securitygroup.InitGcpFirewallRuleService(capability)
 	cloud.InitVpcService(capability)
 	cloud.InitSubnetService(capability)
+	cloud.InitResourceTagService(capability)
+	cloud.InitResAssignRuleService(capability)
+	cloud.InitDiskSnapshotService(capability)
 	cloud.InitCloudService(capability)
 	auth.InitAuthService(capability)
 	disk.InitService(capability)
@@ -234,6 +344,7 @@ func (s *Service) apiSet() *restful.Container {
 	routetable.InitRouteTableService(capability)
 	application.InitApplicationService(capability)
 	application.InitApprovalProcessService(capability)
+	application.InitApprovalService(capability)
 	diskcvmrel.InitService(capability)
 	eipcvmrel.InitService(capability)
 	networkinterface.InitNetInterfaceService(capability)
@@ -269,6 +380,8 @@ func (s *Service) apiSet() *restful.Container {
 	sgcomrel.InitService(capability)
 
 	billexchangerate.InitService(capability)
+	billbudget.InitService(capability)
+	billcostanomaly.InitService(capability)
 	billsyncrecord.InitService(capability)
 
 	task.InitService(capability)
@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudadaptor
+
+import (
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/cc"
+)
+
+// TCloudRoleSecret 构造 tcloud 角色扮演密钥，roleArn 为空时直接使用账号密钥
+func TCloudRoleSecret(secretID, secretKey, roleArn, externalID string) *types.BaseSecret {
+	if len(roleArn) == 0 {
+		return &types.BaseSecret{CloudSecretID: secretID, CloudSecretKey: secretKey}
+	}
+
+	return roleSecret(cc.HCService().AssumeRoleSource.TCloud, secretID, secretKey,
+		&types.AssumeRole{RoleArn: roleArn, ExternalID: externalID})
+}
+
+// AwsRoleSecret 构造 aws 角色扮演密钥，roleArn 为空时直接使用账号密钥
+func AwsRoleSecret(secretID, secretKey, roleArn, externalID string) *types.BaseSecret {
+	if len(roleArn) == 0 {
+		return &types.BaseSecret{CloudSecretID: secretID, CloudSecretKey: secretKey}
+	}
+
+	return roleSecret(cc.HCService().AssumeRoleSource.Aws, secretID, secretKey,
+		&types.AssumeRole{RoleArn: roleArn, ExternalID: externalID})
+}
+
+// HuaWeiAgencySecret 构造 huawei 委托密钥，agencyName 为空时直接使用账号密钥
+func HuaWeiAgencySecret(secretID, secretKey, domainName, agencyName string) *types.BaseSecret {
+	if len(agencyName) == 0 {
+		return &types.BaseSecret{CloudSecretID: secretID, CloudSecretKey: secretKey}
+	}
+
+	return roleSecret(cc.HCService().AssumeRoleSource.HuaWei, secretID, secretKey,
+		&types.AssumeRole{AgencyDomainName: domainName, AgencyName: agencyName})
+}
+
+// roleSecret 账号保存了密钥时使用账号密钥扮演角色，否则使用配置的源身份密钥
+func roleSecret(source cc.AssumeRoleSecret, secretID, secretKey string, role *types.AssumeRole) *types.BaseSecret {
+	if len(secretID) == 0 && len(secretKey) == 0 {
+		secretID, secretKey = source.SecretID, source.SecretKey
+	}
+
+	return &types.BaseSecret{CloudSecretID: secretID, CloudSecretKey: secretKey, AssumeRole: role}
+}
@@ -116,6 +116,22 @@ func (cli *CloudAdaptorClient) Azure(kt *kit.Kit, accountID string) (*azure.Azur
 	return cli.adaptor.Azure(cred)
 }
 
+// TCloudRoot return tcloud root client.
+func (cli *CloudAdaptorClient) TCloudRoot(kt *kit.Kit, accountID string) (tcloud.TCloud, error) {
+	secret, err := cli.secretCli.TCloudRootSecret(kt, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cli.adaptor.TCloud(secret)
+	if err != nil {
+		return nil, err
+	}
+	client.SetRateLimitRetryWithRandomInterval(kt.RequestSource == enumor.AsynchronousTasks)
+
+	return client, nil
+}
+
 // AwsRoot return aws root client.
 func (cli *CloudAdaptorClient) AwsRoot(kt *kit.Kit, accountID string) (*aws.Aws, error) {
 	secret, cloudAccountID, err := cli.secretCli.AwsRootSecret(kt, accountID)
@@ -54,10 +54,8 @@ func (cli *SecretClient) TCloudSecret(kt *kit.Kit, accountID string) (*types.Bas
 		return nil, errors.New("tcloud account extension is nil")
 	}
 
-	secret := &types.BaseSecret{
-		CloudSecretID:  account.Extension.CloudSecretID,
-		CloudSecretKey: account.Extension.CloudSecretKey,
-	}
+	ext := account.Extension
+	secret := TCloudRoleSecret(ext.CloudSecretID, ext.CloudSecretKey, ext.CloudRoleArn, ext.CloudExternalID)
 
 	if err := secret.Validate(); err != nil {
 		return nil, err
@@ -81,10 +79,8 @@ func (cli *SecretClient) AwsSecret(kt *kit.Kit, accountID string) (*types.BaseSe
 		return nil, "", errors.New("aws account extension is nil")
 	}
 
-	secret := &types.BaseSecret{
-		CloudSecretID:  account.Extension.CloudSecretID,
-		CloudSecretKey: account.Extension.CloudSecretKey,
-	}
+	ext := account.Extension
+	secret := AwsRoleSecret(ext.CloudSecretID, ext.CloudSecretKey, ext.CloudRoleArn, ext.CloudExternalID)
 
 	if err := secret.Validate(); err != nil {
 		return nil, "", err
@@ -108,10 +104,8 @@ func (cli *SecretClient) HuaWeiSecret(kt *kit.Kit, accountID string) (*types.Bas
 		return nil, errors.New("huawei account extension is nil")
 	}
 
-	secret := &types.BaseSecret{
-		CloudSecretID:  account.Extension.CloudSecretID,
-		CloudSecretKey: account.Extension.CloudSecretKey,
-	}
+	ext := account.Extension
+	secret := HuaWeiAgencySecret(ext.CloudSecretID, ext.CloudSecretKey, ext.CloudSubAccountName, ext.CloudAgencyName)
 
 	if err := secret.Validate(); err != nil {
 		return nil, err
@@ -199,6 +193,29 @@ func (cli *SecretClient) GcpRegisterCredential(kt *kit.Kit, accountID string) (*
 	return cred, nil
 }
 
+// TCloudRootSecret get tcloud root account secret and validate secret.
+func (cli *SecretClient) TCloudRootSecret(kt *kit.Kit, accountID string) (*types.BaseSecret, error) {
+	account, err := cli.data.TCloud.RootAccount.Get(kt, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get tcloud root account failed, err: %v", err)
+	}
+
+	if account.Extension == nil {
+		return nil, errors.New("tcloud root account extension is nil")
+	}
+
+	secret := &types.BaseSecret{
+		CloudSecretID:  account.Extension.CloudSecretID,
+		CloudSecretKey: account.Extension.CloudSecretKey,
+	}
+
+	if err := secret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
 // AwsRootSecret get aws secret and validate secret.
 func (cli *SecretClient) AwsRootSecret(kt *kit.Kit, accountID string) (*types.BaseSecret, string, error) {
 	account, err := cli.data.Aws.RootAccount.Get(kt, accountID)
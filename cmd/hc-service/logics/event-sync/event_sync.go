@@ -0,0 +1,171 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package eventsync 基于云上审计事件的增量同步，只同步事件涉及的资源
+package eventsync
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// maxPageCount 单次同步最多拉取的事件页数，避免时间窗口过大时长时间阻塞
+const maxPageCount = 200
+
+// Source 云上事件源，腾讯云、aws、华为云的 adaptor 均实现了该接口，测试时可以使用 FileSource。
+type Source interface {
+	ListCloudEvent(kt *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error)
+}
+
+// Change 事件映射后的资源变更
+type Change struct {
+	Region  string
+	ResType enumor.CloudResourceType
+	CloudID string
+}
+
+// Mapper 将云上事件映射为资源变更，不关心的事件返回空
+type Mapper func(event cloudevent.CloudEvent) []Change
+
+// ResSyncFunc 按云ID同步指定地域下的资源，单次传入的云ID数量不超过 constant.CloudResourceSyncMaxLimit
+type ResSyncFunc func(kt *kit.Kit, region string, cloudIDs []string) error
+
+// syncOrder 资源同步顺序，与全量同步保持一致，被依赖的资源先同步
+var syncOrder = []enumor.CloudResourceType{
+	enumor.VpcCloudResType,
+	enumor.SubnetCloudResType,
+	enumor.SecurityGroupCloudResType,
+	enumor.DiskCloudResType,
+	enumor.EipCloudResType,
+	enumor.CvmCloudResType,
+	enumor.RouteTableCloudResType,
+	enumor.LoadBalancerCloudResType,
+}
+
+// Syncer 拉取时间窗口内的事件，并调用各资源的同步函数同步涉及的资源
+type Syncer struct {
+	Source    Source
+	Mapper    Mapper
+	SyncFuncs map[enumor.CloudResourceType]ResSyncFunc
+}
+
+// Sync 增量同步 opt 时间窗口内发生变更的资源，部分资源同步失败时会继续同步其余资源并返回错误，调用方应重试该时间窗口
+func (s *Syncer) Sync(kt *kit.Kit, opt *cloudevent.ListOption) (*sync.CloudEventSyncResult, error) {
+	if s.Source == nil || s.Mapper == nil {
+		return nil, errors.New("event source and mapper are required")
+	}
+
+	changes, eventCount, err := s.listChanges(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sync.CloudEventSyncResult{
+		EventCount:  eventCount,
+		SyncedCount: make(map[enumor.CloudResourceType]int),
+	}
+
+	grouped := groupChanges(changes)
+	var firstErr error
+	for _, resType := range syncOrder {
+		regionIDs, exists := grouped[resType]
+		if !exists {
+			continue
+		}
+
+		syncFunc, exists := s.SyncFuncs[resType]
+		if !exists {
+			logs.V(3).Infof("event sync not support %s, skip, rid: %s", resType, kt.Rid)
+			continue
+		}
+
+		for region, cloudIDs := range regionIDs {
+			for _, batch := range slice.Split(cloudIDs, constant.CloudResourceSyncMaxLimit) {
+				if err = syncFunc(kt, region, batch); err != nil {
+					logs.Errorf("event sync %s failed, err: %v, region: %s, cloudIDs: %v, rid: %s", resType, err,
+						region, batch, kt.Rid)
+					if firstErr == nil {
+						firstErr = fmt.Errorf("sync %s failed, err: %v", resType, err)
+					}
+					continue
+				}
+				result.SyncedCount[resType] += len(batch)
+			}
+		}
+	}
+
+	return result, firstErr
+}
+
+func (s *Syncer) listChanges(kt *kit.Kit, opt *cloudevent.ListOption) ([]Change, int, error) {
+	listOpt := *opt
+	changes := make([]Change, 0)
+	eventCount := 0
+	for page := 0; page < maxPageCount; page++ {
+		result, err := s.Source.ListCloudEvent(kt, &listOpt)
+		if err != nil {
+			logs.Errorf("list cloud event failed, err: %v, opt: %+v, rid: %s", err, listOpt, kt.Rid)
+			return nil, 0, err
+		}
+
+		eventCount += len(result.Events)
+		for _, event := range result.Events {
+			if isReadOnlyEvent(event.EventName) {
+				continue
+			}
+			changes = append(changes, s.Mapper(event)...)
+		}
+
+		if len(result.NextToken) == 0 {
+			return changes, eventCount, nil
+		}
+		listOpt.NextToken = result.NextToken
+	}
+
+	return nil, 0, fmt.Errorf("cloud event count exceeds %d pages, please shorten the time window", maxPageCount)
+}
+
+// groupChanges 按资源类型、地域分组并去重，保持事件出现的顺序
+func groupChanges(changes []Change) map[enumor.CloudResourceType]map[string][]string {
+	grouped := make(map[enumor.CloudResourceType]map[string][]string)
+	seen := make(map[Change]struct{}, len(changes))
+	for _, one := range changes {
+		if len(one.CloudID) == 0 || len(one.Region) == 0 {
+			continue
+		}
+		if _, exists := seen[one]; exists {
+			continue
+		}
+		seen[one] = struct{}{}
+
+		if _, exists := grouped[one.ResType]; !exists {
+			grouped[one.ResType] = make(map[string][]string)
+		}
+		grouped[one.ResType][one.Region] = append(grouped[one.ResType][one.Region], one.CloudID)
+	}
+	return grouped
+}
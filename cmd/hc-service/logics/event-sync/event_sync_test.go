@@ -0,0 +1,149 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncCall struct {
+	resType  enumor.CloudResourceType
+	region   string
+	cloudIDs []string
+}
+
+type fakeSyncer struct {
+	calls []syncCall
+	fail  map[enumor.CloudResourceType]bool
+}
+
+func (f *fakeSyncer) funcs(resTypes ...enumor.CloudResourceType) map[enumor.CloudResourceType]ResSyncFunc {
+	funcs := make(map[enumor.CloudResourceType]ResSyncFunc)
+	for _, one := range resTypes {
+		resType := one
+		funcs[resType] = func(kt *kit.Kit, region string, cloudIDs []string) error {
+			f.calls = append(f.calls, syncCall{resType: resType, region: region, cloudIDs: cloudIDs})
+			if f.fail[resType] {
+				return errors.New("mock sync failed")
+			}
+			return nil
+		}
+	}
+	return funcs
+}
+
+func window(start, end string) *cloudevent.ListOption {
+	startTime, _ := time.Parse(time.RFC3339, start)
+	endTime, _ := time.Parse(time.RFC3339, end)
+	return &cloudevent.ListOption{StartTime: startTime, EndTime: endTime}
+}
+
+func TestSyncerSync(t *testing.T) {
+	source, err := NewFileSource(filepath.Join("testdata", "tcloud_events.jsonl"))
+	require.NoError(t, err)
+
+	fake := &fakeSyncer{fail: map[enumor.CloudResourceType]bool{enumor.SubnetCloudResType: true}}
+	syncer := &Syncer{
+		Source: source,
+		Mapper: TCloudMapper,
+		SyncFuncs: fake.funcs(enumor.VpcCloudResType, enumor.SubnetCloudResType, enumor.DiskCloudResType,
+			enumor.CvmCloudResType),
+	}
+
+	// 11:00 的事件不在时间窗口内，Describe 为只读事件，子网同步失败时其余资源继续同步
+	result, err := syncer.Sync(kit.New(), window("2023-06-01T10:00:00Z", "2023-06-01T11:00:00Z"))
+	assert.Error(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 5, result.EventCount)
+
+	expect := []syncCall{
+		{resType: enumor.VpcCloudResType, region: "ap-guangzhou", cloudIDs: []string{"vpc-11112222"}},
+		{resType: enumor.SubnetCloudResType, region: "ap-guangzhou", cloudIDs: []string{"subnet-33334444"}},
+		{resType: enumor.DiskCloudResType, region: "ap-shanghai", cloudIDs: []string{"disk-aaaabbbb"}},
+		{resType: enumor.CvmCloudResType, region: "ap-guangzhou", cloudIDs: []string{"ins-abcd1234", "ins-abcd5678"}},
+	}
+	assert.Equal(t, expect, fake.calls)
+	assert.Equal(t, map[enumor.CloudResourceType]int{
+		enumor.VpcCloudResType:  1,
+		enumor.DiskCloudResType: 1,
+		enumor.CvmCloudResType:  2,
+	}, result.SyncedCount)
+
+	// 指定地域
+	fake.calls = nil
+	opt := window("2023-06-01T10:00:00Z", "2023-06-01T12:00:00Z")
+	opt.Region = "ap-shanghai"
+	result, err = syncer.Sync(kit.New(), opt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.EventCount)
+	assert.Len(t, fake.calls, 1)
+}
+
+func TestSyncerBatch(t *testing.T) {
+	source := new(FileSource)
+	eventTime, _ := time.Parse(time.RFC3339, "2023-06-01T10:00:00Z")
+	total := constant.CloudResourceSyncMaxLimit + 1
+	for i := 0; i < total; i++ {
+		source.events = append(source.events, cloudevent.CloudEvent{
+			EventName:    "ModifyVolume",
+			Region:       "us-east-1",
+			ResourceType: "AWS::EC2::Volume",
+			ResourceIDs:  []string{fmt.Sprintf("vol-%d", i)},
+			EventTime:    eventTime,
+		})
+	}
+
+	fake := new(fakeSyncer)
+	syncer := &Syncer{Source: source, Mapper: AwsMapper, SyncFuncs: fake.funcs(enumor.DiskCloudResType)}
+	result, err := syncer.Sync(kit.New(), window("2023-06-01T00:00:00Z", "2023-06-02T00:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, total, result.SyncedCount[enumor.DiskCloudResType])
+	require.Len(t, fake.calls, 2)
+	assert.Len(t, fake.calls[0].cloudIDs, constant.CloudResourceSyncMaxLimit)
+	assert.Len(t, fake.calls[1].cloudIDs, 1)
+}
+
+func TestMapper(t *testing.T) {
+	aws := AwsMapper(cloudevent.CloudEvent{Region: "us-east-1", ResourceType: "AWS::EC2::Instance",
+		ResourceIDs: []string{"i-0123"}})
+	assert.Equal(t, []Change{{Region: "us-east-1", ResType: enumor.CvmCloudResType, CloudID: "i-0123"}}, aws)
+	assert.Empty(t, AwsMapper(cloudevent.CloudEvent{ResourceType: "AWS::S3::Bucket", ResourceIDs: []string{"b"}}))
+
+	huawei := HuaWeiMapper(cloudevent.CloudEvent{Region: "cn-north-4", ResourceType: "Security_Groups",
+		ResourceIDs: []string{"sg-uuid"}})
+	assert.Equal(t, []Change{{Region: "cn-north-4", ResType: enumor.SecurityGroupCloudResType,
+		CloudID: "sg-uuid"}}, huawei)
+
+	assert.True(t, isReadOnlyEvent("DescribeInstances"))
+	assert.True(t, isReadOnlyEvent("listTraces"))
+	assert.False(t, isReadOnlyEvent("RunInstances"))
+}
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+)
+
+// fileSourcePageSize FileSource 单页返回的事件数量
+const fileSourcePageSize = 2
+
+// FileSource 从文件读取云上事件，文件每行为一个json格式的 cloudevent.CloudEvent，用于测试及本地调试
+type FileSource struct {
+	events []cloudevent.CloudEvent
+}
+
+// NewFileSource new file source.
+func NewFileSource(path string) (*FileSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	source := new(FileSource)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		event := cloudevent.CloudEvent{}
+		if err = json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("unmarshal event at line %d failed, err: %v", line, err)
+		}
+		source.events = append(source.events, event)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// ListCloudEvent 返回时间窗口内的事件，地域不为空时只返回该地域的事件，分页方式与云上接口一致
+func (f *FileSource) ListCloudEvent(_ *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error) {
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	matched := make([]cloudevent.CloudEvent, 0)
+	for _, one := range f.events {
+		if one.EventTime.Before(opt.StartTime) || !one.EventTime.Before(opt.EndTime) {
+			continue
+		}
+		if len(opt.Region) != 0 && one.Region != opt.Region {
+			continue
+		}
+		matched = append(matched, one)
+	}
+
+	offset := 0
+	if len(opt.NextToken) != 0 {
+		var err error
+		if offset, err = strconv.Atoi(opt.NextToken); err != nil {
+			return nil, fmt.Errorf("invalid next token: %s", opt.NextToken)
+		}
+	}
+	if offset >= len(matched) {
+		return new(cloudevent.ListResult), nil
+	}
+
+	end := offset + fileSourcePageSize
+	result := new(cloudevent.ListResult)
+	if end < len(matched) {
+		result.NextToken = strconv.Itoa(end)
+	} else {
+		end = len(matched)
+	}
+	result.Events = matched[offset:end]
+
+	return result, nil
+}
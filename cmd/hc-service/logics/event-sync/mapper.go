@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"regexp"
+	"strings"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/criteria/enumor"
+)
+
+// readOnlyPrefixes 只读事件名前缀，只读事件不会引起资源变更
+var readOnlyPrefixes = []string{"describe", "get", "list", "inquiry", "query", "lookup", "show", "check"}
+
+func isReadOnlyEvent(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tcloudIDPatterns 腾讯云云审计返回的资源名称格式不固定，按资源ID格式从资源名称中提取
+var tcloudIDPatterns = []struct {
+	resType enumor.CloudResourceType
+	pattern *regexp.Regexp
+}{
+	{resType: enumor.CvmCloudResType, pattern: regexp.MustCompile(`\bins-[0-9a-z]{8}\b`)},
+	{resType: enumor.VpcCloudResType, pattern: regexp.MustCompile(`\bvpc-[0-9a-z]{8}\b`)},
+	{resType: enumor.SubnetCloudResType, pattern: regexp.MustCompile(`\bsubnet-[0-9a-z]{8}\b`)},
+	{resType: enumor.SecurityGroupCloudResType, pattern: regexp.MustCompile(`\bsg-[0-9a-z]{8}\b`)},
+	{resType: enumor.EipCloudResType, pattern: regexp.MustCompile(`\beip-[0-9a-z]{8}\b`)},
+	{resType: enumor.DiskCloudResType, pattern: regexp.MustCompile(`\bdisk-[0-9a-z]{8}\b`)},
+	{resType: enumor.RouteTableCloudResType, pattern: regexp.MustCompile(`\brtb-[0-9a-z]{8}\b`)},
+	{resType: enumor.LoadBalancerCloudResType, pattern: regexp.MustCompile(`\blb-[0-9a-z]{8}\b`)},
+}
+
+// TCloudMapper 腾讯云云审计事件映射
+func TCloudMapper(event cloudevent.CloudEvent) []Change {
+	changes := make([]Change, 0)
+	for _, name := range event.ResourceIDs {
+		for _, one := range tcloudIDPatterns {
+			for _, cloudID := range one.pattern.FindAllString(name, -1) {
+				changes = append(changes, Change{Region: event.Region, ResType: one.resType, CloudID: cloudID})
+			}
+		}
+	}
+	return changes
+}
+
+// awsResTypes aws CloudTrail 资源类型映射
+var awsResTypes = map[string]enumor.CloudResourceType{
+	"AWS::EC2::Instance":      enumor.CvmCloudResType,
+	"AWS::EC2::VPC":           enumor.VpcCloudResType,
+	"AWS::EC2::Subnet":        enumor.SubnetCloudResType,
+	"AWS::EC2::SecurityGroup": enumor.SecurityGroupCloudResType,
+	"AWS::EC2::EIP":           enumor.EipCloudResType,
+	"AWS::EC2::Volume":        enumor.DiskCloudResType,
+	"AWS::EC2::RouteTable":    enumor.RouteTableCloudResType,
+}
+
+// AwsMapper aws CloudTrail 事件映射
+func AwsMapper(event cloudevent.CloudEvent) []Change {
+	return mapByResType(awsResTypes, event)
+}
+
+// huaweiResTypes 华为云CTS资源类型映射，子网同步依赖所属vpc，事件中无法获取，由全量同步兜底
+var huaweiResTypes = map[string]enumor.CloudResourceType{
+	"ecs":             enumor.CvmCloudResType,
+	"vpcs":            enumor.VpcCloudResType,
+	"security_groups": enumor.SecurityGroupCloudResType,
+	"publicips":       enumor.EipCloudResType,
+	"volumes":         enumor.DiskCloudResType,
+	"routetables":     enumor.RouteTableCloudResType,
+}
+
+// HuaWeiMapper 华为云CTS事件映射
+func HuaWeiMapper(event cloudevent.CloudEvent) []Change {
+	event.ResourceType = strings.ToLower(event.ResourceType)
+	return mapByResType(huaweiResTypes, event)
+}
+
+func mapByResType(resTypes map[string]enumor.CloudResourceType, event cloudevent.CloudEvent) []Change {
+	resType, exists := resTypes[event.ResourceType]
+	if !exists {
+		return nil
+	}
+
+	changes := make([]Change, 0, len(event.ResourceIDs))
+	for _, cloudID := range event.ResourceIDs {
+		changes = append(changes, Change{Region: event.Region, ResType: resType, CloudID: cloudID})
+	}
+	return changes
+}
@@ -0,0 +1,142 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/huawei"
+	"hcm/cmd/hc-service/logics/res-sync/tcloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// TCloudSyncFuncs 腾讯云各资源按云ID同步的函数
+func TCloudSyncFuncs(accountID string, cli tcloud.Interface) map[enumor.CloudResourceType]ResSyncFunc {
+	params := func(region string, cloudIDs []string) *tcloud.SyncBaseParams {
+		return &tcloud.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+	}
+
+	return map[enumor.CloudResourceType]ResSyncFunc{
+		enumor.VpcCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Vpc(kt, params(region, cloudIDs), new(tcloud.SyncVpcOption))
+			return err
+		},
+		enumor.SubnetCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Subnet(kt, params(region, cloudIDs), new(tcloud.SyncSubnetOption))
+			return err
+		},
+		enumor.SecurityGroupCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.SecurityGroup(kt, params(region, cloudIDs), new(tcloud.SyncSGOption))
+			return err
+		},
+		enumor.DiskCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Disk(kt, params(region, cloudIDs), new(tcloud.SyncDiskOption))
+			return err
+		},
+		enumor.EipCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Eip(kt, params(region, cloudIDs), new(tcloud.SyncEipOption))
+			return err
+		},
+		enumor.CvmCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.CvmWithRelRes(kt, params(region, cloudIDs), new(tcloud.SyncCvmWithRelResOption))
+			return err
+		},
+		enumor.RouteTableCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.RouteTable(kt, params(region, cloudIDs), new(tcloud.SyncRouteTableOption))
+			return err
+		},
+		enumor.LoadBalancerCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.LoadBalancerWithListener(kt, params(region, cloudIDs), new(tcloud.SyncLBOption))
+			return err
+		},
+	}
+}
+
+// AwsSyncFuncs aws各资源按云ID同步的函数
+func AwsSyncFuncs(accountID string, cli aws.Interface) map[enumor.CloudResourceType]ResSyncFunc {
+	params := func(region string, cloudIDs []string) *aws.SyncBaseParams {
+		return &aws.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+	}
+
+	return map[enumor.CloudResourceType]ResSyncFunc{
+		enumor.VpcCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Vpc(kt, params(region, cloudIDs), new(aws.SyncVpcOption))
+			return err
+		},
+		enumor.SubnetCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Subnet(kt, params(region, cloudIDs), new(aws.SyncSubnetOption))
+			return err
+		},
+		enumor.SecurityGroupCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.SecurityGroup(kt, params(region, cloudIDs), new(aws.SyncSGOption))
+			return err
+		},
+		enumor.DiskCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Disk(kt, params(region, cloudIDs), new(aws.SyncDiskOption))
+			return err
+		},
+		enumor.EipCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Eip(kt, params(region, cloudIDs), new(aws.SyncEipOption))
+			return err
+		},
+		enumor.CvmCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.CvmWithRelRes(kt, params(region, cloudIDs), new(aws.SyncCvmWithRelResOption))
+			return err
+		},
+		enumor.RouteTableCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.RouteTable(kt, params(region, cloudIDs), new(aws.SyncRouteTableOption))
+			return err
+		},
+	}
+}
+
+// HuaWeiSyncFuncs 华为云各资源按云ID同步的函数
+func HuaWeiSyncFuncs(accountID string, cli huawei.Interface) map[enumor.CloudResourceType]ResSyncFunc {
+	params := func(region string, cloudIDs []string) *huawei.SyncBaseParams {
+		return &huawei.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+	}
+
+	return map[enumor.CloudResourceType]ResSyncFunc{
+		enumor.VpcCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Vpc(kt, params(region, cloudIDs), new(huawei.SyncVpcOption))
+			return err
+		},
+		enumor.SecurityGroupCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.SecurityGroup(kt, params(region, cloudIDs), new(huawei.SyncSGOption))
+			return err
+		},
+		enumor.DiskCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Disk(kt, params(region, cloudIDs), new(huawei.SyncDiskOption))
+			return err
+		},
+		enumor.EipCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.Eip(kt, params(region, cloudIDs), new(huawei.SyncEipOption))
+			return err
+		},
+		enumor.CvmCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.CvmWithRelRes(kt, params(region, cloudIDs), new(huawei.SyncCvmWithRelResOption))
+			return err
+		},
+		enumor.RouteTableCloudResType: func(kt *kit.Kit, region string, cloudIDs []string) error {
+			_, err := cli.RouteTable(kt, params(region, cloudIDs), new(huawei.SyncRouteTableOption))
+			return err
+		},
+	}
+}
@@ -24,12 +24,12 @@ import (
 	"strings"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/aws"
 	adaptordisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
-	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
@@ -58,47 +58,10 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	diskFromCloud, err := cli.listDiskFromCloud(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	diskFromDB, err := cli.listDiskFromDB(kt, params)
-	if err != nil {
+	if err := cli.diskResSyncer(params.AccountID, params.Region, opt).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-	if opt.BootMap != nil {
-		// 标记启动盘
-		for i, d := range diskFromCloud {
-			_, exists := opt.BootMap[d.GetCloudID()]
-			diskFromCloud[i].Boot = converter.ValToPtr(exists)
-		}
-	}
-	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.AwsDisk, *coredisk.Disk[coredisk.AwsExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
-
-	if len(delCloudIDs) > 0 {
-		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addSlice) > 0 {
-		if err = cli.createDisk(kt, params.AccountID, params.Region, addSlice); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateDisk(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return new(SyncResult), nil
 }
 
@@ -338,101 +301,100 @@ func (cli *client) listDiskFromDB(kt *kit.Kit, params *SyncBaseParams) (
 
 // RemoveDiskDeleteFromCloud ...
 func (cli *client) RemoveDiskDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.ListDisk(kt, req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list disk failed, err: %v, req: %v, rid: %s", enumor.Aws,
-				err, req, kt.Rid)
-			return err
-		}
+	return cli.diskResSyncer(accountID, region, new(SyncDiskOption)).RemoveDeletedFromCloud(kt)
+}
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+// DiskSyncer 基于通用同步器的硬盘全量同步，next 为云上硬盘分页查询函数
+func (cli *client) DiskSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+	rs := cli.diskResSyncer(accountID, region, new(SyncDiskOption))
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		var delCloudIDs []string
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			delCloudIDs, err = cli.listRemoveDiskID(kt, params)
+func (cli *client) diskResSyncer(accountID, region string, opt *SyncDiskOption) *common.ResSyncer[*SyncBaseParams,
+	adaptordisk.AwsDisk, *coredisk.Disk[coredisk.AwsExtension]] {
+
+	return &common.ResSyncer[*SyncBaseParams, adaptordisk.AwsDisk, *coredisk.Disk[coredisk.AwsExtension]]{
+		ResType: enumor.DiskCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.ListDisk(kt, req)
 			if err != nil {
-				return err
+				logs.Errorf("[%s] request dataservice to list disk failed, err: %v, req: %v, rid: %s", enumor.Aws,
+					err, req, kt.Rid)
+				return nil, err
 			}
-		}
 
-		if len(delCloudIDs) != 0 {
-			if err = cli.deleteDisk(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
-
-		req.Page.Start += constant.BatchOperationMaxLimit
+			return cloudIDMap, nil
+		},
+		ListFromCloud: func(kt *kit.Kit, params *SyncBaseParams) ([]adaptordisk.AwsDisk, error) {
+			diskFromCloud, err := cli.listExistDiskFromCloud(kt, params)
+			if err != nil {
+				return nil, err
+			}
+			if opt.BootMap != nil {
+				// 标记启动盘
+				for i, d := range diskFromCloud {
+					_, exists := opt.BootMap[d.GetCloudID()]
+					diskFromCloud[i].Boot = converter.ValToPtr(exists)
+				}
+			}
+			return diskFromCloud, nil
+		},
+		ListFromDB: cli.listDiskFromDB,
+		IsChange:   isDiskChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []adaptordisk.AwsDisk) error {
+			return cli.createDisk(kt, params.AccountID, params.Region, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]adaptordisk.AwsDisk) error {
+			return cli.updateDisk(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []adaptordisk.AwsDisk) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.DiskCloudResType,
+				dataFromCloud)
+		},
 	}
-
-	return nil
 }
 
-func (cli *client) listRemoveDiskID(kt *kit.Kit, params *SyncBaseParams) ([]string, error) {
+// listExistDiskFromCloud 查询云上存在的硬盘，aws 查询不存在的硬盘会报错，需剔除不存在的云ID后重新查询
+func (cli *client) listExistDiskFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]adaptordisk.AwsDisk, error) {
 	if err := params.Validate(); err != nil {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	delCloudIDs := make([]string, 0)
 	cloudIDs := params.CloudIDs
-	for {
+	for len(cloudIDs) > 0 {
 		opt := &adaptordisk.AwsDiskListOption{
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
-		_, _, err := cli.cloudCli.ListDisk(kt, opt)
-		if err != nil {
-			if strings.Contains(err.Error(), aws.ErrDiskNotFound) {
-				var delCloudID string
-				cloudIDs, delCloudID = removeNotFoundCloudID(cloudIDs, err)
-				delCloudIDs = append(delCloudIDs, delCloudID)
-
-				if len(cloudIDs) <= 0 {
-					break
-				}
-
-				continue
-			}
+		result, _, err := cli.cloudCli.ListDisk(kt, opt)
+		if err == nil {
+			return result, nil
+		}
 
+		if !strings.Contains(err.Error(), aws.ErrDiskNotFound) {
 			logs.Errorf("[%s] list disk from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws, err,
 				params.AccountID, opt, kt.Rid)
 			return nil, err
 		}
-
-		break
+		cloudIDs, _ = removeNotFoundCloudID(cloudIDs, err)
 	}
 
-	return delCloudIDs, nil
+	return make([]adaptordisk.AwsDisk, 0), nil
 }
 
 func isDiskChange(cloud adaptordisk.AwsDisk, db *coredisk.Disk[coredisk.AwsExtension]) bool {
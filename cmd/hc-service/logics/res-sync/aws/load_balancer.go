@@ -0,0 +1,486 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// SyncLBOption ...
+type SyncLBOption struct {
+	// BkBizID 负载均衡创建时，通过同步写入DB，需要传入业务ID
+	BkBizID int64 `json:"bk_biz_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt SyncLBOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// LoadBalancerWithListener 同步指定负载均衡及下属资源
+// 1. 同步该负载均衡自身属性
+// 2. 同步该负载均衡关联的目标组及目标
+// 3. 同步该负载均衡下的监听器及监听器下的规则
+func (cli *client) LoadBalancerWithListener(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult,
+	error) {
+
+	if _, err := cli.LoadBalancer(kt, params, opt); err != nil {
+		logs.Errorf("[%s] fail to sync load balancer with rel, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return nil, err
+	}
+
+	lbList, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		logs.Errorf("[%s] fail to get lb from db after lb layer sync, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return nil, err
+	}
+
+	for i := range lbList {
+		// 规则需要关联目标组的本地ID，因此目标组需要先于监听器同步
+		tgMap, err := cli.targetGroupByLb(kt, params.AccountID, params.Region, &lbList[i])
+		if err != nil {
+			logs.Errorf("[%s] fail to sync target group of lb(%s), err: %v, rid: %s", enumor.Aws,
+				lbList[i].CloudID, err, kt.Rid)
+			return nil, err
+		}
+
+		if err = cli.listenerByLb(kt, params.Region, &lbList[i], tgMap); err != nil {
+			logs.Errorf("[%s] fail to sync listener of lb(%s), err: %v, rid: %s", enumor.Aws,
+				lbList[i].CloudID, err, kt.Rid)
+			return nil, err
+		}
+	}
+
+	return new(SyncResult), nil
+}
+
+// LoadBalancer 同步指定负载均衡自身属性，不同步关联资源
+func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult, error) {
+	if err := validator.ValidateTool(params, opt); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbFromCloud, err := cli.listLBFromCloud(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	lbFromDB, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lbFromCloud) == 0 && len(lbFromDB) == 0 {
+		return new(SyncResult), nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.AwsLoadBalancer, corelb.AwsLoadBalancer](
+		lbFromCloud, lbFromDB, isLBChange)
+
+	if err = cli.deleteLoadBalancer(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
+		return nil, err
+	}
+
+	if err = cli.createLoadBalancer(kt, params.AccountID, params.Region, opt.BkBizID, addSlice); err != nil {
+		return nil, err
+	}
+
+	if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
+		return nil, err
+	}
+
+	return new(SyncResult), nil
+}
+
+// RemoveLoadBalancerDeleteFromCloud 删除存在本地但是在云上被删除的数据
+func (cli *client) RemoveLoadBalancerDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("vendor", enumor.Aws),
+		),
+		Page: &core.BasePage{Start: 0, Limit: constant.BatchOperationMaxLimit},
+	}
+
+	for {
+		lbFromDB, err := cli.dbCli.Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] request dataservice to list lb failed, err: %v, req: %v, rid: %s", enumor.Aws,
+				err, req, kt.Rid)
+			return err
+		}
+
+		cloudIDs := slice.Map(lbFromDB.Details, func(lb corelb.BaseLoadBalancer) string { return lb.CloudID })
+		if len(cloudIDs) == 0 {
+			break
+		}
+
+		params := &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		lbFromCloud, err := cli.listLBFromCloud(kt, params)
+		if err != nil {
+			return err
+		}
+
+		// 如果有资源没有查询出来，说明数据被从云上删除
+		if len(lbFromCloud) != len(cloudIDs) {
+			cloudIDMap := cvt.StringSliceToMap(cloudIDs)
+			for _, one := range lbFromCloud {
+				delete(cloudIDMap, one.GetCloudID())
+			}
+
+			delCloudIDs := cvt.MapKeyToStringSlice(cloudIDMap)
+			if err = cli.deleteLoadBalancer(kt, accountID, region, delCloudIDs); err != nil {
+				return err
+			}
+		}
+
+		if len(lbFromDB.Details) < constant.BatchOperationMaxLimit {
+			break
+		}
+
+		req.Page.Start += constant.BatchOperationMaxLimit
+	}
+
+	// 目标组可以不关联负载均衡，需要单独清理云上已删除的目标组
+	return cli.removeTargetGroupDeleteFromCloud(kt, accountID, region)
+}
+
+func (cli *client) createLoadBalancer(kt *kit.Kit, accountID string, region string, bizID int64,
+	addSlice []typeslb.AwsLoadBalancer) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	vpcMap, subnetMap, err := cli.getLoadBalancerRelatedRes(kt, accountID, region, addSlice)
+	if err != nil {
+		return err
+	}
+
+	createReq := new(protocloud.AwsLBCreateReq)
+	for _, one := range addSlice {
+		createReq.Lbs = append(createReq.Lbs, convCloudToDBCreate(one, accountID, region, bizID, vpcMap, subnetMap))
+	}
+
+	if _, err = cli.dbCli.Aws.LoadBalancer.BatchCreateAwsLoadBalancer(kt, createReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch create lb failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to create lb success, accountID: %s, count: %d, rid: %s", enumor.Aws,
+		accountID, len(addSlice), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) updateLoadBalancer(kt *kit.Kit, accountID string, region string,
+	updateMap map[string]typeslb.AwsLoadBalancer) error {
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	lbs := make([]typeslb.AwsLoadBalancer, 0, len(updateMap))
+	for _, one := range updateMap {
+		lbs = append(lbs, one)
+	}
+	vpcMap, subnetMap, err := cli.getLoadBalancerRelatedRes(kt, accountID, region, lbs)
+	if err != nil {
+		return err
+	}
+
+	updateReq := new(protocloud.AwsLBBatchUpdateReq)
+	for id, one := range updateMap {
+		updateReq.Lbs = append(updateReq.Lbs, convCloudToDBUpdate(id, one, vpcMap, subnetMap))
+	}
+
+	if err = cli.dbCli.Aws.LoadBalancer.BatchUpdate(kt, updateReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch update lb failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to update lb success, accountID: %s, count: %d, rid: %s", enumor.Aws,
+		accountID, len(updateMap), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) deleteLoadBalancer(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	checkParams := &SyncBaseParams{
+		AccountID: accountID,
+		Region:    region,
+		CloudIDs:  delCloudIDs,
+	}
+	delLBFromCloud, err := cli.listLBFromCloud(kt, checkParams)
+	if err != nil {
+		return err
+	}
+
+	if len(delLBFromCloud) > 0 {
+		logs.Errorf("[%s] validate lb not exist failed, before delete, opt: %v, failed_count: %d, rid: %s",
+			enumor.Aws, checkParams, len(delLBFromCloud), kt.Rid)
+		return fmt.Errorf("validate lb not exist failed, before delete")
+	}
+
+	deleteReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", delCloudIDs),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("vendor", enumor.Aws),
+		),
+	}
+	if err = cli.dbCli.Global.LoadBalancer.BatchDelete(kt, deleteReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch delete lb failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to delete lb success, accountID: %s, count: %d, rid: %s", enumor.Aws,
+		accountID, len(delCloudIDs), kt.Rid)
+
+	return nil
+}
+
+// getLoadBalancerRelatedRes return vpc map and subnet map of given load balancers
+func (cli *client) getLoadBalancerRelatedRes(kt *kit.Kit, accountID string, region string,
+	lbs []typeslb.AwsLoadBalancer) (map[string]*common.VpcDB, map[string]string, error) {
+
+	cloudVpcIDs := make([]string, 0, len(lbs))
+	cloudSubnetIDs := make([]string, 0, len(lbs))
+	for _, one := range lbs {
+		cloudVpcIDs = append(cloudVpcIDs, cvt.PtrToVal(one.VpcId))
+		cloudSubnetIDs = append(cloudSubnetIDs, one.GetCloudSubnetIDs()...)
+	}
+
+	vpcMap, err := cli.getVpcMap(kt, accountID, region, slice.Unique(cloudVpcIDs))
+	if err != nil {
+		logs.Errorf("[%s] fail to get vpc of lb, err: %v, vpcIDs: %v, rid: %s", enumor.Aws, err, cloudVpcIDs,
+			kt.Rid)
+		return nil, nil, err
+	}
+
+	subnetMap, err := cli.getSubnetMap(kt, accountID, region, slice.Unique(cloudSubnetIDs))
+	if err != nil {
+		logs.Errorf("[%s] fail to get subnet of lb, err: %v, subnetIDs: %v, rid: %s", enumor.Aws, err,
+			cloudSubnetIDs, kt.Rid)
+		return nil, nil, err
+	}
+
+	return vpcMap, subnetMap, nil
+}
+
+func (cli *client) listLBFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]typeslb.AwsLoadBalancer, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result := make([]typeslb.AwsLoadBalancer, 0, len(params.CloudIDs))
+	for _, batch := range slice.Split(params.CloudIDs, typeslb.AwsDescribeLBMax) {
+		opt := &typeslb.AwsListOption{
+			Region:   params.Region,
+			CloudIDs: batch,
+		}
+		lbResult, err := cli.cloudCli.ListLoadBalancer(kt, opt)
+		if err != nil {
+			logs.Errorf("[%s] list lb from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
+				err, params.AccountID, opt, kt.Rid)
+			return nil, err
+		}
+		result = append(result, lbResult.Details...)
+	}
+
+	return result, nil
+}
+
+func (cli *client) listLBFromDB(kt *kit.Kit, params *SyncBaseParams) ([]corelb.AwsLoadBalancer, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", params.AccountID),
+			tools.RuleEqual("region", params.Region),
+			tools.RuleIn("cloud_id", params.CloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := cli.dbCli.Aws.LoadBalancer.ListLoadBalancer(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] list lb from db failed, err: %v, account: %s, req: %v, rid: %s", enumor.Aws, err,
+			params.AccountID, req, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+func convCloudToDBCreate(cloud typeslb.AwsLoadBalancer, accountID string, region string, bizID int64,
+	vpcMap map[string]*common.VpcDB, subnetMap map[string]string) protocloud.AwsLBCreate {
+
+	cloudVpcID := cvt.PtrToVal(cloud.VpcId)
+	cloudSubnetID := firstOrEmpty(cloud.GetCloudSubnetIDs())
+	privateIPv4, publicIPv4, ipv6 := cloud.GetIPAddresses()
+	lb := protocloud.AwsLBCreate{
+		CloudID:              cloud.GetCloudID(),
+		Name:                 cvt.PtrToVal(cloud.LoadBalancerName),
+		Vendor:               enumor.Aws,
+		AccountID:            accountID,
+		BkBizID:              bizID,
+		Region:               region,
+		Zones:                cloud.GetZones(),
+		LoadBalancerType:     string(cloud.GetLoadBalancerType()),
+		IPVersion:            cloud.GetIPVersion(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloudVpcID]).VpcID,
+		CloudVpcID:           cloudVpcID,
+		SubnetID:             subnetMap[cloudSubnetID],
+		CloudSubnetID:        cloudSubnetID,
+		PrivateIPv4Addresses: privateIPv4,
+		PublicIPv4Addresses:  publicIPv4,
+		PublicIPv6Addresses:  ipv6,
+		Domain:               cvt.PtrToVal(cloud.DNSName),
+		Status:               cloud.GetStatus(),
+		Tags:                 cloud.GetTagMap(),
+		Extension:            convAwsLBExtension(cloud),
+	}
+	if cloud.CreatedTime != nil {
+		lb.CloudCreatedTime = times.ConvStdTimeFormat(*cloud.CreatedTime)
+	}
+	if bizID == 0 {
+		lb.BkBizID = constant.UnassignedBiz
+	}
+
+	return lb
+}
+
+func convCloudToDBUpdate(id string, cloud typeslb.AwsLoadBalancer, vpcMap map[string]*common.VpcDB,
+	subnetMap map[string]string) *protocloud.LoadBalancerExtUpdateReq[corelb.AwsLoadBalancerExtension] {
+
+	cloudVpcID := cvt.PtrToVal(cloud.VpcId)
+	cloudSubnetID := firstOrEmpty(cloud.GetCloudSubnetIDs())
+	privateIPv4, publicIPv4, ipv6 := cloud.GetIPAddresses()
+	return &protocloud.LoadBalancerExtUpdateReq[corelb.AwsLoadBalancerExtension]{
+		ID:                   id,
+		Name:                 cvt.PtrToVal(cloud.LoadBalancerName),
+		IPVersion:            cloud.GetIPVersion(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloudVpcID]).VpcID,
+		CloudVpcID:           cloudVpcID,
+		SubnetID:             subnetMap[cloudSubnetID],
+		CloudSubnetID:        cloudSubnetID,
+		PrivateIPv4Addresses: privateIPv4,
+		PublicIPv4Addresses:  publicIPv4,
+		PublicIPv6Addresses:  ipv6,
+		Domain:               cvt.PtrToVal(cloud.DNSName),
+		Status:               cloud.GetStatus(),
+		Tags:                 cloud.GetTagMap(),
+		Extension:            convAwsLBExtension(cloud),
+	}
+}
+
+// convAwsLBExtension 删除保护需要单独调用DescribeLoadBalancerAttributes获取，同步时不覆盖本地值
+func convAwsLBExtension(cloud typeslb.AwsLoadBalancer) *corelb.AwsLoadBalancerExtension {
+	return &corelb.AwsLoadBalancerExtension{
+		Type:                  cloud.Type,
+		Scheme:                cloud.Scheme,
+		IpAddressType:         cloud.IpAddressType,
+		CanonicalHostedZoneID: cloud.CanonicalHostedZoneId,
+		CloudSecurityGroupIDs: cvt.PtrToSlice(cloud.SecurityGroups),
+		CloudSubnetIDs:        cloud.GetCloudSubnetIDs(),
+	}
+}
+
+func isLBChange(cloud typeslb.AwsLoadBalancer, db corelb.AwsLoadBalancer) bool {
+	if db.Name != cvt.PtrToVal(cloud.LoadBalancerName) {
+		return true
+	}
+	if db.Status != cloud.GetStatus() {
+		return true
+	}
+	if db.Domain != cvt.PtrToVal(cloud.DNSName) {
+		return true
+	}
+	if db.IPVersion != cloud.GetIPVersion() {
+		return true
+	}
+	if db.CloudVpcID != cvt.PtrToVal(cloud.VpcId) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Zones, cloud.GetZones()) {
+		return true
+	}
+
+	privateIPv4, publicIPv4, ipv6 := cloud.GetIPAddresses()
+	if !assert.IsStringSliceEqual(db.PrivateIPv4Addresses, privateIPv4) ||
+		!assert.IsStringSliceEqual(db.PublicIPv4Addresses, publicIPv4) ||
+		!assert.IsStringSliceEqual(db.PublicIPv6Addresses, ipv6) {
+		return true
+	}
+
+	if !assert.IsStringMapEqual(db.Tags, cloud.GetTagMap()) {
+		return true
+	}
+
+	if db.Extension == nil {
+		return true
+	}
+	if !assert.IsPtrStringEqual(db.Extension.Scheme, cloud.Scheme) {
+		return true
+	}
+	if !assert.IsPtrStringEqual(db.Extension.IpAddressType, cloud.IpAddressType) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.CloudSecurityGroupIDs, cvt.PtrToSlice(cloud.SecurityGroups)) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.CloudSubnetIDs, cloud.GetCloudSubnetIDs()) {
+		return true
+	}
+
+	return false
+}
+
+func firstOrEmpty(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0]
+}
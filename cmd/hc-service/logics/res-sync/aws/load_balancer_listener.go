@@ -0,0 +1,763 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// -------------------------- Target Group --------------------------
+
+// targetGroupByLb 同步负载均衡关联的目标组及目标组中的目标，返回 云上目标组ID -> 本地目标组ID 的映射
+func (cli *client) targetGroupByLb(kt *kit.Kit, accountID string, region string, lb *corelb.AwsLoadBalancer) (
+	map[string]string, error) {
+
+	listOpt := &typeslb.AwsListTargetGroupOption{Region: region, CloudLoadBalancerID: lb.CloudID}
+	cloudTGs, err := cli.cloudCli.ListTargetGroup(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list target group from cloud failed, err: %v, opt: %+v, rid: %s", enumor.Aws, err,
+			listOpt, kt.Rid)
+		return nil, err
+	}
+	// lambda 类型的目标组没有端口及协议，暂不支持
+	cloudTGs = slice.Filter(cloudTGs, func(tg typeslb.AwsTargetGroup) bool {
+		return cvt.PtrToVal(tg.TargetType) != elbv2.TargetTypeEnumLambda
+	})
+
+	tgMap := make(map[string]string, len(cloudTGs))
+	for _, batch := range slice.Split(cloudTGs, constant.BatchOperationMaxLimit) {
+		cloudIDs := slice.Map(batch, typeslb.AwsTargetGroup.GetCloudID)
+		dbTGs, err := cli.listTargetGroupFromDB(kt, accountID, region, cloudIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		addSlice, updateMap, _ := common.Diff[typeslb.AwsTargetGroup, corelb.AwsTargetGroup](batch, dbTGs,
+			isTargetGroupChange)
+
+		if err = cli.createTargetGroup(kt, accountID, region, lb, addSlice); err != nil {
+			return nil, err
+		}
+		if err = cli.updateTargetGroup(kt, updateMap); err != nil {
+			return nil, err
+		}
+
+		if len(addSlice) > 0 {
+			if dbTGs, err = cli.listTargetGroupFromDB(kt, accountID, region, cloudIDs); err != nil {
+				return nil, err
+			}
+		}
+		for _, tg := range dbTGs {
+			tgMap[tg.CloudID] = tg.ID
+		}
+	}
+
+	for cloudTGID, tgID := range tgMap {
+		if err = cli.target(kt, accountID, region, tgID, cloudTGID); err != nil {
+			return nil, err
+		}
+	}
+
+	return tgMap, nil
+}
+
+// removeTargetGroupDeleteFromCloud 删除本地存在但云上已删除的目标组
+func (cli *client) removeTargetGroupDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	cloudTGs, err := cli.cloudCli.ListTargetGroup(kt, &typeslb.AwsListTargetGroupOption{Region: region})
+	if err != nil {
+		logs.Errorf("[%s] list target group from cloud failed, err: %v, region: %s, rid: %s", enumor.Aws, err,
+			region, kt.Rid)
+		return err
+	}
+	cloudIDMap := cvt.StringSliceToMap(slice.Map(cloudTGs, typeslb.AwsTargetGroup.GetCloudID))
+
+	delIDs := make([]string, 0)
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	for {
+		dbTGs, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list target group from db failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+			return err
+		}
+		for _, tg := range dbTGs.Details {
+			if _, exists := cloudIDMap[tg.CloudID]; !exists {
+				delIDs = append(delIDs, tg.ID)
+			}
+		}
+		if uint(len(dbTGs.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	for _, ids := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &core.ListReq{Filter: tools.ContainersExpression("id", ids), Page: core.NewDefaultBasePage()}
+		if err = cli.dbCli.Global.LoadBalancer.DeleteTargetGroup(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete target group failed, err: %v, ids: %v, rid: %s", enumor.Aws, err, ids, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listTargetGroupFromDB(kt *kit.Kit, accountID string, region string, cloudIDs []string) (
+	[]corelb.AwsTargetGroup, error) {
+
+	if len(cloudIDs) == 0 {
+		return nil, nil
+	}
+
+	result := make([]corelb.AwsTargetGroup, 0, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		// 扩展字段只能通过详情接口获取
+		listReq := &core.ListReq{
+			Fields: []string{"id"},
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", enumor.Aws),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleEqual("region", region),
+				tools.RuleEqual("cloud_id", cloudID),
+			),
+			Page: core.NewDefaultBasePage(),
+		}
+		tgResp, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, listReq)
+		if err != nil {
+			logs.Errorf("[%s] list target group from db failed, err: %v, cloud_id: %s, rid: %s", enumor.Aws, err,
+				cloudID, kt.Rid)
+			return nil, err
+		}
+		if len(tgResp.Details) == 0 {
+			continue
+		}
+
+		tg, err := cli.dbCli.Aws.LoadBalancer.GetTargetGroup(kt, tgResp.Details[0].ID)
+		if err != nil {
+			logs.Errorf("[%s] get target group from db failed, err: %v, id: %s, rid: %s", enumor.Aws, err,
+				tgResp.Details[0].ID, kt.Rid)
+			return nil, err
+		}
+		result = append(result, *tg)
+	}
+
+	return result, nil
+}
+
+func (cli *client) createTargetGroup(kt *kit.Kit, accountID string, region string, lb *corelb.AwsLoadBalancer,
+	addSlice []typeslb.AwsTargetGroup) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	createReq := new(protocloud.AwsTargetGroupCreateReq)
+	for _, tg := range addSlice {
+		one := protocloud.TargetGroupBatchCreate[corelb.AwsTargetGroupExtension]{
+			CloudID:         tg.GetCloudID(),
+			Name:            cvt.PtrToVal(tg.TargetGroupName),
+			Vendor:          enumor.Aws,
+			AccountID:       accountID,
+			BkBizID:         lb.BkBizID,
+			Region:          region,
+			Protocol:        enumor.ProtocolType(cvt.PtrToVal(tg.Protocol)),
+			Port:            cvt.PtrToVal(tg.Port),
+			VpcID:           lb.VpcID,
+			CloudVpcID:      cvt.PtrToVal(tg.VpcId),
+			TargetGroupType: enumor.CloudTargetGroupType,
+			Extension:       convTargetGroupExtension(tg),
+		}
+		createReq.TargetGroups = append(createReq.TargetGroups, one)
+	}
+
+	if _, err := cli.dbCli.Aws.LoadBalancer.BatchCreateAwsTargetGroup(kt, createReq); err != nil {
+		logs.Errorf("[%s] request dataservice to create target group failed, err: %v, rid: %s", enumor.Aws, err,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) updateTargetGroup(kt *kit.Kit, updateMap map[string]typeslb.AwsTargetGroup) error {
+	for id, tg := range updateMap {
+		ext, err := types.NewJsonField(convTargetGroupExtension(tg))
+		if err != nil {
+			return fmt.Errorf("marshal target group(%s) extension failed, err: %v", id, err)
+		}
+		updateReq := &protocloud.TargetGroupUpdateReq{
+			IDs:        []string{id},
+			Name:       cvt.PtrToVal(tg.TargetGroupName),
+			CloudVpcID: cvt.PtrToVal(tg.VpcId),
+			Protocol:   enumor.ProtocolType(cvt.PtrToVal(tg.Protocol)),
+			Port:       cvt.PtrToVal(tg.Port),
+			Extension:  ext,
+		}
+		if err := cli.dbCli.Aws.LoadBalancer.BatchUpdateAwsTargetGroup(kt, updateReq); err != nil {
+			logs.Errorf("[%s] request dataservice to update target group failed, err: %v, id: %s, rid: %s",
+				enumor.Aws, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convTargetGroupExtension(tg typeslb.AwsTargetGroup) *corelb.AwsTargetGroupExtension {
+	return &corelb.AwsTargetGroupExtension{
+		TargetType:           tg.TargetType,
+		ProtocolVersion:      tg.ProtocolVersion,
+		IpAddressType:        tg.IpAddressType,
+		HealthCheck:          tg.GetHealthCheck(),
+		CloudLoadBalancerIDs: cvt.PtrToSlice(tg.LoadBalancerArns),
+	}
+}
+
+func isTargetGroupChange(cloud typeslb.AwsTargetGroup, db corelb.AwsTargetGroup) bool {
+	if db.Name != cvt.PtrToVal(cloud.TargetGroupName) {
+		return true
+	}
+	if db.Protocol != enumor.ProtocolType(cvt.PtrToVal(cloud.Protocol)) {
+		return true
+	}
+	if db.Port != cvt.PtrToVal(cloud.Port) {
+		return true
+	}
+	if db.CloudVpcID != cvt.PtrToVal(cloud.VpcId) {
+		return true
+	}
+	if db.Extension == nil {
+		return true
+	}
+	return isHealthCheckChange(cloud.GetHealthCheck(), db.Extension.HealthCheck)
+}
+
+func isHealthCheckChange(cloud, db *corelb.AwsHealthCheckInfo) bool {
+	if cloud == nil || db == nil {
+		return cloud != db
+	}
+	if !assert.IsPtrBoolEqual(cloud.Enabled, db.Enabled) ||
+		!assert.IsPtrStringEqual(cloud.Protocol, db.Protocol) ||
+		!assert.IsPtrStringEqual(cloud.Port, db.Port) ||
+		!assert.IsPtrStringEqual(cloud.Path, db.Path) ||
+		!assert.IsPtrStringEqual(cloud.Matcher, db.Matcher) {
+		return true
+	}
+	return !assert.IsPtrInt64Equal(cloud.IntervalSeconds, db.IntervalSeconds) ||
+		!assert.IsPtrInt64Equal(cloud.TimeoutSeconds, db.TimeoutSeconds) ||
+		!assert.IsPtrInt64Equal(cloud.HealthyThreshold, db.HealthyThreshold) ||
+		!assert.IsPtrInt64Equal(cloud.UnhealthyThreshold, db.UnhealthyThreshold)
+}
+
+// -------------------------- Target --------------------------
+
+// TargetGroupTarget 同步单个目标组中的目标，用于注册/解绑目标后刷新本地数据
+func (cli *client) TargetGroupTarget(kt *kit.Kit, accountID string, region string, tgID string,
+	cloudTGID string) error {
+
+	return cli.target(kt, accountID, region, tgID, cloudTGID)
+}
+
+// target 同步目标组中的目标，aws 目标没有独立ID，以 {实例ID或IP}:{端口} 作为对比标识
+func (cli *client) target(kt *kit.Kit, accountID string, region string, tgID string, cloudTGID string) error {
+	listOpt := &typeslb.AwsListTargetHealthOption{Region: region, CloudTargetGroupID: cloudTGID}
+	cloudTargets, err := cli.cloudCli.ListTargetHealth(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list target from cloud failed, err: %v, opt: %+v, rid: %s", enumor.Aws, err, listOpt,
+			kt.Rid)
+		return err
+	}
+
+	dbTargets, err := cli.listTargetFromDB(kt, tgID)
+	if err != nil {
+		return err
+	}
+
+	dbMap := make(map[string]corelb.BaseTarget, len(dbTargets))
+	for _, one := range dbTargets {
+		dbMap[awsTargetKey(one)] = one
+	}
+
+	addTargets := make([]*protocloud.TargetBaseReq, 0)
+	for _, one := range cloudTargets {
+		if one.Target == nil {
+			continue
+		}
+		if _, exists := dbMap[one.GetCloudID()]; exists {
+			delete(dbMap, one.GetCloudID())
+			continue
+		}
+		addTargets = append(addTargets, convAwsTarget(one, accountID, region, tgID, cloudTGID))
+	}
+
+	delIDs := make([]string, 0, len(dbMap))
+	for _, one := range dbMap {
+		delIDs = append(delIDs, one.ID)
+	}
+
+	for _, ids := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &protocloud.LoadBalancerBatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+		if err = cli.dbCli.Global.LoadBalancer.BatchDeleteTarget(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete target failed, err: %v, ids: %v, rid: %s", enumor.Aws, err, ids, kt.Rid)
+			return err
+		}
+	}
+
+	for _, targets := range slice.Split(addTargets, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.TargetBatchCreateReq{Targets: targets}
+		if _, err = cli.dbCli.Global.LoadBalancer.BatchCreateTCloudTarget(kt, createReq); err != nil {
+			logs.Errorf("[%s] create target failed, err: %v, tg: %s, rid: %s", enumor.Aws, err, tgID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listTargetFromDB(kt *kit.Kit, tgID string) ([]corelb.BaseTarget, error) {
+	result := make([]corelb.BaseTarget, 0)
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("target_group_id", tgID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.dbCli.Global.LoadBalancer.ListTarget(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list target from db failed, err: %v, tg: %s, rid: %s", enumor.Aws, err, tgID, kt.Rid)
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return result, nil
+}
+
+func convAwsTarget(cloud typeslb.AwsTarget, accountID string, region string, tgID string,
+	cloudTGID string) *protocloud.TargetBaseReq {
+
+	target := &protocloud.TargetBaseReq{
+		Port:               cvt.PtrToVal(cloud.Target.Port),
+		Weight:             cvt.ValToPtr(int64(1)),
+		AccountID:          accountID,
+		TargetGroupID:      tgID,
+		CloudTargetGroupID: cloudTGID,
+		TargetGroupRegion:  region,
+		Zone:               cvt.PtrToVal(cloud.Target.AvailabilityZone),
+	}
+	// instance 类型目标的ID为实例ID，ip 类型目标的ID为IP地址
+	if isAwsInstanceID(cvt.PtrToVal(cloud.Target.Id)) {
+		target.InstType = enumor.CvmInstType
+		target.CloudInstID = cvt.PtrToVal(cloud.Target.Id)
+	} else {
+		target.InstType = enumor.EniInstType
+		target.IP = cvt.PtrToVal(cloud.Target.Id)
+	}
+
+	return target
+}
+
+func awsTargetKey(target corelb.BaseTarget) string {
+	id := target.CloudInstID
+	if len(id) == 0 {
+		id = target.IP
+	}
+	return fmt.Sprintf("%s:%d", id, target.Port)
+}
+
+func isAwsInstanceID(id string) bool {
+	return len(id) > 2 && id[:2] == "i-"
+}
+
+// -------------------------- Listener --------------------------
+
+// listenerByLb 同步负载均衡下的监听器及监听器下的规则
+func (cli *client) listenerByLb(kt *kit.Kit, region string, lb *corelb.AwsLoadBalancer,
+	tgMap map[string]string) error {
+
+	listOpt := &typeslb.AwsListListenersOption{Region: region, CloudLoadBalancerID: lb.CloudID}
+	cloudListeners, err := cli.cloudCli.ListListener(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list listener from cloud failed, err: %v, opt: %+v, rid: %s", enumor.Aws, err,
+			listOpt, kt.Rid)
+		return err
+	}
+
+	dbListeners, err := cli.listListenerFromDB(kt, lb.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(cloudListeners) == 0 && len(dbListeners) == 0 {
+		return nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.AwsListener, corelb.AwsListener](cloudListeners,
+		dbListeners, isListenerChange)
+
+	if err = cli.deleteListener(kt, region, delCloudIDs); err != nil {
+		return err
+	}
+	if err = cli.createListener(kt, region, lb, addSlice); err != nil {
+		return err
+	}
+	if err = cli.updateListener(kt, region, lb.BkBizID, updateMap); err != nil {
+		return err
+	}
+
+	if dbListeners, err = cli.listListenerFromDB(kt, lb.ID); err != nil {
+		return err
+	}
+	for i := range dbListeners {
+		if err = cli.listenerRule(kt, region, lb, &dbListeners[i], tgMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listListenerFromDB(kt *kit.Kit, lbID string) ([]corelb.AwsListener, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lb_id", lbID),
+			tools.RuleEqual("vendor", enumor.Aws),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	resp, err := cli.dbCli.Aws.LoadBalancer.ListListener(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] list listener of lb(%s) from db failed, err: %v, rid: %s", enumor.Aws, lbID, err,
+			kt.Rid)
+		return nil, err
+	}
+
+	return resp.Details, nil
+}
+
+func (cli *client) deleteListener(kt *kit.Kit, region string, cloudIDs []string) error {
+	if len(cloudIDs) == 0 {
+		return nil
+	}
+
+	delReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", cloudIDs),
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("region", region),
+		),
+	}
+	if err := cli.dbCli.Global.LoadBalancer.DeleteListener(kt, delReq); err != nil {
+		logs.Errorf("[%s] delete listener failed, err: %v, cloud_ids: %v, rid: %s", enumor.Aws, err, cloudIDs,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) createListener(kt *kit.Kit, region string, lb *corelb.AwsLoadBalancer,
+	addSlice []typeslb.AwsListener) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	createReq := new(protocloud.AwsListenerBatchCreateReq)
+	for _, lbl := range addSlice {
+		createReq.Listeners = append(createReq.Listeners, protocloud.ListenersCreateReq[corelb.AwsListenerExtension]{
+			CloudID:   lbl.GetCloudID(),
+			Name:      lbl.GetName(),
+			Vendor:    enumor.Aws,
+			AccountID: lb.AccountID,
+			BkBizID:   lb.BkBizID,
+			LbID:      lb.ID,
+			CloudLbID: lb.CloudID,
+			Protocol:  lbl.GetProtocol(),
+			Port:      cvt.PtrToVal(lbl.Port),
+			Region:    region,
+			Extension: convListenerExtension(lbl),
+		})
+	}
+
+	if _, err := cli.dbCli.Aws.LoadBalancer.BatchCreateAwsListener(kt, createReq); err != nil {
+		logs.Errorf("[%s] create listener failed, err: %v, lb: %s, rid: %s", enumor.Aws, err, lb.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) updateListener(kt *kit.Kit, region string, bizID int64,
+	updateMap map[string]typeslb.AwsListener) error {
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	updateReq := new(protocloud.AwsListenerUpdateReq)
+	for id, lbl := range updateMap {
+		updateReq.Listeners = append(updateReq.Listeners, &protocloud.ListenerUpdateReq[corelb.AwsListenerExtension]{
+			ID:        id,
+			Name:      lbl.GetName(),
+			BkBizID:   bizID,
+			Region:    region,
+			Extension: convListenerExtension(lbl),
+		})
+	}
+
+	if err := cli.dbCli.Aws.LoadBalancer.BatchUpdateAwsListener(kt, updateReq); err != nil {
+		logs.Errorf("[%s] update listener failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func convListenerExtension(lbl typeslb.AwsListener) *corelb.AwsListenerExtension {
+	ext := &corelb.AwsListenerExtension{
+		SslPolicy:    lbl.SslPolicy,
+		CertCloudIDs: lbl.GetCertCloudIDs(),
+		AlpnPolicy:   cvt.PtrToSlice(lbl.AlpnPolicy),
+	}
+	if actionType := lbl.GetDefaultActionType(); len(actionType) != 0 {
+		ext.DefaultActionType = cvt.ValToPtr(actionType)
+	}
+	return ext
+}
+
+func isListenerChange(cloud typeslb.AwsListener, db corelb.AwsListener) bool {
+	if db.Protocol != cloud.GetProtocol() {
+		return true
+	}
+	if db.Port != cvt.PtrToVal(cloud.Port) {
+		return true
+	}
+	if db.Extension == nil {
+		return true
+	}
+	if !assert.IsPtrStringEqual(db.Extension.SslPolicy, cloud.SslPolicy) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.CertCloudIDs, cloud.GetCertCloudIDs()) {
+		return true
+	}
+	if cvt.PtrToVal(db.Extension.DefaultActionType) != cloud.GetDefaultActionType() {
+		return true
+	}
+	return false
+}
+
+// -------------------------- Rule --------------------------
+
+// listenerRule 同步监听器下的规则，默认规则作为四层规则记录监听器默认转发的目标组，其余规则作为七层规则
+func (cli *client) listenerRule(kt *kit.Kit, region string, lb *corelb.AwsLoadBalancer, lbl *corelb.AwsListener,
+	tgMap map[string]string) error {
+
+	listOpt := &typeslb.AwsListRulesOption{Region: region, CloudListenerID: lbl.CloudID}
+	cloudRules, err := cli.cloudCli.ListRule(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list rule from cloud failed, err: %v, opt: %+v, rid: %s", enumor.Aws, err, listOpt,
+			kt.Rid)
+		return err
+	}
+
+	dbRules, err := cli.listRuleFromDB(kt, lbl.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(cloudRules) == 0 && len(dbRules) == 0 {
+		return nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.AwsRule, corelb.TCloudLbUrlRule](cloudRules, dbRules,
+		isRuleChange)
+
+	if err = cli.deleteRule(kt, region, delCloudIDs); err != nil {
+		return err
+	}
+	if err = cli.createRule(kt, region, lb, lbl, tgMap, addSlice); err != nil {
+		return err
+	}
+	if err = cli.updateRule(kt, tgMap, updateMap); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) listRuleFromDB(kt *kit.Kit, lblID string) ([]corelb.TCloudLbUrlRule, error) {
+	result := make([]corelb.TCloudLbUrlRule, 0)
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("lbl_id", lblID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.dbCli.Aws.LoadBalancer.ListUrlRule(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list rule of listener(%s) from db failed, err: %v, rid: %s", enumor.Aws, lblID, err,
+				kt.Rid)
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return result, nil
+}
+
+func (cli *client) deleteRule(kt *kit.Kit, region string, cloudIDs []string) error {
+	for _, ids := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		delReq := &protocloud.LoadBalancerBatchDeleteReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleIn("cloud_id", ids),
+				tools.RuleEqual("region", region),
+			),
+		}
+		if err := cli.dbCli.Aws.LoadBalancer.BatchDeleteUrlRule(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete rule failed, err: %v, cloud_ids: %v, rid: %s", enumor.Aws, err, ids, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) createRule(kt *kit.Kit, region string, lb *corelb.AwsLoadBalancer, lbl *corelb.AwsListener,
+	tgMap map[string]string, addSlice []typeslb.AwsRule) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	rules := make([]protocloud.TCloudUrlRuleCreate, 0, len(addSlice))
+	for _, rule := range addSlice {
+		cloudTGID := rule.GetTargetGroupCloudID()
+		rules = append(rules, protocloud.TCloudUrlRuleCreate{
+			Vendor:             enumor.Aws,
+			LbID:               lb.ID,
+			CloudLbID:          lb.CloudID,
+			LblID:              lbl.ID,
+			CloudLBLID:         lbl.CloudID,
+			CloudID:            rule.GetCloudID(),
+			RuleType:           getAwsRuleType(rule),
+			TargetGroupID:      tgMap[cloudTGID],
+			CloudTargetGroupID: cloudTGID,
+			Region:             region,
+			Domain:             rule.GetDomain(),
+			URL:                rule.GetURL(),
+			HealthCheck:        new(corelb.TCloudHealthCheckInfo),
+			Certificate:        new(corelb.TCloudCertificateInfo),
+		})
+	}
+
+	for _, batch := range slice.Split(rules, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.TCloudUrlRuleBatchCreateReq{UrlRules: batch}
+		if _, err := cli.dbCli.Aws.LoadBalancer.BatchCreateUrlRule(kt, createReq); err != nil {
+			logs.Errorf("[%s] create rule failed, err: %v, listener: %s, rid: %s", enumor.Aws, err, lbl.CloudID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) updateRule(kt *kit.Kit, tgMap map[string]string, updateMap map[string]typeslb.AwsRule) error {
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	rules := make([]*protocloud.TCloudUrlRuleUpdate, 0, len(updateMap))
+	for id, rule := range updateMap {
+		cloudTGID := rule.GetTargetGroupCloudID()
+		rules = append(rules, &protocloud.TCloudUrlRuleUpdate{
+			ID:                 id,
+			TargetGroupID:      tgMap[cloudTGID],
+			CloudTargetGroupID: cloudTGID,
+			Domain:             rule.GetDomain(),
+			URL:                rule.GetURL(),
+		})
+	}
+
+	for _, batch := range slice.Split(rules, constant.BatchOperationMaxLimit) {
+		updateReq := &protocloud.TCloudUrlRuleBatchUpdateReq{UrlRules: batch}
+		if err := cli.dbCli.Aws.LoadBalancer.BatchUpdateUrlRule(kt, updateReq); err != nil {
+			logs.Errorf("[%s] update rule failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getAwsRuleType(rule typeslb.AwsRule) enumor.RuleType {
+	if rule.IsDefault() {
+		return enumor.Layer4RuleType
+	}
+	return enumor.Layer7RuleType
+}
+
+func isRuleChange(cloud typeslb.AwsRule, db corelb.TCloudLbUrlRule) bool {
+	if db.RuleType != getAwsRuleType(cloud) {
+		return true
+	}
+	if db.Domain != cloud.GetDomain() {
+		return true
+	}
+	if db.URL != cloud.GetURL() {
+		return true
+	}
+	if db.CloudTargetGroupID != cloud.GetTargetGroupCloudID() {
+		return true
+	}
+	return false
+}
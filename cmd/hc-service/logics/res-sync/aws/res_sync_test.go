@@ -0,0 +1,443 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/cmd/hc-service/logics/res-sync/mock"
+	logicsync "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/adaptor/aws"
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccountID = "account-1"
+	testRegion    = "us-east-1"
+	// testVpcCloudID 子网所属vpc的云ID，创建子网时需要从db查询到该vpc
+	testVpcCloudID = "vpc-base"
+)
+
+// fakeEC2 内存中的aws ec2资源，key为资源云ID，value为资源状态，状态变化时资源需要更新到db。
+// 按 ec2 query 协议响应 DescribeVpcs、DescribeSubnets、DescribeVolumes、DescribeAddresses 请求，
+// 与aws一致，按ID查询vpc、子网、硬盘时，只要有一个ID不存在就返回 NotFound 错误。
+type fakeEC2 struct {
+	lock    sync.Mutex
+	vpcs    map[string]string
+	subnets map[string]string
+	disks   map[string]string
+	eips    map[string]string
+}
+
+func newFakeEC2() *fakeEC2 {
+	return &fakeEC2{
+		vpcs:    make(map[string]string),
+		subnets: make(map[string]string),
+		disks:   make(map[string]string),
+		eips:    make(map[string]string),
+	}
+}
+
+// RoundTrip ...
+func (c *fakeEC2) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	action := form.Get("Action")
+	switch action {
+	case "DescribeVpcs":
+		return c.describe(action, form, "VpcId.", c.vpcs, aws.ErrVpcNotFound, "The vpc ID '%s' does not exist",
+			"vpcSet", func(cloudID, state string) string {
+				return fmt.Sprintf("<vpcId>%s</vpcId><state>available</state>%s", cloudID, nameTag(state))
+			})
+	case "DescribeSubnets":
+		return c.describe(action, form, "SubnetId.", c.subnets, aws.ErrSubnetNotFound,
+			"The subnet ID '%s' does not exist", "subnetSet", func(cloudID, state string) string {
+				return fmt.Sprintf("<subnetId>%s</subnetId><vpcId>%s</vpcId><availabilityZone>%sa</availabilityZone>%s",
+					cloudID, testVpcCloudID, testRegion, nameTag(state))
+			})
+	case "DescribeVolumes":
+		return c.describe(action, form, "VolumeId.", c.disks, aws.ErrDiskNotFound,
+			"The volume '%s' does not exist.", "volumeSet", func(cloudID, state string) string {
+				return fmt.Sprintf("<volumeId>%s</volumeId><status>%s</status><availabilityZone>%sa</availabilityZone>",
+					cloudID, state, testRegion)
+			})
+	case "DescribeAddresses":
+		// 按 allocation-id 过滤，不存在的ID不报错
+		return c.describe(action, form, "Filter.1.Value.", c.eips, "", "", "addressesSet",
+			func(cloudID, state string) string {
+				// aws的eip没有状态，绑定实例时为绑定状态
+				instance := ""
+				if state != "v1" {
+					instance = "<instanceId>i-1</instanceId>"
+				}
+				return fmt.Sprintf("<allocationId>%s</allocationId><publicIp>1.1.1.1</publicIp>%s", cloudID, instance)
+			})
+	default:
+		return response(http.StatusBadRequest, errorXML("InvalidAction", action+" is not supported")), nil
+	}
+}
+
+func (c *fakeEC2) describe(action string, form url.Values, idPrefix string, resources map[string]string,
+	notFoundCode, notFoundMsg, setName string, item func(cloudID, state string) string) (*http.Response, error) {
+
+	cloudIDs := make([]string, 0)
+	for key, values := range form {
+		if strings.HasPrefix(key, idPrefix) {
+			cloudIDs = append(cloudIDs, values...)
+		}
+	}
+	sort.Strings(cloudIDs)
+
+	items := new(strings.Builder)
+	for _, cloudID := range cloudIDs {
+		state, exist := resources[cloudID]
+		if !exist {
+			if notFoundCode == "" {
+				continue
+			}
+			return response(http.StatusBadRequest, errorXML(notFoundCode, fmt.Sprintf(notFoundMsg, cloudID))), nil
+		}
+		items.WriteString("<item>" + item(cloudID, state) + "</item>")
+	}
+
+	return response(http.StatusOK, fmt.Sprintf(`<%sResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">`+
+		`<requestId>req</requestId><%s>%s</%s></%sResponse>`, action, setName, items, setName, action)), nil
+}
+
+// nameTag hcm 使用 ImportMode 标签作为aws资源的名称
+func nameTag(name string) string {
+	return fmt.Sprintf("<tagSet><item><key>ImportMode</key><value>%s</value></item></tagSet>", name)
+}
+
+func errorXML(code, msg string) string {
+	return fmt.Sprintf("<Response><Errors><Error><Code>%s</Code><Message>%s</Message></Error></Errors>"+
+		"<RequestID>req</RequestID></Response>", code, msg)
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// resCase 同步一种资源需要的测试数据和同步方法
+type resCase struct {
+	name  string
+	table string
+	// resources 云上该资源的数据
+	resources func(c *fakeEC2) map[string]string
+	// batch 按云ID同步
+	batch func(kt *kit.Kit, cli *client, params *SyncBaseParams) error
+	// remove 删除云上已删除的数据
+	remove func(kt *kit.Kit, cli *client) error
+	// syncer 全量同步器
+	syncer func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
+	// prepare 预置同步依赖的db数据，可为空
+	prepare func(ds *mock.DataService)
+}
+
+var resCases = []resCase{
+	{
+		name:      "vpc",
+		table:     "vpcs",
+		resources: func(c *fakeEC2) map[string]string { return c.vpcs },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Vpc(kt, params, new(SyncVpcOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveVpcDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.VpcSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+	{
+		name:      "subnet",
+		table:     "subnets",
+		resources: func(c *fakeEC2) map[string]string { return c.subnets },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Subnet(kt, params, new(SyncSubnetOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveSubnetDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.SubnetSyncer(testAccountID, testRegion, next, opt)
+		},
+		prepare: func(ds *mock.DataService) {
+			// 创建子网时需要从db查询所属vpc
+			ds.Insert("vpcs", map[string]any{"id": "base", "cloud_id": testVpcCloudID, "account_id": testAccountID,
+				"region": testRegion, "extension": map[string]any{}})
+		},
+	},
+	{
+		name:      "disk",
+		table:     "disks",
+		resources: func(c *fakeEC2) map[string]string { return c.disks },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Disk(kt, params, new(SyncDiskOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveDiskDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.DiskSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+	{
+		name:      "eip",
+		table:     "eips",
+		resources: func(c *fakeEC2) map[string]string { return c.eips },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Eip(kt, params, new(SyncEipOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveEipDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.EipSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+}
+
+// newTestClient 返回使用内存ec2资源和内存 data-service 的同步客户端，aws sdk 默认使用 http.DefaultClient，
+// 测试期间将其替换为内存ec2
+func newTestClient(t *testing.T, rc resCase) (*client, *fakeEC2, *mock.DataService) {
+	ds := mock.NewDataService()
+	t.Cleanup(ds.Close)
+	if rc.prepare != nil {
+		rc.prepare(ds)
+	}
+
+	// 设置了自定义CA时，aws sdk 要求 transport 为 *http.Transport
+	t.Setenv("AWS_CA_BUNDLE", "")
+	ec2 := newFakeEC2()
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = ec2
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+
+	cloudCli, err := aws.NewAws(&types.BaseSecret{CloudSecretID: "id", CloudSecretKey: "key"}, "cloud-account")
+	require.NoError(t, err)
+
+	return &client{accountID: testAccountID, cloudCli: cloudCli, dbCli: ds.Client()}, ec2, ds
+}
+
+// allCloudIDs 一次返回云上全部资源云ID的分页函数
+func allCloudIDs(resources map[string]string) common.NextFromCloudFunc {
+	done := false
+	return func(_ *kit.Kit) ([]string, error) {
+		if done {
+			return nil, nil
+		}
+		done = true
+
+		cloudIDs := make([]string, 0, len(resources))
+		for cloudID := range resources {
+			cloudIDs = append(cloudIDs, cloudID)
+		}
+		sort.Strings(cloudIDs)
+		return cloudIDs, nil
+	}
+}
+
+// insertDB 向db中写入属于测试账号、地域的数据
+func insertDB(ds *mock.DataService, table string, cloudIDs ...string) {
+	for _, cloudID := range cloudIDs {
+		ds.Insert(table, map[string]any{"id": "db-" + cloudID, "cloud_id": cloudID, "account_id": testAccountID,
+			"region": testRegion, "extension": map[string]any{}})
+	}
+}
+
+// tableCloudIDs 返回db中的资源云ID，按云ID排序
+func tableCloudIDs(ds *mock.DataService, table string) []string {
+	result := ds.CloudIDs(table)
+	sort.Strings(result)
+	return result
+}
+
+func TestSyncEmpty(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, ec2, ds := newTestClient(t, rc)
+			kt := kit.New()
+
+			params := &SyncBaseParams{AccountID: testAccountID, Region: testRegion, CloudIDs: []string{"not-exist"}}
+			require.NoError(t, rc.batch(kt, cli, params))
+
+			_, err := rc.syncer(cli, allCloudIDs(rc.resources(ec2)), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+
+			assert.Empty(t, ds.Writes(), "云上和db都没有数据时不应写db，也不应同步标签")
+		})
+	}
+}
+
+func TestSyncAllPages(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, ec2, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(ec2)
+			for _, cloudID := range []string{"a", "b", "c"} {
+				resources[cloudID] = "v1"
+			}
+
+			_, err := rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "create", Table: rc.table, CloudIDs: []string{"a", "b", "c"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "c"}},
+			}, ds.Writes())
+
+			// 云上数据没有变化时，再次同步不写db
+			ds.Reset()
+			_, err = rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "c"}},
+			}, ds.Writes())
+
+			// 先删除、再创建、最后更新
+			ds.Reset()
+			resources["b"] = "v2"
+			delete(resources, "c")
+			resources["d"] = "v1"
+			_, err = rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"c"}},
+				{Op: "create", Table: rc.table, CloudIDs: []string{"d"}},
+				{Op: "update", Table: rc.table, CloudIDs: []string{"b"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "d"}},
+			}, ds.Writes())
+			assert.Equal(t, []string{"a", "b", "d"}, tableCloudIDs(ds, rc.table))
+		})
+	}
+}
+
+func TestSyncBatch(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, ec2, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(ec2)
+			resources["a"] = "v2"
+			resources["b"] = "v1"
+			insertDB(ds, rc.table, "a", "c", "d")
+
+			// 按云ID同步只处理指定的资源，云上已删除的数据全部删除，不受删除比例阈值限制
+			params := &SyncBaseParams{AccountID: testAccountID, Region: testRegion,
+				CloudIDs: []string{"a", "b", "c", "d"}}
+			require.NoError(t, rc.batch(kt, cli, params))
+
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"c", "d"}},
+				{Op: "create", Table: rc.table, CloudIDs: []string{"b"}},
+				{Op: "update", Table: rc.table, CloudIDs: []string{"a"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b"}},
+			}, ds.Writes())
+		})
+	}
+}
+
+func TestRemoveDeleteFromCloud(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, ec2, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(ec2)
+
+			// db数据超过一页，云上删除了其中3条
+			cloudIDs := make([]string, 0)
+			for i := 0; i < 150; i++ {
+				cloudID := fmt.Sprintf("res-%03d", i)
+				cloudIDs = append(cloudIDs, cloudID)
+				if i != 1 && i != 99 && i != 149 {
+					resources[cloudID] = "v1"
+				}
+			}
+			insertDB(ds, rc.table, cloudIDs...)
+
+			require.NoError(t, rc.remove(kt, cli))
+
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"res-001", "res-099"}},
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"res-149"}},
+			}, ds.Writes())
+			assert.Len(t, tableCloudIDs(ds, rc.table), 147)
+
+			// 按账号、地域分页查询db数据时只查询id和云ID
+			pages := make([]uint32, 0)
+			for _, one := range ds.Lists() {
+				if one.Vendor == "" && one.Table == rc.table {
+					assert.Equal(t, []string{"id", "cloud_id"}, one.Fields)
+					pages = append(pages, one.Start)
+				}
+			}
+			assert.Equal(t, []uint32{0, 100}, pages)
+		})
+	}
+}
+
+func TestSyncDeleteThreshold(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, ec2, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(ec2)
+			resources["a"] = "v1"
+			insertDB(ds, rc.table, "a", "b", "c", "d")
+
+			_, err := rc.syncer(cli, allCloudIDs(resources), logicsync.Option{DeleteThreshold: 50}).AllPages(kt)
+			require.Error(t, err)
+
+			assert.Empty(t, ds.Writes(), "删除比例超过阈值时不应写db")
+			assert.Equal(t, []string{"a", "b", "c", "d"}, tableCloudIDs(ds, rc.table))
+		})
+	}
+}
@@ -115,6 +115,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.SecurityGroupCloudResType,
+		sgFromCloud); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/aws"
 	adcore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/subnet"
@@ -58,41 +59,10 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params)
-	if err != nil {
+	if err := cli.subnetResSyncer(params.AccountID, params.Region).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	subnetFromDB, err := cli.listSubnetFromDB(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AwsSubnet, cloudcore.Subnet[cloudcore.AwsSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isAwsSubnetChange)
-
-	if len(delCloudIDs) > 0 {
-		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addSubnet) > 0 {
-		if err = cli.createSubnet(kt, params.AccountID, params.Region, addSubnet); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return nil, nil
 }
 
@@ -299,102 +269,87 @@ func isAwsSubnetChange(item adtysubnet.AwsSubnet, info cloudcore.Subnet[cloudcor
 
 // RemoveSubnetDeleteFromCloud ...
 func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	return cli.subnetResSyncer(accountID, region).RemoveDeletedFromCloud(kt)
+}
 
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.Subnet.List(kt.Ctx, kt.Header(), req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list subnet failed, err: %v, req: %v, rid: %s", enumor.Aws,
-				err, req, kt.Rid)
-			return err
-		}
+// SubnetSyncer 基于通用同步器的子网全量同步，next 为云上子网分页查询函数
+func (cli *client) SubnetSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+	rs := cli.subnetResSyncer(accountID, region)
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+func (cli *client) subnetResSyncer(accountID, region string) *common.ResSyncer[*SyncBaseParams,
+	adtysubnet.AwsSubnet, cloudcore.Subnet[cloudcore.AwsSubnetExtension]] {
 
-		var delCloudIDs []string
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			delCloudIDs, err = cli.listRemoveSubnetID(kt, params)
+	return &common.ResSyncer[*SyncBaseParams, adtysubnet.AwsSubnet, cloudcore.Subnet[cloudcore.AwsSubnetExtension]]{
+		ResType: enumor.SubnetCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.Subnet.List(kt.Ctx, kt.Header(), req)
 			if err != nil {
-				return err
+				logs.Errorf("[%s] request dataservice to list subnet failed, err: %v, req: %v, rid: %s", enumor.Aws,
+					err, req, kt.Rid)
+				return nil, err
 			}
-		}
 
-		if len(delCloudIDs) != 0 {
-			if err = cli.deleteSubnet(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
-
-		req.Page.Start += constant.BatchOperationMaxLimit
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listExistSubnetFromCloud,
+		ListFromDB:    cli.listSubnetFromDB,
+		IsChange:      isAwsSubnetChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []adtysubnet.AwsSubnet) error {
+			return cli.createSubnet(kt, params.AccountID, params.Region, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]adtysubnet.AwsSubnet) error {
+			return cli.updateSubnet(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []adtysubnet.AwsSubnet) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.SubnetCloudResType,
+				dataFromCloud)
+		},
 	}
-
-	return nil
 }
 
-func (cli *client) listRemoveSubnetID(kt *kit.Kit, params *SyncBaseParams) ([]string, error) {
+// listExistSubnetFromCloud 查询云上存在的子网，aws 查询不存在的子网会报错，需剔除不存在的云ID后重新查询
+func (cli *client) listExistSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]adtysubnet.AwsSubnet, error) {
 	if err := params.Validate(); err != nil {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	delCloudIDs := make([]string, 0)
 	cloudIDs := params.CloudIDs
-	for {
+	for len(cloudIDs) > 0 {
 		opt := &adcore.AwsListOption{
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
-		_, err := cli.cloudCli.ListSubnet(kt, opt)
-		if err != nil {
-			if strings.Contains(err.Error(), aws.ErrSubnetNotFound) {
-				var delCloudID string
-				cloudIDs, delCloudID = removeNotFoundCloudID(cloudIDs, err)
-				delCloudIDs = append(delCloudIDs, delCloudID)
-
-				if len(cloudIDs) <= 0 {
-					break
-				}
-
-				continue
-			}
+		result, err := cli.cloudCli.ListSubnet(kt, opt)
+		if err == nil {
+			return result.Details, nil
+		}
 
+		if !strings.Contains(err.Error(), aws.ErrSubnetNotFound) {
 			logs.Errorf("[%s] list subnet from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws, err,
 				params.AccountID, opt, kt.Rid)
 			return nil, err
 		}
-
-		break
+		cloudIDs, _ = removeNotFoundCloudID(cloudIDs, err)
 	}
 
-	return delCloudIDs, nil
+	return make([]adtysubnet.AwsSubnet, 0), nil
 }
 
 func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]adtysubnet.AwsSubnet, error) {
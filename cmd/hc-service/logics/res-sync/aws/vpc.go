@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/aws"
 	"hcm/pkg/adaptor/types"
 	adcore "hcm/pkg/adaptor/types/core"
@@ -58,41 +59,10 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
-	if err != nil {
+	if err := cli.vpcResSyncer(params.AccountID, params.Region).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	vpcFromDB, err := cli.listVpcFromDB(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addVpc, updateMap, delCloudIDs := common.Diff[types.AwsVpc, cloudcore.Vpc[cloudcore.AwsVpcExtension]](
-		vpcFromCloud, vpcFromDB, isAwsVpcChange)
-
-	if len(delCloudIDs) > 0 {
-		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addVpc) > 0 {
-		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return nil, nil
 }
 
@@ -296,102 +266,87 @@ func isAwsVpcChange(item types.AwsVpc, info cloudcore.Vpc[cloudcore.AwsVpcExtens
 
 // RemoveVpcDeleteFromCloud ...
 func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	return cli.vpcResSyncer(accountID, region).RemoveDeletedFromCloud(kt)
+}
 
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.Vpc.List(kt.Ctx, kt.Header(), req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list vpc failed, err: %v, req: %v, rid: %s", enumor.Aws,
-				err, req, kt.Rid)
-			return err
-		}
+// VpcSyncer 基于通用同步器的vpc全量同步，next 为云上vpc分页查询函数
+func (cli *client) VpcSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+	rs := cli.vpcResSyncer(accountID, region)
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+func (cli *client) vpcResSyncer(accountID, region string) *common.ResSyncer[*SyncBaseParams,
+	types.AwsVpc, cloudcore.Vpc[cloudcore.AwsVpcExtension]] {
 
-		var delCloudIDs []string
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			delCloudIDs, err = cli.listRemoveVpcID(kt, params)
+	return &common.ResSyncer[*SyncBaseParams, types.AwsVpc, cloudcore.Vpc[cloudcore.AwsVpcExtension]]{
+		ResType: enumor.VpcCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.Vpc.List(kt.Ctx, kt.Header(), req)
 			if err != nil {
-				return err
+				logs.Errorf("[%s] request dataservice to list vpc failed, err: %v, req: %v, rid: %s", enumor.Aws,
+					err, req, kt.Rid)
+				return nil, err
 			}
-		}
 
-		if len(delCloudIDs) != 0 {
-			if err = cli.deleteVpc(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
-
-		req.Page.Start += constant.BatchOperationMaxLimit
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listExistVpcFromCloud,
+		ListFromDB:    cli.listVpcFromDB,
+		IsChange:      isAwsVpcChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []types.AwsVpc) error {
+			return cli.createVpc(kt, params.AccountID, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]types.AwsVpc) error {
+			return cli.updateVpc(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []types.AwsVpc) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.VpcCloudResType,
+				dataFromCloud)
+		},
 	}
-
-	return nil
 }
 
-func (cli *client) listRemoveVpcID(kt *kit.Kit, params *SyncBaseParams) ([]string, error) {
+// listExistVpcFromCloud 查询云上存在的vpc，aws 查询不存在的vpc会报错，需剔除不存在的云ID后重新查询
+func (cli *client) listExistVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]types.AwsVpc, error) {
 	if err := params.Validate(); err != nil {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	delCloudIDs := make([]string, 0)
 	cloudIDs := params.CloudIDs
-	for {
+	for len(cloudIDs) > 0 {
 		opt := &adcore.AwsListOption{
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
-		_, err := cli.cloudCli.ListVpc(kt, opt)
-		if err != nil {
-			if strings.Contains(err.Error(), aws.ErrVpcNotFound) {
-				var delCloudID string
-				cloudIDs, delCloudID = removeNotFoundCloudID(cloudIDs, err)
-				delCloudIDs = append(delCloudIDs, delCloudID)
-
-				if len(cloudIDs) <= 0 {
-					break
-				}
-
-				continue
-			}
+		result, err := cli.cloudCli.ListVpc(kt, opt)
+		if err == nil {
+			return result.Details, nil
+		}
 
+		if !strings.Contains(err.Error(), aws.ErrVpcNotFound) {
 			logs.Errorf("[%s] list vpc from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws, err,
 				params.AccountID, opt, kt.Rid)
 			return nil, err
 		}
-
-		break
+		cloudIDs, _ = removeNotFoundCloudID(cloudIDs, err)
 	}
 
-	return delCloudIDs, nil
+	return make([]types.AwsVpc, 0), nil
 }
 
 func (cli *client) listVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]types.AwsVpc, error) {
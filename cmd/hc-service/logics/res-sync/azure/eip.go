@@ -91,6 +91,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.EipCloudResType,
+		eipFromCloud); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -90,6 +90,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.SecurityGroupCloudResType,
+		sgFromCloud); err != nil {
+		return nil, err
+	}
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -91,6 +91,12 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.VpcCloudResType,
+		vpcFromCloud); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/adaptor/types/cert"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	typesdisk "hcm/pkg/adaptor/types/disk"
+	typessnapshot "hcm/pkg/adaptor/types/disk-snapshot"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	firewallrule "hcm/pkg/adaptor/types/firewall-rule"
 	typesimage "hcm/pkg/adaptor/types/image"
@@ -152,7 +153,17 @@ type CloudResType interface {
 		typeslb.TCloudClb |
 		typeslb.TCloudListener |
 		typeslb.TCloudUrlRule |
-		typeslb.Backend
+		typeslb.Backend |
+		typeslb.AwsLoadBalancer |
+		typeslb.AwsListener |
+		typeslb.AwsRule |
+		typeslb.AwsTargetGroup |
+		typeslb.HuaWeiLoadBalancer |
+		typeslb.HuaWeiListener |
+		typeslb.HuaWeiL7Policy |
+		typeslb.HuaWeiPool |
+
+		typessnapshot.DiskSnapshot
 }
 
 // DBResType 本地资源类型
@@ -248,7 +259,15 @@ type DBResType interface {
 		corelb.TCloudLoadBalancer |
 		corelb.TCloudLbUrlRule |
 		corelb.TCloudListener |
-		corelb.BaseTarget
+		corelb.BaseTarget |
+		corelb.AwsLoadBalancer |
+		corelb.AwsListener |
+		corelb.AwsTargetGroup |
+		corelb.HuaWeiLoadBalancer |
+		corelb.HuaWeiListener |
+		corelb.HuaWeiTargetGroup |
+
+		cloudcore.DiskSnapshot
 }
 
 // Diff 对比云和db资源，划分出新增数据，更新数据，删除数据。
@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// TaggedCloudRes 带标签的云资源
+type TaggedCloudRes interface {
+	GetCloudID() string
+	GetTags() []core.TagPair
+}
+
+// CloudResTags 云资源ID及其标签，用于列表接口不返回标签、需要单独查询标签的资源
+type CloudResTags struct {
+	CloudID string
+	Tags    []core.TagPair
+}
+
+// GetCloudID ...
+func (res CloudResTags) GetCloudID() string {
+	return res.CloudID
+}
+
+// GetTags ...
+func (res CloudResTags) GetTags() []core.TagPair {
+	return res.Tags
+}
+
+// NewCloudResTags 将云资源ID到标签的映射转换为 CloudResTags 列表
+func NewCloudResTags(tagMap map[string][]core.TagPair) []CloudResTags {
+	resources := make([]CloudResTags, 0, len(tagMap))
+	for cloudID, tags := range tagMap {
+		resources = append(resources, CloudResTags{CloudID: cloudID, Tags: tags})
+	}
+	return resources
+}
+
+// SyncResourceTags 将云上资源的标签同步到统一的资源标签表，以云上标签为准覆盖db中的标签。
+// 需要在资源本身同步完成后调用，db中不存在的资源会被忽略。
+func SyncResourceTags[T TaggedCloudRes](kt *kit.Kit, dbCli *dataservice.Client, vendor enumor.Vendor,
+	accountID string, resType enumor.CloudResourceType, resources []T) error {
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	items := make([]protocloud.ResourceTagUpsertItem, 0, len(resources))
+	for _, one := range resources {
+		items = append(items, protocloud.ResourceTagUpsertItem{
+			CloudResID: one.GetCloudID(),
+			Tags:       one.GetTags(),
+		})
+	}
+
+	for _, batch := range slice.Split(items, constant.BatchOperationMaxLimit) {
+		req := &protocloud.ResourceTagBatchUpsertReq{
+			Vendor:    vendor,
+			AccountID: accountID,
+			ResType:   resType,
+			Replace:   true,
+			Resources: batch,
+		}
+		if err := dbCli.Global.ResourceTag.BatchUpsert(kt, req); err != nil {
+			logs.Errorf("[%s] sync %s resource tags failed, err: %v, account: %s, rid: %s", vendor, resType, err,
+				accountID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
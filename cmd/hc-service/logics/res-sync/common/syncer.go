@@ -0,0 +1,281 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	logicsync "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+// PlanSyncer 基于通用同步器的全量同步，dry-run 模式下结果中包含变更计划
+type PlanSyncer interface {
+	AllPages(kt *kit.Kit) (*logicsync.Result, error)
+}
+
+// NextFromCloudFunc 分页查询云上资源ID，返回空时表示已查询完毕
+type NextFromCloudFunc func(kt *kit.Kit) ([]string, error)
+
+// NewAccountRegionListReq 按账号、地域分页查询db资源ID和云ID的请求
+func NewAccountRegionListReq(accountID, region string, page *core.BasePage) *core.ListReq {
+	return &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
+			},
+		},
+		Page: page,
+	}
+}
+
+// CloudData 云上资源，适配通用同步器的数据源数据
+type CloudData[T CloudResType] struct {
+	Res T
+}
+
+// GetUUID 以云ID作为唯一标识
+func (d CloudData[T]) GetUUID() string {
+	return d.Res.GetCloudID()
+}
+
+// DBData db资源，适配通用同步器的目标源数据
+type DBData[T DBResType] struct {
+	Res T
+}
+
+// GetUUID 以云ID作为唯一标识
+func (d DBData[T]) GetUUID() string {
+	return d.Res.GetCloudID()
+}
+
+// GetID return db id.
+func (d DBData[T]) GetID() string {
+	return d.Res.GetID()
+}
+
+// ResSyncer 复用 res-sync 中已有的查询、对比、增删改函数，同时作为通用同步器的处理器和分页器。
+type ResSyncer[P any, C CloudResType, D DBResType] struct {
+	ResType enumor.CloudResourceType
+	// BuildParams 根据云ID构建批量同步参数
+	BuildParams func(cloudIDs []string) P
+	// NextFromCloud 分页查询云上资源ID，全量同步时使用，单页最多 constant.CloudResourceSyncMaxLimit 条
+	NextFromCloud NextFromCloudFunc
+	// ListDBPage 按账号、地域分页查询db资源，返回以云ID为key、db id为value的映射，清理云上已删除数据时使用
+	ListDBPage func(kt *kit.Kit, page *core.BasePage) (map[string]string, error)
+
+	ListFromCloud func(kt *kit.Kit, params P) ([]C, error)
+	ListFromDB    func(kt *kit.Kit, params P) ([]D, error)
+	IsChange      func(cloud C, db D) bool
+	Create        func(kt *kit.Kit, params P, addData []C) error
+	// Update updateMap 以db id为key
+	Update func(kt *kit.Kit, params P, updateMap map[string]C) error
+	// Delete 入参为云ID
+	Delete func(kt *kit.Kit, params P, delCloudIDs []string) error
+	// SyncExtra 可选，非 dry-run 模式下增删改完成后执行，如同步资源标签
+	SyncExtra func(kt *kit.Kit, params P, dataFromCloud []C) error
+	// FieldDiff 可选，dry-run 模式下输出字段级变更
+	FieldDiff func(cloud C, db D) []logicsync.FieldChange
+
+	// idCloudIDMap db id 到云ID的映射，通用同步器按db id删除，res-sync 按云ID删除
+	idCloudIDMap map[string]string
+	cloudHasNext bool
+	dbStart      uint32
+	dbHasNext    bool
+}
+
+// Syncer 返回使用该处理器的通用同步器
+func (s *ResSyncer[P, C, D]) Syncer(opt logicsync.Option) *logicsync.Syncer[P, CloudData[C], DBData[D]] {
+	return &logicsync.Syncer[P, CloudData[C], DBData[D]]{
+		Pager:   s,
+		Handler: s,
+		Option:  opt,
+	}
+}
+
+// BatchSync 按云ID批量同步，用于同步指定的资源，如事件同步、关联资源同步等。本批次要删除的数据占比不代表db全量数据，
+// 不校验删除比例阈值。
+func (s *ResSyncer[P, C, D]) BatchSync(kt *kit.Kit, params P) error {
+	_, err := s.Syncer(logicsync.Option{}).BatchOrAll(kt, params)
+	return err
+}
+
+// RemoveDeletedFromCloud 按账号、地域遍历db数据，删除已经从云上删除的数据。不校验删除比例阈值，同步接口的全量同步
+// 通过 Syncer 使用 hc-service 配置的阈值。
+func (s *ResSyncer[P, C, D]) RemoveDeletedFromCloud(kt *kit.Kit) error {
+	_, err := s.Syncer(logicsync.Option{}).RemoveDeletedFromSource(kt)
+	return err
+}
+
+// Name ...
+func (s *ResSyncer[P, C, D]) Name() logicsync.HandlerName {
+	return logicsync.HandlerName(s.ResType)
+}
+
+// BuildParam ...
+func (s *ResSyncer[P, C, D]) BuildParam(uuids []string) P {
+	return s.BuildParams(uuids)
+}
+
+// NextFromSource ...
+func (s *ResSyncer[P, C, D]) NextFromSource(kt *kit.Kit) ([]string, error) {
+	if s.NextFromCloud == nil {
+		return nil, fmt.Errorf("%s next from cloud is not set", s.ResType)
+	}
+
+	cloudIDs, err := s.NextFromCloud(kt)
+	if err != nil {
+		return nil, err
+	}
+	s.cloudHasNext = len(cloudIDs) >= constant.CloudResourceSyncMaxLimit
+	return cloudIDs, nil
+}
+
+// HasNextFromSource ...
+func (s *ResSyncer[P, C, D]) HasNextFromSource() (bool, error) {
+	return s.cloudHasNext, nil
+}
+
+// NextFromTarget ...
+func (s *ResSyncer[P, C, D]) NextFromTarget(kt *kit.Kit) (map[string]string, error) {
+	if s.ListDBPage == nil {
+		return nil, fmt.Errorf("%s list db page is not set", s.ResType)
+	}
+
+	page := &core.BasePage{Start: s.dbStart, Limit: constant.BatchOperationMaxLimit}
+	cloudIDMap, err := s.ListDBPage(kt, page)
+	if err != nil {
+		return nil, err
+	}
+	s.dbHasNext = len(cloudIDMap) >= constant.BatchOperationMaxLimit
+	s.dbStart += constant.BatchOperationMaxLimit
+
+	for cloudID, id := range cloudIDMap {
+		s.recordCloudID(id, cloudID)
+	}
+	return cloudIDMap, nil
+}
+
+// HasNextFromTarget ...
+func (s *ResSyncer[P, C, D]) HasNextFromTarget() (bool, error) {
+	return s.dbHasNext, nil
+}
+
+// QueryFromSource ...
+func (s *ResSyncer[P, C, D]) QueryFromSource(kt *kit.Kit, params P) ([]CloudData[C], error) {
+	dataFromCloud, err := s.ListFromCloud(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CloudData[C], 0, len(dataFromCloud))
+	for _, one := range dataFromCloud {
+		result = append(result, CloudData[C]{Res: one})
+	}
+	return result, nil
+}
+
+// QueryFromTarget ...
+func (s *ResSyncer[P, C, D]) QueryFromTarget(kt *kit.Kit, params P) ([]DBData[D], error) {
+	dataFromDB, err := s.ListFromDB(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DBData[D], 0, len(dataFromDB))
+	for _, one := range dataFromDB {
+		result = append(result, DBData[D]{Res: one})
+		s.recordCloudID(one.GetID(), one.GetCloudID())
+	}
+	return result, nil
+}
+
+// DiffFunc ...
+func (s *ResSyncer[P, C, D]) DiffFunc(sourceData CloudData[C], targetData DBData[D]) bool {
+	return s.IsChange(sourceData.Res, targetData.Res)
+}
+
+// DiffFields ...
+func (s *ResSyncer[P, C, D]) DiffFields(sourceData CloudData[C], targetData DBData[D]) []logicsync.FieldChange {
+	if s.FieldDiff == nil {
+		return nil
+	}
+	return s.FieldDiff(sourceData.Res, targetData.Res)
+}
+
+// DeleteTargetData ...
+func (s *ResSyncer[P, C, D]) DeleteTargetData(kt *kit.Kit, params P, delIDs []string) error {
+	delCloudIDs := make([]string, 0, len(delIDs))
+	for _, id := range delIDs {
+		cloudID, exists := s.idCloudIDMap[id]
+		if !exists {
+			return fmt.Errorf("%s: %s cloud id not found", s.ResType, id)
+		}
+		delCloudIDs = append(delCloudIDs, cloudID)
+	}
+	return s.Delete(kt, params, delCloudIDs)
+}
+
+// CreateTargetData ...
+func (s *ResSyncer[P, C, D]) CreateTargetData(kt *kit.Kit, params P, createData []CloudData[C]) ([]string,
+	error) {
+
+	addData := make([]C, 0, len(createData))
+	for _, one := range createData {
+		addData = append(addData, one.Res)
+	}
+	return nil, s.Create(kt, params, addData)
+}
+
+// UpdateTargetData ...
+func (s *ResSyncer[P, C, D]) UpdateTargetData(kt *kit.Kit, params P, idUpdateDataMap map[string]CloudData[C]) error {
+	updateMap := make(map[string]C, len(idUpdateDataMap))
+	for id, one := range idUpdateDataMap {
+		updateMap[id] = one.Res
+	}
+	return s.Update(kt, params, updateMap)
+}
+
+// AfterSync ...
+func (s *ResSyncer[P, C, D]) AfterSync(kt *kit.Kit, params P, sourceData []CloudData[C]) error {
+	if s.SyncExtra == nil {
+		return nil
+	}
+
+	dataFromCloud := make([]C, 0, len(sourceData))
+	for _, one := range sourceData {
+		dataFromCloud = append(dataFromCloud, one.Res)
+	}
+	return s.SyncExtra(kt, params, dataFromCloud)
+}
+
+func (s *ResSyncer[P, C, D]) recordCloudID(id, cloudID string) {
+	if s.idCloudIDMap == nil {
+		s.idCloudIDMap = make(map[string]string)
+	}
+	s.idCloudIDMap[id] = cloudID
+}
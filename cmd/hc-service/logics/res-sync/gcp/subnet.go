@@ -92,6 +92,12 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.Gcp, params.AccountID, enumor.SubnetCloudResType,
+		subnetFromCloud); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
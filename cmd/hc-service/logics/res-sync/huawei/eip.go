@@ -37,6 +37,7 @@ import (
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/assert"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
 )
 
 // SyncEipOption ...
@@ -91,6 +92,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
+	// 同步资源标签
+	if err = cli.syncTagsByTms(kt, params.AccountID, params.Region, enumor.EipCloudResType,
+		slice.Map(eipFromCloud, (*typeseip.HuaWeiEip).GetCloudID)); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
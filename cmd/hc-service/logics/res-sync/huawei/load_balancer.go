@@ -0,0 +1,498 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/cloud"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncLBOption ...
+type SyncLBOption struct {
+	// BkBizID 负载均衡创建时，通过同步写入DB，需要传入业务ID
+	BkBizID int64 `json:"bk_biz_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt SyncLBOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// LoadBalancerWithListener 同步指定负载均衡及下属资源
+// 1. 同步该负载均衡自身属性
+// 2. 同步该负载均衡下的后端服务器组及后端服务器
+// 3. 同步该负载均衡下的监听器及监听器下的转发策略
+func (cli *client) LoadBalancerWithListener(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult,
+	error) {
+
+	if _, err := cli.LoadBalancer(kt, params, opt); err != nil {
+		logs.Errorf("[%s] fail to sync load balancer with rel, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+		return nil, err
+	}
+
+	lbList, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		logs.Errorf("[%s] fail to get lb from db after lb layer sync, err: %v, rid: %s", enumor.HuaWei, err,
+			kt.Rid)
+		return nil, err
+	}
+
+	for i := range lbList {
+		cloudListeners, err := cli.listListenerFromCloud(kt, params.Region, lbList[i].CloudID)
+		if err != nil {
+			return nil, err
+		}
+
+		// 规则需要关联后端服务器组的本地ID，因此后端服务器组需要先于监听器同步
+		tgMap, err := cli.targetGroupByLb(kt, params.AccountID, params.Region, &lbList[i], cloudListeners)
+		if err != nil {
+			logs.Errorf("[%s] fail to sync pool of lb(%s), err: %v, rid: %s", enumor.HuaWei, lbList[i].CloudID,
+				err, kt.Rid)
+			return nil, err
+		}
+
+		if err = cli.listenerByLb(kt, params.Region, &lbList[i], cloudListeners, tgMap); err != nil {
+			logs.Errorf("[%s] fail to sync listener of lb(%s), err: %v, rid: %s", enumor.HuaWei,
+				lbList[i].CloudID, err, kt.Rid)
+			return nil, err
+		}
+	}
+
+	return new(SyncResult), nil
+}
+
+// LoadBalancer 同步指定负载均衡自身属性，不同步关联资源
+func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult, error) {
+	if err := validator.ValidateTool(params, opt); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbFromCloud, err := cli.listLBFromCloud(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	lbFromDB, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lbFromCloud) == 0 && len(lbFromDB) == 0 {
+		return new(SyncResult), nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.HuaWeiLoadBalancer, corelb.HuaWeiLoadBalancer](
+		lbFromCloud, lbFromDB, isLBChange)
+
+	if err = cli.deleteLoadBalancer(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
+		return nil, err
+	}
+
+	if err = cli.createLoadBalancer(kt, params.AccountID, params.Region, opt.BkBizID, addSlice); err != nil {
+		return nil, err
+	}
+
+	if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
+		return nil, err
+	}
+
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.HuaWei, params.AccountID, enumor.LoadBalancerCloudResType,
+		lbFromCloud); err != nil {
+		return nil, err
+	}
+
+	return new(SyncResult), nil
+}
+
+// RemoveLoadBalancerDeleteFromCloud 删除存在本地但是在云上被删除的数据
+func (cli *client) RemoveLoadBalancerDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("vendor", enumor.HuaWei),
+		),
+		Page: &core.BasePage{Start: 0, Limit: constant.CloudResourceSyncMaxLimit},
+	}
+
+	for {
+		lbFromDB, err := cli.dbCli.Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] request dataservice to list lb failed, err: %v, req: %v, rid: %s", enumor.HuaWei,
+				err, req, kt.Rid)
+			return err
+		}
+
+		cloudIDs := slice.Map(lbFromDB.Details, func(lb corelb.BaseLoadBalancer) string { return lb.CloudID })
+		if len(cloudIDs) == 0 {
+			break
+		}
+
+		params := &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		lbFromCloud, err := cli.listLBFromCloud(kt, params)
+		if err != nil {
+			return err
+		}
+
+		// 如果有资源没有查询出来，说明数据被从云上删除
+		if len(lbFromCloud) != len(cloudIDs) {
+			cloudIDMap := cvt.StringSliceToMap(cloudIDs)
+			for _, one := range lbFromCloud {
+				delete(cloudIDMap, one.GetCloudID())
+			}
+
+			delCloudIDs := cvt.MapKeyToStringSlice(cloudIDMap)
+			if err = cli.deleteLoadBalancer(kt, accountID, region, delCloudIDs); err != nil {
+				return err
+			}
+		}
+
+		if len(lbFromDB.Details) < constant.CloudResourceSyncMaxLimit {
+			break
+		}
+
+		req.Page.Start += constant.CloudResourceSyncMaxLimit
+	}
+
+	return nil
+}
+
+func (cli *client) createLoadBalancer(kt *kit.Kit, accountID string, region string, bizID int64,
+	addSlice []typeslb.HuaWeiLoadBalancer) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	vpcMap, subnetMap, err := cli.getLoadBalancerRelatedRes(kt, accountID, region, addSlice)
+	if err != nil {
+		return err
+	}
+
+	createReq := new(protocloud.HuaWeiLBCreateReq)
+	for _, one := range addSlice {
+		createReq.Lbs = append(createReq.Lbs, convCloudToDBCreate(one, accountID, region, bizID, vpcMap, subnetMap))
+	}
+
+	if _, err = cli.dbCli.HuaWei.LoadBalancer.BatchCreateHuaWeiLoadBalancer(kt, createReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch create lb failed, err: %v, rid: %s", enumor.HuaWei, err,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to create lb success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
+		accountID, len(addSlice), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) updateLoadBalancer(kt *kit.Kit, accountID string, region string,
+	updateMap map[string]typeslb.HuaWeiLoadBalancer) error {
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	lbs := make([]typeslb.HuaWeiLoadBalancer, 0, len(updateMap))
+	for _, one := range updateMap {
+		lbs = append(lbs, one)
+	}
+	vpcMap, subnetMap, err := cli.getLoadBalancerRelatedRes(kt, accountID, region, lbs)
+	if err != nil {
+		return err
+	}
+
+	updateReq := new(protocloud.HuaWeiLBBatchUpdateReq)
+	for id, one := range updateMap {
+		updateReq.Lbs = append(updateReq.Lbs, convCloudToDBUpdate(id, one, vpcMap, subnetMap))
+	}
+
+	if err = cli.dbCli.HuaWei.LoadBalancer.BatchUpdate(kt, updateReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch update lb failed, err: %v, rid: %s", enumor.HuaWei, err,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to update lb success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
+		accountID, len(updateMap), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) deleteLoadBalancer(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	checkParams := &SyncBaseParams{
+		AccountID: accountID,
+		Region:    region,
+		CloudIDs:  delCloudIDs,
+	}
+	delLBFromCloud, err := cli.listLBFromCloud(kt, checkParams)
+	if err != nil {
+		return err
+	}
+
+	if len(delLBFromCloud) > 0 {
+		logs.Errorf("[%s] validate lb not exist failed, before delete, opt: %v, failed_count: %d, rid: %s",
+			enumor.HuaWei, checkParams, len(delLBFromCloud), kt.Rid)
+		return fmt.Errorf("validate lb not exist failed, before delete")
+	}
+
+	deleteReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", delCloudIDs),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("vendor", enumor.HuaWei),
+		),
+	}
+	if err = cli.dbCli.Global.LoadBalancer.BatchDelete(kt, deleteReq); err != nil {
+		logs.Errorf("[%s] request dataservice to batch delete lb failed, err: %v, rid: %s", enumor.HuaWei, err,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to delete lb success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
+		accountID, len(delCloudIDs), kt.Rid)
+
+	return nil
+}
+
+// getLoadBalancerRelatedRes return vpc map and subnet map of given load balancers
+func (cli *client) getLoadBalancerRelatedRes(kt *kit.Kit, accountID string, region string,
+	lbs []typeslb.HuaWeiLoadBalancer) (map[string]*common.VpcDB, map[string]cloud.BaseSubnet, error) {
+
+	cloudVpcIDs := make([]string, 0, len(lbs))
+	cloudSubnetIDs := make([]string, 0, len(lbs))
+	for _, one := range lbs {
+		cloudVpcIDs = append(cloudVpcIDs, one.VpcId)
+		cloudSubnetIDs = append(cloudSubnetIDs, one.ElbVirsubnetIds...)
+	}
+
+	vpcMap, err := cli.getVpcMap(kt, accountID, region, slice.Unique(cloudVpcIDs))
+	if err != nil {
+		logs.Errorf("[%s] fail to get vpc of lb, err: %v, vpcIDs: %v, rid: %s", enumor.HuaWei, err, cloudVpcIDs,
+			kt.Rid)
+		return nil, nil, err
+	}
+
+	subnetMap, err := cli.getSubnetMapByCloudID(kt, slice.Unique(cloudSubnetIDs))
+	if err != nil {
+		logs.Errorf("[%s] fail to get subnet of lb, err: %v, subnetIDs: %v, rid: %s", enumor.HuaWei, err,
+			cloudSubnetIDs, kt.Rid)
+		return nil, nil, err
+	}
+
+	return vpcMap, subnetMap, nil
+}
+
+func (cli *client) listLBFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]typeslb.HuaWeiLoadBalancer, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &typeslb.HuaWeiListOption{
+		Region:   params.Region,
+		CloudIDs: params.CloudIDs,
+		Limit:    cvt.ValToPtr(int32(typeslb.HuaWeiDescribeLimit)),
+	}
+	lbResult, err := cli.cloudCli.ListLoadBalancer(kt, opt)
+	if err != nil {
+		logs.Errorf("[%s] list lb from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei, err,
+			params.AccountID, opt, kt.Rid)
+		return nil, err
+	}
+
+	return lbResult.Details, nil
+}
+
+func (cli *client) listLBFromDB(kt *kit.Kit, params *SyncBaseParams) ([]corelb.HuaWeiLoadBalancer, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", params.AccountID),
+			tools.RuleEqual("region", params.Region),
+			tools.RuleIn("cloud_id", params.CloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := cli.dbCli.HuaWei.LoadBalancer.ListLoadBalancer(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] list lb from db failed, err: %v, account: %s, req: %v, rid: %s", enumor.HuaWei, err,
+			params.AccountID, req, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+func convCloudToDBCreate(cloud typeslb.HuaWeiLoadBalancer, accountID string, region string, bizID int64,
+	vpcMap map[string]*common.VpcDB, subnetMap map[string]cloud.BaseSubnet) protocloud.HuaWeiLBCreate {
+
+	cloudSubnetID := firstOrEmpty(cloud.ElbVirsubnetIds)
+	lb := protocloud.HuaWeiLBCreate{
+		CloudID:              cloud.GetCloudID(),
+		Name:                 cloud.Name,
+		Vendor:               enumor.HuaWei,
+		AccountID:            accountID,
+		BkBizID:              bizID,
+		Region:               region,
+		Zones:                cloud.AvailabilityZoneList,
+		LoadBalancerType:     string(cloud.GetLoadBalancerType()),
+		IPVersion:            cloud.GetIPVersion(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloud.VpcId]).VpcID,
+		CloudVpcID:           cloud.VpcId,
+		SubnetID:             subnetMap[cloudSubnetID].ID,
+		CloudSubnetID:        cloudSubnetID,
+		PrivateIPv4Addresses: nonEmptySlice(cloud.VipAddress),
+		PrivateIPv6Addresses: nonEmptySlice(cloud.Ipv6VipAddress),
+		PublicIPv4Addresses:  cloud.GetPublicIPv4Addresses(),
+		Status:               cloud.ProvisioningStatus,
+		CloudCreatedTime:     cloud.CreatedAt,
+		Tags:                 cloud.GetTagMap(),
+		Memo:                 cvt.ValToPtr(cloud.Description),
+		Extension:            convHuaWeiLBExtension(cloud),
+	}
+	if bizID == 0 {
+		lb.BkBizID = constant.UnassignedBiz
+	}
+
+	return lb
+}
+
+func convCloudToDBUpdate(id string, cloud typeslb.HuaWeiLoadBalancer, vpcMap map[string]*common.VpcDB,
+	subnetMap map[string]cloud.BaseSubnet) *protocloud.LoadBalancerExtUpdateReq[corelb.HuaWeiLoadBalancerExtension] {
+
+	cloudSubnetID := firstOrEmpty(cloud.ElbVirsubnetIds)
+	return &protocloud.LoadBalancerExtUpdateReq[corelb.HuaWeiLoadBalancerExtension]{
+		ID:                   id,
+		Name:                 cloud.Name,
+		IPVersion:            cloud.GetIPVersion(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloud.VpcId]).VpcID,
+		CloudVpcID:           cloud.VpcId,
+		SubnetID:             subnetMap[cloudSubnetID].ID,
+		CloudSubnetID:        cloudSubnetID,
+		PrivateIPv4Addresses: nonEmptySlice(cloud.VipAddress),
+		PrivateIPv6Addresses: nonEmptySlice(cloud.Ipv6VipAddress),
+		PublicIPv4Addresses:  cloud.GetPublicIPv4Addresses(),
+		Status:               cloud.ProvisioningStatus,
+		Tags:                 cloud.GetTagMap(),
+		Memo:                 cvt.ValToPtr(cloud.Description),
+		Extension:            convHuaWeiLBExtension(cloud),
+	}
+}
+
+func convHuaWeiLBExtension(cloud typeslb.HuaWeiLoadBalancer) *corelb.HuaWeiLoadBalancerExtension {
+	return &corelb.HuaWeiLoadBalancerExtension{
+		Guaranteed:               cvt.ValToPtr(cloud.Guaranteed),
+		L4FlavorID:               cvt.ValToPtr(cloud.L4FlavorId),
+		L7FlavorID:               cvt.ValToPtr(cloud.L7FlavorId),
+		VipPortID:                cvt.ValToPtr(cloud.VipPortId),
+		CloudElbVirsubnetIDs:     cloud.ElbVirsubnetIds,
+		CloudEipIDs:              cloud.GetCloudEipIDs(),
+		IpTargetEnable:           cvt.ValToPtr(cloud.IpTargetEnable),
+		DeletionProtectionEnable: cloud.DeletionProtectionEnable,
+		EnterpriseProjectID:      cvt.ValToPtr(cloud.EnterpriseProjectId),
+	}
+}
+
+func isLBChange(cloud typeslb.HuaWeiLoadBalancer, db corelb.HuaWeiLoadBalancer) bool {
+	if db.Name != cloud.Name {
+		return true
+	}
+	if db.Status != cloud.ProvisioningStatus {
+		return true
+	}
+	if db.IPVersion != cloud.GetIPVersion() {
+		return true
+	}
+	if db.CloudVpcID != cloud.VpcId {
+		return true
+	}
+	if cvt.PtrToVal(db.Memo) != cloud.Description {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Zones, cloud.AvailabilityZoneList) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.PrivateIPv4Addresses, nonEmptySlice(cloud.VipAddress)) ||
+		!assert.IsStringSliceEqual(db.PrivateIPv6Addresses, nonEmptySlice(cloud.Ipv6VipAddress)) ||
+		!assert.IsStringSliceEqual(db.PublicIPv4Addresses, cloud.GetPublicIPv4Addresses()) {
+		return true
+	}
+	if !assert.IsStringMapEqual(db.Tags, cloud.GetTagMap()) {
+		return true
+	}
+
+	if db.Extension == nil {
+		return true
+	}
+	if cvt.PtrToVal(db.Extension.L4FlavorID) != cloud.L4FlavorId ||
+		cvt.PtrToVal(db.Extension.L7FlavorID) != cloud.L7FlavorId {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.CloudElbVirsubnetIDs, cloud.ElbVirsubnetIds) {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.CloudEipIDs, cloud.GetCloudEipIDs()) {
+		return true
+	}
+	if !assert.IsPtrBoolEqual(db.Extension.DeletionProtectionEnable, cloud.DeletionProtectionEnable) {
+		return true
+	}
+
+	return false
+}
+
+func firstOrEmpty(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0]
+}
+
+func nonEmptySlice(item string) []string {
+	if len(item) == 0 {
+		return nil
+	}
+	return []string{item}
+}
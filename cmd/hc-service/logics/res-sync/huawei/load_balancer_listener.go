@@ -0,0 +1,819 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// -------------------------- Pool --------------------------
+
+// targetGroupByLb 同步负载均衡下的后端服务器组及后端服务器，返回 云上后端服务器组ID -> 本地目标组ID 的映射
+func (cli *client) targetGroupByLb(kt *kit.Kit, accountID string, region string, lb *corelb.HuaWeiLoadBalancer,
+	cloudListeners []typeslb.HuaWeiListener) (map[string]string, error) {
+
+	listOpt := &typeslb.HuaWeiListPoolsOption{Region: region, CloudLoadBalancerID: lb.CloudID}
+	cloudPools, err := cli.cloudCli.ListPool(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list pool from cloud failed, err: %v, opt: %+v, rid: %s", enumor.HuaWei, err, listOpt,
+			kt.Rid)
+		return nil, err
+	}
+
+	cloudMembers := make(map[string][]typeslb.HuaWeiMember, len(cloudPools))
+	for _, pool := range cloudPools {
+		memberOpt := &typeslb.HuaWeiListMembersOption{Region: region, CloudPoolID: pool.Id}
+		members, err := cli.cloudCli.ListMember(kt, memberOpt)
+		if err != nil {
+			logs.Errorf("[%s] list member from cloud failed, err: %v, opt: %+v, rid: %s", enumor.HuaWei, err,
+				memberOpt, kt.Rid)
+			return nil, err
+		}
+		cloudMembers[pool.Id] = members
+	}
+
+	// 华为云后端服务器组本身没有端口，取关联监听器的端口，未关联监听器时取后端服务器的端口
+	lblPortMap := make(map[string]int64, len(cloudListeners))
+	for _, lbl := range cloudListeners {
+		lblPortMap[lbl.Id] = int64(lbl.ProtocolPort)
+	}
+	poolPortMap := make(map[string]int64, len(cloudPools))
+	validPools := make([]typeslb.HuaWeiPool, 0, len(cloudPools))
+	for _, pool := range cloudPools {
+		port := getPoolPort(pool, lblPortMap, cloudMembers[pool.Id])
+		if port == 0 {
+			logs.Warnf("[%s] pool(%s) has no listener or member, skip sync, rid: %s", enumor.HuaWei, pool.Id,
+				kt.Rid)
+			continue
+		}
+		poolPortMap[pool.Id] = port
+		validPools = append(validPools, pool)
+	}
+
+	tgMap := make(map[string]string, len(validPools))
+	for _, batch := range slice.Split(validPools, constant.BatchOperationMaxLimit) {
+		cloudIDs := slice.Map(batch, typeslb.HuaWeiPool.GetCloudID)
+		dbTGs, err := cli.listTargetGroupFromDB(kt, accountID, region, cloudIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		isChange := func(cloud typeslb.HuaWeiPool, db corelb.HuaWeiTargetGroup) bool {
+			return isTargetGroupChange(cloud, db, poolPortMap[cloud.Id])
+		}
+		addSlice, updateMap, _ := common.Diff[typeslb.HuaWeiPool, corelb.HuaWeiTargetGroup](batch, dbTGs, isChange)
+
+		if err = cli.createTargetGroup(kt, accountID, region, lb, poolPortMap, addSlice); err != nil {
+			return nil, err
+		}
+		if err = cli.updateTargetGroup(kt, poolPortMap, updateMap); err != nil {
+			return nil, err
+		}
+
+		if len(addSlice) > 0 {
+			if dbTGs, err = cli.listTargetGroupFromDB(kt, accountID, region, cloudIDs); err != nil {
+				return nil, err
+			}
+		}
+		for _, tg := range dbTGs {
+			tgMap[tg.CloudID] = tg.ID
+		}
+	}
+
+	for cloudPoolID, tgID := range tgMap {
+		if err = cli.target(kt, accountID, region, tgID, cloudPoolID, cloudMembers[cloudPoolID]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = cli.removeTargetGroupOfLb(kt, accountID, region, lb, cloudPools); err != nil {
+		return nil, err
+	}
+
+	return tgMap, nil
+}
+
+// removeTargetGroupOfLb 删除本地存在但云上已删除的后端服务器组，华为云后端服务器组必须归属于某个负载均衡
+func (cli *client) removeTargetGroupOfLb(kt *kit.Kit, accountID string, region string,
+	lb *corelb.HuaWeiLoadBalancer, cloudPools []typeslb.HuaWeiPool) error {
+
+	cloudIDMap := cvt.StringSliceToMap(slice.Map(cloudPools, typeslb.HuaWeiPool.GetCloudID))
+
+	delIDs := make([]string, 0)
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("cloud_vpc_id", lb.CloudVpcID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	for {
+		dbTGs, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list target group from db failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+			return err
+		}
+		for _, tg := range dbTGs.Details {
+			if _, exists := cloudIDMap[tg.CloudID]; exists {
+				continue
+			}
+			detail, err := cli.dbCli.HuaWei.LoadBalancer.GetTargetGroup(kt, tg.ID)
+			if err != nil {
+				logs.Errorf("[%s] get target group failed, err: %v, id: %s, rid: %s", enumor.HuaWei, err, tg.ID,
+					kt.Rid)
+				return err
+			}
+			if detail.Extension != nil && slice.IsItemInSlice(detail.Extension.CloudLoadBalancerIDs, lb.CloudID) {
+				delIDs = append(delIDs, tg.ID)
+			}
+		}
+		if uint(len(dbTGs.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	for _, ids := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &core.ListReq{Filter: tools.ContainersExpression("id", ids), Page: core.NewDefaultBasePage()}
+		if err := cli.dbCli.Global.LoadBalancer.DeleteTargetGroup(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete target group failed, err: %v, ids: %v, rid: %s", enumor.HuaWei, err, ids,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listTargetGroupFromDB(kt *kit.Kit, accountID string, region string, cloudIDs []string) (
+	[]corelb.HuaWeiTargetGroup, error) {
+
+	if len(cloudIDs) == 0 {
+		return nil, nil
+	}
+
+	listReq := &core.ListReq{
+		Fields: []string{"id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+			tools.RuleIn("cloud_id", cloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	tgResp, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, listReq)
+	if err != nil {
+		logs.Errorf("[%s] list target group from db failed, err: %v, cloud_ids: %v, rid: %s", enumor.HuaWei, err,
+			cloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	// 扩展字段只能通过详情接口获取
+	result := make([]corelb.HuaWeiTargetGroup, 0, len(tgResp.Details))
+	for _, one := range tgResp.Details {
+		tg, err := cli.dbCli.HuaWei.LoadBalancer.GetTargetGroup(kt, one.ID)
+		if err != nil {
+			logs.Errorf("[%s] get target group from db failed, err: %v, id: %s, rid: %s", enumor.HuaWei, err,
+				one.ID, kt.Rid)
+			return nil, err
+		}
+		result = append(result, *tg)
+	}
+
+	return result, nil
+}
+
+func (cli *client) createTargetGroup(kt *kit.Kit, accountID string, region string, lb *corelb.HuaWeiLoadBalancer,
+	poolPortMap map[string]int64, addSlice []typeslb.HuaWeiPool) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	createReq := new(protocloud.HuaWeiTargetGroupCreateReq)
+	for _, pool := range addSlice {
+		createReq.TargetGroups = append(createReq.TargetGroups,
+			protocloud.TargetGroupBatchCreate[corelb.HuaWeiTargetGroupExtension]{
+				CloudID:         pool.GetCloudID(),
+				Name:            getPoolName(pool),
+				Vendor:          enumor.HuaWei,
+				AccountID:       accountID,
+				BkBizID:         lb.BkBizID,
+				Region:          region,
+				Protocol:        enumor.ProtocolType(pool.Protocol),
+				Port:            poolPortMap[pool.Id],
+				VpcID:           lb.VpcID,
+				CloudVpcID:      lb.CloudVpcID,
+				TargetGroupType: enumor.CloudTargetGroupType,
+				Memo:            cvt.ValToPtr(pool.Description),
+				Extension:       convTargetGroupExtension(pool),
+			})
+	}
+
+	if _, err := cli.dbCli.HuaWei.LoadBalancer.BatchCreateHuaWeiTargetGroup(kt, createReq); err != nil {
+		logs.Errorf("[%s] request dataservice to create target group failed, err: %v, rid: %s", enumor.HuaWei,
+			err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) updateTargetGroup(kt *kit.Kit, poolPortMap map[string]int64,
+	updateMap map[string]typeslb.HuaWeiPool) error {
+
+	for id, pool := range updateMap {
+		ext, err := types.NewJsonField(convTargetGroupExtension(pool))
+		if err != nil {
+			return fmt.Errorf("marshal target group(%s) extension failed, err: %v", id, err)
+		}
+		updateReq := &protocloud.TargetGroupUpdateReq{
+			IDs:       []string{id},
+			Name:      getPoolName(pool),
+			Protocol:  enumor.ProtocolType(pool.Protocol),
+			Port:      poolPortMap[pool.Id],
+			Extension: ext,
+		}
+		if err := cli.dbCli.HuaWei.LoadBalancer.BatchUpdateHuaWeiTargetGroup(kt, updateReq); err != nil {
+			logs.Errorf("[%s] request dataservice to update target group failed, err: %v, id: %s, rid: %s",
+				enumor.HuaWei, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getPoolPort(pool typeslb.HuaWeiPool, lblPortMap map[string]int64, members []typeslb.HuaWeiMember) int64 {
+	for _, cloudLblID := range pool.GetCloudListenerIDs() {
+		if port, exists := lblPortMap[cloudLblID]; exists {
+			return port
+		}
+	}
+	for _, member := range members {
+		if member.ProtocolPort != 0 {
+			return int64(member.ProtocolPort)
+		}
+	}
+	return 0
+}
+
+// getPoolName 后端服务器组名称为空时使用ID作为名称
+func getPoolName(pool typeslb.HuaWeiPool) string {
+	if len(pool.Name) != 0 {
+		return pool.Name
+	}
+	return pool.Id
+}
+
+func convTargetGroupExtension(pool typeslb.HuaWeiPool) *corelb.HuaWeiTargetGroupExtension {
+	ext := &corelb.HuaWeiTargetGroupExtension{
+		LbAlgorithm:                    cvt.ValToPtr(pool.LbAlgorithm),
+		IpVersion:                      cvt.ValToPtr(pool.IpVersion),
+		MemberDeletionProtectionEnable: cvt.ValToPtr(pool.MemberDeletionProtectionEnable),
+		CloudListenerIDs:               pool.GetCloudListenerIDs(),
+		CloudLoadBalancerIDs:           pool.GetCloudLoadBalancerIDs(),
+	}
+	if len(pool.HealthmonitorId) != 0 {
+		ext.CloudHealthMonitorID = cvt.ValToPtr(pool.HealthmonitorId)
+	}
+	if pool.SessionPersistence != nil {
+		ext.SessionPersistenceType = cvt.ValToPtr(pool.SessionPersistence.Type)
+		if pool.SessionPersistence.PersistenceTimeout != nil {
+			ext.SessionPersistenceTimeout = cvt.ValToPtr(int64(*pool.SessionPersistence.PersistenceTimeout))
+		}
+	}
+	return ext
+}
+
+func isTargetGroupChange(cloud typeslb.HuaWeiPool, db corelb.HuaWeiTargetGroup, port int64) bool {
+	if db.Name != getPoolName(cloud) {
+		return true
+	}
+	if db.Protocol != enumor.ProtocolType(cloud.Protocol) {
+		return true
+	}
+	if db.Port != port {
+		return true
+	}
+	if db.Extension == nil {
+		return true
+	}
+	if cvt.PtrToVal(db.Extension.LbAlgorithm) != cloud.LbAlgorithm {
+		return true
+	}
+	return cvt.PtrToVal(db.Extension.CloudHealthMonitorID) != cloud.HealthmonitorId
+}
+
+// -------------------------- Member --------------------------
+
+// TargetGroupTarget 同步单个后端服务器组中的后端服务器，用于添加/移除后端服务器后刷新本地数据
+func (cli *client) TargetGroupTarget(kt *kit.Kit, accountID string, region string, tgID string,
+	cloudPoolID string) error {
+
+	memberOpt := &typeslb.HuaWeiListMembersOption{Region: region, CloudPoolID: cloudPoolID}
+	members, err := cli.cloudCli.ListMember(kt, memberOpt)
+	if err != nil {
+		logs.Errorf("[%s] list member from cloud failed, err: %v, opt: %+v, rid: %s", enumor.HuaWei, err,
+			memberOpt, kt.Rid)
+		return err
+	}
+
+	return cli.target(kt, accountID, region, tgID, cloudPoolID, members)
+}
+
+// target 同步后端服务器组中的后端服务器，以 {IP}-{端口} 作为对比标识
+func (cli *client) target(kt *kit.Kit, accountID string, region string, tgID string, cloudPoolID string,
+	cloudMembers []typeslb.HuaWeiMember) error {
+
+	dbTargets, err := cli.listTargetFromDB(kt, tgID)
+	if err != nil {
+		return err
+	}
+
+	dbMap := make(map[string]corelb.BaseTarget, len(dbTargets))
+	for _, one := range dbTargets {
+		dbMap[one.GetCloudID()] = one
+	}
+
+	addTargets := make([]*protocloud.TargetBaseReq, 0)
+	updateTargets := make([]*protocloud.TargetUpdate, 0)
+	for _, member := range cloudMembers {
+		key := fmt.Sprintf("%s-%d", member.Address, member.ProtocolPort)
+		if db, exists := dbMap[key]; exists {
+			delete(dbMap, key)
+			if cvt.PtrToVal(db.Weight) != int64(member.Weight) {
+				updateTargets = append(updateTargets, &protocloud.TargetUpdate{
+					ID:     db.ID,
+					Weight: cvt.ValToPtr(int64(member.Weight)),
+				})
+			}
+			continue
+		}
+		addTargets = append(addTargets, convHuaWeiMember(member, accountID, region, tgID, cloudPoolID))
+	}
+
+	delIDs := make([]string, 0, len(dbMap))
+	for _, one := range dbMap {
+		delIDs = append(delIDs, one.ID)
+	}
+
+	for _, ids := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &protocloud.LoadBalancerBatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+		if err = cli.dbCli.Global.LoadBalancer.BatchDeleteTarget(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete target failed, err: %v, ids: %v, rid: %s", enumor.HuaWei, err, ids, kt.Rid)
+			return err
+		}
+	}
+
+	for _, targets := range slice.Split(addTargets, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.TargetBatchCreateReq{Targets: targets}
+		if _, err = cli.dbCli.Global.LoadBalancer.BatchCreateTCloudTarget(kt, createReq); err != nil {
+			logs.Errorf("[%s] create target failed, err: %v, tg: %s, rid: %s", enumor.HuaWei, err, tgID, kt.Rid)
+			return err
+		}
+	}
+
+	for _, targets := range slice.Split(updateTargets, constant.BatchOperationMaxLimit) {
+		updateReq := &protocloud.TargetBatchUpdateReq{Targets: targets}
+		if err = cli.dbCli.Global.LoadBalancer.BatchUpdateTarget(kt, updateReq); err != nil {
+			logs.Errorf("[%s] update target failed, err: %v, tg: %s, rid: %s", enumor.HuaWei, err, tgID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listTargetFromDB(kt *kit.Kit, tgID string) ([]corelb.BaseTarget, error) {
+	result := make([]corelb.BaseTarget, 0)
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("target_group_id", tgID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.dbCli.Global.LoadBalancer.ListTarget(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list target from db failed, err: %v, tg: %s, rid: %s", enumor.HuaWei, err, tgID,
+				kt.Rid)
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return result, nil
+}
+
+func convHuaWeiMember(member typeslb.HuaWeiMember, accountID string, region string, tgID string,
+	cloudPoolID string) *protocloud.TargetBaseReq {
+
+	target := &protocloud.TargetBaseReq{
+		IP:                 member.Address,
+		Port:               int64(member.ProtocolPort),
+		Weight:             cvt.ValToPtr(int64(member.Weight)),
+		AccountID:          accountID,
+		TargetGroupID:      tgID,
+		CloudTargetGroupID: cloudPoolID,
+		TargetGroupRegion:  region,
+		InstName:           member.Name,
+		PrivateIPAddress:   []string{member.Address},
+	}
+	// 关联了云服务器的后端服务器记录实例ID，否则为跨VPC的IP类型后端
+	if instID := cvt.PtrToVal(member.InstanceId); len(instID) != 0 {
+		target.InstType = enumor.CvmInstType
+		target.CloudInstID = instID
+	} else {
+		target.InstType = enumor.EniInstType
+	}
+
+	return target
+}
+
+// -------------------------- Listener --------------------------
+
+func (cli *client) listListenerFromCloud(kt *kit.Kit, region string, cloudLbID string) ([]typeslb.HuaWeiListener,
+	error) {
+
+	listOpt := &typeslb.HuaWeiListListenersOption{Region: region, CloudLoadBalancerID: cloudLbID}
+	listeners, err := cli.cloudCli.ListListener(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list listener from cloud failed, err: %v, opt: %+v, rid: %s", enumor.HuaWei, err,
+			listOpt, kt.Rid)
+		return nil, err
+	}
+
+	return listeners, nil
+}
+
+// listenerByLb 同步负载均衡下的监听器及监听器下的规则
+func (cli *client) listenerByLb(kt *kit.Kit, region string, lb *corelb.HuaWeiLoadBalancer,
+	cloudListeners []typeslb.HuaWeiListener, tgMap map[string]string) error {
+
+	dbListeners, err := cli.listListenerFromDB(kt, lb.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(cloudListeners) == 0 && len(dbListeners) == 0 {
+		return nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.HuaWeiListener, corelb.HuaWeiListener](cloudListeners,
+		dbListeners, isListenerChange)
+
+	if err = cli.deleteListener(kt, region, delCloudIDs); err != nil {
+		return err
+	}
+	if err = cli.createListener(kt, region, lb, addSlice); err != nil {
+		return err
+	}
+	if err = cli.updateListener(kt, region, lb.BkBizID, updateMap); err != nil {
+		return err
+	}
+
+	if dbListeners, err = cli.listListenerFromDB(kt, lb.ID); err != nil {
+		return err
+	}
+	cloudLblMap := make(map[string]typeslb.HuaWeiListener, len(cloudListeners))
+	for _, one := range cloudListeners {
+		cloudLblMap[one.Id] = one
+	}
+	for i := range dbListeners {
+		cloudLbl, exists := cloudLblMap[dbListeners[i].CloudID]
+		if !exists {
+			continue
+		}
+		if err = cli.listenerRule(kt, region, lb, &dbListeners[i], cloudLbl, tgMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listListenerFromDB(kt *kit.Kit, lbID string) ([]corelb.HuaWeiListener, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lb_id", lbID),
+			tools.RuleEqual("vendor", enumor.HuaWei),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	resp, err := cli.dbCli.HuaWei.LoadBalancer.ListListener(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] list listener of lb(%s) from db failed, err: %v, rid: %s", enumor.HuaWei, lbID, err,
+			kt.Rid)
+		return nil, err
+	}
+
+	return resp.Details, nil
+}
+
+func (cli *client) deleteListener(kt *kit.Kit, region string, cloudIDs []string) error {
+	if len(cloudIDs) == 0 {
+		return nil
+	}
+
+	delReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", cloudIDs),
+			tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleEqual("region", region),
+		),
+	}
+	if err := cli.dbCli.Global.LoadBalancer.DeleteListener(kt, delReq); err != nil {
+		logs.Errorf("[%s] delete listener failed, err: %v, cloud_ids: %v, rid: %s", enumor.HuaWei, err, cloudIDs,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) createListener(kt *kit.Kit, region string, lb *corelb.HuaWeiLoadBalancer,
+	addSlice []typeslb.HuaWeiListener) error {
+
+	if len(addSlice) == 0 {
+		return nil
+	}
+
+	createReq := new(protocloud.HuaWeiListenerBatchCreateReq)
+	for _, lbl := range addSlice {
+		createReq.Listeners = append(createReq.Listeners,
+			protocloud.ListenersCreateReq[corelb.HuaWeiListenerExtension]{
+				CloudID:   lbl.GetCloudID(),
+				Name:      lbl.GetName(),
+				Vendor:    enumor.HuaWei,
+				AccountID: lb.AccountID,
+				BkBizID:   lb.BkBizID,
+				LbID:      lb.ID,
+				CloudLbID: lb.CloudID,
+				Protocol:  lbl.GetProtocol(),
+				Port:      int64(lbl.ProtocolPort),
+				Region:    region,
+				Extension: convListenerExtension(lbl),
+			})
+	}
+
+	if _, err := cli.dbCli.HuaWei.LoadBalancer.BatchCreateHuaWeiListener(kt, createReq); err != nil {
+		logs.Errorf("[%s] create listener failed, err: %v, lb: %s, rid: %s", enumor.HuaWei, err, lb.CloudID,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) updateListener(kt *kit.Kit, region string, bizID int64,
+	updateMap map[string]typeslb.HuaWeiListener) error {
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	updateReq := new(protocloud.HuaWeiListenerUpdateReq)
+	for id, lbl := range updateMap {
+		updateReq.Listeners = append(updateReq.Listeners,
+			&protocloud.ListenerUpdateReq[corelb.HuaWeiListenerExtension]{
+				ID:        id,
+				Name:      lbl.GetName(),
+				BkBizID:   bizID,
+				Region:    region,
+				Extension: convListenerExtension(lbl),
+			})
+	}
+
+	if err := cli.dbCli.HuaWei.LoadBalancer.BatchUpdateHuaWeiListener(kt, updateReq); err != nil {
+		logs.Errorf("[%s] update listener failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func convListenerExtension(lbl typeslb.HuaWeiListener) *corelb.HuaWeiListenerExtension {
+	ext := &corelb.HuaWeiListenerExtension{
+		SniContainerRefs: lbl.SniContainerRefs,
+	}
+	if len(lbl.DefaultPoolId) != 0 {
+		ext.CloudDefaultPoolID = cvt.ValToPtr(lbl.DefaultPoolId)
+	}
+	if len(lbl.DefaultTlsContainerRef) != 0 {
+		ext.DefaultTlsContainerRef = cvt.ValToPtr(lbl.DefaultTlsContainerRef)
+	}
+	if len(lbl.ClientCaTlsContainerRef) != 0 {
+		ext.ClientCaTlsContainerRef = cvt.ValToPtr(lbl.ClientCaTlsContainerRef)
+	}
+	if len(lbl.TlsCiphersPolicy) != 0 {
+		ext.TlsCiphersPolicy = cvt.ValToPtr(lbl.TlsCiphersPolicy)
+	}
+	return ext
+}
+
+func isListenerChange(cloud typeslb.HuaWeiListener, db corelb.HuaWeiListener) bool {
+	if db.Name != cloud.GetName() {
+		return true
+	}
+	if db.Protocol != cloud.GetProtocol() {
+		return true
+	}
+	if db.Port != int64(cloud.ProtocolPort) {
+		return true
+	}
+	if db.Extension == nil {
+		return true
+	}
+	if cvt.PtrToVal(db.Extension.CloudDefaultPoolID) != cloud.DefaultPoolId {
+		return true
+	}
+	if cvt.PtrToVal(db.Extension.DefaultTlsContainerRef) != cloud.DefaultTlsContainerRef {
+		return true
+	}
+	if !assert.IsStringSliceEqual(db.Extension.SniContainerRefs, cloud.SniContainerRefs) {
+		return true
+	}
+	return false
+}
+
+// -------------------------- Rule --------------------------
+
+// listenerRule 同步监听器下的规则，监听器默认后端服务器组作为四层规则(规则ID与监听器ID一致)，转发策略作为七层规则
+func (cli *client) listenerRule(kt *kit.Kit, region string, lb *corelb.HuaWeiLoadBalancer,
+	lbl *corelb.HuaWeiListener, cloudLbl typeslb.HuaWeiListener, tgMap map[string]string) error {
+
+	cloudRules := make(map[string]protocloud.TCloudUrlRuleCreate)
+	if len(cloudLbl.DefaultPoolId) != 0 {
+		cloudRules[lbl.CloudID] = convRule(lb, lbl, region, lbl.CloudID, enumor.Layer4RuleType, "", "",
+			cloudLbl.DefaultPoolId, tgMap)
+	}
+
+	if cloudLbl.GetProtocol().IsLayer7Protocol() {
+		listOpt := &typeslb.HuaWeiListL7PoliciesOption{Region: region, CloudListenerID: lbl.CloudID}
+		policies, err := cli.cloudCli.ListL7Policy(kt, listOpt)
+		if err != nil {
+			logs.Errorf("[%s] list l7 policy from cloud failed, err: %v, opt: %+v, rid: %s", enumor.HuaWei, err,
+				listOpt, kt.Rid)
+			return err
+		}
+		for _, policy := range policies {
+			cloudRules[policy.Id] = convRule(lb, lbl, region, policy.Id, enumor.Layer7RuleType, policy.GetDomain(),
+				policy.GetURL(), policy.RedirectPoolId, tgMap)
+		}
+	}
+
+	dbRules, err := cli.listRuleFromDB(kt, lbl.ID)
+	if err != nil {
+		return err
+	}
+
+	delCloudIDs := make([]string, 0)
+	updateRules := make([]*protocloud.TCloudUrlRuleUpdate, 0)
+	for _, db := range dbRules {
+		cloud, exists := cloudRules[db.CloudID]
+		if !exists {
+			delCloudIDs = append(delCloudIDs, db.CloudID)
+			continue
+		}
+		delete(cloudRules, db.CloudID)
+
+		if db.Domain != cloud.Domain || db.URL != cloud.URL || db.CloudTargetGroupID != cloud.CloudTargetGroupID {
+			updateRules = append(updateRules, &protocloud.TCloudUrlRuleUpdate{
+				ID:                 db.ID,
+				TargetGroupID:      cloud.TargetGroupID,
+				CloudTargetGroupID: cloud.CloudTargetGroupID,
+				Domain:             cloud.Domain,
+				URL:                cloud.URL,
+			})
+		}
+	}
+
+	if err = cli.deleteRule(kt, region, delCloudIDs); err != nil {
+		return err
+	}
+
+	addRules := make([]protocloud.TCloudUrlRuleCreate, 0, len(cloudRules))
+	for _, rule := range cloudRules {
+		addRules = append(addRules, rule)
+	}
+	for _, batch := range slice.Split(addRules, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.TCloudUrlRuleBatchCreateReq{UrlRules: batch}
+		if _, err = cli.dbCli.HuaWei.LoadBalancer.BatchCreateUrlRule(kt, createReq); err != nil {
+			logs.Errorf("[%s] create rule failed, err: %v, listener: %s, rid: %s", enumor.HuaWei, err,
+				lbl.CloudID, kt.Rid)
+			return err
+		}
+	}
+
+	for _, batch := range slice.Split(updateRules, constant.BatchOperationMaxLimit) {
+		updateReq := &protocloud.TCloudUrlRuleBatchUpdateReq{UrlRules: batch}
+		if err = cli.dbCli.HuaWei.LoadBalancer.BatchUpdateUrlRule(kt, updateReq); err != nil {
+			logs.Errorf("[%s] update rule failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listRuleFromDB(kt *kit.Kit, lblID string) ([]corelb.TCloudLbUrlRule, error) {
+	result := make([]corelb.TCloudLbUrlRule, 0)
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("lbl_id", lblID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.dbCli.HuaWei.LoadBalancer.ListUrlRule(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list rule of listener(%s) from db failed, err: %v, rid: %s", enumor.HuaWei, lblID,
+				err, kt.Rid)
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return result, nil
+}
+
+func (cli *client) deleteRule(kt *kit.Kit, region string, cloudIDs []string) error {
+	for _, ids := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		delReq := &protocloud.LoadBalancerBatchDeleteReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleIn("cloud_id", ids),
+				tools.RuleEqual("region", region),
+			),
+		}
+		if err := cli.dbCli.HuaWei.LoadBalancer.BatchDeleteUrlRule(kt, delReq); err != nil {
+			logs.Errorf("[%s] delete rule failed, err: %v, cloud_ids: %v, rid: %s", enumor.HuaWei, err, ids,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convRule(lb *corelb.HuaWeiLoadBalancer, lbl *corelb.HuaWeiListener, region string, cloudID string,
+	ruleType enumor.RuleType, domain string, url string, cloudPoolID string,
+	tgMap map[string]string) protocloud.TCloudUrlRuleCreate {
+
+	return protocloud.TCloudUrlRuleCreate{
+		Vendor:             enumor.HuaWei,
+		LbID:               lb.ID,
+		CloudLbID:          lb.CloudID,
+		LblID:              lbl.ID,
+		CloudLBLID:         lbl.CloudID,
+		CloudID:            cloudID,
+		RuleType:           ruleType,
+		TargetGroupID:      tgMap[cloudPoolID],
+		CloudTargetGroupID: cloudPoolID,
+		Region:             region,
+		Domain:             domain,
+		URL:                url,
+		HealthCheck:        new(corelb.TCloudHealthCheckInfo),
+		Certificate:        new(corelb.TCloudCertificateInfo),
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// syncTagsByTms 华为云 eip、子网、安全组的列表接口不返回标签，通过标签管理服务查询资源标签后同步到资源标签表
+func (cli *client) syncTagsByTms(kt *kit.Kit, accountID, region string, resType enumor.CloudResourceType,
+	cloudIDs []string) error {
+
+	if len(cloudIDs) == 0 {
+		return nil
+	}
+
+	opt := &restag.ListResTagOption{
+		Region:   region,
+		ResType:  resType,
+		CloudIDs: cloudIDs,
+	}
+	tagMap, err := cli.cloudCli.ListResourceTags(kt, opt)
+	if err != nil {
+		logs.Errorf("[%s] list %s resource tags from cloud failed, err: %v, account: %s, rid: %s", enumor.HuaWei,
+			resType, err, accountID, kt.Rid)
+		return err
+	}
+
+	return common.SyncResourceTags(kt, cli.dbCli, enumor.HuaWei, accountID, resType, common.NewCloudResTags(tagMap))
+}
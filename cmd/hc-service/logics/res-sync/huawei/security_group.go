@@ -37,6 +37,7 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
 )
 
 // SyncSGOption ...
@@ -90,6 +91,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	// 同步资源标签
+	if err = cli.syncTagsByTms(kt, params.AccountID, params.Region, enumor.SecurityGroupCloudResType,
+		slice.Map(sgFromCloud, securitygroup.HuaWeiSG.GetCloudID)); err != nil {
+		return nil, err
+	}
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -38,6 +38,7 @@ import (
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/assert"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
 )
 
 // SyncSubnetOption ...
@@ -91,6 +92,12 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
+	// 同步资源标签
+	if err = cli.syncTagsByTms(kt, params.AccountID, params.Region, enumor.SubnetCloudResType,
+		slice.Map(subnetFromCloud, adtysubnet.HuaWeiSubnet.GetCloudID)); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
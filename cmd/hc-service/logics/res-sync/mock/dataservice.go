@@ -0,0 +1,418 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package mock 提供 res-sync 单元测试使用的内存版 data-service。
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/rest"
+	"hcm/pkg/rest/client"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Write data-service 写操作记录
+type Write struct {
+	// Op create、update、delete、upsert
+	Op    string
+	Table string
+	// CloudIDs 本次写操作涉及的资源云ID
+	CloudIDs []string
+}
+
+// List data-service 列表查询记录
+type List struct {
+	// Vendor 通过云厂商接口查询时为云厂商，通过全局接口查询时为空
+	Vendor string
+	Table  string
+	Fields []string
+	Start  uint32
+	Limit  uint32
+}
+
+// DataService 内存版 data-service，按表保存资源，支持列表查询、批量创建、更新、删除及资源标签更新，
+// 并按顺序记录全部读写操作。表名与 data-service 接口路径中的资源名一致，如 vpcs、subnets、disks、eips。
+type DataService struct {
+	server *httptest.Server
+
+	lock   sync.Mutex
+	seq    int
+	tables map[string][]map[string]any
+	writes []Write
+	lists  []List
+}
+
+// NewDataService 启动内存版 data-service，使用完毕后需调用 Close
+func NewDataService() *DataService {
+	ds := &DataService{tables: make(map[string][]map[string]any)}
+	ds.server = httptest.NewServer(http.HandlerFunc(ds.serve))
+	return ds
+}
+
+// Close 关闭 data-service
+func (ds *DataService) Close() {
+	ds.server.Close()
+}
+
+// Client 返回访问该 data-service 的客户端
+func (ds *DataService) Client() *dataservice.Client {
+	cli, err := client.NewClient(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	c := &client.Capability{
+		Client:     cli,
+		Discover:   &discovery{server: ds.server.URL + "/"},
+		MetricOpts: client.MetricOption{Register: prometheus.NewRegistry()},
+	}
+	return dataservice.NewClient(c, "v1")
+}
+
+// Insert 向表中插入数据，数据需包含 id 和 cloud_id
+func (ds *DataService) Insert(table string, records ...map[string]any) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	for _, one := range records {
+		ds.tables[table] = append(ds.tables[table], normalize(one))
+	}
+}
+
+// Records 返回表中的全部数据，按 id 排序
+func (ds *DataService) Records(table string) []map[string]any {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	return ds.sorted(table)
+}
+
+// CloudIDs 返回表中全部数据的云ID，按 id 排序
+func (ds *DataService) CloudIDs(table string) []string {
+	cloudIDs := make([]string, 0)
+	for _, one := range ds.Records(table) {
+		cloudIDs = append(cloudIDs, fmt.Sprint(one["cloud_id"]))
+	}
+	return cloudIDs
+}
+
+// Writes 返回写操作记录
+func (ds *DataService) Writes() []Write {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	return append([]Write(nil), ds.writes...)
+}
+
+// Lists 返回列表查询记录
+func (ds *DataService) Lists() []List {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	return append([]List(nil), ds.lists...)
+}
+
+// Reset 清空读写操作记录，不清空数据
+func (ds *DataService) Reset() {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	ds.writes = nil
+	ds.lists = nil
+}
+
+func (ds *DataService) serve(w http.ResponseWriter, r *http.Request) {
+	var body any
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		reply(w, nil, fmt.Errorf("decode request body failed, err: %v", err))
+		return
+	}
+
+	path := r.URL.Path[strings.Index(r.URL.Path, "/data/")+len("/data/"):]
+	vendor := ""
+	if strings.HasPrefix(path, "vendors/") {
+		segments := strings.SplitN(path, "/", 3)
+		vendor, path = segments[1], segments[2]
+	}
+	table, action, _ := strings.Cut(path, "/")
+
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && action == "list":
+		reply(w, ds.list(vendor, table, body), nil)
+	case r.Method == http.MethodPost && action == "batch/create":
+		reply(w, ds.create(vendor, table, body), nil)
+	case r.Method == http.MethodPatch && (action == "batch" || action == ""):
+		ds.update(table, body)
+		reply(w, nil, nil)
+	case r.Method == http.MethodDelete && action == "batch":
+		ds.delete(table, body)
+		reply(w, nil, nil)
+	case r.Method == http.MethodPost && action == "batch/upsert":
+		ds.upsert(table, body)
+		reply(w, nil, nil)
+	default:
+		reply(w, nil, fmt.Errorf("%s %s is not supported", r.Method, r.URL.Path))
+	}
+}
+
+func (ds *DataService) list(vendor, table string, body any) map[string]any {
+	req, _ := body.(map[string]any)
+	page, _ := req["page"].(map[string]any)
+	start, limit := toInt(page["start"]), toInt(page["limit"])
+	fields := make([]string, 0)
+	if list, ok := req["fields"].([]any); ok {
+		for _, one := range list {
+			fields = append(fields, fmt.Sprint(one))
+		}
+	}
+	ds.lists = append(ds.lists, List{Vendor: vendor, Table: table, Fields: fields, Start: uint32(start),
+		Limit: uint32(limit)})
+
+	matched := make([]map[string]any, 0)
+	for _, one := range ds.sorted(table) {
+		if match(one, req["filter"]) {
+			matched = append(matched, one)
+		}
+	}
+
+	if count, _ := page["count"].(bool); count {
+		return map[string]any{"count": len(matched), "details": []any{}}
+	}
+
+	details := make([]map[string]any, 0)
+	for idx := start; idx < len(matched) && (limit == 0 || idx < start+limit); idx++ {
+		details = append(details, matched[idx])
+	}
+	return map[string]any{"count": len(matched), "details": details}
+}
+
+func (ds *DataService) create(vendor, table string, body any) map[string]any {
+	ids := make([]string, 0)
+	cloudIDs := make([]string, 0)
+	for _, item := range items(body) {
+		ds.seq++
+		record := normalize(item)
+		record["id"] = fmt.Sprintf("%08d", ds.seq)
+		record["vendor"] = vendor
+		ds.tables[table] = append(ds.tables[table], record)
+		ids = append(ids, record["id"].(string))
+		cloudIDs = append(cloudIDs, fmt.Sprint(record["cloud_id"]))
+	}
+
+	ds.writes = append(ds.writes, Write{Op: "create", Table: table, CloudIDs: cloudIDs})
+	return map[string]any{"ids": ids}
+}
+
+func (ds *DataService) update(table string, body any) {
+	cloudIDs := make([]string, 0)
+	for _, item := range items(body) {
+		for _, record := range ds.tables[table] {
+			if record["id"] != item["id"] {
+				continue
+			}
+			merge(record, item)
+			cloudIDs = append(cloudIDs, fmt.Sprint(record["cloud_id"]))
+		}
+	}
+
+	sort.Strings(cloudIDs)
+	ds.writes = append(ds.writes, Write{Op: "update", Table: table, CloudIDs: cloudIDs})
+}
+
+func (ds *DataService) delete(table string, body any) {
+	req, _ := body.(map[string]any)
+	cloudIDs := make([]string, 0)
+	remain := make([]map[string]any, 0)
+	for _, record := range ds.tables[table] {
+		if match(record, req["filter"]) {
+			cloudIDs = append(cloudIDs, fmt.Sprint(record["cloud_id"]))
+			continue
+		}
+		remain = append(remain, record)
+	}
+	ds.tables[table] = remain
+
+	sort.Strings(cloudIDs)
+	ds.writes = append(ds.writes, Write{Op: "delete", Table: table, CloudIDs: cloudIDs})
+}
+
+func (ds *DataService) upsert(table string, body any) {
+	req, _ := body.(map[string]any)
+	cloudIDs := make([]string, 0)
+	if resources, ok := req["resources"].([]any); ok {
+		for _, one := range resources {
+			if res, ok := one.(map[string]any); ok {
+				cloudIDs = append(cloudIDs, fmt.Sprint(res["cloud_res_id"]))
+			}
+		}
+	}
+
+	sort.Strings(cloudIDs)
+	ds.writes = append(ds.writes, Write{Op: "upsert", Table: table, CloudIDs: cloudIDs})
+}
+
+func (ds *DataService) sorted(table string) []map[string]any {
+	records := append([]map[string]any(nil), ds.tables[table]...)
+	sort.Slice(records, func(i, j int) bool {
+		return fmt.Sprint(records[i]["id"]) < fmt.Sprint(records[j]["id"])
+	})
+	return records
+}
+
+// items 批量请求中的数据，请求体为数组，或者包含唯一一个数组字段的对象
+func items(body any) []map[string]any {
+	var list []any
+	switch val := body.(type) {
+	case []any:
+		list = val
+	case map[string]any:
+		for _, field := range val {
+			if one, ok := field.([]any); ok {
+				list = one
+			}
+		}
+	}
+
+	result := make([]map[string]any, 0, len(list))
+	for _, one := range list {
+		if item, ok := one.(map[string]any); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// match 判断数据是否满足过滤条件，支持 and、or 组合及 eq、neq、in、nin 操作符
+func match(record map[string]any, expr any) bool {
+	rule, ok := expr.(map[string]any)
+	if !ok {
+		return true
+	}
+
+	op := fmt.Sprint(rule["op"])
+	if rules, ok := rule["rules"].([]any); ok {
+		for _, one := range rules {
+			matched := match(record, one)
+			if op == "or" && matched {
+				return true
+			}
+			if op != "or" && !matched {
+				return false
+			}
+		}
+		return op != "or"
+	}
+
+	value := fmt.Sprint(record[fmt.Sprint(rule["field"])])
+	switch op {
+	case "eq":
+		return value == fmt.Sprint(rule["value"])
+	case "neq":
+		return value != fmt.Sprint(rule["value"])
+	case "in", "nin":
+		list, _ := rule["value"].([]any)
+		for _, one := range list {
+			if value == fmt.Sprint(one) {
+				return op == "in"
+			}
+		}
+		return op == "nin"
+	default:
+		panic(fmt.Sprintf("filter op %s is not supported", op))
+	}
+}
+
+// merge 将更新请求中的非空字段合并到数据中，对象字段逐个字段合并
+func merge(record, update map[string]any) {
+	for key, val := range update {
+		if key == "id" || val == nil {
+			continue
+		}
+
+		sub, isMap := val.(map[string]any)
+		old, oldIsMap := record[key].(map[string]any)
+		if isMap && oldIsMap {
+			merge(old, sub)
+			continue
+		}
+		record[key] = val
+	}
+}
+
+// normalize 通过json序列化将数据转换为与请求体一致的格式
+func normalize(record map[string]any) map[string]any {
+	data, err := json.Marshal(record)
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]any)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func toInt(val any) int {
+	num, ok := val.(json.Number)
+	if !ok {
+		return 0
+	}
+
+	result, _ := num.Int64()
+	return int(result)
+}
+
+func reply(w http.ResponseWriter, data any, err error) {
+	resp := map[string]any{"code": errf.OK, "message": "", "data": data}
+	if err != nil {
+		resp["code"] = errf.Unknown
+		resp["message"] = err.Error()
+	}
+
+	w.Header().Set("Content-Type", string(rest.JsonContent))
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+type discovery struct {
+	server string
+}
+
+// GetServers ...
+func (d *discovery) GetServers() ([]string, error) {
+	return []string{d.server}, nil
+}
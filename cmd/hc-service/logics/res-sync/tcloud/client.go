@@ -20,6 +20,8 @@
 package tcloud
 
 import (
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/tcloud"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/kit"
@@ -35,9 +37,11 @@ type Interface interface {
 
 	Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption) (*SyncResult, error)
 	RemoveDiskDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
+	DiskSyncer(accountID, region string, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
 
 	Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption) (*SyncResult, error)
 	RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
+	EipSyncer(accountID, region string, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
 
 	RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteTableOption) (*SyncResult, error)
 	RemoveRouteTableDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
@@ -49,12 +53,14 @@ type Interface interface {
 
 	Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOption) (*SyncResult, error)
 	RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
+	SubnetSyncer(accountID, region string, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
 
 	Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOption) (*SyncResult, error)
 	RemoveImageDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
 
 	Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption) (*SyncResult, error)
 	RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, region string) error
+	VpcSyncer(accountID, region string, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
 
 	SecurityGroupRule(kt *kit.Kit, params *SyncBaseParams, opt *SyncSGRuleOption) (*SyncResult, error)
 
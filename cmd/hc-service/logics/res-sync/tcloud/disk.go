@@ -23,12 +23,12 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	adcore "hcm/pkg/adaptor/types/core"
 	typesdisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
-	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
@@ -55,41 +55,10 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	diskFromCloud, err := cli.listDiskFromCloud(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	diskFromDB, err := cli.listDiskFromDB(kt, params)
-	if err != nil {
+	if err := cli.diskResSyncer(params.AccountID, params.Region).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addSlice, updateMap, delCloudIDs := common.Diff[typesdisk.TCloudDisk, *coredisk.Disk[coredisk.TCloudExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
-
-	if len(delCloudIDs) > 0 {
-		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addSlice) > 0 {
-		if err = cli.createDisk(kt, params.AccountID, params.Region, addSlice); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateDisk(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return new(SyncResult), nil
 }
 
@@ -357,66 +326,57 @@ func isDiskChange(cloud typesdisk.TCloudDisk, db *coredisk.Disk[coredisk.TCloudE
 
 // RemoveDiskDeleteFromCloud ...
 func (cli *client) RemoveDiskDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.ListDisk(kt, req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list disk failed, err: %v, req: %v, rid: %s", enumor.TCloud,
-				err, req, kt.Rid)
-			return err
-		}
+	return cli.diskResSyncer(accountID, region).RemoveDeletedFromCloud(kt)
+}
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+// DiskSyncer 基于通用同步器的硬盘全量同步，next 为云上硬盘分页查询函数
+func (cli *client) DiskSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+	rs := cli.diskResSyncer(accountID, region)
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		params := &SyncBaseParams{
-			AccountID: accountID,
-			Region:    region,
-			CloudIDs:  cloudIDs,
-		}
-		resultFromCloud, err := cli.listDiskFromCloud(kt, params)
-		if err != nil {
-			return err
-		}
+func (cli *client) diskResSyncer(accountID, region string) *common.ResSyncer[*SyncBaseParams, typesdisk.TCloudDisk,
+	*coredisk.Disk[coredisk.TCloudExtension]] {
 
-		// 如果有资源没有查询出来，说明数据被从云上删除
-		if len(resultFromCloud) != len(cloudIDs) {
-			cloudIDMap := converter.StringSliceToMap(cloudIDs)
-			for _, one := range resultFromCloud {
-				delete(cloudIDMap, converter.PtrToVal(one.DiskId))
+	return &common.ResSyncer[*SyncBaseParams, typesdisk.TCloudDisk, *coredisk.Disk[coredisk.TCloudExtension]]{
+		ResType: enumor.DiskCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.ListDisk(kt, req)
+			if err != nil {
+				logs.Errorf("[%s] request dataservice to list disk failed, err: %v, req: %v, rid: %s", enumor.TCloud,
+					err, req, kt.Rid)
+				return nil, err
 			}
 
-			cloudIDs := converter.MapKeyToStringSlice(cloudIDMap)
-			if err := cli.deleteDisk(kt, accountID, region, cloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
-
-		req.Page.Start += constant.BatchOperationMaxLimit
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listDiskFromCloud,
+		ListFromDB:    cli.listDiskFromDB,
+		IsChange:      isDiskChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []typesdisk.TCloudDisk) error {
+			return cli.createDisk(kt, params.AccountID, params.Region, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]typesdisk.TCloudDisk) error {
+			return cli.updateDisk(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []typesdisk.TCloudDisk) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.DiskCloudResType,
+				dataFromCloud)
+		},
 	}
-
-	return nil
 }
@@ -23,11 +23,11 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	adcore "hcm/pkg/adaptor/types/core"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/api/core"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
-	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
@@ -56,112 +56,69 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	eipFromCloud, err := cli.listEipFromCloud(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	eipFromDB, err := cli.listEipFromDB(kt, params)
-	if err != nil {
+	if err := cli.eipResSyncer(params.AccountID, params.Region, opt).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	if len(eipFromCloud) == 0 && len(eipFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.TCloudEip,
-		*dataeip.EipExtResult[dataeip.TCloudEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
-
-	if len(delCloudIDs) > 0 {
-		if err = cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addEip) > 0 {
-		if err = cli.createEip(kt, params.AccountID, addEip, opt.BkBizID); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateEip(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return new(SyncResult), nil
 }
 
 // RemoveEipDeleteFromCloud ...
 func (cli *client) RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	return cli.eipResSyncer(accountID, region, new(SyncEipOption)).RemoveDeletedFromCloud(kt)
+}
 
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.ListEip(kt, req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list eip failed, err: %v, req: %v, rid: %s", enumor.TCloud,
-				err, req, kt.Rid)
-			return err
-		}
+// EipSyncer 基于通用同步器的eip全量同步，next 为云上eip分页查询函数
+func (cli *client) EipSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+	rs := cli.eipResSyncer(accountID, region, new(SyncEipOption))
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+func (cli *client) eipResSyncer(accountID, region string, opt *SyncEipOption) *common.ResSyncer[*SyncBaseParams,
+	*typeseip.TCloudEip, *dataeip.EipExtResult[dataeip.TCloudEipExtensionResult]] {
 
-		var resultFromCloud []*typeseip.TCloudEip
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			resultFromCloud, err = cli.listEipFromCloud(kt, params)
+	return &common.ResSyncer[*SyncBaseParams, *typeseip.TCloudEip,
+		*dataeip.EipExtResult[dataeip.TCloudEipExtensionResult]]{
+		ResType: enumor.EipCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.ListEip(kt, req)
 			if err != nil {
-				return err
-			}
-		}
-
-		// 如果有资源没有查询出来，说明数据被从云上删除
-		if len(resultFromCloud) != len(cloudIDs) {
-			cloudIDMap := converter.StringSliceToMap(cloudIDs)
-			for _, one := range resultFromCloud {
-				delete(cloudIDMap, one.CloudID)
+				logs.Errorf("[%s] request dataservice to list eip failed, err: %v, req: %v, rid: %s", enumor.TCloud,
+					err, req, kt.Rid)
+				return nil, err
 			}
 
-			delCloudIDs := converter.MapKeyToStringSlice(cloudIDMap)
-			if err = cli.deleteEip(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
-
-		req.Page.Start += constant.BatchOperationMaxLimit
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listEipFromCloud,
+		ListFromDB:    cli.listEipFromDB,
+		IsChange:      isEipChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []*typeseip.TCloudEip) error {
+			return cli.createEip(kt, params.AccountID, addData, opt.BkBizID)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]*typeseip.TCloudEip) error {
+			return cli.updateEip(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []*typeseip.TCloudEip) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.EipCloudResType,
+				dataFromCloud)
+		},
 	}
-
-	return nil
 }
 
 func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
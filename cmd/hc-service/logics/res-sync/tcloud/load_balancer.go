@@ -126,6 +126,12 @@ func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLB
 	if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
 		return nil, err
 	}
+	// 同步资源标签
+	if err = common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.LoadBalancerCloudResType,
+		lbFromCloud); err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
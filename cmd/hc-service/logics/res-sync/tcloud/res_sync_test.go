@@ -0,0 +1,408 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/cmd/hc-service/logics/res-sync/mock"
+	logicsync "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/adaptor/tcloud"
+	"hcm/pkg/adaptor/types"
+	adcore "hcm/pkg/adaptor/types/core"
+	typesdisk "hcm/pkg/adaptor/types/disk"
+	typeseip "hcm/pkg/adaptor/types/eip"
+	adtysubnet "hcm/pkg/adaptor/types/subnet"
+	cloudcore "hcm/pkg/api/core/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+)
+
+const (
+	testAccountID = "account-1"
+	testRegion    = "ap-guangzhou"
+	// testVpcCloudID 子网所属vpc的云ID，创建子网时需要从db查询到该vpc
+	testVpcCloudID = "vpc-base"
+)
+
+// fakeCloud 内存中的腾讯云资源，key为资源云ID，value为资源状态，状态变化时资源需要更新到db
+type fakeCloud struct {
+	tcloud.TCloud
+	vpcs    map[string]string
+	subnets map[string]string
+	disks   map[string]string
+	eips    map[string]string
+}
+
+func newFakeCloud() *fakeCloud {
+	return &fakeCloud{
+		vpcs:    make(map[string]string),
+		subnets: make(map[string]string),
+		disks:   make(map[string]string),
+		eips:    make(map[string]string),
+	}
+}
+
+// ListVpc ...
+func (c *fakeCloud) ListVpc(_ *kit.Kit, opt *adcore.TCloudListOption) (*types.TCloudVpcListResult, error) {
+	result := new(types.TCloudVpcListResult)
+	for _, cloudID := range filterCloudIDs(c.vpcs, opt.CloudIDs) {
+		result.Details = append(result.Details, types.TCloudVpc{
+			CloudID:   cloudID,
+			Name:      c.vpcs[cloudID],
+			Region:    opt.Region,
+			Extension: new(cloudcore.TCloudVpcExtension),
+		})
+	}
+	return result, nil
+}
+
+// ListSubnet ...
+func (c *fakeCloud) ListSubnet(_ *kit.Kit, opt *adcore.TCloudListOption) (*adtysubnet.TCloudSubnetListResult,
+	error) {
+
+	result := new(adtysubnet.TCloudSubnetListResult)
+	for _, cloudID := range filterCloudIDs(c.subnets, opt.CloudIDs) {
+		result.Details = append(result.Details, adtysubnet.TCloudSubnet{
+			CloudVpcID: testVpcCloudID,
+			CloudID:    cloudID,
+			Name:       c.subnets[cloudID],
+			Region:     opt.Region,
+			Extension:  &adtysubnet.TCloudSubnetExtension{Zone: testRegion + "-3"},
+		})
+	}
+	return result, nil
+}
+
+// ListDisk ...
+func (c *fakeCloud) ListDisk(_ *kit.Kit, opt *adcore.TCloudListOption) ([]typesdisk.TCloudDisk, error) {
+	result := make([]typesdisk.TCloudDisk, 0)
+	for _, cloudID := range filterCloudIDs(c.disks, opt.CloudIDs) {
+		result = append(result, typesdisk.TCloudDisk{Disk: &cbs.Disk{
+			DiskId:    converter.ValToPtr(cloudID),
+			DiskState: converter.ValToPtr(c.disks[cloudID]),
+			Placement: &cbs.Placement{Zone: converter.ValToPtr(testRegion + "-3")},
+		}})
+	}
+	return result, nil
+}
+
+// ListEip ...
+func (c *fakeCloud) ListEip(_ *kit.Kit, opt *typeseip.TCloudEipListOption) (*typeseip.TCloudEipListResult, error) {
+	result := new(typeseip.TCloudEipListResult)
+	for _, cloudID := range filterCloudIDs(c.eips, opt.CloudIDs) {
+		result.Details = append(result.Details, &typeseip.TCloudEip{
+			CloudID: cloudID,
+			Region:  opt.Region,
+			Status:  converter.ValToPtr(c.eips[cloudID]),
+		})
+	}
+	return result, nil
+}
+
+// filterCloudIDs 返回云上存在的资源云ID，按云ID排序
+func filterCloudIDs(resources map[string]string, cloudIDs []string) []string {
+	result := make([]string, 0)
+	for _, cloudID := range cloudIDs {
+		if _, exist := resources[cloudID]; exist {
+			result = append(result, cloudID)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// resCase 同步一种资源需要的测试数据和同步方法
+type resCase struct {
+	name  string
+	table string
+	// resources 云上该资源的数据
+	resources func(c *fakeCloud) map[string]string
+	// batch 按云ID同步
+	batch func(kt *kit.Kit, cli *client, params *SyncBaseParams) error
+	// remove 删除云上已删除的数据
+	remove func(kt *kit.Kit, cli *client) error
+	// syncer 全量同步器
+	syncer func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer
+	// prepare 预置同步依赖的db数据，可为空
+	prepare func(ds *mock.DataService)
+}
+
+var resCases = []resCase{
+	{
+		name:      "vpc",
+		table:     "vpcs",
+		resources: func(c *fakeCloud) map[string]string { return c.vpcs },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Vpc(kt, params, new(SyncVpcOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveVpcDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.VpcSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+	{
+		name:      "subnet",
+		table:     "subnets",
+		resources: func(c *fakeCloud) map[string]string { return c.subnets },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Subnet(kt, params, new(SyncSubnetOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveSubnetDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.SubnetSyncer(testAccountID, testRegion, next, opt)
+		},
+		prepare: func(ds *mock.DataService) {
+			// 创建子网时需要从db查询所属vpc
+			ds.Insert("vpcs", map[string]any{"id": "base", "cloud_id": testVpcCloudID, "account_id": testAccountID,
+				"region": testRegion, "extension": map[string]any{}})
+		},
+	},
+	{
+		name:      "disk",
+		table:     "disks",
+		resources: func(c *fakeCloud) map[string]string { return c.disks },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Disk(kt, params, new(SyncDiskOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveDiskDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.DiskSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+	{
+		name:      "eip",
+		table:     "eips",
+		resources: func(c *fakeCloud) map[string]string { return c.eips },
+		batch: func(kt *kit.Kit, cli *client, params *SyncBaseParams) error {
+			_, err := cli.Eip(kt, params, new(SyncEipOption))
+			return err
+		},
+		remove: func(kt *kit.Kit, cli *client) error {
+			return cli.RemoveEipDeleteFromCloud(kt, testAccountID, testRegion)
+		},
+		syncer: func(cli *client, next common.NextFromCloudFunc, opt logicsync.Option) common.PlanSyncer {
+			return cli.EipSyncer(testAccountID, testRegion, next, opt)
+		},
+	},
+}
+
+// newTestClient 返回使用内存云资源和内存 data-service 的同步客户端
+func newTestClient(t *testing.T, rc resCase) (*client, *fakeCloud, *mock.DataService) {
+	ds := mock.NewDataService()
+	t.Cleanup(ds.Close)
+	if rc.prepare != nil {
+		rc.prepare(ds)
+	}
+
+	cloud := newFakeCloud()
+	return &client{accountID: testAccountID, cloudCli: cloud, dbCli: ds.Client()}, cloud, ds
+}
+
+// allCloudIDs 一次返回云上全部资源云ID的分页函数
+func allCloudIDs(resources map[string]string) common.NextFromCloudFunc {
+	done := false
+	return func(_ *kit.Kit) ([]string, error) {
+		if done {
+			return nil, nil
+		}
+		done = true
+
+		cloudIDs := make([]string, 0, len(resources))
+		for cloudID := range resources {
+			cloudIDs = append(cloudIDs, cloudID)
+		}
+		sort.Strings(cloudIDs)
+		return cloudIDs, nil
+	}
+}
+
+// insertDB 向db中写入属于测试账号、地域的数据
+func insertDB(ds *mock.DataService, table string, cloudIDs ...string) {
+	for _, cloudID := range cloudIDs {
+		ds.Insert(table, map[string]any{"id": "db-" + cloudID, "cloud_id": cloudID, "account_id": testAccountID,
+			"region": testRegion, "extension": map[string]any{}})
+	}
+}
+
+// tableCloudIDs 返回db中的资源云ID，按云ID排序
+func tableCloudIDs(ds *mock.DataService, table string) []string {
+	result := ds.CloudIDs(table)
+	sort.Strings(result)
+	return result
+}
+
+func TestSyncEmpty(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, cloud, ds := newTestClient(t, rc)
+			kt := kit.New()
+
+			params := &SyncBaseParams{AccountID: testAccountID, Region: testRegion, CloudIDs: []string{"not-exist"}}
+			require.NoError(t, rc.batch(kt, cli, params))
+
+			_, err := rc.syncer(cli, allCloudIDs(rc.resources(cloud)), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+
+			assert.Empty(t, ds.Writes(), "云上和db都没有数据时不应写db，也不应同步标签")
+		})
+	}
+}
+
+func TestSyncAllPages(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, cloud, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(cloud)
+			for _, cloudID := range []string{"a", "b", "c"} {
+				resources[cloudID] = "v1"
+			}
+
+			_, err := rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "create", Table: rc.table, CloudIDs: []string{"a", "b", "c"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "c"}},
+			}, ds.Writes())
+
+			// 云上数据没有变化时，再次同步不写db
+			ds.Reset()
+			_, err = rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "c"}},
+			}, ds.Writes())
+
+			// 先删除、再创建、最后更新
+			ds.Reset()
+			resources["b"] = "v2"
+			delete(resources, "c")
+			resources["d"] = "v1"
+			_, err = rc.syncer(cli, allCloudIDs(resources), logicsync.Option{}).AllPages(kt)
+			require.NoError(t, err)
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"c"}},
+				{Op: "create", Table: rc.table, CloudIDs: []string{"d"}},
+				{Op: "update", Table: rc.table, CloudIDs: []string{"b"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b", "d"}},
+			}, ds.Writes())
+			assert.Equal(t, []string{"a", "b", "d"}, tableCloudIDs(ds, rc.table))
+		})
+	}
+}
+
+func TestSyncBatch(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, cloud, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(cloud)
+			resources["a"] = "v2"
+			resources["b"] = "v1"
+			insertDB(ds, rc.table, "a", "c", "d")
+
+			// 按云ID同步只处理指定的资源，云上已删除的数据全部删除，不受删除比例阈值限制
+			params := &SyncBaseParams{AccountID: testAccountID, Region: testRegion,
+				CloudIDs: []string{"a", "b", "c", "d"}}
+			require.NoError(t, rc.batch(kt, cli, params))
+
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"c", "d"}},
+				{Op: "create", Table: rc.table, CloudIDs: []string{"b"}},
+				{Op: "update", Table: rc.table, CloudIDs: []string{"a"}},
+				{Op: "upsert", Table: "resource_tags", CloudIDs: []string{"a", "b"}},
+			}, ds.Writes())
+		})
+	}
+}
+
+func TestRemoveDeleteFromCloud(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, cloud, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(cloud)
+
+			// db数据超过一页，云上删除了其中3条
+			cloudIDs := make([]string, 0)
+			for i := 0; i < 150; i++ {
+				cloudID := fmt.Sprintf("res-%03d", i)
+				cloudIDs = append(cloudIDs, cloudID)
+				if i != 1 && i != 99 && i != 149 {
+					resources[cloudID] = "v1"
+				}
+			}
+			insertDB(ds, rc.table, cloudIDs...)
+
+			require.NoError(t, rc.remove(kt, cli))
+
+			assert.Equal(t, []mock.Write{
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"res-001", "res-099"}},
+				{Op: "delete", Table: rc.table, CloudIDs: []string{"res-149"}},
+			}, ds.Writes())
+			assert.Len(t, tableCloudIDs(ds, rc.table), 147)
+
+			// 按账号、地域分页查询db数据时只查询id和云ID
+			pages := make([]uint32, 0)
+			for _, one := range ds.Lists() {
+				if one.Vendor == "" && one.Table == rc.table {
+					assert.Equal(t, []string{"id", "cloud_id"}, one.Fields)
+					pages = append(pages, one.Start)
+				}
+			}
+			assert.Equal(t, []uint32{0, 100}, pages)
+		})
+	}
+}
+
+func TestSyncDeleteThreshold(t *testing.T) {
+	for _, rc := range resCases {
+		t.Run(rc.name, func(t *testing.T) {
+			cli, cloud, ds := newTestClient(t, rc)
+			kt := kit.New()
+			resources := rc.resources(cloud)
+			resources["a"] = "v1"
+			insertDB(ds, rc.table, "a", "b", "c", "d")
+
+			_, err := rc.syncer(cli, allCloudIDs(resources), logicsync.Option{DeleteThreshold: 50}).AllPages(kt)
+			require.Error(t, err)
+
+			assert.Empty(t, ds.Writes(), "删除比例超过阈值时不应写db")
+			assert.Equal(t, []string{"a", "b", "c", "d"}, tableCloudIDs(ds, rc.table))
+		})
+	}
+}
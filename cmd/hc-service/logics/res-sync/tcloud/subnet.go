@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	adcore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/subnet"
 	"hcm/pkg/api/core"
@@ -56,41 +57,10 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	subnetFromDB, err := cli.listSubnetFromDB(kt, params)
-	if err != nil {
+	if err := cli.subnetResSyncer(params.AccountID, params.Region).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.TCloudSubnet,
-		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]](subnetFromCloud, subnetFromDB, isTCloudSubnetChange)
-
-	if len(delCloudIDs) > 0 {
-		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addSubnet) > 0 {
-		if err = cli.createSubnet(kt, params.AccountID, params.Region, addSubnet); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return new(SyncResult), nil
 }
 
@@ -329,70 +299,66 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]a
 
 // RemoveSubnetDeleteFromCloud ...
 func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	return cli.subnetResSyncer(accountID, region).RemoveDeletedFromCloud(kt)
+}
 
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.Subnet.List(kt.Ctx, kt.Header(), req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list subnet failed, err: %v, req: %v, rid: %s", enumor.TCloud,
-				err, req, kt.Rid)
-			return err
-		}
+// SubnetSyncer 基于通用同步器的子网全量同步，next 为云上子网分页查询函数
+func (cli *client) SubnetSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+	rs := cli.subnetResSyncer(accountID, region)
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+func (cli *client) subnetResSyncer(accountID, region string) *common.ResSyncer[*SyncBaseParams,
+	adtysubnet.TCloudSubnet, cloudcore.Subnet[cloudcore.TCloudSubnetExtension]] {
 
-		var resultFromCloud []adtysubnet.TCloudSubnet
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			resultFromCloud, err = cli.listSubnetFromCloud(kt, params)
+	return &common.ResSyncer[*SyncBaseParams, adtysubnet.TCloudSubnet,
+		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]]{
+		ResType: enumor.SubnetCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.Subnet.List(kt.Ctx, kt.Header(), req)
 			if err != nil {
-				return err
-			}
-		}
-
-		// 如果有资源没有查询出来，说明数据被从云上删除
-		if len(resultFromCloud) != len(cloudIDs) {
-			cloudIDMap := converter.StringSliceToMap(cloudIDs)
-			for _, one := range resultFromCloud {
-				delete(cloudIDMap, one.CloudID)
+				logs.Errorf("[%s] request dataservice to list subnet failed, err: %v, req: %v, rid: %s", enumor.TCloud,
+					err, req, kt.Rid)
+				return nil, err
 			}
 
-			delCloudIDs := converter.MapKeyToStringSlice(cloudIDMap)
-			if err = cli.deleteSubnet(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listSubnetFromCloud,
+		ListFromDB:    cli.listSubnetFromDB,
+		IsChange:      isTCloudSubnetChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []adtysubnet.TCloudSubnet) error {
+			return cli.createSubnet(kt, params.AccountID, params.Region, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]adtysubnet.TCloudSubnet) error {
+			return cli.updateSubnet(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []adtysubnet.TCloudSubnet) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID,
+				enumor.SubnetCloudResType, dataFromCloud)
+		},
+		FieldDiff: func(item adtysubnet.TCloudSubnet,
+			info cloudcore.Subnet[cloudcore.TCloudSubnetExtension]) []logicsync.FieldChange {
 
-		req.Page.Start += constant.BatchOperationMaxLimit
+			changes := logicsync.AppendIfChanged(nil, "name", info.Name, item.Name)
+			changes = logicsync.AppendIfChanged(changes, "cloud_vpc_id", info.CloudVpcID, item.CloudVpcID)
+			return logicsync.AppendIfChanged(changes, "memo", converter.PtrToVal(info.Memo),
+				converter.PtrToVal(item.Memo))
+		},
 	}
-
-	return nil
 }
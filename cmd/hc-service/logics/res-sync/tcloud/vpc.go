@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/types"
 	adcore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/core"
@@ -56,112 +57,76 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
-	if err != nil {
-		return nil, err
-	}
-
-	vpcFromDB, err := cli.listVpcFromDB(kt, params)
-	if err != nil {
+	if err := cli.vpcResSyncer(params.AccountID, params.Region).BatchSync(kt, params); err != nil {
 		return nil, err
 	}
 
-	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
-	}
-
-	addVpc, updateMap, delCloudIDs := common.Diff[types.TCloudVpc, cloudcore.Vpc[cloudcore.TCloudVpcExtension]](
-		vpcFromCloud, vpcFromDB, isTCloudVpcChange)
-
-	if len(delCloudIDs) > 0 {
-		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(addVpc) > 0 {
-		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(updateMap) > 0 {
-		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
-			return nil, err
-		}
-	}
-
 	return new(SyncResult), nil
 }
 
 // RemoveVpcDeleteFromCloud ...
 func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	return cli.vpcResSyncer(accountID, region).RemoveDeletedFromCloud(kt)
+}
 
-	req := &core.ListReq{
-		Fields: []string{"id", "cloud_id"},
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
-				&filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region},
-			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: constant.BatchOperationMaxLimit,
-		},
-	}
-	for {
-		resultFromDB, err := cli.dbCli.Global.Vpc.List(kt.Ctx, kt.Header(), req)
-		if err != nil {
-			logs.Errorf("[%s] request dataservice to list vpc failed, err: %v, req: %v, rid: %s", enumor.TCloud,
-				err, req, kt.Rid)
-			return err
-		}
+// VpcSyncer 基于通用同步器的vpc全量同步，next 为云上vpc分页查询函数
+func (cli *client) VpcSyncer(accountID, region string, next common.NextFromCloudFunc,
+	opt logicsync.Option) common.PlanSyncer {
 
-		cloudIDs := make([]string, 0)
-		for _, one := range resultFromDB.Details {
-			cloudIDs = append(cloudIDs, one.CloudID)
-		}
+	rs := cli.vpcResSyncer(accountID, region)
+	rs.NextFromCloud = next
+	return rs.Syncer(opt)
+}
 
-		if len(cloudIDs) == 0 {
-			break
-		}
+func (cli *client) vpcResSyncer(accountID, region string) *common.ResSyncer[*SyncBaseParams, types.TCloudVpc,
+	cloudcore.Vpc[cloudcore.TCloudVpcExtension]] {
 
-		var resultFromCloud []types.TCloudVpc
-		if len(cloudIDs) != 0 {
-			params := &SyncBaseParams{
-				AccountID: accountID,
-				Region:    region,
-				CloudIDs:  cloudIDs,
-			}
-			resultFromCloud, err = cli.listVpcFromCloud(kt, params)
+	return &common.ResSyncer[*SyncBaseParams, types.TCloudVpc, cloudcore.Vpc[cloudcore.TCloudVpcExtension]]{
+		ResType: enumor.VpcCloudResType,
+		BuildParams: func(cloudIDs []string) *SyncBaseParams {
+			return &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		},
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			req := common.NewAccountRegionListReq(accountID, region, page)
+			result, err := cli.dbCli.Global.Vpc.List(kt.Ctx, kt.Header(), req)
 			if err != nil {
-				return err
-			}
-		}
-
-		// 如果有资源没有查询出来，说明数据被从云上删除
-		if len(resultFromCloud) != len(cloudIDs) {
-			cloudIDMap := converter.StringSliceToMap(cloudIDs)
-			for _, one := range resultFromCloud {
-				delete(cloudIDMap, one.CloudID)
+				logs.Errorf("[%s] request dataservice to list vpc failed, err: %v, req: %v, rid: %s", enumor.TCloud,
+					err, req, kt.Rid)
+				return nil, err
 			}
 
-			delCloudIDs := converter.MapKeyToStringSlice(cloudIDMap)
-			if err = cli.deleteVpc(kt, accountID, region, delCloudIDs); err != nil {
-				return err
+			cloudIDMap := make(map[string]string, len(result.Details))
+			for _, one := range result.Details {
+				cloudIDMap[one.CloudID] = one.ID
 			}
-		}
-
-		if len(resultFromDB.Details) < constant.BatchOperationMaxLimit {
-			break
-		}
+			return cloudIDMap, nil
+		},
+		ListFromCloud: cli.listVpcFromCloud,
+		ListFromDB:    cli.listVpcFromDB,
+		IsChange:      isTCloudVpcChange,
+		Create: func(kt *kit.Kit, params *SyncBaseParams, addData []types.TCloudVpc) error {
+			return cli.createVpc(kt, params.AccountID, addData)
+		},
+		Update: func(kt *kit.Kit, params *SyncBaseParams, updateMap map[string]types.TCloudVpc) error {
+			return cli.updateVpc(kt, params.AccountID, updateMap)
+		},
+		Delete: func(kt *kit.Kit, params *SyncBaseParams, delCloudIDs []string) error {
+			return cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs)
+		},
+		SyncExtra: func(kt *kit.Kit, params *SyncBaseParams, dataFromCloud []types.TCloudVpc) error {
+			// 同步资源标签
+			return common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.VpcCloudResType,
+				dataFromCloud)
+		},
+		FieldDiff: func(item types.TCloudVpc,
+			info cloudcore.Vpc[cloudcore.TCloudVpcExtension]) []logicsync.FieldChange {
 
-		req.Page.Start += constant.BatchOperationMaxLimit
+			changes := logicsync.AppendIfChanged(nil, "name", info.Name, item.Name)
+			changes = logicsync.AppendIfChanged(changes, "region", info.Region, item.Region)
+			return logicsync.AppendIfChanged(changes, "memo", converter.PtrToVal(info.Memo),
+				converter.PtrToVal(item.Memo))
+		},
 	}
-
-	return nil
 }
 
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
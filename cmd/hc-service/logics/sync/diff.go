@@ -19,11 +19,36 @@
 
 package sync
 
-// Diff 对比源数据和目标数据的增/删/改数据。
+// Diff 对比源数据和目标数据的增/删/改数据，idUpdateDataMap 的 key 为目标数据ID。
 func Diff[SourceDataType SourceData, TargetDataType TargetData](
 	sourceData []SourceDataType, targetData []TargetDataType, isChange func(SourceDataType, TargetDataType) bool) (
 	createData []SourceDataType, idUpdateDataMap map[string]SourceDataType, delIDs []string) {
 
+	createData, updates, delData := diffDetail(sourceData, targetData, isChange)
+
+	idUpdateDataMap = make(map[string]SourceDataType, len(updates))
+	for _, one := range updates {
+		idUpdateDataMap[one.target.GetID()] = one.source
+	}
+
+	for _, one := range delData {
+		delIDs = append(delIDs, one.GetID())
+	}
+
+	return createData, idUpdateDataMap, delIDs
+}
+
+// updatePair 发生变化的源数据及其对应的目标数据
+type updatePair[SourceDataType SourceData, TargetDataType TargetData] struct {
+	source SourceDataType
+	target TargetDataType
+}
+
+// diffDetail 对比源数据和目标数据，更新和删除数据保留目标数据本身，用于生成变更计划。
+func diffDetail[SourceDataType SourceData, TargetDataType TargetData](
+	sourceData []SourceDataType, targetData []TargetDataType, isChange func(SourceDataType, TargetDataType) bool) (
+	createData []SourceDataType, updates []updatePair[SourceDataType, TargetDataType], delData []TargetDataType) {
+
 	uuidTargetDataMap := make(map[string]TargetDataType, len(targetData))
 	for _, one := range targetData {
 		uuidTargetDataMap[one.GetUUID()] = one
@@ -38,13 +63,19 @@ func Diff[SourceDataType SourceData, TargetDataType TargetData](
 
 		delete(uuidTargetDataMap, oneFromSource.GetUUID())
 		if isChange(oneFromSource, oneFromTarget) {
-			idUpdateDataMap[oneFromTarget.GetUUID()] = oneFromSource
+			updates = append(updates, updatePair[SourceDataType, TargetDataType]{
+				source: oneFromSource,
+				target: oneFromTarget,
+			})
 		}
 	}
 
-	for _, one := range uuidTargetDataMap {
-		delIDs = append(delIDs, one.GetID())
+	// 按目标数据原有顺序输出，保证结果稳定
+	for _, one := range targetData {
+		if _, exist := uuidTargetDataMap[one.GetUUID()]; exist {
+			delData = append(delData, one)
+		}
 	}
 
-	return createData, idUpdateDataMap, delIDs
+	return createData, updates, delData
 }
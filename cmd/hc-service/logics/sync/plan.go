@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"hcm/pkg/kit"
+)
+
+// ErrDeleteThresholdExceeded 要删除的目标数据比例超过阈值
+var ErrDeleteThresholdExceeded = errors.New("delete threshold exceeded")
+
+// Plan dry-run 变更计划
+type Plan struct {
+	Creates []PlanItem `json:"creates"`
+	Updates []PlanItem `json:"updates"`
+	Deletes []PlanItem `json:"deletes"`
+}
+
+// PlanItem 单条数据的变更
+type PlanItem struct {
+	UUID string `json:"uuid"`
+	// ID 目标数据ID，创建时为空
+	ID string `json:"id,omitempty"`
+	// Changes 字段级变更，仅更新且处理器实现了 FieldDiffer 时返回
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange 字段变更
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// FieldDiffer 处理器可选实现，用于 dry-run 时输出字段级变更。
+type FieldDiffer[SourceDataType SourceData, TargetDataType TargetData] interface {
+	// DiffFields 返回目标数据相对于源数据发生变化的字段
+	DiffFields(sourceData SourceDataType, targetData TargetDataType) []FieldChange
+}
+
+// AfterSyncer 处理器可选实现，非 dry-run 模式下完成增/删/改后执行，如同步资源标签等附属数据。
+type AfterSyncer[ParamType any, SourceDataType SourceData] interface {
+	// AfterSync 批量同步完成后的处理，sourceData 为本批次从数据源查询到的全部数据
+	AfterSync(kt *kit.Kit, params ParamType, sourceData []SourceDataType) error
+}
+
+// AppendIfChanged 字段值不同时追加到变更列表中，便于实现 FieldDiffer。
+func AppendIfChanged(changes []FieldChange, field string, old, new any) []FieldChange {
+	if reflect.DeepEqual(old, new) {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, Old: old, New: new})
+}
+
+// merge 合并另一个计划，删除项按ID去重
+func (p *Plan) merge(other *Plan) {
+	if other == nil {
+		return
+	}
+
+	p.Creates = append(p.Creates, other.Creates...)
+	p.Updates = append(p.Updates, other.Updates...)
+
+	exists := make(map[string]struct{}, len(p.Deletes))
+	for _, one := range p.Deletes {
+		exists[one.ID] = struct{}{}
+	}
+	for _, one := range other.Deletes {
+		if _, ok := exists[one.ID]; ok {
+			continue
+		}
+		exists[one.ID] = struct{}{}
+		p.Deletes = append(p.Deletes, one)
+	}
+}
+
+// checkDeleteThreshold 校验要删除的数据占比是否超过阈值
+func (opt Option) checkDeleteThreshold(delCount, totalCount int) error {
+	if opt.DeleteThreshold <= 0 || delCount == 0 || totalCount == 0 {
+		return nil
+	}
+
+	percent := float64(delCount) * 100 / float64(totalCount)
+	if percent > opt.DeleteThreshold {
+		return fmt.Errorf("%w: %d of %d (%.2f%%) target data would be deleted, threshold: %.2f%%",
+			ErrDeleteThresholdExceeded, delCount, totalCount, percent, opt.DeleteThreshold)
+	}
+	return nil
+}
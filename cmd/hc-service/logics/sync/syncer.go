@@ -33,7 +33,7 @@ type Interface[BatchSyncParamType any, SourceDataType SourceData, TargetDataType
 	AllPages(kt *kit.Kit) (result *Result, err error)
 	// BatchOrAll 批量/全量同步，取决于用户传的查询参数查询的是全量数据还是批量数据。
 	BatchOrAll(kt *kit.Kit, params BatchSyncParamType) (result *Result, err error)
-	// RemoveDeletedFromSource 移除已经从数据源删除的数据。
+	// RemoveDeletedFromSource 移除已经从数据源删除的数据，dry-run 模式下仅返回将要删除的数据ID。
 	RemoveDeletedFromSource(kt *kit.Kit) (ids []string, err error)
 }
 
@@ -44,58 +44,101 @@ type Syncer[BatchSyncParamType any, SourceDataType SourceData, TargetDataType Ta
 
 	// Handler 批量同步处理器
 	Handler Handler[BatchSyncParamType, SourceDataType, TargetDataType]
+
+	// Option 同步选项，dry-run 及删除阈值
+	Option Option
 }
 
-// RemoveDeletedFromSource 移除已经从数据源删除的数据。
+// RemoveDeletedFromSource 移除已经从数据源删除的数据。dry-run 模式下不做删除，返回将要删除的数据ID。
 func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) RemoveDeletedFromSource(kt *kit.Kit) (
 	ids []string, err error) {
 
+	ids, _, err = sync.removeDeletedFromSource(kt)
+	return ids, err
+}
+
+// deleteBatch 目标源中一页待删除的数据
+type deleteBatch[BatchSyncParamType any] struct {
+	params BatchSyncParamType
+	ids    []string
+}
+
+// removeDeletedFromSource 先遍历目标源全部数据收集待删除数据，基于目标源数据总量校验删除比例阈值后再统一删除，
+// 超过阈值时不删除任何数据。
+func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) removeDeletedFromSource(kt *kit.Kit) (
+	ids []string, plan *Plan, err error) {
+
+	plan = new(Plan)
+	batches := make([]deleteBatch[BatchSyncParamType], 0)
+	total := 0
 	for {
 		// 从目标源查询一批数据，判断这批数据是否有已经从数据源删除的数据
 		uuidIDMapFromTarget, err := sync.Pager.NextFromTarget(kt)
 		if err != nil {
 			logs.Errorf("[%s] get next from target failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
-			return nil, err
+			return nil, nil, err
 		}
 
-		if len(uuidIDMapFromTarget) != 0 {
-			// 从数据源查询数据
-			params := sync.Pager.BuildParam(maps.Keys(uuidIDMapFromTarget))
-			sourceData, err := sync.Handler.QueryFromSource(kt, params)
-			if err != nil {
-				logs.Errorf("[%s] query from source failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
-				return nil, err
-			}
-
-			// 如果查询数据和返回数据数量不同，则证明目标源中有数据要被删除
-			if len(uuidIDMapFromTarget) != len(sourceData) {
-				for _, one := range sourceData {
-					delete(uuidIDMapFromTarget, one.GetUUID())
-				}
+		if len(uuidIDMapFromTarget) == 0 {
+			break
+		}
+		total += len(uuidIDMapFromTarget)
 
-				delIDs := maps.Values(uuidIDMapFromTarget)
-				if err = sync.Handler.DeleteTargetData(kt, params, delIDs); err != nil {
-					logs.Errorf("[%s] delete target data failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
-					return nil, err
-				}
+		// 从数据源查询数据
+		params := sync.Pager.BuildParam(maps.Keys(uuidIDMapFromTarget))
+		sourceData, err := sync.Handler.QueryFromSource(kt, params)
+		if err != nil {
+			logs.Errorf("[%s] query from source failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
+			return nil, nil, err
+		}
 
-				ids = append(ids, delIDs...)
+		// 如果查询数据和返回数据数量不同，则证明目标源中有数据要被删除
+		if len(uuidIDMapFromTarget) != len(sourceData) {
+			for _, one := range sourceData {
+				delete(uuidIDMapFromTarget, one.GetUUID())
 			}
 
-			// 判断是否还有下一页资源需要同步
-			hasNext, err := sync.Pager.HasNextFromTarget()
-			if err != nil {
-				logs.Errorf("[%s] exec has next from target failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
-				return ids, err
+			delIDs := make([]string, 0, len(uuidIDMapFromTarget))
+			for uuid, id := range uuidIDMapFromTarget {
+				delIDs = append(delIDs, id)
+				plan.Deletes = append(plan.Deletes, PlanItem{UUID: uuid, ID: id})
 			}
 
-			if !hasNext {
-				break
+			if len(delIDs) != 0 {
+				batches = append(batches, deleteBatch[BatchSyncParamType]{params: params, ids: delIDs})
+				ids = append(ids, delIDs...)
 			}
 		}
+
+		// 判断是否还有下一页资源需要同步
+		hasNext, err := sync.Pager.HasNextFromTarget()
+		if err != nil {
+			logs.Errorf("[%s] exec has next from target failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
+			return nil, nil, err
+		}
+
+		if !hasNext {
+			break
+		}
 	}
 
-	return ids, nil
+	if err = sync.Option.checkDeleteThreshold(len(ids), total); err != nil {
+		logs.Errorf("[%s] remove deleted from source aborted, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
+		return nil, nil, err
+	}
+
+	if sync.Option.DryRun {
+		return ids, plan, nil
+	}
+
+	for _, batch := range batches {
+		if err = sync.Handler.DeleteTargetData(kt, batch.params, batch.ids); err != nil {
+			logs.Errorf("[%s] delete target data failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
+			return nil, nil, err
+		}
+	}
+
+	return ids, plan, nil
 }
 
 // AllPages 分页全量同步，将数据源分页调用批量同步进行同步。
@@ -106,12 +149,17 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) AllPages
 		return nil, errors.New("page sync handler is required")
 	}
 
-	delIDs, err := sync.RemoveDeletedFromSource(kt)
+	result = new(Result)
+	delIDs, plan, err := sync.removeDeletedFromSource(kt)
 	if err != nil {
-		logs.Errorf("[%s] remove deleted from source failed, err: %v, rid: %s", err, kt.Rid)
+		logs.Errorf("[%s] remove deleted from source failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
 		return nil, err
 	}
-	result.DeleteIDs = append(result.DeleteIDs, delIDs...)
+	if sync.Option.DryRun {
+		result.Plan = plan
+	} else {
+		result.DeleteIDs = append(result.DeleteIDs, delIDs...)
+	}
 
 	for {
 		// 获取下一页要同步资源的唯一ID列表
@@ -124,7 +172,8 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) AllPages
 		// 执行批量同步，同步这一页的资源
 		if len(uuids) != 0 {
 			params := sync.Pager.BuildParam(uuids)
-			batchSyncResult, err := sync.BatchOrAll(kt, params)
+			// 删除比例已在 removeDeletedFromSource 中基于目标源全量数据校验，单页的比例不代表整体，不再按页校验
+			batchSyncResult, err := sync.batchOrAll(kt, params, false)
 			if err != nil {
 				logs.Errorf("[%s] batch sync failed, err: %v, uuids: %v, rid: %s", sync.Handler.Name(),
 					err, uuids, kt.Rid)
@@ -134,6 +183,9 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) AllPages
 			result.DeleteIDs = append(result.DeleteIDs, batchSyncResult.DeleteIDs...)
 			result.CreateIDs = append(result.CreateIDs, batchSyncResult.CreateIDs...)
 			result.UpdateIDs = append(result.UpdateIDs, batchSyncResult.UpdateIDs...)
+			if result.Plan != nil {
+				result.Plan.merge(batchSyncResult.Plan)
+			}
 		}
 
 		// 判断是否还有下一页资源需要同步
@@ -155,6 +207,13 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) AllPages
 func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrAll(
 	kt *kit.Kit, params BatchSyncParamType) (result *Result, err error) {
 
+	return sync.batchOrAll(kt, params, true)
+}
+
+// batchOrAll 批量/全量同步，checkThreshold 为 true 时校验本次对比中要删除的数据占比。
+func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) batchOrAll(kt *kit.Kit,
+	params BatchSyncParamType, checkThreshold bool) (result *Result, err error) {
+
 	if sync.Handler == nil {
 		return nil, errors.New("batch sync handler is required")
 	}
@@ -177,11 +236,35 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 
 	// 没有数据需要同步
 	if len(sourceData) == 0 && len(targetData) == 0 {
+		if sync.Option.DryRun {
+			return &Result{Plan: new(Plan)}, nil
+		}
 		return new(Result), nil
 	}
 
 	// 对比数据源和目标源数据，对增/删/改数据进行分类
-	createData, idUpdateDataMap, delIDs := Diff(sourceData, targetData, sync.Handler.DiffFunc)
+	createData, updates, delData := diffDetail(sourceData, targetData, sync.Handler.DiffFunc)
+
+	if checkThreshold {
+		if err = sync.Option.checkDeleteThreshold(len(delData), len(targetData)); err != nil {
+			logs.Errorf("[%s] batch sync aborted, err: %v, params: %+v, rid: %s", sync.Handler.Name(), err,
+				params, kt.Rid)
+			return nil, err
+		}
+	}
+
+	if sync.Option.DryRun {
+		return &Result{Plan: sync.buildPlan(createData, updates, delData)}, nil
+	}
+
+	delIDs := make([]string, 0, len(delData))
+	for _, one := range delData {
+		delIDs = append(delIDs, one.GetID())
+	}
+	idUpdateDataMap := make(map[string]SourceDataType, len(updates))
+	for _, one := range updates {
+		idUpdateDataMap[one.target.GetID()] = one.source
+	}
 
 	// TODO: 添加日志和metrics数量统计，和失败请求统计
 	// 删除目标源中多余的数据
@@ -193,6 +276,17 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 		}
 	}
 
+	var createIDs []string
+	// 添加数据源多出的数据，与 res-sync 保持一致，先删除、再创建、最后更新
+	if len(createData) > 0 {
+		createIDs, err = sync.Handler.CreateTargetData(kt, params, createData)
+		if err != nil {
+			logs.Errorf("[%s] create target data failed, err: %v, params: %+v, createData: %+v, rid: %s",
+				sync.Handler.Name(), err, params, createData, kt.Rid)
+			return nil, err
+		}
+	}
+
 	// 更新源数据更新，但目标源没更新的数据
 	if len(idUpdateDataMap) > 0 {
 		if err = sync.Handler.UpdateTargetData(kt, params, idUpdateDataMap); err != nil {
@@ -202,13 +296,11 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 		}
 	}
 
-	var createIDs []string
-	// 添加数据源多出的数据
-	if len(createData) > 0 {
-		createIDs, err = sync.Handler.CreateTargetData(kt, params, createData)
-		if err != nil {
-			logs.Errorf("[%s] create target data failed, err: %v, params: %+v, createData: %+v, rid: %s",
-				sync.Handler.Name(), err, params, createData, kt.Rid)
+	// 处理附属数据
+	if after, ok := sync.Handler.(AfterSyncer[BatchSyncParamType, SourceDataType]); ok {
+		if err = after.AfterSync(kt, params, sourceData); err != nil {
+			logs.Errorf("[%s] after sync failed, err: %v, params: %+v, rid: %s", sync.Handler.Name(), err, params,
+				kt.Rid)
 			return nil, err
 		}
 	}
@@ -222,3 +314,33 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 
 	return result, nil
 }
+
+// buildPlan 根据对比结果生成变更计划，处理器实现了 FieldDiffer 时输出字段级变更
+func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) buildPlan(createData []SourceDataType,
+	updates []updatePair[SourceDataType, TargetDataType], delData []TargetDataType) *Plan {
+
+	plan := &Plan{
+		Creates: make([]PlanItem, 0, len(createData)),
+		Updates: make([]PlanItem, 0, len(updates)),
+		Deletes: make([]PlanItem, 0, len(delData)),
+	}
+
+	for _, one := range createData {
+		plan.Creates = append(plan.Creates, PlanItem{UUID: one.GetUUID()})
+	}
+
+	differ, canDiff := sync.Handler.(FieldDiffer[SourceDataType, TargetDataType])
+	for _, one := range updates {
+		item := PlanItem{UUID: one.target.GetUUID(), ID: one.target.GetID()}
+		if canDiff {
+			item.Changes = differ.DiffFields(one.source, one.target)
+		}
+		plan.Updates = append(plan.Updates, item)
+	}
+
+	for _, one := range delData {
+		plan.Deletes = append(plan.Deletes, PlanItem{UUID: one.GetUUID(), ID: one.GetID()})
+	}
+
+	return plan
+}
@@ -0,0 +1,329 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	apisync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	CloudID string
+	Name    string
+}
+
+func (s fakeSource) GetUUID() string { return s.CloudID }
+
+type fakeTarget struct {
+	ID      string
+	CloudID string
+	Name    string
+}
+
+func (t fakeTarget) GetUUID() string { return t.CloudID }
+
+func (t fakeTarget) GetID() string { return t.ID }
+
+// fakeHandler 内存中的数据源和目标源
+type fakeHandler struct {
+	source []fakeSource
+	target []fakeTarget
+
+	deleted []string
+	updated []string
+	created []string
+	// afterSynced AfterSync 收到的数据源数据
+	afterSynced []string
+}
+
+func (h *fakeHandler) Name() HandlerName { return "fake" }
+
+func (h *fakeHandler) QueryFromSource(_ *kit.Kit, uuids []string) ([]fakeSource, error) {
+	return filterByUUID(h.source, uuids), nil
+}
+
+func (h *fakeHandler) QueryFromTarget(_ *kit.Kit, uuids []string) ([]fakeTarget, error) {
+	return filterByUUID(h.target, uuids), nil
+}
+
+func (h *fakeHandler) DiffFunc(source fakeSource, target fakeTarget) bool {
+	return source.Name != target.Name
+}
+
+func (h *fakeHandler) DiffFields(source fakeSource, target fakeTarget) []FieldChange {
+	return AppendIfChanged(nil, "name", target.Name, source.Name)
+}
+
+func (h *fakeHandler) DeleteTargetData(_ *kit.Kit, _ []string, delIDs []string) error {
+	h.deleted = append(h.deleted, delIDs...)
+	return nil
+}
+
+func (h *fakeHandler) CreateTargetData(_ *kit.Kit, _ []string, createData []fakeSource) ([]string, error) {
+	ids := make([]string, 0, len(createData))
+	for _, one := range createData {
+		ids = append(ids, "new-"+one.CloudID)
+	}
+	h.created = append(h.created, ids...)
+	return ids, nil
+}
+
+func (h *fakeHandler) UpdateTargetData(_ *kit.Kit, _ []string, idUpdateDataMap map[string]fakeSource) error {
+	for id := range idUpdateDataMap {
+		h.updated = append(h.updated, id)
+	}
+	return nil
+}
+
+func (h *fakeHandler) AfterSync(_ *kit.Kit, _ []string, sourceData []fakeSource) error {
+	for _, one := range sourceData {
+		h.afterSynced = append(h.afterSynced, one.CloudID)
+	}
+	return nil
+}
+
+func filterByUUID[T Data](items []T, uuids []string) []T {
+	if uuids == nil {
+		return items
+	}
+
+	want := make(map[string]struct{}, len(uuids))
+	for _, one := range uuids {
+		want[one] = struct{}{}
+	}
+	result := make([]T, 0)
+	for _, one := range items {
+		if _, ok := want[one.GetUUID()]; ok {
+			result = append(result, one)
+		}
+	}
+	return result
+}
+
+// singlePager 只有一页数据的分页器
+type singlePager struct {
+	handler    *fakeHandler
+	sourceDone bool
+	targetDone bool
+}
+
+func (p *singlePager) BuildParam(uuids []string) []string { return uuids }
+
+func (p *singlePager) NextFromSource(_ *kit.Kit) ([]string, error) {
+	if p.sourceDone {
+		return nil, nil
+	}
+	p.sourceDone = true
+	uuids := make([]string, 0, len(p.handler.source))
+	for _, one := range p.handler.source {
+		uuids = append(uuids, one.GetUUID())
+	}
+	return uuids, nil
+}
+
+func (p *singlePager) HasNextFromSource() (bool, error) { return !p.sourceDone, nil }
+
+func (p *singlePager) NextFromTarget(_ *kit.Kit) (map[string]string, error) {
+	if p.targetDone {
+		return nil, nil
+	}
+	p.targetDone = true
+	uuidIDMap := make(map[string]string, len(p.handler.target))
+	for _, one := range p.handler.target {
+		uuidIDMap[one.GetUUID()] = one.GetID()
+	}
+	return uuidIDMap, nil
+}
+
+func (p *singlePager) HasNextFromTarget() (bool, error) { return !p.targetDone, nil }
+
+func newFakeHandler() *fakeHandler {
+	return &fakeHandler{
+		source: []fakeSource{
+			{CloudID: "c1", Name: "same"},
+			{CloudID: "c2", Name: "renamed"},
+			{CloudID: "c4", Name: "created"},
+		},
+		target: []fakeTarget{
+			{ID: "1", CloudID: "c1", Name: "same"},
+			{ID: "2", CloudID: "c2", Name: "origin"},
+			{ID: "3", CloudID: "c3", Name: "removed"},
+		},
+	}
+}
+
+func TestBatchOrAllApply(t *testing.T) {
+	handler := newFakeHandler()
+	syncer := &Syncer[[]string, fakeSource, fakeTarget]{Handler: handler}
+
+	result, err := syncer.BatchOrAll(kit.New(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"3"}, result.DeleteIDs)
+	assert.Equal(t, []string{"2"}, result.UpdateIDs)
+	assert.Equal(t, []string{"new-c4"}, result.CreateIDs)
+	assert.Nil(t, result.Plan)
+	assert.Equal(t, []string{"3"}, handler.deleted)
+	assert.Equal(t, []string{"2"}, handler.updated)
+	assert.Equal(t, []string{"c1", "c2", "c4"}, handler.afterSynced)
+}
+
+func TestBatchOrAllDryRun(t *testing.T) {
+	handler := newFakeHandler()
+	syncer := &Syncer[[]string, fakeSource, fakeTarget]{Handler: handler, Option: Option{DryRun: true}}
+
+	result, err := syncer.BatchOrAll(kit.New(), nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, handler.deleted)
+	assert.Empty(t, handler.updated)
+	assert.Empty(t, handler.created)
+	assert.Empty(t, handler.afterSynced)
+	assert.Empty(t, result.DeleteIDs)
+
+	require.NotNil(t, result.Plan)
+	assert.Equal(t, []PlanItem{{UUID: "c4"}}, result.Plan.Creates)
+	assert.Equal(t, []PlanItem{{UUID: "c3", ID: "3"}}, result.Plan.Deletes)
+	assert.Equal(t, []PlanItem{{UUID: "c2", ID: "2",
+		Changes: []FieldChange{{Field: "name", Old: "origin", New: "renamed"}}}}, result.Plan.Updates)
+}
+
+func TestDeleteThreshold(t *testing.T) {
+	handler := newFakeHandler()
+	// 数据源接口异常返回空数据，所有本地数据都会被判定为删除
+	handler.source = nil
+	syncer := &Syncer[[]string, fakeSource, fakeTarget]{Handler: handler, Option: Option{DeleteThreshold: 50}}
+
+	_, err := syncer.BatchOrAll(kit.New(), nil)
+	assert.True(t, errors.Is(err, ErrDeleteThresholdExceeded))
+	assert.Empty(t, handler.deleted)
+
+	syncer.Pager = &singlePager{handler: handler}
+	_, err = syncer.RemoveDeletedFromSource(kit.New())
+	assert.True(t, errors.Is(err, ErrDeleteThresholdExceeded))
+	assert.Empty(t, handler.deleted)
+
+	// 删除比例未超过阈值时正常删除
+	handler = newFakeHandler()
+	syncer = &Syncer[[]string, fakeSource, fakeTarget]{Handler: handler, Option: Option{DeleteThreshold: 50}}
+	_, err = syncer.BatchOrAll(kit.New(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, handler.deleted)
+}
+
+func TestAllPagesDryRun(t *testing.T) {
+	handler := newFakeHandler()
+	syncer := &Syncer[[]string, fakeSource, fakeTarget]{
+		Pager:   &singlePager{handler: handler},
+		Handler: handler,
+		Option:  Option{DryRun: true},
+	}
+
+	result, err := syncer.AllPages(kit.New())
+	require.NoError(t, err)
+
+	assert.Empty(t, handler.deleted)
+	require.NotNil(t, result.Plan)
+	assert.Equal(t, []PlanItem{{UUID: "c3", ID: "3"}}, result.Plan.Deletes)
+	assert.Len(t, result.Plan.Creates, 1)
+	assert.Len(t, result.Plan.Updates, 1)
+}
+
+// multiPager 目标源按固定大小分页的分页器
+type multiPager struct {
+	handler *fakeHandler
+	size    int
+	offset  int
+}
+
+func (p *multiPager) BuildParam(uuids []string) []string { return uuids }
+
+func (p *multiPager) NextFromSource(_ *kit.Kit) ([]string, error) { return nil, nil }
+
+func (p *multiPager) HasNextFromSource() (bool, error) { return false, nil }
+
+func (p *multiPager) NextFromTarget(_ *kit.Kit) (map[string]string, error) {
+	uuidIDMap := make(map[string]string)
+	for i := p.offset; i < len(p.handler.target) && i < p.offset+p.size; i++ {
+		uuidIDMap[p.handler.target[i].GetUUID()] = p.handler.target[i].GetID()
+	}
+	p.offset += p.size
+	return uuidIDMap, nil
+}
+
+func (p *multiPager) HasNextFromTarget() (bool, error) { return p.offset < len(p.handler.target), nil }
+
+func TestDeleteThresholdAcrossPages(t *testing.T) {
+	newHandler := func(sourceIDs ...string) *fakeHandler {
+		handler := &fakeHandler{}
+		for i := 1; i <= 6; i++ {
+			id := string(rune('0' + i))
+			handler.target = append(handler.target, fakeTarget{ID: id, CloudID: "c" + id})
+		}
+		for _, id := range sourceIDs {
+			handler.source = append(handler.source, fakeSource{CloudID: "c" + id})
+		}
+		return handler
+	}
+
+	// 第一页全部删除，但整体删除比例未超过阈值，小页的比例不应终止同步
+	handler := newHandler("3", "4", "5", "6")
+	syncer := &Syncer[[]string, fakeSource, fakeTarget]{Pager: &multiPager{handler: handler, size: 2},
+		Handler: handler, Option: Option{DeleteThreshold: 50}}
+	ids, err := syncer.RemoveDeletedFromSource(kit.New())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2"}, ids)
+	assert.ElementsMatch(t, []string{"1", "2"}, handler.deleted)
+
+	// 整体超过阈值时，前面页的数据也不能被删除
+	handler = newHandler("2", "5")
+	syncer = &Syncer[[]string, fakeSource, fakeTarget]{Pager: &multiPager{handler: handler, size: 2},
+		Handler: handler, Option: Option{DeleteThreshold: 50}}
+	_, err = syncer.RemoveDeletedFromSource(kit.New())
+	assert.True(t, errors.Is(err, ErrDeleteThresholdExceeded))
+	assert.Empty(t, handler.deleted)
+}
+
+func TestNewOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		opt    *apisync.PlanOption
+		expect Option
+	}{
+		{name: "nil", opt: nil, expect: Option{DeleteThreshold: 50}},
+		{name: "default threshold", opt: &apisync.PlanOption{DryRun: true},
+			expect: Option{DryRun: true, DeleteThreshold: 50}},
+		{name: "request threshold", opt: &apisync.PlanOption{DeleteThreshold: 80},
+			expect: Option{DeleteThreshold: 80}},
+		{name: "no limit", opt: &apisync.PlanOption{DeleteThreshold: 100}, expect: Option{DeleteThreshold: 100}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expect, newOption(c.opt, 50))
+		})
+	}
+}
@@ -19,6 +19,11 @@
 
 package sync
 
+import (
+	apisync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/cc"
+)
+
 // HandlerName define handler name.
 type HandlerName string
 
@@ -49,4 +54,33 @@ type Result struct {
 	DeleteIDs []string `json:"delete_ids"`
 	CreateIDs []string `json:"create_ids"`
 	UpdateIDs []string `json:"update_ids"`
+	// Plan dry-run 模式下返回的变更计划，此时 DeleteIDs/CreateIDs/UpdateIDs 均为空
+	Plan *Plan `json:"plan,omitempty"`
+}
+
+// Option define syncer option.
+type Option struct {
+	// DryRun 仅对比数据源和目标源，返回变更计划，不对目标源做任何修改
+	DryRun bool `json:"dry_run"`
+	// DeleteThreshold 删除比例阈值(百分比，0-100)，要删除的目标数据占目标源全量数据的比例超过该值时，
+	// 在删除任何数据前终止同步，为0时不限制
+	DeleteThreshold float64 `json:"delete_threshold"`
+}
+
+// NewOption 将同步接口的计划选项转换为同步器选项，请求未指定删除比例阈值时使用 hc-service 配置的阈值
+func NewOption(opt *apisync.PlanOption) Option {
+	return newOption(opt, cc.HCService().SyncConfig.DeleteThreshold)
+}
+
+func newOption(opt *apisync.PlanOption, defaultThreshold float64) Option {
+	result := Option{DeleteThreshold: defaultThreshold}
+	if opt == nil {
+		return result
+	}
+
+	result.DryRun = opt.DryRun
+	if opt.DeleteThreshold > 0 {
+		result.DeleteThreshold = opt.DeleteThreshold
+	}
+	return result
 }
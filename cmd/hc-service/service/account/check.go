@@ -20,6 +20,7 @@
 package account
 
 import (
+	"hcm/cmd/hc-service/logics/cloud-adaptor"
 	"hcm/pkg/adaptor/types"
 	proto "hcm/pkg/api/hc-service/account"
 	"hcm/pkg/criteria/errf"
@@ -36,11 +37,8 @@ func (svc *service) TCloudAccountCheck(cts *rest.Contexts) (interface{}, error)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	client, err := svc.ad.Adaptor().TCloud(
-		&types.BaseSecret{
-			CloudSecretID:  req.CloudSecretID,
-			CloudSecretKey: req.CloudSecretKey,
-		})
+	client, err := svc.ad.Adaptor().TCloud(cloudadaptor.TCloudRoleSecret(req.CloudSecretID, req.CloudSecretKey,
+		req.CloudRoleArn, req.CloudExternalID))
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +48,8 @@ func (svc *service) TCloudAccountCheck(cts *rest.Contexts) (interface{}, error)
 		return nil, err
 	}
 	// check if cloud account info matches the hcm account detail.
-	if infoBySecret.CloudSubAccountID != req.CloudSubAccountID {
+	// 角色扮演时调用方为角色，不是子账号，只校验主账号
+	if len(req.CloudRoleArn) == 0 && infoBySecret.CloudSubAccountID != req.CloudSubAccountID {
 		return nil, errf.New(errf.InvalidParameter,
 			"CloudSubAccountID does not match the account to which the secret belongs")
 	}
@@ -74,11 +73,8 @@ func (svc *service) AwsAccountCheck(cts *rest.Contexts) (interface{}, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	client, err := svc.ad.Adaptor().Aws(
-		&types.BaseSecret{
-			CloudSecretID:  req.CloudSecretID,
-			CloudSecretKey: req.CloudSecretKey,
-		}, req.CloudAccountID)
+	client, err := svc.ad.Adaptor().Aws(cloudadaptor.AwsRoleSecret(req.CloudSecretID, req.CloudSecretKey,
+		req.CloudRoleArn, req.CloudExternalID), req.CloudAccountID)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +84,8 @@ func (svc *service) AwsAccountCheck(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	if infoBySecret.CloudIamUsername != req.CloudIamUsername {
+	// 角色扮演时调用方为角色会话，不是iam用户，只校验账号id
+	if len(req.CloudRoleArn) == 0 && infoBySecret.CloudIamUsername != req.CloudIamUsername {
 		return nil, errf.New(errf.InvalidParameter,
 			"CloudIamUsername does not match the account to which the secret belongs")
 	}
@@ -109,15 +106,26 @@ func (svc *service) HuaWeiAccountCheck(cts *rest.Contexts) (interface{}, error)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	client, err := svc.ad.Adaptor().HuaWei(
-		&types.BaseSecret{
-			CloudSecretID:  req.CloudSecretID,
-			CloudSecretKey: req.CloudSecretKey,
-		})
+	client, err := svc.ad.Adaptor().HuaWei(cloudadaptor.HuaWeiAgencySecret(req.CloudSecretID, req.CloudSecretKey,
+		req.CloudSubAccountName, req.CloudAgencyName))
 	if err != nil {
 		return nil, err
 	}
 
+	// 委托时临时凭证不属于iam用户，只校验委托方账号
+	if len(req.CloudAgencyName) != 0 {
+		info, err := client.GetAgencyAccountInfo(cts.Kit, req.CloudSubAccountName)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.CloudSubAccountID != req.CloudSubAccountID {
+			return nil, errf.New(errf.InvalidParameter,
+				"CloudSubAccountID does not match the account to which the agency belongs")
+		}
+		return nil, nil
+	}
+
 	infoBySecret, err := client.GetAccountInfoBySecret(cts.Kit, req.CloudSecretID)
 	if err != nil {
 		return nil, err
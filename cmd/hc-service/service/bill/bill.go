@@ -40,8 +40,9 @@ import (
 // InitBillService initial the bill service
 func InitBillService(cap *capability.Capability) {
 	v := &bill{
-		ad: cap.CloudAdaptor,
-		cs: cap.ClientSet,
+		ad:              cap.CloudAdaptor,
+		cs:              cap.ClientSet,
+		tcloudRootBills: newRootBillPageCache(tcloudRootBillCacheTTL, tcloudRootBillCacheMaxPages),
 	}
 
 	h := rest.NewHandler()
@@ -50,6 +51,8 @@ func InitBillService(cap *capability.Capability) {
 	h.Add("AwsBillsPipeline", "POST", "/vendors/aws/bills/pipeline", v.AwsBillPipeline)
 	h.Add("AwsBillConfigDelete", "DELETE", "/vendors/aws/bills/{id}", v.AwsBillConfigDelete)
 	h.Add("TCloudGetBillList", "POST", "/vendors/tcloud/bills/list", v.TCloudGetBillList)
+	h.Add("TCloudGetRootAccountBillList", "POST",
+		"/vendors/tcloud/root_account_bills/list", v.TCloudGetRootAccountBillList)
 	h.Add("HuaWeiGetBillList", "POST", "/vendors/huawei/bills/list", v.HuaWeiGetBillList)
 	h.Add("HuaWeiGetFeeRecordList", "POST", "/vendors/huawei/feerecords/list", v.HuaWeiGetFeeRecordList)
 	h.Add("AzureGetBillList", "POST", "/vendors/azure/bills/list", v.AzureGetBillList)
@@ -70,6 +73,8 @@ func InitBillService(cap *capability.Capability) {
 type bill struct {
 	ad *cloudadaptor.CloudAdaptorClient
 	cs *client.ClientSet
+	// tcloudRootBills 腾讯云一级账号账单分页缓存，各二级账号的拉取任务共用
+	tcloudRootBills *rootBillPageCache
 }
 
 // getBillInfo get bill info.
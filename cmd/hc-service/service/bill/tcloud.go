@@ -27,6 +27,9 @@ import (
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
 )
 
 // TCloudGetBillList get tcloud bill list.
@@ -76,3 +79,66 @@ func (b bill) TCloudGetBillList(cts *rest.Contexts) (interface{}, error) {
 		RequestId: resp.RequestId,
 	}, nil
 }
+
+// TCloudGetRootAccountBillList get tcloud root account bill list, and filter by main account.
+func (b bill) TCloudGetRootAccountBillList(cts *rest.Contexts) (interface{}, error) {
+	req := new(hcbillservice.TCloudRootBillListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if req.Page == nil {
+		req.Page = &core.TCloudPage{Offset: 0, Limit: core.TCloudQueryLimit}
+	}
+
+	cli, err := b.ad.TCloudRoot(cts.Kit, req.RootAccountID)
+	if err != nil {
+		logs.Errorf("tcloud request adaptor root client err, err: %+v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	key := rootBillPageKey(req.RootAccountID, req.BeginDate, req.EndDate, req.Page.Offset, req.Page.Limit)
+	page, err := b.tcloudRootBills.Get(key, func() (*rootBillPage, error) {
+		opt := &typesBill.TCloudBillListOption{
+			AccountID: req.RootAccountID,
+			BeginDate: req.BeginDate,
+			EndDate:   req.EndDate,
+			Page: &core.TCloudPage{
+				Offset: req.Page.Offset,
+				Limit:  req.Page.Limit,
+			},
+			Context: req.Context,
+		}
+		resp, err := cli.GetBillList(cts.Kit, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &rootBillPage{total: cvt.PtrToVal(resp.Total), details: resp.DetailSet, context: resp.Context}, nil
+	})
+	if err != nil {
+		logs.Errorf("tcloud request adaptor list root bill failed, req: %v, err: %v, rid: %s", req, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &hcbillservice.TCloudRootBillListResult{
+		Count:   page.total,
+		Details: filterBillByOwnerUin(page.details, req.MainAccountCloudID),
+		Context: page.context,
+	}, nil
+}
+
+// filterBillByOwnerUin 一级账号可以查询到所有二级账号的账单，按照资源归属的二级账号进行过滤
+func filterBillByOwnerUin(details []*billing.BillDetail, ownerUin string) []*billing.BillDetail {
+	result := make([]*billing.BillDetail, 0, len(details))
+	for _, one := range details {
+		if one == nil || cvt.PtrToVal(one.OwnerUin) != ownerUin {
+			continue
+		}
+		result = append(result, one)
+	}
+	return result
+}
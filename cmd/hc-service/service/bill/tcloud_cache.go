@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// tcloudRootBillCacheTTL 同一一级账号下各二级账号的当日拉取任务集中在这段时间内执行
+	tcloudRootBillCacheTTL = 10 * time.Minute
+	// tcloudRootBillCacheMaxPages 最多缓存的账单页数，每页最多 core.TCloudQueryLimit 条
+	tcloudRootBillCacheMaxPages = 200
+)
+
+// rootBillPage 一级账号账单的一页查询结果，未按二级账号过滤
+type rootBillPage struct {
+	total    uint64
+	details  []*billing.BillDetail
+	context  *string
+	expireAt time.Time
+}
+
+// rootBillPageCache 缓存一级账号账单的分页查询结果。腾讯云只能按一级账号查询账单，
+// 同一一级账号下的每个二级账号拉取同一天的账单时都会查询相同的分页，缓存后每页只需查询云上一次。
+type rootBillPageCache struct {
+	ttl      time.Duration
+	maxPages int
+	now      func() time.Time
+
+	lock  sync.Mutex
+	pages map[string]*rootBillPage
+	group singleflight.Group
+}
+
+func newRootBillPageCache(ttl time.Duration, maxPages int) *rootBillPageCache {
+	return &rootBillPageCache{
+		ttl:      ttl,
+		maxPages: maxPages,
+		now:      time.Now,
+		pages:    make(map[string]*rootBillPage),
+	}
+}
+
+// rootBillPageKey 分页按 offset 定位，上下文只用于加快查询，不影响结果，因此不作为缓存键
+func rootBillPageKey(rootAccountID, beginDate, endDate string, offset, limit uint64) string {
+	return fmt.Sprintf("%s/%s/%s/%d/%d", rootAccountID, beginDate, endDate, offset, limit)
+}
+
+// Get 获取缓存的分页，不存在或已过期时调用 fetch 查询，并发查询同一分页时只查询一次
+func (c *rootBillPageCache) Get(key string, fetch func() (*rootBillPage, error)) (*rootBillPage, error) {
+	if page, ok := c.get(key); ok {
+		return page, nil
+	}
+
+	val, err, _ := c.group.Do(key, func() (interface{}, error) {
+		if page, ok := c.get(key); ok {
+			return page, nil
+		}
+		page, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, page)
+		return page, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.(*rootBillPage), nil
+}
+
+func (c *rootBillPageCache) get(key string) (*rootBillPage, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	page, ok := c.pages[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(page.expireAt) {
+		delete(c.pages, key)
+		return nil, false
+	}
+	return page, true
+}
+
+func (c *rootBillPageCache) set(key string, page *rootBillPage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	page.expireAt = now.Add(c.ttl)
+	for k, one := range c.pages {
+		if !now.Before(one.expireAt) {
+			delete(c.pages, k)
+		}
+	}
+	// 超出容量时淘汰最早过期的分页
+	for len(c.pages) >= c.maxPages {
+		oldestKey := ""
+		for k, one := range c.pages {
+			if oldestKey == "" || one.expireAt.Before(c.pages[oldestKey].expireAt) {
+				oldestKey = k
+			}
+		}
+		delete(c.pages, oldestKey)
+	}
+	c.pages[key] = page
+}
@@ -0,0 +1,156 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+func TestFilterBillByOwnerUin(t *testing.T) {
+	a1 := &billing.BillDetail{OwnerUin: cvt.ValToPtr("100001"), ResourceId: cvt.ValToPtr("ins-1")}
+	a2 := &billing.BillDetail{OwnerUin: cvt.ValToPtr("100001"), ResourceId: cvt.ValToPtr("ins-2")}
+	b1 := &billing.BillDetail{OwnerUin: cvt.ValToPtr("100002"), ResourceId: cvt.ValToPtr("ins-3")}
+	noOwner := &billing.BillDetail{ResourceId: cvt.ValToPtr("ins-4")}
+
+	tests := []struct {
+		name     string
+		details  []*billing.BillDetail
+		ownerUin string
+		want     []*billing.BillDetail
+	}{
+		{
+			name:     "empty page",
+			details:  nil,
+			ownerUin: "100001",
+			want:     []*billing.BillDetail{},
+		},
+		{
+			name:     "keep only owner bills in order",
+			details:  []*billing.BillDetail{a1, b1, a2},
+			ownerUin: "100001",
+			want:     []*billing.BillDetail{a1, a2},
+		},
+		{
+			name:     "other owner",
+			details:  []*billing.BillDetail{a1, b1, a2},
+			ownerUin: "100002",
+			want:     []*billing.BillDetail{b1},
+		},
+		{
+			name:     "no match",
+			details:  []*billing.BillDetail{a1, b1},
+			ownerUin: "100003",
+			want:     []*billing.BillDetail{},
+		},
+		{
+			name:     "skip nil and bills without owner",
+			details:  []*billing.BillDetail{nil, noOwner, a1},
+			ownerUin: "100001",
+			want:     []*billing.BillDetail{a1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filterBillByOwnerUin(tt.details, tt.ownerUin))
+		})
+	}
+}
+
+func TestRootBillPageCache(t *testing.T) {
+	now := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	cache := newRootBillPageCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	fetched := 0
+	fetch := func(total uint64) func() (*rootBillPage, error) {
+		return func() (*rootBillPage, error) {
+			fetched++
+			return &rootBillPage{total: total}, nil
+		}
+	}
+	key1 := rootBillPageKey("root-1", "2024-05-01 00:00:00", "2024-05-01 23:59:59", 0, 100)
+	key2 := rootBillPageKey("root-1", "2024-05-01 00:00:00", "2024-05-01 23:59:59", 100, 100)
+	key3 := rootBillPageKey("root-2", "2024-05-01 00:00:00", "2024-05-01 23:59:59", 0, 100)
+
+	// 同一分页只查询一次
+	page, err := cache.Get(key1, fetch(1))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), page.total)
+	page, err = cache.Get(key1, fetch(2))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), page.total)
+	assert.Equal(t, 1, fetched)
+
+	// 查询失败不缓存
+	_, err = cache.Get(key2, func() (*rootBillPage, error) { return nil, errors.New("throttled") })
+	assert.Error(t, err)
+	_, err = cache.Get(key2, fetch(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetched)
+
+	// 超出容量时淘汰最早过期的分页
+	now = now.Add(time.Second)
+	_, err = cache.Get(key3, fetch(3))
+	assert.NoError(t, err)
+	assert.Len(t, cache.pages, 2)
+	assert.NotContains(t, cache.pages, key1)
+
+	// 过期后重新查询
+	now = now.Add(time.Minute)
+	page, err = cache.Get(key3, fetch(4))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), page.total)
+	assert.Equal(t, 4, fetched)
+}
+
+func TestRootBillPageCacheConcurrent(t *testing.T) {
+	cache := newRootBillPageCache(time.Minute, 10)
+	key := rootBillPageKey("root-1", "2024-05-01 00:00:00", "2024-05-01 23:59:59", 0, 100)
+
+	var fetched int32
+	release := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			page, err := cache.Get(key, func() (*rootBillPage, error) {
+				atomic.AddInt32(&fetched, 1)
+				<-release
+				return &rootBillPage{total: 1}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), page.total)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetched))
+}
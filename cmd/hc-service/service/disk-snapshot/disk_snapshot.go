@@ -0,0 +1,365 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot 云硬盘快照的云上操作及同步
+package disksnapshot
+
+import (
+	"fmt"
+	"net/http"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	typesnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitDiskSnapshotService initial the disk snapshot service
+func InitDiskSnapshotService(cap *capability.Capability) {
+	svc := &diskSnapshotSvc{
+		adaptor: cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateDiskSnapshot", http.MethodPost, "/vendors/{vendor}/disk_snapshots/create", svc.CreateDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/vendors/{vendor}/disk_snapshots/batch",
+		svc.BatchDeleteDiskSnapshot)
+	h.Add("RollbackDiskSnapshot", http.MethodPost, "/vendors/{vendor}/disk_snapshots/rollback",
+		svc.RollbackDiskSnapshot)
+	h.Add("SyncDiskSnapshot", http.MethodPost, "/vendors/{vendor}/disk_snapshots/sync", svc.SyncDiskSnapshot)
+
+	h.Load(cap.WebService)
+}
+
+type diskSnapshotSvc struct {
+	adaptor *cloudclient.CloudAdaptorClient
+	dataCli *dataclient.Client
+}
+
+// snapshotter 云厂商客户端的云硬盘快照操作
+type snapshotter interface {
+	CreateDiskSnapshot(kt *kit.Kit, opt *typesnapshot.CreateOption) (string, error)
+	DeleteDiskSnapshot(kt *kit.Kit, opt *typesnapshot.DeleteOption) error
+	ListDiskSnapshot(kt *kit.Kit, opt *typesnapshot.ListOption) ([]typesnapshot.DiskSnapshot, error)
+	RollbackDiskSnapshot(kt *kit.Kit, opt *typesnapshot.RollbackOption) error
+}
+
+func (svc *diskSnapshotSvc) getSnapshotter(kt *kit.Kit, vendor enumor.Vendor, accountID string) (snapshotter,
+	error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return svc.adaptor.TCloud(kt, accountID)
+	case enumor.Aws:
+		return svc.adaptor.Aws(kt, accountID)
+	case enumor.HuaWei:
+		return svc.adaptor.HuaWei(kt, accountID)
+	case enumor.Gcp:
+		return svc.adaptor.Gcp(kt, accountID)
+	case enumor.Azure:
+		return svc.adaptor.Azure(kt, accountID)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "unsupported vendor: %s", vendor)
+	}
+}
+
+// CreateDiskSnapshot 为云盘创建快照，先写云上再写db
+func (svc *diskSnapshotSvc) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	diskInfo, err := svc.getDisk(cts.Kit, vendor, req.AccountID, req.DiskID)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.getSnapshotter(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typesnapshot.CreateOption{
+		Region:            diskInfo.Region,
+		Zone:              diskInfo.Zone,
+		ResourceGroupName: diskInfo.ResourceGroupName,
+		CloudDiskID:       diskInfo.CloudID,
+		Name:              req.Name,
+	}
+	cloudID, err := cli.CreateDiskSnapshot(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("[%s] create disk snapshot failed, err: %v, disk: %s, rid: %s", vendor, err, req.DiskID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	snapshot := typesnapshot.DiskSnapshot{CloudID: cloudID, Name: req.Name, CloudDiskID: diskInfo.CloudID,
+		Zone: diskInfo.Zone, DiskSize: diskInfo.DiskSize}
+	listOpt := &typesnapshot.ListOption{Region: diskInfo.Region, ResourceGroupName: diskInfo.ResourceGroupName,
+		CloudIDs: []string{cloudID}}
+	// 云上快照的详情仅用于补全db数据，查询失败时不影响创建结果，后续由同步更新
+	if snapshots, err := cli.ListDiskSnapshot(cts.Kit, listOpt); err != nil {
+		logs.Errorf("[%s] list created disk snapshot %s failed, err: %v, rid: %s", vendor, cloudID, err,
+			cts.Kit.Rid)
+	} else if len(snapshots) != 0 {
+		snapshot = snapshots[0]
+	}
+
+	one := convSnapshotCreate(vendor, req.AccountID, diskInfo.Region, snapshot,
+		map[string]diskRef{diskInfo.CloudID: diskInfo.diskRef})
+	one.PolicyID = req.PolicyID
+	one.Memo = req.Memo
+	if one.Extension == nil {
+		one.Extension = new(corecloud.DiskSnapshotExtension)
+	}
+	one.Extension.ResourceGroupName = diskInfo.ResourceGroupName
+
+	result, err := svc.dataCli.Global.DiskSnapshot.BatchCreate(cts.Kit,
+		&protocloud.DiskSnapshotBatchCreateReq{Snapshots: []protocloud.DiskSnapshotCreate{one}})
+	if err != nil {
+		logs.Errorf("create disk snapshot %s to db failed, err: %v, rid: %s", cloudID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.IDs) != 1 {
+		return nil, fmt.Errorf("create disk snapshot to db return ids %v is invalid", result.IDs)
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// BatchDeleteDiskSnapshot 批量删除快照，先删除云上再删除db
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listDBSnapshot(cts.Kit, tools.ExpressionAnd(
+		tools.RuleEqual("vendor", vendor),
+		tools.RuleEqual("account_id", req.AccountID),
+		tools.RuleIn("id", req.IDs),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) != len(req.IDs) {
+		return nil, errf.Newf(errf.RecordNotFound, "some disk snapshots are not found in account %s",
+			req.AccountID)
+	}
+
+	cli, err := svc.getSnapshotter(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 快照需按地域及资源组删除
+	type scope struct{ region, resGroup string }
+	scopeMap := make(map[scope][]corecloud.DiskSnapshot)
+	for _, one := range snapshots {
+		key := scope{region: one.Region}
+		if one.Extension != nil {
+			key.resGroup = one.Extension.ResourceGroupName
+		}
+		scopeMap[key] = append(scopeMap[key], one)
+	}
+
+	for key, list := range scopeMap {
+		ids := make([]string, 0, len(list))
+		cloudIDs := make([]string, 0, len(list))
+		for _, one := range list {
+			ids = append(ids, one.ID)
+			cloudIDs = append(cloudIDs, one.CloudID)
+		}
+
+		opt := &typesnapshot.DeleteOption{Region: key.region, ResourceGroupName: key.resGroup, CloudIDs: cloudIDs}
+		if err = cli.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+			logs.Errorf("[%s] delete disk snapshot failed, err: %v, ids: %v, rid: %s", vendor, err, ids,
+				cts.Kit.Rid)
+			return nil, err
+		}
+
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+		if err = svc.dataCli.Global.DiskSnapshot.BatchDelete(cts.Kit, delReq); err != nil {
+			logs.Errorf("delete disk snapshot from db failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// RollbackDiskSnapshot 使用快照回滚其源云盘
+func (svc *diskSnapshotSvc) RollbackDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.RollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listDBSnapshot(cts.Kit, tools.ExpressionAnd(
+		tools.RuleEqual("vendor", vendor),
+		tools.RuleEqual("account_id", req.AccountID),
+		tools.RuleEqual("id", req.ID),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot %s is not found", req.ID)
+	}
+	snapshot := snapshots[0]
+
+	cli, err := svc.getSnapshotter(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typesnapshot.RollbackOption{
+		Region:           snapshot.Region,
+		CloudID:          snapshot.CloudID,
+		CloudDiskID:      snapshot.CloudDiskID,
+		AutoStopInstance: req.AutoStopInstance,
+	}
+	if err = cli.RollbackDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("[%s] rollback disk snapshot %s failed, err: %v, rid: %s", vendor, req.ID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// diskRef 快照关联的hcm云盘信息
+type diskRef struct {
+	ID      string
+	BkBizID int64
+}
+
+// diskInfo 创建快照所需的源云盘信息
+type diskInfo struct {
+	diskRef
+	CloudID           string
+	Region            string
+	Zone              string
+	DiskSize          uint64
+	ResourceGroupName string
+}
+
+func (svc *diskSnapshotSvc) getDisk(kt *kit.Kit, vendor enumor.Vendor, accountID, diskID string) (*diskInfo,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("id", diskID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.ListDisk(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk %s failed, err: %v, rid: %s", diskID, err, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk %s is not found in account %s", diskID, accountID)
+	}
+
+	one := result.Details[0]
+	info := &diskInfo{
+		diskRef:  diskRef{ID: one.ID, BkBizID: one.BkBizID},
+		CloudID:  one.CloudID,
+		Region:   one.Region,
+		Zone:     one.Zone,
+		DiskSize: one.DiskSize,
+	}
+
+	if vendor == enumor.Azure {
+		azureDisk, err := svc.dataCli.Azure.RetrieveDisk(kt.Ctx, kt.Header(), diskID)
+		if err != nil {
+			logs.Errorf("retrieve azure disk %s failed, err: %v, rid: %s", diskID, err, kt.Rid)
+			return nil, err
+		}
+		if azureDisk.Extension != nil {
+			info.ResourceGroupName = azureDisk.Extension.ResourceGroupName
+		}
+	}
+
+	return info, nil
+}
+
+func convSnapshotCreate(vendor enumor.Vendor, accountID, region string, one typesnapshot.DiskSnapshot,
+	diskMap map[string]diskRef) protocloud.DiskSnapshotCreate {
+
+	if len(one.Region) != 0 {
+		region = one.Region
+	}
+
+	ref := diskMap[one.CloudDiskID]
+	bizID := ref.BkBizID
+	if len(ref.ID) == 0 {
+		bizID = constant.UnassignedBiz
+	}
+
+	return protocloud.DiskSnapshotCreate{
+		Vendor:         vendor,
+		AccountID:      accountID,
+		CloudID:        one.CloudID,
+		Name:           one.Name,
+		BkBizID:        bizID,
+		Region:         region,
+		Zone:           one.Zone,
+		DiskID:         ref.ID,
+		CloudDiskID:    one.CloudDiskID,
+		DiskSize:       one.DiskSize,
+		Status:         one.Status,
+		Encrypted:      one.Encrypted,
+		CloudCreatedAt: one.CloudCreatedAt,
+		Extension:      one.Extension,
+	}
+}
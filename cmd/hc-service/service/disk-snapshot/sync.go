@@ -0,0 +1,365 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"reflect"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
+	typesnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncDiskSnapshot 基于通用同步器同步账号下指定地域的快照，以cloud_id对比云上和db的数据，新增、更新并删除云上已不存在的快照，
+// dry-run 模式下返回变更计划，否则返回nil
+func (svc *diskSnapshotSvc) SyncDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.SyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if vendor == enumor.Azure && len(req.ResourceGroupName) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "resource_group_name is required")
+	}
+	if vendor != enumor.Azure && len(req.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	cli, err := svc.getSnapshotter(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 快照云上接口不分页，一次查询全部快照，由同步器按页对比
+	opt := &typesnapshot.ListOption{Region: req.Region, ResourceGroupName: req.ResourceGroupName}
+	cloudSnapshots, err := cli.ListDiskSnapshot(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("[%s] list disk snapshot from cloud failed, err: %v, account: %s, region: %s, rid: %s",
+			vendor, err, req.AccountID, req.Region, cts.Kit.Rid)
+		return nil, err
+	}
+
+	cloudDiskIDs := make([]string, 0, len(cloudSnapshots))
+	for i := range cloudSnapshots {
+		if cloudSnapshots[i].Extension == nil {
+			cloudSnapshots[i].Extension = new(corecloud.DiskSnapshotExtension)
+		}
+		cloudSnapshots[i].Extension.ResourceGroupName = req.ResourceGroupName
+
+		if len(cloudSnapshots[i].CloudDiskID) != 0 {
+			cloudDiskIDs = append(cloudDiskIDs, cloudSnapshots[i].CloudDiskID)
+		}
+	}
+	diskMap, err := svc.listDiskRef(cts.Kit, vendor, req.AccountID, slice.Unique(cloudDiskIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshotSyncer{
+		svc:       svc,
+		vendor:    vendor,
+		req:       req,
+		cloudMap:  make(map[string]typesnapshot.DiskSnapshot, len(cloudSnapshots)),
+		cloudIDs:  make([]string, 0, len(cloudSnapshots)),
+		diskMap:   diskMap,
+		unlinkIDs: make(map[string]struct{}),
+	}
+	for _, one := range cloudSnapshots {
+		s.cloudMap[one.CloudID] = one
+		s.cloudIDs = append(s.cloudIDs, one.CloudID)
+	}
+
+	result, err := s.resSyncer().Syncer(logicsync.NewOption(req.PlanOption)).AllPages(cts.Kit)
+	if err != nil {
+		logs.Errorf("[%s] sync disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", vendor, err,
+			req.AccountID, req.Region, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result.Plan, nil
+}
+
+// snapshotSyncParam 快照批量同步参数
+type snapshotSyncParam struct {
+	CloudIDs []string
+}
+
+// snapshotSyncer 快照同步的数据源和目标源操作，适配通用同步器
+type snapshotSyncer struct {
+	svc    *diskSnapshotSvc
+	vendor enumor.Vendor
+	req    *proto.SyncReq
+	// cloudMap 云上全部快照，以cloud_id为key
+	cloudMap map[string]typesnapshot.DiskSnapshot
+	cloudIDs []string
+	offset   int
+	diskMap  map[string]diskRef
+	// unlinkIDs 未关联hcm云盘的db快照ID，快照创建时云盘可能还未同步到hcm，云盘同步后补全关联关系
+	unlinkIDs map[string]struct{}
+}
+
+func (s *snapshotSyncer) resSyncer() *common.ResSyncer[*snapshotSyncParam, typesnapshot.DiskSnapshot,
+	corecloud.DiskSnapshot] {
+
+	return &common.ResSyncer[*snapshotSyncParam, typesnapshot.DiskSnapshot, corecloud.DiskSnapshot]{
+		ResType: enumor.DiskSnapshotCloudResType,
+		BuildParams: func(cloudIDs []string) *snapshotSyncParam {
+			return &snapshotSyncParam{CloudIDs: cloudIDs}
+		},
+		NextFromCloud: s.nextFromCloud,
+		ListDBPage: func(kt *kit.Kit, page *core.BasePage) (map[string]string, error) {
+			snapshots, err := s.listDB(kt, page, s.scopeRules()...)
+			if err != nil {
+				return nil, err
+			}
+
+			cloudIDMap := make(map[string]string, len(snapshots))
+			for _, one := range snapshots {
+				cloudIDMap[one.CloudID] = one.ID
+			}
+			return cloudIDMap, nil
+		},
+		ListFromCloud: s.listFromCloud,
+		ListFromDB: func(kt *kit.Kit, params *snapshotSyncParam) ([]corecloud.DiskSnapshot, error) {
+			page := &core.BasePage{Limit: constant.CloudResourceSyncMaxLimit}
+			return s.listDB(kt, page, append(s.scopeRules(), tools.RuleIn("cloud_id", params.CloudIDs))...)
+		},
+		IsChange:  s.isChange,
+		Create:    s.create,
+		Update:    s.update,
+		Delete:    s.delete,
+		FieldDiff: s.fieldDiff,
+	}
+}
+
+// scopeRules 本次同步范围内db快照的查询条件
+func (s *snapshotSyncer) scopeRules() []*filter.AtomRule {
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("vendor", s.vendor),
+		tools.RuleEqual("account_id", s.req.AccountID),
+	}
+	if len(s.req.Region) != 0 {
+		rules = append(rules, tools.RuleEqual("region", s.req.Region))
+	}
+	if s.vendor == enumor.Azure {
+		rules = append(rules, tools.RuleEqual("extension.resource_group_name", s.req.ResourceGroupName))
+	}
+	return rules
+}
+
+func (s *snapshotSyncer) nextFromCloud(_ *kit.Kit) ([]string, error) {
+	if s.offset >= len(s.cloudIDs) {
+		return nil, nil
+	}
+
+	end := s.offset + constant.CloudResourceSyncMaxLimit
+	if end > len(s.cloudIDs) {
+		end = len(s.cloudIDs)
+	}
+	cloudIDs := s.cloudIDs[s.offset:end]
+	s.offset = end
+	return cloudIDs, nil
+}
+
+func (s *snapshotSyncer) listFromCloud(_ *kit.Kit, params *snapshotSyncParam) ([]typesnapshot.DiskSnapshot,
+	error) {
+
+	result := make([]typesnapshot.DiskSnapshot, 0, len(params.CloudIDs))
+	for _, cloudID := range params.CloudIDs {
+		if one, exists := s.cloudMap[cloudID]; exists {
+			result = append(result, one)
+		}
+	}
+	return result, nil
+}
+
+func (s *snapshotSyncer) listDB(kt *kit.Kit, page *core.BasePage, rules ...*filter.AtomRule) (
+	[]corecloud.DiskSnapshot, error) {
+
+	listReq := &core.ListReq{Filter: tools.ExpressionAnd(rules...), Page: page}
+	resp, err := s.svc.dataCli.Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	for _, one := range resp.Details {
+		if len(one.DiskID) == 0 {
+			s.unlinkIDs[one.ID] = struct{}{}
+		}
+	}
+	return resp.Details, nil
+}
+
+// needLink 快照未关联hcm云盘，且源云盘已同步到hcm
+func (s *snapshotSyncer) needLink(cloud typesnapshot.DiskSnapshot, db corecloud.DiskSnapshot) bool {
+	if len(db.DiskID) != 0 {
+		return false
+	}
+	_, exists := s.diskMap[cloud.CloudDiskID]
+	return exists
+}
+
+func (s *snapshotSyncer) isChange(cloud typesnapshot.DiskSnapshot, db corecloud.DiskSnapshot) bool {
+	return len(s.fieldDiff(cloud, db)) != 0
+}
+
+func (s *snapshotSyncer) fieldDiff(cloud typesnapshot.DiskSnapshot, db corecloud.DiskSnapshot) []logicsync.FieldChange {
+
+	changes := make([]logicsync.FieldChange, 0)
+	changes = logicsync.AppendIfChanged(changes, "name", db.Name, cloud.Name)
+	changes = logicsync.AppendIfChanged(changes, "disk_size", db.DiskSize, cloud.DiskSize)
+	changes = logicsync.AppendIfChanged(changes, "status", db.Status, cloud.Status)
+	if !reflect.DeepEqual(db.Extension, cloud.Extension) {
+		changes = append(changes, logicsync.FieldChange{Field: "extension", Old: db.Extension, New: cloud.Extension})
+	}
+	if s.needLink(cloud, db) {
+		changes = append(changes, logicsync.FieldChange{Field: "disk_id", Old: db.DiskID,
+			New: s.diskMap[cloud.CloudDiskID].ID})
+	}
+	return changes
+}
+
+func (s *snapshotSyncer) create(kt *kit.Kit, _ *snapshotSyncParam, addData []typesnapshot.DiskSnapshot) error {
+	toCreate := make([]protocloud.DiskSnapshotCreate, 0, len(addData))
+	for _, one := range addData {
+		toCreate = append(toCreate, convSnapshotCreate(s.vendor, s.req.AccountID, s.req.Region, one, s.diskMap))
+	}
+
+	for _, batch := range slice.Split(toCreate, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.DiskSnapshotBatchCreateReq{Snapshots: batch}
+		if _, err := s.svc.dataCli.Global.DiskSnapshot.BatchCreate(kt, createReq); err != nil {
+			logs.Errorf("create disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotSyncer) update(kt *kit.Kit, _ *snapshotSyncParam,
+	updateMap map[string]typesnapshot.DiskSnapshot) error {
+
+	toUpdate := make([]protocloud.DiskSnapshotUpdate, 0, len(updateMap))
+	for id, one := range updateMap {
+		update := protocloud.DiskSnapshotUpdate{
+			ID:        id,
+			Name:      one.Name,
+			DiskSize:  one.DiskSize,
+			Status:    one.Status,
+			Extension: one.Extension,
+		}
+		if _, unlink := s.unlinkIDs[id]; unlink {
+			if ref, ok := s.diskMap[one.CloudDiskID]; ok {
+				update.DiskID = ref.ID
+				update.BkBizID = ref.BkBizID
+			}
+		}
+		toUpdate = append(toUpdate, update)
+	}
+
+	for _, batch := range slice.Split(toUpdate, constant.BatchOperationMaxLimit) {
+		updateReq := &protocloud.DiskSnapshotBatchUpdateReq{Snapshots: batch}
+		if err := s.svc.dataCli.Global.DiskSnapshot.BatchUpdate(kt, updateReq); err != nil {
+			logs.Errorf("update disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotSyncer) delete(kt *kit.Kit, _ *snapshotSyncParam, delCloudIDs []string) error {
+	for _, batch := range slice.Split(delCloudIDs, constant.BatchOperationMaxLimit) {
+		delReq := &dataservice.BatchDeleteReq{
+			Filter: tools.ExpressionAnd(append(s.scopeRules(), tools.RuleIn("cloud_id", batch))...),
+		}
+		if err := s.svc.dataCli.Global.DiskSnapshot.BatchDelete(kt, delReq); err != nil {
+			logs.Errorf("delete disk snapshot failed, err: %v, cloud ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc *diskSnapshotSvc) listDBSnapshot(kt *kit.Kit, expr *filter.Expression) ([]corecloud.DiskSnapshot, error) {
+	listReq := &core.ListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	result := make([]corecloud.DiskSnapshot, 0)
+	for {
+		resp, err := svc.dataCli.Global.DiskSnapshot.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return result, nil
+}
+
+// listDiskRef 查询云上ID对应的hcm云盘
+func (svc *diskSnapshotSvc) listDiskRef(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	cloudDiskIDs []string) (map[string]diskRef, error) {
+
+	result := make(map[string]diskRef, len(cloudDiskIDs))
+	for _, batch := range slice.Split(cloudDiskIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", vendor),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleIn("cloud_id", batch),
+			),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"id", "cloud_id", "bk_biz_id"},
+		}
+		disks, err := svc.dataCli.Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk by cloud ids failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range disks.Details {
+			result[one.CloudID] = diskRef{ID: one.ID, BkBizID: one.BkBizID}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,759 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"net/http"
+
+	syncaws "hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/adaptor/aws"
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	protolb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+)
+
+func (svc *clbSvc) initAwsLBService(cap *capability.Capability) {
+	h := rest.NewHandler()
+
+	h.Add("CreateAwsLoadBalancer", http.MethodPost, "/vendors/aws/load_balancers/create",
+		svc.CreateAwsLoadBalancer)
+	h.Add("UpdateAwsLoadBalancer", http.MethodPatch, "/vendors/aws/load_balancers/{id}", svc.UpdateAwsLoadBalancer)
+	h.Add("BatchDeleteAwsLoadBalancer", http.MethodDelete, "/vendors/aws/load_balancers/batch",
+		svc.BatchDeleteAwsLoadBalancer)
+
+	// 监听器
+	h.Add("CreateAwsListener", http.MethodPost, "/vendors/aws/listeners/create", svc.CreateAwsListener)
+	h.Add("UpdateAwsListener", http.MethodPatch, "/vendors/aws/listeners/{id}", svc.UpdateAwsListener)
+	h.Add("BatchDeleteAwsListener", http.MethodDelete, "/vendors/aws/listeners/batch", svc.BatchDeleteAwsListener)
+
+	// 转发规则
+	h.Add("CreateAwsUrlRule", http.MethodPost, "/vendors/aws/url_rules/create", svc.CreateAwsUrlRule)
+	h.Add("UpdateAwsUrlRule", http.MethodPatch, "/vendors/aws/url_rules/{id}", svc.UpdateAwsUrlRule)
+	h.Add("BatchDeleteAwsUrlRule", http.MethodDelete, "/vendors/aws/url_rules/batch", svc.BatchDeleteAwsUrlRule)
+
+	// 目标组
+	h.Add("CreateAwsTargetGroup", http.MethodPost, "/vendors/aws/target_groups/create", svc.CreateAwsTargetGroup)
+	h.Add("UpdateAwsTargetGroup", http.MethodPatch, "/vendors/aws/target_groups/{id}", svc.UpdateAwsTargetGroup)
+	h.Add("BatchDeleteAwsTargetGroup", http.MethodDelete, "/vendors/aws/target_groups/batch",
+		svc.BatchDeleteAwsTargetGroup)
+	h.Add("BatchRegisterAwsTargets", http.MethodPost,
+		"/vendors/aws/target_groups/{target_group_id}/targets/create", svc.BatchRegisterAwsTargets)
+	h.Add("BatchDeregisterAwsTargets", http.MethodDelete,
+		"/vendors/aws/target_groups/{target_group_id}/targets/batch", svc.BatchDeregisterAwsTargets)
+
+	h.Load(cap.WebService)
+}
+
+// CreateAwsLoadBalancer 创建aws应用型/网络型负载均衡
+func (svc *clbSvc) CreateAwsLoadBalancer(cts *rest.Contexts) (any, error) {
+	req := new(protolb.AwsLoadBalancerCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpt := &typelb.AwsCreateLoadBalancerOption{
+		Region:                req.Region,
+		Name:                  req.Name,
+		Type:                  req.Type,
+		Scheme:                req.Scheme,
+		IpAddressType:         req.IpAddressType,
+		CloudSubnetIDs:        req.CloudSubnetIDs,
+		CloudSecurityGroupIDs: req.CloudSecurityGroupIDs,
+		Tags:                  req.Tags,
+	}
+	cloudID, err := client.CreateLoadBalancer(cts.Kit, createOpt)
+	if err != nil {
+		logs.Errorf("create aws load balancer failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 通过同步写入本地数据，同步时带上业务信息
+	if err = svc.awsLBSync(cts.Kit, client, req.AccountID, req.Region, []string{cloudID}, req.BkBizID); err != nil {
+		return nil, err
+	}
+
+	return &protolb.BatchCreateResult{SuccessCloudIDs: []string{cloudID}}, nil
+}
+
+// UpdateAwsLoadBalancer 更新aws负载均衡属性
+func (svc *clbSvc) UpdateAwsLoadBalancer(cts *rest.Contexts) (any, error) {
+	lbID := cts.PathParameter("id").String()
+	if len(lbID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.AwsLBUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lb, err := svc.dataCli.Aws.LoadBalancer.Get(cts.Kit, lbID)
+	if err != nil {
+		logs.Errorf("fail to get aws load balancer(%s), err: %v, rid: %s", lbID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, lb.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	updateOpt := &typelb.AwsUpdateLoadBalancerOption{
+		Region:                lb.Region,
+		CloudID:               lb.CloudID,
+		DeletionProtection:    req.DeletionProtection,
+		CloudSecurityGroupIDs: req.CloudSecurityGroupIDs,
+	}
+	if err = client.UpdateLoadBalancer(cts.Kit, updateOpt); err != nil {
+		logs.Errorf("fail to call aws update load balancer(id:%s), err: %v, rid: %s", lbID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 同步不拉取删除保护属性，由此处直接更新到本地
+	if req.DeletionProtection != nil {
+		ext := cvt.ValToPtr(cvt.PtrToVal(lb.Extension))
+		ext.DeletionProtection = req.DeletionProtection
+		updateReq := &dataproto.AwsLBBatchUpdateReq{
+			Lbs: []*dataproto.LoadBalancerExtUpdateReq[corelb.AwsLoadBalancerExtension]{
+				{ID: lb.ID, Extension: ext},
+			},
+		}
+		if err = svc.dataCli.Aws.LoadBalancer.BatchUpdate(cts.Kit, updateReq); err != nil {
+			logs.Errorf("fail to update aws load balancer deletion protection, err: %v, id: %s, rid: %s", err,
+				lb.ID, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, svc.awsLBSync(cts.Kit, client, lb.AccountID, lb.Region, []string{lb.CloudID}, 0)
+}
+
+// BatchDeleteAwsLoadBalancer 批量删除aws负载均衡
+func (svc *clbSvc) BatchDeleteAwsLoadBalancer(cts *rest.Contexts) (any, error) {
+	req := new(protolb.AwsBatchDeleteLoadBalancerReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Fields: []string{"cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("id", req.IDs),
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("account_id", req.AccountID),
+			tools.RuleEqual("region", req.Region),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dataCli.Global.LoadBalancer.ListLoadBalancer(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list aws load balancer failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delCloudIDs := make([]string, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		delCloudIDs = append(delCloudIDs, one.CloudID)
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	delOpt := &typelb.AwsDeleteOption{Region: req.Region, CloudIDs: delCloudIDs}
+	if err = client.DeleteLoadBalancer(cts.Kit, delOpt); err != nil {
+		logs.Errorf("delete aws load balancer failed, err: %v, opt: %+v, rid: %s", err, delOpt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	delReq := &dataproto.LoadBalancerBatchDeleteReq{Filter: listReq.Filter}
+	if err = svc.dataCli.Global.LoadBalancer.BatchDeleteLoadBalancer(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete db aws load balancer failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreateAwsListener 创建aws监听器，默认动作转发到指定目标组
+func (svc *clbSvc) CreateAwsListener(cts *rest.Contexts) (any, error) {
+	req := new(protolb.AwsListenerCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lb, err := svc.dataCli.Aws.LoadBalancer.Get(cts.Kit, req.LbID)
+	if err != nil {
+		logs.Errorf("fail to get aws load balancer(%s), err: %v, rid: %s", req.LbID, err, cts.Kit.Rid)
+		return nil, err
+	}
+	tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, req.TargetGroupID)
+	if err != nil {
+		logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", req.TargetGroupID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, lb.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpt := &typelb.AwsCreateListenerOption{
+		Region:              lb.Region,
+		CloudLoadBalancerID: lb.CloudID,
+		Protocol:            req.Protocol,
+		Port:                req.Port,
+		CloudTargetGroupID:  tg.CloudID,
+		SslPolicy:           req.SslPolicy,
+		CertCloudIDs:        req.CertCloudIDs,
+	}
+	cloudID, err := client.CreateListener(cts.Kit, createOpt)
+	if err != nil {
+		logs.Errorf("create aws listener failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.awsLBSync(cts.Kit, client, lb.AccountID, lb.Region, []string{lb.CloudID}, 0); err != nil {
+		return nil, err
+	}
+
+	return &core.CloudCreateResult{CloudID: cloudID}, nil
+}
+
+// UpdateAwsListener 更新aws监听器的协议、端口、证书及默认转发的目标组
+func (svc *clbSvc) UpdateAwsListener(cts *rest.Contexts) (any, error) {
+	lblID := cts.PathParameter("id").String()
+	if len(lblID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.AwsListenerUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbl, err := svc.dataCli.Aws.LoadBalancer.GetListener(cts.Kit, lblID)
+	if err != nil {
+		logs.Errorf("fail to get aws listener(%s), err: %v, rid: %s", lblID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	cloudTGID := ""
+	if len(req.TargetGroupID) != 0 {
+		tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, req.TargetGroupID)
+		if err != nil {
+			logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", req.TargetGroupID, err, cts.Kit.Rid)
+			return nil, err
+		}
+		cloudTGID = tg.CloudID
+	}
+
+	err = svc.awsOperateWithLB(cts.Kit, lbl.LbID, func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error {
+		updateOpt := &typelb.AwsUpdateListenerOption{
+			Region:             lb.Region,
+			CloudID:            lbl.CloudID,
+			Protocol:           req.Protocol,
+			Port:               req.Port,
+			CloudTargetGroupID: cloudTGID,
+			SslPolicy:          req.SslPolicy,
+			CertCloudIDs:       req.CertCloudIDs,
+		}
+		return client.UpdateListener(cts.Kit, updateOpt)
+	})
+	if err != nil {
+		logs.Errorf("update aws listener failed, err: %v, id: %s, rid: %s", err, lblID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteAwsListener 批量删除aws监听器
+func (svc *clbSvc) BatchDeleteAwsListener(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("vendor", enumor.Aws)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	lblResp, err := svc.dataCli.Global.LoadBalancer.ListListener(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list aws listener failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	lbCloudIDsMap := make(map[string][]string)
+	for _, one := range lblResp.Details {
+		lbCloudIDsMap[one.LbID] = append(lbCloudIDsMap[one.LbID], one.CloudID)
+	}
+
+	for lbID, cloudIDs := range lbCloudIDsMap {
+		err = svc.awsOperateWithLB(cts.Kit, lbID, func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error {
+			return client.DeleteListener(cts.Kit, &typelb.AwsDeleteOption{Region: lb.Region, CloudIDs: cloudIDs})
+		})
+		if err != nil {
+			logs.Errorf("delete aws listener failed, err: %v, lbID: %s, cloudIDs: %v, rid: %s", err, lbID,
+				cloudIDs, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateAwsUrlRule 创建aws监听器转发规则
+func (svc *clbSvc) CreateAwsUrlRule(cts *rest.Contexts) (any, error) {
+	req := new(protolb.AwsRuleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbl, err := svc.dataCli.Aws.LoadBalancer.GetListener(cts.Kit, req.ListenerID)
+	if err != nil {
+		logs.Errorf("fail to get aws listener(%s), err: %v, rid: %s", req.ListenerID, err, cts.Kit.Rid)
+		return nil, err
+	}
+	tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, req.TargetGroupID)
+	if err != nil {
+		logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", req.TargetGroupID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	var cloudID string
+	err = svc.awsOperateWithLB(cts.Kit, lbl.LbID, func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error {
+		createOpt := &typelb.AwsCreateRuleOption{
+			Region:             lb.Region,
+			CloudListenerID:    lbl.CloudID,
+			Priority:           req.Priority,
+			Domain:             req.Domain,
+			URL:                req.URL,
+			CloudTargetGroupID: tg.CloudID,
+		}
+		var err error
+		cloudID, err = client.CreateRule(cts.Kit, createOpt)
+		return err
+	})
+	if err != nil {
+		logs.Errorf("create aws rule failed, err: %v, listenerID: %s, rid: %s", err, req.ListenerID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CloudCreateResult{CloudID: cloudID}, nil
+}
+
+// UpdateAwsUrlRule 更新aws转发规则的域名、路径及转发的目标组
+func (svc *clbSvc) UpdateAwsUrlRule(cts *rest.Contexts) (any, error) {
+	ruleID := cts.PathParameter("id").String()
+	if len(ruleID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.AwsRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{Filter: tools.EqualExpression("id", ruleID), Page: core.NewDefaultBasePage()}
+	ruleResp, err := svc.dataCli.Aws.LoadBalancer.ListUrlRule(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list aws rule failed, err: %v, id: %s, rid: %s", err, ruleID, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(ruleResp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "aws rule(%s) not found", ruleID)
+	}
+	rule := ruleResp.Details[0]
+	if rule.RuleType == enumor.Layer4RuleType {
+		return nil, errf.Newf(errf.InvalidParameter, "default rule(%s) can not be updated, update listener instead",
+			ruleID)
+	}
+
+	cloudTGID := ""
+	if len(req.TargetGroupID) != 0 {
+		tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, req.TargetGroupID)
+		if err != nil {
+			logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", req.TargetGroupID, err, cts.Kit.Rid)
+			return nil, err
+		}
+		cloudTGID = tg.CloudID
+	}
+
+	err = svc.awsOperateWithLB(cts.Kit, rule.LbID, func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error {
+		updateOpt := &typelb.AwsUpdateRuleOption{
+			Region:             lb.Region,
+			CloudID:            rule.CloudID,
+			Domain:             req.Domain,
+			URL:                req.URL,
+			CloudTargetGroupID: cloudTGID,
+		}
+		return client.UpdateRule(cts.Kit, updateOpt)
+	})
+	if err != nil {
+		logs.Errorf("update aws rule failed, err: %v, id: %s, rid: %s", err, ruleID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteAwsUrlRule 批量删除aws转发规则，默认规则随监听器删除，不支持单独删除
+func (svc *clbSvc) BatchDeleteAwsUrlRule(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.IDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	ruleResp, err := svc.dataCli.Aws.LoadBalancer.ListUrlRule(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list aws rule failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	lbCloudIDsMap := make(map[string][]string)
+	for _, one := range ruleResp.Details {
+		if one.RuleType == enumor.Layer4RuleType {
+			return nil, errf.Newf(errf.InvalidParameter, "default rule(%s) can not be deleted", one.ID)
+		}
+		lbCloudIDsMap[one.LbID] = append(lbCloudIDsMap[one.LbID], one.CloudID)
+	}
+
+	for lbID, cloudIDs := range lbCloudIDsMap {
+		err = svc.awsOperateWithLB(cts.Kit, lbID, func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error {
+			return client.DeleteRule(cts.Kit, &typelb.AwsDeleteOption{Region: lb.Region, CloudIDs: cloudIDs})
+		})
+		if err != nil {
+			logs.Errorf("delete aws rule failed, err: %v, lbID: %s, cloudIDs: %v, rid: %s", err, lbID, cloudIDs,
+				cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateAwsTargetGroup 创建aws目标组，目标组为地域级资源，创建后直接写入本地
+func (svc *clbSvc) CreateAwsTargetGroup(cts *rest.Contexts) (any, error) {
+	req := new(protolb.AwsTargetGroupCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpt := &typelb.AwsCreateTargetGroupOption{
+		Region:      req.Region,
+		Name:        req.Name,
+		Protocol:    req.Protocol,
+		Port:        req.Port,
+		CloudVpcID:  req.CloudVpcID,
+		TargetType:  req.TargetType,
+		HealthCheck: req.HealthCheck,
+	}
+	cloudID, err := client.CreateTargetGroup(cts.Kit, createOpt)
+	if err != nil {
+		logs.Errorf("create aws target group failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	bizID := req.BkBizID
+	if bizID == 0 {
+		bizID = constant.UnassignedBiz
+	}
+	createReq := &dataproto.AwsTargetGroupCreateReq{
+		TargetGroups: []dataproto.TargetGroupBatchCreate[corelb.AwsTargetGroupExtension]{{
+			CloudID:         cloudID,
+			Name:            req.Name,
+			Vendor:          enumor.Aws,
+			AccountID:       req.AccountID,
+			BkBizID:         bizID,
+			Region:          req.Region,
+			Protocol:        req.Protocol,
+			Port:            req.Port,
+			CloudVpcID:      req.CloudVpcID,
+			TargetGroupType: enumor.CloudTargetGroupType,
+			Extension: &corelb.AwsTargetGroupExtension{
+				TargetType:  req.TargetType,
+				HealthCheck: req.HealthCheck,
+			},
+		}},
+	}
+	result, err := svc.dataCli.Aws.LoadBalancer.BatchCreateAwsTargetGroup(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create db aws target group failed, err: %v, cloudID: %s, rid: %s", err, cloudID, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.IDs) == 0 {
+		return nil, errf.Newf(errf.Aborted, "create db aws target group(%s) return no id", cloudID)
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateAwsTargetGroup 更新aws目标组健康检查，更新后以云上的健康检查刷新本地数据
+func (svc *clbSvc) UpdateAwsTargetGroup(cts *rest.Contexts) (any, error) {
+	tgID := cts.PathParameter("id").String()
+	if len(tgID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.AwsTargetGroupUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, tgID)
+	if err != nil {
+		logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, tg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	updateOpt := &typelb.AwsUpdateTargetGroupOption{
+		Region:      tg.Region,
+		CloudID:     tg.CloudID,
+		HealthCheck: req.HealthCheck,
+	}
+	if err = client.UpdateTargetGroup(cts.Kit, updateOpt); err != nil {
+		logs.Errorf("update aws target group failed, err: %v, id: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	listOpt := &typelb.AwsListTargetGroupOption{Region: tg.Region, CloudIDs: []string{tg.CloudID}}
+	cloudTGs, err := client.ListTargetGroup(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list aws target group failed, err: %v, opt: %+v, rid: %s", err, listOpt, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(cloudTGs) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "aws target group(%s) not found from cloud", tg.CloudID)
+	}
+
+	ext, err := types.NewJsonField(&corelb.AwsTargetGroupExtension{HealthCheck: cloudTGs[0].GetHealthCheck()})
+	if err != nil {
+		return nil, err
+	}
+	dbReq := &dataproto.TargetGroupUpdateReq{IDs: []string{tgID}, Extension: ext}
+	if err = svc.dataCli.Aws.LoadBalancer.BatchUpdateAwsTargetGroup(cts.Kit, dbReq); err != nil {
+		logs.Errorf("update db aws target group failed, err: %v, id: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteAwsTargetGroup 批量删除aws目标组
+func (svc *clbSvc) BatchDeleteAwsTargetGroup(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	for _, id := range req.IDs {
+		tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, id)
+		if err != nil {
+			logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", id, err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		client, err := svc.ad.Aws(cts.Kit, tg.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		delOpt := &typelb.AwsDeleteOption{Region: tg.Region, CloudIDs: []string{tg.CloudID}}
+		if err = client.DeleteTargetGroup(cts.Kit, delOpt); err != nil {
+			logs.Errorf("delete aws target group failed, err: %v, opt: %+v, rid: %s", err, delOpt, cts.Kit.Rid)
+			return nil, err
+		}
+
+		delReq := &core.ListReq{Filter: tools.EqualExpression("id", id), Page: core.NewDefaultBasePage()}
+		if err = svc.dataCli.Global.LoadBalancer.DeleteTargetGroup(cts.Kit, delReq); err != nil {
+			logs.Errorf("delete db aws target group failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// BatchRegisterAwsTargets 注册目标到aws目标组
+func (svc *clbSvc) BatchRegisterAwsTargets(cts *rest.Contexts) (any, error) {
+	return svc.operateAwsTargets(cts, func(kt *kit.Kit, client *aws.Aws, opt *typelb.AwsTargetsOption) error {
+		return client.RegisterTargets(kt, opt)
+	})
+}
+
+// BatchDeregisterAwsTargets 从aws目标组中解绑目标
+func (svc *clbSvc) BatchDeregisterAwsTargets(cts *rest.Contexts) (any, error) {
+	return svc.operateAwsTargets(cts, func(kt *kit.Kit, client *aws.Aws, opt *typelb.AwsTargetsOption) error {
+		return client.DeregisterTargets(kt, opt)
+	})
+}
+
+func (svc *clbSvc) operateAwsTargets(cts *rest.Contexts,
+	operate func(kt *kit.Kit, client *aws.Aws, opt *typelb.AwsTargetsOption) error) (any, error) {
+
+	tgID := cts.PathParameter("target_group_id").String()
+	if len(tgID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "target_group_id is required")
+	}
+
+	req := new(protolb.AwsBatchOperateTargetReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tg, err := svc.dataCli.Aws.LoadBalancer.GetTargetGroup(cts.Kit, tgID)
+	if err != nil {
+		logs.Errorf("fail to get aws target group(%s), err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, tg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typelb.AwsTargetsOption{Region: tg.Region, CloudTargetGroupID: tg.CloudID, Targets: req.Targets}
+	if err = operate(cts.Kit, client, opt); err != nil {
+		logs.Errorf("operate aws targets failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	syncClient := syncaws.NewClient(svc.dataCli, client)
+	if err = syncClient.TargetGroupTarget(cts.Kit, tg.AccountID, tg.Region, tg.ID, tg.CloudID); err != nil {
+		logs.Errorf("sync aws target failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// awsOperateWithLB 获取负载均衡后执行云上操作，并同步该负载均衡下的资源
+func (svc *clbSvc) awsOperateWithLB(kt *kit.Kit, lbID string,
+	operate func(client *aws.Aws, lb *corelb.AwsLoadBalancer) error) error {
+
+	lb, err := svc.dataCli.Aws.LoadBalancer.Get(kt, lbID)
+	if err != nil {
+		logs.Errorf("fail to get aws load balancer(%s), err: %v, rid: %s", lbID, err, kt.Rid)
+		return err
+	}
+
+	client, err := svc.ad.Aws(kt, lb.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if err = operate(client, lb); err != nil {
+		return err
+	}
+
+	return svc.awsLBSync(kt, client, lb.AccountID, lb.Region, []string{lb.CloudID}, 0)
+}
+
+// awsLBSync 同步aws负载均衡及其监听器、规则、目标组
+func (svc *clbSvc) awsLBSync(kt *kit.Kit, client *aws.Aws, accountID string, region string, cloudIDs []string,
+	bizID int64) error {
+
+	syncClient := syncaws.NewClient(svc.dataCli, client)
+	params := &syncaws.SyncBaseParams{
+		AccountID: accountID,
+		Region:    region,
+		CloudIDs:  cloudIDs,
+	}
+	if _, err := syncClient.LoadBalancerWithListener(kt, params, &syncaws.SyncLBOption{BkBizID: bizID}); err != nil {
+		logs.Errorf("sync aws load balancer failed, err: %v, cloudIDs: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,771 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"net/http"
+
+	synchuawei "hcm/cmd/hc-service/logics/res-sync/huawei"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/adaptor/huawei"
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	protolb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+)
+
+func (svc *clbSvc) initHuaWeiLBService(cap *capability.Capability) {
+	h := rest.NewHandler()
+
+	h.Add("CreateHuaWeiLoadBalancer", http.MethodPost, "/vendors/huawei/load_balancers/create",
+		svc.CreateHuaWeiLoadBalancer)
+	h.Add("UpdateHuaWeiLoadBalancer", http.MethodPatch, "/vendors/huawei/load_balancers/{id}",
+		svc.UpdateHuaWeiLoadBalancer)
+	h.Add("BatchDeleteHuaWeiLoadBalancer", http.MethodDelete, "/vendors/huawei/load_balancers/batch",
+		svc.BatchDeleteHuaWeiLoadBalancer)
+
+	// 监听器
+	h.Add("CreateHuaWeiListener", http.MethodPost, "/vendors/huawei/listeners/create", svc.CreateHuaWeiListener)
+	h.Add("UpdateHuaWeiListener", http.MethodPatch, "/vendors/huawei/listeners/{id}", svc.UpdateHuaWeiListener)
+	h.Add("BatchDeleteHuaWeiListener", http.MethodDelete, "/vendors/huawei/listeners/batch",
+		svc.BatchDeleteHuaWeiListener)
+
+	// 转发策略
+	h.Add("CreateHuaWeiUrlRule", http.MethodPost, "/vendors/huawei/url_rules/create", svc.CreateHuaWeiUrlRule)
+	h.Add("UpdateHuaWeiUrlRule", http.MethodPatch, "/vendors/huawei/url_rules/{id}", svc.UpdateHuaWeiUrlRule)
+	h.Add("BatchDeleteHuaWeiUrlRule", http.MethodDelete, "/vendors/huawei/url_rules/batch",
+		svc.BatchDeleteHuaWeiUrlRule)
+
+	// 后端服务器组
+	h.Add("CreateHuaWeiTargetGroup", http.MethodPost, "/vendors/huawei/target_groups/create",
+		svc.CreateHuaWeiTargetGroup)
+	h.Add("UpdateHuaWeiTargetGroup", http.MethodPatch, "/vendors/huawei/target_groups/{id}",
+		svc.UpdateHuaWeiTargetGroup)
+	h.Add("BatchDeleteHuaWeiTargetGroup", http.MethodDelete, "/vendors/huawei/target_groups/batch",
+		svc.BatchDeleteHuaWeiTargetGroup)
+	h.Add("BatchAddHuaWeiMembers", http.MethodPost,
+		"/vendors/huawei/target_groups/{target_group_id}/targets/create", svc.BatchAddHuaWeiMembers)
+	h.Add("BatchRemoveHuaWeiMembers", http.MethodDelete,
+		"/vendors/huawei/target_groups/{target_group_id}/targets/batch", svc.BatchRemoveHuaWeiMembers)
+
+	h.Load(cap.WebService)
+}
+
+// CreateHuaWeiLoadBalancer 创建华为云独享型负载均衡
+func (svc *clbSvc) CreateHuaWeiLoadBalancer(cts *rest.Contexts) (any, error) {
+	req := new(protolb.HuaWeiLoadBalancerCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpt := &typelb.HuaWeiCreateLoadBalancerOption{
+		Region:               req.Region,
+		Name:                 req.Name,
+		CloudVpcID:           req.CloudVpcID,
+		CloudVipSubnetID:     req.CloudVipSubnetID,
+		CloudElbVirsubnetIDs: req.CloudElbVirsubnetIDs,
+		AvailabilityZones:    req.AvailabilityZones,
+		L4FlavorID:           req.L4FlavorID,
+		L7FlavorID:           req.L7FlavorID,
+		CloudEipIDs:          req.CloudEipIDs,
+		DeletionProtection:   req.DeletionProtection,
+		Tags:                 req.Tags,
+	}
+	cloudID, err := client.CreateLoadBalancer(cts.Kit, createOpt)
+	if err != nil {
+		logs.Errorf("create huawei load balancer failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 通过同步写入本地数据，同步时带上业务信息
+	err = svc.huaweiLBSync(cts.Kit, client, req.AccountID, req.Region, []string{cloudID}, req.BkBizID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protolb.BatchCreateResult{SuccessCloudIDs: []string{cloudID}}, nil
+}
+
+// UpdateHuaWeiLoadBalancer 更新华为云负载均衡属性
+func (svc *clbSvc) UpdateHuaWeiLoadBalancer(cts *rest.Contexts) (any, error) {
+	lbID := cts.PathParameter("id").String()
+	if len(lbID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.HuaWeiLBUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := svc.huaweiOperateWithLB(cts.Kit, lbID, func(client *huawei.HuaWei, lb *corelb.HuaWeiLoadBalancer) error {
+		updateOpt := &typelb.HuaWeiUpdateLoadBalancerOption{
+			Region:             lb.Region,
+			CloudID:            lb.CloudID,
+			Name:               req.Name,
+			Description:        req.Memo,
+			DeletionProtection: req.DeletionProtection,
+			IpTargetEnable:     req.IpTargetEnable,
+		}
+		return client.UpdateLoadBalancer(cts.Kit, updateOpt)
+	})
+	if err != nil {
+		logs.Errorf("update huawei load balancer(%s) failed, err: %v, rid: %s", lbID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteHuaWeiLoadBalancer 批量删除华为云负载均衡
+func (svc *clbSvc) BatchDeleteHuaWeiLoadBalancer(cts *rest.Contexts) (any, error) {
+	req := new(protolb.HuaWeiBatchDeleteLoadBalancerReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Fields: []string{"cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("id", req.IDs),
+			tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleEqual("account_id", req.AccountID),
+			tools.RuleEqual("region", req.Region),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dataCli.Global.LoadBalancer.ListLoadBalancer(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list huawei load balancer failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delCloudIDs := make([]string, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		delCloudIDs = append(delCloudIDs, one.CloudID)
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	delOpt := &typelb.HuaWeiDeleteOption{Region: req.Region, CloudIDs: delCloudIDs}
+	if err = client.DeleteLoadBalancer(cts.Kit, delOpt); err != nil {
+		logs.Errorf("delete huawei load balancer failed, err: %v, opt: %+v, rid: %s", err, delOpt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	delReq := &dataproto.LoadBalancerBatchDeleteReq{Filter: listReq.Filter}
+	if err = svc.dataCli.Global.LoadBalancer.BatchDeleteLoadBalancer(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete db huawei load balancer failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreateHuaWeiListener 创建华为云监听器
+func (svc *clbSvc) CreateHuaWeiListener(cts *rest.Contexts) (any, error) {
+	req := new(protolb.HuaWeiListenerCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	var cloudPoolID *string
+	if cvt.PtrToVal(req.TargetGroupID) != "" {
+		tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, *req.TargetGroupID)
+		if err != nil {
+			logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", *req.TargetGroupID, err,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		cloudPoolID = cvt.ValToPtr(tg.CloudID)
+	}
+
+	var cloudID string
+	err := svc.huaweiOperateWithLB(cts.Kit, req.LbID, func(client *huawei.HuaWei,
+		lb *corelb.HuaWeiLoadBalancer) error {
+
+		createOpt := &typelb.HuaWeiCreateListenerOption{
+			Region:                 lb.Region,
+			CloudLoadBalancerID:    lb.CloudID,
+			Name:                   req.Name,
+			Protocol:               req.Protocol,
+			Port:                   req.Port,
+			CloudDefaultPoolID:     cloudPoolID,
+			DefaultTlsContainerRef: req.DefaultTlsContainerRef,
+		}
+		var err error
+		cloudID, err = client.CreateListener(cts.Kit, createOpt)
+		return err
+	})
+	if err != nil {
+		logs.Errorf("create huawei listener failed, err: %v, lbID: %s, rid: %s", err, req.LbID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CloudCreateResult{CloudID: cloudID}, nil
+}
+
+// UpdateHuaWeiListener 更新华为云监听器名称、默认后端服务器组及证书
+func (svc *clbSvc) UpdateHuaWeiListener(cts *rest.Contexts) (any, error) {
+	lblID := cts.PathParameter("id").String()
+	if len(lblID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.HuaWeiListenerUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbl, err := svc.dataCli.HuaWei.LoadBalancer.GetListener(cts.Kit, lblID)
+	if err != nil {
+		logs.Errorf("fail to get huawei listener(%s), err: %v, rid: %s", lblID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	var cloudPoolID *string
+	if req.TargetGroupID != nil {
+		tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, *req.TargetGroupID)
+		if err != nil {
+			logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", *req.TargetGroupID, err,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		cloudPoolID = cvt.ValToPtr(tg.CloudID)
+	}
+
+	err = svc.huaweiOperateWithLB(cts.Kit, lbl.LbID, func(client *huawei.HuaWei,
+		lb *corelb.HuaWeiLoadBalancer) error {
+
+		updateOpt := &typelb.HuaWeiUpdateListenerOption{
+			Region:                 lb.Region,
+			CloudID:                lbl.CloudID,
+			Name:                   req.Name,
+			CloudDefaultPoolID:     cloudPoolID,
+			DefaultTlsContainerRef: req.DefaultTlsContainerRef,
+		}
+		return client.UpdateListener(cts.Kit, updateOpt)
+	})
+	if err != nil {
+		logs.Errorf("update huawei listener failed, err: %v, id: %s, rid: %s", err, lblID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteHuaWeiListener 批量删除华为云监听器
+func (svc *clbSvc) BatchDeleteHuaWeiListener(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("vendor", enumor.HuaWei)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	lblResp, err := svc.dataCli.Global.LoadBalancer.ListListener(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list huawei listener failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	lbCloudIDsMap := make(map[string][]string)
+	for _, one := range lblResp.Details {
+		lbCloudIDsMap[one.LbID] = append(lbCloudIDsMap[one.LbID], one.CloudID)
+	}
+
+	for lbID, cloudIDs := range lbCloudIDsMap {
+		err = svc.huaweiOperateWithLB(cts.Kit, lbID, func(client *huawei.HuaWei,
+			lb *corelb.HuaWeiLoadBalancer) error {
+
+			return client.DeleteListener(cts.Kit, &typelb.HuaWeiDeleteOption{Region: lb.Region, CloudIDs: cloudIDs})
+		})
+		if err != nil {
+			logs.Errorf("delete huawei listener failed, err: %v, lbID: %s, cloudIDs: %v, rid: %s", err, lbID,
+				cloudIDs, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateHuaWeiUrlRule 创建华为云转发策略，转发到指定后端服务器组
+func (svc *clbSvc) CreateHuaWeiUrlRule(cts *rest.Contexts) (any, error) {
+	req := new(protolb.HuaWeiRuleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbl, err := svc.dataCli.HuaWei.LoadBalancer.GetListener(cts.Kit, req.ListenerID)
+	if err != nil {
+		logs.Errorf("fail to get huawei listener(%s), err: %v, rid: %s", req.ListenerID, err, cts.Kit.Rid)
+		return nil, err
+	}
+	tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, req.TargetGroupID)
+	if err != nil {
+		logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", req.TargetGroupID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	var cloudID string
+	err = svc.huaweiOperateWithLB(cts.Kit, lbl.LbID, func(client *huawei.HuaWei,
+		lb *corelb.HuaWeiLoadBalancer) error {
+
+		createOpt := &typelb.HuaWeiCreateL7PolicyOption{
+			Region:          lb.Region,
+			CloudListenerID: lbl.CloudID,
+			Name:            req.Name,
+			Domain:          req.Domain,
+			URL:             req.URL,
+			CloudPoolID:     tg.CloudID,
+		}
+		var err error
+		cloudID, err = client.CreateL7Policy(cts.Kit, createOpt)
+		return err
+	})
+	if err != nil {
+		logs.Errorf("create huawei l7 policy failed, err: %v, listenerID: %s, rid: %s", err, req.ListenerID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CloudCreateResult{CloudID: cloudID}, nil
+}
+
+// UpdateHuaWeiUrlRule 更新华为云转发策略
+func (svc *clbSvc) UpdateHuaWeiUrlRule(cts *rest.Contexts) (any, error) {
+	ruleID := cts.PathParameter("id").String()
+	if len(ruleID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.HuaWeiRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{Filter: tools.EqualExpression("id", ruleID), Page: core.NewDefaultBasePage()}
+	ruleResp, err := svc.dataCli.HuaWei.LoadBalancer.ListUrlRule(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list huawei rule failed, err: %v, id: %s, rid: %s", err, ruleID, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(ruleResp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "huawei rule(%s) not found", ruleID)
+	}
+	rule := ruleResp.Details[0]
+	if rule.RuleType == enumor.Layer4RuleType {
+		return nil, errf.Newf(errf.InvalidParameter, "default rule(%s) can not be updated, update listener instead",
+			ruleID)
+	}
+
+	var cloudPoolID *string
+	if req.TargetGroupID != nil {
+		tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, *req.TargetGroupID)
+		if err != nil {
+			logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", *req.TargetGroupID, err,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		cloudPoolID = cvt.ValToPtr(tg.CloudID)
+	}
+
+	err = svc.huaweiOperateWithLB(cts.Kit, rule.LbID, func(client *huawei.HuaWei,
+		lb *corelb.HuaWeiLoadBalancer) error {
+
+		updateOpt := &typelb.HuaWeiUpdateL7PolicyOption{
+			Region:      lb.Region,
+			CloudID:     rule.CloudID,
+			Name:        req.Name,
+			Domain:      req.Domain,
+			URL:         req.URL,
+			CloudPoolID: cloudPoolID,
+		}
+		return client.UpdateL7Policy(cts.Kit, updateOpt)
+	})
+	if err != nil {
+		logs.Errorf("update huawei l7 policy failed, err: %v, id: %s, rid: %s", err, ruleID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteHuaWeiUrlRule 批量删除华为云转发策略，监听器默认后端服务器组对应的四层规则不支持单独删除
+func (svc *clbSvc) BatchDeleteHuaWeiUrlRule(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.IDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	ruleResp, err := svc.dataCli.HuaWei.LoadBalancer.ListUrlRule(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list huawei rule failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	lbCloudIDsMap := make(map[string][]string)
+	for _, one := range ruleResp.Details {
+		if one.RuleType == enumor.Layer4RuleType {
+			return nil, errf.Newf(errf.InvalidParameter, "default rule(%s) can not be deleted", one.ID)
+		}
+		lbCloudIDsMap[one.LbID] = append(lbCloudIDsMap[one.LbID], one.CloudID)
+	}
+
+	for lbID, cloudIDs := range lbCloudIDsMap {
+		err = svc.huaweiOperateWithLB(cts.Kit, lbID, func(client *huawei.HuaWei,
+			lb *corelb.HuaWeiLoadBalancer) error {
+
+			return client.DeleteL7Policy(cts.Kit, &typelb.HuaWeiDeleteOption{Region: lb.Region, CloudIDs: cloudIDs})
+		})
+		if err != nil {
+			logs.Errorf("delete huawei l7 policy failed, err: %v, lbID: %s, cloudIDs: %v, rid: %s", err, lbID,
+				cloudIDs, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateHuaWeiTargetGroup 在负载均衡下创建华为云后端服务器组，创建后直接写入本地
+func (svc *clbSvc) CreateHuaWeiTargetGroup(cts *rest.Contexts) (any, error) {
+	req := new(protolb.HuaWeiTargetGroupCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lb, err := svc.dataCli.HuaWei.LoadBalancer.Get(cts.Kit, req.LbID)
+	if err != nil {
+		logs.Errorf("fail to get huawei load balancer(%s), err: %v, rid: %s", req.LbID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, lb.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpt := &typelb.HuaWeiCreatePoolOption{
+		Region:              lb.Region,
+		Name:                req.Name,
+		Protocol:            req.Protocol,
+		LbAlgorithm:         req.LbAlgorithm,
+		CloudLoadBalancerID: cvt.ValToPtr(lb.CloudID),
+		HealthCheck:         req.HealthCheck,
+	}
+	cloudID, err := client.CreatePool(cts.Kit, createOpt)
+	if err != nil {
+		logs.Errorf("create huawei pool failed, err: %v, lbID: %s, rid: %s", err, req.LbID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = cloudID
+	}
+	createReq := &dataproto.HuaWeiTargetGroupCreateReq{
+		TargetGroups: []dataproto.TargetGroupBatchCreate[corelb.HuaWeiTargetGroupExtension]{{
+			CloudID:         cloudID,
+			Name:            name,
+			Vendor:          enumor.HuaWei,
+			AccountID:       lb.AccountID,
+			BkBizID:         lb.BkBizID,
+			Region:          lb.Region,
+			Protocol:        req.Protocol,
+			Port:            req.Port,
+			VpcID:           lb.VpcID,
+			CloudVpcID:      lb.CloudVpcID,
+			TargetGroupType: enumor.CloudTargetGroupType,
+			Extension: &corelb.HuaWeiTargetGroupExtension{
+				LbAlgorithm:          cvt.ValToPtr(req.LbAlgorithm),
+				CloudLoadBalancerIDs: []string{lb.CloudID},
+			},
+		}},
+	}
+	result, err := svc.dataCli.HuaWei.LoadBalancer.BatchCreateHuaWeiTargetGroup(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create db huawei target group failed, err: %v, cloudID: %s, rid: %s", err, cloudID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.IDs) == 0 {
+		return nil, errf.Newf(errf.Aborted, "create db huawei target group(%s) return no id", cloudID)
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateHuaWeiTargetGroup 更新华为云后端服务器组名称、负载均衡算法及健康检查
+func (svc *clbSvc) UpdateHuaWeiTargetGroup(cts *rest.Contexts) (any, error) {
+	tgID := cts.PathParameter("id").String()
+	if len(tgID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protolb.HuaWeiTargetGroupUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, tgID)
+	if err != nil {
+		logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	updateOpt := &typelb.HuaWeiUpdatePoolOption{
+		Region:      tg.Region,
+		CloudID:     tg.CloudID,
+		Name:        req.Name,
+		LbAlgorithm: req.LbAlgorithm,
+		HealthCheck: req.HealthCheck,
+	}
+	if tg.Extension != nil {
+		updateOpt.CloudHealthMonitorID = tg.Extension.CloudHealthMonitorID
+	}
+	if req.HealthCheck != nil && len(cvt.PtrToVal(updateOpt.CloudHealthMonitorID)) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "target group(%s) has no health monitor", tgID)
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, tg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.UpdatePool(cts.Kit, updateOpt); err != nil {
+		logs.Errorf("update huawei pool failed, err: %v, id: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Name == nil && req.LbAlgorithm == nil {
+		return nil, nil
+	}
+	dbReq := &dataproto.TargetGroupUpdateReq{IDs: []string{tgID}, Name: cvt.PtrToVal(req.Name)}
+	if req.LbAlgorithm != nil {
+		ext, err := types.NewJsonField(&corelb.HuaWeiTargetGroupExtension{LbAlgorithm: req.LbAlgorithm})
+		if err != nil {
+			return nil, err
+		}
+		dbReq.Extension = ext
+	}
+	if err = svc.dataCli.HuaWei.LoadBalancer.BatchUpdateHuaWeiTargetGroup(cts.Kit, dbReq); err != nil {
+		logs.Errorf("update db huawei target group failed, err: %v, id: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteHuaWeiTargetGroup 批量删除华为云后端服务器组
+func (svc *clbSvc) BatchDeleteHuaWeiTargetGroup(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	for _, id := range req.IDs {
+		tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, id)
+		if err != nil {
+			logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", id, err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		client, err := svc.ad.HuaWei(cts.Kit, tg.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		delOpt := &typelb.HuaWeiDeleteOption{Region: tg.Region, CloudIDs: []string{tg.CloudID}}
+		if err = client.DeletePool(cts.Kit, delOpt); err != nil {
+			logs.Errorf("delete huawei pool failed, err: %v, opt: %+v, rid: %s", err, delOpt, cts.Kit.Rid)
+			return nil, err
+		}
+
+		delReq := &core.ListReq{Filter: tools.EqualExpression("id", id), Page: core.NewDefaultBasePage()}
+		if err = svc.dataCli.Global.LoadBalancer.DeleteTargetGroup(cts.Kit, delReq); err != nil {
+			logs.Errorf("delete db huawei target group failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// BatchAddHuaWeiMembers 添加后端服务器到华为云后端服务器组
+func (svc *clbSvc) BatchAddHuaWeiMembers(cts *rest.Contexts) (any, error) {
+	return svc.operateHuaWeiMembers(cts, func(kt *kit.Kit, client *huawei.HuaWei,
+		opt *typelb.HuaWeiMembersOption) error {
+
+		return client.BatchCreateMember(kt, opt)
+	})
+}
+
+// BatchRemoveHuaWeiMembers 从华为云后端服务器组移除后端服务器
+func (svc *clbSvc) BatchRemoveHuaWeiMembers(cts *rest.Contexts) (any, error) {
+	return svc.operateHuaWeiMembers(cts, func(kt *kit.Kit, client *huawei.HuaWei,
+		opt *typelb.HuaWeiMembersOption) error {
+
+		return client.BatchDeleteMember(kt, opt)
+	})
+}
+
+func (svc *clbSvc) operateHuaWeiMembers(cts *rest.Contexts,
+	operate func(kt *kit.Kit, client *huawei.HuaWei, opt *typelb.HuaWeiMembersOption) error) (any, error) {
+
+	tgID := cts.PathParameter("target_group_id").String()
+	if len(tgID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "target_group_id is required")
+	}
+
+	req := new(protolb.HuaWeiBatchOperateMemberReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tg, err := svc.dataCli.HuaWei.LoadBalancer.GetTargetGroup(cts.Kit, tgID)
+	if err != nil {
+		logs.Errorf("fail to get huawei target group(%s), err: %v, rid: %s", tgID, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, tg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typelb.HuaWeiMembersOption{Region: tg.Region, CloudPoolID: tg.CloudID, Members: req.Members}
+	if err = operate(cts.Kit, client, opt); err != nil {
+		logs.Errorf("operate huawei members failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	syncClient := synchuawei.NewClient(svc.dataCli, client)
+	if err = syncClient.TargetGroupTarget(cts.Kit, tg.AccountID, tg.Region, tg.ID, tg.CloudID); err != nil {
+		logs.Errorf("sync huawei member failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// huaweiOperateWithLB 获取负载均衡后执行云上操作，并同步该负载均衡下的资源
+func (svc *clbSvc) huaweiOperateWithLB(kt *kit.Kit, lbID string,
+	operate func(client *huawei.HuaWei, lb *corelb.HuaWeiLoadBalancer) error) error {
+
+	lb, err := svc.dataCli.HuaWei.LoadBalancer.Get(kt, lbID)
+	if err != nil {
+		logs.Errorf("fail to get huawei load balancer(%s), err: %v, rid: %s", lbID, err, kt.Rid)
+		return err
+	}
+
+	client, err := svc.ad.HuaWei(kt, lb.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if err = operate(client, lb); err != nil {
+		return err
+	}
+
+	return svc.huaweiLBSync(kt, client, lb.AccountID, lb.Region, []string{lb.CloudID}, 0)
+}
+
+// huaweiLBSync 同步华为云负载均衡及其监听器、转发策略、后端服务器组
+func (svc *clbSvc) huaweiLBSync(kt *kit.Kit, client *huawei.HuaWei, accountID string, region string,
+	cloudIDs []string, bizID int64) error {
+
+	syncClient := synchuawei.NewClient(svc.dataCli, client)
+	params := &synchuawei.SyncBaseParams{
+		AccountID: accountID,
+		Region:    region,
+		CloudIDs:  cloudIDs,
+	}
+	_, err := syncClient.LoadBalancerWithListener(kt, params, &synchuawei.SyncLBOption{BkBizID: bizID})
+	if err != nil {
+		logs.Errorf("sync huawei load balancer failed, err: %v, cloudIDs: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return err
+	}
+	return nil
+}
@@ -34,6 +34,8 @@ func InitLoadBalancerService(cap *capability.Capability) {
 	}
 
 	svc.initTCloudClbService(cap)
+	svc.initAwsLBService(cap)
+	svc.initHuaWeiLBService(cap)
 }
 
 type clbSvc struct {
@@ -0,0 +1,219 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag 资源标签的云上写操作
+package restag
+
+import (
+	"fmt"
+	"net/http"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	adrestag "hcm/pkg/adaptor/types/resource-tag"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/resource-tag"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitResourceTagService initial the resource tag service
+func InitResourceTagService(cap *capability.Capability) {
+	svc := &resTagSvc{
+		adaptor: cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchUpsertResourceTag", http.MethodPost, "/vendors/{vendor}/resource_tags/batch/upsert",
+		svc.BatchUpsertResourceTag)
+	h.Add("BatchDeleteResourceTag", http.MethodDelete, "/vendors/{vendor}/resource_tags/batch",
+		svc.BatchDeleteResourceTag)
+
+	h.Load(cap.WebService)
+}
+
+type resTagSvc struct {
+	adaptor *cloudclient.CloudAdaptorClient
+	dataCli *dataclient.Client
+}
+
+// tagger 支持资源标签写操作的云厂商客户端
+type tagger interface {
+	TagResources(kt *kit.Kit, opt *adrestag.TagResOption) error
+	UnTagResources(kt *kit.Kit, opt *adrestag.UnTagResOption) error
+}
+
+// BatchUpsertResourceTag 为资源批量添加或更新标签，先写云上再写db
+func (svc *resTagSvc) BatchUpsertResourceTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.BatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cli, uin, err := svc.getTagger(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudIDs := make([]string, 0, len(req.Resources))
+	items := make([]protocloud.ResourceTagUpsertItem, 0, len(req.Resources))
+	for _, one := range req.Resources {
+		cloudIDs = append(cloudIDs, one.CloudID)
+		items = append(items, protocloud.ResourceTagUpsertItem{ResID: one.ID, CloudResID: one.CloudID, Tags: req.Tags})
+	}
+
+	opt := &adrestag.TagResOption{
+		Region:   req.Region,
+		ResType:  req.ResType,
+		CloudIDs: cloudIDs,
+		Tags:     req.Tags,
+		OwnerUin: uin,
+	}
+	if err = cli.TagResources(cts.Kit, opt); err != nil {
+		logs.Errorf("[%s] tag resources failed, err: %v, account: %s, rid: %s", vendor, err, req.AccountID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	upsertReq := &protocloud.ResourceTagBatchUpsertReq{
+		Vendor:    vendor,
+		AccountID: req.AccountID,
+		ResType:   req.ResType,
+		Resources: items,
+	}
+	if err = svc.dataCli.Global.ResourceTag.BatchUpsert(cts.Kit, upsertReq); err != nil {
+		logs.Errorf("upsert resource tags to db failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteResourceTag 批量删除资源的标签，先删除云上再删除db
+func (svc *resTagSvc) BatchDeleteResourceTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cli, uin, err := svc.getTagger(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(req.Resources))
+	cloudIDs := make([]string, 0, len(req.Resources))
+	for _, one := range req.Resources {
+		ids = append(ids, one.ID)
+		cloudIDs = append(cloudIDs, one.CloudID)
+	}
+
+	opt := &adrestag.UnTagResOption{
+		Region:   req.Region,
+		ResType:  req.ResType,
+		CloudIDs: cloudIDs,
+		TagKeys:  req.TagKeys,
+		OwnerUin: uin,
+	}
+	if err = cli.UnTagResources(cts.Kit, opt); err != nil {
+		logs.Errorf("[%s] untag resources failed, err: %v, account: %s, rid: %s", vendor, err, req.AccountID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", req.ResType),
+			tools.RuleIn("res_id", ids),
+			tools.RuleIn("key", req.TagKeys),
+		),
+	}
+	if err = svc.dataCli.Global.ResourceTag.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete resource tags from db failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// getTagger 返回云厂商客户端，腾讯云同时返回拼接资源六段式所需的主账号ID
+func (svc *resTagSvc) getTagger(kt *kit.Kit, vendor enumor.Vendor, accountID string) (tagger, string, error) {
+	switch vendor {
+	case enumor.TCloud:
+		account, err := svc.dataCli.TCloud.Account.Get(kt.Ctx, kt.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get tcloud account failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+			return nil, "", err
+		}
+
+		cli, err := svc.adaptor.TCloud(kt, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		return cli, account.Extension.CloudMainAccountID, nil
+
+	case enumor.Aws:
+		cli, err := svc.adaptor.Aws(kt, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		return cli, "", nil
+
+	case enumor.HuaWei:
+		cli, err := svc.adaptor.HuaWei(kt, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		return cli, "", nil
+
+	case enumor.Gcp:
+		cli, err := svc.adaptor.Gcp(kt, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		return cli, "", nil
+
+	case enumor.Azure:
+		cli, err := svc.adaptor.Azure(kt, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		return cli, "", nil
+
+	default:
+		return nil, "", errf.NewFromErr(errf.InvalidParameter,
+			fmt.Errorf("%s does not support write back of resource tags", vendor))
+	}
+}
@@ -38,17 +38,20 @@ import (
 	"hcm/cmd/hc-service/service/cert"
 	"hcm/cmd/hc-service/service/cvm"
 	"hcm/cmd/hc-service/service/disk"
+	disksnapshot "hcm/cmd/hc-service/service/disk-snapshot"
 	"hcm/cmd/hc-service/service/eip"
 	"hcm/cmd/hc-service/service/firewall"
 	"hcm/cmd/hc-service/service/image"
 	instancetype "hcm/cmd/hc-service/service/instance-type"
 	loadbalancer "hcm/cmd/hc-service/service/load-balancer"
 	mainaccount "hcm/cmd/hc-service/service/main-account"
+	restag "hcm/cmd/hc-service/service/resource-tag"
 	routetable "hcm/cmd/hc-service/service/route-table"
 	securitygroup "hcm/cmd/hc-service/service/security-group"
 	"hcm/cmd/hc-service/service/subnet"
 	"hcm/cmd/hc-service/service/sync"
 	"hcm/cmd/hc-service/service/vpc"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/errf"
@@ -61,6 +64,7 @@ import (
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the hc service's work
@@ -83,6 +87,10 @@ func NewService(dis serviced.Discover) (*Service, error) {
 	tcloudLblSyncConcurrency := int(cc.HCService().SyncConfig.TCloudLoadBalancerListenerSyncConcurrency)
 	logs.Infof("tcloud loadbalancer listener sync concurrency: %d", tcloudLblSyncConcurrency)
 
+	if err = initCloudRateLimit(); err != nil {
+		return nil, fmt.Errorf("init cloud api rate limit failed, err: %v", err)
+	}
+
 	svr := &Service{
 		clientSet:    cliSet,
 		cloudAdaptor: cloudAdaptor,
@@ -91,6 +99,64 @@ func NewService(dis serviced.Discover) (*Service, error) {
 	return svr, nil
 }
 
+// initCloudRateLimit 根据配置初始化调用云API的限流器
+func initCloudRateLimit() error {
+	cfg := cc.HCService().CloudRateLimit
+	if !cfg.Enable {
+		logs.Infof("cloud api rate limit is disabled")
+		return nil
+	}
+
+	opt := &ratelimit.Option{
+		Default: ratelimit.Quota{QPS: cfg.QPS, Burst: cfg.Burst},
+		Rules:   make([]ratelimit.Rule, 0, len(cfg.Rules)),
+		AIMD: ratelimit.AIMD{
+			IncreaseStep:   cfg.IncreaseStep,
+			DecreaseFactor: cfg.DecreaseFactor,
+			MinQPS:         cfg.MinQPS,
+		},
+		MaxWait:  time.Duration(cfg.MaxWaitSec) * time.Second,
+		Prefetch: cfg.Prefetch,
+	}
+	for _, rule := range cfg.Rules {
+		opt.Rules = append(opt.Rules, ratelimit.Rule{
+			Vendor: rule.Vendor,
+			Region: rule.Region,
+			Action: rule.Action,
+			Quota:  ratelimit.Quota{QPS: rule.QPS, Burst: rule.Burst},
+		})
+	}
+
+	var store ratelimit.Store
+	switch cfg.Store {
+	case cc.CloudRateLimitLocalStore:
+		store = ratelimit.NewMemoryStore()
+	case cc.CloudRateLimitEtcdStore:
+		etcdCfg, err := cc.HCService().Service.Etcd.ToConfig()
+		if err != nil {
+			return err
+		}
+		cli, err := etcd3.New(etcdCfg)
+		if err != nil {
+			return fmt.Errorf("new etcd client for cloud api rate limit failed, err: %v", err)
+		}
+		store, err = ratelimit.NewEtcdStore(&ratelimit.EtcdOption{Client: cli, KeyPrefix: cfg.KeyPrefix})
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported cloud api rate limit store: %s", cfg.Store)
+	}
+
+	if err := ratelimit.Init(opt, store); err != nil {
+		return err
+	}
+
+	logs.Infof("cloud api rate limit is enabled, store: %s, qps: %v, burst: %d, prefetch: %d, rules: %d", cfg.Store,
+		cfg.QPS, cfg.Burst, cfg.Prefetch, len(cfg.Rules))
+	return nil
+}
+
 // ListenAndServeRest listen and serve the restful server
 func (s *Service) ListenAndServeRest() error {
 	root := http.NewServeMux()
@@ -166,6 +232,7 @@ func (s *Service) apiSet() *restful.Container {
 	vpc.InitVpcService(c)
 	subnet.InitSubnetService(c)
 	disk.InitDiskService(c)
+	disksnapshot.InitDiskSnapshotService(c)
 	cvm.InitCvmService(c)
 	routetable.InitRouteTableService(c)
 	eip.InitEipService(c)
@@ -178,6 +245,7 @@ func (s *Service) apiSet() *restful.Container {
 	bwpkg.InitBwPkgService(c)
 	mainaccount.InitService(c)
 	image.InitImageService(c)
+	restag.InitResourceTagService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
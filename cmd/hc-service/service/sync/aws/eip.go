@@ -22,6 +22,8 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/api/hc-service/sync"
@@ -35,7 +37,7 @@ import (
 
 // SyncEip ....
 func (svc *service) SyncEip(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &eipHandler{cli: svc.syncCli})
+	return handler.ResourceSyncByPlan(cts, &eipHandler{cli: svc.syncCli})
 }
 
 // eipHandler eip sync handler.
@@ -49,7 +51,7 @@ type eipHandler struct {
 	eipList [][]*typeseip.AwsEip
 }
 
-var _ handler.Handler = new(eipHandler)
+var _ handler.PlanHandler = new(eipHandler)
 
 // Prepare ...
 func (hd *eipHandler) Prepare(cts *rest.Contexts) error {
@@ -97,30 +99,10 @@ func (hd *eipHandler) Next(kt *kit.Kit) ([]string, error) {
 	return cloudIDs, nil
 }
 
-// Sync ...
-func (hd *eipHandler) Sync(kt *kit.Kit, cloudIDs []string) error {
-	params := &aws.SyncBaseParams{
-		AccountID: hd.request.AccountID,
-		Region:    hd.request.Region,
-		CloudIDs:  cloudIDs,
-	}
-	if _, err := hd.syncCli.Eip(kt, params, new(aws.SyncEipOption)); err != nil {
-		logs.Errorf("sync aws eip failed, err: %v, opt: %v, rid: %s", err, params, kt.Rid)
-		return err
-	}
-
-	return nil
-}
-
-// RemoveDeleteFromCloud ...
-func (hd *eipHandler) RemoveDeleteFromCloud(kt *kit.Kit) error {
-	if err := hd.syncCli.RemoveEipDeleteFromCloud(kt, hd.request.AccountID, hd.request.Region); err != nil {
-		logs.Errorf("remove eip delete from cloud failed, err: %v, accountID: %s, region: %s, rid: %s", err,
-			hd.request.AccountID, hd.request.Region, kt.Rid)
-		return err
-	}
-
-	return nil
+// Syncer ...
+func (hd *eipHandler) Syncer() common.PlanSyncer {
+	return hd.syncCli.EipSyncer(hd.request.AccountID, hd.request.Region, hd.Next,
+		logicsync.NewOption(hd.request.PlanOption))
 }
 
 // Name ...
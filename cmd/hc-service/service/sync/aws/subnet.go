@@ -22,6 +22,8 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/hc-service/sync"
@@ -35,7 +37,7 @@ import (
 
 // SyncSubnet ....
 func (svc *service) SyncSubnet(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &subnetHandler{cli: svc.syncCli})
+	return handler.ResourceSyncByPlan(cts, &subnetHandler{cli: svc.syncCli})
 }
 
 // subnetHandler subnet sync handler.
@@ -48,7 +50,7 @@ type subnetHandler struct {
 	nextToken *string
 }
 
-var _ handler.Handler = new(subnetHandler)
+var _ handler.PlanHandler = new(subnetHandler)
 
 // Prepare ...
 func (hd *subnetHandler) Prepare(cts *rest.Contexts) error {
@@ -92,30 +94,10 @@ func (hd *subnetHandler) Next(kt *kit.Kit) ([]string, error) {
 	return cloudIDs, nil
 }
 
-// Sync ...
-func (hd *subnetHandler) Sync(kt *kit.Kit, cloudIDs []string) error {
-	params := &aws.SyncBaseParams{
-		AccountID: hd.request.AccountID,
-		Region:    hd.request.Region,
-		CloudIDs:  cloudIDs,
-	}
-	if _, err := hd.syncCli.Subnet(kt, params, new(aws.SyncSubnetOption)); err != nil {
-		logs.Errorf("sync aws subnet failed, err: %v, opt: %v, rid: %s", err, params, kt.Rid)
-		return err
-	}
-
-	return nil
-}
-
-// RemoveDeleteFromCloud ...
-func (hd *subnetHandler) RemoveDeleteFromCloud(kt *kit.Kit) error {
-	if err := hd.syncCli.RemoveSubnetDeleteFromCloud(kt, hd.request.AccountID, hd.request.Region); err != nil {
-		logs.Errorf("remove subnet delete from cloud failed, err: %v, accountID: %s, region: %s, rid: %s", err,
-			hd.request.AccountID, hd.request.Region, kt.Rid)
-		return err
-	}
-
-	return nil
+// Syncer ...
+func (hd *subnetHandler) Syncer() common.PlanSyncer {
+	return hd.syncCli.SubnetSyncer(hd.request.AccountID, hd.request.Region, hd.Next,
+		logicsync.NewOption(hd.request.PlanOption))
 }
 
 // Name ...
@@ -22,6 +22,8 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/hc-service/sync"
@@ -35,7 +37,7 @@ import (
 
 // SyncVpc ....
 func (svc *service) SyncVpc(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &vpcHandler{cli: svc.syncCli})
+	return handler.ResourceSyncByPlan(cts, &vpcHandler{cli: svc.syncCli})
 }
 
 // vpcHandler vpc sync handler.
@@ -48,7 +50,7 @@ type vpcHandler struct {
 	nextToken *string
 }
 
-var _ handler.Handler = new(vpcHandler)
+var _ handler.PlanHandler = new(vpcHandler)
 
 // Prepare ...
 func (hd *vpcHandler) Prepare(cts *rest.Contexts) error {
@@ -92,30 +94,10 @@ func (hd *vpcHandler) Next(kt *kit.Kit) ([]string, error) {
 	return cloudIDs, nil
 }
 
-// Sync ...
-func (hd *vpcHandler) Sync(kt *kit.Kit, cloudIDs []string) error {
-	params := &aws.SyncBaseParams{
-		AccountID: hd.request.AccountID,
-		Region:    hd.request.Region,
-		CloudIDs:  cloudIDs,
-	}
-	if _, err := hd.syncCli.Vpc(kt, params, new(aws.SyncVpcOption)); err != nil {
-		logs.Errorf("sync aws vpc failed, err: %v, opt: %v, rid: %s", err, params, kt.Rid)
-		return err
-	}
-
-	return nil
-}
-
-// RemoveDeleteFromCloud ...
-func (hd *vpcHandler) RemoveDeleteFromCloud(kt *kit.Kit) error {
-	if err := hd.syncCli.RemoveVpcDeleteFromCloud(kt, hd.request.AccountID, hd.request.Region); err != nil {
-		logs.Errorf("remove vpc delete from cloud failed, err: %v, accountID: %s, region: %s, rid: %s", err,
-			hd.request.AccountID, hd.request.Region, kt.Rid)
-		return err
-	}
-
-	return nil
+// Syncer ...
+func (hd *vpcHandler) Syncer() common.PlanSyncer {
+	return hd.syncCli.VpcSyncer(hd.request.AccountID, hd.request.Region, hd.Next,
+		logicsync.NewOption(hd.request.PlanOption))
 }
 
 // Name ...
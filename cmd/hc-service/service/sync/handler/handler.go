@@ -27,11 +27,14 @@ import (
 	"time"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
 	"hcm/pkg/tools/slice"
 )
 
@@ -88,6 +91,64 @@ func ResourceSync(cts *rest.Contexts, handler Handler) error {
 	return nil
 }
 
+// PlanHandler 基于通用同步器的全量同步处理器，支持 dry-run 及删除比例阈值。
+type PlanHandler interface {
+	// Prepare 解析请求体，构建同步所需客户端。
+	Prepare(cts *rest.Contexts) error
+	// Syncer 构建通用同步器
+	Syncer() common.PlanSyncer
+
+	Name() enumor.CloudResourceType
+}
+
+// ResourceSyncByPlan 基于通用同步器的资源同步流程，dry-run 模式下返回变更计划，否则返回nil。
+func ResourceSyncByPlan(cts *rest.Contexts, handler PlanHandler) (*logicsync.Plan, error) {
+	kt := cts.Kit
+
+	// 解析请求参数到handler实现中，构建同步需要的客户端
+	if err := handler.Prepare(cts); err != nil {
+		logs.Errorf("%s sync handler to prepare failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
+		return nil, err
+	}
+
+	result, err := handler.Syncer().AllPages(kt)
+	if err != nil {
+		logs.Errorf("%s sync handler to sync all pages failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
+		return nil, err
+	}
+
+	return result.Plan, nil
+}
+
+// planOptionField 同步计划选项在请求体中的字段名
+const planOptionField = "plan_option"
+
+// NotSupportPlan 包装未基于通用同步器实现的同步接口，请求携带同步计划选项时直接拒绝，
+// 避免调用方请求 dry-run 却执行了会删除数据的真实同步。
+func NotSupportPlan(fn func(cts *rest.Contexts) (interface{}, error)) func(cts *rest.Contexts) (interface{},
+	error) {
+
+	return func(cts *rest.Contexts) (interface{}, error) {
+		body, err := cts.RequestBody()
+		if err != nil {
+			return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+		}
+
+		if len(body) != 0 {
+			fields := make(map[string]interface{})
+			if err = json.Unmarshal(body, &fields); err != nil {
+				return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+			}
+
+			if val, exist := fields[planOptionField]; exist && val != nil {
+				return nil, errf.Newf(errf.InvalidParameter, "%s not supported by this sync api", planOptionField)
+			}
+		}
+
+		return fn(cts)
+	}
+}
+
 // HandlerV2 实验性并发同步框架
 type HandlerV2[T common.CloudResType] interface {
 
@@ -0,0 +1,53 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotSupportPlan(t *testing.T) {
+	called := false
+	fn := NotSupportPlan(func(cts *rest.Contexts) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	newCts := func(body string) *rest.Contexts {
+		req := httptest.NewRequest("POST", "/sync", strings.NewReader(body))
+		return &rest.Contexts{Kit: kit.New(), Request: restful.NewRequest(req)}
+	}
+
+	_, err := fn(newCts(`{"account_id":"1","region":"ap-guangzhou","plan_option":{"dry_run":true}}`))
+	assert.Error(t, err)
+	assert.False(t, called)
+
+	_, err = fn(newCts(`{"account_id":"1","region":"ap-guangzhou","plan_option":null}`))
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
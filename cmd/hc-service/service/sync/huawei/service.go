@@ -23,6 +23,7 @@ import (
 	"hcm/cmd/hc-service/logics/cloud-adaptor"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/rest"
@@ -40,18 +41,20 @@ func InitService(cap *capability.Capability) {
 	h := rest.NewHandler()
 	h.Path("/vendors/huawei")
 
-	h.Add("SyncVpc", "POST", "/vpcs/sync", v.SyncVpc)
-	h.Add("SyncSubnet", "POST", "/subnets/sync", v.SyncSubnet)
-	h.Add("SyncDisk", "POST", "/disks/sync", v.SyncDisk)
-	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync", v.SyncSecurityGroup)
-	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", v.SyncCvmWithRelRes)
-	h.Add("SyncEip", "POST", "/eips/sync", v.SyncEip)
-	h.Add("SyncRoute", "POST", "/route_tables/sync", v.SyncRouteTable)
-	h.Add("SyncZone", "POST", "/zones/sync", v.SyncZone)
-	h.Add("SyncRegion", "POST", "/regions/sync", v.SyncRegion)
-	h.Add("SyncImage", "POST", "/images/sync", v.SyncImage)
-	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", v.SyncSubAccount)
+	h.Add("SyncVpc", "POST", "/vpcs/sync", handler.NotSupportPlan(v.SyncVpc))
+	h.Add("SyncSubnet", "POST", "/subnets/sync", handler.NotSupportPlan(v.SyncSubnet))
+	h.Add("SyncDisk", "POST", "/disks/sync", handler.NotSupportPlan(v.SyncDisk))
+	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync", handler.NotSupportPlan(v.SyncSecurityGroup))
+	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", handler.NotSupportPlan(v.SyncCvmWithRelRes))
+	h.Add("SyncEip", "POST", "/eips/sync", handler.NotSupportPlan(v.SyncEip))
+	h.Add("SyncRoute", "POST", "/route_tables/sync", handler.NotSupportPlan(v.SyncRouteTable))
+	h.Add("SyncZone", "POST", "/zones/sync", handler.NotSupportPlan(v.SyncZone))
+	h.Add("SyncRegion", "POST", "/regions/sync", handler.NotSupportPlan(v.SyncRegion))
+	h.Add("SyncImage", "POST", "/images/sync", handler.NotSupportPlan(v.SyncImage))
+	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", handler.NotSupportPlan(v.SyncSubAccount))
+	h.Add("SyncLoadBalancer", "POST", "/load_balancers/sync", handler.NotSupportPlan(v.SyncLoadBalancer))
 
+	h.Add("SyncByCloudEvent", "POST", "/cloud_events/sync", handler.NotSupportPlan(v.SyncByCloudEvent))
 	h.Load(cap.WebService)
 }
 
@@ -21,7 +21,9 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/hc-service/sync"
@@ -34,7 +36,7 @@ import (
 
 // SyncDisk ....
 func (svc *service) SyncDisk(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &diskHandler{cli: svc.syncCli})
+	return handler.ResourceSyncByPlan(cts, &diskHandler{cli: svc.syncCli})
 }
 
 // diskHandler disk sync handler.
@@ -47,7 +49,7 @@ type diskHandler struct {
 	offset  uint64
 }
 
-var _ handler.Handler = new(diskHandler)
+var _ handler.PlanHandler = new(diskHandler)
 
 // Prepare ...
 func (hd *diskHandler) Prepare(cts *rest.Contexts) error {
@@ -91,30 +93,10 @@ func (hd *diskHandler) Next(kt *kit.Kit) ([]string, error) {
 	return cloudIDs, nil
 }
 
-// Sync ...
-func (hd *diskHandler) Sync(kt *kit.Kit, cloudIDs []string) error {
-	params := &tcloud.SyncBaseParams{
-		AccountID: hd.request.AccountID,
-		Region:    hd.request.Region,
-		CloudIDs:  cloudIDs,
-	}
-	if _, err := hd.syncCli.Disk(kt, params, new(tcloud.SyncDiskOption)); err != nil {
-		logs.Errorf("sync tcloud disk failed, err: %v, opt: %v, rid: %s", err, params, kt.Rid)
-		return err
-	}
-
-	return nil
-}
-
-// RemoveDeleteFromCloud ...
-func (hd *diskHandler) RemoveDeleteFromCloud(kt *kit.Kit) error {
-	if err := hd.syncCli.RemoveDiskDeleteFromCloud(kt, hd.request.AccountID, hd.request.Region); err != nil {
-		logs.Errorf("remove disk delete from cloud failed, err: %v, accountID: %s, region: %s, rid: %s", err,
-			hd.request.AccountID, hd.request.Region, kt.Rid)
-		return err
-	}
-
-	return nil
+// Syncer ...
+func (hd *diskHandler) Syncer() common.PlanSyncer {
+	return hd.syncCli.DiskSyncer(hd.request.AccountID, hd.request.Region, hd.Next,
+		logicsync.NewOption(hd.request.PlanOption))
 }
 
 // Name ...
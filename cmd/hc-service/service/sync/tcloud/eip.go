@@ -21,7 +21,9 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
+	logicsync "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	typeseip "hcm/pkg/adaptor/types/eip"
@@ -35,7 +37,7 @@ import (
 
 // SyncEip ....
 func (svc *service) SyncEip(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &eipHandler{cli: svc.syncCli})
+	return handler.ResourceSyncByPlan(cts, &eipHandler{cli: svc.syncCli})
 }
 
 // eipHandler eip sync handler.
@@ -48,7 +50,7 @@ type eipHandler struct {
 	offset  uint64
 }
 
-var _ handler.Handler = new(eipHandler)
+var _ handler.PlanHandler = new(eipHandler)
 
 // Prepare ...
 func (hd *eipHandler) Prepare(cts *rest.Contexts) error {
@@ -91,30 +93,10 @@ func (hd *eipHandler) Next(kt *kit.Kit) ([]string, error) {
 	return cloudIDs, nil
 }
 
-// Sync ...
-func (hd *eipHandler) Sync(kt *kit.Kit, cloudIDs []string) error {
-	params := &tcloud.SyncBaseParams{
-		AccountID: hd.request.AccountID,
-		Region:    hd.request.Region,
-		CloudIDs:  cloudIDs,
-	}
-	if _, err := hd.syncCli.Eip(kt, params, new(tcloud.SyncEipOption)); err != nil {
-		logs.Errorf("sync tcloud eip failed, err: %v, opt: %v, rid: %s", err, params, kt.Rid)
-		return err
-	}
-
-	return nil
-}
-
-// RemoveDeleteFromCloud ...
-func (hd *eipHandler) RemoveDeleteFromCloud(kt *kit.Kit) error {
-	if err := hd.syncCli.RemoveEipDeleteFromCloud(kt, hd.request.AccountID, hd.request.Region); err != nil {
-		logs.Errorf("remove eip delete from cloud failed, err: %v, accountID: %s, region: %s, rid: %s", err,
-			hd.request.AccountID, hd.request.Region, kt.Rid)
-		return err
-	}
-
-	return nil
+// Syncer ...
+func (hd *eipHandler) Syncer() common.PlanSyncer {
+	return hd.syncCli.EipSyncer(hd.request.AccountID, hd.request.Region, hd.Next,
+		logicsync.NewOption(hd.request.PlanOption))
 }
 
 // Name ...
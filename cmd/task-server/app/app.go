@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/runtime/ctl"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 const shutdownWaitTimeSec = 60
@@ -73,6 +74,11 @@ func (ds *taskServer) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.TaskServerName), cc.TaskServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.TaskServer().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -80,7 +86,7 @@ func (ds *taskServer) prepare(opt *options.Option) error {
 	// init service discovery.
 	svcOpt := serviced.NewServiceOption(cc.TaskServerName, cc.TaskServer().Network)
 	discOpt := serviced.DiscoveryOption{
-		Services: []cc.Name{cc.DataServiceName, cc.HCServiceName},
+		Services: []cc.Name{cc.DataServiceName, cc.HCServiceName, cc.CloudServerName},
 	}
 	sd, err := serviced.NewServiceD(cc.TaskServer().Service, svcOpt, discOpt)
 	if err != nil {
@@ -115,6 +121,8 @@ func (ds *taskServer) register() error {
 
 // finalizer ...
 func (ds *taskServer) finalizer() {
+	defer tracing.Shutdown()
+
 	if err := ds.sd.Deregister(); err != nil {
 		logs.Errorf("process service shutdown, but deregister failed, err: %v", err)
 		return
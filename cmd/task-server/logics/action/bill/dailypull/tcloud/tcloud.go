@@ -0,0 +1,195 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tcloud daily puller
+package tcloud
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/cmd/task-server/logics/action/bill/dailypull/registry"
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/adaptor/types/core"
+	dsbill "hcm/pkg/api/data-service/bill"
+	hcbill "hcm/pkg/api/hc-service/bill"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+const (
+	tcloudMaxBill = uint64(core.TCloudQueryLimit)
+	// tcloudGlobalRegion 部分全局产品的账单没有地域信息
+	tcloudGlobalRegion = "global"
+)
+
+func init() {
+	registry.PullerRegistry[enumor.TCloud] = &TCloudPuller{}
+}
+
+// TCloudPuller tcloud puller
+type TCloudPuller struct{}
+
+// Pull pull tcloud data
+func (tp *TCloudPuller) Pull(kt run.ExecuteKit, opt *registry.PullDailyBillOption) (*registry.PullerResult, error) {
+	offset := uint64(0)
+	count := int64(0)
+	cost := decimal.NewFromInt(0)
+	var pageCtx *string
+	for {
+		total, itemLen, itemCost, nextCtx, err := tp.doPull(kt, opt, offset, pageCtx)
+		if err != nil {
+			return nil, err
+		}
+		cost = cost.Add(itemCost)
+		count += int64(itemLen)
+		logs.Infof("get raw bill item %d, offset: %d / total %d of puller %+v", itemLen, offset, total, opt)
+		// 云上返回的是一级账号下的全部账单，按照云上总数翻页，而不是过滤后的条目数
+		offset += tcloudMaxBill
+		if offset >= total {
+			break
+		}
+		pageCtx = nextCtx
+	}
+	return &registry.PullerResult{
+		Count:    count,
+		Currency: enumor.CurrencyCNY,
+		Cost:     cost,
+	}, nil
+}
+
+func (tp *TCloudPuller) doPull(kt run.ExecuteKit, opt *registry.PullDailyBillOption, offset uint64,
+	pageCtx *string) (total uint64, itemLen int, cost decimal.Decimal, nextCtx *string, err error) {
+
+	hcCli := actcli.GetHCService()
+	billDate := fmt.Sprintf("%d-%02d-%02d", opt.BillYear, opt.BillMonth, opt.BillDay)
+	req := &hcbill.TCloudRootBillListReq{
+		RootAccountID:      opt.RootAccountID,
+		MainAccountCloudID: opt.MainAccountCloudID,
+		BeginDate:          billDate + " 00:00:00",
+		EndDate:            billDate + " 23:59:59",
+		Page: &core.TCloudPage{
+			Offset: offset,
+			Limit:  tcloudMaxBill,
+		},
+		Context: pageCtx,
+	}
+	resp, err := hcCli.TCloud.Bill.GetRootAccountBillList(kt.Kit(), req)
+	if err != nil {
+		return 0, 0, decimal.Zero, nil, fmt.Errorf("list tcloud root account bill failed, err %s", err.Error())
+	}
+
+	itemLen = len(resp.Details)
+	if itemLen == 0 {
+		return resp.Count, 0, decimal.Zero, resp.Context, nil
+	}
+
+	billItems, err := convertToRawBill(resp.Details)
+	if err != nil {
+		return 0, 0, decimal.Zero, nil, err
+	}
+	cost = getRawBillCost(billItems)
+
+	filename := fmt.Sprintf("%d-%d.csv", offset, itemLen)
+	if err := tp.createRawBill(kt, opt, filename, billItems); err != nil {
+		return 0, 0, decimal.Zero, nil, err
+	}
+	return resp.Count, itemLen, cost, resp.Context, nil
+}
+
+func getRawBillCost(rawBills []dsbill.RawBillItem) decimal.Decimal {
+	cost := decimal.NewFromInt(0)
+	for _, bill := range rawBills {
+		cost = cost.Add(bill.BillCost)
+	}
+	return cost
+}
+
+// getBillDetailCost 账单明细的实际费用为各组件折后总价之和
+func getBillDetailCost(detail *billing.BillDetail) (decimal.Decimal, error) {
+	cost := decimal.NewFromInt(0)
+	for _, component := range detail.ComponentSet {
+		if component == nil || cvt.PtrToVal(component.RealCost) == "" {
+			continue
+		}
+		realCost, err := decimal.NewFromString(cvt.PtrToVal(component.RealCost))
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("parse tcloud bill component real cost %s failed, err: %v",
+				cvt.PtrToVal(component.RealCost), err)
+		}
+		cost = cost.Add(realCost)
+	}
+	return cost, nil
+}
+
+func convertToRawBill(details []*billing.BillDetail) ([]dsbill.RawBillItem, error) {
+	retList := make([]dsbill.RawBillItem, 0, len(details))
+	for _, detail := range details {
+		cost, err := getBillDetailCost(detail)
+		if err != nil {
+			return nil, err
+		}
+		extensionBytes, err := json.Marshal(detail)
+		if err != nil {
+			return nil, fmt.Errorf("marshal tcloud bill item %v failed", detail)
+		}
+		newBillItem := dsbill.RawBillItem{
+			Region:        cvt.PtrToVal(detail.RegionId),
+			HcProductCode: cvt.PtrToVal(detail.BusinessCode),
+			HcProductName: cvt.PtrToVal(detail.BusinessCodeName),
+			BillCurrency:  enumor.CurrencyCNY,
+			BillCost:      cost,
+			Extension:     types.JsonField(extensionBytes),
+		}
+		if newBillItem.Region == "" {
+			newBillItem.Region = tcloudGlobalRegion
+		}
+		retList = append(retList, newBillItem)
+	}
+	return retList, nil
+}
+
+func (tp *TCloudPuller) createRawBill(kt run.ExecuteKit, opt *registry.PullDailyBillOption,
+	filename string, billItems []dsbill.RawBillItem) error {
+
+	storeReq := &dsbill.RawBillCreateReq{
+		RawBillPathParam: dsbill.RawBillPathParam{
+			Vendor:        enumor.TCloud,
+			RootAccountID: opt.RootAccountID,
+			MainAccountID: opt.MainAccountID,
+			BillYear:      fmt.Sprintf("%d", opt.BillYear),
+			BillMonth:     fmt.Sprintf("%02d", opt.BillMonth),
+			BillDate:      fmt.Sprintf("%02d", opt.BillDay),
+			Version:       fmt.Sprintf("%d", opt.VersionID),
+			FileName:      filename,
+		},
+		Items: billItems,
+	}
+	_, err := actcli.GetDataService().Global.Bill.CreateRawBill(kt.Kit(), storeReq)
+	if err != nil {
+		return fmt.Errorf("create raw bill to dataservice failed, err %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+func TestGetBillDetailCost(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []*billing.BillDetailComponent
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "no component",
+			components: nil,
+			want:       "0",
+		},
+		{
+			name: "sum real cost of components",
+			components: []*billing.BillDetailComponent{
+				{RealCost: cvt.ValToPtr("1.25")},
+				{RealCost: cvt.ValToPtr("0.00000001")},
+				{RealCost: cvt.ValToPtr("3")},
+			},
+			want: "4.25000001",
+		},
+		{
+			name: "skip nil component and empty cost",
+			components: []*billing.BillDetailComponent{
+				nil,
+				{RealCost: cvt.ValToPtr("")},
+				{},
+				{RealCost: cvt.ValToPtr("2.5")},
+			},
+			want: "2.5",
+		},
+		{
+			name: "invalid cost",
+			components: []*billing.BillDetailComponent{
+				{RealCost: cvt.ValToPtr("abc")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getBillDetailCost(&billing.BillDetail{ComponentSet: tt.components})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tt.want).Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestConvertToRawBill(t *testing.T) {
+	details := []*billing.BillDetail{
+		{
+			OwnerUin:         cvt.ValToPtr("100001"),
+			RegionId:         cvt.ValToPtr("ap-guangzhou"),
+			BusinessCode:     cvt.ValToPtr("p_cvm"),
+			BusinessCodeName: cvt.ValToPtr("云服务器CVM"),
+			ComponentSet:     []*billing.BillDetailComponent{{RealCost: cvt.ValToPtr("1.5")}},
+		},
+		{
+			OwnerUin:         cvt.ValToPtr("100001"),
+			BusinessCode:     cvt.ValToPtr("p_cos"),
+			BusinessCodeName: cvt.ValToPtr("对象存储COS"),
+			ComponentSet:     []*billing.BillDetailComponent{{RealCost: cvt.ValToPtr("0.5")}},
+		},
+	}
+
+	items, err := convertToRawBill(details)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	assert.Equal(t, "ap-guangzhou", items[0].Region)
+	assert.Equal(t, "p_cvm", items[0].HcProductCode)
+	assert.Equal(t, "云服务器CVM", items[0].HcProductName)
+	assert.Equal(t, enumor.CurrencyCNY, items[0].BillCurrency)
+	assert.True(t, decimal.RequireFromString("1.5").Equal(items[0].BillCost))
+	assert.Contains(t, string(items[0].Extension), `"OwnerUin":"100001"`)
+
+	// 全局产品没有地域信息
+	assert.Equal(t, tcloudGlobalRegion, items[1].Region)
+	assert.True(t, decimal.RequireFromString("2").Equal(getRawBillCost(items)))
+
+	_, err = convertToRawBill([]*billing.BillDetail{
+		{ComponentSet: []*billing.BillDetailComponent{{RealCost: cvt.ValToPtr("bad")}}},
+	})
+	assert.Error(t, err)
+}
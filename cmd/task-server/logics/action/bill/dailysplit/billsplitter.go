@@ -30,6 +30,7 @@ import (
 )
 
 var vendorSplitterFunc = map[enumor.Vendor]func() RawBillSplitter{
+	enumor.TCloud:   func() RawBillSplitter { return &DefaultSplitter{} },
 	enumor.Aws:      func() RawBillSplitter { return &AwsSplitter{} },
 	enumor.Gcp:      func() RawBillSplitter { return &GcpSplitter{} },
 	enumor.HuaWei:   func() RawBillSplitter { return &DefaultSplitter{} },
@@ -20,6 +20,7 @@
 package actcli
 
 import (
+	"hcm/pkg/async/backend"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	hcservice "hcm/pkg/client/hc-service"
@@ -27,8 +28,9 @@ import (
 )
 
 var (
-	cliSet *client.ClientSet
-	daoSet dao.Set
+	cliSet  *client.ClientSet
+	daoSet  dao.Set
+	asyncBd backend.Backend
 )
 
 // SetClientSet set client set.
@@ -60,3 +62,13 @@ func SetDaoSet(cli dao.Set) {
 func GetDaoSet() dao.Set {
 	return daoSet
 }
+
+// SetAsyncBackend set async backend.
+func SetAsyncBackend(bd backend.Backend) {
+	asyncBd = bd
+}
+
+// GetAsyncBackend get async backend.
+func GetAsyncBackend() backend.Backend {
+	return asyncBd
+}
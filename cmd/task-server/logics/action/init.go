@@ -33,16 +33,19 @@ import (
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
 	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
+	actiontiming "hcm/cmd/task-server/logics/action/timing"
 	actionflow "hcm/cmd/task-server/logics/flow"
 	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/client"
 	"hcm/pkg/dal/dao"
 )
 
 // Init init action.
-func Init(cli *client.ClientSet, dao dao.Set) {
+func Init(cli *client.ClientSet, dao dao.Set, bd backend.Backend) {
 	actcli.SetClientSet(cli)
 	actcli.SetDaoSet(dao)
+	actcli.SetAsyncBackend(bd)
 
 	register()
 }
@@ -91,4 +94,7 @@ func register() {
 
 	action.RegisterAction(actionlb.SyncTCloudLoadBalancerAction{})
 	action.RegisterAction(actionlb.SyncTCloudLoadBalancerListenerAction{})
+
+	action.RegisterAction(actiontiming.RunTimingJobAction{})
+	action.RegisterTpl(actiontiming.FlowRunTimingJobTpl)
 }
@@ -39,13 +39,16 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
 	"hcm/pkg/tools/retry"
+	"hcm/pkg/tools/slice"
 )
 
 // --------------------------[TCloud创建7层规则]-----------------------------
 
 var _ action.Action = new(BatchTaskTCloudCreateL7RuleAction)
 var _ action.ParameterAction = new(BatchTaskTCloudCreateL7RuleAction)
+var _ action.CompensateAction = new(BatchTaskTCloudCreateL7RuleAction)
 
 // BatchTaskTCloudCreateL7RuleAction TCloud创建7层规则
 type BatchTaskTCloudCreateL7RuleAction struct{}
@@ -330,3 +333,55 @@ func (act BatchTaskTCloudCreateL7RuleAction) Rollback(kt run.ExecuteKit, params
 		params, kt.Kit().Rid)
 	return nil
 }
+
+// Compensate 删除本任务创建的规则，执行结果中仅记录本任务新创建的规则，任务执行前已存在的规则不做处理
+func (act BatchTaskTCloudCreateL7RuleAction) Compensate(kt run.ExecuteKit, params any, result string) error {
+	opt, ok := params.(*BatchTaskTCloudCreateL7RuleOption)
+	if !ok {
+		return errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	// 未创建规则时执行结果为描述字符串，无需补偿
+	if !strings.HasPrefix(strings.TrimSpace(result), "{") {
+		return nil
+	}
+	createResult := new(hclb.BatchCreateResult)
+	if err := json.UnmarshalFromString(result, createResult); err != nil {
+		logs.Errorf("unmarshal create l7 rule result failed, err: %v, result: %s, rid: %s", err, result,
+			kt.Kit().Rid)
+		return err
+	}
+	if len(createResult.SuccessCloudIDs) == 0 {
+		return nil
+	}
+
+	listRuleReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lbl_id", opt.ListenerID),
+			tools.RuleIn("cloud_id", createResult.SuccessCloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	switch opt.Vendor {
+	case enumor.TCloud:
+		ruleResp, err := actcli.GetDataService().TCloud.LoadBalancer.ListUrlRule(kt.Kit(), listRuleReq)
+		if err != nil {
+			logs.Errorf("fail to query created url rule, err: %v, req: %+v, rid: %s", err, listRuleReq, kt.Kit().Rid)
+			return err
+		}
+		if len(ruleResp.Details) == 0 {
+			return nil
+		}
+
+		ruleIDs := slice.Map(ruleResp.Details, func(r corelb.TCloudLbUrlRule) string { return r.ID })
+		deleteReq := &hclb.TCloudRuleDeleteByIDReq{RuleIDs: ruleIDs}
+		if err = actcli.GetHCService().TCloud.Clb.BatchDeleteUrlRule(kt.Kit(), opt.ListenerID, deleteReq); err != nil {
+			logs.Errorf("fail to call hc to delete created url rule, err: %v, ids: %v, rid: %s", err, ruleIDs,
+				kt.Kit().Rid)
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupport vendor for compensate create l7 rule: %s", opt.Vendor)
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/assert"
 	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
 	"hcm/pkg/tools/retry"
 )
 
@@ -46,6 +47,7 @@ import (
 
 var _ action.Action = new(BatchTaskTCloudCreateListenerAction)
 var _ action.ParameterAction = new(BatchTaskTCloudCreateListenerAction)
+var _ action.CompensateAction = new(BatchTaskTCloudCreateListenerAction)
 
 // BatchTaskTCloudCreateListenerAction 创建TCloud监听器
 type BatchTaskTCloudCreateListenerAction struct{}
@@ -73,6 +75,13 @@ func (opt BatchTaskTCloudCreateListenerOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
+// CreateListenerResult 创建监听器结果
+type CreateListenerResult struct {
+	core.CloudCreateResult `json:",inline"`
+	// Created 监听器是否由本任务创建，已存在的监听器为false，补偿回滚时仅删除本任务创建的监听器
+	Created bool `json:"created"`
+}
+
 // ParameterNew return request params.
 func (act BatchTaskTCloudCreateListenerAction) ParameterNew() (params any) {
 	return new(BatchTaskTCloudCreateListenerOption)
@@ -92,11 +101,11 @@ func (act BatchTaskTCloudCreateListenerAction) Run(kt run.ExecuteKit, params any
 
 	asyncKit := kt.AsyncKit()
 
-	results := make([]*core.CloudCreateResult, 0, len(opt.Listeners))
+	results := make([]*CreateListenerResult, 0, len(opt.Listeners))
 	for i := range opt.Listeners {
 		detailID := opt.ManagementDetailIDs[i]
 		// 逐条更新结果
-		ret, createErr := act.createSingleListener(asyncKit, opt.Vendor, detailID, opt.Listeners[i]) // 结束后写回状态
+		ret, created, createErr := act.createSingleListener(asyncKit, opt.Vendor, detailID, opt.Listeners[i]) // 结束后写回状态
 		targetState := enumor.TaskDetailSuccess
 		if createErr != nil {
 			// 更新为失败
@@ -112,41 +121,43 @@ func (act BatchTaskTCloudCreateListenerAction) Run(kt run.ExecuteKit, params any
 			// abort
 			return nil, err
 		}
-		results = append(results, ret)
+		if ret != nil {
+			results = append(results, &CreateListenerResult{CloudCreateResult: *ret, Created: created})
+		}
 	}
 	// all success
 	return results, nil
 }
 
 func (act BatchTaskTCloudCreateListenerAction) createSingleListener(kt *kit.Kit, vendor enumor.Vendor, detailId string,
-	req *hclb.TCloudListenerCreateReq) (*core.CloudCreateResult, error) {
+	req *hclb.TCloudListenerCreateReq) (ret *core.CloudCreateResult, created bool, err error) {
 
 	detailList, err := listTaskDetail(kt, []string{detailId})
 	if err != nil {
 		logs.Errorf("fail to query task detail, err: %v, rid: %s", err, kt.Rid)
-		return nil, err
+		return nil, false, err
 	}
 	detail := detailList[0]
 	if detail.State == enumor.TaskDetailCancel {
 		// 任务被取消，跳过该任务, 直接成功即可
-		return nil, nil
+		return nil, false, nil
 	}
 	if detail.State != enumor.TaskDetailInit {
-		return nil, errf.Newf(errf.InvalidParameter, "task management detail(%s) status(%s) is not init",
+		return nil, false, errf.Newf(errf.InvalidParameter, "task management detail(%s) status(%s) is not init",
 			detail.ID, detail.State)
 	}
 	lbl, err := act.checkListenerExists(kt, vendor, req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if lbl != nil {
 		// 已存在且参数一致，认为创建成功
-		return &core.CloudCreateResult{ID: lbl.ID, CloudID: lbl.CloudID}, nil
+		return &core.CloudCreateResult{ID: lbl.ID, CloudID: lbl.CloudID}, false, nil
 	}
 
 	// 更新任务状态为 running
 	if err := batchUpdateTaskDetailState(kt, []string{detailId}, enumor.TaskDetailRunning); err != nil {
-		return nil, fmt.Errorf("fail to update detail to running, err: %v", err)
+		return nil, false, fmt.Errorf("fail to update detail to running, err: %v", err)
 	}
 
 	var lblResp *hclb.ListenerCreateResult
@@ -157,7 +168,7 @@ func (act BatchTaskTCloudCreateListenerAction) createSingleListener(kt *kit.Kit,
 		case enumor.TCloud:
 			lblResp, err = actcli.GetHCService().TCloud.Clb.CreateListener(kt, req)
 		default:
-			return nil, fmt.Errorf("unsupport vendor for create listener: %s", vendor)
+			return nil, false, fmt.Errorf("unsupport vendor for create listener: %s", vendor)
 		}
 		// 仅在碰到限频错误时进行重试
 		if err != nil && strings.Contains(err.Error(), constant.TCloudLimitExceededErrCode) {
@@ -175,9 +186,9 @@ func (act BatchTaskTCloudCreateListenerAction) createSingleListener(kt *kit.Kit,
 
 	if err != nil {
 		logs.Errorf("fail to call hc to create listener, err: %v, rid: %s", err, kt.Rid)
-		return nil, err
+		return nil, false, err
 	}
-	return &core.CloudCreateResult{ID: lblResp.ID, CloudID: lblResp.CloudID}, nil
+	return &core.CloudCreateResult{ID: lblResp.ID, CloudID: lblResp.CloudID}, true, nil
 }
 
 // 检查监听器是否存在，不存在，不返回错误。存在会返回数据库监听器实例，如果存在但是参数一直则不返回错误
@@ -271,6 +282,49 @@ func (act BatchTaskTCloudCreateListenerAction) Rollback(kt run.ExecuteKit, param
 	return nil
 }
 
+// Compensate 删除本任务创建的监听器，任务执行前已存在的监听器不做处理
+func (act BatchTaskTCloudCreateListenerAction) Compensate(kt run.ExecuteKit, params any, result string) error {
+	opt, ok := params.(*BatchTaskTCloudCreateListenerOption)
+	if !ok {
+		return errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	results := make([]*CreateListenerResult, 0)
+	if err := json.UnmarshalFromString(result, &results); err != nil {
+		logs.Errorf("unmarshal create listener result failed, err: %v, result: %s, rid: %s", err, result,
+			kt.Kit().Rid)
+		return err
+	}
+
+	createdIDs := make([]string, 0, len(results))
+	for _, one := range results {
+		if one != nil && one.Created && len(one.ID) != 0 {
+			createdIDs = append(createdIDs, one.ID)
+		}
+	}
+	if len(createdIDs) == 0 {
+		return nil
+	}
+
+	req := &core.BatchDeleteReq{IDs: createdIDs}
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = actcli.GetHCService().TCloud.Clb.DeleteListener(kt.Kit(), req)
+	default:
+		return fmt.Errorf("unsupport vendor for compensate create listener: %s", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("fail to call hc to delete created listener, err: %v, ids: %v, rid: %s", err, createdIDs,
+			kt.Kit().Rid)
+		return err
+	}
+	return nil
+}
+
 func (act BatchTaskTCloudCreateListenerAction) checkL4RuleExists(kt *kit.Kit, lbl *corelb.TCloudListener,
 	req *hclb.TCloudListenerCreateReq) error {
 
@@ -0,0 +1,165 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDiscover 将 hc-service、data-service 的请求都指向测试服务
+type fakeDiscover struct {
+	addr string
+}
+
+func (d fakeDiscover) Discover(cc.Name) ([]string, error) {
+	return []string{d.addr}, nil
+}
+
+func (d fakeDiscover) Services() []cc.Name {
+	return []cc.Name{cc.HCServiceName, cc.DataServiceName}
+}
+
+func (d fakeDiscover) GetServiceAllNodeKeys(cc.Name) ([]string, error) {
+	return nil, nil
+}
+
+// fakeCloud 模拟云上资源，记录删除请求
+type fakeCloud struct {
+	lock      sync.Mutex
+	listeners map[string]bool
+	rules     map[string]corelb.TCloudLbUrlRule
+}
+
+func (f *fakeCloud) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	var data interface{}
+	switch {
+	case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/hc/vendors/tcloud/listeners/batch"):
+		req := new(core.BatchDeleteReq)
+		_ = json.Unmarshal(body, req)
+		for _, id := range req.IDs {
+			delete(f.listeners, id)
+		}
+	case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/rules/batch"):
+		req := new(hclb.TCloudRuleDeleteByIDReq)
+		_ = json.Unmarshal(body, req)
+		for _, id := range req.RuleIDs {
+			delete(f.rules, id)
+		}
+	case strings.HasSuffix(r.URL.Path, "/load_balancers/url_rules/list"):
+		req := new(core.ListReq)
+		_ = json.Unmarshal(body, req)
+		result := new(dataproto.TCloudURLRuleListResult)
+		raw, _ := json.Marshal(req.Filter)
+		for _, rule := range f.rules {
+			if strings.Contains(string(raw), rule.CloudID) {
+				result.Details = append(result.Details, rule)
+			}
+		}
+		data = result
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	resp, _ := json.Marshal(map[string]interface{}{"code": 0, "message": "", "data": data})
+	_, _ = w.Write(resp)
+}
+
+func newFakeCloud(t *testing.T) *fakeCloud {
+	f := &fakeCloud{listeners: make(map[string]bool), rules: make(map[string]corelb.TCloudLbUrlRule)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	old := actcli.GetClientSet()
+	actcli.SetClientSet(client.NewClientSet(http.DefaultClient, fakeDiscover{addr: srv.URL}))
+	t.Cleanup(func() { actcli.SetClientSet(old) })
+	return f
+}
+
+func TestBatchTaskTCloudCreateListenerAction_Compensate(t *testing.T) {
+	cloud := newFakeCloud(t)
+	cloud.listeners["lbl-exist"] = true
+	cloud.listeners["lbl-created"] = true
+
+	result, err := json.MarshalToString([]*CreateListenerResult{
+		{CloudCreateResult: core.CloudCreateResult{ID: "lbl-exist", CloudID: "cloud-lbl-exist"}},
+		{CloudCreateResult: core.CloudCreateResult{ID: "lbl-created", CloudID: "cloud-lbl-created"}, Created: true},
+	})
+	assert.NoError(t, err)
+
+	opt := &BatchTaskTCloudCreateListenerOption{Vendor: enumor.TCloud}
+	kt := run.NewExecuteContext(kit.New(), nil)
+	act := BatchTaskTCloudCreateListenerAction{}
+	assert.NoError(t, act.Compensate(kt, opt, result))
+
+	// 仅删除本任务创建的监听器，已存在的监听器保留
+	assert.Equal(t, map[string]bool{"lbl-exist": true}, cloud.listeners)
+
+	// 没有执行结果时无需补偿
+	assert.NoError(t, act.Compensate(kt, opt, ""))
+}
+
+func TestBatchTaskTCloudCreateL7RuleAction_Compensate(t *testing.T) {
+	cloud := newFakeCloud(t)
+	cloud.rules["rule-exist"] = corelb.TCloudLbUrlRule{ID: "rule-exist", CloudID: "cloud-rule-exist"}
+	cloud.rules["rule-created"] = corelb.TCloudLbUrlRule{ID: "rule-created", CloudID: "cloud-rule-created"}
+
+	result, err := json.MarshalToString(&hclb.BatchCreateResult{SuccessCloudIDs: []string{"cloud-rule-created"}})
+	assert.NoError(t, err)
+
+	opt := &BatchTaskTCloudCreateL7RuleOption{Vendor: enumor.TCloud, LoadBalancerID: "lb-1", ListenerID: "lbl-1"}
+	kt := run.NewExecuteContext(kit.New(), nil)
+	act := BatchTaskTCloudCreateL7RuleAction{}
+	assert.NoError(t, act.Compensate(kt, opt, result))
+
+	_, exist := cloud.rules["rule-created"]
+	assert.False(t, exist)
+	_, exist = cloud.rules["rule-exist"]
+	assert.True(t, exist)
+
+	// 未创建规则时执行结果为描述字符串，无需补偿
+	noRule, err := json.MarshalToString("no rule should be created")
+	assert.NoError(t, err)
+	assert.NoError(t, act.Compensate(kt, opt, noRule))
+	assert.Len(t, cloud.rules, 1)
+}
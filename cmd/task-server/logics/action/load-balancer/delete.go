@@ -47,44 +47,39 @@ var _ action.ParameterAction = new(DeleteLoadBalancerAction)
 // DeleteLoadBalancerAction 删除负载均衡
 type DeleteLoadBalancerAction struct{}
 
-// DeleteLoadBalancerOption ...
+// DeleteLoadBalancerOption 删除同一云厂商、账号、地域下的负载均衡
 type DeleteLoadBalancerOption struct {
-	Vendor                                   enumor.Vendor `json:"vendor,omitempty" validate:"required"`
-	hcproto.TCloudBatchDeleteLoadbalancerReq `json:",inline"`
+	Vendor    enumor.Vendor `json:"vendor" validate:"required"`
+	AccountID string        `json:"account_id" validate:"required"`
+	Region    string        `json:"region" validate:"required"`
+	IDs       []string      `json:"ids" validate:"required,min=1"`
 }
 
+// deleteLoadBalancerOption 用于序列化，避免 MarshalJSON/UnmarshalJSON 递归调用
+type deleteLoadBalancerOption DeleteLoadBalancerOption
+
 // MarshalJSON DeleteLoadBalancerOption.
 func (opt DeleteLoadBalancerOption) MarshalJSON() ([]byte, error) {
-
-	var req interface{}
 	switch opt.Vendor {
-	case enumor.TCloud:
-		req = struct {
-			Vendor                                   enumor.Vendor `json:"vendor" validate:"required"`
-			hcproto.TCloudBatchDeleteLoadbalancerReq `json:",inline"`
-		}{
-			Vendor:                           opt.Vendor,
-			TCloudBatchDeleteLoadbalancerReq: opt.TCloudBatchDeleteLoadbalancerReq,
-		}
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
 	}
 
-	return json.Marshal(req)
+	return json.Marshal(deleteLoadBalancerOption(opt))
 }
 
 // UnmarshalJSON DeleteLoadBalancerOption.
 func (opt *DeleteLoadBalancerOption) UnmarshalJSON(raw []byte) (err error) {
-	opt.Vendor = enumor.Vendor(gjson.GetBytes(raw, "vendor").String())
+	vendor := enumor.Vendor(gjson.GetBytes(raw, "vendor").String())
 
-	switch opt.Vendor {
-	case enumor.TCloud:
-		err = json.Unmarshal(raw, &opt.TCloudBatchDeleteLoadbalancerReq)
+	switch vendor {
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 	default:
-		return fmt.Errorf("vendor: %s not support", opt.Vendor)
+		return fmt.Errorf("vendor: %s not support", vendor)
 	}
 
-	return err
+	return json.Unmarshal(raw, (*deleteLoadBalancerOption)(opt))
 }
 
 // Validate validate option.
@@ -103,7 +98,7 @@ func (act DeleteLoadBalancerAction) Name() enumor.ActionName {
 	return enumor.ActionDeleteLoadBalancer
 }
 
-// Run 将目标组中的RS绑定到监听器/规则中
+// Run 按云厂商调用对应的删除负载均衡接口
 func (act DeleteLoadBalancerAction) Run(kt run.ExecuteKit, params any) (any, error) {
 
 	opt, ok := params.(*DeleteLoadBalancerOption)
@@ -113,15 +108,22 @@ func (act DeleteLoadBalancerAction) Run(kt run.ExecuteKit, params any) (any, err
 	var err error
 	switch opt.Vendor {
 	case enumor.TCloud:
-		err = actcli.GetHCService().TCloud.Clb.BatchDeleteLoadBalancer(kt.Kit(), &opt.TCloudBatchDeleteLoadbalancerReq)
-		if err != nil {
-			logs.Errorf("fail to delete tcloud load balancer, err: %v, opt: %+v rid: %s",
-				err, opt.TCloudBatchDeleteLoadbalancerReq, kt.Kit().Rid)
-			return nil, err
-		}
+		req := &hcproto.TCloudBatchDeleteLoadbalancerReq{AccountID: opt.AccountID, Region: opt.Region, IDs: opt.IDs}
+		err = actcli.GetHCService().TCloud.Clb.BatchDeleteLoadBalancer(kt.Kit(), req)
+	case enumor.Aws:
+		req := &hcproto.AwsBatchDeleteLoadBalancerReq{AccountID: opt.AccountID, Region: opt.Region, IDs: opt.IDs}
+		err = actcli.GetHCService().Aws.LoadBalancer.BatchDelete(kt.Kit(), req)
+	case enumor.HuaWei:
+		req := &hcproto.HuaWeiBatchDeleteLoadBalancerReq{AccountID: opt.AccountID, Region: opt.Region, IDs: opt.IDs}
+		err = actcli.GetHCService().HuaWei.LoadBalancer.BatchDelete(kt.Kit(), req)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
 	}
+	if err != nil {
+		logs.Errorf("fail to delete %s load balancer, err: %v, opt: %+v rid: %s", opt.Vendor, err, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
 
 	return nil, nil
 }
@@ -153,7 +155,7 @@ func (opt DeleteURLRuleOption) MarshalJSON() ([]byte, error) {
 
 	var req interface{}
 	switch opt.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		req = struct {
 			Vendor     enumor.Vendor `json:"vendor" validate:"required"`
 			LbID       string        `json:"lb_id" validate:"required"`
@@ -175,7 +177,7 @@ func (opt *DeleteURLRuleOption) UnmarshalJSON(raw []byte) (err error) {
 	opt.Vendor = enumor.Vendor(gjson.GetBytes(raw, "vendor").String())
 
 	switch opt.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		temp := struct {
 			LbID       string   `json:"lb_id" validate:"required"`
 			URLRuleIDs []string `json:"url_rule_ids"`
@@ -257,6 +259,19 @@ func (act DeleteURLRuleAction) Run(kt run.ExecuteKit, params any) (any, error) {
 				return nil, err
 			}
 		}
+	case enumor.Aws:
+		// aws、huawei 按规则id删除，hc-service 会同步清理本地规则及目标组绑定关系
+		delReq := &core.BatchDeleteReq{IDs: opt.URLRuleIDs}
+		if err := actcli.GetHCService().Aws.LoadBalancer.BatchDeleteUrlRule(kt.Kit(), delReq); err != nil {
+			logs.Errorf("fail to delete aws url rule, err: %v, opt: %+v rid: %s", err, opt, kt.Kit().Rid)
+			return nil, err
+		}
+	case enumor.HuaWei:
+		delReq := &core.BatchDeleteReq{IDs: opt.URLRuleIDs}
+		if err := actcli.GetHCService().HuaWei.LoadBalancer.BatchDeleteUrlRule(kt.Kit(), delReq); err != nil {
+			logs.Errorf("fail to delete huawei url rule, err: %v, opt: %+v rid: %s", err, opt, kt.Kit().Rid)
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
 	}
@@ -291,7 +306,7 @@ func (opt DeleteListenerOption) MarshalJSON() ([]byte, error) {
 
 	var req interface{}
 	switch opt.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		req = struct {
 			Vendor      enumor.Vendor `json:"vendor" validate:"required"`
 			LbID        string        `json:"lb_id" validate:"required"`
@@ -313,7 +328,7 @@ func (opt *DeleteListenerOption) UnmarshalJSON(raw []byte) (err error) {
 	opt.Vendor = enumor.Vendor(gjson.GetBytes(raw, "vendor").String())
 
 	switch opt.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
 		temp := struct {
 			LbID        string   `json:"lb_id" validate:"required"`
 			ListenerIDs []string `json:"url_rule_ids"`
@@ -359,6 +374,18 @@ func (act DeleteListenerAction) Run(kt run.ExecuteKit, params any) (any, error)
 				err, opt, kt.Kit().Rid)
 			return nil, err
 		}
+	case enumor.Aws:
+		delReq := &core.BatchDeleteReq{IDs: opt.ListenerIDs}
+		if err := actcli.GetHCService().Aws.LoadBalancer.BatchDeleteListener(kt.Kit(), delReq); err != nil {
+			logs.Errorf("fail to delete aws listener, err: %v, opt: %+v rid: %s", err, opt, kt.Kit().Rid)
+			return nil, err
+		}
+	case enumor.HuaWei:
+		delReq := &core.BatchDeleteReq{IDs: opt.ListenerIDs}
+		if err := actcli.GetHCService().HuaWei.LoadBalancer.BatchDeleteListener(kt.Kit(), delReq); err != nil {
+			logs.Errorf("fail to delete huawei listener, err: %v, opt: %+v rid: %s", err, opt, kt.Kit().Rid)
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
 	}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteLoadBalancerOption_JSON(t *testing.T) {
+	for _, vendor := range []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei} {
+		opt := DeleteLoadBalancerOption{
+			Vendor:    vendor,
+			AccountID: "acc-1",
+			Region:    "region-1",
+			IDs:       []string{"lb-1", "lb-2"},
+		}
+		raw, err := json.Marshal(opt)
+		if !assert.NoError(t, err, vendor) {
+			continue
+		}
+
+		got := new(DeleteLoadBalancerOption)
+		assert.NoError(t, json.Unmarshal(raw, got), vendor)
+		assert.Equal(t, opt, *got, vendor)
+	}
+
+	_, err := json.Marshal(DeleteLoadBalancerOption{Vendor: enumor.Azure})
+	assert.Error(t, err)
+	assert.Error(t, json.Unmarshal([]byte(`{"vendor":"azure","ids":["lb-1"]}`), new(DeleteLoadBalancerOption)))
+}
+
+func TestDeleteListenerOption_JSON(t *testing.T) {
+	for _, vendor := range []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei} {
+		opt := DeleteListenerOption{Vendor: vendor, LbID: "lb-1", ListenerIDs: []string{"lbl-1"}}
+		raw, err := json.Marshal(opt)
+		if !assert.NoError(t, err, vendor) {
+			continue
+		}
+
+		got := new(DeleteListenerOption)
+		assert.NoError(t, json.Unmarshal(raw, got), vendor)
+		assert.Equal(t, opt, *got, vendor)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actiontiming 周期性任务相关Action
+package actiontiming
+
+import (
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/cloud-server/timing"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/logs"
+)
+
+// FlowRunTimingJobTpl 周期性任务的任务流模版，由各服务注册的定时任务流按cron生成。
+// 任务失败时不重试，等待下一次触发。
+var FlowRunTimingJobTpl = action.FlowTemplate{
+	Name:      enumor.FlowRunTimingJob,
+	ShareData: tableasync.NewShareData(nil),
+	Tasks: []action.TaskTemplate{
+		{
+			ActionID:   "1",
+			ActionName: enumor.ActionRunTimingJob,
+		},
+	},
+}
+
+var _ action.Action = new(RunTimingJobAction)
+var _ action.ParameterAction = new(RunTimingJobAction)
+
+// RunTimingJobAction 调用周期性任务所属服务执行单次任务
+type RunTimingJobAction struct{}
+
+// ParameterNew return request params.
+func (act RunTimingJobAction) ParameterNew() (params any) {
+	return new(timing.RunJobReq)
+}
+
+// Name return action name
+func (act RunTimingJobAction) Name() enumor.ActionName {
+	return enumor.ActionRunTimingJob
+}
+
+// Run 执行一次周期性任务
+func (act RunTimingJobAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	req, ok := params.(*timing.RunJobReq)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := actcli.GetClientSet().CloudServer().Timing.RunJob(kt.Kit(), req); err != nil {
+		logs.Errorf("run timing job %s failed, err: %v, rid: %s", req.Job, err, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Rollback 周期性任务每次执行的都是完整的单次逻辑，无需回滚
+func (act RunTimingJobAction) Rollback(kt run.ExecuteKit, params any) error {
+	return nil
+}
@@ -28,19 +28,16 @@ import (
 	dataproto "hcm/pkg/api/data-service/cloud"
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
-	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
-	typesasync "hcm/pkg/dal/dao/types/async"
-	tableasync "hcm/pkg/dal/table/async"
 	tablelb "hcm/pkg/dal/table/cloud/load-balancer"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
-
-	"github.com/jmoiron/sqlx"
 )
 
 var _ action.Action = new(LoadBalancerOperateWatchAction)
@@ -92,22 +89,22 @@ func (act LoadBalancerOperateWatchAction) Run(kt run.ExecuteKit, params interfac
 			return nil, fmt.Errorf("wait timeout, async task flow: %s is running", opt.FlowID)
 		}
 
-		req := &types.ListOption{
+		input := &backend.ListInput{
 			Filter: tools.EqualExpression("id", opt.FlowID),
 			Page:   core.NewDefaultBasePage(),
 		}
-		flowList, err := actcli.GetDaoSet().AsyncFlow().List(kt.Kit(), req)
+		flows, err := actcli.GetAsyncBackend().ListFlow(kt.Kit(), input)
 		if err != nil {
 			logs.Errorf("list query flow failed, err: %v, flowID: %s, rid: %s", err, opt.FlowID, kt.Kit().Rid)
 			return nil, err
 		}
 
-		if len(flowList.Details) == 0 {
+		if len(flows) == 0 {
 			logs.Infof("list query flow not found, flowID: %s, rid: %s", opt.FlowID, kt.Kit().Rid)
 			return nil, nil
 		}
 
-		isSkip, err := act.processResFlow(kt, opt, flowList.Details[0])
+		isSkip, err := act.processResFlow(kt, opt, flows[0])
 		if err != nil {
 			return nil, err
 		}
@@ -123,16 +120,19 @@ func (act LoadBalancerOperateWatchAction) Run(kt run.ExecuteKit, params interfac
 
 // processResFlow 检查Flow是否终态状态、解锁资源跟Flow的状态
 func (act LoadBalancerOperateWatchAction) processResFlow(kt run.ExecuteKit, opt *LoadBalancerOperateWatchOption,
-	flowInfo tableasync.AsyncFlowTable) (bool, error) {
+	flowInfo model.Flow) (bool, error) {
 
 	switch flowInfo.State {
-	case enumor.FlowSuccess, enumor.FlowCancel:
+	case enumor.FlowSuccess, enumor.FlowCancel, enumor.FlowCompensated:
 		var resStatus enumor.ResFlowStatus
-		if flowInfo.State == enumor.FlowSuccess {
+		switch flowInfo.State {
+		case enumor.FlowSuccess:
 			resStatus = enumor.SuccessResFlowStatus
-		}
-		if flowInfo.State == enumor.FlowCancel {
+		case enumor.FlowCancel:
 			resStatus = enumor.CancelResFlowStatus
+		case enumor.FlowCompensated:
+			// 补偿回滚完成后任务流不可重试，直接解锁资源
+			resStatus = enumor.CompensatedResFlowStatus
 		}
 
 		if err := act.updateTargetGroupListenerRuleRelBindStatus(kt.Kit(), opt, flowInfo.State); err != nil {
@@ -147,7 +147,7 @@ func (act LoadBalancerOperateWatchAction) processResFlow(kt run.ExecuteKit, opt
 			Status:  resStatus,
 		}
 		return true, actcli.GetDataService().Global.LoadBalancer.ResFlowUnLock(kt.Kit(), unlockReq)
-	case enumor.FlowFailed:
+	case enumor.FlowFailed, enumor.FlowCompensateFailed:
 		// 当Flow失败时，检查资源锁定是否超时
 		resFlowLockList, err := act.queryResFlowLock(kt, opt)
 		if err != nil {
@@ -223,18 +223,12 @@ func (act LoadBalancerOperateWatchAction) queryResFlowLock(kt run.ExecuteKit, op
 func (act LoadBalancerOperateWatchAction) updateFlowStateByCAS(kt *kit.Kit, flowID string,
 	source, target enumor.FlowState) error {
 
-	_, err := actcli.GetDaoSet().Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		info := &typesasync.UpdateFlowInfo{
-			ID:     flowID,
-			Source: source,
-			Target: target,
-		}
-		if err := actcli.GetDaoSet().AsyncFlow().UpdateStateByCAS(kt, txn, info); err != nil {
-			return nil, err
-		}
-		return nil, nil
-	})
-	if err != nil {
+	info := backend.UpdateFlowInfo{
+		ID:     flowID,
+		Source: source,
+		Target: target,
+	}
+	if err := actcli.GetAsyncBackend().BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
 		logs.Errorf("call taskserver to update flow watch pending state failed, err: %v, flowID: %s, "+
 			"source: %s, target: %s, rid: %s", err, flowID, source, target, kt.Rid)
 		return err
@@ -254,7 +248,7 @@ func (act LoadBalancerOperateWatchAction) updateTargetGroupListenerRuleRelBindSt
 	switch flowState {
 	case enumor.FlowSuccess:
 		bindStatus = enumor.SuccessBindingStatus
-	case enumor.FlowCancel, enumor.FlowFailed:
+	case enumor.FlowCancel, enumor.FlowFailed, enumor.FlowCompensated, enumor.FlowCompensateFailed:
 		bindStatus = enumor.FailedBindingStatus
 	default:
 		return nil
@@ -22,6 +22,7 @@ package capability
 
 import (
 	"hcm/pkg/async"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/client"
 	"hcm/pkg/dal/dao"
 
@@ -34,4 +35,5 @@ type Capability struct {
 	ApiClient  *client.ClientSet
 	Async      async.Async
 	Dao        dao.Set
+	Backend    backend.Backend
 }
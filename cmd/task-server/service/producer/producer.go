@@ -42,6 +42,9 @@ func Init(cap *capability.Capability) {
 	h.Add("CreateTemplateFlow", "POST", "/template_flows/create", svc.CreateTemplateFlow)
 	h.Add("CreateCustomFlow", "POST", "/custom_flows/create", svc.CreateCustomFlow)
 	h.Add("CloneFlow", "POST", "/flows/{flow_id}/clone", svc.CloneFlow)
+	h.Add("CreateScheduledFlow", "POST", "/scheduled_flows/create", svc.CreateScheduledFlow)
+	h.Add("UpdateScheduledFlow", "PATCH", "/scheduled_flows/{id}", svc.UpdateScheduledFlow)
+	h.Add("DeleteScheduledFlow", "DELETE", "/scheduled_flows/{id}", svc.DeleteScheduledFlow)
 
 	h.Load(cap.WebService)
 }
@@ -126,3 +129,63 @@ func (p service) CloneFlow(cts *rest.Contexts) (any, error) {
 
 	return &core.CreateResult{ID: id}, nil
 }
+
+// CreateScheduledFlow add scheduled flow
+func (p service) CreateScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	// 请求体使用的是 taskserver.AddScheduledFlowReq，解析使用 producer.AddScheduledFlowOption，原因同 CreateTemplateFlow
+	opt := new(producer.AddScheduledFlowOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	id, err := p.pro.AddScheduledFlow(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("add scheduled flow failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// UpdateScheduledFlow update scheduled flow
+func (p service) UpdateScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	opt := new(producer.UpdateScheduledFlowOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := p.pro.UpdateScheduledFlow(cts.Kit, id, opt); err != nil {
+		logs.Errorf("update scheduled flow failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteScheduledFlow delete scheduled flow
+func (p service) DeleteScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := p.pro.DeleteScheduledFlow(cts.Kit, id); err != nil {
+		logs.Errorf("delete scheduled flow failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -50,16 +50,19 @@ import (
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
 	"hcm/pkg/tools/ssl"
+	"hcm/pkg/tools/times"
 
 	"github.com/emicklei/go-restful/v3"
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the task server's work
 type Service struct {
-	client *client.ClientSet
-	dao    dao.Set
-	serve  *http.Server
-	async  async.Async
+	client  *client.ClientSet
+	dao     dao.Set
+	backend backend.Backend
+	serve   *http.Server
+	async   async.Async
 }
 
 // NewService create a service instance.
@@ -90,27 +93,30 @@ func NewService(sd serviced.ServiceDiscover, shutdownWaitTimeSec int) (*Service,
 		return nil, err
 	}
 
-	logicsaction.Init(apiClientSet, dao)
-	async, err := createAndStartAsync(sd, dao, shutdownWaitTimeSec)
+	// 创建async框架使用的backend
+	bd, err := newAsyncBackend(dao)
+	if err != nil {
+		return nil, err
+	}
+
+	logicsaction.Init(apiClientSet, dao, bd)
+	async, err := createAndStartAsync(sd, bd, shutdownWaitTimeSec)
 	if err != nil {
 		return nil, err
 	}
 
 	svr := &Service{
-		client: apiClientSet,
-		dao:    dao,
-		async:  async,
+		client:  apiClientSet,
+		dao:     dao,
+		backend: bd,
+		async:   async,
 	}
 
 	return svr, nil
 }
 
-func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitTimeSec int) (async.Async, error) {
-	// 创建async框架使用的backend
-	bd, err := backend.Factory(enumor.BackendMysql, dao)
-	if err != nil {
-		return nil, err
-	}
+func createAndStartAsync(sd serviced.ServiceDiscover, bd backend.Backend, shutdownWaitTimeSec int) (async.Async,
+	error) {
 
 	leader := leader.NewLeader(sd)
 	cfg := cc.TaskServer().Async
@@ -126,13 +132,18 @@ func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitT
 				TaskExecTimeoutSec: cfg.Executor.TaskExecTimeoutSec,
 			},
 			Dispatcher: &consumer.DispatcherOption{
-				WatchIntervalSec: cfg.Dispatcher.WatchIntervalSec,
+				WatchIntervalSec:        cfg.Dispatcher.WatchIntervalSec,
+				DefaultConcurrencyLimit: cfg.Dispatcher.DefaultConcurrencyLimit,
+				ConcurrencyLimits:       convConcurrencyLimits(cfg.Dispatcher.ConcurrencyLimits),
 			},
 			WatchDog: &consumer.WatchDogOption{
 				WatchIntervalSec:    cfg.WatchDog.WatchIntervalSec,
 				TaskRunTimeoutSec:   cfg.WatchDog.TaskTimeoutSec,
 				ShutdownWaitTimeSec: uint(shutdownWaitTimeSec),
 			},
+			Trigger: &consumer.TriggerOption{
+				WatchIntervalSec: cfg.Trigger.WatchIntervalSec,
+			},
 		},
 	}
 	async, err := async.NewAsync(bd, leader, opt)
@@ -158,6 +169,41 @@ func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitT
 	return async, nil
 }
 
+// convConcurrencyLimits 将配置中的并发控制键类型转换为枚举
+func convConcurrencyLimits(limits map[string]uint) map[enumor.FlowConcurrencyKeyType]uint {
+	result := make(map[enumor.FlowConcurrencyKeyType]uint, len(limits))
+	for keyType, limit := range limits {
+		result[enumor.FlowConcurrencyKeyType(keyType)] = limit
+	}
+
+	return result
+}
+
+// newAsyncBackend 根据配置创建async框架使用的backend
+func newAsyncBackend(dao dao.Set) (backend.Backend, error) {
+	cfg := cc.TaskServer().Async.Backend
+	switch cfg.Type {
+	case enumor.BackendMysql:
+		return backend.Factory(cfg.Type, dao)
+	case enumor.BackendEtcd:
+		etcdCfg, err := cc.TaskServer().Service.Etcd.ToConfig()
+		if err != nil {
+			return nil, err
+		}
+		cli, err := etcd3.New(etcdCfg)
+		if err != nil {
+			return nil, fmt.Errorf("new etcd client for async backend failed, err: %v", err)
+		}
+		return backend.Factory(cfg.Type, &backend.EtcdOption{
+			Client:    cli,
+			KeyPrefix: cfg.Etcd.KeyPrefix,
+			Retention: time.Duration(cfg.Etcd.RetentionDays) * times.Day,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported async backend type: %s", cfg.Type)
+	}
+}
+
 // ListenAndServeRest listen and serve the restful server
 func (s *Service) ListenAndServeRest() error {
 	root := http.NewServeMux()
@@ -224,6 +270,7 @@ func (s *Service) apiSet() *restful.Container {
 		ApiClient:  s.client,
 		Async:      s.async,
 		Dao:        s.dao,
+		Backend:    s.backend,
 	}
 
 	producer.Init(c)
@@ -23,10 +23,10 @@ import (
 	"hcm/pkg/api/core"
 	coreasync "hcm/pkg/api/core/async"
 	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
-	"hcm/pkg/dal/dao/types"
-	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
@@ -42,43 +42,47 @@ func (svc *service) ListFlow(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	opt := &types.ListOption{
-		Fields: req.Fields,
-		Filter: req.Filter,
-		Page:   req.Page,
+	if req.Page.Count {
+		count, err := svc.bd.CountFlow(cts.Kit, req.Filter)
+		if err != nil {
+			logs.Errorf("count flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		return &ts.ListFlowResult{Count: count}, nil
 	}
-	result, err := svc.dao.AsyncFlow().List(cts.Kit, opt)
+
+	list, err := svc.bd.ListFlow(cts.Kit, (*backend.ListInput)(req))
 	if err != nil {
 		logs.Errorf("list flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
 	}
 
-	if req.Page.Count {
-		return &ts.ListFlowResult{Count: result.Count}, nil
-	}
-
-	flows := make([]coreasync.AsyncFlow, 0, len(result.Details))
-	for _, one := range result.Details {
+	flows := make([]coreasync.AsyncFlow, 0, len(list))
+	for _, one := range list {
 		flows = append(flows, convCoreFlow(one))
 	}
 
 	return &ts.ListFlowResult{Details: flows}, nil
 }
 
-func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
+func convCoreFlow(one model.Flow) coreasync.AsyncFlow {
 	return coreasync.AsyncFlow{
-		ID:        one.ID,
-		Name:      one.Name,
-		State:     one.State,
-		Reason:    one.Reason,
-		ShareData: one.ShareData,
-		Memo:      one.Memo,
-		Worker:    one.Worker,
+		ID:             one.ID,
+		Name:           one.Name,
+		State:          one.State,
+		Reason:         one.Reason,
+		ShareData:      one.ShareData,
+		Memo:           one.Memo,
+		Worker:         one.Worker,
+		RollbackMode:   one.RollbackMode,
+		Priority:       one.Priority,
+		ConcurrencyKey: one.ConcurrencyKey,
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
-			CreatedAt: one.CreatedAt.String(),
-			UpdatedAt: one.UpdatedAt.String(),
+			CreatedAt: one.CreatedAt,
+			UpdatedAt: one.UpdatedAt,
 		},
 	}
 }
@@ -86,20 +90,20 @@ func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
 // GetFlow get flow.
 func (svc *service) GetFlow(cts *rest.Contexts) (interface{}, error) {
 	id := cts.PathParameter("id").String()
-	opt := &types.ListOption{
+	input := &backend.ListInput{
 		Filter: tools.EqualExpression("id", id),
 		Page:   core.NewDefaultBasePage(),
 	}
-	result, err := svc.dao.AsyncFlow().List(cts.Kit, opt)
+	list, err := svc.bd.ListFlow(cts.Kit, input)
 	if err != nil {
 		logs.Errorf("list flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
 	}
 
-	if len(result.Details) == 0 {
+	if len(list) == 0 {
 		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
 	}
 
-	flow := convCoreFlow(result.Details[0])
+	flow := convCoreFlow(list[0])
 	return &flow, nil
 }
@@ -0,0 +1,277 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/consumer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
+)
+
+// GetFlowGraph get flow task graph with critical path and wait/run time of each task.
+func (svc *service) GetFlowGraph(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	flowInput := &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	flows, err := svc.bd.ListFlow(cts.Kit, flowInput)
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(flows) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
+	}
+
+	tasks := make([]model.Task, 0)
+	taskInput := &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", id),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+	for {
+		list, err := svc.bd.ListTask(cts.Kit, taskInput)
+		if err != nil {
+			logs.Errorf("list flow task failed, err: %v, flow id: %s, rid: %s", err, id, cts.Kit.Rid)
+			return nil, err
+		}
+		tasks = append(tasks, list...)
+
+		if uint(len(list)) < taskInput.Page.Limit {
+			break
+		}
+		taskInput.Page.Start += uint32(taskInput.Page.Limit)
+	}
+
+	flow := flows[0]
+	graph, err := buildFlowGraph(flow.CreatedAt, tasks, times.ConvStdTimeNow())
+	if err != nil {
+		logs.Errorf("build flow graph failed, err: %v, flow id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, errf.NewFromErr(errf.Aborted, err)
+	}
+	graph.FlowID = flow.ID
+
+	return graph, nil
+}
+
+// graphNode 计算耗时过程中的节点信息
+type graphNode struct {
+	task  *model.Task
+	node  *consumer.TaskNode
+	start *time.Time
+	end   *time.Time
+	// cost 从任务流开始到当前任务结束，途经关键路径的累计耗时
+	cost float64
+	// prev 关键路径上的前驱任务ID
+	prev string
+}
+
+// buildFlowGraph 基于任务依赖关系构建任务图，计算每个任务的等待耗时、执行耗时以及任务流的关键路径。
+// 任务的就绪时间为所有上游任务结束时间的最大值，没有上游任务时为任务流的创建时间。
+func buildFlowGraph(flowCreatedAt string, tasks []model.Task, now time.Time) (
+	*ts.FlowGraphResult, error) {
+
+	if len(tasks) == 0 {
+		return &ts.FlowGraphResult{Nodes: make([]ts.FlowGraphNode, 0), CriticalPath: make([]string, 0)}, nil
+	}
+
+	consumerTasks := make([]*consumer.Task, 0, len(tasks))
+	for _, one := range tasks {
+		consumerTasks = append(consumerTasks, &consumer.Task{Task: model.Task{
+			ID:       one.ID,
+			ActionID: one.ActionID,
+			DependOn: one.DependOn,
+			State:    one.State,
+		}})
+	}
+	root, err := consumer.BuildTaskRoot(consumerTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeMap := make(map[string]*graphNode, len(tasks))
+	for i := range tasks {
+		start, end := parseTaskTiming(&tasks[i])
+		nodeMap[tasks[i].ID] = &graphNode{task: &tasks[i], start: start, end: end}
+	}
+
+	// 按拓扑序遍历，保证计算当前任务时上游任务均已计算完成
+	order := make([]*graphNode, 0, len(tasks))
+	inDegree := make(map[string]int, len(tasks))
+	queue := []*consumer.TaskNode{root}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, child := range cur.GetChildren() {
+			inDegree[child.TaskID]++
+			if inDegree[child.TaskID] != len(child.GetParents()) {
+				continue
+			}
+			gn, exist := nodeMap[child.TaskID]
+			if !exist {
+				return nil, fmt.Errorf("task: %s not found in flow", child.TaskID)
+			}
+			gn.node = child
+			order = append(order, gn)
+			queue = append(queue, child)
+		}
+	}
+
+	flowStart := parseTime(flowCreatedAt)
+	nodes := make([]ts.FlowGraphNode, 0, len(order))
+	var last *graphNode
+	for _, gn := range order {
+		ready := flowStart
+		parents := make([]string, 0, len(gn.node.GetParents()))
+		for _, p := range gn.node.GetParents() {
+			if p.TaskID == consumer.VirtualTaskRootID {
+				continue
+			}
+			parents = append(parents, p.TaskID)
+
+			pn := nodeMap[p.TaskID]
+			if pn.end != nil && (ready == nil || pn.end.After(*ready)) {
+				ready = pn.end
+			}
+			if len(gn.prev) == 0 || pn.cost > nodeMap[gn.prev].cost {
+				gn.prev = p.TaskID
+			}
+		}
+
+		children := make([]string, 0, len(gn.node.GetChildren()))
+		for _, c := range gn.node.GetChildren() {
+			children = append(children, c.TaskID)
+		}
+
+		waitSec, runSec := calTaskCost(ready, gn.start, gn.end, now)
+		gn.cost = waitSec + runSec
+		if len(gn.prev) != 0 {
+			gn.cost += nodeMap[gn.prev].cost
+		}
+		if last == nil || gn.cost > last.cost {
+			last = gn
+		}
+
+		nodes = append(nodes, convFlowGraphNode(gn, parents, children, waitSec, runSec))
+	}
+
+	criticalPath := make([]string, 0)
+	for cur := last; cur != nil; cur = nodeMap[cur.prev] {
+		criticalPath = append([]string{cur.task.ID}, criticalPath...)
+	}
+
+	return &ts.FlowGraphResult{
+		Nodes:           nodes,
+		CriticalPath:    criticalPath,
+		CriticalPathSec: last.cost,
+	}, nil
+}
+
+func convFlowGraphNode(gn *graphNode, parents, children []string, waitSec, runSec float64) ts.FlowGraphNode {
+	node := ts.FlowGraphNode{
+		TaskID:     gn.task.ID,
+		ActionID:   string(gn.task.ActionID),
+		ActionName: gn.task.ActionName,
+		State:      gn.task.State,
+		Parents:    parents,
+		Children:   children,
+		WaitSec:    waitSec,
+		RunSec:     runSec,
+	}
+	if gn.start != nil {
+		node.StartedAt = times.ConvStdTimeFormat(*gn.start)
+	}
+	if gn.end != nil {
+		node.EndedAt = times.ConvStdTimeFormat(*gn.end)
+	}
+	if gn.task.Reason != nil {
+		node.RetryCount = gn.task.Reason.RollbackCount
+		node.Reason = gn.task.Reason.Message
+	}
+
+	return node
+}
+
+// parseTaskTiming 解析任务的开始与结束时间，历史任务未记录结束时间时，终态任务使用更新时间兜底
+func parseTaskTiming(task *model.Task) (start, end *time.Time) {
+	if task.Reason != nil {
+		start = parseTime(task.Reason.StartedAt)
+		end = parseTime(task.Reason.EndedAt)
+	}
+
+	if end == nil {
+		switch task.State {
+		case enumor.TaskSuccess, enumor.TaskFailed, enumor.TaskCancel:
+			end = parseTime(task.UpdatedAt)
+		}
+	}
+
+	return start, end
+}
+
+// calTaskCost 计算任务等待耗时与执行耗时，未开始执行的任务耗时为0，执行中的任务执行耗时计算到当前时间
+func calTaskCost(ready, start, end *time.Time, now time.Time) (waitSec, runSec float64) {
+	if start == nil {
+		return 0, 0
+	}
+
+	if ready != nil && start.After(*ready) {
+		waitSec = start.Sub(*ready).Seconds()
+	}
+
+	finish := now
+	if end != nil {
+		finish = *end
+	}
+	if finish.After(*start) {
+		runSec = finish.Sub(*start).Seconds()
+	}
+
+	return waitSec, runSec
+}
+
+func parseTime(t string) *time.Time {
+	if len(t) == 0 {
+		return nil
+	}
+
+	parsed, err := time.Parse(constant.TimeStdFormat, t)
+	if err != nil {
+		return nil
+	}
+
+	return &parsed
+}
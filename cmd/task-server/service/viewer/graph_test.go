@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFlowGraph(t *testing.T) {
+	// a -> b -> d, a -> c -> d，c 执行更慢，关键路径为 a -> c -> d
+	tasks := []model.Task{
+		newGraphTestTask("a", nil, enumor.TaskSuccess, "2024-01-01T00:00:01+08:00", "2024-01-01T00:00:03+08:00"),
+		newGraphTestTask("b", []action.ActIDType{"a"}, enumor.TaskSuccess, "2024-01-01T00:00:03+08:00",
+			"2024-01-01T00:00:04+08:00"),
+		newGraphTestTask("c", []action.ActIDType{"a"}, enumor.TaskSuccess, "2024-01-01T00:00:05+08:00",
+			"2024-01-01T00:00:10+08:00"),
+		newGraphTestTask("d", []action.ActIDType{"b", "c"}, enumor.TaskRunning, "2024-01-01T00:00:12+08:00", ""),
+	}
+	now, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:15+08:00")
+
+	graph, err := buildFlowGraph("2024-01-01T00:00:00+08:00", tasks, now)
+	assert.NoError(t, err)
+	assert.Len(t, graph.Nodes, 4)
+	assert.Equal(t, []string{"a", "c", "d"}, graph.CriticalPath)
+	assert.Equal(t, float64(15), graph.CriticalPathSec)
+
+	nodes := make(map[string]int)
+	for i, one := range graph.Nodes {
+		nodes[one.TaskID] = i
+	}
+	c := graph.Nodes[nodes["c"]]
+	assert.Equal(t, float64(2), c.WaitSec)
+	assert.Equal(t, float64(5), c.RunSec)
+	d := graph.Nodes[nodes["d"]]
+	assert.ElementsMatch(t, []string{"b", "c"}, d.Parents)
+	assert.Equal(t, float64(2), d.WaitSec)
+	assert.Equal(t, float64(3), d.RunSec)
+}
+
+func newGraphTestTask(id string, dependOn []action.ActIDType, state enumor.TaskState, start, end string) model.Task {
+	return model.Task{
+		ID:        id,
+		ActionID:  action.ActIDType(id),
+		DependOn:  dependOn,
+		State:     state,
+		Reason:    &tableasync.Reason{StartedAt: start, EndedAt: end},
+		UpdatedAt: end,
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListFlowQueueDepth list pending, scheduled and running flow count of each priority.
+func (svc *service) ListFlowQueueDepth(cts *rest.Contexts) (interface{}, error) {
+	details := make([]ts.FlowQueueDepth, 0, len(enumor.FlowPriorities))
+	for _, priority := range enumor.FlowPriorities {
+		depth := ts.FlowQueueDepth{Priority: priority}
+
+		var err error
+		if depth.Pending, err = svc.countFlow(cts, priority, enumor.FlowPending); err != nil {
+			return nil, err
+		}
+		if depth.Scheduled, err = svc.countFlow(cts, priority, enumor.FlowScheduled); err != nil {
+			return nil, err
+		}
+		if depth.Running, err = svc.countFlow(cts, priority, enumor.FlowRunning); err != nil {
+			return nil, err
+		}
+
+		details = append(details, depth)
+	}
+
+	return &ts.FlowQueueDepthResult{Details: details}, nil
+}
+
+func (svc *service) countFlow(cts *rest.Contexts, priority enumor.FlowPriority, state enumor.FlowState) (uint64,
+	error) {
+
+	expr := tools.ExpressionAnd(
+		tools.RuleEqual("state", state),
+		priorityRule(priority),
+	)
+	count, err := svc.bd.CountFlow(cts.Kit, expr)
+	if err != nil {
+		logs.Errorf("count %s flow of priority %d failed, err: %v, rid: %s", state, priority, err, cts.Kit.Rid)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// priorityRule 普通优先级包含未设置优先级的历史任务流
+func priorityRule(priority enumor.FlowPriority) *filter.AtomRule {
+	if priority == enumor.FlowPriorityNormal {
+		return tools.RuleNotIn("priority", []enumor.FlowPriority{enumor.FlowPriorityHigh, enumor.FlowPriorityLow})
+	}
+
+	return tools.RuleEqual("priority", priority)
+}
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// ListScheduledFlow list scheduled flow.
+func (svc *service) ListScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.Page.Count {
+		count, err := svc.bd.CountScheduledFlow(cts.Kit, req.Filter)
+		if err != nil {
+			logs.Errorf("count scheduled flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		return &ts.ListScheduledFlowResult{Count: count}, nil
+	}
+
+	list, err := svc.bd.ListScheduledFlow(cts.Kit, (*backend.ListInput)(req))
+	if err != nil {
+		logs.Errorf("list scheduled flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	flows := make([]coreasync.AsyncScheduledFlow, 0, len(list))
+	for _, one := range list {
+		flows = append(flows, coreasync.AsyncScheduledFlow{
+			ID:            one.ID,
+			Name:          one.Name,
+			Cron:          one.Cron,
+			FlowName:      one.FlowName,
+			Tasks:         one.Tasks,
+			Memo:          one.Memo,
+			MisfirePolicy: one.MisfirePolicy,
+			Enable:        one.Enable,
+			LastFlowID:    one.LastFlowID,
+			LastRunAt:     one.LastRunAt,
+			NextRunAt:     one.NextRunAt,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt,
+				UpdatedAt: one.UpdatedAt,
+			},
+		})
+	}
+
+	return &ts.ListScheduledFlowResult{Details: flows}, nil
+}
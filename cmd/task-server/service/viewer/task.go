@@ -23,10 +23,11 @@ import (
 	"hcm/pkg/api/core"
 	coreasync "hcm/pkg/api/core/async"
 	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
-	"hcm/pkg/dal/dao/types"
-	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
@@ -42,47 +43,53 @@ func (svc *service) ListTask(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	opt := &types.ListOption{
-		Fields: req.Fields,
-		Filter: req.Filter,
-		Page:   req.Page,
+	if req.Page.Count {
+		count, err := svc.bd.CountTask(cts.Kit, req.Filter)
+		if err != nil {
+			logs.Errorf("count task failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		return &ts.ListTaskResult{Count: count}, nil
 	}
-	result, err := svc.dao.AsyncFlowTask().List(cts.Kit, opt)
+
+	list, err := svc.bd.ListTask(cts.Kit, (*backend.ListInput)(req))
 	if err != nil {
 		logs.Errorf("list task failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
 	}
 
-	if req.Page.Count {
-		return &ts.ListTaskResult{Count: result.Count}, nil
-	}
-
-	tasks := make([]coreasync.AsyncFlowTask, 0, len(result.Details))
-	for _, one := range result.Details {
+	tasks := make([]coreasync.AsyncFlowTask, 0, len(list))
+	for _, one := range list {
 		tasks = append(tasks, convCoreTask(one))
 	}
 
 	return &ts.ListTaskResult{Details: tasks}, nil
 }
 
-func convCoreTask(one tableasync.AsyncFlowTaskTable) coreasync.AsyncFlowTask {
+func convCoreTask(one model.Task) coreasync.AsyncFlowTask {
+	dependOn := make(types.StringArray, 0, len(one.DependOn))
+	for _, dep := range one.DependOn {
+		dependOn = append(dependOn, string(dep))
+	}
+
 	return coreasync.AsyncFlowTask{
 		ID:         one.ID,
 		FlowID:     one.FlowID,
 		FlowName:   one.FlowName,
-		ActionID:   one.ActionID,
+		ActionID:   string(one.ActionID),
 		ActionName: one.ActionName,
 		Params:     one.Params,
 		Result:     one.Result,
 		Retry:      one.Retry,
-		DependOn:   one.DependOn,
+		DependOn:   dependOn,
 		State:      one.State,
 		Reason:     one.Reason,
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
-			CreatedAt: one.CreatedAt.String(),
-			UpdatedAt: one.UpdatedAt.String(),
+			CreatedAt: one.CreatedAt,
+			UpdatedAt: one.UpdatedAt,
 		},
 	}
 }
@@ -90,20 +97,20 @@ func convCoreTask(one tableasync.AsyncFlowTaskTable) coreasync.AsyncFlowTask {
 // GetTask get task.
 func (svc *service) GetTask(cts *rest.Contexts) (interface{}, error) {
 	id := cts.PathParameter("id").String()
-	opt := &types.ListOption{
+	input := &backend.ListInput{
 		Filter: tools.EqualExpression("id", id),
 		Page:   core.NewDefaultBasePage(),
 	}
-	result, err := svc.dao.AsyncFlowTask().List(cts.Kit, opt)
+	list, err := svc.bd.ListTask(cts.Kit, input)
 	if err != nil {
 		logs.Errorf("list task failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
 	}
 
-	if len(result.Details) == 0 {
+	if len(list) == 0 {
 		return nil, errf.Newf(errf.RecordNotFound, "task: %s not found", id)
 	}
 
-	task := convCoreTask(result.Details[0])
+	task := convCoreTask(list[0])
 	return &task, nil
 }
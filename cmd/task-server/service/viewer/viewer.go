@@ -22,29 +22,32 @@ package viewer
 
 import (
 	"hcm/cmd/task-server/service/capability"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/client"
-	"hcm/pkg/dal/dao"
 	"hcm/pkg/rest"
 )
 
 // Init initial the async service
 func Init(cap *capability.Capability) {
 	svc := &service{
-		cs:  cap.ApiClient,
-		dao: cap.Dao,
+		cs: cap.ApiClient,
+		bd: cap.Backend,
 	}
 
 	h := rest.NewHandler()
 
 	h.Add("ListFlow", "POST", "/flows/list", svc.ListFlow)
 	h.Add("GetFlow", "GET", "/flows/{id}", svc.GetFlow)
+	h.Add("GetFlowGraph", "GET", "/flows/{id}/graph", svc.GetFlowGraph)
+	h.Add("ListFlowQueueDepth", "GET", "/flow_queue_depths", svc.ListFlowQueueDepth)
 	h.Add("ListTask", "POST", "/tasks/list", svc.ListTask)
 	h.Add("GetTask", "GET", "/tasks/{id}", svc.GetTask)
+	h.Add("ListScheduledFlow", "POST", "/scheduled_flows/list", svc.ListScheduledFlow)
 
 	h.Load(cap.WebService)
 }
 
 type service struct {
-	cs  *client.ClientSet
-	dao dao.Set
+	cs *client.ClientSet
+	bd backend.Backend
 }
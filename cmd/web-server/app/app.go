@@ -34,6 +34,7 @@ import (
 	"hcm/pkg/runtime/ctl/cmd"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/tracing"
 )
 
 // Run start the web server
@@ -68,6 +69,11 @@ func (s *webService) prepare(opt *options.Option) error {
 
 	logs.Infof("load settings from config file success.")
 
+	// init tracing
+	if err := tracing.Init(string(cc.WebServerName), cc.WebServer().Tracing.Option()); err != nil {
+		return fmt.Errorf("init tracing failed, err: %v", err)
+	}
+
 	// init metrics
 	network := cc.WebServer().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
@@ -97,5 +103,5 @@ func (s *webService) prepare(opt *options.Option) error {
 }
 
 func (s *webService) finalizer() {
-	return
+	tracing.Shutdown()
 }
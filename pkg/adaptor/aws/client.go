@@ -20,18 +20,25 @@
 package aws
 
 import (
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/athena"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
 	curservice "github.com/aws/aws-sdk-go/service/costandusagereportservice"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -48,10 +55,75 @@ const (
 
 type clientSet struct {
 	credentials *credentials.Credentials
+	// account 云API限流维度中的账号标识
+	account string
 }
 
 func newClientSet(secret *types.BaseSecret) *clientSet {
-	return &clientSet{credentials.NewStaticCredentials(secret.CloudSecretID, secret.CloudSecretKey, "")}
+	creds := credentials.NewStaticCredentials(secret.CloudSecretID, secret.CloudSecretKey, "")
+	if secret.AssumeRole != nil {
+		creds = credentials.NewCredentials(&assumeRoleProvider{secret: secret})
+	}
+
+	return &clientSet{
+		credentials: creds,
+		account:     ratelimit.AccountOf(secret.Identity()),
+	}
+}
+
+// newSession 创建aws sdk session，每次请求（包括重试）签名前获取限流令牌，被云厂商限流时降低速率，并记录调用链
+func (c *clientSet) newSession(cfg *aws.Config) (*session.Session, error) {
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "hcm.ratelimit.Wait",
+		Fn: func(r *request.Request) {
+			if err := ratelimit.Get().Wait(r.Context(), c.rateLimitKey(r)); err != nil {
+				r.Error = err
+			}
+		},
+	})
+	sess.Handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: "hcm.ratelimit.Throttled",
+		Fn: func(r *request.Request) {
+			if request.IsErrorThrottle(r.Error) {
+				ratelimit.Get().Throttled(r.Context(), c.rateLimitKey(r))
+			}
+		},
+	})
+
+	// 每次云API调用创建一个子span（包含sdk重试的耗时），span 通过请求的 context 传递到结束回调
+	sess.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "hcm.tracing.Start",
+		Fn: func(r *request.Request) {
+			ctx, _ := metric.StartCloudApiSpan(r.Context(), c.rateLimitKey(r))
+			r.SetContext(ctx)
+		},
+	})
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "hcm.tracing.End",
+		Fn: func(r *request.Request) {
+			statusCode := 0
+			if r.HTTPResponse != nil {
+				statusCode = r.HTTPResponse.StatusCode
+			}
+			metric.EndCloudApiSpan(trace.SpanFromContext(r.Context()), statusCode, r.Error)
+		},
+	})
+
+	return sess, nil
+}
+
+func (c *clientSet) rateLimitKey(r *request.Request) ratelimit.Key {
+	return ratelimit.Key{
+		Vendor:  enumor.Aws,
+		Account: c.account,
+		Region:  aws.StringValue(r.Config.Region),
+		Action:  r.ClientInfo.ServiceName + ":" + r.Operation.Name,
+	}
 }
 
 func (c *clientSet) ec2Client(region string) (*ec2.EC2, error) {
@@ -70,7 +142,7 @@ func (c *clientSet) ec2Client(region string) (*ec2.EC2, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +162,7 @@ func (c *clientSet) stsClient() (*sts.STS, error) {
 		SleepDelay:  nil,
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +186,7 @@ func (c *clientSet) athenaClient(region string) (*athena.Athena, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +206,7 @@ func (c *clientSet) organizations() (*organizations.Organizations, error) {
 		SleepDelay:  nil,
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +230,7 @@ func (c *clientSet) s3Client(region string) (*s3.S3, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +254,7 @@ func (c *clientSet) costAndUsageReportClient(region string) (*curservice.Costand
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -206,10 +278,44 @@ func (c *clientSet) cloudFormationClient(region string) (*cloudformation.CloudFo
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	return cloudformation.New(sess, aws.NewConfig().WithRegion(region)), nil
 }
+
+func (c *clientSet) cloudTrailClient(region string) (*cloudtrail.CloudTrail, error) {
+	cfg := &aws.Config{
+		Credentials: c.credentials,
+	}
+
+	if len(region) != 0 {
+		cfg.Region = aws.String(region)
+	}
+
+	sess, err := c.newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudtrail.New(sess), nil
+}
+
+func (c *clientSet) elbv2Client(region string) (*elbv2.ELBV2, error) {
+	cfg := &aws.Config{
+		Credentials: c.credentials,
+	}
+
+	if len(region) != 0 {
+		cfg.Region = aws.String(region)
+	}
+
+	sess, err := c.newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return elbv2.New(sess), nil
+}
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+)
+
+// ListCloudEvent list cloud trail management event, only events of the region in option are returned.
+// reference: https://docs.aws.amazon.com/awscloudtrail/latest/APIReference/API_LookupEvents.html
+func (a *Aws) ListCloudEvent(kt *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws cloud event list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	client, err := a.clientSet.cloudTrailClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &cloudtrail.LookupEventsInput{
+		StartTime:  aws.Time(opt.StartTime),
+		EndTime:    aws.Time(opt.EndTime),
+		MaxResults: aws.Int64(cloudevent.AwsListLimit),
+	}
+	if len(opt.NextToken) != 0 {
+		req.NextToken = aws.String(opt.NextToken)
+	}
+
+	resp, err := client.LookupEventsWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("lookup aws cloud trail events failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	events := make([]cloudevent.CloudEvent, 0, len(resp.Events))
+	for _, one := range resp.Events {
+		// 一个事件可能涉及多种资源，按资源类型拆分
+		byType := make(map[string][]string)
+		types := make([]string, 0)
+		for _, res := range one.Resources {
+			resType := converter.PtrToVal(res.ResourceType)
+			if _, exists := byType[resType]; !exists {
+				types = append(types, resType)
+			}
+			byType[resType] = append(byType[resType], converter.PtrToVal(res.ResourceName))
+		}
+
+		for _, resType := range types {
+			events = append(events, cloudevent.CloudEvent{
+				EventID:      converter.PtrToVal(one.EventId),
+				EventName:    converter.PtrToVal(one.EventName),
+				Region:       opt.Region,
+				ResourceType: resType,
+				ResourceIDs:  byType[resType],
+				EventTime:    converter.PtrToVal(one.EventTime),
+			})
+		}
+	}
+
+	return &cloudevent.ListResult{Events: events, NextToken: converter.PtrToVal(resp.NextToken)}, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"errors"
+	"strings"
+
+	"hcm/pkg/adaptor/credential"
+	"hcm/pkg/adaptor/ratelimit"
+	"hcm/pkg/adaptor/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// assumeRoleProviderName aws sdk credentials provider name.
+const assumeRoleProviderName = "HcmAssumeRoleProvider"
+
+// assumeRoleProvider 使用基础凭证扮演角色获取临时凭证，临时凭证在多个客户端间共享，过期前自动刷新
+type assumeRoleProvider struct {
+	secret *types.BaseSecret
+	cred   *credential.Temporary
+}
+
+// Retrieve temporary credential from cache.
+func (p *assumeRoleProvider) Retrieve() (credentials.Value, error) {
+	role := p.secret.AssumeRole
+	key := credential.Key(assumeRoleProviderName, p.secret.CloudSecretID, p.secret.CloudSecretKey, role.RoleArn,
+		role.ExternalID, role.SessionName)
+	cred, err := credential.Get(key, p.assumeRole)
+	if err != nil {
+		return credentials.Value{ProviderName: assumeRoleProviderName}, err
+	}
+	p.cred = cred
+
+	return credentials.Value{
+		AccessKeyID:     cred.SecretID,
+		SecretAccessKey: cred.SecretKey,
+		SessionToken:    cred.Token,
+		ProviderName:    assumeRoleProviderName,
+	}, nil
+}
+
+// IsExpired returns if the temporary credential need to be refreshed.
+func (p *assumeRoleProvider) IsExpired() bool {
+	return p.cred.NeedRefresh()
+}
+
+func (p *assumeRoleProvider) assumeRole() (*credential.Temporary, error) {
+	source := &clientSet{
+		credentials: credentials.NewStaticCredentials(p.secret.CloudSecretID, p.secret.CloudSecretKey, ""),
+		account:     ratelimit.AccountOf(p.secret.CloudSecretID),
+	}
+	sess, err := source.newSession(&aws.Config{
+		Credentials: source.credentials,
+		Region:      aws.String(stsRegion(p.secret.AssumeRole.RoleArn)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	role := p.secret.AssumeRole
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(role.RoleArn),
+		RoleSessionName: aws.String(credential.DefaultSessionName),
+		DurationSeconds: aws.Int64(credential.DefaultDurationSec),
+	}
+	if len(role.SessionName) != 0 {
+		input.RoleSessionName = aws.String(role.SessionName)
+	}
+	if role.DurationSec != 0 {
+		input.DurationSeconds = aws.Int64(role.DurationSec)
+	}
+	if len(role.ExternalID) != 0 {
+		input.ExternalId = aws.String(role.ExternalID)
+	}
+
+	resp, err := sts.New(sess).AssumeRole(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Credentials == nil {
+		return nil, errors.New("aws assume role returns empty credentials")
+	}
+
+	return &credential.Temporary{
+		SecretID:   aws.StringValue(resp.Credentials.AccessKeyId),
+		SecretKey:  aws.StringValue(resp.Credentials.SecretAccessKey),
+		Token:      aws.StringValue(resp.Credentials.SessionToken),
+		Expiration: aws.TimeValue(resp.Credentials.Expiration),
+	}, nil
+}
+
+// stsRegion 根据角色所在的分区选择sts的区域，中国区的角色需要使用中国区的sts
+func stsRegion(roleArn string) string {
+	if strings.HasPrefix(roleArn, "arn:aws-cn:") {
+		return "cn-north-1"
+	}
+
+	return "us-east-1"
+}
@@ -0,0 +1,167 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"strconv"
+	"strings"
+
+	"hcm/pkg/adaptor/types/core"
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CreateDiskSnapshot 创建云硬盘快照
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_CreateSnapshot.html
+func (a *Aws) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "aws disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	req := &ec2.CreateSnapshotInput{
+		VolumeId:          aws.String(opt.CloudDiskID),
+		TagSpecifications: genNameTags(snapshotTagResType, opt.Name),
+	}
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	return converter.PtrToVal(resp.SnapshotId), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_DeleteSnapshot.html
+func (a *Aws) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &ec2.DeleteSnapshotInput{SnapshotId: aws.String(cloudID)}
+		if _, err = client.DeleteSnapshotWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws disk snapshot %s failed, err: %v, rid: %s", cloudID, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDiskSnapshot 查询当前账号拥有的云硬盘快照列表
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_DescribeSnapshots.html
+func (a *Aws) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ec2.DescribeSnapshotsInput{OwnerIds: []*string{aws.String("self")}}
+	if len(opt.CloudIDs) != 0 {
+		req.SnapshotIds = converter.SliceToPtr(opt.CloudIDs)
+	} else {
+		req.MaxResults = converter.ValToPtr(int64(core.AwsQueryLimit))
+	}
+
+	result := make([]disksnapshot.DiskSnapshot, 0)
+	for {
+		resp, err := client.DescribeSnapshotsWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list aws disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Snapshots {
+			result = append(result, convAwsDiskSnapshot(one))
+		}
+
+		if len(converter.PtrToVal(resp.NextToken)) == 0 {
+			break
+		}
+		req.NextToken = resp.NextToken
+	}
+
+	return result, nil
+}
+
+func convAwsDiskSnapshot(one *ec2.Snapshot) disksnapshot.DiskSnapshot {
+	name, _ := parseTags(one.Tags)
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:     converter.PtrToVal(one.SnapshotId),
+		Name:        name,
+		CloudDiskID: converter.PtrToVal(one.VolumeId),
+		DiskSize:    uint64(converter.PtrToVal(one.VolumeSize)),
+		Status:      converter.PtrToVal(one.State),
+		Encrypted:   converter.PtrToVal(one.Encrypted),
+		Extension:   new(corecloud.DiskSnapshotExtension),
+	}
+
+	if one.StartTime != nil {
+		snapshot.CloudCreatedAt = one.StartTime.Format(constant.TimeStdFormat)
+	}
+
+	// progress 格式如 "100%"
+	progress, err := strconv.ParseInt(strings.TrimSuffix(converter.PtrToVal(one.Progress), "%"), 10, 64)
+	if err == nil {
+		snapshot.Extension.Progress = converter.ValToPtr(progress)
+	}
+
+	return snapshot
+}
+
+// RollbackDiskSnapshot aws 不支持使用快照原地回滚云硬盘，需使用快照创建新的云硬盘
+func (a *Aws) RollbackDiskSnapshot(_ *kit.Kit, _ *disksnapshot.RollbackOption) error {
+	return errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrRollbackNotSupported)
+}
@@ -0,0 +1,765 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// ListLoadBalancer 查询负载均衡(ALB/NLB)，并补充标签信息
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeLoadBalancers.html
+func (a *Aws) ListLoadBalancer(kt *kit.Kit, opt *typelb.AwsListOption) (*typelb.AwsListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.DescribeLoadBalancersInput{Marker: opt.Marker, PageSize: opt.PageSize}
+	if len(opt.CloudIDs) != 0 {
+		req.LoadBalancerArns = cvt.SliceToPtr(opt.CloudIDs)
+		req.PageSize = nil
+	}
+
+	resp, err := client.DescribeLoadBalancersWithContext(kt.Ctx, req)
+	if err != nil {
+		if isLoadBalancerNotFoundErr(err) {
+			return a.listLoadBalancerOneByOne(kt, opt)
+		}
+		logs.Errorf("list aws load balancer failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	tagMap, err := a.listLoadBalancerTags(kt, client, resp.LoadBalancers)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]typelb.AwsLoadBalancer, 0, len(resp.LoadBalancers))
+	for _, one := range resp.LoadBalancers {
+		details = append(details, typelb.AwsLoadBalancer{
+			LoadBalancer: one,
+			Tags:         tagMap[cvt.PtrToVal(one.LoadBalancerArn)],
+		})
+	}
+
+	return &typelb.AwsListResult{Details: details, NextMarker: resp.NextMarker}, nil
+}
+
+// listLoadBalancerOneByOne 按arn批量查询时任一负载均衡不存在整批都会报错，需逐个查询过滤掉已删除的负载均衡
+func (a *Aws) listLoadBalancerOneByOne(kt *kit.Kit, opt *typelb.AwsListOption) (*typelb.AwsListResult, error) {
+	result := new(typelb.AwsListResult)
+	if len(opt.CloudIDs) <= 1 {
+		return result, nil
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		one, err := a.ListLoadBalancer(kt, &typelb.AwsListOption{Region: opt.Region, CloudIDs: []string{cloudID}})
+		if err != nil {
+			return nil, err
+		}
+		result.Details = append(result.Details, one.Details...)
+	}
+	return result, nil
+}
+
+// listLoadBalancerTags 查询标签，返回 arn -> tags
+func (a *Aws) listLoadBalancerTags(kt *kit.Kit, client *elbv2.ELBV2, lbs []*elbv2.LoadBalancer) (
+	map[string][]*elbv2.Tag, error) {
+
+	arns := make([]string, 0, len(lbs))
+	for _, one := range lbs {
+		arns = append(arns, cvt.PtrToVal(one.LoadBalancerArn))
+	}
+
+	tagMap := make(map[string][]*elbv2.Tag, len(arns))
+	for _, batch := range slice.Split(arns, typelb.AwsDescribeTagsMax) {
+		resp, err := client.DescribeTagsWithContext(kt.Ctx,
+			&elbv2.DescribeTagsInput{ResourceArns: cvt.SliceToPtr(batch)})
+		if err != nil {
+			logs.Errorf("describe aws load balancer tags failed, err: %v, arns: %v, rid: %s", err, batch, kt.Rid)
+			return nil, err
+		}
+		for _, desc := range resp.TagDescriptions {
+			tagMap[cvt.PtrToVal(desc.ResourceArn)] = desc.Tags
+		}
+	}
+	return tagMap, nil
+}
+
+// CreateLoadBalancer 创建负载均衡，返回负载均衡arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateLoadBalancer.html
+func (a *Aws) CreateLoadBalancer(kt *kit.Kit, opt *typelb.AwsCreateLoadBalancerOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.CreateLoadBalancerInput{
+		Name:          cvt.ValToPtr(opt.Name),
+		Type:          cvt.ValToPtr(string(opt.Type)),
+		IpAddressType: opt.IpAddressType,
+		Subnets:       cvt.SliceToPtr(opt.CloudSubnetIDs),
+	}
+	if len(opt.Scheme) != 0 {
+		req.Scheme = cvt.ValToPtr(string(opt.Scheme))
+	}
+	if len(opt.CloudSecurityGroupIDs) != 0 {
+		req.SecurityGroups = cvt.SliceToPtr(opt.CloudSecurityGroupIDs)
+	}
+	for _, tag := range opt.Tags {
+		req.Tags = append(req.Tags, &elbv2.Tag{Key: cvt.ValToPtr(tag.Key), Value: cvt.ValToPtr(tag.Value)})
+	}
+
+	resp, err := client.CreateLoadBalancerWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws load balancer failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if len(resp.LoadBalancers) == 0 {
+		return "", errf.New(errf.Aborted, "create aws load balancer return empty result")
+	}
+
+	return cvt.PtrToVal(resp.LoadBalancers[0].LoadBalancerArn), nil
+}
+
+// UpdateLoadBalancer 更新负载均衡删除保护及安全组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_ModifyLoadBalancerAttributes.html
+func (a *Aws) UpdateLoadBalancer(kt *kit.Kit, opt *typelb.AwsUpdateLoadBalancerOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	if opt.DeletionProtection != nil {
+		req := &elbv2.ModifyLoadBalancerAttributesInput{
+			LoadBalancerArn: cvt.ValToPtr(opt.CloudID),
+			Attributes: []*elbv2.LoadBalancerAttribute{{
+				Key:   cvt.ValToPtr("deletion_protection.enabled"),
+				Value: cvt.ValToPtr(fmt.Sprintf("%t", *opt.DeletionProtection)),
+			}},
+		}
+		if _, err = client.ModifyLoadBalancerAttributesWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("modify aws load balancer attributes failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+	}
+
+	if len(opt.CloudSecurityGroupIDs) != 0 {
+		req := &elbv2.SetSecurityGroupsInput{
+			LoadBalancerArn: cvt.ValToPtr(opt.CloudID),
+			SecurityGroups:  cvt.SliceToPtr(opt.CloudSecurityGroupIDs),
+		}
+		if _, err = client.SetSecurityGroupsWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("set aws load balancer security groups failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteLoadBalancer 删除负载均衡，云上不支持批量删除，逐个删除
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteLoadBalancer.html
+func (a *Aws) DeleteLoadBalancer(kt *kit.Kit, opt *typelb.AwsDeleteOption) error {
+	client, err := a.elbv2ClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: cvt.ValToPtr(cloudID)}
+		if _, err = client.DeleteLoadBalancerWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws load balancer failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListListener 查询负载均衡下的监听器
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeListeners.html
+func (a *Aws) ListListener(kt *kit.Kit, opt *typelb.AwsListListenersOption) ([]typelb.AwsListener, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := new(elbv2.DescribeListenersInput)
+	if len(opt.CloudIDs) != 0 {
+		req.ListenerArns = cvt.SliceToPtr(opt.CloudIDs)
+	} else {
+		req.LoadBalancerArn = cvt.ValToPtr(opt.CloudLoadBalancerID)
+		req.PageSize = cvt.ValToPtr(int64(typelb.AwsDescribePageSizeMax))
+	}
+
+	result := make([]typelb.AwsListener, 0)
+	for {
+		resp, err := client.DescribeListenersWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list aws listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		for _, one := range resp.Listeners {
+			result = append(result, typelb.AwsListener{Region: opt.Region, Listener: one})
+		}
+		if resp.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.NextMarker
+	}
+
+	return result, nil
+}
+
+// CreateListener 创建监听器，默认动作为转发至指定目标组，返回监听器arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateListener.html
+func (a *Aws) CreateListener(kt *kit.Kit, opt *typelb.AwsCreateListenerOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.CreateListenerInput{
+		LoadBalancerArn: cvt.ValToPtr(opt.CloudLoadBalancerID),
+		Protocol:        cvt.ValToPtr(string(opt.Protocol)),
+		Port:            cvt.ValToPtr(opt.Port),
+		SslPolicy:       opt.SslPolicy,
+		DefaultActions:  awsForwardActions(opt.CloudTargetGroupID),
+	}
+	for _, cert := range opt.CertCloudIDs {
+		req.Certificates = append(req.Certificates, &elbv2.Certificate{CertificateArn: cvt.ValToPtr(cert)})
+	}
+
+	resp, err := client.CreateListenerWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if len(resp.Listeners) == 0 {
+		return "", errf.New(errf.Aborted, "create aws listener return empty result")
+	}
+
+	return cvt.PtrToVal(resp.Listeners[0].ListenerArn), nil
+}
+
+// UpdateListener 更新监听器的协议、端口、证书及默认转发的目标组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_ModifyListener.html
+func (a *Aws) UpdateListener(kt *kit.Kit, opt *typelb.AwsUpdateListenerOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.ModifyListenerInput{
+		ListenerArn: cvt.ValToPtr(opt.CloudID),
+		Port:        opt.Port,
+		SslPolicy:   opt.SslPolicy,
+	}
+	if len(opt.Protocol) != 0 {
+		req.Protocol = cvt.ValToPtr(string(opt.Protocol))
+	}
+	if len(opt.CloudTargetGroupID) != 0 {
+		req.DefaultActions = awsForwardActions(opt.CloudTargetGroupID)
+	}
+	for _, cert := range opt.CertCloudIDs {
+		req.Certificates = append(req.Certificates, &elbv2.Certificate{CertificateArn: cvt.ValToPtr(cert)})
+	}
+
+	if _, err = client.ModifyListenerWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("modify aws listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteListener 删除监听器，监听器下的规则会一并删除
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteListener.html
+func (a *Aws) DeleteListener(kt *kit.Kit, opt *typelb.AwsDeleteOption) error {
+	client, err := a.elbv2ClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &elbv2.DeleteListenerInput{ListenerArn: cvt.ValToPtr(cloudID)}
+		if _, err = client.DeleteListenerWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws listener failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRule 查询监听器下的规则
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeRules.html
+func (a *Aws) ListRule(kt *kit.Kit, opt *typelb.AwsListRulesOption) ([]typelb.AwsRule, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := new(elbv2.DescribeRulesInput)
+	if len(opt.CloudIDs) != 0 {
+		req.RuleArns = cvt.SliceToPtr(opt.CloudIDs)
+	} else {
+		req.ListenerArn = cvt.ValToPtr(opt.CloudListenerID)
+		req.PageSize = cvt.ValToPtr(int64(typelb.AwsDescribePageSizeMax))
+	}
+
+	result := make([]typelb.AwsRule, 0)
+	for {
+		resp, err := client.DescribeRulesWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list aws listener rule failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		for _, one := range resp.Rules {
+			result = append(result, typelb.AwsRule{Rule: one})
+		}
+		if resp.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.NextMarker
+	}
+
+	return result, nil
+}
+
+// CreateRule 创建转发规则，返回规则arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateRule.html
+func (a *Aws) CreateRule(kt *kit.Kit, opt *typelb.AwsCreateRuleOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.CreateRuleInput{
+		ListenerArn: cvt.ValToPtr(opt.CloudListenerID),
+		Priority:    cvt.ValToPtr(opt.Priority),
+		Actions:     awsForwardActions(opt.CloudTargetGroupID),
+	}
+	if len(opt.Domain) != 0 {
+		req.Conditions = append(req.Conditions, &elbv2.RuleCondition{
+			Field:            cvt.ValToPtr("host-header"),
+			HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: []*string{cvt.ValToPtr(opt.Domain)}},
+		})
+	}
+	if len(opt.URL) != 0 {
+		req.Conditions = append(req.Conditions, &elbv2.RuleCondition{
+			Field:             cvt.ValToPtr("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: []*string{cvt.ValToPtr(opt.URL)}},
+		})
+	}
+
+	resp, err := client.CreateRuleWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws listener rule failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if len(resp.Rules) == 0 {
+		return "", errf.New(errf.Aborted, "create aws listener rule return empty result")
+	}
+
+	return cvt.PtrToVal(resp.Rules[0].RuleArn), nil
+}
+
+// UpdateRule 更新转发规则的域名、路径及转发的目标组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_ModifyRule.html
+func (a *Aws) UpdateRule(kt *kit.Kit, opt *typelb.AwsUpdateRuleOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.ModifyRuleInput{RuleArn: cvt.ValToPtr(opt.CloudID)}
+	if len(opt.CloudTargetGroupID) != 0 {
+		req.Actions = awsForwardActions(opt.CloudTargetGroupID)
+	}
+	if len(opt.Domain) != 0 {
+		req.Conditions = append(req.Conditions, &elbv2.RuleCondition{
+			Field:            cvt.ValToPtr("host-header"),
+			HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: []*string{cvt.ValToPtr(opt.Domain)}},
+		})
+	}
+	if len(opt.URL) != 0 {
+		req.Conditions = append(req.Conditions, &elbv2.RuleCondition{
+			Field:             cvt.ValToPtr("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: []*string{cvt.ValToPtr(opt.URL)}},
+		})
+	}
+
+	if _, err = client.ModifyRuleWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("modify aws listener rule failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteRule 删除转发规则
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteRule.html
+func (a *Aws) DeleteRule(kt *kit.Kit, opt *typelb.AwsDeleteOption) error {
+	client, err := a.elbv2ClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteRuleWithContext(kt.Ctx, &elbv2.DeleteRuleInput{RuleArn: cvt.ValToPtr(cloudID)}); err != nil {
+			logs.Errorf("delete aws listener rule failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTargetGroup 查询目标组，指定负载均衡时只返回该负载均衡关联的目标组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeTargetGroups.html
+func (a *Aws) ListTargetGroup(kt *kit.Kit, opt *typelb.AwsListTargetGroupOption) ([]typelb.AwsTargetGroup, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := new(elbv2.DescribeTargetGroupsInput)
+	switch {
+	case len(opt.CloudIDs) != 0:
+		req.TargetGroupArns = cvt.SliceToPtr(opt.CloudIDs)
+	case len(opt.CloudLoadBalancerID) != 0:
+		req.LoadBalancerArn = cvt.ValToPtr(opt.CloudLoadBalancerID)
+		req.PageSize = cvt.ValToPtr(int64(typelb.AwsDescribePageSizeMax))
+	default:
+		req.PageSize = cvt.ValToPtr(int64(typelb.AwsDescribePageSizeMax))
+	}
+
+	result := make([]typelb.AwsTargetGroup, 0)
+	for {
+		resp, err := client.DescribeTargetGroupsWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list aws target group failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		for _, one := range resp.TargetGroups {
+			result = append(result, typelb.AwsTargetGroup{TargetGroup: one})
+		}
+		if resp.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.NextMarker
+	}
+
+	return result, nil
+}
+
+// CreateTargetGroup 创建目标组，返回目标组arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateTargetGroup.html
+func (a *Aws) CreateTargetGroup(kt *kit.Kit, opt *typelb.AwsCreateTargetGroupOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.CreateTargetGroupInput{
+		Name:       cvt.ValToPtr(opt.Name),
+		Protocol:   cvt.ValToPtr(string(opt.Protocol)),
+		Port:       cvt.ValToPtr(opt.Port),
+		VpcId:      cvt.ValToPtr(opt.CloudVpcID),
+		TargetType: opt.TargetType,
+	}
+	if hc := opt.HealthCheck; hc != nil {
+		req.HealthCheckEnabled = hc.Enabled
+		req.HealthCheckProtocol = hc.Protocol
+		req.HealthCheckPort = hc.Port
+		req.HealthCheckPath = hc.Path
+		req.HealthCheckIntervalSeconds = hc.IntervalSeconds
+		req.HealthCheckTimeoutSeconds = hc.TimeoutSeconds
+		req.HealthyThresholdCount = hc.HealthyThreshold
+		req.UnhealthyThresholdCount = hc.UnhealthyThreshold
+		if hc.Matcher != nil {
+			req.Matcher = &elbv2.Matcher{HttpCode: hc.Matcher}
+		}
+	}
+
+	resp, err := client.CreateTargetGroupWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws target group failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if len(resp.TargetGroups) == 0 {
+		return "", errf.New(errf.Aborted, "create aws target group return empty result")
+	}
+
+	return cvt.PtrToVal(resp.TargetGroups[0].TargetGroupArn), nil
+}
+
+// UpdateTargetGroup 更新目标组健康检查
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_ModifyTargetGroup.html
+func (a *Aws) UpdateTargetGroup(kt *kit.Kit, opt *typelb.AwsUpdateTargetGroupOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	hc := opt.HealthCheck
+	req := &elbv2.ModifyTargetGroupInput{
+		TargetGroupArn:             cvt.ValToPtr(opt.CloudID),
+		HealthCheckEnabled:         hc.Enabled,
+		HealthCheckProtocol:        hc.Protocol,
+		HealthCheckPort:            hc.Port,
+		HealthCheckPath:            hc.Path,
+		HealthCheckIntervalSeconds: hc.IntervalSeconds,
+		HealthCheckTimeoutSeconds:  hc.TimeoutSeconds,
+		HealthyThresholdCount:      hc.HealthyThreshold,
+		UnhealthyThresholdCount:    hc.UnhealthyThreshold,
+	}
+	if hc.Matcher != nil {
+		req.Matcher = &elbv2.Matcher{HttpCode: hc.Matcher}
+	}
+
+	if _, err = client.ModifyTargetGroupWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("modify aws target group failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteTargetGroup 删除目标组，目标组不能被监听器或规则引用
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteTargetGroup.html
+func (a *Aws) DeleteTargetGroup(kt *kit.Kit, opt *typelb.AwsDeleteOption) error {
+	client, err := a.elbv2ClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &elbv2.DeleteTargetGroupInput{TargetGroupArn: cvt.ValToPtr(cloudID)}
+		if _, err = client.DeleteTargetGroupWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws target group failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTargetHealth 查询目标组下的目标及健康状态
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeTargetHealth.html
+func (a *Aws) ListTargetHealth(kt *kit.Kit, opt *typelb.AwsListTargetHealthOption) ([]typelb.AwsTarget, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.DescribeTargetHealthInput{TargetGroupArn: cvt.ValToPtr(opt.CloudTargetGroupID)}
+	resp, err := client.DescribeTargetHealthWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("list aws target health failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]typelb.AwsTarget, 0, len(resp.TargetHealthDescriptions))
+	for _, one := range resp.TargetHealthDescriptions {
+		result = append(result, typelb.AwsTarget{TargetHealthDescription: one})
+	}
+	return result, nil
+}
+
+// RegisterTargets 向目标组注册目标
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_RegisterTargets.html
+func (a *Aws) RegisterTargets(kt *kit.Kit, opt *typelb.AwsTargetsOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "register option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.RegisterTargetsInput{
+		TargetGroupArn: cvt.ValToPtr(opt.CloudTargetGroupID),
+		Targets:        awsTargetDescriptions(opt.Targets),
+	}
+	if _, err = client.RegisterTargetsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("register aws targets failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeregisterTargets 从目标组解绑目标
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeregisterTargets.html
+func (a *Aws) DeregisterTargets(kt *kit.Kit, opt *typelb.AwsTargetsOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "deregister option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+
+	req := &elbv2.DeregisterTargetsInput{
+		TargetGroupArn: cvt.ValToPtr(opt.CloudTargetGroupID),
+		Targets:        awsTargetDescriptions(opt.Targets),
+	}
+	if _, err = client.DeregisterTargetsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("deregister aws targets failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+func (a *Aws) elbv2ClientForDelete(opt *typelb.AwsDeleteOption) (*elbv2.ELBV2, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "delete option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init aws elbv2 client failed, err: %v", err)
+	}
+	return client, nil
+}
+
+func awsForwardActions(cloudTargetGroupID string) []*elbv2.Action {
+	return []*elbv2.Action{{
+		Type:           cvt.ValToPtr(elbv2.ActionTypeEnumForward),
+		TargetGroupArn: cvt.ValToPtr(cloudTargetGroupID),
+	}}
+}
+
+func awsTargetDescriptions(targets []typelb.AwsTargetOption) []*elbv2.TargetDescription {
+	return slice.Map(targets, func(t typelb.AwsTargetOption) *elbv2.TargetDescription {
+		return &elbv2.TargetDescription{Id: cvt.ValToPtr(t.CloudInstID), Port: cvt.ValToPtr(t.Port)}
+	})
+}
+
+func isLoadBalancerNotFoundErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), elbv2.ErrCodeLoadBalancerNotFoundException)
+}
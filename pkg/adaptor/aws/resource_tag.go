@@ -0,0 +1,123 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ec2TagResTypes 支持通过 ec2 CreateTags/DeleteTags 打标签的资源类型
+var ec2TagResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:           {},
+	enumor.DiskCloudResType:          {},
+	enumor.EipCloudResType:           {},
+	enumor.SecurityGroupCloudResType: {},
+	enumor.VpcCloudResType:           {},
+	enumor.SubnetCloudResType:        {},
+}
+
+// TagResources 为资源批量添加标签，标签键已存在时覆盖标签值
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateTags.html
+func (a *Aws) TagResources(kt *kit.Kit, opt *restag.TagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws tag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, ok := ec2TagResTypes[opt.ResType]; !ok {
+		return errf.Newf(errf.InvalidParameter, "aws tag does not support resource type: %s", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ec2 client failed, err: %v", err)
+	}
+
+	tags := make([]*ec2.Tag, 0, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags = append(tags, &ec2.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+
+	for _, batch := range slice.Split(opt.CloudIDs, restag.AwsTagResLimit) {
+		req := &ec2.CreateTagsInput{
+			Resources: aws.StringSlice(batch),
+			Tags:      tags,
+		}
+		if _, err = client.CreateTagsWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("create aws ec2 tags failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 批量删除资源的标签
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteTags.html
+func (a *Aws) UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws untag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, ok := ec2TagResTypes[opt.ResType]; !ok {
+		return errf.Newf(errf.InvalidParameter, "aws tag does not support resource type: %s", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ec2 client failed, err: %v", err)
+	}
+
+	// 不指定标签值时，删除指定键的标签
+	tags := make([]*ec2.Tag, 0, len(opt.TagKeys))
+	for _, key := range opt.TagKeys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key)})
+	}
+
+	for _, batch := range slice.Split(opt.CloudIDs, restag.AwsTagResLimit) {
+		req := &ec2.DeleteTagsInput{
+			Resources: aws.StringSlice(batch),
+			Tags:      tags,
+		}
+		if _, err = client.DeleteTagsWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws ec2 tags failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
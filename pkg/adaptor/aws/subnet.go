@@ -230,8 +230,9 @@ func convertSubnet(data *ec2.Subnet, region string) *adtysubnet.AwsSubnet {
 		},
 	}
 
-	name, _ := parseTags(data.Tags)
+	name, tags := parseTags(data.Tags)
 	s.Name = name
+	s.Tags = convertEc2Tags(tags)
 
 	if data.CidrBlock != nil && converter.PtrToVal(data.CidrBlock) != "" {
 		cidr := converter.PtrToVal(data.CidrBlock)
@@ -20,6 +20,7 @@
 package aws
 
 import (
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/tools/converter"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -34,8 +35,9 @@ const tagKeyForResourceName = "ImportMode"
 type tagResourceType string
 
 const (
-	vpcTagResType    tagResourceType = "vpc"
-	subnetTagResType tagResourceType = "subnet"
+	vpcTagResType      tagResourceType = "vpc"
+	subnetTagResType   tagResourceType = "subnet"
+	snapshotTagResType tagResourceType = "snapshot"
 )
 
 // genNameTags generate name ec2 tags.
@@ -69,3 +71,15 @@ func parseTags(tags []*ec2.Tag) (string, []*ec2.Tag) {
 
 	return "", tags
 }
+
+// convertEc2Tags convert ec2 tags to tag pairs.
+func convertEc2Tags(tags []*ec2.Tag) []apicore.TagPair {
+	pairs := make([]apicore.TagPair, 0, len(tags))
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+		pairs = append(pairs, apicore.TagPair{Key: converter.PtrToVal(tag.Key), Value: converter.PtrToVal(tag.Value)})
+	}
+	return pairs
+}
@@ -248,8 +248,9 @@ func convertVpc(data *ec2.Vpc, region string) *types.AwsVpc {
 		},
 	}
 
-	name, _ := parseTags(data.Tags)
+	name, tags := parseTags(data.Tags)
 	v.Name = name
+	v.Tags = convertEc2Tags(tags)
 
 	if data.CidrBlock != nil {
 		v.Extension.Cidr = append(v.Extension.Cidr, cloud.AwsCidr{
@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"strings"
 
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
 	typesBill "hcm/pkg/adaptor/types/bill"
 	"hcm/pkg/criteria/errf"
@@ -86,12 +87,16 @@ type UsageRespError struct {
 	Message string `json:"message"`
 }
 
-func newBillClient(server string, token *LoginTokenProto) (*billClient, error) {
+// newBillClient keyFunc不为空时，请求前获取限流令牌
+func newBillClient(server string, token *LoginTokenProto, keyFunc ratelimit.KeyFunc) (*billClient, error) {
 	// 生成Client
 	cli, err := client.NewClient(nil)
 	if err != nil {
 		return nil, err
 	}
+	if keyFunc != nil {
+		cli.Transport = ratelimit.NewRoundTripper(cli.Transport, keyFunc, ratelimit.IsTooManyRequests)
+	}
 
 	c := &client.Capability{
 		Client: cli,
@@ -105,7 +110,7 @@ func newBillClient(server string, token *LoginTokenProto) (*billClient, error) {
 }
 
 func getToken(kt *kit.Kit, credential *types.AzureCredential) (*LoginTokenProto, error) {
-	cli, err := newBillClient(LoginServerURL, nil)
+	cli, err := newBillClient(LoginServerURL, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -21,11 +21,18 @@ package azure
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v2"
@@ -33,8 +40,13 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/subscription/armsubscription"
 	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
+	msauth "github.com/microsoftgraph/msgraph-sdk-go-core/authentication"
 )
 
+// graphScope microsoft graph api 默认授权范围
+const graphScope = "https://graph.microsoft.com/.default"
+
 type clientSet struct {
 	credential *types.AzureCredential
 }
@@ -43,6 +55,97 @@ func newClientSet(credential *types.AzureCredential) *clientSet {
 	return &clientSet{credential}
 }
 
+// armOptions arm 客户端配置，每次请求（包括sdk重试）前获取限流令牌，被云厂商限流时降低速率，并记录调用链
+func (c *clientSet) armOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			PerCallPolicies:  []policy.Policy{&tracingPolicy{keyFunc: c.rateLimitKey}},
+			PerRetryPolicies: []policy.Policy{&rateLimitPolicy{keyFunc: c.rateLimitKey}},
+		},
+	}
+}
+
+// tracingPolicy azure sdk 调用链策略，每次云API调用创建一个子span（包含sdk重试的耗时）
+type tracingPolicy struct {
+	keyFunc ratelimit.KeyFunc
+}
+
+// Do ...
+func (p *tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := metric.StartCloudApiSpan(raw.Context(), p.keyFunc(raw))
+
+	resp, err := req.WithContext(ctx).Next()
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	metric.EndCloudApiSpan(span, statusCode, err)
+
+	return resp, err
+}
+
+// rateLimitPolicy azure sdk 限流策略
+type rateLimitPolicy struct {
+	keyFunc ratelimit.KeyFunc
+}
+
+// Do ...
+func (p *rateLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	key := p.keyFunc(raw)
+	if err := ratelimit.Get().Wait(raw.Context(), key); err != nil {
+		return nil, err
+	}
+
+	resp, err := req.Next()
+	if err == nil && ratelimit.IsTooManyRequests(resp) {
+		ratelimit.Get().Throttled(raw.Context(), key)
+	}
+
+	return resp, err
+}
+
+func (c *clientSet) rateLimitKey(req *http.Request) ratelimit.Key {
+	account := c.credential.CloudSubscriptionID
+	if len(account) == 0 {
+		account = c.credential.CloudTenantID
+	}
+
+	return ratelimit.Key{
+		Vendor:  enumor.Azure,
+		Account: account,
+		Region:  azureRegionOfPath(req.URL.Path),
+		Action:  azureActionOfPath(req.Method, req.URL.Path),
+	}
+}
+
+// azureActionOfPath azure RESTful api 路径按 集合/名称 交替出现，名称替换为*作为接口名，providers 之后为资源提供程序命名空间，
+// 例如: /subscriptions/{id}/resourceGroups/{rg}/providers/Microsoft.Network/virtualNetworks/{name}/subnets
+func azureActionOfPath(method, urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for i := 0; i+1 < len(segments); i += 2 {
+		if strings.EqualFold(segments[i], "providers") {
+			continue
+		}
+		segments[i+1] = "*"
+	}
+
+	return method + " /" + strings.Join(segments, "/")
+}
+
+// azureRegionOfPath 从请求路径中解析地域，大部分接口路径中不包含地域
+func azureRegionOfPath(urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "locations") {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
+
 // graphServiceClient ...
 func (c *clientSet) graphServiceClient() (*msgraphsdk.GraphServiceClient, error) {
 	credential, err := c.newClientSecretCredential()
@@ -50,12 +153,23 @@ func (c *clientSet) graphServiceClient() (*msgraphsdk.GraphServiceClient, error)
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := msgraphsdk.NewGraphServiceClientWithCredentials(credential, nil)
+	auth, err := msauth.NewAzureIdentityAuthenticationProviderWithScopes(credential, []string{graphScope})
+	if err != nil {
+		return nil, fmt.Errorf("init azure graph authentication provider failed, err: %v", err)
+	}
+
+	opts := msgraphsdk.GetDefaultClientOptions()
+	httpClient := msgraphcore.GetDefaultClient(&opts)
+	httpClient.Transport = metric.NewTracingRoundTripper(
+		ratelimit.NewRoundTripper(httpClient.Transport, c.rateLimitKey, ratelimit.IsTooManyRequests), c.rateLimitKey)
+
+	adapter, err := msgraphsdk.NewGraphRequestAdapterWithParseNodeFactoryAndSerializationWriterFactoryAndHttpClient(
+		auth, nil, nil, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("init azure subscription client failed, err: %v", err)
 	}
 
-	return client, nil
+	return msgraphsdk.NewGraphServiceClient(adapter), nil
 }
 
 // subscriptionClient ...
@@ -65,7 +179,7 @@ func (c *clientSet) subscriptionClient() (*armsubscription.SubscriptionsClient,
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armsubscription.NewSubscriptionsClient(credential, nil)
+	client, err := armsubscription.NewSubscriptionsClient(credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure subscription client failed, err: %v", err)
 	}
@@ -83,7 +197,7 @@ func (c *clientSet) vpcClient() (*armnetwork.VirtualNetworksClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworksClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewVirtualNetworksClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -100,7 +214,7 @@ func (c *clientSet) usageClient() (*armnetwork.UsagesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewUsagesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewUsagesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure usage client failed, err: %v", err)
 	}
@@ -117,7 +231,7 @@ func (c *clientSet) subnetClient() (*armnetwork.SubnetsClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewSubnetsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewSubnetsClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -130,7 +244,16 @@ func (c *clientSet) diskClient() (*armcompute.DisksClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
-	return armcompute.NewDisksClient(c.credential.CloudSubscriptionID, credential, nil)
+	return armcompute.NewDisksClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
+}
+
+// snapshotClient ...
+func (c *clientSet) snapshotClient() (*armcompute.SnapshotsClient, error) {
+	credential, err := c.newClientSecretCredential()
+	if err != nil {
+		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
+	}
+	return armcompute.NewSnapshotsClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 }
 
 // imageClient ...
@@ -140,7 +263,7 @@ func (c *clientSet) imageClient() (*armcompute.VirtualMachineImagesClient, error
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	return armcompute.NewVirtualMachineImagesClient(c.credential.CloudSubscriptionID, credential, nil)
+	return armcompute.NewVirtualMachineImagesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 }
 
 // newClientSecretCredential ...
@@ -158,7 +281,7 @@ func (c *clientSet) securityGroupClient() (*armnetwork.SecurityGroupsClient, err
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewSecurityGroupsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewSecurityGroupsClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure security group client failed, err: %v", err)
 	}
@@ -173,7 +296,7 @@ func (c *clientSet) virtualMachineClient() (*armcompute.VirtualMachinesClient, e
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewVirtualMachinesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewVirtualMachinesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure virtual machines client failed, err: %v", err)
 	}
@@ -188,7 +311,7 @@ func (c *clientSet) virtualMachineSizeClient() (*armcompute.VirtualMachineSizesC
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewVirtualMachineSizesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewVirtualMachineSizesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure virtual machine sizes client failed, err: %v", err)
 	}
@@ -203,7 +326,7 @@ func (c *clientSet) clientFactory() (*armcompute.ClientFactory, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewClientFactory(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewClientFactory(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure client factory failed, err: %v", err)
 	}
@@ -218,7 +341,7 @@ func (c *clientSet) resourceGroupsClient() (*armresources.ResourceGroupsClient,
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armresources.NewResourceGroupsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armresources.NewResourceGroupsClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init resourceGroups client failed, err: %v", err)
 	}
@@ -226,6 +349,21 @@ func (c *clientSet) resourceGroupsClient() (*armresources.ResourceGroupsClient,
 	return client, nil
 }
 
+// tagsClient ...
+func (c *clientSet) tagsClient() (*armresources.TagsClient, error) {
+	credential, err := c.newClientSecretCredential()
+	if err != nil {
+		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
+	}
+
+	client, err := armresources.NewTagsClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
+	if err != nil {
+		return nil, fmt.Errorf("init tags client failed, err: %v", err)
+	}
+
+	return client, nil
+}
+
 // regionClient ...
 func (c *clientSet) regionClient() (*armsubscriptions.Client, error) {
 	credential, err := c.newClientSecretCredential()
@@ -233,7 +371,7 @@ func (c *clientSet) regionClient() (*armsubscriptions.Client, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armsubscriptions.NewClient(credential, nil)
+	client, err := armsubscriptions.NewClient(credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init region client failed, err: %v", err)
 	}
@@ -251,7 +389,7 @@ func (c *clientSet) routeTableClient() (*armnetwork.RouteTablesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewRouteTablesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewRouteTablesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -268,7 +406,7 @@ func (c *clientSet) routeClient() (*armnetwork.RoutesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewRoutesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewRoutesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -281,7 +419,7 @@ func (c *clientSet) publicIPAddressesClient() (*armnetwork.PublicIPAddressesClie
 	if err != nil {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
-	client, err := armnetwork.NewPublicIPAddressesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewPublicIPAddressesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure public ip addresses client failed, err: %v", err)
 	}
@@ -295,7 +433,7 @@ func (c *clientSet) networkInterfaceClient() (*armnetwork.InterfacesClient, erro
 		return nil, fmt.Errorf("init network interface credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewInterfacesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewInterfacesClient(c.credential.CloudSubscriptionID, credential, c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init network interface client failed, err: %v", err)
 	}
@@ -311,7 +449,7 @@ func (c *clientSet) networkInterfaceIPConfigClient() (*armnetwork.InterfaceIPCon
 	}
 
 	client, err := armnetwork.NewInterfaceIPConfigurationsClient(c.credential.CloudSubscriptionID, credential,
-		nil)
+		c.armOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init network interface ipconfig client failed, err: %v", err)
 	}
@@ -327,7 +465,7 @@ func (c *clientSet) usageDetailClient(kt *kit.Kit) (*billClient, error) {
 		return nil, err
 	}
 
-	return newBillClient(ManageServerURL, token)
+	return newBillClient(ManageServerURL, token, c.rateLimitKey)
 }
 
 // GenResourceName 生产azure批量创建资源名称
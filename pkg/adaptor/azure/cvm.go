@@ -263,6 +263,7 @@ func converterToAzureCvm(vms []*armcompute.VirtualMachine) []*typecvm.AzureCvm {
 			Location: SPtrToLowerNoSpaceSPtr(v.Location),
 			Type:     v.Type,
 			Zones:    v.Zones,
+			Tags:     convertTags(v.Tags),
 		}
 
 		if v.Properties == nil {
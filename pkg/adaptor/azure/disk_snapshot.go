@@ -0,0 +1,191 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// CreateDiskSnapshot 创建云硬盘快照，返回快照的资源ID
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/create-or-update?tabs=Go
+func (az *Azure) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "azure disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.ResourceGroupName) == 0 {
+		return "", errf.New(errf.InvalidParameter, "resource_group_name is required")
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return "", err
+	}
+
+	snapshot := armcompute.Snapshot{
+		Location: to.Ptr(opt.Region),
+		Properties: &armcompute.SnapshotProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     to.Ptr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: to.Ptr(opt.CloudDiskID),
+			},
+		},
+	}
+	pollerResp, err := client.BeginCreateOrUpdate(kt.Ctx, opt.ResourceGroupName, opt.Name, snapshot, nil)
+	if err != nil {
+		logs.Errorf("create azure disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	resp, err := pollerResp.PollUntilDone(kt.Ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return SPtrToLowerStr(resp.ID), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/delete?tabs=Go
+func (az *Azure) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		pollerResp, err := client.BeginDelete(kt.Ctx, opt.ResourceGroupName, parseIDToName(cloudID), nil)
+		if err != nil {
+			logs.Errorf("delete azure disk snapshot %s failed, err: %v, rid: %s", cloudID, err, kt.Rid)
+			return errorf(err)
+		}
+
+		if _, err = pollerResp.PollUntilDone(kt.Ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDiskSnapshot 查询资源组下指定地域的云硬盘快照列表
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/list-by-resource-group?tabs=Go
+func (az *Azure) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "azure disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.ResourceGroupName) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "resource_group_name is required")
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := converter.StringSliceToMap(opt.CloudIDs)
+	result := make([]disksnapshot.DiskSnapshot, 0)
+	pager := client.NewListByResourceGroupPager(opt.ResourceGroupName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(kt.Ctx)
+		if err != nil {
+			logs.Errorf("list azure disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, errorf(err)
+		}
+
+		for _, one := range page.Value {
+			if len(opt.Region) != 0 && SPtrToLowerNoSpaceStr(one.Location) != StrToLowerNoSpaceStr(opt.Region) {
+				continue
+			}
+
+			snapshot := convAzureDiskSnapshot(one, opt.ResourceGroupName)
+			if len(idMap) != 0 {
+				if _, exist := idMap[snapshot.CloudID]; !exist {
+					continue
+				}
+			}
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+}
+
+func convAzureDiskSnapshot(one *armcompute.Snapshot, resGroupName string) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID: SPtrToLowerStr(one.ID),
+		Name:    converter.PtrToVal(one.Name),
+		Region:  SPtrToLowerNoSpaceStr(one.Location),
+		Extension: &corecloud.DiskSnapshotExtension{
+			ResourceGroupName: resGroupName,
+		},
+	}
+
+	if one.Properties == nil {
+		return snapshot
+	}
+
+	props := one.Properties
+	snapshot.DiskSize = uint64(converter.PtrToVal(props.DiskSizeGB))
+	snapshot.Status = converter.PtrToVal(props.ProvisioningState)
+	snapshot.Encrypted = props.Encryption != nil && props.Encryption.Type != nil
+	if props.CreationData != nil {
+		snapshot.CloudDiskID = SPtrToLowerStr(props.CreationData.SourceResourceID)
+	}
+	if props.TimeCreated != nil {
+		snapshot.CloudCreatedAt = props.TimeCreated.Format(constant.TimeStdFormat)
+	}
+	if props.CompletionPercent != nil {
+		snapshot.Extension.Progress = converter.ValToPtr(int64(*props.CompletionPercent))
+	}
+
+	return snapshot
+}
+
+// RollbackDiskSnapshot azure 不支持使用快照原地回滚云硬盘，需使用快照创建新的云硬盘
+func (az *Azure) RollbackDiskSnapshot(_ *kit.Kit, _ *disksnapshot.RollbackOption) error {
+	return errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrRollbackNotSupported)
+}
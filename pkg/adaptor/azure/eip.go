@@ -131,6 +131,7 @@ func convertEip(one *armnetwork.PublicIPAddress, resGroupName string) *eip.Azure
 		ResourceGroupName:      strings.ToLower(resGroupName),
 		Location:               one.Location,
 		PublicIPAddressVersion: (*string)(one.Properties.PublicIPAddressVersion),
+		Tags:                   convertTags(one.Tags),
 	}
 
 	if one.Properties.DNSSettings != nil {
@@ -0,0 +1,141 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	apicore "hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// tagResTypes 支持通过 Tags At Scope 接口打标签的资源类型，资源的云ID即为资源的 ARM 资源ID
+var tagResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:           {},
+	enumor.DiskCloudResType:          {},
+	enumor.EipCloudResType:           {},
+	enumor.SecurityGroupCloudResType: {},
+	enumor.VpcCloudResType:           {},
+}
+
+// TagResources 为资源批量添加标签，标签键已存在时覆盖标签值
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) TagResources(kt *kit.Kit, opt *restag.TagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure tag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, ok := tagResTypes[opt.ResType]; !ok {
+		return errf.Newf(errf.InvalidParameter, "azure tag does not support resource type: %s", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	tags := make(map[string]*string, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags[tag.Key] = converter.ValToPtr(tag.Value)
+	}
+
+	req := armresources.TagsPatchResource{
+		Operation:  converter.ValToPtr(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{Tags: tags},
+	}
+	for _, id := range opt.CloudIDs {
+		if _, err = client.UpdateAtScope(kt.Ctx, id, req, nil); err != nil {
+			logs.Errorf("merge azure resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 批量删除资源的标签，Delete 操作需要标签值匹配才会删除，所以先查询资源当前的标签再整体替换
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure untag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, ok := tagResTypes[opt.ResType]; !ok {
+		return errf.Newf(errf.InvalidParameter, "azure tag does not support resource type: %s", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	for _, id := range opt.CloudIDs {
+		resp, err := client.GetAtScope(kt.Ctx, id, nil)
+		if err != nil {
+			logs.Errorf("get azure resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+
+		tags := make(map[string]*string)
+		if resp.Properties != nil {
+			for key, value := range resp.Properties.Tags {
+				tags[key] = value
+			}
+		}
+		for _, key := range opt.TagKeys {
+			delete(tags, key)
+		}
+
+		req := armresources.TagsPatchResource{
+			Operation:  converter.ValToPtr(armresources.TagsPatchOperationReplace),
+			Properties: &armresources.Tags{Tags: tags},
+		}
+		if _, err = client.UpdateAtScope(kt.Ctx, id, req, nil); err != nil {
+			logs.Errorf("replace azure resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertTags 转换 azure 资源的标签
+func convertTags(tags map[string]*string) []apicore.TagPair {
+	tagMap := make(apicore.TagMap, len(tags))
+	for key, value := range tags {
+		tagMap[key] = converter.PtrToVal(value)
+	}
+	return tagMap.Pairs()
+}
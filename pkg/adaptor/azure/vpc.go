@@ -270,6 +270,7 @@ func convertVpc(data *armnetwork.VirtualNetwork, resourceGroup string) *types.Az
 			DNSServers:        make([]string, 0),
 			Cidr:              nil,
 		},
+		Tags: convertTags(data.Tags),
 	}
 
 	if data.Properties == nil {
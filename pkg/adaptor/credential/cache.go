@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package credential 扮演角色获取的临时凭证的缓存，临时凭证在过期前自动刷新，多个云API客户端共享同一份凭证
+package credential
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"hcm/pkg/logs"
+)
+
+const (
+	// DefaultDurationSec 默认的临时凭证有效期
+	DefaultDurationSec = 3600
+	// DefaultSessionName 默认的角色会话名称
+	DefaultSessionName = "hcm"
+	// refreshAhead 临时凭证在过期前提前刷新的时间
+	refreshAhead = 5 * time.Minute
+)
+
+// Temporary 临时凭证
+type Temporary struct {
+	SecretID   string
+	SecretKey  string
+	Token      string
+	Expiration time.Time
+}
+
+// NeedRefresh 临时凭证即将过期时需要刷新
+func (t *Temporary) NeedRefresh() bool {
+	return t.needRefresh(time.Now())
+}
+
+func (t *Temporary) needRefresh(now time.Time) bool {
+	return t == nil || now.Add(refreshAhead).After(t.Expiration)
+}
+
+// expired 临时凭证已过期
+func (t *Temporary) expired(now time.Time) bool {
+	return t == nil || !now.Before(t.Expiration)
+}
+
+// FetchFunc 扮演角色获取临时凭证
+type FetchFunc func() (*Temporary, error)
+
+// Cache 临时凭证缓存
+type Cache struct {
+	lock  sync.Mutex
+	items map[string]*item
+	now   func() time.Time
+}
+
+type item struct {
+	// lock 同一个凭证同时只有一个刷新请求
+	lock sync.Mutex
+	cred *Temporary
+}
+
+// NewCache new temporary credential cache.
+func NewCache() *Cache {
+	return &Cache{items: make(map[string]*item), now: time.Now}
+}
+
+var defaultCache = NewCache()
+
+// Get 从默认缓存获取临时凭证
+func Get(key string, fetch FetchFunc) (*Temporary, error) {
+	return defaultCache.Get(key, fetch)
+}
+
+// Get 获取临时凭证，缓存不存在或即将过期时调用 fetch 刷新，刷新失败但原凭证未过期时继续使用原凭证
+func (c *Cache) Get(key string, fetch FetchFunc) (*Temporary, error) {
+	c.lock.Lock()
+	one, exists := c.items[key]
+	if !exists {
+		one = new(item)
+		c.items[key] = one
+	}
+	c.lock.Unlock()
+
+	one.lock.Lock()
+	defer one.lock.Unlock()
+
+	now := c.now()
+	if !one.cred.needRefresh(now) {
+		return one.cred, nil
+	}
+
+	cred, err := fetch()
+	if err == nil && cred == nil {
+		err = errors.New("fetched temporary credential is nil")
+	}
+	if err != nil {
+		if !one.cred.expired(now) {
+			logs.Errorf("refresh temporary credential failed, use the unexpired one, err: %v", err)
+			return one.cred, nil
+		}
+		return nil, err
+	}
+
+	one.cred = cred
+	return cred, nil
+}
+
+// Key 生成临时凭证的缓存键，包含基础凭证的密钥摘要，避免使用错误密钥时命中其他请求缓存的临时凭证
+func Key(fields ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package credential
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheGet(t *testing.T) {
+	now := time.Now()
+	c := NewCache()
+	c.now = func() time.Time { return now }
+
+	fetched := 0
+	fetch := func() (*Temporary, error) {
+		fetched++
+		return &Temporary{SecretID: "ak", Expiration: now.Add(time.Hour)}, nil
+	}
+
+	if _, err := c.Get("k", fetch); err != nil {
+		t.Fatalf("get credential failed, err: %v", err)
+	}
+	if _, err := c.Get("k", fetch); err != nil || fetched != 1 {
+		t.Fatalf("unexpired credential should be cached, fetched: %d, err: %v", fetched, err)
+	}
+
+	// 即将过期时提前刷新
+	now = now.Add(time.Hour - refreshAhead + time.Second)
+	if _, err := c.Get("k", fetch); err != nil || fetched != 2 {
+		t.Fatalf("credential should be refreshed ahead, fetched: %d, err: %v", fetched, err)
+	}
+
+	// 刷新失败时继续使用未过期的凭证，过期后返回错误
+	failed := func() (*Temporary, error) { return nil, errors.New("assume role failed") }
+	now = now.Add(time.Hour - refreshAhead + time.Second)
+	if cred, err := c.Get("k", failed); err != nil || cred.SecretID != "ak" {
+		t.Fatalf("unexpired credential should be used when refresh failed, err: %v", err)
+	}
+
+	now = now.Add(refreshAhead)
+	if _, err := c.Get("k", failed); err == nil {
+		t.Fatalf("expired credential should not be used")
+	}
+}
@@ -20,9 +20,15 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 
 	asset "cloud.google.com/go/asset/apiv1"
@@ -33,8 +39,15 @@ import (
 	"google.golang.org/api/compute/v1"
 	iam "google.golang.org/api/iam/v1"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// cloudPlatformScope 自定义http transport时需要显式指定授权范围，该范围覆盖所有使用到的gcp服务
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
 type clientSet struct {
 	credential *types.GcpCredential
 }
@@ -43,9 +56,104 @@ func newClientSet(credential *types.GcpCredential) *clientSet {
 	return &clientSet{credential}
 }
 
+// httpOption gcp http api 使用的客户端配置，发送请求前获取限流令牌，被云厂商限流时降低速率，并记录调用链
+func (c *clientSet) httpOption(kt *kit.Kit) (option.ClientOption, error) {
+	keyFunc := func(req *http.Request) ratelimit.Key {
+		return c.rateLimitKey(gcpRegionOfPath(req.URL.Path), gcpActionOfPath(req.Method, req.URL.Path))
+	}
+	base := metric.NewTracingRoundTripper(ratelimit.NewRoundTripper(nil, keyFunc, ratelimit.IsTooManyRequests),
+		keyFunc)
+
+	transport, err := htransport.NewTransport(kt.Ctx, base, option.WithCredentialsJSON(c.credential.Json),
+		option.WithScopes(cloudPlatformScope))
+	if err != nil {
+		return nil, err
+	}
+
+	return option.WithHTTPClient(&http.Client{Transport: transport}), nil
+}
+
+// grpcOption gcp grpc api 使用的客户端配置，调用前获取限流令牌，被云厂商限流时降低速率，并记录调用链
+func (c *clientSet) grpcOption() option.ClientOption {
+	interceptor := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
+
+		key := c.rateLimitKey("", method)
+		ctx, span := metric.StartCloudApiSpan(ctx, key)
+		defer func() { metric.EndCloudApiSpan(span, 0, err) }()
+
+		if err = ratelimit.Get().Wait(ctx, key); err != nil {
+			return err
+		}
+
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) == codes.ResourceExhausted {
+			ratelimit.Get().Throttled(ctx, key)
+		}
+
+		return err
+	}
+
+	return option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(interceptor))
+}
+
+func (c *clientSet) rateLimitKey(region, action string) ratelimit.Key {
+	return ratelimit.Key{
+		Vendor:  enumor.Gcp,
+		Account: c.credential.CloudProjectID,
+		Region:  region,
+		Action:  action,
+	}
+}
+
+// gcpActionOfPath gcp RESTful api 路径中 projects 之后按 集合/名称 交替出现，名称替换为*作为接口名，
+// 例如: /compute/v1/projects/{project}/zones/{zone}/instances/{name}/stop
+func gcpActionOfPath(method, urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	start := -1
+	for i, seg := range segments {
+		if seg == "projects" {
+			start = i
+			break
+		}
+	}
+
+	if start != -1 {
+		for i := start + 1; i < len(segments); i += 2 {
+			// 保留自定义方法，例如 {project}:search
+			if idx := strings.Index(segments[i], ":"); idx != -1 {
+				segments[i] = "*" + segments[i][idx:]
+				continue
+			}
+			segments[i] = "*"
+		}
+	}
+
+	return method + " /" + strings.Join(segments, "/")
+}
+
+// gcpRegionOfPath 从请求路径中解析地域，可用区转换为所属地域
+func gcpRegionOfPath(urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		switch segments[i] {
+		case "regions":
+			return segments[i+1]
+		case "zones":
+			zone := segments[i+1]
+			if idx := strings.LastIndex(zone, "-"); idx != -1 {
+				return zone[:idx]
+			}
+			return zone
+		}
+	}
+
+	return ""
+}
+
 func (c *clientSet) assetClient(kt *kit.Kit) (*asset.Client, error) {
 	opt := option.WithCredentialsJSON(c.credential.Json)
-	client, err := asset.NewClient(kt.Ctx, opt)
+	client, err := asset.NewClient(kt.Ctx, opt, c.grpcOption())
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +163,7 @@ func (c *clientSet) assetClient(kt *kit.Kit) (*asset.Client, error) {
 
 func (c *clientSet) iamClient(kt *kit.Kit) (*credentials.IamCredentialsClient, error) {
 	opt := option.WithCredentialsJSON(c.credential.Json)
-	client, err := credentials.NewIamCredentialsClient(kt.Ctx, opt)
+	client, err := credentials.NewIamCredentialsClient(kt.Ctx, opt, c.grpcOption())
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +172,11 @@ func (c *clientSet) iamClient(kt *kit.Kit) (*credentials.IamCredentialsClient, e
 }
 
 func (c *clientSet) computeClient(kt *kit.Kit) (*compute.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := compute.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -74,7 +186,11 @@ func (c *clientSet) computeClient(kt *kit.Kit) (*compute.Service, error) {
 }
 
 func (c *clientSet) bigQueryClient(kt *kit.Kit) (*bigquery.Client, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := bigquery.NewClient(kt.Ctx, c.credential.CloudProjectID, opt)
 	if err != nil {
 		return nil, fmt.Errorf("gcp.bigquery.NewClient, projectID: %s, err: %+v",
@@ -86,7 +202,11 @@ func (c *clientSet) bigQueryClient(kt *kit.Kit) (*bigquery.Client, error) {
 }
 
 func (c *clientSet) resClient(kt *kit.Kit) (*res.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := res.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -96,7 +216,11 @@ func (c *clientSet) resClient(kt *kit.Kit) (*res.Service, error) {
 }
 
 func (c *clientSet) iamServiceClient(kt *kit.Kit) (*iam.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := iam.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -106,7 +230,11 @@ func (c *clientSet) iamServiceClient(kt *kit.Kit) (*iam.Service, error) {
 }
 
 func (c *clientSet) billingClient(kt *kit.Kit) (*cloudbilling.APIService, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := cloudbilling.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,204 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// CreateDiskSnapshot 创建云硬盘快照，gcp 快照为全局资源，返回快照的数字ID
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/createSnapshot
+func (g *Gcp) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "gcp disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Zone) == 0 {
+		return "", errf.New(errf.InvalidParameter, "zone is required")
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = client.Disks.CreateSnapshot(g.CloudProjectID(), opt.Zone, opt.CloudDiskID,
+		&compute.Snapshot{Name: opt.Name}).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("create gcp disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	return g.getDiskSnapshotCloudID(kt, opt.Name)
+}
+
+// getDiskSnapshotCloudID 快照由异步操作创建，等待快照资源出现后返回其数字ID
+func (g *Gcp) getDiskSnapshotCloudID(kt *kit.Kit, name string) (string, error) {
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	endTime := time.Now().Add(time.Minute)
+	for time.Now().Before(endTime) {
+		resp, err := client.Snapshots.Get(g.CloudProjectID(), name).Context(kt.Ctx).Do()
+		if err == nil {
+			return strconv.FormatUint(resp.Id, 10), nil
+		}
+
+		if gErr, ok := err.(*googleapi.Error); !ok || gErr.Code != http.StatusNotFound {
+			return "", err
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+
+	return "", fmt.Errorf("disk snapshot %s not found after created", name)
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/delete
+func (g *Gcp) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.Snapshots.Delete(g.CloudProjectID(), cloudID).Context(kt.Ctx).Do(); err != nil {
+			logs.Errorf("delete gcp disk snapshot %s failed, err: %v, rid: %s", cloudID, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDiskSnapshot 查询云硬盘快照列表，gcp 快照为全局资源，按源云盘所在的地域过滤
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/list
+func (g *Gcp) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "gcp disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := converter.StringSliceToMap(opt.CloudIDs)
+	result := make([]disksnapshot.DiskSnapshot, 0)
+	err = client.Snapshots.List(g.CloudProjectID()).Pages(kt.Ctx, func(page *compute.SnapshotList) error {
+		for _, one := range page.Items {
+			snapshot := convGcpDiskSnapshot(one)
+			if zoneToRegion(snapshot.Zone) != opt.Region {
+				continue
+			}
+
+			if len(idMap) != 0 {
+				if _, exist := idMap[snapshot.CloudID]; !exist {
+					continue
+				}
+			}
+
+			result = append(result, snapshot)
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func convGcpDiskSnapshot(one *compute.Snapshot) disksnapshot.DiskSnapshot {
+	return disksnapshot.DiskSnapshot{
+		CloudID:        strconv.FormatUint(one.Id, 10),
+		Name:           one.Name,
+		Zone:           parseSourceDiskZone(one.SourceDisk),
+		CloudDiskID:    one.SourceDiskId,
+		DiskSize:       uint64(one.DiskSizeGb),
+		Status:         one.Status,
+		Encrypted:      one.SnapshotEncryptionKey != nil,
+		CloudCreatedAt: one.CreationTimestamp,
+		Extension: &corecloud.DiskSnapshotExtension{
+			SelfLink:     one.SelfLink,
+			SnapshotType: one.SnapshotType,
+		},
+	}
+}
+
+// parseSourceDiskZone parse zone from source disk link.
+// source disk link format: https://www.googleapis.com/compute/v1/projects/xxx/zones/{zone}/disks/{name}.
+func parseSourceDiskZone(link string) string {
+	fields := strings.Split(link, "/")
+	for idx := 0; idx < len(fields)-1; idx++ {
+		if fields[idx] == "zones" {
+			return fields[idx+1]
+		}
+	}
+	return ""
+}
+
+// zoneToRegion convert zone to region, e.g. us-central1-a to us-central1.
+func zoneToRegion(zone string) string {
+	idx := strings.LastIndex(zone, "-")
+	if idx < 0 {
+		return zone
+	}
+	return zone[:idx]
+}
+
+// RollbackDiskSnapshot gcp 不支持使用快照原地回滚云硬盘，需使用快照创建新的云硬盘
+func (g *Gcp) RollbackDiskSnapshot(_ *kit.Kit, _ *disksnapshot.RollbackOption) error {
+	return errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrRollbackNotSupported)
+}
@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// labelResTypes 支持通过 setLabels 设置标签的资源类型，gcp 的 vpc、子网不支持标签
+var labelResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:  {},
+	enumor.DiskCloudResType: {},
+	enumor.EipCloudResType:  {},
+}
+
+// labelUpdater 根据资源当前的标签生成新的标签
+type labelUpdater func(labels map[string]string) map[string]string
+
+// TagResources 为资源批量添加标签，标签键已存在时覆盖标签值
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/instances/setLabels
+func (g *Gcp) TagResources(kt *kit.Kit, opt *restag.TagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp tag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	updater := func(labels map[string]string) map[string]string {
+		merged := make(map[string]string, len(labels)+len(opt.Tags))
+		for key, value := range labels {
+			merged[key] = value
+		}
+		for _, tag := range opt.Tags {
+			merged[tag.Key] = tag.Value
+		}
+		return merged
+	}
+
+	return g.updateLabels(kt, opt.ResType, opt.CloudIDs, updater)
+}
+
+// UnTagResources 批量删除资源的标签
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/instances/setLabels
+func (g *Gcp) UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp untag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	updater := func(labels map[string]string) map[string]string {
+		remained := make(map[string]string, len(labels))
+		for key, value := range labels {
+			remained[key] = value
+		}
+		for _, key := range opt.TagKeys {
+			delete(remained, key)
+		}
+		return remained
+	}
+
+	return g.updateLabels(kt, opt.ResType, opt.CloudIDs, updater)
+}
+
+// updateLabels gcp 设置标签时需要资源名称、所在地域/可用区以及当前标签的指纹，所以先按云ID查询资源再逐个设置标签
+func (g *Gcp) updateLabels(kt *kit.Kit, resType enumor.CloudResourceType, cloudIDs []string,
+	updater labelUpdater) error {
+
+	if _, ok := labelResTypes[resType]; !ok {
+		return errf.Newf(errf.InvalidParameter, "gcp label does not support resource type: %s", resType)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	switch resType {
+	case enumor.CvmCloudResType:
+		return g.updateCvmLabels(kt, client, cloudIDs, updater)
+	case enumor.DiskCloudResType:
+		return g.updateDiskLabels(kt, client, cloudIDs, updater)
+	default:
+		return g.updateEipLabels(kt, client, cloudIDs, updater)
+	}
+}
+
+func (g *Gcp) updateCvmLabels(kt *kit.Kit, client *compute.Service, cloudIDs []string,
+	updater labelUpdater) error {
+
+	resp, err := client.Instances.AggregatedList(g.CloudProjectID()).Context(kt.Ctx).
+		Filter(generateResourceIDsFilter(cloudIDs)).Do()
+	if err != nil {
+		logs.Errorf("list aggregated instance failed, err: %v, ids: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return err
+	}
+
+	for _, scoped := range resp.Items {
+		for _, one := range scoped.Instances {
+			req := &compute.InstancesSetLabelsRequest{
+				Labels:           updater(one.Labels),
+				LabelFingerprint: one.LabelFingerprint,
+			}
+			_, err = client.Instances.SetLabels(g.CloudProjectID(), parseSelfLinkToName(one.Zone), one.Name, req).
+				Context(kt.Ctx).Do()
+			if err != nil {
+				logs.Errorf("set gcp instance labels failed, err: %v, id: %d, rid: %s", err, one.Id, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *Gcp) updateDiskLabels(kt *kit.Kit, client *compute.Service, cloudIDs []string,
+	updater labelUpdater) error {
+
+	resp, err := client.Disks.AggregatedList(g.CloudProjectID()).Context(kt.Ctx).
+		Filter(generateResourceIDsFilter(cloudIDs)).Do()
+	if err != nil {
+		logs.Errorf("list aggregated disk failed, err: %v, ids: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return err
+	}
+
+	for _, scoped := range resp.Items {
+		for _, one := range scoped.Disks {
+			req := &compute.ZoneSetLabelsRequest{
+				Labels:           updater(one.Labels),
+				LabelFingerprint: one.LabelFingerprint,
+			}
+			_, err = client.Disks.SetLabels(g.CloudProjectID(), parseSelfLinkToName(one.Zone), one.Name, req).
+				Context(kt.Ctx).Do()
+			if err != nil {
+				logs.Errorf("set gcp disk labels failed, err: %v, id: %d, rid: %s", err, one.Id, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *Gcp) updateEipLabels(kt *kit.Kit, client *compute.Service, cloudIDs []string,
+	updater labelUpdater) error {
+
+	resp, err := client.Addresses.AggregatedList(g.CloudProjectID()).Context(kt.Ctx).
+		Filter(generateResourceIDsFilter(cloudIDs)).Do()
+	if err != nil {
+		logs.Errorf("list aggregated address failed, err: %v, ids: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return err
+	}
+
+	for _, scoped := range resp.Items {
+		for _, one := range scoped.Addresses {
+			// 全局 eip 的地域为空，需要使用 globalAddresses 接口
+			if len(one.Region) == 0 {
+				req := &compute.GlobalSetLabelsRequest{
+					Labels:           updater(one.Labels),
+					LabelFingerprint: one.LabelFingerprint,
+				}
+				_, err = client.GlobalAddresses.SetLabels(g.CloudProjectID(), one.Name, req).Context(kt.Ctx).Do()
+			} else {
+				req := &compute.RegionSetLabelsRequest{
+					Labels:           updater(one.Labels),
+					LabelFingerprint: one.LabelFingerprint,
+				}
+				_, err = client.Addresses.SetLabels(g.CloudProjectID(), parseSelfLinkToName(one.Region), one.Name,
+					req).Context(kt.Ctx).Do()
+			}
+			if err != nil {
+				logs.Errorf("set gcp address labels failed, err: %v, id: %d, rid: %s", err, one.Id, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
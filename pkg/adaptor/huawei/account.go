@@ -159,3 +159,28 @@ func (h *HuaWei) GetAccountInfoBySecret(kt *kit.Kit, accessKeyID string) (*cloud
 	return accountInfo, nil
 
 }
+
+// GetAgencyAccountInfo 委托模式下临时凭证无法查询永久AK所属用户，仅根据委托方账号名获取账号信息
+// https://console-intl.huaweicloud.com/apiexplorer/#/openapi/IAM/doc?api=KeystoneListAuthDomains
+func (h *HuaWei) GetAgencyAccountInfo(kt *kit.Kit, domainName string) (*cloud.HuaWeiInfoBySecret, error) {
+	client, err := h.clientSet.iamGlobalClient(region.AP_SOUTHEAST_1)
+	if err != nil {
+		logs.Errorf("new iam client failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	domainResp, err := client.KeystoneListAuthDomains(new(model.KeystoneListAuthDomainsRequest))
+	if err != nil {
+		logs.Errorf("KeystoneListAuthDomainsRequest failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, fmt.Errorf("KeystoneListAuthDomainsRequest failed, err: %v", err)
+	}
+
+	for _, one := range converter.PtrToVal(domainResp.Domains) {
+		if one.Name == domainName {
+			return &cloud.HuaWeiInfoBySecret{CloudSubAccountID: one.Id, CloudSubAccountName: one.Name}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("KeystoneListAuthDomainsRequest not fount domain: %s, domains: %v", domainName,
+		domainResp.Domains)
+}
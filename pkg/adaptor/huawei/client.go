@@ -20,16 +20,26 @@
 package huawei
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/logs"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/global"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/config"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/httphandler"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
 	bssintl "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2"
 	bssintlv2region "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/region"
+	cts "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3"
+	ctsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3/region"
 	dcs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/dcs/v2"
 	dcsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/dcs/v2/region"
 	ecs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2"
@@ -38,6 +48,8 @@ import (
 	eipregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v2/region"
 	eipv3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v3"
 	eipv3region "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v3/region"
+	elb "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/elb/v3"
+	elbregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/elb/v3/region"
 	evs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2"
 	evsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/region"
 	iam "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3"
@@ -45,6 +57,8 @@ import (
 	ims "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ims/v2"
 	rms "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rms/v1"
 	rmsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rms/v1/region"
+	tms "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/tms/v1"
+	tmsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/tms/v1/region"
 	vpcv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v2"
 	vpc "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3"
 	vpcregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3/region"
@@ -64,9 +78,15 @@ type NewGlobalCredentialsFunc func() *global.Credentials
 type clientSet struct {
 	credentials       NewCredentialsFunc
 	globalCredentials NewGlobalCredentialsFunc
+	// account 云API限流维度中的账号标识
+	account string
 }
 
-func newClientSet(secret *types.BaseSecret) *clientSet {
+func newClientSet(secret *types.BaseSecret) (*clientSet, error) {
+	if secret.AssumeRole != nil {
+		return newAgencyClientSet(secret)
+	}
+
 	return &clientSet{
 		credentials: func() *basic.Credentials {
 			return basic.NewCredentialsBuilder().
@@ -80,6 +100,73 @@ func newClientSet(secret *types.BaseSecret) *clientSet {
 				WithSk(secret.CloudSecretKey).
 				Build()
 		},
+		account: ratelimit.AccountOf(secret.CloudSecretID),
+	}, nil
+}
+
+// newAgencyClientSet 使用委托获取的临时凭证访问云API，每次创建sdk客户端时获取最新的临时凭证
+func newAgencyClientSet(secret *types.BaseSecret) (*clientSet, error) {
+	agency, err := newAgencyCredential(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientSet{
+		credentials: func() *basic.Credentials {
+			cred := agency.get()
+			return basic.NewCredentialsBuilder().
+				WithAk(cred.SecretID).
+				WithSk(cred.SecretKey).
+				WithSecurityToken(cred.Token).
+				Build()
+		},
+		globalCredentials: func() *global.Credentials {
+			cred := agency.get()
+			return global.NewCredentialsBuilder().
+				WithAk(cred.SecretID).
+				WithSk(cred.SecretKey).
+				WithSecurityToken(cred.Token).
+				Build()
+		},
+		account: ratelimit.AccountOf(secret.Identity()),
+	}, nil
+}
+
+// httpConfig huawei sdk 使用的http配置，发送请求前获取限流令牌，被云厂商限流时降低速率。
+// Note: huawei sdk 的请求回调无法中断请求，获取令牌失败时仅记录日志，请求仍会发送。
+// huawei sdk 不支持传递 context，云API调用的span根据请求耗时补记，无法关联到上游调用链。
+func (c *clientSet) httpConfig() *config.HttpConfig {
+	handler := httphandler.NewHttpHandler().
+		AddRequestHandler(func(req http.Request) {
+			key := c.rateLimitKey(req.URL.Host, req.Method, req.URL.Path)
+			if err := ratelimit.Get().Wait(req.Context(), key); err != nil {
+				logs.Errorf("wait huawei cloud api rate limit failed, err: %v, key: %s", err, key)
+			}
+		}).
+		AddMonitorHandler(func(mm *httphandler.MonitorMetric) {
+			key := c.rateLimitKey(mm.Host, mm.Method, mm.Path)
+			if mm.StatusCode == http.StatusTooManyRequests {
+				ratelimit.Get().Throttled(context.Background(), key)
+			}
+			metric.RecordCloudApiSpan(key, mm.Latency, mm.StatusCode)
+		})
+
+	return config.DefaultHttpConfig().WithHttpHandler(handler)
+}
+
+// rateLimitKey huawei 云API的endpoint格式为 {service}.{region}.myhuaweicloud.com，接口名使用服务名、请求方法和路径
+func (c *clientSet) rateLimitKey(host, method, path string) ratelimit.Key {
+	labels := strings.Split(host, ".")
+	region := ""
+	if len(labels) > 3 {
+		region = labels[1]
+	}
+
+	return ratelimit.Key{
+		Vendor:  enumor.HuaWei,
+		Account: c.account,
+		Region:  region,
+		Action:  labels[0] + ":" + ratelimit.ActionOfPath(method, path),
 	}
 }
 
@@ -94,7 +181,7 @@ func (c *clientSet) iamGlobalClient(region *region.Region) (client *iam.IamClien
 		iam.IamClientBuilder().
 			WithRegion(region).
 			WithCredential(c.globalCredentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -111,7 +198,7 @@ func (c *clientSet) iamClient(region *region.Region) (client *iam.IamClient, err
 		iam.IamClientBuilder().
 			WithRegion(region).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -127,7 +214,7 @@ func (c *clientSet) iamRegionClient(region string) (client *iam.IamClient, err e
 		iam.IamClientBuilder().
 			WithRegion(iamregion.ValueOf(region)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -143,7 +230,7 @@ func (c *clientSet) evsClient(region string) (client *evs.EvsClient, err error)
 		evs.EvsClientBuilder().
 			WithRegion(evsregion.ValueOf(region)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -160,7 +247,7 @@ func (c *clientSet) vpcClient(regionID string) (cli *vpc.VpcClient, err error) {
 		vpc.VpcClientBuilder().
 			WithRegion(vpcregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -177,7 +264,7 @@ func (c *clientSet) vpcClientV2(regionID string) (cli *vpcv2.VpcClient, err erro
 		vpcv2.VpcClientBuilder().
 			WithRegion(vpcregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -194,7 +281,7 @@ func (c *clientSet) imsClientV2(region *region.Region) (cli *ims.ImsClient, err
 		ims.ImsClientBuilder().
 			WithRegion(region).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -211,7 +298,7 @@ func (c *clientSet) ecsClient(regionID string) (cli *ecs.EcsClient, err error) {
 		ecs.EcsClientBuilder().
 			WithRegion(ecsregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -228,7 +315,7 @@ func (c *clientSet) dcsClient(regionID string) (cli *dcs.DcsClient, err error) {
 		dcs.DcsClientBuilder().
 			WithRegion(dcsregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -245,7 +332,7 @@ func (c *clientSet) eipClient(regionID string) (cli *eip.EipClient, err error) {
 		eip.EipClientBuilder().
 			WithRegion(eipregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -262,7 +349,7 @@ func (c *clientSet) eipV3Client(regionID string) (cli *eipv3.EipClient, err erro
 		eipv3.EipClientBuilder().
 			WithRegion(eipv3region.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -279,6 +366,7 @@ func (c *clientSet) bssintlClient(regionID string) (cli *bssintl.BssintlClient,
 		bssintl.BssintlClientBuilder().
 			WithRegion(bssintlv2region.ValueOf(regionID)).
 			WithCredential(c.credentials()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -295,6 +383,7 @@ func (c *clientSet) bssintlGlobalClient() (cli *bssintl.BssintlClient, err error
 		bssintl.BssintlClientBuilder().
 			WithRegion(bssintlv2region.ValueOf(bssintlv2region.AP_SOUTHEAST_1.Id)).
 			WithCredential(c.globalCredentials()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -311,7 +400,59 @@ func (c *clientSet) newRmsClient() (cli *rms.RmsClient, err error) {
 		rms.RmsClientBuilder().
 			WithRegion(rmsregion.ValueOf("cn-north-4")).
 			WithCredential(c.globalCredentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
+			Build())
+
+	return client, nil
+}
+
+// tmsClient 标签管理服务是全局服务，使用全局凭证
+func (c *clientSet) tmsClient() (cli *tms.TmsClient, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("huawei error recovered, err: %v", p)
+		}
+	}()
+
+	client := tms.NewTmsClient(
+		tms.TmsClientBuilder().
+			WithRegion(tmsregion.ValueOf(tmsregion.CN_NORTH_4.Id)).
+			WithCredential(c.globalCredentials()).
+			WithHttpConfig(c.httpConfig()).
+			Build())
+
+	return client, nil
+}
+
+func (c *clientSet) ctsClient(regionID string) (cli *cts.CtsClient, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("huawei error recovered, err: %v", p)
+		}
+	}()
+
+	client := cts.NewCtsClient(
+		cts.CtsClientBuilder().
+			WithRegion(ctsregion.ValueOf(regionID)).
+			WithCredential(c.credentials()).
+			WithHttpConfig(c.httpConfig()).
+			Build())
+
+	return client, nil
+}
+
+func (c *clientSet) elbClient(regionID string) (cli *elb.ElbClient, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("huawei error recovered, err: %v", p)
+		}
+	}()
+
+	client := elb.NewElbClient(
+		elb.ElbClientBuilder().
+			WithRegion(elbregion.ValueOf(regionID)).
+			WithCredential(c.credentials()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
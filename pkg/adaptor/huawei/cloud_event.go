@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"time"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3/model"
+)
+
+// ListCloudEvent list cts system trace of the region in option.
+// reference: https://support.huaweicloud.com/api-cts/cts_api_0597.html
+func (h *HuaWei) ListCloudEvent(kt *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei cloud event list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	client, err := h.clientSet.ctsClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &model.ListTracesRequest{
+		TraceType: model.GetListTracesRequestTraceTypeEnum().SYSTEM,
+		Limit:     converter.ValToPtr(int32(cloudevent.HuaWeiListLimit)),
+		From:      converter.ValToPtr(opt.StartTime.UnixMilli()),
+		To:        converter.ValToPtr(opt.EndTime.UnixMilli()),
+	}
+	if len(opt.NextToken) != 0 {
+		req.Next = converter.ValToPtr(opt.NextToken)
+	}
+
+	resp, err := client.ListTraces(req)
+	if err != nil {
+		logs.Errorf("list huawei cts traces failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	result := new(cloudevent.ListResult)
+	if resp.Traces == nil {
+		return result, nil
+	}
+
+	result.Events = make([]cloudevent.CloudEvent, 0, len(*resp.Traces))
+	for _, one := range *resp.Traces {
+		event := cloudevent.CloudEvent{
+			EventID:      converter.PtrToVal(one.TraceId),
+			EventName:    converter.PtrToVal(one.TraceName),
+			Region:       opt.Region,
+			ResourceType: converter.PtrToVal(one.ResourceType),
+			EventTime:    time.UnixMilli(converter.PtrToVal(one.Time)),
+		}
+		if id := converter.PtrToVal(one.ResourceId); len(id) != 0 {
+			event.ResourceIDs = []string{id}
+		}
+		result.Events = append(result.Events, event)
+	}
+
+	// 返回条数小于分页大小时说明已查询完毕
+	if resp.MetaData != nil && len(*resp.Traces) >= cloudevent.HuaWeiListLimit {
+		result.NextToken = converter.PtrToVal(resp.MetaData.Marker)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"hcm/pkg/adaptor/credential"
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/logs"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3/model"
+	iamregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3/region"
+)
+
+// agencyCredential 使用基础凭证通过委托获取临时凭证，临时凭证在多个客户端间共享，过期前自动刷新
+type agencyCredential struct {
+	secret *types.BaseSecret
+	key    string
+	// last 最近一次获取到的临时凭证，sdk 构造客户端时不支持返回错误，刷新失败时继续使用
+	last atomic.Pointer[credential.Temporary]
+}
+
+// newAgencyCredential 创建时获取一次临时凭证，委托失败时直接返回错误
+func newAgencyCredential(secret *types.BaseSecret) (*agencyCredential, error) {
+	role := secret.AssumeRole
+	c := &agencyCredential{
+		secret: secret,
+		key: credential.Key("huawei.agency", secret.CloudSecretID, secret.CloudSecretKey, role.AgencyDomainName,
+			role.AgencyName, role.SessionName),
+	}
+
+	cred, err := credential.Get(c.key, c.assumeAgency)
+	if err != nil {
+		return nil, err
+	}
+	c.last.Store(cred)
+
+	return c, nil
+}
+
+func (c *agencyCredential) get() *credential.Temporary {
+	cred, err := credential.Get(c.key, c.assumeAgency)
+	if err != nil {
+		logs.Errorf("huawei assume agency %s failed, err: %v", c.secret.Identity(), err)
+		return c.last.Load()
+	}
+
+	c.last.Store(cred)
+	return cred
+}
+
+func (c *agencyCredential) assumeAgency() (*credential.Temporary, error) {
+	source, err := newClientSet(&types.BaseSecret{
+		CloudSecretID:  c.secret.CloudSecretID,
+		CloudSecretKey: c.secret.CloudSecretKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := source.iamGlobalClient(iamregion.AP_SOUTHEAST_1)
+	if err != nil {
+		return nil, err
+	}
+
+	role := c.secret.AssumeRole
+	sessionName := credential.DefaultSessionName
+	if len(role.SessionName) != 0 {
+		sessionName = role.SessionName
+	}
+	duration := int32(credential.DefaultDurationSec)
+	if role.DurationSec != 0 {
+		duration = int32(role.DurationSec)
+	}
+
+	req := &model.CreateTemporaryAccessKeyByAgencyRequest{
+		Body: &model.CreateTemporaryAccessKeyByAgencyRequestBody{
+			Auth: &model.AgencyAuth{
+				Identity: &model.AgencyAuthIdentity{
+					Methods: []model.AgencyAuthIdentityMethods{
+						model.GetAgencyAuthIdentityMethodsEnum().ASSUME_ROLE,
+					},
+					AssumeRole: &model.IdentityAssumerole{
+						AgencyName:      role.AgencyName,
+						DomainName:      &role.AgencyDomainName,
+						DurationSeconds: &duration,
+						SessionUser:     &model.AssumeroleSessionuser{Name: &sessionName},
+					},
+				},
+			},
+		},
+	}
+
+	resp, err := client.CreateTemporaryAccessKeyByAgency(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Credential == nil {
+		return nil, errors.New("huawei assume agency returns empty credential")
+	}
+
+	expiration, err := time.Parse(time.RFC3339Nano, resp.Credential.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credential.Temporary{
+		SecretID:   resp.Credential.Access,
+		SecretKey:  resp.Credential.Secret,
+		Token:      resp.Credential.Securitytoken,
+		Expiration: expiration,
+	}, nil
+}
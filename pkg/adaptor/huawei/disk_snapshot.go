@@ -0,0 +1,197 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"strconv"
+	"strings"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// huaWeiSnapshotQueryLimit 华为云快照列表单次查询的最大数量
+const huaWeiSnapshotQueryLimit int32 = 1000
+
+// CreateDiskSnapshot 创建云硬盘快照，云盘挂载的实例处于运行状态时强制创建快照
+func (h *HuaWei) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "huawei disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	req := &model.CreateSnapshotRequest{
+		Body: &model.CreateSnapshotRequestBody{
+			Snapshot: &model.CreateSnapshotOption{
+				VolumeId: opt.CloudDiskID,
+				Name:     converter.ValToPtr(opt.Name),
+				Force:    converter.ValToPtr(true),
+			},
+		},
+	}
+
+	resp, err := client.CreateSnapshot(req)
+	if err != nil {
+		logs.Errorf("create huawei disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	if resp.Snapshot == nil {
+		return "", errf.New(errf.Unknown, "huawei create disk snapshot return empty snapshot")
+	}
+
+	return converter.PtrToVal(resp.Snapshot.Id), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+func (h *HuaWei) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteSnapshot(&model.DeleteSnapshotRequest{SnapshotId: cloudID}); err != nil {
+			logs.Errorf("delete huawei disk snapshot %s failed, err: %v, rid: %s", cloudID, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDiskSnapshot 查询云硬盘快照列表，华为云不支持按多个ID查询，指定ID时查询全部快照后过滤。
+func (h *HuaWei) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := converter.StringSliceToMap(opt.CloudIDs)
+	result := make([]disksnapshot.DiskSnapshot, 0)
+	req := &model.ListSnapshotsRequest{Limit: converter.ValToPtr(huaWeiSnapshotQueryLimit)}
+	for offset := int32(0); ; offset += huaWeiSnapshotQueryLimit {
+		req.Offset = converter.ValToPtr(offset)
+
+		resp, err := client.ListSnapshots(req)
+		if err != nil {
+			logs.Errorf("list huawei disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		snapshots := converter.PtrToVal(resp.Snapshots)
+		for _, one := range snapshots {
+			if len(idMap) != 0 {
+				if _, exist := idMap[one.Id]; !exist {
+					continue
+				}
+			}
+			result = append(result, convHuaWeiDiskSnapshot(one))
+		}
+
+		if len(snapshots) < int(huaWeiSnapshotQueryLimit) {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func convHuaWeiDiskSnapshot(one model.SnapshotList) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:        one.Id,
+		Name:           converter.PtrToVal(one.Name),
+		CloudDiskID:    one.VolumeId,
+		DiskSize:       uint64(one.Size),
+		Status:         one.Status,
+		CloudCreatedAt: one.CreatedAt,
+		Extension:      new(corecloud.DiskSnapshotExtension),
+	}
+
+	// progress 格式如 "100%"
+	progress, err := strconv.ParseInt(strings.TrimSuffix(one.OsExtendedSnapshotAttributesprogress, "%"), 10, 64)
+	if err == nil {
+		snapshot.Extension.Progress = converter.ValToPtr(progress)
+	}
+
+	return snapshot
+}
+
+// RollbackDiskSnapshot 使用快照回滚云硬盘，云盘需处于未挂载状态
+func (h *HuaWei) RollbackDiskSnapshot(kt *kit.Kit, opt *disksnapshot.RollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &model.RollbackSnapshotRequest{
+		SnapshotId: opt.CloudID,
+		Body: &model.RollbackSnapshotRequestBody{
+			Rollback: &model.RollbackSnapshotOption{VolumeId: opt.CloudDiskID},
+		},
+	}
+	if _, err = client.RollbackSnapshot(req); err != nil {
+		logs.Errorf("rollback huawei disk %s with snapshot %s failed, err: %v, rid: %s", opt.CloudDiskID,
+			opt.CloudID, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -42,7 +42,12 @@ func NewHuaWei(s *types.BaseSecret) (*HuaWei, error) {
 	if err := validateSecret(s); err != nil {
 		return nil, err
 	}
-	return &HuaWei{clientSet: newClientSet(s)}, nil
+	clientSet, err := newClientSet(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HuaWei{clientSet: clientSet}, nil
 }
 
 // HuaWei is huawei operator.
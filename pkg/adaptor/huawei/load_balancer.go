@@ -0,0 +1,737 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+	"strings"
+
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	elb "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/elb/v3"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/elb/v3/model"
+)
+
+// ListLoadBalancer 查询负载均衡
+// reference: https://support.huaweicloud.com/api-elb/ListLoadBalancers.html
+func (h *HuaWei) ListLoadBalancer(kt *kit.Kit, opt *typelb.HuaWeiListOption) (*typelb.HuaWeiListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.ListLoadBalancersRequest{Marker: opt.Marker, Limit: opt.Limit}
+	if len(opt.CloudIDs) != 0 {
+		req.Id = cvt.ValToPtr(opt.CloudIDs)
+	}
+
+	resp, err := client.ListLoadBalancers(req)
+	if err != nil {
+		logs.Errorf("list huawei load balancer failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	lbs := cvt.PtrToVal(resp.Loadbalancers)
+	details := make([]typelb.HuaWeiLoadBalancer, 0, len(lbs))
+	for i := range lbs {
+		details = append(details, typelb.HuaWeiLoadBalancer{LoadBalancer: &lbs[i]})
+	}
+
+	result := &typelb.HuaWeiListResult{Details: details}
+	if resp.PageInfo != nil {
+		result.NextMarker = resp.PageInfo.NextMarker
+	}
+	return result, nil
+}
+
+// CreateLoadBalancer 创建独享型负载均衡，返回负载均衡ID
+// reference: https://support.huaweicloud.com/api-elb/CreateLoadBalancer.html
+func (h *HuaWei) CreateLoadBalancer(kt *kit.Kit, opt *typelb.HuaWeiCreateLoadBalancerOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	lbOpt := &model.CreateLoadBalancerOption{
+		Name:                     cvt.ValToPtr(opt.Name),
+		VpcId:                    cvt.ValToPtr(opt.CloudVpcID),
+		VipSubnetCidrId:          opt.CloudVipSubnetID,
+		AvailabilityZoneList:     opt.AvailabilityZones,
+		L4FlavorId:               opt.L4FlavorID,
+		L7FlavorId:               opt.L7FlavorID,
+		DeletionProtectionEnable: opt.DeletionProtection,
+	}
+	if len(opt.CloudElbVirsubnetIDs) != 0 {
+		lbOpt.ElbVirsubnetIds = cvt.ValToPtr(opt.CloudElbVirsubnetIDs)
+	}
+	if len(opt.CloudEipIDs) != 0 {
+		lbOpt.PublicipIds = cvt.ValToPtr(opt.CloudEipIDs)
+	}
+	if len(opt.Tags) != 0 {
+		tags := make([]model.Tag, 0, len(opt.Tags))
+		for _, tag := range opt.Tags {
+			tags = append(tags, model.Tag{Key: cvt.ValToPtr(tag.Key), Value: cvt.ValToPtr(tag.Value)})
+		}
+		lbOpt.Tags = &tags
+	}
+
+	req := &model.CreateLoadBalancerRequest{Body: &model.CreateLoadBalancerRequestBody{Loadbalancer: lbOpt}}
+	resp, err := client.CreateLoadBalancer(req)
+	if err != nil {
+		logs.Errorf("create huawei load balancer failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+
+	if resp.LoadbalancerId != nil {
+		return cvt.PtrToVal(resp.LoadbalancerId), nil
+	}
+	if resp.Loadbalancer != nil {
+		return resp.Loadbalancer.Id, nil
+	}
+	return "", errf.New(errf.Aborted, "create huawei load balancer return empty id")
+}
+
+// UpdateLoadBalancer 更新负载均衡
+// reference: https://support.huaweicloud.com/api-elb/UpdateLoadBalancer.html
+func (h *HuaWei) UpdateLoadBalancer(kt *kit.Kit, opt *typelb.HuaWeiUpdateLoadBalancerOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.UpdateLoadBalancerRequest{
+		LoadbalancerId: opt.CloudID,
+		Body: &model.UpdateLoadBalancerRequestBody{Loadbalancer: &model.UpdateLoadBalancerOption{
+			Name:                     opt.Name,
+			Description:              opt.Description,
+			DeletionProtectionEnable: opt.DeletionProtection,
+			IpTargetEnable:           opt.IpTargetEnable,
+		}},
+	}
+	if _, err = client.UpdateLoadBalancer(req); err != nil {
+		logs.Errorf("update huawei load balancer failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteLoadBalancer 删除负载均衡，需先删除其下的监听器及后端服务器组
+// reference: https://support.huaweicloud.com/api-elb/DeleteLoadBalancer.html
+func (h *HuaWei) DeleteLoadBalancer(kt *kit.Kit, opt *typelb.HuaWeiDeleteOption) error {
+	client, err := h.elbClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteLoadBalancer(&model.DeleteLoadBalancerRequest{LoadbalancerId: cloudID}); err != nil {
+			logs.Errorf("delete huawei load balancer failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListListener 查询负载均衡下的监听器
+// reference: https://support.huaweicloud.com/api-elb/ListListeners.html
+func (h *HuaWei) ListListener(kt *kit.Kit, opt *typelb.HuaWeiListListenersOption) ([]typelb.HuaWeiListener, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.ListListenersRequest{
+		LoadbalancerId: cvt.ValToPtr([]string{opt.CloudLoadBalancerID}),
+		Limit:          cvt.ValToPtr(int32(typelb.HuaWeiDescribeLimit)),
+	}
+	if len(opt.CloudIDs) != 0 {
+		req.Id = cvt.ValToPtr(opt.CloudIDs)
+	}
+
+	result := make([]typelb.HuaWeiListener, 0)
+	for {
+		resp, err := client.ListListeners(req)
+		if err != nil {
+			logs.Errorf("list huawei listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		listeners := cvt.PtrToVal(resp.Listeners)
+		for i := range listeners {
+			result = append(result, typelb.HuaWeiListener{Region: opt.Region, Listener: &listeners[i]})
+		}
+		if len(listeners) < typelb.HuaWeiDescribeLimit || resp.PageInfo == nil || resp.PageInfo.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.PageInfo.NextMarker
+	}
+
+	return result, nil
+}
+
+// CreateListener 创建监听器，返回监听器ID
+// reference: https://support.huaweicloud.com/api-elb/CreateListener.html
+func (h *HuaWei) CreateListener(kt *kit.Kit, opt *typelb.HuaWeiCreateListenerOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	lblOpt := &model.CreateListenerOption{
+		LoadbalancerId:         opt.CloudLoadBalancerID,
+		Protocol:               string(opt.Protocol),
+		ProtocolPort:           opt.Port,
+		DefaultPoolId:          opt.CloudDefaultPoolID,
+		DefaultTlsContainerRef: opt.DefaultTlsContainerRef,
+	}
+	if len(opt.Name) != 0 {
+		lblOpt.Name = cvt.ValToPtr(opt.Name)
+	}
+
+	req := &model.CreateListenerRequest{Body: &model.CreateListenerRequestBody{Listener: lblOpt}}
+	resp, err := client.CreateListener(req)
+	if err != nil {
+		logs.Errorf("create huawei listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if resp.Listener == nil {
+		return "", errf.New(errf.Aborted, "create huawei listener return empty result")
+	}
+	return resp.Listener.Id, nil
+}
+
+// UpdateListener 更新监听器名称、默认后端服务器组及证书
+// reference: https://support.huaweicloud.com/api-elb/UpdateListener.html
+func (h *HuaWei) UpdateListener(kt *kit.Kit, opt *typelb.HuaWeiUpdateListenerOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.UpdateListenerRequest{
+		ListenerId: opt.CloudID,
+		Body: &model.UpdateListenerRequestBody{Listener: &model.UpdateListenerOption{
+			Name:                   opt.Name,
+			DefaultPoolId:          opt.CloudDefaultPoolID,
+			DefaultTlsContainerRef: opt.DefaultTlsContainerRef,
+		}},
+	}
+	if _, err = client.UpdateListener(req); err != nil {
+		logs.Errorf("update huawei listener failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteListener 删除监听器
+// reference: https://support.huaweicloud.com/api-elb/DeleteListener.html
+func (h *HuaWei) DeleteListener(kt *kit.Kit, opt *typelb.HuaWeiDeleteOption) error {
+	client, err := h.elbClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteListener(&model.DeleteListenerRequest{ListenerId: cloudID}); err != nil {
+			logs.Errorf("delete huawei listener failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListL7Policy 查询监听器下的转发策略，并补充策略下的转发规则
+// reference: https://support.huaweicloud.com/api-elb/ListL7Policies.html
+func (h *HuaWei) ListL7Policy(kt *kit.Kit, opt *typelb.HuaWeiListL7PoliciesOption) ([]typelb.HuaWeiL7Policy, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.ListL7PoliciesRequest{
+		ListenerId: cvt.ValToPtr([]string{opt.CloudListenerID}),
+		Limit:      cvt.ValToPtr(int32(typelb.HuaWeiDescribeLimit)),
+	}
+	resp, err := client.ListL7Policies(req)
+	if err != nil {
+		logs.Errorf("list huawei l7 policy failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	policies := cvt.PtrToVal(resp.L7policies)
+	result := make([]typelb.HuaWeiL7Policy, 0, len(policies))
+	for i := range policies {
+		rulesResp, err := client.ListL7Rules(&model.ListL7RulesRequest{L7policyId: policies[i].Id})
+		if err != nil {
+			logs.Errorf("list huawei l7 rule failed, err: %v, policy: %s, rid: %s", err, policies[i].Id, kt.Rid)
+			return nil, err
+		}
+		result = append(result, typelb.HuaWeiL7Policy{L7Policy: &policies[i], Rules: cvt.PtrToVal(rulesResp.Rules)})
+	}
+
+	return result, nil
+}
+
+// CreateL7Policy 创建转发至后端服务器组的转发策略，返回策略ID
+// reference: https://support.huaweicloud.com/api-elb/CreateL7Policy.html
+func (h *HuaWei) CreateL7Policy(kt *kit.Kit, opt *typelb.HuaWeiCreateL7PolicyOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	rules := make([]model.CreateL7PolicyRuleOption, 0, 2)
+	if len(opt.Domain) != 0 {
+		rules = append(rules, model.CreateL7PolicyRuleOption{Type: "HOST_NAME", CompareType: "EQUAL_TO", Value: opt.Domain})
+	}
+	if len(opt.URL) != 0 {
+		rules = append(rules, model.CreateL7PolicyRuleOption{Type: "PATH", CompareType: "STARTS_WITH", Value: opt.URL})
+	}
+	policyOpt := &model.CreateL7PolicyOption{
+		Action:         "REDIRECT_TO_POOL",
+		ListenerId:     opt.CloudListenerID,
+		RedirectPoolId: cvt.ValToPtr(opt.CloudPoolID),
+		Rules:          &rules,
+	}
+	if len(opt.Name) != 0 {
+		policyOpt.Name = cvt.ValToPtr(opt.Name)
+	}
+
+	req := &model.CreateL7PolicyRequest{Body: &model.CreateL7PolicyRequestBody{L7policy: policyOpt}}
+	resp, err := client.CreateL7Policy(req)
+	if err != nil {
+		logs.Errorf("create huawei l7 policy failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if resp.L7policy == nil {
+		return "", errf.New(errf.Aborted, "create huawei l7 policy return empty result")
+	}
+	return resp.L7policy.Id, nil
+}
+
+// UpdateL7Policy 更新转发策略，指定域名或路径时整体替换策略下的转发规则
+// reference: https://support.huaweicloud.com/api-elb/UpdateL7Policy.html
+func (h *HuaWei) UpdateL7Policy(kt *kit.Kit, opt *typelb.HuaWeiUpdateL7PolicyOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	policyOpt := &model.UpdateL7PolicyOption{Name: opt.Name, RedirectPoolId: opt.CloudPoolID}
+	if len(opt.Domain) != 0 || len(opt.URL) != 0 {
+		rules := make([]model.CreateRuleOption, 0, 2)
+		if len(opt.Domain) != 0 {
+			rules = append(rules, model.CreateRuleOption{Type: "HOST_NAME", CompareType: "EQUAL_TO", Value: opt.Domain})
+		}
+		if len(opt.URL) != 0 {
+			rules = append(rules, model.CreateRuleOption{Type: "PATH", CompareType: "STARTS_WITH", Value: opt.URL})
+		}
+		policyOpt.Rules = &rules
+	}
+
+	req := &model.UpdateL7PolicyRequest{
+		L7policyId: opt.CloudID,
+		Body:       &model.UpdateL7PolicyRequestBody{L7policy: policyOpt},
+	}
+	if _, err = client.UpdateL7Policy(req); err != nil {
+		logs.Errorf("update huawei l7 policy failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// DeleteL7Policy 删除转发策略
+// reference: https://support.huaweicloud.com/api-elb/DeleteL7Policy.html
+func (h *HuaWei) DeleteL7Policy(kt *kit.Kit, opt *typelb.HuaWeiDeleteOption) error {
+	client, err := h.elbClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteL7Policy(&model.DeleteL7PolicyRequest{L7policyId: cloudID}); err != nil {
+			logs.Errorf("delete huawei l7 policy failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPool 查询后端服务器组
+// reference: https://support.huaweicloud.com/api-elb/ListPools.html
+func (h *HuaWei) ListPool(kt *kit.Kit, opt *typelb.HuaWeiListPoolsOption) ([]typelb.HuaWeiPool, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.ListPoolsRequest{Limit: cvt.ValToPtr(int32(typelb.HuaWeiDescribeLimit))}
+	if len(opt.CloudLoadBalancerID) != 0 {
+		req.LoadbalancerId = cvt.ValToPtr([]string{opt.CloudLoadBalancerID})
+	}
+	if len(opt.CloudIDs) != 0 {
+		req.Id = cvt.ValToPtr(opt.CloudIDs)
+	}
+
+	result := make([]typelb.HuaWeiPool, 0)
+	for {
+		resp, err := client.ListPools(req)
+		if err != nil {
+			logs.Errorf("list huawei pool failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		pools := cvt.PtrToVal(resp.Pools)
+		for i := range pools {
+			result = append(result, typelb.HuaWeiPool{Pool: &pools[i]})
+		}
+		if len(pools) < typelb.HuaWeiDescribeLimit || resp.PageInfo == nil || resp.PageInfo.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.PageInfo.NextMarker
+	}
+
+	return result, nil
+}
+
+// CreatePool 创建后端服务器组，指定健康检查时一并创建健康检查，返回后端服务器组ID
+// reference: https://support.huaweicloud.com/api-elb/CreatePool.html
+func (h *HuaWei) CreatePool(kt *kit.Kit, opt *typelb.HuaWeiCreatePoolOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	poolOpt := &model.CreatePoolOption{
+		Protocol:       string(opt.Protocol),
+		LbAlgorithm:    opt.LbAlgorithm,
+		LoadbalancerId: opt.CloudLoadBalancerID,
+		ListenerId:     opt.CloudListenerID,
+		VpcId:          opt.CloudVpcID,
+	}
+	if len(opt.Name) != 0 {
+		poolOpt.Name = cvt.ValToPtr(opt.Name)
+	}
+
+	resp, err := client.CreatePool(&model.CreatePoolRequest{Body: &model.CreatePoolRequestBody{Pool: poolOpt}})
+	if err != nil {
+		logs.Errorf("create huawei pool failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return "", err
+	}
+	if resp.Pool == nil {
+		return "", errf.New(errf.Aborted, "create huawei pool return empty result")
+	}
+
+	if hc := opt.HealthCheck; hc != nil {
+		monitorOpt := &model.CreateHealthMonitorOption{
+			AdminStateUp:   hc.AdminStateUp,
+			Delay:          cvt.PtrToVal(hc.Delay),
+			DomainName:     hc.DomainName,
+			ExpectedCodes:  hc.ExpectedCodes,
+			HttpMethod:     hc.HttpMethod,
+			MaxRetries:     cvt.PtrToVal(hc.MaxRetries),
+			MaxRetriesDown: hc.MaxRetriesDown,
+			MonitorPort:    hc.MonitorPort,
+			PoolId:         resp.Pool.Id,
+			Timeout:        cvt.PtrToVal(hc.Timeout),
+			Type:           cvt.PtrToVal(hc.Type),
+			UrlPath:        hc.UrlPath,
+		}
+		monitorReq := &model.CreateHealthMonitorRequest{
+			Body: &model.CreateHealthMonitorRequestBody{Healthmonitor: monitorOpt},
+		}
+		if _, err = client.CreateHealthMonitor(monitorReq); err != nil {
+			logs.Errorf("create huawei health monitor failed, err: %v, pool: %s, rid: %s", err, resp.Pool.Id, kt.Rid)
+			return resp.Pool.Id, err
+		}
+	}
+
+	return resp.Pool.Id, nil
+}
+
+// UpdatePool 更新后端服务器组名称、负载均衡算法及健康检查
+// reference: https://support.huaweicloud.com/api-elb/UpdatePool.html
+func (h *HuaWei) UpdatePool(kt *kit.Kit, opt *typelb.HuaWeiUpdatePoolOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "update option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	if opt.Name != nil || opt.LbAlgorithm != nil {
+		req := &model.UpdatePoolRequest{
+			PoolId: opt.CloudID,
+			Body: &model.UpdatePoolRequestBody{Pool: &model.UpdatePoolOption{
+				Name:        opt.Name,
+				LbAlgorithm: opt.LbAlgorithm,
+			}},
+		}
+		if _, err = client.UpdatePool(req); err != nil {
+			logs.Errorf("update huawei pool failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+	}
+
+	if hc := opt.HealthCheck; hc != nil {
+		// http_method 为枚举类型，暂不支持修改
+		monitorOpt := &model.UpdateHealthMonitorOption{
+			AdminStateUp:   hc.AdminStateUp,
+			Delay:          hc.Delay,
+			DomainName:     hc.DomainName,
+			ExpectedCodes:  hc.ExpectedCodes,
+			MaxRetries:     hc.MaxRetries,
+			MaxRetriesDown: hc.MaxRetriesDown,
+			MonitorPort:    hc.MonitorPort,
+			Timeout:        hc.Timeout,
+			UrlPath:        hc.UrlPath,
+			Type:           hc.Type,
+		}
+		req := &model.UpdateHealthMonitorRequest{
+			HealthmonitorId: cvt.PtrToVal(opt.CloudHealthMonitorID),
+			Body:            &model.UpdateHealthMonitorRequestBody{Healthmonitor: monitorOpt},
+		}
+		if _, err = client.UpdateHealthMonitor(req); err != nil {
+			logs.Errorf("update huawei health monitor failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeletePool 删除后端服务器组
+// reference: https://support.huaweicloud.com/api-elb/DeletePool.html
+func (h *HuaWei) DeletePool(kt *kit.Kit, opt *typelb.HuaWeiDeleteOption) error {
+	client, err := h.elbClientForDelete(opt)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeletePool(&model.DeletePoolRequest{PoolId: cloudID}); err != nil {
+			logs.Errorf("delete huawei pool failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// ListMember 查询后端服务器组下的后端服务器
+// reference: https://support.huaweicloud.com/api-elb/ListMembers.html
+func (h *HuaWei) ListMember(kt *kit.Kit, opt *typelb.HuaWeiListMembersOption) ([]typelb.HuaWeiMember, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	req := &model.ListMembersRequest{PoolId: opt.CloudPoolID, Limit: cvt.ValToPtr(int32(typelb.HuaWeiDescribeLimit))}
+	result := make([]typelb.HuaWeiMember, 0)
+	for {
+		resp, err := client.ListMembers(req)
+		if err != nil {
+			if strings.Contains(err.Error(), ErrDataNotFound) {
+				return result, nil
+			}
+			logs.Errorf("list huawei member failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+		members := cvt.PtrToVal(resp.Members)
+		for i := range members {
+			result = append(result, typelb.HuaWeiMember{Member: &members[i]})
+		}
+		if len(members) < typelb.HuaWeiDescribeLimit || resp.PageInfo == nil || resp.PageInfo.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.PageInfo.NextMarker
+	}
+
+	return result, nil
+}
+
+// BatchCreateMember 批量添加后端服务器
+// reference: https://support.huaweicloud.com/api-elb/BatchCreateMembers.html
+func (h *HuaWei) BatchCreateMember(kt *kit.Kit, opt *typelb.HuaWeiMembersOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "create option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	members := slice.Map(opt.Members, func(m typelb.HuaWeiMemberOption) model.BatchCreateMembersOption {
+		return model.BatchCreateMembersOption{
+			Address:      m.Address,
+			ProtocolPort: m.Port,
+			Weight:       m.Weight,
+			SubnetCidrId: m.CloudSubnetID,
+		}
+	})
+	req := &model.BatchCreateMembersRequest{
+		PoolId: opt.CloudPoolID,
+		Body:   &model.BatchCreateMembersRequestBody{Members: members},
+	}
+	if _, err = client.BatchCreateMembers(req); err != nil {
+		logs.Errorf("batch create huawei member failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// BatchDeleteMember 批量移除后端服务器
+// reference: https://support.huaweicloud.com/api-elb/BatchDeleteMembers.html
+func (h *HuaWei) BatchDeleteMember(kt *kit.Kit, opt *typelb.HuaWeiMembersOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "delete option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+
+	members := slice.Map(opt.Members, func(m typelb.HuaWeiMemberOption) model.BatchDeleteMembersOption {
+		return model.BatchDeleteMembersOption{Address: cvt.ValToPtr(m.Address), ProtocolPort: cvt.ValToPtr(m.Port)}
+	})
+	req := &model.BatchDeleteMembersRequest{
+		PoolId: opt.CloudPoolID,
+		Body:   &model.BatchDeleteMembersRequestBody{Members: members},
+	}
+	if _, err = client.BatchDeleteMembers(req); err != nil {
+		logs.Errorf("batch delete huawei member failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+func (h *HuaWei) elbClientForDelete(opt *typelb.HuaWeiDeleteOption) (*elb.ElbClient, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "delete option is required")
+	}
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.elbClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei elb client failed, err: %v", err)
+	}
+	return client, nil
+}
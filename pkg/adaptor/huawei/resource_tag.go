@@ -0,0 +1,213 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	apicore "hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	tms "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/tms/v1"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/tms/v1/model"
+)
+
+// tmsResTypes 资源类型对应的标签管理服务(TMS)资源类型
+// reference: https://support.huaweicloud.com/api-tms/tms_api_0008.html
+var tmsResTypes = map[enumor.CloudResourceType]string{
+	enumor.CvmCloudResType:           "ecs",
+	enumor.DiskCloudResType:          "disk",
+	enumor.EipCloudResType:           "eip",
+	enumor.SecurityGroupCloudResType: "security-groups",
+	enumor.VpcCloudResType:           "vpcs",
+	enumor.SubnetCloudResType:        "subnets",
+}
+
+// TagResources 为资源批量添加标签，标签键已存在时覆盖标签值
+// reference: https://support.huaweicloud.com/api-tms/tms_api_0009.html
+func (h *HuaWei) TagResources(kt *kit.Kit, opt *restag.TagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei tag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, projectID, resType, err := h.prepareTms(kt, opt.Region, opt.ResType)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]model.CreateTagRequest, 0, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags = append(tags, model.CreateTagRequest{Key: tag.Key, Value: tag.Value})
+	}
+
+	for _, batch := range slice.Split(opt.CloudIDs, restag.HuaWeiTagResLimit) {
+		req := &model.CreateResourceTagRequest{
+			Body: &model.ReqCreateTag{
+				ProjectId: converter.ValToPtr(projectID),
+				Resources: tmsResources(resType, batch),
+				Tags:      tags,
+			},
+		}
+		resp, err := client.CreateResourceTag(req)
+		if err != nil {
+			logs.Errorf("create huawei resource tags failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+
+		if resp.FailedResources != nil && len(*resp.FailedResources) > 0 {
+			failed := (*resp.FailedResources)[0]
+			logs.Errorf("create huawei resource tags partially failed, failed: %+v, rid: %s", *resp.FailedResources,
+				kt.Rid)
+			return fmt.Errorf("create %s tags failed, code: %s, msg: %s", failed.ResourceId, failed.ErrorCode,
+				failed.ErrorMsg)
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 批量删除资源的标签
+// reference: https://support.huaweicloud.com/api-tms/tms_api_0010.html
+func (h *HuaWei) UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei untag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, projectID, resType, err := h.prepareTms(kt, opt.Region, opt.ResType)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]model.DeleteTagRequest, 0, len(opt.TagKeys))
+	for _, key := range opt.TagKeys {
+		tags = append(tags, model.DeleteTagRequest{Key: key})
+	}
+
+	for _, batch := range slice.Split(opt.CloudIDs, restag.HuaWeiTagResLimit) {
+		req := &model.DeleteResourceTagRequest{
+			Body: &model.ReqDeleteTag{
+				ProjectId: converter.ValToPtr(projectID),
+				Resources: tmsResources(resType, batch),
+				Tags:      tags,
+			},
+		}
+		resp, err := client.DeleteResourceTag(req)
+		if err != nil {
+			logs.Errorf("delete huawei resource tags failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+
+		if resp.FailedResources != nil && len(*resp.FailedResources) > 0 {
+			failed := (*resp.FailedResources)[0]
+			logs.Errorf("delete huawei resource tags partially failed, failed: %+v, rid: %s", *resp.FailedResources,
+				kt.Rid)
+			return fmt.Errorf("delete %s tags failed, code: %s, msg: %s", failed.ResourceId, failed.ErrorCode,
+				failed.ErrorMsg)
+		}
+	}
+
+	return nil
+}
+
+// ListResourceTags 查询资源的标签，用于列表接口不返回标签的资源，返回资源云ID到标签的映射，没有标签的资源对应空列表
+// reference: https://support.huaweicloud.com/api-tms/tms_api_0011.html
+func (h *HuaWei) ListResourceTags(kt *kit.Kit, opt *restag.ListResTagOption) (map[string][]apicore.TagPair, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei list resource tags option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, projectID, resType, err := h.prepareTms(kt, opt.Region, opt.ResType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]apicore.TagPair, len(opt.CloudIDs))
+	for _, id := range opt.CloudIDs {
+		req := &model.ShowResourceTagRequest{
+			ResourceId:   id,
+			ProjectId:    converter.ValToPtr(projectID),
+			ResourceType: resType,
+		}
+		resp, err := client.ShowResourceTag(req)
+		if err != nil {
+			logs.Errorf("show huawei resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return nil, err
+		}
+
+		tags := make([]apicore.TagPair, 0, len(converter.PtrToVal(resp.Tags)))
+		for _, tag := range converter.PtrToVal(resp.Tags) {
+			tags = append(tags, apicore.TagPair{Key: tag.Key, Value: converter.PtrToVal(tag.Value)})
+		}
+		result[id] = tags
+	}
+
+	return result, nil
+}
+
+// prepareTms 返回 TMS 客户端、资源所在地域的项目ID以及 TMS 资源类型
+func (h *HuaWei) prepareTms(kt *kit.Kit, region string, resType enumor.CloudResourceType) (*tms.TmsClient,
+	string, string, error) {
+
+	tmsResType, ok := tmsResTypes[resType]
+	if !ok {
+		return nil, "", "", errf.Newf(errf.InvalidParameter, "huawei tag does not support resource type: %s",
+			resType)
+	}
+
+	// 区域级服务的资源需要指定项目ID
+	projectID, err := h.GetProjectID(kt, region)
+	if err != nil {
+		logs.Errorf("get huawei project id failed, err: %v, region: %s, rid: %s", err, region, kt.Rid)
+		return nil, "", "", err
+	}
+
+	client, err := h.clientSet.tmsClient()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("new tms client failed, err: %v", err)
+	}
+
+	return client, projectID, tmsResType, nil
+}
+
+func tmsResources(resType string, cloudIDs []string) []model.ResourceTagBody {
+	resources := make([]model.ResourceTagBody, 0, len(cloudIDs))
+	for _, id := range cloudIDs {
+		resources = append(resources, model.ResourceTagBody{ResourceId: id, ResourceType: resType})
+	}
+	return resources
+}
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/adaptor/poller"
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
@@ -235,6 +236,11 @@ func convertVpc(data *model.Vpc, region string) *types.HuaWeiVpc {
 			Status:              data.Status,
 			EnterpriseProjectId: data.EnterpriseProjectId,
 		},
+		Tags: make([]apicore.TagPair, 0, len(data.Tags)),
+	}
+
+	for _, tag := range data.Tags {
+		v.Tags = append(v.Tags, apicore.TagPair{Key: tag.Key, Value: tag.Value})
 	}
 
 	if data.Cidr != "" {
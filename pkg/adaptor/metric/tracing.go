@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package metric
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"hcm/pkg/adaptor/ratelimit"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartCloudApiSpan 为云API调用创建子span，key 为云API的限流维度，包含云厂商、账号、地域和接口名
+func StartCloudApiSpan(ctx context.Context, key ratelimit.Key, opts ...trace.SpanStartOption) (context.Context,
+	trace.Span) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rid, _ := ctx.Value(constant.RidKey).(string)
+
+	opts = append(opts, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		tracing.RidKey.String(rid),
+		attribute.String("cloud.provider", string(key.Vendor)),
+		attribute.String("cloud.account", key.Account),
+		attribute.String("cloud.region", key.Region),
+		attribute.String("cloud.api", key.Action),
+	))
+
+	return tracing.Start(ctx, "cloud-api "+key.Action, opts...)
+}
+
+// EndCloudApiSpan 结束云API调用span，记录http状态码，状态码不小于400时将span状态置为失败
+func EndCloudApiSpan(span trace.Span, statusCode int, err error, opts ...trace.SpanEndOption) {
+	if statusCode != 0 {
+		span.SetAttributes(attribute.Int("http.status_code", statusCode))
+		if err == nil && statusCode >= http.StatusBadRequest {
+			err = errors.New(http.StatusText(statusCode))
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End(opts...)
+}
+
+// NewTracingRoundTripper 为每个云API http请求创建子span，用于支持自定义 http.RoundTripper 的云厂商SDK
+func NewTracingRoundTripper(next http.RoundTripper, keyFunc ratelimit.KeyFunc) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &tracingRoundTripper{next: next, keyFunc: keyFunc}
+}
+
+type tracingRoundTripper struct {
+	next    http.RoundTripper
+	keyFunc ratelimit.KeyFunc
+}
+
+// RoundTrip ...
+func (rt *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := StartCloudApiSpan(req.Context(), rt.keyFunc(req))
+
+	resp, err := rt.next.RoundTrip(req.WithContext(ctx))
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	EndCloudApiSpan(span, statusCode, err)
+
+	return resp, err
+}
+
+// RecordCloudApiSpan 根据已完成的云API调用的耗时补记span，用于无法传递 context 的云厂商SDK，span 无法关联到上游调用链
+func RecordCloudApiSpan(key ratelimit.Key, latency time.Duration, statusCode int) {
+	end := time.Now()
+	_, span := StartCloudApiSpan(context.Background(), key, trace.WithTimestamp(end.Add(-latency)))
+	EndCloudApiSpan(span, statusCode, nil, trace.WithTimestamp(end))
+}
@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket 令牌桶状态，多个副本通过存储共享
+type Bucket struct {
+	// Tokens 当前剩余令牌数
+	Tokens float64 `json:"tokens"`
+	// Rate 当前生效的QPS，被限流后乘性降低，随时间加性恢复到配额QPS
+	Rate float64 `json:"rate"`
+	// UpdatedAt 上次更新时间，unix 毫秒
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// refill 按流逝的时间补充令牌，并加性恢复速率
+func (b *Bucket) refill(quota Quota, aimd AIMD, now time.Time) {
+	nowMS := now.UnixMilli()
+	if b.UpdatedAt == 0 || b.Rate <= 0 {
+		b.Tokens, b.Rate, b.UpdatedAt = float64(quota.Burst), quota.QPS, nowMS
+		return
+	}
+
+	elapsed := math.Max(float64(nowMS-b.UpdatedAt)/1000, 0)
+	b.Tokens = math.Min(float64(quota.Burst), b.Tokens+elapsed*b.Rate)
+	b.Rate = math.Min(quota.QPS, b.Rate+elapsed*aimd.IncreaseStep)
+	if nowMS > b.UpdatedAt {
+		b.UpdatedAt = nowMS
+	}
+}
+
+// Take 获取一个令牌，获取成功返回0，令牌不足时返回需要等待的时长
+func (b *Bucket) Take(quota Quota, aimd AIMD, now time.Time) time.Duration {
+	_, wait := b.TakeN(quota, aimd, now, 1)
+	return wait
+}
+
+// TakeN 最多获取n个令牌，有可用令牌时返回实际获取的令牌数，不会等待凑满n个；令牌不足一个时返回需要等待的时长
+func (b *Bucket) TakeN(quota Quota, aimd AIMD, now time.Time, n int) (int, time.Duration) {
+	b.refill(quota, aimd, now)
+	if b.Tokens >= 1 {
+		got := int(math.Min(float64(n), math.Floor(b.Tokens)))
+		if got < 1 {
+			got = 1
+		}
+		b.Tokens -= float64(got)
+		return got, 0
+	}
+
+	wait := time.Duration((1 - b.Tokens) / b.Rate * float64(time.Second))
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+
+	return 0, wait
+}
+
+// Throttle 被云厂商限流，乘性降低速率并清空令牌
+func (b *Bucket) Throttle(quota Quota, aimd AIMD, now time.Time) {
+	b.refill(quota, aimd, now)
+	b.Rate = math.Max(aimd.MinQPS, b.Rate*aimd.DecreaseFactor)
+	b.Tokens = math.Min(b.Tokens, 0)
+}
@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketTakeAndThrottle(t *testing.T) {
+	quota := Quota{QPS: 10, Burst: 2}
+	aimd := AIMD{IncreaseStep: 1, DecreaseFactor: 0.5, MinQPS: 1}
+	now := time.Now()
+
+	b := new(Bucket)
+	assert.Zero(t, b.Take(quota, aimd, now))
+	assert.Zero(t, b.Take(quota, aimd, now))
+	// 令牌耗尽，按10QPS需要等待100ms
+	assert.Equal(t, 100*time.Millisecond, b.Take(quota, aimd, now))
+
+	b.Throttle(quota, aimd, now)
+	assert.Equal(t, float64(5), b.Rate)
+	// 降速后需要等待200ms
+	assert.Equal(t, 200*time.Millisecond, b.Take(quota, aimd, now))
+
+	// 2s后速率加性恢复到7，令牌补满
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, b.Take(quota, aimd, now))
+	assert.Equal(t, float64(7), b.Rate)
+
+	// 速率恢复不超过配额
+	now = now.Add(10 * time.Second)
+	b.Take(quota, aimd, now)
+	assert.Equal(t, quota.QPS, b.Rate)
+
+	// 速率降低不低于最小值
+	for i := 0; i < 10; i++ {
+		b.Throttle(quota, aimd, now)
+	}
+	assert.Equal(t, aimd.MinQPS, b.Rate)
+}
+
+func TestOptionQuotaOf(t *testing.T) {
+	opt := &Option{
+		Default: Quota{QPS: 20, Burst: 20},
+		Rules: []Rule{
+			{Vendor: enumor.TCloud, Quota: Quota{QPS: 15, Burst: 15}},
+			{Vendor: enumor.TCloud, Action: "DescribeInstances", Quota: Quota{QPS: 10, Burst: 10}},
+			{Vendor: enumor.TCloud, Region: "ap-guangzhou", Quota: Quota{QPS: 12, Burst: 12}},
+			{Vendor: enumor.TCloud, Region: "ap-guangzhou", Action: "DescribeInstances", Quota: Quota{QPS: 5, Burst: 5}},
+		},
+	}
+
+	key := Key{Vendor: enumor.Aws, Region: "ap-guangzhou", Action: "DescribeInstances"}
+	assert.Equal(t, float64(20), opt.QuotaOf(key).QPS)
+
+	key = Key{Vendor: enumor.TCloud, Region: "ap-shanghai", Action: "DescribeVpcs"}
+	assert.Equal(t, float64(15), opt.QuotaOf(key).QPS)
+
+	key = Key{Vendor: enumor.TCloud, Region: "ap-guangzhou", Action: "DescribeVpcs"}
+	assert.Equal(t, float64(12), opt.QuotaOf(key).QPS)
+
+	key = Key{Vendor: enumor.TCloud, Region: "ap-shanghai", Action: "DescribeInstances"}
+	assert.Equal(t, float64(10), opt.QuotaOf(key).QPS)
+
+	key = Key{Vendor: enumor.TCloud, Region: "ap-guangzhou", Action: "DescribeInstances"}
+	assert.Equal(t, float64(5), opt.QuotaOf(key).QPS)
+}
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// Quota 令牌桶配额
+type Quota struct {
+	// QPS 每秒补充的令牌数
+	QPS float64
+	// Burst 令牌桶容量
+	Burst uint
+}
+
+// Validate Quota.
+func (q Quota) Validate() error {
+	if q.QPS <= 0 {
+		return errors.New("qps should > 0")
+	}
+
+	if q.Burst == 0 {
+		return errors.New("burst should > 0")
+	}
+
+	return nil
+}
+
+// Rule 按云厂商、地域、接口配置的配额，Region、Action 为空时匹配所有地域、接口
+type Rule struct {
+	Vendor enumor.Vendor
+	Region string
+	Action string
+	Quota  Quota
+}
+
+// match 返回规则是否匹配Key，以及匹配的精确程度，越大越精确
+func (r Rule) match(key Key) (bool, int) {
+	if r.Vendor != key.Vendor {
+		return false, 0
+	}
+
+	score := 0
+	if len(r.Region) != 0 {
+		if r.Region != key.Region {
+			return false, 0
+		}
+		score++
+	}
+
+	if len(r.Action) != 0 {
+		if r.Action != key.Action {
+			return false, 0
+		}
+		score += 2
+	}
+
+	return true, score
+}
+
+// AIMD 被限流时的自适应速率调整参数
+type AIMD struct {
+	// IncreaseStep 未被限流时每秒加性恢复的QPS
+	IncreaseStep float64
+	// DecreaseFactor 被限流时速率乘以该系数
+	DecreaseFactor float64
+	// MinQPS 乘性降速后的最低QPS
+	MinQPS float64
+}
+
+// Validate AIMD.
+func (a AIMD) Validate() error {
+	if a.IncreaseStep <= 0 {
+		return errors.New("increase step should > 0")
+	}
+
+	if a.DecreaseFactor <= 0 || a.DecreaseFactor >= 1 {
+		return errors.New("decrease factor should be in (0, 1)")
+	}
+
+	if a.MinQPS <= 0 {
+		return errors.New("min qps should > 0")
+	}
+
+	return nil
+}
+
+// Option 限流器配置
+type Option struct {
+	// Default 未匹配到规则的接口使用的配额
+	Default Quota
+	// Rules 按云厂商、地域、接口配置的配额，匹配多条时使用最精确的规则
+	Rules []Rule
+	// AIMD 自适应速率调整参数
+	AIMD AIMD
+	// MaxWait 获取令牌的最长等待时间
+	MaxWait time.Duration
+	// Prefetch 每次访问存储时最多预取的令牌数，预取的令牌在当前副本内短时间有效，减少访问共享存储的次数。
+	// 为0或1时每次调用都访问存储
+	Prefetch uint
+}
+
+// Validate Option.
+func (opt *Option) Validate() error {
+	if err := opt.Default.Validate(); err != nil {
+		return fmt.Errorf("default quota is invalid, err: %v", err)
+	}
+
+	for _, rule := range opt.Rules {
+		if len(rule.Vendor) == 0 {
+			return errors.New("rule vendor is required")
+		}
+
+		if err := rule.Quota.Validate(); err != nil {
+			return fmt.Errorf("rule %s/%s/%s quota is invalid, err: %v", rule.Vendor, rule.Region, rule.Action, err)
+		}
+	}
+
+	if err := opt.AIMD.Validate(); err != nil {
+		return err
+	}
+
+	if opt.MaxWait <= 0 {
+		return errors.New("max wait should > 0")
+	}
+
+	return nil
+}
+
+// QuotaOf 获取Key对应的配额
+func (opt *Option) QuotaOf(key Key) Quota {
+	quota, best := opt.Default, -1
+	for _, rule := range opt.Rules {
+		ok, score := rule.match(key)
+		if ok && score > best {
+			quota, best = rule.Quota, score
+		}
+	}
+
+	return quota
+}
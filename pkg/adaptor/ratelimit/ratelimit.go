@@ -0,0 +1,269 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ratelimit 云API限流器，按云厂商、账号、地域、接口维度限制调用云API的频率，令牌桶状态可通过etcd在多个副本间共享，
+// 被云厂商限流时按AIMD（加性增、乘性减）策略自适应降低调用速率。
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+)
+
+// Key 限流维度，同一个Key共享一个令牌桶
+type Key struct {
+	Vendor  enumor.Vendor
+	Account string
+	Region  string
+	Action  string
+}
+
+// String 返回令牌桶的唯一标识
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", k.Vendor, k.Account, k.Region, k.Action)
+}
+
+// AccountOf 将云账号的访问凭证ID转换为限流维度中的账号标识，避免凭证ID明文出现在存储的key中
+func AccountOf(secretID string) string {
+	sum := sha256.Sum256([]byte(secretID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Limiter 云API限流器
+type Limiter interface {
+	// Wait 阻塞直到获取到Key对应的令牌，ctx 结束或等待超过最长等待时间时返回错误
+	Wait(ctx context.Context, key Key) error
+	// Throttled 调用云API被云厂商限流时调用，乘性降低Key对应令牌桶的速率
+	Throttled(ctx context.Context, key Key)
+}
+
+var (
+	limiter Limiter = new(noopLimiter)
+	once    sync.Once
+)
+
+// Init 初始化全局云API限流器，未初始化时不做限流
+func Init(opt *Option, store Store) error {
+	if opt == nil || store == nil {
+		return errors.New("rate limit option and store are required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+
+	once.Do(func() {
+		limiter = newBucketLimiter(opt, store)
+	})
+
+	return nil
+}
+
+// Get 获取全局云API限流器
+func Get() Limiter {
+	return limiter
+}
+
+// noopLimiter 未开启限流时使用，不做任何限制
+type noopLimiter struct{}
+
+// Wait ...
+func (l *noopLimiter) Wait(context.Context, Key) error {
+	return nil
+}
+
+// Throttled ...
+func (l *noopLimiter) Throttled(context.Context, Key) {}
+
+const (
+	// reservedTokenTTL 预取令牌在本地的有效期，过期未使用的令牌丢弃，避免长时间占用其他副本的配额
+	reservedTokenTTL = time.Second
+	// storeTimeout 单次访问令牌桶存储的超时时间
+	storeTimeout = 3 * time.Second
+	// storeRetryInterval 存储异常后使用本地令牌桶的时长，到期后再次尝试访问存储
+	storeRetryInterval = 10 * time.Second
+)
+
+// bucketLimiter 基于令牌桶的限流器，令牌桶状态保存在 Store 中
+type bucketLimiter struct {
+	opt   *Option
+	store Store
+	// fallback 存储异常时使用的本地令牌桶，保证存储不可用时仍按配额在当前副本内限流
+	fallback Store
+	now      func() time.Time
+
+	lock sync.Mutex
+	// reserved 从存储预取、尚未使用的令牌，key为令牌桶的唯一标识
+	reserved map[string]*reservation
+	// storeDownUntil 在该时间之前不访问存储，直接使用本地令牌桶
+	storeDownUntil time.Time
+}
+
+// reservation 当前副本预取的令牌
+type reservation struct {
+	tokens   int
+	expireAt time.Time
+}
+
+func newBucketLimiter(opt *Option, store Store) *bucketLimiter {
+	return &bucketLimiter{
+		opt:      opt,
+		store:    store,
+		fallback: NewMemoryStore(),
+		now:      time.Now,
+		reserved: make(map[string]*reservation),
+	}
+}
+
+// Wait 阻塞直到获取到Key对应的令牌，优先使用预取的令牌，存储异常时使用本地令牌桶限流
+func (l *bucketLimiter) Wait(ctx context.Context, key Key) error {
+	bucketKey := key.String()
+	if l.takeReserved(bucketKey) {
+		return nil
+	}
+
+	quota := l.opt.QuotaOf(key)
+	prefetch := l.prefetchOf(quota)
+	deadline := l.now().Add(l.opt.MaxWait)
+	for {
+		got := 0
+		wait := l.update(ctx, bucketKey, func(b *Bucket) time.Duration {
+			var wait time.Duration
+			got, wait = b.TakeN(quota, l.opt.AIMD, l.now(), prefetch)
+			return wait
+		})
+
+		if got > 0 {
+			l.reserve(bucketKey, got-1)
+			return nil
+		}
+
+		if l.now().Add(wait).After(deadline) {
+			return errf.Newf(errf.TooManyRequest, "cloud api %s rate limit exceeded, wait timeout", key)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// prefetchOf 每次从存储获取的令牌数，不超过令牌桶容量
+func (l *bucketLimiter) prefetchOf(quota Quota) int {
+	prefetch := l.opt.Prefetch
+	if prefetch > quota.Burst {
+		prefetch = quota.Burst
+	}
+	if prefetch < 1 {
+		prefetch = 1
+	}
+	return int(prefetch)
+}
+
+// takeReserved 使用一个预取的令牌，没有未过期的预取令牌时返回false
+func (l *bucketLimiter) takeReserved(bucketKey string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	r, exist := l.reserved[bucketKey]
+	if !exist {
+		return false
+	}
+
+	if r.tokens <= 0 || !l.now().Before(r.expireAt) {
+		delete(l.reserved, bucketKey)
+		return false
+	}
+
+	r.tokens--
+	return true
+}
+
+// reserve 保存预取的令牌，覆盖之前未使用完的令牌
+func (l *bucketLimiter) reserve(bucketKey string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.reserved[bucketKey] = &reservation{tokens: tokens, expireAt: l.now().Add(reservedTokenTTL)}
+}
+
+// update 在存储中更新令牌桶，存储异常时改用本地令牌桶，并在 storeRetryInterval 内不再访问存储
+func (l *bucketLimiter) update(ctx context.Context, bucketKey string, fn func(b *Bucket) time.Duration) time.Duration {
+	l.lock.Lock()
+	storeDown := l.now().Before(l.storeDownUntil)
+	l.lock.Unlock()
+
+	if !storeDown {
+		storeCtx, cancel := context.WithTimeout(ctx, storeTimeout)
+		wait, err := l.store.Update(storeCtx, bucketKey, fn)
+		cancel()
+		if err == nil {
+			return wait
+		}
+
+		// 调用方取消不是存储异常，本次使用本地令牌桶即可
+		if ctx.Err() != nil {
+			wait, _ = l.fallback.Update(ctx, bucketKey, fn)
+			return wait
+		}
+
+		logs.Errorf("update cloud api rate limit bucket in store failed, use local bucket for %s, err: %v, key: %s",
+			storeRetryInterval, err, bucketKey)
+		l.lock.Lock()
+		l.storeDownUntil = l.now().Add(storeRetryInterval)
+		l.lock.Unlock()
+	}
+
+	// 本地令牌桶不会返回错误
+	wait, _ := l.fallback.Update(ctx, bucketKey, fn)
+	return wait
+}
+
+// Throttled 乘性降低Key对应令牌桶的速率，并丢弃预取的令牌
+func (l *bucketLimiter) Throttled(ctx context.Context, key Key) {
+	bucketKey := key.String()
+	l.lock.Lock()
+	delete(l.reserved, bucketKey)
+	l.lock.Unlock()
+
+	quota := l.opt.QuotaOf(key)
+	l.update(ctx, bucketKey, func(b *Bucket) time.Duration {
+		b.Throttle(quota, l.opt.AIMD, l.now())
+		return 0
+	})
+
+	logs.V(3).Infof("cloud api %s is throttled by vendor, decrease rate", key)
+}
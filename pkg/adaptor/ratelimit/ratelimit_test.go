@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countStore 记录访问次数，可以模拟存储异常
+type countStore struct {
+	Store
+	calls int
+	err   error
+}
+
+// Update ...
+func (s *countStore) Update(ctx context.Context, key string, fn func(b *Bucket) time.Duration) (time.Duration, error) {
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.Store.Update(ctx, key, fn)
+}
+
+func newTestLimiter(store Store, quota Quota, prefetch uint) (*bucketLimiter, *time.Time) {
+	now := time.Now()
+	l := newBucketLimiter(&Option{
+		Default:  quota,
+		AIMD:     AIMD{IncreaseStep: 1, DecreaseFactor: 0.5, MinQPS: 1},
+		MaxWait:  time.Millisecond,
+		Prefetch: prefetch,
+	}, store)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestBucketTakeN(t *testing.T) {
+	quota := Quota{QPS: 10, Burst: 5}
+	aimd := AIMD{IncreaseStep: 1, DecreaseFactor: 0.5, MinQPS: 1}
+	now := time.Now()
+
+	b := new(Bucket)
+	got, wait := b.TakeN(quota, aimd, now, 3)
+	assert.Equal(t, 3, got)
+	assert.Zero(t, wait)
+
+	// 只剩2个令牌，不等待凑满
+	got, wait = b.TakeN(quota, aimd, now, 3)
+	assert.Equal(t, 2, got)
+	assert.Zero(t, wait)
+
+	got, wait = b.TakeN(quota, aimd, now, 3)
+	assert.Zero(t, got)
+	assert.Equal(t, 100*time.Millisecond, wait)
+}
+
+func TestLimiterPrefetch(t *testing.T) {
+	store := &countStore{Store: NewMemoryStore()}
+	l, now := newTestLimiter(store, Quota{QPS: 10, Burst: 10}, 4)
+	key := Key{Vendor: enumor.TCloud, Account: "a", Region: "ap-guangzhou", Action: "DescribeInstances"}
+	ctx := context.Background()
+
+	for i := 0; i < 8; i++ {
+		assert.NoError(t, l.Wait(ctx, key))
+	}
+	assert.Equal(t, 2, store.calls, "each store update should prefetch 4 tokens")
+
+	// 预取的令牌过期后丢弃，重新访问存储
+	assert.NoError(t, l.Wait(ctx, key))
+	assert.Equal(t, 3, store.calls)
+	*now = now.Add(reservedTokenTTL)
+	assert.NoError(t, l.Wait(ctx, key))
+	assert.Equal(t, 4, store.calls)
+
+	// 被限流时丢弃预取的令牌
+	l.Throttled(ctx, key)
+	calls := store.calls
+	_ = l.Wait(ctx, key)
+	assert.Equal(t, calls+1, store.calls)
+}
+
+func TestLimiterPrefetchNotExceedBurst(t *testing.T) {
+	store := &countStore{Store: NewMemoryStore()}
+	l, _ := newTestLimiter(store, Quota{QPS: 1, Burst: 2}, 10)
+	key := Key{Vendor: enumor.Aws, Action: "ec2:DescribeInstances"}
+
+	assert.NoError(t, l.Wait(context.Background(), key))
+	assert.NoError(t, l.Wait(context.Background(), key))
+	assert.Equal(t, 1, store.calls)
+
+	err := l.Wait(context.Background(), key)
+	assert.Error(t, err)
+	assert.Equal(t, int32(errf.TooManyRequest), errf.Error(err).Code)
+}
+
+func TestLimiterStoreFallback(t *testing.T) {
+	store := &countStore{Store: NewMemoryStore(), err: errors.New("etcd unavailable")}
+	l, now := newTestLimiter(store, Quota{QPS: 1, Burst: 2}, 1)
+	key := Key{Vendor: enumor.HuaWei, Action: "ecs:GET /v1/*/cloudservers/detail"}
+	ctx := context.Background()
+
+	// 存储异常时使用本地令牌桶，仍按配额限流
+	assert.NoError(t, l.Wait(ctx, key))
+	assert.NoError(t, l.Wait(ctx, key))
+	assert.Error(t, l.Wait(ctx, key))
+	assert.Equal(t, 1, store.calls, "store should not be accessed before retry interval")
+
+	// 到期后重新访问存储，存储恢复后使用存储中的令牌桶
+	store.err = nil
+	*now = now.Add(storeRetryInterval)
+	assert.NoError(t, l.Wait(ctx, key))
+	assert.Equal(t, 2, store.calls)
+}
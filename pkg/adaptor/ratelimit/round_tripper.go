@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// KeyFunc 从云API的http请求中解析限流维度
+type KeyFunc func(req *http.Request) Key
+
+// ThrottledFunc 判断云API的http响应是否为被云厂商限流
+type ThrottledFunc func(resp *http.Response) bool
+
+// IsTooManyRequests 通过http状态码429判断是否被限流
+func IsTooManyRequests(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+}
+
+// NewRoundTripper 在发送云API请求前获取令牌，被云厂商限流时降低速率，用于支持自定义 http.RoundTripper 的云厂商SDK
+func NewRoundTripper(next http.RoundTripper, keyFunc KeyFunc, throttled ThrottledFunc) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &roundTripper{next: next, keyFunc: keyFunc, throttled: throttled}
+}
+
+type roundTripper struct {
+	next      http.RoundTripper
+	keyFunc   KeyFunc
+	throttled ThrottledFunc
+}
+
+// RoundTrip ...
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := rt.keyFunc(req)
+	if err := Get().Wait(req.Context(), key); err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err == nil && rt.throttled(resp) {
+		Get().Throttled(req.Context(), key)
+	}
+
+	return resp, err
+}
+
+var idSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{16,}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// ActionOfPath 使用请求方法和路径作为RESTful云API的接口名，路径中的资源ID、项目ID等替换为*，避免每个资源一个令牌桶
+func ActionOfPath(method, urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for i, seg := range segments {
+		if idSegment.MatchString(seg) {
+			segments[i] = "*"
+		}
+	}
+
+	return method + " /" + strings.Join(segments, "/")
+}
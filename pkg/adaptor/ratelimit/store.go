@@ -0,0 +1,171 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"hcm/pkg/logs"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Store 令牌桶状态存储，Update 需要保证对同一个key的更新是原子的
+type Store interface {
+	// Update 原子的读取key对应的令牌桶，执行fn修改令牌桶后写回，返回fn的返回值
+	Update(ctx context.Context, key string, fn func(b *Bucket) time.Duration) (time.Duration, error)
+}
+
+// NewMemoryStore 创建基于内存的令牌桶存储，仅在当前副本内生效
+func NewMemoryStore() Store {
+	return &memoryStore{buckets: make(map[string]*Bucket)}
+}
+
+type memoryStore struct {
+	lock    sync.Mutex
+	buckets map[string]*Bucket
+}
+
+// Update ...
+func (s *memoryStore) Update(_ context.Context, key string, fn func(b *Bucket) time.Duration) (time.Duration, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	b, exist := s.buckets[key]
+	if !exist {
+		b = new(Bucket)
+		s.buckets[key] = b
+	}
+
+	return fn(b), nil
+}
+
+const (
+	// DefaultEtcdKeyPrefix etcd 存储默认的key前缀
+	DefaultEtcdKeyPrefix = "/hcm/ratelimit"
+
+	// etcdCASRetryCount 并发修改冲突时的重试次数
+	etcdCASRetryCount = 10
+	// etcdLeaseTTLSec 令牌桶key的租约时间，长时间未调用的接口令牌桶会被自动删除
+	etcdLeaseTTLSec = 600
+)
+
+// EtcdOption etcd store option.
+type EtcdOption struct {
+	Client *clientv3.Client
+	// KeyPrefix 令牌桶key的前缀，为空时使用 DefaultEtcdKeyPrefix
+	KeyPrefix string
+}
+
+// NewEtcdStore 创建基于etcd的令牌桶存储，多个副本共享令牌桶，基于key的ModRevision做CAS更新
+func NewEtcdStore(opt *EtcdOption) (Store, error) {
+	if opt == nil || opt.Client == nil {
+		return nil, errors.New("etcd client is required")
+	}
+
+	prefix := strings.TrimRight(opt.KeyPrefix, "/")
+	if len(prefix) == 0 {
+		prefix = DefaultEtcdKeyPrefix
+	}
+
+	return &etcdStore{cli: opt.Client, prefix: prefix}, nil
+}
+
+type etcdStore struct {
+	cli    *clientv3.Client
+	prefix string
+
+	leaseLock sync.Mutex
+	leaseID   clientv3.LeaseID
+	leaseExp  time.Time
+}
+
+// Update ...
+func (s *etcdStore) Update(ctx context.Context, key string, fn func(b *Bucket) time.Duration) (time.Duration, error) {
+	etcdKey := path.Join(s.prefix, key)
+	for i := 0; i < etcdCASRetryCount; i++ {
+		resp, err := s.cli.Get(ctx, etcdKey)
+		if err != nil {
+			return 0, err
+		}
+
+		b := new(Bucket)
+		var cmp clientv3.Cmp
+		if len(resp.Kvs) == 0 {
+			cmp = clientv3.Compare(clientv3.CreateRevision(etcdKey), "=", 0)
+		} else {
+			if err = json.Unmarshal(resp.Kvs[0].Value, b); err != nil {
+				logs.Errorf("unmarshal rate limit bucket failed, reset it, err: %v, key: %s", err, etcdKey)
+				b = new(Bucket)
+			}
+			cmp = clientv3.Compare(clientv3.ModRevision(etcdKey), "=", resp.Kvs[0].ModRevision)
+		}
+
+		wait := fn(b)
+		val, err := json.Marshal(b)
+		if err != nil {
+			return 0, err
+		}
+
+		leaseID, err := s.lease(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		txn, err := s.cli.Txn(ctx).If(cmp).Then(clientv3.OpPut(etcdKey, string(val), clientv3.WithLease(leaseID))).
+			Commit()
+		if err != nil {
+			return 0, err
+		}
+
+		if txn.Succeeded {
+			return wait, nil
+		}
+	}
+
+	return 0, fmt.Errorf("update rate limit bucket %s conflict after %d retries", etcdKey, etcdCASRetryCount)
+}
+
+// lease 获取令牌桶key使用的租约，租约过期前重新申请，避免长时间未调用的令牌桶一直占用存储
+func (s *etcdStore) lease(ctx context.Context) (clientv3.LeaseID, error) {
+	s.leaseLock.Lock()
+	defer s.leaseLock.Unlock()
+
+	if s.leaseID != 0 && time.Now().Before(s.leaseExp) {
+		return s.leaseID, nil
+	}
+
+	resp, err := s.cli.Grant(ctx, etcdLeaseTTLSec)
+	if err != nil {
+		return 0, err
+	}
+
+	s.leaseID = resp.ID
+	// 租约使用一半时间后重新申请，保证写入的key至少保留半个租约周期
+	s.leaseExp = time.Now().Add(etcdLeaseTTLSec / 2 * time.Second)
+	return s.leaseID, nil
+}
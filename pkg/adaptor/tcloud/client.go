@@ -20,11 +20,17 @@
 package tcloud
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/tools/rand"
 
 	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
@@ -53,21 +59,66 @@ type ClientSet interface {
 	BillClient() (*billing.Client, error)
 	ClbClient(region string) (*clb.Client, error)
 	CertClient() (*ssl.Client, error)
+	CommonClient(region string) (*common.Client, error)
 }
 
 // clientSet to get tcloud sdk client set
 type clientSet struct {
-	credential *common.Credential
+	credential common.CredentialIface
 	profile    *profile.ClientProfile
+	// account 云API限流维度中的账号标识
+	account string
 }
 
-func newClientSet(s *types.BaseSecret, profile *profile.ClientProfile) ClientSet {
+func newClientSet(s *types.BaseSecret, profile *profile.ClientProfile) (ClientSet, error) {
+	var cred common.CredentialIface = common.NewCredential(s.CloudSecretID, s.CloudSecretKey)
+	if s.AssumeRole != nil {
+		roleCred, err := newAssumeRoleCredential(s)
+		if err != nil {
+			return nil, err
+		}
+		cred = roleCred
+	}
+
 	return &clientSet{
-		credential: common.NewCredential(s.CloudSecretID, s.CloudSecretKey),
+		credential: cred,
 		profile:    profile,
+		account:    ratelimit.AccountOf(s.Identity()),
+	}, nil
+}
+
+// transport tcloud sdk 使用的 http transport，调用云API前获取限流令牌，并记录云API调用指标和调用链
+func (c *clientSet) transport() http.RoundTripper {
+	limited := ratelimit.NewRoundTripper(metric.GetTCloudRecordRoundTripper(nil), c.rateLimitKey,
+		isRequestLimitExceeded)
+	return metric.NewTracingRoundTripper(limited, c.rateLimitKey)
+}
+
+func (c *clientSet) rateLimitKey(req *http.Request) ratelimit.Key {
+	return ratelimit.Key{
+		Vendor:  enumor.TCloud,
+		Account: c.account,
+		Region:  strings.Join(req.Header["X-TC-Region"], ","),
+		Action:  strings.Join(req.Header["X-TC-Action"], ","),
 	}
 }
 
+// isRequestLimitExceeded tcloud 限流时http状态码仍为200，需要通过响应体中的错误码判断
+func isRequestLimitExceeded(resp *http.Response) bool {
+	if resp == nil || resp.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	return bytes.Contains(body, []byte(constant.TCloudLimitExceededErrCode))
+}
+
 // SetRateLimitRetryWithConstInterval Set up a retry mechanism with constant interval after exceeding the rate limit
 func (c *clientSet) SetRateLimitRetryWithConstInterval() {
 	c.profile.RateLimitExceededMaxRetries = constant.MaxRetries
@@ -89,7 +140,7 @@ func (c *clientSet) CamServiceClient(region string) (*cam.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 	return client, nil
 }
 
@@ -99,7 +150,7 @@ func (c *clientSet) CvmClient(region string) (*cvm.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 	return client, nil
 }
 
@@ -109,7 +160,7 @@ func (c *clientSet) CbsClient(region string) (*cbs.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 	return client, nil
 }
 
@@ -119,7 +170,7 @@ func (c *clientSet) VpcClient(region string) (*vpc.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 
 	return client, nil
 }
@@ -130,7 +181,7 @@ func (c *clientSet) BillClient() (*billing.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 
 	return client, nil
 }
@@ -141,7 +192,7 @@ func (c *clientSet) ClbClient(region string) (*clb.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
 
 	return client, nil
 }
@@ -152,7 +203,15 @@ func (c *clientSet) CertClient() (*ssl.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport())
+
+	return client, nil
+}
+
+// CommonClient tcloud common client, used for products without sdk dependency, such as cloudaudit
+func (c *clientSet) CommonClient(region string) (*common.Client, error) {
+	client := common.NewCommonClient(c.credential, region, c.profile)
+	client.WithHttpTransport(c.transport())
 
 	return client, nil
 }
@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"hcm/pkg/adaptor/types/cloud-event"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// lookUpEventsResp cloudaudit LookUpEvents response, sdk of cloudaudit is not imported, so define it here.
+type lookUpEventsResp struct {
+	Response *struct {
+		Events []struct {
+			EventID     string `json:"EventId"`
+			EventName   string `json:"EventName"`
+			EventTime   string `json:"EventTime"`
+			EventRegion string `json:"EventRegion"`
+			Resources   *struct {
+				ResourceType string `json:"ResourceType"`
+				ResourceName string `json:"ResourceName"`
+			} `json:"Resources"`
+		} `json:"Events"`
+		ListOver  bool   `json:"ListOver"`
+		NextToken uint64 `json:"NextToken"`
+	} `json:"Response"`
+}
+
+// ListCloudEvent list cloud audit event.
+// reference: https://cloud.tencent.com/document/api/629/12359
+func (t *TCloudImpl) ListCloudEvent(kt *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "tcloud cloud event list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	region := opt.Region
+	if len(region) == 0 {
+		region = constant.TCloudDefaultRegion
+	}
+
+	client, err := t.clientSet.CommonClient(region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud common client failed, err: %v", err)
+	}
+
+	params := map[string]interface{}{
+		"StartTime":  opt.StartTime.Unix(),
+		"EndTime":    opt.EndTime.Unix(),
+		"MaxResults": cloudevent.TCloudListLimit,
+	}
+	if len(opt.NextToken) != 0 {
+		nextToken, err := strconv.ParseUint(opt.NextToken, 10, 64)
+		if err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "invalid next token: %s", opt.NextToken)
+		}
+		params["NextToken"] = nextToken
+	}
+
+	req := tchttp.NewCommonRequest("cloudaudit", "2019-03-19", "LookUpEvents")
+	if err = req.SetActionParameters(params); err != nil {
+		return nil, err
+	}
+
+	resp := tchttp.NewCommonResponse()
+	if err = client.Send(req, resp); err != nil {
+		logs.Errorf("look up tcloud cloud audit events failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	result := new(lookUpEventsResp)
+	if err = json.Unmarshal(resp.GetBody(), result); err != nil {
+		return nil, fmt.Errorf("unmarshal look up events response failed, err: %v", err)
+	}
+
+	if result.Response == nil {
+		return new(cloudevent.ListResult), nil
+	}
+
+	events := make([]cloudevent.CloudEvent, 0, len(result.Response.Events))
+	for _, one := range result.Response.Events {
+		event := cloudevent.CloudEvent{
+			EventID:   one.EventID,
+			EventName: one.EventName,
+			Region:    one.EventRegion,
+		}
+		if len(event.Region) == 0 {
+			event.Region = opt.Region
+		}
+		if sec, err := strconv.ParseInt(one.EventTime, 10, 64); err == nil {
+			event.EventTime = time.Unix(sec, 0)
+		}
+		if one.Resources != nil {
+			event.ResourceType = one.Resources.ResourceType
+			if len(one.Resources.ResourceName) != 0 {
+				event.ResourceIDs = []string{one.Resources.ResourceName}
+			}
+		}
+		events = append(events, event)
+	}
+
+	listResult := &cloudevent.ListResult{Events: events}
+	if !result.Response.ListOver {
+		listResult.NextToken = strconv.FormatUint(result.Response.NextToken, 10)
+	}
+
+	return listResult, nil
+}
@@ -0,0 +1,170 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"hcm/pkg/adaptor/credential"
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/logs"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+)
+
+const (
+	stsEndpoint = "sts.tencentcloudapi.com"
+	stsRegion   = "ap-guangzhou"
+	stsVersion  = "2018-08-13"
+)
+
+// assumeRoleCredential 实现 tcloud sdk 的 CredentialIface，使用基础凭证扮演角色获取临时凭证，
+// 临时凭证在多个客户端间共享，过期前自动刷新
+type assumeRoleCredential struct {
+	secret *types.BaseSecret
+	key    string
+	// last 最近一次获取到的临时凭证，sdk 获取凭证的接口不支持返回错误，刷新失败时继续使用
+	last atomic.Pointer[credential.Temporary]
+}
+
+// newAssumeRoleCredential 创建时获取一次临时凭证，扮演角色失败时直接返回错误
+func newAssumeRoleCredential(secret *types.BaseSecret) (*assumeRoleCredential, error) {
+	role := secret.AssumeRole
+	c := &assumeRoleCredential{
+		secret: secret,
+		key: credential.Key(stsEndpoint, secret.CloudSecretID, secret.CloudSecretKey, role.RoleArn,
+			role.ExternalID, role.SessionName),
+	}
+
+	cred, err := credential.Get(c.key, c.assumeRole)
+	if err != nil {
+		return nil, err
+	}
+	c.last.Store(cred)
+
+	return c, nil
+}
+
+// GetSecretId returns temporary secret id.
+func (c *assumeRoleCredential) GetSecretId() string {
+	return c.get().SecretID
+}
+
+// GetSecretKey returns temporary secret key.
+func (c *assumeRoleCredential) GetSecretKey() string {
+	return c.get().SecretKey
+}
+
+// GetToken returns temporary token.
+func (c *assumeRoleCredential) GetToken() string {
+	return c.get().Token
+}
+
+// GetCredential returns temporary secret id, secret key and token.
+func (c *assumeRoleCredential) GetCredential() (string, string, string) {
+	cred := c.get()
+	return cred.SecretID, cred.SecretKey, cred.Token
+}
+
+func (c *assumeRoleCredential) get() *credential.Temporary {
+	cred, err := credential.Get(c.key, c.assumeRole)
+	if err != nil {
+		logs.Errorf("tcloud assume role %s failed, err: %v", c.secret.AssumeRole.RoleArn, err)
+		return c.last.Load()
+	}
+
+	c.last.Store(cred)
+	return cred
+}
+
+type assumeRoleResp struct {
+	Response struct {
+		Credentials *struct {
+			Token        string `json:"Token"`
+			TmpSecretId  string `json:"TmpSecretId"`
+			TmpSecretKey string `json:"TmpSecretKey"`
+		} `json:"Credentials"`
+		ExpiredTime int64 `json:"ExpiredTime"`
+	} `json:"Response"`
+}
+
+func (c *assumeRoleCredential) assumeRole() (*credential.Temporary, error) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.Endpoint = stsEndpoint
+	prof.HttpProfile.ReqMethod = "POST"
+	source, err := newClientSet(&types.BaseSecret{
+		CloudSecretID:  c.secret.CloudSecretID,
+		CloudSecretKey: c.secret.CloudSecretKey,
+	}, prof)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := source.CommonClient(stsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	role := c.secret.AssumeRole
+	params := map[string]interface{}{
+		"RoleArn":         role.RoleArn,
+		"RoleSessionName": credential.DefaultSessionName,
+		"DurationSeconds": credential.DefaultDurationSec,
+	}
+	if len(role.SessionName) != 0 {
+		params["RoleSessionName"] = role.SessionName
+	}
+	if role.DurationSec != 0 {
+		params["DurationSeconds"] = role.DurationSec
+	}
+	if len(role.ExternalID) != 0 {
+		params["ExternalId"] = role.ExternalID
+	}
+
+	req := tchttp.NewCommonRequest("sts", stsVersion, "AssumeRole")
+	if err = req.SetActionParameters(params); err != nil {
+		return nil, err
+	}
+
+	resp := tchttp.NewCommonResponse()
+	if err = client.Send(req, resp); err != nil {
+		return nil, err
+	}
+
+	result := new(assumeRoleResp)
+	if err = json.Unmarshal(resp.GetBody(), result); err != nil {
+		return nil, err
+	}
+
+	if result.Response.Credentials == nil {
+		return nil, errors.New("tcloud assume role returns empty credentials")
+	}
+
+	return &credential.Temporary{
+		SecretID:   result.Response.Credentials.TmpSecretId,
+		SecretKey:  result.Response.Credentials.TmpSecretKey,
+		Token:      result.Response.Credentials.Token,
+		Expiration: time.Unix(result.Response.ExpiredTime, 0),
+	}, nil
+}
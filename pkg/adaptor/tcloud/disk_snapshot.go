@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/core"
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// CreateDiskSnapshot 创建云硬盘快照
+func (t *TCloudImpl) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "tcloud disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewCreateSnapshotRequest()
+	req.DiskId = common.StringPtr(opt.CloudDiskID)
+	req.SnapshotName = common.StringPtr(opt.Name)
+
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create tcloud disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	return converter.PtrToVal(resp.Response.SnapshotId), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+func (t *TCloudImpl) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewDeleteSnapshotsRequest()
+	req.SnapshotIds = common.StringPtrs(opt.CloudIDs)
+
+	if _, err = client.DeleteSnapshotsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("delete tcloud disk snapshot failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListDiskSnapshot 查询云硬盘快照列表
+func (t *TCloudImpl) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "tcloud disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewDescribeSnapshotsRequest()
+	if len(opt.CloudIDs) != 0 {
+		req.SnapshotIds = common.StringPtrs(opt.CloudIDs)
+	}
+	req.Limit = common.Uint64Ptr(uint64(core.TCloudQueryLimit))
+
+	result := make([]disksnapshot.DiskSnapshot, 0)
+	for offset := uint64(0); ; offset += uint64(core.TCloudQueryLimit) {
+		req.Offset = common.Uint64Ptr(offset)
+
+		resp, err := client.DescribeSnapshotsWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list tcloud disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Response.SnapshotSet {
+			result = append(result, convTCloudDiskSnapshot(one))
+		}
+
+		if len(resp.Response.SnapshotSet) < int(core.TCloudQueryLimit) {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func convTCloudDiskSnapshot(one *cbs.Snapshot) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:        converter.PtrToVal(one.SnapshotId),
+		Name:           converter.PtrToVal(one.SnapshotName),
+		CloudDiskID:    converter.PtrToVal(one.DiskId),
+		DiskSize:       converter.PtrToVal(one.DiskSize),
+		Status:         converter.PtrToVal(one.SnapshotState),
+		Encrypted:      converter.PtrToVal(one.Encrypt),
+		CloudCreatedAt: converter.PtrToVal(one.CreateTime),
+		Extension: &corecloud.DiskSnapshotExtension{
+			SnapshotType: converter.PtrToVal(one.SnapshotType),
+		},
+	}
+
+	if one.Placement != nil {
+		snapshot.Zone = converter.PtrToVal(one.Placement.Zone)
+	}
+
+	if one.Percent != nil {
+		snapshot.Extension.Progress = converter.ValToPtr(int64(*one.Percent))
+	}
+
+	return snapshot
+}
+
+// RollbackDiskSnapshot 使用快照回滚云硬盘，云盘需处于未挂载或所挂载的实例已关机的状态，
+// 设置 AutoStopInstance 时由云上自动关机，回滚完成后自动开机。
+func (t *TCloudImpl) RollbackDiskSnapshot(kt *kit.Kit, opt *disksnapshot.RollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewApplySnapshotRequest()
+	req.SnapshotId = common.StringPtr(opt.CloudID)
+	req.DiskId = common.StringPtr(opt.CloudDiskID)
+	if opt.AutoStopInstance {
+		req.AutoStopInstance = common.BoolPtr(true)
+		req.AutoStartInstance = common.BoolPtr(true)
+	}
+
+	if _, err = client.ApplySnapshotWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("rollback tcloud disk %s with snapshot %s failed, err: %v, rid: %s", opt.CloudDiskID,
+			opt.CloudID, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
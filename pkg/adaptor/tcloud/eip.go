@@ -94,6 +94,7 @@ func (t *TCloudImpl) ListEip(kt *kit.Kit, opt *eip.TCloudEipListOption) (*eip.TC
 			Bandwidth:               address.Bandwidth,
 			InternetChargeType:      address.InternetChargeType,
 			InternetServiceProvider: address.InternetServiceProvider,
+			Tags:                    convertVpcTags(address.TagSet),
 		}
 	}
 
@@ -28,14 +28,17 @@ import (
 	typeargstpl "hcm/pkg/adaptor/types/argument-template"
 	typesBill "hcm/pkg/adaptor/types/bill"
 	"hcm/pkg/adaptor/types/cert"
+	"hcm/pkg/adaptor/types/cloud-event"
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/adaptor/types/disk"
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
 	"hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/adaptor/types/image"
 	"hcm/pkg/adaptor/types/instance-type"
 	typelb "hcm/pkg/adaptor/types/load-balancer"
 	"hcm/pkg/adaptor/types/region"
+	restag "hcm/pkg/adaptor/types/resource-tag"
 	"hcm/pkg/adaptor/types/route-table"
 	"hcm/pkg/adaptor/types/security-group"
 	"hcm/pkg/adaptor/types/security-group-rule"
@@ -88,6 +91,10 @@ type TCloud interface {
 	DeleteDisk(kt *kit.Kit, opt *disk.TCloudDiskDeleteOption) error
 	AttachDisk(kt *kit.Kit, opt *disk.TCloudDiskAttachOption) error
 	DetachDisk(kt *kit.Kit, opt *disk.TCloudDiskDetachOption) error
+	CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error)
+	DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error
+	ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error)
+	RollbackDiskSnapshot(kt *kit.Kit, opt *disksnapshot.RollbackOption) error
 	ListEip(kt *kit.Kit, opt *eip.TCloudEipListOption) (*eip.TCloudEipListResult, error)
 	CountEip(kt *kit.Kit, region string) (int32, error)
 	DeleteEip(kt *kit.Kit, opt *eip.TCloudEipDeleteOption) error
@@ -97,6 +104,9 @@ type TCloud interface {
 	CreateEip(kt *kit.Kit, opt *eip.TCloudEipCreateOption) (*poller.BaseDoneResult, error)
 	ListRegion(kt *kit.Kit) (*region.TCloudRegionListResult, error)
 	GetBillList(kt *kit.Kit, opt *typesBill.TCloudBillListOption) (*billing.DescribeBillDetailResponseParams, error)
+	ListCloudEvent(kt *kit.Kit, opt *cloudevent.ListOption) (*cloudevent.ListResult, error)
+	TagResources(kt *kit.Kit, opt *restag.TagResOption) error
+	UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error
 	ListInstanceType(kt *kit.Kit, opt *instancetype.TCloudInstanceTypeListOption) (
 		[]instancetype.TCloudInstanceType, error)
 	UpdateRouteTable(_ *kit.Kit, _ *routetable.TCloudRouteTableUpdateOption) error
@@ -0,0 +1,178 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+	"strings"
+
+	restag "hcm/pkg/adaptor/types/resource-tag"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// tagResNameSegments 资源类型对应的资源六段式中的服务类型和资源前缀
+var tagResNameSegments = map[enumor.CloudResourceType][2]string{
+	enumor.CvmCloudResType:           {"cvm", "instance"},
+	enumor.DiskCloudResType:          {"cvm", "volume"},
+	enumor.EipCloudResType:           {"cvm", "eip"},
+	enumor.SecurityGroupCloudResType: {"cvm", "sg"},
+	enumor.VpcCloudResType:           {"vpc", "vpc"},
+	enumor.SubnetCloudResType:        {"vpc", "subnet"},
+	enumor.LoadBalancerCloudResType:  {"clb", "clb"},
+}
+
+// tagResourceResp tag TagResources/UnTagResources response, sdk of tag is not imported, so define it here.
+type tagResourceResp struct {
+	Response *struct {
+		FailedResources []struct {
+			Resource string `json:"Resource"`
+			Code     string `json:"Code"`
+			Message  string `json:"Message"`
+		} `json:"FailedResources"`
+	} `json:"Response"`
+}
+
+// genTagResourceNames 生成资源六段式, 如 qcs::cvm:ap-guangzhou:uin/123:instance/ins-xxx
+// reference: https://cloud.tencent.com/document/product/598/10606
+func genTagResourceNames(resType enumor.CloudResourceType, region, uin string, cloudIDs []string) ([]string,
+	error) {
+
+	segments, ok := tagResNameSegments[resType]
+	if !ok {
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud tag does not support resource type: %s", resType)
+	}
+
+	if len(uin) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "owner uin is required")
+	}
+
+	names := make([]string, 0, len(cloudIDs))
+	for _, id := range cloudIDs {
+		names = append(names, fmt.Sprintf("qcs::%s:%s:uin/%s:%s/%s", segments[0], region, uin, segments[1], id))
+	}
+
+	return names, nil
+}
+
+// TagResources 为资源批量添加标签，标签键已存在时覆盖标签值
+// reference: https://cloud.tencent.com/document/api/651/72270
+func (t *TCloudImpl) TagResources(kt *kit.Kit, opt *restag.TagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud tag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	names, err := genTagResourceNames(opt.ResType, opt.Region, opt.OwnerUin, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]map[string]string, 0, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags = append(tags, map[string]string{"TagKey": tag.Key, "TagValue": tag.Value})
+	}
+
+	for _, batch := range slice.Split(names, restag.TCloudTagResLimit) {
+		params := map[string]interface{}{
+			"ResourceList": batch,
+			"Tags":         tags,
+		}
+		if err = t.sendTagRequest(kt, "TagResources", params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 批量解绑资源的标签
+// reference: https://cloud.tencent.com/document/api/651/72269
+func (t *TCloudImpl) UnTagResources(kt *kit.Kit, opt *restag.UnTagResOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud untag resources option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	names, err := genTagResourceNames(opt.ResType, opt.Region, opt.OwnerUin, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range slice.Split(names, restag.TCloudTagResLimit) {
+		params := map[string]interface{}{
+			"ResourceList": batch,
+			"TagKeys":      opt.TagKeys,
+		}
+		if err = t.sendTagRequest(kt, "UnTagResources", params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TCloudImpl) sendTagRequest(kt *kit.Kit, action string, params map[string]interface{}) error {
+	// 标签服务为全局服务，使用默认地域
+	client, err := t.clientSet.CommonClient(constant.TCloudDefaultRegion)
+	if err != nil {
+		return fmt.Errorf("new tcloud common client failed, err: %v", err)
+	}
+
+	req := tchttp.NewCommonRequest("tag", "2018-08-13", action)
+	if err = req.SetActionParameters(params); err != nil {
+		return err
+	}
+
+	resp := tchttp.NewCommonResponse()
+	if err = client.Send(req, resp); err != nil {
+		logs.Errorf("tcloud %s failed, err: %v, params: %+v, rid: %s", action, err, params, kt.Rid)
+		return err
+	}
+
+	result := new(tagResourceResp)
+	if err = json.Unmarshal(resp.GetBody(), result); err != nil {
+		return fmt.Errorf("unmarshal %s response failed, err: %v", action, err)
+	}
+
+	if result.Response == nil || len(result.Response.FailedResources) == 0 {
+		return nil
+	}
+
+	failed := make([]string, 0, len(result.Response.FailedResources))
+	for _, one := range result.Response.FailedResources {
+		failed = append(failed, fmt.Sprintf("%s(%s: %s)", one.Resource, one.Code, one.Message))
+	}
+	logs.Errorf("tcloud %s partially failed, failed: %v, rid: %s", action, failed, kt.Rid)
+	return fmt.Errorf("tcloud %s failed for resources: %s", action, strings.Join(failed, ", "))
+}
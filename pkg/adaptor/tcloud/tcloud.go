@@ -43,7 +43,12 @@ func NewTCloud(s *types.BaseSecret) (TCloud, error) {
 		return nil, err
 	}
 
-	return &TCloudImpl{clientSet: newClientSet(s, prof)}, nil
+	clientSet, err := newClientSet(s, prof)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCloudImpl{clientSet: clientSet}, nil
 }
 
 // TCloudImpl is tencent cloud operator.
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/adaptor/poller"
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
@@ -171,6 +172,7 @@ func convertVpc(data *vpc.Vpc, region string) *types.TCloudVpc {
 		CloudID: converter.PtrToVal(data.VpcId),
 		Name:    converter.PtrToVal(data.VpcName),
 		Region:  region,
+		Tags:    convertVpcTags(data.TagSet),
 		Extension: &cloud.TCloudVpcExtension{
 			Cidr:            nil,
 			IsDefault:       converter.PtrToVal(data.IsDefault),
@@ -273,3 +275,15 @@ func (h *createVpcPollingHandler) Poll(client *TCloudImpl, kt *kit.Kit, cloudIDs
 
 	return vpcs, nil
 }
+
+// convertVpcTags convert vpc sdk tags to tag pairs.
+func convertVpcTags(tags []*vpc.Tag) []apicore.TagPair {
+	pairs := make([]apicore.TagPair, 0, len(tags))
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+		pairs = append(pairs, apicore.TagPair{Key: converter.PtrToVal(tag.Key), Value: converter.PtrToVal(tag.Value)})
+	}
+	return pairs
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cloudevent 云上操作审计事件，来源于腾讯云云审计、AWS CloudTrail、华为云云审计服务CTS
+package cloudevent
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/validator"
+)
+
+const (
+	// TCloudListLimit 腾讯云云审计单次查询最大条数
+	TCloudListLimit = 50
+	// AwsListLimit aws CloudTrail 单次查询最大条数
+	AwsListLimit = 50
+	// HuaWeiListLimit 华为云CTS单次查询最大条数
+	HuaWeiListLimit = 200
+)
+
+// ListOption define cloud event list option.
+type ListOption struct {
+	// Region 腾讯云云审计为全地域查询，该字段可为空
+	Region    string    `json:"region"`
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+	// NextToken 上一次查询返回的分页标记，为空表示从头开始查询
+	NextToken string `json:"next_token"`
+}
+
+// Validate cloud event list option.
+func (opt ListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if !opt.EndTime.After(opt.StartTime) {
+		return errors.New("end_time must be after start_time")
+	}
+
+	return nil
+}
+
+// CloudEvent 云上操作事件
+type CloudEvent struct {
+	EventID   string `json:"event_id"`
+	EventName string `json:"event_name"`
+	// Region 事件发生的地域，云上未返回时为查询时指定的地域
+	Region string `json:"region"`
+	// ResourceType 云上的资源类型，各云厂商取值不同
+	ResourceType string `json:"resource_type"`
+	// ResourceIDs 事件涉及的资源ID，部分云厂商返回的是资源名称，需要调用方按资源ID格式过滤
+	ResourceIDs []string  `json:"resource_ids"`
+	EventTime   time.Time `json:"event_time"`
+}
+
+// ListResult define cloud event list result.
+type ListResult struct {
+	Events []CloudEvent `json:"events"`
+	// NextToken 为空表示没有更多数据
+	NextToken string `json:"next_token"`
+}
@@ -21,6 +21,7 @@ package cvm
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -186,3 +187,17 @@ type AwsCvm struct {
 func (cvm AwsCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
+
+// GetTags 返回Tag信息
+func (cvm AwsCvm) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range cvm.Tags {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 
 	"google.golang.org/api/compute/v1"
@@ -209,3 +210,11 @@ type GcpCvm struct {
 func (cvm GcpCvm) GetCloudID() string {
 	return fmt.Sprint(cvm.Id)
 }
+
+// GetTags 返回Label信息
+func (cvm GcpCvm) GetTags() []apicore.TagPair {
+	if cvm.Instance == nil {
+		return nil
+	}
+	return apicore.TagMap(cvm.Labels).Pairs()
+}
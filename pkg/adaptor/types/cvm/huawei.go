@@ -21,9 +21,12 @@ package cvm
 
 import (
 	"fmt"
+	"strings"
 
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/converter"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
 )
@@ -320,3 +323,14 @@ type HuaWeiCvm struct {
 func (cvm HuaWeiCvm) GetCloudID() string {
 	return cvm.Id
 }
+
+// GetTags 返回Tag信息
+func (cvm HuaWeiCvm) GetTags() []apicore.TagPair {
+	// 华为云主机标签格式为 "key=value"
+	tags := make([]apicore.TagPair, 0)
+	for _, one := range converter.PtrToVal(cvm.Tags) {
+		key, value, _ := strings.Cut(one, "=")
+		tags = append(tags, apicore.TagPair{Key: key, Value: value})
+	}
+	return tags
+}
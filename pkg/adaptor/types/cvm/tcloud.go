@@ -23,6 +23,7 @@ import (
 	"errors"
 
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -316,6 +317,20 @@ func (cvm TCloudCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
 
+// GetTags 返回Tag信息
+func (cvm TCloudCvm) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range cvm.Tags {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot defines the vendor-neutral options and result of disk snapshot adaptor operations.
+package disksnapshot
+
+import (
+	"errors"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateOption define disk snapshot create option.
+type CreateOption struct {
+	Region string `json:"region" validate:"required"`
+	// Zone gcp 云盘所在可用区
+	Zone string `json:"zone" validate:"omitempty"`
+	// ResourceGroupName azure 快照所在资源组
+	ResourceGroupName string `json:"resource_group_name" validate:"omitempty"`
+	// CloudDiskID 源云盘的云上ID，azure 为云盘的资源ID
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+	Name        string `json:"name" validate:"required,lte=60"`
+}
+
+// Validate disk snapshot create option.
+func (opt *CreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// DeleteOption define disk snapshot delete option.
+type DeleteOption struct {
+	Region            string   `json:"region" validate:"required"`
+	ResourceGroupName string   `json:"resource_group_name" validate:"omitempty"`
+	CloudIDs          []string `json:"cloud_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate disk snapshot delete option.
+func (opt *DeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ListOption define disk snapshot list option, list all snapshots of the region when cloud ids is empty.
+type ListOption struct {
+	// Region azure 为空时查询资源组下所有地域的快照
+	Region            string   `json:"region" validate:"omitempty"`
+	ResourceGroupName string   `json:"resource_group_name" validate:"omitempty"`
+	CloudIDs          []string `json:"cloud_ids" validate:"omitempty,max=100"`
+}
+
+// Validate disk snapshot list option.
+func (opt *ListOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// RollbackOption define disk snapshot rollback option.
+type RollbackOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+	// AutoStopInstance tcloud 回滚前是否自动关闭云盘挂载的实例，回滚完成后自动开机
+	AutoStopInstance bool `json:"auto_stop_instance" validate:"omitempty"`
+}
+
+// Validate disk snapshot rollback option.
+func (opt *RollbackOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ErrRollbackNotSupported is returned by the vendors which can not roll back a disk in place.
+var ErrRollbackNotSupported = errors.New("rolling back disk with snapshot is not supported by this vendor")
+
+// DiskSnapshot define vendor-neutral disk snapshot.
+type DiskSnapshot struct {
+	CloudID string `json:"cloud_id"`
+	Name    string `json:"name"`
+	// Region 快照所在地域，仅 azure 返回
+	Region      string `json:"region"`
+	Zone        string `json:"zone"`
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 单位GB
+	DiskSize       uint64                           `json:"disk_size"`
+	Status         string                           `json:"status"`
+	Encrypted      bool                             `json:"encrypted"`
+	CloudCreatedAt string                           `json:"cloud_created_at"`
+	Extension      *corecloud.DiskSnapshotExtension `json:"extension"`
+}
+
+// GetCloudID ...
+func (s DiskSnapshot) GetCloudID() string {
+	return s.CloudID
+}
@@ -21,6 +21,7 @@ package disk
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -155,3 +156,17 @@ type AwsDisk struct {
 func (disk AwsDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.VolumeId)
 }
+
+// GetTags 返回Tag信息
+func (disk AwsDisk) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range disk.Tags {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
@@ -20,6 +20,7 @@
 package disk
 
 import (
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -144,9 +145,15 @@ type AzureDisk struct {
 	SKUName  *string   `json:"sku_name"`
 	SKUTier  *string   `json:"sku_tier"`
 	Boot     *bool
+	Tags     []apicore.TagPair `json:"tags"`
 }
 
 // GetCloudID ...
 func (disk AzureDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.ID)
 }
+
+// GetTags 返回Tag信息
+func (disk AzureDisk) GetTags() []apicore.TagPair {
+	return disk.Tags
+}
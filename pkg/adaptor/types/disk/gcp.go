@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 
 	"google.golang.org/api/compute/v1"
@@ -151,3 +152,11 @@ type GcpDisk struct {
 func (disk GcpDisk) GetCloudID() string {
 	return fmt.Sprint(disk.Id)
 }
+
+// GetTags 返回Label信息
+func (disk GcpDisk) GetTags() []apicore.TagPair {
+	if disk.Disk == nil {
+		return nil
+	}
+	return apicore.TagMap(disk.Labels).Pairs()
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -286,3 +287,8 @@ type HuaWeiDisk struct {
 func (disk HuaWeiDisk) GetCloudID() string {
 	return disk.Id
 }
+
+// GetTags 返回Tag信息
+func (disk HuaWeiDisk) GetTags() []apicore.TagPair {
+	return apicore.TagMap(disk.Tags).Pairs()
+}
@@ -21,6 +21,7 @@ package disk
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -196,6 +197,20 @@ func (disk TCloudDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.DiskId)
 }
 
+// GetTags 返回Tag信息
+func (disk TCloudDisk) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range disk.Tags {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
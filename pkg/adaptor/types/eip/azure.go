@@ -20,6 +20,7 @@
 package eip
 
 import (
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
@@ -79,6 +80,7 @@ type AzureEip struct {
 	Fqdn                   *string
 	Zones                  []*string
 	PublicIPAddressVersion *string
+	Tags                   []apicore.TagPair
 }
 
 // GetCloudID ...
@@ -86,6 +88,11 @@ func (eip *AzureEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetTags 返回Tag信息
+func (eip *AzureEip) GetTags() []apicore.TagPair {
+	return eip.Tags
+}
+
 // AzureEipDeleteOption ...
 type AzureEipDeleteOption struct {
 	ResourceGroupName string `json:"resource_group_name" validate:"required"`
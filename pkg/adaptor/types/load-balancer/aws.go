@@ -0,0 +1,540 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strconv"
+
+	apicore "hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// AwsLoadBalancerScheme aws 负载均衡网络类型
+type AwsLoadBalancerScheme string
+
+const (
+	// AwsInternetFacingScheme 公网
+	AwsInternetFacingScheme AwsLoadBalancerScheme = "internet-facing"
+	// AwsInternalScheme 内网
+	AwsInternalScheme AwsLoadBalancerScheme = "internal"
+)
+
+// AwsLoadBalancerKind aws 负载均衡类型
+type AwsLoadBalancerKind string
+
+const (
+	// AwsApplicationLoadBalancer 应用型负载均衡
+	AwsApplicationLoadBalancer AwsLoadBalancerKind = "application"
+	// AwsNetworkLoadBalancer 网络型负载均衡
+	AwsNetworkLoadBalancer AwsLoadBalancerKind = "network"
+)
+
+const (
+	// AwsDescribeLBMax DescribeLoadBalancers 指定arn时单次最多查询数量
+	AwsDescribeLBMax = 20
+	// AwsDescribeTagsMax DescribeTags 单次最多查询数量
+	AwsDescribeTagsMax = 20
+	// AwsDescribePageSizeMax Describe* 接口单页最大数量
+	AwsDescribePageSizeMax = 400
+)
+
+// -------------------------- List Load Balancer --------------------------
+
+// AwsListOption defines options to list aws elbv2 load balancers.
+type AwsListOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"omitempty,max=20"`
+	// Marker 分页标记，为空时查询第一页
+	Marker *string `json:"marker" validate:"omitempty"`
+	// PageSize 分页大小，最大400
+	PageSize *int64 `json:"page_size" validate:"omitempty,max=400"`
+}
+
+// Validate aws elbv2 list option.
+func (opt AwsListOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsListResult aws elbv2 load balancer list result.
+type AwsListResult struct {
+	Details    []AwsLoadBalancer `json:"details"`
+	NextMarker *string           `json:"next_marker"`
+}
+
+// AwsLoadBalancer for aws elbv2 load balancer
+type AwsLoadBalancer struct {
+	*elbv2.LoadBalancer
+	Tags []*elbv2.Tag
+}
+
+// GetCloudID get cloud id
+func (lb AwsLoadBalancer) GetCloudID() string {
+	return cvt.PtrToVal(lb.LoadBalancerArn)
+}
+
+// GetIPVersion 返回ip版本信息
+func (lb AwsLoadBalancer) GetIPVersion() enumor.IPAddressType {
+	switch cvt.PtrToVal(lb.IpAddressType) {
+	case elbv2.IpAddressTypeIpv4:
+		return enumor.Ipv4
+	case elbv2.IpAddressTypeDualstack:
+		return enumor.Ipv6DualStack
+	}
+	return enumor.IPAddressType(cvt.PtrToVal(lb.IpAddressType))
+}
+
+// GetLoadBalancerType 转换为本地的网络类型 OPEN | INTERNAL
+func (lb AwsLoadBalancer) GetLoadBalancerType() TCloudLoadBalancerType {
+	if AwsLoadBalancerScheme(cvt.PtrToVal(lb.Scheme)) == AwsInternetFacingScheme {
+		return OpenLoadBalancerType
+	}
+	return InternalLoadBalancerType
+}
+
+// GetStatus 返回负载均衡状态
+func (lb AwsLoadBalancer) GetStatus() string {
+	if lb.State == nil {
+		return ""
+	}
+	return cvt.PtrToVal(lb.State.Code)
+}
+
+// GetZones 返回可用区列表
+func (lb AwsLoadBalancer) GetZones() []string {
+	zones := make([]string, 0, len(lb.AvailabilityZones))
+	for _, zone := range lb.AvailabilityZones {
+		zones = append(zones, cvt.PtrToVal(zone.ZoneName))
+	}
+	return zones
+}
+
+// GetCloudSubnetIDs 返回各可用区子网
+func (lb AwsLoadBalancer) GetCloudSubnetIDs() []string {
+	subnets := make([]string, 0, len(lb.AvailabilityZones))
+	for _, zone := range lb.AvailabilityZones {
+		if zone.SubnetId != nil {
+			subnets = append(subnets, cvt.PtrToVal(zone.SubnetId))
+		}
+	}
+	return subnets
+}
+
+// GetIPAddresses 返回负载均衡绑定的静态IP，ALB 仅能通过域名访问，因此只对 NLB 有效
+func (lb AwsLoadBalancer) GetIPAddresses() (privateIPv4, publicIPv4, ipv6 []string) {
+	for _, zone := range lb.AvailabilityZones {
+		for _, addr := range zone.LoadBalancerAddresses {
+			if addr.PrivateIPv4Address != nil {
+				privateIPv4 = append(privateIPv4, cvt.PtrToVal(addr.PrivateIPv4Address))
+			}
+			if addr.IpAddress != nil {
+				publicIPv4 = append(publicIPv4, cvt.PtrToVal(addr.IpAddress))
+			}
+			if addr.IPv6Address != nil {
+				ipv6 = append(ipv6, cvt.PtrToVal(addr.IPv6Address))
+			}
+		}
+	}
+	return privateIPv4, publicIPv4, ipv6
+}
+
+// GetTagMap ...
+func (lb AwsLoadBalancer) GetTagMap() apicore.TagMap {
+	return awsTagsToMap(lb.Tags)
+}
+
+func awsTagsToMap(tags []*elbv2.Tag) apicore.TagMap {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagMap := make(apicore.TagMap, len(tags))
+	for _, tag := range tags {
+		tagMap.Set(cvt.PtrToVal(tag.Key), cvt.PtrToVal(tag.Value))
+	}
+	return tagMap
+}
+
+// -------------------------- List Listener --------------------------
+
+// AwsListListenersOption defines options to list aws listeners.
+type AwsListListenersOption struct {
+	Region              string   `json:"region" validate:"required"`
+	CloudLoadBalancerID string   `json:"cloud_load_balancer_id" validate:"required_without=CloudIDs"`
+	CloudIDs            []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate aws listeners list option.
+func (opt AwsListListenersOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsListener for aws listener
+type AwsListener struct {
+	Region string
+	*elbv2.Listener
+}
+
+// GetCloudID get cloud id
+func (lbl AwsListener) GetCloudID() string {
+	return cvt.PtrToVal(lbl.ListenerArn)
+}
+
+// GetProtocol ...
+func (lbl AwsListener) GetProtocol() enumor.ProtocolType {
+	return enumor.ProtocolType(cvt.PtrToVal(lbl.Protocol))
+}
+
+// GetName aws 监听器没有名称，以 协议:端口 作为名称
+func (lbl AwsListener) GetName() string {
+	return fmt.Sprintf("%s:%d", cvt.PtrToVal(lbl.Protocol), cvt.PtrToVal(lbl.Port))
+}
+
+// GetCertCloudIDs 返回监听器绑定的证书
+func (lbl AwsListener) GetCertCloudIDs() []string {
+	certs := make([]string, 0, len(lbl.Certificates))
+	for _, cert := range lbl.Certificates {
+		certs = append(certs, cvt.PtrToVal(cert.CertificateArn))
+	}
+	return certs
+}
+
+// GetDefaultActionType 返回默认动作类型
+func (lbl AwsListener) GetDefaultActionType() string {
+	if len(lbl.DefaultActions) == 0 {
+		return ""
+	}
+	return cvt.PtrToVal(lbl.DefaultActions[0].Type)
+}
+
+// GetDefaultTargetGroupCloudID 返回默认转发的目标组
+func (lbl AwsListener) GetDefaultTargetGroupCloudID() string {
+	return awsForwardTargetGroup(lbl.DefaultActions)
+}
+
+// -------------------------- List Rule --------------------------
+
+// AwsListRulesOption defines options to list aws listener rules.
+type AwsListRulesOption struct {
+	Region          string   `json:"region" validate:"required"`
+	CloudListenerID string   `json:"cloud_listener_id" validate:"required_without=CloudIDs"`
+	CloudIDs        []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate aws rules list option.
+func (opt AwsListRulesOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsRule for aws listener rule
+type AwsRule struct {
+	*elbv2.Rule
+}
+
+// GetCloudID get cloud id
+func (rule AwsRule) GetCloudID() string {
+	return cvt.PtrToVal(rule.RuleArn)
+}
+
+// IsDefault 是否监听器默认规则，默认规则与监听器默认动作一致，作为四层规则同步
+func (rule AwsRule) IsDefault() bool {
+	return cvt.PtrToVal(rule.Rule.IsDefault)
+}
+
+// GetDomain 返回 host-header 条件的第一个值
+func (rule AwsRule) GetDomain() string {
+	return rule.conditionValue("host-header")
+}
+
+// GetURL 返回 path-pattern 条件的第一个值
+func (rule AwsRule) GetURL() string {
+	return rule.conditionValue("path-pattern")
+}
+
+// GetPriority ...
+func (rule AwsRule) GetPriority() int64 {
+	priority, _ := strconv.ParseInt(cvt.PtrToVal(rule.Priority), 10, 64)
+	return priority
+}
+
+// GetTargetGroupCloudID 返回规则转发的目标组
+func (rule AwsRule) GetTargetGroupCloudID() string {
+	return awsForwardTargetGroup(rule.Actions)
+}
+
+func (rule AwsRule) conditionValue(field string) string {
+	for _, cond := range rule.Conditions {
+		if cvt.PtrToVal(cond.Field) != field {
+			continue
+		}
+		if field == "host-header" && cond.HostHeaderConfig != nil && len(cond.HostHeaderConfig.Values) > 0 {
+			return cvt.PtrToVal(cond.HostHeaderConfig.Values[0])
+		}
+		if field == "path-pattern" && cond.PathPatternConfig != nil && len(cond.PathPatternConfig.Values) > 0 {
+			return cvt.PtrToVal(cond.PathPatternConfig.Values[0])
+		}
+		if len(cond.Values) > 0 {
+			return cvt.PtrToVal(cond.Values[0])
+		}
+	}
+	return ""
+}
+
+func awsForwardTargetGroup(actions []*elbv2.Action) string {
+	for _, action := range actions {
+		if cvt.PtrToVal(action.Type) != elbv2.ActionTypeEnumForward {
+			continue
+		}
+		if action.TargetGroupArn != nil {
+			return cvt.PtrToVal(action.TargetGroupArn)
+		}
+		if action.ForwardConfig != nil && len(action.ForwardConfig.TargetGroups) > 0 {
+			return cvt.PtrToVal(action.ForwardConfig.TargetGroups[0].TargetGroupArn)
+		}
+	}
+	return ""
+}
+
+// -------------------------- List Target Group --------------------------
+
+// AwsListTargetGroupOption defines options to list aws target groups.
+type AwsListTargetGroupOption struct {
+	Region              string   `json:"region" validate:"required"`
+	CloudLoadBalancerID string   `json:"cloud_load_balancer_id" validate:"omitempty"`
+	CloudIDs            []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate aws target group list option.
+func (opt AwsListTargetGroupOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsTargetGroup for aws target group
+type AwsTargetGroup struct {
+	*elbv2.TargetGroup
+}
+
+// GetCloudID get cloud id
+func (tg AwsTargetGroup) GetCloudID() string {
+	return cvt.PtrToVal(tg.TargetGroupArn)
+}
+
+// GetHealthCheck 转换为本地健康检查信息
+func (tg AwsTargetGroup) GetHealthCheck() *corelb.AwsHealthCheckInfo {
+	hc := &corelb.AwsHealthCheckInfo{
+		Enabled:            tg.HealthCheckEnabled,
+		Protocol:           tg.HealthCheckProtocol,
+		Port:               tg.HealthCheckPort,
+		Path:               tg.HealthCheckPath,
+		IntervalSeconds:    tg.HealthCheckIntervalSeconds,
+		TimeoutSeconds:     tg.HealthCheckTimeoutSeconds,
+		HealthyThreshold:   tg.HealthyThresholdCount,
+		UnhealthyThreshold: tg.UnhealthyThresholdCount,
+	}
+	if tg.Matcher != nil {
+		hc.Matcher = tg.Matcher.HttpCode
+		if hc.Matcher == nil {
+			hc.Matcher = tg.Matcher.GrpcCode
+		}
+	}
+	return hc
+}
+
+// AwsListTargetHealthOption defines options to list targets of aws target group.
+type AwsListTargetHealthOption struct {
+	Region             string `json:"region" validate:"required"`
+	CloudTargetGroupID string `json:"cloud_target_group_id" validate:"required"`
+}
+
+// Validate ...
+func (opt AwsListTargetHealthOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsTarget for aws target group target
+type AwsTarget struct {
+	*elbv2.TargetHealthDescription
+}
+
+// GetCloudID 目标没有id，以 {实例ID或IP}:{端口} 作为唯一标识
+func (t AwsTarget) GetCloudID() string {
+	if t.Target == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", cvt.PtrToVal(t.Target.Id), cvt.PtrToVal(t.Target.Port))
+}
+
+// -------------------------- Create Load Balancer --------------------------
+
+// AwsCreateLoadBalancerOption defines options to create aws elbv2 load balancer.
+type AwsCreateLoadBalancerOption struct {
+	Region                string                `json:"region" validate:"required"`
+	Name                  string                `json:"name" validate:"required,max=32"`
+	Type                  AwsLoadBalancerKind   `json:"type" validate:"required,oneof=application network"`
+	Scheme                AwsLoadBalancerScheme `json:"scheme" validate:"omitempty,oneof=internet-facing internal"`
+	IpAddressType         *string               `json:"ip_address_type" validate:"omitempty"`
+	CloudSubnetIDs        []string              `json:"cloud_subnet_ids" validate:"required,min=1"`
+	CloudSecurityGroupIDs []string              `json:"cloud_security_group_ids" validate:"omitempty"`
+	Tags                  []apicore.TagPair     `json:"tags" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsCreateLoadBalancerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsUpdateLoadBalancerOption defines options to update aws elbv2 load balancer.
+type AwsUpdateLoadBalancerOption struct {
+	Region                string   `json:"region" validate:"required"`
+	CloudID               string   `json:"cloud_id" validate:"required"`
+	DeletionProtection    *bool    `json:"deletion_protection" validate:"omitempty"`
+	CloudSecurityGroupIDs []string `json:"cloud_security_group_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsUpdateLoadBalancerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsDeleteOption 批量删除，适用于负载均衡、监听器、目标组、规则
+type AwsDeleteOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate ...
+func (opt AwsDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Create Listener --------------------------
+
+// AwsCreateListenerOption defines options to create aws listener, default action is forward to target group.
+type AwsCreateListenerOption struct {
+	Region              string              `json:"region" validate:"required"`
+	CloudLoadBalancerID string              `json:"cloud_load_balancer_id" validate:"required"`
+	Protocol            enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port                int64               `json:"port" validate:"required,min=1,max=65535"`
+	CloudTargetGroupID  string              `json:"cloud_target_group_id" validate:"required"`
+	SslPolicy           *string             `json:"ssl_policy" validate:"omitempty"`
+	CertCloudIDs        []string            `json:"cert_cloud_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsCreateListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsUpdateListenerOption defines options to update aws listener, nil or empty field is not modified.
+type AwsUpdateListenerOption struct {
+	Region             string              `json:"region" validate:"required"`
+	CloudID            string              `json:"cloud_id" validate:"required"`
+	Protocol           enumor.ProtocolType `json:"protocol" validate:"omitempty"`
+	Port               *int64              `json:"port" validate:"omitempty,min=1,max=65535"`
+	CloudTargetGroupID string              `json:"cloud_target_group_id" validate:"omitempty"`
+	SslPolicy          *string             `json:"ssl_policy" validate:"omitempty"`
+	CertCloudIDs       []string            `json:"cert_cloud_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsUpdateListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsCreateRuleOption defines options to create aws listener rule, action is forward to target group.
+type AwsCreateRuleOption struct {
+	Region             string `json:"region" validate:"required"`
+	CloudListenerID    string `json:"cloud_listener_id" validate:"required"`
+	Priority           int64  `json:"priority" validate:"required,min=1,max=50000"`
+	Domain             string `json:"domain" validate:"required_without=URL"`
+	URL                string `json:"url" validate:"omitempty"`
+	CloudTargetGroupID string `json:"cloud_target_group_id" validate:"required"`
+}
+
+// Validate ...
+func (opt AwsCreateRuleOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsUpdateRuleOption defines options to update aws listener rule, empty field is not modified.
+type AwsUpdateRuleOption struct {
+	Region             string `json:"region" validate:"required"`
+	CloudID            string `json:"cloud_id" validate:"required"`
+	Domain             string `json:"domain" validate:"omitempty"`
+	URL                string `json:"url" validate:"omitempty"`
+	CloudTargetGroupID string `json:"cloud_target_group_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsUpdateRuleOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Create Target Group --------------------------
+
+// AwsCreateTargetGroupOption defines options to create aws target group.
+type AwsCreateTargetGroupOption struct {
+	Region      string                     `json:"region" validate:"required"`
+	Name        string                     `json:"name" validate:"required,max=32"`
+	Protocol    enumor.ProtocolType        `json:"protocol" validate:"required"`
+	Port        int64                      `json:"port" validate:"required,min=1,max=65535"`
+	CloudVpcID  string                     `json:"cloud_vpc_id" validate:"required"`
+	TargetType  *string                    `json:"target_type" validate:"omitempty,oneof=instance ip"`
+	HealthCheck *corelb.AwsHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt AwsCreateTargetGroupOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsUpdateTargetGroupOption defines options to update aws target group, only health check can be modified.
+type AwsUpdateTargetGroupOption struct {
+	Region      string                     `json:"region" validate:"required"`
+	CloudID     string                     `json:"cloud_id" validate:"required"`
+	HealthCheck *corelb.AwsHealthCheckInfo `json:"health_check" validate:"required"`
+}
+
+// Validate ...
+func (opt AwsUpdateTargetGroupOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsTargetsOption defines options to register/deregister targets of aws target group.
+type AwsTargetsOption struct {
+	Region             string            `json:"region" validate:"required"`
+	CloudTargetGroupID string            `json:"cloud_target_group_id" validate:"required"`
+	Targets            []AwsTargetOption `json:"targets" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (opt AwsTargetsOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsTargetOption 实例ID或IP，及端口
+type AwsTargetOption struct {
+	CloudInstID string `json:"cloud_inst_id" validate:"required"`
+	Port        int64  `json:"port" validate:"required,min=1,max=65535"`
+}
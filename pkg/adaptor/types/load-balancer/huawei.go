@@ -0,0 +1,450 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	apicore "hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/elb/v3/model"
+)
+
+const (
+	// HuaWeiDescribeLimit 华为云 elb 列表接口单页最大数量
+	HuaWeiDescribeLimit = 2000
+)
+
+// -------------------------- List Load Balancer --------------------------
+
+// HuaWeiListOption defines options to list huawei elb instances.
+type HuaWeiListOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"omitempty"`
+	// Marker 上一页最后一条记录的ID，为空时查询第一页
+	Marker *string `json:"marker" validate:"omitempty"`
+	Limit  *int32  `json:"limit" validate:"omitempty,max=2000"`
+}
+
+// Validate huawei elb list option.
+func (opt HuaWeiListOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiListResult huawei elb list result.
+type HuaWeiListResult struct {
+	Details    []HuaWeiLoadBalancer `json:"details"`
+	NextMarker *string              `json:"next_marker"`
+}
+
+// HuaWeiLoadBalancer for huawei elb instance
+type HuaWeiLoadBalancer struct {
+	*model.LoadBalancer
+}
+
+// GetCloudID get cloud id
+func (lb HuaWeiLoadBalancer) GetCloudID() string {
+	return lb.Id
+}
+
+// GetTags 返回Tag信息
+func (lb HuaWeiLoadBalancer) GetTags() []apicore.TagPair {
+	tags := make([]apicore.TagPair, 0, len(lb.Tags))
+	for _, tag := range lb.Tags {
+		tags = append(tags, apicore.TagPair{Key: cvt.PtrToVal(tag.Key), Value: cvt.PtrToVal(tag.Value)})
+	}
+	return tags
+}
+
+// GetIPVersion 返回ip版本信息
+func (lb HuaWeiLoadBalancer) GetIPVersion() enumor.IPAddressType {
+	if len(lb.Ipv6VipAddress) != 0 {
+		if len(lb.VipAddress) != 0 {
+			return enumor.Ipv6DualStack
+		}
+		return enumor.Ipv6
+	}
+	return enumor.Ipv4
+}
+
+// GetLoadBalancerType 转换为本地的网络类型，绑定了EIP的视为公网
+func (lb HuaWeiLoadBalancer) GetLoadBalancerType() TCloudLoadBalancerType {
+	if len(lb.Eips) != 0 || len(lb.Publicips) != 0 {
+		return OpenLoadBalancerType
+	}
+	return InternalLoadBalancerType
+}
+
+// GetPublicIPv4Addresses 返回绑定的公网IPv4地址
+func (lb HuaWeiLoadBalancer) GetPublicIPv4Addresses() []string {
+	ips := make([]string, 0, len(lb.Eips))
+	for _, one := range lb.Eips {
+		if cvt.PtrToVal(one.IpVersion) == 6 {
+			continue
+		}
+		ips = append(ips, cvt.PtrToVal(one.EipAddress))
+	}
+	return ips
+}
+
+// GetCloudEipIDs 返回绑定的EIP
+func (lb HuaWeiLoadBalancer) GetCloudEipIDs() []string {
+	ids := make([]string, 0, len(lb.Eips))
+	for _, one := range lb.Eips {
+		ids = append(ids, cvt.PtrToVal(one.EipId))
+	}
+	return ids
+}
+
+// GetTagMap ...
+func (lb HuaWeiLoadBalancer) GetTagMap() apicore.TagMap {
+	return huaweiTagsToMap(lb.Tags)
+}
+
+func huaweiTagsToMap(tags []model.Tag) apicore.TagMap {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagMap := make(apicore.TagMap, len(tags))
+	for _, tag := range tags {
+		tagMap.Set(cvt.PtrToVal(tag.Key), cvt.PtrToVal(tag.Value))
+	}
+	return tagMap
+}
+
+// -------------------------- List Listener --------------------------
+
+// HuaWeiListListenersOption defines options to list huawei listeners.
+type HuaWeiListListenersOption struct {
+	Region              string   `json:"region" validate:"required"`
+	CloudLoadBalancerID string   `json:"cloud_load_balancer_id" validate:"required"`
+	CloudIDs            []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate huawei listeners list option.
+func (opt HuaWeiListListenersOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiListener for huawei listener
+type HuaWeiListener struct {
+	Region string
+	*model.Listener
+}
+
+// GetCloudID get cloud id
+func (lbl HuaWeiListener) GetCloudID() string {
+	return lbl.Id
+}
+
+// GetProtocol ...
+func (lbl HuaWeiListener) GetProtocol() enumor.ProtocolType {
+	return enumor.ProtocolType(lbl.Protocol)
+}
+
+// GetName 监听器名称为空时以 协议:端口 作为名称
+func (lbl HuaWeiListener) GetName() string {
+	if len(lbl.Name) != 0 {
+		return lbl.Name
+	}
+	return fmt.Sprintf("%s:%d", lbl.Protocol, lbl.ProtocolPort)
+}
+
+// -------------------------- List L7 Policy --------------------------
+
+// HuaWeiListL7PoliciesOption defines options to list huawei l7 policies of listener.
+type HuaWeiListL7PoliciesOption struct {
+	Region          string `json:"region" validate:"required"`
+	CloudListenerID string `json:"cloud_listener_id" validate:"required"`
+}
+
+// Validate ...
+func (opt HuaWeiListL7PoliciesOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiL7Policy for huawei l7 policy with its rules
+type HuaWeiL7Policy struct {
+	*model.L7Policy
+	Rules []model.L7Rule
+}
+
+// GetCloudID get cloud id
+func (p HuaWeiL7Policy) GetCloudID() string {
+	return p.Id
+}
+
+// GetDomain 返回 HOST_NAME 规则的值
+func (p HuaWeiL7Policy) GetDomain() string {
+	return p.ruleValue(model.GetL7RuleTypeEnum().HOST_NAME)
+}
+
+// GetURL 返回 PATH 规则的值
+func (p HuaWeiL7Policy) GetURL() string {
+	return p.ruleValue(model.GetL7RuleTypeEnum().PATH)
+}
+
+func (p HuaWeiL7Policy) ruleValue(ruleType model.L7RuleType) string {
+	for _, rule := range p.Rules {
+		if rule.Type == ruleType {
+			return rule.Value
+		}
+	}
+	return ""
+}
+
+// -------------------------- List Pool --------------------------
+
+// HuaWeiListPoolsOption defines options to list huawei pools.
+type HuaWeiListPoolsOption struct {
+	Region              string   `json:"region" validate:"required"`
+	CloudLoadBalancerID string   `json:"cloud_load_balancer_id" validate:"omitempty"`
+	CloudIDs            []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiListPoolsOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiPool for huawei pool(target group)
+type HuaWeiPool struct {
+	*model.Pool
+}
+
+// GetCloudID get cloud id
+func (p HuaWeiPool) GetCloudID() string {
+	return p.Id
+}
+
+// GetCloudListenerIDs ...
+func (p HuaWeiPool) GetCloudListenerIDs() []string {
+	ids := make([]string, 0, len(p.Listeners))
+	for _, one := range p.Listeners {
+		ids = append(ids, one.Id)
+	}
+	return ids
+}
+
+// GetCloudLoadBalancerIDs ...
+func (p HuaWeiPool) GetCloudLoadBalancerIDs() []string {
+	ids := make([]string, 0, len(p.Loadbalancers))
+	for _, one := range p.Loadbalancers {
+		ids = append(ids, cvt.PtrToVal(one.Id))
+	}
+	return ids
+}
+
+// HuaWeiListMembersOption defines options to list members of huawei pool.
+type HuaWeiListMembersOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudPoolID string `json:"cloud_pool_id" validate:"required"`
+}
+
+// Validate ...
+func (opt HuaWeiListMembersOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiMember for huawei pool member
+type HuaWeiMember struct {
+	*model.Member
+}
+
+// GetCloudID get cloud id
+func (m HuaWeiMember) GetCloudID() string {
+	return m.Id
+}
+
+// -------------------------- Create Load Balancer --------------------------
+
+// HuaWeiCreateLoadBalancerOption defines options to create huawei dedicated elb.
+type HuaWeiCreateLoadBalancerOption struct {
+	Region               string            `json:"region" validate:"required"`
+	Name                 string            `json:"name" validate:"required,max=255"`
+	CloudVpcID           string            `json:"cloud_vpc_id" validate:"required"`
+	CloudVipSubnetID     *string           `json:"cloud_vip_subnet_id" validate:"omitempty"`
+	CloudElbVirsubnetIDs []string          `json:"cloud_elb_virsubnet_ids" validate:"omitempty"`
+	AvailabilityZones    []string          `json:"availability_zones" validate:"required,min=1"`
+	L4FlavorID           *string           `json:"l4_flavor_id" validate:"omitempty"`
+	L7FlavorID           *string           `json:"l7_flavor_id" validate:"omitempty"`
+	CloudEipIDs          []string          `json:"cloud_eip_ids" validate:"omitempty"`
+	DeletionProtection   *bool             `json:"deletion_protection" validate:"omitempty"`
+	Tags                 []apicore.TagPair `json:"tags" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiCreateLoadBalancerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiUpdateLoadBalancerOption defines options to update huawei elb.
+type HuaWeiUpdateLoadBalancerOption struct {
+	Region             string  `json:"region" validate:"required"`
+	CloudID            string  `json:"cloud_id" validate:"required"`
+	Name               *string `json:"name" validate:"omitempty"`
+	Description        *string `json:"description" validate:"omitempty"`
+	DeletionProtection *bool   `json:"deletion_protection" validate:"omitempty"`
+	IpTargetEnable     *bool   `json:"ip_target_enable" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiUpdateLoadBalancerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiDeleteOption 批量删除，适用于负载均衡、监听器、后端服务器组、转发策略
+type HuaWeiDeleteOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate ...
+func (opt HuaWeiDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Create Listener --------------------------
+
+// HuaWeiCreateListenerOption defines options to create huawei listener.
+type HuaWeiCreateListenerOption struct {
+	Region                 string              `json:"region" validate:"required"`
+	CloudLoadBalancerID    string              `json:"cloud_load_balancer_id" validate:"required"`
+	Name                   string              `json:"name" validate:"omitempty"`
+	Protocol               enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port                   int32               `json:"port" validate:"required,min=1,max=65535"`
+	CloudDefaultPoolID     *string             `json:"cloud_default_pool_id" validate:"omitempty"`
+	DefaultTlsContainerRef *string             `json:"default_tls_container_ref" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiCreateListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiUpdateListenerOption defines options to update huawei listener, nil field is not modified.
+type HuaWeiUpdateListenerOption struct {
+	Region                 string  `json:"region" validate:"required"`
+	CloudID                string  `json:"cloud_id" validate:"required"`
+	Name                   *string `json:"name" validate:"omitempty"`
+	CloudDefaultPoolID     *string `json:"cloud_default_pool_id" validate:"omitempty"`
+	DefaultTlsContainerRef *string `json:"default_tls_container_ref" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiUpdateListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiCreateL7PolicyOption defines options to create huawei l7 policy, action is forward to pool.
+type HuaWeiCreateL7PolicyOption struct {
+	Region          string `json:"region" validate:"required"`
+	CloudListenerID string `json:"cloud_listener_id" validate:"required"`
+	Name            string `json:"name" validate:"omitempty"`
+	Domain          string `json:"domain" validate:"required_without=URL"`
+	URL             string `json:"url" validate:"omitempty"`
+	CloudPoolID     string `json:"cloud_pool_id" validate:"required"`
+}
+
+// Validate ...
+func (opt HuaWeiCreateL7PolicyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiUpdateL7PolicyOption defines options to update huawei l7 policy, domain and url replace all rules of
+// the policy when either is set.
+type HuaWeiUpdateL7PolicyOption struct {
+	Region      string  `json:"region" validate:"required"`
+	CloudID     string  `json:"cloud_id" validate:"required"`
+	Name        *string `json:"name" validate:"omitempty"`
+	Domain      string  `json:"domain" validate:"omitempty"`
+	URL         string  `json:"url" validate:"omitempty"`
+	CloudPoolID *string `json:"cloud_pool_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiUpdateL7PolicyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Create Pool --------------------------
+
+// HuaWeiCreatePoolOption defines options to create huawei pool(target group).
+type HuaWeiCreatePoolOption struct {
+	Region              string                        `json:"region" validate:"required"`
+	Name                string                        `json:"name" validate:"omitempty"`
+	Protocol            enumor.ProtocolType           `json:"protocol" validate:"required"`
+	LbAlgorithm         string                        `json:"lb_algorithm" validate:"required"`
+	CloudLoadBalancerID *string                       `json:"cloud_load_balancer_id" validate:"omitempty"`
+	CloudListenerID     *string                       `json:"cloud_listener_id" validate:"omitempty"`
+	CloudVpcID          *string                       `json:"cloud_vpc_id" validate:"omitempty"`
+	HealthCheck         *corelb.HuaWeiHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiCreatePoolOption) Validate() error {
+	if opt.CloudLoadBalancerID == nil && opt.CloudListenerID == nil && opt.CloudVpcID == nil {
+		return fmt.Errorf("one of cloud_load_balancer_id, cloud_listener_id, cloud_vpc_id is required")
+	}
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiUpdatePoolOption defines options to update huawei pool and its health monitor.
+type HuaWeiUpdatePoolOption struct {
+	Region      string  `json:"region" validate:"required"`
+	CloudID     string  `json:"cloud_id" validate:"required"`
+	Name        *string `json:"name" validate:"omitempty"`
+	LbAlgorithm *string `json:"lb_algorithm" validate:"omitempty"`
+	// CloudHealthMonitorID 更新健康检查时必填
+	CloudHealthMonitorID *string                       `json:"cloud_health_monitor_id" validate:"omitempty"`
+	HealthCheck          *corelb.HuaWeiHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt HuaWeiUpdatePoolOption) Validate() error {
+	if opt.HealthCheck != nil && len(cvt.PtrToVal(opt.CloudHealthMonitorID)) == 0 {
+		return fmt.Errorf("cloud_health_monitor_id is required when update health check")
+	}
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiMembersOption defines options to add/remove members of huawei pool.
+type HuaWeiMembersOption struct {
+	Region      string               `json:"region" validate:"required"`
+	CloudPoolID string               `json:"cloud_pool_id" validate:"required"`
+	Members     []HuaWeiMemberOption `json:"members" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (opt HuaWeiMembersOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiMemberOption 后端服务器IP、端口及权重
+type HuaWeiMemberOption struct {
+	Address       string  `json:"address" validate:"required"`
+	Port          int32   `json:"port" validate:"required,min=1,max=65535"`
+	Weight        *int32  `json:"weight" validate:"omitempty,min=0,max=100"`
+	CloudSubnetID *string `json:"cloud_subnet_id" validate:"omitempty"`
+}
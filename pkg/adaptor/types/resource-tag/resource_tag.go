@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag 云资源标签的写操作
+package restag
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+const (
+	// TCloudTagResLimit 腾讯云标签接口单次操作的最大资源数
+	TCloudTagResLimit = 10
+	// AwsTagResLimit aws CreateTags/DeleteTags 单次操作的最大资源数
+	AwsTagResLimit = 100
+	// HuaWeiTagResLimit 华为云标签管理服务批量添加/删除资源标签单次操作的最大资源数
+	HuaWeiTagResLimit = 50
+)
+
+// TagResOption define tag resources option.
+type TagResOption struct {
+	Region   string                   `json:"region" validate:"required"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"min=1,max=100"`
+	Tags     []core.TagPair           `json:"tags" validate:"min=1,max=50"`
+	// OwnerUin 资源所属的主账号ID，腾讯云拼接资源六段式时需要
+	OwnerUin string `json:"owner_uin"`
+}
+
+// Validate tag resources option.
+func (opt TagResOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	for _, tag := range opt.Tags {
+		if len(tag.Key) == 0 {
+			return errors.New("tag key is required")
+		}
+	}
+
+	return nil
+}
+
+// UnTagResOption define untag resources option.
+type UnTagResOption struct {
+	Region   string                   `json:"region" validate:"required"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"min=1,max=100"`
+	TagKeys  []string                 `json:"tag_keys" validate:"min=1,max=50,dive,required"`
+	// OwnerUin 资源所属的主账号ID，腾讯云拼接资源六段式时需要
+	OwnerUin string `json:"owner_uin"`
+}
+
+// Validate untag resources option.
+func (opt UnTagResOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ListResTagOption define list resource tags option.
+type ListResTagOption struct {
+	Region   string                   `json:"region" validate:"required"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"min=1"`
+}
+
+// Validate list resource tags option.
+func (opt ListResTagOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
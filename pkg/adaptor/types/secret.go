@@ -32,6 +32,8 @@ type BaseSecret struct {
 	CloudSecretKey string `json:"cloud_secret_key"`
 	// CloudAccountID is the account id to do credential.
 	CloudAccountID string `json:"cloud_account_id"`
+	// AssumeRole 不为空时，使用 CloudSecretID/CloudSecretKey 扮演角色获取临时凭证，再使用临时凭证访问云API
+	AssumeRole *AssumeRole `json:"assume_role,omitempty"`
 }
 
 // Validate BaseSecret.
@@ -44,6 +46,54 @@ func (b BaseSecret) Validate() error {
 		return errf.New(errf.InvalidParameter, "secret key is required")
 	}
 
+	if b.AssumeRole != nil {
+		if err := b.AssumeRole.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Identity 云API调用方身份标识，扮演角色时为扮演的角色，否则为密钥ID
+func (b BaseSecret) Identity() string {
+	if b.AssumeRole == nil {
+		return b.CloudSecretID
+	}
+
+	if len(b.AssumeRole.RoleArn) != 0 {
+		return b.AssumeRole.RoleArn
+	}
+
+	return b.AssumeRole.AgencyDomainName + "/" + b.AssumeRole.AgencyName
+}
+
+// AssumeRole defines the role to assume with base secret, aws and tcloud use role arn, huawei uses agency.
+type AssumeRole struct {
+	// RoleArn aws、tcloud 扮演的角色，如 arn:aws:iam::123456789012:role/hcm、qcs::cam::uin/100000:roleName/hcm
+	RoleArn string `json:"role_arn,omitempty"`
+	// ExternalID 角色信任策略中要求的外部ID，huawei 委托不支持
+	ExternalID string `json:"external_id,omitempty"`
+	// AgencyDomainName huawei 创建委托的账号名
+	AgencyDomainName string `json:"agency_domain_name,omitempty"`
+	// AgencyName huawei 委托名
+	AgencyName string `json:"agency_name,omitempty"`
+	// SessionName 角色会话名称，为空时使用默认值
+	SessionName string `json:"session_name,omitempty"`
+	// DurationSec 临时凭证有效期，为0时使用默认值
+	DurationSec int64 `json:"duration_sec,omitempty"`
+}
+
+// Validate AssumeRole.
+func (a AssumeRole) Validate() error {
+	if len(a.RoleArn) == 0 && (len(a.AgencyDomainName) == 0 || len(a.AgencyName) == 0) {
+		return errf.New(errf.InvalidParameter, "assume role arn or agency domain name and agency name is required")
+	}
+
+	if a.DurationSec < 0 {
+		return errf.New(errf.InvalidParameter, "assume role duration should >= 0")
+	}
+
 	return nil
 }
 
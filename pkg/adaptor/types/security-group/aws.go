@@ -21,6 +21,7 @@ package securitygroup
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -102,3 +103,17 @@ type AwsSG struct {
 func (sg AwsSG) GetCloudID() string {
 	return converter.PtrToVal(sg.GroupId)
 }
+
+// GetTags 返回Tag信息
+func (sg AwsSG) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range sg.Tags {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
@@ -20,6 +20,7 @@
 package securitygroup
 
 import (
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -108,9 +109,15 @@ type AzureSecurityGroup struct {
 	FlushConnection *bool                      `json:"flush_connection"`
 	ResourceGUID    *string                    `json:"resource_guid"`
 	SecurityRules   []*armnetwork.SecurityRule `json:"security_rules"`
+	Tags            []apicore.TagPair          `json:"tags"`
 }
 
 // GetCloudID ...
 func (sg AzureSecurityGroup) GetCloudID() string {
 	return converter.PtrToVal(sg.ID)
 }
+
+// GetTags 返回Tag信息
+func (sg AzureSecurityGroup) GetTags() []apicore.TagPair {
+	return sg.Tags
+}
@@ -21,6 +21,7 @@ package securitygroup
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -128,3 +129,17 @@ type TCloudSG struct {
 func (sg TCloudSG) GetCloudID() string {
 	return converter.PtrToVal(sg.SecurityGroupId)
 }
+
+// GetTags 返回Tag信息
+func (sg TCloudSG) GetTags() (tags []apicore.TagPair) {
+	for _, tag := range sg.TagSet {
+		if tag == nil {
+			continue
+		}
+		tags = append(tags, apicore.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+	return tags
+}
@@ -19,7 +19,10 @@
 
 package adtysubnet
 
-import "hcm/pkg/criteria/validator"
+import (
+	apicore "hcm/pkg/api/core"
+	"hcm/pkg/criteria/validator"
+)
 
 // AwsSubnetCreateExt defines create aws subnet extensional info.
 type AwsSubnetCreateExt struct {
@@ -82,3 +85,8 @@ type AwsSubnet Subnet[AwsSubnetExtension]
 func (vpc AwsSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags 返回Tag信息
+func (vpc AwsSubnet) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
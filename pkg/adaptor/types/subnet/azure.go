@@ -21,6 +21,7 @@ package adtysubnet
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
 )
@@ -128,3 +129,8 @@ type AzureSubnet Subnet[AzureSubnetExtension]
 func (vpc AzureSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags 返回Tag信息
+func (vpc AzureSubnet) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
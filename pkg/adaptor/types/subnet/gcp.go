@@ -22,6 +22,7 @@ package adtysubnet
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
 )
@@ -112,3 +113,8 @@ type GcpSubnet Subnet[GcpSubnetExtension]
 func (vpc GcpSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags 返回Tag信息
+func (vpc GcpSubnet) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
@@ -21,6 +21,7 @@ package adtysubnet
 
 import (
 	"fmt"
+	apicore "hcm/pkg/api/core"
 
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/criteria/constant"
@@ -150,3 +151,8 @@ type HuaWeiSubnet Subnet[HuaWeiSubnetExtension]
 func (vpc HuaWeiSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags 返回Tag信息
+func (vpc HuaWeiSubnet) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
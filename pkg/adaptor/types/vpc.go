@@ -22,6 +22,7 @@ package types
 import (
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/subnet"
+	apicore "hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
@@ -276,6 +277,8 @@ type Vpc[T VpcExtension] struct {
 	Region    string  `json:"region"`
 	Memo      *string `json:"memo,omitempty"`
 	Extension *T      `json:"extension"`
+	// Tags 资源标签
+	Tags []apicore.TagPair `json:"tags,omitempty"`
 }
 
 // AzureVpcExtension defines azure vpc extensional info.
@@ -300,6 +303,11 @@ func (vpc TCloudVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetTags 返回Tag信息
+func (vpc TCloudVpc) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
+
 // AwsVpc defines aws vpc.
 type AwsVpc Vpc[cloud.AwsVpcExtension]
 
@@ -308,6 +316,11 @@ func (vpc AwsVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetTags 返回Tag信息
+func (vpc AwsVpc) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
+
 // GcpVpc defines gcp vpc.
 type GcpVpc Vpc[cloud.GcpVpcExtension]
 
@@ -316,6 +329,11 @@ func (vpc GcpVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetTags 返回Tag信息
+func (vpc GcpVpc) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
+
 // AzureVpc defines azure vpc.
 type AzureVpc Vpc[AzureVpcExtension]
 
@@ -324,6 +342,11 @@ func (vpc AzureVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetTags 返回Tag信息
+func (vpc AzureVpc) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
+
 // HuaWeiVpc defines huawei vpc.
 type HuaWeiVpc Vpc[cloud.HuaWeiVpcExtension]
 
@@ -332,6 +355,11 @@ func (vpc HuaWeiVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetTags 返回Tag信息
+func (vpc HuaWeiVpc) GetTags() []apicore.TagPair {
+	return vpc.Tags
+}
+
 // VpcUsage define vpc usage.
 type VpcUsage struct {
 	ID           *string  `json:"id"`
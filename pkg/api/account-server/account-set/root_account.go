@@ -95,6 +95,22 @@ func (req *AwsRootAccountExtensionUpdateReq) Validate() error {
 	return nil
 }
 
+// TCloudRootAccountExtensionUpdateReq ...
+type TCloudRootAccountExtensionUpdateReq struct {
+	CloudSubAccountID string `json:"cloud_sub_account_id" validate:"required"`
+	CloudSecretID     string `json:"cloud_secret_id" validate:"omitempty"`
+	CloudSecretKey    string `json:"cloud_secret_key" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *TCloudRootAccountExtensionUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GcpRootAccountExtensionUpdateReq ...
 type GcpRootAccountExtensionUpdateReq struct {
 	CloudProjectName        string `json:"cloud_project_name" validate:"omitempty"`
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostForecastReq 费用预测请求
+type CostForecastReq struct {
+	BillYear  int                          `json:"bill_year" validate:"required"`
+	BillMonth int                          `json:"bill_month" validate:"required,min=1,max=12"`
+	Dimension enumor.CostForecastDimension `json:"dimension" validate:"required"`
+	// Filter 二级账号账单汇总的过滤条件
+	Filter *filter.Expression `json:"filter" validate:"omitempty"`
+}
+
+// Validate ...
+func (r *CostForecastReq) Validate() error {
+	if err := r.Dimension.Validate(); err != nil {
+		return err
+	}
+	if r.Filter != nil {
+		err := r.Filter.Validate(filter.NewExprOption(
+			filter.RuleFields(tablebill.AccountBillSummaryMainColumns.ColumnTypes())))
+		if err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(r)
+}
+
+// CostForecastResult 费用预测结果
+type CostForecastResult struct {
+	Details []CostForecast `json:"details"`
+}
+
+// CostForecast 按维度汇总的费用预测，金额均为人民币，上下限为 95% 置信区间
+type CostForecast struct {
+	BkBizID       int64         `json:"bk_biz_id,omitempty"`
+	MainAccountID string        `json:"main_account_id,omitempty"`
+	Vendor        enumor.Vendor `json:"vendor,omitempty"`
+	// CurrentRMBCost 账单月份的当前费用，含已确认的调账
+	CurrentRMBCost decimal.Decimal `json:"current_rmb_cost"`
+	// MonthEndRMBCost 账单月份的月末费用预测
+	MonthEndRMBCost  decimal.Decimal `json:"month_end_rmb_cost"`
+	MonthEndRMBLower decimal.Decimal `json:"month_end_rmb_lower"`
+	MonthEndRMBUpper decimal.Decimal `json:"month_end_rmb_upper"`
+	// NextMonthRMBCost 账单月份下个月的费用预测，含已确认的调账
+	NextMonthRMBCost  decimal.Decimal `json:"next_month_rmb_cost"`
+	NextMonthRMBLower decimal.Decimal `json:"next_month_rmb_lower"`
+	NextMonthRMBUpper decimal.Decimal `json:"next_month_rmb_upper"`
+}
@@ -37,6 +37,8 @@ type TCloudAccountExtensionCreateReq struct {
 	CloudSubAccountID  string `json:"cloud_sub_account_id" validate:"required"`
 	CloudSecretID      string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey     string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn       string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID    string `json:"cloud_external_id" validate:"omitempty"`
 }
 
 // Validate ...
@@ -53,9 +55,14 @@ func (req *TCloudAccountExtensionCreateReq) Validate(accountType enumor.AccountT
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *TCloudAccountExtensionCreateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *TCloudAccountExtensionCreateReq) IsAssumeRole() bool {
+	return req.CloudRoleArn != ""
 }
 
 // AwsAccountExtensionCreateReq ...
@@ -64,6 +71,8 @@ type AwsAccountExtensionCreateReq struct {
 	CloudIamUsername string `json:"cloud_iam_username" validate:"required"`
 	CloudSecretID    string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey   string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn     string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID  string `json:"cloud_external_id" validate:"omitempty"`
 }
 
 // Validate ...
@@ -80,9 +89,14 @@ func (req *AwsAccountExtensionCreateReq) Validate(accountType enumor.AccountType
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *AwsAccountExtensionCreateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *AwsAccountExtensionCreateReq) IsAssumeRole() bool {
+	return req.CloudRoleArn != ""
 }
 
 // HuaWeiAccountExtensionCreateReq ...
@@ -93,6 +107,7 @@ type HuaWeiAccountExtensionCreateReq struct {
 	CloudIamUsername    string `json:"cloud_iam_username" validate:"required"`
 	CloudSecretID       string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey      string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudAgencyName     string `json:"cloud_agency_name" validate:"omitempty"`
 }
 
 // Validate ...
@@ -109,9 +124,14 @@ func (req *HuaWeiAccountExtensionCreateReq) Validate(accountType enumor.AccountT
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *HuaWeiAccountExtensionCreateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *HuaWeiAccountExtensionCreateReq) IsAssumeRole() bool {
+	return req.CloudAgencyName != ""
 }
 
 // GcpAccountExtensionCreateReq ...
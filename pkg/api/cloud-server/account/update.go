@@ -32,6 +32,8 @@ type TCloudAccountExtensionUpdateReq struct {
 	CloudSubAccountID string `json:"cloud_sub_account_id" validate:"required"`
 	CloudSecretID     string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey    string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn      string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID   string `json:"cloud_external_id" validate:"omitempty"`
 }
 
 // Validate ...
@@ -48,9 +50,14 @@ func (req *TCloudAccountExtensionUpdateReq) Validate(accountType enumor.AccountT
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *TCloudAccountExtensionUpdateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *TCloudAccountExtensionUpdateReq) IsAssumeRole() bool {
+	return req.CloudRoleArn != ""
 }
 
 // AwsAccountExtensionUpdateReq ...
@@ -58,6 +65,8 @@ type AwsAccountExtensionUpdateReq struct {
 	CloudIamUsername string `json:"cloud_iam_username" validate:"required"`
 	CloudSecretID    string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey   string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn     string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID  string `json:"cloud_external_id" validate:"omitempty"`
 }
 
 // Validate ...
@@ -74,9 +83,14 @@ func (req *AwsAccountExtensionUpdateReq) Validate(accountType enumor.AccountType
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *AwsAccountExtensionUpdateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *AwsAccountExtensionUpdateReq) IsAssumeRole() bool {
+	return req.CloudRoleArn != ""
 }
 
 // HuaWeiAccountExtensionUpdateReq ...
@@ -86,6 +100,7 @@ type HuaWeiAccountExtensionUpdateReq struct {
 	CloudIamUsername    string `json:"cloud_iam_username" validate:"required"`
 	CloudSecretID       string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey      string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudAgencyName     string `json:"cloud_agency_name" validate:"omitempty"`
 }
 
 // Validate ...
@@ -102,9 +117,14 @@ func (req *HuaWeiAccountExtensionUpdateReq) Validate(accountType enumor.AccountT
 	return nil
 }
 
-// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值
+// IsFull 对于不同账号类型，有些字段是允许为空的，这里返回是否所有字段都有值，角色扮演模式下密钥可为空
 func (req *HuaWeiAccountExtensionUpdateReq) IsFull() bool {
-	return req.CloudSecretID != "" && req.CloudSecretKey != ""
+	return req.IsAssumeRole() || (req.CloudSecretID != "" && req.CloudSecretKey != "")
+}
+
+// IsAssumeRole 是否通过角色扮演获取临时凭证访问该账号
+func (req *HuaWeiAccountExtensionUpdateReq) IsAssumeRole() bool {
+	return req.CloudAgencyName != ""
 }
 
 // GcpAccountExtensionUpdateReq ...
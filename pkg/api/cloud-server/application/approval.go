@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApprovalOperateReq 内置审批引擎的审批通过、驳回请求
+type ApprovalOperateReq struct {
+	Memo string `json:"memo" validate:"lte=255"`
+}
+
+// Validate ...
+func (req *ApprovalOperateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalDelegateReq 内置审批引擎的转交审批请求
+type ApprovalDelegateReq struct {
+	DelegateTo string `json:"delegate_to" validate:"required,lte=64"`
+	Memo       string `json:"memo" validate:"lte=255"`
+}
+
+// Validate ...
+func (req *ApprovalDelegateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalTodoListReq 查询当前用户待审批的审批单请求
+type ApprovalTodoListReq struct {
+	Page *core.BasePage `json:"page" validate:"required"`
+}
+
+// Validate ...
+func (req *ApprovalTodoListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Page.Validate()
+}
+
+// ApprovalChainCreateReq 创建审批链请求
+type ApprovalChainCreateReq struct {
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	// BkBizID 适用的业务，-1表示默认审批链
+	BkBizID int64           `json:"bk_biz_id" validate:"required"`
+	Stages  approval.Stages `json:"stages" validate:"required"`
+	Memo    *string         `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.BkBizID < approval.DefaultChainBizID {
+		return errors.New("bk_biz_id should be > 0 or -1")
+	}
+
+	return req.Stages.Validate()
+}
+
+// ApprovalChainUpdateReq 更新审批链请求
+type ApprovalChainUpdateReq struct {
+	Stages approval.Stages `json:"stages" validate:"omitempty"`
+	Memo   *string         `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Stages) == 0 && req.Memo == nil {
+		return errors.New("stages or memo is required")
+	}
+
+	if len(req.Stages) == 0 {
+		return nil
+	}
+
+	return req.Stages.Validate()
+}
@@ -21,6 +21,7 @@ package application
 
 import (
 	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/approval"
 	"hcm/pkg/criteria/enumor"
 )
 
@@ -38,4 +39,6 @@ type ApplicationGetResp struct {
 	core.Revision  `json:",inline"`
 
 	TicketUrl string `json:"ticket_url"`
+	// ApprovalTicket 内置审批引擎的审批单，仅 source 为 native 时返回
+	ApprovalTicket *approval.Ticket `json:"approval_ticket,omitempty"`
 }
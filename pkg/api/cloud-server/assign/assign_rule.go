@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assign
+
+import (
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateResAssignRuleReq create res assign rule request.
+type CreateResAssignRuleReq struct {
+	Name       string                       `json:"name" validate:"required,lte=255"`
+	ResTypes   []enumor.CloudResourceType   `json:"res_types" validate:"required"`
+	Priority   int64                        `json:"priority" validate:"omitempty"`
+	Enabled    bool                         `json:"enabled" validate:"omitempty"`
+	Conditions cloud.ResAssignRuleCondition `json:"conditions" validate:"required"`
+	BkBizID    int64                        `json:"bk_biz_id" validate:"required,min=1"`
+	// BkCloudID 主机未绑定管控区域时，先将其VPC绑定到该管控区域再分配，-1或不填表示不绑定
+	BkCloudID *int64  `json:"bk_cloud_id" validate:"omitempty"`
+	Memo      *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreateResAssignRuleReq.
+func (req *CreateResAssignRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := cloud.ValidateResAssignRuleResTypes(req.ResTypes); err != nil {
+		return err
+	}
+
+	return req.Conditions.Validate()
+}
+
+// UpdateResAssignRuleReq update res assign rule request, nil field will not be updated.
+type UpdateResAssignRuleReq struct {
+	Name       string                        `json:"name" validate:"lte=255"`
+	ResTypes   []enumor.CloudResourceType    `json:"res_types" validate:"omitempty"`
+	Priority   *int64                        `json:"priority" validate:"omitempty"`
+	Enabled    *bool                         `json:"enabled" validate:"omitempty"`
+	Conditions *cloud.ResAssignRuleCondition `json:"conditions" validate:"omitempty"`
+	BkBizID    int64                         `json:"bk_biz_id" validate:"omitempty,min=1"`
+	BkCloudID  *int64                        `json:"bk_cloud_id" validate:"omitempty"`
+	Memo       *string                       `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate UpdateResAssignRuleReq.
+func (req *UpdateResAssignRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResTypes) != 0 {
+		if err := cloud.ValidateResAssignRuleResTypes(req.ResTypes); err != nil {
+			return err
+		}
+	}
+
+	if req.Conditions != nil {
+		return req.Conditions.Validate()
+	}
+
+	return nil
+}
+
+// DeleteResAssignRuleReq delete res assign rule request.
+type DeleteResAssignRuleReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate DeleteResAssignRuleReq.
+func (req *DeleteResAssignRuleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RunResAssignRuleReq preview or execute res assign rules request.
+type RunResAssignRuleReq struct {
+	// RuleIDs 指定执行的规则，为空时执行所有已启用的规则，预览时可以指定未启用的规则
+	RuleIDs []string `json:"rule_ids" validate:"omitempty,max=100"`
+}
+
+// Validate RunResAssignRuleReq.
+func (req *RunResAssignRuleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ResAssignRuleResult result of preview or execute res assign rules.
+type ResAssignRuleResult struct {
+	// Matched 命中规则的资源，预览时为将要分配的资源
+	Matched []ResAssignMatch `json:"matched"`
+	// Conflicts 命中了多条同优先级且分配目标不同的规则的资源，这些资源不会被分配
+	Conflicts []ResAssignConflict `json:"conflicts"`
+	// Failed 分配失败的资源，仅在执行时返回
+	Failed []ResAssignFailure `json:"failed"`
+	// AssignedCount 分配成功的资源数量，仅在执行时返回
+	AssignedCount int `json:"assigned_count"`
+}
+
+// ResAssignMatch resource matched by res assign rule.
+type ResAssignMatch struct {
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ResID     string                   `json:"res_id"`
+	Name      string                   `json:"name"`
+	AccountID string                   `json:"account_id"`
+	Region    string                   `json:"region"`
+	// RuleID 生效的规则
+	RuleID    string `json:"rule_id"`
+	BkBizID   int64  `json:"bk_biz_id"`
+	BkCloudID int64  `json:"bk_cloud_id"`
+	// MatchedRuleIDs 资源命中的全部规则，按优先级排序
+	MatchedRuleIDs []string `json:"matched_rule_ids"`
+}
+
+// ResAssignConflict resource matched by conflict res assign rules.
+type ResAssignConflict struct {
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ResID     string                   `json:"res_id"`
+	Name      string                   `json:"name"`
+	AccountID string                   `json:"account_id"`
+	RuleIDs   []string                 `json:"rule_ids"`
+}
+
+// ResAssignFailure resource failed to be assigned.
+type ResAssignFailure struct {
+	ResType enumor.CloudResourceType `json:"res_type"`
+	ResID   string                   `json:"res_id"`
+	RuleID  string                   `json:"rule_id"`
+	Reason  string                   `json:"reason"`
+}
@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq create disk snapshot request.
+type CreateReq struct {
+	DiskID string  `json:"disk_id" validate:"required"`
+	Name   string  `json:"name" validate:"required,lte=60"`
+	Memo   *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeleteReq batch delete disk snapshot request.
+type BatchDeleteReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate BatchDeleteReq.
+func (req *BatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RollbackReq rollback source disk with disk snapshot request.
+type RollbackReq struct {
+	ID string `json:"id" validate:"required"`
+	// AutoStopInstance 回滚前是否自动关闭云盘挂载的实例，仅腾讯云支持
+	AutoStopInstance bool `json:"auto_stop_instance" validate:"omitempty"`
+}
+
+// Validate RollbackReq.
+func (req *RollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// rollbackSupportedVendors 支持使用快照原地回滚云盘的云厂商。aws、gcp、azure 只能基于快照创建新的云盘，不支持原地回滚
+var rollbackSupportedVendors = map[enumor.Vendor]struct{}{
+	enumor.TCloud: {},
+	enumor.HuaWei: {},
+}
+
+// IsRollbackSupported 云厂商是否支持使用快照回滚云盘
+func IsRollbackSupported(vendor enumor.Vendor) bool {
+	_, ok := rollbackSupportedVendors[vendor]
+	return ok
+}
+
+// CreatePolicyReq create disk snapshot policy request.
+type CreatePolicyReq struct {
+	Name      string                        `json:"name" validate:"required,lte=255"`
+	ScopeType cloud.DiskSnapshotPolicyScope `json:"scope_type" validate:"required"`
+	// DiskIDs 策略作用的云盘，scope_type 为 disk 时必填
+	DiskIDs []string `json:"disk_ids" validate:"max=500"`
+	// IntervalHours 自动创建快照的间隔小时数
+	IntervalHours uint64 `json:"interval_hours" validate:"required,min=1"`
+	// RetentionCount 每块云盘保留的策略快照数量，超出时删除最早的快照
+	RetentionCount uint64  `json:"retention_count" validate:"required,min=1,max=1000"`
+	Enabled        bool    `json:"enabled" validate:"omitempty"`
+	Memo           *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreatePolicyReq.
+func (req *CreatePolicyReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.ScopeType.Validate(); err != nil {
+		return err
+	}
+
+	switch req.ScopeType {
+	case cloud.DiskScopeSnapshotPolicy:
+		if len(req.DiskIDs) == 0 {
+			return errors.New("disk_ids is required when scope_type is disk")
+		}
+	case cloud.BizScopeSnapshotPolicy:
+		if len(req.DiskIDs) != 0 {
+			return errors.New("disk_ids should be empty when scope_type is biz")
+		}
+	}
+
+	return nil
+}
+
+// UpdatePolicyReq update disk snapshot policy request, nil or empty field will not be updated.
+type UpdatePolicyReq struct {
+	Name           string   `json:"name" validate:"lte=255"`
+	DiskIDs        []string `json:"disk_ids" validate:"max=500"`
+	IntervalHours  uint64   `json:"interval_hours" validate:"omitempty,min=1"`
+	RetentionCount uint64   `json:"retention_count" validate:"omitempty,min=1,max=1000"`
+	Enabled        *bool    `json:"enabled" validate:"omitempty"`
+	Memo           *string  `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate UpdatePolicyReq.
+func (req *UpdatePolicyReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeletePolicyReq batch delete disk snapshot policy request.
+type BatchDeletePolicyReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate BatchDeletePolicyReq.
+func (req *BatchDeletePolicyReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
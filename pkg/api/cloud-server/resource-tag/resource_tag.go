@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag ...
+package restag
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// BatchUpsertReq defines batch create or update resource tags request.
+type BatchUpsertReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"min=1,max=100"`
+	Tags    []core.TagPair           `json:"tags" validate:"min=1,max=50"`
+}
+
+// Validate BatchUpsertReq.
+func (req *BatchUpsertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := validateResType(req.ResType); err != nil {
+		return err
+	}
+
+	keys := make(map[string]struct{}, len(req.Tags))
+	for _, tag := range req.Tags {
+		if len(tag.Key) == 0 {
+			return fmt.Errorf("tag key is required")
+		}
+		if _, exist := keys[tag.Key]; exist {
+			return fmt.Errorf("tag key %s is duplicated", tag.Key)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// BatchDeleteReq defines batch delete resource tags request.
+type BatchDeleteReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"min=1,max=100"`
+	TagKeys []string                 `json:"tag_keys" validate:"min=1,max=50,dive,required"`
+}
+
+// Validate BatchDeleteReq.
+func (req *BatchDeleteReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return validateResType(req.ResType)
+}
+
+// ListReq defines list tags of resources request.
+type ListReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"min=1,max=500"`
+}
+
+// Validate ListReq.
+func (req *ListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return validateResType(req.ResType)
+}
+
+func validateResType(resType enumor.CloudResourceType) error {
+	if _, ok := cloud.ResourceTagResTypes[resType]; !ok {
+		return fmt.Errorf("res_type %s does not support tag", resType)
+	}
+	return nil
+}
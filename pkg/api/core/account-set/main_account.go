@@ -65,6 +65,25 @@ func (e *AwsMainAccountExtension) DecryptSecretKey(cipher cryptography.Crypto) e
 	return nil
 }
 
+// TCloudMainAccountExtension 云主账号/云二级账号扩展字段
+type TCloudMainAccountExtension struct {
+	CloudMainAccountID   string `json:"cloud_main_account_id"`
+	CloudMainAccountName string `json:"cloud_main_account_name"`
+	CloudInitPassword    string `json:"cloud_init_password"`
+}
+
+// DecryptSecretKey ...
+func (e *TCloudMainAccountExtension) DecryptSecretKey(cipher cryptography.Crypto) error {
+	if e.CloudInitPassword != "" {
+		plainSecretKey, err := cipher.DecryptFromBase64(e.CloudInitPassword)
+		if err != nil {
+			return err
+		}
+		e.CloudInitPassword = plainSecretKey
+	}
+	return nil
+}
+
 // GcpMainAccountExtension 云主账号/云二级账号扩展字段
 type GcpMainAccountExtension struct {
 	CloudProjectID   string `json:"cloud_project_id"`
@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package approval defines the core types of the native approval engine.
+package approval
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+const (
+	// DefaultChainBizID 默认审批链的业务ID，业务未单独配置审批链时使用
+	DefaultChainBizID int64 = -1
+	// MaxStageCount 审批链最多支持的审批节点数
+	MaxStageCount = 10
+	// AccountManagerVar 审批人变量，创建审批单时替换为申请单关联账号的负责人
+	AccountManagerVar = "${account_manager}"
+	// PlatformManagerVar 审批人变量，创建审批单时替换为审批流程中配置的平台管理员
+	PlatformManagerVar = "${platform_manager}"
+)
+
+// Stage 审批节点
+type Stage struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Approvers 审批人，支持 ${account_manager}、${platform_manager} 变量
+	Approvers []string            `json:"approvers" validate:"required,min=1,max=50"`
+	Mode      enumor.ApprovalMode `json:"mode" validate:"required"`
+	// TimeoutMin 审批超时时间（分钟），0表示不超时，超时后由 EscalateTo 中的人员接手审批
+	TimeoutMin uint     `json:"timeout_min"`
+	EscalateTo []string `json:"escalate_to,omitempty" validate:"omitempty,max=50"`
+}
+
+// Validate Stage.
+func (s *Stage) Validate() error {
+	if err := validator.Validate.Struct(s); err != nil {
+		return err
+	}
+
+	if err := s.Mode.Validate(); err != nil {
+		return err
+	}
+
+	if s.TimeoutMin > 0 && len(s.EscalateTo) == 0 {
+		return fmt.Errorf("stage %s escalate_to is required when timeout_min is set", s.Name)
+	}
+
+	return nil
+}
+
+// Stages 审批链的审批节点，按顺序审批
+type Stages []Stage
+
+// Validate Stages.
+func (s Stages) Validate() error {
+	if len(s) == 0 {
+		return errors.New("stages is required")
+	}
+
+	if len(s) > MaxStageCount {
+		return fmt.Errorf("stages should <= %d", MaxStageCount)
+	}
+
+	for idx := range s {
+		if err := s[idx].Validate(); err != nil {
+			return fmt.Errorf("stages[%d] is invalid, err: %v", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// Chain 审批链
+type Chain struct {
+	ID              string                 `json:"id"`
+	ApplicationType enumor.ApplicationType `json:"application_type"`
+	// BkBizID 适用的业务，-1表示默认审批链
+	BkBizID       int64   `json:"bk_biz_id"`
+	Stages        Stages  `json:"stages"`
+	Memo          *string `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// TicketStage 审批单中的审批节点，审批人是已替换变量后的实际审批人
+type TicketStage struct {
+	Stage `json:",inline"`
+	// ApprovedBy 已审批通过的人
+	ApprovedBy []string `json:"approved_by"`
+	// Escalated 是否已超时升级
+	Escalated bool `json:"escalated"`
+	// Substituted 超时升级后由升级审批人代为审批的原审批人
+	Substituted []string `json:"substituted,omitempty"`
+	StartedAt   string   `json:"started_at,omitempty"`
+	FinishedAt  string   `json:"finished_at,omitempty"`
+}
+
+// Record 审批记录
+type Record struct {
+	Stage    int64                 `json:"stage"`
+	Operator string                `json:"operator"`
+	Action   enumor.ApprovalAction `json:"action"`
+	Memo     string                `json:"memo,omitempty"`
+	// Delegate 转交时的被转交人，超时升级时的升级审批人
+	Delegate []string `json:"delegate,omitempty"`
+	Time     string   `json:"time"`
+}
+
+// Ticket 内置审批引擎的审批单
+type Ticket struct {
+	ID              string                   `json:"id"`
+	SN              string                   `json:"sn"`
+	ApplicationID   string                   `json:"application_id"`
+	ApplicationType enumor.ApplicationType   `json:"application_type"`
+	BkBizID         int64                    `json:"bk_biz_id"`
+	Applicant       string                   `json:"applicant"`
+	Title           string                   `json:"title"`
+	Status          enumor.ApplicationStatus `json:"status"`
+	CurrentStage    int64                    `json:"current_stage"`
+	Stages          []TicketStage            `json:"stages"`
+	// CurrentApprovers 当前节点待审批人
+	CurrentApprovers []string `json:"current_approvers"`
+	// Deadline 当前节点超时时间，为空表示不超时
+	Deadline      string   `json:"deadline"`
+	Records       []Record `json:"records"`
+	Version       int64    `json:"version"`
+	core.Revision `json:",inline"`
+}
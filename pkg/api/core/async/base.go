@@ -28,14 +28,17 @@ import (
 
 // AsyncFlow ...
 type AsyncFlow struct {
-	ID            string                `json:"id"`
-	Name          enumor.FlowName       `json:"name"`
-	State         enumor.FlowState      `json:"state"`
-	Reason        *tableasync.Reason    `json:"reason"`
-	ShareData     *tableasync.ShareData `json:"share_data"`
-	Memo          string                `json:"memo"`
-	Worker        *string               `json:"worker"`
-	core.Revision `json:",inline"`
+	ID             string                  `json:"id"`
+	Name           enumor.FlowName         `json:"name"`
+	State          enumor.FlowState        `json:"state"`
+	Reason         *tableasync.Reason      `json:"reason"`
+	ShareData      *tableasync.ShareData   `json:"share_data"`
+	Memo           string                  `json:"memo"`
+	Worker         *string                 `json:"worker"`
+	RollbackMode   enumor.FlowRollbackMode `json:"rollback_mode"`
+	Priority       enumor.FlowPriority     `json:"priority"`
+	ConcurrencyKey string                  `json:"concurrency_key"`
+	core.Revision  `json:",inline"`
 }
 
 // AsyncFlowTask ...
@@ -53,3 +56,19 @@ type AsyncFlowTask struct {
 	Reason        *tableasync.Reason `json:"reason"`
 	core.Revision `json:",inline"`
 }
+
+// AsyncScheduledFlow ...
+type AsyncScheduledFlow struct {
+	ID            string                        `json:"id"`
+	Name          string                        `json:"name"`
+	Cron          string                        `json:"cron"`
+	FlowName      enumor.FlowName               `json:"flow_name"`
+	Tasks         tableasync.ScheduledFlowTasks `json:"tasks"`
+	Memo          string                        `json:"memo"`
+	MisfirePolicy enumor.MisfirePolicy          `json:"misfire_policy"`
+	Enable        *bool                         `json:"enable"`
+	LastFlowID    string                        `json:"last_flow_id"`
+	LastRunAt     string                        `json:"last_run_at"`
+	NextRunAt     string                        `json:"next_run_at"`
+	core.Revision `json:",inline"`
+}
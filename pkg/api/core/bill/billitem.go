@@ -28,6 +28,7 @@ import (
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/model"
 	"github.com/shopspring/decimal"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
 )
 
 // BaseBillItem 存储分账后的明细
@@ -98,6 +99,7 @@ type BillItemExtension interface {
 
 // TCloudBillItemExtension ...
 type TCloudBillItemExtension struct {
+	*billing.BillDetail `json:",inline"`
 }
 
 // AwsBillItemExtension ...
@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+// Budget 云账单预算
+type Budget struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	ScopeType  enumor.BudgetScopeType `json:"scope_type"`
+	ScopeValue string                 `json:"scope_value"`
+	Period     enumor.BudgetPeriod    `json:"period"`
+	Amount     *decimal.Decimal       `json:"amount"`
+	Currency   enumor.CurrencyCode    `json:"currency"`
+	// ActualThresholds 实际费用达到预算金额的百分比阈值
+	ActualThresholds []int64 `json:"actual_thresholds"`
+	// ForecastThresholds 预测费用达到预算金额的百分比阈值
+	ForecastThresholds []int64  `json:"forecast_thresholds"`
+	Receivers          []string `json:"receivers"`
+	Memo               *string  `json:"memo"`
+
+	*core.Revision `json:",inline"`
+}
+
+// BudgetRecord 云账单预算执行记录
+type BudgetRecord struct {
+	ID              string              `json:"id"`
+	BudgetID        string              `json:"budget_id"`
+	PeriodKey       string              `json:"period_key"`
+	BillYear        int                 `json:"bill_year"`
+	BillMonth       int                 `json:"bill_month"`
+	BillDay         int                 `json:"bill_day"`
+	Currency        enumor.CurrencyCode `json:"currency"`
+	Amount          *decimal.Decimal    `json:"amount"`
+	ActualCost      *decimal.Decimal    `json:"actual_cost"`
+	ForecastCost    *decimal.Decimal    `json:"forecast_cost"`
+	ActualAlerted   []int64             `json:"actual_alerted"`
+	ForecastAlerted []int64             `json:"forecast_alerted"`
+
+	*core.Revision `json:",inline"`
+}
+
+// ValidateBudgetThresholds 校验预算告警阈值，阈值为预算金额的百分比
+func ValidateBudgetThresholds(thresholds []int64) error {
+	for _, one := range thresholds {
+		if one <= 0 || one > 1000 {
+			return fmt.Errorf("budget threshold %d should be in range (0, 1000]", one)
+		}
+	}
+
+	return nil
+}
@@ -49,6 +49,9 @@ type TCloudAccountExtension struct {
 	CloudSubAccountID  string `json:"cloud_sub_account_id"`
 	CloudSecretID      string `json:"cloud_secret_id"`
 	CloudSecretKey     string `json:"cloud_secret_key,omitempty"`
+	// CloudRoleArn 配置后通过 CAM 角色扮演获取临时凭证访问该账号
+	CloudRoleArn    string `json:"cloud_role_arn,omitempty"`
+	CloudExternalID string `json:"cloud_external_id,omitempty"`
 }
 
 // DecryptSecretKey ...
@@ -70,6 +73,9 @@ type AwsAccountExtension struct {
 	CloudIamUsername string `json:"cloud_iam_username"`
 	CloudSecretID    string `json:"cloud_secret_id"`
 	CloudSecretKey   string `json:"cloud_secret_key,omitempty"`
+	// CloudRoleArn 配置后通过 STS AssumeRole 获取临时凭证访问该账号
+	CloudRoleArn    string `json:"cloud_role_arn,omitempty"`
+	CloudExternalID string `json:"cloud_external_id,omitempty"`
 }
 
 // DecryptSecretKey ...
@@ -94,6 +100,8 @@ type HuaWeiAccountExtension struct {
 	CloudSecretKey       string `json:"cloud_secret_key,omitempty"`
 	CloudIamUserID       string `json:"cloud_iam_user_id" `
 	CloudIamUsername     string `json:"cloud_iam_username"`
+	// CloudAgencyName 配置后通过委托获取临时凭证访问该账号，委托方为 CloudSubAccountName
+	CloudAgencyName string `json:"cloud_agency_name,omitempty"`
 }
 
 // DecryptSecretKey ...
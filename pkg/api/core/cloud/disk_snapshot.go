@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// DiskSnapshot define disk snapshot.
+type DiskSnapshot struct {
+	ID        string        `json:"id"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	CloudID   string        `json:"cloud_id"`
+	Name      string        `json:"name"`
+	BkBizID   int64         `json:"bk_biz_id"`
+	Region    string        `json:"region"`
+	Zone      string        `json:"zone"`
+	// DiskID 源云盘在hcm中的ID，云盘已删除或未同步时为空
+	DiskID      string `json:"disk_id"`
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 源云盘大小，单位GB
+	DiskSize  uint64 `json:"disk_size"`
+	Status    string `json:"status"`
+	Encrypted bool   `json:"encrypted"`
+	// PolicyID 创建该快照的快照策略ID，手动创建或同步的快照为空
+	PolicyID       string                 `json:"policy_id"`
+	CloudCreatedAt string                 `json:"cloud_created_at"`
+	Memo           *string                `json:"memo"`
+	Extension      *DiskSnapshotExtension `json:"extension"`
+	core.Revision  `json:",inline"`
+}
+
+// GetID ...
+func (s DiskSnapshot) GetID() string {
+	return s.ID
+}
+
+// GetCloudID ...
+func (s DiskSnapshot) GetCloudID() string {
+	return s.CloudID
+}
+
+// DiskSnapshotExtension define disk snapshot extension.
+type DiskSnapshotExtension struct {
+	// ResourceGroupName azure 快照所在资源组
+	ResourceGroupName string `json:"resource_group_name,omitempty"`
+	// SelfLink gcp 快照的资源链接
+	SelfLink string `json:"self_link,omitempty"`
+	// Progress 快照创建进度，百分比
+	Progress *int64 `json:"progress,omitempty"`
+	// SnapshotType 快照类型，如tcloud的PRIVATE_SNAPSHOT、SHARED_SNAPSHOT
+	SnapshotType string `json:"snapshot_type,omitempty"`
+}
+
+// DiskSnapshotPolicyScope define disk snapshot policy scope type.
+type DiskSnapshotPolicyScope string
+
+const (
+	// DiskScopeSnapshotPolicy 策略作用于指定的云盘
+	DiskScopeSnapshotPolicy DiskSnapshotPolicyScope = "disk"
+	// BizScopeSnapshotPolicy 策略作用于业务下的全部云盘
+	BizScopeSnapshotPolicy DiskSnapshotPolicyScope = "biz"
+)
+
+// Validate DiskSnapshotPolicyScope.
+func (s DiskSnapshotPolicyScope) Validate() error {
+	switch s {
+	case DiskScopeSnapshotPolicy, BizScopeSnapshotPolicy:
+	default:
+		return fmt.Errorf("unsupported disk snapshot policy scope type: %s", s)
+	}
+
+	return nil
+}
+
+// DiskSnapshotPolicy define disk snapshot policy, the policy creates snapshots for disks in its scope every
+// interval hours, and deletes the oldest policy snapshots of a disk when they exceed the retention count.
+type DiskSnapshotPolicy struct {
+	ID        string                  `json:"id"`
+	Name      string                  `json:"name"`
+	BkBizID   int64                   `json:"bk_biz_id"`
+	ScopeType DiskSnapshotPolicyScope `json:"scope_type"`
+	// DiskIDs 策略范围类型为disk时作用的云盘ID
+	DiskIDs       []string `json:"disk_ids"`
+	IntervalHours uint64   `json:"interval_hours"`
+	// RetentionCount 每块云盘保留的策略快照数量
+	RetentionCount uint64  `json:"retention_count"`
+	Enabled        bool    `json:"enabled"`
+	LastExecutedAt string  `json:"last_executed_at"`
+	Memo           *string `json:"memo"`
+	core.Revision  `json:",inline"`
+}
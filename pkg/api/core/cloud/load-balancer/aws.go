@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+// AwsLoadBalancer ...
+type AwsLoadBalancer = LoadBalancer[AwsLoadBalancerExtension]
+
+// AwsLoadBalancerExtension aws elbv2 load balancer extension.
+type AwsLoadBalancerExtension struct {
+	// Type 负载均衡类型，application：应用型负载均衡(ALB)，network：网络型负载均衡(NLB)，gateway：网关型负载均衡
+	Type *string `json:"type,omitempty"`
+	// Scheme 网络类型，internet-facing：公网，internal：内网
+	Scheme *string `json:"scheme,omitempty"`
+	// IpAddressType IP地址类型，ipv4 | dualstack | dualstack-without-public-ipv4
+	IpAddressType *string `json:"ip_address_type,omitempty"`
+	// CanonicalHostedZoneID 负载均衡关联的Route 53托管区域ID
+	CanonicalHostedZoneID *string `json:"canonical_hosted_zone_id,omitempty"`
+	// CloudSecurityGroupIDs 负载均衡关联的安全组，仅ALB及部分NLB支持
+	CloudSecurityGroupIDs []string `json:"cloud_security_group_ids,omitempty"`
+	// CloudSubnetIDs 负载均衡所在的子网，每个可用区一个
+	CloudSubnetIDs []string `json:"cloud_subnet_ids,omitempty"`
+	// DeletionProtection 是否开启删除保护
+	DeletionProtection *bool `json:"deletion_protection,omitempty"`
+}
+
+// AwsListenerExtension aws 监听器拓展
+type AwsListenerExtension struct {
+	// SslPolicy HTTPS/TLS监听器使用的安全策略
+	SslPolicy *string `json:"ssl_policy,omitempty"`
+	// CertCloudIDs 监听器绑定的证书ARN
+	CertCloudIDs []string `json:"cert_cloud_ids,omitempty"`
+	// AlpnPolicy TLS监听器的ALPN策略
+	AlpnPolicy []string `json:"alpn_policy,omitempty"`
+	// DefaultActionType 默认动作类型，forward | redirect | fixed-response | authenticate-oidc | authenticate-cognito
+	DefaultActionType *string `json:"default_action_type,omitempty"`
+}
+
+// AwsListener ...
+type AwsListener = Listener[AwsListenerExtension]
+
+// AwsTargetGroupExtension aws target group extension.
+type AwsTargetGroupExtension struct {
+	// TargetType 目标类型，instance | ip | lambda | alb
+	TargetType *string `json:"target_type,omitempty"`
+	// ProtocolVersion 协议版本，HTTP1 | HTTP2 | GRPC，仅适用于HTTP/HTTPS目标组
+	ProtocolVersion *string `json:"protocol_version,omitempty"`
+	// IpAddressType 目标组IP地址类型，ipv4 | ipv6
+	IpAddressType *string `json:"ip_address_type,omitempty"`
+	// HealthCheck 健康检查配置
+	HealthCheck *AwsHealthCheckInfo `json:"health_check,omitempty"`
+	// CloudLoadBalancerIDs 目标组关联的负载均衡
+	CloudLoadBalancerIDs []string `json:"cloud_load_balancer_ids,omitempty"`
+}
+
+// AwsHealthCheckInfo aws 目标组健康检查
+type AwsHealthCheckInfo struct {
+	Enabled            *bool   `json:"enabled,omitempty"`
+	Protocol           *string `json:"protocol,omitempty"`
+	Port               *string `json:"port,omitempty"`
+	Path               *string `json:"path,omitempty"`
+	IntervalSeconds    *int64  `json:"interval_seconds,omitempty"`
+	TimeoutSeconds     *int64  `json:"timeout_seconds,omitempty"`
+	HealthyThreshold   *int64  `json:"healthy_threshold,omitempty"`
+	UnhealthyThreshold *int64  `json:"unhealthy_threshold,omitempty"`
+	// Matcher 健康状态码，如 200 或 200-299
+	Matcher *string `json:"matcher,omitempty"`
+}
+
+// AwsTargetGroup ...
+type AwsTargetGroup = TargetGroup[AwsTargetGroupExtension]
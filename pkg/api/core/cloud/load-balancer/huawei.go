@@ -0,0 +1,106 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+// HuaWeiLoadBalancer ...
+type HuaWeiLoadBalancer = LoadBalancer[HuaWeiLoadBalancerExtension]
+
+// HuaWeiLoadBalancerExtension huawei elb extension.
+type HuaWeiLoadBalancerExtension struct {
+	// Guaranteed 是否独享型负载均衡，true：独享型，false：共享型
+	Guaranteed *bool `json:"guaranteed,omitempty"`
+	// L4FlavorID 四层规格ID
+	L4FlavorID *string `json:"l4_flavor_id,omitempty"`
+	// L7FlavorID 七层规格ID
+	L7FlavorID *string `json:"l7_flavor_id,omitempty"`
+	// VipPortID 负载均衡器IPv4对应的port ID
+	VipPortID *string `json:"vip_port_id,omitempty"`
+	// CloudElbVirsubnetIDs 下联面子网的网络ID列表
+	CloudElbVirsubnetIDs []string `json:"cloud_elb_virsubnet_ids,omitempty"`
+	// CloudEipIDs 负载均衡绑定的EIP
+	CloudEipIDs []string `json:"cloud_eip_ids,omitempty"`
+	// IpTargetEnable 是否启用跨VPC后端转发
+	IpTargetEnable *bool `json:"ip_target_enable,omitempty"`
+	// DeletionProtectionEnable 是否开启删除保护
+	DeletionProtectionEnable *bool `json:"deletion_protection_enable,omitempty"`
+	// ChargeMode 计费模式，flavor：按规格计费，lcu：按使用量计费
+	ChargeMode *string `json:"charge_mode,omitempty"`
+	// EnterpriseProjectID 企业项目ID
+	EnterpriseProjectID *string `json:"enterprise_project_id,omitempty"`
+}
+
+// HuaWeiListenerExtension 华为云监听器拓展
+type HuaWeiListenerExtension struct {
+	// CloudDefaultPoolID 监听器的默认后端服务器组
+	CloudDefaultPoolID *string `json:"cloud_default_pool_id,omitempty"`
+	// DefaultTlsContainerRef 监听器使用的服务器证书ID
+	DefaultTlsContainerRef *string `json:"default_tls_container_ref,omitempty"`
+	// ClientCaTlsContainerRef 监听器使用的CA证书ID
+	ClientCaTlsContainerRef *string `json:"client_ca_tls_container_ref,omitempty"`
+	// SniContainerRefs 监听器使用的SNI证书ID列表
+	SniContainerRefs []string `json:"sni_container_refs,omitempty"`
+	// TlsCiphersPolicy 监听器使用的安全策略
+	TlsCiphersPolicy *string `json:"tls_ciphers_policy,omitempty"`
+	// Http2Enable 客户端与LB之间的HTTPS请求的HTTP2功能的开启状态
+	Http2Enable *bool `json:"http2_enable,omitempty"`
+	// EnhanceL7policyEnable 是否开启高级转发策略
+	EnhanceL7policyEnable *bool `json:"enhance_l7policy_enable,omitempty"`
+}
+
+// HuaWeiListener ...
+type HuaWeiListener = Listener[HuaWeiListenerExtension]
+
+// HuaWeiTargetGroupExtension huawei target group(pool) extension.
+type HuaWeiTargetGroupExtension struct {
+	// LbAlgorithm 后端云服务器组的负载均衡算法，ROUND_ROBIN | LEAST_CONNECTIONS | SOURCE_IP | QUIC_CID
+	LbAlgorithm *string `json:"lb_algorithm,omitempty"`
+	// SessionPersistenceType 会话保持类型，SOURCE_IP | HTTP_COOKIE | APP_COOKIE
+	SessionPersistenceType *string `json:"session_persistence_type,omitempty"`
+	// SessionPersistenceTimeout 会话保持时间，单位：分钟
+	SessionPersistenceTimeout *int64 `json:"session_persistence_timeout,omitempty"`
+	// IpVersion 后端云服务器组支持的IP版本，v4 | dualstack
+	IpVersion *string `json:"ip_version,omitempty"`
+	// MemberDeletionProtectionEnable 是否开启误删保护
+	MemberDeletionProtectionEnable *bool `json:"member_deletion_protection_enable,omitempty"`
+	// CloudHealthMonitorID 后端服务器组关联的健康检查ID
+	CloudHealthMonitorID *string `json:"cloud_health_monitor_id,omitempty"`
+	// CloudListenerIDs 后端服务器组关联的监听器
+	CloudListenerIDs []string `json:"cloud_listener_ids,omitempty"`
+	// CloudLoadBalancerIDs 后端服务器组关联的负载均衡
+	CloudLoadBalancerIDs []string `json:"cloud_load_balancer_ids,omitempty"`
+}
+
+// HuaWeiHealthCheckInfo 华为云后端服务器组健康检查
+type HuaWeiHealthCheckInfo struct {
+	AdminStateUp   *bool   `json:"admin_state_up,omitempty"`
+	Type           *string `json:"type,omitempty"`
+	Delay          *int32  `json:"delay,omitempty"`
+	Timeout        *int32  `json:"timeout,omitempty"`
+	MaxRetries     *int32  `json:"max_retries,omitempty"`
+	MonitorPort    *int32  `json:"monitor_port,omitempty"`
+	DomainName     *string `json:"domain_name,omitempty"`
+	UrlPath        *string `json:"url_path,omitempty"`
+	HttpMethod     *string `json:"http_method,omitempty"`
+	ExpectedCodes  *string `json:"expected_codes,omitempty"`
+	MaxRetriesDown *int32  `json:"max_retries_down,omitempty"`
+}
+
+// HuaWeiTargetGroup ...
+type HuaWeiTargetGroup = TargetGroup[HuaWeiTargetGroupExtension]
@@ -91,7 +91,7 @@ func (lb LoadBalancer[T]) GetCloudID() string {
 
 // Extension extension.
 type Extension interface {
-	TCloudClbExtension
+	TCloudClbExtension | AwsLoadBalancerExtension | HuaWeiLoadBalancerExtension
 }
 
 // BaseListener define base listener.
@@ -136,7 +136,7 @@ func (lbl Listener[T]) GetCloudID() string {
 
 // ListenerExtension 监听器拓展
 type ListenerExtension interface {
-	TCloudListenerExtension
+	TCloudListenerExtension | AwsListenerExtension | HuaWeiListenerExtension
 }
 
 // TCloudLbUrlRule define base tcloud lb url rule.
@@ -65,7 +65,7 @@ func (tg TargetGroup[T]) GetCloudID() string {
 
 // TargetGroupExtension extension.
 type TargetGroupExtension interface {
-	TCloudTargetGroupExtension
+	TCloudTargetGroupExtension | AwsTargetGroupExtension | HuaWeiTargetGroupExtension
 }
 
 // BaseTarget define base target.
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResAssignRule define resource auto assign rule.
+type ResAssignRule struct {
+	ID       string                     `json:"id"`
+	Name     string                     `json:"name"`
+	ResTypes []enumor.CloudResourceType `json:"res_types"`
+	// Priority 优先级，数值越小优先级越高
+	Priority   int64                  `json:"priority"`
+	Enabled    bool                   `json:"enabled"`
+	Conditions ResAssignRuleCondition `json:"conditions"`
+	BkBizID    int64                  `json:"bk_biz_id"`
+	// BkCloudID 主机未绑定管控区域时，其VPC绑定的管控区域，-1表示不绑定
+	BkCloudID     int64   `json:"bk_cloud_id"`
+	Memo          *string `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// ResAssignRuleCondition 资源自动分配规则的匹配条件，各条件之间为与关系，同一条件的多个取值之间为或关系。
+type ResAssignRuleCondition struct {
+	AccountIDs  []string `json:"account_ids" validate:"min=1,max=100"`
+	Regions     []string `json:"regions,omitempty" validate:"max=100"`
+	CloudVpcIDs []string `json:"cloud_vpc_ids,omitempty" validate:"max=100"`
+	// NameRegex 资源名称需匹配的正则表达式
+	NameRegex string `json:"name_regex,omitempty" validate:"lte=255"`
+	// Tags 资源需包含全部标签
+	Tags []core.TagPair `json:"tags,omitempty" validate:"max=20"`
+	// Cidrs 主机内网IP或弹性IP地址所在网段
+	Cidrs []string `json:"cidrs,omitempty" validate:"max=100"`
+}
+
+// Validate ResAssignRuleCondition.
+func (c *ResAssignRuleCondition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	if len(c.NameRegex) != 0 {
+		if _, err := regexp.Compile(c.NameRegex); err != nil {
+			return fmt.Errorf("name_regex is invalid, err: %v", err)
+		}
+	}
+
+	for _, tag := range c.Tags {
+		if len(tag.Key) == 0 {
+			return errors.New("tag key is required")
+		}
+	}
+
+	for _, cidr := range c.Cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("cidr %s is invalid, err: %v", cidr, err)
+		}
+	}
+
+	return nil
+}
+
+// ResAssignRuleResTypes 支持自动分配的资源类型
+var ResAssignRuleResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:  {},
+	enumor.DiskCloudResType: {},
+	enumor.EipCloudResType:  {},
+}
+
+// ValidateResAssignRuleResTypes validate res types of res assign rule.
+func ValidateResAssignRuleResTypes(resTypes []enumor.CloudResourceType) error {
+	if len(resTypes) == 0 {
+		return errors.New("res_types is required")
+	}
+
+	for _, resType := range resTypes {
+		if _, ok := ResAssignRuleResTypes[resType]; !ok {
+			return fmt.Errorf("res_type %s does not support auto assign", resType)
+		}
+	}
+
+	return nil
+}
@@ -37,3 +37,15 @@ type AccountSyncDetailTable struct {
 	CreatedAt       types.Time      `json:"created_at"`
 	UpdatedAt       types.Time      `json:"updated_at"`
 }
+
+// SyncCursor 同步游标，记录周期性同步已处理到的位置
+type SyncCursor struct {
+	ID          string                `json:"id"`
+	Type        enumor.SyncCursorType `json:"type"`
+	Scope       string                `json:"scope"`
+	CursorValue string                `json:"cursor_value"`
+	Creator     string                `json:"creator"`
+	Reviser     string                `json:"reviser"`
+	CreatedAt   types.Time            `json:"created_at"`
+	UpdatedAt   types.Time            `json:"updated_at"`
+}
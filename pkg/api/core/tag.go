@@ -21,6 +21,7 @@ package core
 
 import (
 	"maps"
+	"slices"
 )
 
 // TagMap  tag collection for key-value pair
@@ -44,6 +45,20 @@ func (m TagMap) Map() map[string]string {
 	return dst
 }
 
+// Pairs return tag pairs sorted by key
+func (m TagMap) Pairs() []TagPair {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	pairs := make([]TagPair, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, TagPair{Key: k, Value: m[k]})
+	}
+	return pairs
+}
+
 // TagPair key-value Pair
 type TagPair struct {
 	Key   string `json:"key"`
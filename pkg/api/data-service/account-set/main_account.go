@@ -35,7 +35,8 @@ import (
 type MainAccountExtensionCreateReq interface {
 	AwsMainAccountExtensionCreateReq | GcpMainAccountExtensionCreateReq |
 		AzureMainAccountExtensionCreateReq | HuaWeiMainAccountExtensionCreateReq |
-		ZenlayerMainAccountExtensionCreateReq | KaopuMainAccountExtensionCreateReq
+		ZenlayerMainAccountExtensionCreateReq | KaopuMainAccountExtensionCreateReq |
+		TCloudMainAccountExtensionCreateReq
 }
 
 // AwsMainAccountExtensionCreateReq ...
@@ -46,8 +47,30 @@ type AwsMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *AwsMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+func (req *AwsMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
+}
+
+// TCloudMainAccountExtensionCreateReq ...
+type TCloudMainAccountExtensionCreateReq struct {
+	CloudMainAccountID   string `json:"cloud_main_account_id"`
+	CloudMainAccountName string `json:"cloud_main_account_name"`
+	CloudInitPassword    string `json:"cloud_init_password"`
+}
+
+// EncryptSecretKey encrypt secret key
+func (req *TCloudMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
 }
 
 // GcpMainAccountExtensionCreateReq ...
@@ -57,8 +80,9 @@ type GcpMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *GcpMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *GcpMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	// nothing to encrypt
+	return nil
 }
 
 // AzureMainAccountExtensionCreateReq ...
@@ -69,9 +93,14 @@ type AzureMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *AzureMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *AzureMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	// nothing to encrypt
-	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
 }
 
 // HuaWeiMainAccountExtensionCreateReq ...
@@ -82,8 +111,13 @@ type HuaWeiMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *HuaWeiMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+func (req *HuaWeiMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
 }
 
 // ZenlayerMainAccountExtensionCreateReq ...
@@ -94,8 +128,13 @@ type ZenlayerMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *ZenlayerMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+func (req *ZenlayerMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
 }
 
 // KaopuMainAccountExtensionCreateReq ...
@@ -106,8 +145,13 @@ type KaopuMainAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *KaopuMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+func (req *KaopuMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudInitPassword)
+	if err != nil {
+		return err
+	}
+	req.CloudInitPassword = encrypted
+	return nil
 }
 
 // MainAccountCreateReq ...
@@ -167,7 +211,8 @@ type MainAccountGetBaseResp struct {
 type MainAccountExtensionGetResp interface {
 	protocore.AwsMainAccountExtension | protocore.GcpMainAccountExtension |
 		protocore.HuaWeiMainAccountExtension | protocore.AzureMainAccountExtension |
-		protocore.ZenlayerMainAccountExtension | protocore.KaopuMainAccountExtension
+		protocore.ZenlayerMainAccountExtension | protocore.KaopuMainAccountExtension |
+		protocore.TCloudMainAccountExtension
 }
 
 // MainAccountGetResult defines get main account result.
@@ -198,7 +243,7 @@ type MainAccountListResp struct {
 // SecretEncryptor 用于加密"泛型"Extension密钥
 type SecretEncryptor[T MainAccountExtensionCreateReq] interface {
 	// EncryptSecretKey 加密约束，将密钥进行加密设置
-	EncryptSecretKey(cryptography.Crypto)
+	EncryptSecretKey(cryptography.Crypto) error
 	*T
 }
 
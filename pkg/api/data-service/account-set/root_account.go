@@ -35,7 +35,8 @@ import (
 type RootAccountExtensionCreateReq interface {
 	AwsRootAccountExtensionCreateReq | GcpRootAccountExtensionCreateReq |
 		AzureRootAccountExtensionCreateReq | HuaWeiRootAccountExtensionCreateReq |
-		ZenlayerRootAccountExtensionCreateReq | KaopuRootAccountExtensionCreateReq
+		ZenlayerRootAccountExtensionCreateReq | KaopuRootAccountExtensionCreateReq |
+		TCloudRootAccountExtensionCreateReq
 }
 
 // AwsRootAccountExtensionCreateReq ...
@@ -47,8 +48,31 @@ type AwsRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *AwsRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+func (req *AwsRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
+}
+
+// TCloudRootAccountExtensionCreateReq ...
+type TCloudRootAccountExtensionCreateReq struct {
+	CloudMainAccountID string `json:"cloud_main_account_id" validate:"required"`
+	CloudSubAccountID  string `json:"cloud_sub_account_id" validate:"required"`
+	CloudSecretID      string `json:"cloud_secret_id" validate:"omitempty"`
+	CloudSecretKey     string `json:"cloud_secret_key" validate:"omitempty"`
+}
+
+// EncryptSecretKey encrypt secret key
+func (req *TCloudRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
 }
 
 // GcpRootAccountExtensionCreateReq ...
@@ -63,8 +87,13 @@ type GcpRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *GcpRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudServiceSecretKey = cipher.EncryptToBase64(req.CloudServiceSecretKey)
+func (req *GcpRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudServiceSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudServiceSecretKey = encrypted
+	return nil
 }
 
 // AzureRootAccountExtensionCreateReq ...
@@ -79,8 +108,13 @@ type AzureRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey encrypt secret key
-func (req *AzureRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudClientSecretKey = cipher.EncryptToBase64(req.CloudClientSecretKey)
+func (req *AzureRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudClientSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudClientSecretKey = encrypted
+	return nil
 }
 
 // HuaWeiRootAccountExtensionCreateReq ...
@@ -94,8 +128,13 @@ type HuaWeiRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *HuaWeiRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+func (req *HuaWeiRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
 }
 
 // ZenlayerRootAccountExtensionCreateReq ...
@@ -104,8 +143,8 @@ type ZenlayerRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *ZenlayerRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-
+func (req *ZenlayerRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	return nil
 }
 
 // KaopuRootAccountExtensionCreateReq ...
@@ -114,7 +153,8 @@ type KaopuRootAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *KaopuRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *KaopuRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	return nil
 }
 
 // RootAccountCreateReq ...
@@ -141,7 +181,8 @@ func (c *RootAccountCreateReq[T]) Validate() error {
 type RootAccountExtensionUpdateReq interface {
 	AwsRootAccountExtensionUpdateReq | GcpRootAccountExtensionUpdateReq |
 		HuaWeiRootAccountExtensionUpdateReq | AzureRootAccountExtensionUpdateReq |
-		ZenlayerRootAccountExtensionUpdateReq | KaopuRootAccountExtensionUpdateReq
+		ZenlayerRootAccountExtensionUpdateReq | KaopuRootAccountExtensionUpdateReq |
+		TCloudRootAccountExtensionUpdateReq
 }
 
 // AwsRootAccountExtensionUpdateReq ...
@@ -153,11 +194,35 @@ type AwsRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *AwsRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *AwsRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudSecretKey != nil {
-		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudSecretKey = &encryptedCloudSecretKey
 	}
+	return nil
+}
+
+// TCloudRootAccountExtensionUpdateReq ...
+type TCloudRootAccountExtensionUpdateReq struct {
+	CloudMainAccountID string  `json:"cloud_main_account_id,omitempty" validate:"omitempty"`
+	CloudSubAccountID  string  `json:"cloud_sub_account_id,omitempty" validate:"omitempty"`
+	CloudSecretID      *string `json:"cloud_secret_id,omitempty" validate:"omitempty"`
+	CloudSecretKey     *string `json:"cloud_secret_key,omitempty" validate:"omitempty"`
+}
+
+// EncryptSecretKey ...
+func (req *TCloudRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	if req.CloudSecretKey != nil {
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
+		req.CloudSecretKey = &encryptedCloudSecretKey
+	}
+	return nil
 }
 
 // HuaWeiRootAccountExtensionUpdateReq ...
@@ -171,11 +236,15 @@ type HuaWeiRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *HuaWeiRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *HuaWeiRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudSecretKey != nil {
-		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudSecretKey = &encryptedCloudSecretKey
 	}
+	return nil
 }
 
 // GcpRootAccountExtensionUpdateReq ...
@@ -192,11 +261,15 @@ type GcpRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *GcpRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *GcpRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudServiceSecretKey != nil {
-		encryptedCloudServiceSecretKey := cipher.EncryptToBase64(*req.CloudServiceSecretKey)
+		encryptedCloudServiceSecretKey, err := cipher.EncryptToBase64(*req.CloudServiceSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudServiceSecretKey = &encryptedCloudServiceSecretKey
 	}
+	return nil
 }
 
 // AzureRootAccountExtensionUpdateReq ...
@@ -212,11 +285,15 @@ type AzureRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *AzureRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *AzureRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudClientSecretKey != nil {
-		encryptedCloudClientSecretKey := cipher.EncryptToBase64(*req.CloudClientSecretKey)
+		encryptedCloudClientSecretKey, err := cipher.EncryptToBase64(*req.CloudClientSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudClientSecretKey = &encryptedCloudClientSecretKey
 	}
+	return nil
 }
 
 // ZenlayerRootAccountExtensionUpdateReq ...
@@ -224,8 +301,8 @@ type ZenlayerRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *ZenlayerRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-
+func (req *ZenlayerRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	return nil
 }
 
 // KaopuRootAccountExtensionUpdateReq ...
@@ -233,8 +310,8 @@ type KaopuRootAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *KaopuRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-
+func (req *KaopuRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	return nil
 }
 
 // RootAccountUpdateReq 不允许对extension更新，允许更新字段：负责人/备份负责人/组织架构/运营产品/业务，单独更新状态
@@ -269,7 +346,8 @@ type RootAccountGetBaseResp struct {
 type RootAccountExtensionGetResp interface {
 	protocore.AwsRootAccountExtension | protocore.GcpRootAccountExtension |
 		protocore.HuaWeiRootAccountExtension | protocore.AzureRootAccountExtension |
-		protocore.ZenlayerRootAccountExtension | protocore.KaopuRootAccountExtension
+		protocore.ZenlayerRootAccountExtension | protocore.KaopuRootAccountExtension |
+		protocore.TCloudRootAccountExtension
 }
 
 // RootAccountGetResult ...
@@ -281,6 +359,9 @@ type RootAccountGetResult[T RootAccountExtensionGetResp] struct {
 // AwsRootAccount ...
 type AwsRootAccount = RootAccountGetResult[protocore.AwsRootAccountExtension]
 
+// TCloudRootAccount ...
+type TCloudRootAccount = RootAccountGetResult[protocore.TCloudRootAccountExtension]
+
 // HuaweiRootAccount ...
 type HuaweiRootAccount = RootAccountGetResult[protocore.HuaWeiRootAccountExtension]
 
@@ -308,7 +389,7 @@ type RootAccountListResp struct {
 // RootSecretEncryptor ... 用于加密"泛型"Extension密钥
 type RootSecretEncryptor[T RootAccountExtensionCreateReq | RootAccountExtensionUpdateReq] interface {
 	// EncryptSecretKey 加密约束，将密钥进行加密设置
-	EncryptSecretKey(cryptography.Crypto)
+	EncryptSecretKey(cryptography.Crypto) error
 	*T
 }
 
@@ -0,0 +1,149 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dataservice
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApprovalChainBatchCreateReq defines batch create approval chain request.
+type ApprovalChainBatchCreateReq struct {
+	Chains []ApprovalChainCreate `json:"chains" validate:"min=1,max=100,dive"`
+}
+
+// ApprovalChainCreate defines approval chain to create.
+type ApprovalChainCreate struct {
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	BkBizID         int64                  `json:"bk_biz_id" validate:"required"`
+	Stages          approval.Stages        `json:"stages" validate:"required"`
+	Memo            *string                `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ApprovalChainBatchCreateReq.
+func (req *ApprovalChainBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Chains {
+		if one.BkBizID < -1 {
+			return errors.New("bk_biz_id should be > 0 or -1")
+		}
+
+		if err := one.Stages.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApprovalChainBatchUpdateReq defines batch update approval chain request.
+type ApprovalChainBatchUpdateReq struct {
+	Chains []ApprovalChainUpdate `json:"chains" validate:"min=1,max=100,dive"`
+}
+
+// ApprovalChainUpdate defines approval chain to update, nil field will not be updated.
+type ApprovalChainUpdate struct {
+	ID     string          `json:"id" validate:"required"`
+	Stages approval.Stages `json:"stages" validate:"omitempty"`
+	Memo   *string         `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ApprovalChainBatchUpdateReq.
+func (req *ApprovalChainBatchUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Chains {
+		if len(one.Stages) == 0 {
+			continue
+		}
+
+		if err := one.Stages.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApprovalChainListResult defines list approval chain result.
+type ApprovalChainListResult struct {
+	Count   uint64           `json:"count"`
+	Details []approval.Chain `json:"details"`
+}
+
+// ApprovalTicketCreateReq defines create approval ticket request.
+type ApprovalTicketCreateReq struct {
+	SN               string                   `json:"sn" validate:"required,lte=64"`
+	ApplicationID    string                   `json:"application_id" validate:"required,lte=64"`
+	ApplicationType  enumor.ApplicationType   `json:"application_type" validate:"required"`
+	BkBizID          int64                    `json:"bk_biz_id" validate:"required"`
+	Applicant        string                   `json:"applicant" validate:"required,lte=64"`
+	Title            string                   `json:"title" validate:"lte=255"`
+	Status           enumor.ApplicationStatus `json:"status" validate:"required"`
+	CurrentStage     int64                    `json:"current_stage" validate:"min=0"`
+	Stages           []approval.TicketStage   `json:"stages" validate:"required,min=1"`
+	CurrentApprovers []string                 `json:"current_approvers" validate:"required"`
+	Deadline         string                   `json:"deadline" validate:"lte=32"`
+	Records          []approval.Record        `json:"records" validate:"omitempty"`
+}
+
+// Validate ApprovalTicketCreateReq.
+func (req *ApprovalTicketCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalTicketUpdateReq defines update approval ticket request, it replaces the whole approval progress
+// of the ticket, and only succeeds when the version of the ticket equals to the version in request.
+type ApprovalTicketUpdateReq struct {
+	ID               string                   `json:"id" validate:"required"`
+	Version          int64                    `json:"version" validate:"min=0"`
+	Status           enumor.ApplicationStatus `json:"status" validate:"required"`
+	CurrentStage     int64                    `json:"current_stage" validate:"min=0"`
+	Stages           []approval.TicketStage   `json:"stages" validate:"required,min=1"`
+	CurrentApprovers []string                 `json:"current_approvers" validate:"omitempty"`
+	Deadline         string                   `json:"deadline" validate:"lte=32"`
+	Records          []approval.Record        `json:"records" validate:"omitempty"`
+	// ApplicationID、ApplicationStatus 审批单结束时同步更新的申请单及其状态，与审批单在同一事务中更新，仅更新审批中的申请单
+	ApplicationID     string                   `json:"application_id" validate:"omitempty"`
+	ApplicationStatus enumor.ApplicationStatus `json:"application_status" validate:"omitempty"`
+}
+
+// Validate ApprovalTicketUpdateReq.
+func (req *ApprovalTicketUpdateReq) Validate() error {
+	if len(req.ApplicationStatus) != 0 && len(req.ApplicationID) == 0 {
+		return errors.New("application_id is required when application_status is set")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalTicketListResult defines list approval ticket result.
+type ApprovalTicketListResult struct {
+	Count   uint64            `json:"count"`
+	Details []approval.Ticket `json:"details"`
+}
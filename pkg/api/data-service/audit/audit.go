@@ -21,13 +21,16 @@
 package audit
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	coreasync "hcm/pkg/api/core/async"
 	"hcm/pkg/api/core/audit"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/rest"
 )
 
@@ -141,6 +144,8 @@ func (o *OperationAction) ConvAuditAction() (enumor.AuditAction, error) {
 		return enumor.Associate, nil
 	case Disassociate:
 		return enumor.Disassociate, nil
+	case Rollback:
+		return enumor.Rollback, nil
 
 	default:
 		return "", fmt.Errorf("action is not corresponding audit action")
@@ -157,6 +162,8 @@ const (
 	Associate OperationAction = "associate"
 	// Disassociate 解绑、解挂载等操作
 	Disassociate OperationAction = "disassociate"
+	// Rollback 使用快照回滚云盘等操作
+	Rollback OperationAction = "rollback"
 )
 
 // CloudResourceOperationAuditReq define cloud resource operation audit req.
@@ -247,3 +254,61 @@ type GetAsyncTaskResp struct {
 	Flow  *coreasync.AsyncFlow      `json:"flow"`
 	Tasks []coreasync.AsyncFlowTask `json:"tasks"`
 }
+
+// -------------------------- Verify Audit Chain --------------------------
+
+// VerifyChainMaxDays 单次校验审计哈希链的最大天数
+const VerifyChainMaxDays = 31
+
+// VerifyChainReq defines verify audit hash chain request, audit records are chained by day.
+type VerifyChainReq struct {
+	// Start 开始日期，格式 2006-01-02
+	Start string `json:"start" validate:"required"`
+	// End 结束日期，格式 2006-01-02，包含当天
+	End string `json:"end" validate:"required"`
+}
+
+// Validate VerifyChainReq.
+func (req *VerifyChainReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	_, err := req.Days()
+	return err
+}
+
+// Days 返回需要校验哈希链的日期，每天按分片可能有多条哈希链
+func (req *VerifyChainReq) Days() ([]string, error) {
+	start, err := time.Parse(constant.DateLayout, req.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %s, err: %v", req.Start, err)
+	}
+
+	end, err := time.Parse(constant.DateLayout, req.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %s, err: %v", req.End, err)
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end date should not be before start date")
+	}
+
+	if end.Sub(start) >= VerifyChainMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("date range should <= %d days", VerifyChainMaxDays)
+	}
+
+	keys := make([]string, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		keys = append(keys, day.Format(constant.DateLayout))
+	}
+
+	return keys, nil
+}
+
+// VerifyChainResult defines verify audit hash chain result.
+type VerifyChainResult struct {
+	// Intact 所有哈希链是否完整
+	Intact bool                           `json:"intact"`
+	Chains []types.AuditChainVerifyResult `json:"chains"`
+}
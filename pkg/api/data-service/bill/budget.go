@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// BudgetCreateReq create budget request
+type BudgetCreateReq struct {
+	Name               string                 `json:"name" validate:"required,max=255"`
+	ScopeType          enumor.BudgetScopeType `json:"scope_type" validate:"required"`
+	ScopeValue         string                 `json:"scope_value" validate:"required,max=64"`
+	Period             enumor.BudgetPeriod    `json:"period" validate:"required"`
+	Amount             *decimal.Decimal       `json:"amount" validate:"required"`
+	Currency           enumor.CurrencyCode    `json:"currency" validate:"required"`
+	ActualThresholds   []int64                `json:"actual_thresholds" validate:"omitempty"`
+	ForecastThresholds []int64                `json:"forecast_thresholds" validate:"omitempty"`
+	Receivers          []string               `json:"receivers" validate:"omitempty"`
+	Memo               *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *BudgetCreateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if err := r.ScopeType.Validate(); err != nil {
+		return err
+	}
+
+	if r.ScopeType == enumor.BudgetScopeBiz || r.ScopeType == enumor.BudgetScopeProduct {
+		if _, err := strconv.ParseInt(r.ScopeValue, 10, 64); err != nil {
+			return fmt.Errorf("scope_value of %s should be an integer id", r.ScopeType)
+		}
+	}
+
+	if err := r.Period.Validate(); err != nil {
+		return err
+	}
+
+	if !r.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+
+	if err := bill.ValidateBudgetThresholds(r.ActualThresholds); err != nil {
+		return err
+	}
+
+	return bill.ValidateBudgetThresholds(r.ForecastThresholds)
+}
+
+// BudgetUpdateReq update budget request, scope and period can not be updated.
+type BudgetUpdateReq struct {
+	ID                 string              `json:"id" validate:"required"`
+	Name               string              `json:"name" validate:"omitempty,max=255"`
+	Amount             *decimal.Decimal    `json:"amount" validate:"omitempty"`
+	Currency           enumor.CurrencyCode `json:"currency" validate:"omitempty"`
+	ActualThresholds   []int64             `json:"actual_thresholds" validate:"omitempty"`
+	ForecastThresholds []int64             `json:"forecast_thresholds" validate:"omitempty"`
+	Receivers          []string            `json:"receivers" validate:"omitempty"`
+	Memo               *string             `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *BudgetUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.Amount != nil && !r.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+
+	if err := bill.ValidateBudgetThresholds(r.ActualThresholds); err != nil {
+		return err
+	}
+
+	return bill.ValidateBudgetThresholds(r.ForecastThresholds)
+}
+
+// BudgetListResult list budget result
+type BudgetListResult = core.ListResultT[bill.Budget]
+
+// BudgetRecordCreateReq create budget record request
+type BudgetRecordCreateReq struct {
+	BudgetID        string              `json:"budget_id" validate:"required"`
+	PeriodKey       string              `json:"period_key" validate:"required,max=16"`
+	BillYear        int                 `json:"bill_year" validate:"required"`
+	BillMonth       int                 `json:"bill_month" validate:"required,min=1,max=12"`
+	BillDay         int                 `json:"bill_day" validate:"required,min=1,max=31"`
+	Currency        enumor.CurrencyCode `json:"currency" validate:"required"`
+	Amount          *decimal.Decimal    `json:"amount" validate:"required"`
+	ActualCost      *decimal.Decimal    `json:"actual_cost" validate:"required"`
+	ForecastCost    *decimal.Decimal    `json:"forecast_cost" validate:"required"`
+	ActualAlerted   []int64             `json:"actual_alerted" validate:"omitempty"`
+	ForecastAlerted []int64             `json:"forecast_alerted" validate:"omitempty"`
+}
+
+// Validate ...
+func (r *BudgetRecordCreateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BudgetRecordUpdateReq update budget record request
+type BudgetRecordUpdateReq struct {
+	ID              string           `json:"id" validate:"required"`
+	Amount          *decimal.Decimal `json:"amount" validate:"omitempty"`
+	ActualCost      *decimal.Decimal `json:"actual_cost" validate:"omitempty"`
+	ForecastCost    *decimal.Decimal `json:"forecast_cost" validate:"omitempty"`
+	ActualAlerted   []int64          `json:"actual_alerted" validate:"omitempty"`
+	ForecastAlerted []int64          `json:"forecast_alerted" validate:"omitempty"`
+}
+
+// Validate ...
+func (r *BudgetRecordUpdateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BudgetRecordListResult list budget record result
+type BudgetRecordListResult = core.ListResultT[bill.BudgetRecord]
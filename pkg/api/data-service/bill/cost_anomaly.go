@@ -0,0 +1,85 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostAnomalyCreateReq create cost anomaly request
+type CostAnomalyCreateReq struct {
+	RootAccountID string                        `json:"root_account_id" validate:"required"`
+	MainAccountID string                        `json:"main_account_id" validate:"required"`
+	Vendor        enumor.Vendor                 `json:"vendor" validate:"required"`
+	ProductID     int64                         `json:"product_id" validate:"omitempty"`
+	BkBizID       int64                         `json:"bk_biz_id" validate:"omitempty"`
+	BillYear      int                           `json:"bill_year" validate:"required"`
+	BillMonth     int                           `json:"bill_month" validate:"required,min=1,max=12"`
+	BillDay       int                           `json:"bill_day" validate:"required,min=1,max=31"`
+	Currency      enumor.CurrencyCode           `json:"currency" validate:"required"`
+	Cost          *decimal.Decimal              `json:"cost" validate:"required"`
+	Baseline      *decimal.Decimal              `json:"baseline" validate:"required"`
+	Deviation     *decimal.Decimal              `json:"deviation" validate:"required"`
+	Score         *decimal.Decimal              `json:"score" validate:"required"`
+	Severity      enumor.CostAnomalySeverity    `json:"severity" validate:"required"`
+	Contributors  []bill.CostAnomalyContributor `json:"contributors" validate:"omitempty"`
+	Notified      bool                          `json:"notified" validate:"omitempty"`
+}
+
+// Validate ...
+func (r *CostAnomalyCreateReq) Validate() error {
+	if err := r.Severity.Validate(); err != nil {
+		return err
+	}
+
+	return validator.Validate.Struct(r)
+}
+
+// CostAnomalyUpdateReq update cost anomaly request
+type CostAnomalyUpdateReq struct {
+	ID           string                        `json:"id" validate:"required"`
+	Currency     enumor.CurrencyCode           `json:"currency" validate:"omitempty"`
+	Cost         *decimal.Decimal              `json:"cost" validate:"omitempty"`
+	Baseline     *decimal.Decimal              `json:"baseline" validate:"omitempty"`
+	Deviation    *decimal.Decimal              `json:"deviation" validate:"omitempty"`
+	Score        *decimal.Decimal              `json:"score" validate:"omitempty"`
+	Severity     enumor.CostAnomalySeverity    `json:"severity" validate:"omitempty"`
+	Contributors []bill.CostAnomalyContributor `json:"contributors" validate:"omitempty"`
+	Notified     *bool                         `json:"notified" validate:"omitempty"`
+}
+
+// Validate ...
+func (r *CostAnomalyUpdateReq) Validate() error {
+	if len(r.Severity) != 0 {
+		if err := r.Severity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(r)
+}
+
+// CostAnomalyListResult list cost anomaly result
+type CostAnomalyListResult = core.ListResultT[bill.CostAnomaly]
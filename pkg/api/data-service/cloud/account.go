@@ -44,11 +44,18 @@ type TCloudAccountExtensionCreateReq struct {
 	CloudSubAccountID  string `json:"cloud_sub_account_id" validate:"required"`
 	CloudSecretID      string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey     string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn       string `json:"cloud_role_arn,omitempty" validate:"omitempty"`
+	CloudExternalID    string `json:"cloud_external_id,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *TCloudAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+func (req *TCloudAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
 }
 
 // AwsAccountExtensionCreateReq ...
@@ -57,11 +64,18 @@ type AwsAccountExtensionCreateReq struct {
 	CloudIamUsername string `json:"cloud_iam_username" validate:"required"`
 	CloudSecretID    string `json:"cloud_secret_id" validate:"omitempty"`
 	CloudSecretKey   string `json:"cloud_secret_key" validate:"omitempty"`
+	CloudRoleArn     string `json:"cloud_role_arn,omitempty" validate:"omitempty"`
+	CloudExternalID  string `json:"cloud_external_id,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *AwsAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+func (req *AwsAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
 }
 
 // HuaWeiAccountExtensionCreateReq ...
@@ -72,11 +86,17 @@ type HuaWeiAccountExtensionCreateReq struct {
 	CloudSecretKey      string `json:"cloud_secret_key" validate:"omitempty"`
 	CloudIamUserID      string `json:"cloud_iam_user_id" validate:"required"`
 	CloudIamUsername    string `json:"cloud_iam_username" validate:"required"`
+	CloudAgencyName     string `json:"cloud_agency_name,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *HuaWeiAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+func (req *HuaWeiAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudSecretKey = encrypted
+	return nil
 }
 
 // GcpAccountExtensionCreateReq ...
@@ -91,8 +111,13 @@ type GcpAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *GcpAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudServiceSecretKey = cipher.EncryptToBase64(req.CloudServiceSecretKey)
+func (req *GcpAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudServiceSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudServiceSecretKey = encrypted
+	return nil
 }
 
 // AzureAccountExtensionCreateReq ...
@@ -107,8 +132,13 @@ type AzureAccountExtensionCreateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *AzureAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
-	req.CloudClientSecretKey = cipher.EncryptToBase64(req.CloudClientSecretKey)
+func (req *AzureAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
+	encrypted, err := cipher.EncryptToBase64(req.CloudClientSecretKey)
+	if err != nil {
+		return err
+	}
+	req.CloudClientSecretKey = encrypted
+	return nil
 }
 
 // AccountCreateReq ...
@@ -141,14 +171,20 @@ type TCloudAccountExtensionUpdateReq struct {
 	CloudSubAccountID  string  `json:"cloud_sub_account_id,omitempty" validate:"omitempty"`
 	CloudSecretID      *string `json:"cloud_secret_id,omitempty" validate:"omitempty"`
 	CloudSecretKey     *string `json:"cloud_secret_key,omitempty" validate:"omitempty"`
+	CloudRoleArn       *string `json:"cloud_role_arn,omitempty" validate:"omitempty"`
+	CloudExternalID    *string `json:"cloud_external_id,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *TCloudAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *TCloudAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudSecretKey != nil {
-		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudSecretKey = &encryptedCloudSecretKey
 	}
+	return nil
 }
 
 type AwsAccountExtensionUpdateReq struct {
@@ -156,14 +192,20 @@ type AwsAccountExtensionUpdateReq struct {
 	CloudIamUsername string  `json:"cloud_iam_username,omitempty" validate:"omitempty"`
 	CloudSecretID    *string `json:"cloud_secret_id,omitempty" validate:"omitempty"`
 	CloudSecretKey   *string `json:"cloud_secret_key,omitempty" validate:"omitempty"`
+	CloudRoleArn     *string `json:"cloud_role_arn,omitempty" validate:"omitempty"`
+	CloudExternalID  *string `json:"cloud_external_id,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *AwsAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *AwsAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudSecretKey != nil {
-		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudSecretKey = &encryptedCloudSecretKey
 	}
+	return nil
 }
 
 type HuaWeiAccountExtensionUpdateReq struct {
@@ -173,14 +215,19 @@ type HuaWeiAccountExtensionUpdateReq struct {
 	CloudSecretKey      *string `json:"cloud_secret_key,omitempty" validate:"omitempty"`
 	CloudIamUserID      string  `json:"cloud_iam_user_id,omitempty" validate:"omitempty"`
 	CloudIamUsername    string  `json:"cloud_iam_username,omitempty" validate:"omitempty"`
+	CloudAgencyName     *string `json:"cloud_agency_name,omitempty" validate:"omitempty"`
 }
 
 // EncryptSecretKey ...
-func (req *HuaWeiAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *HuaWeiAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudSecretKey != nil {
-		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		encryptedCloudSecretKey, err := cipher.EncryptToBase64(*req.CloudSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudSecretKey = &encryptedCloudSecretKey
 	}
+	return nil
 }
 
 type GcpAccountExtensionUpdateReq struct {
@@ -194,11 +241,15 @@ type GcpAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *GcpAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *GcpAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudServiceSecretKey != nil {
-		encryptedCloudServiceSecretKey := cipher.EncryptToBase64(*req.CloudServiceSecretKey)
+		encryptedCloudServiceSecretKey, err := cipher.EncryptToBase64(*req.CloudServiceSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudServiceSecretKey = &encryptedCloudServiceSecretKey
 	}
+	return nil
 }
 
 type AzureAccountExtensionUpdateReq struct {
@@ -213,11 +264,15 @@ type AzureAccountExtensionUpdateReq struct {
 }
 
 // EncryptSecretKey ...
-func (req *AzureAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+func (req *AzureAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) error {
 	if req.CloudClientSecretKey != nil {
-		encryptedCloudClientSecretKey := cipher.EncryptToBase64(*req.CloudClientSecretKey)
+		encryptedCloudClientSecretKey, err := cipher.EncryptToBase64(*req.CloudClientSecretKey)
+		if err != nil {
+			return err
+		}
 		req.CloudClientSecretKey = &encryptedCloudClientSecretKey
 	}
+	return nil
 }
 
 // AccountUpdateReq ...
@@ -336,7 +391,7 @@ type AccountWithExtensionListResp struct {
 // SecretEncryptor 用于加密"泛型"Extension密钥
 type SecretEncryptor[T AccountExtensionCreateReq | AccountExtensionUpdateReq] interface {
 	// EncryptSecretKey 加密约束，将密钥进行加密设置
-	EncryptSecretKey(cryptography.Crypto)
+	EncryptSecretKey(cryptography.Crypto) error
 	*T
 }
 
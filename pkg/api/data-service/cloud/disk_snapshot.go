@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// DiskSnapshotBatchCreateReq defines batch create disk snapshot request.
+type DiskSnapshotBatchCreateReq struct {
+	Snapshots []DiskSnapshotCreate `json:"snapshots" validate:"min=1,max=100,dive"`
+}
+
+// DiskSnapshotCreate defines disk snapshot to create.
+type DiskSnapshotCreate struct {
+	Vendor         enumor.Vendor                `json:"vendor" validate:"required"`
+	AccountID      string                       `json:"account_id" validate:"required"`
+	CloudID        string                       `json:"cloud_id" validate:"required"`
+	Name           string                       `json:"name" validate:"omitempty,lte=255"`
+	BkBizID        int64                        `json:"bk_biz_id" validate:"omitempty"`
+	Region         string                       `json:"region" validate:"omitempty"`
+	Zone           string                       `json:"zone" validate:"omitempty"`
+	DiskID         string                       `json:"disk_id" validate:"omitempty"`
+	CloudDiskID    string                       `json:"cloud_disk_id" validate:"omitempty"`
+	DiskSize       uint64                       `json:"disk_size" validate:"omitempty"`
+	Status         string                       `json:"status" validate:"omitempty"`
+	Encrypted      bool                         `json:"encrypted" validate:"omitempty"`
+	PolicyID       string                       `json:"policy_id" validate:"omitempty"`
+	CloudCreatedAt string                       `json:"cloud_created_at" validate:"omitempty"`
+	Memo           *string                      `json:"memo" validate:"omitempty,lte=255"`
+	Extension      *cloud.DiskSnapshotExtension `json:"extension" validate:"omitempty"`
+}
+
+// Validate DiskSnapshotBatchCreateReq.
+func (req *DiskSnapshotBatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotBatchUpdateReq defines batch update disk snapshot request.
+type DiskSnapshotBatchUpdateReq struct {
+	Snapshots []DiskSnapshotUpdate `json:"snapshots" validate:"min=1,max=100,dive"`
+}
+
+// DiskSnapshotUpdate defines disk snapshot to update, empty field will not be updated.
+type DiskSnapshotUpdate struct {
+	ID        string                       `json:"id" validate:"required"`
+	Name      string                       `json:"name" validate:"omitempty,lte=255"`
+	BkBizID   int64                        `json:"bk_biz_id" validate:"omitempty"`
+	DiskID    string                       `json:"disk_id" validate:"omitempty"`
+	DiskSize  uint64                       `json:"disk_size" validate:"omitempty"`
+	Status    string                       `json:"status" validate:"omitempty"`
+	Memo      *string                      `json:"memo" validate:"omitempty,lte=255"`
+	Extension *cloud.DiskSnapshotExtension `json:"extension" validate:"omitempty"`
+}
+
+// Validate DiskSnapshotBatchUpdateReq.
+func (req *DiskSnapshotBatchUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotListResult defines list disk snapshot result.
+type DiskSnapshotListResult struct {
+	Count   uint64               `json:"count"`
+	Details []cloud.DiskSnapshot `json:"details"`
+}
+
+// DiskSnapshotPolicyBatchCreateReq defines batch create disk snapshot policy request.
+type DiskSnapshotPolicyBatchCreateReq struct {
+	Policies []DiskSnapshotPolicyCreate `json:"policies" validate:"min=1,max=100,dive"`
+}
+
+// DiskSnapshotPolicyCreate defines disk snapshot policy to create.
+type DiskSnapshotPolicyCreate struct {
+	Name           string                        `json:"name" validate:"required,lte=255"`
+	BkBizID        int64                         `json:"bk_biz_id" validate:"required"`
+	ScopeType      cloud.DiskSnapshotPolicyScope `json:"scope_type" validate:"required"`
+	DiskIDs        []string                      `json:"disk_ids" validate:"max=500"`
+	IntervalHours  uint64                        `json:"interval_hours" validate:"required,min=1"`
+	RetentionCount uint64                        `json:"retention_count" validate:"required,min=1,max=1000"`
+	Enabled        bool                          `json:"enabled" validate:"omitempty"`
+	Memo           *string                       `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate DiskSnapshotPolicyCreate.
+func (p *DiskSnapshotPolicyCreate) Validate() error {
+	if err := p.ScopeType.Validate(); err != nil {
+		return err
+	}
+
+	return validateDiskSnapshotPolicyScope(p.ScopeType, p.BkBizID, p.DiskIDs)
+}
+
+// validateDiskSnapshotPolicyScope 指定云盘的策略需要设置云盘，业务策略需要设置业务
+func validateDiskSnapshotPolicyScope(scope cloud.DiskSnapshotPolicyScope, bizID int64, diskIDs []string) error {
+	switch scope {
+	case cloud.DiskScopeSnapshotPolicy:
+		if len(diskIDs) == 0 {
+			return errors.New("disk_ids is required when scope_type is disk")
+		}
+	case cloud.BizScopeSnapshotPolicy:
+		if bizID <= 0 {
+			return errors.New("bk_biz_id should be > 0 when scope_type is biz")
+		}
+		if len(diskIDs) != 0 {
+			return errors.New("disk_ids should be empty when scope_type is biz")
+		}
+	}
+
+	return nil
+}
+
+// Validate DiskSnapshotPolicyBatchCreateReq.
+func (req *DiskSnapshotPolicyBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Policies {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DiskSnapshotPolicyBatchUpdateReq defines batch update disk snapshot policy request.
+type DiskSnapshotPolicyBatchUpdateReq struct {
+	Policies []DiskSnapshotPolicyUpdate `json:"policies" validate:"min=1,max=100,dive"`
+}
+
+// DiskSnapshotPolicyUpdate defines disk snapshot policy to update, nil or empty field will not be updated.
+type DiskSnapshotPolicyUpdate struct {
+	ID             string   `json:"id" validate:"required"`
+	Name           string   `json:"name" validate:"lte=255"`
+	DiskIDs        []string `json:"disk_ids" validate:"max=500"`
+	IntervalHours  uint64   `json:"interval_hours" validate:"omitempty,min=1"`
+	RetentionCount uint64   `json:"retention_count" validate:"omitempty,min=1,max=1000"`
+	Enabled        *bool    `json:"enabled" validate:"omitempty"`
+	LastExecutedAt string   `json:"last_executed_at" validate:"omitempty,lte=32"`
+	Memo           *string  `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate DiskSnapshotPolicyBatchUpdateReq.
+func (req *DiskSnapshotPolicyBatchUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotPolicyListResult defines list disk snapshot policy result.
+type DiskSnapshotPolicyListResult struct {
+	Count   uint64                     `json:"count"`
+	Details []cloud.DiskSnapshotPolicy `json:"details"`
+}
@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResAssignRuleBatchCreateReq defines batch create res assign rule request.
+type ResAssignRuleBatchCreateReq struct {
+	Rules []ResAssignRuleCreate `json:"rules" validate:"min=1,max=100,dive"`
+}
+
+// ResAssignRuleCreate defines res assign rule to create.
+type ResAssignRuleCreate struct {
+	Name       string                       `json:"name" validate:"required,lte=255"`
+	ResTypes   []enumor.CloudResourceType   `json:"res_types" validate:"required"`
+	Priority   int64                        `json:"priority" validate:"omitempty"`
+	Enabled    bool                         `json:"enabled" validate:"omitempty"`
+	Conditions cloud.ResAssignRuleCondition `json:"conditions" validate:"required"`
+	BkBizID    int64                        `json:"bk_biz_id" validate:"required,min=1"`
+	BkCloudID  int64                        `json:"bk_cloud_id" validate:"omitempty"`
+	Memo       *string                      `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ResAssignRuleCreate.
+func (r *ResAssignRuleCreate) Validate() error {
+	if err := cloud.ValidateResAssignRuleResTypes(r.ResTypes); err != nil {
+		return err
+	}
+
+	return r.Conditions.Validate()
+}
+
+// Validate ResAssignRuleBatchCreateReq.
+func (req *ResAssignRuleBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Rules {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResAssignRuleBatchUpdateReq defines batch update res assign rule request.
+type ResAssignRuleBatchUpdateReq struct {
+	Rules []ResAssignRuleUpdate `json:"rules" validate:"min=1,max=100,dive"`
+}
+
+// ResAssignRuleUpdate defines res assign rule to update, nil field will not be updated.
+type ResAssignRuleUpdate struct {
+	ID         string                        `json:"id" validate:"required"`
+	Name       string                        `json:"name" validate:"lte=255"`
+	ResTypes   []enumor.CloudResourceType    `json:"res_types" validate:"omitempty"`
+	Priority   *int64                        `json:"priority" validate:"omitempty"`
+	Enabled    *bool                         `json:"enabled" validate:"omitempty"`
+	Conditions *cloud.ResAssignRuleCondition `json:"conditions" validate:"omitempty"`
+	BkBizID    int64                         `json:"bk_biz_id" validate:"omitempty,min=1"`
+	BkCloudID  *int64                        `json:"bk_cloud_id" validate:"omitempty"`
+	Memo       *string                       `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate ResAssignRuleBatchUpdateReq.
+func (req *ResAssignRuleBatchUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Rules {
+		if len(one.ResTypes) != 0 {
+			if err := cloud.ValidateResAssignRuleResTypes(one.ResTypes); err != nil {
+				return err
+			}
+		}
+
+		if one.Conditions != nil {
+			if err := one.Conditions.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResAssignRuleListResult defines list res assign rule result.
+type ResAssignRuleListResult struct {
+	Count   uint64                `json:"count"`
+	Details []cloud.ResAssignRule `json:"details"`
+}
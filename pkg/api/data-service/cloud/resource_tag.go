@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResourceTagBatchUpsertReq defines batch upsert resource tag request.
+type ResourceTagBatchUpsertReq struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// Replace 为 true 时，资源在 db 中存在但不在 Tags 中的标签会被删除，用于资源同步
+	Replace   bool                    `json:"replace" validate:"omitempty"`
+	Resources []ResourceTagUpsertItem `json:"resources" validate:"min=1,max=500,dive"`
+}
+
+// ResourceTagUpsertItem defines tags of one resource to upsert.
+type ResourceTagUpsertItem struct {
+	// ResID 为空时根据 CloudResID 查询资源ID，云上ID对应的资源不存在时忽略该资源
+	ResID      string         `json:"res_id" validate:"omitempty"`
+	CloudResID string         `json:"cloud_res_id" validate:"required"`
+	Tags       []core.TagPair `json:"tags" validate:"max=100"`
+}
+
+// Validate ResourceTagBatchUpsertReq.
+func (req *ResourceTagBatchUpsertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := cloud.ResourceTagResTypes[req.ResType]; !ok {
+		return fmt.Errorf("res_type %s does not support tag", req.ResType)
+	}
+
+	for _, one := range req.Resources {
+		keys := make(map[string]struct{}, len(one.Tags))
+		for _, tag := range one.Tags {
+			if len(tag.Key) == 0 {
+				return fmt.Errorf("resource %s tag key is required", one.CloudResID)
+			}
+
+			if _, exist := keys[tag.Key]; exist {
+				return fmt.Errorf("resource %s tag key %s is duplicated", one.CloudResID, tag.Key)
+			}
+			keys[tag.Key] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// ResourceTagListResult defines list resource tag result.
+type ResourceTagListResult struct {
+	Count   uint64              `json:"count"`
+	Details []cloud.ResourceTag `json:"details"`
+}
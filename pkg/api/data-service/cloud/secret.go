@@ -0,0 +1,50 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+// RewrapSecretResult 密钥加密密钥轮换后，存量加密字段重新包装的结果
+type RewrapSecretResult struct {
+	Account     *RewrapSecretStat `json:"account"`
+	RootAccount *RewrapSecretStat `json:"root_account"`
+	MainAccount *RewrapSecretStat `json:"main_account"`
+	Application *RewrapSecretStat `json:"application"`
+}
+
+// Failed 重新包装失败的加密字段总数，存在失败时这些字段仍依赖旧版本的KEK
+func (r *RewrapSecretResult) Failed() uint {
+	failed := uint(0)
+	for _, stat := range []*RewrapSecretStat{r.Account, r.RootAccount, r.MainAccount, r.Application} {
+		if stat != nil {
+			failed += stat.Failed
+		}
+	}
+	return failed
+}
+
+// RewrapSecretStat 一张表中加密字段的重新包装统计，Total 为记录数，其他为加密字段数
+type RewrapSecretStat struct {
+	Total     uint `json:"total"`
+	Rewrapped uint `json:"rewrapped"`
+	// Conflicted 重新包装期间加密字段被修改，由下一次重新包装处理
+	Conflicted uint `json:"conflicted"`
+	Failed     uint `json:"failed"`
+	// Discovered 不在已知加密字段中，但内容为信封加密密文的字段，同样会被重新包装
+	Discovered uint `json:"discovered"`
+}
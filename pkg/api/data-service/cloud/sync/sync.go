@@ -103,3 +103,38 @@ type ListResult struct {
 	Count   uint64                            `json:"count"`
 	Details []coresync.AccountSyncDetailTable `json:"details"`
 }
+
+// -------------------------- Sync Cursor --------------------------
+
+// SyncCursorBatchSetReq define batch set sync cursor request, the cursor is created if it not exists.
+type SyncCursorBatchSetReq struct {
+	Items []SyncCursorSetField `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate SyncCursorBatchSetReq.
+func (req SyncCursorBatchSetReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Type.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncCursorSetField define sync cursor set field.
+type SyncCursorSetField struct {
+	Type        enumor.SyncCursorType `json:"type" validate:"required"`
+	Scope       string                `json:"scope" validate:"lte=255"`
+	CursorValue string                `json:"cursor_value" validate:"required,lte=64"`
+}
+
+// SyncCursorListResult defines list sync cursor result.
+type SyncCursorListResult struct {
+	Count   uint64                `json:"count"`
+	Details []coresync.SyncCursor `json:"details"`
+}
@@ -82,6 +82,9 @@ type TargetBaseReq struct {
 	Zone              string          `json:"zone,omitempty" validate:"omitempty"`
 	NewPort           *int64          `json:"new_port,omitempty" validate:"omitempty"`
 	NewWeight         *int64          `json:"new_weight,omitempty" validate:"omitempty"`
+
+	// CloudTargetGroupID 云上目标组ID，为空时与TargetGroupID一致
+	CloudTargetGroupID string `json:"cloud_target_group_id,omitempty" validate:"omitempty"`
 }
 
 // Validate ...
@@ -107,8 +110,16 @@ func (req *TargetGroupBatchCreateReq[T]) Validate() error {
 // TCloudTargetGroupCreateReq ...
 type TCloudTargetGroupCreateReq = TargetGroupBatchCreateReq[corelb.TCloudTargetGroupExtension]
 
+// AwsTargetGroupCreateReq ...
+type AwsTargetGroupCreateReq = TargetGroupBatchCreateReq[corelb.AwsTargetGroupExtension]
+
+// HuaWeiTargetGroupCreateReq ...
+type HuaWeiTargetGroupCreateReq = TargetGroupBatchCreateReq[corelb.HuaWeiTargetGroupExtension]
+
 // TargetGroupBatchCreate define target group batch create.
 type TargetGroupBatchCreate[Extension corelb.TargetGroupExtension] struct {
+	// CloudID 云上目标组ID，aws目标组、华为云后端服务器组需要传入，本地目标组为空时与本地ID一致
+	CloudID         string                 `json:"cloud_id" validate:"omitempty"`
 	Name            string                 `json:"name" validate:"required"`
 	Vendor          enumor.Vendor          `json:"vendor" validate:"required"`
 	AccountID       string                 `json:"account_id" validate:"required"`
@@ -189,6 +200,8 @@ type TargetGroupUpdateReq struct {
 	Port            int64                         `json:"port"`
 	Weight          *int64                        `json:"weight,omitempty"`
 	HealthCheck     *corelb.TCloudHealthCheckInfo `json:"health_check"`
+	// Extension 云厂商拓展字段，与已有拓展字段合并更新
+	Extension types.JsonField `json:"extension,omitempty"`
 }
 
 // Validate ...
@@ -306,6 +319,12 @@ func (req *TGListenerRelStatusUpdateReq) Validate() error {
 // TCloudListenerBatchCreateReq ...
 type TCloudListenerBatchCreateReq = ListenerBatchCreateReq[corelb.TCloudListenerExtension]
 
+// AwsListenerBatchCreateReq ...
+type AwsListenerBatchCreateReq = ListenerBatchCreateReq[corelb.AwsListenerExtension]
+
+// HuaWeiListenerBatchCreateReq ...
+type HuaWeiListenerBatchCreateReq = ListenerBatchCreateReq[corelb.HuaWeiListenerExtension]
+
 // ListenerBatchCreateReq listener batch create req.
 type ListenerBatchCreateReq[T corelb.ListenerExtension] struct {
 	Listeners []ListenersCreateReq[T] `json:"listeners" validate:"required,min=1,dive,required"`
@@ -398,6 +417,12 @@ type ListenerBatchUpdateReq[Extension corelb.ListenerExtension] struct {
 // TCloudListenerUpdateReq ...
 type TCloudListenerUpdateReq = ListenerBatchUpdateReq[corelb.TCloudListenerExtension]
 
+// AwsListenerUpdateReq ...
+type AwsListenerUpdateReq = ListenerBatchUpdateReq[corelb.AwsListenerExtension]
+
+// HuaWeiListenerUpdateReq ...
+type HuaWeiListenerUpdateReq = ListenerBatchUpdateReq[corelb.HuaWeiListenerExtension]
+
 // Validate 验证监听器更新参数
 func (req *ListenerBatchUpdateReq[T]) Validate() error {
 	for _, item := range req.Listeners {
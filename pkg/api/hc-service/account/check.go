@@ -25,8 +25,11 @@ import (
 
 // TCloudAccountCheckReq ...
 type TCloudAccountCheckReq struct {
-	CloudSecretID  string `json:"cloud_secret_id" validate:"required"`
-	CloudSecretKey string `json:"cloud_secret_key" validate:"required"`
+	// 角色扮演模式下密钥可为空，此时使用 hc-service 配置的源身份密钥
+	CloudSecretID   string `json:"cloud_secret_id" validate:"required_without=CloudRoleArn"`
+	CloudSecretKey  string `json:"cloud_secret_key" validate:"required_without=CloudRoleArn"`
+	CloudRoleArn    string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID string `json:"cloud_external_id" validate:"omitempty"`
 
 	CloudMainAccountID string `json:"cloud_main_account_id" validate:"required"`
 	CloudSubAccountID  string `json:"cloud_sub_account_id" validate:"required"`
@@ -40,8 +43,11 @@ func (r *TCloudAccountCheckReq) Validate() error {
 
 // AwsAccountCheckReq ...
 type AwsAccountCheckReq struct {
-	CloudSecretID  string `json:"cloud_secret_id" validate:"required"`
-	CloudSecretKey string `json:"cloud_secret_key" validate:"required"`
+	// 角色扮演模式下密钥可为空，此时使用 hc-service 配置的源身份密钥
+	CloudSecretID   string `json:"cloud_secret_id" validate:"required_without=CloudRoleArn"`
+	CloudSecretKey  string `json:"cloud_secret_key" validate:"required_without=CloudRoleArn"`
+	CloudRoleArn    string `json:"cloud_role_arn" validate:"omitempty"`
+	CloudExternalID string `json:"cloud_external_id" validate:"omitempty"`
 
 	CloudAccountID   string `json:"cloud_account_id" validate:"required"`
 	CloudIamUsername string `json:"cloud_iam_username" validate:"required"`
@@ -54,8 +60,10 @@ func (r *AwsAccountCheckReq) Validate() error {
 
 // HuaWeiAccountCheckReq ...
 type HuaWeiAccountCheckReq struct {
-	CloudSecretID  string `json:"cloud_secret_id" validate:"required"`
-	CloudSecretKey string `json:"cloud_secret_key" validate:"required"`
+	// 委托模式下密钥可为空，此时使用 hc-service 配置的源身份密钥
+	CloudSecretID   string `json:"cloud_secret_id" validate:"required_without=CloudAgencyName"`
+	CloudSecretKey  string `json:"cloud_secret_key" validate:"required_without=CloudAgencyName"`
+	CloudAgencyName string `json:"cloud_agency_name" validate:"omitempty"`
 
 	CloudSubAccountID   string `json:"cloud_sub_account_id" validate:"required"`
 	CloudSubAccountName string `json:"cloud_sub_account_name" validate:"required"`
@@ -21,6 +21,8 @@ package bill
 
 import (
 	"hcm/pkg/rest"
+
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
 )
 
 // -------------------------- List --------------------------
@@ -41,3 +43,13 @@ type TCloudBillListResp struct {
 	rest.BaseResp `json:",inline"`
 	Data          *TCloudBillListResult `json:"data"`
 }
+
+// TCloudRootBillListResult define tcloud root account bill list result.
+type TCloudRootBillListResult struct {
+	// Count 云上本次查询条件下的账单总条数，未按二级账号过滤
+	Count uint64 `json:"count"`
+	// Details 已按二级账号过滤后的账单明细
+	Details []*billing.BillDetail `json:"details"`
+	// Context 本次请求的上下文信息，可用于下一次请求的请求参数中，加快查询速度
+	Context *string `json:"context,omitempty"`
+}
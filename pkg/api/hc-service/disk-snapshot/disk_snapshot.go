@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	apisync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq defines create disk snapshot request.
+type CreateReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// DiskID 源云盘在hcm中的ID
+	DiskID string `json:"disk_id" validate:"required"`
+	Name   string `json:"name" validate:"required,lte=60"`
+	// PolicyID 由快照策略创建时设置
+	PolicyID string  `json:"policy_id" validate:"omitempty"`
+	Memo     *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeleteReq defines batch delete disk snapshot request, snapshots must belong to the same account.
+type BatchDeleteReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	IDs       []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate BatchDeleteReq.
+func (req *BatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RollbackReq defines rollback source disk with disk snapshot request.
+type RollbackReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+	// AutoStopInstance 回滚前是否自动关闭云盘挂载的实例，仅腾讯云支持
+	AutoStopInstance bool `json:"auto_stop_instance" validate:"omitempty"`
+}
+
+// Validate RollbackReq.
+func (req *RollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SyncReq defines sync disk snapshot request.
+type SyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// Region azure 可不指定地域，同步资源组下所有地域的快照
+	Region string `json:"region" validate:"omitempty"`
+	// ResourceGroupName azure 需要指定资源组
+	ResourceGroupName string `json:"resource_group_name" validate:"omitempty"`
+	// 同步计划选项，dry-run 及删除比例阈值
+	PlanOption *apisync.PlanOption `json:"plan_option,omitempty" validate:"omitempty"`
+}
+
+// Validate SyncReq.
+func (req *SyncReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package hclb
+
+import (
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	apicore "hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// AwsLoadBalancerCreateReq aws application/network load balancer create req.
+type AwsLoadBalancerCreateReq struct {
+	AccountID             string                       `json:"account_id" validate:"required"`
+	BkBizID               int64                        `json:"bk_biz_id"`
+	Region                string                       `json:"region" validate:"required"`
+	Name                  string                       `json:"name" validate:"required,max=32"`
+	Type                  typelb.AwsLoadBalancerKind   `json:"type" validate:"required,oneof=application network"`
+	Scheme                typelb.AwsLoadBalancerScheme `json:"scheme" validate:"omitempty,oneof=internet-facing internal"`
+	IpAddressType         *string                      `json:"ip_address_type" validate:"omitempty"`
+	CloudSubnetIDs        []string                     `json:"cloud_subnet_ids" validate:"required,min=1"`
+	CloudSecurityGroupIDs []string                     `json:"cloud_security_group_ids" validate:"omitempty"`
+	Tags                  []apicore.TagPair            `json:"tags" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsLoadBalancerCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsLBUpdateReq aws load balancer update req.
+type AwsLBUpdateReq struct {
+	DeletionProtection    *bool    `json:"deletion_protection" validate:"omitempty"`
+	CloudSecurityGroupIDs []string `json:"cloud_security_group_ids" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsLBUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsBatchDeleteLoadBalancerReq aws batch delete load balancer request.
+type AwsBatchDeleteLoadBalancerReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	Region    string   `json:"region" validate:"required"`
+	IDs       []string `json:"ids" validate:"required,min=1,max=20"`
+}
+
+// Validate request.
+func (req *AwsBatchDeleteLoadBalancerReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsListenerCreateReq aws listener create req, 默认动作为转发到指定目标组
+type AwsListenerCreateReq struct {
+	LbID          string              `json:"lb_id" validate:"required"`
+	Protocol      enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port          int64               `json:"port" validate:"required,min=1,max=65535"`
+	TargetGroupID string              `json:"target_group_id" validate:"required"`
+	SslPolicy     *string             `json:"ssl_policy" validate:"omitempty"`
+	CertCloudIDs  []string            `json:"cert_cloud_ids" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsListenerCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsListenerUpdateReq aws listener update req, 为空的字段不修改
+type AwsListenerUpdateReq struct {
+	Protocol      enumor.ProtocolType `json:"protocol" validate:"omitempty"`
+	Port          *int64              `json:"port" validate:"omitempty,min=1,max=65535"`
+	TargetGroupID string              `json:"target_group_id" validate:"omitempty"`
+	SslPolicy     *string             `json:"ssl_policy" validate:"omitempty"`
+	CertCloudIDs  []string            `json:"cert_cloud_ids" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsListenerUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsRuleCreateReq aws listener rule create req.
+type AwsRuleCreateReq struct {
+	ListenerID    string `json:"listener_id" validate:"required"`
+	Priority      int64  `json:"priority" validate:"required,min=1,max=50000"`
+	Domain        string `json:"domain" validate:"required_without=URL"`
+	URL           string `json:"url" validate:"omitempty"`
+	TargetGroupID string `json:"target_group_id" validate:"required"`
+}
+
+// Validate request.
+func (req *AwsRuleCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsRuleUpdateReq aws listener rule update req, 为空的字段不修改
+type AwsRuleUpdateReq struct {
+	Domain        string `json:"domain" validate:"omitempty"`
+	URL           string `json:"url" validate:"omitempty"`
+	TargetGroupID string `json:"target_group_id" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsRuleUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsTargetGroupCreateReq aws target group create req.
+type AwsTargetGroupCreateReq struct {
+	AccountID   string                     `json:"account_id" validate:"required"`
+	BkBizID     int64                      `json:"bk_biz_id"`
+	Region      string                     `json:"region" validate:"required"`
+	Name        string                     `json:"name" validate:"required,max=32"`
+	Protocol    enumor.ProtocolType        `json:"protocol" validate:"required"`
+	Port        int64                      `json:"port" validate:"required,min=1,max=65535"`
+	CloudVpcID  string                     `json:"cloud_vpc_id" validate:"required"`
+	TargetType  *string                    `json:"target_type" validate:"omitempty,oneof=instance ip"`
+	HealthCheck *corelb.AwsHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *AwsTargetGroupCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsTargetGroupUpdateReq aws target group update req, 云上仅支持修改健康检查
+type AwsTargetGroupUpdateReq struct {
+	HealthCheck *corelb.AwsHealthCheckInfo `json:"health_check" validate:"required"`
+}
+
+// Validate request.
+func (req *AwsTargetGroupUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// AwsBatchOperateTargetReq aws register/deregister targets req.
+type AwsBatchOperateTargetReq struct {
+	Targets []typelb.AwsTargetOption `json:"targets" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate request.
+func (req *AwsBatchOperateTargetReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,166 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package hclb
+
+import (
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	apicore "hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// HuaWeiLoadBalancerCreateReq huawei dedicated elb create req.
+type HuaWeiLoadBalancerCreateReq struct {
+	AccountID            string            `json:"account_id" validate:"required"`
+	BkBizID              int64             `json:"bk_biz_id"`
+	Region               string            `json:"region" validate:"required"`
+	Name                 string            `json:"name" validate:"required,max=255"`
+	CloudVpcID           string            `json:"cloud_vpc_id" validate:"required"`
+	CloudVipSubnetID     *string           `json:"cloud_vip_subnet_id" validate:"omitempty"`
+	CloudElbVirsubnetIDs []string          `json:"cloud_elb_virsubnet_ids" validate:"omitempty"`
+	AvailabilityZones    []string          `json:"availability_zones" validate:"required,min=1"`
+	L4FlavorID           *string           `json:"l4_flavor_id" validate:"omitempty"`
+	L7FlavorID           *string           `json:"l7_flavor_id" validate:"omitempty"`
+	CloudEipIDs          []string          `json:"cloud_eip_ids" validate:"omitempty"`
+	DeletionProtection   *bool             `json:"deletion_protection" validate:"omitempty"`
+	Tags                 []apicore.TagPair `json:"tags" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiLoadBalancerCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiLBUpdateReq huawei load balancer update req.
+type HuaWeiLBUpdateReq struct {
+	Name               *string `json:"name" validate:"omitempty"`
+	Memo               *string `json:"memo" validate:"omitempty"`
+	DeletionProtection *bool   `json:"deletion_protection" validate:"omitempty"`
+	IpTargetEnable     *bool   `json:"ip_target_enable" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiLBUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiBatchDeleteLoadBalancerReq huawei batch delete load balancer request.
+type HuaWeiBatchDeleteLoadBalancerReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	Region    string   `json:"region" validate:"required"`
+	IDs       []string `json:"ids" validate:"required,min=1,max=20"`
+}
+
+// Validate request.
+func (req *HuaWeiBatchDeleteLoadBalancerReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiListenerCreateReq huawei listener create req.
+type HuaWeiListenerCreateReq struct {
+	LbID                   string              `json:"lb_id" validate:"required"`
+	Name                   string              `json:"name" validate:"omitempty"`
+	Protocol               enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port                   int32               `json:"port" validate:"required,min=1,max=65535"`
+	TargetGroupID          *string             `json:"target_group_id" validate:"omitempty"`
+	DefaultTlsContainerRef *string             `json:"default_tls_container_ref" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiListenerCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiListenerUpdateReq huawei listener update req, 为空的字段不修改
+type HuaWeiListenerUpdateReq struct {
+	Name                   *string `json:"name" validate:"omitempty"`
+	TargetGroupID          *string `json:"target_group_id" validate:"omitempty"`
+	DefaultTlsContainerRef *string `json:"default_tls_container_ref" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiListenerUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiRuleCreateReq huawei l7 policy create req.
+type HuaWeiRuleCreateReq struct {
+	ListenerID    string `json:"listener_id" validate:"required"`
+	Name          string `json:"name" validate:"omitempty"`
+	Domain        string `json:"domain" validate:"required_without=URL"`
+	URL           string `json:"url" validate:"omitempty"`
+	TargetGroupID string `json:"target_group_id" validate:"required"`
+}
+
+// Validate request.
+func (req *HuaWeiRuleCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiRuleUpdateReq huawei l7 policy update req, 指定域名或路径时整体替换转发规则
+type HuaWeiRuleUpdateReq struct {
+	Name          *string `json:"name" validate:"omitempty"`
+	Domain        string  `json:"domain" validate:"omitempty"`
+	URL           string  `json:"url" validate:"omitempty"`
+	TargetGroupID *string `json:"target_group_id" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiRuleUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiTargetGroupCreateReq huawei pool create req, 华为云后端服务器组本身没有端口，Port 为后端服务端口，仅记录在本地
+type HuaWeiTargetGroupCreateReq struct {
+	LbID        string                        `json:"lb_id" validate:"required"`
+	Name        string                        `json:"name" validate:"omitempty"`
+	Protocol    enumor.ProtocolType           `json:"protocol" validate:"required"`
+	LbAlgorithm string                        `json:"lb_algorithm" validate:"required"`
+	Port        int64                         `json:"port" validate:"required,min=1,max=65535"`
+	HealthCheck *corelb.HuaWeiHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiTargetGroupCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiTargetGroupUpdateReq huawei pool update req, 为空的字段不修改
+type HuaWeiTargetGroupUpdateReq struct {
+	Name        *string                       `json:"name" validate:"omitempty"`
+	LbAlgorithm *string                       `json:"lb_algorithm" validate:"omitempty"`
+	HealthCheck *corelb.HuaWeiHealthCheckInfo `json:"health_check" validate:"omitempty"`
+}
+
+// Validate request.
+func (req *HuaWeiTargetGroupUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// HuaWeiBatchOperateMemberReq huawei add/remove members req.
+type HuaWeiBatchOperateMemberReq struct {
+	Members []typelb.HuaWeiMemberOption `json:"members" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate request.
+func (req *HuaWeiBatchOperateMemberReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
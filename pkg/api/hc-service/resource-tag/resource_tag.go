@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag ...
+package restag
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResourceRef defines resource id and cloud id.
+type ResourceRef struct {
+	ID      string `json:"id" validate:"required"`
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// BatchUpsertReq defines batch create or update resource tags request, resources must belong to the same
+// account and region.
+type BatchUpsertReq struct {
+	AccountID string                   `json:"account_id" validate:"required"`
+	Region    string                   `json:"region" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Resources []ResourceRef            `json:"resources" validate:"min=1,max=100,dive"`
+	Tags      []core.TagPair           `json:"tags" validate:"min=1,max=50,dive"`
+}
+
+// Validate BatchUpsertReq.
+func (req *BatchUpsertReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeleteReq defines batch delete resource tags request, resources must belong to the same account and region.
+type BatchDeleteReq struct {
+	AccountID string                   `json:"account_id" validate:"required"`
+	Region    string                   `json:"region" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Resources []ResourceRef            `json:"resources" validate:"min=1,max=100,dive"`
+	TagKeys   []string                 `json:"tag_keys" validate:"min=1,max=50,dive,required"`
+}
+
+// Validate BatchDeleteReq.
+func (req *BatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CloudEventSyncReq 基于云上审计事件的增量同步请求，同步 [start_time, end_time) 内发生变更的资源
+type CloudEventSyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// Region 腾讯云云审计为全地域查询，可为空，aws、华为云为必填
+	Region    string    `json:"region" validate:"omitempty"`
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+}
+
+// Validate cloud event sync request.
+func (req *CloudEventSyncReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if !req.EndTime.After(req.StartTime) {
+		return errors.New("end_time must be after start_time")
+	}
+
+	return nil
+}
+
+// CloudEventSyncResult 增量同步结果
+type CloudEventSyncResult struct {
+	// EventCount 拉取到的事件数量
+	EventCount int `json:"event_count"`
+	// SyncedCount 各资源类型同步的云ID数量
+	SyncedCount map[enumor.CloudResourceType]int `json:"synced_count"`
+}
@@ -0,0 +1,38 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// PlanOption 同步计划选项，供基于通用同步器的同步接口使用
+type PlanOption struct {
+	// DryRun 为 true 时仅返回增/删/改变更计划，不修改本地数据
+	DryRun bool `json:"dry_run,omitempty"`
+	// DeleteThreshold 删除比例阈值(百分比)，要删除的本地数据占本地全量数据的比例超过该值时，在删除前终止同步，
+	// 为0时使用 hc-service 配置的阈值，为100时不限制
+	DeleteThreshold float64 `json:"delete_threshold,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// Validate plan option.
+func (opt *PlanOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
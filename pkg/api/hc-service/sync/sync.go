@@ -45,6 +45,8 @@ type TCloudSyncReq struct {
 	Concurrent uint `json:"concurrent,omitempty"`
 	// 指定标签同步，仅特定资源支持
 	TagFilters core.MultiValueTagMap `json:"tag_filters,omitempty"`
+	// 同步计划选项，仅基于通用同步器的资源支持(vpc、子网、硬盘、eip)，其余资源携带时拒绝请求
+	PlanOption *PlanOption `json:"plan_option,omitempty" validate:"omitempty"`
 }
 
 // Validate tcloud sync request.
@@ -66,6 +68,8 @@ func (req *AwsGlobalSyncReq) Validate() error {
 type AwsSyncReq struct {
 	AccountID string `json:"account_id" validate:"required"`
 	Region    string `json:"region" validate:"required"`
+	// 同步计划选项，仅基于通用同步器的资源支持(vpc、子网、硬盘、eip)，其余资源携带时拒绝请求
+	PlanOption *PlanOption `json:"plan_option,omitempty" validate:"omitempty"`
 }
 
 // Validate aws sync request.
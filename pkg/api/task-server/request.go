@@ -36,6 +36,12 @@ type AddTemplateFlowReq struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"required, min=1"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// RollbackMode 任务流失败后的回滚模式，不设置时使用任务流模版中定义的回滚模式
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode" validate:"omitempty"`
+	// Priority 任务流派发优先级，默认为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// ConcurrencyKey 并发控制键，如 account:00000001，相同键的任务流同时运行的数量受派发器并发上限控制
+	ConcurrencyKey string `json:"concurrency_key" validate:"omitempty,lte=255"`
 }
 
 // Validate AddTemplateFlowReq
@@ -79,6 +85,12 @@ type AddCustomFlowReq struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// RollbackMode 任务流失败后的回滚模式，默认为 none
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode" validate:"omitempty"`
+	// Priority 任务流派发优先级，默认为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// ConcurrencyKey 并发控制键，如 account:00000001，相同键的任务流同时运行的数量受派发器并发上限控制
+	ConcurrencyKey string `json:"concurrency_key" validate:"omitempty,lte=255"`
 }
 
 // Validate AddCustomFlowReq
@@ -116,3 +128,56 @@ type CustomFlowTask struct {
 func (task *CustomFlowTask) Validate() error {
 	return validator.Validate.Struct(task)
 }
+
+// AddScheduledFlowReq define add scheduled flow option.
+type AddScheduledFlowReq struct {
+	// Name 定时任务流名称，全局唯一
+	Name string `json:"name" validate:"required,max=64"`
+	// Cron 标准5段式cron表达式，例如 "0 2 * * *"
+	Cron string `json:"cron" validate:"required,max=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// Memo 备注
+	Memo string `json:"memo" validate:"omitempty,max=64"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+	// MisfirePolicy 错过触发时间的处理策略，默认为skip
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	// Enable 是否启用，默认启用
+	Enable *bool `json:"enable" validate:"omitempty"`
+}
+
+// Validate AddScheduledFlowReq
+func (req *AddScheduledFlowReq) Validate() error {
+	if err := req.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	for _, task := range req.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// UpdateScheduledFlowReq define update scheduled flow option.
+type UpdateScheduledFlowReq struct {
+	Cron          string               `json:"cron,omitempty" validate:"omitempty,max=64"`
+	Memo          string               `json:"memo,omitempty" validate:"omitempty,max=64"`
+	Tasks         []TemplateFlowTask   `json:"tasks,omitempty" validate:"omitempty"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy,omitempty" validate:"omitempty"`
+	Enable        *bool                `json:"enable,omitempty" validate:"omitempty"`
+}
+
+// Validate UpdateScheduledFlowReq
+func (req *UpdateScheduledFlowReq) Validate() error {
+	for _, task := range req.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
@@ -19,7 +19,10 @@
 
 package taskserver
 
-import "hcm/pkg/api/core/async"
+import (
+	"hcm/pkg/api/core/async"
+	"hcm/pkg/criteria/enumor"
+)
 
 // ListFlowResult ...
 type ListFlowResult struct {
@@ -32,3 +35,55 @@ type ListTaskResult struct {
 	Count   uint64                    `json:"count"`
 	Details []coreasync.AsyncFlowTask `json:"details"`
 }
+
+// ListScheduledFlowResult ...
+type ListScheduledFlowResult struct {
+	Count   uint64                         `json:"count"`
+	Details []coreasync.AsyncScheduledFlow `json:"details"`
+}
+
+// FlowGraphResult 任务流的任务依赖图及耗时分析结果
+type FlowGraphResult struct {
+	FlowID string          `json:"flow_id"`
+	Nodes  []FlowGraphNode `json:"nodes"`
+	// CriticalPath 关键路径，按执行顺序排列的任务ID
+	CriticalPath []string `json:"critical_path"`
+	// CriticalPathSec 关键路径上任务等待与执行耗时之和，单位秒
+	CriticalPathSec float64 `json:"critical_path_sec"`
+}
+
+// FlowGraphNode 任务依赖图节点
+type FlowGraphNode struct {
+	TaskID     string            `json:"task_id"`
+	ActionID   string            `json:"action_id"`
+	ActionName enumor.ActionName `json:"action_name"`
+	State      enumor.TaskState  `json:"state"`
+	// Parents 依赖的上游任务ID
+	Parents []string `json:"parents"`
+	// Children 依赖当前任务的下游任务ID
+	Children   []string `json:"children"`
+	StartedAt  string   `json:"started_at,omitempty"`
+	EndedAt    string   `json:"ended_at,omitempty"`
+	RetryCount uint     `json:"retry_count"`
+	Reason     string   `json:"reason,omitempty"`
+	// WaitSec 上游任务全部完成到当前任务开始执行的等待耗时，单位秒
+	WaitSec float64 `json:"wait_sec"`
+	// RunSec 当前任务开始执行到结束(未结束则到当前时间)的执行耗时，单位秒
+	RunSec float64 `json:"run_sec"`
+}
+
+// FlowQueueDepthResult 各优先级任务流队列深度
+type FlowQueueDepthResult struct {
+	Details []FlowQueueDepth `json:"details"`
+}
+
+// FlowQueueDepth 单个优先级的任务流队列深度
+type FlowQueueDepth struct {
+	Priority enumor.FlowPriority `json:"priority"`
+	// Pending 等待派发的任务流数量
+	Pending uint64 `json:"pending"`
+	// Scheduled 已派发等待执行的任务流数量
+	Scheduled uint64 `json:"scheduled"`
+	// Running 执行中的任务流数量
+	Running uint64 `json:"running"`
+}
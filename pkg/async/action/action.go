@@ -39,6 +39,13 @@ type RollbackAction interface {
 	Rollback(kt run.ExecuteKit, params interface{}) error
 }
 
+// CompensateAction Action如果支持补偿回滚，实现该接口。任务流开启补偿回滚模式时，任务流失败后会按拓扑逆序
+// 对已成功的Action调用补偿，撤销Action已生效的变更（如删除Action创建的资源），result 为Action执行成功时保存的结果。
+// State: success -> compensating -> compensated
+type CompensateAction interface {
+	Compensate(kt run.ExecuteKit, params interface{}, result string) error
+}
+
 // ParameterAction 如果任务运行需要依赖请求参数，需要通过该接口返回参数结构，会将任务实例中的参数内容解析到这个返回参数上。
 type ParameterAction interface {
 	// ParameterNew 返回新的参数结构。返回参数可以实现 Decoder 接口，自定义解码方式。
@@ -30,6 +30,9 @@ type FlowTemplate struct {
 	Name      enumor.FlowName       `json:"name" validate:"required"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Tasks     []TaskTemplate        `json:"tasks" validate:"required,min=1"`
+
+	// RollbackMode 任务流失败后的回滚模式，设置为 compensate 时会按拓扑逆序调用已成功任务的 Rollback 进行补偿。
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode" validate:"omitempty"`
 }
 
 // Validate FlowTemplate.
@@ -38,6 +41,12 @@ func (tpl *FlowTemplate) Validate() error {
 		return err
 	}
 
+	if len(tpl.RollbackMode) != 0 {
+		if err := tpl.RollbackMode.Validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, one := range tpl.Tasks {
 		if err := one.Validate(); err != nil {
 			return err
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/criteria/validator"
 	typesasync "hcm/pkg/dal/dao/types/async"
 	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
 )
 
 // Backend - a common interface for all backends
@@ -39,6 +40,8 @@ type Backend interface {
 	BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error
 	// ListFlow 查询任务流
 	ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error)
+	// CountFlow 查询满足过滤条件的任务流数量
+	CountFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error)
 	// BatchUpdateFlowStateByCAS CAS批量更新Flow状态
 	BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error
 
@@ -53,9 +56,33 @@ type Backend interface {
 	UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error
 	// ListTask 查询任务
 	ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error)
+	// CountTask 查询满足过滤条件的任务数量
+	CountTask(kt *kit.Kit, expr *filter.Expression) (uint64, error)
 
 	// RetryTask 重试任务 将flow置为running, task 置为pending
 	RetryTask(kt *kit.Kit, flowID, taskID string) error
+
+	/*
+		ScheduledFlow 相关接口
+	*/
+	// CreateScheduledFlow 创建定时任务流
+	CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error)
+	// UpdateScheduledFlow 更新定时任务流，仅更新非空字段
+	UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error
+	// UpdateScheduledFlowNextRunByCAS CAS更新定时任务流下一次触发时间，用于保证同一触发时间只被一个触发器抢占
+	UpdateScheduledFlowNextRunByCAS(kt *kit.Kit, info *UpdateScheduledFlowNextRunInfo) error
+	// ListScheduledFlow 查询定时任务流
+	ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error)
+	// CountScheduledFlow 查询满足过滤条件的定时任务流数量
+	CountScheduledFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error)
+	// DeleteScheduledFlow 删除定时任务流
+	DeleteScheduledFlow(kt *kit.Kit, id string) error
+}
+
+// Sweeper 需要定期清理过期数据的backend实现该接口，由主节点的WatchDog定期调用
+type Sweeper interface {
+	// Sweep 清理超过保留时长的终态任务流及其任务
+	Sweep(kt *kit.Kit) error
 }
 
 // ListInput 查询输入参数
@@ -69,6 +96,14 @@ func (info *UpdateFlowInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
 
+// UpdateScheduledFlowNextRunInfo define update scheduled flow next run info.
+type UpdateScheduledFlowNextRunInfo typesasync.UpdateScheduledFlowNextRunInfo
+
+// Validate UpdateScheduledFlowNextRunInfo
+func (info *UpdateScheduledFlowNextRunInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
+
 // UpdateTaskInfo define update task info.
 type UpdateTaskInfo typesasync.UpdateTaskInfo
 
@@ -0,0 +1,650 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// TestMysqlBackend 需要设置 HCM_TEST_MYSQL_ENDPOINTS、HCM_TEST_MYSQL_DATABASE、HCM_TEST_MYSQL_USER、
+// HCM_TEST_MYSQL_PASSWORD 环境变量，指向已初始化表结构的数据库。
+func TestMysqlBackend(t *testing.T) {
+	endpoints := os.Getenv("HCM_TEST_MYSQL_ENDPOINTS")
+	if len(endpoints) == 0 {
+		t.Skip("HCM_TEST_MYSQL_ENDPOINTS not set, skip mysql backend conformance test")
+	}
+
+	opt := cc.DataBase{
+		Resource: cc.ResourceDB{
+			Endpoints:       strings.Split(endpoints, ","),
+			Database:        os.Getenv("HCM_TEST_MYSQL_DATABASE"),
+			User:            os.Getenv("HCM_TEST_MYSQL_USER"),
+			Password:        os.Getenv("HCM_TEST_MYSQL_PASSWORD"),
+			DialTimeoutSec:  15,
+			ReadTimeoutSec:  10,
+			WriteTimeoutSec: 10,
+			MaxOpenConn:     10,
+			MaxIdleConn:     5,
+		},
+		MaxSlowLogLatencyMS: 200,
+		Limiter:             &cc.Limiter{QPS: 500, Burst: 500},
+	}
+	set, err := dao.NewDaoSet(opt)
+	require.NoError(t, err)
+
+	bd, err := Factory(enumor.BackendMysql, set)
+	require.NoError(t, err)
+
+	runBackendConformance(t, bd)
+}
+
+// TestEtcdBackend 默认在内嵌的etcd上运行，设置 HCM_TEST_ETCD_ENDPOINTS 环境变量(多个地址以逗号分隔)时使用外部etcd。
+func TestEtcdBackend(t *testing.T) {
+	cli, prefix := newTestEtcdClient(t)
+
+	bd, err := Factory(enumor.BackendEtcd, &EtcdOption{Client: cli, KeyPrefix: prefix})
+	require.NoError(t, err)
+
+	runBackendConformance(t, bd)
+}
+
+// TestEtcdBackendSweep 过期的终态任务流连同其任务、索引一起被清理，未过期或非终态的任务流保留
+func TestEtcdBackendSweep(t *testing.T) {
+	cli, prefix := newTestEtcdClient(t)
+	kt := newTestKit()
+
+	keep, err := NewEtcd(&EtcdOption{Client: cli, KeyPrefix: prefix})
+	require.NoError(t, err)
+	successFlow, successTasks := createTestFlow(t, keep, 2)
+	pendingFlow, _ := createTestFlow(t, keep, 1)
+	require.NoError(t, keep.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: successFlow.ID, Source: enumor.FlowPending, Target: enumor.FlowSuccess},
+	}))
+
+	// 未配置保留时长时不清理
+	require.NoError(t, keep.(Sweeper).Sweep(kt))
+	assert.Equal(t, enumor.FlowSuccess, getTestFlow(t, keep, successFlow.ID).State)
+
+	// 保留时长内的任务流不清理
+	bd, err := NewEtcd(&EtcdOption{Client: cli, KeyPrefix: prefix, Retention: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, bd.(Sweeper).Sweep(kt))
+	assert.Equal(t, enumor.FlowSuccess, getTestFlow(t, bd, successFlow.ID).State)
+
+	// flow的更新时间精确到秒，等待其早于保留时长
+	time.Sleep(2 * time.Second)
+	bd, err = NewEtcd(&EtcdOption{Client: cli, KeyPrefix: prefix, Retention: time.Second})
+	require.NoError(t, err)
+	require.NoError(t, bd.(Sweeper).Sweep(kt))
+
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: tools.ContainersExpression("id", []string{successFlow.ID, pendingFlow.ID}),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, pendingFlow.ID, flows[0].ID)
+	assert.Empty(t, listTestTasks(t, bd, successFlow.ID))
+
+	e := bd.(*etcd)
+	leftKeys := []string{e.flowStateKey(enumor.FlowSuccess, successFlow.ID), e.flowTaskPrefix(successFlow.ID)}
+	for _, one := range successTasks {
+		leftKeys = append(leftKeys, e.taskKey(one.ID), e.taskStateKey(one.State, one.ID))
+	}
+	for _, key := range leftKeys {
+		resp, err := cli.Get(kt.Ctx, key, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		require.NoError(t, err)
+		assert.Zero(t, resp.Count, "key %s should be deleted", key)
+	}
+}
+
+// newTestEtcdClient 返回etcd客户端及本次测试独占的key前缀，未设置 HCM_TEST_ETCD_ENDPOINTS 时启动内嵌的etcd
+func newTestEtcdClient(t *testing.T) (*clientv3.Client, string) {
+	endpoints := os.Getenv("HCM_TEST_ETCD_ENDPOINTS")
+	if len(endpoints) == 0 {
+		endpoints = startEmbedEtcd(t)
+	}
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	prefix := fmt.Sprintf("/hcm_test/async/%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		cli.Delete(kit.New().Ctx, prefix+"/", clientv3.WithPrefix())
+		cli.Close()
+	})
+
+	return cli, prefix
+}
+
+// startEmbedEtcd 在临时目录中启动单节点etcd，返回客户端地址，测试结束时关闭
+func startEmbedEtcd(t *testing.T) string {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL := url.URL{Scheme: "http", Host: freeLocalAddr(t)}
+	peerURL := url.URL{Scheme: "http", Host: freeLocalAddr(t)}
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		server.Server.Stop()
+		t.Fatal("embed etcd start timeout")
+	}
+
+	return clientURL.String()
+}
+
+func freeLocalAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+// runBackendConformance 所有Backend实现都需要满足的行为，各实现共用
+func runBackendConformance(t *testing.T, bd Backend) {
+	t.Run("CreateAndList", func(t *testing.T) { testCreateAndList(t, bd) })
+	t.Run("CreateLargeFlow", func(t *testing.T) { testCreateLargeFlow(t, bd) })
+	t.Run("FlowStateCAS", func(t *testing.T) { testFlowStateCAS(t, bd) })
+	t.Run("FlowStateCASConcurrent", func(t *testing.T) { testFlowStateCASConcurrent(t, bd) })
+	t.Run("UpdateFlowAndFilter", func(t *testing.T) { testUpdateFlowAndFilter(t, bd) })
+	t.Run("CountAndSort", func(t *testing.T) { testCountAndSort(t, bd) })
+	t.Run("TaskStateCAS", func(t *testing.T) { testTaskStateCAS(t, bd) })
+	t.Run("UpdateTask", func(t *testing.T) { testUpdateTask(t, bd) })
+	t.Run("BatchCreateTask", func(t *testing.T) { testBatchCreateTask(t, bd) })
+	t.Run("RetryTask", func(t *testing.T) { testRetryTask(t, bd) })
+	t.Run("ScheduledFlowNextRunCAS", func(t *testing.T) { testScheduledFlowNextRunCAS(t, bd) })
+	t.Run("ListByState", func(t *testing.T) { testListByState(t, bd) })
+}
+
+func newTestKit() *kit.Kit {
+	kt := kit.New()
+	kt.User = "conformance"
+	return kt
+}
+
+func createTestFlow(t *testing.T, bd Backend, taskCount int) (*model.Flow, []model.Task) {
+	kt := newTestKit()
+
+	tasks := make([]model.Task, 0, taskCount)
+	for i := 0; i < taskCount; i++ {
+		task := model.Task{
+			FlowName:   enumor.FlowName("conformance"),
+			ActionID:   action.ActIDType(fmt.Sprintf("%d", i+1)),
+			ActionName: enumor.ActionName("conformance"),
+			Params:     `{"index":1}`,
+			Retry:      &tableasync.Retry{Enable: false},
+		}
+		if i > 0 {
+			task.DependOn = []action.ActIDType{action.ActIDType(fmt.Sprintf("%d", i))}
+		}
+		tasks = append(tasks, task)
+	}
+
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      enumor.FlowName("conformance"),
+		ShareData: tableasync.NewShareData(map[string]string{"key": "value"}),
+		Memo:      "conformance test",
+		Tasks:     tasks,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, flowID)
+
+	return getTestFlow(t, bd, flowID), listTestTasks(t, bd, flowID)
+}
+
+func getTestFlow(t *testing.T, bd Backend, flowID string) *model.Flow {
+	flows, err := bd.ListFlow(newTestKit(), &ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+
+	return &flows[0]
+}
+
+func getTestTask(t *testing.T, bd Backend, taskID string) *model.Task {
+	tasks, err := bd.ListTask(newTestKit(), &ListInput{
+		Filter: tools.EqualExpression("id", taskID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	return &tasks[0]
+}
+
+func listTestTasks(t *testing.T, bd Backend, flowID string) []model.Task {
+	tasks, err := bd.ListTask(newTestKit(), &ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+
+	return tasks
+}
+
+func assertRecordNotUpdate(t *testing.T, err error) {
+	require.Error(t, err)
+	var ef *errf.ErrorF
+	require.True(t, errors.As(err, &ef), "error should be errf.ErrorF, got: %v", err)
+	assert.Equal(t, errf.RecordNotUpdate, ef.Code)
+}
+
+func testCreateAndList(t *testing.T, bd Backend) {
+	flow, tasks := createTestFlow(t, bd, 3)
+
+	assert.Equal(t, enumor.FlowPending, flow.State)
+	assert.Equal(t, "conformance test", flow.Memo)
+	assert.Equal(t, "conformance", flow.Creator)
+	require.NotNil(t, flow.Worker)
+	assert.Empty(t, *flow.Worker)
+	require.NotNil(t, flow.ShareData)
+	val, exist := flow.ShareData.Get("key")
+	assert.True(t, exist)
+	assert.Equal(t, "value", val)
+
+	require.Len(t, tasks, 3)
+	for idx, one := range tasks {
+		assert.Equal(t, flow.ID, one.FlowID)
+		assert.Equal(t, enumor.TaskPending, one.State)
+		assert.Equal(t, action.ActIDType(fmt.Sprintf("%d", idx+1)), one.ActionID)
+		if idx > 0 {
+			assert.Equal(t, []action.ActIDType{tasks[idx-1].ActionID}, one.DependOn)
+		}
+	}
+
+	// 分页
+	page, err := bd.ListTask(newTestKit(), &ListInput{
+		Filter: tools.EqualExpression("flow_id", flow.ID),
+		Page:   &core.BasePage{Start: 1, Limit: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, tasks[1].ID, page[0].ID)
+
+	// init 状态创建的flow不参与调度
+	initFlowID, err := bd.CreateFlow(newTestKit(), &model.Flow{
+		Name:  enumor.FlowName("conformance"),
+		State: enumor.FlowInit,
+		Tasks: []model.Task{{ActionID: "1", ActionName: "conformance", State: enumor.TaskInit}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, enumor.FlowInit, getTestFlow(t, bd, initFlowID).State)
+	initTasks := listTestTasks(t, bd, initFlowID)
+	require.Len(t, initTasks, 1)
+	assert.Equal(t, enumor.TaskInit, initTasks[0].State)
+}
+
+func testCreateLargeFlow(t *testing.T, bd Backend) {
+	// 任务数量超过etcd单事务操作数上限
+	_, tasks := createTestFlow(t, bd, 150)
+	require.Len(t, tasks, 150)
+	for idx := 1; idx < len(tasks); idx++ {
+		assert.Less(t, tasks[idx-1].ID, tasks[idx].ID, "tasks should be ordered by id")
+	}
+}
+
+func testFlowStateCAS(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flowA, _ := createTestFlow(t, bd, 1)
+	flowB, _ := createTestFlow(t, bd, 1)
+
+	// source 不匹配时不更新
+	err := bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowA.ID, Source: enumor.FlowRunning, Target: enumor.FlowSuccess},
+	})
+	assertRecordNotUpdate(t, err)
+	assert.Equal(t, enumor.FlowPending, getTestFlow(t, bd, flowA.ID).State)
+
+	// 批量中任一失败则整体不更新
+	err = bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowA.ID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("w1")},
+		{ID: flowB.ID, Source: enumor.FlowRunning, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("w1")},
+	})
+	assertRecordNotUpdate(t, err)
+	assert.Equal(t, enumor.FlowPending, getTestFlow(t, bd, flowA.ID).State)
+	assert.Equal(t, enumor.FlowPending, getTestFlow(t, bd, flowB.ID).State)
+
+	err = bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowA.ID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("w1")},
+		{ID: flowB.ID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("w2"),
+			Reason: &tableasync.Reason{Message: "dispatch"}},
+	})
+	require.NoError(t, err)
+
+	gotA := getTestFlow(t, bd, flowA.ID)
+	assert.Equal(t, enumor.FlowScheduled, gotA.State)
+	assert.Equal(t, "w1", converter.PtrToVal(gotA.Worker))
+	gotB := getTestFlow(t, bd, flowB.ID)
+	assert.Equal(t, enumor.FlowScheduled, gotB.State)
+	assert.Equal(t, "w2", converter.PtrToVal(gotB.Worker))
+	require.NotNil(t, gotB.Reason)
+	assert.Equal(t, "dispatch", gotB.Reason.Message)
+
+	// 不指定worker时保留原worker
+	err = bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowA.ID, Source: enumor.FlowScheduled, Target: enumor.FlowRunning},
+	})
+	require.NoError(t, err)
+	gotA = getTestFlow(t, bd, flowA.ID)
+	assert.Equal(t, enumor.FlowRunning, gotA.State)
+	assert.Equal(t, "w1", converter.PtrToVal(gotA.Worker))
+}
+
+func testFlowStateCASConcurrent(t *testing.T, bd Backend) {
+	flow, _ := createTestFlow(t, bd, 1)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeed := make([]string, 0)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			err := bd.BatchUpdateFlowStateByCAS(newTestKit(), []UpdateFlowInfo{
+				{ID: flow.ID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: &worker},
+			})
+			if err == nil {
+				mu.Lock()
+				succeed = append(succeed, worker)
+				mu.Unlock()
+			}
+		}(fmt.Sprintf("worker-%d", i))
+	}
+	wg.Wait()
+
+	require.Len(t, succeed, 1, "exactly one worker should win the cas update")
+	got := getTestFlow(t, bd, flow.ID)
+	assert.Equal(t, enumor.FlowScheduled, got.State)
+	assert.Equal(t, succeed[0], converter.PtrToVal(got.Worker))
+}
+
+func testUpdateFlowAndFilter(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flowA, _ := createTestFlow(t, bd, 1)
+	flowB, _ := createTestFlow(t, bd, 1)
+
+	err := bd.BatchUpdateFlow(kt, []model.Flow{
+		{ID: flowA.ID, State: enumor.FlowRunning, Worker: converter.ValToPtr("alive"), Reviser: "a"},
+		{ID: flowB.ID, State: enumor.FlowRunning, Worker: converter.ValToPtr("dead"), Reviser: "b"},
+	})
+	require.NoError(t, err)
+
+	gotA := getTestFlow(t, bd, flowA.ID)
+	assert.Equal(t, enumor.FlowRunning, gotA.State)
+	assert.Equal(t, "conformance test", gotA.Memo, "unset fields should be kept")
+	assert.Equal(t, "a", gotA.Reviser)
+
+	// watch dog 查询处理节点已下线的flow的过滤方式
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				tools.RuleIn("id", []string{flowA.ID, flowB.ID}),
+				&filter.AtomRule{Field: "state", Op: filter.Equal.Factory(), Value: enumor.FlowRunning},
+				&filter.AtomRule{Field: "worker", Op: filter.NotIn.Factory(), Value: []string{"alive"}},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, flowB.ID, flows[0].ID)
+
+	// 重置worker为空
+	err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: flowB.ID, State: enumor.FlowPending,
+		Worker: converter.ValToPtr("")}})
+	require.NoError(t, err)
+	gotB := getTestFlow(t, bd, flowB.ID)
+	assert.Equal(t, enumor.FlowPending, gotB.State)
+	assert.Empty(t, converter.PtrToVal(gotB.Worker))
+}
+
+func testCountAndSort(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flowA, _ := createTestFlow(t, bd, 2)
+	flowB, _ := createTestFlow(t, bd, 3)
+	ids := []string{flowA.ID, flowB.ID}
+
+	count, err := bd.CountFlow(kt, tools.ContainersExpression("id", ids))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	count, err = bd.CountTask(kt, tools.EqualExpression("flow_id", flowB.ID))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Descending},
+	})
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+	assert.Equal(t, flowB.ID, flows[0].ID)
+	assert.Equal(t, flowA.ID, flows[1].ID)
+}
+
+func testTaskStateCAS(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	_, tasks := createTestFlow(t, bd, 1)
+	task := tasks[0]
+
+	err := bd.UpdateTaskStateByCAS(kt, &UpdateTaskInfo{ID: task.ID, Source: enumor.TaskRunning,
+		Target: enumor.TaskSuccess})
+	assertRecordNotUpdate(t, err)
+	assert.Equal(t, enumor.TaskPending, getTestTask(t, bd, task.ID).State)
+
+	err = bd.UpdateTaskStateByCAS(kt, &UpdateTaskInfo{ID: task.ID, Source: enumor.TaskPending,
+		Target: enumor.TaskRunning, Reason: &tableasync.Reason{Message: "start"}})
+	require.NoError(t, err)
+	got := getTestTask(t, bd, task.ID)
+	assert.Equal(t, enumor.TaskRunning, got.State)
+	require.NotNil(t, got.Reason)
+	assert.Equal(t, "start", got.Reason.Message)
+}
+
+func testUpdateTask(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	_, tasks := createTestFlow(t, bd, 1)
+	task := tasks[0]
+
+	err := bd.UpdateTask(kt, &model.Task{ID: task.ID, State: enumor.TaskSuccess, Result: `{"ok":true}`})
+	require.NoError(t, err)
+
+	got := getTestTask(t, bd, task.ID)
+	assert.Equal(t, enumor.TaskSuccess, got.State)
+	assert.JSONEq(t, `{"ok":true}`, string(got.Result))
+	assert.JSONEq(t, string(task.Params), string(got.Params), "unset fields should be kept")
+}
+
+func testBatchCreateTask(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flow, tasks := createTestFlow(t, bd, 1)
+
+	ids, err := bd.BatchCreateTask(kt, []model.Task{
+		{FlowID: flow.ID, FlowName: flow.Name, ActionID: "2", ActionName: "conformance",
+			Reason: new(tableasync.Reason), Creator: kt.User, Reviser: kt.User},
+		{FlowID: flow.ID, FlowName: flow.Name, ActionID: "3", ActionName: "conformance",
+			Reason: new(tableasync.Reason), Creator: kt.User, Reviser: kt.User},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	all := listTestTasks(t, bd, flow.ID)
+	require.Len(t, all, 3)
+	assert.Equal(t, tasks[0].ID, all[0].ID)
+
+	byIDs, err := bd.ListTask(kt, &ListInput{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, byIDs, 2)
+	for _, one := range byIDs {
+		assert.Equal(t, enumor.TaskPending, one.State)
+		assert.Contains(t, ids, one.ID)
+	}
+}
+
+func testRetryTask(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flow, tasks := createTestFlow(t, bd, 2)
+
+	// 非 failed 状态不允许重试
+	require.Error(t, bd.RetryTask(kt, flow.ID, tasks[0].ID))
+
+	require.NoError(t, bd.BatchUpdateFlow(kt, []model.Flow{{ID: flow.ID, State: enumor.FlowFailed}}))
+	require.Error(t, bd.RetryTask(kt, flow.ID, tasks[0].ID), "task is not failed")
+
+	require.NoError(t, bd.UpdateTask(kt, &model.Task{ID: tasks[0].ID, State: enumor.TaskFailed}))
+	require.Error(t, bd.RetryTask(kt, "not-exist", tasks[0].ID))
+
+	require.NoError(t, bd.RetryTask(kt, flow.ID, tasks[0].ID))
+	gotFlow := getTestFlow(t, bd, flow.ID)
+	assert.Equal(t, enumor.FlowPending, gotFlow.State)
+	gotTask := getTestTask(t, bd, tasks[0].ID)
+	assert.Equal(t, enumor.TaskPending, gotTask.State)
+	require.NotNil(t, gotTask.Reason)
+	assert.Equal(t, "retry task "+tasks[0].ID, gotTask.Reason.Message)
+
+	// 重试后状态已变化，不能重复重试
+	require.Error(t, bd.RetryTask(kt, flow.ID, tasks[0].ID))
+}
+
+func testScheduledFlowNextRunCAS(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	id, err := bd.CreateScheduledFlow(kt, &model.ScheduledFlow{
+		Name:          fmt.Sprintf("conformance-%d", time.Now().UnixNano()),
+		Cron:          "*/5 * * * *",
+		FlowName:      enumor.FlowName("conformance"),
+		Tasks:         tableasync.ScheduledFlowTasks{},
+		MisfirePolicy: enumor.MisfireSkip,
+		Enable:        converter.ValToPtr(true),
+		NextRunAt:     "2025-01-01 00:00:00",
+	})
+	require.NoError(t, err)
+	defer bd.DeleteScheduledFlow(kt, id)
+
+	info := &UpdateScheduledFlowNextRunInfo{ID: id, Source: "2025-01-01 00:00:00", Target: "2025-01-01 00:05:00"}
+	require.NoError(t, bd.UpdateScheduledFlowNextRunByCAS(kt, info))
+
+	// 同一触发时间只能被抢占一次
+	assertRecordNotUpdate(t, bd.UpdateScheduledFlowNextRunByCAS(kt, info))
+
+	flows, err := bd.ListScheduledFlow(kt, &ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, "2025-01-01 00:05:00", flows[0].NextRunAt)
+}
+
+func testListByState(t *testing.T, bd Backend) {
+	kt := newTestKit()
+	flow, tasks := createTestFlow(t, bd, 2)
+
+	require.NoError(t, bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flow.ID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("w1")},
+	}))
+	require.NoError(t, bd.UpdateTaskStateByCAS(kt, &UpdateTaskInfo{
+		ID: tasks[0].ID, Source: enumor.TaskPending, Target: enumor.TaskRunning,
+	}))
+
+	listFlowIDs := func(states ...enumor.FlowState) []string {
+		flows, err := bd.ListFlow(kt, &ListInput{
+			Filter: tools.ContainersExpression("state", states),
+			Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+		})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(flows))
+		for _, one := range flows {
+			assert.Contains(t, states, one.State)
+			ids = append(ids, one.ID)
+		}
+		return ids
+	}
+	assert.Contains(t, listFlowIDs(enumor.FlowScheduled, enumor.FlowRunning), flow.ID)
+	assert.NotContains(t, listFlowIDs(enumor.FlowPending), flow.ID)
+
+	listTaskIDs := func(state enumor.TaskState) []string {
+		result, err := bd.ListTask(kt, &ListInput{
+			Filter: tools.EqualExpression("state", state),
+			Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+		})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(result))
+		for _, one := range result {
+			assert.Equal(t, state, one.State)
+			ids = append(ids, one.ID)
+		}
+		return ids
+	}
+	assert.Contains(t, listTaskIDs(enumor.TaskRunning), tasks[0].ID)
+	assert.NotContains(t, listTaskIDs(enumor.TaskPending), tasks[0].ID)
+
+	// 非CAS更新状态时同样迁移索引
+	require.NoError(t, bd.UpdateTask(kt, &model.Task{ID: tasks[0].ID, State: enumor.TaskSuccess}))
+	assert.NotContains(t, listTaskIDs(enumor.TaskRunning), tasks[0].ID)
+	assert.Contains(t, listTaskIDs(enumor.TaskSuccess), tasks[0].ID)
+}
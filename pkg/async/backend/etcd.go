@@ -0,0 +1,1150 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// DefaultEtcdKeyPrefix etcd backend 默认的key前缀
+	DefaultEtcdKeyPrefix = "/hcm/async"
+
+	// etcdMaxTxnOps etcd 单个事务允许的最大操作数(etcd server --max-txn-ops 默认值)
+	etcdMaxTxnOps = 128
+	// etcdCASRetryCount CAS更新因并发修改冲突时的重试次数
+	etcdCASRetryCount = 5
+	// etcdScanLimit 范围查询时每批次读取的key数量
+	etcdScanLimit = 1000
+	// etcdCASBatchSize CAS更新时单个事务包含的key数量，每个key最多对应写入记录、删除及写入状态索引3个操作
+	etcdCASBatchSize = etcdMaxTxnOps / 3
+	// etcdSweepInterval 清理过期任务流的最小间隔
+	etcdSweepInterval = 10 * time.Minute
+)
+
+// etcdSweepStates 可被清理的任务流终态
+var etcdSweepStates = []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel}
+
+// EtcdOption etcd backend option.
+type EtcdOption struct {
+	Client *clientv3.Client
+	// KeyPrefix 所有key的前缀，为空时使用 DefaultEtcdKeyPrefix
+	KeyPrefix string
+	// Retention 处于终态的任务流的保留时长，超过后连同其任务一起被清理，为0时不清理
+	Retention time.Duration
+}
+
+// NewEtcd create etcd instance
+func NewEtcd(opt *EtcdOption) (Backend, error) {
+	if opt == nil || opt.Client == nil {
+		return nil, errors.New("etcd client is required")
+	}
+
+	prefix := strings.TrimRight(opt.KeyPrefix, "/")
+	if len(prefix) == 0 {
+		prefix = DefaultEtcdKeyPrefix
+	}
+
+	return &etcd{
+		cli:       opt.Client,
+		prefix:    prefix,
+		retention: opt.Retention,
+	}, nil
+}
+
+// etcd 基于etcd实现的backend，flow、task以json格式存储，状态的CAS更新基于key的ModRevision实现。
+// key 结构:
+//
+//	{prefix}/flow/{flow_id}               -> flow
+//	{prefix}/task/{task_id}               -> task
+//	{prefix}/flow_task/{flow_id}/{task_id} -> 空值，flow_id到task的索引
+//	{prefix}/flow_state/{state}/{flow_id}  -> 空值，状态到flow的索引，与flow在同一事务中更新
+//	{prefix}/task_state/{state}/{task_id}  -> 空值，状态到task的索引，与task在同一事务中更新
+//	{prefix}/scheduled_flow/{id}           -> scheduled flow
+//	{prefix}/scheduled_flow_name/{name}    -> scheduled flow id，保证定时任务流名称唯一
+//	{prefix}/id_generator/{resource}       -> 当前最大id
+type etcd struct {
+	cli       *clientv3.Client
+	prefix    string
+	retention time.Duration
+
+	sweepLock   sync.Mutex
+	lastSweepAt time.Time
+}
+
+var _ Backend = new(etcd)
+
+var _ Sweeper = new(etcd)
+
+func (e *etcd) flowPrefix() string {
+	return path.Join(e.prefix, "flow") + "/"
+}
+
+func (e *etcd) flowKey(id string) string {
+	return e.flowPrefix() + id
+}
+
+func (e *etcd) taskPrefix() string {
+	return path.Join(e.prefix, "task") + "/"
+}
+
+func (e *etcd) taskKey(id string) string {
+	return e.taskPrefix() + id
+}
+
+func (e *etcd) flowTaskPrefix(flowID string) string {
+	return path.Join(e.prefix, "flow_task", flowID) + "/"
+}
+
+func (e *etcd) flowTaskKey(flowID, taskID string) string {
+	return e.flowTaskPrefix(flowID) + taskID
+}
+
+func (e *etcd) flowStatePrefix(state enumor.FlowState) string {
+	return path.Join(e.prefix, "flow_state", string(state)) + "/"
+}
+
+func (e *etcd) flowStateKey(state enumor.FlowState, id string) string {
+	return e.flowStatePrefix(state) + id
+}
+
+func (e *etcd) taskStatePrefix(state enumor.TaskState) string {
+	return path.Join(e.prefix, "task_state", string(state)) + "/"
+}
+
+func (e *etcd) taskStateKey(state enumor.TaskState, id string) string {
+	return e.taskStatePrefix(state) + id
+}
+
+func (e *etcd) scheduledFlowPrefix() string {
+	return path.Join(e.prefix, "scheduled_flow") + "/"
+}
+
+func (e *etcd) scheduledFlowKey(id string) string {
+	return e.scheduledFlowPrefix() + id
+}
+
+func (e *etcd) scheduledFlowNameKey(name string) string {
+	return path.Join(e.prefix, "scheduled_flow_name", name)
+}
+
+func (e *etcd) idGeneratorKey(resource table.Name) string {
+	return path.Join(e.prefix, "id_generator", string(resource))
+}
+
+// CreateFlow 创建任务流，先写入任务再写入任务流，保证任务流可见时其任务均已存在。
+func (e *etcd) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+
+	flowState := enumor.FlowPending
+	if flow.State == enumor.FlowInit {
+		flowState = flow.State
+	}
+
+	rollbackMode := flow.RollbackMode
+	if len(rollbackMode) == 0 {
+		rollbackMode = enumor.FlowRollbackNone
+	}
+
+	priority := flow.Priority
+	if priority == 0 {
+		priority = enumor.FlowPriorityNormal
+	}
+
+	flowIDs, err := e.genIDs(kt, table.AsyncFlowTable, 1)
+	if err != nil {
+		return "", err
+	}
+	flowID := flowIDs[0]
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	tasks := make([]model.Task, 0, len(flow.Tasks))
+	for _, one := range flow.Tasks {
+		taskState := enumor.TaskPending
+		if one.State == enumor.TaskInit {
+			taskState = one.State
+		}
+
+		tasks = append(tasks, model.Task{
+			FlowID:     flowID,
+			FlowName:   one.FlowName,
+			ActionID:   one.ActionID,
+			ActionName: one.ActionName,
+			Params:     one.Params,
+			Retry:      one.Retry,
+			DependOn:   one.DependOn,
+			State:      taskState,
+			Reason:     new(tableasync.Reason),
+			Creator:    kt.User,
+			Reviser:    kt.User,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	if _, err = e.createTasks(kt, tasks); err != nil {
+		return "", err
+	}
+
+	md := &model.Flow{
+		ID:             flowID,
+		Name:           flow.Name,
+		State:          flowState,
+		Reason:         new(tableasync.Reason),
+		ShareData:      flow.ShareData,
+		Memo:           flow.Memo,
+		Worker:         converter.ValToPtr(""),
+		RollbackMode:   rollbackMode,
+		Priority:       priority,
+		ConcurrencyKey: flow.ConcurrencyKey,
+		Creator:        kt.User,
+		Reviser:        kt.User,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	val, err := json.Marshal(md)
+	if err != nil {
+		return "", err
+	}
+
+	key := e.flowKey(flowID)
+	resp, err := e.cli.Txn(kt.Ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(val)), clientv3.OpPut(e.flowStateKey(md.State, flowID), "")).
+		Commit()
+	if err != nil {
+		logs.Errorf("put async flow to etcd failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return "", err
+	}
+	if !resp.Succeeded {
+		return "", fmt.Errorf("flow %s already exists", flowID)
+	}
+
+	return flowID, nil
+}
+
+// BatchUpdateFlow 批量更新任务流，仅更新非空字段
+func (e *etcd) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
+
+	updates := make(map[string]model.Flow, len(flows))
+	keys := make([]string, 0, len(flows))
+	for _, one := range flows {
+		if len(one.ID) == 0 {
+			return errors.New("flow id is required")
+		}
+		key := e.flowKey(one.ID)
+		updates[key] = one
+		keys = append(keys, key)
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	return e.casUpdate(kt, keys, func(key string, val []byte) ([]byte, error) {
+		one := updates[key]
+		if val == nil {
+			return nil, errf.Newf(errf.RecordNotFound, "flow %s not found", one.ID)
+		}
+
+		flow := new(model.Flow)
+		if err := json.Unmarshal(val, flow); err != nil {
+			return nil, err
+		}
+
+		if len(one.State) != 0 {
+			flow.State = one.State
+		}
+		if one.Reason != nil {
+			flow.Reason = one.Reason
+		}
+		if one.ShareData != nil {
+			flow.ShareData = one.ShareData
+		}
+		if len(one.Memo) != 0 {
+			flow.Memo = one.Memo
+		}
+		if one.Worker != nil {
+			flow.Worker = one.Worker
+		}
+		if len(one.Reviser) != 0 {
+			flow.Reviser = one.Reviser
+		}
+		flow.UpdatedAt = now
+
+		return json.Marshal(flow)
+	})
+}
+
+// ListFlow 查询任务流
+func (e *etcd) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
+
+	if err := validateListInput(input); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.Flow, 0), nil
+	}
+
+	matcher := newPageMatcher[model.Flow](input)
+	if err := e.scanFlow(kt, input.Filter, matcher); err != nil {
+		return nil, err
+	}
+
+	return matcher.result(), nil
+}
+
+// CountFlow 查询任务流数量
+func (e *etcd) CountFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	matcher := newCountMatcher[model.Flow](expr)
+	if err := e.scanFlow(kt, expr, matcher); err != nil {
+		return 0, err
+	}
+
+	return matcher.count, nil
+}
+
+func (e *etcd) scanFlow(kt *kit.Kit, expr *filter.Expression, matcher *pageMatcher[model.Flow]) error {
+	if ids, ok := pinnedIDs(expr); ok {
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, e.flowKey(id))
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	if states, ok := pinnedStates(expr); ok {
+		keys := make([]string, 0)
+		for _, state := range states {
+			stateKeys, err := e.listIndexedKeys(kt, e.flowStatePrefix(enumor.FlowState(state)), e.flowKey)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, stateKeys...)
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	return scanEtcdPrefix(kt, e.cli, e.flowPrefix(), matcher)
+}
+
+// BatchUpdateFlowStateByCAS CAS批量更新流状态
+func (e *etcd) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error {
+
+	updates := make(map[string]UpdateFlowInfo, len(infos))
+	keys := make([]string, 0, len(infos))
+	for _, one := range infos {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+		key := e.flowKey(one.ID)
+		updates[key] = one
+		keys = append(keys, key)
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	return e.casUpdate(kt, keys, func(key string, val []byte) ([]byte, error) {
+		info := updates[key]
+		flow := new(model.Flow)
+		if val != nil {
+			if err := json.Unmarshal(val, flow); err != nil {
+				return nil, err
+			}
+		}
+
+		if val == nil || flow.State != info.Source {
+			return nil, errf.Newf(errf.RecordNotUpdate, "flow[%s] update state: `%s`->`%s`, worker: %+v failed",
+				info.ID, info.Source, info.Target, info.Worker)
+		}
+
+		flow.State = info.Target
+		if info.Worker != nil {
+			flow.Worker = info.Worker
+		}
+		if info.Reason != nil {
+			flow.Reason = info.Reason
+		}
+		flow.UpdatedAt = now
+
+		return json.Marshal(flow)
+	})
+}
+
+// BatchCreateTask 批量创建任务
+func (e *etcd) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	mds := make([]model.Task, 0, len(tasks))
+	for _, one := range tasks {
+		mds = append(mds, model.Task{
+			FlowID:     one.FlowID,
+			FlowName:   one.FlowName,
+			ActionID:   one.ActionID,
+			ActionName: one.ActionName,
+			Params:     one.Params,
+			Retry:      one.Retry,
+			DependOn:   one.DependOn,
+			State:      enumor.TaskPending,
+			Reason:     one.Reason,
+			Creator:    one.Creator,
+			Reviser:    one.Reviser,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+
+	return e.createTasks(kt, mds)
+}
+
+// createTasks 分批写入任务及flow_id、状态索引，任一批次失败时尽力删除已写入的任务。
+func (e *etcd) createTasks(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+	if len(tasks) == 0 {
+		return make([]string, 0), nil
+	}
+
+	ids, err := e.genIDs(kt, table.AsyncFlowTaskTable, len(tasks))
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]clientv3.Op, 0, 3*len(tasks))
+	for idx := range tasks {
+		tasks[idx].ID = ids[idx]
+		val, err := json.Marshal(tasks[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		ops = append(ops, clientv3.OpPut(e.taskKey(ids[idx]), string(val)),
+			clientv3.OpPut(e.flowTaskKey(tasks[idx].FlowID, ids[idx]), ""),
+			clientv3.OpPut(e.taskStateKey(tasks[idx].State, ids[idx]), ""))
+	}
+
+	created := make([]clientv3.Op, 0, len(ops))
+	// 同一任务的3个操作在同一批次中写入
+	for _, part := range slice.Split(ops, etcdCASBatchSize*3) {
+		if _, err = e.cli.Txn(kt.Ctx).Then(part...).Commit(); err != nil {
+			logs.Errorf("put async flow tasks to etcd failed, err: %v, rid: %s", err, kt.Rid)
+			e.rollbackPuts(kt, created)
+			return nil, err
+		}
+		created = append(created, part...)
+	}
+
+	return ids, nil
+}
+
+func (e *etcd) rollbackPuts(kt *kit.Kit, puts []clientv3.Op) {
+	deletes := make([]clientv3.Op, 0, len(puts))
+	for _, one := range puts {
+		deletes = append(deletes, clientv3.OpDelete(string(one.KeyBytes())))
+	}
+
+	for _, part := range slice.Split(deletes, etcdMaxTxnOps) {
+		if _, err := e.cli.Txn(kt.Ctx).Then(part...).Commit(); err != nil {
+			logs.Errorf("rollback etcd puts failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+// UpdateTask 更新任务，仅更新非空字段
+func (e *etcd) UpdateTask(kt *kit.Kit, task *model.Task) error {
+
+	if task == nil || len(task.ID) == 0 {
+		return errors.New("task id is required")
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	return e.casUpdate(kt, []string{e.taskKey(task.ID)}, func(key string, val []byte) ([]byte, error) {
+		if val == nil {
+			return nil, errf.Newf(errf.RecordNotFound, "task %s not found", task.ID)
+		}
+
+		md := new(model.Task)
+		if err := json.Unmarshal(val, md); err != nil {
+			return nil, err
+		}
+
+		if task.Retry != nil {
+			md.Retry = task.Retry
+		}
+		if len(task.State) != 0 {
+			md.State = task.State
+		}
+		if len(task.Result) != 0 {
+			md.Result = task.Result
+		}
+		if task.Reason != nil {
+			md.Reason = task.Reason
+		}
+		md.Reviser = kt.User
+		md.UpdatedAt = now
+
+		return json.Marshal(md)
+	})
+}
+
+// UpdateTaskStateByCAS CAS更新任务状态
+func (e *etcd) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	err := e.casUpdate(kt, []string{e.taskKey(info.ID)}, func(key string, val []byte) ([]byte, error) {
+		return casTask(val, info, now)
+	})
+	if err != nil {
+		logs.Errorf("fail to update task state cas, err: %v, info: %+v, rid: %s", err, info, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func casTask(val []byte, info *UpdateTaskInfo, now string) ([]byte, error) {
+	task := new(model.Task)
+	if val != nil {
+		if err := json.Unmarshal(val, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if val == nil || task.State != info.Source {
+		return nil, errf.Newf(errf.RecordNotUpdate, "task[%s: %s] update state to %s failed", info.ID,
+			info.Source, info.Target)
+	}
+
+	task.State = info.Target
+	if info.Reason != nil {
+		task.Reason = info.Reason
+	}
+	task.UpdatedAt = now
+
+	return json.Marshal(task)
+}
+
+// ListTask 查询任务
+func (e *etcd) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
+
+	if err := validateListInput(input); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.Task, 0), nil
+	}
+
+	matcher := newPageMatcher[model.Task](input)
+	if err := e.scanTask(kt, input.Filter, matcher); err != nil {
+		return nil, err
+	}
+
+	return matcher.result(), nil
+}
+
+// CountTask 查询任务数量
+func (e *etcd) CountTask(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	matcher := newCountMatcher[model.Task](expr)
+	if err := e.scanTask(kt, expr, matcher); err != nil {
+		return 0, err
+	}
+
+	return matcher.count, nil
+}
+
+func (e *etcd) scanTask(kt *kit.Kit, expr *filter.Expression, matcher *pageMatcher[model.Task]) error {
+	if ids, ok := pinnedIDs(expr); ok {
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, e.taskKey(id))
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	if flowID, ok := pinnedFlowID(expr); ok {
+		keys, err := e.listIndexedKeys(kt, e.flowTaskPrefix(flowID), e.taskKey)
+		if err != nil {
+			return err
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	if states, ok := pinnedStates(expr); ok {
+		keys := make([]string, 0)
+		for _, state := range states {
+			stateKeys, err := e.listIndexedKeys(kt, e.taskStatePrefix(enumor.TaskState(state)), e.taskKey)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, stateKeys...)
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	return scanEtcdPrefix(kt, e.cli, e.taskPrefix(), matcher)
+}
+
+// listIndexedKeys 分批读取索引前缀下的全部key，将索引key末尾的id通过 toKey 转换为记录的key
+func (e *etcd) listIndexedKeys(kt *kit.Kit, prefix string, toKey func(id string) string) ([]string, error) {
+	keys := make([]string, 0)
+	end := clientv3.GetPrefixRangeEnd(prefix)
+	from := prefix
+	for {
+		resp, err := e.cli.Get(kt.Ctx, from, clientv3.WithRange(end), clientv3.WithLimit(etcdScanLimit),
+			clientv3.WithKeysOnly())
+		if err != nil {
+			logs.Errorf("list index keys from etcd failed, err: %v, prefix: %s, rid: %s", err, prefix, kt.Rid)
+			return nil, err
+		}
+
+		for _, kv := range resp.Kvs {
+			keys = append(keys, toKey(strings.TrimPrefix(string(kv.Key), prefix)))
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			return keys, nil
+		}
+		from = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+// RetryTask 重试任务，将 failed 状态的flow和task同时置为pending
+func (e *etcd) RetryTask(kt *kit.Kit, flowID, taskID string) error {
+
+	if len(flowID) == 0 || len(taskID) == 0 {
+		return errors.New("empty flow id or task id")
+	}
+
+	reason := &tableasync.Reason{Message: "retry task " + taskID}
+	flowKey := e.flowKey(flowID)
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	err := e.casUpdate(kt, []string{flowKey, e.taskKey(taskID)}, func(key string, val []byte) ([]byte, error) {
+		if key != flowKey {
+			task := new(model.Task)
+			if val == nil {
+				return nil, fmt.Errorf("task(%s) of flow(%s) not found", taskID, flowID)
+			}
+			if err := json.Unmarshal(val, task); err != nil {
+				return nil, err
+			}
+			if task.FlowID != flowID {
+				return nil, fmt.Errorf("task(%s) of flow(%s) not found", taskID, flowID)
+			}
+			if task.State != enumor.TaskFailed {
+				return nil, fmt.Errorf("task(%s) state(%s) wrong, only `failed` allowed for retry",
+					taskID, task.State)
+			}
+
+			return casTask(val, &UpdateTaskInfo{ID: taskID, Source: enumor.TaskFailed,
+				Target: enumor.TaskPending, Reason: reason}, now)
+		}
+
+		flow := new(model.Flow)
+		if val == nil {
+			return nil, fmt.Errorf("flow %s not found", flowID)
+		}
+		if err := json.Unmarshal(val, flow); err != nil {
+			return nil, err
+		}
+		if flow.State != enumor.FlowFailed {
+			return nil, fmt.Errorf("flow(%s) state(%s) wrong, only `failed` allowed for retry",
+				flowID, flow.State)
+		}
+
+		flow.State = enumor.FlowPending
+		flow.Reason = reason
+		flow.UpdatedAt = now
+		return json.Marshal(flow)
+	})
+	if err != nil {
+		logs.Errorf("fail to retry task, err: %v, flow id: %s, task id: %s, rid: %s", err, flowID, taskID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// CreateScheduledFlow 创建定时任务流，名称索引与定时任务流在同一事务中写入，名称重复时创建失败
+func (e *etcd) CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error) {
+
+	ids, err := e.genIDs(kt, table.AsyncScheduledFlowTable, 1)
+	if err != nil {
+		return "", err
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	md := &model.ScheduledFlow{
+		ID:            ids[0],
+		Name:          flow.Name,
+		Cron:          flow.Cron,
+		FlowName:      flow.FlowName,
+		Tasks:         flow.Tasks,
+		Memo:          flow.Memo,
+		MisfirePolicy: flow.MisfirePolicy,
+		Enable:        flow.Enable,
+		NextRunAt:     flow.NextRunAt,
+		Creator:       kt.User,
+		Reviser:       kt.User,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	val, err := json.Marshal(md)
+	if err != nil {
+		return "", err
+	}
+
+	nameKey := e.scheduledFlowNameKey(md.Name)
+	resp, err := e.cli.Txn(kt.Ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(nameKey), "=", 0)).
+		Then(clientv3.OpPut(nameKey, md.ID), clientv3.OpPut(e.scheduledFlowKey(md.ID), string(val))).
+		Commit()
+	if err != nil {
+		logs.Errorf("put async scheduled flow to etcd failed, err: %v, name: %s, rid: %s", err, md.Name, kt.Rid)
+		return "", err
+	}
+	if !resp.Succeeded {
+		return "", errf.Newf(errf.RecordDuplicated, "scheduled flow %s already exists", md.Name)
+	}
+
+	return md.ID, nil
+}
+
+// UpdateScheduledFlow 更新定时任务流，仅更新非空字段
+func (e *etcd) UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error {
+
+	if flow == nil || len(flow.ID) == 0 {
+		return errors.New("scheduled flow id is required")
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	return e.casUpdate(kt, []string{e.scheduledFlowKey(flow.ID)}, func(key string, val []byte) ([]byte, error) {
+		if val == nil {
+			return nil, errf.Newf(errf.RecordNotFound, "scheduled flow %s not found", flow.ID)
+		}
+
+		md := new(model.ScheduledFlow)
+		if err := json.Unmarshal(val, md); err != nil {
+			return nil, err
+		}
+
+		if len(flow.Cron) != 0 {
+			md.Cron = flow.Cron
+		}
+		if flow.Tasks != nil {
+			md.Tasks = flow.Tasks
+		}
+		if len(flow.Memo) != 0 {
+			md.Memo = flow.Memo
+		}
+		if len(flow.MisfirePolicy) != 0 {
+			md.MisfirePolicy = flow.MisfirePolicy
+		}
+		if flow.Enable != nil {
+			md.Enable = flow.Enable
+		}
+		if len(flow.LastFlowID) != 0 {
+			md.LastFlowID = flow.LastFlowID
+		}
+		if len(flow.LastRunAt) != 0 {
+			md.LastRunAt = flow.LastRunAt
+		}
+		if len(flow.NextRunAt) != 0 {
+			md.NextRunAt = flow.NextRunAt
+		}
+		md.Reviser = kt.User
+		md.UpdatedAt = now
+
+		return json.Marshal(md)
+	})
+}
+
+// UpdateScheduledFlowNextRunByCAS CAS更新定时任务流下一次触发时间
+func (e *etcd) UpdateScheduledFlowNextRunByCAS(kt *kit.Kit, info *UpdateScheduledFlowNextRunInfo) error {
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	return e.casUpdate(kt, []string{e.scheduledFlowKey(info.ID)}, func(key string, val []byte) ([]byte, error) {
+		md := new(model.ScheduledFlow)
+		if val != nil {
+			if err := json.Unmarshal(val, md); err != nil {
+				return nil, err
+			}
+		}
+
+		if val == nil || md.NextRunAt != info.Source {
+			return nil, errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] update next run at: `%s`->`%s` failed",
+				info.ID, info.Source, info.Target)
+		}
+
+		md.NextRunAt = info.Target
+		md.Reviser = kt.User
+		md.UpdatedAt = now
+
+		return json.Marshal(md)
+	})
+}
+
+// ListScheduledFlow 查询定时任务流
+func (e *etcd) ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error) {
+
+	if err := validateListInput(input); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.ScheduledFlow, 0), nil
+	}
+
+	matcher := newPageMatcher[model.ScheduledFlow](input)
+	if err := e.scanScheduledFlow(kt, input.Filter, matcher); err != nil {
+		return nil, err
+	}
+
+	return matcher.result(), nil
+}
+
+// CountScheduledFlow 查询定时任务流数量
+func (e *etcd) CountScheduledFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	matcher := newCountMatcher[model.ScheduledFlow](expr)
+	if err := e.scanScheduledFlow(kt, expr, matcher); err != nil {
+		return 0, err
+	}
+
+	return matcher.count, nil
+}
+
+func (e *etcd) scanScheduledFlow(kt *kit.Kit, expr *filter.Expression,
+	matcher *pageMatcher[model.ScheduledFlow]) error {
+
+	if ids, ok := pinnedIDs(expr); ok {
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, e.scheduledFlowKey(id))
+		}
+		return scanEtcdKeys(kt, e.cli, keys, matcher)
+	}
+
+	return scanEtcdPrefix(kt, e.cli, e.scheduledFlowPrefix(), matcher)
+}
+
+// DeleteScheduledFlow 删除定时任务流及其名称索引
+func (e *etcd) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+
+	if len(id) == 0 {
+		return errors.New("scheduled flow id is required")
+	}
+
+	key := e.scheduledFlowKey(id)
+	resp, err := e.cli.Get(kt.Ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil
+	}
+
+	md := new(model.ScheduledFlow)
+	if err = json.Unmarshal(resp.Kvs[0].Value, md); err != nil {
+		return err
+	}
+
+	txnResp, err := e.cli.Txn(kt.Ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+		Then(clientv3.OpDelete(key), clientv3.OpDelete(e.scheduledFlowNameKey(md.Name))).
+		Commit()
+	if err != nil {
+		logs.Errorf("delete async scheduled flow from etcd failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+	if !txnResp.Succeeded {
+		return errf.Newf(errf.RecordNotUpdate, "scheduled flow %s concurrently modified", id)
+	}
+
+	return nil
+}
+
+// casUpdate 读取keys当前的值，经 mutate 计算后以各key的ModRevision作为比较条件写回，
+// 期间有并发修改时重新读取并重试，flow、task的状态发生变化时在同一事务中迁移其状态索引。
+// 超过单个事务操作数上限时按批次更新，批次之间不保证原子性。
+func (e *etcd) casUpdate(kt *kit.Kit, keys []string,
+	mutate func(key string, val []byte) ([]byte, error)) error {
+
+	for _, part := range slice.Split(keys, etcdCASBatchSize) {
+		if err := e.casUpdatePart(kt, part, mutate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *etcd) casUpdatePart(kt *kit.Kit, keys []string,
+	mutate func(key string, val []byte) ([]byte, error)) error {
+
+	for retry := 0; retry < etcdCASRetryCount; retry++ {
+		gets := make([]clientv3.Op, 0, len(keys))
+		for _, key := range keys {
+			gets = append(gets, clientv3.OpGet(key))
+		}
+		getResp, err := e.cli.Txn(kt.Ctx).Then(gets...).Commit()
+		if err != nil {
+			logs.Errorf("get keys from etcd failed, err: %v, keys: %v, rid: %s", err, keys, kt.Rid)
+			return err
+		}
+
+		cmps := make([]clientv3.Cmp, 0, len(keys))
+		puts := make([]clientv3.Op, 0, 3*len(keys))
+		for idx, key := range keys {
+			var val []byte
+			var modRevision int64
+			if kvs := getResp.Responses[idx].GetResponseRange().Kvs; len(kvs) != 0 {
+				val = kvs[0].Value
+				modRevision = kvs[0].ModRevision
+			}
+
+			newVal, err := mutate(key, val)
+			if err != nil {
+				return err
+			}
+
+			indexOps, err := e.stateIndexOps(key, val, newVal)
+			if err != nil {
+				return err
+			}
+
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(key), "=", modRevision))
+			puts = append(puts, clientv3.OpPut(key, string(newVal)))
+			puts = append(puts, indexOps...)
+		}
+
+		resp, err := e.cli.Txn(kt.Ctx).If(cmps...).Then(puts...).Commit()
+		if err != nil {
+			logs.Errorf("cas update etcd keys failed, err: %v, keys: %v, rid: %s", err, keys, kt.Rid)
+			return err
+		}
+		if resp.Succeeded {
+			return nil
+		}
+	}
+
+	return errf.Newf(errf.RecordNotUpdate, "keys %v concurrently modified, retried %d times", keys,
+		etcdCASRetryCount)
+}
+
+// stateIndexOps 比较flow、task更新前后的状态，状态变化时返回删除旧状态索引、写入新状态索引的操作。
+func (e *etcd) stateIndexOps(key string, oldVal, newVal []byte) ([]clientv3.Op, error) {
+	var stateKey func(state, id string) string
+	var id string
+	switch {
+	case strings.HasPrefix(key, e.flowPrefix()):
+		id = strings.TrimPrefix(key, e.flowPrefix())
+		stateKey = func(state, id string) string { return e.flowStateKey(enumor.FlowState(state), id) }
+	case strings.HasPrefix(key, e.taskPrefix()):
+		id = strings.TrimPrefix(key, e.taskPrefix())
+		stateKey = func(state, id string) string { return e.taskStateKey(enumor.TaskState(state), id) }
+	default:
+		return nil, nil
+	}
+
+	oldState, err := parseState(oldVal)
+	if err != nil {
+		return nil, err
+	}
+	newState, err := parseState(newVal)
+	if err != nil {
+		return nil, err
+	}
+	if oldState == newState {
+		return nil, nil
+	}
+
+	ops := make([]clientv3.Op, 0, 2)
+	if len(oldState) != 0 {
+		ops = append(ops, clientv3.OpDelete(stateKey(oldState, id)))
+	}
+	if len(newState) != 0 {
+		ops = append(ops, clientv3.OpPut(stateKey(newState, id), ""))
+	}
+
+	return ops, nil
+}
+
+func parseState(val []byte) (string, error) {
+	if val == nil {
+		return "", nil
+	}
+
+	md := new(struct {
+		State string `json:"state"`
+	})
+	if err := json.Unmarshal(val, md); err != nil {
+		return "", err
+	}
+
+	return md.State, nil
+}
+
+// genIDs 生成与 id_generator 表格式一致的id，当前最大id以36进制存储在etcd中，通过CAS递增。
+func (e *etcd) genIDs(kt *kit.Kit, resource table.Name, count int) ([]string, error) {
+	key := e.idGeneratorKey(resource)
+
+	for retry := 0; retry < etcdCASRetryCount; retry++ {
+		resp, err := e.cli.Get(kt.Ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("gen %s unique id, but get max id failed, err: %v", resource, err)
+		}
+
+		var maxID uint64
+		var modRevision int64
+		if len(resp.Kvs) != 0 {
+			maxID, err = strconv.ParseUint(string(resp.Kvs[0].Value), 36, 64)
+			if err != nil {
+				return nil, fmt.Errorf("gen %s unique id, but parse max id failed, err: %v", resource, err)
+			}
+			modRevision = resp.Kvs[0].ModRevision
+		}
+
+		newMaxID := fmt.Sprintf("%08s", strconv.FormatUint(maxID+uint64(count), 36))
+		txnResp, err := e.cli.Txn(kt.Ctx).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+			Then(clientv3.OpPut(key, newMaxID)).
+			Commit()
+		if err != nil {
+			return nil, fmt.Errorf("gen %s unique id, but update max id failed, err: %v", resource, err)
+		}
+		if !txnResp.Succeeded {
+			continue
+		}
+
+		ids := make([]string, count)
+		for idx := 0; idx < count; idx++ {
+			ids[idx] = fmt.Sprintf("%08s", strconv.FormatUint(maxID+uint64(idx+1), 36))
+		}
+		return ids, nil
+	}
+
+	return nil, fmt.Errorf("gen %s unique id, but max id concurrently modified", resource)
+}
+
+// scanEtcdKeys 读取指定的keys，按key(即id)排序后逐条交给matcher匹配。
+func scanEtcdKeys[T any](kt *kit.Kit, cli *clientv3.Client, keys []string, matcher *pageMatcher[T]) error {
+
+	keys = slice.Unique(keys)
+	values := make(map[string][]byte, len(keys))
+	for _, part := range slice.Split(keys, etcdMaxTxnOps) {
+		gets := make([]clientv3.Op, 0, len(part))
+		for _, key := range part {
+			gets = append(gets, clientv3.OpGet(key))
+		}
+		resp, err := cli.Txn(kt.Ctx).Then(gets...).Commit()
+		if err != nil {
+			logs.Errorf("get keys from etcd failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		for idx, key := range part {
+			if kvs := resp.Responses[idx].GetResponseRange().Kvs; len(kvs) != 0 {
+				values[key] = kvs[0].Value
+			}
+		}
+	}
+
+	// etcd 的范围查询按key有序返回，这里保持一致
+	sortedKeys := make([]string, 0, len(values))
+	for key := range values {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		if done, err := matcher.add(values[key]); err != nil || done {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanEtcdPrefix 按批次扫描前缀下的全部key，逐条交给matcher匹配，直到matcher取满当前页。
+func scanEtcdPrefix[T any](kt *kit.Kit, cli *clientv3.Client, prefix string, matcher *pageMatcher[T]) error {
+
+	end := clientv3.GetPrefixRangeEnd(prefix)
+	from := prefix
+	var rev int64
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(etcdScanLimit)}
+		if rev != 0 {
+			// 多批次读取同一版本，保证结果是一致的快照
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := cli.Get(kt.Ctx, from, opts...)
+		if err != nil {
+			logs.Errorf("scan etcd prefix failed, err: %v, prefix: %s, rid: %s", err, prefix, kt.Rid)
+			return err
+		}
+		rev = resp.Header.Revision
+
+		for _, kv := range resp.Kvs {
+			if done, err := matcher.add(kv.Value); err != nil || done {
+				return err
+			}
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			return nil
+		}
+		from = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+func validateListInput(input *ListInput) error {
+	if input == nil {
+		return errors.New("list input is required")
+	}
+
+	if input.Page == nil {
+		return errors.New("page is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/json"
+)
+
+// pageMatcher 在内存中按过滤条件及分页参数筛选etcd中读取到的记录，记录需按id顺序添加。
+// 按id升序查询时流式取满当前页即结束，其余排序方式需收集全部匹配记录后在 result 中排序分页。
+type pageMatcher[T any] struct {
+	expr      *filter.Expression
+	skip      uint32
+	limit     uint
+	sortField string
+	desc      bool
+	// countOnly 仅统计匹配数量，不保存记录
+	countOnly bool
+	count     uint64
+	sorted    []sortItem[T]
+	matched   []T
+}
+
+type sortItem[T any] struct {
+	key  interface{}
+	item T
+}
+
+func newPageMatcher[T any](input *ListInput) *pageMatcher[T] {
+	m := &pageMatcher[T]{
+		expr:    input.Filter,
+		skip:    input.Page.Start,
+		limit:   input.Page.Limit,
+		desc:    input.Page.Order == core.Descending,
+		matched: make([]T, 0),
+	}
+	if input.Page.Sort != "" && input.Page.Sort != "id" {
+		m.sortField = input.Page.Sort
+	}
+
+	return m
+}
+
+func newCountMatcher[T any](expr *filter.Expression) *pageMatcher[T] {
+	return &pageMatcher[T]{
+		expr:      expr,
+		countOnly: true,
+		matched:   make([]T, 0),
+	}
+}
+
+// needSort 是否需要收集全部匹配记录后再排序
+func (m *pageMatcher[T]) needSort() bool {
+	return m.sortField != "" || m.desc
+}
+
+// add 解析并匹配一条记录，返回是否已经取满当前页。
+func (m *pageMatcher[T]) add(val []byte) (bool, error) {
+	item := new(T)
+	if err := json.Unmarshal(val, item); err != nil {
+		return false, err
+	}
+
+	record, err := toRecord(item)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := matchRule(m.expr, record)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if m.countOnly {
+		m.count++
+		return false, nil
+	}
+
+	if m.needSort() {
+		var key interface{}
+		if m.sortField != "" {
+			key = lookupField(record, m.sortField)
+		}
+		m.sorted = append(m.sorted, sortItem[T]{key: key, item: *item})
+		return false, nil
+	}
+
+	if m.skip > 0 {
+		m.skip--
+		return false, nil
+	}
+
+	m.matched = append(m.matched, *item)
+	return m.limit != 0 && uint(len(m.matched)) >= m.limit, nil
+}
+
+// result 返回当前页的记录，需要排序时按排序字段排序(相同时保持id顺序)后分页。
+func (m *pageMatcher[T]) result() []T {
+	if !m.needSort() {
+		return m.matched
+	}
+
+	// 记录按id升序添加，降序时先整体反转，再稳定排序，保证排序字段相同时按id降序
+	if m.desc {
+		for i, j := 0, len(m.sorted)-1; i < j; i, j = i+1, j-1 {
+			m.sorted[i], m.sorted[j] = m.sorted[j], m.sorted[i]
+		}
+	}
+	if m.sortField != "" {
+		sort.SliceStable(m.sorted, func(i, j int) bool {
+			cmp := compareSortKey(m.sorted[i].key, m.sorted[j].key)
+			if m.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+	}
+
+	start := uint(m.skip)
+	if start >= uint(len(m.sorted)) {
+		return m.matched
+	}
+	end := uint(len(m.sorted))
+	if m.limit != 0 && start+m.limit < end {
+		end = start + m.limit
+	}
+	for _, one := range m.sorted[start:end] {
+		m.matched = append(m.matched, one.item)
+	}
+
+	return m.matched
+}
+
+// compareSortKey 比较排序字段值，与mysql保持一致，空值最小，无法比较的类型视为相等
+func compareSortKey(a, b interface{}) int {
+	a, b = normalizeValue(a), normalizeValue(b)
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	cmp, _ := compareValue(a, b)
+	return cmp
+}
+
+// toRecord 将记录转为以json字段名(与db列名一致)为key的map，用于过滤条件匹配。
+func toRecord(item interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]interface{})
+	if err = json.Unmarshal(raw, &record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// matchRule 判断记录是否满足过滤条件，仅支持异步任务框架用到的非json类操作符。
+func matchRule(rule filter.RuleFactory, record map[string]interface{}) (bool, error) {
+	switch r := rule.(type) {
+	case *filter.Expression:
+		if r == nil {
+			return true, nil
+		}
+		return matchExpression(r, record)
+	case *filter.AtomRule:
+		if r == nil {
+			return true, nil
+		}
+		return matchAtomRule(r, record)
+	case filter.AtomRule:
+		return matchAtomRule(&r, record)
+	case nil:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported filter rule type: %T", rule)
+	}
+}
+
+func matchExpression(expr *filter.Expression, record map[string]interface{}) (bool, error) {
+	if expr == nil || len(expr.Rules) == 0 {
+		return true, nil
+	}
+
+	for _, one := range expr.Rules {
+		ok, err := matchRule(one, record)
+		if err != nil {
+			return false, err
+		}
+
+		switch expr.Op {
+		case filter.And:
+			if !ok {
+				return false, nil
+			}
+		case filter.Or:
+			if ok {
+				return true, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported filter logic operator: %s", expr.Op)
+		}
+	}
+
+	return expr.Op == filter.And, nil
+}
+
+func matchAtomRule(rule *filter.AtomRule, record map[string]interface{}) (bool, error) {
+	val := lookupField(record, rule.Field)
+
+	switch op := filter.OpType(rule.Op); op {
+	case filter.Equal:
+		return equalValue(val, rule.Value), nil
+	case filter.NotEqual:
+		return !equalValue(val, rule.Value), nil
+	case filter.In, filter.NotIn:
+		values, err := toValueSlice(rule.Value)
+		if err != nil {
+			return false, err
+		}
+		in := false
+		for _, one := range values {
+			if equalValue(val, one) {
+				in = true
+				break
+			}
+		}
+		return in == (op == filter.In), nil
+	case filter.GreaterThan, filter.IDGreaterThan, filter.GreaterThanEqual, filter.LessThan,
+		filter.LessThanEqual:
+		cmp, ok := compareValue(val, rule.Value)
+		if !ok {
+			return false, nil
+		}
+		switch op {
+		case filter.GreaterThan, filter.IDGreaterThan:
+			return cmp > 0, nil
+		case filter.GreaterThanEqual:
+			return cmp >= 0, nil
+		case filter.LessThan:
+			return cmp < 0, nil
+		default:
+			return cmp <= 0, nil
+		}
+	case filter.ContainsSensitive:
+		str, ok := val.(string)
+		return ok && strings.Contains(str, fmt.Sprint(normalizeValue(rule.Value))), nil
+	case filter.ContainsInsensitive:
+		str, ok := val.(string)
+		return ok && strings.Contains(strings.ToLower(str),
+			strings.ToLower(fmt.Sprint(normalizeValue(rule.Value)))), nil
+	default:
+		return false, fmt.Errorf("filter operator %s is not supported by etcd backend", op)
+	}
+}
+
+// lookupField 获取字段值，支持以 . 分隔的嵌套字段
+func lookupField(record map[string]interface{}, field string) interface{} {
+	var cur interface{} = record
+	for _, name := range strings.Split(field, filter.JSONFieldSeparator) {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = obj[name]
+	}
+
+	return cur
+}
+
+// normalizeValue 将过滤条件中的值转为与json解析结果相同的类型，字符串别名转为string，数值转为float64
+func normalizeValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Invalid:
+		return nil
+	default:
+		return rv.Interface()
+	}
+}
+
+func equalValue(val, target interface{}) bool {
+	return reflect.DeepEqual(normalizeValue(val), normalizeValue(target))
+}
+
+func compareValue(val, target interface{}) (int, bool) {
+	switch v := normalizeValue(val).(type) {
+	case string:
+		t, ok := normalizeValue(target).(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(v, t), true
+	case float64:
+		t, ok := normalizeValue(target).(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case v > t:
+			return 1, true
+		case v < t:
+			return -1, true
+		default:
+			return 0, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+func toValueSlice(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("in/nin filter value should be an array, but got %T", v)
+	}
+
+	values := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, rv.Index(i).Interface())
+	}
+
+	return values, nil
+}
+
+// pinnedIDs 当过滤条件为and且包含 id 的 eq/in 规则时返回这些id，用于直接按key读取，避免全量扫描。
+func pinnedIDs(expr *filter.Expression) ([]string, bool) {
+	return pinnedValues(expr, "id")
+}
+
+// pinnedStates 当过滤条件为and且包含 state 的 eq/in 规则时返回这些状态，用于通过状态索引读取记录。
+func pinnedStates(expr *filter.Expression) ([]string, bool) {
+	return pinnedValues(expr, "state")
+}
+
+// pinnedFlowID 当过滤条件为and且包含 flow_id 的 eq 规则时返回该flow_id，用于通过索引读取任务。
+func pinnedFlowID(expr *filter.Expression) (string, bool) {
+	values, ok := pinnedValues(expr, "flow_id")
+	if !ok || len(values) != 1 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+func pinnedValues(expr *filter.Expression, field string) ([]string, bool) {
+	if expr == nil || (expr.Op != filter.And && len(expr.Rules) != 1) {
+		return nil, false
+	}
+
+	for _, one := range expr.Rules {
+		var rule *filter.AtomRule
+		switch r := one.(type) {
+		case *filter.AtomRule:
+			rule = r
+		case filter.AtomRule:
+			rule = &r
+		}
+		if rule == nil || rule.Field != field {
+			continue
+		}
+
+		switch filter.OpType(rule.Op) {
+		case filter.Equal:
+			str, ok := normalizeValue(rule.Value).(string)
+			if !ok {
+				continue
+			}
+			return []string{str}, true
+		case filter.In:
+			values, err := toValueSlice(rule.Value)
+			if err != nil {
+				continue
+			}
+			result := make([]string, 0, len(values))
+			for _, v := range values {
+				str, ok := normalizeValue(v).(string)
+				if !ok {
+					return nil, false
+				}
+				result = append(result, str)
+			}
+			return result, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"testing"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageMatcher(t *testing.T) {
+	flows := []model.Flow{
+		{ID: "00000001", State: enumor.FlowPending, Worker: converter.ValToPtr("")},
+		{ID: "00000002", State: enumor.FlowRunning, Worker: converter.ValToPtr("node-1")},
+		{ID: "00000003", State: enumor.FlowRunning, Worker: converter.ValToPtr("node-2")},
+		{ID: "00000004", State: enumor.FlowPending, Worker: converter.ValToPtr("")},
+		{ID: "00000005", State: enumor.FlowRunning, Worker: converter.ValToPtr("node-3")},
+	}
+
+	match := func(expr *filter.Expression, page *core.BasePage) []string {
+		matcher := newPageMatcher[model.Flow](&ListInput{Filter: expr, Page: page})
+		for _, one := range flows {
+			raw, err := json.Marshal(one)
+			require.NoError(t, err)
+			done, err := matcher.add(raw)
+			require.NoError(t, err)
+			if done {
+				break
+			}
+		}
+
+		result := matcher.result()
+		ids := make([]string, 0, len(result))
+		for _, one := range result {
+			ids = append(ids, one.ID)
+		}
+		return ids
+	}
+
+	dispatch := tools.ExpressionAnd(tools.RuleEqual("worker", ""), tools.RuleEqual("state", enumor.FlowPending))
+	assert.Equal(t, []string{"00000001", "00000004"}, match(dispatch, core.NewDefaultBasePage()))
+
+	notExistWorker := tools.ExpressionAnd(tools.RuleEqual("state", enumor.FlowRunning),
+		tools.RuleNotIn("worker", []string{"node-1", "node-3"}))
+	assert.Equal(t, []string{"00000003"}, match(notExistWorker, core.NewDefaultBasePage()))
+
+	byIDs := tools.ContainersExpression("id", []string{"00000002", "00000005"})
+	assert.Equal(t, []string{"00000002", "00000005"}, match(byIDs, core.NewDefaultBasePage()))
+
+	or := &filter.Expression{
+		Op: filter.Or,
+		Rules: []filter.RuleFactory{
+			tools.RuleEqual("id", "00000001"),
+			&filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{
+				tools.RuleEqual("state", enumor.FlowRunning),
+				&filter.AtomRule{Field: "id", Op: filter.GreaterThan.Factory(), Value: "00000004"},
+			}},
+		},
+	}
+	assert.Equal(t, []string{"00000001", "00000005"}, match(or, core.NewDefaultBasePage()))
+
+	// 分页
+	assert.Equal(t, []string{"00000002", "00000003"}, match(nil, &core.BasePage{Start: 1, Limit: 2}))
+
+	// 排序，排序字段相同时按id排序
+	assert.Equal(t, []string{"00000004", "00000003"},
+		match(nil, &core.BasePage{Start: 1, Limit: 2, Order: core.Descending}))
+	assert.Equal(t, []string{"00000001", "00000004", "00000002"},
+		match(nil, &core.BasePage{Limit: 3, Sort: "worker"}))
+	assert.Equal(t, []string{"00000005", "00000003", "00000002"},
+		match(tools.EqualExpression("state", enumor.FlowRunning),
+			&core.BasePage{Limit: 3, Sort: "worker", Order: core.Descending}))
+
+	// 计数
+	counter := newCountMatcher[model.Flow](tools.EqualExpression("state", enumor.FlowRunning))
+	for _, one := range flows {
+		raw, err := json.Marshal(one)
+		require.NoError(t, err)
+		_, err = counter.add(raw)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, uint64(3), counter.count)
+}
+
+func TestPinnedValues(t *testing.T) {
+	ids, ok := pinnedIDs(tools.EqualExpression("id", "00000001"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"00000001"}, ids)
+
+	ids, ok = pinnedIDs(tools.ExpressionAnd(tools.RuleEqual("state", enumor.TaskPending),
+		tools.RuleIn("id", []string{"00000001", "00000002"})))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"00000001", "00000002"}, ids)
+
+	_, ok = pinnedIDs(&filter.Expression{Op: filter.Or, Rules: []filter.RuleFactory{
+		tools.RuleEqual("id", "00000001"), tools.RuleEqual("state", enumor.TaskPending)}})
+	assert.False(t, ok)
+
+	flowID, ok := pinnedFlowID(tools.EqualExpression("flow_id", "00000003"))
+	assert.True(t, ok)
+	assert.Equal(t, "00000003", flowID)
+
+	_, ok = pinnedFlowID(tools.ContainersExpression("flow_id", []string{"00000001", "00000002"}))
+	assert.False(t, ok)
+}
@@ -0,0 +1,162 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Sweep 清理更新时间早于保留时长的终态任务流，连同其任务、flow_id索引及状态索引一起删除。
+// 两次清理的间隔不小于 etcdSweepInterval，未配置保留时长时不清理。
+func (e *etcd) Sweep(kt *kit.Kit) error {
+	if e.retention <= 0 {
+		return nil
+	}
+
+	e.sweepLock.Lock()
+	defer e.sweepLock.Unlock()
+
+	now := time.Now()
+	if now.Sub(e.lastSweepAt) < etcdSweepInterval {
+		return nil
+	}
+
+	before := now.Add(-e.retention)
+	for _, state := range etcdSweepStates {
+		flowKeys, err := e.listIndexedKeys(kt, e.flowStatePrefix(state), e.flowKey)
+		if err != nil {
+			return err
+		}
+
+		for _, part := range slice.Split(flowKeys, etcdMaxTxnOps) {
+			if err = e.sweepFlows(kt, part, before); err != nil {
+				return err
+			}
+		}
+	}
+	e.lastSweepAt = now
+
+	return nil
+}
+
+// sweepFlows 删除一批任务流中已过期的部分，单个任务流删除失败时记录日志后继续。
+func (e *etcd) sweepFlows(kt *kit.Kit, flowKeys []string, before time.Time) error {
+	gets := make([]clientv3.Op, 0, len(flowKeys))
+	for _, key := range flowKeys {
+		gets = append(gets, clientv3.OpGet(key))
+	}
+	resp, err := e.cli.Txn(kt.Ctx).Then(gets...).Commit()
+	if err != nil {
+		logs.Errorf("get flows to sweep from etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	for idx := range flowKeys {
+		kvs := resp.Responses[idx].GetResponseRange().Kvs
+		if len(kvs) == 0 {
+			continue
+		}
+
+		flow := new(model.Flow)
+		if err = json.Unmarshal(kvs[0].Value, flow); err != nil {
+			return err
+		}
+
+		updatedAt, err := time.Parse(constant.TimeStdFormat, flow.UpdatedAt)
+		if err != nil {
+			logs.Errorf("parse flow updated at failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+			continue
+		}
+		if !updatedAt.Before(before) {
+			continue
+		}
+
+		if err = e.deleteFlow(kt, flow, kvs[0].ModRevision); err != nil {
+			logs.Errorf("sweep expired flow failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+// deleteFlow 以任务流的ModRevision作为比较条件，分批删除其任务及索引，最后一批删除任务流本身。
+// 任务流被并发修改(如重试)时停止删除，已删除的任务不会恢复。
+func (e *etcd) deleteFlow(kt *kit.Kit, flow *model.Flow, modRevision int64) error {
+	taskPrefix := e.flowTaskPrefix(flow.ID)
+	taskKeys, err := e.listIndexedKeys(kt, taskPrefix, e.taskKey)
+	if err != nil {
+		return err
+	}
+
+	ops := make([]clientv3.Op, 0, 3*len(taskKeys)+2)
+	for _, part := range slice.Split(taskKeys, etcdMaxTxnOps) {
+		gets := make([]clientv3.Op, 0, len(part))
+		for _, key := range part {
+			gets = append(gets, clientv3.OpGet(key))
+		}
+		resp, err := e.cli.Txn(kt.Ctx).Then(gets...).Commit()
+		if err != nil {
+			return err
+		}
+
+		for idx, key := range part {
+			taskID := strings.TrimPrefix(key, e.taskPrefix())
+			ops = append(ops, clientv3.OpDelete(key), clientv3.OpDelete(taskPrefix+taskID))
+
+			kvs := resp.Responses[idx].GetResponseRange().Kvs
+			if len(kvs) == 0 {
+				continue
+			}
+			task := new(model.Task)
+			if err = json.Unmarshal(kvs[0].Value, task); err != nil {
+				return err
+			}
+			ops = append(ops, clientv3.OpDelete(e.taskStateKey(task.State, taskID)))
+		}
+	}
+	ops = append(ops, clientv3.OpDelete(e.flowStateKey(flow.State, flow.ID)), clientv3.OpDelete(e.flowKey(flow.ID)))
+
+	flowKey := e.flowKey(flow.ID)
+	for _, part := range slice.Split(ops, etcdMaxTxnOps) {
+		resp, err := e.cli.Txn(kt.Ctx).
+			If(clientv3.Compare(clientv3.ModRevision(flowKey), "=", modRevision)).
+			Then(part...).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if !resp.Succeeded {
+			return fmt.Errorf("flow %s concurrently modified", flow.ID)
+		}
+	}
+
+	return nil
+}
@@ -31,6 +31,12 @@ type Flow struct {
 	Name      enumor.FlowName       `json:"name"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Memo      string                `json:"memo"`
+	// RollbackMode 任务流失败后的回滚模式，为空时等同于 none
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode"`
+	// Priority 任务流派发优先级，为空时等同于 normal
+	Priority enumor.FlowPriority `json:"priority"`
+	// ConcurrencyKey 并发控制键，如 account:00000001，为空时不做并发控制
+	ConcurrencyKey string `json:"concurrency_key"`
 
 	ID        string             `json:"id"`
 	State     enumor.FlowState   `json:"state"`
@@ -63,6 +69,18 @@ func (f Flow) CreateValidate() error {
 		return errors.New("reason can not set")
 	}
 
+	if len(f.RollbackMode) != 0 {
+		if err := f.RollbackMode.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if f.Priority != 0 {
+		if err := f.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if len(f.Creator) == 0 {
 		return errors.New("creator is required")
 	}
@@ -82,6 +100,11 @@ func (f Flow) CreateValidate() error {
 	return nil
 }
 
+// IsCompensate 任务流失败后是否需要对已成功的任务进行补偿回滚
+func (f Flow) IsCompensate() bool {
+	return f.RollbackMode == enumor.FlowRollbackCompensate
+}
+
 // UpdateValidate Flow.
 func (f Flow) UpdateValidate() error {
 
@@ -0,0 +1,47 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// ScheduledFlow 定时任务流，按cron表达式周期性地基于任务流模版生成任务流
+type ScheduledFlow struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Cron 标准5段式cron表达式
+	Cron string `json:"cron"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name"`
+	// Tasks 任务流模版中任务的执行参数
+	Tasks         tableasync.ScheduledFlowTasks `json:"tasks"`
+	Memo          string                        `json:"memo"`
+	MisfirePolicy enumor.MisfirePolicy          `json:"misfire_policy"`
+	Enable        *bool                         `json:"enable"`
+	LastFlowID    string                        `json:"last_flow_id"`
+	LastRunAt     string                        `json:"last_run_at"`
+	NextRunAt     string                        `json:"next_run_at"`
+	Creator       string                        `json:"creator"`
+	Reviser       string                        `json:"reviser"`
+	CreatedAt     string                        `json:"created_at"`
+	UpdatedAt     string                        `json:"updated_at"`
+}
@@ -37,6 +37,7 @@ import (
 	tabletypes "hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/converter"
 
 	"github.com/jmoiron/sqlx"
@@ -195,17 +196,30 @@ func (db *mysql) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
 		flowState = flow.State
 	}
 
+	rollbackMode := flow.RollbackMode
+	if len(rollbackMode) == 0 {
+		rollbackMode = enumor.FlowRollbackNone
+	}
+
+	priority := flow.Priority
+	if priority == 0 {
+		priority = enumor.FlowPriorityNormal
+	}
+
 	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		// 创建任务流
 		md := &tableasync.AsyncFlowTable{
-			Name:      flow.Name,
-			State:     flowState,
-			Reason:    new(tableasync.Reason),
-			ShareData: flow.ShareData,
-			Memo:      flow.Memo,
-			Worker:    converter.ValToPtr(""),
-			Creator:   kt.User,
-			Reviser:   kt.User,
+			Name:           flow.Name,
+			State:          flowState,
+			Reason:         new(tableasync.Reason),
+			ShareData:      flow.ShareData,
+			Memo:           flow.Memo,
+			Worker:         converter.ValToPtr(""),
+			RollbackMode:   rollbackMode,
+			Priority:       priority,
+			ConcurrencyKey: flow.ConcurrencyKey,
+			Creator:        kt.User,
+			Reviser:        kt.User,
 		}
 		flowID, err := db.dao.AsyncFlow().Create(kt, txn, md)
 		if err != nil {
@@ -297,23 +311,40 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 	flows := make([]model.Flow, 0, len(list.Details))
 	for _, one := range list.Details {
 		flows = append(flows, model.Flow{
-			ID:        one.ID,
-			Name:      one.Name,
-			State:     one.State,
-			Reason:    one.Reason,
-			ShareData: one.ShareData,
-			Memo:      one.Memo,
-			Worker:    one.Worker,
-			Creator:   one.Creator,
-			Reviser:   one.Reviser,
-			CreatedAt: one.CreatedAt.String(),
-			UpdatedAt: one.UpdatedAt.String(),
+			ID:             one.ID,
+			Name:           one.Name,
+			State:          one.State,
+			Reason:         one.Reason,
+			ShareData:      one.ShareData,
+			Memo:           one.Memo,
+			Worker:         one.Worker,
+			RollbackMode:   one.RollbackMode,
+			Priority:       one.Priority,
+			ConcurrencyKey: one.ConcurrencyKey,
+			Creator:        one.Creator,
+			Reviser:        one.Reviser,
+			CreatedAt:      one.CreatedAt.String(),
+			UpdatedAt:      one.UpdatedAt.String(),
 		})
 	}
 
 	return flows, nil
 }
 
+// CountFlow 查询任务流数量
+func (db *mysql) CountFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	opt := &types.ListOption{
+		Filter: expr,
+		Page:   core.NewCountPage(),
+	}
+	list, err := db.dao.AsyncFlow().List(kt, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return list.Count, nil
+}
+
 // BatchCreateTask 批量创建任务
 func (db *mysql) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
 
@@ -388,6 +419,20 @@ func (db *mysql) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
 	return tasks, nil
 }
 
+// CountTask 查询任务数量
+func (db *mysql) CountTask(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	opt := &types.ListOption{
+		Filter: expr,
+		Page:   core.NewCountPage(),
+	}
+	list, err := db.dao.AsyncFlowTask().List(kt, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return list.Count, nil
+}
+
 func dependOnToStringArray(d []action.ActIDType) tabletypes.StringArray {
 	result := make(tabletypes.StringArray, 0, len(d))
 	for _, one := range d {
@@ -405,3 +450,98 @@ func dependOnToActIDArray(d tabletypes.StringArray) []action.ActIDType {
 
 	return result
 }
+
+// CreateScheduledFlow 创建定时任务流
+func (db *mysql) CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error) {
+	md := &tableasync.AsyncScheduledFlowTable{
+		Name:          flow.Name,
+		Cron:          flow.Cron,
+		FlowName:      flow.FlowName,
+		Tasks:         flow.Tasks,
+		Memo:          flow.Memo,
+		MisfirePolicy: flow.MisfirePolicy,
+		Enable:        flow.Enable,
+		NextRunAt:     flow.NextRunAt,
+		Creator:       kt.User,
+		Reviser:       kt.User,
+	}
+
+	return db.dao.AsyncScheduledFlow().Create(kt, md)
+}
+
+// UpdateScheduledFlow 更新定时任务流
+func (db *mysql) UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error {
+	md := &tableasync.AsyncScheduledFlowTable{
+		Cron:          flow.Cron,
+		Tasks:         flow.Tasks,
+		Memo:          flow.Memo,
+		MisfirePolicy: flow.MisfirePolicy,
+		Enable:        flow.Enable,
+		LastFlowID:    flow.LastFlowID,
+		LastRunAt:     flow.LastRunAt,
+		NextRunAt:     flow.NextRunAt,
+		Reviser:       kt.User,
+	}
+
+	return db.dao.AsyncScheduledFlow().UpdateByID(kt, flow.ID, md)
+}
+
+// UpdateScheduledFlowNextRunByCAS CAS更新定时任务流下一次触发时间
+func (db *mysql) UpdateScheduledFlowNextRunByCAS(kt *kit.Kit, info *UpdateScheduledFlowNextRunInfo) error {
+	return db.dao.AsyncScheduledFlow().UpdateNextRunAtByCAS(kt, (*typesasync.UpdateScheduledFlowNextRunInfo)(info))
+}
+
+// ListScheduledFlow 查询定时任务流
+func (db *mysql) ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error) {
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncScheduledFlow().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.ScheduledFlow, 0, len(list.Details))
+	for _, one := range list.Details {
+		flows = append(flows, model.ScheduledFlow{
+			ID:            one.ID,
+			Name:          one.Name,
+			Cron:          one.Cron,
+			FlowName:      one.FlowName,
+			Tasks:         one.Tasks,
+			Memo:          one.Memo,
+			MisfirePolicy: one.MisfirePolicy,
+			Enable:        one.Enable,
+			LastFlowID:    one.LastFlowID,
+			LastRunAt:     one.LastRunAt,
+			NextRunAt:     one.NextRunAt,
+			Creator:       one.Creator,
+			Reviser:       one.Reviser,
+			CreatedAt:     one.CreatedAt.String(),
+			UpdatedAt:     one.UpdatedAt.String(),
+		})
+	}
+
+	return flows, nil
+}
+
+// CountScheduledFlow 查询定时任务流数量
+func (db *mysql) CountScheduledFlow(kt *kit.Kit, expr *filter.Expression) (uint64, error) {
+	opt := &types.ListOption{
+		Filter: expr,
+		Page:   core.NewCountPage(),
+	}
+	list, err := db.dao.AsyncScheduledFlow().List(kt, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return list.Count, nil
+}
+
+// DeleteScheduledFlow 删除定时任务流
+func (db *mysql) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+	return db.dao.AsyncScheduledFlow().DeleteByID(kt, id)
+}
@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+/*
+compensateFlow 补偿回滚处于 Compensating 状态的任务流：
+ 1. 按拓扑逆序获取执行成功的任务，依次调用其 CompensateAction.Compensate 进行补偿，补偿过程使用任务流持久化的共享数据。
+ 2. 任务状态变化: success -> compensating -> compensated / compensate_failed，未实现 CompensateAction 的任务保持 success 不做处理。
+ 3. 任一任务补偿失败，停止补偿，任务流更新为 compensate_failed，否则更新为 compensated。
+*/
+func compensateFlow(kt *kit.Kit, bd backend.Backend, flow *Flow) error {
+	tasks, err := listTaskByFlowID(kt, bd, flow.ID)
+	if err != nil {
+		logs.Errorf("list task by flow id failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+		return err
+	}
+
+	root, err := BuildTaskRoot(tasks)
+	if err != nil {
+		logs.Errorf("build task root failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+		return err
+	}
+
+	taskIDMap := make(map[string]*Task, len(tasks))
+	for _, one := range tasks {
+		taskIDMap[one.ID] = one
+	}
+
+	if flow.ShareData == nil {
+		flow.ShareData = tableasync.NewShareData(nil)
+	}
+	flow.ShareData.Save = func(kt *kit.Kit, data *tableasync.ShareData) error {
+		return bd.BatchUpdateFlow(kt, []model.Flow{{ID: flow.ID, ShareData: data}})
+	}
+
+	for _, id := range root.GetCompensateTasks() {
+		task := taskIDMap[id]
+		task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData), func(taskKit *kit.Kit, task *model.Task) error {
+			return bd.UpdateTask(taskKit, task)
+		}, flow)
+
+		if err = compensateTask(task); err != nil {
+			logs.Errorf("compensate task failed, err: %v, flow: %s, task: %s, rid: %s", err, flow.ID, task.ID,
+				task.Kit.Rid)
+
+			if stateErr := updateFlowStateAndReason(kt, bd, flow.ID, enumor.FlowCompensating,
+				enumor.FlowCompensateFailed, ErrSomeTaskCompensateFailed); stateErr != nil {
+
+				logs.Errorf("update flow state to %s failed, err: %v, rid: %s", enumor.FlowCompensateFailed,
+					stateErr, kt.Rid)
+				return stateErr
+			}
+
+			return nil
+		}
+	}
+
+	if err = updateFlowState(kt, bd, flow.ID, enumor.FlowCompensating, enumor.FlowCompensated); err != nil {
+		logs.Errorf("update flow state to %s failed, err: %v, rid: %s", enumor.FlowCompensated, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// compensateTask 调用任务的 Compensate 进行补偿回滚，并记录补偿状态。
+func compensateTask(task *Task) error {
+	act, exist := action.GetAction(task.ActionName)
+	if !exist {
+		return compensateTaskFailed(task, fmt.Errorf("action: %s not found", task.ActionName))
+	}
+
+	compensateAct, ok := act.(action.CompensateAction)
+	if !ok {
+		// 未实现补偿的任务无需补偿
+		logs.V(3).Infof("action: %s not impl CompensateAction, skip compensate, task: %s, rid: %s", act.Name(),
+			task.ID, task.Kit.Rid)
+		return nil
+	}
+
+	if task.State == enumor.TaskSuccess {
+		if err := task.UpdateState(enumor.TaskCompensating); err != nil {
+			return err
+		}
+	}
+
+	params, err := task.prepareParams(act)
+	if err != nil {
+		return compensateTaskFailed(task, err)
+	}
+
+	if err = compensateAct.Compensate(task.ExecuteKit, params, string(task.Result)); err != nil {
+		return compensateTaskFailed(task, fmt.Errorf("compensate failed, err: %v", err))
+	}
+
+	return task.UpdateState(enumor.TaskCompensated)
+}
+
+// compensateTaskFailed 记录任务补偿失败状态及原因，并返回补偿失败的错误。
+func compensateTaskFailed(task *Task, err error) error {
+	if updateErr := task.UpdateTask(enumor.TaskCompensateFailed, err.Error(), nil); updateErr != nil {
+		return updateErr
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCompensateAction = enumor.ActionCreateFactoryTest
+	testRollbackAction   = enumor.ActionProduceTest
+)
+
+// createdRes 测试任务创建的资源，补偿时按执行结果删除
+var createdRes = make(map[string]bool)
+
+type testCompensateAct struct{}
+
+func (act testCompensateAct) Name() enumor.ActionName {
+	return testCompensateAction
+}
+
+func (act testCompensateAct) Run(run.ExecuteKit, interface{}) (interface{}, error) {
+	createdRes["res-1"] = true
+	return "res-1", nil
+}
+
+func (act testCompensateAct) Compensate(_ run.ExecuteKit, _ interface{}, result string) error {
+	var id string
+	if err := json.UnmarshalFromString(result, &id); err != nil {
+		return err
+	}
+	delete(createdRes, id)
+	return nil
+}
+
+type testRollbackOnlyAct struct{}
+
+func (act testRollbackOnlyAct) Name() enumor.ActionName {
+	return testRollbackAction
+}
+
+func (act testRollbackOnlyAct) Run(run.ExecuteKit, interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func (act testRollbackOnlyAct) Rollback(run.ExecuteKit, interface{}) error {
+	panic("rollback should not be called by compensate")
+}
+
+func TestCompensateTask(t *testing.T) {
+	action.RegisterAction(testCompensateAct{}, testRollbackOnlyAct{})
+
+	newTask := func(name enumor.ActionName, result string) (*Task, *[]enumor.TaskState) {
+		states := make([]enumor.TaskState, 0)
+		task := &Task{Task: model.Task{ID: "task-1", ActionName: name, State: enumor.TaskSuccess,
+			Reason: new(tableasync.Reason)}, Kit: kit.New()}
+		task.InitDep(run.NewExecuteContext(task.Kit, nil), func(_ *kit.Kit, md *model.Task) error {
+			states = append(states, md.State)
+			return nil
+		}, nil)
+		task.Result = types.JsonField(result)
+		return task, &states
+	}
+
+	// 实现补偿接口的任务，按执行结果删除创建的资源
+	_, err := testCompensateAct{}.Run(nil, nil)
+	assert.NoError(t, err)
+	task, states := newTask(testCompensateAction, `"res-1"`)
+	assert.NoError(t, compensateTask(task))
+	assert.Empty(t, createdRes)
+	assert.Equal(t, []enumor.TaskState{enumor.TaskCompensating, enumor.TaskCompensated}, *states)
+
+	// 仅实现重试回滚接口的任务不做补偿
+	task, states = newTask(testRollbackAction, "")
+	assert.NoError(t, compensateTask(task))
+	assert.Empty(t, *states)
+	assert.Equal(t, enumor.TaskSuccess, task.State)
+}
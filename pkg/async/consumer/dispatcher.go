@@ -21,35 +21,47 @@ package consumer
 
 import (
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/async/consumer/leader"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	cvt "hcm/pkg/tools/converter"
 )
 
 // NewDispatcher new dispatcher.
 func NewDispatcher(bd backend.Backend, ld leader.Leader, opt *DispatcherOption) *Dispatcher {
 	return &Dispatcher{
-		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
-		bd:               bd,
-		ld:               ld,
-		closeCh:          make(chan struct{}),
-		wg:               new(sync.WaitGroup),
+		watchIntervalSec:        time.Duration(opt.WatchIntervalSec) * time.Second,
+		defaultConcurrencyLimit: opt.DefaultConcurrencyLimit,
+		concurrencyLimits:       opt.ConcurrencyLimits,
+		bd:                      bd,
+		ld:                      ld,
+		closeCh:                 make(chan struct{}),
+		wg:                      new(sync.WaitGroup),
 	}
 }
 
-// Dispatcher 派发器，负责将Pending状态的任务流，派发到指定节点去执行，并将Flow状态改为Scheduled。。
+// Dispatcher 派发器，负责将Pending状态的任务流，派发到指定节点去执行，并将Flow状态改为Scheduled。
+// 任务流按优先级从高到低派发，优先派发到当前正在执行任务最少的节点，并限制相同并发控制键的任务流同时运行的数量。
 type Dispatcher struct {
 	watchIntervalSec time.Duration
 
+	// defaultConcurrencyLimit 相同并发控制键的任务流同时运行的默认上限，0 表示不限制
+	defaultConcurrencyLimit uint
+	// concurrencyLimits 按并发控制键类型设置的并发上限
+	concurrencyLimits map[enumor.FlowConcurrencyKeyType]uint
+
 	bd backend.Backend
 	ld leader.Leader
 
@@ -85,22 +97,15 @@ func (d *Dispatcher) WatchPendingFlow() {
 
 // Do 监听处于Pending状态的流，并派发到指定节点。
 func (d *Dispatcher) Do(kt *kit.Kit) error {
-	input := &backend.ListInput{
-		Filter: tools.ExpressionAnd(
-			// 走worker,state 索引
-			tools.RuleEqual("worker", ""),
-			tools.RuleEqual("state", enumor.FlowPending),
-		),
-		Page: core.NewDefaultBasePage(),
-	}
-	flows, err := d.bd.ListFlow(kt, input)
+	pending, err := d.bd.CountFlow(kt, tools.ExpressionAnd(tools.RuleEqual("worker", ""),
+		tools.RuleEqual("state", enumor.FlowPending)))
 	if err != nil {
-		logs.Errorf("list flow failed, err: %v, rid: %s", err, kt.Rid)
+		logs.Errorf("count pending flow failed, err: %v, rid: %s", err, kt.Rid)
 		return err
 	}
 
-	if len(flows) == 0 {
-		logs.V(3).Infof("currently no task flows to assign, skip handleRunningFlow, rid: %s", kt.Rid)
+	if pending == 0 {
+		logs.V(3).Infof("currently no task flows to assign, skip dispatch, rid: %s", kt.Rid)
 		return nil
 	}
 
@@ -113,24 +118,224 @@ func (d *Dispatcher) Do(kt *kit.Kit) error {
 		return errors.New("alive nodes not found")
 	}
 
-	infos := make([]backend.UpdateFlowInfo, 0, len(flows))
-	for index, one := range flows {
-		infos = append(infos, backend.UpdateFlowInfo{
-			ID:     one.ID,
-			Source: enumor.FlowPending,
-			Target: enumor.FlowScheduled,
-			Worker: cvt.ValToPtr(nodes[index%len(nodes)]), // 任务分发算法，后续看是否优化
-		})
+	plan, err := d.newDispatchPlan(kt, nodes)
+	if err != nil {
+		return err
 	}
 
-	if err = d.bd.BatchUpdateFlowStateByCAS(kt, infos); err != nil {
-		logs.Errorf("batch update flow failed, err: %v, rid: %s", err, kt.Rid)
-		return err
+	for _, priority := range enumor.FlowPriorities {
+		if err = d.dispatchPriority(kt, plan, priority); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maxDispatchPagePerPriority 每轮派发中每个优先级最多查询的页数，避免单轮派发耗时过长
+const maxDispatchPagePerPriority = 20
+
+// dispatchPriority 派发某个优先级的待派发任务流。查询时排除已达到并发上限的并发控制键，避免这些任务流占满一页
+// 而阻塞后面可以派发的任务流。每页派发后已派发的任务流不再是待派发状态，新达到上限的键也会被排除，因此每次都从头查询
+func (d *Dispatcher) dispatchPriority(kt *kit.Kit, plan *dispatchPlan, priority enumor.FlowPriority) error {
+	for page := 0; page < maxDispatchPagePerPriority; page++ {
+		input := &backend.ListInput{
+			Filter: pendingFlowFilter(priority, plan.saturatedKeys()),
+			Page:   core.NewDefaultBasePage(),
+		}
+		flows, err := d.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list %d priority pending flow failed, err: %v, rid: %s", priority, err, kt.Rid)
+			return err
+		}
+
+		infos := make([]backend.UpdateFlowInfo, 0, len(flows))
+		for _, one := range flows {
+			worker, ok := plan.assign(one)
+			if !ok {
+				// 并发控制键已达到上限，留在Pending状态等待下次派发
+				continue
+			}
+
+			infos = append(infos, backend.UpdateFlowInfo{
+				ID:     one.ID,
+				Source: enumor.FlowPending,
+				Target: enumor.FlowScheduled,
+				Worker: cvt.ValToPtr(worker),
+			})
+		}
+
+		if len(infos) != 0 {
+			if err = d.bd.BatchUpdateFlowStateByCAS(kt, infos); err != nil {
+				logs.Errorf("batch update flow failed, err: %v, rid: %s", err, kt.Rid)
+				return err
+			}
+		}
+
+		// 本页的任务流要么已派发，要么其并发控制键已达到上限会在下次查询中排除，本页没有派发时不会再有可派发的任务流
+		if uint(len(flows)) < input.Page.Limit || len(infos) == 0 {
+			return nil
+		}
 	}
 
 	return nil
 }
 
+// pendingFlowFilter 查询待派发任务流的条件，排除已达到并发上限的并发控制键
+func pendingFlowFilter(priority enumor.FlowPriority, saturatedKeys []string) *filter.Expression {
+	rules := []*filter.AtomRule{
+		// 走worker,state 索引
+		tools.RuleEqual("worker", ""),
+		tools.RuleEqual("state", enumor.FlowPending),
+		priorityRule(priority),
+	}
+	if len(saturatedKeys) != 0 {
+		rules = append(rules, tools.RuleNotIn("concurrency_key", saturatedKeys))
+	}
+
+	return tools.ExpressionAnd(rules...)
+}
+
+// priorityRule 普通优先级使用排除高、低优先级的方式查询，兼容未设置优先级的历史任务流
+func priorityRule(priority enumor.FlowPriority) *filter.AtomRule {
+	if priority == enumor.FlowPriorityNormal {
+		return tools.RuleNotIn("priority", []enumor.FlowPriority{enumor.FlowPriorityHigh, enumor.FlowPriorityLow})
+	}
+
+	return tools.RuleEqual("priority", priority)
+}
+
+// newDispatchPlan 统计各节点上正在执行的任务数量作为节点负载，以及各并发控制键已占用的并发数
+func (d *Dispatcher) newDispatchPlan(kt *kit.Kit, nodes []string) (*dispatchPlan, error) {
+	plan := &dispatchPlan{
+		nodes:        nodes,
+		loads:        make(map[string]uint, len(nodes)),
+		keyRunning:   make(map[string]uint),
+		defaultLimit: d.defaultConcurrencyLimit,
+		limits:       d.concurrencyLimits,
+	}
+
+	runningTasks, err := d.countRunningTask(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.ContainersExpression("state", []enumor.FlowState{enumor.FlowScheduled, enumor.FlowRunning}),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "worker", "concurrency_key"},
+	}
+	for {
+		flows, err := d.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list running flow failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range flows {
+			// 已派发但还没有任务在执行的任务流即将开始执行任务，至少按一个任务计算
+			plan.loads[cvt.PtrToVal(one.Worker)] += max(runningTasks[one.ID], 1)
+			if len(one.ConcurrencyKey) != 0 {
+				plan.keyRunning[one.ConcurrencyKey]++
+			}
+		}
+
+		if uint(len(flows)) < input.Page.Limit {
+			break
+		}
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return plan, nil
+}
+
+// countRunningTask 统计各任务流正在执行的任务数量，包括正在执行、回滚和补偿的任务
+func (d *Dispatcher) countRunningTask(kt *kit.Kit) (map[string]uint, error) {
+	input := &backend.ListInput{
+		Filter: tools.ContainersExpression("state", []enumor.TaskState{enumor.TaskRunning, enumor.TaskRollback,
+			enumor.TaskCompensating}),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "flow_id"},
+	}
+
+	result := make(map[string]uint)
+	for {
+		tasks, err := d.bd.ListTask(kt, input)
+		if err != nil {
+			logs.Errorf("list running task failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range tasks {
+			result[one.FlowID]++
+		}
+
+		if uint(len(tasks)) < input.Page.Limit {
+			break
+		}
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return result, nil
+}
+
+// dispatchPlan 一轮派发的节点负载和并发控制键占用情况
+type dispatchPlan struct {
+	nodes []string
+	// loads 节点上正在执行的任务数量
+	loads map[string]uint
+	// keyRunning 并发控制键已派发和运行中的任务流数量
+	keyRunning   map[string]uint
+	defaultLimit uint
+	limits       map[enumor.FlowConcurrencyKeyType]uint
+}
+
+// saturatedKeys 已达到并发上限的并发控制键
+func (p *dispatchPlan) saturatedKeys() []string {
+	keys := make([]string, 0)
+	for key, running := range p.keyRunning {
+		if limit := p.limitOf(key); limit > 0 && running >= limit {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// assign 为任务流选择负载最低的节点，并发控制键达到上限时返回false
+func (p *dispatchPlan) assign(flow model.Flow) (string, bool) {
+	key := flow.ConcurrencyKey
+	if len(key) != 0 {
+		if limit := p.limitOf(key); limit > 0 && p.keyRunning[key] >= limit {
+			return "", false
+		}
+	}
+
+	worker := p.nodes[0]
+	for _, node := range p.nodes[1:] {
+		if p.loads[node] < p.loads[worker] {
+			worker = node
+		}
+	}
+
+	p.loads[worker]++
+	if len(key) != 0 {
+		p.keyRunning[key]++
+	}
+
+	return worker, true
+}
+
+// limitOf 获取并发控制键的上限，键格式为 <type>:<id>
+func (p *dispatchPlan) limitOf(key string) uint {
+	keyType, _, _ := strings.Cut(key, ":")
+	if limit, exist := p.limits[enumor.FlowConcurrencyKeyType(keyType)]; exist {
+		return limit
+	}
+
+	return p.defaultLimit
+}
+
 // Close dispatcher
 func (d *Dispatcher) Close() {
 
@@ -0,0 +1,253 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	cvt "hcm/pkg/tools/converter"
+)
+
+func TestDispatchPlanAssign(t *testing.T) {
+	plan := &dispatchPlan{
+		nodes:      []string{"node-a", "node-b"},
+		loads:      map[string]uint{"node-a": 2},
+		keyRunning: map[string]uint{"account:1": 1},
+		limits: map[enumor.FlowConcurrencyKeyType]uint{
+			enumor.AccountConcurrencyKey: 2,
+		},
+	}
+
+	worker, ok := plan.assign(model.Flow{ConcurrencyKey: "account:1"})
+	if !ok || worker != "node-b" {
+		t.Fatalf("expect assign to node-b, got: %s, %v", worker, ok)
+	}
+
+	if _, ok = plan.assign(model.Flow{ConcurrencyKey: "account:1"}); ok {
+		t.Fatalf("expect account:1 reach concurrency limit")
+	}
+
+	// 未配置上限的键类型使用默认上限 0，即不限制
+	for i := 0; i < 3; i++ {
+		if _, ok = plan.assign(model.Flow{ConcurrencyKey: "region:ap-guangzhou"}); !ok {
+			t.Fatalf("expect region key not limited")
+		}
+	}
+
+	if plan.loads["node-a"] != 3 || plan.loads["node-b"] != 3 {
+		t.Fatalf("unexpected node loads: %v", plan.loads)
+	}
+}
+
+func TestDispatchPlanDefaultLimit(t *testing.T) {
+	plan := &dispatchPlan{
+		nodes:        []string{"node-a"},
+		loads:        map[string]uint{},
+		keyRunning:   map[string]uint{},
+		defaultLimit: 1,
+	}
+
+	if _, ok := plan.assign(model.Flow{ConcurrencyKey: "lb:lb-1"}); !ok {
+		t.Fatalf("expect first flow assigned")
+	}
+	if _, ok := plan.assign(model.Flow{ConcurrencyKey: "lb:lb-1"}); ok {
+		t.Fatalf("expect lb:lb-1 reach default limit")
+	}
+	if _, ok := plan.assign(model.Flow{}); !ok {
+		t.Fatalf("expect flow without concurrency key assigned")
+	}
+}
+
+// fakeDispatchBackend 内存中的任务流和任务，只实现派发器使用的方法
+type fakeDispatchBackend struct {
+	backend.Backend
+	flows []*model.Flow
+	tasks []model.Task
+}
+
+func (b *fakeDispatchBackend) CountFlow(_ *kit.Kit, expr *filter.Expression) (uint64, error) {
+	count := uint64(0)
+	for _, one := range b.flows {
+		if matchFakeRules(expr, flowField(one)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (b *fakeDispatchBackend) ListFlow(_ *kit.Kit, input *backend.ListInput) ([]model.Flow, error) {
+	matched := make([]model.Flow, 0)
+	for _, one := range b.flows {
+		if matchFakeRules(input.Filter, flowField(one)) {
+			matched = append(matched, *one)
+		}
+	}
+
+	start := min(int(input.Page.Start), len(matched))
+	end := min(start+int(input.Page.Limit), len(matched))
+	return matched[start:end], nil
+}
+
+func (b *fakeDispatchBackend) ListTask(_ *kit.Kit, input *backend.ListInput) ([]model.Task, error) {
+	matched := make([]model.Task, 0)
+	for _, one := range b.tasks {
+		fields := map[string]interface{}{"state": one.State, "flow_id": one.FlowID}
+		if matchFakeRules(input.Filter, func(name string) interface{} { return fields[name] }) {
+			matched = append(matched, one)
+		}
+	}
+
+	start := min(int(input.Page.Start), len(matched))
+	end := min(start+int(input.Page.Limit), len(matched))
+	return matched[start:end], nil
+}
+
+func (b *fakeDispatchBackend) BatchUpdateFlowStateByCAS(_ *kit.Kit, infos []backend.UpdateFlowInfo) error {
+	for _, info := range infos {
+		for _, one := range b.flows {
+			if one.ID == info.ID {
+				if one.State != info.Source {
+					return fmt.Errorf("flow %s state is %s", one.ID, one.State)
+				}
+				one.State, one.Worker = info.Target, info.Worker
+			}
+		}
+	}
+	return nil
+}
+
+func flowField(flow *model.Flow) func(name string) interface{} {
+	return func(name string) interface{} {
+		switch name {
+		case "id":
+			return flow.ID
+		case "worker":
+			return cvt.PtrToVal(flow.Worker)
+		case "state":
+			return flow.State
+		case "priority":
+			return flow.Priority
+		case "concurrency_key":
+			return flow.ConcurrencyKey
+		}
+		return nil
+	}
+}
+
+// matchFakeRules 只支持派发器使用的 AND 条件和 eq、in、nin 操作
+func matchFakeRules(expr *filter.Expression, field func(name string) interface{}) bool {
+	for _, one := range expr.Rules {
+		rule, ok := one.(*filter.AtomRule)
+		if !ok {
+			val := one.(filter.AtomRule)
+			rule = &val
+		}
+		val := fmt.Sprint(field(rule.Field))
+		switch rule.Op {
+		case filter.Equal.Factory():
+			if val != fmt.Sprint(rule.Value) {
+				return false
+			}
+		case filter.In.Factory(), filter.NotIn.Factory():
+			in := false
+			values := reflect.ValueOf(rule.Value)
+			for i := 0; i < values.Len(); i++ {
+				if fmt.Sprint(values.Index(i).Interface()) == val {
+					in = true
+				}
+			}
+			if in != (rule.Op == filter.In.Factory()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type fakeLeader struct {
+	nodes []string
+}
+
+func (l *fakeLeader) IsLeader() bool                { return true }
+func (l *fakeLeader) AliveNodes() ([]string, error) { return l.nodes, nil }
+func (l *fakeLeader) CurrNode() string              { return l.nodes[0] }
+
+func TestDispatcherSkipSaturatedKeys(t *testing.T) {
+	bd := new(fakeDispatchBackend)
+	// 一整页都是已达到上限的账号的任务流，后面其他账号的任务流也需要派发
+	for i := 0; i < 600; i++ {
+		bd.flows = append(bd.flows, &model.Flow{ID: fmt.Sprintf("a-%d", i), State: enumor.FlowPending,
+			Priority: enumor.FlowPriorityNormal, ConcurrencyKey: "account:a"})
+	}
+	for i := 0; i < 3; i++ {
+		bd.flows = append(bd.flows, &model.Flow{ID: fmt.Sprintf("b-%d", i), State: enumor.FlowPending,
+			Priority: enumor.FlowPriorityNormal, ConcurrencyKey: "account:b"})
+	}
+
+	d := NewDispatcher(bd, &fakeLeader{nodes: []string{"node-a"}}, &DispatcherOption{
+		ConcurrencyLimits: map[enumor.FlowConcurrencyKeyType]uint{enumor.AccountConcurrencyKey: 1},
+	})
+	if err := d.Do(NewKit()); err != nil {
+		t.Fatalf("dispatch failed, err: %v", err)
+	}
+
+	scheduled := make(map[string]int)
+	for _, one := range bd.flows {
+		if one.State == enumor.FlowScheduled {
+			scheduled[one.ConcurrencyKey]++
+		}
+	}
+	if scheduled["account:a"] != 1 || scheduled["account:b"] != 1 {
+		t.Fatalf("expect one flow of each account scheduled, got: %v", scheduled)
+	}
+}
+
+func TestDispatcherLoadByRunningTask(t *testing.T) {
+	bd := &fakeDispatchBackend{
+		flows: []*model.Flow{
+			{ID: "busy", State: enumor.FlowRunning, Worker: cvt.ValToPtr("node-a")},
+			{ID: "idle-1", State: enumor.FlowRunning, Worker: cvt.ValToPtr("node-b")},
+			{ID: "idle-2", State: enumor.FlowScheduled, Worker: cvt.ValToPtr("node-b")},
+			{ID: "new", State: enumor.FlowPending, Priority: enumor.FlowPriorityHigh},
+		},
+	}
+	for i := 0; i < 5; i++ {
+		bd.tasks = append(bd.tasks, model.Task{ID: fmt.Sprintf("task-%d", i), FlowID: "busy",
+			State: enumor.TaskRunning})
+	}
+	bd.tasks = append(bd.tasks, model.Task{ID: "task-5", FlowID: "idle-1", State: enumor.TaskRunning})
+
+	d := NewDispatcher(bd, &fakeLeader{nodes: []string{"node-a", "node-b"}}, new(DispatcherOption))
+	if err := d.Do(NewKit()); err != nil {
+		t.Fatalf("dispatch failed, err: %v", err)
+	}
+
+	// node-a 只有一个任务流，但有5个任务正在执行，新任务流应该派发到 node-b
+	if worker := cvt.PtrToVal(bd.flows[3].Worker); worker != "node-b" {
+		t.Fatalf("expect flow dispatched to node-b, got: %s", worker)
+	}
+}
@@ -40,6 +40,10 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/retry"
 	"hcm/pkg/tools/times"
+	"hcm/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Executor （执行器）: 准备任务执行所需要的超时控制，共享数据等工具，并执行任务。
@@ -166,6 +170,16 @@ func (exec *executor) subWorkerQueue() {
 
 // 任务执行体
 func (exec *executor) workerDo(task *Task) (err error) {
+	// 任务执行的span，task.Kit 与 ExecuteKit 共享，action 中的下游调用均挂在该span下
+	ctx, span := tracing.Start(task.Kit.Ctx, "async-task "+string(task.ActionName), trace.WithAttributes(
+		tracing.RidKey.String(task.Kit.Rid),
+		attribute.String("async.flow_id", task.FlowID),
+		attribute.String("async.flow_name", string(task.FlowName)),
+		attribute.String("async.task_id", task.ID),
+		attribute.String("async.action_name", string(task.ActionName)),
+	))
+	task.Kit.Ctx = ctx
+	defer func() { tracing.End(span, err) }()
 
 	// cancelMap清理执行成功/失败的任务
 	defer exec.cancelMap.Delete(task.ID)
@@ -405,6 +419,7 @@ func (exec *executor) UpdateTask(task *Task, state enumor.TaskState, reason stri
 	}
 
 	task.State = state
+	task.syncTiming(md)
 
 	return nil
 }
@@ -50,6 +50,7 @@ type LeaderChangeHandler struct {
 
 	dispatcher *Dispatcher
 	watchDog   WatchDog
+	trigger    *Trigger
 
 	closeCh chan struct{}
 
@@ -113,6 +114,12 @@ func (handler *LeaderChangeHandler) startLeaderComponent() {
 	wd.Start()
 	handler.closers = append(handler.closers, wd)
 	handler.watchDog = wd
+
+	// 初始化定时任务流触发器并启动同时设置关闭函数
+	tg := NewTrigger(handler.bd, handler.opt.Trigger)
+	tg.Start()
+	handler.closers = append(handler.closers, tg)
+	handler.trigger = tg
 }
 
 // Close 主从切换处理器
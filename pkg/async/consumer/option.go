@@ -22,6 +22,7 @@ package consumer
 import (
 	"time"
 
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 )
 
@@ -31,6 +32,7 @@ type Option struct {
 	Executor   *ExecutorOption   `json:"executor" validate:"required"`
 	Dispatcher *DispatcherOption `json:"dispatcher" validate:"required"`
 	WatchDog   *WatchDogOption   `json:"watch_dog" validate:"required"`
+	Trigger    *TriggerOption    `json:"trigger" validate:"required"`
 }
 
 // Validate Option
@@ -63,6 +65,10 @@ func (opt ExecutorOption) Validate() error {
 // DispatcherOption 主节点组件，负责派发任务
 type DispatcherOption struct {
 	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+	// DefaultConcurrencyLimit 相同并发控制键的任务流同时派发和运行的默认上限，0 表示不限制
+	DefaultConcurrencyLimit uint `json:"default_concurrency_limit" validate:"omitempty"`
+	// ConcurrencyLimits 按并发控制键类型设置的并发上限，未设置的类型使用默认上限
+	ConcurrencyLimits map[enumor.FlowConcurrencyKeyType]uint `json:"concurrency_limits" validate:"omitempty"`
 }
 
 // Validate DispatcherOption
@@ -82,6 +88,16 @@ func (opt WatchDogOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
+// TriggerOption 主节点组件，负责按cron表达式将定时任务流生成任务流
+type TriggerOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+}
+
+// Validate TriggerOption
+func (opt TriggerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
 // SleepPolicy defines the policy of loop interval with different scenario.
 type SleepPolicy struct {
 	baseInterval time.Duration
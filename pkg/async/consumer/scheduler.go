@@ -44,6 +44,7 @@ import (
 Scheduler （调度器）: TODO: 换为 捕获器、消费器，添加假死任务销毁逻辑
  1. 获取分配给当前节点的处于Scheduled状态的任务流，构建任务流树，将待执行任务推送到执行器执行。
  2. 分析执行器执行完的任务，判断任务流树状态，如果任务流处理完，更新状态，否则将子节点推送到执行器执行。
+ 3. 获取分配给当前节点的处于Compensating状态的任务流，对已成功的任务执行补偿回滚。
 */
 type Scheduler interface {
 	compctrl.Closer
@@ -97,6 +98,7 @@ func (sch *scheduler) Start() {
 	// 定期获取等待执行的任务流
 	go sch.scheduledFlowWatcher()
 	go sch.canceledFlowWatcher()
+	go sch.compensatingFlowWatcher()
 
 	// 启动workerNumber个协程进行任务流解析
 	for i := 0; i < int(sch.workerNumber); i++ {
@@ -234,6 +236,67 @@ func (sch *scheduler) handleCanceledFlow(kt *kit.Kit) (working bool, err error)
 	return true, nil
 }
 
+// compensatingFlowWatcher 查询当前节点上处于补偿中的flow并执行补偿回滚
+func (sch *scheduler) compensatingFlowWatcher() {
+	sch.workerWg.Add(1)
+
+stopLoop:
+	for {
+		select {
+		case <-sch.closeCh:
+			logs.Infof("received stop signal, stop watch compensating flow job success.")
+			break stopLoop
+		default:
+		}
+
+		kt := NewKit()
+		working, err := sch.handleCompensatingFlow(kt)
+		if err != nil {
+			logs.Errorf("%s: scheduler watch compensating flow failed, err: %v, rid: %s",
+				constant.AsyncTaskWarnSign, err, kt.Rid)
+			sch.sp.ExceptionSleep()
+			continue
+		}
+
+		if working {
+			sch.sp.ShortSleep()
+			continue
+		}
+
+		sch.sp.NormalSleep()
+	}
+
+	sch.workerWg.Done()
+}
+
+func (sch *scheduler) handleCompensatingFlow(kt *kit.Kit) (working bool, err error) {
+
+	dbFlows, err := sch.queryCurrNodeFlow(kt, enumor.FlowCompensating, listScheduledFlowLimit)
+	if err != nil {
+		logs.Errorf("fail to list compensating flow, err: %v, rid: %s", err, kt.Rid)
+		return false, err
+	}
+
+	if len(dbFlows) == 0 {
+		return false, nil
+	}
+
+	for _, one := range dbFlows {
+		flow := &Flow{Flow: one, Kit: kt.NewSubKit()}
+		logs.Infof("compensating flow: %s, rid: %s", flow.ID, flow.Kit.Rid)
+
+		if err = compensateFlow(flow.Kit, sch.backend, flow); err != nil {
+			logs.Errorf("compensate flow failed, err: %v, flow id: %s, rid: %s", err, flow.ID, flow.Kit.Rid)
+			// keep compensating other flow
+			continue
+		}
+
+		logs.Infof("compensate flow: %s finished, rid: %s", flow.ID, flow.Kit.Rid)
+	}
+
+	return true, nil
+}
+
 // listTaskByFlowID 查询当前FlowID全部的任务节点
 func listTaskByFlowID(kt *kit.Kit, bd backend.Backend, flowID string) ([]*Task, error) {
 
@@ -346,10 +409,11 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 		}
 
 		if state == enumor.FlowFailed {
-			if err = updateFlowStateAndReason(kt, sch.backend, flow.ID, enumor.FlowRunning, state,
+			target := failedFlowState(flow.Flow)
+			if err = updateFlowStateAndReason(kt, sch.backend, flow.ID, enumor.FlowRunning, target,
 				ErrSomeTaskExecFailed); err != nil {
 
-				logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
+				logs.Errorf("update flow state to %s failed, err: %v, rid: %s", target, err, kt.Rid)
 				return err
 			}
 		}
@@ -516,10 +580,11 @@ func (sch *scheduler) executeNext(kt *kit.Kit, task *Task) error {
 
 			sch.DeleteFlowTaskTree(task.FlowID)
 		case enumor.FlowFailed:
-			if err := updateFlowStateAndReason(kt, sch.backend, task.FlowID, enumor.FlowRunning, state,
+			target := failedFlowState(tree.Flow.Flow)
+			if err := updateFlowStateAndReason(kt, sch.backend, task.FlowID, enumor.FlowRunning, target,
 				ErrSomeTaskExecFailed); err != nil {
 
-				logs.Errorf("update flow state to `%s` failed, err: %v, rid: %s", state, err, kt.Rid)
+				logs.Errorf("update flow state to `%s` failed, err: %v, rid: %s", target, err, kt.Rid)
 				return err
 			}
 
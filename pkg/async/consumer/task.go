@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/retry"
+	"hcm/pkg/tools/times"
 )
 
 // Task 异步任务执行体，包含了任务运行流程、回滚流程。
@@ -201,10 +202,17 @@ func (task *Task) UpdateTask(state enumor.TaskState, reason string, result inter
 	}
 
 	task.State = state
+	task.syncTiming(md)
 
 	return nil
 }
 
+// syncTiming 将已写入的任务时间点同步到内存中的任务，避免后续状态更新覆盖
+func (task *Task) syncTiming(md *model.Task) {
+	task.Reason.StartedAt = md.Reason.StartedAt
+	task.Reason.EndedAt = md.Reason.EndedAt
+}
+
 func (task *Task) buildTaskUpdateModel(kt *kit.Kit, state enumor.TaskState, reason string,
 	result interface{}) (*model.Task, error) {
 
@@ -215,12 +223,24 @@ func (task *Task) buildTaskUpdateModel(kt *kit.Kit, state enumor.TaskState, reas
 			Message:       task.Reason.Message,
 			RollbackCount: task.Reason.RollbackCount,
 			PreState:      string(task.State),
+			StartedAt:     task.Reason.StartedAt,
+			EndedAt:       task.Reason.EndedAt,
 		},
 	}
 	if reason != "" {
 		md.Reason.Message = reason
 	}
 
+	// 记录任务首次开始执行和进入终态的时间，用于分析任务等待耗时和执行耗时
+	switch state {
+	case enumor.TaskRunning:
+		if md.Reason.StartedAt == "" {
+			md.Reason.StartedAt = times.ConvStdTimeFormat(times.ConvStdTimeNow())
+		}
+	case enumor.TaskSuccess, enumor.TaskFailed, enumor.TaskCancel:
+		md.Reason.EndedAt = times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	}
+
 	// 更新为rollback，记录rollback次数
 	if state == enumor.TaskRollback {
 		md.Reason.RollbackCount = task.Reason.RollbackCount + 1
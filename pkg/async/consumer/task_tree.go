@@ -192,6 +192,41 @@ func (t *TaskNode) GetNextExecutableTaskNodes(completedOrRetryTask *Task) (execu
 	return
 }
 
+// GetCompensateTasks 获取需要补偿回滚的节点（执行成功或补偿中），按拓扑逆序返回，保证子节点先于父节点补偿。
+func (t *TaskNode) GetCompensateTasks() (ids []string) {
+	inDegree := make(map[string]int)
+	queue := []*TaskNode{t}
+	order := make([]*TaskNode, 0)
+	for len(queue) != 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		order = append(order, cur)
+
+		for _, c := range cur.children {
+			if _, ok := inDegree[c.TaskID]; !ok {
+				inDegree[c.TaskID] = len(c.parents)
+			}
+			inDegree[c.TaskID]--
+			if inDegree[c.TaskID] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		if node.TaskID == VirtualTaskRootID {
+			continue
+		}
+
+		if node.State == enumor.TaskSuccess || node.State == enumor.TaskCompensating {
+			ids = append(ids, node.TaskID)
+		}
+	}
+
+	return ids
+}
+
 // HasCycle check has cycle
 func (t *TaskNode) HasCycle() (cycleStart *TaskNode) {
 	visited, incomplete := map[string]struct{}{}, map[string]*TaskNode{}
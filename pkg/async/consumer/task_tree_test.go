@@ -0,0 +1,52 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompensateTasks(t *testing.T) {
+	newTask := func(id string, state enumor.TaskState, dependOn ...action.ActIDType) *Task {
+		return &Task{Task: model.Task{ID: id, ActionID: action.ActIDType(id), State: state, DependOn: dependOn}}
+	}
+
+	// a -> b -> d, a -> c -> d, a -> e
+	tasks := []*Task{
+		newTask("a", enumor.TaskSuccess),
+		newTask("b", enumor.TaskSuccess, "a"),
+		newTask("c", enumor.TaskCompensating, "a"),
+		newTask("d", enumor.TaskFailed, "b", "c"),
+		newTask("e", enumor.TaskCompensated, "a"),
+	}
+	root, err := BuildTaskRoot(tasks)
+	assert.NoError(t, err)
+
+	ids := root.GetCompensateTasks()
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, ids)
+	// 子节点先于父节点补偿
+	assert.Equal(t, "a", ids[len(ids)-1])
+}
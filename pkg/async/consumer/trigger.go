@@ -0,0 +1,246 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/times"
+)
+
+// NewTrigger new trigger.
+func NewTrigger(bd backend.Backend, opt *TriggerOption) *Trigger {
+	return &Trigger{
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		bd:               bd,
+		closeCh:          make(chan struct{}),
+		wg:               new(sync.WaitGroup),
+	}
+}
+
+// Trigger 触发器，负责将到达触发时间的定时任务流生成任务流，生成的任务流按普通任务流进行派发和执行。
+type Trigger struct {
+	watchIntervalSec time.Duration
+
+	bd backend.Backend
+
+	wg      *sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// Start trigger.
+func (t *Trigger) Start() {
+	t.wg.Add(1)
+	go t.WatchScheduledFlow()
+}
+
+// WatchScheduledFlow 周期性检查启用的定时任务流，到达触发时间时生成任务流。
+func (t *Trigger) WatchScheduledFlow() {
+	for {
+		select {
+		case <-t.closeCh:
+			t.wg.Done()
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := t.Do(kt); err != nil {
+			logs.Errorf("%s: trigger do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(t.watchIntervalSec)
+	}
+}
+
+// Do 检查所有启用的定时任务流，单个定时任务流触发失败不影响其他定时任务流。
+func (t *Trigger) Do(kt *kit.Kit) error {
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("enable", true),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+	for {
+		flows, err := t.bd.ListScheduledFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list scheduled flow failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		now := times.ConvStdTimeNow()
+		for i := range flows {
+			if err = t.fire(kt, &flows[i], now); err != nil {
+				logs.Errorf("%s: fire scheduled flow failed, err: %v, id: %s, name: %s, rid: %s",
+					constant.AsyncTaskWarnSign, err, flows[i].ID, flows[i].Name, kt.Rid)
+			}
+		}
+
+		if uint(len(flows)) < input.Page.Limit {
+			return nil
+		}
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+}
+
+func (t *Trigger) fire(kt *kit.Kit, sf *model.ScheduledFlow, now time.Time) error {
+	schedule, err := cron.Parse(sf.Cron)
+	if err != nil {
+		return err
+	}
+
+	// 上一次生成的任务流还未结束时不触发，避免同一个定时任务流重叠执行，错过的触发时间按misfire策略处理
+	if len(sf.LastFlowID) != 0 {
+		running, err := t.isFlowRunning(kt, sf.LastFlowID)
+		if err != nil {
+			return err
+		}
+		if running {
+			logs.V(3).Infof("scheduled flow %s last flow %s is still running, skip, rid: %s", sf.ID,
+				sf.LastFlowID, kt.Rid)
+			return nil
+		}
+	}
+
+	plan := planFire(sf, schedule, now)
+	if !plan.fire && plan.nextRunAt.IsZero() {
+		return nil
+	}
+
+	// 先以当前的下一次触发时间为条件CAS推进触发时间，抢占成功后再生成任务流。推进和生成之间发生进程崩溃或
+	// 主节点切换时最多丢失一次触发，不会因为其他节点读到旧的触发时间而重复触发。
+	info := &backend.UpdateScheduledFlowNextRunInfo{ID: sf.ID, Source: sf.NextRunAt}
+	if !plan.nextRunAt.IsZero() {
+		info.Target = times.ConvStdTimeFormat(plan.nextRunAt)
+	}
+	if err = t.bd.UpdateScheduledFlowNextRunByCAS(kt, info); err != nil {
+		if errf.IsRecordNotUpdate(err) {
+			logs.V(3).Infof("scheduled flow %s next run at %s already taken, skip, rid: %s", sf.ID, sf.NextRunAt,
+				kt.Rid)
+			return nil
+		}
+		logs.Errorf("update scheduled flow next run at failed, err: %v, id: %s, rid: %s", err, sf.ID, kt.Rid)
+		return err
+	}
+
+	if !plan.fire {
+		if plan.misfired {
+			logs.Warnf("scheduled flow %s misfired at %s, skip to %s, rid: %s", sf.ID, sf.NextRunAt, info.Target,
+				kt.Rid)
+		}
+		return nil
+	}
+
+	flow, err := producer.BuildScheduledFlow(sf)
+	if err != nil {
+		return err
+	}
+
+	flowID, err := t.bd.CreateFlow(kt, flow)
+	if err != nil {
+		logs.Errorf("create flow for scheduled flow failed, err: %v, id: %s, rid: %s", err, sf.ID, kt.Rid)
+		return err
+	}
+
+	md := &model.ScheduledFlow{
+		ID:         sf.ID,
+		LastFlowID: flowID,
+		LastRunAt:  times.ConvStdTimeFormat(plan.runAt),
+	}
+	logs.Infof("scheduled flow %s fired, run at: %s, flow id: %s, rid: %s", sf.ID, md.LastRunAt, flowID, kt.Rid)
+
+	return t.bd.UpdateScheduledFlow(kt, md)
+}
+
+func (t *Trigger) isFlowRunning(kt *kit.Kit, flowID string) (bool, error) {
+	flows, err := t.bd.ListFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return false, err
+	}
+
+	if len(flows) == 0 {
+		return false, nil
+	}
+
+	switch flows[0].State {
+	case enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// firePlan 定时任务流本次检查的处理结果，nextRunAt为零值时表示无需更新下一次触发时间
+type firePlan struct {
+	fire     bool
+	misfired bool
+	runAt    time.Time
+	// nextRunAt 处理后的下一次触发时间
+	nextRunAt time.Time
+}
+
+// planFire 计算定时任务流本次是否触发。计划触发时间之后的下一个触发时间也已经过去时，认为错过了触发(misfire)：
+// skip 策略丢弃错过的触发，从当前时间重新计算下一次触发时间；catch_up 策略按计划触发时间逐个补偿执行。
+func planFire(sf *model.ScheduledFlow, schedule *cron.Schedule, now time.Time) firePlan {
+	next, err := time.Parse(constant.TimeStdFormat, sf.NextRunAt)
+	if err != nil {
+		return firePlan{nextRunAt: schedule.Next(now)}
+	}
+	// cron 表达式按当前时区计算
+	next = next.In(now.Location())
+
+	if now.Before(next) {
+		return firePlan{}
+	}
+
+	following := schedule.Next(next)
+	misfired := !following.IsZero() && !following.After(now)
+	if misfired && sf.MisfirePolicy != enumor.MisfireCatchUp {
+		return firePlan{misfired: true, nextRunAt: schedule.Next(now)}
+	}
+
+	return firePlan{fire: true, misfired: misfired, runAt: next, nextRunAt: following}
+}
+
+// Close trigger
+func (t *Trigger) Close() {
+
+	logs.Infof("trigger receive close cmd, start to close")
+
+	close(t.closeCh)
+	t.wg.Wait()
+
+	logs.Infof("trigger close success")
+
+}
@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/times"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanFire(t *testing.T) {
+	schedule, err := cron.Parse("0 * * * *")
+	assert.NoError(t, err)
+
+	nextRunAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	sf := &model.ScheduledFlow{NextRunAt: times.ConvStdTimeFormat(nextRunAt), MisfirePolicy: enumor.MisfireSkip}
+
+	// 未到触发时间
+	plan := planFire(sf, schedule, nextRunAt.Add(-time.Second))
+	assert.False(t, plan.fire)
+	assert.True(t, plan.nextRunAt.IsZero())
+
+	// 到达触发时间，下一次触发时间按计划时间顺延
+	plan = planFire(sf, schedule, nextRunAt.Add(10*time.Minute))
+	assert.True(t, plan.fire)
+	assert.False(t, plan.misfired)
+	assert.Equal(t, nextRunAt, plan.runAt)
+	assert.Equal(t, nextRunAt.Add(time.Hour), plan.nextRunAt)
+
+	// 错过触发时间，skip 策略从当前时间重新计算
+	now := nextRunAt.Add(3*time.Hour + 10*time.Minute)
+	plan = planFire(sf, schedule, now)
+	assert.False(t, plan.fire)
+	assert.True(t, plan.misfired)
+	assert.Equal(t, nextRunAt.Add(4*time.Hour), plan.nextRunAt)
+
+	// 错过触发时间，catch_up 策略按计划时间逐个补偿
+	sf.MisfirePolicy = enumor.MisfireCatchUp
+	plan = planFire(sf, schedule, now)
+	assert.True(t, plan.fire)
+	assert.True(t, plan.misfired)
+	assert.Equal(t, nextRunAt, plan.runAt)
+	assert.Equal(t, nextRunAt.Add(time.Hour), plan.nextRunAt)
+
+	// 下一次触发时间为空时，仅计算下一次触发时间
+	plan = planFire(&model.ScheduledFlow{}, schedule, now)
+	assert.False(t, plan.fire)
+	assert.Equal(t, nextRunAt.Add(4*time.Hour), plan.nextRunAt)
+}
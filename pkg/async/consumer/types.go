@@ -22,6 +22,7 @@ package consumer
 import (
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 )
 
@@ -44,6 +45,8 @@ const (
 	ErrTaskNodeShutdown = "task node shutdown"
 	// ErrSomeTaskExecFailed 部分任务执行失败
 	ErrSomeTaskExecFailed = "some tasks failed to be executed"
+	// ErrSomeTaskCompensateFailed 部分任务补偿回滚失败
+	ErrSomeTaskCompensateFailed = "some tasks failed to be compensated"
 
 	//  listScheduledFlowLimit 每次调度器查询分配给当前节点的任务流数量
 	listScheduledFlowLimit = 20
@@ -58,3 +61,12 @@ type Flow struct {
 
 	Kit *kit.Kit `json:"-"`
 }
+
+// failedFlowState 任务流存在失败任务时的目标状态，开启补偿回滚的任务流进入补偿中状态，否则直接失败。
+func failedFlowState(flow model.Flow) enumor.FlowState {
+	if flow.IsCompensate() {
+		return enumor.FlowCompensating
+	}
+
+	return enumor.FlowFailed
+}
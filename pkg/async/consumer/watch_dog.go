@@ -32,6 +32,7 @@ import (
 	"hcm/pkg/async/consumer/leader"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -45,6 +46,8 @@ WatchDog （看门狗）:
  1. 处理超时任务
  2. 处理处于Scheduled状态，但执行节点已经挂掉的任务流
  3. 处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
+ 4. 处理处于Compensating状态，但执行节点已经挂掉的任务流
+ 5. backend需要定期清理过期数据时，清理超过保留时长的终态任务流
 */
 type WatchDog interface {
 	compctrl.Closer
@@ -90,6 +93,12 @@ func (wd *watchDog) Start() {
 	go wd.watchWrapper(wd.handleScheduledNotExistWorkerFlow)
 	wd.wg.Add(1)
 	go wd.watchWrapper(wd.handleRunningNotExistWorkerFlow)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleCompensatingNotExistWorkerFlow)
+	if sweeper, ok := wd.bd.(backend.Sweeper); ok {
+		wd.wg.Add(1)
+		go wd.watchWrapper(sweeper.Sweep)
+	}
 }
 
 // 定期处理异常任务流或任务
@@ -166,10 +175,15 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 			return err
 		}
 
+		state, err := wd.failedFlowStateByID(kt, one.FlowID)
+		if err != nil {
+			return err
+		}
+
 		flows := []model.Flow{
 			{
 				ID:    one.FlowID,
-				State: enumor.FlowFailed,
+				State: state,
 				Reason: &tableasync.Reason{
 					Message: ErrTaskExecTimeout,
 				},
@@ -186,6 +200,25 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 	return nil
 }
 
+// failedFlowStateByID 查询任务流的回滚模式，获取任务流存在失败任务时的目标状态
+func (wd *watchDog) failedFlowStateByID(kt *kit.Kit, flowID string) (enumor.FlowState, error) {
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	flows, err := wd.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return "", err
+	}
+
+	if len(flows) == 0 {
+		return enumor.FlowFailed, nil
+	}
+
+	return failedFlowState(flows[0]), nil
+}
+
 func (wd *watchDog) updateTimeoutTask(kt *kit.Kit, id string) error {
 	task := &model.Task{
 		ID:    id,
@@ -233,6 +266,48 @@ func (wd *watchDog) handleScheduledNotExistWorkerFlow(kt *kit.Kit) error {
 	return nil
 }
 
+// handleCompensatingNotExistWorkerFlow 将处于补偿中【Compensating】且分配的节点已经下线的任务流重新分配到存活节点继续补偿.
+func (wd *watchDog) handleCompensatingNotExistWorkerFlow(kt *kit.Kit) error {
+
+	flows, err := wd.queryNotExistNodesFlowByState(kt, enumor.FlowCompensating)
+	if err != nil {
+		return err
+	}
+
+	if len(flows) == 0 {
+		return nil
+	}
+
+	nodes, err := wd.ld.AliveNodes()
+	if err != nil {
+		logs.Errorf("query alive nodes failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	infos := make([]backend.UpdateFlowInfo, 0, len(flows))
+	ids := make([]string, 0, len(flows))
+	for index, one := range flows {
+		ids = append(ids, one.ID)
+		infos = append(infos, backend.UpdateFlowInfo{
+			ID:     one.ID,
+			Source: enumor.FlowCompensating,
+			Target: enumor.FlowCompensating,
+			Worker: converter.ValToPtr(nodes[index%len(nodes)]),
+		})
+	}
+	if err = wd.bd.BatchUpdateFlowStateByCAS(kt, infos); err != nil {
+		logs.Errorf("update flows failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("handleCompensatingNotExistWorkerFlow success, count: %d, ids: %v, rid: %s", len(ids), ids, kt.Rid)
+
+	return nil
+}
+
 func (wd *watchDog) queryNotExistNodesFlowByState(kt *kit.Kit, state enumor.FlowState) ([]model.Flow, error) {
 	nodes, err := wd.ld.AliveNodes()
 	if err != nil {
@@ -329,7 +404,10 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 
 	// 如果树已经处于结束状态，则直接更新
 	state := root.TreeState()
-	if state == enumor.FlowSuccess || state == enumor.FlowFailed {
+	if state == enumor.FlowFailed {
+		state = failedFlowState(flow)
+	}
+	if state == enumor.FlowSuccess || state == enumor.FlowFailed || state == enumor.FlowCompensating {
 		if err = updateFlowState(kt, wd.bd, flow.ID, enumor.FlowRunning, state); err != nil {
 			logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
 			return err
@@ -109,10 +109,13 @@ func buildCustomFlow(opt *AddCustomFlowOption) *model.Flow {
 	}
 
 	flow := &model.Flow{
-		Name:      opt.Name,
-		ShareData: opt.ShareData,
-		Memo:      opt.Memo,
-		Tasks:     make([]model.Task, 0, len(opt.Tasks)),
+		Name:           opt.Name,
+		ShareData:      opt.ShareData,
+		Memo:           opt.Memo,
+		Tasks:          make([]model.Task, 0, len(opt.Tasks)),
+		RollbackMode:   opt.RollbackMode,
+		Priority:       opt.Priority,
+		ConcurrencyKey: opt.ConcurrencyKey,
 	}
 	if opt.IsInitState {
 		flow.State = enumor.FlowInit
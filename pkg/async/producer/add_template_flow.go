@@ -60,14 +60,20 @@ func (p *producer) AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id
 
 func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow {
 	flow := &model.Flow{
-		Name:      tpl.Name,
-		ShareData: tpl.ShareData,
-		Memo:      opt.Memo,
-		Tasks:     make([]model.Task, 0, len(tpl.Tasks)),
+		Name:           tpl.Name,
+		ShareData:      tpl.ShareData,
+		Memo:           opt.Memo,
+		Tasks:          make([]model.Task, 0, len(tpl.Tasks)),
+		RollbackMode:   tpl.RollbackMode,
+		Priority:       opt.Priority,
+		ConcurrencyKey: opt.ConcurrencyKey,
 	}
 	if opt.IsInitState {
 		flow.State = enumor.FlowInit
 	}
+	if len(opt.RollbackMode) != 0 {
+		flow.RollbackMode = opt.RollbackMode
+	}
 
 	m := make(map[action.ActIDType]types.JsonField, len(opt.Tasks))
 	for _, one := range opt.Tasks {
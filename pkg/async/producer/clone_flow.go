@@ -67,15 +67,18 @@ func (p *producer) CloneFlow(kt *kit.Kit, flowId string, opt *CloneFlowOption) (
 
 func clone(kt *kit.Kit, oldFlow model.Flow, oldTaskList []model.Task, opt *CloneFlowOption) (newFlow *model.Flow) {
 	newFlow = &model.Flow{
-		Name:      oldFlow.Name,
-		ShareData: tableasync.NewShareData(oldFlow.ShareData.GetInitData()),
-		Memo:      oldFlow.Memo,
-		State:     enumor.FlowPending,
-		Reason:    nil,
-		Worker:    nil,
-		Tasks:     make([]model.Task, len(oldTaskList)),
-		Creator:   kt.User,
-		Reviser:   kt.User,
+		Name:           oldFlow.Name,
+		ShareData:      tableasync.NewShareData(oldFlow.ShareData.GetInitData()),
+		Memo:           oldFlow.Memo,
+		State:          enumor.FlowPending,
+		Reason:         nil,
+		Worker:         nil,
+		Tasks:          make([]model.Task, len(oldTaskList)),
+		Creator:        kt.User,
+		Reviser:        kt.User,
+		RollbackMode:   oldFlow.RollbackMode,
+		Priority:       oldFlow.Priority,
+		ConcurrencyKey: oldFlow.ConcurrencyKey,
 	}
 
 	if opt.IsInitState {
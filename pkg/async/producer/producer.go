@@ -36,6 +36,9 @@ type Producer interface {
 	BatchUpdateCustomFlowState(kt *kit.Kit, opt *UpdateCustomFlowStateOption) error
 	RetryFlowTask(kt *kit.Kit, flowID, taskID string) error
 	CloneFlow(kt *kit.Kit, flowId string, opt *CloneFlowOption) (id string, err error)
+	AddScheduledFlow(kt *kit.Kit, opt *AddScheduledFlowOption) (id string, err error)
+	UpdateScheduledFlow(kt *kit.Kit, id string, opt *UpdateScheduledFlowOption) error
+	DeleteScheduledFlow(kt *kit.Kit, id string) error
 }
 
 var _ Producer = new(producer)
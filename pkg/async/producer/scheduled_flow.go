@@ -0,0 +1,201 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/times"
+)
+
+// AddScheduledFlow add scheduled flow, the leader consumer will materialize it into flow by cron.
+func (p *producer) AddScheduledFlow(kt *kit.Kit, opt *AddScheduledFlowOption) (id string, err error) {
+	if err = opt.Validate(); err != nil {
+		return "", err
+	}
+
+	if err = validateScheduledFlowTpl(kt, opt.FlowName, opt.Tasks); err != nil {
+		return "", err
+	}
+
+	nextRunAt, err := NextRunAt(opt.Cron, times.ConvStdTimeNow())
+	if err != nil {
+		return "", err
+	}
+
+	policy := opt.MisfirePolicy
+	if len(policy) == 0 {
+		policy = enumor.MisfireSkip
+	}
+	enable := opt.Enable
+	if enable == nil {
+		enable = converter.ValToPtr(true)
+	}
+
+	flow := &model.ScheduledFlow{
+		Name:          opt.Name,
+		Cron:          opt.Cron,
+		FlowName:      opt.FlowName,
+		Tasks:         convScheduledFlowTasks(opt.Tasks),
+		Memo:          opt.Memo,
+		MisfirePolicy: policy,
+		Enable:        enable,
+		NextRunAt:     nextRunAt,
+	}
+	id, err = p.backend.CreateScheduledFlow(kt, flow)
+	if err != nil {
+		logs.Errorf("create scheduled flow failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// UpdateScheduledFlow update scheduled flow. 修改cron或重新启用时，从当前时间重新计算下一次触发时间。
+func (p *producer) UpdateScheduledFlow(kt *kit.Kit, id string, opt *UpdateScheduledFlowOption) error {
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+
+	flows, err := p.backend.ListScheduledFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+	if len(flows) == 0 {
+		return errf.Newf(errf.RecordNotFound, "scheduled flow: %s not found", id)
+	}
+	origin := flows[0]
+
+	md := &model.ScheduledFlow{
+		ID:            id,
+		Cron:          opt.Cron,
+		Memo:          opt.Memo,
+		MisfirePolicy: opt.MisfirePolicy,
+		Enable:        opt.Enable,
+	}
+
+	if opt.Tasks != nil {
+		if err = validateScheduledFlowTpl(kt, origin.FlowName, opt.Tasks); err != nil {
+			return err
+		}
+		md.Tasks = convScheduledFlowTasks(opt.Tasks)
+	}
+
+	if len(opt.Cron) != 0 || (opt.Enable != nil && *opt.Enable) {
+		cronExpr := origin.Cron
+		if len(opt.Cron) != 0 {
+			cronExpr = opt.Cron
+		}
+		if md.NextRunAt, err = NextRunAt(cronExpr, times.ConvStdTimeNow()); err != nil {
+			return err
+		}
+	}
+
+	if err = p.backend.UpdateScheduledFlow(kt, md); err != nil {
+		logs.Errorf("update scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteScheduledFlow delete scheduled flow, flows already materialized will not be affected.
+func (p *producer) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+	if err := p.backend.DeleteScheduledFlow(kt, id); err != nil {
+		logs.Errorf("delete scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// BuildScheduledFlow 按定时任务流的模版和参数构建一次执行的任务流
+func BuildScheduledFlow(sf *model.ScheduledFlow) (*model.Flow, error) {
+	tpl, exist := action.GetTpl(sf.FlowName)
+	if !exist {
+		return nil, fmt.Errorf("flow tempalte: %s not found", sf.FlowName)
+	}
+
+	opt := &AddTemplateFlowOption{
+		Name:  sf.FlowName,
+		Memo:  sf.Memo,
+		Tasks: make([]TemplateFlowTask, 0, len(sf.Tasks)),
+	}
+	for _, one := range sf.Tasks {
+		opt.Tasks = append(opt.Tasks, TemplateFlowTask{ActionID: action.ActIDType(one.ActionID), Params: one.Params})
+	}
+
+	return buildFlow(tpl, opt), nil
+}
+
+// NextRunAt 计算cron表达式严格晚于after的下一次触发时间，返回HCM标准时间格式
+func NextRunAt(cronExpr string, after time.Time) (string, error) {
+	schedule, err := cron.Parse(cronExpr)
+	if err != nil {
+		return "", err
+	}
+
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return "", fmt.Errorf("cron: %s will never be fired", cronExpr)
+	}
+
+	return times.ConvStdTimeFormat(next), nil
+}
+
+func validateScheduledFlowTpl(kt *kit.Kit, name enumor.FlowName, tasks []TemplateFlowTask) error {
+	tpl, exist := action.GetTpl(name)
+	if !exist {
+		return fmt.Errorf("flow tempalte: %s not found", name)
+	}
+
+	if err := validateTplUseParam(kt, tpl, &AddTemplateFlowOption{Name: name, Tasks: tasks}); err != nil {
+		logs.Errorf("validate scheduled flow template use param failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func convScheduledFlowTasks(tasks []TemplateFlowTask) tableasync.ScheduledFlowTasks {
+	result := make(tableasync.ScheduledFlowTasks, 0, len(tasks))
+	for _, one := range tasks {
+		result = append(result, tableasync.ScheduledFlowTask{ActionID: string(one.ActionID), Params: one.Params})
+	}
+
+	return result
+}
@@ -28,6 +28,7 @@ import (
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/cron"
 )
 
 // AddTemplateFlowOption define add flow option.
@@ -40,6 +41,12 @@ type AddTemplateFlowOption struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// RollbackMode 任务流失败后的回滚模式，不设置时使用任务流模版中定义的回滚模式
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode" validate:"omitempty"`
+	// Priority 任务流派发优先级，默认为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// ConcurrencyKey 并发控制键，如 account:00000001，相同键的任务流同时运行的数量受派发器并发上限控制
+	ConcurrencyKey string `json:"concurrency_key" validate:"omitempty,lte=255"`
 }
 
 // Validate AddTemplateFlowOption
@@ -53,6 +60,18 @@ func (opt *AddTemplateFlowOption) Validate() error {
 		return err
 	}
 
+	if len(opt.RollbackMode) != 0 {
+		if err := opt.RollbackMode.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if opt.Priority != 0 {
+		if err := opt.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	for index := range opt.Tasks {
 		if err := opt.Tasks[index].Validate(); err != nil {
 			return err
@@ -87,6 +106,12 @@ type AddCustomFlowOption struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"required"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// RollbackMode 任务流失败后的回滚模式，默认为 none
+	RollbackMode enumor.FlowRollbackMode `json:"rollback_mode" validate:"omitempty"`
+	// Priority 任务流派发优先级，默认为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// ConcurrencyKey 并发控制键，如 account:00000001，相同键的任务流同时运行的数量受派发器并发上限控制
+	ConcurrencyKey string `json:"concurrency_key" validate:"omitempty,lte=255"`
 }
 
 // Validate AddCustomFlowOption
@@ -96,6 +121,18 @@ func (opt *AddCustomFlowOption) Validate() error {
 		return err
 	}
 
+	if len(opt.RollbackMode) != 0 {
+		if err := opt.RollbackMode.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if opt.Priority != 0 {
+		if err := opt.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if len(opt.Tasks) == 0 {
 		return errors.New("tasks is required")
 	}
@@ -171,3 +208,88 @@ func (opt *CloneFlowOption) Validate() error {
 
 	return validator.Validate.Struct(opt)
 }
+
+// AddScheduledFlowOption define add scheduled flow option.
+type AddScheduledFlowOption struct {
+	// Name 定时任务流名称，全局唯一
+	Name string `json:"name" validate:"required,max=64"`
+	// Cron 标准5段式cron表达式，例如 "0 2 * * *"
+	Cron string `json:"cron" validate:"required,max=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// Memo 备注，同时作为生成的任务流的备注
+	Memo string `json:"memo" validate:"omitempty,max=64"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+	// MisfirePolicy 错过触发时间的处理策略，默认为skip
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	// Enable 是否启用，默认启用
+	Enable *bool `json:"enable" validate:"omitempty"`
+}
+
+// Validate AddScheduledFlowOption
+func (opt *AddScheduledFlowOption) Validate() error {
+
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if err := opt.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := cron.Parse(opt.Cron); err != nil {
+		return err
+	}
+
+	if len(opt.MisfirePolicy) != 0 {
+		if err := opt.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for index := range opt.Tasks {
+		if err := opt.Tasks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateScheduledFlowOption define update scheduled flow option.
+type UpdateScheduledFlowOption struct {
+	Cron          string               `json:"cron" validate:"omitempty,max=64"`
+	Memo          string               `json:"memo" validate:"omitempty,max=64"`
+	Tasks         []TemplateFlowTask   `json:"tasks" validate:"omitempty"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	Enable        *bool                `json:"enable" validate:"omitempty"`
+}
+
+// Validate UpdateScheduledFlowOption
+func (opt *UpdateScheduledFlowOption) Validate() error {
+
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.Cron) != 0 {
+		if _, err := cron.Parse(opt.Cron); err != nil {
+			return err
+		}
+	}
+
+	if len(opt.MisfirePolicy) != 0 {
+		if err := opt.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for index := range opt.Tasks {
+		if err := opt.Tasks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
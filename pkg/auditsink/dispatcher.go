@@ -0,0 +1,265 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auditsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/dal/dao/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+)
+
+const (
+	// cleanInterval 清理已投递发件箱记录的间隔
+	cleanInterval = time.Minute
+	// cleanBatchSize 单次清理的发件箱记录数
+	cleanBatchSize = 1000
+)
+
+// Dispatcher 在主节点上按发件箱id顺序将审计事件投递到各个目标，每个目标独立记录投递进度，
+// 投递成功后才更新进度，失败时按指数退避重试同一批事件，保证至少投递一次且不乱序
+type Dispatcher struct {
+	opt     cc.AuditSink
+	store   audit.OutboxInterface
+	state   serviced.State
+	targets []*target
+}
+
+// target 投递目标及其在当前节点上的投递状态
+type target struct {
+	sink   Sink
+	filter *filter
+
+	lock sync.Mutex
+	// loaded 投递进度是否已从数据库加载，切换为主节点时需要重新加载
+	loaded   bool
+	lastID   uint64
+	attempts uint
+	retryAt  time.Time
+}
+
+// NewDispatcher new audit event dispatcher.
+func NewDispatcher(opt cc.AuditSink, store audit.OutboxInterface, state serviced.State) (*Dispatcher, error) {
+	d := &Dispatcher{opt: opt, store: store, state: state}
+
+	for _, one := range opt.Sinks {
+		sink, err := New(one)
+		if err != nil {
+			return nil, err
+		}
+		d.targets = append(d.targets, &target{sink: sink, filter: newFilter(one.Filter)})
+	}
+
+	return d, nil
+}
+
+// Run 为每个投递目标启动投递协程，并启动发件箱清理协程，ctx 取消后退出
+func (d *Dispatcher) Run(ctx context.Context) {
+	for _, t := range d.targets {
+		go d.loop(ctx, t)
+	}
+
+	go d.cleanLoop(ctx)
+}
+
+func (d *Dispatcher) loop(ctx context.Context, t *target) {
+	defer t.sink.Close()
+
+	interval := time.Duration(d.opt.IntervalSec) * time.Second
+	for {
+		busy := false
+		if d.state.IsMaster() {
+			kt := core.NewBackendKit()
+			var err error
+			if busy, err = d.dispatch(kt, t); err != nil {
+				logs.Errorf("dispatch audit events to sink %s failed, err: %v, rid: %s", t.sink.Name(), err, kt.Rid)
+			}
+		} else {
+			t.unload()
+		}
+
+		if busy {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// dispatch 投递一批事件，返回发件箱中是否可能还有待投递的事件
+func (d *Dispatcher) dispatch(kt *kit.Kit, t *target) (bool, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.loaded {
+		cursor, err := d.store.GetSinkCursor(kt, t.sink.Name())
+		if err != nil {
+			return false, err
+		}
+		t.loaded, t.lastID, t.attempts = true, cursor.LastID, cursor.Attempts
+		t.retryAt = time.Time{}
+	}
+
+	if time.Now().Before(t.retryAt) {
+		return false, nil
+	}
+
+	list, err := d.store.ListOutbox(kt, t.lastID, d.opt.BatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	if len(list) == 0 {
+		return false, nil
+	}
+
+	events := make([]*Event, 0, len(list))
+	for _, one := range list {
+		if !t.filter.match(one.ResType, one.Action) {
+			continue
+		}
+
+		event, err := NewEvent(one)
+		if err != nil {
+			return false, err
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 0 {
+		if err = t.sink.Send(kt.Ctx, events); err != nil {
+			return false, d.fail(kt, t, err)
+		}
+	}
+
+	lastID := list[len(list)-1].ID
+	if err = d.store.AdvanceSinkCursor(kt, t.sink.Name(), lastID); err != nil {
+		// 进度更新失败时重新加载进度，这批事件会被再次投递
+		t.loaded = false
+		return false, err
+	}
+	t.lastID, t.attempts, t.retryAt = lastID, 0, time.Time{}
+
+	return uint(len(list)) == d.opt.BatchSize, nil
+}
+
+// fail 记录投递失败，下次按指数退避后重试同一批事件
+func (d *Dispatcher) fail(kt *kit.Kit, t *target, sendErr error) error {
+	t.attempts++
+	t.retryAt = time.Now().Add(d.backoff(t.attempts))
+
+	if err := d.store.FailSinkCursor(kt, t.sink.Name(), t.attempts, sendErr.Error()); err != nil {
+		logs.Errorf("record audit sink %s failure failed, err: %v, rid: %s", t.sink.Name(), err, kt.Rid)
+	}
+
+	return fmt.Errorf("send %d attempts failed, retry at %s, err: %v", t.attempts,
+		t.retryAt.Format(time.RFC3339), sendErr)
+}
+
+// backoff 第n次失败后的重试等待时间
+func (d *Dispatcher) backoff(attempts uint) time.Duration {
+	wait := time.Duration(d.opt.RetryBaseSec) * time.Second
+	max := time.Duration(d.opt.RetryMaxSec) * time.Second
+	for i := uint(1); i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+
+	if wait > max {
+		wait = max
+	}
+
+	return wait
+}
+
+func (t *target) unload() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.loaded = false
+}
+
+// delivered 返回投递目标已投递的最后一个发件箱记录id，进度未加载时返回false
+func (t *target) delivered() (uint64, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.lastID, t.loaded
+}
+
+func (d *Dispatcher) cleanLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cleanInterval):
+		}
+
+		if !d.state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := d.clean(kt); err != nil {
+			logs.Errorf("clean audit outbox failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+// clean 删除所有投递目标都已投递的发件箱记录
+func (d *Dispatcher) clean(kt *kit.Kit) error {
+	if len(d.targets) == 0 {
+		return errors.New("no audit sink configured")
+	}
+
+	var maxID uint64
+	for i, t := range d.targets {
+		lastID, loaded := t.delivered()
+		if !loaded {
+			return nil
+		}
+
+		if i == 0 || lastID < maxID {
+			maxID = lastID
+		}
+	}
+
+	for {
+		deleted, err := d.store.CleanOutbox(kt, maxID, cleanBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if deleted < cleanBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auditsink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/audit"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+)
+
+// memoryOutbox 内存中的发件箱，用于测试投递流程
+type memoryOutbox struct {
+	lock    sync.Mutex
+	outbox  []tableaudit.AuditOutboxTable
+	cursors map[string]*tableaudit.AuditSinkCursorTable
+}
+
+var _ audit.OutboxInterface = new(memoryOutbox)
+
+func newMemoryOutbox(audits ...tableaudit.AuditTable) *memoryOutbox {
+	m := &memoryOutbox{cursors: make(map[string]*tableaudit.AuditSinkCursorTable)}
+	for i, one := range audits {
+		one.ChainKey, one.ChainSeq = "2024-12-20", uint64(i+1)
+		payload, _ := json.Marshal(one)
+		m.outbox = append(m.outbox, tableaudit.AuditOutboxTable{
+			ID:        uint64(i + 1),
+			ResType:   one.ResType,
+			Action:    one.Action,
+			Payload:   types.JsonField(payload),
+			CreatedAt: "2024-12-20T10:00:00+08:00",
+		})
+	}
+	return m
+}
+
+func (m *memoryOutbox) ListOutbox(_ *kit.Kit, afterID uint64, limit uint) ([]tableaudit.AuditOutboxTable, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	list := make([]tableaudit.AuditOutboxTable, 0)
+	for _, one := range m.outbox {
+		if one.ID > afterID && uint(len(list)) < limit {
+			list = append(list, one)
+		}
+	}
+	return list, nil
+}
+
+func (m *memoryOutbox) GetSinkCursor(_ *kit.Kit, sink string) (*tableaudit.AuditSinkCursorTable, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, exist := m.cursors[sink]; !exist {
+		m.cursors[sink] = &tableaudit.AuditSinkCursorTable{Sink: sink}
+	}
+	cursor := *m.cursors[sink]
+	return &cursor, nil
+}
+
+func (m *memoryOutbox) AdvanceSinkCursor(_ *kit.Kit, sink string, lastID uint64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cursors[sink].LastID, m.cursors[sink].Attempts, m.cursors[sink].LastError = lastID, 0, ""
+	return nil
+}
+
+func (m *memoryOutbox) FailSinkCursor(_ *kit.Kit, sink string, attempts uint, reason string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cursors[sink].Attempts, m.cursors[sink].LastError = attempts, reason
+	return nil
+}
+
+func (m *memoryOutbox) CleanOutbox(_ *kit.Kit, maxID uint64, _ uint) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	remain := make([]tableaudit.AuditOutboxTable, 0)
+	for _, one := range m.outbox {
+		if one.ID > maxID {
+			remain = append(remain, one)
+		}
+	}
+	deleted := len(m.outbox) - len(remain)
+	m.outbox = remain
+	return int64(deleted), nil
+}
+
+type masterState struct{}
+
+func (masterState) IsMaster() bool { return true }
+
+func (masterState) DisableMasterSlave(bool) {}
+
+func testAudits() []tableaudit.AuditTable {
+	return []tableaudit.AuditTable{
+		{ResID: "cvm-1", ResType: enumor.CvmAuditResType, Action: enumor.Delete, Operator: "admin"},
+		{ResID: "disk-1", ResType: enumor.DiskAuditResType, Action: enumor.Delete, Operator: "admin"},
+		{ResID: "cvm-2", ResType: enumor.CvmAuditResType, Action: enumor.Update, Operator: "admin",
+			Detail: &tableaudit.BasicDetail{Changed: map[string]interface{}{"name": "cvm-2"}}},
+	}
+}
+
+func TestDispatchWebhookRetry(t *testing.T) {
+	var lock sync.Mutex
+	requests := 0
+	received := make([]*Event, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(WebhookSignatureHeader) != WebhookSignature("secret",
+			r.Header.Get(WebhookTimestampHeader), body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// 第一次请求失败，验证失败后重试同一批事件
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		req := new(WebhookBody)
+		if err := json.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, req.Events...)
+	}))
+	defer server.Close()
+
+	store := newMemoryOutbox(testAudits()...)
+	opt := cc.AuditSink{
+		Sinks: []cc.AuditSinkTarget{{
+			Name:    "webhook",
+			Type:    cc.WebhookAuditSink,
+			Filter:  cc.AuditSinkFilter{ResTypes: []enumor.AuditResourceType{enumor.CvmAuditResType}},
+			Webhook: cc.WebhookSink{URL: server.URL, Secret: "secret", TimeoutSec: 5},
+		}},
+		BatchSize:    10,
+		RetryBaseSec: 1,
+		RetryMaxSec:  1,
+	}
+	dispatcher, err := NewDispatcher(opt, store, masterState{})
+	if err != nil {
+		t.Fatalf("new dispatcher failed, err: %v", err)
+	}
+	target := dispatcher.targets[0]
+
+	kt := core.NewBackendKit()
+	if _, err = dispatcher.dispatch(kt, target); err == nil {
+		t.Fatalf("first dispatch should fail")
+	}
+	if cursor := store.cursors["webhook"]; cursor.LastID != 0 || cursor.Attempts != 1 {
+		t.Fatalf("cursor should not advance after failure, got: %+v", cursor)
+	}
+
+	// 退避时间内不重试
+	if _, err = dispatcher.dispatch(kt, target); err != nil || requests != 1 {
+		t.Fatalf("dispatch should wait for backoff, err: %v, requests: %d", err, requests)
+	}
+
+	target.retryAt = time.Now()
+	if _, err = dispatcher.dispatch(kt, target); err != nil {
+		t.Fatalf("retry dispatch failed, err: %v", err)
+	}
+
+	if len(received) != 2 || received[0].ResID != "cvm-1" || received[1].ResID != "cvm-2" {
+		t.Fatalf("unexpected received events: %+v", received)
+	}
+	if received[1].ID != "2024-12-20-3" || received[1].Detail == nil {
+		t.Errorf("unexpected event: %+v", received[1])
+	}
+	if cursor := store.cursors["webhook"]; cursor.LastID != 3 || cursor.Attempts != 0 {
+		t.Errorf("cursor should advance to the last outbox record, got: %+v", cursor)
+	}
+
+	if err = dispatcher.clean(kt); err != nil || len(store.outbox) != 0 {
+		t.Errorf("delivered outbox should be cleaned, err: %v, remain: %d", err, len(store.outbox))
+	}
+}
+
+func TestDispatchSyslog(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed, err: %v", err)
+	}
+	defer listener.Close()
+
+	frames := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			// octet counting: "<长度> <消息>"
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			msg := make([]byte, size)
+			if _, err = io.ReadFull(reader, msg); err != nil {
+				return
+			}
+			frames <- string(msg)
+		}
+	}()
+
+	store := newMemoryOutbox(testAudits()...)
+	opt := cc.AuditSink{
+		Sinks: []cc.AuditSinkTarget{{
+			Name:   "syslog",
+			Type:   cc.SyslogAuditSink,
+			Filter: cc.AuditSinkFilter{Actions: []enumor.AuditAction{enumor.Delete}},
+			Syslog: cc.SyslogSink{Network: "tcp", Address: listener.Addr().String(), AppName: "hcm", TimeoutSec: 5},
+		}},
+		BatchSize:    2,
+		RetryBaseSec: 1,
+		RetryMaxSec:  1,
+	}
+	dispatcher, err := NewDispatcher(opt, store, masterState{})
+	if err != nil {
+		t.Fatalf("new dispatcher failed, err: %v", err)
+	}
+	defer dispatcher.targets[0].sink.Close()
+
+	kt := core.NewBackendKit()
+	busy, err := dispatcher.dispatch(kt, dispatcher.targets[0])
+	if err != nil || !busy {
+		t.Fatalf("dispatch first batch failed, busy: %v, err: %v", busy, err)
+	}
+	if _, err = dispatcher.dispatch(kt, dispatcher.targets[0]); err != nil {
+		t.Fatalf("dispatch second batch failed, err: %v", err)
+	}
+
+	for _, resID := range []string{"cvm-1", "disk-1"} {
+		select {
+		case frame := <-frames:
+			prefix := fmt.Sprintf("<%d>1 2024-12-20T10:00:00+08:00 ", syslogPriority)
+			if !strings.HasPrefix(frame, prefix) || !strings.Contains(frame, " hcm - delete - {") ||
+				!strings.Contains(frame, `"res_id":"`+resID+`"`) {
+				t.Errorf("unexpected syslog frame: %s", frame)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("syslog frame of %s not received", resID)
+		}
+	}
+
+	if cursor := store.cursors["syslog"]; cursor.LastID != 3 {
+		t.Errorf("cursor should advance to the last outbox record, got: %+v", cursor)
+	}
+}
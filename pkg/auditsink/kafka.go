@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/tools/ssl"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// kafkaSink 投递到 kafka topic，消息key为资源id，保证同一个资源的审计事件在同一个分区内有序
+type kafkaSink struct {
+	name   string
+	writer *kafka.Writer
+}
+
+func newKafkaSink(name string, opt cc.KafkaSink) (*kafkaSink, error) {
+	transport := &kafka.Transport{DialTimeout: time.Duration(opt.TimeoutSec) * time.Second}
+
+	if opt.TLS.Enable() {
+		tlsConf, err := ssl.ClientTLSConfVerify(opt.TLS.InsecureSkipVerify, opt.TLS.CAFile, opt.TLS.CertFile,
+			opt.TLS.KeyFile, opt.TLS.Password)
+		if err != nil {
+			return nil, fmt.Errorf("init kafka sink %s tls config failed, err: %v", name, err)
+		}
+		transport.TLS = tlsConf
+	}
+
+	if len(opt.User) != 0 {
+		transport.SASL = plain.Mechanism{Username: opt.User, Password: opt.Password}
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(opt.Brokers...),
+		Topic:        opt.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// 同步写入，事件由调用方批量传入，不需要等待攒批
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: time.Duration(opt.TimeoutSec) * time.Second,
+		ReadTimeout:  time.Duration(opt.TimeoutSec) * time.Second,
+		Transport:    transport,
+	}
+
+	return &kafkaSink{name: name, writer: writer}, nil
+}
+
+// Name return sink name.
+func (k *kafkaSink) Name() string {
+	return k.name
+}
+
+// Send 同步写入所有事件，所有副本确认后返回
+func (k *kafkaSink) Send(ctx context.Context, events []*Event) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal audit event %s failed, err: %v", event.ID, err)
+		}
+
+		messages = append(messages, kafka.Message{Key: []byte(event.ResID), Value: value})
+	}
+
+	if err := k.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("write kafka topic %s failed, err: %v", k.writer.Topic, err)
+	}
+
+	return nil
+}
+
+// Close the kafka writer.
+func (k *kafkaSink) Close() error {
+	return k.writer.Close()
+}
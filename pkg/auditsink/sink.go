@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package auditsink 审计事件投递，将发件箱中的审计记录投递到 syslog、kafka、webhook 等外部目标
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/audit"
+)
+
+// Sink 审计事件投递目标，Send 返回成功表示所有事件都已被目标接收
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+// New 根据配置创建投递目标
+func New(opt cc.AuditSinkTarget) (Sink, error) {
+	switch opt.Type {
+	case cc.SyslogAuditSink:
+		return newSyslogSink(opt.Name, opt.Syslog)
+	case cc.KafkaAuditSink:
+		return newKafkaSink(opt.Name, opt.Kafka)
+	case cc.WebhookAuditSink:
+		return newWebhookSink(opt.Name, opt.Webhook)
+	default:
+		return nil, fmt.Errorf("unsupported audit sink type: %s", opt.Type)
+	}
+}
+
+// Event 投递到外部目标的审计事件
+type Event struct {
+	// ID 事件唯一标识，由审计记录所在的哈希链和序号组成，重复投递时保持不变，接收方可据此去重
+	ID         string                   `json:"id"`
+	ResID      string                   `json:"res_id"`
+	CloudResID string                   `json:"cloud_res_id"`
+	ResName    string                   `json:"res_name"`
+	ResType    enumor.AuditResourceType `json:"res_type"`
+	Action     enumor.AuditAction       `json:"action"`
+	BkBizID    int64                    `json:"bk_biz_id"`
+	Vendor     enumor.Vendor            `json:"vendor"`
+	AccountID  string                   `json:"account_id"`
+	Operator   string                   `json:"operator"`
+	Source     enumor.RequestSourceType `json:"source"`
+	Rid        string                   `json:"rid"`
+	AppCode    string                   `json:"app_code"`
+	Detail     *audit.BasicDetail       `json:"detail"`
+	ChainKey   string                   `json:"chain_key"`
+	ChainSeq   uint64                   `json:"chain_seq"`
+	Hash       string                   `json:"hash"`
+	CreatedAt  string                   `json:"created_at"`
+}
+
+// NewEvent 将发件箱记录转换为审计事件
+func NewEvent(one audit.AuditOutboxTable) (*Event, error) {
+	record := new(audit.AuditTable)
+	if err := json.Unmarshal([]byte(one.Payload), record); err != nil {
+		return nil, fmt.Errorf("unmarshal audit outbox %d payload failed, err: %v", one.ID, err)
+	}
+
+	event := &Event{
+		ID:         fmt.Sprintf("%s-%d", record.ChainKey, record.ChainSeq),
+		ResID:      record.ResID,
+		CloudResID: record.CloudResID,
+		ResName:    record.ResName,
+		ResType:    record.ResType,
+		Action:     record.Action,
+		BkBizID:    record.BkBizID,
+		Vendor:     record.Vendor,
+		AccountID:  record.AccountID,
+		Operator:   record.Operator,
+		Source:     record.Source,
+		Rid:        record.Rid,
+		AppCode:    record.AppCode,
+		Detail:     record.Detail,
+		ChainKey:   record.ChainKey,
+		ChainSeq:   record.ChainSeq,
+		Hash:       record.Hash,
+		CreatedAt:  string(one.CreatedAt),
+	}
+	return event, nil
+}
+
+// filter 投递目标的审计事件过滤条件
+type filter struct {
+	resTypes map[enumor.AuditResourceType]struct{}
+	actions  map[enumor.AuditAction]struct{}
+}
+
+func newFilter(opt cc.AuditSinkFilter) *filter {
+	f := &filter{
+		resTypes: make(map[enumor.AuditResourceType]struct{}, len(opt.ResTypes)),
+		actions:  make(map[enumor.AuditAction]struct{}, len(opt.Actions)),
+	}
+	for _, resType := range opt.ResTypes {
+		f.resTypes[resType] = struct{}{}
+	}
+	for _, action := range opt.Actions {
+		f.actions[action] = struct{}{}
+	}
+
+	return f
+}
+
+// match 资源类型和动作都满足条件时投递，条件为空时不过滤
+func (f *filter) match(resType enumor.AuditResourceType, action enumor.AuditAction) bool {
+	if len(f.resTypes) != 0 {
+		if _, exist := f.resTypes[resType]; !exist {
+			return false
+		}
+	}
+
+	if len(f.actions) != 0 {
+		if _, exist := f.actions[action]; !exist {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/tools/ssl"
+)
+
+const (
+	// syslogPriority facility 为 log audit(13)，severity 为 notice(5)
+	syslogPriority = 13*8 + 5
+	// syslogNilValue RFC5424 中的空值
+	syslogNilValue = "-"
+)
+
+// syslogSink 通过 TCP 或 TLS 投递 RFC5424 syslog，使用 RFC6587 octet counting 分帧，连接出错后下次投递时重连
+type syslogSink struct {
+	name     string
+	opt      cc.SyslogSink
+	tlsConf  *tls.Config
+	hostname string
+
+	lock sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogSink(name string, opt cc.SyslogSink) (*syslogSink, error) {
+	s := &syslogSink{name: name, opt: opt, hostname: syslogNilValue}
+
+	if opt.Network == "tls" {
+		tlsConf, err := ssl.ClientTLSConfVerify(opt.TLS.InsecureSkipVerify, opt.TLS.CAFile, opt.TLS.CertFile,
+			opt.TLS.KeyFile, opt.TLS.Password)
+		if err != nil {
+			return nil, fmt.Errorf("init syslog sink %s tls config failed, err: %v", name, err)
+		}
+		s.tlsConf = tlsConf
+	}
+
+	if hostname, err := os.Hostname(); err == nil && len(hostname) != 0 {
+		s.hostname = hostname
+	}
+
+	return s, nil
+}
+
+// Name return sink name.
+func (s *syslogSink) Name() string {
+	return s.name
+}
+
+// Send 按顺序写入所有事件，写入失败时关闭连接，由调用方重试
+func (s *syslogSink) Send(ctx context.Context, events []*Event) error {
+	messages := make([]string, 0, len(events))
+	for _, event := range events {
+		msg, err := s.format(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, msg)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("connect to syslog %s failed, err: %v", s.opt.Address, err)
+		}
+		s.conn = conn
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(time.Duration(s.opt.TimeoutSec) * time.Second)); err != nil {
+		s.closeConn()
+		return err
+	}
+
+	writer := bufio.NewWriter(s.conn)
+	for _, msg := range messages {
+		if _, err := fmt.Fprintf(writer, "%d %s", len(msg), msg); err != nil {
+			s.closeConn()
+			return fmt.Errorf("write syslog failed, err: %v", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		s.closeConn()
+		return fmt.Errorf("write syslog failed, err: %v", err)
+	}
+
+	return nil
+}
+
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: time.Duration(s.opt.TimeoutSec) * time.Second}
+	if s.tlsConf != nil {
+		return (&tls.Dialer{NetDialer: dialer, Config: s.tlsConf}).DialContext(ctx, "tcp", s.opt.Address)
+	}
+
+	return dialer.DialContext(ctx, "tcp", s.opt.Address)
+}
+
+// format 生成 RFC5424 消息，MSGID 为审计动作，MSG 为审计事件的json
+func (s *syslogSink) format(event *Event) (string, error) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit event %s failed, err: %v", event.ID, err)
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s", syslogPriority, syslogHeaderValue(event.CreatedAt),
+		syslogHeaderValue(s.hostname), syslogHeaderValue(s.opt.AppName), syslogNilValue,
+		syslogHeaderValue(string(event.Action)), syslogNilValue, msg), nil
+}
+
+// syslogHeaderValue RFC5424 头部字段不能为空，也不能包含空白字符
+func syslogHeaderValue(value string) string {
+	if len(value) == 0 {
+		return syslogNilValue
+	}
+
+	return strings.Join(strings.Fields(value), "_")
+}
+
+func (s *syslogSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close the syslog connection.
+func (s *syslogSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closeConn()
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/tools/ssl"
+)
+
+const (
+	// WebhookTimestampHeader webhook 请求的时间戳，unix 秒
+	WebhookTimestampHeader = "X-HCM-Timestamp"
+	// WebhookSignatureHeader webhook 请求的签名，格式为 sha256=<hex>
+	WebhookSignatureHeader = "X-HCM-Signature"
+)
+
+// WebhookBody webhook 请求体
+type WebhookBody struct {
+	Events []*Event `json:"events"`
+}
+
+// WebhookSignature 计算 webhook 签名，签名内容为 "<时间戳>.<请求体>"，接收方可以据此校验请求来源并拒绝过期请求
+func WebhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookSink 通过 HTTP POST 投递审计事件，返回 2xx 表示接收成功
+type webhookSink struct {
+	name   string
+	opt    cc.WebhookSink
+	client *http.Client
+}
+
+func newWebhookSink(name string, opt cc.WebhookSink) (*webhookSink, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opt.TLS.Enable() || opt.TLS.InsecureSkipVerify {
+		tlsConf, err := ssl.ClientTLSConfVerify(opt.TLS.InsecureSkipVerify, opt.TLS.CAFile, opt.TLS.CertFile,
+			opt.TLS.KeyFile, opt.TLS.Password)
+		if err != nil {
+			return nil, fmt.Errorf("init webhook sink %s tls config failed, err: %v", name, err)
+		}
+		transport.TLSClientConfig = tlsConf
+	}
+
+	return &webhookSink{
+		name: name,
+		opt:  opt,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(opt.TimeoutSec) * time.Second,
+		},
+	}, nil
+}
+
+// Name return sink name.
+func (w *webhookSink) Name() string {
+	return w.name
+}
+
+// Send 一次请求投递所有事件
+func (w *webhookSink) Send(ctx context.Context, events []*Event) error {
+	body, err := json.Marshal(&WebhookBody{Events: events})
+	if err != nil {
+		return fmt.Errorf("marshal webhook body failed, err: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opt.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new webhook request failed, err: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(w.opt.Secret) != 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(w.opt.Secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request webhook failed, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook response status: %d, body: %s", resp.StatusCode, msg)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close the idle connections.
+func (w *webhookSink) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
@@ -24,6 +24,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"hcm/pkg/criteria/enumor"
 )
 
 var (
@@ -80,6 +82,7 @@ type ApiServerSetting struct {
 	Network Network   `yaml:"network"`
 	Service Service   `yaml:"service"`
 	Log     LogOption `yaml:"log"`
+	Tracing Tracing   `yaml:"tracing"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -92,6 +95,7 @@ func (s *ApiServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 
 	return
 }
@@ -103,6 +107,10 @@ func (s ApiServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -115,6 +123,7 @@ type CloudServerSetting struct {
 	Network        Network        `yaml:"network"`
 	Service        Service        `yaml:"service"`
 	Log            LogOption      `yaml:"log"`
+	Tracing        Tracing        `yaml:"tracing"`
 	Crypto         Crypto         `yaml:"crypto"`
 	Esb            Esb            `yaml:"esb"`
 	BkHcmUrl       string         `yaml:"bkHcmUrl"`
@@ -122,6 +131,7 @@ type CloudServerSetting struct {
 	Recycle        Recycle        `yaml:"recycle"`
 	BillConfig     BillConfig     `yaml:"billConfig"`
 	Itsm           ApiGateway     `yaml:"itsm"`
+	Approval       Approval       `yaml:"approval"`
 	CloudSelection CloudSelection `yaml:"cloudSelection"`
 	Cmsi           CMSI           `yaml:"cmsi"`
 }
@@ -136,6 +146,8 @@ func (s *CloudServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
+	s.Approval.trySetDefault()
 
 	return
 }
@@ -147,6 +159,10 @@ func (s CloudServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -170,10 +186,21 @@ func (s CloudServerSetting) Validate() error {
 		return err
 	}
 
-	if err := s.Itsm.validate(); err != nil {
+	if err := s.BillConfig.validate(); err != nil {
+		return err
+	}
+
+	if err := s.Approval.validate(); err != nil {
 		return err
 	}
 
+	// 使用内置审批引擎时可以不部署ITSM
+	if s.Approval.Engine != enumor.NativeApprovalEngine {
+		if err := s.Itsm.validate(); err != nil {
+			return err
+		}
+	}
+
 	if err := s.Cmsi.validate(); err != nil {
 		return err
 	}
@@ -183,13 +210,16 @@ func (s CloudServerSetting) Validate() error {
 
 // DataServiceSetting defines data service used setting options.
 type DataServiceSetting struct {
-	Network     Network     `yaml:"network"`
-	Service     Service     `yaml:"service"`
-	Log         LogOption   `yaml:"log"`
-	Database    DataBase    `yaml:"database"`
-	Objectstore ObjectStore `yaml:"objectstore"`
-	Crypto      Crypto      `yaml:"crypto"`
-	Esb         Esb         `yaml:"esb"`
+	Network      Network      `yaml:"network"`
+	Service      Service      `yaml:"service"`
+	Log          LogOption    `yaml:"log"`
+	Tracing      Tracing      `yaml:"tracing"`
+	Database     DataBase     `yaml:"database"`
+	Objectstore  ObjectStore  `yaml:"objectstore"`
+	Crypto       Crypto       `yaml:"crypto"`
+	Esb          Esb          `yaml:"esb"`
+	AuditSink    AuditSink    `yaml:"auditSink"`
+	AuditArchive AuditArchive `yaml:"auditArchive"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -202,7 +232,11 @@ func (s *DataServiceSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 	s.Database.trySetDefault()
+	s.AuditSink.trySetDefault()
+	s.AuditArchive.trySetDefault()
+	s.Database.AuditOutbox = s.AuditSink.Enable()
 
 	return
 }
@@ -213,6 +247,10 @@ func (s DataServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -229,6 +267,14 @@ func (s DataServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.AuditSink.validate(); err != nil {
+		return err
+	}
+
+	if err := s.AuditArchive.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -236,6 +282,8 @@ func (s DataServiceSetting) Validate() error {
 type SyncConfig struct {
 	// 腾讯云监听器同步并发数
 	TCloudLoadBalancerListenerSyncConcurrency uint `yaml:"tcloudLblConcurrency"`
+	// DeleteThreshold 基于通用同步器的全量同步删除比例阈值(百分比，0-100)，同步请求未指定阈值时使用，为0时不限制
+	DeleteThreshold float64 `yaml:"deleteThreshold"`
 }
 
 func (s *SyncConfig) trySetDefault() {
@@ -244,12 +292,25 @@ func (s *SyncConfig) trySetDefault() {
 	}
 }
 
+func (s SyncConfig) validate() error {
+	if s.DeleteThreshold < 0 || s.DeleteThreshold > 100 {
+		return fmt.Errorf("sync.deleteThreshold %v is invalid, should be in [0, 100]", s.DeleteThreshold)
+	}
+
+	return nil
+}
+
 // HCServiceSetting defines hc service used setting options.
 type HCServiceSetting struct {
 	Network    Network    `yaml:"network"`
 	Service    Service    `yaml:"service"`
 	Log        LogOption  `yaml:"log"`
+	Tracing    Tracing    `yaml:"tracing"`
 	SyncConfig SyncConfig `yaml:"sync"`
+	// CloudRateLimit 调用云API的限流配置
+	CloudRateLimit CloudRateLimit `yaml:"cloudRateLimit"`
+	// AssumeRoleSource 角色扮演使用的源身份密钥
+	AssumeRoleSource AssumeRoleSource `yaml:"assumeRoleSource"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -262,7 +323,9 @@ func (s *HCServiceSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 	s.SyncConfig.trySetDefault()
+	s.CloudRateLimit.trySetDefault()
 
 	return
 }
@@ -274,10 +337,26 @@ func (s HCServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
 
+	if err := s.SyncConfig.validate(); err != nil {
+		return err
+	}
+
+	if err := s.CloudRateLimit.validate(); err != nil {
+		return err
+	}
+
+	if err := s.AssumeRoleSource.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -286,6 +365,7 @@ type AuthServerSetting struct {
 	Network Network   `yaml:"network"`
 	Service Service   `yaml:"service"`
 	Log     LogOption `yaml:"log"`
+	Tracing Tracing   `yaml:"tracing"`
 	Esb     Esb       `yaml:"esb"`
 
 	IAM IAM `yaml:"iam"`
@@ -301,6 +381,7 @@ func (s *AuthServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 
 	return
 }
@@ -311,6 +392,10 @@ func (s AuthServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -331,6 +416,7 @@ type WebServerSetting struct {
 	Network       Network       `yaml:"network"`
 	Service       Service       `yaml:"service"`
 	Log           LogOption     `yaml:"log"`
+	Tracing       Tracing       `yaml:"tracing"`
 	Web           Web           `yaml:"web"`
 	Esb           Esb           `yaml:"esb"`
 	Itsm          ApiGateway    `yaml:"itsm"`
@@ -349,6 +435,7 @@ func (s *WebServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 	s.ChangeLogPath.trySetDefault()
 	if len(s.TemplatePath) == 0 {
 		s.TemplatePath = "template"
@@ -364,6 +451,10 @@ func (s WebServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -393,6 +484,7 @@ type TaskServerSetting struct {
 	Service  Service   `yaml:"service"`
 	Database DataBase  `yaml:"database"`
 	Log      LogOption `yaml:"log"`
+	Tracing  Tracing   `yaml:"tracing"`
 	Async    Async     `yaml:"async"`
 }
 
@@ -407,6 +499,8 @@ func (s *TaskServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Database.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
+	s.Async.trySetDefault()
 
 	return
 }
@@ -418,6 +512,10 @@ func (s TaskServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -435,9 +533,12 @@ type AccountServerSetting struct {
 	Service        Service              `yaml:"service"`
 	Controller     BillControllerOption `yaml:"controller"`
 	Log            LogOption            `yaml:"log"`
+	Tracing        Tracing              `yaml:"tracing"`
 	BillAllocation BillAllocationOption `yaml:"billAllocation"`
 	Esb            Esb                  `yaml:"esb"`
 	TmpFileDir     string               `yaml:"tmpFileDir"`
+	// Cmsi 预算告警的邮件通知配置，未配置时只记录日志
+	Cmsi CMSI `yaml:"cmsi"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -451,6 +552,7 @@ func (s *AccountServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Controller.trySetDefault()
 	s.Log.trySetDefault()
+	s.Tracing.trySetDefault()
 	if s.TmpFileDir == "" {
 		s.TmpFileDir = "/tmp"
 	}
@@ -463,6 +565,10 @@ func (s AccountServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Tracing.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Service.validate(); err != nil {
 		return err
 	}
@@ -471,6 +577,12 @@ func (s AccountServerSetting) Validate() error {
 		return err
 	}
 
+	if len(s.Cmsi.Endpoints) != 0 {
+		if err := s.Cmsi.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -25,11 +25,15 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/cryptography"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/cron"
 	"hcm/pkg/tools/ssl"
+	"hcm/pkg/tracing"
 	"hcm/pkg/version"
 
 	etcd3 "go.etcd.io/etcd/client/v3"
@@ -159,10 +163,18 @@ func (lm *Limiter) trySetDefault() {
 
 // Async defines async relating.
 type Async struct {
-	Scheduler  Parser     `yaml:"scheduler"`
-	Executor   Executor   `yaml:"executor"`
-	Dispatcher Dispatcher `yaml:"dispatcher"`
-	WatchDog   WatchDog   `yaml:"watchDog"`
+	Scheduler  Parser       `yaml:"scheduler"`
+	Executor   Executor     `yaml:"executor"`
+	Dispatcher Dispatcher   `yaml:"dispatcher"`
+	WatchDog   WatchDog     `yaml:"watchDog"`
+	Trigger    Trigger      `yaml:"trigger"`
+	Backend    AsyncBackend `yaml:"backend"`
+}
+
+// trySetDefault set the Async default value if user not configured.
+func (a *Async) trySetDefault() {
+	a.Trigger.trySetDefault()
+	a.Backend.trySetDefault()
 }
 
 // Validate Async
@@ -186,6 +198,10 @@ type Executor struct {
 // Dispatcher 主节点组件，负责派发任务
 type Dispatcher struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+	// DefaultConcurrencyLimit 相同并发控制键的任务流同时运行的默认上限，0 表示不限制
+	DefaultConcurrencyLimit uint `yaml:"defaultConcurrencyLimit"`
+	// ConcurrencyLimits 按并发控制键类型（account、region、lb）设置的并发上限
+	ConcurrencyLimits map[string]uint `yaml:"concurrencyLimits"`
 }
 
 // WatchDog 主节点组件，负责异常任务修正（超时任务，任务处理节点已经挂掉的任务等）
@@ -194,6 +210,496 @@ type WatchDog struct {
 	TaskTimeoutSec   uint `yaml:"taskTimeoutSec"`
 }
 
+// Trigger 主节点组件，负责按cron表达式将定时任务流生成任务流
+type Trigger struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+}
+
+// trySetDefault set the Trigger default value if user not configured.
+func (t *Trigger) trySetDefault() {
+	if t.WatchIntervalSec == 0 {
+		t.WatchIntervalSec = 10
+	}
+}
+
+// AsyncBackend 异步任务框架存储flow、task的后端
+type AsyncBackend struct {
+	// Type 后端类型，支持 mysql、etcd，默认 mysql
+	Type enumor.BackendType `yaml:"type"`
+	// Etcd etcd后端配置，连接信息复用 service.etcd
+	Etcd AsyncEtcdBackend `yaml:"etcd"`
+}
+
+// trySetDefault set the AsyncBackend default value if user not configured.
+func (b *AsyncBackend) trySetDefault() {
+	if len(b.Type) == 0 {
+		b.Type = enumor.BackendMysql
+	}
+}
+
+// AsyncEtcdBackend etcd后端配置
+type AsyncEtcdBackend struct {
+	// KeyPrefix flow、task等key的前缀，为空时使用 /hcm/async
+	KeyPrefix string `yaml:"keyPrefix"`
+	// RetentionDays 处于终态(成功、失败、取消)的任务流的保留天数，超过后连同其任务一起删除，为0时不删除
+	RetentionDays uint `yaml:"retentionDays"`
+}
+
+// CloudRateLimit 调用云API的限流配置，按云厂商、账号、地域、接口维度限流
+type CloudRateLimit struct {
+	// Enable 是否开启限流
+	Enable bool `yaml:"enable"`
+	// Store 令牌桶存储，etcd: 多副本共享令牌桶，连接信息复用 service.etcd；local: 仅当前副本内生效。默认 etcd
+	Store string `yaml:"store"`
+	// KeyPrefix 令牌桶在etcd中的key前缀，为空时使用 /hcm/ratelimit
+	KeyPrefix string `yaml:"keyPrefix"`
+	// QPS 未匹配到规则的接口每秒调用次数
+	QPS float64 `yaml:"qps"`
+	// Burst 未匹配到规则的接口令牌桶容量
+	Burst uint `yaml:"burst"`
+	// Rules 按云厂商、地域、接口配置的配额
+	Rules []CloudRateLimitRule `yaml:"rules"`
+	// IncreaseStep 未被限流时每秒加性恢复的QPS
+	IncreaseStep float64 `yaml:"increaseStep"`
+	// DecreaseFactor 被限流时速率乘以该系数
+	DecreaseFactor float64 `yaml:"decreaseFactor"`
+	// MinQPS 被限流降速后的最低QPS
+	MinQPS float64 `yaml:"minQPS"`
+	// MaxWaitSec 获取令牌的最长等待时间
+	MaxWaitSec uint `yaml:"maxWaitSec"`
+	// Prefetch 每次访问令牌桶存储时最多预取的令牌数，预取的令牌在当前副本内1秒内有效，为1时不预取。默认 5
+	Prefetch uint `yaml:"prefetch"`
+}
+
+const (
+	// CloudRateLimitEtcdStore 令牌桶存储在etcd中
+	CloudRateLimitEtcdStore = "etcd"
+	// CloudRateLimitLocalStore 令牌桶存储在本地内存中
+	CloudRateLimitLocalStore = "local"
+)
+
+// trySetDefault set the CloudRateLimit default value if user not configured.
+func (c *CloudRateLimit) trySetDefault() {
+	if len(c.Store) == 0 {
+		c.Store = CloudRateLimitEtcdStore
+	}
+
+	if c.QPS == 0 {
+		c.QPS = 20
+	}
+
+	if c.Burst == 0 {
+		c.Burst = 20
+	}
+
+	if c.IncreaseStep == 0 {
+		c.IncreaseStep = 1
+	}
+
+	if c.DecreaseFactor == 0 {
+		c.DecreaseFactor = 0.5
+	}
+
+	if c.MinQPS == 0 {
+		c.MinQPS = 1
+	}
+
+	if c.MaxWaitSec == 0 {
+		c.MaxWaitSec = 60
+	}
+
+	if c.Prefetch == 0 {
+		c.Prefetch = 5
+	}
+}
+
+// validate CloudRateLimit.
+func (c CloudRateLimit) validate() error {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.Store != CloudRateLimitEtcdStore && c.Store != CloudRateLimitLocalStore {
+		return fmt.Errorf("cloudRateLimit.store %s is invalid, should be etcd or local", c.Store)
+	}
+
+	for _, rule := range c.Rules {
+		if err := rule.Vendor.Validate(); err != nil {
+			return fmt.Errorf("cloudRateLimit.rules vendor is invalid, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// CloudRateLimitRule 按云厂商、地域、接口配置的配额，Region、Action 为空时匹配所有地域、接口，匹配多条时使用最精确的规则。
+// Action 格式: tcloud 为接口名，如 DescribeInstances；aws 为 服务名:接口名，如 ec2:DescribeInstances；
+// huawei 为 服务名:请求方法 路径；gcp、azure 为 请求方法 路径，路径中资源名称替换为*
+type CloudRateLimitRule struct {
+	Vendor enumor.Vendor `yaml:"vendor"`
+	Region string        `yaml:"region"`
+	Action string        `yaml:"action"`
+	QPS    float64       `yaml:"qps"`
+	Burst  uint          `yaml:"burst"`
+}
+
+// AssumeRoleSource 角色扮演使用的源身份密钥，账号配置了角色/委托但未保存密钥时，使用该密钥获取临时凭证，
+// 源身份仅需要扮演角色的权限，避免在数据库中保存各账号的长期密钥
+type AssumeRoleSource struct {
+	TCloud AssumeRoleSecret `yaml:"tcloud"`
+	Aws    AssumeRoleSecret `yaml:"aws"`
+	HuaWei AssumeRoleSecret `yaml:"huawei"`
+}
+
+// AssumeRoleSecret 源身份密钥
+type AssumeRoleSecret struct {
+	SecretID  string `yaml:"secretId"`
+	SecretKey string `yaml:"secretKey"`
+}
+
+// validate AssumeRoleSource.
+func (a AssumeRoleSource) validate() error {
+	secrets := map[string]AssumeRoleSecret{"tcloud": a.TCloud, "aws": a.Aws, "huawei": a.HuaWei}
+	for vendor, secret := range secrets {
+		if (len(secret.SecretID) == 0) != (len(secret.SecretKey) == 0) {
+			return fmt.Errorf("assumeRoleSource.%s secretId and secretKey should be set together", vendor)
+		}
+	}
+
+	return nil
+}
+
+// AuditChain 审计哈希链配置，审计记录按天串成哈希链，并定期生成签名检查点，用于校验审计记录是否被篡改或删除
+type AuditChain struct {
+	// SignKey 检查点的HMAC签名密钥，不能存放在数据库中，为空时不生成检查点
+	SignKey string `yaml:"signKey"`
+	// CheckpointInterval 每新增多少条审计记录生成一个检查点
+	CheckpointInterval uint `yaml:"checkpointInterval"`
+	// CheckpointIntervalMin 距离上次检查点超过多少分钟后，新增审计记录时生成检查点
+	CheckpointIntervalMin uint `yaml:"checkpointIntervalMin"`
+	// Shards 每天的哈希链分片数，同一条链上的写入需要串行加锁，写入量大时增加分片数提高并发，分片数不能减少
+	Shards uint `yaml:"shards"`
+}
+
+// MaxAuditChainShards 每天的审计哈希链最大分片数
+const MaxAuditChainShards = 16
+
+// trySetDefault set the AuditChain default value if user not configured.
+func (a *AuditChain) trySetDefault() {
+	if a.CheckpointInterval == 0 {
+		a.CheckpointInterval = 1000
+	}
+
+	if a.CheckpointIntervalMin == 0 {
+		a.CheckpointIntervalMin = 60
+	}
+
+	if a.Shards == 0 {
+		a.Shards = 1
+	}
+}
+
+// validate AuditChain.
+func (a AuditChain) validate() error {
+	if a.Shards > MaxAuditChainShards {
+		return fmt.Errorf("auditChain.shards should <= %d", MaxAuditChainShards)
+	}
+
+	return nil
+}
+
+// AuditSinkType 审计事件投递目标类型
+type AuditSinkType string
+
+const (
+	// SyslogAuditSink RFC5424 syslog，通过 TCP 或 TLS 投递
+	SyslogAuditSink AuditSinkType = "syslog"
+	// KafkaAuditSink 投递到 kafka topic
+	KafkaAuditSink AuditSinkType = "kafka"
+	// WebhookAuditSink 投递到 HTTP webhook，请求体使用 HMAC-SHA256 签名
+	WebhookAuditSink AuditSinkType = "webhook"
+)
+
+// AuditSink 审计事件投递配置，审计记录写入时同事务写入发件箱，再由主节点投递到各个外部目标，保证至少投递一次
+type AuditSink struct {
+	// Sinks 审计事件投递目标，为空时不写入发件箱
+	Sinks []AuditSinkTarget `yaml:"sinks"`
+	// BatchSize 每次从发件箱读取并投递的审计事件数
+	BatchSize uint `yaml:"batchSize"`
+	// IntervalSec 没有待投递事件时的轮询间隔
+	IntervalSec uint `yaml:"intervalSec"`
+	// RetryBaseSec 投递失败后首次重试的等待时间，之后按指数退避
+	RetryBaseSec uint `yaml:"retryBaseSec"`
+	// RetryMaxSec 投递失败后重试的最大等待时间
+	RetryMaxSec uint `yaml:"retryMaxSec"`
+}
+
+// Enable 是否配置了审计事件投递目标
+func (a AuditSink) Enable() bool {
+	return len(a.Sinks) > 0
+}
+
+// trySetDefault set the AuditSink default value if user not configured.
+func (a *AuditSink) trySetDefault() {
+	if a.BatchSize == 0 {
+		a.BatchSize = 100
+	}
+
+	if a.IntervalSec == 0 {
+		a.IntervalSec = 2
+	}
+
+	if a.RetryBaseSec == 0 {
+		a.RetryBaseSec = 2
+	}
+
+	if a.RetryMaxSec == 0 {
+		a.RetryMaxSec = 300
+	}
+
+	for i := range a.Sinks {
+		a.Sinks[i].trySetDefault()
+	}
+}
+
+// validate AuditSink options.
+func (a AuditSink) validate() error {
+	if a.RetryMaxSec < a.RetryBaseSec {
+		return errors.New("auditSink.retryMaxSec should not be less than retryBaseSec")
+	}
+
+	names := make(map[string]struct{}, len(a.Sinks))
+	for _, one := range a.Sinks {
+		if err := one.validate(); err != nil {
+			return fmt.Errorf("auditSink.sinks[%s] is invalid, %v", one.Name, err)
+		}
+
+		if _, exist := names[one.Name]; exist {
+			return fmt.Errorf("auditSink.sinks name %s is duplicated", one.Name)
+		}
+		names[one.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// AuditSinkTarget 审计事件投递目标
+type AuditSinkTarget struct {
+	// Name 投递目标名称，唯一标识一个目标，用于记录投递进度，修改后会从发件箱中最早的事件开始重新投递
+	Name string `yaml:"name"`
+	// Type 投递目标类型，根据类型使用对应的配置
+	Type    AuditSinkType   `yaml:"type"`
+	Filter  AuditSinkFilter `yaml:"filter"`
+	Syslog  SyslogSink      `yaml:"syslog"`
+	Kafka   KafkaSink       `yaml:"kafka"`
+	Webhook WebhookSink     `yaml:"webhook"`
+}
+
+// trySetDefault set the AuditSinkTarget default value if user not configured.
+func (a *AuditSinkTarget) trySetDefault() {
+	switch a.Type {
+	case SyslogAuditSink:
+		if len(a.Syslog.Network) == 0 {
+			a.Syslog.Network = "tcp"
+		}
+		if len(a.Syslog.AppName) == 0 {
+			a.Syslog.AppName = "hcm"
+		}
+		if a.Syslog.TimeoutSec == 0 {
+			a.Syslog.TimeoutSec = 10
+		}
+	case KafkaAuditSink:
+		if a.Kafka.TimeoutSec == 0 {
+			a.Kafka.TimeoutSec = 10
+		}
+	case WebhookAuditSink:
+		if a.Webhook.TimeoutSec == 0 {
+			a.Webhook.TimeoutSec = 10
+		}
+	}
+}
+
+// validate AuditSinkTarget options.
+func (a AuditSinkTarget) validate() error {
+	if len(a.Name) == 0 || len(a.Name) > 64 {
+		return errors.New("name is required and should not be longer than 64")
+	}
+
+	if err := a.Filter.validate(); err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case SyslogAuditSink:
+		return a.Syslog.validate()
+	case KafkaAuditSink:
+		return a.Kafka.validate()
+	case WebhookAuditSink:
+		return a.Webhook.validate()
+	default:
+		return fmt.Errorf("unsupported sink type: %s", a.Type)
+	}
+}
+
+// AuditSinkFilter 投递目标的审计事件过滤条件，为空时不过滤
+type AuditSinkFilter struct {
+	// ResTypes 需要投递的审计资源类型
+	ResTypes []enumor.AuditResourceType `yaml:"resTypes"`
+	// Actions 需要投递的审计动作
+	Actions []enumor.AuditAction `yaml:"actions"`
+}
+
+// validate AuditSinkFilter options.
+func (f AuditSinkFilter) validate() error {
+	for _, resType := range f.ResTypes {
+		if !resType.Exist() {
+			return fmt.Errorf("unsupported filter res type: %s", resType)
+		}
+	}
+
+	for _, action := range f.Actions {
+		if !action.Exist() {
+			return fmt.Errorf("unsupported filter action: %s", action)
+		}
+	}
+
+	return nil
+}
+
+// SyslogSink RFC5424 syslog 投递配置，使用 RFC6587 octet counting 分帧
+type SyslogSink struct {
+	// Network 连接协议，支持 tcp、tls
+	Network string `yaml:"network"`
+	// Address syslog 服务地址，格式为 host:port
+	Address string `yaml:"address"`
+	// AppName syslog 消息的 APP-NAME 字段
+	AppName    string    `yaml:"appName"`
+	TimeoutSec uint      `yaml:"timeoutSec"`
+	TLS        TLSConfig `yaml:"tls"`
+}
+
+// validate SyslogSink options.
+func (s SyslogSink) validate() error {
+	if s.Network != "tcp" && s.Network != "tls" {
+		return fmt.Errorf("unsupported syslog network: %s", s.Network)
+	}
+
+	if len(s.Address) == 0 {
+		return errors.New("syslog address is required")
+	}
+
+	return s.TLS.validate()
+}
+
+// KafkaSink kafka 投递配置
+type KafkaSink struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+	// User、Password 不为空时使用 SASL/PLAIN 认证
+	User       string    `yaml:"user"`
+	Password   string    `yaml:"password"`
+	TimeoutSec uint      `yaml:"timeoutSec"`
+	TLS        TLSConfig `yaml:"tls"`
+}
+
+// validate KafkaSink options.
+func (k KafkaSink) validate() error {
+	if len(k.Brokers) == 0 {
+		return errors.New("kafka brokers is required")
+	}
+
+	if len(k.Topic) == 0 {
+		return errors.New("kafka topic is required")
+	}
+
+	return k.TLS.validate()
+}
+
+// WebhookSink HTTP webhook 投递配置
+type WebhookSink struct {
+	URL string `yaml:"url"`
+	// Secret 请求体的 HMAC-SHA256 签名密钥，为空时不签名
+	Secret     string    `yaml:"secret"`
+	TimeoutSec uint      `yaml:"timeoutSec"`
+	TLS        TLSConfig `yaml:"tls"`
+}
+
+// validate WebhookSink options.
+func (w WebhookSink) validate() error {
+	if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+		return fmt.Errorf("invalid webhook url: %s", w.URL)
+	}
+
+	return w.TLS.validate()
+}
+
+// AuditArchive 审计记录归档配置，超过保留天数的审计记录按天压缩归档到对象存储，并从数据库中删除
+type AuditArchive struct {
+	// DefaultRetentionDays 未单独配置保留天数的审计资源类型的保留天数，为0时不归档
+	DefaultRetentionDays uint `yaml:"defaultRetentionDays"`
+	// RetentionDays 按审计资源类型配置的保留天数，为0时不归档该类型
+	RetentionDays map[enumor.AuditResourceType]uint `yaml:"retentionDays"`
+	// Prefix 归档文件在对象存储中的路径前缀
+	Prefix string `yaml:"prefix"`
+	// BatchSize 每次从数据库中读取并归档的审计记录数
+	BatchSize uint `yaml:"batchSize"`
+	// IntervalMin 归档任务的执行间隔，单位分钟
+	IntervalMin uint `yaml:"intervalMin"`
+	// MaxScanRecords 查询归档时单次最多扫描的审计记录数，超过时需要缩小查询的时间范围
+	MaxScanRecords uint `yaml:"maxScanRecords"`
+	// CacheRecords 查询归档时缓存的已解析审计记录数，分页查询时不需要重复下载和解析归档文件
+	CacheRecords uint `yaml:"cacheRecords"`
+}
+
+// Enable 是否有审计资源类型需要归档
+func (a AuditArchive) Enable() bool {
+	if a.DefaultRetentionDays > 0 {
+		return true
+	}
+
+	for _, days := range a.RetentionDays {
+		if days > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trySetDefault set the AuditArchive default value if user not configured.
+func (a *AuditArchive) trySetDefault() {
+	if len(a.Prefix) == 0 {
+		a.Prefix = "audit-archive"
+	}
+
+	if a.BatchSize == 0 {
+		a.BatchSize = 5000
+	}
+
+	if a.IntervalMin == 0 {
+		a.IntervalMin = 60
+	}
+
+	if a.MaxScanRecords == 0 {
+		a.MaxScanRecords = 100000
+	}
+
+	if a.CacheRecords == 0 {
+		a.CacheRecords = 2 * a.MaxScanRecords
+	}
+}
+
+// validate AuditArchive options.
+func (a AuditArchive) validate() error {
+	for resType := range a.RetentionDays {
+		if !resType.Exist() {
+			return fmt.Errorf("auditArchive.retentionDays res type %s is not supported", resType)
+		}
+	}
+
+	return nil
+}
+
 // DataBase defines database related runtime
 type DataBase struct {
 	Resource ResourceDB `yaml:"resource"`
@@ -204,6 +710,10 @@ type DataBase struct {
 	// Limiter defines request's to ORM's limitation for each sharding, and
 	// each sharding have the independent request limitation.
 	Limiter *Limiter `yaml:"limiter"`
+	// AuditChain defines audit hash chain's checkpoint signing options.
+	AuditChain AuditChain `yaml:"auditChain"`
+	// AuditOutbox 审计记录写入时是否同事务写入发件箱，由 data-service 根据是否配置了审计事件投递目标设置
+	AuditOutbox bool `yaml:"-"`
 }
 
 // trySetDefault set the sharding default value if user not configured.
@@ -219,6 +729,7 @@ func (s *DataBase) trySetDefault() {
 	}
 
 	s.Limiter.trySetDefault()
+	s.AuditChain.trySetDefault()
 }
 
 // validate sharding runtime
@@ -237,6 +748,10 @@ func (s DataBase) validate() error {
 		}
 	}
 
+	if err := s.AuditChain.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -316,6 +831,52 @@ func (ds ResourceDB) validate() error {
 	return nil
 }
 
+// Tracing defines OpenTelemetry tracing related configuration.
+type Tracing struct {
+	// Enable 是否开启链路追踪
+	Enable bool `yaml:"enable"`
+	// Endpoint OTLP gRPC 接收端地址，例如 127.0.0.1:4317
+	Endpoint string `yaml:"endpoint"`
+	// Insecure 是否使用非 TLS 连接上报
+	Insecure bool `yaml:"insecure"`
+	// SampleRatio 根 span 的采样率，取值范围 (0, 1]，未配置时全量采样
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+// trySetDefault set the tracing default value if user not configured.
+func (t *Tracing) trySetDefault() {
+	if t.SampleRatio == 0 {
+		t.SampleRatio = 1
+	}
+}
+
+// validate tracing.
+func (t Tracing) validate() error {
+	if !t.Enable {
+		return nil
+	}
+
+	if len(t.Endpoint) == 0 {
+		return errors.New("tracing endpoint is required")
+	}
+
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("tracing sampleRatio %v should be in (0, 1]", t.SampleRatio)
+	}
+
+	return nil
+}
+
+// Option convert it to tracing.Option.
+func (t Tracing) Option() tracing.Option {
+	return tracing.Option{
+		Enable:      t.Enable,
+		Endpoint:    t.Endpoint,
+		Insecure:    t.Insecure,
+		SampleRatio: t.SampleRatio,
+	}
+}
+
 // LogOption defines log's related configuration
 type LogOption struct {
 	LogDir           string `yaml:"logDir"`
@@ -591,6 +1152,8 @@ func (a AesGcm) validate() error {
 // TODO: 这里默认只支持AES Gcm算法，后续需要支持国密等的选择，可能还需要支持根据不同场景配置不同（比如不同场景，加密的密钥等都不一样）
 type Crypto struct {
 	AesGcm AesGcm `yaml:"aesGcm"`
+	// Envelope 开启信封加密后，AesGcm 仅用于解密历史密文
+	Envelope Envelope `yaml:"envelope"`
 }
 
 func (c Crypto) validate() error {
@@ -598,6 +1161,114 @@ func (c Crypto) validate() error {
 		return err
 	}
 
+	if err := c.Envelope.validate(); err != nil {
+		return fmt.Errorf("validate crypto envelope failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// Option convert to cryptography option.
+func (c Crypto) Option() cryptography.Option {
+	opt := cryptography.Option{
+		AesGcmKey:   c.AesGcm.Key,
+		AesGcmNonce: c.AesGcm.Nonce,
+	}
+
+	if !c.Envelope.Enable {
+		return opt
+	}
+
+	vault := c.Envelope.Vault
+	opt.Envelope = &cryptography.EnvelopeOption{
+		KMS:          c.Envelope.KMS,
+		LocalKeyFile: c.Envelope.Local.KeyFile,
+		Vault: cryptography.VaultOption{
+			Address:            vault.Address,
+			Token:              vault.Token,
+			Namespace:          vault.Namespace,
+			MountPath:          vault.MountPath,
+			KeyName:            vault.KeyName,
+			Timeout:            time.Duration(vault.TimeoutSec) * time.Second,
+			InsecureSkipVerify: vault.TLS.InsecureSkipVerify,
+			CAFile:             vault.TLS.CAFile,
+			CertFile:           vault.TLS.CertFile,
+			KeyFile:            vault.TLS.KeyFile,
+			Password:           vault.TLS.Password,
+		},
+	}
+
+	return opt
+}
+
+// Envelope 信封加密配置，每条记录使用独立的数据密钥加密，数据密钥由KMS中的密钥加密密钥(KEK)包装
+type Envelope struct {
+	Enable bool `yaml:"enable"`
+	// KMS 密钥管理服务类型，支持 local、vault
+	KMS   cryptography.KMSType `yaml:"kms"`
+	Local LocalKMS             `yaml:"local"`
+	Vault VaultKMS             `yaml:"vault"`
+	// RewrapIntervalMin KEK轮换后重新包装存量密钥的后台任务执行间隔，单位分钟，为0时不启动后台任务，仅data-service使用
+	RewrapIntervalMin uint `yaml:"rewrapIntervalMin"`
+}
+
+func (e Envelope) validate() error {
+	if !e.Enable {
+		return nil
+	}
+
+	switch e.KMS {
+	case cryptography.LocalKMSType:
+		if len(e.Local.KeyFile) == 0 {
+			return errors.New("local kms keyFile is not set")
+		}
+	case cryptography.VaultKMSType:
+		if err := e.Vault.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported kms: %s", e.KMS)
+	}
+
+	return nil
+}
+
+// LocalKMS 本地密钥文件KMS配置
+type LocalKMS struct {
+	// KeyFile 密钥文件路径，文件内容格式为 {"primary": "2", "keys": {"1": "<base64>", "2": "<base64>"}}，
+	// keys 为KEK版本到Base64格式的32字节密钥的映射，primary 为当前用于加密的KEK版本
+	KeyFile string `yaml:"keyFile"`
+}
+
+// VaultKMS HashiCorp Vault Transit 引擎KMS配置
+type VaultKMS struct {
+	Address   string `yaml:"address"`
+	Token     string `yaml:"token"`
+	Namespace string `yaml:"namespace"`
+	// MountPath Transit 引擎挂载路径，默认为 transit
+	MountPath  string    `yaml:"mountPath"`
+	KeyName    string    `yaml:"keyName"`
+	TimeoutSec uint      `yaml:"timeoutSec"`
+	TLS        TLSConfig `yaml:"tls"`
+}
+
+func (v VaultKMS) validate() error {
+	if len(v.Address) == 0 {
+		return errors.New("vault kms address is not set")
+	}
+
+	if len(v.Token) == 0 {
+		return errors.New("vault kms token is not set")
+	}
+
+	if len(v.KeyName) == 0 {
+		return errors.New("vault kms keyName is not set")
+	}
+
+	if err := v.TLS.validate(); err != nil {
+		return fmt.Errorf("validate vault kms tls failed, err: %v", err)
+	}
+
 	return nil
 }
 
@@ -616,9 +1287,19 @@ func (c CloudResource) validate() error {
 
 // CloudResourceSync 云资源同步配置
 type CloudResourceSync struct {
-	Enable                       bool   `yaml:"enable"`
-	SyncIntervalMin              uint64 `yaml:"syncIntervalMin"`
+	Enable          bool   `yaml:"enable"`
+	SyncIntervalMin uint64 `yaml:"syncIntervalMin"`
+	// Cron 同步的cron表达式，设置后忽略 SyncIntervalMin
+	Cron                         string `yaml:"cron"`
 	SyncFrequencyLimitingTimeMin uint64 `yaml:"syncFrequencyLimitingTimeMin"`
+	// EventSync 基于云上审计事件的增量同步
+	EventSync CloudEventSync `yaml:"eventSync"`
+}
+
+// SyncCron 返回全量同步的cron表达式
+func (c CloudResourceSync) SyncCron() string {
+	expr, _ := cronOrEvery(c.Cron, c.SyncIntervalMin)
+	return expr
 }
 
 func (c CloudResourceSync) validate() error {
@@ -626,11 +1307,78 @@ func (c CloudResourceSync) validate() error {
 		if c.SyncFrequencyLimitingTimeMin < 10 {
 			return errors.New("syncFrequencyLimitingTimeMin must > 10")
 		}
+
+		if _, err := cronOrEvery(c.Cron, c.SyncIntervalMin); err != nil {
+			return fmt.Errorf("cloudResource.sync schedule is invalid, err: %v", err)
+		}
+	}
+
+	if err := c.EventSync.validate(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// CloudEventSync 云审计事件增量同步配置
+type CloudEventSync struct {
+	Enable bool `yaml:"enable"`
+	// SyncIntervalMin 拉取事件的时间间隔，单位：分钟
+	SyncIntervalMin uint64 `yaml:"syncIntervalMin"`
+	// Cron 拉取事件的cron表达式，设置后忽略 SyncIntervalMin
+	Cron string `yaml:"cron"`
+	// DelayMin 云上审计事件投递存在延迟，仅拉取 DelayMin 分钟之前的事件
+	DelayMin uint64 `yaml:"delayMin"`
+}
+
+// SyncCron 返回拉取事件的cron表达式
+func (c CloudEventSync) SyncCron() string {
+	expr, _ := cronOrEvery(c.Cron, c.SyncIntervalMin)
+	return expr
+}
+
+// SyncInterval 返回拉取事件的间隔，配置cron表达式时取相邻两次触发的间隔
+func (c CloudEventSync) SyncInterval() time.Duration {
+	if len(c.Cron) == 0 {
+		return time.Duration(c.SyncIntervalMin) * time.Minute
+	}
+
+	schedule, err := cron.Parse(c.Cron)
+	if err != nil {
+		return time.Duration(c.SyncIntervalMin) * time.Minute
+	}
+	next := schedule.Next(time.Now())
+	return schedule.Next(next).Sub(next)
+}
+
+func (c CloudEventSync) validate() error {
+	if !c.Enable {
+		return nil
+	}
+
+	if len(c.Cron) == 0 && c.SyncIntervalMin < 1 {
+		return errors.New("eventSync.syncIntervalMin must >= 1")
+	}
+
+	if _, err := cronOrEvery(c.Cron, c.SyncIntervalMin); err != nil {
+		return fmt.Errorf("eventSync schedule is invalid, err: %v", err)
+	}
+
+	return nil
+}
+
+// cronOrEvery 返回配置的cron表达式，未配置时按时间间隔生成，时间间隔无法用cron表达式准确表示时返回错误
+func cronOrEvery(expr string, intervalMin uint64) (string, error) {
+	if len(expr) != 0 {
+		if _, err := cron.Parse(expr); err != nil {
+			return "", err
+		}
+		return expr, nil
+	}
+
+	return cron.Every(time.Duration(intervalMin) * time.Minute)
+}
+
 // Recycle configuration.
 type Recycle struct {
 	AutoDeleteTime uint `yaml:"autoDeleteTimeHour"`
@@ -648,13 +1396,67 @@ func (a Recycle) validate() error {
 type BillConfig struct {
 	Enable          bool   `yaml:"enable"`
 	SyncIntervalMin uint64 `yaml:"syncIntervalMin"`
+	// Cron 生成账单配置的cron表达式，设置后忽略 SyncIntervalMin
+	Cron string `yaml:"cron"`
+}
+
+// SyncCron 返回生成账单配置的cron表达式
+func (c BillConfig) SyncCron() string {
+	expr, _ := cronOrEvery(c.Cron, c.SyncIntervalMin)
+	return expr
 }
 
 func (c BillConfig) validate() error {
-	if c.Enable && c.SyncIntervalMin < 1 {
+	if !c.Enable {
+		return nil
+	}
+
+	if len(c.Cron) == 0 && c.SyncIntervalMin < 1 {
 		return errors.New("BillConfig.SyncIntervalMin must >= 1")
 	}
 
+	if _, err := cronOrEvery(c.Cron, c.SyncIntervalMin); err != nil {
+		return fmt.Errorf("billConfig schedule is invalid, err: %v", err)
+	}
+
+	return nil
+}
+
+// Approval 申请单审批配置
+type Approval struct {
+	// Engine 审批引擎，itsm 使用蓝鲸ITSM审批，native 使用内置审批引擎，默认为 itsm
+	Engine enumor.ApprovalEngine `yaml:"engine"`
+	// TimeoutCheckIntervalMin 内置审批引擎检查审批超时并升级的间隔，默认1分钟
+	TimeoutCheckIntervalMin uint `yaml:"timeoutCheckIntervalMin"`
+	// TimeoutCheckCron 检查审批超时的cron表达式，设置后忽略 TimeoutCheckIntervalMin
+	TimeoutCheckCron string `yaml:"timeoutCheckCron"`
+}
+
+// CheckCron 返回检查审批超时的cron表达式
+func (a Approval) CheckCron() string {
+	expr, _ := cronOrEvery(a.TimeoutCheckCron, uint64(a.TimeoutCheckIntervalMin))
+	return expr
+}
+
+func (a *Approval) trySetDefault() {
+	if a.Engine == "" {
+		a.Engine = enumor.ItsmApprovalEngine
+	}
+
+	if a.TimeoutCheckIntervalMin == 0 {
+		a.TimeoutCheckIntervalMin = 1
+	}
+}
+
+func (a Approval) validate() error {
+	if err := a.Engine.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := cronOrEvery(a.TimeoutCheckCron, uint64(a.TimeoutCheckIntervalMin)); err != nil {
+		return fmt.Errorf("approval timeout check schedule is invalid, err: %v", err)
+	}
+
 	return nil
 }
 
@@ -720,6 +1522,7 @@ type CloudSelection struct {
 	CoverRate            float64                   `yaml:"coverRate"`
 	CoverPingRanges      []ThreshHoldRanges        `yaml:"coverPingRanges"`
 	IDCPriceRanges       []ThreshHoldRanges        `yaml:"idcPriceRanges"`
+	AlgorithmType        AlgorithmType             `yaml:"algorithmType"`
 	AlgorithmPlugin      Plugin                    `yaml:"algorithmPlugin"`
 	TableNames           CloudSelectionTableNames  `yaml:"tableNames"`
 	DataSourceType       string                    `yaml:"dataSourceType"`
@@ -727,6 +1530,16 @@ type CloudSelection struct {
 	DefaultIdcPrice      map[enumor.Vendor]float64 `yaml:"defaultIdcPrice"`
 }
 
+// AlgorithmType 推荐算法实现方式
+type AlgorithmType string
+
+const (
+	// NativeAlgorithm 内置的Go实现，默认值
+	NativeAlgorithm AlgorithmType = "native"
+	// PluginAlgorithm 调用 AlgorithmPlugin 配置的外部可执行文件
+	PluginAlgorithm AlgorithmType = "plugin"
+)
+
 // Plugin outside binary plugin
 type Plugin struct {
 	BinaryPath string   `yaml:"binaryPath"`
@@ -765,6 +1578,16 @@ func (c CloudSelection) Validate() error {
 		return fmt.Errorf("data source: %s not support", c.DataSourceType)
 	}
 
+	switch c.AlgorithmType {
+	case "", NativeAlgorithm:
+	case PluginAlgorithm:
+		if len(c.AlgorithmPlugin.BinaryPath) == 0 {
+			return errors.New("algorithm plugin binary path is required")
+		}
+	default:
+		return fmt.Errorf("algorithm type: %s not support", c.AlgorithmType)
+	}
+
 	return nil
 }
 
@@ -785,8 +1608,67 @@ type ThreshHoldRanges struct {
 
 // ObjectStore object store config
 type ObjectStore struct {
+	// Type 对象存储类型，支持 tcloud、s3、local，为空时不启用对象存储
 	Type              string `yaml:"type"`
 	ObjectStoreTCloud `yaml:",inline"`
+	S3                ObjectStoreS3    `yaml:"s3"`
+	Local             ObjectStoreLocal `yaml:"local"`
+}
+
+// ObjectStoreS3 s3 compatible object store config, such as aws s3, minio, huawei obs.
+type ObjectStoreS3 struct {
+	// Endpoint 服务地址，如 https://obs.cn-north-4.myhuaweicloud.com、http://127.0.0.1:9000，使用aws s3时可为空
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"accessKeyId"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	Bucket          string `yaml:"bucket"`
+	Prefix          string `yaml:"prefix"`
+	// ForcePathStyle 使用 endpoint/bucket/key 形式的路径访问，minio 需要开启
+	ForcePathStyle bool `yaml:"forcePathStyle"`
+	IsDebug        bool `yaml:"isDebug"`
+}
+
+// Validate do validate
+func (s ObjectStoreS3) Validate() error {
+	if len(s.Region) == 0 {
+		return errors.New("s3 region cannot be empty")
+	}
+	if len(s.AccessKeyID) == 0 {
+		return errors.New("s3 access_key_id cannot be empty")
+	}
+	if len(s.SecretAccessKey) == 0 {
+		return errors.New("s3 secret_access_key cannot be empty")
+	}
+	if len(s.Bucket) == 0 {
+		return errors.New("s3 bucket cannot be empty")
+	}
+	return nil
+}
+
+// ObjectStoreLocal local filesystem object store config, used by dev and air-gapped deployments.
+type ObjectStoreLocal struct {
+	// RootDir 文件存储的根目录
+	RootDir string `yaml:"rootDir"`
+	Prefix  string `yaml:"prefix"`
+	// SignSecret 生成临时下载、上传链接时使用的HMAC密钥
+	SignSecret string `yaml:"signSecret"`
+	// URLBase 临时链接的访问地址，指向data-service，如 http://127.0.0.1:9600
+	URLBase string `yaml:"urlBase"`
+}
+
+// Validate do validate
+func (l ObjectStoreLocal) Validate() error {
+	if len(l.RootDir) == 0 {
+		return errors.New("local root_dir cannot be empty")
+	}
+	if len(l.SignSecret) == 0 {
+		return errors.New("local sign_secret cannot be empty")
+	}
+	if len(l.URLBase) == 0 {
+		return errors.New("local url_base cannot be empty")
+	}
+	return nil
 }
 
 // ObjectStoreTCloud tencent cloud cos config
@@ -829,6 +1711,8 @@ var (
 	defaultMainAccountSummarySyncDuration = 10 * time.Minute
 	defaultRootAccountSummarySyncDuration = 10 * time.Minute
 	defaultDailySummarySyncDuration       = 30 * time.Second
+	defaultBudgetSyncDuration             = 10 * time.Minute
+	defaultCostAnomalySyncDuration        = 10 * time.Minute
 )
 
 // BillControllerOption bill controller option
@@ -839,6 +1723,10 @@ type BillControllerOption struct {
 	MainAccountSummarySyncDuration *time.Duration `yaml:"mainAccountSummarySyncDuration,omitempty"`
 	RootAccountSummarySyncDuration *time.Duration `yaml:"rootAccountSummarySyncDuration,omitempty"`
 	DailySummarySyncDuration       *time.Duration `yaml:"dailySummarySyncDuration,omitempty"`
+	// BudgetSyncDuration 检查每日汇总账单是否更新的间隔，更新后评估所有预算
+	BudgetSyncDuration *time.Duration `yaml:"budgetSyncDuration,omitempty"`
+	// CostAnomalySyncDuration 检查每日汇总账单是否更新的间隔，更新后检测二级账号的每日费用异常
+	CostAnomalySyncDuration *time.Duration `yaml:"costAnomalySyncDuration,omitempty"`
 }
 
 func (bco *BillControllerOption) trySetDefault() {
@@ -854,6 +1742,12 @@ func (bco *BillControllerOption) trySetDefault() {
 	if bco.DailySummarySyncDuration == nil {
 		bco.DailySummarySyncDuration = &defaultDailySummarySyncDuration
 	}
+	if bco.BudgetSyncDuration == nil {
+		bco.BudgetSyncDuration = &defaultBudgetSyncDuration
+	}
+	if bco.CostAnomalySyncDuration == nil {
+		bco.CostAnomalySyncDuration = &defaultCostAnomalySyncDuration
+	}
 }
 
 // CMSI cmsi config
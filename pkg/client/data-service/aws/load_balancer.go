@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// LoadBalancerClient ...
+type LoadBalancerClient struct {
+	client rest.ClientInterface
+}
+
+// NewLoadBalancerClient ...
+func NewLoadBalancerClient(client rest.ClientInterface) *LoadBalancerClient {
+	return &LoadBalancerClient{client: client}
+}
+
+// BatchCreateAwsLoadBalancer 批量创建aws负载均衡
+func (cli *LoadBalancerClient) BatchCreateAwsLoadBalancer(kt *kit.Kit, req *dataproto.AwsLBCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataproto.AwsLBCreateReq, core.BatchCreateResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/batch/create")
+}
+
+// Get 获取负载均衡详情
+func (cli *LoadBalancerClient) Get(kt *kit.Kit, id string) (*corelb.AwsLoadBalancer, error) {
+	return common.Request[common.Empty, corelb.AwsLoadBalancer](
+		cli.client, rest.GET, kt, nil, "/load_balancers/%s", id)
+}
+
+// BatchUpdate 批量更新负载均衡
+func (cli *LoadBalancerClient) BatchUpdate(kt *kit.Kit, req *dataproto.AwsLBBatchUpdateReq) error {
+	return common.RequestNoResp[dataproto.AwsLBBatchUpdateReq](cli.client,
+		rest.PATCH, kt, req, "/load_balancers/batch/update")
+}
+
+// ListLoadBalancer list aws load balancer
+func (cli *LoadBalancerClient) ListLoadBalancer(kt *kit.Kit, req *core.ListReq) (
+	*core.ListResultT[corelb.AwsLoadBalancer], error) {
+
+	return common.Request[core.ListReq, core.ListResultT[corelb.AwsLoadBalancer]](
+		cli.client, rest.POST, kt, req, "/load_balancers/list")
+}
+
+// GetListener 获取监听器详情
+func (cli *LoadBalancerClient) GetListener(kt *kit.Kit, id string) (*corelb.AwsListener, error) {
+	return common.Request[common.Empty, corelb.AwsListener](cli.client, rest.GET, kt, nil, "/listeners/%s", id)
+}
+
+// ListListener list listener with aws extension.
+func (cli *LoadBalancerClient) ListListener(kt *kit.Kit, req *core.ListReq) (
+	*dataproto.AwsListenerListResult, error) {
+
+	return common.Request[core.ListReq, dataproto.AwsListenerListResult](cli.client,
+		rest.POST, kt, req, "/load_balancers/listeners/list")
+}
+
+// BatchCreateAwsListener 批量创建aws监听器
+func (cli *LoadBalancerClient) BatchCreateAwsListener(kt *kit.Kit, req *dataproto.AwsListenerBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataproto.AwsListenerBatchCreateReq, core.BatchCreateResult](
+		cli.client, rest.POST, kt, req, "/listeners/batch/create")
+}
+
+// BatchUpdateAwsListener 批量更新aws监听器
+func (cli *LoadBalancerClient) BatchUpdateAwsListener(kt *kit.Kit, req *dataproto.AwsListenerUpdateReq) error {
+	return common.RequestNoResp[dataproto.AwsListenerUpdateReq](
+		cli.client, rest.PATCH, kt, req, "/listeners/batch/update")
+}
+
+// ListUrlRule list url rule.
+func (cli *LoadBalancerClient) ListUrlRule(kt *kit.Kit, req *core.ListReq) (*dataproto.TCloudURLRuleListResult, error) {
+	return common.Request[core.ListReq, dataproto.TCloudURLRuleListResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/url_rules/list")
+}
+
+// BatchCreateUrlRule 批量创建aws转发规则，与腾讯云共用url规则表
+func (cli *LoadBalancerClient) BatchCreateUrlRule(kt *kit.Kit, req *dataproto.TCloudUrlRuleBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataproto.TCloudUrlRuleBatchCreateReq, core.BatchCreateResult](
+		cli.client, rest.POST, kt, req, "/url_rules/batch/create")
+}
+
+// BatchUpdateUrlRule 批量更新aws转发规则
+func (cli *LoadBalancerClient) BatchUpdateUrlRule(kt *kit.Kit, req *dataproto.TCloudUrlRuleBatchUpdateReq) error {
+	return common.RequestNoResp[dataproto.TCloudUrlRuleBatchUpdateReq](
+		cli.client, rest.PATCH, kt, req, "/url_rules/batch/update")
+}
+
+// BatchDeleteUrlRule 批量删除aws转发规则
+func (cli *LoadBalancerClient) BatchDeleteUrlRule(kt *kit.Kit, req *dataproto.LoadBalancerBatchDeleteReq) error {
+	return common.RequestNoResp[dataproto.LoadBalancerBatchDeleteReq](
+		cli.client, rest.DELETE, kt, req, "/url_rules/batch")
+}
+
+// BatchCreateAwsTargetGroup 批量创建aws目标组
+func (cli *LoadBalancerClient) BatchCreateAwsTargetGroup(kt *kit.Kit, req *dataproto.AwsTargetGroupCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataproto.AwsTargetGroupCreateReq, core.BatchCreateResult](
+		cli.client, rest.POST, kt, req, "/target_groups/batch/create")
+}
+
+// BatchUpdateAwsTargetGroup 批量更新aws目标组
+func (cli *LoadBalancerClient) BatchUpdateAwsTargetGroup(kt *kit.Kit, req *dataproto.TargetGroupUpdateReq) error {
+	return common.RequestNoResp[dataproto.TargetGroupUpdateReq](
+		cli.client, rest.PATCH, kt, req, "/target_groups")
+}
+
+// GetTargetGroup 获取目标组详情
+func (cli *LoadBalancerClient) GetTargetGroup(kt *kit.Kit, id string) (*corelb.AwsTargetGroup, error) {
+	return common.Request[common.Empty, corelb.AwsTargetGroup](
+		cli.client, rest.GET, kt, nil, "/target_groups/%s", id)
+}
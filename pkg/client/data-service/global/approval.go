@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewApprovalClient create a new native approval engine api client.
+func NewApprovalClient(client rest.ClientInterface) *ApprovalClient {
+	return &ApprovalClient{
+		client: client,
+	}
+}
+
+// ApprovalClient is data service native approval engine api client.
+type ApprovalClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreateChain batch create approval chains.
+func (cli *ApprovalClient) BatchCreateChain(kt *kit.Kit, req *dataservice.ApprovalChainBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataservice.ApprovalChainBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, req, "/approval_chains/batch/create")
+}
+
+// BatchUpdateChain batch update approval chains.
+func (cli *ApprovalClient) BatchUpdateChain(kt *kit.Kit, req *dataservice.ApprovalChainBatchUpdateReq) error {
+	return common.RequestNoResp[dataservice.ApprovalChainBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/approval_chains/batch/update")
+}
+
+// ListChain list approval chains.
+func (cli *ApprovalClient) ListChain(kt *kit.Kit, req *core.ListReq) (*dataservice.ApprovalChainListResult, error) {
+	return common.Request[core.ListReq, dataservice.ApprovalChainListResult](cli.client, rest.POST, kt, req,
+		"/approval_chains/list")
+}
+
+// BatchDeleteChain batch delete approval chains.
+func (cli *ApprovalClient) BatchDeleteChain(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/approval_chains/batch")
+}
+
+// CreateTicket create approval ticket.
+func (cli *ApprovalClient) CreateTicket(kt *kit.Kit, req *dataservice.ApprovalTicketCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[dataservice.ApprovalTicketCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/approval_tickets/create")
+}
+
+// UpdateTicket update approval ticket, return RecordNotFound error if the ticket version has changed.
+func (cli *ApprovalClient) UpdateTicket(kt *kit.Kit, req *dataservice.ApprovalTicketUpdateReq) error {
+	return common.RequestNoResp[dataservice.ApprovalTicketUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/approval_tickets/update")
+}
+
+// ListTicket list approval tickets.
+func (cli *ApprovalClient) ListTicket(kt *kit.Kit, req *core.ListReq) (*dataservice.ApprovalTicketListResult,
+	error) {
+
+	return common.Request[core.ListReq, dataservice.ApprovalTicketListResult](cli.client, rest.POST, kt, req,
+		"/approval_tickets/list")
+}
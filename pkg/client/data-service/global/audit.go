@@ -218,3 +218,11 @@ func (a *AuditClient) GetAuditRaw(kt *kit.Kit, id uint64) (
 	return common.Request[common.Empty, coreaudit.RawAudit](a.client, rest.GET, kt, nil,
 		"/audits/%d", id)
 }
+
+// VerifyAuditChain verify audit hash chains, report altered or removed audit records.
+func (a *AuditClient) VerifyAuditChain(kt *kit.Kit, req *protoaudit.VerifyChainReq) (
+	*protoaudit.VerifyChainResult, error) {
+
+	return common.Request[protoaudit.VerifyChainReq, protoaudit.VerifyChainResult](a.client, rest.POST, kt, req,
+		"/audits/chains/verify")
+}
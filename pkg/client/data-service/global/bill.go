@@ -94,6 +94,72 @@ func (b *BillClient) BatchDelete(ctx context.Context, h http.Header, req *datase
 	return nil
 }
 
+// --- bill budget ---
+
+// CreateBudget create bill budget
+func (b *BillClient) CreateBudget(kt *kit.Kit, req *billproto.BudgetCreateReq) (*core.CreateResult, error) {
+	return common.Request[billproto.BudgetCreateReq, core.CreateResult](
+		b.client, rest.POST, kt, req, "/bills/budgets/create")
+}
+
+// UpdateBudget update bill budget
+func (b *BillClient) UpdateBudget(kt *kit.Kit, req *billproto.BudgetUpdateReq) error {
+	return common.RequestNoResp[billproto.BudgetUpdateReq](b.client, rest.PATCH, kt, req, "/bills/budgets")
+}
+
+// ListBudget list bill budget
+func (b *BillClient) ListBudget(kt *kit.Kit, req *core.ListReq) (*billproto.BudgetListResult, error) {
+	return common.Request[core.ListReq, billproto.BudgetListResult](b.client, rest.POST, kt, req,
+		"/bills/budgets/list")
+}
+
+// BatchDeleteBudget batch delete bill budget and its records
+func (b *BillClient) BatchDeleteBudget(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](b.client, rest.DELETE, kt, req, "/bills/budgets/batch")
+}
+
+// CreateBudgetRecord create bill budget record
+func (b *BillClient) CreateBudgetRecord(kt *kit.Kit, req *billproto.BudgetRecordCreateReq) (*core.CreateResult,
+	error) {
+
+	return common.Request[billproto.BudgetRecordCreateReq, core.CreateResult](
+		b.client, rest.POST, kt, req, "/bills/budgets/records/create")
+}
+
+// UpdateBudgetRecord update bill budget record
+func (b *BillClient) UpdateBudgetRecord(kt *kit.Kit, req *billproto.BudgetRecordUpdateReq) error {
+	return common.RequestNoResp[billproto.BudgetRecordUpdateReq](b.client, rest.PATCH, kt, req,
+		"/bills/budgets/records")
+}
+
+// ListBudgetRecord list bill budget record
+func (b *BillClient) ListBudgetRecord(kt *kit.Kit, req *core.ListReq) (*billproto.BudgetRecordListResult, error) {
+	return common.Request[core.ListReq, billproto.BudgetRecordListResult](b.client, rest.POST, kt, req,
+		"/bills/budgets/records/list")
+}
+
+// --- bill cost anomaly ---
+
+// CreateCostAnomaly create bill cost anomaly
+func (b *BillClient) CreateCostAnomaly(kt *kit.Kit, req *billproto.CostAnomalyCreateReq) (*core.CreateResult,
+	error) {
+
+	return common.Request[billproto.CostAnomalyCreateReq, core.CreateResult](
+		b.client, rest.POST, kt, req, "/bills/cost_anomalies/create")
+}
+
+// UpdateCostAnomaly update bill cost anomaly
+func (b *BillClient) UpdateCostAnomaly(kt *kit.Kit, req *billproto.CostAnomalyUpdateReq) error {
+	return common.RequestNoResp[billproto.CostAnomalyUpdateReq](b.client, rest.PATCH, kt, req,
+		"/bills/cost_anomalies")
+}
+
+// ListCostAnomaly list bill cost anomaly
+func (b *BillClient) ListCostAnomaly(kt *kit.Kit, req *core.ListReq) (*billproto.CostAnomalyListResult, error) {
+	return common.Request[core.ListReq, billproto.CostAnomalyListResult](b.client, rest.POST, kt, req,
+		"/bills/cost_anomalies/list")
+}
+
 // --- bill adjustment item ---
 
 // BatchCreateBillAdjustmentItem create bill adjustment item
@@ -41,6 +41,10 @@ type Client struct {
 	NetworkInterfaceCvmRel *NetworkInterfaceCvmRelClient
 	SubAccount             *SubAccountClient
 	AccountSyncDetail      *AccountSyncDetailClient
+	SyncCursor             *SyncCursorClient
+	ResourceTag            *ResourceTagClient
+	ResAssignRule          *ResAssignRuleClient
+	DiskSnapshot           *DiskSnapshotClient
 
 	Auth          *AuthClient
 	Account       *AccountClient
@@ -49,6 +53,7 @@ type Client struct {
 
 	Application     *ApplicationClient
 	ApprovalProcess *ApprovalProcessClient
+	Approval        *ApprovalClient
 	Bill            *BillClient
 
 	UserCollection *UserCollectionClient
@@ -88,6 +93,10 @@ func NewClient(client rest.ClientInterface) *Client {
 		NetworkInterfaceCvmRel: NewNetworkInterfaceCvmRelClient(client),
 		SubAccount:             NewSubAccountClient(client),
 		AccountSyncDetail:      NewAccountSyncDetailClient(client),
+		SyncCursor:             NewSyncCursorClient(client),
+		ResourceTag:            NewResourceTagClient(client),
+		ResAssignRule:          NewResAssignRuleClient(client),
+		DiskSnapshot:           NewDiskSnapshotClient(client),
 
 		Auth:          NewAuthClient(client),
 		Account:       NewAccountClient(client),
@@ -96,6 +105,7 @@ func NewClient(client rest.ClientInterface) *Client {
 
 		Application:     NewApplicationClient(client),
 		ApprovalProcess: NewApprovalProcessClient(client),
+		Approval:        NewApprovalClient(client),
 		Bill:            NewBillClient(client),
 
 		UserCollection: NewUserCollectionClient(client),
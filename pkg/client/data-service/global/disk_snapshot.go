@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewDiskSnapshotClient create a new disk snapshot api client.
+func NewDiskSnapshotClient(client rest.ClientInterface) *DiskSnapshotClient {
+	return &DiskSnapshotClient{
+		client: client,
+	}
+}
+
+// DiskSnapshotClient is data service disk snapshot and disk snapshot policy api client.
+type DiskSnapshotClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreate batch create disk snapshots.
+func (cli *DiskSnapshotClient) BatchCreate(kt *kit.Kit, req *protocloud.DiskSnapshotBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.DiskSnapshotBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, req, "/disk_snapshots/batch/create")
+}
+
+// BatchUpdate batch update disk snapshots.
+func (cli *DiskSnapshotClient) BatchUpdate(kt *kit.Kit, req *protocloud.DiskSnapshotBatchUpdateReq) error {
+	return common.RequestNoResp[protocloud.DiskSnapshotBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/disk_snapshots/batch/update")
+}
+
+// List disk snapshots.
+func (cli *DiskSnapshotClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.DiskSnapshotListResult, error) {
+	return common.Request[core.ListReq, protocloud.DiskSnapshotListResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshots/list")
+}
+
+// BatchDelete batch delete disk snapshots.
+func (cli *DiskSnapshotClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/disk_snapshots/batch")
+}
+
+// BatchCreatePolicy batch create disk snapshot policies.
+func (cli *DiskSnapshotClient) BatchCreatePolicy(kt *kit.Kit, req *protocloud.DiskSnapshotPolicyBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.DiskSnapshotPolicyBatchCreateReq, core.BatchCreateResult](cli.client,
+		rest.POST, kt, req, "/disk_snapshot_policies/batch/create")
+}
+
+// BatchUpdatePolicy batch update disk snapshot policies.
+func (cli *DiskSnapshotClient) BatchUpdatePolicy(kt *kit.Kit,
+	req *protocloud.DiskSnapshotPolicyBatchUpdateReq) error {
+
+	return common.RequestNoResp[protocloud.DiskSnapshotPolicyBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/disk_snapshot_policies/batch/update")
+}
+
+// ListPolicy list disk snapshot policies.
+func (cli *DiskSnapshotClient) ListPolicy(kt *kit.Kit, req *core.ListReq) (*protocloud.DiskSnapshotPolicyListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.DiskSnapshotPolicyListResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshot_policies/list")
+}
+
+// BatchDeletePolicy batch delete disk snapshot policies.
+func (cli *DiskSnapshotClient) BatchDeletePolicy(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/disk_snapshot_policies/batch")
+}
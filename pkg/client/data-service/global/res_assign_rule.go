@@ -0,0 +1,67 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewResAssignRuleClient create a new res assign rule api client.
+func NewResAssignRuleClient(client rest.ClientInterface) *ResAssignRuleClient {
+	return &ResAssignRuleClient{
+		client: client,
+	}
+}
+
+// ResAssignRuleClient is data service res assign rule api client.
+type ResAssignRuleClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreate batch create res assign rules.
+func (cli *ResAssignRuleClient) BatchCreate(kt *kit.Kit, req *protocloud.ResAssignRuleBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.ResAssignRuleBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, req, "/res_assign_rules/batch/create")
+}
+
+// BatchUpdate batch update res assign rules.
+func (cli *ResAssignRuleClient) BatchUpdate(kt *kit.Kit, req *protocloud.ResAssignRuleBatchUpdateReq) error {
+	return common.RequestNoResp[protocloud.ResAssignRuleBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/res_assign_rules/batch/update")
+}
+
+// List res assign rules.
+func (cli *ResAssignRuleClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.ResAssignRuleListResult, error) {
+	return common.Request[core.ListReq, protocloud.ResAssignRuleListResult](cli.client, rest.POST, kt, req,
+		"/res_assign_rules/list")
+}
+
+// BatchDelete batch delete res assign rules.
+func (cli *ResAssignRuleClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/res_assign_rules/batch")
+}
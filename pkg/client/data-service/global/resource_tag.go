@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewResourceTagClient create a new resource tag api client.
+func NewResourceTagClient(client rest.ClientInterface) *ResourceTagClient {
+	return &ResourceTagClient{
+		client: client,
+	}
+}
+
+// ResourceTagClient is data service resource tag api client.
+type ResourceTagClient struct {
+	client rest.ClientInterface
+}
+
+// BatchUpsert batch create or update resource tags.
+func (cli *ResourceTagClient) BatchUpsert(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq) error {
+	return common.RequestNoResp[protocloud.ResourceTagBatchUpsertReq](cli.client, rest.POST, kt, req,
+		"/resource_tags/batch/upsert")
+}
+
+// List resource tags.
+func (cli *ResourceTagClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.ResourceTagListResult, error) {
+	return common.Request[core.ListReq, protocloud.ResourceTagListResult](cli.client, rest.POST, kt, req,
+		"/resource_tags/list")
+}
+
+// BatchDelete batch delete resource tags.
+func (cli *ResourceTagClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/resource_tags/batch")
+}
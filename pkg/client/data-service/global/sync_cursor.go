@@ -0,0 +1,52 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewSyncCursorClient create a new sync cursor api client.
+func NewSyncCursorClient(client rest.ClientInterface) *SyncCursorClient {
+	return &SyncCursorClient{
+		client: client,
+	}
+}
+
+// SyncCursorClient is data service sync cursor api client.
+type SyncCursorClient struct {
+	client rest.ClientInterface
+}
+
+// List sync cursors.
+func (cli *SyncCursorClient) List(kt *kit.Kit, req *core.ListReq) (*dssync.SyncCursorListResult, error) {
+	return common.Request[core.ListReq, dssync.SyncCursorListResult](cli.client, rest.POST, kt, req,
+		"/sync_cursors/list")
+}
+
+// BatchSet batch set sync cursors, the cursor is created if it not exists.
+func (cli *SyncCursorClient) BatchSet(kt *kit.Kit, req *dssync.SyncCursorBatchSetReq) error {
+	return common.RequestNoResp[dssync.SyncCursorBatchSetReq](cli.client, rest.PUT, kt, req,
+		"/sync_cursors/batch/set")
+}
@@ -36,6 +36,8 @@ type Client struct {
 	RouteTable    *RouteTableClient
 	SubAccount    *SubAccountClient
 	LoadBalancer  *LoadBalancerClient
+	MainAccount   *MainAccountClient
+	RootAccount   *RootAccountClient
 }
 
 type restClient struct {
@@ -56,5 +58,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		RouteTable:    NewRouteTableClient(client),
 		SubAccount:    NewSubAccountClient(client),
 		LoadBalancer:  NewLoadBalancerClient(client),
+		MainAccount:   NewMainAccountClient(client),
+		RootAccount:   NewRootAccountClient(client),
 	}
 }
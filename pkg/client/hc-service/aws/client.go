@@ -31,6 +31,7 @@ type Client struct {
 	Subnet        *SubnetClient
 	Eip           *EipClient
 	Disk          *DiskClient
+	DiskSnapshot  *DiskSnapshotClient
 	Zone          *ZoneClient
 	Region        *RegionClient
 	Cvm           *CvmClient
@@ -39,6 +40,9 @@ type Client struct {
 	InstanceType  *InstanceTypeClient
 	Bill          *BillClient
 	MainAccount   *MainAccountClient
+	CloudEvent    *CloudEventClient
+	ResourceTag   *ResourceTagClient
+	LoadBalancer  *LoadBalancerClient
 }
 
 // NewClient create a new aws api client.
@@ -50,6 +54,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		Subnet:        NewSubnetClient(client),
 		Eip:           NewEipClient(client),
 		Disk:          NewCloudDiskClient(client),
+		DiskSnapshot:  NewDiskSnapshotClient(client),
 		Zone:          NewZoneClient(client),
 		Region:        NewRegionClient(client),
 		Cvm:           NewCvmClient(client),
@@ -58,5 +63,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:  NewInstanceTypeClient(client),
 		Bill:          NewBillClient(client),
 		MainAccount:   NewMainAccountClient(client),
+		CloudEvent:    NewCloudEventClient(client),
+		ResourceTag:   NewResourceTagClient(client),
+		LoadBalancer:  NewLoadBalancerClient(client),
 	}
 }
@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"net/http"
+
+	"hcm/pkg/api/core"
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewLoadBalancerClient create a new load balancer api client.
+func NewLoadBalancerClient(client rest.ClientInterface) *LoadBalancerClient {
+	return &LoadBalancerClient{
+		client: client,
+	}
+}
+
+// LoadBalancerClient is hc service aws load balancer api client.
+type LoadBalancerClient struct {
+	client rest.ClientInterface
+}
+
+// SyncLoadBalancer 同步负载均衡及其监听器、规则、目标组
+func (c *LoadBalancerClient) SyncLoadBalancer(kt *kit.Kit, req *sync.AwsSyncReq) error {
+	return common.RequestNoResp[sync.AwsSyncReq](c.client, http.MethodPost, kt, req, "/load_balancers/sync")
+}
+
+// Create 创建负载均衡
+func (c *LoadBalancerClient) Create(kt *kit.Kit, req *hcproto.AwsLoadBalancerCreateReq) (
+	*hcproto.BatchCreateResult, error) {
+
+	return common.Request[hcproto.AwsLoadBalancerCreateReq, hcproto.BatchCreateResult](
+		c.client, http.MethodPost, kt, req, "/load_balancers/create")
+}
+
+// Update 更新负载均衡
+func (c *LoadBalancerClient) Update(kt *kit.Kit, id string, req *hcproto.AwsLBUpdateReq) error {
+	return common.RequestNoResp[hcproto.AwsLBUpdateReq](c.client, http.MethodPatch,
+		kt, req, "/load_balancers/%s", id)
+}
+
+// BatchDelete 批量删除负载均衡
+func (c *LoadBalancerClient) BatchDelete(kt *kit.Kit, req *hcproto.AwsBatchDeleteLoadBalancerReq) error {
+	return common.RequestNoResp[hcproto.AwsBatchDeleteLoadBalancerReq](c.client, http.MethodDelete,
+		kt, req, "/load_balancers/batch")
+}
+
+// CreateListener 创建监听器
+func (c *LoadBalancerClient) CreateListener(kt *kit.Kit, req *hcproto.AwsListenerCreateReq) (
+	*core.CloudCreateResult, error) {
+
+	return common.Request[hcproto.AwsListenerCreateReq, core.CloudCreateResult](
+		c.client, http.MethodPost, kt, req, "/listeners/create")
+}
+
+// UpdateListener 更新监听器
+func (c *LoadBalancerClient) UpdateListener(kt *kit.Kit, id string, req *hcproto.AwsListenerUpdateReq) error {
+	return common.RequestNoResp[hcproto.AwsListenerUpdateReq](c.client, http.MethodPatch, kt, req, "/listeners/%s", id)
+}
+
+// BatchDeleteListener 批量删除监听器
+func (c *LoadBalancerClient) BatchDeleteListener(kt *kit.Kit, req *core.BatchDeleteReq) error {
+	return common.RequestNoResp[core.BatchDeleteReq](c.client, http.MethodDelete, kt, req, "/listeners/batch")
+}
+
+// CreateUrlRule 创建转发规则
+func (c *LoadBalancerClient) CreateUrlRule(kt *kit.Kit, req *hcproto.AwsRuleCreateReq) (
+	*core.CloudCreateResult, error) {
+
+	return common.Request[hcproto.AwsRuleCreateReq, core.CloudCreateResult](
+		c.client, http.MethodPost, kt, req, "/url_rules/create")
+}
+
+// UpdateUrlRule 更新转发规则
+func (c *LoadBalancerClient) UpdateUrlRule(kt *kit.Kit, id string, req *hcproto.AwsRuleUpdateReq) error {
+	return common.RequestNoResp[hcproto.AwsRuleUpdateReq](c.client, http.MethodPatch, kt, req, "/url_rules/%s", id)
+}
+
+// BatchDeleteUrlRule 批量删除转发规则
+func (c *LoadBalancerClient) BatchDeleteUrlRule(kt *kit.Kit, req *core.BatchDeleteReq) error {
+	return common.RequestNoResp[core.BatchDeleteReq](c.client, http.MethodDelete, kt, req, "/url_rules/batch")
+}
+
+// CreateTargetGroup 创建目标组
+func (c *LoadBalancerClient) CreateTargetGroup(kt *kit.Kit, req *hcproto.AwsTargetGroupCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[hcproto.AwsTargetGroupCreateReq, core.CreateResult](
+		c.client, http.MethodPost, kt, req, "/target_groups/create")
+}
+
+// UpdateTargetGroup 更新目标组
+func (c *LoadBalancerClient) UpdateTargetGroup(kt *kit.Kit, id string, req *hcproto.AwsTargetGroupUpdateReq) error {
+	return common.RequestNoResp[hcproto.AwsTargetGroupUpdateReq](c.client, http.MethodPatch, kt, req,
+		"/target_groups/%s", id)
+}
+
+// BatchDeleteTargetGroup 批量删除目标组
+func (c *LoadBalancerClient) BatchDeleteTargetGroup(kt *kit.Kit, req *core.BatchDeleteReq) error {
+	return common.RequestNoResp[core.BatchDeleteReq](c.client, http.MethodDelete, kt, req, "/target_groups/batch")
+}
+
+// BatchRegisterTargets 注册目标到目标组
+func (c *LoadBalancerClient) BatchRegisterTargets(kt *kit.Kit, tgID string, req *hcproto.AwsBatchOperateTargetReq) error {
+	return common.RequestNoResp[hcproto.AwsBatchOperateTargetReq](c.client, http.MethodPost, kt, req,
+		"/target_groups/%s/targets/create", tgID)
+}
+
+// BatchDeregisterTargets 从目标组解绑目标
+func (c *LoadBalancerClient) BatchDeregisterTargets(kt *kit.Kit, tgID string, req *hcproto.AwsBatchOperateTargetReq) error {
+	return common.RequestNoResp[hcproto.AwsBatchOperateTargetReq](c.client, http.MethodDelete, kt, req,
+		"/target_groups/%s/targets/batch", tgID)
+}
@@ -31,6 +31,7 @@ type Client struct {
 	Subnet           *SubnetClient
 	Eip              *EipClient
 	Disk             *DiskClient
+	DiskSnapshot     *DiskSnapshotClient
 	Region           *RegionClient
 	ResourceGroup    *ResourceGroupClient
 	Image            *ImageClient
@@ -39,6 +40,7 @@ type Client struct {
 	InstanceType     *InstanceTypeClient
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
+	ResourceTag      *ResourceTagClient
 }
 
 // NewClient create a new azure api client.
@@ -50,6 +52,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		Subnet:           NewSubnetClient(client),
 		Eip:              NewEipClient(client),
 		Disk:             NewCloudDiskClient(client),
+		DiskSnapshot:     NewDiskSnapshotClient(client),
 		Region:           NewRegionClient(client),
 		ResourceGroup:    NewResourceGroupClient(client),
 		Cvm:              NewCvmClient(client),
@@ -58,5 +61,6 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:     NewInstanceTypeClient(client),
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
+		ResourceTag:      NewResourceTagClient(client),
 	}
 }
@@ -31,6 +31,7 @@ type Client struct {
 	Subnet           *SubnetClient
 	Eip              *EipClient
 	Disk             *DiskClient
+	DiskSnapshot     *DiskSnapshotClient
 	Zone             *ZoneClient
 	Region           *RegionClient
 	Cvm              *CvmClient
@@ -39,6 +40,9 @@ type Client struct {
 	InstanceType     *InstanceTypeClient
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
+	CloudEvent       *CloudEventClient
+	LoadBalancer     *LoadBalancerClient
+	ResourceTag      *ResourceTagClient
 }
 
 // NewClient create a new huawei api client.
@@ -51,6 +55,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		SecurityGroup:    NewCloudSecurityGroupClient(client),
 		Eip:              NewEipClient(client),
 		Disk:             NewCloudDiskClient(client),
+		DiskSnapshot:     NewDiskSnapshotClient(client),
 		Zone:             NewZoneClient(client),
 		Region:           NewRegionClient(client),
 		Cvm:              NewCvmClient(client),
@@ -58,5 +63,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:     NewInstanceTypeClient(client),
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
+		CloudEvent:       NewCloudEventClient(client),
+		LoadBalancer:     NewLoadBalancerClient(client),
+		ResourceTag:      NewResourceTagClient(client),
 	}
 }
@@ -24,7 +24,9 @@ import (
 	"net/http"
 
 	hcbillservice "hcm/pkg/api/hc-service/bill"
+	"hcm/pkg/client/common"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
 	"hcm/pkg/rest"
 )
 
@@ -64,3 +66,11 @@ func (v *BillClient) List(ctx context.Context, h http.Header, req *hcbillservice
 
 	return resp.Data, nil
 }
+
+// GetRootAccountBillList list root account bill list filtered by main account
+func (v *BillClient) GetRootAccountBillList(kt *kit.Kit, req *hcbillservice.TCloudRootBillListReq) (
+	*hcbillservice.TCloudRootBillListResult, error) {
+
+	return common.Request[hcbillservice.TCloudRootBillListReq, hcbillservice.TCloudRootBillListResult](
+		v.client, rest.POST, kt, req, "/root_account_bills/list")
+}
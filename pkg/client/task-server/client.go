@@ -122,6 +122,17 @@ func (c *Client) GetFlow(kt *kit.Kit, id string) (*coreasync.AsyncFlow, error) {
 	return resp.Data, err
 }
 
+// GetFlowGraph get flow task graph with critical path.
+func (c *Client) GetFlowGraph(kt *kit.Kit, id string) (*apits.FlowGraphResult, error) {
+	return common.Request[common.Empty, apits.FlowGraphResult](c.client, rest.GET, kt, nil, "/flows/%s/graph", id)
+}
+
+// ListFlowQueueDepth list pending, scheduled and running flow count of each priority.
+func (c *Client) ListFlowQueueDepth(kt *kit.Kit) (*apits.FlowQueueDepthResult, error) {
+	return common.Request[common.Empty, apits.FlowQueueDepthResult](c.client, rest.GET, kt, nil,
+		"/flow_queue_depths")
+}
+
 // ListTask list task.
 func (c *Client) ListTask(kt *kit.Kit, req *core.ListReq) (*apits.ListTaskResult, error) {
 	resp := new(core.BaseResp[*apits.ListTaskResult])
@@ -183,3 +194,26 @@ func (c *Client) RetryTask(kt *kit.Kit, flowID string, taskID string) error {
 	return common.RequestNoResp[common.Empty](c.client, rest.PATCH, kt, nil,
 		"/flows/%s/tasks/%s/retry", flowID, taskID)
 }
+
+// CreateScheduledFlow 创建定时任务流
+func (c *Client) CreateScheduledFlow(kt *kit.Kit, req *apits.AddScheduledFlowReq) (*core.CreateResult, error) {
+	return common.Request[apits.AddScheduledFlowReq, core.CreateResult](c.client, rest.POST, kt, req,
+		"/scheduled_flows/create")
+}
+
+// UpdateScheduledFlow 更新定时任务流
+func (c *Client) UpdateScheduledFlow(kt *kit.Kit, id string, req *apits.UpdateScheduledFlowReq) error {
+	return common.RequestNoResp[apits.UpdateScheduledFlowReq](c.client, rest.PATCH, kt, req,
+		"/scheduled_flows/%s", id)
+}
+
+// DeleteScheduledFlow 删除定时任务流
+func (c *Client) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+	return common.RequestNoResp[common.Empty](c.client, rest.DELETE, kt, nil, "/scheduled_flows/%s", id)
+}
+
+// ListScheduledFlow 查询定时任务流
+func (c *Client) ListScheduledFlow(kt *kit.Kit, req *core.ListReq) (*apits.ListScheduledFlowResult, error) {
+	return common.Request[core.ListReq, apits.ListScheduledFlowResult](c.client, rest.POST, kt, req,
+		"/scheduled_flows/list")
+}
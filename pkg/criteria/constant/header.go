@@ -26,6 +26,9 @@ const (
 	// RidKey is request id header key.
 	RidKey = "X-Bkapi-Request-Id"
 
+	// TraceIDKey is the response header key of the OpenTelemetry trace id related to the request id.
+	TraceIDKey = "X-Hcm-Trace-Id"
+
 	// UserKey is operator name header key.
 	UserKey = "X-Bkapi-User-Name"
 
@@ -119,4 +119,6 @@ type ApplicationSource string
 const (
 	// ApplicationSourceITSM itsm 单据
 	ApplicationSourceITSM ApplicationSource = "itsm"
+	// ApplicationSourceNative 内置审批引擎单据
+	ApplicationSourceNative ApplicationSource = "native"
 )
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// ApprovalEngine 申请单使用的审批引擎
+type ApprovalEngine string
+
+const (
+	// ItsmApprovalEngine 使用蓝鲸ITSM审批
+	ItsmApprovalEngine ApprovalEngine = "itsm"
+	// NativeApprovalEngine 使用内置审批引擎
+	NativeApprovalEngine ApprovalEngine = "native"
+)
+
+// Validate ApprovalEngine.
+func (e ApprovalEngine) Validate() error {
+	switch e {
+	case ItsmApprovalEngine, NativeApprovalEngine:
+	default:
+		return fmt.Errorf("unsupported approval engine: %s", e)
+	}
+
+	return nil
+}
+
+// ApprovalMode 审批节点的会签方式
+type ApprovalMode string
+
+const (
+	// AnyApprovalMode 或签，任一审批人通过即可
+	AnyApprovalMode ApprovalMode = "any"
+	// AllApprovalMode 会签，需全部审批人通过
+	AllApprovalMode ApprovalMode = "all"
+)
+
+// Validate ApprovalMode.
+func (m ApprovalMode) Validate() error {
+	switch m {
+	case AnyApprovalMode, AllApprovalMode:
+	default:
+		return fmt.Errorf("unsupported approval mode: %s", m)
+	}
+
+	return nil
+}
+
+// ApprovalAction 审批操作
+type ApprovalAction string
+
+const (
+	// ApproveApprovalAction 审批通过
+	ApproveApprovalAction ApprovalAction = "approve"
+	// RejectApprovalAction 审批驳回
+	RejectApprovalAction ApprovalAction = "reject"
+	// WithdrawApprovalAction 申请人撤回
+	WithdrawApprovalAction ApprovalAction = "withdraw"
+	// DelegateApprovalAction 审批人转交给他人审批
+	DelegateApprovalAction ApprovalAction = "delegate"
+	// EscalateApprovalAction 审批超时升级
+	EscalateApprovalAction ApprovalAction = "escalate"
+)
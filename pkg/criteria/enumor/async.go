@@ -39,6 +39,12 @@ const (
 	TaskSuccess TaskState = "success"
 	// TaskFailed task state is failed
 	TaskFailed TaskState = "failed"
+	// TaskCompensating task state is compensating, 任务流失败后正在对已成功的任务执行补偿回滚
+	TaskCompensating TaskState = "compensating"
+	// TaskCompensated task state is compensated, 任务已补偿回滚成功
+	TaskCompensated TaskState = "compensated"
+	// TaskCompensateFailed task state is compensate_failed, 任务补偿回滚失败
+	TaskCompensateFailed TaskState = "compensate_failed"
 )
 
 // FlowState is flow state.
@@ -59,6 +65,80 @@ const (
 	FlowSuccess FlowState = "success"
 	// FlowFailed flow state is failed
 	FlowFailed FlowState = "failed"
+	// FlowCompensating flow state is compensating（开启补偿回滚的任务流失败后，正在补偿已成功的任务）
+	FlowCompensating FlowState = "compensating"
+	// FlowCompensated flow state is compensated（任务流执行失败，已成功的任务均已补偿回滚）
+	FlowCompensated FlowState = "compensated"
+	// FlowCompensateFailed flow state is compensate_failed（任务流执行失败，且补偿回滚过程中存在失败任务）
+	FlowCompensateFailed FlowState = "compensate_failed"
+)
+
+// FlowRollbackMode is flow rollback mode, define how to handle the succeeded tasks when flow failed.
+type FlowRollbackMode string
+
+// Validate FlowRollbackMode.
+func (v FlowRollbackMode) Validate() error {
+	switch v {
+	case FlowRollbackNone:
+	case FlowRollbackCompensate:
+	default:
+		return fmt.Errorf("unsupported flow rollback mode: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowRollbackNone 任务流失败后不做处理，已成功的任务保持原状
+	FlowRollbackNone FlowRollbackMode = "none"
+	// FlowRollbackCompensate 任务流失败后，按拓扑逆序对已成功的任务调用 Rollback 进行补偿
+	FlowRollbackCompensate FlowRollbackMode = "compensate"
+)
+
+// FlowPriority is flow priority, the dispatcher dispatches flows with higher priority first.
+type FlowPriority uint
+
+// Validate FlowPriority.
+func (v FlowPriority) Validate() error {
+	switch v {
+	case FlowPriorityLow:
+	case FlowPriorityNormal:
+	case FlowPriorityHigh:
+	default:
+		return fmt.Errorf("unsupported flow priority: %d", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowPriorityLow 低优先级，用于批量导入等后台任务
+	FlowPriorityLow FlowPriority = 1
+	// FlowPriorityNormal 普通优先级，未设置优先级的任务流使用该优先级
+	FlowPriorityNormal FlowPriority = 2
+	// FlowPriorityHigh 高优先级，用于用户交互触发的单个资源操作
+	FlowPriorityHigh FlowPriority = 3
+)
+
+// FlowPriorities 按派发顺序排列的任务流优先级
+var FlowPriorities = []FlowPriority{FlowPriorityHigh, FlowPriorityNormal, FlowPriorityLow}
+
+// FlowConcurrencyKeyType is the type of flow concurrency key, flows with the same concurrency key are limited
+// by the concurrency limit of the key type.
+type FlowConcurrencyKeyType string
+
+// Key 生成任务流并发控制键，格式为 <type>:<id>，如 account:00000001
+func (t FlowConcurrencyKeyType) Key(id string) string {
+	return string(t) + ":" + id
+}
+
+const (
+	// AccountConcurrencyKey 按云账号控制并发
+	AccountConcurrencyKey FlowConcurrencyKeyType = "account"
+	// RegionConcurrencyKey 按地域控制并发
+	RegionConcurrencyKey FlowConcurrencyKeyType = "region"
+	// LoadBalancerConcurrencyKey 按负载均衡控制并发
+	LoadBalancerConcurrencyKey FlowConcurrencyKeyType = "lb"
 )
 
 // BackendType is backend type.
@@ -68,6 +148,7 @@ type BackendType string
 func (v BackendType) Validate() error {
 	switch v {
 	case BackendMysql:
+	case BackendEtcd:
 	default:
 		return fmt.Errorf("unsupported backend type: %s", v)
 	}
@@ -78,4 +159,28 @@ func (v BackendType) Validate() error {
 const (
 	// BackendMysql mysql backend
 	BackendMysql BackendType = "mysql"
+	// BackendEtcd etcd backend
+	BackendEtcd BackendType = "etcd"
+)
+
+// MisfirePolicy is scheduled flow misfire policy, define how to handle the fire times missed.
+type MisfirePolicy string
+
+// Validate MisfirePolicy.
+func (v MisfirePolicy) Validate() error {
+	switch v {
+	case MisfireSkip:
+	case MisfireCatchUp:
+	default:
+		return fmt.Errorf("unsupported misfire policy: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// MisfireSkip 跳过错过的触发时间，从当前时间开始计算下一次触发时间
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireCatchUp 按触发时间顺序逐个补偿执行错过的触发
+	MisfireCatchUp MisfirePolicy = "catch_up"
 )
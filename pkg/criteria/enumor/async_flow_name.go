@@ -59,6 +59,7 @@ var defaultFlowNameMap = map[FlowName]struct{}{
 	FlowBillMainAccountSummary: {},
 	FlowBillRootAccountSummary: {},
 	FlowBillMonthTask:          {},
+	FlowRunTimingJob:           {},
 }
 
 // ValidateDefault validate default FlowName.
@@ -173,3 +174,9 @@ const (
 	FlowBillRootAccountSummary FlowName = "bill_root_account_summary"
 	FlowBillMonthTask          FlowName = "bill_month_task"
 )
+
+// 周期性任务相关Flow
+const (
+	// FlowRunTimingJob 触发周期性任务执行，由定时任务流按cron生成
+	FlowRunTimingJob FlowName = "run_timing_job"
+)
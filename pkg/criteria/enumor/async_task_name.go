@@ -48,6 +48,7 @@ func (v ActionName) Validate() error {
 	case ActionBatchTaskTCloudCreateL7Rule, ActionBatchTaskTCloudBindTarget, ActionBatchTaskTCloudCreateListener,
 		ActionBatchTaskTCloudUnBindTarget, ActionBatchTaskTCloudModifyRsWeight, ActionBatchTaskDeleteListener:
 	case ActionSyncTCloudLoadBalancer, SyncTCloudLoadBalancerListener:
+	case ActionRunTimingJob:
 
 	default:
 		return fmt.Errorf("unsupported action name type: %s", v)
@@ -155,3 +156,9 @@ const (
 	// SyncTCloudLoadBalancerListener ...
 	SyncTCloudLoadBalancerListener = "sync_tcloud_load_balancer_listener"
 )
+
+// 周期性任务相关Action
+const (
+	// ActionRunTimingJob 调用周期性任务所属服务执行单次任务
+	ActionRunTimingJob ActionName = "run_timing_job"
+)
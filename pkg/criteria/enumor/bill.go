@@ -276,3 +276,139 @@ var (
 		RootAccountBillSummaryStateStop:       "停止中",
 	}
 )
+
+// BudgetScopeType 预算范围类型
+type BudgetScopeType string
+
+const (
+	// BudgetScopeBiz 业务
+	BudgetScopeBiz BudgetScopeType = "biz"
+	// BudgetScopeMainAccount 二级账号
+	BudgetScopeMainAccount BudgetScopeType = "main_account"
+	// BudgetScopeRootAccount 一级账号
+	BudgetScopeRootAccount BudgetScopeType = "root_account"
+	// BudgetScopeVendor 云厂商
+	BudgetScopeVendor BudgetScopeType = "vendor"
+	// BudgetScopeProduct 运营产品
+	BudgetScopeProduct BudgetScopeType = "product"
+)
+
+// Validate BudgetScopeType.
+func (t BudgetScopeType) Validate() error {
+	switch t {
+	case BudgetScopeBiz, BudgetScopeMainAccount, BudgetScopeRootAccount, BudgetScopeVendor, BudgetScopeProduct:
+	default:
+		return fmt.Errorf("unsupported budget scope type: %s", t)
+	}
+
+	return nil
+}
+
+// SummaryField 返回预算范围对应的二级账号账单汇总字段
+func (t BudgetScopeType) SummaryField() string {
+	switch t {
+	case BudgetScopeBiz:
+		return "bk_biz_id"
+	case BudgetScopeMainAccount:
+		return "main_account_id"
+	case BudgetScopeRootAccount:
+		return "root_account_id"
+	case BudgetScopeVendor:
+		return "vendor"
+	case BudgetScopeProduct:
+		return "product_id"
+	default:
+		return ""
+	}
+}
+
+// BudgetPeriod 预算周期
+type BudgetPeriod string
+
+const (
+	// BudgetPeriodMonth 月度
+	BudgetPeriodMonth BudgetPeriod = "month"
+	// BudgetPeriodQuarter 季度
+	BudgetPeriodQuarter BudgetPeriod = "quarter"
+)
+
+// Validate BudgetPeriod.
+func (p BudgetPeriod) Validate() error {
+	switch p {
+	case BudgetPeriodMonth, BudgetPeriodQuarter:
+	default:
+		return fmt.Errorf("unsupported budget period: %s", p)
+	}
+
+	return nil
+}
+
+// BudgetAlertType 预算告警类型
+type BudgetAlertType string
+
+const (
+	// BudgetAlertActual 实际费用达到阈值
+	BudgetAlertActual BudgetAlertType = "actual"
+	// BudgetAlertForecast 预测费用达到阈值
+	BudgetAlertForecast BudgetAlertType = "forecast"
+)
+
+// CostAnomalySeverity 费用异常严重程度
+type CostAnomalySeverity string
+
+const (
+	// CostAnomalySeverityLow 轻微
+	CostAnomalySeverityLow CostAnomalySeverity = "low"
+	// CostAnomalySeverityMedium 中等
+	CostAnomalySeverityMedium CostAnomalySeverity = "medium"
+	// CostAnomalySeverityHigh 严重
+	CostAnomalySeverityHigh CostAnomalySeverity = "high"
+)
+
+// Validate CostAnomalySeverity
+func (s CostAnomalySeverity) Validate() error {
+	switch s {
+	case CostAnomalySeverityLow, CostAnomalySeverityMedium, CostAnomalySeverityHigh:
+	default:
+		return fmt.Errorf("unsupported cost anomaly severity: %s", s)
+	}
+
+	return nil
+}
+
+// Level 严重程度等级，数值越大越严重
+func (s CostAnomalySeverity) Level() int {
+	switch s {
+	case CostAnomalySeverityLow:
+		return 1
+	case CostAnomalySeverityMedium:
+		return 2
+	case CostAnomalySeverityHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// CostForecastDimension 费用预测的汇总维度
+type CostForecastDimension string
+
+const (
+	// CostForecastDimensionBiz 按业务汇总
+	CostForecastDimensionBiz CostForecastDimension = "biz"
+	// CostForecastDimensionMainAccount 按二级账号汇总
+	CostForecastDimensionMainAccount CostForecastDimension = "main_account"
+	// CostForecastDimensionVendor 按云厂商汇总
+	CostForecastDimensionVendor CostForecastDimension = "vendor"
+)
+
+// Validate CostForecastDimension
+func (d CostForecastDimension) Validate() error {
+	switch d {
+	case CostForecastDimensionBiz, CostForecastDimensionMainAccount, CostForecastDimensionVendor:
+	default:
+		return fmt.Errorf("unsupported cost forecast dimension: %s", d)
+	}
+
+	return nil
+}
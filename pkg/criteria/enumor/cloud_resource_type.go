@@ -75,6 +75,7 @@ const (
 	EipCloudResType              CloudResourceType = "eip"
 	CvmCloudResType              CloudResourceType = "cvm"
 	DiskCloudResType             CloudResourceType = "disk"
+	DiskSnapshotCloudResType     CloudResourceType = "disk_snapshot"
 	RouteTableCloudResType       CloudResourceType = "route_table"
 	RouteCloudResType            CloudResourceType = "route"
 	NetworkInterfaceCloudResType CloudResourceType = "network_interface"
@@ -168,11 +168,14 @@ const (
 	SuccessResFlowStatus = ResFlowStatus(FlowSuccess)
 	// CancelResFlowStatus 资源跟Flow的状态类型-取消
 	CancelResFlowStatus = ResFlowStatus(FlowCancel)
+	// CompensatedResFlowStatus 资源跟Flow的状态类型-已补偿回滚
+	CompensatedResFlowStatus = ResFlowStatus(FlowCompensated)
 )
 
 // IsEnd 是否为终态
 func (r ResFlowStatus) IsEnd() bool {
-	return r == CancelResFlowStatus || r == TimeoutResFlowStatus || r == SuccessResFlowStatus
+	return r == CancelResFlowStatus || r == TimeoutResFlowStatus || r == SuccessResFlowStatus ||
+		r == CompensatedResFlowStatus
 }
 
 // OperationType 操作类型
@@ -47,3 +47,25 @@ const (
 	// Syncing status
 	Syncing SyncStatus = "syncing"
 )
+
+// SyncCursorType 同步游标类型
+type SyncCursorType string
+
+// Validate SyncCursorType.
+func (v SyncCursorType) Validate() error {
+	switch v {
+	case CloudEventSyncCursor:
+	case ResAssignRuleCursor:
+	default:
+		return fmt.Errorf("unsupported sync cursor type: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// CloudEventSyncCursor 云审计事件增量同步游标，作用范围为 账号ID/地域，值为已同步到的时间点
+	CloudEventSyncCursor SyncCursorType = "cloud_event"
+	// ResAssignRuleCursor 资源自动分配规则增量匹配游标，作用范围为资源类型，值为上次匹配的资源创建时间点
+	ResAssignRuleCursor SyncCursorType = "res_assign_rule"
+)
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// TimingJob 周期性任务名称。周期性任务由 task-server 按定时任务流触发，在所属服务中执行单次任务逻辑。
+type TimingJob string
+
+// Validate TimingJob.
+func (j TimingJob) Validate() error {
+	switch j {
+	case RecycleDiskTimingJob, RecycleCvmTimingJob, BillConfigTimingJob, CloudResourceSyncTimingJob,
+		ApprovalEscalateTimingJob, DiskSnapshotPolicyTimingJob, CloudEventSyncTimingJob:
+	default:
+		return fmt.Errorf("unsupported timing job: %s", j)
+	}
+
+	return nil
+}
+
+const (
+	// RecycleDiskTimingJob 回收到期的硬盘
+	RecycleDiskTimingJob TimingJob = "recycle_disk"
+	// RecycleCvmTimingJob 回收到期的主机
+	RecycleCvmTimingJob TimingJob = "recycle_cvm"
+	// BillConfigTimingJob 生成云账单配置
+	BillConfigTimingJob TimingJob = "bill_config"
+	// CloudResourceSyncTimingJob 全量同步所有账号的云资源
+	CloudResourceSyncTimingJob TimingJob = "cloud_resource_sync"
+	// CloudEventSyncTimingJob 基于云上审计事件增量同步云资源
+	CloudEventSyncTimingJob TimingJob = "cloud_event_sync"
+	// ApprovalEscalateTimingJob 内置审批引擎升级超时的审批节点
+	ApprovalEscalateTimingJob TimingJob = "approval_escalate"
+	// DiskSnapshotPolicyTimingJob 执行到期的云盘快照策略
+	DiskSnapshotPolicyTimingJob TimingJob = "disk_snapshot_policy"
+)
@@ -58,6 +58,18 @@ func IsRecordNotFound(err error) bool {
 	return false
 }
 
+// IsRecordNotUpdate return true if error is a record not update error
+func IsRecordNotUpdate(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ef *ErrorF
+	if errors.As(err, &ef) {
+		return ef.Code == RecordNotUpdate
+	}
+	return false
+}
+
 // IsContextCanceled return true if error contains string "context canceled"
 func IsContextCanceled(err error) bool {
 	if err == nil {
@@ -43,10 +43,10 @@ func NewAESGcm(key []byte, nonce []byte) (*AESGcm, error) {
 }
 
 // EncryptToBase64 : 将字符串明文，使用AES Gcm算法加密后再转化为Base64格式的字符串
-func (a *AESGcm) EncryptToBase64(plaintext string) string {
+func (a *AESGcm) EncryptToBase64(plaintext string) (string, error) {
 	plaintextBytes := conv.StringToBytes(plaintext)
 	encryptedText := a.Encrypt(plaintextBytes)
-	return base64.StdEncoding.EncodeToString(encryptedText)
+	return base64.StdEncoding.EncodeToString(encryptedText), nil
 }
 
 // DecryptFromBase64 : 将Base64格式的AES Gcm密文，解密为明文字符串
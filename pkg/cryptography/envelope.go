@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TencentBlueKing/gopkg/conv"
+)
+
+const (
+	// envelopePrefix 信封加密密文前缀，历史的AES-GCM密文为纯Base64字符串，不包含分隔符
+	envelopePrefix = "hcmenc"
+	envelopeSep    = ":"
+	dataKeySize    = 32
+)
+
+// Envelope 信封加密，每条记录使用随机生成的数据密钥(DEK)加密，DEK由KMS中的KEK包装后随密文存储，
+// 密文格式为 hcmenc:<KEK版本>:<Base64格式的包装后DEK>:<Base64格式的nonce及密文>
+type Envelope struct {
+	kms KMS
+	// legacy 用于解密未使用信封加密的历史密文，为空时不支持历史密文
+	legacy Crypto
+}
+
+// NewEnvelope new envelope crypto.
+func NewEnvelope(kms KMS, legacy Crypto) *Envelope {
+	return &Envelope{kms: kms, legacy: legacy}
+}
+
+// envelopeText 信封加密密文
+type envelopeText struct {
+	version string
+	wrapped []byte
+	data    []byte
+}
+
+// String 序列化为存储的密文格式
+func (t *envelopeText) String() string {
+	return strings.Join([]string{envelopePrefix, t.version, base64.StdEncoding.EncodeToString(t.wrapped),
+		base64.StdEncoding.EncodeToString(t.data)}, envelopeSep)
+}
+
+// IsEnvelope 判断密文是否为信封加密的密文
+func IsEnvelope(encryptedText string) bool {
+	return strings.HasPrefix(encryptedText, envelopePrefix+envelopeSep)
+}
+
+func parseEnvelope(encryptedText string) (*envelopeText, error) {
+	fields := strings.Split(encryptedText, envelopeSep)
+	if len(fields) != 4 || fields[0] != envelopePrefix {
+		return nil, errors.New("invalid envelope encrypted text")
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode wrapped data key failed, err: %v", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("decode envelope data failed, err: %v", err)
+	}
+
+	return &envelopeText{version: fields[1], wrapped: wrapped, data: data}, nil
+}
+
+// EncryptToBase64 使用随机数据密钥加密明文，并使用当前版本的KEK包装数据密钥
+func (e *Envelope) EncryptToBase64(plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("generate data key failed, err: %v", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := sealWithRandomNonce(aead, conv.StringToBytes(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("encrypt data failed, err: %v", err)
+	}
+
+	version, wrapped, err := e.kms.Wrap(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key failed, err: %v", err)
+	}
+
+	if err = validateKeyVersion(version); err != nil {
+		return "", err
+	}
+
+	return (&envelopeText{version: version, wrapped: wrapped, data: data}).String(), nil
+}
+
+// DecryptFromBase64 解密信封加密的密文，非信封加密的历史密文使用历史加解密器解密
+func (e *Envelope) DecryptFromBase64(encryptedText string) (string, error) {
+	if !IsEnvelope(encryptedText) {
+		if e.legacy == nil {
+			return "", errors.New("legacy encrypted text is not supported")
+		}
+		return e.legacy.DecryptFromBase64(encryptedText)
+	}
+
+	text, err := parseEnvelope(encryptedText)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := e.kms.Unwrap(text.version, text.wrapped)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key failed, err: %v", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := openWithNonce(aead, text.data)
+	if err != nil {
+		return "", fmt.Errorf("decrypt data failed, err: %v", err)
+	}
+
+	return conv.BytesToString(plaintext), nil
+}
+
+// Rewrap 使用当前版本的KEK重新包装数据密钥，数据密文保持不变；历史密文重新使用信封加密
+func (e *Envelope) Rewrap(encryptedText string) (string, bool, error) {
+	if !IsEnvelope(encryptedText) {
+		plaintext, err := e.DecryptFromBase64(encryptedText)
+		if err != nil {
+			return "", false, err
+		}
+
+		reEncrypted, err := e.EncryptToBase64(plaintext)
+		if err != nil {
+			return "", false, err
+		}
+		return reEncrypted, true, nil
+	}
+
+	text, err := parseEnvelope(encryptedText)
+	if err != nil {
+		return "", false, err
+	}
+
+	current, err := e.kms.CurrentVersion()
+	if err != nil {
+		return "", false, fmt.Errorf("get current kms key version failed, err: %v", err)
+	}
+
+	if text.version == current {
+		return encryptedText, false, nil
+	}
+
+	dataKey, err := e.kms.Unwrap(text.version, text.wrapped)
+	if err != nil {
+		return "", false, fmt.Errorf("unwrap data key failed, err: %v", err)
+	}
+
+	version, wrapped, err := e.kms.Wrap(dataKey)
+	if err != nil {
+		return "", false, fmt.Errorf("wrap data key failed, err: %v", err)
+	}
+
+	rewrapped := &envelopeText{version: version, wrapped: wrapped, data: text.data}
+	return rewrapped.String(), true, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeKeyFile(t *testing.T, path string, primary string, versions ...string) {
+	keys := make([]string, 0, len(versions))
+	for _, version := range versions {
+		key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(version, 32)[:32]))
+		keys = append(keys, fmt.Sprintf(`"%s":"%s"`, version, key))
+	}
+
+	content := fmt.Sprintf(`{"primary":"%s","keys":{%s}}`, primary, strings.Join(keys, ","))
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write key file failed, err: %v", err)
+	}
+
+	// 确保修改时间变化，触发密钥文件重新加载
+	modTime := time.Now().Add(time.Duration(len(versions)) * time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("change key file time failed, err: %v", err)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kek.json")
+	writeKeyFile(t, path, "1", "1")
+
+	kms, err := NewLocalKMS(path)
+	if err != nil {
+		t.Fatalf("new local kms failed, err: %v", err)
+	}
+
+	legacy, err := NewAESGcm([]byte("0123456789abcdef"), []byte("0123456789ab"))
+	if err != nil {
+		t.Fatalf("new aes gcm failed, err: %v", err)
+	}
+	envelope := NewEnvelope(kms, legacy)
+
+	encrypted, err := envelope.EncryptToBase64("secret")
+	if err != nil {
+		t.Fatalf("encrypt failed, err: %v", err)
+	}
+
+	if !strings.HasPrefix(encrypted, "hcmenc:1:") {
+		t.Fatalf("encrypted text should carry kek version, got: %s", encrypted)
+	}
+
+	another, _ := envelope.EncryptToBase64("secret")
+	if another == encrypted {
+		t.Fatalf("each encryption should use a different data key")
+	}
+
+	// 历史密文使用AES-GCM解密
+	legacyEncrypted, _ := legacy.EncryptToBase64("legacy")
+	if plaintext, err := envelope.DecryptFromBase64(legacyEncrypted); err != nil || plaintext != "legacy" {
+		t.Fatalf("decrypt legacy text failed, plaintext: %s, err: %v", plaintext, err)
+	}
+
+	if _, changed, _ := envelope.Rewrap(encrypted); changed {
+		t.Fatalf("text encrypted with current kek should not be rewrapped")
+	}
+
+	// 轮换KEK后，旧密文仍可解密，重新包装后使用新版本KEK
+	writeKeyFile(t, path, "2", "1", "2")
+
+	rewrapped, changed, err := envelope.Rewrap(encrypted)
+	if err != nil || !changed {
+		t.Fatalf("rewrap failed, changed: %v, err: %v", changed, err)
+	}
+
+	if !strings.HasPrefix(rewrapped, "hcmenc:2:") {
+		t.Fatalf("rewrapped text should use new kek version, got: %s", rewrapped)
+	}
+
+	for _, text := range []string{encrypted, rewrapped} {
+		if plaintext, err := envelope.DecryptFromBase64(text); err != nil || plaintext != "secret" {
+			t.Fatalf("decrypt failed, plaintext: %s, err: %v", plaintext, err)
+		}
+	}
+
+	rewrappedLegacy, changed, err := envelope.Rewrap(legacyEncrypted)
+	if err != nil || !changed || !IsEnvelope(rewrappedLegacy) {
+		t.Fatalf("rewrap legacy text failed, changed: %v, err: %v", changed, err)
+	}
+}
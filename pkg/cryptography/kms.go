@@ -0,0 +1,217 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KMS 密钥管理服务，使用密钥加密密钥(KEK)包装和解包数据密钥(DEK)
+type KMS interface {
+	// CurrentVersion 返回当前用于包装数据密钥的KEK版本
+	CurrentVersion() (string, error)
+	// Wrap 使用当前版本的KEK包装数据密钥，返回包装使用的KEK版本
+	Wrap(dataKey []byte) (version string, wrapped []byte, err error)
+	// Unwrap 使用指定版本的KEK解包数据密钥
+	Unwrap(version string, wrapped []byte) ([]byte, error)
+}
+
+// localKeyFile 本地密钥文件格式，keys 为KEK版本到Base64格式的32字节密钥的映射，primary 为当前使用的KEK版本
+type localKeyFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKMS 基于本地密钥文件的KMS，密钥文件变更后自动重新加载，便于在线轮换KEK
+type LocalKMS struct {
+	path string
+
+	lock    sync.RWMutex
+	modTime time.Time
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKMS new local key file kms.
+func NewLocalKMS(path string) (*LocalKMS, error) {
+	if len(path) == 0 {
+		return nil, errors.New("local kms key file is not set")
+	}
+
+	k := &LocalKMS{path: path}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// reload 密钥文件修改时间变化时重新加载密钥
+func (k *LocalKMS) reload() error {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return fmt.Errorf("stat kms key file failed, err: %v", err)
+	}
+
+	k.lock.RLock()
+	unchanged := info.ModTime().Equal(k.modTime)
+	k.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	content, err := os.ReadFile(k.path)
+	if err != nil {
+		return fmt.Errorf("read kms key file failed, err: %v", err)
+	}
+
+	keyFile := new(localKeyFile)
+	if err = json.Unmarshal(content, keyFile); err != nil {
+		return fmt.Errorf("unmarshal kms key file failed, err: %v", err)
+	}
+
+	keys := make(map[string]cipher.AEAD, len(keyFile.Keys))
+	for version, keyB64 := range keyFile.Keys {
+		if err = validateKeyVersion(version); err != nil {
+			return err
+		}
+
+		key, err := base64.StdEncoding.DecodeString(keyB64)
+		if err != nil {
+			return fmt.Errorf("decode kms key of version %s failed, err: %v", version, err)
+		}
+
+		if len(key) != 32 {
+			return fmt.Errorf("kms key of version %s should be 32 bytes", version)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return err
+		}
+		keys[version] = aead
+	}
+
+	if _, exists := keys[keyFile.Primary]; !exists {
+		return fmt.Errorf("primary kms key version %s not exists in key file", keyFile.Primary)
+	}
+
+	k.lock.Lock()
+	k.modTime = info.ModTime()
+	k.primary = keyFile.Primary
+	k.keys = keys
+	k.lock.Unlock()
+
+	return nil
+}
+
+// CurrentVersion returns primary key version in the key file.
+func (k *LocalKMS) CurrentVersion() (string, error) {
+	if err := k.reload(); err != nil {
+		return "", err
+	}
+
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return k.primary, nil
+}
+
+// Wrap data key with primary key.
+func (k *LocalKMS) Wrap(dataKey []byte) (string, []byte, error) {
+	if err := k.reload(); err != nil {
+		return "", nil, err
+	}
+
+	k.lock.RLock()
+	version, aead := k.primary, k.keys[k.primary]
+	k.lock.RUnlock()
+
+	wrapped, err := sealWithRandomNonce(aead, dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return version, wrapped, nil
+}
+
+// Unwrap data key with the key of the version.
+func (k *LocalKMS) Unwrap(version string, wrapped []byte) ([]byte, error) {
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+
+	k.lock.RLock()
+	aead, exists := k.keys[version]
+	k.lock.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("kms key of version %s not exists", version)
+	}
+
+	return openWithNonce(aead, wrapped)
+}
+
+// validateKeyVersion KEK版本会写入密文前缀，不能为空或包含分隔符
+func validateKeyVersion(version string) error {
+	if len(version) == 0 || strings.Contains(version, envelopeSep) {
+		return fmt.Errorf("invalid kms key version: %q", version)
+	}
+
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealWithRandomNonce 使用随机nonce加密，返回nonce与密文拼接后的结果
+func sealWithRandomNonce(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openWithNonce 解密 sealWithRandomNonce 加密的结果
+func openWithNonce(aead cipher.AEAD, encrypted []byte) ([]byte, error) {
+	if len(encrypted) < aead.NonceSize() {
+		return nil, errors.New("encrypted text is too short")
+	}
+
+	nonceSize := aead.NonceSize()
+	return aead.Open(nil, encrypted[:nonceSize], encrypted[nonceSize:], nil)
+}
@@ -0,0 +1,75 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import "fmt"
+
+// KMSType kms provider type.
+type KMSType string
+
+const (
+	// LocalKMSType 本地密钥文件
+	LocalKMSType KMSType = "local"
+	// VaultKMSType HashiCorp Vault Transit 引擎
+	VaultKMSType KMSType = "vault"
+)
+
+// Option 加解密器配置
+type Option struct {
+	AesGcmKey   string
+	AesGcmNonce string
+	// Envelope 为空时使用AES-GCM加解密，否则使用信封加密，AES-GCM仅用于解密历史密文
+	Envelope *EnvelopeOption
+}
+
+// EnvelopeOption 信封加密配置
+type EnvelopeOption struct {
+	KMS          KMSType
+	LocalKeyFile string
+	Vault        VaultOption
+}
+
+// New 根据配置生成对应的加解密器
+func New(opt Option) (Crypto, error) {
+	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
+	aesGcm, err := NewAESGcm([]byte(opt.AesGcmKey), []byte(opt.AesGcmNonce))
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Envelope == nil {
+		return aesGcm, nil
+	}
+
+	var kms KMS
+	switch opt.Envelope.KMS {
+	case LocalKMSType:
+		kms, err = NewLocalKMS(opt.Envelope.LocalKeyFile)
+	case VaultKMSType:
+		kms, err = NewVaultKMS(opt.Envelope.Vault)
+	default:
+		return nil, fmt.Errorf("unsupported kms type: %s", opt.Envelope.KMS)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEnvelope(kms, aesGcm), nil
+}
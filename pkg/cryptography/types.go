@@ -19,14 +19,15 @@
 
 package cryptography
 
-// Crypto 定义了需要实现的三组加解密方法，分别是以字节、字符串、Base64字符串格式的方法
+// Crypto 定义了需要实现的加解密方法，明文加密后以字符串格式的密文存储
 type Crypto interface {
-	Encrypt(plaintext []byte) []byte
-	Decrypt(encryptedText []byte) ([]byte, error)
-
-	EncryptToString(plaintext []byte) string
-	DecryptString(encryptedText string) ([]byte, error)
-
-	EncryptToBase64(plaintext string) string
+	EncryptToBase64(plaintext string) (string, error)
 	DecryptFromBase64(encryptedTextB64 string) (string, error)
 }
+
+// Rewrapper 支持密钥轮换的加解密器，可将旧版本密钥加密的密文转换为当前版本密钥加密的密文
+type Rewrapper interface {
+	Crypto
+	// Rewrap 使用当前版本的密钥重新包装密文，密文无需轮换时返回false
+	Rewrap(encryptedText string) (string, bool, error)
+}
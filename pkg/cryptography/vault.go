@@ -0,0 +1,224 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hcm/pkg/tools/ssl"
+)
+
+const (
+	vaultCiphertextPrefix = "vault:v"
+	vaultVersionCacheTTL  = time.Minute
+)
+
+// VaultOption HashiCorp Vault Transit 引擎配置
+type VaultOption struct {
+	Address   string
+	Token     string
+	Namespace string
+	// MountPath Transit 引擎挂载路径，默认为 transit
+	MountPath string
+	KeyName   string
+	Timeout   time.Duration
+
+	InsecureSkipVerify bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	Password           string
+}
+
+// VaultKMS 基于 HashiCorp Vault Transit 引擎的KMS，KEK版本即 Transit 密钥的版本
+type VaultKMS struct {
+	opt    VaultOption
+	client *http.Client
+
+	lock          sync.Mutex
+	latestVersion string
+	fetchedAt     time.Time
+}
+
+// NewVaultKMS new vault transit kms.
+func NewVaultKMS(opt VaultOption) (*VaultKMS, error) {
+	if len(opt.Address) == 0 || len(opt.Token) == 0 || len(opt.KeyName) == 0 {
+		return nil, errors.New("vault kms address, token and key name are required")
+	}
+
+	if len(opt.MountPath) == 0 {
+		opt.MountPath = "transit"
+	}
+
+	if opt.Timeout == 0 {
+		opt.Timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if strings.HasPrefix(opt.Address, "https://") {
+		tlsConf, err := ssl.ClientTLSConfVerify(opt.InsecureSkipVerify, opt.CAFile, opt.CertFile, opt.KeyFile,
+			opt.Password)
+		if err != nil {
+			return nil, fmt.Errorf("init vault tls config failed, err: %v", err)
+		}
+		transport.TLSClientConfig = tlsConf
+	}
+
+	return &VaultKMS{
+		opt:    opt,
+		client: &http.Client{Transport: transport, Timeout: opt.Timeout},
+	}, nil
+}
+
+// CurrentVersion returns latest version of the transit key, the result is cached for a while.
+func (v *VaultKMS) CurrentVersion() (string, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if len(v.latestVersion) != 0 && time.Since(v.fetchedAt) < vaultVersionCacheTTL {
+		return v.latestVersion, nil
+	}
+
+	resp := new(struct {
+		LatestVersion int `json:"latest_version"`
+	})
+	if err := v.do(http.MethodGet, "keys", nil, resp); err != nil {
+		return "", err
+	}
+
+	v.latestVersion = strconv.Itoa(resp.LatestVersion)
+	v.fetchedAt = time.Now()
+	return v.latestVersion, nil
+}
+
+// Wrap data key with latest version of the transit key.
+func (v *VaultKMS) Wrap(dataKey []byte) (string, []byte, error) {
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	resp := new(struct {
+		Ciphertext string `json:"ciphertext"`
+	})
+	if err := v.do(http.MethodPost, "encrypt", req, resp); err != nil {
+		return "", nil, err
+	}
+
+	version, err := parseVaultVersion(resp.Ciphertext)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return version, []byte(resp.Ciphertext), nil
+}
+
+// Unwrap data key, vault ciphertext carries the key version itself.
+func (v *VaultKMS) Unwrap(version string, wrapped []byte) ([]byte, error) {
+	wrappedVersion, err := parseVaultVersion(string(wrapped))
+	if err != nil {
+		return nil, err
+	}
+
+	if wrappedVersion != version {
+		return nil, fmt.Errorf("vault key version %s mismatch with envelope version %s", wrappedVersion, version)
+	}
+
+	req := map[string]string{"ciphertext": string(wrapped)}
+	resp := new(struct {
+		Plaintext string `json:"plaintext"`
+	})
+	if err = v.do(http.MethodPost, "decrypt", req, resp); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// do 调用 Transit 引擎接口，并将响应中的 data 字段解析到 result
+func (v *VaultKMS) do(method, action string, body interface{}, result interface{}) error {
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(v.opt.Address, "/"), v.opt.MountPath, action,
+		v.opt.KeyName)
+
+	var reader io.Reader
+	if body != nil {
+		marshalled, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(marshalled)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.opt.Token)
+	if len(v.opt.Namespace) != 0 {
+		req.Header.Set("X-Vault-Namespace", v.opt.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request vault %s failed, err: %v", action, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read vault %s response failed, err: %v", action, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault %s failed, status: %d, body: %s", action, resp.StatusCode, content)
+	}
+
+	data := &struct {
+		Data interface{} `json:"data"`
+	}{Data: result}
+	if err = json.Unmarshal(content, data); err != nil {
+		return fmt.Errorf("unmarshal vault %s response failed, err: %v", action, err)
+	}
+
+	return nil
+}
+
+// parseVaultVersion 解析 Transit 密文中的密钥版本，密文格式为 vault:v<version>:<base64>
+func parseVaultVersion(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, vaultCiphertextPrefix) {
+		return "", errors.New("invalid vault ciphertext")
+	}
+
+	version, _, found := strings.Cut(strings.TrimPrefix(ciphertext, vaultCiphertextPrefix), ":")
+	if !found || len(version) == 0 {
+		return "", errors.New("invalid vault ciphertext")
+	}
+
+	return version, nil
+}
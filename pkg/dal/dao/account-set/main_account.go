@@ -45,6 +45,7 @@ import (
 type MainAccount interface {
 	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, account *tableaccountset.MainAccountTable) (string, error)
 	Update(kt *kit.Kit, expr *filter.Expression, model *tableaccountset.MainAccountTable) error
+	UpdateExtensionField(kt *kit.Kit, expr *filter.Expression, path []string, oldValue string, value string) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListMainAccountDetails, error)
 }
 
@@ -151,6 +152,53 @@ func (a MainAccountDao) Update(kt *kit.Kit, filterExpr *filter.Expression,
 	return nil
 }
 
+// UpdateExtensionField 仅当二级账号扩展字段中的一个字段等于原值时更新该字段，不覆盖扩展字段中的其他字段，
+// 字段不等于原值或记录不存在时返回 RecordNotFound 错误
+func (a MainAccountDao) UpdateExtensionField(kt *kit.Kit, filterExpr *filter.Expression, path []string, oldValue string,
+	value string) error {
+
+	if filterExpr == nil || len(filterExpr.Rules) == 0 {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	setExpr, cmpExpr, err := tools.JSONFieldCASExpr("extension", path)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s SET %s, reviser = :reviser %s AND %s`, table.MainAccountTable, setExpr, whereExpr,
+		cmpExpr)
+	toUpdate := map[string]interface{}{tools.JSONFieldValuePlaceholder: value,
+		tools.JSONFieldOldValuePlaceholder: oldValue, "reviser": kt.User}
+
+	_, err = a.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		effected, err := a.Orm.Txn(txn).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+		if err != nil {
+			logs.ErrorJson("update main account extension field failed, err: %v, filter: %s, path: %v, rid: %v", err,
+				filterExpr, path, kt.Rid)
+			return nil, err
+		}
+
+		if effected == 0 {
+			logs.ErrorJson("update main account extension field, but record not found, filter: %v, path: %v, rid: %v",
+				filterExpr, path, kt.Rid)
+			return nil, errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // List list main accounts.
 func (ma MainAccountDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListMainAccountDetails, error) {
 	if opt == nil {
@@ -43,6 +43,8 @@ import (
 type Application interface {
 	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApplicationTable) (string, error)
 	Update(kt *kit.Kit, expr *filter.Expression, model *application.ApplicationTable) error
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *application.ApplicationTable) error
+	UpdateContentField(kt *kit.Kit, expr *filter.Expression, path []string, oldValue string, value string) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListApplicationDetails, error)
 }
 
@@ -123,6 +125,91 @@ func (a *ApplicationDao) Update(kt *kit.Kit, filterExpr *filter.Expression, mode
 	return nil
 }
 
+// UpdateWithTx update application with transaction, 未匹配到记录时返回RecordNotFound。
+func (a *ApplicationDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression,
+	model *application.ApplicationTable) error {
+
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, model.TableName(), setExpr, whereExpr)
+	effected, err := a.Orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+	if err != nil {
+		logs.ErrorJson("update application failed, err: %v, filter: %s, rid: %v", err, filterExpr, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		logs.ErrorJson("update application, but record not found, filter: %v, rid: %v", filterExpr, kt.Rid)
+		return errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+	}
+
+	return nil
+}
+
+// UpdateContentField 仅当申请单内容中的一个字段等于原值时更新该字段，不覆盖内容中的其他字段，
+// 字段不等于原值或记录不存在时返回 RecordNotFound 错误
+func (a *ApplicationDao) UpdateContentField(kt *kit.Kit, filterExpr *filter.Expression, path []string, oldValue string,
+	value string) error {
+
+	if filterExpr == nil || len(filterExpr.Rules) == 0 {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	setExpr, cmpExpr, err := tools.JSONFieldCASExpr("content", path)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s SET %s, reviser = :reviser %s AND %s`, table.ApplicationTable, setExpr, whereExpr,
+		cmpExpr)
+	toUpdate := map[string]interface{}{tools.JSONFieldValuePlaceholder: value,
+		tools.JSONFieldOldValuePlaceholder: oldValue, "reviser": kt.User}
+
+	_, err = a.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		effected, err := a.Orm.Txn(txn).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+		if err != nil {
+			logs.ErrorJson("update application content field failed, err: %v, filter: %s, path: %v, rid: %v", err,
+				filterExpr, path, kt.Rid)
+			return nil, err
+		}
+
+		if effected == 0 {
+			logs.ErrorJson("update application content field, but record not found, filter: %v, path: %v, rid: %v",
+				filterExpr, path, kt.Rid)
+			return nil, errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // List ...
 func (a *ApplicationDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListApplicationDetails, error) {
 	if opt == nil {
@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/application"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ApprovalChain defines approval chain dao operations.
+type ApprovalChain interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []application.ApprovalChainTable) ([]string, error)
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *application.ApprovalChainTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalChainDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ ApprovalChain = new(approvalChainDao)
+
+// approvalChainDao approval chain dao.
+type approvalChainDao struct {
+	orm   orm.Interface
+	idGen idgenerator.IDGenInterface
+}
+
+// NewApprovalChainDao create an approval chain dao.
+func NewApprovalChainDao(orm orm.Interface, idGen idgenerator.IDGenInterface) ApprovalChain {
+	return &approvalChainDao{
+		orm:   orm,
+		idGen: idGen,
+	}
+}
+
+// BatchCreateWithTx create approval chain with transaction.
+func (a *approvalChainDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []application.ApprovalChainTable) (
+	[]string, error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := a.idGen.Batch(kt, table.ApprovalChainTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range models {
+		models[idx].ID = ids[idx]
+
+		if err = models[idx].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.ApprovalChainTable,
+		application.ApprovalChainColumns.ColumnExpr(), application.ApprovalChainColumns.ColonNameExpr())
+
+	if err = a.orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.ApprovalChainTable, err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.ApprovalChainTable, err)
+	}
+
+	return ids, nil
+}
+
+// UpdateWithTx update approval chain with transaction.
+func (a *approvalChainDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *application.ApprovalChainTable) error {
+
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddBlankedFields("memo").AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, table.ApprovalChainTable, setExpr, whereExpr)
+	if _, err = a.orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue)); err != nil {
+		logs.ErrorJson("update approval chain failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// List approval chains.
+func (a *approvalChainDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalChainDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list approval chain options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(application.ApprovalChainColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is a count request, do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ApprovalChainTable, whereExpr)
+
+		count, err := a.orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count approval chains failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListApprovalChainDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, application.ApprovalChainColumns.FieldsNamedExpr(opt.Fields),
+		table.ApprovalChainTable, whereExpr, pageExpr)
+
+	details := make([]application.ApprovalChainTable, 0)
+	if err = a.orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+
+	return &types.ListApprovalChainDetails{Details: details}, nil
+}
+
+// DeleteWithTx delete approval chain with transaction.
+func (a *approvalChainDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.ApprovalChainTable, whereExpr)
+	if _, err = a.orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete approval chain failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/application"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ApprovalTicket defines approval ticket dao operations.
+type ApprovalTicket interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApprovalTicketTable) (string, error)
+	Update(kt *kit.Kit, expr *filter.Expression, model *application.ApprovalTicketTable) error
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *application.ApprovalTicketTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalTicketDetails, error)
+}
+
+var _ ApprovalTicket = new(approvalTicketDao)
+
+// approvalTicketDao approval ticket dao.
+type approvalTicketDao struct {
+	orm   orm.Interface
+	idGen idgenerator.IDGenInterface
+}
+
+// NewApprovalTicketDao create an approval ticket dao.
+func NewApprovalTicketDao(orm orm.Interface, idGen idgenerator.IDGenInterface) ApprovalTicket {
+	return &approvalTicketDao{
+		orm:   orm,
+		idGen: idGen,
+	}
+}
+
+// CreateWithTx create approval ticket with transaction.
+func (a *approvalTicketDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApprovalTicketTable) (
+	string, error) {
+
+	id, err := a.idGen.One(kt, table.ApprovalTicketTable)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	if err = model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.ApprovalTicketTable,
+		application.ApprovalTicketColumns.ColumnExpr(), application.ApprovalTicketColumns.ColonNameExpr())
+
+	if err = a.orm.Txn(tx).Insert(kt.Ctx, sql, model); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.ApprovalTicketTable, err, kt.Rid)
+		return "", fmt.Errorf("insert %s failed, err: %v", table.ApprovalTicketTable, err)
+	}
+
+	return id, nil
+}
+
+// Update approval ticket. 调用方需要在过滤条件中带上旧的version，并发审批时只有一个能更新成功，
+// 其余的返回RecordNotFound。
+func (a *approvalTicketDao) Update(kt *kit.Kit, expr *filter.Expression,
+	model *application.ApprovalTicketTable) error {
+
+	sql, args, err := a.prepareUpdate(expr, model)
+	if err != nil {
+		return err
+	}
+
+	effected, err := a.orm.Do().Update(kt.Ctx, sql, args)
+	return a.checkUpdateResult(kt, expr, effected, err)
+}
+
+// UpdateWithTx update approval ticket with transaction. 版本冲突时同 Update 返回RecordNotFound。
+func (a *approvalTicketDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *application.ApprovalTicketTable) error {
+
+	sql, args, err := a.prepareUpdate(expr, model)
+	if err != nil {
+		return err
+	}
+
+	effected, err := a.orm.Txn(tx).Update(kt.Ctx, sql, args)
+	return a.checkUpdateResult(kt, expr, effected, err)
+}
+
+func (a *approvalTicketDao) prepareUpdate(expr *filter.Expression, model *application.ApprovalTicketTable) (
+	string, map[string]interface{}, error) {
+
+	if expr == nil {
+		return "", nil, errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return "", nil, err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return "", nil, err
+	}
+
+	opts := utils.NewFieldOptions().AddBlankedFields("current_approvers", "deadline").
+		AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return "", nil, fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, table.ApprovalTicketTable, setExpr, whereExpr)
+	return sql, tools.MapMerge(toUpdate, whereValue), nil
+}
+
+func (a *approvalTicketDao) checkUpdateResult(kt *kit.Kit, expr *filter.Expression, effected int64, err error) error {
+	if err != nil {
+		logs.ErrorJson("update approval ticket failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		logs.ErrorJson("update approval ticket, but record not found, filter: %s, rid: %s", expr, kt.Rid)
+		return errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+	}
+
+	return nil
+}
+
+// List approval tickets.
+func (a *approvalTicketDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalTicketDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list approval ticket options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(application.ApprovalTicketColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is a count request, do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ApprovalTicketTable, whereExpr)
+
+		count, err := a.orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count approval tickets failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListApprovalTicketDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, application.ApprovalTicketColumns.FieldsNamedExpr(opt.Fields),
+		table.ApprovalTicketTable, whereExpr, pageExpr)
+
+	details := make([]application.ApprovalTicketTable, 0)
+	if err = a.orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+
+	return &types.ListApprovalTicketDetails{Details: details}, nil
+}
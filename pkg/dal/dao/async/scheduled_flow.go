@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// AsyncScheduledFlow only used async scheduled flow.
+type AsyncScheduledFlow interface {
+	Create(kt *kit.Kit, model *tableasync.AsyncScheduledFlowTable) (string, error)
+	UpdateByID(kt *kit.Kit, id string, model *tableasync.AsyncScheduledFlowTable) error
+	UpdateNextRunAtByCAS(kt *kit.Kit, info *typesasync.UpdateScheduledFlowNextRunInfo) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncScheduledFlows, error)
+	DeleteByID(kt *kit.Kit, id string) error
+}
+
+var _ AsyncScheduledFlow = new(AsyncScheduledFlowDao)
+
+// AsyncScheduledFlowDao async scheduled flow dao.
+type AsyncScheduledFlowDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// Create async scheduled flow.
+func (dao *AsyncScheduledFlowDao) Create(kt *kit.Kit, model *tableasync.AsyncScheduledFlowTable) (string, error) {
+
+	id, err := dao.IDGen.One(kt, table.AsyncScheduledFlowTable)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	if err = model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AsyncScheduledFlowTable,
+		tableasync.AsyncScheduledFlowColumns.ColumnExpr(), tableasync.AsyncScheduledFlowColumns.ColonNameExpr())
+
+	if err = dao.Orm.Do().Insert(kt.Ctx, sql, model); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AsyncScheduledFlowTable, err, sql, kt.Rid)
+		return "", fmt.Errorf("insert %s failed, err: %v", table.AsyncScheduledFlowTable, err)
+	}
+
+	return id, nil
+}
+
+// UpdateByID async scheduled flow.
+func (dao *AsyncScheduledFlowDao) UpdateByID(kt *kit.Kit, id string,
+	model *tableasync.AsyncScheduledFlowTable) error {
+
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s where id = :id`, model.TableName(), setExpr)
+
+	toUpdate["id"] = id
+	if _, err = dao.Orm.Do().Update(kt.Ctx, sql, toUpdate); err != nil {
+		logs.Errorf("update async scheduled flow failed, err: %v, id: %s, sql: %s, rid: %v", err, id, sql, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNextRunAtByCAS update async scheduled flow next run at by CAS.
+func (dao *AsyncScheduledFlowDao) UpdateNextRunAtByCAS(kt *kit.Kit,
+	info *typesasync.UpdateScheduledFlowNextRunInfo) error {
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`update %s set next_run_at = :target, reviser = :reviser where id = :id and `+
+		`next_run_at = :source`, table.AsyncScheduledFlowTable)
+
+	whereValue := map[string]interface{}{
+		"id":      info.ID,
+		"source":  info.Source,
+		"target":  info.Target,
+		"reviser": kt.User,
+	}
+	effected, err := dao.Orm.Do().Update(kt.Ctx, sql, whereValue)
+	if err != nil {
+		logs.Errorf("update async scheduled flow next run at failed, err: %v, id: %s, sql: %s, rid: %v", err,
+			info.ID, sql, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		return errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] update next run at: `%s`->`%s` failed",
+			info.ID, info.Source, info.Target)
+	}
+
+	return nil
+}
+
+// List async scheduled flow.
+func (dao *AsyncScheduledFlowDao) List(kt *kit.Kit, opt *types.ListOption) (
+	*typesasync.ListAsyncScheduledFlows, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async scheduled flow options is nil")
+	}
+
+	columnTypes := tableasync.AsyncScheduledFlowColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AsyncScheduledFlowTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count async scheduled flow failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesasync.ListAsyncScheduledFlows{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tableasync.AsyncScheduledFlowColumns.FieldsNamedExpr(opt.Fields),
+		table.AsyncScheduledFlowTable, whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncScheduledFlowTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async scheduled flow failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncScheduledFlows{Count: 0, Details: details}, nil
+}
+
+// DeleteByID async scheduled flow.
+func (dao *AsyncScheduledFlowDao) DeleteByID(kt *kit.Kit, id string) error {
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s where id = :id`, table.AsyncScheduledFlowTable)
+	if _, err := dao.Orm.Do().Delete(kt.Ctx, sql, map[string]interface{}{"id": id}); err != nil {
+		logs.Errorf("delete async scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
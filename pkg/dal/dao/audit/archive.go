@@ -0,0 +1,181 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ArchiveInterface define audit archive interface.
+type ArchiveInterface interface {
+	ListExpired(kt *kit.Kit, opt *ListExpiredOption) ([]audit.AuditTable, error)
+	CreateArchive(kt *kit.Kit, archive *audit.AuditArchiveTable, ids []uint64) error
+	ListArchive(kt *kit.Kit, opt *ListArchiveOption) ([]audit.AuditArchiveTable, error)
+}
+
+var _ ArchiveInterface = new(ArchiveDao)
+
+// NewArchive new audit archive.
+func NewArchive(orm orm.Interface) ArchiveInterface {
+	return &ArchiveDao{Orm: orm}
+}
+
+// ArchiveDao audit archive dao.
+type ArchiveDao struct {
+	Orm orm.Interface
+}
+
+// ListExpiredOption 查询超过保留天数的审计记录的条件
+type ListExpiredOption struct {
+	// ResTypes 需要查询的资源类型，为空时不限制
+	ResTypes []enumor.AuditResourceType
+	// ExcludeResTypes 需要排除的资源类型
+	ExcludeResTypes []enumor.AuditResourceType
+	// RetentionDays 保留天数，创建时间早于该天数的记录为过期记录
+	RetentionDays uint
+	Limit         uint
+}
+
+// ListExpired 按id顺序查询超过保留天数的审计记录，时间由数据库计算避免时区问题
+func (d ArchiveDao) ListExpired(kt *kit.Kit, opt *ListExpiredOption) ([]audit.AuditTable, error) {
+	if opt == nil || opt.RetentionDays == 0 || opt.Limit == 0 {
+		return nil, fmt.Errorf("invalid list expired audit option")
+	}
+
+	conditions := []string{"created_at < DATE_SUB(now(), INTERVAL :days DAY)"}
+	arg := map[string]interface{}{"days": opt.RetentionDays}
+	if len(opt.ResTypes) != 0 {
+		conditions = append(conditions, "res_type IN (:res_types)")
+		arg["res_types"] = opt.ResTypes
+	}
+	if len(opt.ExcludeResTypes) != 0 {
+		conditions = append(conditions, "res_type NOT IN (:exclude_res_types)")
+		arg["exclude_res_types"] = opt.ExcludeResTypes
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY id LIMIT %d`, audit.AuditColumns.NamedExpr(),
+		table.AuditTable, strings.Join(conditions, " AND "), opt.Limit)
+
+	list := make([]audit.AuditTable, 0, opt.Limit)
+	if err := d.Orm.Do().Select(kt.Ctx, &list, sql, arg); err != nil {
+		logs.Errorf("list expired audit failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// CreateArchive 记录归档文件，并在同一事务中删除已归档的审计记录，需要在归档文件上传成功后调用
+func (d ArchiveDao) CreateArchive(kt *kit.Kit, archive *audit.AuditArchiveTable, ids []uint64) error {
+	if archive == nil || len(ids) == 0 {
+		return fmt.Errorf("archive or ids is empty")
+	}
+
+	_, err := d.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		sql := fmt.Sprintf(`INSERT INTO %s (%s) VALUES(%s)`, table.AuditArchiveTable,
+			audit.AuditArchiveColumns.ColumnExpr(), audit.AuditArchiveColumns.ColonNameExpr())
+		if err := d.Orm.Txn(txn).Insert(kt.Ctx, sql, archive); err != nil {
+			logs.Errorf("insert audit archive %s failed, err: %v, rid: %s", archive.Path, err, kt.Rid)
+			return nil, fmt.Errorf("insert audit archive failed, err: %v", err)
+		}
+
+		delSql := fmt.Sprintf(`DELETE FROM %s WHERE id IN (:ids)`, table.AuditTable)
+		deleted, err := d.Orm.Txn(txn).Delete(kt.Ctx, delSql, map[string]interface{}{"ids": ids})
+		if err != nil {
+			logs.Errorf("delete archived audit failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, fmt.Errorf("delete archived audit failed, err: %v", err)
+		}
+
+		if deleted != int64(len(ids)) {
+			return nil, fmt.Errorf("archived audit count %d does not match deleted count %d", len(ids), deleted)
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// ListArchiveOption 查询归档文件的条件，字段为空时不限制
+type ListArchiveOption struct {
+	ResTypes     []enumor.AuditResourceType
+	PartitionKey string
+	// Start、End 查询与该时间范围有交集的归档文件
+	Start *time.Time
+	End   *time.Time
+	// ID 查询包含该审计记录id的归档文件
+	ID uint64
+}
+
+// ListArchive 查询归档文件清单，按id顺序返回
+func (d ArchiveDao) ListArchive(kt *kit.Kit, opt *ListArchiveOption) ([]audit.AuditArchiveTable, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("list archive option is nil")
+	}
+
+	conditions := make([]string, 0)
+	arg := make(map[string]interface{})
+	if len(opt.ResTypes) != 0 {
+		conditions = append(conditions, "res_type IN (:res_types)")
+		arg["res_types"] = opt.ResTypes
+	}
+	if len(opt.PartitionKey) != 0 {
+		conditions = append(conditions, "partition_key = :partition_key")
+		arg["partition_key"] = opt.PartitionKey
+	}
+	if opt.Start != nil {
+		conditions = append(conditions, "end_at >= :start")
+		arg["start"] = *opt.Start
+	}
+	if opt.End != nil {
+		conditions = append(conditions, "start_at <= :end")
+		arg["end"] = *opt.End
+	}
+	if opt.ID != 0 {
+		conditions = append(conditions, "start_id <= :id AND end_id >= :id")
+		arg["id"] = opt.ID
+	}
+
+	whereExpr := ""
+	if len(conditions) != 0 {
+		whereExpr = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY id`, audit.AuditArchiveColumns.NamedExpr(),
+		table.AuditArchiveTable, whereExpr)
+	list := make([]audit.AuditArchiveTable, 0)
+	if err := d.Orm.Do().Select(kt.Ctx, &list, sql, arg); err != nil {
+		logs.Errorf("list audit archive failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
@@ -44,20 +45,28 @@ type Interface interface {
 	BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error
 	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListAuditDetails, error)
+	VerifyChain(kt *kit.Kit, chainKey string, loader ChainArchiveLoader) (*types.AuditChainVerifyResult, error)
+	ListChainKeys(kt *kit.Kit, day string) ([]string, error)
 }
 
 var _ Interface = new(Dao)
 
 // NewAudit new audit.
-func NewAudit(orm orm.Interface) Interface {
+func NewAudit(orm orm.Interface, chainOpt cc.AuditChain, outbox bool) Interface {
 	return &Dao{
-		Orm: orm,
+		Orm:      orm,
+		ChainOpt: chainOpt,
+		Outbox:   outbox,
 	}
 }
 
 // Dao audit dao.
 type Dao struct {
 	Orm orm.Interface
+	// ChainOpt 审计哈希链配置
+	ChainOpt cc.AuditChain
+	// Outbox 是否将审计记录同事务写入发件箱
+	Outbox bool
 }
 
 // Create audit.
@@ -67,31 +76,29 @@ func (d Dao) Create(kt *kit.Kit, one *audit.AuditTable) error {
 
 // BatchCreate batch create audit.
 func (d Dao) BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error {
-	for _, one := range audits {
-		if err := one.CreateValidate(); err != nil {
-			return err
-		}
-	}
-
-	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
-		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
-
-	if err := d.Orm.Do().BulkInsert(kt.Ctx, sql, audits); err != nil {
-		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditTable, err, kt.Rid)
-		return fmt.Errorf("insert %s failed, err: %v", table.AuditTable, err)
-	}
-
-	return nil
+	_, err := d.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, d.BatchCreateWithTx(kt, txn, audits)
+	})
+	return err
 }
 
-// BatchCreateWithTx batch create audit with tx.
+// BatchCreateWithTx batch create audit with tx, audits are appended to the hash chain of the day,
+// and written to the outbox if audit sinks are configured.
 func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	if len(audits) == 0 {
+		return nil
+	}
+
 	for _, one := range audits {
 		if err := one.CreateValidate(); err != nil {
 			return err
 		}
 	}
 
+	if err := d.appendToChain(kt, tx, audits); err != nil {
+		return err
+	}
+
 	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
 		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
 
@@ -100,6 +107,12 @@ func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTa
 		return fmt.Errorf("insert %s failed, err: %v", table.AuditTable, err)
 	}
 
+	if d.Outbox {
+		if err := d.enqueueOutbox(kt, tx, audits); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
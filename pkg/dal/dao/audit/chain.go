@@ -0,0 +1,482 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// chainVerifyPageSize 校验哈希链时每次查询的审计记录数
+const chainVerifyPageSize = 500
+
+// ChainArchiveLoader 加载哈希链上已归档的记录，按序号排序返回
+type ChainArchiveLoader func(kt *kit.Kit, chainKey string) ([]audit.ChainRecord, error)
+
+// chainHead 加锁查询到的链头
+type chainHead struct {
+	audit.AuditChainTable
+	// CheckpointElapsedMin 距离上次检查点的分钟数，由数据库计算避免时区问题
+	CheckpointElapsedMin int64 `db:"checkpoint_elapsed_min"`
+}
+
+// chainKeyOf 哈希链的标识，每天每个分片一条链，只有一个分片时直接使用日期
+func chainKeyOf(day string, shard, shards uint) string {
+	if shards <= 1 {
+		return day
+	}
+	return fmt.Sprintf("%s/%d", day, shard)
+}
+
+// chainShard 按请求ID选择哈希链分片，同一个请求的审计记录追加到同一条链上
+func (d Dao) chainShard(kt *kit.Kit) uint {
+	if d.ChainOpt.Shards <= 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(kt.Rid))
+	return uint(h.Sum32()) % d.ChainOpt.Shards
+}
+
+// appendToChain 将审计记录追加到当天所在分片的哈希链上，需要在事务中调用。
+// 链头加行锁保证同一条链上的记录串行追加，同一分片的审计写入会在链头行锁上排队直到事务提交，
+// 写入量大时通过 auditChain.shards 增加分片数提高并发。
+func (d Dao) appendToChain(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	shard := d.chainShard(kt)
+	chainKey := chainKeyOf(time.Now().Format(constant.DateLayout), shard, d.ChainOpt.Shards)
+
+	if err := d.initChainHead(kt, tx, chainKey, shard); err != nil {
+		return err
+	}
+
+	lockSql := fmt.Sprintf(`SELECT %s, TIMESTAMPDIFF(MINUTE, checkpoint_at, now()) AS checkpoint_elapsed_min
+		FROM %s WHERE chain_key = :chain_key FOR UPDATE`, audit.AuditChainColumns.NamedExpr(), table.AuditChainTable)
+	heads := make([]chainHead, 0, 1)
+	if err := d.Orm.Txn(tx).Select(kt.Ctx, &heads, lockSql, map[string]interface{}{"chain_key": chainKey}); err != nil {
+		logs.Errorf("lock audit chain %s failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return fmt.Errorf("lock audit chain %s failed, err: %v", chainKey, err)
+	}
+
+	if len(heads) != 1 {
+		return fmt.Errorf("audit chain %s head not found", chainKey)
+	}
+	head := heads[0]
+
+	seq, prevHash := head.LastSeq, head.LastHash
+	for _, one := range audits {
+		seq++
+		one.ChainKey, one.ChainSeq, one.PrevHash = chainKey, seq, prevHash
+
+		hash, err := one.ChainHash()
+		if err != nil {
+			logs.Errorf("calculate audit chain hash failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+		one.Hash, prevHash = hash, hash
+	}
+
+	setExpr := "last_seq = :last_seq, last_hash = :last_hash, signature = :signature"
+	if d.needCheckpoint(head, seq) {
+		checkpoint := &audit.AuditCheckpointTable{
+			ChainKey:  chainKey,
+			ChainSeq:  seq,
+			Hash:      prevHash,
+			Signature: signCheckpoint(d.ChainOpt.SignKey, chainKey, seq, prevHash, head.CheckpointSig),
+		}
+		cpSql := fmt.Sprintf(`INSERT INTO %s (%s) VALUES(%s)`, table.AuditCheckpointTable,
+			audit.AuditCheckpointColumns.ColumnExpr(), audit.AuditCheckpointColumns.ColonNameExpr())
+		if err := d.Orm.Txn(tx).Insert(kt.Ctx, cpSql, checkpoint); err != nil {
+			logs.Errorf("insert audit checkpoint failed, err: %v, chain: %s, rid: %s", err, chainKey, kt.Rid)
+			return fmt.Errorf("insert audit checkpoint failed, err: %v", err)
+		}
+		setExpr += ", checkpoint_seq = :last_seq, checkpoint_sig = :checkpoint_sig, checkpoint_at = now()"
+		head.CheckpointSeq, head.CheckpointSig = seq, checkpoint.Signature
+	}
+
+	head.LastSeq, head.LastHash = seq, prevHash
+	updateSql := fmt.Sprintf(`UPDATE %s SET %s WHERE chain_key = :chain_key`, table.AuditChainTable, setExpr)
+	toUpdate := map[string]interface{}{"chain_key": chainKey, "last_seq": seq, "last_hash": prevHash,
+		"checkpoint_sig": head.CheckpointSig, "signature": signHead(d.ChainOpt.SignKey, &head.AuditChainTable)}
+	if _, err := d.Orm.Txn(tx).Update(kt.Ctx, updateSql, toUpdate); err != nil {
+		logs.Errorf("update audit chain %s head failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return fmt.Errorf("update audit chain %s head failed, err: %v", chainKey, err)
+	}
+
+	return nil
+}
+
+// initChainHead 链头不存在时创建链头，链上第一条记录衔接同分片上一条链的最后一条记录，
+// 上一条链不存在时从空哈希开始。上一条链的链头不加锁，之后追加到上一条链的记录由其链头签名保护
+func (d Dao) initChainHead(kt *kit.Kit, tx *sqlx.Tx, chainKey string, shard uint) error {
+	arg := map[string]interface{}{"chain_key": chainKey, "shard": shard}
+	countSql := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE chain_key = :chain_key`, table.AuditChainTable)
+	count, err := d.Orm.Txn(tx).Count(kt.Ctx, countSql, arg)
+	if err != nil {
+		logs.Errorf("count audit chain %s failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return fmt.Errorf("count audit chain %s failed, err: %v", chainKey, err)
+	}
+
+	if count != 0 {
+		return nil
+	}
+
+	prevSql := fmt.Sprintf(`SELECT %s FROM %s WHERE shard = :shard AND chain_key < :chain_key
+		ORDER BY chain_key DESC LIMIT 1`, audit.AuditChainColumns.NamedExpr(), table.AuditChainTable)
+	prevs := make([]audit.AuditChainTable, 0, 1)
+	if err = d.Orm.Txn(tx).Select(kt.Ctx, &prevs, prevSql, arg); err != nil {
+		logs.Errorf("get previous chain of audit chain %s failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return fmt.Errorf("get previous chain of audit chain %s failed, err: %v", chainKey, err)
+	}
+
+	arg["prev_chain_key"], arg["prev_seq"], arg["prev_hash"] = "", uint64(0), ""
+	if len(prevs) != 0 {
+		arg["prev_chain_key"], arg["prev_seq"], arg["prev_hash"] = prevs[0].ChainKey, prevs[0].LastSeq,
+			prevs[0].LastHash
+	}
+
+	// 链上第一条记录的 prev_hash 取链头的 last_hash，因此初始化为上一条链的哈希值
+	initSql := fmt.Sprintf(`INSERT IGNORE INTO %s (chain_key, shard, prev_chain_key, prev_seq, prev_hash, last_seq,
+		last_hash, checkpoint_seq, checkpoint_at) VALUES (:chain_key, :shard, :prev_chain_key, :prev_seq, :prev_hash,
+		0, :prev_hash, 0, now())`, table.AuditChainTable)
+	if err = d.Orm.Txn(tx).Insert(kt.Ctx, initSql, arg); err != nil {
+		logs.Errorf("init audit chain %s failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return fmt.Errorf("init audit chain %s failed, err: %v", chainKey, err)
+	}
+
+	return nil
+}
+
+// needCheckpoint 配置了签名密钥，并且距离上次检查点的记录数或时间超过配置时生成检查点
+func (d Dao) needCheckpoint(head chainHead, seq uint64) bool {
+	if len(d.ChainOpt.SignKey) == 0 {
+		return false
+	}
+
+	if seq-head.CheckpointSeq >= uint64(d.ChainOpt.CheckpointInterval) {
+		return true
+	}
+
+	return head.CheckpointElapsedMin >= int64(d.ChainOpt.CheckpointIntervalMin)
+}
+
+// signCheckpoint 使用HMAC-SHA256对检查点签名，签名包含链上上一个检查点的签名，删除或替换中间的检查点会使后续签名失效
+func signCheckpoint(key, chainKey string, seq uint64, hash, prevSig string) string {
+	return hmacSign(key, fmt.Sprintf("%s/%d/%s/%s", chainKey, seq, hash, prevSig))
+}
+
+// signHead 使用HMAC-SHA256对链头签名，未配置签名密钥时不签名
+func signHead(key string, head *audit.AuditChainTable) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	return hmacSign(key, fmt.Sprintf("head/%s/%d/%s/%d/%s/%d/%s/%d/%s", head.ChainKey, head.Shard, head.PrevChainKey,
+		head.PrevSeq, head.PrevHash, head.LastSeq, head.LastHash, head.CheckpointSeq, head.CheckpointSig))
+}
+
+func hmacSign(key, content string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(content))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ListChainKeys 返回某天的所有哈希链，包含按配置分片数计算的链和链头表中存在的链
+func (d Dao) ListChainKeys(kt *kit.Kit, day string) ([]string, error) {
+	sql := fmt.Sprintf(`SELECT chain_key FROM %s WHERE chain_key = :day OR chain_key LIKE :prefix`,
+		table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0)
+	arg := map[string]interface{}{"day": day, "prefix": day + "/%"}
+	if err := d.Orm.Do().Select(kt.Ctx, &heads, sql, arg); err != nil {
+		logs.Errorf("list audit chains of %s failed, err: %v, rid: %s", day, err, kt.Rid)
+		return nil, err
+	}
+
+	keys := make([]string, 0, d.ChainOpt.Shards)
+	exists := make(map[string]struct{})
+	for shard := uint(0); shard < d.ChainOpt.Shards || shard == 0; shard++ {
+		key := chainKeyOf(day, shard, d.ChainOpt.Shards)
+		keys = append(keys, key)
+		exists[key] = struct{}{}
+	}
+
+	for _, head := range heads {
+		if _, ok := exists[head.ChainKey]; !ok {
+			keys = append(keys, head.ChainKey)
+			exists[head.ChainKey] = struct{}{}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// VerifyChain 校验哈希链的完整性：
+// 1. 重新计算每条记录的哈希值，与存储的哈希值不一致说明记录被篡改；
+// 2. 记录的 prev_hash 与上一条记录的哈希值不一致，说明记录被篡改或链被重排，第一条记录衔接同分片上一条链；
+// 3. 序号不连续或小于链头/检查点的序号，说明记录被删除，序号重复说明记录是伪造插入的；
+// 4. 检查点签名包含上一个检查点的签名，签名不正确说明检查点被伪造或中间的检查点被删除，
+// 检查点对应记录的哈希值不一致说明链被整体重新计算过；
+// 5. 链头签名不正确说明链头被回退或伪造，最后一个检查点与链头记录的不一致说明链尾的检查点被删除；
+// 6. 链头记录的上一条链中对应记录不存在或哈希值不一致，说明上一条链被删除或篡改。
+// loader 用于加载已归档的链上记录，和数据库中的记录合并后一起校验。
+func (d Dao) VerifyChain(kt *kit.Kit, chainKey string, loader ChainArchiveLoader) (
+	*types.AuditChainVerifyResult, error) {
+
+	result := &types.AuditChainVerifyResult{
+		ChainKey:          chainKey,
+		Altered:           make([]types.AuditAlteredRecord, 0),
+		Removed:           make([]types.AuditSeqRange, 0),
+		ForgedCheckpoints: make([]uint64, 0),
+	}
+
+	archived, err := loader(kt, chainKey)
+	if err != nil {
+		logs.Errorf("load audit chain %s archived records failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return nil, err
+	}
+
+	arg := map[string]interface{}{"chain_key": chainKey}
+	headSql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain_key = :chain_key`, audit.AuditChainColumns.NamedExpr(),
+		table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0, 1)
+	if err := d.Orm.Do().Select(kt.Ctx, &heads, headSql, arg); err != nil {
+		logs.Errorf("get audit chain %s head failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return nil, err
+	}
+
+	var head *audit.AuditChainTable
+	if len(heads) != 0 {
+		head = &heads[0]
+		result.LastSeq, result.PrevChainKey = head.LastSeq, head.PrevChainKey
+		if len(d.ChainOpt.SignKey) != 0 &&
+			!hmac.Equal([]byte(head.Signature), []byte(signHead(d.ChainOpt.SignKey, head))) {
+			result.ForgedHead = true
+		}
+	}
+
+	cpSql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain_key = :chain_key ORDER BY chain_seq, id`,
+		audit.AuditCheckpointColumns.NamedExpr(), table.AuditCheckpointTable)
+	checkpoints := make([]audit.AuditCheckpointTable, 0)
+	if err := d.Orm.Do().Select(kt.Ctx, &checkpoints, cpSql, arg); err != nil {
+		logs.Errorf("list audit chain %s checkpoints failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+		return nil, err
+	}
+	result.CheckpointCount = uint64(len(checkpoints))
+
+	// 有效检查点和链头的序号和哈希值，用于校验对应的记录
+	cpHashes := d.verifyCheckpoints(result, head, checkpoints)
+	maxSeq := result.LastSeq
+	for seq := range cpHashes {
+		if seq > maxSeq {
+			maxSeq = seq
+		}
+	}
+
+	expectSeq, prevHash := uint64(1), ""
+	if head != nil {
+		prevHash = head.PrevHash
+	}
+	verify := func(record *audit.ChainRecord) {
+		result.RecordCount++
+		// 序号重复，说明记录是伪造插入的
+		if record.ChainSeq < expectSeq {
+			result.Altered = append(result.Altered, types.AuditAlteredRecord{ID: record.ID,
+				ChainSeq: record.ChainSeq, Reason: "duplicated chain_seq, record is inserted"})
+			return
+		}
+
+		verifyChainRecord(result, record, expectSeq, prevHash, cpHashes)
+		expectSeq, prevHash = record.ChainSeq+1, record.Hash
+	}
+
+	archivedIdx := 0
+	listSql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain_key = :chain_key AND chain_seq >= :start_seq
+		ORDER BY chain_seq, id LIMIT %d`, strings.Join(audit.ChainRecordColumns, ","), table.AuditTable,
+		chainVerifyPageSize)
+	for {
+		records := make([]audit.ChainRecord, 0, chainVerifyPageSize)
+		err := d.Orm.Do().Select(kt.Ctx, &records, listSql, map[string]interface{}{"chain_key": chainKey,
+			"start_seq": expectSeq})
+		if err != nil {
+			logs.Errorf("list audit chain %s records failed, err: %v, rid: %s", chainKey, err, kt.Rid)
+			return nil, err
+		}
+
+		for i := range records {
+			// 按序号合并已归档的记录
+			for archivedIdx < len(archived) && archived[archivedIdx].ChainSeq <= records[i].ChainSeq {
+				result.ArchivedCount++
+				verify(&archived[archivedIdx])
+				archivedIdx++
+			}
+			verify(&records[i])
+		}
+
+		if len(records) < chainVerifyPageSize {
+			break
+		}
+	}
+
+	for ; archivedIdx < len(archived); archivedIdx++ {
+		result.ArchivedCount++
+		verify(&archived[archivedIdx])
+	}
+
+	// 链尾的记录被删除
+	if maxSeq >= expectSeq {
+		result.Removed = append(result.Removed, types.AuditSeqRange{StartSeq: expectSeq, EndSeq: maxSeq})
+	}
+
+	// 链上有记录或检查点但链头不存在，说明链头被删除
+	if head == nil && (result.RecordCount != 0 || result.CheckpointCount != 0) {
+		result.ForgedHead = true
+	}
+
+	if head != nil && len(head.PrevChainKey) != 0 {
+		if result.BrokenLink, err = d.isBrokenLink(kt, head, loader); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Intact = len(result.Altered) == 0 && len(result.Removed) == 0 && len(result.ForgedCheckpoints) == 0 &&
+		!result.ForgedHead && !result.CheckpointRemoved && !result.BrokenLink
+	return result, nil
+}
+
+// verifyCheckpoints 按序号校验检查点签名链，返回签名有效的检查点和链头的序号与哈希值
+func (d Dao) verifyCheckpoints(result *types.AuditChainVerifyResult, head *audit.AuditChainTable,
+	checkpoints []audit.AuditCheckpointTable) map[uint64]string {
+
+	cpHashes := make(map[uint64]string)
+	if len(d.ChainOpt.SignKey) == 0 {
+		for _, cp := range checkpoints {
+			cpHashes[cp.ChainSeq] = cp.Hash
+		}
+		return cpHashes
+	}
+
+	prevSig := ""
+	for _, cp := range checkpoints {
+		expect := signCheckpoint(d.ChainOpt.SignKey, cp.ChainKey, cp.ChainSeq, cp.Hash, prevSig)
+		prevSig = cp.Signature
+		if !hmac.Equal([]byte(cp.Signature), []byte(expect)) {
+			result.ForgedCheckpoints = append(result.ForgedCheckpoints, cp.ID)
+			continue
+		}
+		cpHashes[cp.ChainSeq] = cp.Hash
+	}
+
+	if head == nil || result.ForgedHead {
+		return cpHashes
+	}
+
+	// 链头记录了最后一个检查点的签名，不一致说明链尾的检查点被删除
+	if head.CheckpointSig != prevSig {
+		result.CheckpointRemoved = true
+	}
+
+	if head.LastSeq != 0 {
+		cpHashes[head.LastSeq] = head.LastHash
+	}
+
+	return cpHashes
+}
+
+// isBrokenLink 校验链头记录的上一条链衔接，上一条链中对应序号的记录不存在或哈希值不一致时衔接被破坏
+func (d Dao) isBrokenLink(kt *kit.Kit, head *audit.AuditChainTable, loader ChainArchiveLoader) (bool, error) {
+	if head.PrevSeq == 0 {
+		return len(head.PrevHash) != 0, nil
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain_key = :chain_key AND chain_seq = :chain_seq ORDER BY id LIMIT 1`,
+		strings.Join(audit.ChainRecordColumns, ","), table.AuditTable)
+	records := make([]audit.ChainRecord, 0, 1)
+	err := d.Orm.Do().Select(kt.Ctx, &records, sql, map[string]interface{}{"chain_key": head.PrevChainKey,
+		"chain_seq": head.PrevSeq})
+	if err != nil {
+		logs.Errorf("get audit chain %s record %d failed, err: %v, rid: %s", head.PrevChainKey, head.PrevSeq, err,
+			kt.Rid)
+		return false, err
+	}
+
+	if len(records) == 0 {
+		// 上一条链的记录可能已经归档
+		if records, err = loader(kt, head.PrevChainKey); err != nil {
+			logs.Errorf("load audit chain %s archived records failed, err: %v, rid: %s", head.PrevChainKey, err,
+				kt.Rid)
+			return false, err
+		}
+	}
+
+	for _, record := range records {
+		if record.ChainSeq == head.PrevSeq {
+			return record.Hash != head.PrevHash, nil
+		}
+	}
+
+	return true, nil
+}
+
+// verifyChainRecord 校验单条记录，将发现的篡改和删除记录到校验结果中
+func verifyChainRecord(result *types.AuditChainVerifyResult, record *audit.ChainRecord, expectSeq uint64,
+	prevHash string, cpHashes map[uint64]string) {
+
+	// 序号不连续，中间的记录被删除，此时无法校验 prev_hash
+	if record.ChainSeq > expectSeq {
+		result.Removed = append(result.Removed, types.AuditSeqRange{StartSeq: expectSeq, EndSeq: record.ChainSeq - 1})
+	} else if record.PrevHash != prevHash {
+		result.Altered = append(result.Altered, types.AuditAlteredRecord{ID: record.ID, ChainSeq: record.ChainSeq,
+			Reason: "prev_hash does not match the hash of previous record"})
+	}
+
+	hash, err := record.ChainHash()
+	if err != nil {
+		result.Altered = append(result.Altered, types.AuditAlteredRecord{ID: record.ID, ChainSeq: record.ChainSeq,
+			Reason: fmt.Sprintf("calculate hash failed, err: %v", err)})
+		return
+	}
+
+	if hash != record.Hash {
+		result.Altered = append(result.Altered, types.AuditAlteredRecord{ID: record.ID, ChainSeq: record.ChainSeq,
+			Reason: "content does not match the stored hash"})
+		return
+	}
+
+	if cpHash, exists := cpHashes[record.ChainSeq]; exists && cpHash != record.Hash {
+		result.Altered = append(result.Altered, types.AuditAlteredRecord{ID: record.ID, ChainSeq: record.ChainSeq,
+			Reason: "hash does not match the signed checkpoint or chain head, records have been rehashed"})
+	}
+}
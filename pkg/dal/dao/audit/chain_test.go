@@ -0,0 +1,85 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"testing"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table/audit"
+)
+
+func TestChainKeyOf(t *testing.T) {
+	if key := chainKeyOf("2024-12-18", 0, 1); key != "2024-12-18" {
+		t.Errorf("single shard chain key should be the day, got: %s", key)
+	}
+
+	if key := chainKeyOf("2024-12-18", 3, 4); key != "2024-12-18/3" {
+		t.Errorf("sharded chain key is invalid, got: %s", key)
+	}
+}
+
+func TestVerifyCheckpoints(t *testing.T) {
+	d := Dao{ChainOpt: cc.AuditChain{SignKey: "key"}}
+	chainKey := "2024-12-18"
+
+	checkpoints := make([]audit.AuditCheckpointTable, 0)
+	prevSig := ""
+	for i, hash := range []string{"h1", "h2", "h3"} {
+		seq := uint64(i+1) * 10
+		sig := signCheckpoint(d.ChainOpt.SignKey, chainKey, seq, hash, prevSig)
+		checkpoints = append(checkpoints, audit.AuditCheckpointTable{ID: uint64(i + 1), ChainKey: chainKey,
+			ChainSeq: seq, Hash: hash, Signature: sig})
+		prevSig = sig
+	}
+	head := &audit.AuditChainTable{ChainKey: chainKey, LastSeq: 35, LastHash: "h35", CheckpointSeq: 30,
+		CheckpointSig: prevSig}
+	head.Signature = signHead(d.ChainOpt.SignKey, head)
+
+	result := new(types.AuditChainVerifyResult)
+	cpHashes := d.verifyCheckpoints(result, head, checkpoints)
+	if len(result.ForgedCheckpoints) != 0 || result.CheckpointRemoved {
+		t.Fatalf("intact checkpoints are reported as forged: %+v", result)
+	}
+	if len(cpHashes) != 4 || cpHashes[35] != "h35" {
+		t.Errorf("signed head should be verified as a checkpoint, got: %v", cpHashes)
+	}
+
+	// 删除中间的检查点，后一个检查点的签名失效
+	result = new(types.AuditChainVerifyResult)
+	d.verifyCheckpoints(result, head, []audit.AuditCheckpointTable{checkpoints[0], checkpoints[2]})
+	if len(result.ForgedCheckpoints) != 1 || result.ForgedCheckpoints[0] != 3 {
+		t.Errorf("checkpoint after the removed one should be forged, got: %v", result.ForgedCheckpoints)
+	}
+
+	// 删除链尾的检查点，与链头记录的检查点签名不一致
+	result = new(types.AuditChainVerifyResult)
+	d.verifyCheckpoints(result, head, checkpoints[:2])
+	if !result.CheckpointRemoved {
+		t.Errorf("removed tail checkpoint should be reported")
+	}
+
+	// 链头被回退后签名失效
+	head.LastSeq, head.LastHash = 30, "h3"
+	if signHead(d.ChainOpt.SignKey, head) == head.Signature {
+		t.Errorf("rolled back head should not match the signature")
+	}
+}
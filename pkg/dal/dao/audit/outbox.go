@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxSinkErrorLength 投递失败原因的最大长度，和表字段长度保持一致
+const maxSinkErrorLength = 1024
+
+// OutboxInterface define audit outbox interface.
+type OutboxInterface interface {
+	ListOutbox(kt *kit.Kit, afterID uint64, limit uint) ([]audit.AuditOutboxTable, error)
+	GetSinkCursor(kt *kit.Kit, sink string) (*audit.AuditSinkCursorTable, error)
+	AdvanceSinkCursor(kt *kit.Kit, sink string, lastID uint64) error
+	FailSinkCursor(kt *kit.Kit, sink string, attempts uint, reason string) error
+	CleanOutbox(kt *kit.Kit, maxID uint64, limit uint) (int64, error)
+}
+
+var _ OutboxInterface = new(OutboxDao)
+
+// NewOutbox new audit outbox.
+func NewOutbox(orm orm.Interface) OutboxInterface {
+	return &OutboxDao{Orm: orm}
+}
+
+// OutboxDao audit outbox dao.
+type OutboxDao struct {
+	Orm orm.Interface
+}
+
+// enqueueOutbox 审计记录写入发件箱，需要和审计记录在同一个事务中调用，链头的行锁保证发件箱记录的id顺序和提交顺序一致
+func (d Dao) enqueueOutbox(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	outbox := make([]audit.AuditOutboxTable, 0, len(audits))
+	for _, one := range audits {
+		payload, err := json.Marshal(one)
+		if err != nil {
+			return fmt.Errorf("marshal audit outbox payload failed, err: %v", err)
+		}
+
+		outbox = append(outbox, audit.AuditOutboxTable{
+			ResType: one.ResType,
+			Action:  one.Action,
+			Payload: types.JsonField(payload),
+		})
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s) VALUES(%s)`, table.AuditOutboxTable,
+		audit.AuditOutboxColumns.ColumnExpr(), audit.AuditOutboxColumns.ColonNameExpr())
+	if err := d.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, outbox); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditOutboxTable, err, kt.Rid)
+		return fmt.Errorf("insert %s failed, err: %v", table.AuditOutboxTable, err)
+	}
+
+	return nil
+}
+
+// ListOutbox 按id顺序查询大于指定id的发件箱记录
+func (d OutboxDao) ListOutbox(kt *kit.Kit, afterID uint64, limit uint) ([]audit.AuditOutboxTable, error) {
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE id > :after_id ORDER BY id LIMIT %d`,
+		audit.AuditOutboxColumns.NamedExpr(), table.AuditOutboxTable, limit)
+
+	list := make([]audit.AuditOutboxTable, 0, limit)
+	if err := d.Orm.Do().Select(kt.Ctx, &list, sql, map[string]interface{}{"after_id": afterID}); err != nil {
+		logs.Errorf("list audit outbox failed, err: %v, after id: %d, rid: %s", err, afterID, kt.Rid)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetSinkCursor 查询投递目标的投递进度，不存在时初始化
+func (d OutboxDao) GetSinkCursor(kt *kit.Kit, sink string) (*audit.AuditSinkCursorTable, error) {
+	initSql := fmt.Sprintf(`INSERT IGNORE INTO %s (sink, last_id, attempts, last_error) VALUES (:sink, 0, 0, '')`,
+		table.AuditSinkCursorTable)
+	if err := d.Orm.Do().Insert(kt.Ctx, initSql, map[string]interface{}{"sink": sink}); err != nil {
+		logs.Errorf("init audit sink %s cursor failed, err: %v, rid: %s", sink, err, kt.Rid)
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE sink = :sink`, audit.AuditSinkCursorColumns.NamedExpr(),
+		table.AuditSinkCursorTable)
+	list := make([]audit.AuditSinkCursorTable, 0, 1)
+	if err := d.Orm.Do().Select(kt.Ctx, &list, sql, map[string]interface{}{"sink": sink}); err != nil {
+		logs.Errorf("get audit sink %s cursor failed, err: %v, rid: %s", sink, err, kt.Rid)
+		return nil, err
+	}
+
+	if len(list) != 1 {
+		return nil, fmt.Errorf("audit sink %s cursor not found", sink)
+	}
+
+	return &list[0], nil
+}
+
+// AdvanceSinkCursor 投递成功后更新投递进度，并清空失败状态
+func (d OutboxDao) AdvanceSinkCursor(kt *kit.Kit, sink string, lastID uint64) error {
+	sql := fmt.Sprintf(`UPDATE %s SET last_id = :last_id, attempts = 0, last_error = '' WHERE sink = :sink`,
+		table.AuditSinkCursorTable)
+	if _, err := d.Orm.Do().Update(kt.Ctx, sql, map[string]interface{}{"sink": sink, "last_id": lastID}); err != nil {
+		logs.Errorf("advance audit sink %s cursor to %d failed, err: %v, rid: %s", sink, lastID, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// FailSinkCursor 记录投递失败次数和原因，投递进度不变
+func (d OutboxDao) FailSinkCursor(kt *kit.Kit, sink string, attempts uint, reason string) error {
+	if len(reason) > maxSinkErrorLength {
+		reason = reason[:maxSinkErrorLength]
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s SET attempts = :attempts, last_error = :last_error WHERE sink = :sink`,
+		table.AuditSinkCursorTable)
+	toUpdate := map[string]interface{}{"sink": sink, "attempts": attempts, "last_error": reason}
+	if _, err := d.Orm.Do().Update(kt.Ctx, sql, toUpdate); err != nil {
+		logs.Errorf("update audit sink %s failure failed, err: %v, rid: %s", sink, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// CleanOutbox 删除所有投递目标都已投递的发件箱记录，单次最多删除 limit 条
+func (d OutboxDao) CleanOutbox(kt *kit.Kit, maxID uint64, limit uint) (int64, error) {
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE id <= :max_id ORDER BY id LIMIT %d`, table.AuditOutboxTable, limit)
+	deleted, err := d.Orm.Do().Delete(kt.Ctx, sql, map[string]interface{}{"max_id": maxID})
+	if err != nil {
+		logs.Errorf("clean audit outbox failed, err: %v, max id: %d, rid: %s", err, maxID, kt.Rid)
+		return 0, err
+	}
+
+	return deleted, nil
+}
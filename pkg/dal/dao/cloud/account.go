@@ -46,6 +46,7 @@ import (
 type Account interface {
 	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, account *cloud.AccountTable) (string, error)
 	Update(kt *kit.Kit, expr *filter.Expression, model *cloud.AccountTable) error
+	UpdateExtensionField(kt *kit.Kit, expr *filter.Expression, path []string, oldValue string, value string) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListAccountDetails, error)
 	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
 	DeleteValidate(kt *kit.Kit, accountID string) (map[string]uint64, error)
@@ -211,6 +212,53 @@ func (a AccountDao) Update(kt *kit.Kit, filterExpr *filter.Expression, model *cl
 	return nil
 }
 
+// UpdateExtensionField 仅当账号扩展字段中的一个字段等于原值时更新该字段，不覆盖扩展字段中的其他字段，
+// 字段不等于原值或记录不存在时返回 RecordNotFound 错误
+func (a AccountDao) UpdateExtensionField(kt *kit.Kit, filterExpr *filter.Expression, path []string, oldValue string,
+	value string) error {
+
+	if filterExpr == nil || len(filterExpr.Rules) == 0 {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	setExpr, cmpExpr, err := tools.JSONFieldCASExpr("extension", path)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s SET %s, reviser = :reviser %s AND %s`, table.AccountTable, setExpr, whereExpr,
+		cmpExpr)
+	toUpdate := map[string]interface{}{tools.JSONFieldValuePlaceholder: value,
+		tools.JSONFieldOldValuePlaceholder: oldValue, "reviser": kt.User}
+
+	_, err = a.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		effected, err := a.Orm.Txn(txn).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+		if err != nil {
+			logs.ErrorJson("update account extension field failed, err: %v, filter: %s, path: %v, rid: %v", err,
+				filterExpr, path, kt.Rid)
+			return nil, err
+		}
+
+		if effected == 0 {
+			logs.ErrorJson("update account extension field, but record not found, filter: %v, path: %v, rid: %v",
+				filterExpr, path, kt.Rid)
+			return nil, errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // List accounts.
 func (a AccountDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListAccountDetails, error) {
 	if opt == nil {
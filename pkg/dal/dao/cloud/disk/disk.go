@@ -187,12 +187,14 @@ func (diskDao DiskDao) List(kt *kit.Kit, opt *types.ListOption) (*cloud.DiskList
 	columnTypes["extension.resource_group_name"] = enumor.String
 	columnTypes["extension.self_link"] = enumor.String
 	columnTypes["extension.zones"] = enumor.Json
+	tools.AddResTagRuleField(columnTypes)
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereOpt := tools.DefaultSqlWhereOption
+	whereOpt := tools.ResTagSqlWhereOption(tools.DefaultSqlWhereOption, enumor.DiskCloudResType,
+		string(table.DiskTable))
 	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,194 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/cloud"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DiskSnapshotPolicy defines disk snapshot policy dao operations.
+type DiskSnapshotPolicy interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []cloud.DiskSnapshotPolicyTable) ([]string, error)
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *cloud.DiskSnapshotPolicyTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListDiskSnapshotPolicyDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ DiskSnapshotPolicy = new(diskSnapshotPolicyDao)
+
+// diskSnapshotPolicyDao disk snapshot policy dao.
+type diskSnapshotPolicyDao struct {
+	orm   orm.Interface
+	idGen idgenerator.IDGenInterface
+}
+
+// NewDiskSnapshotPolicyDao create a disk snapshot policy dao.
+func NewDiskSnapshotPolicyDao(orm orm.Interface, idGen idgenerator.IDGenInterface) DiskSnapshotPolicy {
+	return &diskSnapshotPolicyDao{
+		orm:   orm,
+		idGen: idGen,
+	}
+}
+
+// BatchCreateWithTx create disk snapshot policy with transaction.
+func (d *diskSnapshotPolicyDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	models []cloud.DiskSnapshotPolicyTable) ([]string, error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := d.idGen.Batch(kt, table.DiskSnapshotPolicyTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range models {
+		models[idx].ID = ids[idx]
+
+		if err = models[idx].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.DiskSnapshotPolicyTable,
+		cloud.DiskSnapshotPolicyColumns.ColumnExpr(), cloud.DiskSnapshotPolicyColumns.ColonNameExpr())
+
+	if err = d.orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.DiskSnapshotPolicyTable, err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.DiskSnapshotPolicyTable, err)
+	}
+
+	return ids, nil
+}
+
+// UpdateWithTx update disk snapshot policy with transaction.
+func (d *diskSnapshotPolicyDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *cloud.DiskSnapshotPolicyTable) error {
+
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddBlankedFields("memo").AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, table.DiskSnapshotPolicyTable, setExpr, whereExpr)
+	if _, err = d.orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue)); err != nil {
+		logs.ErrorJson("update disk snapshot policy failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// List disk snapshot policies.
+func (d *diskSnapshotPolicyDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListDiskSnapshotPolicyDetails,
+	error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list disk snapshot policy options is nil")
+	}
+
+	columnTypes := cloud.DiskSnapshotPolicyColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is a count request, do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.DiskSnapshotPolicyTable, whereExpr)
+
+		count, err := d.orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count disk snapshot policies failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListDiskSnapshotPolicyDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, cloud.DiskSnapshotPolicyColumns.FieldsNamedExpr(opt.Fields),
+		table.DiskSnapshotPolicyTable, whereExpr, pageExpr)
+
+	details := make([]cloud.DiskSnapshotPolicyTable, 0)
+	if err = d.orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+
+	return &types.ListDiskSnapshotPolicyDetails{Details: details}, nil
+}
+
+// DeleteWithTx delete disk snapshot policy with transaction.
+func (d *diskSnapshotPolicyDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.DiskSnapshotPolicyTable, whereExpr)
+	if _, err = d.orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete disk snapshot policy failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -195,14 +195,17 @@ func (dao LoadBalancerDao) List(kt *kit.Kit, opt *types.ListOption) (*typeslb.Li
 	}
 
 	columnTypes := tablelb.LoadBalancerColumns.ColumnTypes()
-	columnTypes["tags.*"] = enumor.String
+	// tags.* 既可以使用 json 操作符查询 tags 字段，也可以使用标签操作符查询 resource_tag 表
+	tools.AddResTagRuleField(columnTypes)
 
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	whereOpt := tools.ResTagSqlWhereOption(tools.DefaultSqlWhereOption, enumor.LoadBalancerCloudResType,
+		string(table.LoadBalancerTable))
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,255 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/cloud"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ResourceTag defines resource tag dao operations.
+type ResourceTag interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []cloud.ResourceTagTable) ([]string, error)
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *cloud.ResourceTagTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListResourceTagDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+	DeleteByResWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, resIDs []string) error
+	ListResIDByCloudID(kt *kit.Kit, resType enumor.CloudResourceType, accountID string, cloudIDs []string) (
+		map[string]string, error)
+}
+
+var _ ResourceTag = new(resourceTagDao)
+
+// resourceTagDao resource tag dao.
+type resourceTagDao struct {
+	orm   orm.Interface
+	idGen idgenerator.IDGenInterface
+}
+
+// NewResourceTagDao create a resource tag dao.
+func NewResourceTagDao(orm orm.Interface, idGen idgenerator.IDGenInterface) ResourceTag {
+	return &resourceTagDao{
+		orm:   orm,
+		idGen: idGen,
+	}
+}
+
+// BatchCreateWithTx create resource tag with transaction.
+func (r *resourceTagDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []cloud.ResourceTagTable) ([]string,
+	error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := r.idGen.Batch(kt, table.ResourceTagTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range models {
+		models[idx].ID = ids[idx]
+
+		if err = models[idx].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.ResourceTagTable,
+		cloud.ResourceTagColumns.ColumnExpr(), cloud.ResourceTagColumns.ColonNameExpr())
+
+	if err = r.orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.ResourceTagTable, err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.ResourceTagTable, err)
+	}
+
+	return ids, nil
+}
+
+// UpdateWithTx update resource tag with transaction.
+func (r *resourceTagDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *cloud.ResourceTagTable) error {
+
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	// 标签值允许为空
+	opts := utils.NewFieldOptions().AddBlankedFields("value").AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, table.ResourceTagTable, setExpr, whereExpr)
+	if _, err = r.orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue)); err != nil {
+		logs.ErrorJson("update resource tag failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// List resource tags.
+func (r *resourceTagDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListResourceTagDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list resource tag options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(cloud.ResourceTagColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is a count request, do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ResourceTagTable, whereExpr)
+
+		count, err := r.orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count resource tags failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListResourceTagDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, cloud.ResourceTagColumns.FieldsNamedExpr(opt.Fields),
+		table.ResourceTagTable, whereExpr, pageExpr)
+
+	details := make([]cloud.ResourceTagTable, 0)
+	if err = r.orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+
+	return &types.ListResourceTagDetails{Details: details}, nil
+}
+
+// DeleteWithTx delete resource tag with transaction.
+func (r *resourceTagDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.ResourceTagTable, whereExpr)
+	if _, err = r.orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete resource tag failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByResWithTx 删除资源的所有标签，用于资源删除时清理标签
+func (r *resourceTagDao) DeleteByResWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	resIDs []string) error {
+
+	if len(resIDs) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE res_type = :res_type AND res_id IN (:res_ids)`, table.ResourceTagTable)
+	args := map[string]interface{}{
+		"res_type": resType,
+		"res_ids":  resIDs,
+	}
+	if _, err := r.orm.Txn(tx).Delete(kt.Ctx, sql, args); err != nil {
+		logs.Errorf("delete %s resource tag failed, err: %v, ids: %v, rid: %s", resType, err, resIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListResIDByCloudID 根据云上ID查询资源在hcm中的ID，返回 cloud_id -> id 的映射
+func (r *resourceTagDao) ListResIDByCloudID(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string) (map[string]string, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(cloudIDs) == 0 {
+		return make(map[string]string), nil
+	}
+
+	sql := fmt.Sprintf(`SELECT id, cloud_id FROM %s WHERE account_id = :account_id AND cloud_id IN (:cloud_ids)`,
+		tableName)
+	args := map[string]interface{}{
+		"account_id": accountID,
+		"cloud_ids":  cloudIDs,
+	}
+
+	list := make([]struct {
+		ID      string `db:"id"`
+		CloudID string `db:"cloud_id"`
+	}, 0, len(cloudIDs))
+	if err = r.orm.Do().Select(kt.Ctx, &list, sql, args); err != nil {
+		logs.Errorf("list %s id by cloud id failed, err: %v, account: %s, rid: %s", resType, err, accountID, kt.Rid)
+		return nil, err
+	}
+
+	cloudIDMap := make(map[string]string, len(list))
+	for _, one := range list {
+		cloudIDMap[one.CloudID] = one.ID
+	}
+
+	return cloudIDMap, nil
+}
@@ -193,12 +193,15 @@ func (s SecurityGroupDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListS
 	columnTypes := cloud.SecurityGroupColumns.ColumnTypes()
 	columnTypes["extension.resource_group_name"] = enumor.String
 	columnTypes["extension.vpc_id"] = enumor.String
+	tools.AddResTagRuleField(columnTypes)
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	whereOpt := tools.ResTagSqlWhereOption(tools.DefaultSqlWhereOption, enumor.SecurityGroupCloudResType,
+		string(table.SecurityGroupTable))
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,167 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daosync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typessync "hcm/pkg/dal/dao/types/sync"
+	"hcm/pkg/dal/table"
+	tablessync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SyncCursor only used sync cursor.
+type SyncCursor interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tablessync.SyncCursorTable) ([]string, error)
+	// UpdateWithTx 更新匹配的游标，返回更新的行数
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *tablessync.SyncCursorTable) (int64,
+		error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListSyncCursorDetails, error)
+}
+
+var _ SyncCursor = new(SyncCursorDao)
+
+// SyncCursorDao sync cursor dao.
+type SyncCursorDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// BatchCreateWithTx sync cursor with tx.
+func (dao *SyncCursorDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tablessync.SyncCursorTable) (
+	[]string, error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := dao.IDGen.Batch(kt, table.SyncCursorTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.SyncCursorTable,
+		tablessync.SyncCursorColumns.ColumnExpr(), tablessync.SyncCursorColumns.ColonNameExpr())
+
+	if err = dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.SyncCursorTable, err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.SyncCursorTable, err)
+	}
+
+	return ids, nil
+}
+
+// UpdateWithTx sync cursor with tx.
+func (dao *SyncCursorDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *tablessync.SyncCursorTable) (int64, error) {
+
+	if expr == nil {
+		return 0, errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return 0, err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return 0, fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, table.SyncCursorTable, setExpr, whereExpr)
+	effected, err := dao.Orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+	if err != nil {
+		logs.ErrorJson("update sync cursor failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return 0, err
+	}
+
+	return effected, nil
+}
+
+// List sync cursor.
+func (dao *SyncCursorDao) List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListSyncCursorDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list sync cursor options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(tablessync.SyncCursorColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.SyncCursorTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count sync cursor failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typessync.ListSyncCursorDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tablessync.SyncCursorColumns.FieldsNamedExpr(opt.Fields),
+		table.SyncCursorTable, whereExpr, pageExpr)
+
+	details := make([]tablessync.SyncCursorTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select sync cursor failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typessync.ListSyncCursorDetails{Details: details}, nil
+}
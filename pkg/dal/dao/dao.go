@@ -71,6 +71,8 @@ import (
 // Set defines all the DAO to be operated.
 type Set interface {
 	Audit() audit.Interface
+	AuditOutbox() audit.OutboxInterface
+	AuditArchive() audit.ArchiveInterface
 	Auth() auth.Auth
 	Account() cloud.Account
 	SubAccount() daosubaccount.SubAccount
@@ -85,11 +87,16 @@ type Set interface {
 	AccountBizRel() cloud.AccountBizRel
 	Vpc() cloud.Vpc
 	Subnet() cloud.Subnet
+	ResourceTag() cloud.ResourceTag
+	ResAssignRule() cloud.ResAssignRule
+	DiskSnapshot() cloud.DiskSnapshot
+	DiskSnapshotPolicy() cloud.DiskSnapshotPolicy
 	HuaWeiRegion() region.HuaWeiRegion
 	AzureRG() resourcegroup.AzureRG
 	AzureRegion() region.AzureRegion
 	Zone() zone.Zone
 	AccountSyncDetail() daosync.AccountSyncDetail
+	SyncCursor() daosync.SyncCursor
 	TCloudRegion() region.TCloudRegion
 	AwsRegion() region.AwsRegion
 	GcpRegion() region.GcpRegion
@@ -98,6 +105,8 @@ type Set interface {
 	Route() routetable.Route
 	Application() application.Application
 	ApprovalProcess() application.ApprovalProcess
+	ApprovalChain() application.ApprovalChain
+	ApprovalTicket() application.ApprovalTicket
 	NetworkInterface() networkinterface.NetworkInterface
 	RecycleRecord() recyclerecord.RecycleRecord
 	Eip() eip.Eip
@@ -118,8 +127,12 @@ type Set interface {
 	RootAccountBillConfig() bill.RootAccountBillConfig
 	AccountBillExchangeRate() bill.AccountBillExchangeRate
 	AccountBillSyncRecord() bill.AccountBillSyncRecord
+	AccountBillBudget() bill.AccountBillBudget
+	AccountBillBudgetRecord() bill.AccountBillBudgetRecord
+	AccountBillCostAnomaly() bill.AccountBillCostAnomaly
 	AsyncFlow() daoasync.AsyncFlow
 	AsyncFlowTask() daoasync.AsyncFlowTask
+	AsyncScheduledFlow() daoasync.AsyncScheduledFlow
 	UserCollection() daouser.Interface
 	CloudSelectionScheme() daoselection.SchemeInterface
 	CloudSelectionBizType() daoselection.BizTypeInterface
@@ -158,7 +171,7 @@ func NewDaoSet(opt cc.DataBase) (Set, error) {
 		idGen: idGen,
 		orm:   ormInst,
 		db:    db,
-		audit: audit.NewAudit(ormInst),
+		audit: audit.NewAudit(ormInst, opt.AuditChain, opt.AuditOutbox),
 	}
 
 	return s, nil
@@ -270,6 +283,14 @@ func (s *set) AccountSyncDetail() daosync.AccountSyncDetail {
 	}
 }
 
+// SyncCursor return SyncCursor dao.
+func (s *set) SyncCursor() daosync.SyncCursor {
+	return &daosync.SyncCursorDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // AzureRegion return AzureRegion dao.
 func (s *set) AzureRegion() region.AzureRegion {
 	return &region.AzureRegionDao{
@@ -329,6 +350,26 @@ func (s *set) Subnet() cloud.Subnet {
 	return cloud.NewSubnetDao(s.orm, s.idGen, s.audit)
 }
 
+// ResourceTag returns resource tag dao.
+func (s *set) ResourceTag() cloud.ResourceTag {
+	return cloud.NewResourceTagDao(s.orm, s.idGen)
+}
+
+// ResAssignRule returns res assign rule dao.
+func (s *set) ResAssignRule() cloud.ResAssignRule {
+	return cloud.NewResAssignRuleDao(s.orm, s.idGen)
+}
+
+// DiskSnapshot returns disk snapshot dao.
+func (s *set) DiskSnapshot() cloud.DiskSnapshot {
+	return cloud.NewDiskSnapshotDao(s.orm, s.idGen, s.audit)
+}
+
+// DiskSnapshotPolicy returns disk snapshot policy dao.
+func (s *set) DiskSnapshotPolicy() cloud.DiskSnapshotPolicy {
+	return cloud.NewDiskSnapshotPolicyDao(s.orm, s.idGen)
+}
+
 // Auth return auth dao.
 func (s *set) Auth() auth.Auth {
 	return &auth.AuthDao{
@@ -443,6 +484,16 @@ func (s *set) Audit() audit.Interface {
 	return s.audit
 }
 
+// AuditOutbox return audit outbox dao.
+func (s *set) AuditOutbox() audit.OutboxInterface {
+	return audit.NewOutbox(s.orm)
+}
+
+// AuditArchive return audit archive dao.
+func (s *set) AuditArchive() audit.ArchiveInterface {
+	return audit.NewArchive(s.orm)
+}
+
 // Application return application dao.
 func (s *set) Application() application.Application {
 	return &application.ApplicationDao{
@@ -459,6 +510,16 @@ func (s *set) ApprovalProcess() application.ApprovalProcess {
 	}
 }
 
+// ApprovalChain returns approval chain dao.
+func (s *set) ApprovalChain() application.ApprovalChain {
+	return application.NewApprovalChainDao(s.orm, s.idGen)
+}
+
+// ApprovalTicket returns approval ticket dao.
+func (s *set) ApprovalTicket() application.ApprovalTicket {
+	return application.NewApprovalTicketDao(s.orm, s.idGen)
+}
+
 // NetworkInterface return network interface dao.
 func (s *set) NetworkInterface() networkinterface.NetworkInterface {
 	return &networkinterface.NetworkInterfaceDao{
@@ -588,6 +649,30 @@ func (s *set) AccountBillSyncRecord() bill.AccountBillSyncRecord {
 	}
 }
 
+// AccountBillBudget return bill.AccountBillBudget dao
+func (s *set) AccountBillBudget() bill.AccountBillBudget {
+	return &bill.AccountBillBudgetDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillBudgetRecord return bill.AccountBillBudgetRecord dao
+func (s *set) AccountBillBudgetRecord() bill.AccountBillBudgetRecord {
+	return &bill.AccountBillBudgetRecordDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillCostAnomaly return bill.AccountBillCostAnomaly dao
+func (s *set) AccountBillCostAnomaly() bill.AccountBillCostAnomaly {
+	return &bill.AccountBillCostAnomalyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // UserCollection returns user collection dao.
 func (s *set) UserCollection() daouser.Interface {
 	return &daouser.Dao{
@@ -612,6 +697,14 @@ func (s *set) AsyncFlowTask() daoasync.AsyncFlowTask {
 	}
 }
 
+// AsyncScheduledFlow return AsyncScheduledFlow dao.
+func (s *set) AsyncScheduledFlow() daoasync.AsyncScheduledFlow {
+	return &daoasync.AsyncScheduledFlowDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // CloudSelectionScheme returns cloud selection scheme dao.
 func (s *set) CloudSelectionScheme() daoselection.SchemeInterface {
 	return &daoselection.SchemeDao{
@@ -142,17 +142,21 @@ func (o *runtimeOrm) tryAccept() error {
 
 // Do create a new orm do instance.
 func (o *runtimeOrm) Do() DoOrm {
-	return &do{
-		db: o.db,
-		ro: o,
+	return &tracingDo{
+		do: &do{
+			db: o.db,
+			ro: o,
+		},
 	}
 }
 
 // Txn create a new transaction orm instance.
 func (o *runtimeOrm) Txn(tx *sqlx.Tx) DoOrmWithTransaction {
-	return &doTxn{
-		tx: tx,
-		ro: o,
+	return &tracingDoTxn{
+		doTxn: &doTxn{
+			tx: tx,
+			ro: o,
+		},
 	}
 }
 
@@ -255,7 +259,7 @@ type tableShardingOrm struct {
 }
 
 type tableShardingDo struct {
-	do                *do
+	do                DoOrm
 	tableShardingOpts []TableShardingOpt
 }
 
@@ -357,10 +361,7 @@ func (ds *tableShardingDo) Select(ctx context.Context, dest interface{}, expr st
 func (t tableShardingOrm) Do() DoOrm {
 
 	return &tableShardingDo{
-		do: &do{
-			db: t.orm.db,
-			ro: t.orm,
-		},
+		do:                t.orm.Do(),
 		tableShardingOpts: t.tableShardingOpts,
 	}
 }
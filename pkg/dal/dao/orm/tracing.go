@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package orm
+
+import (
+	"context"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan 为orm命令创建子span，记录sql模板，不记录参数值
+func startSpan(ctx context.Context, cmd string, expr string) (context.Context, trace.Span) {
+	rid, _ := ctx.Value(constant.RidKey).(string)
+	return tracing.Start(ctx, "orm "+cmd, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		tracing.RidKey.String(rid),
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.operation", cmd),
+		attribute.String("db.statement", expr),
+	))
+}
+
+var _ DoOrm = new(tracingDo)
+
+// tracingDo 为每个orm命令创建子span
+type tracingDo struct {
+	do DoOrm
+}
+
+// Select ...
+func (t *tracingDo) Select(ctx context.Context, dest interface{}, expr string, arg map[string]interface{}) error {
+	ctx, span := startSpan(ctx, "select", expr)
+	err := t.do.Select(ctx, dest, expr, arg)
+	tracing.End(span, err)
+	return err
+}
+
+// Count ...
+func (t *tracingDo) Count(ctx context.Context, expr string, arg map[string]interface{}) (uint64, error) {
+	ctx, span := startSpan(ctx, "count", expr)
+	count, err := t.do.Count(ctx, expr, arg)
+	tracing.End(span, err)
+	return count, err
+}
+
+// Delete ...
+func (t *tracingDo) Delete(ctx context.Context, expr string, arg map[string]interface{}) (int64, error) {
+	ctx, span := startSpan(ctx, "delete", expr)
+	affected, err := t.do.Delete(ctx, expr, arg)
+	tracing.End(span, err)
+	return affected, err
+}
+
+// Update ...
+func (t *tracingDo) Update(ctx context.Context, expr string, arg map[string]interface{}) (int64, error) {
+	ctx, span := startSpan(ctx, "update", expr)
+	affected, err := t.do.Update(ctx, expr, arg)
+	tracing.End(span, err)
+	return affected, err
+}
+
+// Exec ...
+func (t *tracingDo) Exec(ctx context.Context, expr string) (int64, error) {
+	ctx, span := startSpan(ctx, "exec", expr)
+	affected, err := t.do.Exec(ctx, expr)
+	tracing.End(span, err)
+	return affected, err
+}
+
+// Insert ...
+func (t *tracingDo) Insert(ctx context.Context, expr string, data interface{}) error {
+	ctx, span := startSpan(ctx, "insert", expr)
+	err := t.do.Insert(ctx, expr, data)
+	tracing.End(span, err)
+	return err
+}
+
+// BulkInsert ...
+func (t *tracingDo) BulkInsert(ctx context.Context, expr string, args interface{}) error {
+	ctx, span := startSpan(ctx, "bulk_insert", expr)
+	err := t.do.BulkInsert(ctx, expr, args)
+	tracing.End(span, err)
+	return err
+}
+
+var _ DoOrmWithTransaction = new(tracingDoTxn)
+
+// tracingDoTxn 为事务中的每个orm命令创建子span
+type tracingDoTxn struct {
+	doTxn DoOrmWithTransaction
+}
+
+// Count ...
+func (t *tracingDoTxn) Count(ctx context.Context, expr string, arg map[string]interface{}) (uint64, error) {
+	ctx, span := startSpan(ctx, "count", expr)
+	count, err := t.doTxn.Count(ctx, expr, arg)
+	tracing.End(span, err)
+	return count, err
+}
+
+// Select ...
+func (t *tracingDoTxn) Select(ctx context.Context, dest interface{}, expr string,
+	arg map[string]interface{}) error {
+
+	ctx, span := startSpan(ctx, "select", expr)
+	err := t.doTxn.Select(ctx, dest, expr, arg)
+	tracing.End(span, err)
+	return err
+}
+
+// Delete ...
+func (t *tracingDoTxn) Delete(ctx context.Context, expr string, args map[string]interface{}) (int64, error) {
+	ctx, span := startSpan(ctx, "delete", expr)
+	affected, err := t.doTxn.Delete(ctx, expr, args)
+	tracing.End(span, err)
+	return affected, err
+}
+
+// Update ...
+func (t *tracingDoTxn) Update(ctx context.Context, expr string, args map[string]interface{}) (int64, error) {
+	ctx, span := startSpan(ctx, "update", expr)
+	affected, err := t.doTxn.Update(ctx, expr, args)
+	tracing.End(span, err)
+	return affected, err
+}
+
+// Insert ...
+func (t *tracingDoTxn) Insert(ctx context.Context, expr string, args interface{}) error {
+	ctx, span := startSpan(ctx, "insert", expr)
+	err := t.doTxn.Insert(ctx, expr, args)
+	tracing.End(span, err)
+	return err
+}
+
+// BulkInsert ...
+func (t *tracingDoTxn) BulkInsert(ctx context.Context, expr string, args interface{}) error {
+	ctx, span := startSpan(ctx, "bulk_insert", expr)
+	err := t.doTxn.BulkInsert(ctx, expr, args)
+	tracing.End(span, err)
+	return err
+}
@@ -66,3 +66,36 @@ func MainAccountExtensionRemoveSecretKey(extension string) string {
 
 	return fmt.Sprintf("{%s}", ext[:len(ext)-1])
 }
+
+const (
+	// JSONFieldValuePlaceholder 为 JSONFieldCASExpr 中新值的占位符名称
+	JSONFieldValuePlaceholder = "json_field_value"
+	// JSONFieldOldValuePlaceholder 为 JSONFieldCASExpr 中原值的占位符名称
+	JSONFieldOldValuePlaceholder = "json_field_old_value"
+)
+
+// JSONFieldCASExpr 返回仅更新JSON列中一个字段的SET表达式，以及该字段等于原值的条件表达式，用于追加到WHERE条件中，
+// 新值、原值分别通过 JSONFieldValuePlaceholder、JSONFieldOldValuePlaceholder 占位符传入，如
+// extension = JSON_SET(extension, '$."secret_key"', :json_field_value) 和
+// extension->>'$."secret_key"' = :json_field_old_value
+func JSONFieldCASExpr(column string, path []string) (setExpr string, cmpExpr string, err error) {
+	if len(column) == 0 || strings.ContainsAny(column, "`\"'\\$. ") {
+		return "", "", fmt.Errorf("invalid json column: %s", column)
+	}
+
+	if len(path) == 0 {
+		return "", "", fmt.Errorf("json field path of %s is empty", column)
+	}
+
+	jsonPath := "$"
+	for _, field := range path {
+		if len(field) == 0 || strings.ContainsAny(field, `"'\$.`) {
+			return "", "", fmt.Errorf("invalid json field %s of %s", field, column)
+		}
+		jsonPath += fmt.Sprintf(`."%s"`, field)
+	}
+
+	setExpr = fmt.Sprintf(`%s = JSON_SET(%s, '%s', :%s)`, column, column, jsonPath, JSONFieldValuePlaceholder)
+	cmpExpr = fmt.Sprintf(`%s->>'%s' = :%s`, column, jsonPath, JSONFieldOldValuePlaceholder)
+	return setExpr, cmpExpr, nil
+}
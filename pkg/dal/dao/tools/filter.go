@@ -22,6 +22,7 @@ package tools
 import (
 	"fmt"
 
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/runtime/filter"
 )
 
@@ -192,3 +193,20 @@ func ExpressionOr(rules ...*filter.AtomRule) *filter.Expression {
 		Rules: factories,
 	}
 }
+
+// ResTagSqlWhereOption 返回设置了资源类型和查询表名(或别名)的 sql where option 副本，
+// 用于支持标签操作符 tag_eq、tag_in、tag_exists
+func ResTagSqlWhereOption(opt *filter.SQLWhereOption, resType enumor.CloudResourceType,
+	resTable string) *filter.SQLWhereOption {
+
+	copied := *opt
+	copied.ResType = resType
+	copied.ResTable = resTable
+	return &copied
+}
+
+// AddResTagRuleField 添加标签字段 tags.* 到可查询字段中
+func AddResTagRuleField(columnTypes map[string]enumor.ColumnType) map[string]enumor.ColumnType {
+	columnTypes[filter.ResTagFieldPrefix+filter.WildcardPlaceholder] = enumor.Json
+	return columnTypes
+}
@@ -44,3 +44,23 @@ type UpdateFlowInfo struct {
 func (info *UpdateFlowInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
+
+// ListAsyncScheduledFlows list async scheduled flows.
+type ListAsyncScheduledFlows struct {
+	Count   uint64                               `json:"count,omitempty"`
+	Details []tableasync.AsyncScheduledFlowTable `json:"details,omitempty"`
+}
+
+// UpdateScheduledFlowNextRunInfo define update scheduled flow next run at info.
+type UpdateScheduledFlowNextRunInfo struct {
+	ID string `json:"id" validate:"required"`
+	// Source 当前的下一次触发时间，仅当库中的值与之相等时更新
+	Source string `json:"source"`
+	// Target 新的下一次触发时间，为空时表示后续不再触发
+	Target string `json:"target"`
+}
+
+// Validate UpdateScheduledFlowNextRunInfo.
+func (info *UpdateScheduledFlowNextRunInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
@@ -26,3 +26,45 @@ type ListAuditDetails struct {
 	Count   uint64             `json:"count"`
 	Details []audit.AuditTable `json:"details"`
 }
+
+// AuditChainVerifyResult 审计哈希链校验结果
+type AuditChainVerifyResult struct {
+	ChainKey string `json:"chain_key"`
+	// RecordCount 链上现存的审计记录数，包含已归档的记录
+	RecordCount uint64 `json:"record_count"`
+	// ArchivedCount 链上已归档到对象存储的审计记录数
+	ArchivedCount uint64 `json:"archived_count"`
+	// LastSeq 链头记录的最后一条审计记录序号
+	LastSeq uint64 `json:"last_seq"`
+	// CheckpointCount 链上的签名检查点数
+	CheckpointCount uint64 `json:"checkpoint_count"`
+	// PrevChainKey 同分片的上一条链，链上第一条记录与其衔接
+	PrevChainKey string `json:"prev_chain_key"`
+	// Intact 哈希链是否完整，没有记录被篡改或删除
+	Intact bool `json:"intact"`
+	// Altered 被篡改的审计记录
+	Altered []AuditAlteredRecord `json:"altered"`
+	// Removed 被删除的审计记录序号区间
+	Removed []AuditSeqRange `json:"removed"`
+	// ForgedCheckpoints 签名校验失败的检查点ID
+	ForgedCheckpoints []uint64 `json:"forged_checkpoints"`
+	// CheckpointRemoved 链尾的检查点被删除
+	CheckpointRemoved bool `json:"checkpoint_removed"`
+	// ForgedHead 链头签名校验失败或链头被删除
+	ForgedHead bool `json:"forged_head"`
+	// BrokenLink 与上一条链的衔接被破坏，上一条链的记录被删除或篡改
+	BrokenLink bool `json:"broken_link"`
+}
+
+// AuditAlteredRecord 被篡改的审计记录
+type AuditAlteredRecord struct {
+	ID       uint64 `json:"id"`
+	ChainSeq uint64 `json:"chain_seq"`
+	Reason   string `json:"reason"`
+}
+
+// AuditSeqRange 审计记录序号区间，包含首尾
+type AuditSeqRange struct {
+	StartSeq uint64 `json:"start_seq"`
+	EndSeq   uint64 `json:"end_seq"`
+}
@@ -117,3 +117,21 @@ func (p *ItemCommonOpt) Validate() error {
 	}
 	return nil
 }
+
+// ListAccountBillBudgetDetails list account bill budget details
+type ListAccountBillBudgetDetails struct {
+	Count   uint64                        `json:"count,omitempty"`
+	Details []tablebill.AccountBillBudget `json:"details,omitempty"`
+}
+
+// ListAccountBillBudgetRecordDetails list account bill budget record details
+type ListAccountBillBudgetRecordDetails struct {
+	Count   uint64                              `json:"count,omitempty"`
+	Details []tablebill.AccountBillBudgetRecord `json:"details,omitempty"`
+}
+
+// ListAccountBillCostAnomalyDetails list account bill cost anomaly details
+type ListAccountBillCostAnomalyDetails struct {
+	Count   uint64                             `json:"count,omitempty"`
+	Details []tablebill.AccountBillCostAnomaly `json:"details,omitempty"`
+}
@@ -17,15 +17,12 @@
  * to the current version of the project delivered to anyone in the future.
  */
 
-package cloud
+package types
 
-import "hcm/pkg/api/core"
+import "hcm/pkg/dal/table/cloud"
 
-// SecurityGroupTag define security group tag.
-type SecurityGroupTag struct {
-	ID              string         `json:"id"`
-	SecurityGroupID string         `json:"security_group_id"`
-	Key             string         `json:"key"`
-	Value           string         `json:"value"`
-	Revision        *core.Revision `json:"revision"`
+// ListResAssignRuleDetails list res assign rule details.
+type ListResAssignRuleDetails struct {
+	Count   uint64                     `json:"count,omitempty"`
+	Details []cloud.ResAssignRuleTable `json:"details,omitempty"`
 }
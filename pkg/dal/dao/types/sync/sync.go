@@ -28,3 +28,9 @@ type ListAccountSyncDetails struct {
 	Count   uint64                              `json:"count,omitempty"`
 	Details []tablessync.AccountSyncDetailTable `json:"details,omitempty"`
 }
+
+// ListSyncCursorDetails list sync cursor details.
+type ListSyncCursorDetails struct {
+	Count   uint64                       `json:"count,omitempty"`
+	Details []tablessync.SyncCursorTable `json:"details,omitempty"`
+}
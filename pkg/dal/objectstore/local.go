@@ -0,0 +1,265 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	sts "github.com/tencentyun/qcloud-cos-sts-sdk/go"
+)
+
+// LocalURLPath 本地存储临时链接的访问路径，由 LocalStorage.ServeHTTP 处理
+const LocalURLPath = "/objectstore/local/objects"
+
+// LocalStorage local filesystem object store, used by dev and air-gapped deployments.
+type LocalStorage struct {
+	prefix string
+	config cc.ObjectStoreLocal
+	// now 用于测试时替换当前时间
+	now func() time.Time
+}
+
+// NewLocalStorage create local filesystem storage
+func NewLocalStorage(config cc.ObjectStoreLocal) (*LocalStorage, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(config.RootDir, 0o750); err != nil {
+		return nil, fmt.Errorf("create root dir %s failed, err %s", config.RootDir, err.Error())
+	}
+
+	return &LocalStorage{
+		prefix: config.Prefix,
+		config: config,
+		now:    time.Now,
+	}, nil
+}
+
+// Upload put object to path
+func (l *LocalStorage) Upload(kt *kit.Kit, uploadPath string, r io.Reader) error {
+	uploadPath = l.prependPrefix(uploadPath)
+	if err := l.writeFile(uploadPath, r); err != nil {
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	return nil
+}
+
+// Download get object from path
+func (l *LocalStorage) Download(kt *kit.Kit, downloadPath string, w io.Writer) error {
+	downloadPath = l.prependPrefix(downloadPath)
+	file, err := os.Open(l.filePath(downloadPath))
+	if err != nil {
+		return fmt.Errorf("get from path %s failed, err %s", downloadPath, err.Error())
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	if err != nil {
+		return fmt.Errorf("failed writing response, err %s", err.Error())
+	}
+	return nil
+}
+
+// ListItems list items under path, 与cos一致，仅返回当前目录下的文件，返回值包含前缀
+func (l *LocalStorage) ListItems(kt *kit.Kit, folderPath string) ([]string, error) {
+	folderPath = l.prependPrefix(folderPath)
+	entries, err := os.ReadDir(l.filePath(folderPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list item for path %s failed, err %s", folderPath, err.Error())
+	}
+
+	var retList []string
+	for _, entry := range entries {
+		// 跳过目录及上传中的临时文件
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		retList = append(retList, filepath.Join(folderPath, entry.Name()))
+	}
+	return retList, nil
+}
+
+// Delete delete object by path
+func (l *LocalStorage) Delete(kt *kit.Kit, path string) error {
+	err := os.Remove(l.filePath(l.prependPrefix(path)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetPreSignedURL 获取带HMAC签名的临时链接，由data-service的 LocalURLPath 处理，不返回临时密钥
+func (l *LocalStorage) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.Duration,
+	path string) (tempCred *sts.Credentials, url string, err error) {
+
+	if action != DownloadOperateAction && action != UploadOperateAction {
+		return nil, "", errors.New("invalid action for get presigned url: " + string(action))
+	}
+
+	key := l.prependPrefix(path)
+	expires := l.now().Add(ttl).Unix()
+	return nil, l.signedURL(action, key, expires), nil
+}
+
+// ServeHTTP 处理临时链接，GET 为下载，PUT 为上传
+func (l *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kt := kit.New()
+
+	var action OperateAction
+	switch r.Method {
+	case http.MethodGet:
+		action = DownloadOperateAction
+	case http.MethodPut:
+		action = UploadOperateAction
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	key := query.Get("path")
+	if err := l.verify(action, key, query.Get("expires"), query.Get("signature")); err != nil {
+		logs.Errorf("verify local object store url failed, err: %v, action: %s, path: %s, rid: %s", err, action,
+			key, kt.Rid)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if action == UploadOperateAction {
+		defer r.Body.Close()
+		if err := l.writeFile(key, r.Body); err != nil {
+			logs.Errorf("upload local object failed, err: %v, path: %s, rid: %s", err, key, kt.Rid)
+			http.Error(w, "upload failed", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	file, err := os.Open(l.filePath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "object not found", http.StatusNotFound)
+			return
+		}
+		logs.Errorf("open local object failed, err: %v, path: %s, rid: %s", err, key, kt.Rid)
+		http.Error(w, "download failed", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		logs.Errorf("stat local object failed, err: %v, path: %s, rid: %s", err, key, kt.Rid)
+		http.Error(w, "download failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filepath.Base(key)))
+	http.ServeContent(w, r, filepath.Base(key), stat.ModTime(), file)
+}
+
+func (l *LocalStorage) signedURL(action OperateAction, key string, expires int64) string {
+	query := url.Values{}
+	query.Set("path", key)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", l.sign(action, key, expires))
+	return strings.TrimRight(l.config.URLBase, "/") + LocalURLPath + "?" + query.Encode()
+}
+
+func (l *LocalStorage) sign(action OperateAction, key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(l.config.SignSecret))
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%d", action, key, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *LocalStorage) verify(action OperateAction, key, expiresStr, signature string) error {
+	if len(key) == 0 || len(expiresStr) == 0 || len(signature) == 0 {
+		return errors.New("path, expires and signature are required")
+	}
+
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires: %s", expiresStr)
+	}
+	if l.now().Unix() > expires {
+		return errors.New("url expired")
+	}
+
+	expected := l.sign(action, key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// writeFile 先写入临时文件再重命名，避免读取到写了一半的文件
+func (l *LocalStorage) writeFile(key string, r io.Reader) error {
+	target := l.filePath(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+// filePath 将对象key转换为根目录下的文件路径，key中的 .. 不会越出根目录
+func (l *LocalStorage) filePath(key string) string {
+	return filepath.Join(l.config.RootDir, filepath.Clean("/"+key))
+}
+
+func (l *LocalStorage) prependPrefix(path string) string {
+	return filepath.Join(l.prefix, path)
+}
@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStorage(t *testing.T, urlBase string) *LocalStorage {
+	store, err := NewLocalStorage(cc.ObjectStoreLocal{
+		RootDir:    t.TempDir(),
+		Prefix:     "hcm",
+		SignSecret: "secret",
+		URLBase:    urlBase,
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestLocalStorage(t *testing.T) {
+	kt := kit.New()
+	store := newTestLocalStorage(t, "http://127.0.0.1")
+
+	require.NoError(t, store.Upload(kt, "rawbills/a/1.csv", strings.NewReader("a,b")))
+	require.NoError(t, store.Upload(kt, "rawbills/a/2.csv", strings.NewReader("c,d")))
+	require.NoError(t, store.Upload(kt, "rawbills/a/sub/3.csv", strings.NewReader("e,f")))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, store.Download(kt, "rawbills/a/1.csv", buf))
+	assert.Equal(t, "a,b", buf.String())
+
+	items, err := store.ListItems(kt, "rawbills/a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hcm/rawbills/a/1.csv", "hcm/rawbills/a/2.csv"}, items)
+
+	require.NoError(t, store.Delete(kt, "rawbills/a/1.csv"))
+	require.Error(t, store.Download(kt, "rawbills/a/1.csv", new(bytes.Buffer)))
+	require.NoError(t, store.Delete(kt, "rawbills/a/1.csv"), "delete not exist object should succeed")
+
+	// 路径中的 .. 不能越出根目录
+	assert.True(t, strings.HasPrefix(store.filePath("../../etc/passwd"), store.config.RootDir))
+}
+
+func TestLocalStoragePreSignedURL(t *testing.T) {
+	kt := kit.New()
+	store := newTestLocalStorage(t, "http://placeholder")
+	server := httptest.NewServer(store)
+	defer server.Close()
+	store.config.URLBase = server.URL
+
+	_, uploadURL, err := store.GetPreSignedURL(kt, UploadOperateAction, time.Minute, "export/bill.csv")
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, uploadURL, strings.NewReader("x,y"))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// 上传链接不能用于下载
+	resp, err = http.Get(uploadURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	cred, downloadURL, err := store.GetPreSignedURL(kt, DownloadOperateAction, time.Minute, "export/bill.csv")
+	require.NoError(t, err)
+	assert.Nil(t, cred)
+	resp, err = http.Get(downloadURL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "x,y", string(body))
+
+	// 篡改路径
+	resp, err = http.Get(strings.Replace(downloadURL, "bill.csv", "other.csv", 1))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// 过期
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	resp, err = http.Get(downloadURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
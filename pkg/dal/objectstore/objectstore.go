@@ -32,6 +32,13 @@ import (
 	sts "github.com/tencentyun/qcloud-cos-sts-sdk/go"
 )
 
+const (
+	// S3StoreType s3 compatible object store, such as aws s3, minio, huawei obs.
+	S3StoreType = "s3"
+	// LocalStoreType local filesystem object store.
+	LocalStoreType = "local"
+)
+
 // GetObjectStore get object store from env
 func GetObjectStore(config cc.ObjectStore) (Storage, error) {
 	switch config.Type {
@@ -39,6 +46,10 @@ func GetObjectStore(config cc.ObjectStore) (Storage, error) {
 		return nil, nil
 	case string(enumor.TCloud):
 		return NewTCloudCOS(config.ObjectStoreTCloud)
+	case S3StoreType:
+		return NewS3Storage(config.S3)
+	case LocalStoreType:
+		return NewLocalStorage(config.Local)
 	default:
 		return nil, fmt.Errorf("invalid object store type %s", config.Type)
 	}
@@ -0,0 +1,233 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testS3Bucket = "hcm-bucket"
+
+// fakeS3 path-style s3 endpoint in memory, only implements the apis used by S3Storage.
+// Requests must be signed either by Authorization header or by presigned query.
+type fakeS3 struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") &&
+		r.URL.Query().Get("X-Amz-Signature") == "" {
+		writeS3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != testS3Bucket {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, r.URL.Query())
+	case r.Method == http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = body
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+type fakeS3ListResult struct {
+	XMLName     xml.Name `xml:"ListBucketResult"`
+	Name        string   `xml:"Name"`
+	Prefix      string   `xml:"Prefix"`
+	KeyCount    int      `xml:"KeyCount"`
+	IsTruncated bool     `xml:"IsTruncated"`
+	Contents    []struct {
+		Key  string `xml:"Key"`
+		Size int    `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, query url.Values) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	keys := make([]string, 0)
+	for key := range f.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		// 分隔符之后的对象属于子目录，不列出
+		if delimiter != "" && strings.Contains(strings.TrimPrefix(key, prefix), delimiter) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := fakeS3ListResult{Name: testS3Bucket, Prefix: prefix, KeyCount: len(keys)}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, struct {
+			Key  string `xml:"Key"`
+			Size int    `xml:"Size"`
+		}{Key: key, Size: len(f.objects[key])})
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("<Error><Code>" + code + "</Code><Message>" + code + "</Message></Error>"))
+}
+
+func newTestS3Storage(t *testing.T) (*S3Storage, *fakeS3) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Storage(cc.ObjectStoreS3{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		AccessKeyID:     "ak",
+		SecretAccessKey: "sk",
+		Bucket:          testS3Bucket,
+		Prefix:          "hcm",
+		ForcePathStyle:  true,
+	})
+	require.NoError(t, err)
+	return store, fake
+}
+
+func TestNewS3StorageBucketNotExist(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	_, err := NewS3Storage(cc.ObjectStoreS3{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		AccessKeyID:     "ak",
+		SecretAccessKey: "sk",
+		Bucket:          "other-bucket",
+		ForcePathStyle:  true,
+	})
+	assert.Error(t, err)
+}
+
+func TestS3Storage(t *testing.T) {
+	kt := kit.New()
+	store, fake := newTestS3Storage(t)
+
+	require.NoError(t, store.Upload(kt, "rawbills/a/1.csv", strings.NewReader("a,b")))
+	require.NoError(t, store.Upload(kt, "rawbills/a/2.csv", strings.NewReader("c,d")))
+	require.NoError(t, store.Upload(kt, "rawbills/a/sub/3.csv", strings.NewReader("e,f")))
+	assert.Equal(t, []byte("a,b"), fake.objects["hcm/rawbills/a/1.csv"], "key should be prefixed")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, store.Download(kt, "rawbills/a/1.csv", buf))
+	assert.Equal(t, "a,b", buf.String())
+
+	items, err := store.ListItems(kt, "rawbills/a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hcm/rawbills/a/1.csv", "hcm/rawbills/a/2.csv"}, items)
+
+	require.NoError(t, store.Delete(kt, "rawbills/a/1.csv"))
+	require.Error(t, store.Download(kt, "rawbills/a/1.csv", new(bytes.Buffer)))
+	require.NoError(t, store.Delete(kt, "rawbills/a/1.csv"), "delete not exist object should succeed")
+}
+
+func TestS3StoragePreSignedURL(t *testing.T) {
+	kt := kit.New()
+	store, fake := newTestS3Storage(t)
+
+	cred, uploadURL, err := store.GetPreSignedURL(kt, UploadOperateAction, time.Minute, "export/bill.csv")
+	require.NoError(t, err)
+	assert.Nil(t, cred, "s3 presigned url should not return temp credential")
+	parsed, err := url.Parse(uploadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/"+testS3Bucket+"/hcm/export/bill.csv", parsed.Path)
+	assert.Equal(t, "60", parsed.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, parsed.Query().Get("X-Amz-Signature"))
+
+	req, err := http.NewRequest(http.MethodPut, uploadURL, strings.NewReader("x,y"))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte("x,y"), fake.objects["hcm/export/bill.csv"])
+
+	_, downloadURL, err := store.GetPreSignedURL(kt, DownloadOperateAction, time.Minute, "export/bill.csv")
+	require.NoError(t, err)
+	resp, err = http.Get(downloadURL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "x,y", string(body))
+
+	// 未签名的请求会被拒绝
+	resp, err = http.Get(strings.Split(downloadURL, "?")[0])
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, _, err = store.GetPreSignedURL(kt, OperateAction("invalid"), time.Minute, "export/bill.csv")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ApprovalChainColumns defines all the approval chain table's columns.
+var ApprovalChainColumns = utils.MergeColumns(nil, ApprovalChainColumnDescriptor)
+
+// ApprovalChainColumnDescriptor is approval chain's column descriptors.
+var ApprovalChainColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "stages", NamedC: "stages", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ApprovalChainTable 内置审批引擎的审批链配置表
+type ApprovalChainTable struct {
+	ID              string `db:"id" validate:"lte=64" json:"id"`
+	ApplicationType string `db:"application_type" validate:"lte=64" json:"application_type"`
+	// BkBizID 适用的业务，-1表示默认审批链
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Stages 审批节点
+	Stages    types.JsonField `db:"stages" json:"stages"`
+	Memo      *string         `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator   string          `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser   string          `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt types.Time      `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt types.Time      `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return approval chain table name.
+func (t ApprovalChainTable) TableName() table.Name {
+	return table.ApprovalChainTable
+}
+
+// InsertValidate approval chain table when insert.
+func (t ApprovalChainTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.ApplicationType) == 0 {
+		return errors.New("application_type is required")
+	}
+
+	if t.BkBizID == 0 || t.BkBizID < -1 {
+		return errors.New("bk_biz_id should be > 0 or -1")
+	}
+
+	if len(t.Stages) == 0 {
+		return errors.New("stages is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate approval chain table when update.
+func (t ApprovalChainTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.ApplicationType) != 0 || t.BkBizID != 0 {
+		return errors.New("application_type and bk_biz_id can not update")
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ApprovalTicketColumns defines all the approval ticket table's columns.
+var ApprovalTicketColumns = utils.MergeColumns(nil, ApprovalTicketColumnDescriptor)
+
+// ApprovalTicketColumnDescriptor is approval ticket's column descriptors.
+var ApprovalTicketColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "sn", NamedC: "sn", Type: enumor.String},
+	{Column: "application_id", NamedC: "application_id", Type: enumor.String},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "applicant", NamedC: "applicant", Type: enumor.String},
+	{Column: "title", NamedC: "title", Type: enumor.String},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "current_stage", NamedC: "current_stage", Type: enumor.Numeric},
+	{Column: "stages", NamedC: "stages", Type: enumor.Json},
+	{Column: "current_approvers", NamedC: "current_approvers", Type: enumor.Json},
+	{Column: "deadline", NamedC: "deadline", Type: enumor.String},
+	{Column: "records", NamedC: "records", Type: enumor.Json},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ApprovalTicketTable 内置审批引擎的审批单表，一个申请单对应一个审批单
+type ApprovalTicketTable struct {
+	ID              string `db:"id" validate:"lte=64" json:"id"`
+	SN              string `db:"sn" validate:"lte=64" json:"sn"`
+	ApplicationID   string `db:"application_id" validate:"lte=64" json:"application_id"`
+	ApplicationType string `db:"application_type" validate:"lte=64" json:"application_type"`
+	BkBizID         int64  `db:"bk_biz_id" json:"bk_biz_id"`
+	Applicant       string `db:"applicant" validate:"lte=64" json:"applicant"`
+	Title           string `db:"title" validate:"lte=255" json:"title"`
+	Status          string `db:"status" validate:"lte=32" json:"status"`
+	// CurrentStage 当前审批节点序号
+	CurrentStage *int64 `db:"current_stage" json:"current_stage"`
+	// Stages 创建时审批链的快照及各节点审批进度
+	Stages types.JsonField `db:"stages" json:"stages"`
+	// CurrentApprovers 当前节点待审批人
+	CurrentApprovers types.StringArray `db:"current_approvers" json:"current_approvers"`
+	// Deadline 当前节点超时时间，为空表示不超时
+	Deadline *string `db:"deadline" validate:"omitempty,lte=32" json:"deadline"`
+	// Records 审批记录
+	Records types.JsonField `db:"records" json:"records"`
+	// Version 版本号，每次更新加一，用于并发审批时的乐观锁
+	Version   int64      `db:"version" json:"version"`
+	Creator   string     `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser   string     `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt types.Time `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return approval ticket table name.
+func (t ApprovalTicketTable) TableName() table.Name {
+	return table.ApprovalTicketTable
+}
+
+// InsertValidate approval ticket table when insert.
+func (t ApprovalTicketTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.SN) == 0 {
+		return errors.New("sn is required")
+	}
+
+	if len(t.ApplicationID) == 0 {
+		return errors.New("application_id is required")
+	}
+
+	if len(t.ApplicationType) == 0 {
+		return errors.New("application_type is required")
+	}
+
+	if len(t.Applicant) == 0 {
+		return errors.New("applicant is required")
+	}
+
+	if len(t.Status) == 0 {
+		return errors.New("status is required")
+	}
+
+	if t.CurrentStage == nil {
+		return errors.New("current_stage is required")
+	}
+
+	if len(t.Stages) == 0 {
+		return errors.New("stages is required")
+	}
+
+	if t.CurrentApprovers == nil {
+		return errors.New("current_approvers is required")
+	}
+
+	if t.Deadline == nil {
+		return errors.New("deadline is required")
+	}
+
+	if len(t.Records) == 0 {
+		return errors.New("records is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate approval ticket table when update.
+func (t ApprovalTicketTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.SN) != 0 || len(t.ApplicationID) != 0 {
+		return errors.New("sn and application_id can not update")
+	}
+
+	if t.Version <= 0 {
+		return errors.New("version should be > 0")
+	}
+
+	return nil
+}
@@ -41,6 +41,9 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
 	{Column: "share_data", NamedC: "share_data", Type: enumor.Json},
 	{Column: "worker", NamedC: "worker", Type: enumor.String},
+	{Column: "rollback_mode", NamedC: "rollback_mode", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "concurrency_key", NamedC: "concurrency_key", Type: enumor.String},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -56,10 +59,16 @@ type AsyncFlowTable struct {
 	ShareData *ShareData       `db:"share_data" json:"share_data"`
 	Memo      string           `db:"memo" json:"memo"`
 	Worker    *string          `db:"worker" json:"worker"`
-	Creator   string           `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser   string           `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt types.Time       `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt types.Time       `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// RollbackMode 任务流失败后的回滚模式
+	RollbackMode enumor.FlowRollbackMode `db:"rollback_mode" json:"rollback_mode"`
+	// Priority 任务流派发优先级
+	Priority enumor.FlowPriority `db:"priority" json:"priority"`
+	// ConcurrencyKey 并发控制键，相同键的任务流同时运行的数量受限
+	ConcurrencyKey string     `db:"concurrency_key" json:"concurrency_key" validate:"lte=255"`
+	Creator        string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser        string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt      types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt      types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return async_flow table name.
@@ -86,6 +95,18 @@ func (a AsyncFlowTable) InsertValidate() error {
 		return errors.New("state is required")
 	}
 
+	if len(a.RollbackMode) != 0 {
+		if err := a.RollbackMode.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if a.Priority != 0 {
+		if err := a.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if len(a.Creator) == 0 {
 		return errors.New("creator is required")
 	}
@@ -112,5 +133,17 @@ func (a AsyncFlowTable) UpdateValidate() error {
 		return errors.New("creator can not update")
 	}
 
+	if len(a.RollbackMode) != 0 {
+		return errors.New("rollback_mode can not update")
+	}
+
+	if a.Priority != 0 {
+		return errors.New("priority can not update")
+	}
+
+	if len(a.ConcurrencyKey) != 0 {
+		return errors.New("concurrency_key can not update")
+	}
+
 	return nil
 }
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncScheduledFlowColumns defines all the async_scheduled_flow table's columns.
+var AsyncScheduledFlowColumns = utils.MergeColumns(nil, AsyncScheduledFlowTableColumnDescriptor)
+
+// AsyncScheduledFlowTableColumnDescriptor is async_scheduled_flow's column descriptors.
+var AsyncScheduledFlowTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "cron", NamedC: "cron", Type: enumor.String},
+	{Column: "flow_name", NamedC: "flow_name", Type: enumor.String},
+	{Column: "tasks", NamedC: "tasks", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "misfire_policy", NamedC: "misfire_policy", Type: enumor.String},
+	{Column: "enable", NamedC: "enable", Type: enumor.Boolean},
+	{Column: "last_flow_id", NamedC: "last_flow_id", Type: enumor.String},
+	{Column: "last_run_at", NamedC: "last_run_at", Type: enumor.String},
+	{Column: "next_run_at", NamedC: "next_run_at", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AsyncScheduledFlowTable define async_scheduled_flow table.
+type AsyncScheduledFlowTable struct {
+	ID            string               `db:"id" json:"id" validate:"lte=64"`
+	Name          string               `db:"name" json:"name" validate:"lte=64"`
+	Cron          string               `db:"cron" json:"cron" validate:"lte=64"`
+	FlowName      enumor.FlowName      `db:"flow_name" json:"flow_name"`
+	Tasks         ScheduledFlowTasks   `db:"tasks" json:"tasks"`
+	Memo          string               `db:"memo" json:"memo" validate:"lte=64"`
+	MisfirePolicy enumor.MisfirePolicy `db:"misfire_policy" json:"misfire_policy"`
+	Enable        *bool                `db:"enable" json:"enable"`
+	// LastFlowID 最近一次触发生成的任务流ID，用于防止同一个定时任务流重叠执行
+	LastFlowID string `db:"last_flow_id" json:"last_flow_id" validate:"lte=64"`
+	// LastRunAt 最近一次触发对应的计划触发时间
+	LastRunAt string `db:"last_run_at" json:"last_run_at"`
+	// NextRunAt 下一次计划触发时间
+	NextRunAt string     `db:"next_run_at" json:"next_run_at"`
+	Creator   string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return async_scheduled_flow table name.
+func (a AsyncScheduledFlowTable) TableName() table.Name {
+	return table.AsyncScheduledFlowTable
+}
+
+// InsertValidate async_scheduled_flow table when insert.
+func (a AsyncScheduledFlowTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(a.Cron) == 0 {
+		return errors.New("cron is required")
+	}
+
+	if len(a.FlowName) == 0 {
+		return errors.New("flow_name is required")
+	}
+
+	if len(a.MisfirePolicy) == 0 {
+		return errors.New("misfire_policy is required")
+	}
+
+	if a.Enable == nil {
+		return errors.New("enable is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate async_scheduled_flow table when update.
+func (a AsyncScheduledFlowTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.Name) != 0 {
+		return errors.New("name can not update")
+	}
+
+	if len(a.FlowName) != 0 {
+		return errors.New("flow_name can not update")
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// ScheduledFlowTasks define scheduled flow template task params.
+type ScheduledFlowTasks []ScheduledFlowTask
+
+// ScheduledFlowTask define scheduled flow template task param.
+type ScheduledFlowTask struct {
+	ActionID string          `json:"action_id"`
+	Params   types.JsonField `json:"params"`
+}
+
+// Scan is used to decode raw message which is read from db into ScheduledFlowTasks.
+func (d *ScheduledFlowTasks) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the ScheduledFlowTasks to a json raw, so that it can be stored to db with json raw.
+func (d ScheduledFlowTasks) Value() (driver.Value, error) {
+	return types.Value(d)
+}
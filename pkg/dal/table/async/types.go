@@ -31,6 +31,10 @@ type Reason struct {
 	PreState string `json:"pre_state,omitempty"`
 	// 改为rollback的次数
 	RollbackCount uint `json:"rollback_count,omitempty"`
+	// StartedAt 任务首次进入running状态的时间，仅任务使用
+	StartedAt string `json:"started_at,omitempty"`
+	// EndedAt 任务进入终态(success/failed/canceled)的时间，仅任务使用
+	EndedAt string `json:"ended_at,omitempty"`
 }
 
 // Scan is used to decode raw message which is read from db into Reason.
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AuditArchiveColumns defines all the audit archive table's columns.
+var AuditArchiveColumns = utils.MergeColumns(utils.InsertWithoutPrimaryID, AuditArchiveColumnDescriptor)
+
+// AuditArchiveColumnDescriptor is AuditArchiveTable's column descriptors.
+var AuditArchiveColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "partition_key", NamedC: "partition_key", Type: enumor.String},
+	{Column: "path", NamedC: "path", Type: enumor.String},
+	{Column: "start_id", NamedC: "start_id", Type: enumor.Numeric},
+	{Column: "end_id", NamedC: "end_id", Type: enumor.Numeric},
+	{Column: "record_count", NamedC: "record_count", Type: enumor.Numeric},
+	{Column: "start_at", NamedC: "start_at", Type: enumor.Time},
+	{Column: "end_at", NamedC: "end_at", Type: enumor.Time},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AuditArchiveTable 审计记录归档文件清单，每个归档文件是同一天同一资源类型的一批审计记录，
+// 使用 gzip 压缩的 JSON Lines 格式
+type AuditArchiveTable struct {
+	ID      uint64                   `db:"id" json:"id"`
+	ResType enumor.AuditResourceType `db:"res_type" json:"res_type"`
+	// PartitionKey 归档分区，为审计记录所属的哈希链，即记录写入的日期，存量记录使用创建日期
+	PartitionKey string `db:"partition_key" json:"partition_key"`
+	// Path 归档文件在对象存储中的路径
+	Path        string `db:"path" json:"path"`
+	StartID     uint64 `db:"start_id" json:"start_id"`
+	EndID       uint64 `db:"end_id" json:"end_id"`
+	RecordCount uint64 `db:"record_count" json:"record_count"`
+	// StartAt、EndAt 归档文件中审计记录创建时间的范围，用于按时间范围查询归档
+	StartAt   time.Time  `db:"start_at" json:"start_at"`
+	EndAt     time.Time  `db:"end_at" json:"end_at"`
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+}
+
+// TableName is the audit archive's database table name.
+func (a AuditArchiveTable) TableName() table.Name {
+	return table.AuditArchiveTable
+}
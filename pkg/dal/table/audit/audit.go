@@ -52,6 +52,10 @@ var AuditColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "rid", NamedC: "rid", Type: enumor.String},
 	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
 	{Column: "detail", NamedC: "detail", Type: enumor.Json},
+	{Column: "chain_key", NamedC: "chain_key", Type: enumor.String},
+	{Column: "chain_seq", NamedC: "chain_seq", Type: enumor.Numeric},
+	{Column: "prev_hash", NamedC: "prev_hash", Type: enumor.String},
+	{Column: "hash", NamedC: "hash", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 }
 
@@ -71,7 +75,15 @@ type AuditTable struct {
 	Rid        string                   `db:"rid" json:"rid" validate:"lte=64"`
 	AppCode    string                   `db:"app_code" json:"app_code" validate:"lte=64"`
 	Detail     *BasicDetail             `db:"detail" json:"detail" validate:"-"`
-	CreatedAt  types.Time               `db:"created_at" json:"created_at"`
+	// ChainKey 审计记录所属的哈希链，按天划分，例如 2024-12-18
+	ChainKey string `db:"chain_key" json:"chain_key" validate:"lte=16"`
+	// ChainSeq 审计记录在哈希链中的序号，从1开始连续递增
+	ChainSeq uint64 `db:"chain_seq" json:"chain_seq"`
+	// PrevHash 哈希链中上一条审计记录的哈希值
+	PrevHash string `db:"prev_hash" json:"prev_hash" validate:"lte=64"`
+	// Hash 审计记录的哈希值，由上一条记录的哈希值和当前记录内容计算得到
+	Hash      string     `db:"hash" json:"hash" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
 }
 
 // CreateValidate audit when created
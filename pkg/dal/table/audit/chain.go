@@ -0,0 +1,284 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AuditChainColumns defines all the audit chain table's columns.
+var AuditChainColumns = utils.MergeColumns(nil, AuditChainColumnDescriptor)
+
+// AuditChainColumnDescriptor is AuditChainTable's column descriptors.
+var AuditChainColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "chain_key", NamedC: "chain_key", Type: enumor.String},
+	{Column: "shard", NamedC: "shard", Type: enumor.Numeric},
+	{Column: "prev_chain_key", NamedC: "prev_chain_key", Type: enumor.String},
+	{Column: "prev_seq", NamedC: "prev_seq", Type: enumor.Numeric},
+	{Column: "prev_hash", NamedC: "prev_hash", Type: enumor.String},
+	{Column: "last_seq", NamedC: "last_seq", Type: enumor.Numeric},
+	{Column: "last_hash", NamedC: "last_hash", Type: enumor.String},
+	{Column: "checkpoint_seq", NamedC: "checkpoint_seq", Type: enumor.Numeric},
+	{Column: "checkpoint_sig", NamedC: "checkpoint_sig", Type: enumor.String},
+	{Column: "checkpoint_at", NamedC: "checkpoint_at", Type: enumor.Time},
+	{Column: "signature", NamedC: "signature", Type: enumor.String},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AuditChainTable 审计哈希链的链头，写入审计记录时加行锁保证同一条链的记录串行追加
+type AuditChainTable struct {
+	ChainKey string `db:"chain_key" json:"chain_key"`
+	// Shard 哈希链所属的分片
+	Shard uint `db:"shard" json:"shard"`
+	// PrevChainKey 同分片的上一条链，链上第一条记录的 prev_hash 为上一条链中 PrevSeq 记录的哈希值 PrevHash
+	PrevChainKey string `db:"prev_chain_key" json:"prev_chain_key"`
+	PrevSeq      uint64 `db:"prev_seq" json:"prev_seq"`
+	PrevHash     string `db:"prev_hash" json:"prev_hash"`
+	// LastSeq 链上最后一条审计记录的序号
+	LastSeq uint64 `db:"last_seq" json:"last_seq"`
+	// LastHash 链上最后一条审计记录的哈希值
+	LastHash string `db:"last_hash" json:"last_hash"`
+	// CheckpointSeq 最近一个检查点对应的审计记录序号
+	CheckpointSeq uint64 `db:"checkpoint_seq" json:"checkpoint_seq"`
+	// CheckpointSig 最近一个检查点的签名，下一个检查点的签名包含它，使检查点也串成链
+	CheckpointSig string `db:"checkpoint_sig" json:"checkpoint_sig"`
+	// CheckpointAt 最近一个检查点的生成时间
+	CheckpointAt types.Time `db:"checkpoint_at" json:"checkpoint_at"`
+	// Signature 链头签名，每次追加记录时更新，用于发现链尾记录被删除后链头被回退
+	Signature string     `db:"signature" json:"signature"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName is the audit chain's database table name.
+func (a AuditChainTable) TableName() table.Name {
+	return table.AuditChainTable
+}
+
+// AuditCheckpointColumns defines all the audit checkpoint table's columns.
+var AuditCheckpointColumns = utils.MergeColumns(utils.InsertWithoutPrimaryID, AuditCheckpointColumnDescriptor)
+
+// AuditCheckpointColumnDescriptor is AuditCheckpointTable's column descriptors.
+var AuditCheckpointColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.Numeric},
+	{Column: "chain_key", NamedC: "chain_key", Type: enumor.String},
+	{Column: "chain_seq", NamedC: "chain_seq", Type: enumor.Numeric},
+	{Column: "hash", NamedC: "hash", Type: enumor.String},
+	{Column: "signature", NamedC: "signature", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AuditCheckpointTable 审计哈希链的签名检查点，签名密钥不存放在数据库中，
+// 即使整条链被重新计算哈希，也能通过检查点发现
+type AuditCheckpointTable struct {
+	ID        uint64     `db:"id" json:"id"`
+	ChainKey  string     `db:"chain_key" json:"chain_key"`
+	ChainSeq  uint64     `db:"chain_seq" json:"chain_seq"`
+	Hash      string     `db:"hash" json:"hash"`
+	Signature string     `db:"signature" json:"signature"`
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+}
+
+// TableName is the audit checkpoint's database table name.
+func (a AuditCheckpointTable) TableName() table.Name {
+	return table.AuditCheckpointTable
+}
+
+// ChainRecordColumns 校验哈希链时需要查询的审计记录字段
+var ChainRecordColumns = []string{"id", "res_id", "cloud_res_id", "res_name", "res_type", "action", "bk_biz_id",
+	"vendor", "account_id", "operator", "source", "rid", "app_code", "detail", "chain_key", "chain_seq", "prev_hash",
+	"hash"}
+
+// ChainRecord 校验哈希链时使用的审计记录，detail 保留数据库中的原始json
+type ChainRecord struct {
+	ID         uint64                   `db:"id"`
+	ResID      string                   `db:"res_id"`
+	CloudResID string                   `db:"cloud_res_id"`
+	ResName    string                   `db:"res_name"`
+	ResType    enumor.AuditResourceType `db:"res_type"`
+	Action     enumor.AuditAction       `db:"action"`
+	BkBizID    int64                    `db:"bk_biz_id"`
+	Vendor     enumor.Vendor            `db:"vendor"`
+	AccountID  string                   `db:"account_id"`
+	Operator   string                   `db:"operator"`
+	Source     enumor.RequestSourceType `db:"source"`
+	Rid        string                   `db:"rid"`
+	AppCode    string                   `db:"app_code"`
+	Detail     *types.JsonField         `db:"detail"`
+	ChainKey   string                   `db:"chain_key"`
+	ChainSeq   uint64                   `db:"chain_seq"`
+	PrevHash   string                   `db:"prev_hash"`
+	Hash       string                   `db:"hash"`
+}
+
+// NewChainRecord 将审计记录转换为校验哈希链使用的记录，用于校验已归档的审计记录
+func NewChainRecord(a *AuditTable) (*ChainRecord, error) {
+	record := &ChainRecord{
+		ID:         a.ID,
+		ResID:      a.ResID,
+		CloudResID: a.CloudResID,
+		ResName:    a.ResName,
+		ResType:    a.ResType,
+		Action:     a.Action,
+		BkBizID:    a.BkBizID,
+		Vendor:     a.Vendor,
+		AccountID:  a.AccountID,
+		Operator:   a.Operator,
+		Source:     a.Source,
+		Rid:        a.Rid,
+		AppCode:    a.AppCode,
+		ChainKey:   a.ChainKey,
+		ChainSeq:   a.ChainSeq,
+		PrevHash:   a.PrevHash,
+		Hash:       a.Hash,
+	}
+
+	if a.Detail != nil {
+		detail, err := json.Marshal(a.Detail)
+		if err != nil {
+			return nil, fmt.Errorf("marshal audit detail failed, err: %v", err)
+		}
+		field := types.JsonField(detail)
+		record.Detail = &field
+	}
+
+	return record, nil
+}
+
+// ChainHash 重新计算审计记录的哈希值
+func (r *ChainRecord) ChainHash() (string, error) {
+	var detail []byte
+	if r.Detail != nil {
+		detail = []byte(*r.Detail)
+	}
+
+	return chainHash(chainContent{
+		ChainKey:   r.ChainKey,
+		ChainSeq:   r.ChainSeq,
+		PrevHash:   r.PrevHash,
+		ResID:      r.ResID,
+		CloudResID: r.CloudResID,
+		ResName:    r.ResName,
+		ResType:    r.ResType,
+		Action:     r.Action,
+		BkBizID:    r.BkBizID,
+		Vendor:     r.Vendor,
+		AccountID:  r.AccountID,
+		Operator:   r.Operator,
+		Source:     r.Source,
+		Rid:        r.Rid,
+		AppCode:    r.AppCode,
+	}, detail)
+}
+
+// ChainHash 计算审计记录在哈希链中的哈希值，需要先设置 ChainKey、ChainSeq、PrevHash
+func (a *AuditTable) ChainHash() (string, error) {
+	var detail []byte
+	if a.Detail != nil {
+		var err error
+		if detail, err = json.Marshal(a.Detail); err != nil {
+			return "", fmt.Errorf("marshal audit detail failed, err: %v", err)
+		}
+	}
+
+	return chainHash(chainContent{
+		ChainKey:   a.ChainKey,
+		ChainSeq:   a.ChainSeq,
+		PrevHash:   a.PrevHash,
+		ResID:      a.ResID,
+		CloudResID: a.CloudResID,
+		ResName:    a.ResName,
+		ResType:    a.ResType,
+		Action:     a.Action,
+		BkBizID:    a.BkBizID,
+		Vendor:     a.Vendor,
+		AccountID:  a.AccountID,
+		Operator:   a.Operator,
+		Source:     a.Source,
+		Rid:        a.Rid,
+		AppCode:    a.AppCode,
+	}, detail)
+}
+
+// chainContent 参与哈希计算的审计记录内容。created_at 由数据库生成，不参与计算，记录所属的天由 chain_key 保证
+type chainContent struct {
+	ChainKey   string                   `json:"chain_key"`
+	ChainSeq   uint64                   `json:"chain_seq"`
+	PrevHash   string                   `json:"prev_hash"`
+	ResID      string                   `json:"res_id"`
+	CloudResID string                   `json:"cloud_res_id"`
+	ResName    string                   `json:"res_name"`
+	ResType    enumor.AuditResourceType `json:"res_type"`
+	Action     enumor.AuditAction       `json:"action"`
+	BkBizID    int64                    `json:"bk_biz_id"`
+	Vendor     enumor.Vendor            `json:"vendor"`
+	AccountID  string                   `json:"account_id"`
+	Operator   string                   `json:"operator"`
+	Source     enumor.RequestSourceType `json:"source"`
+	Rid        string                   `json:"rid"`
+	AppCode    string                   `json:"app_code"`
+	Detail     json.RawMessage          `json:"detail"`
+}
+
+func chainHash(content chainContent, detail []byte) (string, error) {
+	canonical, err := canonicalJSON(detail)
+	if err != nil {
+		return "", err
+	}
+	content.Detail = canonical
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit chain content failed, err: %v", err)
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON mysql json 类型会重排key、调整空白字符，统一转换为key有序的紧凑格式后再计算哈希，数字保持原样避免精度变化
+func canonicalJSON(raw []byte) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode audit detail failed, err: %v", err)
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit detail failed, err: %v", err)
+	}
+
+	return canonical, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+)
+
+func TestChainHash(t *testing.T) {
+	audit := &AuditTable{
+		ResID:    "00000001",
+		ResName:  "cvm-1",
+		ResType:  enumor.CvmAuditResType,
+		Action:   enumor.Update,
+		BkBizID:  100,
+		Vendor:   enumor.TCloud,
+		Operator: "admin",
+		Detail: &BasicDetail{
+			Data:    map[string]interface{}{"name": "cvm-1", "cpu": 2},
+			Changed: map[string]interface{}{"name": "cvm-2"},
+		},
+		ChainKey: "2024-12-18",
+		ChainSeq: 1,
+	}
+	hash, err := audit.ChainHash()
+	if err != nil {
+		t.Fatalf("calculate audit hash failed, err: %v", err)
+	}
+
+	// mysql json 会重排key并加入空格，重新计算的哈希值应保持一致
+	detail := types.JsonField(`{"changed": {"name": "cvm-2"}, "data": {"cpu": 2, "name": "cvm-1"}}`)
+	record := &ChainRecord{
+		ResID:    audit.ResID,
+		ResName:  audit.ResName,
+		ResType:  audit.ResType,
+		Action:   audit.Action,
+		BkBizID:  audit.BkBizID,
+		Vendor:   audit.Vendor,
+		Operator: audit.Operator,
+		Detail:   &detail,
+		ChainKey: audit.ChainKey,
+		ChainSeq: audit.ChainSeq,
+	}
+	recordHash, err := record.ChainHash()
+	if err != nil {
+		t.Fatalf("calculate record hash failed, err: %v", err)
+	}
+	if recordHash != hash {
+		t.Errorf("record hash %s not equal to audit hash %s", recordHash, hash)
+	}
+
+	record.Operator = "hacker"
+	tampered, err := record.ChainHash()
+	if err != nil {
+		t.Fatalf("calculate tampered record hash failed, err: %v", err)
+	}
+	if tampered == hash {
+		t.Errorf("tampered record should have different hash")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AuditOutboxColumns defines all the audit outbox table's columns.
+var AuditOutboxColumns = utils.MergeColumns(utils.InsertWithoutPrimaryID, AuditOutboxColumnDescriptor)
+
+// AuditOutboxColumnDescriptor is AuditOutboxTable's column descriptors.
+var AuditOutboxColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "action", NamedC: "action", Type: enumor.String},
+	{Column: "payload", NamedC: "payload", Type: enumor.Json},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AuditOutboxTable 审计事件发件箱，和审计记录在同一个事务中写入，投递目标按自增id顺序消费
+type AuditOutboxTable struct {
+	ID      uint64                   `db:"id" json:"id"`
+	ResType enumor.AuditResourceType `db:"res_type" json:"res_type"`
+	Action  enumor.AuditAction       `db:"action" json:"action"`
+	// Payload 审计记录的json内容
+	Payload   types.JsonField `db:"payload" json:"payload"`
+	CreatedAt types.Time      `db:"created_at" json:"created_at"`
+}
+
+// TableName is the audit outbox's database table name.
+func (a AuditOutboxTable) TableName() table.Name {
+	return table.AuditOutboxTable
+}
+
+// AuditSinkCursorColumns defines all the audit sink cursor table's columns.
+var AuditSinkCursorColumns = utils.MergeColumns(nil, AuditSinkCursorColumnDescriptor)
+
+// AuditSinkCursorColumnDescriptor is AuditSinkCursorTable's column descriptors.
+var AuditSinkCursorColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "sink", NamedC: "sink", Type: enumor.String},
+	{Column: "last_id", NamedC: "last_id", Type: enumor.Numeric},
+	{Column: "attempts", NamedC: "attempts", Type: enumor.Numeric},
+	{Column: "last_error", NamedC: "last_error", Type: enumor.String},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AuditSinkCursorTable 审计事件投递目标的投递进度
+type AuditSinkCursorTable struct {
+	// Sink 投递目标名称
+	Sink string `db:"sink" json:"sink"`
+	// LastID 已投递成功的最后一个发件箱记录id
+	LastID uint64 `db:"last_id" json:"last_id"`
+	// Attempts 当前批次连续投递失败的次数，投递成功后清零
+	Attempts uint `db:"attempts" json:"attempts"`
+	// LastError 最近一次投递失败的原因
+	LastError string     `db:"last_error" json:"last_error"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName is the audit sink cursor's database table name.
+func (a AuditSinkCursorTable) TableName() table.Name {
+	return table.AuditSinkCursorTable
+}
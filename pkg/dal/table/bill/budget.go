@@ -0,0 +1,255 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	cvt "hcm/pkg/tools/converter"
+)
+
+// AccountBillBudgetColumns defines account_bill_budget's columns.
+var AccountBillBudgetColumns = utils.MergeColumns(nil, AccountBillBudgetColumnDescriptor)
+
+// AccountBillBudgetColumnDescriptor is account_bill_budget's column descriptors.
+var AccountBillBudgetColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "scope_type", NamedC: "scope_type", Type: enumor.String},
+	{Column: "scope_value", NamedC: "scope_value", Type: enumor.String},
+	{Column: "period", NamedC: "period", Type: enumor.String},
+	{Column: "amount", NamedC: "amount", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "actual_thresholds", NamedC: "actual_thresholds", Type: enumor.Json},
+	{Column: "forecast_thresholds", NamedC: "forecast_thresholds", Type: enumor.Json},
+	{Column: "receivers", NamedC: "receivers", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillBudget 云账单预算表
+type AccountBillBudget struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// Name 预算名称
+	Name string `db:"name" validate:"lte=255" json:"name"`
+	// ScopeType 预算范围类型
+	ScopeType enumor.BudgetScopeType `db:"scope_type" json:"scope_type"`
+	// ScopeValue 预算范围取值，如业务ID、账号ID、云厂商、运营产品ID
+	ScopeValue string `db:"scope_value" validate:"lte=64" json:"scope_value"`
+	// Period 预算周期
+	Period enumor.BudgetPeriod `db:"period" json:"period"`
+	// Amount 预算金额
+	Amount *types.Decimal `db:"amount" json:"amount"`
+	// Currency 预算币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// ActualThresholds 实际费用达到预算金额的百分比阈值，达到时发送通知
+	ActualThresholds types.Int64Array `db:"actual_thresholds" json:"actual_thresholds"`
+	// ForecastThresholds 预测费用达到预算金额的百分比阈值，达到时发送通知
+	ForecastThresholds types.Int64Array `db:"forecast_thresholds" json:"forecast_thresholds"`
+	// Receivers 通知接收人
+	Receivers types.StringArray `db:"receivers" json:"receivers"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"lte=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"lte=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回预算表名
+func (b *AccountBillBudget) TableName() table.Name {
+	return table.AccountBillBudgetTable
+}
+
+// InsertValidate validate budget on insert
+func (b *AccountBillBudget) InsertValidate() error {
+	if len(b.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(b.Name) == 0 {
+		return errors.New("name is required")
+	}
+	if err := b.ScopeType.Validate(); err != nil {
+		return err
+	}
+	if len(b.ScopeValue) == 0 {
+		return errors.New("scope_value is required")
+	}
+	if err := b.Period.Validate(); err != nil {
+		return err
+	}
+	if !cvt.PtrToVal(b.Amount).IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	if len(b.Currency) == 0 {
+		return errors.New("currency is required")
+	}
+	if len(b.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+	if len(b.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	return validator.Validate.Struct(b)
+}
+
+// UpdateValidate validate budget on update
+func (b *AccountBillBudget) UpdateValidate() error {
+	if len(b.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(b.ScopeType) != 0 {
+		if err := b.ScopeType.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(b.Period) != 0 {
+		if err := b.Period.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.Amount != nil && !b.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	if len(b.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	if len(b.Creator) != 0 {
+		return errors.New("creator is not allowed")
+	}
+	return validator.Validate.Struct(b)
+}
+
+// AccountBillBudgetRecordColumns defines account_bill_budget_record's columns.
+var AccountBillBudgetRecordColumns = utils.MergeColumns(nil, AccountBillBudgetRecordColumnDescriptor)
+
+// AccountBillBudgetRecordColumnDescriptor is account_bill_budget_record's column descriptors.
+var AccountBillBudgetRecordColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "budget_id", NamedC: "budget_id", Type: enumor.String},
+	{Column: "period_key", NamedC: "period_key", Type: enumor.String},
+	{Column: "bill_year", NamedC: "bill_year", Type: enumor.Numeric},
+	{Column: "bill_month", NamedC: "bill_month", Type: enumor.Numeric},
+	{Column: "bill_day", NamedC: "bill_day", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "amount", NamedC: "amount", Type: enumor.Numeric},
+	{Column: "actual_cost", NamedC: "actual_cost", Type: enumor.Numeric},
+	{Column: "forecast_cost", NamedC: "forecast_cost", Type: enumor.Numeric},
+	{Column: "actual_alerted", NamedC: "actual_alerted", Type: enumor.Json},
+	{Column: "forecast_alerted", NamedC: "forecast_alerted", Type: enumor.Json},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillBudgetRecord 预算执行记录表，每个预算每天一条，记录预算与实际、预测费用的对比
+type AccountBillBudgetRecord struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// BudgetID 预算ID
+	BudgetID string `db:"budget_id" validate:"lte=64" json:"budget_id"`
+	// PeriodKey 预算周期标识，月度为 2024-01，季度为 2024-Q1
+	PeriodKey string `db:"period_key" validate:"lte=16" json:"period_key"`
+	// BillYear 评估日期所在年份
+	BillYear int `db:"bill_year" json:"bill_year"`
+	// BillMonth 评估日期所在月份
+	BillMonth int `db:"bill_month" json:"bill_month"`
+	// BillDay 评估日期
+	BillDay int `db:"bill_day" json:"bill_day"`
+	// Currency 预算币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// Amount 评估时的预算金额
+	Amount *types.Decimal `db:"amount" json:"amount"`
+	// ActualCost 周期内的实际费用
+	ActualCost *types.Decimal `db:"actual_cost" json:"actual_cost"`
+	// ForecastCost 按周期内日均费用预测的周期总费用
+	ForecastCost *types.Decimal `db:"forecast_cost" json:"forecast_cost"`
+	// ActualAlerted 周期内已通知的实际费用阈值
+	ActualAlerted types.Int64Array `db:"actual_alerted" json:"actual_alerted"`
+	// ForecastAlerted 周期内已通知的预测费用阈值
+	ForecastAlerted types.Int64Array `db:"forecast_alerted" json:"forecast_alerted"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"lte=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"lte=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回预算执行记录表名
+func (r *AccountBillBudgetRecord) TableName() table.Name {
+	return table.AccountBillBudgetRecordTable
+}
+
+// InsertValidate validate budget record on insert
+func (r *AccountBillBudgetRecord) InsertValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.BudgetID) == 0 {
+		return errors.New("budget_id is required")
+	}
+	if len(r.PeriodKey) == 0 {
+		return errors.New("period_key is required")
+	}
+	if r.BillYear == 0 || r.BillMonth == 0 || r.BillDay == 0 {
+		return errors.New("bill_year, bill_month and bill_day are required")
+	}
+	if r.Amount == nil || r.ActualCost == nil || r.ForecastCost == nil {
+		return errors.New("amount, actual_cost and forecast_cost are required")
+	}
+	if len(r.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+	if len(r.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	return validator.Validate.Struct(r)
+}
+
+// UpdateValidate validate budget record on update
+func (r *AccountBillBudgetRecord) UpdateValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	if len(r.Creator) != 0 {
+		return errors.New("creator is not allowed")
+	}
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,197 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/tools/json"
+)
+
+// AccountBillCostAnomalyColumns defines account_bill_cost_anomaly's columns.
+var AccountBillCostAnomalyColumns = utils.MergeColumns(nil, AccountBillCostAnomalyColumnDescriptor)
+
+// AccountBillCostAnomalyColumnDescriptor is account_bill_cost_anomaly's column descriptors.
+var AccountBillCostAnomalyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "root_account_id", NamedC: "root_account_id", Type: enumor.String},
+	{Column: "main_account_id", NamedC: "main_account_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "product_id", NamedC: "product_id", Type: enumor.Numeric},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "bill_year", NamedC: "bill_year", Type: enumor.Numeric},
+	{Column: "bill_month", NamedC: "bill_month", Type: enumor.Numeric},
+	{Column: "bill_day", NamedC: "bill_day", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "cost", NamedC: "cost", Type: enumor.Numeric},
+	{Column: "baseline", NamedC: "baseline", Type: enumor.Numeric},
+	{Column: "deviation", NamedC: "deviation", Type: enumor.Numeric},
+	{Column: "score", NamedC: "score", Type: enumor.Numeric},
+	{Column: "severity", NamedC: "severity", Type: enumor.String},
+	{Column: "contributors", NamedC: "contributors", Type: enumor.Json},
+	{Column: "notified", NamedC: "notified", Type: enumor.Boolean},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillCostAnomaly 二级账号每日费用异常表，每个二级账号每天最多一条
+type AccountBillCostAnomaly struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// RootAccountID 一级账号ID
+	RootAccountID string `db:"root_account_id" json:"root_account_id"`
+	// MainAccountID 账号ID
+	MainAccountID string `db:"main_account_id" json:"main_account_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// ProductID 运营产品ID
+	ProductID int64 `db:"product_id" json:"product_id"`
+	// BkBizID 业务ID
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// BillYear 账单年份
+	BillYear int `db:"bill_year" json:"bill_year"`
+	// BillMonth 账单月份
+	BillMonth int `db:"bill_month" json:"bill_month"`
+	// BillDay 账单日期
+	BillDay int `db:"bill_day" json:"bill_day"`
+	// Currency 币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// Cost 当天费用
+	Cost *types.Decimal `db:"cost" json:"cost"`
+	// Baseline 滚动窗口内每日费用的中位数
+	Baseline *types.Decimal `db:"baseline" json:"baseline"`
+	// Deviation 当天费用与基线的差值
+	Deviation *types.Decimal `db:"deviation" json:"deviation"`
+	// Score 基于中位数绝对偏差的稳健 z 分数
+	Score *types.Decimal `db:"score" json:"score"`
+	// Severity 严重程度
+	Severity enumor.CostAnomalySeverity `db:"severity" json:"severity"`
+	// Contributors 费用增长的云产品
+	Contributors CostAnomalyContributors `db:"contributors" json:"contributors"`
+	// Notified 是否已通知
+	Notified *bool `db:"notified" json:"notified"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"lte=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"lte=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回费用异常表名
+func (a *AccountBillCostAnomaly) TableName() table.Name {
+	return table.AccountBillCostAnomalyTable
+}
+
+// InsertValidate validate cost anomaly on insert
+func (a *AccountBillCostAnomaly) InsertValidate() error {
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(a.MainAccountID) == 0 {
+		return errors.New("main_account_id is required")
+	}
+	if len(a.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+	if a.BillYear == 0 || a.BillMonth == 0 || a.BillDay == 0 {
+		return errors.New("bill_year, bill_month and bill_day are required")
+	}
+	if a.Cost == nil || a.Baseline == nil || a.Deviation == nil || a.Score == nil {
+		return errors.New("cost, baseline, deviation and score are required")
+	}
+	if err := a.Severity.Validate(); err != nil {
+		return err
+	}
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	return validator.Validate.Struct(a)
+}
+
+// UpdateValidate validate cost anomaly on update
+func (a *AccountBillCostAnomaly) UpdateValidate() error {
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(a.Severity) != 0 {
+		if err := a.Severity.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	if len(a.Creator) != 0 {
+		return errors.New("creator is not allowed")
+	}
+	return validator.Validate.Struct(a)
+}
+
+// CostAnomalyContributors define cost anomaly contributors.
+type CostAnomalyContributors []billcore.CostAnomalyContributor
+
+// Scan is used to decode raw message which is read from db into CostAnomalyContributors.
+func (c *CostAnomalyContributors) Scan(raw interface{}) error {
+	if c == nil || raw == nil {
+		return nil
+	}
+
+	switch v := raw.(type) {
+	case []byte:
+		if err := json.Unmarshal(v, &c); err != nil {
+			return fmt.Errorf("decode into cost anomaly contributors failed, err: %v", err)
+		}
+		return nil
+
+	case string:
+		if err := json.Unmarshal([]byte(v), &c); err != nil {
+			return fmt.Errorf("decode into cost anomaly contributors failed, err: %v", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported cost anomaly contributors raw type: %T", v)
+	}
+}
+
+// Value encode the CostAnomalyContributors to a json raw, so that it can be stored to db with json raw.
+func (c CostAnomalyContributors) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	return json.Marshal(c)
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DiskSnapshotColumns defines all the disk snapshot table's columns.
+var DiskSnapshotColumns = utils.MergeColumns(nil, DiskSnapshotColumnDescriptor)
+
+// DiskSnapshotColumnDescriptor is disk snapshot's column descriptors.
+var DiskSnapshotColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "zone", NamedC: "zone", Type: enumor.String},
+	{Column: "disk_id", NamedC: "disk_id", Type: enumor.String},
+	{Column: "cloud_disk_id", NamedC: "cloud_disk_id", Type: enumor.String},
+	{Column: "disk_size", NamedC: "disk_size", Type: enumor.Numeric},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "encrypted", NamedC: "encrypted", Type: enumor.Boolean},
+	{Column: "policy_id", NamedC: "policy_id", Type: enumor.String},
+	{Column: "cloud_created_at", NamedC: "cloud_created_at", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "extension", NamedC: "extension", Type: enumor.Json},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DiskSnapshotTable define disk snapshot table.
+type DiskSnapshotTable struct {
+	ID        string        `db:"id" validate:"lte=64" json:"id"`
+	Vendor    enumor.Vendor `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID string        `db:"account_id" validate:"lte=64" json:"account_id"`
+	CloudID   string        `db:"cloud_id" validate:"lte=255" json:"cloud_id"`
+	Name      string        `db:"name" validate:"lte=255" json:"name"`
+	BkBizID   int64         `db:"bk_biz_id" json:"bk_biz_id"`
+	Region    string        `db:"region" validate:"lte=255" json:"region"`
+	Zone      string        `db:"zone" validate:"lte=255" json:"zone"`
+	// DiskID 源云盘在hcm中的ID，云盘已删除或未同步时为空
+	DiskID      string `db:"disk_id" validate:"lte=64" json:"disk_id"`
+	CloudDiskID string `db:"cloud_disk_id" validate:"lte=255" json:"cloud_disk_id"`
+	DiskSize    uint64 `db:"disk_size" json:"disk_size"`
+	Status      string `db:"status" validate:"lte=64" json:"status"`
+	Encrypted   *bool  `db:"encrypted" json:"encrypted"`
+	// PolicyID 创建该快照的快照策略ID
+	PolicyID       string          `db:"policy_id" validate:"lte=64" json:"policy_id"`
+	CloudCreatedAt string          `db:"cloud_created_at" validate:"lte=64" json:"cloud_created_at"`
+	Memo           *string         `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Extension      types.JsonField `db:"extension" json:"extension"`
+	Creator        string          `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser        string          `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt      types.Time      `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt      types.Time      `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return disk snapshot table name.
+func (t DiskSnapshotTable) TableName() table.Name {
+	return table.DiskSnapshotTable
+}
+
+// InsertValidate disk snapshot table when insert.
+func (t DiskSnapshotTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.CloudID) == 0 {
+		return errors.New("cloud_id is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate disk snapshot table when update.
+func (t DiskSnapshotTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 {
+		return errors.New("vendor can not update")
+	}
+
+	if len(t.AccountID) != 0 {
+		return errors.New("account_id can not update")
+	}
+
+	if len(t.CloudID) != 0 {
+		return errors.New("cloud_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DiskSnapshotPolicyColumns defines all the disk snapshot policy table's columns.
+var DiskSnapshotPolicyColumns = utils.MergeColumns(nil, DiskSnapshotPolicyColumnDescriptor)
+
+// DiskSnapshotPolicyColumnDescriptor is disk snapshot policy's column descriptors.
+var DiskSnapshotPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "scope_type", NamedC: "scope_type", Type: enumor.String},
+	{Column: "disk_ids", NamedC: "disk_ids", Type: enumor.Json},
+	{Column: "interval_hours", NamedC: "interval_hours", Type: enumor.Numeric},
+	{Column: "retention_count", NamedC: "retention_count", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "last_executed_at", NamedC: "last_executed_at", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DiskSnapshotPolicyTable define disk snapshot policy table, the policy creates snapshots for
+// disks in its scope periodically and keeps at most retention_count policy snapshots per disk.
+type DiskSnapshotPolicyTable struct {
+	ID      string `db:"id" validate:"lte=64" json:"id"`
+	Name    string `db:"name" validate:"lte=255" json:"name"`
+	BkBizID int64  `db:"bk_biz_id" json:"bk_biz_id"`
+	// ScopeType 策略范围类型，disk：指定云盘，biz：业务下的全部云盘
+	ScopeType string            `db:"scope_type" validate:"lte=32" json:"scope_type"`
+	DiskIDs   types.StringArray `db:"disk_ids" json:"disk_ids"`
+	// IntervalHours 创建快照的间隔，单位小时
+	IntervalHours uint64 `db:"interval_hours" json:"interval_hours"`
+	// RetentionCount 每块云盘保留的策略快照数量
+	RetentionCount uint64 `db:"retention_count" json:"retention_count"`
+	Enabled        *bool  `db:"enabled" json:"enabled"`
+	// LastExecutedAt 上次执行时间，RFC3339格式
+	LastExecutedAt string     `db:"last_executed_at" validate:"lte=32" json:"last_executed_at"`
+	Memo           *string    `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator        string     `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser        string     `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt      types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt      types.Time `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return disk snapshot policy table name.
+func (t DiskSnapshotPolicyTable) TableName() table.Name {
+	return table.DiskSnapshotPolicyTable
+}
+
+// InsertValidate disk snapshot policy table when insert.
+func (t DiskSnapshotPolicyTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.ScopeType) == 0 {
+		return errors.New("scope_type is required")
+	}
+
+	if t.IntervalHours == 0 {
+		return errors.New("interval_hours should be > 0")
+	}
+
+	if t.RetentionCount == 0 {
+		return errors.New("retention_count should be > 0")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate disk snapshot policy table when update.
+func (t DiskSnapshotPolicyTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
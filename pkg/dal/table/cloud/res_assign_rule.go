@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ResAssignRuleColumns defines all the res assign rule table's columns.
+var ResAssignRuleColumns = utils.MergeColumns(nil, ResAssignRuleColumnDescriptor)
+
+// ResAssignRuleColumnDescriptor is res assign rule's column descriptors.
+var ResAssignRuleColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "res_types", NamedC: "res_types", Type: enumor.Json},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "conditions", NamedC: "conditions", Type: enumor.Json},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "bk_cloud_id", NamedC: "bk_cloud_id", Type: enumor.Numeric},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ResAssignRuleTable define res assign rule table, the rule assigns matched unassigned resources to business.
+type ResAssignRuleTable struct {
+	ID   string `db:"id" validate:"lte=64" json:"id"`
+	Name string `db:"name" validate:"lte=255" json:"name"`
+	// ResTypes 规则适用的资源类型
+	ResTypes types.StringArray `db:"res_types" json:"res_types"`
+	// Priority 优先级，数值越小优先级越高
+	Priority *int64 `db:"priority" json:"priority"`
+	Enabled  *bool  `db:"enabled" json:"enabled"`
+	// Conditions 匹配条件
+	Conditions types.JsonField `db:"conditions" json:"conditions"`
+	BkBizID    int64           `db:"bk_biz_id" json:"bk_biz_id"`
+	// BkCloudID 主机未绑定管控区域时，其VPC绑定的管控区域
+	BkCloudID *int64     `db:"bk_cloud_id" json:"bk_cloud_id"`
+	Memo      *string    `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator   string     `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser   string     `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt types.Time `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return res assign rule table name.
+func (t ResAssignRuleTable) TableName() table.Name {
+	return table.ResAssignRuleTable
+}
+
+// InsertValidate res assign rule table when insert.
+func (t ResAssignRuleTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.ResTypes) == 0 {
+		return errors.New("res_types is required")
+	}
+
+	if t.Priority == nil {
+		return errors.New("priority is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Conditions) == 0 {
+		return errors.New("conditions is required")
+	}
+
+	if t.BkBizID <= 0 {
+		return errors.New("bk_biz_id should be > 0")
+	}
+
+	if t.BkCloudID == nil {
+		return errors.New("bk_cloud_id is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate res assign rule table when update.
+func (t ResAssignRuleTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
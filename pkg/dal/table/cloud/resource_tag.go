@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ResourceTagColumns defines all the resource tag table's columns.
+var ResourceTagColumns = utils.MergeColumns(nil, ResourceTagColumnDescriptor)
+
+// ResourceTagColumnDescriptor is resource tag's column descriptors.
+var ResourceTagColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "key", NamedC: "key", Type: enumor.String},
+	{Column: "value", NamedC: "value", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ResourceTagTable define resource tag table, all kinds of cloud resource's tags are stored here,
+// a resource is identified by res_type and res_id.
+type ResourceTagTable struct {
+	ID         string                   `db:"id" validate:"lte=64" json:"id"`
+	Vendor     enumor.Vendor            `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID  string                   `db:"account_id" validate:"lte=64" json:"account_id"`
+	ResType    enumor.CloudResourceType `db:"res_type" validate:"lte=64" json:"res_type"`
+	ResID      string                   `db:"res_id" validate:"lte=64" json:"res_id"`
+	CloudResID string                   `db:"cloud_res_id" validate:"lte=255" json:"cloud_res_id"`
+	Key        string                   `db:"key" validate:"lte=255" json:"key"`
+	Value      string                   `db:"value" validate:"lte=255" json:"value"`
+	Creator    string                   `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser    string                   `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt  types.Time               `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt  types.Time               `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return resource tag table name.
+func (t ResourceTagTable) TableName() table.Name {
+	return table.ResourceTagTable
+}
+
+// InsertValidate resource tag table when insert.
+func (t ResourceTagTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if len(t.Key) == 0 {
+		return errors.New("key is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate resource tag table when update.
+func (t ResourceTagTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
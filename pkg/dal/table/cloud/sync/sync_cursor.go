@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablessync
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SyncCursorColumns defines all the sync_cursor table's columns.
+var SyncCursorColumns = utils.MergeColumns(nil, SyncCursorColumnDescriptor)
+
+// SyncCursorColumnDescriptor is sync_cursor's column descriptors.
+var SyncCursorColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "type", NamedC: "type", Type: enumor.String},
+	{Column: "scope", NamedC: "scope", Type: enumor.String},
+	{Column: "cursor_value", NamedC: "cursor_value", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SyncCursorTable define sync_cursor table, it records where a periodic sync has processed to.
+type SyncCursorTable struct {
+	ID   string                `db:"id" json:"id" validate:"lte=64"`
+	Type enumor.SyncCursorType `db:"type" json:"type" validate:"lte=64"`
+	// Scope 游标作用范围，同一类型下唯一
+	Scope string `db:"scope" json:"scope" validate:"lte=255"`
+	// CursorValue 已处理到的位置
+	CursorValue string     `db:"cursor_value" json:"cursor_value" validate:"lte=64"`
+	Creator     string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser     string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt   types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt   types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return sync_cursor table name.
+func (c SyncCursorTable) TableName() table.Name {
+	return table.SyncCursorTable
+}
+
+// InsertValidate sync_cursor table when insert.
+func (c SyncCursorTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	if len(c.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := c.Type.Validate(); err != nil {
+		return err
+	}
+
+	if len(c.CursorValue) == 0 {
+		return errors.New("cursor_value is required")
+	}
+
+	if len(c.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(c.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate sync_cursor table when update.
+func (c SyncCursorTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	if len(c.Type) != 0 {
+		return errors.New("type can not update")
+	}
+
+	if len(c.Scope) != 0 {
+		return errors.New("scope can not update")
+	}
+
+	if len(c.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
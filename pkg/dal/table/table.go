@@ -38,6 +38,16 @@ const (
 	IDGenerator Name = "id_generator"
 	// AuditTable is audit table's name
 	AuditTable Name = "audit"
+	// AuditChainTable is audit hash chain head table's name.
+	AuditChainTable Name = "audit_chain"
+	// AuditCheckpointTable is audit hash chain signed checkpoint table's name.
+	AuditCheckpointTable Name = "audit_checkpoint"
+	// AuditOutboxTable is audit event outbox table's name.
+	AuditOutboxTable Name = "audit_outbox"
+	// AuditSinkCursorTable is audit event sink delivery cursor table's name.
+	AuditSinkCursorTable Name = "audit_sink_cursor"
+	// AuditArchiveTable is audit archive file manifest table's name.
+	AuditArchiveTable Name = "audit_archive"
 	// RecycleRecordTable is recycle record table name
 	RecycleRecordTable Name = "recycle_record"
 	// AccountTable is account table's name.
@@ -50,8 +60,14 @@ const (
 	SecurityGroupTable Name = "security_group"
 	// VpcSecurityGroupRelTable is vpc and security group table's name.
 	VpcSecurityGroupRelTable Name = "vpc_security_group_rel"
-	// SecurityGroupTagTable is security group tag table's name.
-	SecurityGroupTagTable Name = "security_group_tag"
+	// ResourceTagTable is cloud resource tag table's name.
+	ResourceTagTable Name = "resource_tag"
+	// ResAssignRuleTable is resource auto assign rule table's name.
+	ResAssignRuleTable Name = "res_assign_rule"
+	// DiskSnapshotTable is disk snapshot table's name.
+	DiskSnapshotTable Name = "disk_snapshot"
+	// DiskSnapshotPolicyTable is disk snapshot policy table's name.
+	DiskSnapshotPolicyTable Name = "disk_snapshot_policy"
 	// SecurityGroupSubnetTable is security group subnet table's name.
 	SecurityGroupSubnetTable Name = "security_group_subnet_rel"
 	// SecurityGroupCvmTable is security group cvm table's name.
@@ -115,11 +131,17 @@ const (
 
 	// AccountSyncDetailTable is account_sync_detail table's name.
 	AccountSyncDetailTable Name = "account_sync_detail"
+	// SyncCursorTable is sync_cursor table's name.
+	SyncCursorTable Name = "sync_cursor"
 
 	// ApplicationTable is application table name
 	ApplicationTable Name = "application"
 	// ApprovalProcessTable is approval process table name
 	ApprovalProcessTable Name = "approval_process"
+	// ApprovalChainTable is native approval chain table name
+	ApprovalChainTable Name = "approval_chain"
+	// ApprovalTicketTable is native approval ticket table name
+	ApprovalTicketTable Name = "approval_ticket"
 	// NetworkInterfaceTable is network interface table's name.
 	NetworkInterfaceTable Name = "network_interface"
 	// NetworkInterfaceCvmRelTable is network interface and cvm rel table's name.
@@ -138,6 +160,8 @@ const (
 	AsyncFlowTable Name = "async_flow"
 	// AsyncFlowTaskTable is async flow task table's name.
 	AsyncFlowTaskTable Name = "async_flow_task"
+	// AsyncScheduledFlowTable is async scheduled flow table's name.
+	AsyncScheduledFlowTable Name = "async_scheduled_flow"
 
 	// CloudSelectionSchemeTable is cloud selection scheme table's name.
 	CloudSelectionSchemeTable Name = "cloud_selection_scheme"
@@ -198,6 +222,12 @@ const (
 	AccountBillExchangeRateTable = "account_bill_exchange_rate"
 	// AccountBillSyncRecordTable 账单同步记录
 	AccountBillSyncRecordTable = "account_bill_sync_record"
+	// AccountBillBudgetTable 云账单预算
+	AccountBillBudgetTable = "account_bill_budget"
+	// AccountBillBudgetRecordTable 云账单预算执行记录
+	AccountBillBudgetRecordTable = "account_bill_budget_record"
+	// AccountBillCostAnomalyTable 二级账号每日费用异常
+	AccountBillCostAnomalyTable = "account_bill_cost_anomaly"
 	// TaskDetailTable 任务详情表
 	TaskDetailTable = "task_detail"
 	// TaskManagementTable 任务管理表
@@ -221,6 +251,11 @@ func (n Name) Validate() error {
 // TableMap table map config
 var TableMap = map[Name]struct{}{
 	AuditTable:                   {},
+	AuditChainTable:              {},
+	AuditCheckpointTable:         {},
+	AuditOutboxTable:             {},
+	AuditSinkCursorTable:         {},
+	AuditArchiveTable:            {},
 	AccountTable:                 {},
 	SubAccountTable:              {},
 	AccountBizRelTable:           {},
@@ -229,7 +264,10 @@ var TableMap = map[Name]struct{}{
 	IDGenerator:                  {},
 	SecurityGroupTable:           {},
 	VpcSecurityGroupRelTable:     {},
-	SecurityGroupTagTable:        {},
+	ResourceTagTable:             {},
+	ResAssignRuleTable:           {},
+	DiskSnapshotTable:            {},
+	DiskSnapshotPolicyTable:      {},
 	SecurityGroupSubnetTable:     {},
 	SGSecurityGroupRuleTable:     {},
 	TCloudSecurityGroupRuleTable: {},
@@ -254,6 +292,8 @@ var TableMap = map[Name]struct{}{
 	CvmTable:                     {},
 	ApplicationTable:             {},
 	ApprovalProcessTable:         {},
+	ApprovalChainTable:           {},
+	ApprovalTicketTable:          {},
 	NetworkInterfaceTable:        {},
 	NetworkInterfaceCvmRelTable:  {},
 	RecycleRecordTable:           {},
@@ -265,6 +305,7 @@ var TableMap = map[Name]struct{}{
 	AccountBillConfigTable:       {},
 	UserCollectionTable:          {},
 	AccountSyncDetailTable:       {},
+	SyncCursorTable:              {},
 	CloudSelectionSchemeTable:    {},
 	CloudSelectionBizTypeTable:   {},
 	CloudSelectionIdcTable:       {},
@@ -273,8 +314,9 @@ var TableMap = map[Name]struct{}{
 	// TODO: 临时方案
 	RecycleRecordTableTaskID: {},
 
-	AsyncFlowTable:     {},
-	AsyncFlowTaskTable: {},
+	AsyncFlowTable:          {},
+	AsyncFlowTaskTable:      {},
+	AsyncScheduledFlowTable: {},
 
 	ArgumentTemplateTable: {},
 
@@ -289,6 +331,9 @@ var TableMap = map[Name]struct{}{
 	RootAccountBillConfigTable:      {},
 	AccountBillExchangeRateTable:    {},
 	AccountBillSyncRecordTable:      {},
+	AccountBillBudgetTable:          {},
+	AccountBillBudgetRecordTable:    {},
+	AccountBillCostAnomalyTable:     {},
 	LoadBalancerTable:               {},
 	SecurityGroupCommonRelTable:     {},
 	LoadBalancerListenerTable:       {},
@@ -31,6 +31,7 @@ import (
 	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/rand"
 	"hcm/pkg/tools/uuid"
+	"hcm/pkg/tracing"
 )
 
 // New initial a kit with rid and context.
@@ -155,15 +156,18 @@ func (kt *Kit) Validate() error {
 	return nil
 }
 
-// Header generate header by kit
+// Header generate header by kit, trace context in kit's context is also injected into the header.
 func (kt *Kit) Header() http.Header {
-	return http.Header{
+	header := http.Header{
 		constant.UserKey:          []string{kt.User},
 		constant.RidKey:           []string{kt.Rid},
 		constant.AppCodeKey:       []string{kt.AppCode},
 		constant.TenantIDKey:      []string{kt.TenantID},
 		constant.RequestSourceKey: []string{string(kt.RequestSource)},
 	}
+	tracing.Inject(kt.Ctx, header)
+
+	return header
 }
 
 // FromHeader http request header to context kit and validate.
@@ -173,7 +177,7 @@ func FromHeader(ctx context.Context, header http.Header) (*Kit, error) {
 	}
 
 	kt := &Kit{
-		Ctx:           ctx,
+		Ctx:           tracing.Extract(ctx, header),
 		User:          header.Get(constant.UserKey),
 		Rid:           header.Get(constant.RidKey),
 		AppCode:       header.Get(constant.AppCodeKey),
@@ -34,9 +34,12 @@ import (
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tracing"
 
 	"github.com/emicklei/go-restful/v3"
 	prm "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var once sync.Once
@@ -137,9 +140,21 @@ func (r *Handler) wrapperAction(action *action) func(req *restful.Request, resp
 			return
 		}
 
+		// 基于上游的链路上下文创建服务端 span，并在响应头中返回 trace id，用于关联 rid 与 trace
+		var spanErr error
+		ctx, span := tracing.Start(kt.Ctx, action.Alias, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(tracing.RidKey.String(kt.Rid), attribute.String("http.method", action.Verb),
+				attribute.String("http.route", action.Path)))
+		kt.Ctx = ctx
+		defer func() { tracing.End(span, spanErr) }()
+		if traceID := tracing.TraceID(ctx); len(traceID) != 0 {
+			resp.Header().Set(constant.TraceIDKey, traceID)
+		}
+
 		defer func() {
 			if fatalErr := recover(); fatalErr != nil {
-				cts.respError(fmt.Errorf("panic err: %v", fatalErr))
+				spanErr = fmt.Errorf("panic err: %v", fatalErr)
+				cts.respError(spanErr)
 				logs.Errorf("[hcm server panic], err: %v, rid: %s, debug strace: %s", fatalErr, kt.Rid, debug.Stack())
 				logs.CloseLogs()
 			}
@@ -154,6 +169,7 @@ func (r *Handler) wrapperAction(action *action) func(req *restful.Request, resp
 			byt, err := ioutil.ReadAll(req.Request.Body)
 			if err != nil {
 				logs.Errorf("restful request %s peek failed, err: %v, rid: %s", action.Alias, err, cts.Kit.Rid)
+				spanErr = err
 
 				cts.WithStatusCode(http.StatusBadRequest)
 				cts.respError(errf.NewFromErr(errf.InvalidParameter, err))
@@ -174,6 +190,7 @@ func (r *Handler) wrapperAction(action *action) func(req *restful.Request, resp
 		start := time.Now()
 		reply, err := action.Handler(cts)
 		if err != nil {
+			spanErr = err
 			if logs.V(2) {
 				logs.Errorf("do restful request %s failed, err: %v, rid: %s", action.Alias, err, cts.Kit.Rid)
 			}
@@ -40,8 +40,11 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest/client"
+	"hcm/pkg/tracing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // VerbType http request verb type
@@ -362,17 +365,27 @@ func (r *Request) Do() *Result {
 		return result
 	}
 
+	// 创建客户端 span，并将链路上下文写入请求头，传递给下游服务
+	ctx, span := tracing.Start(r.ctx, string(r.verb)+" "+r.subPath, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(tracing.RidKey.String(rid), attribute.String("http.method", string(r.verb))))
+	if r.headers == nil {
+		r.headers = make(http.Header)
+	}
+	tracing.Inject(ctx, r.headers)
+
 	maxRetryCycle := 3
 	for try := 0; try < maxRetryCycle; try++ {
 		for index, host := range hosts {
 			result, isComplete := r.doWithHost(client, host, try+index, rid)
 			if isComplete {
+				tracing.End(span, result.Err)
 				return result
 			}
 		}
 	}
 
 	result.Err = errors.New("unexpected error")
+	tracing.End(span, result.Err)
 	return result
 }
 
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+)
+
+// VerifyAuditChainFunc verify audit hash chains function.
+type VerifyAuditChainFunc func(kt *kit.Kit, req *protoaudit.VerifyChainReq) (*protoaudit.VerifyChainResult, error)
+
+// WithVerifyAuditChain init and returns the verify audit hash chain command.
+func WithVerifyAuditChain(verify VerifyAuditChainFunc) Cmd {
+	cmd := &defaultCmd{
+		cmd: &Command{
+			Name:  "verify-audit-chain",
+			Usage: "verify audit hash chains in the date range, report altered or removed audit records",
+			Parameters: []Parameter{{
+				Name:  "start",
+				Usage: "defines the start date, eg. \"2024-12-01\"",
+				Value: new(string),
+			}, {
+				Name:  "end",
+				Usage: "defines the end date which is included, eg. \"2024-12-18\"",
+				Value: new(string),
+			}},
+			FromURL: true,
+			Run: func(kt *kit.Kit, params map[string]interface{}) (interface{}, error) {
+				start, exists := params["start"]
+				if !exists {
+					return nil, errf.New(errf.InvalidParameter, "start is not set")
+				}
+
+				end, exists := params["end"]
+				if !exists {
+					return nil, errf.New(errf.InvalidParameter, "end is not set")
+				}
+
+				req := &protoaudit.VerifyChainReq{Start: *start.(*string), End: *end.(*string)}
+				if err := req.Validate(); err != nil {
+					return nil, errf.NewFromErr(errf.InvalidParameter, err)
+				}
+
+				return verify(kt, req)
+			},
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// RewrapSecretFunc rewrap account secrets function.
+type RewrapSecretFunc func(kt *kit.Kit) (*protocloud.RewrapSecretResult, error)
+
+// WithRewrapSecret init and returns the rewrap account secret command.
+func WithRewrapSecret(rewrap RewrapSecretFunc) Cmd {
+	cmd := &defaultCmd{
+		cmd: &Command{
+			Name:  "rewrap-secret",
+			Usage: "rewrap encrypted account secrets, main account passwords and application contents with current " +
+				"kms key version after key rotation",
+			Run: func(kt *kit.Kit, params map[string]interface{}) (interface{}, error) {
+				result, err := rewrap(kt)
+				if err != nil {
+					logs.Errorf("rewrap account secret failed, err: %v, rid: %s", err, kt.Rid)
+					return nil, err
+				}
+
+				return result, nil
+			},
+		},
+	}
+
+	return cmd
+}
@@ -474,6 +474,10 @@ func (ar AtomRule) Validate(opt *ExprOption) error {
 		return fmt.Errorf("operator %s field only support id field", ar.Op)
 	}
 
+	if isResTagOp(ar.Op) && !strings.HasPrefix(ar.Field, ResTagFieldPrefix) {
+		return fmt.Errorf("operator %s field should be prefixed with %s", ar.Op, ResTagFieldPrefix)
+	}
+
 	// validate the operator's value
 	if err := ar.Op.Operator().ValidateValue(ar.Value, opt); err != nil {
 		return fmt.Errorf("%s validate failed, %v", ar.Field, err)
@@ -565,6 +569,15 @@ func (ar AtomRule) RuleField() string {
 
 // SQLExprAndValue convert this atom rule to a mysql's sub query expression, and field's value.
 func (ar AtomRule) SQLExprAndValue(opt *SQLWhereOption) (string, map[string]interface{}, error) {
+	if tagOp, ok := ar.Op.Operator().(ResTagOperator); ok {
+		if opt == nil || len(opt.ResType) == 0 || len(opt.ResTable) == 0 {
+			return "", nil, fmt.Errorf("tag operator %s is not supported by the queried resource", ar.Op)
+		}
+
+		return tagOp.ResTagSQLExprAndValue(&ResTagOption{ResType: opt.ResType, Table: opt.ResTable}, ar.Field,
+			ar.Value)
+	}
+
 	expr, value, err := ar.Op.Operator().SQLExprAndValue(ar.Field, ar.Value)
 	if err != nil {
 		return "", nil, err
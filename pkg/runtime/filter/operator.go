@@ -65,6 +65,10 @@ func init() {
 	opFactory[JSONContainsPath.Factory()] = JSONContainsPathOp(JSONContainsPath)
 	opFactory[JSONNotContainsPath.Factory()] = JSONNotContainsPathOp(JSONNotContainsPath)
 	opFactory[JSONLength.Factory()] = JSONLengthOp(JSONLength)
+
+	opFactory[TagEqual.Factory()] = TagEqualOp(TagEqual)
+	opFactory[TagIn.Factory()] = TagInOp(TagIn)
+	opFactory[TagExists.Factory()] = TagExistsOp(TagExists)
 }
 
 const (
@@ -181,6 +185,8 @@ func (op OpType) Validate() error {
 
 	case IDGreaterThan:
 
+	case TagEqual, TagIn, TagExists:
+
 	default:
 		return fmt.Errorf("unsupported operator: %s", op)
 	}
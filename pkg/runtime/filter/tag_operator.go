@@ -0,0 +1,250 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/tools/assert"
+)
+
+// ResTagFieldPrefix is the prefix of tag rule's field, the rest of the field is the tag key,
+// e.g. "tags.env" matches resources with tag key "env".
+const ResTagFieldPrefix = "tags."
+
+// tag operators, resources are matched with their tags in resource_tag table by res_type and res_id.
+const (
+	// TagEqual matches resources that have the tag key with the given value.
+	TagEqual OpType = "tag_eq"
+	// TagIn matches resources that have the tag key with any of the given values.
+	TagIn OpType = "tag_in"
+	// TagExists matches resources that have (value is true) or not have (value is false) the tag key.
+	TagExists OpType = "tag_exists"
+)
+
+// ResTagOption is the queried resource info that tag operators used to generate the sub query.
+type ResTagOption struct {
+	// ResType is the cloud resource type of the queried table.
+	ResType enumor.CloudResourceType
+	// Table is the queried table name or alias, used to qualify the resource id column.
+	Table string
+}
+
+// ResTagOperator is the operator that filters resources by tags stored in the resource_tag table,
+// it needs the queried resource type to generate the sub query.
+type ResTagOperator interface {
+	Operator
+	// ResTagSQLExprAndValue generate the operator's SQL expression with the queried resource info.
+	ResTagSQLExprAndValue(opt *ResTagOption, field string, value interface{}) (string,
+		map[string]interface{}, error)
+}
+
+// isResTagOp returns whether the operator is a tag operator.
+func isResTagOp(op OpFactory) bool {
+	_, ok := op.Operator().(ResTagOperator)
+	return ok
+}
+
+// parseResTagKey parse tag key from the rule field.
+func parseResTagKey(field string) (string, error) {
+	if !strings.HasPrefix(field, ResTagFieldPrefix) {
+		return "", fmt.Errorf("tag operator's field should be prefixed with %s", ResTagFieldPrefix)
+	}
+
+	key := strings.TrimPrefix(field, ResTagFieldPrefix)
+	if len(key) == 0 {
+		return "", errors.New("tag key is empty")
+	}
+
+	return key, nil
+}
+
+// resTagSubQuery generate the sub query that selects the queried resource ids with the tag conditions.
+// the resource id column is qualified with the queried table, so that it is not ambiguous in join queries.
+func resTagSubQuery(opt *ResTagOption, key string, cond string, not bool) (string, map[string]interface{}) {
+	typePH := fieldPlaceholderName("tag_res_type")
+	keyPH := fieldPlaceholderName("tag_key")
+	values := map[string]interface{}{
+		typePH: string(opt.ResType),
+		keyPH:  key,
+	}
+
+	op := "IN"
+	if not {
+		op = "NOT IN"
+	}
+
+	sql := fmt.Sprintf("%s.id %s (SELECT rt.res_id FROM %s AS rt WHERE rt.res_type = %s%s AND rt.`key` = %s%s%s)",
+		opt.Table, op, table.ResourceTagTable, SqlPlaceholder, typePH, SqlPlaceholder, keyPH, cond)
+	return sql, values
+}
+
+// TagEqualOp is tag equal operator.
+type TagEqualOp OpType
+
+// Name is tag equal operator
+func (op TagEqualOp) Name() OpType {
+	return TagEqual
+}
+
+// ValidateValue validate tag equal's value
+func (op TagEqualOp) ValidateValue(v interface{}, opt *ExprOption) error {
+	if reflect.ValueOf(v).Kind() != reflect.String {
+		return errors.New("tag_eq operator's value should be a string")
+	}
+
+	return nil
+}
+
+// SQLExprAndValue tag operator can not generate sql expression without resource type.
+func (op TagEqualOp) SQLExprAndValue(_ string, _ interface{}) (string, map[string]interface{}, error) {
+	return "", nil, errors.New("tag_eq operator requires resource type, can not gen sql expression")
+}
+
+// ResTagSQLExprAndValue convert this operator's field and value to a sub query of resource_tag table.
+func (op TagEqualOp) ResTagSQLExprAndValue(opt *ResTagOption, field string, value interface{}) (
+	string, map[string]interface{}, error) {
+
+	key, err := parseResTagKey(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = op.ValidateValue(value, nil); err != nil {
+		return "", nil, err
+	}
+
+	valuePH := fieldPlaceholderName("tag_value")
+	sql, values := resTagSubQuery(opt, key, fmt.Sprintf(" AND rt.value = %s%s", SqlPlaceholder, valuePH), false)
+	values[valuePH] = value
+	return sql, values, nil
+}
+
+// TagInOp is tag in operator.
+type TagInOp OpType
+
+// Name is tag in operator
+func (op TagInOp) Name() OpType {
+	return TagIn
+}
+
+// ValidateValue validate tag in's value
+func (op TagInOp) ValidateValue(v interface{}, opt *ExprOption) error {
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Array:
+	case reflect.Slice:
+	default:
+		return errors.New("tag_in operator's value should be an array")
+	}
+
+	value := reflect.ValueOf(v)
+	length := value.Len()
+	if length == 0 {
+		return errors.New("invalid tag_in operator's value, at least have one element")
+	}
+
+	maxInV := DefaultMaxInLimit
+	if opt != nil && opt.MaxInLimit > 0 {
+		maxInV = opt.MaxInLimit
+	}
+
+	if length > int(maxInV) {
+		return fmt.Errorf("invalid tag_in operator's value, at most have %d elements", maxInV)
+	}
+
+	for i := 0; i < length; i++ {
+		if !assert.IsBasicValue(value.Index(i).Interface()) ||
+			reflect.ValueOf(value.Index(i).Interface()).Kind() != reflect.String {
+			return fmt.Errorf("invalid tag_in operator's value: %v, each element should be a string",
+				value.Index(i).Interface())
+		}
+	}
+
+	return nil
+}
+
+// SQLExprAndValue tag operator can not generate sql expression without resource type.
+func (op TagInOp) SQLExprAndValue(_ string, _ interface{}) (string, map[string]interface{}, error) {
+	return "", nil, errors.New("tag_in operator requires resource type, can not gen sql expression")
+}
+
+// ResTagSQLExprAndValue convert this operator's field and value to a sub query of resource_tag table.
+func (op TagInOp) ResTagSQLExprAndValue(opt *ResTagOption, field string, value interface{}) (
+	string, map[string]interface{}, error) {
+
+	key, err := parseResTagKey(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = op.ValidateValue(value, nil); err != nil {
+		return "", nil, err
+	}
+
+	valuePH := fieldPlaceholderName("tag_values")
+	sql, values := resTagSubQuery(opt, key, fmt.Sprintf(" AND rt.value IN (%s%s)", SqlPlaceholder, valuePH),
+		false)
+	values[valuePH] = value
+	return sql, values, nil
+}
+
+// TagExistsOp is tag exists operator.
+type TagExistsOp OpType
+
+// Name is tag exists operator
+func (op TagExistsOp) Name() OpType {
+	return TagExists
+}
+
+// ValidateValue validate tag exists's value
+func (op TagExistsOp) ValidateValue(v interface{}, opt *ExprOption) error {
+	if reflect.ValueOf(v).Kind() != reflect.Bool {
+		return errors.New("tag_exists operator's value should be a boolean")
+	}
+
+	return nil
+}
+
+// SQLExprAndValue tag operator can not generate sql expression without resource type.
+func (op TagExistsOp) SQLExprAndValue(_ string, _ interface{}) (string, map[string]interface{}, error) {
+	return "", nil, errors.New("tag_exists operator requires resource type, can not gen sql expression")
+}
+
+// ResTagSQLExprAndValue convert this operator's field and value to a sub query of resource_tag table.
+func (op TagExistsOp) ResTagSQLExprAndValue(opt *ResTagOption, field string, value interface{}) (
+	string, map[string]interface{}, error) {
+
+	key, err := parseResTagKey(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = op.ValidateValue(value, nil); err != nil {
+		return "", nil, err
+	}
+
+	sql, values := resTagSubQuery(opt, key, "", !reflect.ValueOf(value).Bool())
+	return sql, values, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestResTagSQLExpr(t *testing.T) {
+	expr := &Expression{
+		Op: And,
+		Rules: []RuleFactory{
+			&AtomRule{Field: "name", Op: Equal.Factory(), Value: "hcm"},
+			&AtomRule{Field: "tags.env", Op: TagEqual.Factory(), Value: "prod"},
+			&AtomRule{Field: "tags.owner", Op: TagExists.Factory(), Value: false},
+		},
+	}
+
+	opt := NewExprOption(RuleFields(map[string]enumor.ColumnType{
+		"name":                                  enumor.String,
+		ResTagFieldPrefix + WildcardPlaceholder: enumor.Json,
+	}))
+	if err := expr.Validate(opt); err != nil {
+		t.Errorf("validate tag expression failed, err: %v", err)
+		return
+	}
+
+	if _, _, err := expr.SQLWhereExpr(&SQLWhereOption{Priority: Priority{"id"}}); err == nil {
+		t.Errorf("tag operator without resource type should fail")
+		return
+	}
+
+	where, values, err := expr.SQLWhereExpr(&SQLWhereOption{Priority: Priority{"id"},
+		ResType: enumor.CvmCloudResType, ResTable: "cvm"})
+	if err != nil {
+		t.Errorf("gen tag expression sql failed, err: %v", err)
+		return
+	}
+
+	inExpr := "cvm.id IN (SELECT rt.res_id FROM resource_tag AS rt WHERE rt.res_type = :tag_res_type_"
+	notInExpr := "cvm.id NOT IN (SELECT rt.res_id FROM resource_tag AS rt"
+	if !strings.Contains(where, inExpr) || !strings.Contains(where, notInExpr) {
+		t.Errorf("got wrong tag expression: %s", where)
+		return
+	}
+
+	// name, res_type, key, value of tag_eq and res_type, key of tag_exists.
+	if len(values) != 6 {
+		t.Errorf("got wrong tag expression values: %v", values)
+		return
+	}
+
+	invalid := &Expression{
+		Op:    And,
+		Rules: []RuleFactory{&AtomRule{Field: "name", Op: TagEqual.Factory(), Value: "prod"}},
+	}
+	if err = invalid.Validate(opt); err == nil {
+		t.Errorf("tag operator with non tag field should be invalid")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/tools/assert"
 
 	"github.com/tidwall/gjson"
@@ -68,6 +69,12 @@ type SQLWhereOption struct {
 	// field during query.
 	Priority      Priority
 	CrownedOption *CrownedOption
+	// ResType is the cloud resource type of the queried table, tag operators
+	// use it to match resources in resource_tag table.
+	ResType enumor.CloudResourceType
+	// ResTable is the queried table name or alias, tag operators use it to
+	// qualify the resource id column.
+	ResTable string
 }
 
 // Validate the options is valid or not
@@ -0,0 +1,238 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cron 提供标准5段式cron表达式解析及下次触发时间计算
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldBound cron 表达式每一段的取值范围
+type fieldBound struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBound = fieldBound{name: "minute", min: 0, max: 59}
+	hourBound   = fieldBound{name: "hour", min: 0, max: 23}
+	domBound    = fieldBound{name: "day of month", min: 1, max: 31}
+	monthBound  = fieldBound{name: "month", min: 1, max: 12}
+	dowBound    = fieldBound{name: "day of week", min: 0, max: 6}
+)
+
+// maxSearchYears 计算下次触发时间时最多向后查找的年数，避免 2月30日 这类永远无法触发的表达式死循环
+const maxSearchYears = 5
+
+// Schedule 解析后的cron表达式，按 分 时 日 月 周 的顺序，每一段记录允许的取值集合
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domStar/dowStar 日和周是否为*，二者都不为*时，满足任意一个即可触发（与标准cron行为一致）
+	domStar bool
+	dowStar bool
+}
+
+// Parse 解析标准5段式cron表达式，例如 "*/5 * * * *"、"0 2 * * 1-5"、"30 8 1,15 * *"。
+// 每一段支持 *、数字、范围(a-b)、步长(*/n、a-b/n)及逗号分隔的列表。周日可以使用0或7表示。
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, but got %d: %s", len(fields), expr)
+	}
+
+	var err error
+	s := new(Schedule)
+	if s.minute, err = parseField(fields[0], minuteBound); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBound); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBound); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBound); err != nil {
+		return nil, err
+	}
+
+	// 周日兼容7的写法
+	dow := fieldBound{name: dowBound.name, min: dowBound.min, max: 7}
+	if s.dow, err = parseField(fields[4], dow); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+func parseField(field string, bound fieldBound) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		b, err := parseRange(part, bound)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+
+	return bits, nil
+}
+
+func parseRange(part string, bound fieldBound) (uint64, error) {
+	rangeExpr, step := part, 1
+	if idx := strings.Index(part, "/"); idx >= 0 {
+		var err error
+		rangeExpr = part[:idx]
+		if step, err = strconv.Atoi(part[idx+1:]); err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %s field: %s", bound.name, part)
+		}
+	}
+
+	start, end := bound.min, bound.max
+	switch {
+	case rangeExpr == "*":
+	case strings.Contains(rangeExpr, "-"):
+		bounds := strings.SplitN(rangeExpr, "-", 2)
+		var err error
+		if start, err = parseValue(bounds[0], bound); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(bounds[1], bound); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range in %s field: %s", bound.name, part)
+		}
+	default:
+		val, err := parseValue(rangeExpr, bound)
+		if err != nil {
+			return 0, err
+		}
+		start, end = val, val
+		// 单个数字带步长时，例如 5/10，表示从5开始到最大值
+		if step > 1 {
+			end = bound.max
+		}
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	return bits, nil
+}
+
+func parseValue(val string, bound fieldBound) (int, error) {
+	num, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: %s", bound.name, val)
+	}
+
+	if num < bound.min || num > bound.max {
+		return 0, fmt.Errorf("%s field value %d out of range [%d, %d]", bound.name, num, bound.min, bound.max)
+	}
+
+	return num, nil
+}
+
+// Next 返回严格晚于t的下一次触发时间，精度为分钟，时区与t一致。表达式永远无法触发时返回零值。
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, val int) bool {
+	return bits&(1<<uint(val)) != 0
+}
+
+// Every 生成按固定间隔触发的cron表达式，用于将原有按时间间隔执行的周期性任务迁移为定时任务流。
+// cron 表达式的步长在每小时/每天重新开始计数，仅支持能被准确表示的间隔：能整除60的分钟数、能整除24的小时数以及1天，
+// 其他间隔生成的表达式实际间隔会短于d，返回错误，需要直接使用cron表达式。
+func Every(d time.Duration) (string, error) {
+	if d < time.Minute || d%time.Minute != 0 {
+		return "", fmt.Errorf("interval %v must be whole minutes and at least 1 minute", d)
+	}
+
+	minutes := int(d / time.Minute)
+	switch {
+	case minutes == 1:
+		return "* * * * *", nil
+	case minutes < 60 && 60%minutes == 0:
+		return fmt.Sprintf("*/%d * * * *", minutes), nil
+	case minutes == 60:
+		return "0 * * * *", nil
+	case minutes < 24*60 && minutes%60 == 0 && (24*60)%minutes == 0:
+		return fmt.Sprintf("0 */%d * * *", minutes/60), nil
+	case minutes == 24*60:
+		return "0 0 * * *", nil
+	default:
+		return "", fmt.Errorf("interval %v can not be represented by cron expression, use cron expression instead", d)
+	}
+}
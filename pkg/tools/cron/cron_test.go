@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	base := time.Date(2024, 1, 31, 23, 58, 30, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)},
+		{expr: "*/5 * * * *", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 2 * * *", want: time.Date(2024, 2, 1, 2, 30, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// 2024-02-05 是周一
+		{expr: "0 9 * * 1-5/2", want: time.Date(2024, 2, 2, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		// 日和周都不为*时满足其一即可
+		{expr: "0 0 15 * 1", want: time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		assert.NoError(t, err, c.expr)
+		assert.Equal(t, c.want, s.Next(base), c.expr)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *",
+		"*/0 * * * *", "a * * * *"}
+	for _, expr := range invalid {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	cases := map[time.Duration]string{
+		time.Minute:     "* * * * *",
+		5 * time.Minute: "*/5 * * * *",
+		time.Hour:       "0 * * * *",
+		6 * time.Hour:   "0 */6 * * *",
+		24 * time.Hour:  "0 0 * * *",
+	}
+	for d, want := range cases {
+		expr, err := Every(d)
+		assert.NoError(t, err, d.String())
+		assert.Equal(t, want, expr, d.String())
+		_, err = Parse(expr)
+		assert.NoError(t, err, d.String())
+	}
+
+	// 无法用cron表达式准确表示的间隔
+	for _, d := range []time.Duration{0, time.Second, 90 * time.Second, 7 * time.Minute, 90 * time.Minute,
+		5 * time.Hour, 48 * time.Hour} {
+		_, err := Every(d)
+		assert.Error(t, err, d.String())
+	}
+}
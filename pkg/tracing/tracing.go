@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tracing 基于 OpenTelemetry 的分布式链路追踪，通过 OTLP 协议上报 span，
+// 未开启时使用 noop 实现，仅透传上游的链路上下文。
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "hcm"
+
+var (
+	// RidKey span 中记录 hcm 请求ID的属性，用于关联 trace id 与 rid
+	RidKey = attribute.Key("hcm.rid")
+
+	provider *sdktrace.TracerProvider
+)
+
+// Option defines tracing options.
+type Option struct {
+	// Enable 是否开启链路追踪
+	Enable bool
+	// Endpoint OTLP gRPC 接收端地址，例如 127.0.0.1:4317
+	Endpoint string
+	// Insecure 是否使用非 TLS 连接
+	Insecure bool
+	// SampleRatio 根 span 的采样率，取值范围 [0, 1]，非根 span 跟随上游的采样结果
+	SampleRatio float64
+}
+
+// Init 初始化链路追踪，设置全局的 TracerProvider 和 w3c trace context 传播器。
+func Init(serviceName string, opt Option) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{},
+		propagation.Baggage{}))
+
+	if !opt.Enable {
+		return nil
+	}
+
+	if len(opt.Endpoint) == 0 {
+		return errors.New("tracing endpoint is required")
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opt.Endpoint)}
+	if opt.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), clientOpts...)
+	if err != nil {
+		return fmt.Errorf("new otlp trace exporter failed, err: %v", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName)))
+	if err != nil {
+		return fmt.Errorf("new tracing resource failed, err: %v", err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opt.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return nil
+}
+
+// Shutdown 上报缓存中的 span 并关闭 exporter，未开启链路追踪时不做处理。
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+}
+
+// Start 基于 ctx 中的链路上下文创建子 span，返回携带新 span 的 ctx。
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End 结束 span，err 不为空时记录错误并将 span 状态置为失败。
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// TraceID 获取 ctx 中的 trace id，不存在时返回空字符串。
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+
+	return sc.TraceID().String()
+}
+
+// Inject 将 ctx 中的链路上下文写入 http header。
+func Inject(ctx context.Context, header http.Header) {
+	if ctx == nil {
+		return
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract 从 http header 中解析上游的链路上下文，并写入 ctx。
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
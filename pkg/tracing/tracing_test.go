@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	if err := Init("test", Option{}); err != nil {
+		t.Fatalf("init tracing failed, err: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID,
+		TraceFlags: trace.FlagsSampled})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+
+	header := make(http.Header)
+	Inject(ctx, header)
+	if header.Get("traceparent") == "" {
+		t.Fatalf("expect traceparent header injected")
+	}
+
+	// 未开启链路追踪时，子 span 仍然透传上游的 trace id
+	child, span := Start(Extract(context.Background(), header), "child")
+	defer span.End()
+	if got := TraceID(child); got != traceID.String() {
+		t.Fatalf("expect trace id %s, got: %s", traceID, got)
+	}
+
+	if got := TraceID(context.Background()); got != "" {
+		t.Fatalf("expect empty trace id, got: %s", got)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0032,HCMVER=v1.7.0
+
+    Notes:
+    1. 审计表`audit`新增哈希链字段`chain_key`、`chain_seq`、`prev_hash`、`hash`
+    2. 新增审计哈希链链头表`audit_chain`
+    3. 新增审计哈希链签名检查点表`audit_checkpoint`
+*/
+
+START TRANSACTION;
+
+-- 1. 审计表新增哈希链字段，存量审计记录不在哈希链上
+alter table `audit`
+    add column `chain_key` varchar(16) not null default '' after `detail`,
+    add column `chain_seq` bigint(1) unsigned not null default 0 after `chain_key`,
+    add column `prev_hash` char(64) not null default '' after `chain_seq`,
+    add column `hash` char(64) not null default '' after `prev_hash`,
+    add index `idx_chain_key_chain_seq` (`chain_key`, `chain_seq`);
+
+-- 2. 新增审计哈希链链头表
+create table if not exists `audit_chain`
+(
+    `chain_key`      varchar(16)        not null,
+    `last_seq`       bigint(1) unsigned not null default 0,
+    `last_hash`      char(64)           not null default '',
+    `checkpoint_seq` bigint(1) unsigned not null default 0,
+    `checkpoint_at`  timestamp          not null default current_timestamp,
+    `updated_at`     timestamp          not null default current_timestamp on update current_timestamp,
+    primary key (`chain_key`)
+) engine = innodb
+  default charset = utf8mb4;
+
+-- 3. 新增审计哈希链签名检查点表
+create table if not exists `audit_checkpoint`
+(
+    `id`         bigint(1) unsigned not null auto_increment,
+    `chain_key`  varchar(16)        not null,
+    `chain_seq`  bigint(1) unsigned not null,
+    `hash`       char(64)           not null,
+    `signature`  char(64)           not null,
+    `created_at` timestamp          not null default current_timestamp,
+    primary key (`id`),
+    index `idx_chain_key_chain_seq` (`chain_key`, `chain_seq`)
+) engine = innodb
+  default charset = utf8mb4;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0032' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0033,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增审计事件发件箱表`audit_outbox`
+    2. 新增审计事件投递进度表`audit_sink_cursor`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增审计事件发件箱表，审计记录写入时同事务写入，投递目标全部投递后清理
+create table if not exists `audit_outbox`
+(
+    `id`         bigint(1) unsigned not null auto_increment,
+    `res_type`   varchar(50)        not null,
+    `action`     varchar(20)        not null,
+    `payload`    json               not null,
+    `created_at` timestamp          not null default current_timestamp,
+    primary key (`id`)
+) engine = innodb
+  default charset = utf8mb4;
+
+-- 2. 新增审计事件投递进度表
+create table if not exists `audit_sink_cursor`
+(
+    `sink`       varchar(64)        not null,
+    `last_id`    bigint(1) unsigned not null default 0,
+    `attempts`   int(1) unsigned    not null default 0,
+    `last_error` varchar(1024)      not null default '',
+    `updated_at` timestamp          not null default current_timestamp on update current_timestamp,
+    primary key (`sink`)
+) engine = innodb
+  default charset = utf8mb4;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0033' as `sql_ver`;
+
+COMMIT
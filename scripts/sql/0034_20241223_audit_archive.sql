@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0034,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增审计记录归档文件清单表`audit_archive`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增审计记录归档文件清单表，归档文件存放在对象存储中
+create table if not exists `audit_archive`
+(
+    `id`            bigint(1) unsigned not null auto_increment,
+    `res_type`      varchar(50)        not null,
+    `partition_key` varchar(16)        not null,
+    `path`          varchar(255)       not null,
+    `start_id`      bigint(1) unsigned not null,
+    `end_id`        bigint(1) unsigned not null,
+    `record_count`  bigint(1) unsigned not null,
+    `start_at`      timestamp          not null default current_timestamp,
+    `end_at`        timestamp          not null default current_timestamp,
+    `created_at`    timestamp          not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_path` (`path`),
+    index `idx_partition_key` (`partition_key`),
+    index `idx_start_at_end_at` (`start_at`, `end_at`)
+) engine = innodb
+  default charset = utf8mb4;
+
+-- 2. 审计表新增创建时间索引，用于查询超过保留天数的审计记录
+alter table `audit`
+    add index `idx_res_type_created_at` (`res_type`, `created_at`);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0034' as `sql_ver`;
+
+COMMIT
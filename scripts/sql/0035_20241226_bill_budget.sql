@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0035,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增云账单预算表`account_bill_budget`
+    2. 新增云账单预算执行记录表`account_bill_budget_record`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增云账单预算表
+create table if not exists `account_bill_budget`
+(
+    `id`                  varchar(64)     not null,
+    `name`                varchar(255)    not null,
+    `scope_type`          varchar(32)     not null comment '预算范围类型：biz、main_account、root_account、vendor、product',
+    `scope_value`         varchar(64)     not null comment '预算范围取值',
+    `period`              varchar(16)     not null comment '预算周期：month、quarter',
+    `amount`              decimal(38, 10) not null comment '预算金额',
+    `currency`            varchar(32)     not null comment '预算币种',
+    `actual_thresholds`   json            not null comment '实际费用告警阈值百分比',
+    `forecast_thresholds` json            not null comment '预测费用告警阈值百分比',
+    `receivers`           json            not null comment '通知接收人',
+    `memo`                varchar(255)             default '',
+    `creator`             varchar(64)     not null,
+    `reviser`             varchar(64)     not null,
+    `created_at`          timestamp       not null default current_timestamp,
+    `updated_at`          timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    key `idx_scope_type_scope_value` (`scope_type`, `scope_value`)
+) engine = innodb
+  default charset = utf8mb4 comment '云账单预算';
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_budget', '0');
+
+-- 2. 新增云账单预算执行记录表，每个预算每天一条
+create table if not exists `account_bill_budget_record`
+(
+    `id`               varchar(64)     not null,
+    `budget_id`        varchar(64)     not null,
+    `period_key`       varchar(16)     not null comment '预算周期标识，如 2024-01、2024-Q1',
+    `bill_year`        int             not null,
+    `bill_month`       int             not null,
+    `bill_day`         int             not null,
+    `currency`         varchar(32)     not null,
+    `amount`           decimal(38, 10) not null comment '评估时的预算金额',
+    `actual_cost`      decimal(38, 10) not null comment '周期内实际费用',
+    `forecast_cost`    decimal(38, 10) not null comment '周期总费用预测',
+    `actual_alerted`   json            not null comment '周期内已通知的实际费用阈值',
+    `forecast_alerted` json            not null comment '周期内已通知的预测费用阈值',
+    `creator`          varchar(64)     not null,
+    `reviser`          varchar(64)     not null,
+    `created_at`       timestamp       not null default current_timestamp,
+    `updated_at`       timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_budget_id_bill_date` (`budget_id`, `bill_year`, `bill_month`, `bill_day`),
+    key `idx_budget_id_period_key` (`budget_id`, `period_key`)
+) engine = innodb
+  default charset = utf8mb4 comment '云账单预算执行记录';
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_budget_record', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0035' as `sql_ver`;
+
+COMMIT
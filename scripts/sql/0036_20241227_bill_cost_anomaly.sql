@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0036,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增二级账号每日费用异常表`account_bill_cost_anomaly`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增二级账号每日费用异常表，每个二级账号每天最多一条
+create table if not exists `account_bill_cost_anomaly`
+(
+    `id`              varchar(64)     not null,
+    `root_account_id` varchar(64)     not null,
+    `main_account_id` varchar(64)     not null,
+    `vendor`          varchar(16)     not null,
+    `product_id`      bigint(1)                default 0,
+    `bk_biz_id`       bigint(1)                default 0,
+    `bill_year`       int             not null,
+    `bill_month`      int             not null,
+    `bill_day`        int             not null,
+    `currency`        varchar(32)     not null,
+    `cost`            decimal(38, 10) not null comment '当天费用',
+    `baseline`        decimal(38, 10) not null comment '滚动窗口内每日费用中位数',
+    `deviation`       decimal(38, 10) not null comment '当天费用与基线的差值',
+    `score`           decimal(38, 10) not null comment '稳健 z 分数',
+    `severity`        varchar(16)     not null comment '严重程度：low、medium、high',
+    `contributors`    json            not null comment '费用增长的云产品',
+    `notified`        tinyint(1) unsigned      default 0 comment '是否已通知',
+    `creator`         varchar(64)     not null,
+    `reviser`         varchar(64)     not null,
+    `created_at`      timestamp       not null default current_timestamp,
+    `updated_at`      timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_main_account_id_bill_date` (`main_account_id`, `bill_year`, `bill_month`, `bill_day`),
+    key `idx_bill_date` (`bill_year`, `bill_month`, `bill_day`)
+) engine = innodb
+  default charset = utf8mb4 comment '二级账号每日费用异常';
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_cost_anomaly', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0036' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0037,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增统一资源标签表`resource_tag`，由各云厂商的资源同步写入
+*/
+
+START TRANSACTION;
+
+-- 1. 新增统一资源标签表，通过 res_type + res_id 关联具体资源
+create table if not exists `resource_tag`
+(
+    `id`           varchar(64)  not null,
+    `vendor`       varchar(16)  not null,
+    `account_id`   varchar(64)  not null,
+    `res_type`     varchar(64)  not null comment '资源类型，如 cvm、disk、eip、vpc、subnet、security_group',
+    `res_id`       varchar(64)  not null comment '资源在hcm中的ID',
+    `cloud_res_id` varchar(255) not null default '' comment '资源的云上ID',
+    `key`          varchar(255) not null,
+    `value`        varchar(255) not null default '',
+    `creator`      varchar(64)  not null,
+    `reviser`      varchar(64)  not null,
+    `created_at`   timestamp    not null default current_timestamp,
+    `updated_at`   timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_res_id_key` (`res_type`, `res_id`, `key`),
+    key `idx_res_type_key_value` (`res_type`, `key`, `value`),
+    key `idx_account_id_res_type` (`account_id`, `res_type`)
+) engine = innodb
+  default charset = utf8mb4 comment '云资源标签';
+
+insert into id_generator(`resource`, `max_id`)
+values ('resource_tag', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0037' as `sql_ver`;
+
+COMMIT
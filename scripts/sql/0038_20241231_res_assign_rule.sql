@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0038,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增资源自动分配规则表`res_assign_rule`，用于将同步到的未分配资源自动分配到业务
+*/
+
+START TRANSACTION;
+
+-- 1. 新增资源自动分配规则表
+create table if not exists `res_assign_rule`
+(
+    `id`          varchar(64)  not null,
+    `name`        varchar(255) not null,
+    `res_types`   json         not null comment '规则适用的资源类型，如 cvm、disk、eip',
+    `priority`    bigint       not null default 0 comment '优先级，数值越小优先级越高',
+    `enabled`     boolean      not null default true,
+    `conditions`  json         not null comment '匹配条件，包括账号、地域、VPC、名称正则、标签、CIDR',
+    `bk_biz_id`   bigint       not null comment '匹配的资源分配到的业务',
+    `bk_cloud_id` bigint       not null default -1 comment '主机未绑定管控区域时，其VPC绑定的管控区域，-1表示不绑定',
+    `memo`        varchar(255)          default '',
+    `creator`     varchar(64)  not null,
+    `reviser`     varchar(64)  not null,
+    `created_at`  timestamp    not null default current_timestamp,
+    `updated_at`  timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    key `idx_enabled_priority` (`enabled`, `priority`)
+) engine = innodb
+  default charset = utf8mb4 comment '资源自动分配规则';
+
+insert into id_generator(`resource`, `max_id`)
+values ('res_assign_rule', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0038' as `sql_ver`;
+
+COMMIT
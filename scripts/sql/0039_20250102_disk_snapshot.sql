@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0039,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增云硬盘快照表`disk_snapshot`
+    2. 新增云硬盘快照策略表`disk_snapshot_policy`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增云硬盘快照表
+create table if not exists `disk_snapshot`
+(
+    `id`               varchar(64)  not null,
+    `vendor`           varchar(16)  not null,
+    `account_id`       varchar(64)  not null,
+    `cloud_id`         varchar(255) not null,
+    `name`             varchar(255) not null default '',
+    `bk_biz_id`        bigint       not null default -1 comment '快照所属云盘分配的业务',
+    `region`           varchar(255) not null default '',
+    `zone`             varchar(255) not null default '',
+    `disk_id`          varchar(64)  not null default '' comment '源云盘在hcm中的ID，云盘不在hcm中时为空',
+    `cloud_disk_id`    varchar(255) not null default '' comment '源云盘的云上ID',
+    `disk_size`        bigint       not null default 0 comment '源云盘大小，单位GB',
+    `status`           varchar(64)  not null default '',
+    `encrypted`        boolean      not null default false,
+    `policy_id`        varchar(64)  not null default '' comment '创建快照的快照策略ID，手动创建或同步的快照为空',
+    `cloud_created_at` varchar(64)  not null default '' comment '快照在云上的创建时间',
+    `memo`             varchar(255)          default '',
+    `extension`        json         not null,
+    `creator`          varchar(64)  not null,
+    `reviser`          varchar(64)  not null,
+    `created_at`       timestamp    not null default current_timestamp,
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_account_id_cloud_id` (`account_id`, `cloud_id`),
+    key `idx_disk_id` (`disk_id`),
+    key `idx_policy_id_disk_id` (`policy_id`, `disk_id`),
+    key `idx_bk_biz_id` (`bk_biz_id`)
+) engine = innodb
+  default charset = utf8mb4 comment '云硬盘快照';
+
+-- 2. 新增云硬盘快照策略表
+create table if not exists `disk_snapshot_policy`
+(
+    `id`               varchar(64)  not null,
+    `name`             varchar(255) not null,
+    `bk_biz_id`        bigint       not null default -1 comment '策略所属业务，-1表示资源下的策略',
+    `scope_type`       varchar(32)  not null comment '策略范围类型，disk：指定云盘，biz：业务下的全部云盘',
+    `disk_ids`         json         not null comment '策略范围类型为disk时生效的云盘ID',
+    `interval_hours`   bigint       not null comment '创建快照的间隔，单位小时',
+    `retention_count`  bigint       not null comment '每块云盘保留的策略快照数量，超出时删除最早的快照',
+    `enabled`          boolean      not null default true,
+    `last_executed_at` varchar(32)  not null default '' comment '上次执行时间',
+    `memo`             varchar(255)          default '',
+    `creator`          varchar(64)  not null,
+    `reviser`          varchar(64)  not null,
+    `created_at`       timestamp    not null default current_timestamp,
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4 comment '云硬盘快照策略';
+
+insert into id_generator(`resource`, `max_id`)
+values ('disk_snapshot', '0'),
+       ('disk_snapshot_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0039' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,38 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0040,HCMVER=v1.7.0
+
+    Notes:
+    1. 异步任务流表`async_flow`新增优先级字段`priority`、并发控制键字段`concurrency_key`
+*/
+
+START TRANSACTION;
+
+-- 1. 新增任务流优先级、并发控制键字段
+alter table `async_flow`
+    add column `priority`        tinyint unsigned not null default 2 after `rollback_mode`,
+    add column `concurrency_key` varchar(255)     not null default '' after `priority`,
+    add index `idx_state_priority` (`state`, `priority`);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0040' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0041,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增审批链配置表`approval_chain`，按申请单类型、业务配置多级审批
+    2. 新增内置审批单表`approval_ticket`，记录申请单的审批进度和审批记录
+*/
+
+START TRANSACTION;
+
+-- 1. 新增审批链配置表
+create table if not exists `approval_chain`
+(
+    `id`               varchar(64)  not null,
+    `application_type` varchar(64)  not null,
+    `bk_biz_id`        bigint       not null default -1 comment '适用的业务，-1表示未单独配置审批链的业务使用的默认审批链',
+    `stages`           json         not null comment '审批节点，按顺序审批，包括审批人、会签方式、超时时间和超时升级审批人',
+    `memo`             varchar(255)          default '',
+    `creator`          varchar(64)  not null,
+    `reviser`          varchar(64)  not null,
+    `created_at`       timestamp    not null default current_timestamp,
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_application_type_bk_biz_id` (`application_type`, `bk_biz_id`)
+) engine = innodb
+  default charset = utf8mb4 comment '审批链配置';
+
+-- 2. 新增内置审批单表
+create table if not exists `approval_ticket`
+(
+    `id`                varchar(64)  not null,
+    `sn`                varchar(64)  not null,
+    `application_id`    varchar(64)  not null,
+    `application_type`  varchar(64)  not null,
+    `bk_biz_id`         bigint       not null default -1,
+    `applicant`         varchar(64)  not null,
+    `title`             varchar(255) not null default '',
+    `status`            varchar(32)  not null comment '审批状态：pending、pass、rejected、cancelled',
+    `current_stage`     bigint       not null default 0 comment '当前审批节点序号',
+    `stages`            json         not null comment '创建时审批链的快照及各节点审批进度',
+    `current_approvers` json         not null comment '当前节点待审批人，用于查询待办',
+    `deadline`          varchar(32)  not null default '' comment '当前节点超时时间，为空表示不超时',
+    `records`           json         not null comment '审批记录',
+    `version`           bigint       not null default 0 comment '版本号，用于并发审批时的乐观锁',
+    `creator`           varchar(64)  not null,
+    `reviser`           varchar(64)  not null,
+    `created_at`        timestamp    not null default current_timestamp,
+    `updated_at`        timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_sn` (`sn`),
+    unique key `idx_uk_application_id` (`application_id`),
+    key `idx_status` (`status`)
+) engine = innodb
+  default charset = utf8mb4 comment '内置审批单';
+
+insert into id_generator(`resource`, `max_id`)
+values ('approval_chain', '0'),
+       ('approval_ticket', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0041' as `sql_ver`;
+
+COMMIT
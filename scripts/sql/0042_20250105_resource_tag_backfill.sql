@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0042,HCMVER=v1.7.0
+
+    Notes:
+    1. 将安全组、负载均衡`tags`字段中已有的标签回填到统一资源标签表`resource_tag`，并推进`resource_tag`的ID生成器
+*/
+
+START TRANSACTION;
+
+-- 1. 收集待回填的安全组、负载均衡标签
+create temporary table if not exists `tmp_resource_tag_backfill`
+(
+    `seq`          bigint       not null auto_increment,
+    `vendor`       varchar(16)  not null,
+    `account_id`   varchar(64)  not null,
+    `res_type`     varchar(64)  not null,
+    `res_id`       varchar(64)  not null,
+    `cloud_res_id` varchar(255) not null,
+    `key`          varchar(255) not null,
+    `value`        varchar(255) not null,
+    primary key (`seq`)
+) engine = innodb
+  default charset = utf8mb4;
+
+insert into `tmp_resource_tag_backfill`(`vendor`, `account_id`, `res_type`, `res_id`, `cloud_res_id`, `key`, `value`)
+select sg.vendor, sg.account_id, 'security_group', sg.id, sg.cloud_id, jt.tag_key,
+       ifnull(json_unquote(json_extract(sg.tags, concat('$."', jt.tag_key, '"'))), '')
+from `security_group` as sg,
+     json_table(json_keys(sg.tags), '$[*]' columns (`tag_key` varchar(255) path '$')) as jt
+where json_type(sg.tags) = 'OBJECT'
+  and not exists(select 1
+                 from `resource_tag` as rt
+                 where rt.res_type = 'security_group'
+                   and rt.res_id = sg.id
+                   and rt.`key` = jt.tag_key);
+
+insert into `tmp_resource_tag_backfill`(`vendor`, `account_id`, `res_type`, `res_id`, `cloud_res_id`, `key`, `value`)
+select lb.vendor, lb.account_id, 'load_balancer', lb.id, lb.cloud_id, jt.tag_key,
+       ifnull(json_unquote(json_extract(lb.tags, concat('$."', jt.tag_key, '"'))), '')
+from `load_balancer` as lb,
+     json_table(json_keys(lb.tags), '$[*]' columns (`tag_key` varchar(255) path '$')) as jt
+where json_type(lb.tags) = 'OBJECT'
+  and not exists(select 1
+                 from `resource_tag` as rt
+                 where rt.res_type = 'load_balancer'
+                   and rt.res_id = lb.id
+                   and rt.`key` = jt.tag_key);
+
+-- 2. 基于当前ID生成器的最大值为回填数据分配ID，ID格式与ID生成器保持一致
+select conv(`max_id`, 36, 10)
+into @base_id
+from `id_generator`
+where `resource` = 'resource_tag' for update;
+
+insert into `resource_tag`(`id`, `vendor`, `account_id`, `res_type`, `res_id`, `cloud_res_id`, `key`, `value`,
+                           `creator`, `reviser`)
+select lpad(lower(conv(@base_id + tmp.seq, 10, 36)), 8, '0'),
+       tmp.vendor,
+       tmp.account_id,
+       tmp.res_type,
+       tmp.res_id,
+       tmp.cloud_res_id,
+       tmp.`key`,
+       tmp.value,
+       'system',
+       'system'
+from `tmp_resource_tag_backfill` as tmp;
+
+update `id_generator`
+set `max_id` = lpad(lower(conv(@base_id + (select ifnull(max(`seq`), 0) from `tmp_resource_tag_backfill`), 10, 36)),
+                    8, '0')
+where `resource` = 'resource_tag';
+
+drop temporary table if exists `tmp_resource_tag_backfill`;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0042' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0043,HCMVER=v1.7.0
+
+    Notes:
+    1. 审计哈希链链头表`audit_chain`新增分片`shard`、上一条链的衔接`prev_chain_key`、`prev_seq`、`prev_hash`，
+       最近一个检查点的签名`checkpoint_sig`和链头签名`signature`
+*/
+
+START TRANSACTION;
+
+-- 1. 审计哈希链链头表新增字段，每天的链由上一天同分片链的最后一条记录衔接，链头和检查点一起签名
+alter table `audit_chain`
+    add column `shard` int(1) unsigned not null default 0 after `chain_key`,
+    add column `prev_chain_key` varchar(16) not null default '' after `shard`,
+    add column `prev_seq` bigint(1) unsigned not null default 0 after `prev_chain_key`,
+    add column `prev_hash` char(64) not null default '' after `prev_seq`,
+    add column `checkpoint_sig` char(64) not null default '' after `checkpoint_seq`,
+    add column `signature` char(64) not null default '' after `checkpoint_at`,
+    add index `idx_shard_chain_key` (`shard`, `chain_key`);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0043' as `sql_ver`;
+
+COMMIT
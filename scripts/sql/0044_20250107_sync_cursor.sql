@@ -0,0 +1,50 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0044,HCMVER=v1.7.0
+
+    Notes:
+    1. 新增同步游标表`sync_cursor`，记录增量同步等周期性任务已处理到的位置，跨实例、重启后继续
+*/
+
+START TRANSACTION;
+
+-- 1. 新增同步游标表
+create table if not exists `sync_cursor`
+(
+    `id`           varchar(64)  not null,
+    `type`         varchar(64)  not null comment '游标类型，如 cloud_event、res_assign_rule',
+    `scope`        varchar(255) not null default '' comment '游标作用范围，如 账号ID/地域',
+    `cursor_value` varchar(64)  not null comment '已处理到的位置，如时间点',
+    `creator`      varchar(64)  not null,
+    `reviser`      varchar(64)  not null,
+    `created_at`   timestamp    not null default current_timestamp,
+    `updated_at`   timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_type_scope` (`type`, `scope`)
+) engine = innodb
+  default charset = utf8mb4 comment '同步游标';
+
+insert into id_generator(`resource`, `max_id`)
+values ('sync_cursor', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.7.0' as `hcm_ver`, '0044' as `sql_ver`;
+
+COMMIT
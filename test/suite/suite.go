@@ -128,6 +128,11 @@ func ClearData() error {
 		table.AccountSyncDetailTable,
 		table.AccountBizRelTable,
 		table.AuditTable,
+		table.AuditChainTable,
+		table.AuditCheckpointTable,
+		table.AuditOutboxTable,
+		table.AuditSinkCursorTable,
+		table.AuditArchiveTable,
 		table.VpcTable,
 		table.SubnetTable,
 		table.RouteTableTable,